@@ -16,11 +16,9 @@ package antlr
 
 import (
 	"fmt"
-	"github.com/rs/zerolog"
-	"github.com/sirupsen/logrus"
-	"go.uber.org/zap"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/antlr4-go/antlr/v4"
 	"github.com/hyperjumptech/grule-rule-engine/antlr/parser/grulev3"
@@ -42,28 +40,9 @@ var (
 
 // SetLogger changes default logger on external
 func SetLogger(log interface{}) {
-	var entry logger.LogEntry
+	entry, ok := logger.FromExternal(log)
+	if !ok {
 
-	switch log.(type) {
-	case *zap.Logger:
-		log, ok := log.(*zap.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewZap(log)
-	case *logrus.Logger:
-		log, ok := log.(*logrus.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewLogrus(log)
-	case *zerolog.Logger:
-		log, ok := log.(*zerolog.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewZero(log)
-	default:
 		return
 	}
 
@@ -92,6 +71,52 @@ type GruleV3ParserListener struct {
 	StopParse     bool
 	ErrorCallback *pkg.GruleErrorReporter
 	KnowledgeBase *ast.KnowledgeBase
+
+	// Logger, if it wraps a logger, is used instead of thisListener.log().
+	Logger logger.LogEntry
+
+	// Units, LiteralSuffixes and ScriptLanguages, if set, are used instead of the default registries to build the
+	// quantities, the suffix literals and the scripts of the rules.
+	Units           *pkg.UnitRegistry
+	LiteralSuffixes *pkg.LiteralSuffixRegistry
+	ScriptLanguages *ast.ScriptLanguageRegistry
+
+	// testEntry is the test block being walked, its nodes are kept in its own working memory.
+	testEntry *ast.TestEntry
+}
+
+// workingMemory returns the working memory the walked nodes should be registered into.
+func (thisListener *GruleV3ParserListener) workingMemory() *ast.WorkingMemory {
+	if thisListener.testEntry != nil {
+
+		return thisListener.testEntry.WorkingMemory
+	}
+
+	return thisListener.KnowledgeBase.WorkingMemory
+}
+
+// log returns the logger of this listener.
+func (thisListener *GruleV3ParserListener) log() logger.LogEntry {
+
+	return thisListener.Logger.Or(LoggerV3)
+}
+
+// expectKeyword reports an error if a contextual keyword of a test block is misspelled. A keyword missing after a
+// syntax error stops the parse, the syntax error being reported already.
+func (thisListener *GruleV3ParserListener) expectKeyword(keyword string, node antlr.TerminalNode) bool {
+	if node == nil {
+		thisListener.StopParse = true
+
+		return false
+	}
+	if strings.EqualFold(node.GetText(), keyword) {
+
+		return true
+	}
+	thisListener.StopParse = true
+	thisListener.ErrorCallback.AddError(fmt.Errorf("expecting '%s' but got '%s'", keyword, node.GetText()))
+
+	return false
 }
 
 // VisitTerminal is called when a terminal node is visited.
@@ -108,7 +133,7 @@ func (thisListener *GruleV3ParserListener) VisitTerminal(node antlr.TerminalNode
 
 // VisitErrorNode is called when an error node is visited.
 func (thisListener *GruleV3ParserListener) VisitErrorNode(node antlr.ErrorNode) {
-	LoggerV3.Errorf("GRL error, after '%v' and then unexpected '%thisListener'", thisListener.PreviousNode, node.GetText())
+	thisListener.log().Errorf("GRL error, after '%v' and then unexpected '%thisListener'", thisListener.PreviousNode, node.GetText())
 	thisListener.StopParse = true
 }
 
@@ -141,8 +166,127 @@ func (thisListener *GruleV3ParserListener) ExitGrl(ctx *grulev3.GrlContext) {
 			thisListener.ErrorCallback.AddError(err)
 		}
 	}
+	for _, te := range thisListener.Grl.TestEntries {
+		err := thisListener.KnowledgeBase.AddTestEntry(te)
+		if err != nil {
+			thisListener.ErrorCallback.AddError(err)
+		}
+	}
+	for _, he := range thisListener.Grl.HaltEntries {
+		thisListener.KnowledgeBase.AddHaltEntry(he)
+	}
+}
+
+// EnterHaltEntry is called when production haltEntry is entered.
+func (thisListener *GruleV3ParserListener) EnterHaltEntry(ctx *grulev3.HaltEntryContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("halt", ctx.SIMPLENAME()) {
+
+		return
+	}
+	entry := ast.NewHaltEntry()
+	entry.GrlText = ctx.GetText()
+	thisListener.Stack.Push(entry)
+}
+
+// ExitHaltEntry is called when production haltEntry is exited.
+func (thisListener *GruleV3ParserListener) ExitHaltEntry(ctx *grulev3.HaltEntryContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	entry, popOk := thisListener.Stack.Pop().(*ast.HaltEntry)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	entryReceiver, popOk := thisListener.Stack.Peek().(ast.HaltEntryReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := entryReceiver.ReceiveHaltEntry(entry)
+	if err != nil {
+		thisListener.ErrorCallback.AddError(err)
+	} else {
+		thisListener.log().Debugf("Added HaltEntry : %s", entry.GrlText)
+	}
+}
+
+// EnterTestEntry is called when production testEntry is entered.
+func (thisListener *GruleV3ParserListener) EnterTestEntry(ctx *grulev3.TestEntryContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("test", ctx.SIMPLENAME()) {
+
+		return
+	}
+	entry := ast.NewTestEntry(thisListener.KnowledgeBase.Name, thisListener.KnowledgeBase.Version)
+	entry.WorkingMemory.Units = thisListener.Units
+	entry.GrlText = ctx.GetText()
+	thisListener.testEntry = entry
+	thisListener.Stack.Push(entry)
+}
+
+// ExitTestEntry is called when production testEntry is exited.
+func (thisListener *GruleV3ParserListener) ExitTestEntry(ctx *grulev3.TestEntryContext) {
+	thisListener.testEntry = nil
+	if thisListener.StopParse {
+
+		return
+	}
+	entry, popOk := thisListener.Stack.Pop().(*ast.TestEntry)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	entry.WorkingMemory.IndexVariables()
+	entryReceiver, popOk := thisListener.Stack.Peek().(ast.TestEntryReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := entryReceiver.ReceiveTestEntry(entry)
+	if err != nil {
+		thisListener.ErrorCallback.AddError(err)
+	} else {
+		thisListener.log().Debugf("Added TestEntry : %s", entry.TestName)
+	}
+}
+
+// EnterGivenScope is called when production givenScope is entered.
+func (thisListener *GruleV3ParserListener) EnterGivenScope(ctx *grulev3.GivenScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thisListener.expectKeyword("given", ctx.SIMPLENAME())
+}
+
+// ExitGivenScope is called when production givenScope is exited.
+func (thisListener *GruleV3ParserListener) ExitGivenScope(ctx *grulev3.GivenScopeContext) {}
+
+// EnterExpectScope is called when production expectScope is entered.
+func (thisListener *GruleV3ParserListener) EnterExpectScope(ctx *grulev3.ExpectScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thisListener.expectKeyword("expect", ctx.SIMPLENAME())
 }
 
+// ExitExpectScope is called when production expectScope is exited.
+func (thisListener *GruleV3ParserListener) ExitExpectScope(ctx *grulev3.ExpectScopeContext) {}
+
 // EnterRuleEntry is called when production ruleEntry is entered.
 func (thisListener *GruleV3ParserListener) EnterRuleEntry(ctx *grulev3.RuleEntryContext) {
 	if thisListener.StopParse {
@@ -184,222 +328,718 @@ func (thisListener *GruleV3ParserListener) ExitRuleEntry(ctx *grulev3.RuleEntryC
 	err := entryReceiver.ReceiveRuleEntry(entry)
 	if err != nil {
 		thisListener.ErrorCallback.AddError(err)
-	} else {
-		LoggerV3.Debugf("Added RuleEntry : %thisListener", entry.RuleName)
+	} else {
+		thisListener.log().Debugf("Added RuleEntry : %thisListener", entry.RuleName)
+	}
+}
+
+// EnterSalience is called when production salience is entered.
+func (thisListener *GruleV3ParserListener) EnterSalience(ctx *grulev3.SalienceContext) {
+	sal := ast.NewSalience(0)
+	thisListener.Stack.Push(sal)
+}
+
+// ExitSalience is called when production salience is exited.
+func (thisListener *GruleV3ParserListener) ExitSalience(ctx *grulev3.SalienceContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	salience, popOk := thisListener.Stack.Pop().(*ast.Salience)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	salienceReceiver, popOk := thisListener.Stack.Peek().(ast.SalienceReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := salienceReceiver.AcceptSalience(salience)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterRuleAnnotation is called when production ruleAnnotation is entered.
+// The annotation is either @profile, @sink or @approval.
+func (thisListener *GruleV3ParserListener) EnterRuleAnnotation(ctx *grulev3.RuleAnnotationContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	switch strings.ToLower(ctx.SIMPLENAME().GetText()) {
+	case "profile":
+		thisListener.Stack.Push(ast.NewProfile())
+	case "sink":
+		thisListener.Stack.Push(ast.NewSink())
+	case "approval":
+		thisListener.Stack.Push(ast.NewApproval())
+	default:
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(fmt.Errorf("expecting 'profile', 'sink' or 'approval' but got '%s'", ctx.SIMPLENAME().GetText()))
+	}
+}
+
+// ExitRuleAnnotation is called when production ruleAnnotation is exited.
+func (thisListener *GruleV3ParserListener) ExitRuleAnnotation(ctx *grulev3.RuleAnnotationContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	var err error
+	switch annotation := thisListener.Stack.Pop().(type) {
+	case *ast.Profile:
+		profileReceiver, popOk := thisListener.Stack.Peek().(ast.ProfileReceiver)
+		if !popOk {
+			thisListener.StopParse = true
+
+			return
+		}
+		err = profileReceiver.AcceptProfile(annotation)
+	case *ast.Sink:
+		sinkReceiver, popOk := thisListener.Stack.Peek().(ast.SinkReceiver)
+		if !popOk {
+			thisListener.StopParse = true
+
+			return
+		}
+		err = sinkReceiver.AcceptSink(annotation)
+	case *ast.Approval:
+		approvalReceiver, popOk := thisListener.Stack.Peek().(ast.ApprovalReceiver)
+		if !popOk {
+			thisListener.StopParse = true
+
+			return
+		}
+		err = approvalReceiver.AcceptApproval(annotation)
+	default:
+		thisListener.StopParse = true
+
+		return
+	}
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterRuleId is called when production ruleId is entered.
+func (thisListener *GruleV3ParserListener) EnterRuleId(ctx *grulev3.RuleIdContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("id", ctx.SIMPLENAME()) {
+
+		return
+	}
+	thisListener.Stack.Push(ast.NewRuleID())
+}
+
+// ExitRuleId is called when production ruleId is exited.
+func (thisListener *GruleV3ParserListener) ExitRuleId(ctx *grulev3.RuleIdContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	ruleID, popOk := thisListener.Stack.Pop().(*ast.RuleID)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	ruleIDReceiver, popOk := thisListener.Stack.Peek().(ast.RuleIDReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := ruleIDReceiver.AcceptRuleID(ruleID)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterMaxFires is called when production maxFires is entered.
+func (thisListener *GruleV3ParserListener) EnterMaxFires(ctx *grulev3.MaxFiresContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thisListener.Stack.Push(ast.NewMaxFires())
+}
+
+// ExitMaxFires is called when production maxFires is exited.
+func (thisListener *GruleV3ParserListener) ExitMaxFires(ctx *grulev3.MaxFiresContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	// max-fires is a contextual keyword written as a name, a minus and a name, without any space in between.
+	if !thisListener.expectKeyword("max", ctx.SIMPLENAME(0)) || !thisListener.expectKeyword("fires", ctx.SIMPLENAME(1)) {
+
+		return
+	}
+	if ctx.MINUS().GetSymbol().GetStart() != ctx.SIMPLENAME(0).GetSymbol().GetStop()+1 || ctx.SIMPLENAME(1).GetSymbol().GetStart() != ctx.MINUS().GetSymbol().GetStop()+1 {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(fmt.Errorf("expecting 'max-fires' but got '%s %s %s'", ctx.SIMPLENAME(0).GetText(), ctx.MINUS().GetText(), ctx.SIMPLENAME(1).GetText()))
+
+		return
+	}
+	maxFires, popOk := thisListener.Stack.Pop().(*ast.MaxFires)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	maxFiresReceiver, popOk := thisListener.Stack.Peek().(ast.MaxFiresReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := maxFiresReceiver.AcceptMaxFires(maxFires)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterCooldown is called when production cooldown is entered.
+func (thisListener *GruleV3ParserListener) EnterCooldown(ctx *grulev3.CooldownContext) {}
+
+// ExitCooldown is called when production cooldown is exited.
+func (thisListener *GruleV3ParserListener) ExitCooldown(ctx *grulev3.CooldownContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("cooldown", ctx.SIMPLENAME()) {
+
+		return
+	}
+	duration, err := time.ParseDuration(ctx.DURATION_LIT().GetText())
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+
+		return
+	}
+	cooldownReceiver, popOk := thisListener.Stack.Peek().(ast.CooldownReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err = cooldownReceiver.AcceptCooldown(ast.NewCooldown(duration))
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterCriticality is called when production criticality is entered.
+func (thisListener *GruleV3ParserListener) EnterCriticality(ctx *grulev3.CriticalityContext) {}
+
+// ExitCriticality is called when production criticality is exited.
+func (thisListener *GruleV3ParserListener) ExitCriticality(ctx *grulev3.CriticalityContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("criticality", ctx.SIMPLENAME(0)) {
+
+		return
+	}
+	criticality, err := ast.ParseCriticality(ctx.SIMPLENAME(1).GetText())
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+
+		return
+	}
+	criticalityReceiver, popOk := thisListener.Stack.Peek().(ast.CriticalityReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err = criticalityReceiver.AcceptCriticality(criticality)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterWhenScope is called when production whenScope is entered.
+func (thisListener *GruleV3ParserListener) EnterWhenScope(ctx *grulev3.WhenScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	whenScope := ast.NewWhenScope()
+	whenScope.GrlText = ctx.GetText()
+	thisListener.Stack.Push(whenScope)
+}
+
+// ExitWhenScope is called when production whenScope is exited.
+func (thisListener *GruleV3ParserListener) ExitWhenScope(ctx *grulev3.WhenScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	when, popOk := thisListener.Stack.Pop().(*ast.WhenScope)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.WhenScopeReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptWhenScope(when)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterScriptBlock is called when production scriptBlock is entered.
+func (thisListener *GruleV3ParserListener) EnterScriptBlock(ctx *grulev3.ScriptBlockContext) {}
+
+// ExitScriptBlock is called when production scriptBlock is exited.
+func (thisListener *GruleV3ParserListener) ExitScriptBlock(ctx *grulev3.ScriptBlockContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.ScriptBlockReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptScriptBlock(ast.NewScriptBlock(ctx.SIMPLENAME().GetText(), ctx.SCRIPT_LIT().GetText()))
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterThenScope is called when production thenScope is entered.
+func (thisListener *GruleV3ParserListener) EnterThenScope(ctx *grulev3.ThenScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	then := ast.NewThenScope()
+	then.GrlText = ctx.GetText()
+	thisListener.Stack.Push(then)
+}
+
+// ExitThenScope is called when production thenScope is exited.
+func (thisListener *GruleV3ParserListener) ExitThenScope(ctx *grulev3.ThenScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	then, popOk := thisListener.Stack.Pop().(*ast.ThenScope)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.ThenScopeReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	if rule, isRule := receiver.(*ast.RuleEntry); isRule && then.Script != nil {
+		if err := then.Script.CompileWith(rule.RuleName, thisListener.ScriptLanguages); err != nil {
+			thisListener.StopParse = true
+			thisListener.ErrorCallback.AddError(err)
+
+			return
+		}
+	}
+	err := receiver.AcceptThenScope(then)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterElseScope is called when production elseScope is entered.
+func (thisListener *GruleV3ParserListener) EnterElseScope(ctx *grulev3.ElseScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	if !thisListener.expectKeyword("else", ctx.SIMPLENAME()) {
+
+		return
+	}
+	elseScope := ast.NewThenScope()
+	elseScope.GrlText = ctx.GetText()
+	thisListener.Stack.Push(elseScope)
+}
+
+// ExitElseScope is called when production elseScope is exited.
+func (thisListener *GruleV3ParserListener) ExitElseScope(ctx *grulev3.ElseScopeContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	elseScope, popOk := thisListener.Stack.Pop().(*ast.ThenScope)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.ElseScopeReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptElseScope(elseScope)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterThenExpressionList is called when production thenExpressionList is entered.
+func (thisListener *GruleV3ParserListener) EnterThenExpressionList(ctx *grulev3.ThenExpressionListContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thenExpList := ast.NewThenExpressionList()
+	thenExpList.GrlText = ctx.GetText()
+	thisListener.Stack.Push(thenExpList)
+}
+
+// ExitThenExpressionList is called when production thenExpressionList is exited.
+func (thisListener *GruleV3ParserListener) ExitThenExpressionList(ctx *grulev3.ThenExpressionListContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thenExpList, popOk := thisListener.Stack.Pop().(*ast.ThenExpressionList)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.ThenExpressionListReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptThenExpressionList(thenExpList)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
+// EnterThenExpression is called when production thenExpression is entered.
+func (thisListener *GruleV3ParserListener) EnterThenExpression(ctx *grulev3.ThenExpressionContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thenExpr := ast.NewThenExpression()
+	thenExpr.GrlText = ctx.GetText()
+	thisListener.Stack.Push(thenExpr)
+}
+
+// ExitThenExpression is called when production thenExpression is exited.
+func (thisListener *GruleV3ParserListener) ExitThenExpression(ctx *grulev3.ThenExpressionContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thenExpr, popOk := thisListener.Stack.Pop().(*ast.ThenExpression)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.ThenExpressionReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptThenExpression(thenExpr)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterSalience is called when production salience is entered.
-func (thisListener *GruleV3ParserListener) EnterSalience(ctx *grulev3.SalienceContext) {
-	sal := ast.NewSalience(0)
-	thisListener.Stack.Push(sal)
+// EnterAssignment is called when production assignment is entered.
+func (thisListener *GruleV3ParserListener) EnterAssignment(ctx *grulev3.AssignmentContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	assign := ast.NewAssignment()
+	assign.GrlText = ctx.GetText()
+	thisListener.Stack.Push(assign)
 }
 
-// ExitSalience is called when production salience is exited.
-func (thisListener *GruleV3ParserListener) ExitSalience(ctx *grulev3.SalienceContext) {
+// ExitAssignment is called when production assignment is exited.
+func (thisListener *GruleV3ParserListener) ExitAssignment(ctx *grulev3.AssignmentContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	salience, popOk := thisListener.Stack.Pop().(*ast.Salience)
-	if !popOk {
+	assign, okPop := thisListener.Stack.Pop().(*ast.Assignment)
+	if !okPop {
 		thisListener.StopParse = true
 
 		return
 	}
-	salienceReceiver, popOk := thisListener.Stack.Peek().(ast.SalienceReceiver)
-	if !popOk {
+	receiver, okPop := thisListener.Stack.Peek().(ast.AssignmentReceiver)
+	if !okPop {
 		thisListener.StopParse = true
 
 		return
 	}
-	err := salienceReceiver.AcceptSalience(salience)
+	assign.IsAssign = ctx.ASSIGN() != nil
+	assign.IsPlusAssign = ctx.PLUS_ASIGN() != nil
+	assign.IsMinusAssign = ctx.MINUS_ASIGN() != nil
+	assign.IsDivAssign = ctx.DIV_ASIGN() != nil
+	assign.IsMulAssign = ctx.MUL_ASIGN() != nil
+
+	err := receiver.AcceptAssignment(assign)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterWhenScope is called when production whenScope is entered.
-func (thisListener *GruleV3ParserListener) EnterWhenScope(ctx *grulev3.WhenScopeContext) {
+// EnterCollectStatement is called when production collectStatement is entered.
+func (thisListener *GruleV3ParserListener) EnterCollectStatement(ctx *grulev3.CollectStatementContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	whenScope := ast.NewWhenScope()
-	whenScope.GrlText = ctx.GetText()
-	thisListener.Stack.Push(whenScope)
+	// the name of the collection is either the second SIMPLENAME or the soft keyword in, the keywords follow it.
+	name, keywords := ctx.IN(), 1
+	if name == nil {
+		name, keywords = ctx.SIMPLENAME(1), 2
+	}
+	if !thisListener.expectKeyword("collect", ctx.SIMPLENAME(0)) || !thisListener.expectKeyword("from", ctx.SIMPLENAME(keywords)) {
+
+		return
+	}
+	if ctx.SIMPLENAME(keywords+1) != nil && !thisListener.expectKeyword("where", ctx.SIMPLENAME(keywords+1)) {
+
+		return
+	}
+	collect := ast.NewCollectStatement()
+	collect.GrlText = ctx.GetText()
+	collect.Name = name.GetText()
+	thisListener.Stack.Push(collect)
 }
 
-// ExitWhenScope is called when production whenScope is exited.
-func (thisListener *GruleV3ParserListener) ExitWhenScope(ctx *grulev3.WhenScopeContext) {
+// ExitCollectStatement is called when production collectStatement is exited.
+func (thisListener *GruleV3ParserListener) ExitCollectStatement(ctx *grulev3.CollectStatementContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	when, popOk := thisListener.Stack.Pop().(*ast.WhenScope)
+	collect, popOk := thisListener.Stack.Pop().(*ast.CollectStatement)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	receiver, popOk := thisListener.Stack.Peek().(ast.WhenScopeReceiver)
+	receiver, popOk := thisListener.Stack.Peek().(ast.CollectStatementReceiver)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	err := receiver.AcceptWhenScope(when)
+	err := receiver.AcceptCollectStatement(collect)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterThenScope is called when production thenScope is entered.
-func (thisListener *GruleV3ParserListener) EnterThenScope(ctx *grulev3.ThenScopeContext) {
+// EnterSwitchStatement is called when production switchStatement is entered.
+func (thisListener *GruleV3ParserListener) EnterSwitchStatement(ctx *grulev3.SwitchStatementContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	then := ast.NewThenScope()
-	then.GrlText = ctx.GetText()
-	thisListener.Stack.Push(then)
+	if !thisListener.expectKeyword("switch", ctx.SIMPLENAME()) {
+
+		return
+	}
+	switchStmt := ast.NewSwitchStatement()
+	switchStmt.GrlText = ctx.GetText()
+	thisListener.Stack.Push(switchStmt)
 }
 
-// ExitThenScope is called when production thenScope is exited.
-func (thisListener *GruleV3ParserListener) ExitThenScope(ctx *grulev3.ThenScopeContext) {
+// ExitSwitchStatement is called when production switchStatement is exited.
+func (thisListener *GruleV3ParserListener) ExitSwitchStatement(ctx *grulev3.SwitchStatementContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	then, popOk := thisListener.Stack.Pop().(*ast.ThenScope)
+	switchStmt, popOk := thisListener.Stack.Pop().(*ast.SwitchStatement)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	receiver, popOk := thisListener.Stack.Peek().(ast.ThenScopeReceiver)
+	receiver, popOk := thisListener.Stack.Peek().(ast.SwitchStatementReceiver)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	err := receiver.AcceptThenScope(then)
+	err := receiver.AcceptSwitchStatement(switchStmt)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterThenExpressionList is called when production thenExpressionList is entered.
-func (thisListener *GruleV3ParserListener) EnterThenExpressionList(ctx *grulev3.ThenExpressionListContext) {
+// EnterSwitchCase is called when production switchCase is entered.
+func (thisListener *GruleV3ParserListener) EnterSwitchCase(ctx *grulev3.SwitchCaseContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	thenExpList := ast.NewThenExpressionList()
-	thenExpList.GrlText = ctx.GetText()
-	thisListener.Stack.Push(thenExpList)
+	// a case lists the values it matches, the default case has none.
+	keyword := "case"
+	if len(ctx.AllExpression()) == 0 {
+		keyword = "default"
+	}
+	if !thisListener.expectKeyword(keyword, ctx.SIMPLENAME()) {
+
+		return
+	}
+	switchCase := &ast.SwitchCase{
+		GrlText: ctx.GetText(),
+		Default: len(ctx.AllExpression()) == 0,
+	}
+	thisListener.Stack.Push(switchCase)
 }
 
-// ExitThenExpressionList is called when production thenExpressionList is exited.
-func (thisListener *GruleV3ParserListener) ExitThenExpressionList(ctx *grulev3.ThenExpressionListContext) {
+// ExitSwitchCase is called when production switchCase is exited.
+func (thisListener *GruleV3ParserListener) ExitSwitchCase(ctx *grulev3.SwitchCaseContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	thenExpList, popOk := thisListener.Stack.Pop().(*ast.ThenExpressionList)
+	switchCase, popOk := thisListener.Stack.Pop().(*ast.SwitchCase)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	receiver, popOk := thisListener.Stack.Peek().(ast.ThenExpressionListReceiver)
+	receiver, popOk := thisListener.Stack.Peek().(ast.SwitchCaseReceiver)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	err := receiver.AcceptThenExpressionList(thenExpList)
+	err := receiver.AcceptSwitchCase(switchCase)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterThenExpression is called when production thenExpression is entered.
-func (thisListener *GruleV3ParserListener) EnterThenExpression(ctx *grulev3.ThenExpressionContext) {
+// EnterMatchExpression is called when production matchExpression is entered.
+func (thisListener *GruleV3ParserListener) EnterMatchExpression(ctx *grulev3.MatchExpressionContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	thenExpr := ast.NewThenExpression()
-	thenExpr.GrlText = ctx.GetText()
-	thisListener.Stack.Push(thenExpr)
+	if !thisListener.expectKeyword("match", ctx.SIMPLENAME()) {
+
+		return
+	}
+	match := ast.NewMatchExpression()
+	match.GrlText = ctx.GetText()
+	thisListener.Stack.Push(match)
 }
 
-// ExitThenExpression is called when production thenExpression is exited.
-func (thisListener *GruleV3ParserListener) ExitThenExpression(ctx *grulev3.ThenExpressionContext) {
+// ExitMatchExpression is called when production matchExpression is exited.
+func (thisListener *GruleV3ParserListener) ExitMatchExpression(ctx *grulev3.MatchExpressionContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	thenExpr, popOk := thisListener.Stack.Pop().(*ast.ThenExpression)
+	match, popOk := thisListener.Stack.Pop().(*ast.MatchExpression)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	receiver, popOk := thisListener.Stack.Peek().(ast.ThenExpressionReceiver)
+	receiver, popOk := thisListener.Stack.Peek().(ast.MatchExpressionReceiver)
 	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	err := receiver.AcceptThenExpression(thenExpr)
+	err := receiver.AcceptMatchExpression(match)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
 	}
 }
 
-// EnterAssignment is called when production assignment is entered.
-func (thisListener *GruleV3ParserListener) EnterAssignment(ctx *grulev3.AssignmentContext) {
+// EnterMatchArm is called when production matchArm is entered.
+func (thisListener *GruleV3ParserListener) EnterMatchArm(ctx *grulev3.MatchArmContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	assign := ast.NewAssignment()
-	assign.GrlText = ctx.GetText()
-	thisListener.Stack.Push(assign)
+	arm := ast.NewMatchArm()
+	arm.GrlText = ctx.GetText()
+	arm.Default = ctx.UNDERSCORE() != nil
+	thisListener.Stack.Push(arm)
 }
 
-// ExitAssignment is called when production assignment is exited.
-func (thisListener *GruleV3ParserListener) ExitAssignment(ctx *grulev3.AssignmentContext) {
+// ExitMatchArm is called when production matchArm is exited.
+func (thisListener *GruleV3ParserListener) ExitMatchArm(ctx *grulev3.MatchArmContext) {
 	if thisListener.StopParse {
 
 		return
 	}
-	assign, okPop := thisListener.Stack.Pop().(*ast.Assignment)
-	if !okPop {
+	arm, popOk := thisListener.Stack.Pop().(*ast.MatchArm)
+	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	receiver, okPop := thisListener.Stack.Peek().(ast.AssignmentReceiver)
-	if !okPop {
+	receiver, popOk := thisListener.Stack.Peek().(ast.MatchArmReceiver)
+	if !popOk {
 		thisListener.StopParse = true
 
 		return
 	}
-	assign.IsAssign = ctx.ASSIGN() != nil
-	assign.IsPlusAssign = ctx.PLUS_ASIGN() != nil
-	assign.IsMinusAssign = ctx.MINUS_ASIGN() != nil
-	assign.IsDivAssign = ctx.DIV_ASIGN() != nil
-	assign.IsMulAssign = ctx.MUL_ASIGN() != nil
-
-	err := receiver.AcceptAssignment(assign)
+	err := receiver.AcceptMatchArm(arm)
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
@@ -439,8 +1079,24 @@ func (thisListener *GruleV3ParserListener) ExitExpression(ctx *grulev3.Expressio
 	if ctx.LR_BRACKET() != nil && ctx.RR_BRACKET() != nil && ctx.NEGATION() != nil {
 		expr.Negated = ctx.NEGATION() != nil
 	}
+	if ctx.SIMPLENAME() != nil {
+		// within is a contextual keyword, it sets the tolerance of the ~== comparison it follows.
+		if !thisListener.expectKeyword("within", ctx.SIMPLENAME()) {
 
-	err := exprRec.AcceptExpression(thisListener.KnowledgeBase.WorkingMemory.AddExpression(expr))
+			return
+		}
+		expr.Operator = ast.OpApproxEq
+	}
+	if parent, ok := ctx.GetParent().(*grulev3.ExpressionContext); ok && parent.SIMPLENAME() != nil && parent.Expression(2) == ctx {
+		// the third expression of a ~== comparison is its tolerance rather than one of its sides.
+		if comparison, ok := exprRec.(*ast.Expression); ok {
+			comparison.Tolerance = thisListener.workingMemory().AddExpression(expr)
+		}
+
+		return
+	}
+
+	err := exprRec.AcceptExpression(thisListener.workingMemory().AddExpression(expr))
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
@@ -506,25 +1162,33 @@ func (thisListener *GruleV3ParserListener) EnterComparisonOperator(ctx *grulev3.
 
 		return
 	}
-	expr, ok := thisListener.Stack.Peek().(*ast.Expression)
-	if !ok {
-		thisListener.StopParse = true
-
-		return
-	}
-	switch ctx.GetText() {
+	var operator int
+	switch strings.ToLower(ctx.GetText()) {
 	case "<":
-		expr.Operator = ast.OpLT
+		operator = ast.OpLT
 	case "<=":
-		expr.Operator = ast.OpLTE
+		operator = ast.OpLTE
 	case ">":
-		expr.Operator = ast.OpGT
+		operator = ast.OpGT
 	case ">=":
-		expr.Operator = ast.OpGTE
+		operator = ast.OpGTE
 	case "==":
-		expr.Operator = ast.OpEq
+		operator = ast.OpEq
 	case "!=":
-		expr.Operator = ast.OpNEq
+		operator = ast.OpNEq
+	case "~==":
+		operator = ast.OpApproxEq
+	case "in":
+		operator = ast.OpIn
+	}
+	// the operator either belongs to a comparison expression, or it prefixes the pattern of a match arm.
+	switch node := thisListener.Stack.Peek().(type) {
+	case *ast.Expression:
+		node.Operator = operator
+	case *ast.MatchArm:
+		node.Operator = operator
+	default:
+		thisListener.StopParse = true
 	}
 }
 
@@ -600,7 +1264,7 @@ func (thisListener *GruleV3ParserListener) ExitExpressionAtom(ctx *grulev3.Expre
 	}
 	expressionAtm.Negated = ctx.NEGATION() != nil
 
-	err := expr.AcceptExpressionAtom(thisListener.KnowledgeBase.WorkingMemory.AddExpressionAtom(expressionAtm))
+	err := expr.AcceptExpressionAtom(thisListener.workingMemory().AddExpressionAtom(expressionAtm))
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
@@ -643,6 +1307,58 @@ func (thisListener *GruleV3ParserListener) ExitArrayMapSelector(ctx *grulev3.Arr
 	}
 }
 
+// EnterQuantifier is called when production quantifier is entered.
+func (thisListener *GruleV3ParserListener) EnterQuantifier(ctx *grulev3.QuantifierContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	quantifier := ast.NewQuantifier()
+	quantifier.GrlText = ctx.GetText()
+	if keyword := ctx.SIMPLENAME(0); keyword != nil && strings.EqualFold(keyword.GetText(), ast.QuantifierForAll) {
+		quantifier.ForAll = true
+	} else if !thisListener.expectKeyword(ast.QuantifierExists, keyword) {
+
+		return
+	}
+	name := ctx.IN()
+	if name == nil {
+		name = ctx.SIMPLENAME(1)
+	}
+	if name == nil {
+		thisListener.StopParse = true
+
+		return
+	}
+	quantifier.Name = name.GetText()
+	thisListener.Stack.Push(quantifier)
+}
+
+// ExitQuantifier is called when production quantifier is exited.
+func (thisListener *GruleV3ParserListener) ExitQuantifier(ctx *grulev3.QuantifierContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	quantifier, popOk := thisListener.Stack.Pop().(*ast.Quantifier)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.QuantifierReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := receiver.AcceptQuantifier(quantifier)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
 // EnterFunctionCall is called when production functionCall is entered.
 func (thisListener *GruleV3ParserListener) EnterFunctionCall(ctx *grulev3.FunctionCallContext) {
 	if thisListener.StopParse {
@@ -650,7 +1366,10 @@ func (thisListener *GruleV3ParserListener) EnterFunctionCall(ctx *grulev3.Functi
 		return
 	}
 	fun := ast.NewFunctionCall()
-	fun.FunctionName = ctx.SIMPLENAME().GetText()
+	fun.FunctionName = ctx.GetStart().GetText()
+	if thisListener.testEntry != nil && strings.EqualFold(fun.FunctionName, ast.TestFiredFunction) {
+		fun.FunctionName = ast.TestFiredFunction
+	}
 	thisListener.Stack.Push(fun)
 }
 
@@ -708,7 +1427,7 @@ func (thisListener *GruleV3ParserListener) ExitArgumentList(ctx *grulev3.Argumen
 
 		return
 	}
-	LoggerV3.Tracef("Adding Argument List To Receiver")
+	thisListener.log().Tracef("Adding Argument List To Receiver")
 	err := argListRec.AcceptArgumentList(argList)
 	if err != nil {
 		thisListener.StopParse = true
@@ -723,8 +1442,9 @@ func (thisListener *GruleV3ParserListener) EnterVariable(ctx *grulev3.VariableCo
 		return
 	}
 	vari := ast.NewVariable()
-	if ctx.SIMPLENAME() != nil && len(ctx.SIMPLENAME().GetText()) > 0 {
-		vari.Name = ctx.SIMPLENAME().GetText()
+	if ctx.SIMPLENAME() != nil || ctx.IN() != nil {
+		// in is only an operator between two expressions, it may still name a fact.
+		vari.Name = ctx.GetText()
 	}
 	if ctx.MemberVariable() != nil && len(ctx.MemberVariable().GetText()) > 0 {
 		vari.Name = ctx.MemberVariable().GetText()[1:]
@@ -752,7 +1472,7 @@ func (thisListener *GruleV3ParserListener) ExitVariable(ctx *grulev3.VariableCon
 		return
 	}
 
-	err := variRec.AcceptVariable(thisListener.KnowledgeBase.WorkingMemory.AddVariable(vari))
+	err := variRec.AcceptVariable(thisListener.workingMemory().AddVariable(vari))
 	if err != nil {
 		thisListener.StopParse = true
 		thisListener.ErrorCallback.AddError(err)
@@ -770,7 +1490,7 @@ func (thisListener *GruleV3ParserListener) ExitMemberVariable(ctx *grulev3.Membe
 
 		return
 	}
-	vari.AcceptMemberVariable(ctx.SIMPLENAME().GetText())
+	vari.AcceptMemberVariable(ctx.GetText()[1:])
 }
 
 // EnterConstant is called when production constant is entered.
@@ -837,6 +1557,149 @@ func (thisListener *GruleV3ParserListener) ExitStringLiteral(ctx *grulev3.String
 	receiver.AcceptStringLiteral(&ast.StringLiteral{String: dec})
 }
 
+// EnterQuantityLiteral is called when production quantityLiteral is entered.
+func (thisListener *GruleV3ParserListener) EnterQuantityLiteral(ctx *grulev3.QuantityLiteralContext) {
+}
+
+// ExitQuantityLiteral is called when production quantityLiteral is exited.
+func (thisListener *GruleV3ParserListener) ExitQuantityLiteral(ctx *grulev3.QuantityLiteralContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	var number, unit string
+	switch {
+	case ctx.QUANTITY_LIT() != nil:
+		text := ctx.QUANTITY_LIT().GetText()
+		split := strings.IndexFunc(text, func(r rune) bool {
+
+			return (r < '0' || r > '9') && r != '.'
+		})
+		number, unit = text[:split], text[split:]
+		if ctx.MINUS() != nil {
+			number = "-" + number
+		}
+	case ctx.MOD() != nil:
+		unit = pkg.PercentUnit
+	default:
+		unit = ctx.SIMPLENAME().GetText()
+	}
+	if ctx.DecimalLiteral() != nil {
+		number = ctx.DecimalLiteral().GetText()
+	} else if ctx.DecimalFloatLiteral() != nil {
+		number = ctx.DecimalFloatLiteral().GetText()
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+
+		return
+	}
+	quantity, err := thisListener.Units.NewQuantity(value, unit)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(fmt.Errorf("error parsing quantity %s. got %w", ctx.GetText(), err))
+
+		return
+	}
+	receiver, ok := thisListener.Stack.Peek().(ast.QuantityLiteralReceiver)
+	if !ok {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver.AcceptQuantityLiteral(&ast.QuantityLiteral{Quantity: quantity})
+}
+
+// EnterSuffixLiteral is called when production suffixLiteral is entered.
+func (thisListener *GruleV3ParserListener) EnterSuffixLiteral(ctx *grulev3.SuffixLiteralContext) {
+}
+
+// ExitSuffixLiteral is called when production suffixLiteral is exited.
+func (thisListener *GruleV3ParserListener) ExitSuffixLiteral(ctx *grulev3.SuffixLiteralContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	literal := ctx.SUFFIX_LIT().GetText()
+	if ctx.MINUS() != nil {
+		literal = "-" + literal
+	}
+	value, err := thisListener.LiteralSuffixes.Parse(literal)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+
+		return
+	}
+	receiver, ok := thisListener.Stack.Peek().(ast.SuffixLiteralReceiver)
+	if !ok {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver.AcceptSuffixLiteral(&ast.SuffixLiteral{Literal: literal, Value: value})
+}
+
+// EnterCollectionLiteral is called when production collectionLiteral is entered.
+func (thisListener *GruleV3ParserListener) EnterCollectionLiteral(ctx *grulev3.CollectionLiteralContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	thisListener.Stack.Push(ast.NewCollectionLiteral())
+}
+
+// ExitCollectionLiteral is called when production collectionLiteral is exited.
+func (thisListener *GruleV3ParserListener) ExitCollectionLiteral(ctx *grulev3.CollectionLiteralContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	lit, popOk := thisListener.Stack.Pop().(*ast.CollectionLiteral)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	receiver, popOk := thisListener.Stack.Peek().(ast.CollectionLiteralReceiver)
+	if !popOk {
+		thisListener.StopParse = true
+
+		return
+	}
+	lit.List = ctx.LS_BRACKET() != nil
+	err := receiver.AcceptCollectionLiteral(lit)
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(fmt.Errorf("error parsing collection %s. got %w", ctx.GetText(), err))
+	}
+}
+
+// EnterCollectionElement is called when production collectionElement is entered.
+func (thisListener *GruleV3ParserListener) EnterCollectionElement(ctx *grulev3.CollectionElementContext) {
+}
+
+// ExitCollectionElement is called when production collectionElement is exited.
+func (thisListener *GruleV3ParserListener) ExitCollectionElement(ctx *grulev3.CollectionElementContext) {
+	if thisListener.StopParse {
+
+		return
+	}
+	lit, ok := thisListener.Stack.Peek().(*ast.CollectionLiteral)
+	if !ok {
+		thisListener.StopParse = true
+
+		return
+	}
+	err := lit.CloseElement()
+	if err != nil {
+		thisListener.StopParse = true
+		thisListener.ErrorCallback.AddError(err)
+	}
+}
+
 // EnterBooleanLiteral is called when production booleanLiteral is entered.
 func (thisListener *GruleV3ParserListener) EnterBooleanLiteral(ctx *grulev3.BooleanLiteralContext) {}
 
@@ -2,49 +2,117 @@ grammar grulev3;
 
 // PARSER HERE
 grl
-    : ruleEntry* EOF
+    : (ruleEntry | testEntry | haltEntry)* EOF
     ;
 
 ruleEntry
-    : RULE ruleName ruleDescription? salience? LR_BRACE whenScope thenScope RR_BRACE
+    : ruleAnnotation* RULE ruleName ruleDescription? ruleId? salience? maxFires? cooldown? criticality? LR_BRACE whenScope thenScope elseScope? RR_BRACE
+    ;
+
+ruleAnnotation
+    : AT SIMPLENAME LR_BRACKET stringLiteral (',' stringLiteral)* RR_BRACKET
+    ;
+
+testEntry
+    : SIMPLENAME stringLiteral LR_BRACE givenScope? expectScope RR_BRACE
+    ;
+
+haltEntry
+    : SIMPLENAME WHEN expression SEMICOLON?
+    ;
+
+givenScope
+    : SIMPLENAME LR_BRACE thenExpressionList? RR_BRACE
+    ;
+
+expectScope
+    : SIMPLENAME expression SEMICOLON?
     ;
 
 salience
     : SALIENCE integerLiteral
     ;
 
+maxFires
+    : SIMPLENAME MINUS SIMPLENAME integerLiteral PER_EXECUTION?
+    ;
+
+cooldown
+    : SIMPLENAME DURATION_LIT
+    ;
+
+criticality
+    : SIMPLENAME SIMPLENAME
+    ;
+
 ruleName
     : SIMPLENAME
+    | IN
     ;
 
 ruleDescription
     : DQUOTA_STRING | SQUOTA_STRING
     ;
 
+ruleId
+    : SIMPLENAME stringLiteral
+    ;
+
 whenScope
     : WHEN  expression
     ;
 
 thenScope
-    : THEN  thenExpressionList
+    : THEN  (scriptBlock | thenExpressionList)
+    ;
+
+elseScope
+    : SIMPLENAME LR_BRACE thenExpressionList RR_BRACE
+    ;
+
+scriptBlock
+    : SIMPLENAME SCRIPT_LIT
     ;
 
 thenExpressionList
-    : (thenExpression SEMICOLON)+
+    : thenExpression SEMICOLON (thenExpression SEMICOLON)*
     ;
 
 thenExpression
     : assignment
+    | collectStatement
+    | switchStatement
     | expressionAtom
     ;
 
+collectStatement
+    : SIMPLENAME (SIMPLENAME | IN) SIMPLENAME expression (SIMPLENAME expression)?
+    ;
+
+switchStatement
+    : SIMPLENAME expression LR_BRACE switchCase switchCase* RR_BRACE
+    ;
+
+switchCase
+    : SIMPLENAME (expression (',' expression)*)? LR_BRACE thenExpressionList? RR_BRACE
+    ;
+
 assignment
-    : variable (ASSIGN | PLUS_ASIGN | MINUS_ASIGN | DIV_ASIGN | MUL_ASIGN) expression
+    : variable (ASSIGN | PLUS_ASIGN | MINUS_ASIGN | DIV_ASIGN | MUL_ASIGN) (matchExpression | expression)
+    ;
+
+matchExpression
+    : SIMPLENAME expression LR_BRACE matchArm (',' matchArm)* ','? RR_BRACE
+    ;
+
+matchArm
+    : (UNDERSCORE | comparisonOperator? expression) ARROW expression
     ;
 
 expression
     : expression mulDivOperators expression
     | expression addMinusOperators expression
+    | expression APPROX_EQUALS expression SIMPLENAME expression
     | expression comparisonOperator expression
     | expression andLogicOperator expression
     | expression orLogicOperator expression
@@ -61,7 +129,7 @@ addMinusOperators
     ;
 
 comparisonOperator
-    : GT | LT | GTE | LTE | EQUALS | NOTEQUALS
+    : GT | LT | GTE | LTE | EQUALS | NOTEQUALS | APPROX_EQUALS | IN
     ;
 
 andLogicOperator
@@ -75,6 +143,7 @@ orLogicOperator
 expressionAtom
     : constant
     | variable
+    | quantifier
     | functionCall
     | expressionAtom methodCall
     | expressionAtom memberVariable
@@ -86,14 +155,27 @@ constant
     : stringLiteral
     | integerLiteral
     | floatLiteral
+    | quantityLiteral
+    | suffixLiteral
     | booleanLiteral
+    | collectionLiteral
     | NIL_LITERAL
     ;
 
+collectionLiteral
+    : LR_BRACE (collectionElement (',' collectionElement)*)? RR_BRACE
+    | LS_BRACKET (collectionElement (',' collectionElement)*)? RS_BRACKET
+    ;
+
+collectionElement
+    : constant (COLON constant)?
+    ;
+
 variable
     : variable memberVariable
     | variable arrayMapSelector
     | SIMPLENAME
+    | IN
     ;
 
 arrayMapSelector
@@ -101,11 +183,15 @@ arrayMapSelector
     ;
 
 memberVariable
-    : DOT SIMPLENAME
+    : DOT (SIMPLENAME | IN)
+    ;
+
+quantifier
+    : SIMPLENAME LR_BRACKET expression ',' (SIMPLENAME | IN) LAMBDA expression RR_BRACKET
     ;
 
 functionCall
-    : SIMPLENAME LR_BRACKET argumentList? RR_BRACKET
+    : (SIMPLENAME | IN) LR_BRACKET argumentList? RR_BRACKET
     ;
 
 methodCall
@@ -147,6 +233,15 @@ octalLiteral
     : MINUS? OCT_LIT
     ;
 
+quantityLiteral
+    : MINUS? QUANTITY_LIT
+    | (decimalLiteral | decimalFloatLiteral) (MOD | SIMPLENAME)
+    ;
+
+suffixLiteral
+    : MINUS? SUFFIX_LIT
+    ;
+
 stringLiteral
     : DQUOTA_STRING | SQUOTA_STRING
     ;
@@ -231,8 +326,12 @@ FALSE                       : F A L S E ;
 NIL_LITERAL                 : N I L ;
 NEGATION                    : '!' ;
 SALIENCE                    : S A L I E N C E ;
+PER_EXECUTION               : P E R [ \t\r\n]+ E X E C U T I O N ;
+IN                          : I N ;
 
 EQUALS                      : '==' ;
+ARROW                       : '=>' ;
+LAMBDA                      : '->' ;
 ASSIGN                      : '=' ;
 PLUS_ASIGN                  : '+=' ;
 MINUS_ASIGN                 : '-=' ;
@@ -243,15 +342,22 @@ LT                          : '<' ;
 GTE                         : '>=' ;
 LTE                         : '<=' ;
 NOTEQUALS                   : '!=' ;
+APPROX_EQUALS               : '~==' ;
 
 BITAND                      : '&';
 BITOR                       : '|';
+UNDERSCORE                  : '_';
+AT                          : '@';
+COLON                       : ':';
 
 SIMPLENAME                  : ISC IC*;
 
 DQUOTA_STRING               : '"' ( '\\'. | '""' | ~('"'| '\\') )* '"';
 SQUOTA_STRING               : '\'' ('\\'. | '\'\'' | ~('\'' | '\\'))* '\'';
+SCRIPT_LIT                  : '```' .*? '```';
+
 
+DURATION_LIT                : (DEC_DIGITS ('.' DEC_DIGITS)? ('ns' | 'us' | '\u00B5s' | 'ms' | 's' | 'm' | 'h'))+ ;
 
 DECIMAL_FLOAT_LIT           : DEC_LIT DOT DEC_DIGITS DECIMAL_EXPONENT?
                             | DEC_LIT DECIMAL_EXPONENT
@@ -278,6 +384,12 @@ DEC_LIT                     : '0'
 HEX_LIT                     : '0' X HEX_DIGITS;
 OCT_LIT                     : '0' OCT_DIGITS;
 
+QUANTITY_LIT                : DEC_DIGITS ('.' DEC_DIGITS)? ISC IC*;
+
+SUFFIX_LIT                  : DEC_DIGITS ('.' DEC_DIGITS)? '_' ISC IC*
+                            | DEC_DIGITS '-' DEC_DIGITS '-' DEC_DIGITS '_' ISC IC*
+                            ;
+
 fragment HEX_DIGITS         : HEX_DIGIT+;
 fragment DEC_DIGITS         : DEC_DIGIT+;
 fragment OCT_DIGITS         : OCT_DIGIT+;
@@ -32,12 +32,60 @@ func (s *Basegrulev3Listener) EnterRuleEntry(ctx *RuleEntryContext) {}
 // ExitRuleEntry is called when production ruleEntry is exited.
 func (s *Basegrulev3Listener) ExitRuleEntry(ctx *RuleEntryContext) {}
 
+// EnterRuleAnnotation is called when production ruleAnnotation is entered.
+func (s *Basegrulev3Listener) EnterRuleAnnotation(ctx *RuleAnnotationContext) {}
+
+// ExitRuleAnnotation is called when production ruleAnnotation is exited.
+func (s *Basegrulev3Listener) ExitRuleAnnotation(ctx *RuleAnnotationContext) {}
+
+// EnterTestEntry is called when production testEntry is entered.
+func (s *Basegrulev3Listener) EnterTestEntry(ctx *TestEntryContext) {}
+
+// ExitTestEntry is called when production testEntry is exited.
+func (s *Basegrulev3Listener) ExitTestEntry(ctx *TestEntryContext) {}
+
+// EnterHaltEntry is called when production haltEntry is entered.
+func (s *Basegrulev3Listener) EnterHaltEntry(ctx *HaltEntryContext) {}
+
+// ExitHaltEntry is called when production haltEntry is exited.
+func (s *Basegrulev3Listener) ExitHaltEntry(ctx *HaltEntryContext) {}
+
+// EnterGivenScope is called when production givenScope is entered.
+func (s *Basegrulev3Listener) EnterGivenScope(ctx *GivenScopeContext) {}
+
+// ExitGivenScope is called when production givenScope is exited.
+func (s *Basegrulev3Listener) ExitGivenScope(ctx *GivenScopeContext) {}
+
+// EnterExpectScope is called when production expectScope is entered.
+func (s *Basegrulev3Listener) EnterExpectScope(ctx *ExpectScopeContext) {}
+
+// ExitExpectScope is called when production expectScope is exited.
+func (s *Basegrulev3Listener) ExitExpectScope(ctx *ExpectScopeContext) {}
+
 // EnterSalience is called when production salience is entered.
 func (s *Basegrulev3Listener) EnterSalience(ctx *SalienceContext) {}
 
 // ExitSalience is called when production salience is exited.
 func (s *Basegrulev3Listener) ExitSalience(ctx *SalienceContext) {}
 
+// EnterMaxFires is called when production maxFires is entered.
+func (s *Basegrulev3Listener) EnterMaxFires(ctx *MaxFiresContext) {}
+
+// ExitMaxFires is called when production maxFires is exited.
+func (s *Basegrulev3Listener) ExitMaxFires(ctx *MaxFiresContext) {}
+
+// EnterCooldown is called when production cooldown is entered.
+func (s *Basegrulev3Listener) EnterCooldown(ctx *CooldownContext) {}
+
+// ExitCooldown is called when production cooldown is exited.
+func (s *Basegrulev3Listener) ExitCooldown(ctx *CooldownContext) {}
+
+// EnterCriticality is called when production criticality is entered.
+func (s *Basegrulev3Listener) EnterCriticality(ctx *CriticalityContext) {}
+
+// ExitCriticality is called when production criticality is exited.
+func (s *Basegrulev3Listener) ExitCriticality(ctx *CriticalityContext) {}
+
 // EnterRuleName is called when production ruleName is entered.
 func (s *Basegrulev3Listener) EnterRuleName(ctx *RuleNameContext) {}
 
@@ -50,6 +98,12 @@ func (s *Basegrulev3Listener) EnterRuleDescription(ctx *RuleDescriptionContext)
 // ExitRuleDescription is called when production ruleDescription is exited.
 func (s *Basegrulev3Listener) ExitRuleDescription(ctx *RuleDescriptionContext) {}
 
+// EnterRuleId is called when production ruleId is entered.
+func (s *Basegrulev3Listener) EnterRuleId(ctx *RuleIdContext) {}
+
+// ExitRuleId is called when production ruleId is exited.
+func (s *Basegrulev3Listener) ExitRuleId(ctx *RuleIdContext) {}
+
 // EnterWhenScope is called when production whenScope is entered.
 func (s *Basegrulev3Listener) EnterWhenScope(ctx *WhenScopeContext) {}
 
@@ -62,6 +116,18 @@ func (s *Basegrulev3Listener) EnterThenScope(ctx *ThenScopeContext) {}
 // ExitThenScope is called when production thenScope is exited.
 func (s *Basegrulev3Listener) ExitThenScope(ctx *ThenScopeContext) {}
 
+// EnterElseScope is called when production elseScope is entered.
+func (s *Basegrulev3Listener) EnterElseScope(ctx *ElseScopeContext) {}
+
+// ExitElseScope is called when production elseScope is exited.
+func (s *Basegrulev3Listener) ExitElseScope(ctx *ElseScopeContext) {}
+
+// EnterScriptBlock is called when production scriptBlock is entered.
+func (s *Basegrulev3Listener) EnterScriptBlock(ctx *ScriptBlockContext) {}
+
+// ExitScriptBlock is called when production scriptBlock is exited.
+func (s *Basegrulev3Listener) ExitScriptBlock(ctx *ScriptBlockContext) {}
+
 // EnterThenExpressionList is called when production thenExpressionList is entered.
 func (s *Basegrulev3Listener) EnterThenExpressionList(ctx *ThenExpressionListContext) {}
 
@@ -74,12 +140,42 @@ func (s *Basegrulev3Listener) EnterThenExpression(ctx *ThenExpressionContext) {}
 // ExitThenExpression is called when production thenExpression is exited.
 func (s *Basegrulev3Listener) ExitThenExpression(ctx *ThenExpressionContext) {}
 
+// EnterCollectStatement is called when production collectStatement is entered.
+func (s *Basegrulev3Listener) EnterCollectStatement(ctx *CollectStatementContext) {}
+
+// ExitCollectStatement is called when production collectStatement is exited.
+func (s *Basegrulev3Listener) ExitCollectStatement(ctx *CollectStatementContext) {}
+
+// EnterSwitchStatement is called when production switchStatement is entered.
+func (s *Basegrulev3Listener) EnterSwitchStatement(ctx *SwitchStatementContext) {}
+
+// ExitSwitchStatement is called when production switchStatement is exited.
+func (s *Basegrulev3Listener) ExitSwitchStatement(ctx *SwitchStatementContext) {}
+
+// EnterSwitchCase is called when production switchCase is entered.
+func (s *Basegrulev3Listener) EnterSwitchCase(ctx *SwitchCaseContext) {}
+
+// ExitSwitchCase is called when production switchCase is exited.
+func (s *Basegrulev3Listener) ExitSwitchCase(ctx *SwitchCaseContext) {}
+
 // EnterAssignment is called when production assignment is entered.
 func (s *Basegrulev3Listener) EnterAssignment(ctx *AssignmentContext) {}
 
 // ExitAssignment is called when production assignment is exited.
 func (s *Basegrulev3Listener) ExitAssignment(ctx *AssignmentContext) {}
 
+// EnterMatchExpression is called when production matchExpression is entered.
+func (s *Basegrulev3Listener) EnterMatchExpression(ctx *MatchExpressionContext) {}
+
+// ExitMatchExpression is called when production matchExpression is exited.
+func (s *Basegrulev3Listener) ExitMatchExpression(ctx *MatchExpressionContext) {}
+
+// EnterMatchArm is called when production matchArm is entered.
+func (s *Basegrulev3Listener) EnterMatchArm(ctx *MatchArmContext) {}
+
+// ExitMatchArm is called when production matchArm is exited.
+func (s *Basegrulev3Listener) ExitMatchArm(ctx *MatchArmContext) {}
+
 // EnterExpression is called when production expression is entered.
 func (s *Basegrulev3Listener) EnterExpression(ctx *ExpressionContext) {}
 
@@ -128,6 +224,18 @@ func (s *Basegrulev3Listener) EnterConstant(ctx *ConstantContext) {}
 // ExitConstant is called when production constant is exited.
 func (s *Basegrulev3Listener) ExitConstant(ctx *ConstantContext) {}
 
+// EnterCollectionLiteral is called when production collectionLiteral is entered.
+func (s *Basegrulev3Listener) EnterCollectionLiteral(ctx *CollectionLiteralContext) {}
+
+// ExitCollectionLiteral is called when production collectionLiteral is exited.
+func (s *Basegrulev3Listener) ExitCollectionLiteral(ctx *CollectionLiteralContext) {}
+
+// EnterCollectionElement is called when production collectionElement is entered.
+func (s *Basegrulev3Listener) EnterCollectionElement(ctx *CollectionElementContext) {}
+
+// ExitCollectionElement is called when production collectionElement is exited.
+func (s *Basegrulev3Listener) ExitCollectionElement(ctx *CollectionElementContext) {}
+
 // EnterVariable is called when production variable is entered.
 func (s *Basegrulev3Listener) EnterVariable(ctx *VariableContext) {}
 
@@ -146,6 +254,12 @@ func (s *Basegrulev3Listener) EnterMemberVariable(ctx *MemberVariableContext) {}
 // ExitMemberVariable is called when production memberVariable is exited.
 func (s *Basegrulev3Listener) ExitMemberVariable(ctx *MemberVariableContext) {}
 
+// EnterQuantifier is called when production quantifier is entered.
+func (s *Basegrulev3Listener) EnterQuantifier(ctx *QuantifierContext) {}
+
+// ExitQuantifier is called when production quantifier is exited.
+func (s *Basegrulev3Listener) ExitQuantifier(ctx *QuantifierContext) {}
+
 // EnterFunctionCall is called when production functionCall is entered.
 func (s *Basegrulev3Listener) EnterFunctionCall(ctx *FunctionCallContext) {}
 
@@ -206,6 +320,18 @@ func (s *Basegrulev3Listener) EnterOctalLiteral(ctx *OctalLiteralContext) {}
 // ExitOctalLiteral is called when production octalLiteral is exited.
 func (s *Basegrulev3Listener) ExitOctalLiteral(ctx *OctalLiteralContext) {}
 
+// EnterQuantityLiteral is called when production quantityLiteral is entered.
+func (s *Basegrulev3Listener) EnterQuantityLiteral(ctx *QuantityLiteralContext) {}
+
+// ExitQuantityLiteral is called when production quantityLiteral is exited.
+func (s *Basegrulev3Listener) ExitQuantityLiteral(ctx *QuantityLiteralContext) {}
+
+// EnterSuffixLiteral is called when production suffixLiteral is entered.
+func (s *Basegrulev3Listener) EnterSuffixLiteral(ctx *SuffixLiteralContext) {}
+
+// ExitSuffixLiteral is called when production suffixLiteral is exited.
+func (s *Basegrulev3Listener) ExitSuffixLiteral(ctx *SuffixLiteralContext) {}
+
 // EnterStringLiteral is called when production stringLiteral is entered.
 func (s *Basegrulev3Listener) EnterStringLiteral(ctx *StringLiteralContext) {}
 
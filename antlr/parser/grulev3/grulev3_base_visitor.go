@@ -15,10 +15,42 @@ func (v *Basegrulev3Visitor) VisitRuleEntry(ctx *RuleEntryContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitRuleAnnotation(ctx *RuleAnnotationContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitTestEntry(ctx *TestEntryContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitHaltEntry(ctx *HaltEntryContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitGivenScope(ctx *GivenScopeContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitExpectScope(ctx *ExpectScopeContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitSalience(ctx *SalienceContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitMaxFires(ctx *MaxFiresContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitCooldown(ctx *CooldownContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitCriticality(ctx *CriticalityContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitRuleName(ctx *RuleNameContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -27,6 +59,10 @@ func (v *Basegrulev3Visitor) VisitRuleDescription(ctx *RuleDescriptionContext) i
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitRuleId(ctx *RuleIdContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitWhenScope(ctx *WhenScopeContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -35,6 +71,14 @@ func (v *Basegrulev3Visitor) VisitThenScope(ctx *ThenScopeContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitElseScope(ctx *ElseScopeContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitScriptBlock(ctx *ScriptBlockContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitThenExpressionList(ctx *ThenExpressionListContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -43,10 +87,30 @@ func (v *Basegrulev3Visitor) VisitThenExpression(ctx *ThenExpressionContext) int
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitCollectStatement(ctx *CollectStatementContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitSwitchStatement(ctx *SwitchStatementContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitSwitchCase(ctx *SwitchCaseContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitAssignment(ctx *AssignmentContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitMatchExpression(ctx *MatchExpressionContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitMatchArm(ctx *MatchArmContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitExpression(ctx *ExpressionContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -79,6 +143,14 @@ func (v *Basegrulev3Visitor) VisitConstant(ctx *ConstantContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitCollectionLiteral(ctx *CollectionLiteralContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitCollectionElement(ctx *CollectionElementContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitVariable(ctx *VariableContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -91,6 +163,10 @@ func (v *Basegrulev3Visitor) VisitMemberVariable(ctx *MemberVariableContext) int
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitQuantifier(ctx *QuantifierContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitFunctionCall(ctx *FunctionCallContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -131,6 +207,14 @@ func (v *Basegrulev3Visitor) VisitOctalLiteral(ctx *OctalLiteralContext) interfa
 	return v.VisitChildren(ctx)
 }
 
+func (v *Basegrulev3Visitor) VisitQuantityLiteral(ctx *QuantityLiteralContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *Basegrulev3Visitor) VisitSuffixLiteral(ctx *SuffixLiteralContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *Basegrulev3Visitor) VisitStringLiteral(ctx *StringLiteralContext) interface{} {
 	return v.VisitChildren(ctx)
 }
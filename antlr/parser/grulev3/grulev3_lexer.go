@@ -45,18 +45,21 @@ func grulev3lexerLexerInit() {
 	staticData.LiteralNames = []string{
 		"", "','", "'+'", "'-'", "'/'", "'*'", "'%'", "'.'", "';'", "'{'", "'}'",
 		"'('", "')'", "'['", "']'", "", "", "", "'&&'", "'||'", "", "", "",
-		"'!'", "", "'=='", "'='", "'+='", "'-='", "'/='", "'*='", "'>'", "'<'",
-		"'>='", "'<='", "'!='", "'&'", "'|'",
+		"'!'", "", "", "", "'=='", "'=>'", "'->'", "'='", "'+='", "'-='", "'/='",
+		"'*='", "'>'", "'<'", "'>='", "'<='", "'!='", "'~=='", "'&'", "'|'",
+		"'_'", "'@'", "':'",
 	}
 	staticData.SymbolicNames = []string{
 		"", "", "PLUS", "MINUS", "DIV", "MUL", "MOD", "DOT", "SEMICOLON", "LR_BRACE",
 		"RR_BRACE", "LR_BRACKET", "RR_BRACKET", "LS_BRACKET", "RS_BRACKET",
 		"RULE", "WHEN", "THEN", "AND", "OR", "TRUE", "FALSE", "NIL_LITERAL",
-		"NEGATION", "SALIENCE", "EQUALS", "ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN",
-		"DIV_ASIGN", "MUL_ASIGN", "GT", "LT", "GTE", "LTE", "NOTEQUALS", "BITAND",
-		"BITOR", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING", "DECIMAL_FLOAT_LIT",
-		"DECIMAL_EXPONENT", "HEX_FLOAT_LIT", "HEX_EXPONENT", "DEC_LIT", "HEX_LIT",
-		"OCT_LIT", "SPACE", "COMMENT", "LINE_COMMENT",
+		"NEGATION", "SALIENCE", "PER_EXECUTION", "IN", "EQUALS", "ARROW", "LAMBDA",
+		"ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN", "DIV_ASIGN", "MUL_ASIGN", "GT",
+		"LT", "GTE", "LTE", "NOTEQUALS", "APPROX_EQUALS", "BITAND", "BITOR",
+		"UNDERSCORE", "AT", "COLON", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING",
+		"SCRIPT_LIT", "DURATION_LIT", "DECIMAL_FLOAT_LIT", "DECIMAL_EXPONENT",
+		"HEX_FLOAT_LIT", "HEX_EXPONENT", "DEC_LIT", "HEX_LIT", "OCT_LIT", "QUANTITY_LIT",
+		"SUFFIX_LIT", "SPACE", "COMMENT", "LINE_COMMENT",
 	}
 	staticData.RuleNames = []string{
 		"T__0", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L",
@@ -64,16 +67,19 @@ func grulev3lexerLexerInit() {
 		"ISC", "IC", "PLUS", "MINUS", "DIV", "MUL", "MOD", "DOT", "SEMICOLON",
 		"LR_BRACE", "RR_BRACE", "LR_BRACKET", "RR_BRACKET", "LS_BRACKET", "RS_BRACKET",
 		"RULE", "WHEN", "THEN", "AND", "OR", "TRUE", "FALSE", "NIL_LITERAL",
-		"NEGATION", "SALIENCE", "EQUALS", "ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN",
-		"DIV_ASIGN", "MUL_ASIGN", "GT", "LT", "GTE", "LTE", "NOTEQUALS", "BITAND",
-		"BITOR", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING", "DECIMAL_FLOAT_LIT",
-		"DECIMAL_EXPONENT", "HEX_FLOAT_LIT", "HEX_MANTISA", "HEX_EXPONENT",
-		"DEC_LIT", "HEX_LIT", "OCT_LIT", "HEX_DIGITS", "DEC_DIGITS", "OCT_DIGITS",
-		"DEC_DIGIT", "OCT_DIGIT", "HEX_DIGIT", "SPACE", "COMMENT", "LINE_COMMENT",
+		"NEGATION", "SALIENCE", "PER_EXECUTION", "IN", "EQUALS", "ARROW", "LAMBDA",
+		"ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN", "DIV_ASIGN", "MUL_ASIGN", "GT",
+		"LT", "GTE", "LTE", "NOTEQUALS", "APPROX_EQUALS", "BITAND", "BITOR",
+		"UNDERSCORE", "AT", "COLON", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING",
+		"SCRIPT_LIT", "DURATION_LIT", "DECIMAL_FLOAT_LIT", "DECIMAL_EXPONENT",
+		"HEX_FLOAT_LIT", "HEX_MANTISA", "HEX_EXPONENT", "DEC_LIT", "HEX_LIT",
+		"OCT_LIT", "QUANTITY_LIT", "SUFFIX_LIT", "HEX_DIGITS", "DEC_DIGITS",
+		"OCT_DIGITS", "DEC_DIGIT", "OCT_DIGIT", "HEX_DIGIT", "SPACE", "COMMENT",
+		"LINE_COMMENT",
 	}
 	staticData.PredictionContextCache = antlr.NewPredictionContextCache()
 	staticData.serializedATN = []int32{
-		4, 0, 50, 484, 6, -1, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2,
+		4, 0, 62, 619, 6, -1, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2,
 		4, 7, 4, 2, 5, 7, 5, 2, 6, 7, 6, 2, 7, 7, 7, 2, 8, 7, 8, 2, 9, 7, 9, 2,
 		10, 7, 10, 2, 11, 7, 11, 2, 12, 7, 12, 2, 13, 7, 13, 2, 14, 7, 14, 2, 15,
 		7, 15, 2, 16, 7, 16, 2, 17, 7, 17, 2, 18, 7, 18, 2, 19, 7, 19, 2, 20, 7,
@@ -89,208 +95,272 @@ func grulev3lexerLexerInit() {
 		2, 68, 7, 68, 2, 69, 7, 69, 2, 70, 7, 70, 2, 71, 7, 71, 2, 72, 7, 72, 2,
 		73, 7, 73, 2, 74, 7, 74, 2, 75, 7, 75, 2, 76, 7, 76, 2, 77, 7, 77, 2, 78,
 		7, 78, 2, 79, 7, 79, 2, 80, 7, 80, 2, 81, 7, 81, 2, 82, 7, 82, 2, 83, 7,
-		83, 2, 84, 7, 84, 1, 0, 1, 0, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 3, 1, 4,
-		1, 4, 1, 5, 1, 5, 1, 6, 1, 6, 1, 7, 1, 7, 1, 8, 1, 8, 1, 9, 1, 9, 1, 10,
-		1, 10, 1, 11, 1, 11, 1, 12, 1, 12, 1, 13, 1, 13, 1, 14, 1, 14, 1, 15, 1,
-		15, 1, 16, 1, 16, 1, 17, 1, 17, 1, 18, 1, 18, 1, 19, 1, 19, 1, 20, 1, 20,
-		1, 21, 1, 21, 1, 22, 1, 22, 1, 23, 1, 23, 1, 24, 1, 24, 1, 25, 1, 25, 1,
-		26, 1, 26, 1, 27, 1, 27, 1, 28, 1, 28, 3, 28, 230, 8, 28, 1, 29, 1, 29,
-		1, 30, 1, 30, 1, 31, 1, 31, 1, 32, 1, 32, 1, 33, 1, 33, 1, 34, 1, 34, 1,
-		35, 1, 35, 1, 36, 1, 36, 1, 37, 1, 37, 1, 38, 1, 38, 1, 39, 1, 39, 1, 40,
-		1, 40, 1, 41, 1, 41, 1, 42, 1, 42, 1, 42, 1, 42, 1, 42, 1, 43, 1, 43, 1,
-		43, 1, 43, 1, 43, 1, 44, 1, 44, 1, 44, 1, 44, 1, 44, 1, 45, 1, 45, 1, 45,
-		1, 46, 1, 46, 1, 46, 1, 47, 1, 47, 1, 47, 1, 47, 1, 47, 1, 48, 1, 48, 1,
-		48, 1, 48, 1, 48, 1, 48, 1, 49, 1, 49, 1, 49, 1, 49, 1, 50, 1, 50, 1, 51,
-		1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 52, 1, 52, 1,
-		52, 1, 53, 1, 53, 1, 54, 1, 54, 1, 54, 1, 55, 1, 55, 1, 55, 1, 56, 1, 56,
-		1, 56, 1, 57, 1, 57, 1, 57, 1, 58, 1, 58, 1, 59, 1, 59, 1, 60, 1, 60, 1,
-		60, 1, 61, 1, 61, 1, 61, 1, 62, 1, 62, 1, 62, 1, 63, 1, 63, 1, 64, 1, 64,
-		1, 65, 1, 65, 5, 65, 341, 8, 65, 10, 65, 12, 65, 344, 9, 65, 1, 66, 1,
-		66, 1, 66, 1, 66, 1, 66, 1, 66, 5, 66, 352, 8, 66, 10, 66, 12, 66, 355,
-		9, 66, 1, 66, 1, 66, 1, 67, 1, 67, 1, 67, 1, 67, 1, 67, 1, 67, 5, 67, 365,
-		8, 67, 10, 67, 12, 67, 368, 9, 67, 1, 67, 1, 67, 1, 68, 1, 68, 1, 68, 1,
-		68, 3, 68, 376, 8, 68, 1, 68, 1, 68, 1, 68, 1, 68, 1, 68, 1, 68, 3, 68,
-		384, 8, 68, 3, 68, 386, 8, 68, 1, 69, 1, 69, 1, 69, 3, 69, 391, 8, 69,
-		1, 69, 1, 69, 1, 70, 1, 70, 1, 70, 1, 70, 1, 70, 1, 71, 1, 71, 1, 71, 3,
-		71, 403, 8, 71, 1, 71, 1, 71, 1, 71, 1, 71, 3, 71, 409, 8, 71, 1, 72, 1,
-		72, 1, 72, 3, 72, 414, 8, 72, 1, 72, 1, 72, 1, 73, 1, 73, 1, 73, 3, 73,
-		421, 8, 73, 3, 73, 423, 8, 73, 1, 74, 1, 74, 1, 74, 1, 74, 1, 75, 1, 75,
-		1, 75, 1, 76, 4, 76, 433, 8, 76, 11, 76, 12, 76, 434, 1, 77, 4, 77, 438,
-		8, 77, 11, 77, 12, 77, 439, 1, 78, 4, 78, 443, 8, 78, 11, 78, 12, 78, 444,
-		1, 79, 1, 79, 1, 80, 1, 80, 1, 81, 1, 81, 1, 82, 4, 82, 454, 8, 82, 11,
-		82, 12, 82, 455, 1, 82, 1, 82, 1, 83, 1, 83, 1, 83, 1, 83, 5, 83, 464,
-		8, 83, 10, 83, 12, 83, 467, 9, 83, 1, 83, 1, 83, 1, 83, 1, 83, 1, 83, 1,
-		84, 1, 84, 1, 84, 1, 84, 5, 84, 478, 8, 84, 10, 84, 12, 84, 481, 9, 84,
-		1, 84, 1, 84, 1, 465, 0, 85, 1, 1, 3, 0, 5, 0, 7, 0, 9, 0, 11, 0, 13, 0,
-		15, 0, 17, 0, 19, 0, 21, 0, 23, 0, 25, 0, 27, 0, 29, 0, 31, 0, 33, 0, 35,
-		0, 37, 0, 39, 0, 41, 0, 43, 0, 45, 0, 47, 0, 49, 0, 51, 0, 53, 0, 55, 0,
-		57, 0, 59, 2, 61, 3, 63, 4, 65, 5, 67, 6, 69, 7, 71, 8, 73, 9, 75, 10,
-		77, 11, 79, 12, 81, 13, 83, 14, 85, 15, 87, 16, 89, 17, 91, 18, 93, 19,
-		95, 20, 97, 21, 99, 22, 101, 23, 103, 24, 105, 25, 107, 26, 109, 27, 111,
-		28, 113, 29, 115, 30, 117, 31, 119, 32, 121, 33, 123, 34, 125, 35, 127,
-		36, 129, 37, 131, 38, 133, 39, 135, 40, 137, 41, 139, 42, 141, 43, 143,
-		0, 145, 44, 147, 45, 149, 46, 151, 47, 153, 0, 155, 0, 157, 0, 159, 0,
-		161, 0, 163, 0, 165, 48, 167, 49, 169, 50, 1, 0, 36, 2, 0, 65, 65, 97,
-		97, 2, 0, 66, 66, 98, 98, 2, 0, 67, 67, 99, 99, 2, 0, 68, 68, 100, 100,
-		2, 0, 69, 69, 101, 101, 2, 0, 70, 70, 102, 102, 2, 0, 71, 71, 103, 103,
-		2, 0, 72, 72, 104, 104, 2, 0, 73, 73, 105, 105, 2, 0, 74, 74, 106, 106,
-		2, 0, 75, 75, 107, 107, 2, 0, 76, 76, 108, 108, 2, 0, 77, 77, 109, 109,
-		2, 0, 78, 78, 110, 110, 2, 0, 79, 79, 111, 111, 2, 0, 80, 80, 112, 112,
-		2, 0, 81, 81, 113, 113, 2, 0, 82, 82, 114, 114, 2, 0, 83, 83, 115, 115,
-		2, 0, 84, 84, 116, 116, 2, 0, 85, 85, 117, 117, 2, 0, 86, 86, 118, 118,
-		2, 0, 87, 87, 119, 119, 2, 0, 88, 88, 120, 120, 2, 0, 89, 89, 121, 121,
-		2, 0, 90, 90, 122, 122, 13, 0, 65, 90, 97, 122, 192, 214, 216, 246, 248,
-		767, 880, 893, 895, 8191, 8204, 8205, 8304, 8591, 11264, 12271, 12289,
-		55295, 63744, 64975, 65008, 65533, 5, 0, 48, 57, 95, 95, 183, 183, 768,
-		879, 8255, 8256, 2, 0, 34, 34, 92, 92, 2, 0, 39, 39, 92, 92, 1, 0, 49,
-		57, 1, 0, 48, 57, 1, 0, 48, 55, 3, 0, 48, 57, 65, 70, 97, 102, 3, 0, 9,
-		10, 13, 13, 32, 32, 2, 0, 10, 10, 13, 13, 475, 0, 1, 1, 0, 0, 0, 0, 59,
-		1, 0, 0, 0, 0, 61, 1, 0, 0, 0, 0, 63, 1, 0, 0, 0, 0, 65, 1, 0, 0, 0, 0,
-		67, 1, 0, 0, 0, 0, 69, 1, 0, 0, 0, 0, 71, 1, 0, 0, 0, 0, 73, 1, 0, 0, 0,
-		0, 75, 1, 0, 0, 0, 0, 77, 1, 0, 0, 0, 0, 79, 1, 0, 0, 0, 0, 81, 1, 0, 0,
-		0, 0, 83, 1, 0, 0, 0, 0, 85, 1, 0, 0, 0, 0, 87, 1, 0, 0, 0, 0, 89, 1, 0,
-		0, 0, 0, 91, 1, 0, 0, 0, 0, 93, 1, 0, 0, 0, 0, 95, 1, 0, 0, 0, 0, 97, 1,
-		0, 0, 0, 0, 99, 1, 0, 0, 0, 0, 101, 1, 0, 0, 0, 0, 103, 1, 0, 0, 0, 0,
-		105, 1, 0, 0, 0, 0, 107, 1, 0, 0, 0, 0, 109, 1, 0, 0, 0, 0, 111, 1, 0,
-		0, 0, 0, 113, 1, 0, 0, 0, 0, 115, 1, 0, 0, 0, 0, 117, 1, 0, 0, 0, 0, 119,
-		1, 0, 0, 0, 0, 121, 1, 0, 0, 0, 0, 123, 1, 0, 0, 0, 0, 125, 1, 0, 0, 0,
-		0, 127, 1, 0, 0, 0, 0, 129, 1, 0, 0, 0, 0, 131, 1, 0, 0, 0, 0, 133, 1,
-		0, 0, 0, 0, 135, 1, 0, 0, 0, 0, 137, 1, 0, 0, 0, 0, 139, 1, 0, 0, 0, 0,
-		141, 1, 0, 0, 0, 0, 145, 1, 0, 0, 0, 0, 147, 1, 0, 0, 0, 0, 149, 1, 0,
-		0, 0, 0, 151, 1, 0, 0, 0, 0, 165, 1, 0, 0, 0, 0, 167, 1, 0, 0, 0, 0, 169,
-		1, 0, 0, 0, 1, 171, 1, 0, 0, 0, 3, 173, 1, 0, 0, 0, 5, 175, 1, 0, 0, 0,
-		7, 177, 1, 0, 0, 0, 9, 179, 1, 0, 0, 0, 11, 181, 1, 0, 0, 0, 13, 183, 1,
-		0, 0, 0, 15, 185, 1, 0, 0, 0, 17, 187, 1, 0, 0, 0, 19, 189, 1, 0, 0, 0,
-		21, 191, 1, 0, 0, 0, 23, 193, 1, 0, 0, 0, 25, 195, 1, 0, 0, 0, 27, 197,
-		1, 0, 0, 0, 29, 199, 1, 0, 0, 0, 31, 201, 1, 0, 0, 0, 33, 203, 1, 0, 0,
-		0, 35, 205, 1, 0, 0, 0, 37, 207, 1, 0, 0, 0, 39, 209, 1, 0, 0, 0, 41, 211,
-		1, 0, 0, 0, 43, 213, 1, 0, 0, 0, 45, 215, 1, 0, 0, 0, 47, 217, 1, 0, 0,
-		0, 49, 219, 1, 0, 0, 0, 51, 221, 1, 0, 0, 0, 53, 223, 1, 0, 0, 0, 55, 225,
-		1, 0, 0, 0, 57, 229, 1, 0, 0, 0, 59, 231, 1, 0, 0, 0, 61, 233, 1, 0, 0,
-		0, 63, 235, 1, 0, 0, 0, 65, 237, 1, 0, 0, 0, 67, 239, 1, 0, 0, 0, 69, 241,
-		1, 0, 0, 0, 71, 243, 1, 0, 0, 0, 73, 245, 1, 0, 0, 0, 75, 247, 1, 0, 0,
-		0, 77, 249, 1, 0, 0, 0, 79, 251, 1, 0, 0, 0, 81, 253, 1, 0, 0, 0, 83, 255,
-		1, 0, 0, 0, 85, 257, 1, 0, 0, 0, 87, 262, 1, 0, 0, 0, 89, 267, 1, 0, 0,
-		0, 91, 272, 1, 0, 0, 0, 93, 275, 1, 0, 0, 0, 95, 278, 1, 0, 0, 0, 97, 283,
-		1, 0, 0, 0, 99, 289, 1, 0, 0, 0, 101, 293, 1, 0, 0, 0, 103, 295, 1, 0,
-		0, 0, 105, 304, 1, 0, 0, 0, 107, 307, 1, 0, 0, 0, 109, 309, 1, 0, 0, 0,
-		111, 312, 1, 0, 0, 0, 113, 315, 1, 0, 0, 0, 115, 318, 1, 0, 0, 0, 117,
-		321, 1, 0, 0, 0, 119, 323, 1, 0, 0, 0, 121, 325, 1, 0, 0, 0, 123, 328,
-		1, 0, 0, 0, 125, 331, 1, 0, 0, 0, 127, 334, 1, 0, 0, 0, 129, 336, 1, 0,
-		0, 0, 131, 338, 1, 0, 0, 0, 133, 345, 1, 0, 0, 0, 135, 358, 1, 0, 0, 0,
-		137, 385, 1, 0, 0, 0, 139, 387, 1, 0, 0, 0, 141, 394, 1, 0, 0, 0, 143,
-		408, 1, 0, 0, 0, 145, 410, 1, 0, 0, 0, 147, 422, 1, 0, 0, 0, 149, 424,
-		1, 0, 0, 0, 151, 428, 1, 0, 0, 0, 153, 432, 1, 0, 0, 0, 155, 437, 1, 0,
-		0, 0, 157, 442, 1, 0, 0, 0, 159, 446, 1, 0, 0, 0, 161, 448, 1, 0, 0, 0,
-		163, 450, 1, 0, 0, 0, 165, 453, 1, 0, 0, 0, 167, 459, 1, 0, 0, 0, 169,
-		473, 1, 0, 0, 0, 171, 172, 5, 44, 0, 0, 172, 2, 1, 0, 0, 0, 173, 174, 7,
-		0, 0, 0, 174, 4, 1, 0, 0, 0, 175, 176, 7, 1, 0, 0, 176, 6, 1, 0, 0, 0,
-		177, 178, 7, 2, 0, 0, 178, 8, 1, 0, 0, 0, 179, 180, 7, 3, 0, 0, 180, 10,
-		1, 0, 0, 0, 181, 182, 7, 4, 0, 0, 182, 12, 1, 0, 0, 0, 183, 184, 7, 5,
-		0, 0, 184, 14, 1, 0, 0, 0, 185, 186, 7, 6, 0, 0, 186, 16, 1, 0, 0, 0, 187,
-		188, 7, 7, 0, 0, 188, 18, 1, 0, 0, 0, 189, 190, 7, 8, 0, 0, 190, 20, 1,
-		0, 0, 0, 191, 192, 7, 9, 0, 0, 192, 22, 1, 0, 0, 0, 193, 194, 7, 10, 0,
-		0, 194, 24, 1, 0, 0, 0, 195, 196, 7, 11, 0, 0, 196, 26, 1, 0, 0, 0, 197,
-		198, 7, 12, 0, 0, 198, 28, 1, 0, 0, 0, 199, 200, 7, 13, 0, 0, 200, 30,
-		1, 0, 0, 0, 201, 202, 7, 14, 0, 0, 202, 32, 1, 0, 0, 0, 203, 204, 7, 15,
-		0, 0, 204, 34, 1, 0, 0, 0, 205, 206, 7, 16, 0, 0, 206, 36, 1, 0, 0, 0,
-		207, 208, 7, 17, 0, 0, 208, 38, 1, 0, 0, 0, 209, 210, 7, 18, 0, 0, 210,
-		40, 1, 0, 0, 0, 211, 212, 7, 19, 0, 0, 212, 42, 1, 0, 0, 0, 213, 214, 7,
-		20, 0, 0, 214, 44, 1, 0, 0, 0, 215, 216, 7, 21, 0, 0, 216, 46, 1, 0, 0,
-		0, 217, 218, 7, 22, 0, 0, 218, 48, 1, 0, 0, 0, 219, 220, 7, 23, 0, 0, 220,
-		50, 1, 0, 0, 0, 221, 222, 7, 24, 0, 0, 222, 52, 1, 0, 0, 0, 223, 224, 7,
-		25, 0, 0, 224, 54, 1, 0, 0, 0, 225, 226, 7, 26, 0, 0, 226, 56, 1, 0, 0,
-		0, 227, 230, 3, 55, 27, 0, 228, 230, 7, 27, 0, 0, 229, 227, 1, 0, 0, 0,
-		229, 228, 1, 0, 0, 0, 230, 58, 1, 0, 0, 0, 231, 232, 5, 43, 0, 0, 232,
-		60, 1, 0, 0, 0, 233, 234, 5, 45, 0, 0, 234, 62, 1, 0, 0, 0, 235, 236, 5,
-		47, 0, 0, 236, 64, 1, 0, 0, 0, 237, 238, 5, 42, 0, 0, 238, 66, 1, 0, 0,
-		0, 239, 240, 5, 37, 0, 0, 240, 68, 1, 0, 0, 0, 241, 242, 5, 46, 0, 0, 242,
-		70, 1, 0, 0, 0, 243, 244, 5, 59, 0, 0, 244, 72, 1, 0, 0, 0, 245, 246, 5,
-		123, 0, 0, 246, 74, 1, 0, 0, 0, 247, 248, 5, 125, 0, 0, 248, 76, 1, 0,
-		0, 0, 249, 250, 5, 40, 0, 0, 250, 78, 1, 0, 0, 0, 251, 252, 5, 41, 0, 0,
-		252, 80, 1, 0, 0, 0, 253, 254, 5, 91, 0, 0, 254, 82, 1, 0, 0, 0, 255, 256,
-		5, 93, 0, 0, 256, 84, 1, 0, 0, 0, 257, 258, 3, 37, 18, 0, 258, 259, 3,
-		43, 21, 0, 259, 260, 3, 25, 12, 0, 260, 261, 3, 11, 5, 0, 261, 86, 1, 0,
-		0, 0, 262, 263, 3, 47, 23, 0, 263, 264, 3, 17, 8, 0, 264, 265, 3, 11, 5,
-		0, 265, 266, 3, 29, 14, 0, 266, 88, 1, 0, 0, 0, 267, 268, 3, 41, 20, 0,
-		268, 269, 3, 17, 8, 0, 269, 270, 3, 11, 5, 0, 270, 271, 3, 29, 14, 0, 271,
-		90, 1, 0, 0, 0, 272, 273, 5, 38, 0, 0, 273, 274, 5, 38, 0, 0, 274, 92,
-		1, 0, 0, 0, 275, 276, 5, 124, 0, 0, 276, 277, 5, 124, 0, 0, 277, 94, 1,
-		0, 0, 0, 278, 279, 3, 41, 20, 0, 279, 280, 3, 37, 18, 0, 280, 281, 3, 43,
-		21, 0, 281, 282, 3, 11, 5, 0, 282, 96, 1, 0, 0, 0, 283, 284, 3, 13, 6,
-		0, 284, 285, 3, 3, 1, 0, 285, 286, 3, 25, 12, 0, 286, 287, 3, 39, 19, 0,
-		287, 288, 3, 11, 5, 0, 288, 98, 1, 0, 0, 0, 289, 290, 3, 29, 14, 0, 290,
-		291, 3, 19, 9, 0, 291, 292, 3, 25, 12, 0, 292, 100, 1, 0, 0, 0, 293, 294,
-		5, 33, 0, 0, 294, 102, 1, 0, 0, 0, 295, 296, 3, 39, 19, 0, 296, 297, 3,
-		3, 1, 0, 297, 298, 3, 25, 12, 0, 298, 299, 3, 19, 9, 0, 299, 300, 3, 11,
-		5, 0, 300, 301, 3, 29, 14, 0, 301, 302, 3, 7, 3, 0, 302, 303, 3, 11, 5,
-		0, 303, 104, 1, 0, 0, 0, 304, 305, 5, 61, 0, 0, 305, 306, 5, 61, 0, 0,
-		306, 106, 1, 0, 0, 0, 307, 308, 5, 61, 0, 0, 308, 108, 1, 0, 0, 0, 309,
-		310, 5, 43, 0, 0, 310, 311, 5, 61, 0, 0, 311, 110, 1, 0, 0, 0, 312, 313,
-		5, 45, 0, 0, 313, 314, 5, 61, 0, 0, 314, 112, 1, 0, 0, 0, 315, 316, 5,
-		47, 0, 0, 316, 317, 5, 61, 0, 0, 317, 114, 1, 0, 0, 0, 318, 319, 5, 42,
-		0, 0, 319, 320, 5, 61, 0, 0, 320, 116, 1, 0, 0, 0, 321, 322, 5, 62, 0,
-		0, 322, 118, 1, 0, 0, 0, 323, 324, 5, 60, 0, 0, 324, 120, 1, 0, 0, 0, 325,
-		326, 5, 62, 0, 0, 326, 327, 5, 61, 0, 0, 327, 122, 1, 0, 0, 0, 328, 329,
-		5, 60, 0, 0, 329, 330, 5, 61, 0, 0, 330, 124, 1, 0, 0, 0, 331, 332, 5,
-		33, 0, 0, 332, 333, 5, 61, 0, 0, 333, 126, 1, 0, 0, 0, 334, 335, 5, 38,
-		0, 0, 335, 128, 1, 0, 0, 0, 336, 337, 5, 124, 0, 0, 337, 130, 1, 0, 0,
-		0, 338, 342, 3, 55, 27, 0, 339, 341, 3, 57, 28, 0, 340, 339, 1, 0, 0, 0,
-		341, 344, 1, 0, 0, 0, 342, 340, 1, 0, 0, 0, 342, 343, 1, 0, 0, 0, 343,
-		132, 1, 0, 0, 0, 344, 342, 1, 0, 0, 0, 345, 353, 5, 34, 0, 0, 346, 347,
-		5, 92, 0, 0, 347, 352, 9, 0, 0, 0, 348, 349, 5, 34, 0, 0, 349, 352, 5,
-		34, 0, 0, 350, 352, 8, 28, 0, 0, 351, 346, 1, 0, 0, 0, 351, 348, 1, 0,
-		0, 0, 351, 350, 1, 0, 0, 0, 352, 355, 1, 0, 0, 0, 353, 351, 1, 0, 0, 0,
-		353, 354, 1, 0, 0, 0, 354, 356, 1, 0, 0, 0, 355, 353, 1, 0, 0, 0, 356,
-		357, 5, 34, 0, 0, 357, 134, 1, 0, 0, 0, 358, 366, 5, 39, 0, 0, 359, 360,
-		5, 92, 0, 0, 360, 365, 9, 0, 0, 0, 361, 362, 5, 39, 0, 0, 362, 365, 5,
-		39, 0, 0, 363, 365, 8, 29, 0, 0, 364, 359, 1, 0, 0, 0, 364, 361, 1, 0,
-		0, 0, 364, 363, 1, 0, 0, 0, 365, 368, 1, 0, 0, 0, 366, 364, 1, 0, 0, 0,
-		366, 367, 1, 0, 0, 0, 367, 369, 1, 0, 0, 0, 368, 366, 1, 0, 0, 0, 369,
-		370, 5, 39, 0, 0, 370, 136, 1, 0, 0, 0, 371, 372, 3, 147, 73, 0, 372, 373,
-		3, 69, 34, 0, 373, 375, 3, 155, 77, 0, 374, 376, 3, 139, 69, 0, 375, 374,
-		1, 0, 0, 0, 375, 376, 1, 0, 0, 0, 376, 386, 1, 0, 0, 0, 377, 378, 3, 147,
-		73, 0, 378, 379, 3, 139, 69, 0, 379, 386, 1, 0, 0, 0, 380, 381, 3, 69,
-		34, 0, 381, 383, 3, 155, 77, 0, 382, 384, 3, 139, 69, 0, 383, 382, 1, 0,
-		0, 0, 383, 384, 1, 0, 0, 0, 384, 386, 1, 0, 0, 0, 385, 371, 1, 0, 0, 0,
-		385, 377, 1, 0, 0, 0, 385, 380, 1, 0, 0, 0, 386, 138, 1, 0, 0, 0, 387,
-		390, 3, 11, 5, 0, 388, 391, 3, 59, 29, 0, 389, 391, 3, 61, 30, 0, 390,
-		388, 1, 0, 0, 0, 390, 389, 1, 0, 0, 0, 390, 391, 1, 0, 0, 0, 391, 392,
-		1, 0, 0, 0, 392, 393, 3, 155, 77, 0, 393, 140, 1, 0, 0, 0, 394, 395, 5,
-		48, 0, 0, 395, 396, 3, 49, 24, 0, 396, 397, 3, 143, 71, 0, 397, 398, 3,
-		145, 72, 0, 398, 142, 1, 0, 0, 0, 399, 400, 3, 153, 76, 0, 400, 402, 3,
-		69, 34, 0, 401, 403, 3, 153, 76, 0, 402, 401, 1, 0, 0, 0, 402, 403, 1,
-		0, 0, 0, 403, 409, 1, 0, 0, 0, 404, 409, 3, 153, 76, 0, 405, 406, 3, 69,
-		34, 0, 406, 407, 3, 153, 76, 0, 407, 409, 1, 0, 0, 0, 408, 399, 1, 0, 0,
-		0, 408, 404, 1, 0, 0, 0, 408, 405, 1, 0, 0, 0, 409, 144, 1, 0, 0, 0, 410,
-		413, 3, 33, 16, 0, 411, 414, 3, 59, 29, 0, 412, 414, 3, 61, 30, 0, 413,
-		411, 1, 0, 0, 0, 413, 412, 1, 0, 0, 0, 413, 414, 1, 0, 0, 0, 414, 415,
-		1, 0, 0, 0, 415, 416, 3, 155, 77, 0, 416, 146, 1, 0, 0, 0, 417, 423, 5,
-		48, 0, 0, 418, 420, 7, 30, 0, 0, 419, 421, 3, 155, 77, 0, 420, 419, 1,
-		0, 0, 0, 420, 421, 1, 0, 0, 0, 421, 423, 1, 0, 0, 0, 422, 417, 1, 0, 0,
-		0, 422, 418, 1, 0, 0, 0, 423, 148, 1, 0, 0, 0, 424, 425, 5, 48, 0, 0, 425,
-		426, 3, 49, 24, 0, 426, 427, 3, 153, 76, 0, 427, 150, 1, 0, 0, 0, 428,
-		429, 5, 48, 0, 0, 429, 430, 3, 157, 78, 0, 430, 152, 1, 0, 0, 0, 431, 433,
-		3, 163, 81, 0, 432, 431, 1, 0, 0, 0, 433, 434, 1, 0, 0, 0, 434, 432, 1,
-		0, 0, 0, 434, 435, 1, 0, 0, 0, 435, 154, 1, 0, 0, 0, 436, 438, 3, 159,
-		79, 0, 437, 436, 1, 0, 0, 0, 438, 439, 1, 0, 0, 0, 439, 437, 1, 0, 0, 0,
-		439, 440, 1, 0, 0, 0, 440, 156, 1, 0, 0, 0, 441, 443, 3, 161, 80, 0, 442,
-		441, 1, 0, 0, 0, 443, 444, 1, 0, 0, 0, 444, 442, 1, 0, 0, 0, 444, 445,
-		1, 0, 0, 0, 445, 158, 1, 0, 0, 0, 446, 447, 7, 31, 0, 0, 447, 160, 1, 0,
-		0, 0, 448, 449, 7, 32, 0, 0, 449, 162, 1, 0, 0, 0, 450, 451, 7, 33, 0,
-		0, 451, 164, 1, 0, 0, 0, 452, 454, 7, 34, 0, 0, 453, 452, 1, 0, 0, 0, 454,
-		455, 1, 0, 0, 0, 455, 453, 1, 0, 0, 0, 455, 456, 1, 0, 0, 0, 456, 457,
-		1, 0, 0, 0, 457, 458, 6, 82, 0, 0, 458, 166, 1, 0, 0, 0, 459, 460, 5, 47,
-		0, 0, 460, 461, 5, 42, 0, 0, 461, 465, 1, 0, 0, 0, 462, 464, 9, 0, 0, 0,
-		463, 462, 1, 0, 0, 0, 464, 467, 1, 0, 0, 0, 465, 466, 1, 0, 0, 0, 465,
-		463, 1, 0, 0, 0, 466, 468, 1, 0, 0, 0, 467, 465, 1, 0, 0, 0, 468, 469,
-		5, 42, 0, 0, 469, 470, 5, 47, 0, 0, 470, 471, 1, 0, 0, 0, 471, 472, 6,
-		83, 0, 0, 472, 168, 1, 0, 0, 0, 473, 474, 5, 47, 0, 0, 474, 475, 5, 47,
-		0, 0, 475, 479, 1, 0, 0, 0, 476, 478, 8, 35, 0, 0, 477, 476, 1, 0, 0, 0,
-		478, 481, 1, 0, 0, 0, 479, 477, 1, 0, 0, 0, 479, 480, 1, 0, 0, 0, 480,
-		482, 1, 0, 0, 0, 481, 479, 1, 0, 0, 0, 482, 483, 6, 84, 0, 0, 483, 170,
-		1, 0, 0, 0, 22, 0, 229, 342, 351, 353, 364, 366, 375, 383, 385, 390, 402,
-		408, 413, 420, 422, 434, 439, 444, 455, 465, 479, 1, 6, 0, 0,
+		83, 2, 84, 7, 84, 2, 85, 7, 85, 2, 86, 7, 86, 2, 87, 7, 87, 2, 88, 7, 88,
+		2, 89, 7, 89, 2, 90, 7, 90, 2, 91, 7, 91, 2, 92, 7, 92, 2, 93, 7, 93, 2,
+		94, 7, 94, 2, 95, 7, 95, 2, 96, 7, 96, 1, 0, 1, 0, 1, 1, 1, 1, 1, 2, 1,
+		2, 1, 3, 1, 3, 1, 4, 1, 4, 1, 5, 1, 5, 1, 6, 1, 6, 1, 7, 1, 7, 1, 8, 1,
+		8, 1, 9, 1, 9, 1, 10, 1, 10, 1, 11, 1, 11, 1, 12, 1, 12, 1, 13, 1, 13,
+		1, 14, 1, 14, 1, 15, 1, 15, 1, 16, 1, 16, 1, 17, 1, 17, 1, 18, 1, 18, 1,
+		19, 1, 19, 1, 20, 1, 20, 1, 21, 1, 21, 1, 22, 1, 22, 1, 23, 1, 23, 1, 24,
+		1, 24, 1, 25, 1, 25, 1, 26, 1, 26, 1, 27, 1, 27, 1, 28, 1, 28, 3, 28, 254,
+		8, 28, 1, 29, 1, 29, 1, 30, 1, 30, 1, 31, 1, 31, 1, 32, 1, 32, 1, 33, 1,
+		33, 1, 34, 1, 34, 1, 35, 1, 35, 1, 36, 1, 36, 1, 37, 1, 37, 1, 38, 1, 38,
+		1, 39, 1, 39, 1, 40, 1, 40, 1, 41, 1, 41, 1, 42, 1, 42, 1, 42, 1, 42, 1,
+		42, 1, 43, 1, 43, 1, 43, 1, 43, 1, 43, 1, 44, 1, 44, 1, 44, 1, 44, 1, 44,
+		1, 45, 1, 45, 1, 45, 1, 46, 1, 46, 1, 46, 1, 47, 1, 47, 1, 47, 1, 47, 1,
+		47, 1, 48, 1, 48, 1, 48, 1, 48, 1, 48, 1, 48, 1, 49, 1, 49, 1, 49, 1, 49,
+		1, 50, 1, 50, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1, 51, 1,
+		51, 1, 52, 1, 52, 1, 52, 1, 52, 4, 52, 333, 8, 52, 11, 52, 12, 52, 334,
+		1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1, 52, 1,
+		53, 1, 53, 1, 53, 1, 54, 1, 54, 1, 54, 1, 55, 1, 55, 1, 55, 1, 56, 1, 56,
+		1, 56, 1, 57, 1, 57, 1, 58, 1, 58, 1, 58, 1, 59, 1, 59, 1, 59, 1, 60, 1,
+		60, 1, 60, 1, 61, 1, 61, 1, 61, 1, 62, 1, 62, 1, 63, 1, 63, 1, 64, 1, 64,
+		1, 64, 1, 65, 1, 65, 1, 65, 1, 66, 1, 66, 1, 66, 1, 67, 1, 67, 1, 67, 1,
+		67, 1, 68, 1, 68, 1, 69, 1, 69, 1, 70, 1, 70, 1, 71, 1, 71, 1, 72, 1, 72,
+		1, 73, 1, 73, 5, 73, 402, 8, 73, 10, 73, 12, 73, 405, 9, 73, 1, 74, 1,
+		74, 1, 74, 1, 74, 1, 74, 1, 74, 5, 74, 413, 8, 74, 10, 74, 12, 74, 416,
+		9, 74, 1, 74, 1, 74, 1, 75, 1, 75, 1, 75, 1, 75, 1, 75, 1, 75, 5, 75, 426,
+		8, 75, 10, 75, 12, 75, 429, 9, 75, 1, 75, 1, 75, 1, 76, 1, 76, 1, 76, 1,
+		76, 1, 76, 5, 76, 438, 8, 76, 10, 76, 12, 76, 441, 9, 76, 1, 76, 1, 76,
+		1, 76, 1, 76, 1, 77, 1, 77, 1, 77, 3, 77, 450, 8, 77, 1, 77, 1, 77, 1,
+		77, 1, 77, 1, 77, 1, 77, 1, 77, 1, 77, 1, 77, 3, 77, 461, 8, 77, 4, 77,
+		463, 8, 77, 11, 77, 12, 77, 464, 1, 78, 1, 78, 1, 78, 1, 78, 3, 78, 471,
+		8, 78, 1, 78, 1, 78, 1, 78, 1, 78, 1, 78, 1, 78, 3, 78, 479, 8, 78, 3,
+		78, 481, 8, 78, 1, 79, 1, 79, 1, 79, 3, 79, 486, 8, 79, 1, 79, 1, 79, 1,
+		80, 1, 80, 1, 80, 1, 80, 1, 80, 1, 81, 1, 81, 1, 81, 3, 81, 498, 8, 81,
+		1, 81, 1, 81, 1, 81, 1, 81, 3, 81, 504, 8, 81, 1, 82, 1, 82, 1, 82, 3,
+		82, 509, 8, 82, 1, 82, 1, 82, 1, 83, 1, 83, 1, 83, 3, 83, 516, 8, 83, 3,
+		83, 518, 8, 83, 1, 84, 1, 84, 1, 84, 1, 84, 1, 85, 1, 85, 1, 85, 1, 86,
+		1, 86, 1, 86, 3, 86, 530, 8, 86, 1, 86, 1, 86, 5, 86, 534, 8, 86, 10, 86,
+		12, 86, 537, 9, 86, 1, 87, 1, 87, 1, 87, 3, 87, 542, 8, 87, 1, 87, 1, 87,
+		1, 87, 5, 87, 547, 8, 87, 10, 87, 12, 87, 550, 9, 87, 1, 87, 1, 87, 1,
+		87, 1, 87, 1, 87, 1, 87, 1, 87, 1, 87, 5, 87, 560, 8, 87, 10, 87, 12, 87,
+		563, 9, 87, 3, 87, 565, 8, 87, 1, 88, 4, 88, 568, 8, 88, 11, 88, 12, 88,
+		569, 1, 89, 4, 89, 573, 8, 89, 11, 89, 12, 89, 574, 1, 90, 4, 90, 578,
+		8, 90, 11, 90, 12, 90, 579, 1, 91, 1, 91, 1, 92, 1, 92, 1, 93, 1, 93, 1,
+		94, 4, 94, 589, 8, 94, 11, 94, 12, 94, 590, 1, 94, 1, 94, 1, 95, 1, 95,
+		1, 95, 1, 95, 5, 95, 599, 8, 95, 10, 95, 12, 95, 602, 9, 95, 1, 95, 1,
+		95, 1, 95, 1, 95, 1, 95, 1, 96, 1, 96, 1, 96, 1, 96, 5, 96, 613, 8, 96,
+		10, 96, 12, 96, 616, 9, 96, 1, 96, 1, 96, 2, 439, 600, 0, 97, 1, 1, 3,
+		0, 5, 0, 7, 0, 9, 0, 11, 0, 13, 0, 15, 0, 17, 0, 19, 0, 21, 0, 23, 0, 25,
+		0, 27, 0, 29, 0, 31, 0, 33, 0, 35, 0, 37, 0, 39, 0, 41, 0, 43, 0, 45, 0,
+		47, 0, 49, 0, 51, 0, 53, 0, 55, 0, 57, 0, 59, 2, 61, 3, 63, 4, 65, 5, 67,
+		6, 69, 7, 71, 8, 73, 9, 75, 10, 77, 11, 79, 12, 81, 13, 83, 14, 85, 15,
+		87, 16, 89, 17, 91, 18, 93, 19, 95, 20, 97, 21, 99, 22, 101, 23, 103, 24,
+		105, 25, 107, 26, 109, 27, 111, 28, 113, 29, 115, 30, 117, 31, 119, 32,
+		121, 33, 123, 34, 125, 35, 127, 36, 129, 37, 131, 38, 133, 39, 135, 40,
+		137, 41, 139, 42, 141, 43, 143, 44, 145, 45, 147, 46, 149, 47, 151, 48,
+		153, 49, 155, 50, 157, 51, 159, 52, 161, 53, 163, 0, 165, 54, 167, 55,
+		169, 56, 171, 57, 173, 58, 175, 59, 177, 0, 179, 0, 181, 0, 183, 0, 185,
+		0, 187, 0, 189, 60, 191, 61, 193, 62, 1, 0, 37, 2, 0, 65, 65, 97, 97, 2,
+		0, 66, 66, 98, 98, 2, 0, 67, 67, 99, 99, 2, 0, 68, 68, 100, 100, 2, 0,
+		69, 69, 101, 101, 2, 0, 70, 70, 102, 102, 2, 0, 71, 71, 103, 103, 2, 0,
+		72, 72, 104, 104, 2, 0, 73, 73, 105, 105, 2, 0, 74, 74, 106, 106, 2, 0,
+		75, 75, 107, 107, 2, 0, 76, 76, 108, 108, 2, 0, 77, 77, 109, 109, 2, 0,
+		78, 78, 110, 110, 2, 0, 79, 79, 111, 111, 2, 0, 80, 80, 112, 112, 2, 0,
+		81, 81, 113, 113, 2, 0, 82, 82, 114, 114, 2, 0, 83, 83, 115, 115, 2, 0,
+		84, 84, 116, 116, 2, 0, 85, 85, 117, 117, 2, 0, 86, 86, 118, 118, 2, 0,
+		87, 87, 119, 119, 2, 0, 88, 88, 120, 120, 2, 0, 89, 89, 121, 121, 2, 0,
+		90, 90, 122, 122, 13, 0, 65, 90, 97, 122, 192, 214, 216, 246, 248, 767,
+		880, 893, 895, 8191, 8204, 8205, 8304, 8591, 11264, 12271, 12289, 55295,
+		63744, 64975, 65008, 65533, 5, 0, 48, 57, 95, 95, 183, 183, 768, 879, 8255,
+		8256, 3, 0, 9, 10, 13, 13, 32, 32, 2, 0, 34, 34, 92, 92, 2, 0, 39, 39,
+		92, 92, 3, 0, 104, 104, 109, 109, 115, 115, 1, 0, 49, 57, 1, 0, 48, 57,
+		1, 0, 48, 55, 3, 0, 48, 57, 65, 70, 97, 102, 2, 0, 10, 10, 13, 13, 624,
+		0, 1, 1, 0, 0, 0, 0, 59, 1, 0, 0, 0, 0, 61, 1, 0, 0, 0, 0, 63, 1, 0, 0,
+		0, 0, 65, 1, 0, 0, 0, 0, 67, 1, 0, 0, 0, 0, 69, 1, 0, 0, 0, 0, 71, 1, 0,
+		0, 0, 0, 73, 1, 0, 0, 0, 0, 75, 1, 0, 0, 0, 0, 77, 1, 0, 0, 0, 0, 79, 1,
+		0, 0, 0, 0, 81, 1, 0, 0, 0, 0, 83, 1, 0, 0, 0, 0, 85, 1, 0, 0, 0, 0, 87,
+		1, 0, 0, 0, 0, 89, 1, 0, 0, 0, 0, 91, 1, 0, 0, 0, 0, 93, 1, 0, 0, 0, 0,
+		95, 1, 0, 0, 0, 0, 97, 1, 0, 0, 0, 0, 99, 1, 0, 0, 0, 0, 101, 1, 0, 0,
+		0, 0, 103, 1, 0, 0, 0, 0, 105, 1, 0, 0, 0, 0, 107, 1, 0, 0, 0, 0, 109,
+		1, 0, 0, 0, 0, 111, 1, 0, 0, 0, 0, 113, 1, 0, 0, 0, 0, 115, 1, 0, 0, 0,
+		0, 117, 1, 0, 0, 0, 0, 119, 1, 0, 0, 0, 0, 121, 1, 0, 0, 0, 0, 123, 1,
+		0, 0, 0, 0, 125, 1, 0, 0, 0, 0, 127, 1, 0, 0, 0, 0, 129, 1, 0, 0, 0, 0,
+		131, 1, 0, 0, 0, 0, 133, 1, 0, 0, 0, 0, 135, 1, 0, 0, 0, 0, 137, 1, 0,
+		0, 0, 0, 139, 1, 0, 0, 0, 0, 141, 1, 0, 0, 0, 0, 143, 1, 0, 0, 0, 0, 145,
+		1, 0, 0, 0, 0, 147, 1, 0, 0, 0, 0, 149, 1, 0, 0, 0, 0, 151, 1, 0, 0, 0,
+		0, 153, 1, 0, 0, 0, 0, 155, 1, 0, 0, 0, 0, 157, 1, 0, 0, 0, 0, 159, 1,
+		0, 0, 0, 0, 161, 1, 0, 0, 0, 0, 165, 1, 0, 0, 0, 0, 167, 1, 0, 0, 0, 0,
+		169, 1, 0, 0, 0, 0, 171, 1, 0, 0, 0, 0, 173, 1, 0, 0, 0, 0, 175, 1, 0,
+		0, 0, 0, 189, 1, 0, 0, 0, 0, 191, 1, 0, 0, 0, 0, 193, 1, 0, 0, 0, 1, 195,
+		1, 0, 0, 0, 3, 197, 1, 0, 0, 0, 5, 199, 1, 0, 0, 0, 7, 201, 1, 0, 0, 0,
+		9, 203, 1, 0, 0, 0, 11, 205, 1, 0, 0, 0, 13, 207, 1, 0, 0, 0, 15, 209,
+		1, 0, 0, 0, 17, 211, 1, 0, 0, 0, 19, 213, 1, 0, 0, 0, 21, 215, 1, 0, 0,
+		0, 23, 217, 1, 0, 0, 0, 25, 219, 1, 0, 0, 0, 27, 221, 1, 0, 0, 0, 29, 223,
+		1, 0, 0, 0, 31, 225, 1, 0, 0, 0, 33, 227, 1, 0, 0, 0, 35, 229, 1, 0, 0,
+		0, 37, 231, 1, 0, 0, 0, 39, 233, 1, 0, 0, 0, 41, 235, 1, 0, 0, 0, 43, 237,
+		1, 0, 0, 0, 45, 239, 1, 0, 0, 0, 47, 241, 1, 0, 0, 0, 49, 243, 1, 0, 0,
+		0, 51, 245, 1, 0, 0, 0, 53, 247, 1, 0, 0, 0, 55, 249, 1, 0, 0, 0, 57, 253,
+		1, 0, 0, 0, 59, 255, 1, 0, 0, 0, 61, 257, 1, 0, 0, 0, 63, 259, 1, 0, 0,
+		0, 65, 261, 1, 0, 0, 0, 67, 263, 1, 0, 0, 0, 69, 265, 1, 0, 0, 0, 71, 267,
+		1, 0, 0, 0, 73, 269, 1, 0, 0, 0, 75, 271, 1, 0, 0, 0, 77, 273, 1, 0, 0,
+		0, 79, 275, 1, 0, 0, 0, 81, 277, 1, 0, 0, 0, 83, 279, 1, 0, 0, 0, 85, 281,
+		1, 0, 0, 0, 87, 286, 1, 0, 0, 0, 89, 291, 1, 0, 0, 0, 91, 296, 1, 0, 0,
+		0, 93, 299, 1, 0, 0, 0, 95, 302, 1, 0, 0, 0, 97, 307, 1, 0, 0, 0, 99, 313,
+		1, 0, 0, 0, 101, 317, 1, 0, 0, 0, 103, 319, 1, 0, 0, 0, 105, 328, 1, 0,
+		0, 0, 107, 346, 1, 0, 0, 0, 109, 349, 1, 0, 0, 0, 111, 352, 1, 0, 0, 0,
+		113, 355, 1, 0, 0, 0, 115, 358, 1, 0, 0, 0, 117, 360, 1, 0, 0, 0, 119,
+		363, 1, 0, 0, 0, 121, 366, 1, 0, 0, 0, 123, 369, 1, 0, 0, 0, 125, 372,
+		1, 0, 0, 0, 127, 374, 1, 0, 0, 0, 129, 376, 1, 0, 0, 0, 131, 379, 1, 0,
+		0, 0, 133, 382, 1, 0, 0, 0, 135, 385, 1, 0, 0, 0, 137, 389, 1, 0, 0, 0,
+		139, 391, 1, 0, 0, 0, 141, 393, 1, 0, 0, 0, 143, 395, 1, 0, 0, 0, 145,
+		397, 1, 0, 0, 0, 147, 399, 1, 0, 0, 0, 149, 406, 1, 0, 0, 0, 151, 419,
+		1, 0, 0, 0, 153, 432, 1, 0, 0, 0, 155, 462, 1, 0, 0, 0, 157, 480, 1, 0,
+		0, 0, 159, 482, 1, 0, 0, 0, 161, 489, 1, 0, 0, 0, 163, 503, 1, 0, 0, 0,
+		165, 505, 1, 0, 0, 0, 167, 517, 1, 0, 0, 0, 169, 519, 1, 0, 0, 0, 171,
+		523, 1, 0, 0, 0, 173, 526, 1, 0, 0, 0, 175, 564, 1, 0, 0, 0, 177, 567,
+		1, 0, 0, 0, 179, 572, 1, 0, 0, 0, 181, 577, 1, 0, 0, 0, 183, 581, 1, 0,
+		0, 0, 185, 583, 1, 0, 0, 0, 187, 585, 1, 0, 0, 0, 189, 588, 1, 0, 0, 0,
+		191, 594, 1, 0, 0, 0, 193, 608, 1, 0, 0, 0, 195, 196, 5, 44, 0, 0, 196,
+		2, 1, 0, 0, 0, 197, 198, 7, 0, 0, 0, 198, 4, 1, 0, 0, 0, 199, 200, 7, 1,
+		0, 0, 200, 6, 1, 0, 0, 0, 201, 202, 7, 2, 0, 0, 202, 8, 1, 0, 0, 0, 203,
+		204, 7, 3, 0, 0, 204, 10, 1, 0, 0, 0, 205, 206, 7, 4, 0, 0, 206, 12, 1,
+		0, 0, 0, 207, 208, 7, 5, 0, 0, 208, 14, 1, 0, 0, 0, 209, 210, 7, 6, 0,
+		0, 210, 16, 1, 0, 0, 0, 211, 212, 7, 7, 0, 0, 212, 18, 1, 0, 0, 0, 213,
+		214, 7, 8, 0, 0, 214, 20, 1, 0, 0, 0, 215, 216, 7, 9, 0, 0, 216, 22, 1,
+		0, 0, 0, 217, 218, 7, 10, 0, 0, 218, 24, 1, 0, 0, 0, 219, 220, 7, 11, 0,
+		0, 220, 26, 1, 0, 0, 0, 221, 222, 7, 12, 0, 0, 222, 28, 1, 0, 0, 0, 223,
+		224, 7, 13, 0, 0, 224, 30, 1, 0, 0, 0, 225, 226, 7, 14, 0, 0, 226, 32,
+		1, 0, 0, 0, 227, 228, 7, 15, 0, 0, 228, 34, 1, 0, 0, 0, 229, 230, 7, 16,
+		0, 0, 230, 36, 1, 0, 0, 0, 231, 232, 7, 17, 0, 0, 232, 38, 1, 0, 0, 0,
+		233, 234, 7, 18, 0, 0, 234, 40, 1, 0, 0, 0, 235, 236, 7, 19, 0, 0, 236,
+		42, 1, 0, 0, 0, 237, 238, 7, 20, 0, 0, 238, 44, 1, 0, 0, 0, 239, 240, 7,
+		21, 0, 0, 240, 46, 1, 0, 0, 0, 241, 242, 7, 22, 0, 0, 242, 48, 1, 0, 0,
+		0, 243, 244, 7, 23, 0, 0, 244, 50, 1, 0, 0, 0, 245, 246, 7, 24, 0, 0, 246,
+		52, 1, 0, 0, 0, 247, 248, 7, 25, 0, 0, 248, 54, 1, 0, 0, 0, 249, 250, 7,
+		26, 0, 0, 250, 56, 1, 0, 0, 0, 251, 254, 3, 55, 27, 0, 252, 254, 7, 27,
+		0, 0, 253, 251, 1, 0, 0, 0, 253, 252, 1, 0, 0, 0, 254, 58, 1, 0, 0, 0,
+		255, 256, 5, 43, 0, 0, 256, 60, 1, 0, 0, 0, 257, 258, 5, 45, 0, 0, 258,
+		62, 1, 0, 0, 0, 259, 260, 5, 47, 0, 0, 260, 64, 1, 0, 0, 0, 261, 262, 5,
+		42, 0, 0, 262, 66, 1, 0, 0, 0, 263, 264, 5, 37, 0, 0, 264, 68, 1, 0, 0,
+		0, 265, 266, 5, 46, 0, 0, 266, 70, 1, 0, 0, 0, 267, 268, 5, 59, 0, 0, 268,
+		72, 1, 0, 0, 0, 269, 270, 5, 123, 0, 0, 270, 74, 1, 0, 0, 0, 271, 272,
+		5, 125, 0, 0, 272, 76, 1, 0, 0, 0, 273, 274, 5, 40, 0, 0, 274, 78, 1, 0,
+		0, 0, 275, 276, 5, 41, 0, 0, 276, 80, 1, 0, 0, 0, 277, 278, 5, 91, 0, 0,
+		278, 82, 1, 0, 0, 0, 279, 280, 5, 93, 0, 0, 280, 84, 1, 0, 0, 0, 281, 282,
+		3, 37, 18, 0, 282, 283, 3, 43, 21, 0, 283, 284, 3, 25, 12, 0, 284, 285,
+		3, 11, 5, 0, 285, 86, 1, 0, 0, 0, 286, 287, 3, 47, 23, 0, 287, 288, 3,
+		17, 8, 0, 288, 289, 3, 11, 5, 0, 289, 290, 3, 29, 14, 0, 290, 88, 1, 0,
+		0, 0, 291, 292, 3, 41, 20, 0, 292, 293, 3, 17, 8, 0, 293, 294, 3, 11, 5,
+		0, 294, 295, 3, 29, 14, 0, 295, 90, 1, 0, 0, 0, 296, 297, 5, 38, 0, 0,
+		297, 298, 5, 38, 0, 0, 298, 92, 1, 0, 0, 0, 299, 300, 5, 124, 0, 0, 300,
+		301, 5, 124, 0, 0, 301, 94, 1, 0, 0, 0, 302, 303, 3, 41, 20, 0, 303, 304,
+		3, 37, 18, 0, 304, 305, 3, 43, 21, 0, 305, 306, 3, 11, 5, 0, 306, 96, 1,
+		0, 0, 0, 307, 308, 3, 13, 6, 0, 308, 309, 3, 3, 1, 0, 309, 310, 3, 25,
+		12, 0, 310, 311, 3, 39, 19, 0, 311, 312, 3, 11, 5, 0, 312, 98, 1, 0, 0,
+		0, 313, 314, 3, 29, 14, 0, 314, 315, 3, 19, 9, 0, 315, 316, 3, 25, 12,
+		0, 316, 100, 1, 0, 0, 0, 317, 318, 5, 33, 0, 0, 318, 102, 1, 0, 0, 0, 319,
+		320, 3, 39, 19, 0, 320, 321, 3, 3, 1, 0, 321, 322, 3, 25, 12, 0, 322, 323,
+		3, 19, 9, 0, 323, 324, 3, 11, 5, 0, 324, 325, 3, 29, 14, 0, 325, 326, 3,
+		7, 3, 0, 326, 327, 3, 11, 5, 0, 327, 104, 1, 0, 0, 0, 328, 329, 3, 33,
+		16, 0, 329, 330, 3, 11, 5, 0, 330, 332, 3, 37, 18, 0, 331, 333, 7, 28,
+		0, 0, 332, 331, 1, 0, 0, 0, 333, 334, 1, 0, 0, 0, 334, 332, 1, 0, 0, 0,
+		334, 335, 1, 0, 0, 0, 335, 336, 1, 0, 0, 0, 336, 337, 3, 11, 5, 0, 337,
+		338, 3, 49, 24, 0, 338, 339, 3, 11, 5, 0, 339, 340, 3, 7, 3, 0, 340, 341,
+		3, 43, 21, 0, 341, 342, 3, 41, 20, 0, 342, 343, 3, 19, 9, 0, 343, 344,
+		3, 31, 15, 0, 344, 345, 3, 29, 14, 0, 345, 106, 1, 0, 0, 0, 346, 347, 3,
+		19, 9, 0, 347, 348, 3, 29, 14, 0, 348, 108, 1, 0, 0, 0, 349, 350, 5, 61,
+		0, 0, 350, 351, 5, 61, 0, 0, 351, 110, 1, 0, 0, 0, 352, 353, 5, 61, 0,
+		0, 353, 354, 5, 62, 0, 0, 354, 112, 1, 0, 0, 0, 355, 356, 5, 45, 0, 0,
+		356, 357, 5, 62, 0, 0, 357, 114, 1, 0, 0, 0, 358, 359, 5, 61, 0, 0, 359,
+		116, 1, 0, 0, 0, 360, 361, 5, 43, 0, 0, 361, 362, 5, 61, 0, 0, 362, 118,
+		1, 0, 0, 0, 363, 364, 5, 45, 0, 0, 364, 365, 5, 61, 0, 0, 365, 120, 1,
+		0, 0, 0, 366, 367, 5, 47, 0, 0, 367, 368, 5, 61, 0, 0, 368, 122, 1, 0,
+		0, 0, 369, 370, 5, 42, 0, 0, 370, 371, 5, 61, 0, 0, 371, 124, 1, 0, 0,
+		0, 372, 373, 5, 62, 0, 0, 373, 126, 1, 0, 0, 0, 374, 375, 5, 60, 0, 0,
+		375, 128, 1, 0, 0, 0, 376, 377, 5, 62, 0, 0, 377, 378, 5, 61, 0, 0, 378,
+		130, 1, 0, 0, 0, 379, 380, 5, 60, 0, 0, 380, 381, 5, 61, 0, 0, 381, 132,
+		1, 0, 0, 0, 382, 383, 5, 33, 0, 0, 383, 384, 5, 61, 0, 0, 384, 134, 1,
+		0, 0, 0, 385, 386, 5, 126, 0, 0, 386, 387, 5, 61, 0, 0, 387, 388, 5, 61,
+		0, 0, 388, 136, 1, 0, 0, 0, 389, 390, 5, 38, 0, 0, 390, 138, 1, 0, 0, 0,
+		391, 392, 5, 124, 0, 0, 392, 140, 1, 0, 0, 0, 393, 394, 5, 95, 0, 0, 394,
+		142, 1, 0, 0, 0, 395, 396, 5, 64, 0, 0, 396, 144, 1, 0, 0, 0, 397, 398,
+		5, 58, 0, 0, 398, 146, 1, 0, 0, 0, 399, 403, 3, 55, 27, 0, 400, 402, 3,
+		57, 28, 0, 401, 400, 1, 0, 0, 0, 402, 405, 1, 0, 0, 0, 403, 401, 1, 0,
+		0, 0, 403, 404, 1, 0, 0, 0, 404, 148, 1, 0, 0, 0, 405, 403, 1, 0, 0, 0,
+		406, 414, 5, 34, 0, 0, 407, 408, 5, 92, 0, 0, 408, 413, 9, 0, 0, 0, 409,
+		410, 5, 34, 0, 0, 410, 413, 5, 34, 0, 0, 411, 413, 8, 29, 0, 0, 412, 407,
+		1, 0, 0, 0, 412, 409, 1, 0, 0, 0, 412, 411, 1, 0, 0, 0, 413, 416, 1, 0,
+		0, 0, 414, 412, 1, 0, 0, 0, 414, 415, 1, 0, 0, 0, 415, 417, 1, 0, 0, 0,
+		416, 414, 1, 0, 0, 0, 417, 418, 5, 34, 0, 0, 418, 150, 1, 0, 0, 0, 419,
+		427, 5, 39, 0, 0, 420, 421, 5, 92, 0, 0, 421, 426, 9, 0, 0, 0, 422, 423,
+		5, 39, 0, 0, 423, 426, 5, 39, 0, 0, 424, 426, 8, 30, 0, 0, 425, 420, 1,
+		0, 0, 0, 425, 422, 1, 0, 0, 0, 425, 424, 1, 0, 0, 0, 426, 429, 1, 0, 0,
+		0, 427, 425, 1, 0, 0, 0, 427, 428, 1, 0, 0, 0, 428, 430, 1, 0, 0, 0, 429,
+		427, 1, 0, 0, 0, 430, 431, 5, 39, 0, 0, 431, 152, 1, 0, 0, 0, 432, 433,
+		5, 96, 0, 0, 433, 434, 5, 96, 0, 0, 434, 435, 5, 96, 0, 0, 435, 439, 1,
+		0, 0, 0, 436, 438, 9, 0, 0, 0, 437, 436, 1, 0, 0, 0, 438, 441, 1, 0, 0,
+		0, 439, 440, 1, 0, 0, 0, 439, 437, 1, 0, 0, 0, 440, 442, 1, 0, 0, 0, 441,
+		439, 1, 0, 0, 0, 442, 443, 5, 96, 0, 0, 443, 444, 5, 96, 0, 0, 444, 445,
+		5, 96, 0, 0, 445, 154, 1, 0, 0, 0, 446, 449, 3, 179, 89, 0, 447, 448, 5,
+		46, 0, 0, 448, 450, 3, 179, 89, 0, 449, 447, 1, 0, 0, 0, 449, 450, 1, 0,
+		0, 0, 450, 460, 1, 0, 0, 0, 451, 452, 5, 110, 0, 0, 452, 461, 5, 115, 0,
+		0, 453, 454, 5, 117, 0, 0, 454, 461, 5, 115, 0, 0, 455, 456, 5, 181, 0,
+		0, 456, 461, 5, 115, 0, 0, 457, 458, 5, 109, 0, 0, 458, 461, 5, 115, 0,
+		0, 459, 461, 7, 31, 0, 0, 460, 451, 1, 0, 0, 0, 460, 453, 1, 0, 0, 0, 460,
+		455, 1, 0, 0, 0, 460, 457, 1, 0, 0, 0, 460, 459, 1, 0, 0, 0, 461, 463,
+		1, 0, 0, 0, 462, 446, 1, 0, 0, 0, 463, 464, 1, 0, 0, 0, 464, 462, 1, 0,
+		0, 0, 464, 465, 1, 0, 0, 0, 465, 156, 1, 0, 0, 0, 466, 467, 3, 167, 83,
+		0, 467, 468, 3, 69, 34, 0, 468, 470, 3, 179, 89, 0, 469, 471, 3, 159, 79,
+		0, 470, 469, 1, 0, 0, 0, 470, 471, 1, 0, 0, 0, 471, 481, 1, 0, 0, 0, 472,
+		473, 3, 167, 83, 0, 473, 474, 3, 159, 79, 0, 474, 481, 1, 0, 0, 0, 475,
+		476, 3, 69, 34, 0, 476, 478, 3, 179, 89, 0, 477, 479, 3, 159, 79, 0, 478,
+		477, 1, 0, 0, 0, 478, 479, 1, 0, 0, 0, 479, 481, 1, 0, 0, 0, 480, 466,
+		1, 0, 0, 0, 480, 472, 1, 0, 0, 0, 480, 475, 1, 0, 0, 0, 481, 158, 1, 0,
+		0, 0, 482, 485, 3, 11, 5, 0, 483, 486, 3, 59, 29, 0, 484, 486, 3, 61, 30,
+		0, 485, 483, 1, 0, 0, 0, 485, 484, 1, 0, 0, 0, 485, 486, 1, 0, 0, 0, 486,
+		487, 1, 0, 0, 0, 487, 488, 3, 179, 89, 0, 488, 160, 1, 0, 0, 0, 489, 490,
+		5, 48, 0, 0, 490, 491, 3, 49, 24, 0, 491, 492, 3, 163, 81, 0, 492, 493,
+		3, 165, 82, 0, 493, 162, 1, 0, 0, 0, 494, 495, 3, 177, 88, 0, 495, 497,
+		3, 69, 34, 0, 496, 498, 3, 177, 88, 0, 497, 496, 1, 0, 0, 0, 497, 498,
+		1, 0, 0, 0, 498, 504, 1, 0, 0, 0, 499, 504, 3, 177, 88, 0, 500, 501, 3,
+		69, 34, 0, 501, 502, 3, 177, 88, 0, 502, 504, 1, 0, 0, 0, 503, 494, 1,
+		0, 0, 0, 503, 499, 1, 0, 0, 0, 503, 500, 1, 0, 0, 0, 504, 164, 1, 0, 0,
+		0, 505, 508, 3, 33, 16, 0, 506, 509, 3, 59, 29, 0, 507, 509, 3, 61, 30,
+		0, 508, 506, 1, 0, 0, 0, 508, 507, 1, 0, 0, 0, 508, 509, 1, 0, 0, 0, 509,
+		510, 1, 0, 0, 0, 510, 511, 3, 179, 89, 0, 511, 166, 1, 0, 0, 0, 512, 518,
+		5, 48, 0, 0, 513, 515, 7, 32, 0, 0, 514, 516, 3, 179, 89, 0, 515, 514,
+		1, 0, 0, 0, 515, 516, 1, 0, 0, 0, 516, 518, 1, 0, 0, 0, 517, 512, 1, 0,
+		0, 0, 517, 513, 1, 0, 0, 0, 518, 168, 1, 0, 0, 0, 519, 520, 5, 48, 0, 0,
+		520, 521, 3, 49, 24, 0, 521, 522, 3, 177, 88, 0, 522, 170, 1, 0, 0, 0,
+		523, 524, 5, 48, 0, 0, 524, 525, 3, 181, 90, 0, 525, 172, 1, 0, 0, 0, 526,
+		529, 3, 179, 89, 0, 527, 528, 5, 46, 0, 0, 528, 530, 3, 179, 89, 0, 529,
+		527, 1, 0, 0, 0, 529, 530, 1, 0, 0, 0, 530, 531, 1, 0, 0, 0, 531, 535,
+		3, 55, 27, 0, 532, 534, 3, 57, 28, 0, 533, 532, 1, 0, 0, 0, 534, 537, 1,
+		0, 0, 0, 535, 533, 1, 0, 0, 0, 535, 536, 1, 0, 0, 0, 536, 174, 1, 0, 0,
+		0, 537, 535, 1, 0, 0, 0, 538, 541, 3, 179, 89, 0, 539, 540, 5, 46, 0, 0,
+		540, 542, 3, 179, 89, 0, 541, 539, 1, 0, 0, 0, 541, 542, 1, 0, 0, 0, 542,
+		543, 1, 0, 0, 0, 543, 544, 5, 95, 0, 0, 544, 548, 3, 55, 27, 0, 545, 547,
+		3, 57, 28, 0, 546, 545, 1, 0, 0, 0, 547, 550, 1, 0, 0, 0, 548, 546, 1,
+		0, 0, 0, 548, 549, 1, 0, 0, 0, 549, 565, 1, 0, 0, 0, 550, 548, 1, 0, 0,
+		0, 551, 552, 3, 179, 89, 0, 552, 553, 5, 45, 0, 0, 553, 554, 3, 179, 89,
+		0, 554, 555, 5, 45, 0, 0, 555, 556, 3, 179, 89, 0, 556, 557, 5, 95, 0,
+		0, 557, 561, 3, 55, 27, 0, 558, 560, 3, 57, 28, 0, 559, 558, 1, 0, 0, 0,
+		560, 563, 1, 0, 0, 0, 561, 559, 1, 0, 0, 0, 561, 562, 1, 0, 0, 0, 562,
+		565, 1, 0, 0, 0, 563, 561, 1, 0, 0, 0, 564, 538, 1, 0, 0, 0, 564, 551,
+		1, 0, 0, 0, 565, 176, 1, 0, 0, 0, 566, 568, 3, 187, 93, 0, 567, 566, 1,
+		0, 0, 0, 568, 569, 1, 0, 0, 0, 569, 567, 1, 0, 0, 0, 569, 570, 1, 0, 0,
+		0, 570, 178, 1, 0, 0, 0, 571, 573, 3, 183, 91, 0, 572, 571, 1, 0, 0, 0,
+		573, 574, 1, 0, 0, 0, 574, 572, 1, 0, 0, 0, 574, 575, 1, 0, 0, 0, 575,
+		180, 1, 0, 0, 0, 576, 578, 3, 185, 92, 0, 577, 576, 1, 0, 0, 0, 578, 579,
+		1, 0, 0, 0, 579, 577, 1, 0, 0, 0, 579, 580, 1, 0, 0, 0, 580, 182, 1, 0,
+		0, 0, 581, 582, 7, 33, 0, 0, 582, 184, 1, 0, 0, 0, 583, 584, 7, 34, 0,
+		0, 584, 186, 1, 0, 0, 0, 585, 586, 7, 35, 0, 0, 586, 188, 1, 0, 0, 0, 587,
+		589, 7, 28, 0, 0, 588, 587, 1, 0, 0, 0, 589, 590, 1, 0, 0, 0, 590, 588,
+		1, 0, 0, 0, 590, 591, 1, 0, 0, 0, 591, 592, 1, 0, 0, 0, 592, 593, 6, 94,
+		0, 0, 593, 190, 1, 0, 0, 0, 594, 595, 5, 47, 0, 0, 595, 596, 5, 42, 0,
+		0, 596, 600, 1, 0, 0, 0, 597, 599, 9, 0, 0, 0, 598, 597, 1, 0, 0, 0, 599,
+		602, 1, 0, 0, 0, 600, 601, 1, 0, 0, 0, 600, 598, 1, 0, 0, 0, 601, 603,
+		1, 0, 0, 0, 602, 600, 1, 0, 0, 0, 603, 604, 5, 42, 0, 0, 604, 605, 5, 47,
+		0, 0, 605, 606, 1, 0, 0, 0, 606, 607, 6, 95, 0, 0, 607, 192, 1, 0, 0, 0,
+		608, 609, 5, 47, 0, 0, 609, 610, 5, 47, 0, 0, 610, 614, 1, 0, 0, 0, 611,
+		613, 8, 36, 0, 0, 612, 611, 1, 0, 0, 0, 613, 616, 1, 0, 0, 0, 614, 612,
+		1, 0, 0, 0, 614, 615, 1, 0, 0, 0, 615, 617, 1, 0, 0, 0, 616, 614, 1, 0,
+		0, 0, 617, 618, 6, 96, 0, 0, 618, 194, 1, 0, 0, 0, 33, 0, 253, 334, 403,
+		412, 414, 425, 427, 439, 449, 460, 464, 470, 478, 480, 485, 497, 503, 508,
+		515, 517, 529, 535, 541, 548, 561, 564, 569, 574, 579, 590, 600, 614, 1,
+		6, 0, 0,
 	}
 	deserializer := antlr.NewATNDeserializer(nil)
 	staticData.atn = deserializer.Deserialize(staticData.serializedATN)
@@ -355,30 +425,42 @@ const (
 	grulev3LexerNIL_LITERAL       = 22
 	grulev3LexerNEGATION          = 23
 	grulev3LexerSALIENCE          = 24
-	grulev3LexerEQUALS            = 25
-	grulev3LexerASSIGN            = 26
-	grulev3LexerPLUS_ASIGN        = 27
-	grulev3LexerMINUS_ASIGN       = 28
-	grulev3LexerDIV_ASIGN         = 29
-	grulev3LexerMUL_ASIGN         = 30
-	grulev3LexerGT                = 31
-	grulev3LexerLT                = 32
-	grulev3LexerGTE               = 33
-	grulev3LexerLTE               = 34
-	grulev3LexerNOTEQUALS         = 35
-	grulev3LexerBITAND            = 36
-	grulev3LexerBITOR             = 37
-	grulev3LexerSIMPLENAME        = 38
-	grulev3LexerDQUOTA_STRING     = 39
-	grulev3LexerSQUOTA_STRING     = 40
-	grulev3LexerDECIMAL_FLOAT_LIT = 41
-	grulev3LexerDECIMAL_EXPONENT  = 42
-	grulev3LexerHEX_FLOAT_LIT     = 43
-	grulev3LexerHEX_EXPONENT      = 44
-	grulev3LexerDEC_LIT           = 45
-	grulev3LexerHEX_LIT           = 46
-	grulev3LexerOCT_LIT           = 47
-	grulev3LexerSPACE             = 48
-	grulev3LexerCOMMENT           = 49
-	grulev3LexerLINE_COMMENT      = 50
+	grulev3LexerPER_EXECUTION     = 25
+	grulev3LexerIN                = 26
+	grulev3LexerEQUALS            = 27
+	grulev3LexerARROW             = 28
+	grulev3LexerLAMBDA            = 29
+	grulev3LexerASSIGN            = 30
+	grulev3LexerPLUS_ASIGN        = 31
+	grulev3LexerMINUS_ASIGN       = 32
+	grulev3LexerDIV_ASIGN         = 33
+	grulev3LexerMUL_ASIGN         = 34
+	grulev3LexerGT                = 35
+	grulev3LexerLT                = 36
+	grulev3LexerGTE               = 37
+	grulev3LexerLTE               = 38
+	grulev3LexerNOTEQUALS         = 39
+	grulev3LexerAPPROX_EQUALS     = 40
+	grulev3LexerBITAND            = 41
+	grulev3LexerBITOR             = 42
+	grulev3LexerUNDERSCORE        = 43
+	grulev3LexerAT                = 44
+	grulev3LexerCOLON             = 45
+	grulev3LexerSIMPLENAME        = 46
+	grulev3LexerDQUOTA_STRING     = 47
+	grulev3LexerSQUOTA_STRING     = 48
+	grulev3LexerSCRIPT_LIT        = 49
+	grulev3LexerDURATION_LIT      = 50
+	grulev3LexerDECIMAL_FLOAT_LIT = 51
+	grulev3LexerDECIMAL_EXPONENT  = 52
+	grulev3LexerHEX_FLOAT_LIT     = 53
+	grulev3LexerHEX_EXPONENT      = 54
+	grulev3LexerDEC_LIT           = 55
+	grulev3LexerHEX_LIT           = 56
+	grulev3LexerOCT_LIT           = 57
+	grulev3LexerQUANTITY_LIT      = 58
+	grulev3LexerSUFFIX_LIT        = 59
+	grulev3LexerSPACE             = 60
+	grulev3LexerCOMMENT           = 61
+	grulev3LexerLINE_COMMENT      = 62
 )
@@ -13,30 +13,78 @@ type grulev3Listener interface {
 	// EnterRuleEntry is called when entering the ruleEntry production.
 	EnterRuleEntry(c *RuleEntryContext)
 
+	// EnterRuleAnnotation is called when entering the ruleAnnotation production.
+	EnterRuleAnnotation(c *RuleAnnotationContext)
+
+	// EnterTestEntry is called when entering the testEntry production.
+	EnterTestEntry(c *TestEntryContext)
+
+	// EnterHaltEntry is called when entering the haltEntry production.
+	EnterHaltEntry(c *HaltEntryContext)
+
+	// EnterGivenScope is called when entering the givenScope production.
+	EnterGivenScope(c *GivenScopeContext)
+
+	// EnterExpectScope is called when entering the expectScope production.
+	EnterExpectScope(c *ExpectScopeContext)
+
 	// EnterSalience is called when entering the salience production.
 	EnterSalience(c *SalienceContext)
 
+	// EnterMaxFires is called when entering the maxFires production.
+	EnterMaxFires(c *MaxFiresContext)
+
+	// EnterCooldown is called when entering the cooldown production.
+	EnterCooldown(c *CooldownContext)
+
+	// EnterCriticality is called when entering the criticality production.
+	EnterCriticality(c *CriticalityContext)
+
 	// EnterRuleName is called when entering the ruleName production.
 	EnterRuleName(c *RuleNameContext)
 
 	// EnterRuleDescription is called when entering the ruleDescription production.
 	EnterRuleDescription(c *RuleDescriptionContext)
 
+	// EnterRuleId is called when entering the ruleId production.
+	EnterRuleId(c *RuleIdContext)
+
 	// EnterWhenScope is called when entering the whenScope production.
 	EnterWhenScope(c *WhenScopeContext)
 
 	// EnterThenScope is called when entering the thenScope production.
 	EnterThenScope(c *ThenScopeContext)
 
+	// EnterElseScope is called when entering the elseScope production.
+	EnterElseScope(c *ElseScopeContext)
+
+	// EnterScriptBlock is called when entering the scriptBlock production.
+	EnterScriptBlock(c *ScriptBlockContext)
+
 	// EnterThenExpressionList is called when entering the thenExpressionList production.
 	EnterThenExpressionList(c *ThenExpressionListContext)
 
 	// EnterThenExpression is called when entering the thenExpression production.
 	EnterThenExpression(c *ThenExpressionContext)
 
+	// EnterCollectStatement is called when entering the collectStatement production.
+	EnterCollectStatement(c *CollectStatementContext)
+
+	// EnterSwitchStatement is called when entering the switchStatement production.
+	EnterSwitchStatement(c *SwitchStatementContext)
+
+	// EnterSwitchCase is called when entering the switchCase production.
+	EnterSwitchCase(c *SwitchCaseContext)
+
 	// EnterAssignment is called when entering the assignment production.
 	EnterAssignment(c *AssignmentContext)
 
+	// EnterMatchExpression is called when entering the matchExpression production.
+	EnterMatchExpression(c *MatchExpressionContext)
+
+	// EnterMatchArm is called when entering the matchArm production.
+	EnterMatchArm(c *MatchArmContext)
+
 	// EnterExpression is called when entering the expression production.
 	EnterExpression(c *ExpressionContext)
 
@@ -61,6 +109,12 @@ type grulev3Listener interface {
 	// EnterConstant is called when entering the constant production.
 	EnterConstant(c *ConstantContext)
 
+	// EnterCollectionLiteral is called when entering the collectionLiteral production.
+	EnterCollectionLiteral(c *CollectionLiteralContext)
+
+	// EnterCollectionElement is called when entering the collectionElement production.
+	EnterCollectionElement(c *CollectionElementContext)
+
 	// EnterVariable is called when entering the variable production.
 	EnterVariable(c *VariableContext)
 
@@ -70,6 +124,9 @@ type grulev3Listener interface {
 	// EnterMemberVariable is called when entering the memberVariable production.
 	EnterMemberVariable(c *MemberVariableContext)
 
+	// EnterQuantifier is called when entering the quantifier production.
+	EnterQuantifier(c *QuantifierContext)
+
 	// EnterFunctionCall is called when entering the functionCall production.
 	EnterFunctionCall(c *FunctionCallContext)
 
@@ -100,6 +157,12 @@ type grulev3Listener interface {
 	// EnterOctalLiteral is called when entering the octalLiteral production.
 	EnterOctalLiteral(c *OctalLiteralContext)
 
+	// EnterQuantityLiteral is called when entering the quantityLiteral production.
+	EnterQuantityLiteral(c *QuantityLiteralContext)
+
+	// EnterSuffixLiteral is called when entering the suffixLiteral production.
+	EnterSuffixLiteral(c *SuffixLiteralContext)
+
 	// EnterStringLiteral is called when entering the stringLiteral production.
 	EnterStringLiteral(c *StringLiteralContext)
 
@@ -112,30 +175,78 @@ type grulev3Listener interface {
 	// ExitRuleEntry is called when exiting the ruleEntry production.
 	ExitRuleEntry(c *RuleEntryContext)
 
+	// ExitRuleAnnotation is called when exiting the ruleAnnotation production.
+	ExitRuleAnnotation(c *RuleAnnotationContext)
+
+	// ExitTestEntry is called when exiting the testEntry production.
+	ExitTestEntry(c *TestEntryContext)
+
+	// ExitHaltEntry is called when exiting the haltEntry production.
+	ExitHaltEntry(c *HaltEntryContext)
+
+	// ExitGivenScope is called when exiting the givenScope production.
+	ExitGivenScope(c *GivenScopeContext)
+
+	// ExitExpectScope is called when exiting the expectScope production.
+	ExitExpectScope(c *ExpectScopeContext)
+
 	// ExitSalience is called when exiting the salience production.
 	ExitSalience(c *SalienceContext)
 
+	// ExitMaxFires is called when exiting the maxFires production.
+	ExitMaxFires(c *MaxFiresContext)
+
+	// ExitCooldown is called when exiting the cooldown production.
+	ExitCooldown(c *CooldownContext)
+
+	// ExitCriticality is called when exiting the criticality production.
+	ExitCriticality(c *CriticalityContext)
+
 	// ExitRuleName is called when exiting the ruleName production.
 	ExitRuleName(c *RuleNameContext)
 
 	// ExitRuleDescription is called when exiting the ruleDescription production.
 	ExitRuleDescription(c *RuleDescriptionContext)
 
+	// ExitRuleId is called when exiting the ruleId production.
+	ExitRuleId(c *RuleIdContext)
+
 	// ExitWhenScope is called when exiting the whenScope production.
 	ExitWhenScope(c *WhenScopeContext)
 
 	// ExitThenScope is called when exiting the thenScope production.
 	ExitThenScope(c *ThenScopeContext)
 
+	// ExitElseScope is called when exiting the elseScope production.
+	ExitElseScope(c *ElseScopeContext)
+
+	// ExitScriptBlock is called when exiting the scriptBlock production.
+	ExitScriptBlock(c *ScriptBlockContext)
+
 	// ExitThenExpressionList is called when exiting the thenExpressionList production.
 	ExitThenExpressionList(c *ThenExpressionListContext)
 
 	// ExitThenExpression is called when exiting the thenExpression production.
 	ExitThenExpression(c *ThenExpressionContext)
 
+	// ExitCollectStatement is called when exiting the collectStatement production.
+	ExitCollectStatement(c *CollectStatementContext)
+
+	// ExitSwitchStatement is called when exiting the switchStatement production.
+	ExitSwitchStatement(c *SwitchStatementContext)
+
+	// ExitSwitchCase is called when exiting the switchCase production.
+	ExitSwitchCase(c *SwitchCaseContext)
+
 	// ExitAssignment is called when exiting the assignment production.
 	ExitAssignment(c *AssignmentContext)
 
+	// ExitMatchExpression is called when exiting the matchExpression production.
+	ExitMatchExpression(c *MatchExpressionContext)
+
+	// ExitMatchArm is called when exiting the matchArm production.
+	ExitMatchArm(c *MatchArmContext)
+
 	// ExitExpression is called when exiting the expression production.
 	ExitExpression(c *ExpressionContext)
 
@@ -160,6 +271,12 @@ type grulev3Listener interface {
 	// ExitConstant is called when exiting the constant production.
 	ExitConstant(c *ConstantContext)
 
+	// ExitCollectionLiteral is called when exiting the collectionLiteral production.
+	ExitCollectionLiteral(c *CollectionLiteralContext)
+
+	// ExitCollectionElement is called when exiting the collectionElement production.
+	ExitCollectionElement(c *CollectionElementContext)
+
 	// ExitVariable is called when exiting the variable production.
 	ExitVariable(c *VariableContext)
 
@@ -169,6 +286,9 @@ type grulev3Listener interface {
 	// ExitMemberVariable is called when exiting the memberVariable production.
 	ExitMemberVariable(c *MemberVariableContext)
 
+	// ExitQuantifier is called when exiting the quantifier production.
+	ExitQuantifier(c *QuantifierContext)
+
 	// ExitFunctionCall is called when exiting the functionCall production.
 	ExitFunctionCall(c *FunctionCallContext)
 
@@ -199,6 +319,12 @@ type grulev3Listener interface {
 	// ExitOctalLiteral is called when exiting the octalLiteral production.
 	ExitOctalLiteral(c *OctalLiteralContext)
 
+	// ExitQuantityLiteral is called when exiting the quantityLiteral production.
+	ExitQuantityLiteral(c *QuantityLiteralContext)
+
+	// ExitSuffixLiteral is called when exiting the suffixLiteral production.
+	ExitSuffixLiteral(c *SuffixLiteralContext)
+
 	// ExitStringLiteral is called when exiting the stringLiteral production.
 	ExitStringLiteral(c *StringLiteralContext)
 
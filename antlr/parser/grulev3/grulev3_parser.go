@@ -34,143 +34,274 @@ func grulev3ParserInit() {
 	staticData.LiteralNames = []string{
 		"", "','", "'+'", "'-'", "'/'", "'*'", "'%'", "'.'", "';'", "'{'", "'}'",
 		"'('", "')'", "'['", "']'", "", "", "", "'&&'", "'||'", "", "", "",
-		"'!'", "", "'=='", "'='", "'+='", "'-='", "'/='", "'*='", "'>'", "'<'",
-		"'>='", "'<='", "'!='", "'&'", "'|'",
+		"'!'", "", "", "", "'=='", "'=>'", "'->'", "'='", "'+='", "'-='", "'/='",
+		"'*='", "'>'", "'<'", "'>='", "'<='", "'!='", "'~=='", "'&'", "'|'",
+		"'_'", "'@'", "':'",
 	}
 	staticData.SymbolicNames = []string{
 		"", "", "PLUS", "MINUS", "DIV", "MUL", "MOD", "DOT", "SEMICOLON", "LR_BRACE",
 		"RR_BRACE", "LR_BRACKET", "RR_BRACKET", "LS_BRACKET", "RS_BRACKET",
 		"RULE", "WHEN", "THEN", "AND", "OR", "TRUE", "FALSE", "NIL_LITERAL",
-		"NEGATION", "SALIENCE", "EQUALS", "ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN",
-		"DIV_ASIGN", "MUL_ASIGN", "GT", "LT", "GTE", "LTE", "NOTEQUALS", "BITAND",
-		"BITOR", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING", "DECIMAL_FLOAT_LIT",
-		"DECIMAL_EXPONENT", "HEX_FLOAT_LIT", "HEX_EXPONENT", "DEC_LIT", "HEX_LIT",
-		"OCT_LIT", "SPACE", "COMMENT", "LINE_COMMENT",
+		"NEGATION", "SALIENCE", "PER_EXECUTION", "IN", "EQUALS", "ARROW", "LAMBDA",
+		"ASSIGN", "PLUS_ASIGN", "MINUS_ASIGN", "DIV_ASIGN", "MUL_ASIGN", "GT",
+		"LT", "GTE", "LTE", "NOTEQUALS", "APPROX_EQUALS", "BITAND", "BITOR",
+		"UNDERSCORE", "AT", "COLON", "SIMPLENAME", "DQUOTA_STRING", "SQUOTA_STRING",
+		"SCRIPT_LIT", "DURATION_LIT", "DECIMAL_FLOAT_LIT", "DECIMAL_EXPONENT",
+		"HEX_FLOAT_LIT", "HEX_EXPONENT", "DEC_LIT", "HEX_LIT", "OCT_LIT", "QUANTITY_LIT",
+		"SUFFIX_LIT", "SPACE", "COMMENT", "LINE_COMMENT",
 	}
 	staticData.RuleNames = []string{
-		"grl", "ruleEntry", "salience", "ruleName", "ruleDescription", "whenScope",
-		"thenScope", "thenExpressionList", "thenExpression", "assignment", "expression",
-		"mulDivOperators", "addMinusOperators", "comparisonOperator", "andLogicOperator",
-		"orLogicOperator", "expressionAtom", "constant", "variable", "arrayMapSelector",
-		"memberVariable", "functionCall", "methodCall", "argumentList", "floatLiteral",
-		"decimalFloatLiteral", "hexadecimalFloatLiteral", "integerLiteral",
-		"decimalLiteral", "hexadecimalLiteral", "octalLiteral", "stringLiteral",
-		"booleanLiteral",
+		"grl", "ruleEntry", "ruleAnnotation", "testEntry", "haltEntry", "givenScope",
+		"expectScope", "salience", "maxFires", "cooldown", "criticality", "ruleName",
+		"ruleDescription", "ruleId", "whenScope", "thenScope", "elseScope",
+		"scriptBlock", "thenExpressionList", "thenExpression", "collectStatement",
+		"switchStatement", "switchCase", "assignment", "matchExpression", "matchArm",
+		"expression", "mulDivOperators", "addMinusOperators", "comparisonOperator",
+		"andLogicOperator", "orLogicOperator", "expressionAtom", "constant",
+		"collectionLiteral", "collectionElement", "variable", "arrayMapSelector",
+		"memberVariable", "quantifier", "functionCall", "methodCall", "argumentList",
+		"floatLiteral", "decimalFloatLiteral", "hexadecimalFloatLiteral", "integerLiteral",
+		"decimalLiteral", "hexadecimalLiteral", "octalLiteral", "quantityLiteral",
+		"suffixLiteral", "stringLiteral", "booleanLiteral",
 	}
 	staticData.PredictionContextCache = antlr.NewPredictionContextCache()
 	staticData.serializedATN = []int32{
-		4, 1, 50, 263, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
+		4, 1, 62, 533, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
 		4, 2, 5, 7, 5, 2, 6, 7, 6, 2, 7, 7, 7, 2, 8, 7, 8, 2, 9, 7, 9, 2, 10, 7,
 		10, 2, 11, 7, 11, 2, 12, 7, 12, 2, 13, 7, 13, 2, 14, 7, 14, 2, 15, 7, 15,
 		2, 16, 7, 16, 2, 17, 7, 17, 2, 18, 7, 18, 2, 19, 7, 19, 2, 20, 7, 20, 2,
 		21, 7, 21, 2, 22, 7, 22, 2, 23, 7, 23, 2, 24, 7, 24, 2, 25, 7, 25, 2, 26,
 		7, 26, 2, 27, 7, 27, 2, 28, 7, 28, 2, 29, 7, 29, 2, 30, 7, 30, 2, 31, 7,
-		31, 2, 32, 7, 32, 1, 0, 5, 0, 68, 8, 0, 10, 0, 12, 0, 71, 9, 0, 1, 0, 1,
-		0, 1, 1, 1, 1, 1, 1, 3, 1, 78, 8, 1, 1, 1, 3, 1, 81, 8, 1, 1, 1, 1, 1,
-		1, 1, 1, 1, 1, 1, 1, 2, 1, 2, 1, 2, 1, 3, 1, 3, 1, 4, 1, 4, 1, 5, 1, 5,
-		1, 5, 1, 6, 1, 6, 1, 6, 1, 7, 1, 7, 1, 7, 4, 7, 104, 8, 7, 11, 7, 12, 7,
-		105, 1, 8, 1, 8, 3, 8, 110, 8, 8, 1, 9, 1, 9, 1, 9, 1, 9, 1, 10, 1, 10,
-		3, 10, 118, 8, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 3, 10, 125, 8, 10,
-		1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1,
-		10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 1, 10, 5, 10,
-		147, 8, 10, 10, 10, 12, 10, 150, 9, 10, 1, 11, 1, 11, 1, 12, 1, 12, 1,
-		13, 1, 13, 1, 14, 1, 14, 1, 15, 1, 15, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16,
-		1, 16, 3, 16, 168, 8, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 5,
-		16, 176, 8, 16, 10, 16, 12, 16, 179, 9, 16, 1, 17, 1, 17, 1, 17, 1, 17,
-		1, 17, 3, 17, 186, 8, 17, 1, 18, 1, 18, 1, 18, 1, 18, 1, 18, 1, 18, 1,
-		18, 5, 18, 195, 8, 18, 10, 18, 12, 18, 198, 9, 18, 1, 19, 1, 19, 1, 19,
-		1, 19, 1, 20, 1, 20, 1, 20, 1, 21, 1, 21, 1, 21, 3, 21, 210, 8, 21, 1,
-		21, 1, 21, 1, 22, 1, 22, 1, 22, 1, 23, 1, 23, 1, 23, 5, 23, 220, 8, 23,
-		10, 23, 12, 23, 223, 9, 23, 1, 24, 1, 24, 3, 24, 227, 8, 24, 1, 25, 3,
-		25, 230, 8, 25, 1, 25, 1, 25, 1, 26, 3, 26, 235, 8, 26, 1, 26, 1, 26, 1,
-		27, 1, 27, 1, 27, 3, 27, 242, 8, 27, 1, 28, 3, 28, 245, 8, 28, 1, 28, 1,
-		28, 1, 29, 3, 29, 250, 8, 29, 1, 29, 1, 29, 1, 30, 3, 30, 255, 8, 30, 1,
-		30, 1, 30, 1, 31, 1, 31, 1, 32, 1, 32, 1, 32, 0, 3, 20, 32, 36, 33, 0,
-		2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38,
-		40, 42, 44, 46, 48, 50, 52, 54, 56, 58, 60, 62, 64, 0, 6, 1, 0, 39, 40,
-		1, 0, 26, 30, 1, 0, 4, 6, 2, 0, 2, 3, 36, 37, 2, 0, 25, 25, 31, 35, 1,
-		0, 20, 21, 263, 0, 69, 1, 0, 0, 0, 2, 74, 1, 0, 0, 0, 4, 87, 1, 0, 0, 0,
-		6, 90, 1, 0, 0, 0, 8, 92, 1, 0, 0, 0, 10, 94, 1, 0, 0, 0, 12, 97, 1, 0,
-		0, 0, 14, 103, 1, 0, 0, 0, 16, 109, 1, 0, 0, 0, 18, 111, 1, 0, 0, 0, 20,
-		124, 1, 0, 0, 0, 22, 151, 1, 0, 0, 0, 24, 153, 1, 0, 0, 0, 26, 155, 1,
-		0, 0, 0, 28, 157, 1, 0, 0, 0, 30, 159, 1, 0, 0, 0, 32, 167, 1, 0, 0, 0,
-		34, 185, 1, 0, 0, 0, 36, 187, 1, 0, 0, 0, 38, 199, 1, 0, 0, 0, 40, 203,
-		1, 0, 0, 0, 42, 206, 1, 0, 0, 0, 44, 213, 1, 0, 0, 0, 46, 216, 1, 0, 0,
-		0, 48, 226, 1, 0, 0, 0, 50, 229, 1, 0, 0, 0, 52, 234, 1, 0, 0, 0, 54, 241,
-		1, 0, 0, 0, 56, 244, 1, 0, 0, 0, 58, 249, 1, 0, 0, 0, 60, 254, 1, 0, 0,
-		0, 62, 258, 1, 0, 0, 0, 64, 260, 1, 0, 0, 0, 66, 68, 3, 2, 1, 0, 67, 66,
-		1, 0, 0, 0, 68, 71, 1, 0, 0, 0, 69, 67, 1, 0, 0, 0, 69, 70, 1, 0, 0, 0,
-		70, 72, 1, 0, 0, 0, 71, 69, 1, 0, 0, 0, 72, 73, 5, 0, 0, 1, 73, 1, 1, 0,
-		0, 0, 74, 75, 5, 15, 0, 0, 75, 77, 3, 6, 3, 0, 76, 78, 3, 8, 4, 0, 77,
-		76, 1, 0, 0, 0, 77, 78, 1, 0, 0, 0, 78, 80, 1, 0, 0, 0, 79, 81, 3, 4, 2,
-		0, 80, 79, 1, 0, 0, 0, 80, 81, 1, 0, 0, 0, 81, 82, 1, 0, 0, 0, 82, 83,
-		5, 9, 0, 0, 83, 84, 3, 10, 5, 0, 84, 85, 3, 12, 6, 0, 85, 86, 5, 10, 0,
-		0, 86, 3, 1, 0, 0, 0, 87, 88, 5, 24, 0, 0, 88, 89, 3, 54, 27, 0, 89, 5,
-		1, 0, 0, 0, 90, 91, 5, 38, 0, 0, 91, 7, 1, 0, 0, 0, 92, 93, 7, 0, 0, 0,
-		93, 9, 1, 0, 0, 0, 94, 95, 5, 16, 0, 0, 95, 96, 3, 20, 10, 0, 96, 11, 1,
-		0, 0, 0, 97, 98, 5, 17, 0, 0, 98, 99, 3, 14, 7, 0, 99, 13, 1, 0, 0, 0,
-		100, 101, 3, 16, 8, 0, 101, 102, 5, 8, 0, 0, 102, 104, 1, 0, 0, 0, 103,
-		100, 1, 0, 0, 0, 104, 105, 1, 0, 0, 0, 105, 103, 1, 0, 0, 0, 105, 106,
-		1, 0, 0, 0, 106, 15, 1, 0, 0, 0, 107, 110, 3, 18, 9, 0, 108, 110, 3, 32,
-		16, 0, 109, 107, 1, 0, 0, 0, 109, 108, 1, 0, 0, 0, 110, 17, 1, 0, 0, 0,
-		111, 112, 3, 36, 18, 0, 112, 113, 7, 1, 0, 0, 113, 114, 3, 20, 10, 0, 114,
-		19, 1, 0, 0, 0, 115, 117, 6, 10, -1, 0, 116, 118, 5, 23, 0, 0, 117, 116,
-		1, 0, 0, 0, 117, 118, 1, 0, 0, 0, 118, 119, 1, 0, 0, 0, 119, 120, 5, 11,
-		0, 0, 120, 121, 3, 20, 10, 0, 121, 122, 5, 12, 0, 0, 122, 125, 1, 0, 0,
-		0, 123, 125, 3, 32, 16, 0, 124, 115, 1, 0, 0, 0, 124, 123, 1, 0, 0, 0,
-		125, 148, 1, 0, 0, 0, 126, 127, 10, 7, 0, 0, 127, 128, 3, 22, 11, 0, 128,
-		129, 3, 20, 10, 8, 129, 147, 1, 0, 0, 0, 130, 131, 10, 6, 0, 0, 131, 132,
-		3, 24, 12, 0, 132, 133, 3, 20, 10, 7, 133, 147, 1, 0, 0, 0, 134, 135, 10,
-		5, 0, 0, 135, 136, 3, 26, 13, 0, 136, 137, 3, 20, 10, 6, 137, 147, 1, 0,
-		0, 0, 138, 139, 10, 4, 0, 0, 139, 140, 3, 28, 14, 0, 140, 141, 3, 20, 10,
-		5, 141, 147, 1, 0, 0, 0, 142, 143, 10, 3, 0, 0, 143, 144, 3, 30, 15, 0,
-		144, 145, 3, 20, 10, 4, 145, 147, 1, 0, 0, 0, 146, 126, 1, 0, 0, 0, 146,
-		130, 1, 0, 0, 0, 146, 134, 1, 0, 0, 0, 146, 138, 1, 0, 0, 0, 146, 142,
-		1, 0, 0, 0, 147, 150, 1, 0, 0, 0, 148, 146, 1, 0, 0, 0, 148, 149, 1, 0,
-		0, 0, 149, 21, 1, 0, 0, 0, 150, 148, 1, 0, 0, 0, 151, 152, 7, 2, 0, 0,
-		152, 23, 1, 0, 0, 0, 153, 154, 7, 3, 0, 0, 154, 25, 1, 0, 0, 0, 155, 156,
-		7, 4, 0, 0, 156, 27, 1, 0, 0, 0, 157, 158, 5, 18, 0, 0, 158, 29, 1, 0,
-		0, 0, 159, 160, 5, 19, 0, 0, 160, 31, 1, 0, 0, 0, 161, 162, 6, 16, -1,
-		0, 162, 168, 3, 34, 17, 0, 163, 168, 3, 36, 18, 0, 164, 168, 3, 42, 21,
-		0, 165, 166, 5, 23, 0, 0, 166, 168, 3, 32, 16, 1, 167, 161, 1, 0, 0, 0,
-		167, 163, 1, 0, 0, 0, 167, 164, 1, 0, 0, 0, 167, 165, 1, 0, 0, 0, 168,
-		177, 1, 0, 0, 0, 169, 170, 10, 4, 0, 0, 170, 176, 3, 44, 22, 0, 171, 172,
-		10, 3, 0, 0, 172, 176, 3, 40, 20, 0, 173, 174, 10, 2, 0, 0, 174, 176, 3,
-		38, 19, 0, 175, 169, 1, 0, 0, 0, 175, 171, 1, 0, 0, 0, 175, 173, 1, 0,
-		0, 0, 176, 179, 1, 0, 0, 0, 177, 175, 1, 0, 0, 0, 177, 178, 1, 0, 0, 0,
-		178, 33, 1, 0, 0, 0, 179, 177, 1, 0, 0, 0, 180, 186, 3, 62, 31, 0, 181,
-		186, 3, 54, 27, 0, 182, 186, 3, 48, 24, 0, 183, 186, 3, 64, 32, 0, 184,
-		186, 5, 22, 0, 0, 185, 180, 1, 0, 0, 0, 185, 181, 1, 0, 0, 0, 185, 182,
-		1, 0, 0, 0, 185, 183, 1, 0, 0, 0, 185, 184, 1, 0, 0, 0, 186, 35, 1, 0,
-		0, 0, 187, 188, 6, 18, -1, 0, 188, 189, 5, 38, 0, 0, 189, 196, 1, 0, 0,
-		0, 190, 191, 10, 3, 0, 0, 191, 195, 3, 40, 20, 0, 192, 193, 10, 2, 0, 0,
-		193, 195, 3, 38, 19, 0, 194, 190, 1, 0, 0, 0, 194, 192, 1, 0, 0, 0, 195,
-		198, 1, 0, 0, 0, 196, 194, 1, 0, 0, 0, 196, 197, 1, 0, 0, 0, 197, 37, 1,
-		0, 0, 0, 198, 196, 1, 0, 0, 0, 199, 200, 5, 13, 0, 0, 200, 201, 3, 20,
-		10, 0, 201, 202, 5, 14, 0, 0, 202, 39, 1, 0, 0, 0, 203, 204, 5, 7, 0, 0,
-		204, 205, 5, 38, 0, 0, 205, 41, 1, 0, 0, 0, 206, 207, 5, 38, 0, 0, 207,
-		209, 5, 11, 0, 0, 208, 210, 3, 46, 23, 0, 209, 208, 1, 0, 0, 0, 209, 210,
-		1, 0, 0, 0, 210, 211, 1, 0, 0, 0, 211, 212, 5, 12, 0, 0, 212, 43, 1, 0,
-		0, 0, 213, 214, 5, 7, 0, 0, 214, 215, 3, 42, 21, 0, 215, 45, 1, 0, 0, 0,
-		216, 221, 3, 20, 10, 0, 217, 218, 5, 1, 0, 0, 218, 220, 3, 20, 10, 0, 219,
-		217, 1, 0, 0, 0, 220, 223, 1, 0, 0, 0, 221, 219, 1, 0, 0, 0, 221, 222,
-		1, 0, 0, 0, 222, 47, 1, 0, 0, 0, 223, 221, 1, 0, 0, 0, 224, 227, 3, 50,
-		25, 0, 225, 227, 3, 52, 26, 0, 226, 224, 1, 0, 0, 0, 226, 225, 1, 0, 0,
-		0, 227, 49, 1, 0, 0, 0, 228, 230, 5, 3, 0, 0, 229, 228, 1, 0, 0, 0, 229,
-		230, 1, 0, 0, 0, 230, 231, 1, 0, 0, 0, 231, 232, 5, 41, 0, 0, 232, 51,
-		1, 0, 0, 0, 233, 235, 5, 3, 0, 0, 234, 233, 1, 0, 0, 0, 234, 235, 1, 0,
-		0, 0, 235, 236, 1, 0, 0, 0, 236, 237, 5, 43, 0, 0, 237, 53, 1, 0, 0, 0,
-		238, 242, 3, 56, 28, 0, 239, 242, 3, 58, 29, 0, 240, 242, 3, 60, 30, 0,
-		241, 238, 1, 0, 0, 0, 241, 239, 1, 0, 0, 0, 241, 240, 1, 0, 0, 0, 242,
-		55, 1, 0, 0, 0, 243, 245, 5, 3, 0, 0, 244, 243, 1, 0, 0, 0, 244, 245, 1,
-		0, 0, 0, 245, 246, 1, 0, 0, 0, 246, 247, 5, 45, 0, 0, 247, 57, 1, 0, 0,
-		0, 248, 250, 5, 3, 0, 0, 249, 248, 1, 0, 0, 0, 249, 250, 1, 0, 0, 0, 250,
-		251, 1, 0, 0, 0, 251, 252, 5, 46, 0, 0, 252, 59, 1, 0, 0, 0, 253, 255,
-		5, 3, 0, 0, 254, 253, 1, 0, 0, 0, 254, 255, 1, 0, 0, 0, 255, 256, 1, 0,
-		0, 0, 256, 257, 5, 47, 0, 0, 257, 61, 1, 0, 0, 0, 258, 259, 7, 0, 0, 0,
-		259, 63, 1, 0, 0, 0, 260, 261, 7, 5, 0, 0, 261, 65, 1, 0, 0, 0, 24, 69,
-		77, 80, 105, 109, 117, 124, 146, 148, 167, 175, 177, 185, 194, 196, 209,
-		221, 226, 229, 234, 241, 244, 249, 254,
+		31, 2, 32, 7, 32, 2, 33, 7, 33, 2, 34, 7, 34, 2, 35, 7, 35, 2, 36, 7, 36,
+		2, 37, 7, 37, 2, 38, 7, 38, 2, 39, 7, 39, 2, 40, 7, 40, 2, 41, 7, 41, 2,
+		42, 7, 42, 2, 43, 7, 43, 2, 44, 7, 44, 2, 45, 7, 45, 2, 46, 7, 46, 2, 47,
+		7, 47, 2, 48, 7, 48, 2, 49, 7, 49, 2, 50, 7, 50, 2, 51, 7, 51, 2, 52, 7,
+		52, 2, 53, 7, 53, 1, 0, 1, 0, 1, 0, 5, 0, 112, 8, 0, 10, 0, 12, 0, 115,
+		9, 0, 1, 0, 1, 0, 1, 1, 5, 1, 120, 8, 1, 10, 1, 12, 1, 123, 9, 1, 1, 1,
+		1, 1, 1, 1, 3, 1, 128, 8, 1, 1, 1, 3, 1, 131, 8, 1, 1, 1, 3, 1, 134, 8,
+		1, 1, 1, 3, 1, 137, 8, 1, 1, 1, 3, 1, 140, 8, 1, 1, 1, 3, 1, 143, 8, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 3, 1, 149, 8, 1, 1, 1, 1, 1, 1, 2, 1, 2, 1, 2,
+		1, 2, 1, 2, 1, 2, 5, 2, 159, 8, 2, 10, 2, 12, 2, 162, 9, 2, 1, 2, 1, 2,
+		1, 3, 1, 3, 1, 3, 1, 3, 3, 3, 170, 8, 3, 1, 3, 1, 3, 1, 3, 1, 4, 1, 4,
+		1, 4, 1, 4, 3, 4, 179, 8, 4, 1, 5, 1, 5, 1, 5, 3, 5, 184, 8, 5, 1, 5, 1,
+		5, 1, 6, 1, 6, 1, 6, 3, 6, 191, 8, 6, 1, 7, 1, 7, 1, 7, 1, 8, 1, 8, 1,
+		8, 1, 8, 1, 8, 3, 8, 201, 8, 8, 1, 9, 1, 9, 1, 9, 1, 10, 1, 10, 1, 10,
+		1, 11, 1, 11, 1, 12, 1, 12, 1, 13, 1, 13, 1, 13, 1, 14, 1, 14, 1, 14, 1,
+		15, 1, 15, 1, 15, 3, 15, 222, 8, 15, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16,
+		1, 17, 1, 17, 1, 17, 1, 18, 1, 18, 1, 18, 1, 18, 1, 18, 5, 18, 237, 8,
+		18, 10, 18, 12, 18, 240, 9, 18, 1, 19, 1, 19, 1, 19, 1, 19, 3, 19, 246,
+		8, 19, 1, 20, 1, 20, 1, 20, 1, 20, 1, 20, 1, 20, 3, 20, 254, 8, 20, 1,
+		21, 1, 21, 1, 21, 1, 21, 1, 21, 5, 21, 261, 8, 21, 10, 21, 12, 21, 264,
+		9, 21, 1, 21, 1, 21, 1, 22, 1, 22, 1, 22, 1, 22, 5, 22, 272, 8, 22, 10,
+		22, 12, 22, 275, 9, 22, 3, 22, 277, 8, 22, 1, 22, 1, 22, 3, 22, 281, 8,
+		22, 1, 22, 1, 22, 1, 23, 1, 23, 1, 23, 1, 23, 3, 23, 289, 8, 23, 1, 24,
+		1, 24, 1, 24, 1, 24, 1, 24, 1, 24, 5, 24, 297, 8, 24, 10, 24, 12, 24, 300,
+		9, 24, 1, 24, 3, 24, 303, 8, 24, 1, 24, 1, 24, 1, 25, 1, 25, 3, 25, 309,
+		8, 25, 1, 25, 3, 25, 312, 8, 25, 1, 25, 1, 25, 1, 25, 1, 26, 1, 26, 3,
+		26, 319, 8, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 3, 26, 326, 8, 26, 1,
+		26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26,
+		1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1, 26, 1,
+		26, 1, 26, 1, 26, 1, 26, 1, 26, 5, 26, 354, 8, 26, 10, 26, 12, 26, 357,
+		9, 26, 1, 27, 1, 27, 1, 28, 1, 28, 1, 29, 1, 29, 1, 30, 1, 30, 1, 31, 1,
+		31, 1, 32, 1, 32, 1, 32, 1, 32, 1, 32, 1, 32, 1, 32, 3, 32, 376, 8, 32,
+		1, 32, 1, 32, 1, 32, 1, 32, 1, 32, 1, 32, 5, 32, 384, 8, 32, 10, 32, 12,
+		32, 387, 9, 32, 1, 33, 1, 33, 1, 33, 1, 33, 1, 33, 1, 33, 1, 33, 1, 33,
+		3, 33, 397, 8, 33, 1, 34, 1, 34, 1, 34, 1, 34, 5, 34, 403, 8, 34, 10, 34,
+		12, 34, 406, 9, 34, 3, 34, 408, 8, 34, 1, 34, 1, 34, 1, 34, 1, 34, 1, 34,
+		5, 34, 415, 8, 34, 10, 34, 12, 34, 418, 9, 34, 3, 34, 420, 8, 34, 1, 34,
+		3, 34, 423, 8, 34, 1, 35, 1, 35, 1, 35, 3, 35, 428, 8, 35, 1, 36, 1, 36,
+		1, 36, 3, 36, 433, 8, 36, 1, 36, 1, 36, 1, 36, 1, 36, 5, 36, 439, 8, 36,
+		10, 36, 12, 36, 442, 9, 36, 1, 37, 1, 37, 1, 37, 1, 37, 1, 38, 1, 38, 1,
+		38, 1, 39, 1, 39, 1, 39, 1, 39, 1, 39, 1, 39, 1, 39, 1, 39, 1, 39, 1, 40,
+		1, 40, 1, 40, 3, 40, 463, 8, 40, 1, 40, 1, 40, 1, 41, 1, 41, 1, 41, 1,
+		42, 1, 42, 1, 42, 5, 42, 473, 8, 42, 10, 42, 12, 42, 476, 9, 42, 1, 43,
+		1, 43, 3, 43, 480, 8, 43, 1, 44, 3, 44, 483, 8, 44, 1, 44, 1, 44, 1, 45,
+		3, 45, 488, 8, 45, 1, 45, 1, 45, 1, 46, 1, 46, 1, 46, 3, 46, 495, 8, 46,
+		1, 47, 3, 47, 498, 8, 47, 1, 47, 1, 47, 1, 48, 3, 48, 503, 8, 48, 1, 48,
+		1, 48, 1, 49, 3, 49, 508, 8, 49, 1, 49, 1, 49, 1, 50, 3, 50, 513, 8, 50,
+		1, 50, 1, 50, 1, 50, 3, 50, 518, 8, 50, 1, 50, 1, 50, 3, 50, 522, 8, 50,
+		1, 51, 3, 51, 525, 8, 51, 1, 51, 1, 51, 1, 52, 1, 52, 1, 53, 1, 53, 1,
+		53, 0, 3, 52, 64, 72, 54, 0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24,
+		26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58, 60,
+		62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 82, 84, 86, 88, 90, 92, 94, 96,
+		98, 100, 102, 104, 106, 0, 8, 2, 0, 26, 26, 46, 46, 1, 0, 47, 48, 1, 0,
+		30, 34, 1, 0, 4, 6, 2, 0, 2, 3, 41, 42, 2, 0, 26, 27, 35, 40, 2, 0, 6,
+		6, 46, 46, 1, 0, 20, 21, 555, 0, 113, 1, 0, 0, 0, 2, 121, 1, 0, 0, 0, 4,
+		152, 1, 0, 0, 0, 6, 165, 1, 0, 0, 0, 8, 174, 1, 0, 0, 0, 10, 180, 1, 0,
+		0, 0, 12, 187, 1, 0, 0, 0, 14, 192, 1, 0, 0, 0, 16, 195, 1, 0, 0, 0, 18,
+		202, 1, 0, 0, 0, 20, 205, 1, 0, 0, 0, 22, 208, 1, 0, 0, 0, 24, 210, 1,
+		0, 0, 0, 26, 212, 1, 0, 0, 0, 28, 215, 1, 0, 0, 0, 30, 218, 1, 0, 0, 0,
+		32, 223, 1, 0, 0, 0, 34, 228, 1, 0, 0, 0, 36, 231, 1, 0, 0, 0, 38, 245,
+		1, 0, 0, 0, 40, 247, 1, 0, 0, 0, 42, 255, 1, 0, 0, 0, 44, 267, 1, 0, 0,
+		0, 46, 284, 1, 0, 0, 0, 48, 290, 1, 0, 0, 0, 50, 311, 1, 0, 0, 0, 52, 325,
+		1, 0, 0, 0, 54, 358, 1, 0, 0, 0, 56, 360, 1, 0, 0, 0, 58, 362, 1, 0, 0,
+		0, 60, 364, 1, 0, 0, 0, 62, 366, 1, 0, 0, 0, 64, 375, 1, 0, 0, 0, 66, 396,
+		1, 0, 0, 0, 68, 422, 1, 0, 0, 0, 70, 424, 1, 0, 0, 0, 72, 432, 1, 0, 0,
+		0, 74, 443, 1, 0, 0, 0, 76, 447, 1, 0, 0, 0, 78, 450, 1, 0, 0, 0, 80, 459,
+		1, 0, 0, 0, 82, 466, 1, 0, 0, 0, 84, 469, 1, 0, 0, 0, 86, 479, 1, 0, 0,
+		0, 88, 482, 1, 0, 0, 0, 90, 487, 1, 0, 0, 0, 92, 494, 1, 0, 0, 0, 94, 497,
+		1, 0, 0, 0, 96, 502, 1, 0, 0, 0, 98, 507, 1, 0, 0, 0, 100, 521, 1, 0, 0,
+		0, 102, 524, 1, 0, 0, 0, 104, 528, 1, 0, 0, 0, 106, 530, 1, 0, 0, 0, 108,
+		112, 3, 2, 1, 0, 109, 112, 3, 6, 3, 0, 110, 112, 3, 8, 4, 0, 111, 108,
+		1, 0, 0, 0, 111, 109, 1, 0, 0, 0, 111, 110, 1, 0, 0, 0, 112, 115, 1, 0,
+		0, 0, 113, 111, 1, 0, 0, 0, 113, 114, 1, 0, 0, 0, 114, 116, 1, 0, 0, 0,
+		115, 113, 1, 0, 0, 0, 116, 117, 5, 0, 0, 1, 117, 1, 1, 0, 0, 0, 118, 120,
+		3, 4, 2, 0, 119, 118, 1, 0, 0, 0, 120, 123, 1, 0, 0, 0, 121, 119, 1, 0,
+		0, 0, 121, 122, 1, 0, 0, 0, 122, 124, 1, 0, 0, 0, 123, 121, 1, 0, 0, 0,
+		124, 125, 5, 15, 0, 0, 125, 127, 3, 22, 11, 0, 126, 128, 3, 24, 12, 0,
+		127, 126, 1, 0, 0, 0, 127, 128, 1, 0, 0, 0, 128, 130, 1, 0, 0, 0, 129,
+		131, 3, 26, 13, 0, 130, 129, 1, 0, 0, 0, 130, 131, 1, 0, 0, 0, 131, 133,
+		1, 0, 0, 0, 132, 134, 3, 14, 7, 0, 133, 132, 1, 0, 0, 0, 133, 134, 1, 0,
+		0, 0, 134, 136, 1, 0, 0, 0, 135, 137, 3, 16, 8, 0, 136, 135, 1, 0, 0, 0,
+		136, 137, 1, 0, 0, 0, 137, 139, 1, 0, 0, 0, 138, 140, 3, 18, 9, 0, 139,
+		138, 1, 0, 0, 0, 139, 140, 1, 0, 0, 0, 140, 142, 1, 0, 0, 0, 141, 143,
+		3, 20, 10, 0, 142, 141, 1, 0, 0, 0, 142, 143, 1, 0, 0, 0, 143, 144, 1,
+		0, 0, 0, 144, 145, 5, 9, 0, 0, 145, 146, 3, 28, 14, 0, 146, 148, 3, 30,
+		15, 0, 147, 149, 3, 32, 16, 0, 148, 147, 1, 0, 0, 0, 148, 149, 1, 0, 0,
+		0, 149, 150, 1, 0, 0, 0, 150, 151, 5, 10, 0, 0, 151, 3, 1, 0, 0, 0, 152,
+		153, 5, 44, 0, 0, 153, 154, 5, 46, 0, 0, 154, 155, 5, 11, 0, 0, 155, 160,
+		3, 104, 52, 0, 156, 157, 5, 1, 0, 0, 157, 159, 3, 104, 52, 0, 158, 156,
+		1, 0, 0, 0, 159, 162, 1, 0, 0, 0, 160, 158, 1, 0, 0, 0, 160, 161, 1, 0,
+		0, 0, 161, 163, 1, 0, 0, 0, 162, 160, 1, 0, 0, 0, 163, 164, 5, 12, 0, 0,
+		164, 5, 1, 0, 0, 0, 165, 166, 5, 46, 0, 0, 166, 167, 3, 104, 52, 0, 167,
+		169, 5, 9, 0, 0, 168, 170, 3, 10, 5, 0, 169, 168, 1, 0, 0, 0, 169, 170,
+		1, 0, 0, 0, 170, 171, 1, 0, 0, 0, 171, 172, 3, 12, 6, 0, 172, 173, 5, 10,
+		0, 0, 173, 7, 1, 0, 0, 0, 174, 175, 5, 46, 0, 0, 175, 176, 5, 16, 0, 0,
+		176, 178, 3, 52, 26, 0, 177, 179, 5, 8, 0, 0, 178, 177, 1, 0, 0, 0, 178,
+		179, 1, 0, 0, 0, 179, 9, 1, 0, 0, 0, 180, 181, 5, 46, 0, 0, 181, 183, 5,
+		9, 0, 0, 182, 184, 3, 36, 18, 0, 183, 182, 1, 0, 0, 0, 183, 184, 1, 0,
+		0, 0, 184, 185, 1, 0, 0, 0, 185, 186, 5, 10, 0, 0, 186, 11, 1, 0, 0, 0,
+		187, 188, 5, 46, 0, 0, 188, 190, 3, 52, 26, 0, 189, 191, 5, 8, 0, 0, 190,
+		189, 1, 0, 0, 0, 190, 191, 1, 0, 0, 0, 191, 13, 1, 0, 0, 0, 192, 193, 5,
+		24, 0, 0, 193, 194, 3, 92, 46, 0, 194, 15, 1, 0, 0, 0, 195, 196, 5, 46,
+		0, 0, 196, 197, 5, 3, 0, 0, 197, 198, 5, 46, 0, 0, 198, 200, 3, 92, 46,
+		0, 199, 201, 5, 25, 0, 0, 200, 199, 1, 0, 0, 0, 200, 201, 1, 0, 0, 0, 201,
+		17, 1, 0, 0, 0, 202, 203, 5, 46, 0, 0, 203, 204, 5, 50, 0, 0, 204, 19,
+		1, 0, 0, 0, 205, 206, 5, 46, 0, 0, 206, 207, 5, 46, 0, 0, 207, 21, 1, 0,
+		0, 0, 208, 209, 7, 0, 0, 0, 209, 23, 1, 0, 0, 0, 210, 211, 7, 1, 0, 0,
+		211, 25, 1, 0, 0, 0, 212, 213, 5, 46, 0, 0, 213, 214, 3, 104, 52, 0, 214,
+		27, 1, 0, 0, 0, 215, 216, 5, 16, 0, 0, 216, 217, 3, 52, 26, 0, 217, 29,
+		1, 0, 0, 0, 218, 221, 5, 17, 0, 0, 219, 222, 3, 34, 17, 0, 220, 222, 3,
+		36, 18, 0, 221, 219, 1, 0, 0, 0, 221, 220, 1, 0, 0, 0, 222, 31, 1, 0, 0,
+		0, 223, 224, 5, 46, 0, 0, 224, 225, 5, 9, 0, 0, 225, 226, 3, 36, 18, 0,
+		226, 227, 5, 10, 0, 0, 227, 33, 1, 0, 0, 0, 228, 229, 5, 46, 0, 0, 229,
+		230, 5, 49, 0, 0, 230, 35, 1, 0, 0, 0, 231, 232, 3, 38, 19, 0, 232, 238,
+		5, 8, 0, 0, 233, 234, 3, 38, 19, 0, 234, 235, 5, 8, 0, 0, 235, 237, 1,
+		0, 0, 0, 236, 233, 1, 0, 0, 0, 237, 240, 1, 0, 0, 0, 238, 236, 1, 0, 0,
+		0, 238, 239, 1, 0, 0, 0, 239, 37, 1, 0, 0, 0, 240, 238, 1, 0, 0, 0, 241,
+		246, 3, 46, 23, 0, 242, 246, 3, 40, 20, 0, 243, 246, 3, 42, 21, 0, 244,
+		246, 3, 64, 32, 0, 245, 241, 1, 0, 0, 0, 245, 242, 1, 0, 0, 0, 245, 243,
+		1, 0, 0, 0, 245, 244, 1, 0, 0, 0, 246, 39, 1, 0, 0, 0, 247, 248, 5, 46,
+		0, 0, 248, 249, 7, 0, 0, 0, 249, 250, 5, 46, 0, 0, 250, 253, 3, 52, 26,
+		0, 251, 252, 5, 46, 0, 0, 252, 254, 3, 52, 26, 0, 253, 251, 1, 0, 0, 0,
+		253, 254, 1, 0, 0, 0, 254, 41, 1, 0, 0, 0, 255, 256, 5, 46, 0, 0, 256,
+		257, 3, 52, 26, 0, 257, 258, 5, 9, 0, 0, 258, 262, 3, 44, 22, 0, 259, 261,
+		3, 44, 22, 0, 260, 259, 1, 0, 0, 0, 261, 264, 1, 0, 0, 0, 262, 260, 1,
+		0, 0, 0, 262, 263, 1, 0, 0, 0, 263, 265, 1, 0, 0, 0, 264, 262, 1, 0, 0,
+		0, 265, 266, 5, 10, 0, 0, 266, 43, 1, 0, 0, 0, 267, 276, 5, 46, 0, 0, 268,
+		273, 3, 52, 26, 0, 269, 270, 5, 1, 0, 0, 270, 272, 3, 52, 26, 0, 271, 269,
+		1, 0, 0, 0, 272, 275, 1, 0, 0, 0, 273, 271, 1, 0, 0, 0, 273, 274, 1, 0,
+		0, 0, 274, 277, 1, 0, 0, 0, 275, 273, 1, 0, 0, 0, 276, 268, 1, 0, 0, 0,
+		276, 277, 1, 0, 0, 0, 277, 278, 1, 0, 0, 0, 278, 280, 5, 9, 0, 0, 279,
+		281, 3, 36, 18, 0, 280, 279, 1, 0, 0, 0, 280, 281, 1, 0, 0, 0, 281, 282,
+		1, 0, 0, 0, 282, 283, 5, 10, 0, 0, 283, 45, 1, 0, 0, 0, 284, 285, 3, 72,
+		36, 0, 285, 288, 7, 2, 0, 0, 286, 289, 3, 48, 24, 0, 287, 289, 3, 52, 26,
+		0, 288, 286, 1, 0, 0, 0, 288, 287, 1, 0, 0, 0, 289, 47, 1, 0, 0, 0, 290,
+		291, 5, 46, 0, 0, 291, 292, 3, 52, 26, 0, 292, 293, 5, 9, 0, 0, 293, 298,
+		3, 50, 25, 0, 294, 295, 5, 1, 0, 0, 295, 297, 3, 50, 25, 0, 296, 294, 1,
+		0, 0, 0, 297, 300, 1, 0, 0, 0, 298, 296, 1, 0, 0, 0, 298, 299, 1, 0, 0,
+		0, 299, 302, 1, 0, 0, 0, 300, 298, 1, 0, 0, 0, 301, 303, 5, 1, 0, 0, 302,
+		301, 1, 0, 0, 0, 302, 303, 1, 0, 0, 0, 303, 304, 1, 0, 0, 0, 304, 305,
+		5, 10, 0, 0, 305, 49, 1, 0, 0, 0, 306, 312, 5, 43, 0, 0, 307, 309, 3, 58,
+		29, 0, 308, 307, 1, 0, 0, 0, 308, 309, 1, 0, 0, 0, 309, 310, 1, 0, 0, 0,
+		310, 312, 3, 52, 26, 0, 311, 306, 1, 0, 0, 0, 311, 308, 1, 0, 0, 0, 312,
+		313, 1, 0, 0, 0, 313, 314, 5, 28, 0, 0, 314, 315, 3, 52, 26, 0, 315, 51,
+		1, 0, 0, 0, 316, 318, 6, 26, -1, 0, 317, 319, 5, 23, 0, 0, 318, 317, 1,
+		0, 0, 0, 318, 319, 1, 0, 0, 0, 319, 320, 1, 0, 0, 0, 320, 321, 5, 11, 0,
+		0, 321, 322, 3, 52, 26, 0, 322, 323, 5, 12, 0, 0, 323, 326, 1, 0, 0, 0,
+		324, 326, 3, 64, 32, 0, 325, 316, 1, 0, 0, 0, 325, 324, 1, 0, 0, 0, 326,
+		355, 1, 0, 0, 0, 327, 328, 10, 8, 0, 0, 328, 329, 3, 54, 27, 0, 329, 330,
+		3, 52, 26, 9, 330, 354, 1, 0, 0, 0, 331, 332, 10, 7, 0, 0, 332, 333, 3,
+		56, 28, 0, 333, 334, 3, 52, 26, 8, 334, 354, 1, 0, 0, 0, 335, 336, 10,
+		6, 0, 0, 336, 337, 5, 40, 0, 0, 337, 338, 3, 52, 26, 0, 338, 339, 5, 46,
+		0, 0, 339, 340, 3, 52, 26, 7, 340, 354, 1, 0, 0, 0, 341, 342, 10, 5, 0,
+		0, 342, 343, 3, 58, 29, 0, 343, 344, 3, 52, 26, 6, 344, 354, 1, 0, 0, 0,
+		345, 346, 10, 4, 0, 0, 346, 347, 3, 60, 30, 0, 347, 348, 3, 52, 26, 5,
+		348, 354, 1, 0, 0, 0, 349, 350, 10, 3, 0, 0, 350, 351, 3, 62, 31, 0, 351,
+		352, 3, 52, 26, 4, 352, 354, 1, 0, 0, 0, 353, 327, 1, 0, 0, 0, 353, 331,
+		1, 0, 0, 0, 353, 335, 1, 0, 0, 0, 353, 341, 1, 0, 0, 0, 353, 345, 1, 0,
+		0, 0, 353, 349, 1, 0, 0, 0, 354, 357, 1, 0, 0, 0, 355, 353, 1, 0, 0, 0,
+		355, 356, 1, 0, 0, 0, 356, 53, 1, 0, 0, 0, 357, 355, 1, 0, 0, 0, 358, 359,
+		7, 3, 0, 0, 359, 55, 1, 0, 0, 0, 360, 361, 7, 4, 0, 0, 361, 57, 1, 0, 0,
+		0, 362, 363, 7, 5, 0, 0, 363, 59, 1, 0, 0, 0, 364, 365, 5, 18, 0, 0, 365,
+		61, 1, 0, 0, 0, 366, 367, 5, 19, 0, 0, 367, 63, 1, 0, 0, 0, 368, 369, 6,
+		32, -1, 0, 369, 376, 3, 66, 33, 0, 370, 376, 3, 72, 36, 0, 371, 376, 3,
+		78, 39, 0, 372, 376, 3, 80, 40, 0, 373, 374, 5, 23, 0, 0, 374, 376, 3,
+		64, 32, 1, 375, 368, 1, 0, 0, 0, 375, 370, 1, 0, 0, 0, 375, 371, 1, 0,
+		0, 0, 375, 372, 1, 0, 0, 0, 375, 373, 1, 0, 0, 0, 376, 385, 1, 0, 0, 0,
+		377, 378, 10, 4, 0, 0, 378, 384, 3, 82, 41, 0, 379, 380, 10, 3, 0, 0, 380,
+		384, 3, 76, 38, 0, 381, 382, 10, 2, 0, 0, 382, 384, 3, 74, 37, 0, 383,
+		377, 1, 0, 0, 0, 383, 379, 1, 0, 0, 0, 383, 381, 1, 0, 0, 0, 384, 387,
+		1, 0, 0, 0, 385, 383, 1, 0, 0, 0, 385, 386, 1, 0, 0, 0, 386, 65, 1, 0,
+		0, 0, 387, 385, 1, 0, 0, 0, 388, 397, 3, 104, 52, 0, 389, 397, 3, 92, 46,
+		0, 390, 397, 3, 86, 43, 0, 391, 397, 3, 100, 50, 0, 392, 397, 3, 102, 51,
+		0, 393, 397, 3, 106, 53, 0, 394, 397, 3, 68, 34, 0, 395, 397, 5, 22, 0,
+		0, 396, 388, 1, 0, 0, 0, 396, 389, 1, 0, 0, 0, 396, 390, 1, 0, 0, 0, 396,
+		391, 1, 0, 0, 0, 396, 392, 1, 0, 0, 0, 396, 393, 1, 0, 0, 0, 396, 394,
+		1, 0, 0, 0, 396, 395, 1, 0, 0, 0, 397, 67, 1, 0, 0, 0, 398, 407, 5, 9,
+		0, 0, 399, 404, 3, 70, 35, 0, 400, 401, 5, 1, 0, 0, 401, 403, 3, 70, 35,
+		0, 402, 400, 1, 0, 0, 0, 403, 406, 1, 0, 0, 0, 404, 402, 1, 0, 0, 0, 404,
+		405, 1, 0, 0, 0, 405, 408, 1, 0, 0, 0, 406, 404, 1, 0, 0, 0, 407, 399,
+		1, 0, 0, 0, 407, 408, 1, 0, 0, 0, 408, 409, 1, 0, 0, 0, 409, 423, 5, 10,
+		0, 0, 410, 419, 5, 13, 0, 0, 411, 416, 3, 70, 35, 0, 412, 413, 5, 1, 0,
+		0, 413, 415, 3, 70, 35, 0, 414, 412, 1, 0, 0, 0, 415, 418, 1, 0, 0, 0,
+		416, 414, 1, 0, 0, 0, 416, 417, 1, 0, 0, 0, 417, 420, 1, 0, 0, 0, 418,
+		416, 1, 0, 0, 0, 419, 411, 1, 0, 0, 0, 419, 420, 1, 0, 0, 0, 420, 421,
+		1, 0, 0, 0, 421, 423, 5, 14, 0, 0, 422, 398, 1, 0, 0, 0, 422, 410, 1, 0,
+		0, 0, 423, 69, 1, 0, 0, 0, 424, 427, 3, 66, 33, 0, 425, 426, 5, 45, 0,
+		0, 426, 428, 3, 66, 33, 0, 427, 425, 1, 0, 0, 0, 427, 428, 1, 0, 0, 0,
+		428, 71, 1, 0, 0, 0, 429, 430, 6, 36, -1, 0, 430, 433, 5, 46, 0, 0, 431,
+		433, 5, 26, 0, 0, 432, 429, 1, 0, 0, 0, 432, 431, 1, 0, 0, 0, 433, 440,
+		1, 0, 0, 0, 434, 435, 10, 4, 0, 0, 435, 439, 3, 76, 38, 0, 436, 437, 10,
+		3, 0, 0, 437, 439, 3, 74, 37, 0, 438, 434, 1, 0, 0, 0, 438, 436, 1, 0,
+		0, 0, 439, 442, 1, 0, 0, 0, 440, 438, 1, 0, 0, 0, 440, 441, 1, 0, 0, 0,
+		441, 73, 1, 0, 0, 0, 442, 440, 1, 0, 0, 0, 443, 444, 5, 13, 0, 0, 444,
+		445, 3, 52, 26, 0, 445, 446, 5, 14, 0, 0, 446, 75, 1, 0, 0, 0, 447, 448,
+		5, 7, 0, 0, 448, 449, 7, 0, 0, 0, 449, 77, 1, 0, 0, 0, 450, 451, 5, 46,
+		0, 0, 451, 452, 5, 11, 0, 0, 452, 453, 3, 52, 26, 0, 453, 454, 5, 1, 0,
+		0, 454, 455, 7, 0, 0, 0, 455, 456, 5, 29, 0, 0, 456, 457, 3, 52, 26, 0,
+		457, 458, 5, 12, 0, 0, 458, 79, 1, 0, 0, 0, 459, 460, 7, 0, 0, 0, 460,
+		462, 5, 11, 0, 0, 461, 463, 3, 84, 42, 0, 462, 461, 1, 0, 0, 0, 462, 463,
+		1, 0, 0, 0, 463, 464, 1, 0, 0, 0, 464, 465, 5, 12, 0, 0, 465, 81, 1, 0,
+		0, 0, 466, 467, 5, 7, 0, 0, 467, 468, 3, 80, 40, 0, 468, 83, 1, 0, 0, 0,
+		469, 474, 3, 52, 26, 0, 470, 471, 5, 1, 0, 0, 471, 473, 3, 52, 26, 0, 472,
+		470, 1, 0, 0, 0, 473, 476, 1, 0, 0, 0, 474, 472, 1, 0, 0, 0, 474, 475,
+		1, 0, 0, 0, 475, 85, 1, 0, 0, 0, 476, 474, 1, 0, 0, 0, 477, 480, 3, 88,
+		44, 0, 478, 480, 3, 90, 45, 0, 479, 477, 1, 0, 0, 0, 479, 478, 1, 0, 0,
+		0, 480, 87, 1, 0, 0, 0, 481, 483, 5, 3, 0, 0, 482, 481, 1, 0, 0, 0, 482,
+		483, 1, 0, 0, 0, 483, 484, 1, 0, 0, 0, 484, 485, 5, 51, 0, 0, 485, 89,
+		1, 0, 0, 0, 486, 488, 5, 3, 0, 0, 487, 486, 1, 0, 0, 0, 487, 488, 1, 0,
+		0, 0, 488, 489, 1, 0, 0, 0, 489, 490, 5, 53, 0, 0, 490, 91, 1, 0, 0, 0,
+		491, 495, 3, 94, 47, 0, 492, 495, 3, 96, 48, 0, 493, 495, 3, 98, 49, 0,
+		494, 491, 1, 0, 0, 0, 494, 492, 1, 0, 0, 0, 494, 493, 1, 0, 0, 0, 495,
+		93, 1, 0, 0, 0, 496, 498, 5, 3, 0, 0, 497, 496, 1, 0, 0, 0, 497, 498, 1,
+		0, 0, 0, 498, 499, 1, 0, 0, 0, 499, 500, 5, 55, 0, 0, 500, 95, 1, 0, 0,
+		0, 501, 503, 5, 3, 0, 0, 502, 501, 1, 0, 0, 0, 502, 503, 1, 0, 0, 0, 503,
+		504, 1, 0, 0, 0, 504, 505, 5, 56, 0, 0, 505, 97, 1, 0, 0, 0, 506, 508,
+		5, 3, 0, 0, 507, 506, 1, 0, 0, 0, 507, 508, 1, 0, 0, 0, 508, 509, 1, 0,
+		0, 0, 509, 510, 5, 57, 0, 0, 510, 99, 1, 0, 0, 0, 511, 513, 5, 3, 0, 0,
+		512, 511, 1, 0, 0, 0, 512, 513, 1, 0, 0, 0, 513, 514, 1, 0, 0, 0, 514,
+		522, 5, 58, 0, 0, 515, 518, 3, 94, 47, 0, 516, 518, 3, 88, 44, 0, 517,
+		515, 1, 0, 0, 0, 517, 516, 1, 0, 0, 0, 518, 519, 1, 0, 0, 0, 519, 520,
+		7, 6, 0, 0, 520, 522, 1, 0, 0, 0, 521, 512, 1, 0, 0, 0, 521, 517, 1, 0,
+		0, 0, 522, 101, 1, 0, 0, 0, 523, 525, 5, 3, 0, 0, 524, 523, 1, 0, 0, 0,
+		524, 525, 1, 0, 0, 0, 525, 526, 1, 0, 0, 0, 526, 527, 5, 59, 0, 0, 527,
+		103, 1, 0, 0, 0, 528, 529, 7, 1, 0, 0, 529, 105, 1, 0, 0, 0, 530, 531,
+		7, 7, 0, 0, 531, 107, 1, 0, 0, 0, 59, 111, 113, 121, 127, 130, 133, 136,
+		139, 142, 148, 160, 169, 178, 183, 190, 200, 221, 238, 245, 253, 262, 273,
+		276, 280, 288, 298, 302, 308, 311, 318, 325, 353, 355, 375, 383, 385, 396,
+		404, 407, 416, 419, 422, 427, 432, 438, 440, 462, 474, 479, 482, 487, 494,
+		497, 502, 507, 512, 517, 521, 524,
 	}
 	deserializer := antlr.NewATNDeserializer(nil)
 	staticData.atn = deserializer.Deserialize(staticData.serializedATN)
@@ -233,69 +364,102 @@ const (
 	grulev3ParserNIL_LITERAL       = 22
 	grulev3ParserNEGATION          = 23
 	grulev3ParserSALIENCE          = 24
-	grulev3ParserEQUALS            = 25
-	grulev3ParserASSIGN            = 26
-	grulev3ParserPLUS_ASIGN        = 27
-	grulev3ParserMINUS_ASIGN       = 28
-	grulev3ParserDIV_ASIGN         = 29
-	grulev3ParserMUL_ASIGN         = 30
-	grulev3ParserGT                = 31
-	grulev3ParserLT                = 32
-	grulev3ParserGTE               = 33
-	grulev3ParserLTE               = 34
-	grulev3ParserNOTEQUALS         = 35
-	grulev3ParserBITAND            = 36
-	grulev3ParserBITOR             = 37
-	grulev3ParserSIMPLENAME        = 38
-	grulev3ParserDQUOTA_STRING     = 39
-	grulev3ParserSQUOTA_STRING     = 40
-	grulev3ParserDECIMAL_FLOAT_LIT = 41
-	grulev3ParserDECIMAL_EXPONENT  = 42
-	grulev3ParserHEX_FLOAT_LIT     = 43
-	grulev3ParserHEX_EXPONENT      = 44
-	grulev3ParserDEC_LIT           = 45
-	grulev3ParserHEX_LIT           = 46
-	grulev3ParserOCT_LIT           = 47
-	grulev3ParserSPACE             = 48
-	grulev3ParserCOMMENT           = 49
-	grulev3ParserLINE_COMMENT      = 50
+	grulev3ParserPER_EXECUTION     = 25
+	grulev3ParserIN                = 26
+	grulev3ParserEQUALS            = 27
+	grulev3ParserARROW             = 28
+	grulev3ParserLAMBDA            = 29
+	grulev3ParserASSIGN            = 30
+	grulev3ParserPLUS_ASIGN        = 31
+	grulev3ParserMINUS_ASIGN       = 32
+	grulev3ParserDIV_ASIGN         = 33
+	grulev3ParserMUL_ASIGN         = 34
+	grulev3ParserGT                = 35
+	grulev3ParserLT                = 36
+	grulev3ParserGTE               = 37
+	grulev3ParserLTE               = 38
+	grulev3ParserNOTEQUALS         = 39
+	grulev3ParserAPPROX_EQUALS     = 40
+	grulev3ParserBITAND            = 41
+	grulev3ParserBITOR             = 42
+	grulev3ParserUNDERSCORE        = 43
+	grulev3ParserAT                = 44
+	grulev3ParserCOLON             = 45
+	grulev3ParserSIMPLENAME        = 46
+	grulev3ParserDQUOTA_STRING     = 47
+	grulev3ParserSQUOTA_STRING     = 48
+	grulev3ParserSCRIPT_LIT        = 49
+	grulev3ParserDURATION_LIT      = 50
+	grulev3ParserDECIMAL_FLOAT_LIT = 51
+	grulev3ParserDECIMAL_EXPONENT  = 52
+	grulev3ParserHEX_FLOAT_LIT     = 53
+	grulev3ParserHEX_EXPONENT      = 54
+	grulev3ParserDEC_LIT           = 55
+	grulev3ParserHEX_LIT           = 56
+	grulev3ParserOCT_LIT           = 57
+	grulev3ParserQUANTITY_LIT      = 58
+	grulev3ParserSUFFIX_LIT        = 59
+	grulev3ParserSPACE             = 60
+	grulev3ParserCOMMENT           = 61
+	grulev3ParserLINE_COMMENT      = 62
 )
 
 // grulev3Parser rules.
 const (
 	grulev3ParserRULE_grl                     = 0
 	grulev3ParserRULE_ruleEntry               = 1
-	grulev3ParserRULE_salience                = 2
-	grulev3ParserRULE_ruleName                = 3
-	grulev3ParserRULE_ruleDescription         = 4
-	grulev3ParserRULE_whenScope               = 5
-	grulev3ParserRULE_thenScope               = 6
-	grulev3ParserRULE_thenExpressionList      = 7
-	grulev3ParserRULE_thenExpression          = 8
-	grulev3ParserRULE_assignment              = 9
-	grulev3ParserRULE_expression              = 10
-	grulev3ParserRULE_mulDivOperators         = 11
-	grulev3ParserRULE_addMinusOperators       = 12
-	grulev3ParserRULE_comparisonOperator      = 13
-	grulev3ParserRULE_andLogicOperator        = 14
-	grulev3ParserRULE_orLogicOperator         = 15
-	grulev3ParserRULE_expressionAtom          = 16
-	grulev3ParserRULE_constant                = 17
-	grulev3ParserRULE_variable                = 18
-	grulev3ParserRULE_arrayMapSelector        = 19
-	grulev3ParserRULE_memberVariable          = 20
-	grulev3ParserRULE_functionCall            = 21
-	grulev3ParserRULE_methodCall              = 22
-	grulev3ParserRULE_argumentList            = 23
-	grulev3ParserRULE_floatLiteral            = 24
-	grulev3ParserRULE_decimalFloatLiteral     = 25
-	grulev3ParserRULE_hexadecimalFloatLiteral = 26
-	grulev3ParserRULE_integerLiteral          = 27
-	grulev3ParserRULE_decimalLiteral          = 28
-	grulev3ParserRULE_hexadecimalLiteral      = 29
-	grulev3ParserRULE_octalLiteral            = 30
-	grulev3ParserRULE_stringLiteral           = 31
-	grulev3ParserRULE_booleanLiteral          = 32
+	grulev3ParserRULE_ruleAnnotation          = 2
+	grulev3ParserRULE_testEntry               = 3
+	grulev3ParserRULE_haltEntry               = 4
+	grulev3ParserRULE_givenScope              = 5
+	grulev3ParserRULE_expectScope             = 6
+	grulev3ParserRULE_salience                = 7
+	grulev3ParserRULE_maxFires                = 8
+	grulev3ParserRULE_cooldown                = 9
+	grulev3ParserRULE_criticality             = 10
+	grulev3ParserRULE_ruleName                = 11
+	grulev3ParserRULE_ruleDescription         = 12
+	grulev3ParserRULE_ruleId                  = 13
+	grulev3ParserRULE_whenScope               = 14
+	grulev3ParserRULE_thenScope               = 15
+	grulev3ParserRULE_elseScope               = 16
+	grulev3ParserRULE_scriptBlock             = 17
+	grulev3ParserRULE_thenExpressionList      = 18
+	grulev3ParserRULE_thenExpression          = 19
+	grulev3ParserRULE_collectStatement        = 20
+	grulev3ParserRULE_switchStatement         = 21
+	grulev3ParserRULE_switchCase              = 22
+	grulev3ParserRULE_assignment              = 23
+	grulev3ParserRULE_matchExpression         = 24
+	grulev3ParserRULE_matchArm                = 25
+	grulev3ParserRULE_expression              = 26
+	grulev3ParserRULE_mulDivOperators         = 27
+	grulev3ParserRULE_addMinusOperators       = 28
+	grulev3ParserRULE_comparisonOperator      = 29
+	grulev3ParserRULE_andLogicOperator        = 30
+	grulev3ParserRULE_orLogicOperator         = 31
+	grulev3ParserRULE_expressionAtom          = 32
+	grulev3ParserRULE_constant                = 33
+	grulev3ParserRULE_collectionLiteral       = 34
+	grulev3ParserRULE_collectionElement       = 35
+	grulev3ParserRULE_variable                = 36
+	grulev3ParserRULE_arrayMapSelector        = 37
+	grulev3ParserRULE_memberVariable          = 38
+	grulev3ParserRULE_quantifier              = 39
+	grulev3ParserRULE_functionCall            = 40
+	grulev3ParserRULE_methodCall              = 41
+	grulev3ParserRULE_argumentList            = 42
+	grulev3ParserRULE_floatLiteral            = 43
+	grulev3ParserRULE_decimalFloatLiteral     = 44
+	grulev3ParserRULE_hexadecimalFloatLiteral = 45
+	grulev3ParserRULE_integerLiteral          = 46
+	grulev3ParserRULE_decimalLiteral          = 47
+	grulev3ParserRULE_hexadecimalLiteral      = 48
+	grulev3ParserRULE_octalLiteral            = 49
+	grulev3ParserRULE_quantityLiteral         = 50
+	grulev3ParserRULE_suffixLiteral           = 51
+	grulev3ParserRULE_stringLiteral           = 52
+	grulev3ParserRULE_booleanLiteral          = 53
 )
 
 // IGrlContext is an interface to support dynamic dispatch.
@@ -309,6 +473,10 @@ type IGrlContext interface {
 	EOF() antlr.TerminalNode
 	AllRuleEntry() []IRuleEntryContext
 	RuleEntry(i int) IRuleEntryContext
+	AllTestEntry() []ITestEntryContext
+	TestEntry(i int) ITestEntryContext
+	AllHaltEntry() []IHaltEntryContext
+	HaltEntry(i int) IHaltEntryContext
 
 	// IsGrlContext differentiates from other interfaces.
 	IsGrlContext()
@@ -391,6 +559,88 @@ func (s *GrlContext) RuleEntry(i int) IRuleEntryContext {
 	return t.(IRuleEntryContext)
 }
 
+func (s *GrlContext) AllTestEntry() []ITestEntryContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(ITestEntryContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]ITestEntryContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(ITestEntryContext); ok {
+			tst[i] = t.(ITestEntryContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *GrlContext) TestEntry(i int) ITestEntryContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ITestEntryContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ITestEntryContext)
+}
+
+func (s *GrlContext) AllHaltEntry() []IHaltEntryContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IHaltEntryContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IHaltEntryContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IHaltEntryContext); ok {
+			tst[i] = t.(IHaltEntryContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *GrlContext) HaltEntry(i int) IHaltEntryContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IHaltEntryContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IHaltEntryContext)
+}
+
 func (s *GrlContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
@@ -427,20 +677,44 @@ func (p *grulev3Parser) Grl() (localctx IGrlContext) {
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(69)
+	p.SetState(113)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
 	_la = p.GetTokenStream().LA(1)
 
-	for _la == grulev3ParserRULE {
-		{
-			p.SetState(66)
-			p.RuleEntry()
+	for (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&87960930254848) != 0 {
+		p.SetState(111)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+
+		switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 0, p.GetParserRuleContext()) {
+		case 1:
+			{
+				p.SetState(108)
+				p.RuleEntry()
+			}
+
+		case 2:
+			{
+				p.SetState(109)
+				p.TestEntry()
+			}
+
+		case 3:
+			{
+				p.SetState(110)
+				p.HaltEntry()
+			}
+
+		case antlr.ATNInvalidAltNumber:
+			goto errorExit
 		}
 
-		p.SetState(71)
+		p.SetState(115)
 		p.GetErrorHandler().Sync(p)
 		if p.HasError() {
 			goto errorExit
@@ -448,7 +722,7 @@ func (p *grulev3Parser) Grl() (localctx IGrlContext) {
 		_la = p.GetTokenStream().LA(1)
 	}
 	{
-		p.SetState(72)
+		p.SetState(116)
 		p.Match(grulev3ParserEOF)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -483,8 +757,15 @@ type IRuleEntryContext interface {
 	WhenScope() IWhenScopeContext
 	ThenScope() IThenScopeContext
 	RR_BRACE() antlr.TerminalNode
+	AllRuleAnnotation() []IRuleAnnotationContext
+	RuleAnnotation(i int) IRuleAnnotationContext
 	RuleDescription() IRuleDescriptionContext
+	RuleId() IRuleIdContext
 	Salience() ISalienceContext
+	MaxFires() IMaxFiresContext
+	Cooldown() ICooldownContext
+	Criticality() ICriticalityContext
+	ElseScope() IElseScopeContext
 
 	// IsRuleEntryContext differentiates from other interfaces.
 	IsRuleEntryContext()
@@ -582,6 +863,47 @@ func (s *RuleEntryContext) RR_BRACE() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserRR_BRACE, 0)
 }
 
+func (s *RuleEntryContext) AllRuleAnnotation() []IRuleAnnotationContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IRuleAnnotationContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IRuleAnnotationContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IRuleAnnotationContext); ok {
+			tst[i] = t.(IRuleAnnotationContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *RuleEntryContext) RuleAnnotation(i int) IRuleAnnotationContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IRuleAnnotationContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IRuleAnnotationContext)
+}
+
 func (s *RuleEntryContext) RuleDescription() IRuleDescriptionContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
@@ -598,6 +920,22 @@ func (s *RuleEntryContext) RuleDescription() IRuleDescriptionContext {
 	return t.(IRuleDescriptionContext)
 }
 
+func (s *RuleEntryContext) RuleId() IRuleIdContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IRuleIdContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IRuleIdContext)
+}
+
 func (s *RuleEntryContext) Salience() ISalienceContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
@@ -614,6 +952,70 @@ func (s *RuleEntryContext) Salience() ISalienceContext {
 	return t.(ISalienceContext)
 }
 
+func (s *RuleEntryContext) MaxFires() IMaxFiresContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMaxFiresContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMaxFiresContext)
+}
+
+func (s *RuleEntryContext) Cooldown() ICooldownContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ICooldownContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICooldownContext)
+}
+
+func (s *RuleEntryContext) Criticality() ICriticalityContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ICriticalityContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICriticalityContext)
+}
+
+func (s *RuleEntryContext) ElseScope() IElseScopeContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IElseScopeContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IElseScopeContext)
+}
+
 func (s *RuleEntryContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
@@ -650,8 +1052,28 @@ func (p *grulev3Parser) RuleEntry() (localctx IRuleEntryContext) {
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
+	p.SetState(121)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	for _la == grulev3ParserAT {
+		{
+			p.SetState(118)
+			p.RuleAnnotation()
+		}
+
+		p.SetState(123)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+	}
 	{
-		p.SetState(74)
+		p.SetState(124)
 		p.Match(grulev3ParserRULE)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -659,10 +1081,10 @@ func (p *grulev3Parser) RuleEntry() (localctx IRuleEntryContext) {
 		}
 	}
 	{
-		p.SetState(75)
+		p.SetState(125)
 		p.RuleName()
 	}
-	p.SetState(77)
+	p.SetState(127)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -671,12 +1093,27 @@ func (p *grulev3Parser) RuleEntry() (localctx IRuleEntryContext) {
 
 	if _la == grulev3ParserDQUOTA_STRING || _la == grulev3ParserSQUOTA_STRING {
 		{
-			p.SetState(76)
+			p.SetState(126)
 			p.RuleDescription()
 		}
 
 	}
-	p.SetState(80)
+	p.SetState(130)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 4, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(129)
+			p.RuleId()
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
+	p.SetState(133)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -685,13 +1122,57 @@ func (p *grulev3Parser) RuleEntry() (localctx IRuleEntryContext) {
 
 	if _la == grulev3ParserSALIENCE {
 		{
-			p.SetState(79)
+			p.SetState(132)
 			p.Salience()
 		}
 
+	}
+	p.SetState(136)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 6, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(135)
+			p.MaxFires()
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
+	p.SetState(139)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 7, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(138)
+			p.Cooldown()
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
+	p.SetState(142)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserSIMPLENAME {
+		{
+			p.SetState(141)
+			p.Criticality()
+		}
+
 	}
 	{
-		p.SetState(82)
+		p.SetState(144)
 		p.Match(grulev3ParserLR_BRACE)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -699,15 +1180,29 @@ func (p *grulev3Parser) RuleEntry() (localctx IRuleEntryContext) {
 		}
 	}
 	{
-		p.SetState(83)
+		p.SetState(145)
 		p.WhenScope()
 	}
 	{
-		p.SetState(84)
+		p.SetState(146)
 		p.ThenScope()
 	}
+	p.SetState(148)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserSIMPLENAME {
+		{
+			p.SetState(147)
+			p.ElseScope()
+		}
+
+	}
 	{
-		p.SetState(85)
+		p.SetState(150)
 		p.Match(grulev3ParserRR_BRACE)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -728,63 +1223,100 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// ISalienceContext is an interface to support dynamic dispatch.
-type ISalienceContext interface {
+// IRuleAnnotationContext is an interface to support dynamic dispatch.
+type IRuleAnnotationContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	SALIENCE() antlr.TerminalNode
-	IntegerLiteral() IIntegerLiteralContext
+	AT() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	LR_BRACKET() antlr.TerminalNode
+	AllStringLiteral() []IStringLiteralContext
+	StringLiteral(i int) IStringLiteralContext
+	RR_BRACKET() antlr.TerminalNode
 
-	// IsSalienceContext differentiates from other interfaces.
-	IsSalienceContext()
+	// IsRuleAnnotationContext differentiates from other interfaces.
+	IsRuleAnnotationContext()
 }
 
-type SalienceContext struct {
+type RuleAnnotationContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptySalienceContext() *SalienceContext {
-	var p = new(SalienceContext)
+func NewEmptyRuleAnnotationContext() *RuleAnnotationContext {
+	var p = new(RuleAnnotationContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_salience
+	p.RuleIndex = grulev3ParserRULE_ruleAnnotation
 	return p
 }
 
-func InitEmptySalienceContext(p *SalienceContext) {
+func InitEmptyRuleAnnotationContext(p *RuleAnnotationContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_salience
+	p.RuleIndex = grulev3ParserRULE_ruleAnnotation
 }
 
-func (*SalienceContext) IsSalienceContext() {}
+func (*RuleAnnotationContext) IsRuleAnnotationContext() {}
 
-func NewSalienceContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *SalienceContext {
-	var p = new(SalienceContext)
+func NewRuleAnnotationContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleAnnotationContext {
+	var p = new(RuleAnnotationContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_salience
+	p.RuleIndex = grulev3ParserRULE_ruleAnnotation
 
 	return p
 }
 
-func (s *SalienceContext) GetParser() antlr.Parser { return s.parser }
+func (s *RuleAnnotationContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *SalienceContext) SALIENCE() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserSALIENCE, 0)
+func (s *RuleAnnotationContext) AT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserAT, 0)
 }
 
-func (s *SalienceContext) IntegerLiteral() IIntegerLiteralContext {
+func (s *RuleAnnotationContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *RuleAnnotationContext) LR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACKET, 0)
+}
+
+func (s *RuleAnnotationContext) AllStringLiteral() []IStringLiteralContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IStringLiteralContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IStringLiteralContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IStringLiteralContext); ok {
+			tst[i] = t.(IStringLiteralContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *RuleAnnotationContext) StringLiteral(i int) IStringLiteralContext {
 	var t antlr.RuleContext
+	j := 0
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IIntegerLiteralContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+		if _, ok := ctx.(IStringLiteralContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
 		}
 	}
 
@@ -792,54 +1324,112 @@ func (s *SalienceContext) IntegerLiteral() IIntegerLiteralContext {
 		return nil
 	}
 
-	return t.(IIntegerLiteralContext)
+	return t.(IStringLiteralContext)
 }
 
-func (s *SalienceContext) GetRuleContext() antlr.RuleContext {
+func (s *RuleAnnotationContext) RR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACKET, 0)
+}
+
+func (s *RuleAnnotationContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *SalienceContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *RuleAnnotationContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *SalienceContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *RuleAnnotationContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterSalience(s)
+		listenerT.EnterRuleAnnotation(s)
 	}
 }
 
-func (s *SalienceContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *RuleAnnotationContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitSalience(s)
+		listenerT.ExitRuleAnnotation(s)
 	}
 }
 
-func (s *SalienceContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *RuleAnnotationContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitSalience(s)
+		return t.VisitRuleAnnotation(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) Salience() (localctx ISalienceContext) {
-	localctx = NewSalienceContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 4, grulev3ParserRULE_salience)
+func (p *grulev3Parser) RuleAnnotation() (localctx IRuleAnnotationContext) {
+	localctx = NewRuleAnnotationContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 4, grulev3ParserRULE_ruleAnnotation)
+	var _la int
+
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(87)
-		p.Match(grulev3ParserSALIENCE)
+		p.SetState(152)
+		p.Match(grulev3ParserAT)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
 	{
-		p.SetState(88)
-		p.IntegerLiteral()
+		p.SetState(153)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(154)
+		p.Match(grulev3ParserLR_BRACKET)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(155)
+		p.StringLiteral()
+	}
+	p.SetState(160)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	for _la == grulev3ParserT__0 {
+		{
+			p.SetState(156)
+			p.Match(grulev3ParserT__0)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(157)
+			p.StringLiteral()
+		}
+
+		p.SetState(162)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+	}
+	{
+		p.SetState(163)
+		p.Match(grulev3ParserRR_BRACKET)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
 	}
 
 errorExit:
@@ -855,8 +1445,8 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IRuleNameContext is an interface to support dynamic dispatch.
-type IRuleNameContext interface {
+// ITestEntryContext is an interface to support dynamic dispatch.
+type ITestEntryContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
@@ -864,89 +1454,189 @@ type IRuleNameContext interface {
 
 	// Getter signatures
 	SIMPLENAME() antlr.TerminalNode
+	StringLiteral() IStringLiteralContext
+	LR_BRACE() antlr.TerminalNode
+	ExpectScope() IExpectScopeContext
+	RR_BRACE() antlr.TerminalNode
+	GivenScope() IGivenScopeContext
 
-	// IsRuleNameContext differentiates from other interfaces.
-	IsRuleNameContext()
+	// IsTestEntryContext differentiates from other interfaces.
+	IsTestEntryContext()
 }
 
-type RuleNameContext struct {
+type TestEntryContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyRuleNameContext() *RuleNameContext {
-	var p = new(RuleNameContext)
+func NewEmptyTestEntryContext() *TestEntryContext {
+	var p = new(TestEntryContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_ruleName
+	p.RuleIndex = grulev3ParserRULE_testEntry
 	return p
 }
 
-func InitEmptyRuleNameContext(p *RuleNameContext) {
+func InitEmptyTestEntryContext(p *TestEntryContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_ruleName
+	p.RuleIndex = grulev3ParserRULE_testEntry
 }
 
-func (*RuleNameContext) IsRuleNameContext() {}
+func (*TestEntryContext) IsTestEntryContext() {}
 
-func NewRuleNameContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleNameContext {
-	var p = new(RuleNameContext)
+func NewTestEntryContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *TestEntryContext {
+	var p = new(TestEntryContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_ruleName
+	p.RuleIndex = grulev3ParserRULE_testEntry
 
 	return p
 }
 
-func (s *RuleNameContext) GetParser() antlr.Parser { return s.parser }
+func (s *TestEntryContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *RuleNameContext) SIMPLENAME() antlr.TerminalNode {
+func (s *TestEntryContext) SIMPLENAME() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *RuleNameContext) GetRuleContext() antlr.RuleContext {
+func (s *TestEntryContext) StringLiteral() IStringLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IStringLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IStringLiteralContext)
+}
+
+func (s *TestEntryContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *TestEntryContext) ExpectScope() IExpectScopeContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpectScopeContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpectScopeContext)
+}
+
+func (s *TestEntryContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *TestEntryContext) GivenScope() IGivenScopeContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IGivenScopeContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IGivenScopeContext)
+}
+
+func (s *TestEntryContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *RuleNameContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *TestEntryContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *RuleNameContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *TestEntryContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterRuleName(s)
+		listenerT.EnterTestEntry(s)
 	}
 }
 
-func (s *RuleNameContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *TestEntryContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitRuleName(s)
+		listenerT.ExitTestEntry(s)
 	}
 }
 
-func (s *RuleNameContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *TestEntryContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitRuleName(s)
+		return t.VisitTestEntry(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) RuleName() (localctx IRuleNameContext) {
-	localctx = NewRuleNameContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 6, grulev3ParserRULE_ruleName)
+func (p *grulev3Parser) TestEntry() (localctx ITestEntryContext) {
+	localctx = NewTestEntryContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 6, grulev3ParserRULE_testEntry)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(90)
+		p.SetState(165)
 		p.Match(grulev3ParserSIMPLENAME)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
+	{
+		p.SetState(166)
+		p.StringLiteral()
+	}
+	{
+		p.SetState(167)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(169)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 11, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(168)
+			p.GivenScope()
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
+	{
+		p.SetState(171)
+		p.ExpectScope()
+	}
+	{
+		p.SetState(172)
+		p.Match(grulev3ParserRR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
 
 errorExit:
 	if p.HasError() {
@@ -961,107 +1651,156 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IRuleDescriptionContext is an interface to support dynamic dispatch.
-type IRuleDescriptionContext interface {
+// IHaltEntryContext is an interface to support dynamic dispatch.
+type IHaltEntryContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	DQUOTA_STRING() antlr.TerminalNode
-	SQUOTA_STRING() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	WHEN() antlr.TerminalNode
+	Expression() IExpressionContext
+	SEMICOLON() antlr.TerminalNode
 
-	// IsRuleDescriptionContext differentiates from other interfaces.
-	IsRuleDescriptionContext()
+	// IsHaltEntryContext differentiates from other interfaces.
+	IsHaltEntryContext()
 }
 
-type RuleDescriptionContext struct {
+type HaltEntryContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyRuleDescriptionContext() *RuleDescriptionContext {
-	var p = new(RuleDescriptionContext)
+func NewEmptyHaltEntryContext() *HaltEntryContext {
+	var p = new(HaltEntryContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_ruleDescription
+	p.RuleIndex = grulev3ParserRULE_haltEntry
 	return p
 }
 
-func InitEmptyRuleDescriptionContext(p *RuleDescriptionContext) {
+func InitEmptyHaltEntryContext(p *HaltEntryContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_ruleDescription
+	p.RuleIndex = grulev3ParserRULE_haltEntry
 }
 
-func (*RuleDescriptionContext) IsRuleDescriptionContext() {}
+func (*HaltEntryContext) IsHaltEntryContext() {}
 
-func NewRuleDescriptionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleDescriptionContext {
-	var p = new(RuleDescriptionContext)
+func NewHaltEntryContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *HaltEntryContext {
+	var p = new(HaltEntryContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_ruleDescription
+	p.RuleIndex = grulev3ParserRULE_haltEntry
 
 	return p
 }
 
-func (s *RuleDescriptionContext) GetParser() antlr.Parser { return s.parser }
+func (s *HaltEntryContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *RuleDescriptionContext) DQUOTA_STRING() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserDQUOTA_STRING, 0)
+func (s *HaltEntryContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *RuleDescriptionContext) SQUOTA_STRING() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserSQUOTA_STRING, 0)
+func (s *HaltEntryContext) WHEN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserWHEN, 0)
 }
 
-func (s *RuleDescriptionContext) GetRuleContext() antlr.RuleContext {
-	return s
-}
+func (s *HaltEntryContext) Expression() IExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
 
-func (s *RuleDescriptionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
-	return antlr.TreesStringTree(s, ruleNames, recog)
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
 }
 
-func (s *RuleDescriptionContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *HaltEntryContext) SEMICOLON() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSEMICOLON, 0)
+}
+
+func (s *HaltEntryContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *HaltEntryContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *HaltEntryContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterRuleDescription(s)
+		listenerT.EnterHaltEntry(s)
 	}
 }
 
-func (s *RuleDescriptionContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *HaltEntryContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitRuleDescription(s)
+		listenerT.ExitHaltEntry(s)
 	}
 }
 
-func (s *RuleDescriptionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *HaltEntryContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitRuleDescription(s)
+		return t.VisitHaltEntry(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) RuleDescription() (localctx IRuleDescriptionContext) {
-	localctx = NewRuleDescriptionContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 8, grulev3ParserRULE_ruleDescription)
+func (p *grulev3Parser) HaltEntry() (localctx IHaltEntryContext) {
+	localctx = NewHaltEntryContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 8, grulev3ParserRULE_haltEntry)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(92)
-		_la = p.GetTokenStream().LA(1)
+		p.SetState(174)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(175)
+		p.Match(grulev3ParserWHEN)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(176)
+		p.expression(0)
+	}
+	p.SetState(178)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
 
-		if !(_la == grulev3ParserDQUOTA_STRING || _la == grulev3ParserSQUOTA_STRING) {
-			p.GetErrorHandler().RecoverInline(p)
-		} else {
-			p.GetErrorHandler().ReportMatch(p)
-			p.Consume()
+	if _la == grulev3ParserSEMICOLON {
+		{
+			p.SetState(177)
+			p.Match(grulev3ParserSEMICOLON)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
+
 	}
 
 errorExit:
@@ -1077,61 +1816,71 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IWhenScopeContext is an interface to support dynamic dispatch.
-type IWhenScopeContext interface {
+// IGivenScopeContext is an interface to support dynamic dispatch.
+type IGivenScopeContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	WHEN() antlr.TerminalNode
-	Expression() IExpressionContext
+	SIMPLENAME() antlr.TerminalNode
+	LR_BRACE() antlr.TerminalNode
+	RR_BRACE() antlr.TerminalNode
+	ThenExpressionList() IThenExpressionListContext
 
-	// IsWhenScopeContext differentiates from other interfaces.
-	IsWhenScopeContext()
+	// IsGivenScopeContext differentiates from other interfaces.
+	IsGivenScopeContext()
 }
 
-type WhenScopeContext struct {
+type GivenScopeContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyWhenScopeContext() *WhenScopeContext {
-	var p = new(WhenScopeContext)
+func NewEmptyGivenScopeContext() *GivenScopeContext {
+	var p = new(GivenScopeContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_whenScope
+	p.RuleIndex = grulev3ParserRULE_givenScope
 	return p
 }
 
-func InitEmptyWhenScopeContext(p *WhenScopeContext) {
+func InitEmptyGivenScopeContext(p *GivenScopeContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_whenScope
+	p.RuleIndex = grulev3ParserRULE_givenScope
 }
 
-func (*WhenScopeContext) IsWhenScopeContext() {}
+func (*GivenScopeContext) IsGivenScopeContext() {}
 
-func NewWhenScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *WhenScopeContext {
-	var p = new(WhenScopeContext)
+func NewGivenScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *GivenScopeContext {
+	var p = new(GivenScopeContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_whenScope
+	p.RuleIndex = grulev3ParserRULE_givenScope
 
 	return p
 }
 
-func (s *WhenScopeContext) GetParser() antlr.Parser { return s.parser }
+func (s *GivenScopeContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *WhenScopeContext) WHEN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserWHEN, 0)
+func (s *GivenScopeContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *WhenScopeContext) Expression() IExpressionContext {
+func (s *GivenScopeContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *GivenScopeContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *GivenScopeContext) ThenExpressionList() IThenExpressionListContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionContext); ok {
+		if _, ok := ctx.(IThenExpressionListContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -1141,54 +1890,82 @@ func (s *WhenScopeContext) Expression() IExpressionContext {
 		return nil
 	}
 
-	return t.(IExpressionContext)
+	return t.(IThenExpressionListContext)
 }
 
-func (s *WhenScopeContext) GetRuleContext() antlr.RuleContext {
+func (s *GivenScopeContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *WhenScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *GivenScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *WhenScopeContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *GivenScopeContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterWhenScope(s)
+		listenerT.EnterGivenScope(s)
 	}
 }
 
-func (s *WhenScopeContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *GivenScopeContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitWhenScope(s)
+		listenerT.ExitGivenScope(s)
 	}
 }
 
-func (s *WhenScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *GivenScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitWhenScope(s)
+		return t.VisitGivenScope(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) WhenScope() (localctx IWhenScopeContext) {
-	localctx = NewWhenScopeContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 10, grulev3ParserRULE_whenScope)
+func (p *grulev3Parser) GivenScope() (localctx IGivenScopeContext) {
+	localctx = NewGivenScopeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 10, grulev3ParserRULE_givenScope)
+	var _la int
+
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(94)
-		p.Match(grulev3ParserWHEN)
+		p.SetState(180)
+		p.Match(grulev3ParserSIMPLENAME)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
 	{
-		p.SetState(95)
-		p.expression(0)
+		p.SetState(181)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(183)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&1128644287948399112) != 0 {
+		{
+			p.SetState(182)
+			p.ThenExpressionList()
+		}
+
+	}
+	{
+		p.SetState(185)
+		p.Match(grulev3ParserRR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
 	}
 
 errorExit:
@@ -1204,61 +1981,62 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IThenScopeContext is an interface to support dynamic dispatch.
-type IThenScopeContext interface {
+// IExpectScopeContext is an interface to support dynamic dispatch.
+type IExpectScopeContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	THEN() antlr.TerminalNode
-	ThenExpressionList() IThenExpressionListContext
+	SIMPLENAME() antlr.TerminalNode
+	Expression() IExpressionContext
+	SEMICOLON() antlr.TerminalNode
 
-	// IsThenScopeContext differentiates from other interfaces.
-	IsThenScopeContext()
+	// IsExpectScopeContext differentiates from other interfaces.
+	IsExpectScopeContext()
 }
 
-type ThenScopeContext struct {
+type ExpectScopeContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyThenScopeContext() *ThenScopeContext {
-	var p = new(ThenScopeContext)
+func NewEmptyExpectScopeContext() *ExpectScopeContext {
+	var p = new(ExpectScopeContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenScope
+	p.RuleIndex = grulev3ParserRULE_expectScope
 	return p
 }
 
-func InitEmptyThenScopeContext(p *ThenScopeContext) {
+func InitEmptyExpectScopeContext(p *ExpectScopeContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenScope
+	p.RuleIndex = grulev3ParserRULE_expectScope
 }
 
-func (*ThenScopeContext) IsThenScopeContext() {}
+func (*ExpectScopeContext) IsExpectScopeContext() {}
 
-func NewThenScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenScopeContext {
-	var p = new(ThenScopeContext)
+func NewExpectScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ExpectScopeContext {
+	var p = new(ExpectScopeContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_thenScope
+	p.RuleIndex = grulev3ParserRULE_expectScope
 
 	return p
 }
 
-func (s *ThenScopeContext) GetParser() antlr.Parser { return s.parser }
+func (s *ExpectScopeContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ThenScopeContext) THEN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserTHEN, 0)
+func (s *ExpectScopeContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *ThenScopeContext) ThenExpressionList() IThenExpressionListContext {
+func (s *ExpectScopeContext) Expression() IExpressionContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IThenExpressionListContext); ok {
+		if _, ok := ctx.(IExpressionContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -1268,54 +2046,78 @@ func (s *ThenScopeContext) ThenExpressionList() IThenExpressionListContext {
 		return nil
 	}
 
-	return t.(IThenExpressionListContext)
+	return t.(IExpressionContext)
 }
 
-func (s *ThenScopeContext) GetRuleContext() antlr.RuleContext {
+func (s *ExpectScopeContext) SEMICOLON() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSEMICOLON, 0)
+}
+
+func (s *ExpectScopeContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ThenScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *ExpectScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ThenScopeContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *ExpectScopeContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterThenScope(s)
+		listenerT.EnterExpectScope(s)
 	}
 }
 
-func (s *ThenScopeContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *ExpectScopeContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitThenScope(s)
+		listenerT.ExitExpectScope(s)
 	}
 }
 
-func (s *ThenScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *ExpectScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitThenScope(s)
+		return t.VisitExpectScope(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) ThenScope() (localctx IThenScopeContext) {
-	localctx = NewThenScopeContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 12, grulev3ParserRULE_thenScope)
+func (p *grulev3Parser) ExpectScope() (localctx IExpectScopeContext) {
+	localctx = NewExpectScopeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 12, grulev3ParserRULE_expectScope)
+	var _la int
+
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(97)
-		p.Match(grulev3ParserTHEN)
+		p.SetState(187)
+		p.Match(grulev3ParserSIMPLENAME)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
 	{
-		p.SetState(98)
-		p.ThenExpressionList()
+		p.SetState(188)
+		p.expression(0)
+	}
+	p.SetState(190)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserSEMICOLON {
+		{
+			p.SetState(189)
+			p.Match(grulev3ParserSEMICOLON)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
 	}
 
 errorExit:
@@ -1331,167 +2133,118 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IThenExpressionListContext is an interface to support dynamic dispatch.
-type IThenExpressionListContext interface {
+// ISalienceContext is an interface to support dynamic dispatch.
+type ISalienceContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	AllThenExpression() []IThenExpressionContext
-	ThenExpression(i int) IThenExpressionContext
-	AllSEMICOLON() []antlr.TerminalNode
-	SEMICOLON(i int) antlr.TerminalNode
+	SALIENCE() antlr.TerminalNode
+	IntegerLiteral() IIntegerLiteralContext
 
-	// IsThenExpressionListContext differentiates from other interfaces.
-	IsThenExpressionListContext()
+	// IsSalienceContext differentiates from other interfaces.
+	IsSalienceContext()
 }
 
-type ThenExpressionListContext struct {
+type SalienceContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyThenExpressionListContext() *ThenExpressionListContext {
-	var p = new(ThenExpressionListContext)
+func NewEmptySalienceContext() *SalienceContext {
+	var p = new(SalienceContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+	p.RuleIndex = grulev3ParserRULE_salience
 	return p
 }
 
-func InitEmptyThenExpressionListContext(p *ThenExpressionListContext) {
+func InitEmptySalienceContext(p *SalienceContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+	p.RuleIndex = grulev3ParserRULE_salience
 }
 
-func (*ThenExpressionListContext) IsThenExpressionListContext() {}
+func (*SalienceContext) IsSalienceContext() {}
 
-func NewThenExpressionListContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenExpressionListContext {
-	var p = new(ThenExpressionListContext)
+func NewSalienceContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *SalienceContext {
+	var p = new(SalienceContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+	p.RuleIndex = grulev3ParserRULE_salience
 
 	return p
 }
 
-func (s *ThenExpressionListContext) GetParser() antlr.Parser { return s.parser }
+func (s *SalienceContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ThenExpressionListContext) AllThenExpression() []IThenExpressionContext {
-	children := s.GetChildren()
-	len := 0
-	for _, ctx := range children {
-		if _, ok := ctx.(IThenExpressionContext); ok {
-			len++
+func (s *SalienceContext) SALIENCE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSALIENCE, 0)
+}
+
+func (s *SalienceContext) IntegerLiteral() IIntegerLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIntegerLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
 		}
 	}
 
-	tst := make([]IThenExpressionContext, len)
-	i := 0
-	for _, ctx := range children {
-		if t, ok := ctx.(IThenExpressionContext); ok {
-			tst[i] = t.(IThenExpressionContext)
-			i++
-		}
+	if t == nil {
+		return nil
 	}
 
-	return tst
+	return t.(IIntegerLiteralContext)
 }
 
-func (s *ThenExpressionListContext) ThenExpression(i int) IThenExpressionContext {
-	var t antlr.RuleContext
-	j := 0
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IThenExpressionContext); ok {
-			if j == i {
-				t = ctx.(antlr.RuleContext)
-				break
-			}
-			j++
-		}
-	}
-
-	if t == nil {
-		return nil
-	}
-
-	return t.(IThenExpressionContext)
-}
-
-func (s *ThenExpressionListContext) AllSEMICOLON() []antlr.TerminalNode {
-	return s.GetTokens(grulev3ParserSEMICOLON)
-}
-
-func (s *ThenExpressionListContext) SEMICOLON(i int) antlr.TerminalNode {
-	return s.GetToken(grulev3ParserSEMICOLON, i)
-}
-
-func (s *ThenExpressionListContext) GetRuleContext() antlr.RuleContext {
+func (s *SalienceContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ThenExpressionListContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *SalienceContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ThenExpressionListContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *SalienceContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterThenExpressionList(s)
+		listenerT.EnterSalience(s)
 	}
 }
 
-func (s *ThenExpressionListContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *SalienceContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitThenExpressionList(s)
+		listenerT.ExitSalience(s)
 	}
 }
 
-func (s *ThenExpressionListContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *SalienceContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitThenExpressionList(s)
+		return t.VisitSalience(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) ThenExpressionList() (localctx IThenExpressionListContext) {
-	localctx = NewThenExpressionListContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 14, grulev3ParserRULE_thenExpressionList)
-	var _la int
-
+func (p *grulev3Parser) Salience() (localctx ISalienceContext) {
+	localctx = NewSalienceContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 14, grulev3ParserRULE_salience)
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(103)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
-	_la = p.GetTokenStream().LA(1)
-
-	for ok := true; ok; ok = ((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&259209881976840) != 0) {
-		{
-			p.SetState(100)
-			p.ThenExpression()
-		}
-		{
-			p.SetState(101)
-			p.Match(grulev3ParserSEMICOLON)
-			if p.HasError() {
-				// Recognition error - abort rule
-				goto errorExit
-			}
-		}
-
-		p.SetState(105)
-		p.GetErrorHandler().Sync(p)
+	{
+		p.SetState(192)
+		p.Match(grulev3ParserSALIENCE)
 		if p.HasError() {
+			// Recognition error - abort rule
 			goto errorExit
 		}
-		_la = p.GetTokenStream().LA(1)
+	}
+	{
+		p.SetState(193)
+		p.IntegerLiteral()
 	}
 
 errorExit:
@@ -1507,73 +2260,72 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IThenExpressionContext is an interface to support dynamic dispatch.
-type IThenExpressionContext interface {
+// IMaxFiresContext is an interface to support dynamic dispatch.
+type IMaxFiresContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	Assignment() IAssignmentContext
-	ExpressionAtom() IExpressionAtomContext
+	AllSIMPLENAME() []antlr.TerminalNode
+	SIMPLENAME(i int) antlr.TerminalNode
+	MINUS() antlr.TerminalNode
+	IntegerLiteral() IIntegerLiteralContext
+	PER_EXECUTION() antlr.TerminalNode
 
-	// IsThenExpressionContext differentiates from other interfaces.
-	IsThenExpressionContext()
+	// IsMaxFiresContext differentiates from other interfaces.
+	IsMaxFiresContext()
 }
 
-type ThenExpressionContext struct {
+type MaxFiresContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyThenExpressionContext() *ThenExpressionContext {
-	var p = new(ThenExpressionContext)
+func NewEmptyMaxFiresContext() *MaxFiresContext {
+	var p = new(MaxFiresContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenExpression
+	p.RuleIndex = grulev3ParserRULE_maxFires
 	return p
 }
 
-func InitEmptyThenExpressionContext(p *ThenExpressionContext) {
+func InitEmptyMaxFiresContext(p *MaxFiresContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_thenExpression
+	p.RuleIndex = grulev3ParserRULE_maxFires
 }
 
-func (*ThenExpressionContext) IsThenExpressionContext() {}
+func (*MaxFiresContext) IsMaxFiresContext() {}
 
-func NewThenExpressionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenExpressionContext {
-	var p = new(ThenExpressionContext)
+func NewMaxFiresContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MaxFiresContext {
+	var p = new(MaxFiresContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_thenExpression
+	p.RuleIndex = grulev3ParserRULE_maxFires
 
 	return p
 }
 
-func (s *ThenExpressionContext) GetParser() antlr.Parser { return s.parser }
+func (s *MaxFiresContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ThenExpressionContext) Assignment() IAssignmentContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IAssignmentContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
+func (s *MaxFiresContext) AllSIMPLENAME() []antlr.TerminalNode {
+	return s.GetTokens(grulev3ParserSIMPLENAME)
+}
 
-	if t == nil {
-		return nil
-	}
+func (s *MaxFiresContext) SIMPLENAME(i int) antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, i)
+}
 
-	return t.(IAssignmentContext)
+func (s *MaxFiresContext) MINUS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMINUS, 0)
 }
 
-func (s *ThenExpressionContext) ExpressionAtom() IExpressionAtomContext {
+func (s *MaxFiresContext) IntegerLiteral() IIntegerLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionAtomContext); ok {
+		if _, ok := ctx.(IIntegerLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -1583,65 +2335,94 @@ func (s *ThenExpressionContext) ExpressionAtom() IExpressionAtomContext {
 		return nil
 	}
 
-	return t.(IExpressionAtomContext)
+	return t.(IIntegerLiteralContext)
 }
 
-func (s *ThenExpressionContext) GetRuleContext() antlr.RuleContext {
+func (s *MaxFiresContext) PER_EXECUTION() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserPER_EXECUTION, 0)
+}
+
+func (s *MaxFiresContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ThenExpressionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *MaxFiresContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ThenExpressionContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *MaxFiresContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterThenExpression(s)
+		listenerT.EnterMaxFires(s)
 	}
 }
 
-func (s *ThenExpressionContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *MaxFiresContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitThenExpression(s)
+		listenerT.ExitMaxFires(s)
 	}
 }
 
-func (s *ThenExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *MaxFiresContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitThenExpression(s)
+		return t.VisitMaxFires(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) ThenExpression() (localctx IThenExpressionContext) {
-	localctx = NewThenExpressionContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 16, grulev3ParserRULE_thenExpression)
-	p.SetState(109)
+func (p *grulev3Parser) MaxFires() (localctx IMaxFiresContext) {
+	localctx = NewMaxFiresContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 16, grulev3ParserRULE_maxFires)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(195)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(196)
+		p.Match(grulev3ParserMINUS)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(197)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(198)
+		p.IntegerLiteral()
+	}
+	p.SetState(200)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
+	_la = p.GetTokenStream().LA(1)
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 4, p.GetParserRuleContext()) {
-	case 1:
-		p.EnterOuterAlt(localctx, 1)
-		{
-			p.SetState(107)
-			p.Assignment()
-		}
-
-	case 2:
-		p.EnterOuterAlt(localctx, 2)
+	if _la == grulev3ParserPER_EXECUTION {
 		{
-			p.SetState(108)
-			p.expressionAtom(0)
+			p.SetState(199)
+			p.Match(grulev3ParserPER_EXECUTION)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
 
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
 	}
 
 errorExit:
@@ -1657,165 +2438,111 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IAssignmentContext is an interface to support dynamic dispatch.
-type IAssignmentContext interface {
+// ICooldownContext is an interface to support dynamic dispatch.
+type ICooldownContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	Variable() IVariableContext
-	Expression() IExpressionContext
-	ASSIGN() antlr.TerminalNode
-	PLUS_ASIGN() antlr.TerminalNode
-	MINUS_ASIGN() antlr.TerminalNode
-	DIV_ASIGN() antlr.TerminalNode
-	MUL_ASIGN() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	DURATION_LIT() antlr.TerminalNode
 
-	// IsAssignmentContext differentiates from other interfaces.
-	IsAssignmentContext()
+	// IsCooldownContext differentiates from other interfaces.
+	IsCooldownContext()
 }
 
-type AssignmentContext struct {
+type CooldownContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyAssignmentContext() *AssignmentContext {
-	var p = new(AssignmentContext)
+func NewEmptyCooldownContext() *CooldownContext {
+	var p = new(CooldownContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_assignment
+	p.RuleIndex = grulev3ParserRULE_cooldown
 	return p
 }
 
-func InitEmptyAssignmentContext(p *AssignmentContext) {
+func InitEmptyCooldownContext(p *CooldownContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_assignment
+	p.RuleIndex = grulev3ParserRULE_cooldown
 }
 
-func (*AssignmentContext) IsAssignmentContext() {}
+func (*CooldownContext) IsCooldownContext() {}
 
-func NewAssignmentContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AssignmentContext {
-	var p = new(AssignmentContext)
+func NewCooldownContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CooldownContext {
+	var p = new(CooldownContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_assignment
+	p.RuleIndex = grulev3ParserRULE_cooldown
 
 	return p
 }
 
-func (s *AssignmentContext) GetParser() antlr.Parser { return s.parser }
-
-func (s *AssignmentContext) Variable() IVariableContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IVariableContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
-
-	if t == nil {
-		return nil
-	}
-
-	return t.(IVariableContext)
-}
-
-func (s *AssignmentContext) Expression() IExpressionContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
-
-	if t == nil {
-		return nil
-	}
-
-	return t.(IExpressionContext)
-}
-
-func (s *AssignmentContext) ASSIGN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserASSIGN, 0)
-}
-
-func (s *AssignmentContext) PLUS_ASIGN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserPLUS_ASIGN, 0)
-}
-
-func (s *AssignmentContext) MINUS_ASIGN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserMINUS_ASIGN, 0)
-}
+func (s *CooldownContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *AssignmentContext) DIV_ASIGN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserDIV_ASIGN, 0)
+func (s *CooldownContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *AssignmentContext) MUL_ASIGN() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserMUL_ASIGN, 0)
+func (s *CooldownContext) DURATION_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserDURATION_LIT, 0)
 }
 
-func (s *AssignmentContext) GetRuleContext() antlr.RuleContext {
+func (s *CooldownContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *AssignmentContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *CooldownContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *AssignmentContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *CooldownContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterAssignment(s)
+		listenerT.EnterCooldown(s)
 	}
 }
 
-func (s *AssignmentContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *CooldownContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitAssignment(s)
+		listenerT.ExitCooldown(s)
 	}
 }
 
-func (s *AssignmentContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *CooldownContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitAssignment(s)
+		return t.VisitCooldown(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) Assignment() (localctx IAssignmentContext) {
-	localctx = NewAssignmentContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 18, grulev3ParserRULE_assignment)
-	var _la int
-
+func (p *grulev3Parser) Cooldown() (localctx ICooldownContext) {
+	localctx = NewCooldownContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 18, grulev3ParserRULE_cooldown)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(111)
-		p.variable(0)
+		p.SetState(202)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
 	}
 	{
-		p.SetState(112)
-		_la = p.GetTokenStream().LA(1)
-
-		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&2080374784) != 0) {
-			p.GetErrorHandler().RecoverInline(p)
-		} else {
-			p.GetErrorHandler().ReportMatch(p)
-			p.Consume()
+		p.SetState(203)
+		p.Match(grulev3ParserDURATION_LIT)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
 	}
-	{
-		p.SetState(113)
-		p.expression(0)
-	}
 
 errorExit:
 	if p.HasError() {
@@ -1830,441 +2557,225 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IExpressionContext is an interface to support dynamic dispatch.
-type IExpressionContext interface {
+// ICriticalityContext is an interface to support dynamic dispatch.
+type ICriticalityContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	LR_BRACKET() antlr.TerminalNode
-	AllExpression() []IExpressionContext
-	Expression(i int) IExpressionContext
-	RR_BRACKET() antlr.TerminalNode
-	NEGATION() antlr.TerminalNode
-	ExpressionAtom() IExpressionAtomContext
-	MulDivOperators() IMulDivOperatorsContext
-	AddMinusOperators() IAddMinusOperatorsContext
-	ComparisonOperator() IComparisonOperatorContext
-	AndLogicOperator() IAndLogicOperatorContext
-	OrLogicOperator() IOrLogicOperatorContext
+	AllSIMPLENAME() []antlr.TerminalNode
+	SIMPLENAME(i int) antlr.TerminalNode
 
-	// IsExpressionContext differentiates from other interfaces.
-	IsExpressionContext()
+	// IsCriticalityContext differentiates from other interfaces.
+	IsCriticalityContext()
 }
 
-type ExpressionContext struct {
+type CriticalityContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyExpressionContext() *ExpressionContext {
-	var p = new(ExpressionContext)
+func NewEmptyCriticalityContext() *CriticalityContext {
+	var p = new(CriticalityContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_expression
+	p.RuleIndex = grulev3ParserRULE_criticality
 	return p
 }
 
-func InitEmptyExpressionContext(p *ExpressionContext) {
+func InitEmptyCriticalityContext(p *CriticalityContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_expression
+	p.RuleIndex = grulev3ParserRULE_criticality
 }
 
-func (*ExpressionContext) IsExpressionContext() {}
+func (*CriticalityContext) IsCriticalityContext() {}
 
-func NewExpressionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ExpressionContext {
-	var p = new(ExpressionContext)
+func NewCriticalityContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CriticalityContext {
+	var p = new(CriticalityContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_expression
+	p.RuleIndex = grulev3ParserRULE_criticality
 
 	return p
 }
 
-func (s *ExpressionContext) GetParser() antlr.Parser { return s.parser }
+func (s *CriticalityContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ExpressionContext) LR_BRACKET() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserLR_BRACKET, 0)
+func (s *CriticalityContext) AllSIMPLENAME() []antlr.TerminalNode {
+	return s.GetTokens(grulev3ParserSIMPLENAME)
 }
 
-func (s *ExpressionContext) AllExpression() []IExpressionContext {
-	children := s.GetChildren()
-	len := 0
-	for _, ctx := range children {
-		if _, ok := ctx.(IExpressionContext); ok {
-			len++
-		}
-	}
+func (s *CriticalityContext) SIMPLENAME(i int) antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, i)
+}
 
-	tst := make([]IExpressionContext, len)
-	i := 0
-	for _, ctx := range children {
-		if t, ok := ctx.(IExpressionContext); ok {
-			tst[i] = t.(IExpressionContext)
-			i++
-		}
-	}
+func (s *CriticalityContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
 
-	return tst
+func (s *CriticalityContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ExpressionContext) Expression(i int) IExpressionContext {
-	var t antlr.RuleContext
-	j := 0
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionContext); ok {
-			if j == i {
-				t = ctx.(antlr.RuleContext)
-				break
-			}
-			j++
-		}
+func (s *CriticalityContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterCriticality(s)
 	}
+}
 
-	if t == nil {
-		return nil
+func (s *CriticalityContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitCriticality(s)
 	}
-
-	return t.(IExpressionContext)
 }
 
-func (s *ExpressionContext) RR_BRACKET() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserRR_BRACKET, 0)
-}
+func (s *CriticalityContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitCriticality(s)
 
-func (s *ExpressionContext) NEGATION() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserNEGATION, 0)
+	default:
+		return t.VisitChildren(s)
+	}
 }
 
-func (s *ExpressionContext) ExpressionAtom() IExpressionAtomContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionAtomContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+func (p *grulev3Parser) Criticality() (localctx ICriticalityContext) {
+	localctx = NewCriticalityContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 20, grulev3ParserRULE_criticality)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(205)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
 	}
-
-	if t == nil {
-		return nil
-	}
-
-	return t.(IExpressionAtomContext)
-}
-
-func (s *ExpressionContext) MulDivOperators() IMulDivOperatorsContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IMulDivOperatorsContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+	{
+		p.SetState(206)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
 	}
 
-	if t == nil {
-		return nil
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
 	}
-
-	return t.(IMulDivOperatorsContext)
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-func (s *ExpressionContext) AddMinusOperators() IAddMinusOperatorsContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IAddMinusOperatorsContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
+// IRuleNameContext is an interface to support dynamic dispatch.
+type IRuleNameContext interface {
+	antlr.ParserRuleContext
 
-	if t == nil {
-		return nil
-	}
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
 
-	return t.(IAddMinusOperatorsContext)
+	// Getter signatures
+	SIMPLENAME() antlr.TerminalNode
+	IN() antlr.TerminalNode
+
+	// IsRuleNameContext differentiates from other interfaces.
+	IsRuleNameContext()
 }
 
-func (s *ExpressionContext) ComparisonOperator() IComparisonOperatorContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IComparisonOperatorContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
+type RuleNameContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
 
-	if t == nil {
-		return nil
-	}
+func NewEmptyRuleNameContext() *RuleNameContext {
+	var p = new(RuleNameContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_ruleName
+	return p
+}
 
-	return t.(IComparisonOperatorContext)
+func InitEmptyRuleNameContext(p *RuleNameContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_ruleName
 }
 
-func (s *ExpressionContext) AndLogicOperator() IAndLogicOperatorContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IAndLogicOperatorContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
+func (*RuleNameContext) IsRuleNameContext() {}
 
-	if t == nil {
-		return nil
-	}
+func NewRuleNameContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleNameContext {
+	var p = new(RuleNameContext)
 
-	return t.(IAndLogicOperatorContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_ruleName
+
+	return p
 }
 
-func (s *ExpressionContext) OrLogicOperator() IOrLogicOperatorContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IOrLogicOperatorContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
+func (s *RuleNameContext) GetParser() antlr.Parser { return s.parser }
 
-	if t == nil {
-		return nil
-	}
+func (s *RuleNameContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
 
-	return t.(IOrLogicOperatorContext)
+func (s *RuleNameContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
 }
 
-func (s *ExpressionContext) GetRuleContext() antlr.RuleContext {
+func (s *RuleNameContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ExpressionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *RuleNameContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ExpressionContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *RuleNameContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterExpression(s)
+		listenerT.EnterRuleName(s)
 	}
 }
 
-func (s *ExpressionContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *RuleNameContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitExpression(s)
+		listenerT.ExitRuleName(s)
 	}
 }
 
-func (s *ExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *RuleNameContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitExpression(s)
+		return t.VisitRuleName(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) Expression() (localctx IExpressionContext) {
-	return p.expression(0)
-}
-
-func (p *grulev3Parser) expression(_p int) (localctx IExpressionContext) {
-	var _parentctx antlr.ParserRuleContext = p.GetParserRuleContext()
-
-	_parentState := p.GetState()
-	localctx = NewExpressionContext(p, p.GetParserRuleContext(), _parentState)
-	var _prevctx IExpressionContext = localctx
-	var _ antlr.ParserRuleContext = _prevctx // TODO: To prevent unused variable warning.
-	_startState := 20
-	p.EnterRecursionRule(localctx, 20, grulev3ParserRULE_expression, _p)
+func (p *grulev3Parser) RuleName() (localctx IRuleNameContext) {
+	localctx = NewRuleNameContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 22, grulev3ParserRULE_ruleName)
 	var _la int
 
-	var _alt int
-
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(124)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
+	{
+		p.SetState(208)
+		_la = p.GetTokenStream().LA(1)
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 6, p.GetParserRuleContext()) {
-	case 1:
-		p.SetState(117)
-		p.GetErrorHandler().Sync(p)
-		if p.HasError() {
-			goto errorExit
-		}
-		_la = p.GetTokenStream().LA(1)
-
-		if _la == grulev3ParserNEGATION {
-			{
-				p.SetState(116)
-				p.Match(grulev3ParserNEGATION)
-				if p.HasError() {
-					// Recognition error - abort rule
-					goto errorExit
-				}
-			}
-
-		}
-		{
-			p.SetState(119)
-			p.Match(grulev3ParserLR_BRACKET)
-			if p.HasError() {
-				// Recognition error - abort rule
-				goto errorExit
-			}
-		}
-		{
-			p.SetState(120)
-			p.expression(0)
-		}
-		{
-			p.SetState(121)
-			p.Match(grulev3ParserRR_BRACKET)
-			if p.HasError() {
-				// Recognition error - abort rule
-				goto errorExit
-			}
-		}
-
-	case 2:
-		{
-			p.SetState(123)
-			p.expressionAtom(0)
-		}
-
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
-	}
-	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
-	p.SetState(148)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
-	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 8, p.GetParserRuleContext())
-	if p.HasError() {
-		goto errorExit
-	}
-	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
-		if _alt == 1 {
-			if p.GetParseListeners() != nil {
-				p.TriggerExitRuleEvent()
-			}
-			_prevctx = localctx
-			p.SetState(146)
-			p.GetErrorHandler().Sync(p)
-			if p.HasError() {
-				goto errorExit
-			}
-
-			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 7, p.GetParserRuleContext()) {
-			case 1:
-				localctx = NewExpressionContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
-				p.SetState(126)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 7)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 7)", ""))
-					goto errorExit
-				}
-				{
-					p.SetState(127)
-					p.MulDivOperators()
-				}
-				{
-					p.SetState(128)
-					p.expression(8)
-				}
-
-			case 2:
-				localctx = NewExpressionContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
-				p.SetState(130)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 6)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 6)", ""))
-					goto errorExit
-				}
-				{
-					p.SetState(131)
-					p.AddMinusOperators()
-				}
-				{
-					p.SetState(132)
-					p.expression(7)
-				}
-
-			case 3:
-				localctx = NewExpressionContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
-				p.SetState(134)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 5)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 5)", ""))
-					goto errorExit
-				}
-				{
-					p.SetState(135)
-					p.ComparisonOperator()
-				}
-				{
-					p.SetState(136)
-					p.expression(6)
-				}
-
-			case 4:
-				localctx = NewExpressionContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
-				p.SetState(138)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
-					goto errorExit
-				}
-				{
-					p.SetState(139)
-					p.AndLogicOperator()
-				}
-				{
-					p.SetState(140)
-					p.expression(5)
-				}
-
-			case 5:
-				localctx = NewExpressionContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
-				p.SetState(142)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
-					goto errorExit
-				}
-				{
-					p.SetState(143)
-					p.OrLogicOperator()
-				}
-				{
-					p.SetState(144)
-					p.expression(4)
-				}
-
-			case antlr.ATNInvalidAltNumber:
-				goto errorExit
-			}
-
-		}
-		p.SetState(150)
-		p.GetErrorHandler().Sync(p)
-		if p.HasError() {
-			goto errorExit
-		}
-		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 8, p.GetParserRuleContext())
-		if p.HasError() {
-			goto errorExit
+		if !(_la == grulev3ParserIN || _la == grulev3ParserSIMPLENAME) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
 		}
 	}
 
@@ -2276,112 +2787,107 @@ errorExit:
 		p.GetErrorHandler().Recover(p, v)
 		p.SetError(nil)
 	}
-	p.UnrollRecursionContexts(_parentctx)
+	p.ExitRule()
 	return localctx
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IMulDivOperatorsContext is an interface to support dynamic dispatch.
-type IMulDivOperatorsContext interface {
+// IRuleDescriptionContext is an interface to support dynamic dispatch.
+type IRuleDescriptionContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	MUL() antlr.TerminalNode
-	DIV() antlr.TerminalNode
-	MOD() antlr.TerminalNode
+	DQUOTA_STRING() antlr.TerminalNode
+	SQUOTA_STRING() antlr.TerminalNode
 
-	// IsMulDivOperatorsContext differentiates from other interfaces.
-	IsMulDivOperatorsContext()
+	// IsRuleDescriptionContext differentiates from other interfaces.
+	IsRuleDescriptionContext()
 }
 
-type MulDivOperatorsContext struct {
+type RuleDescriptionContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyMulDivOperatorsContext() *MulDivOperatorsContext {
-	var p = new(MulDivOperatorsContext)
+func NewEmptyRuleDescriptionContext() *RuleDescriptionContext {
+	var p = new(RuleDescriptionContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+	p.RuleIndex = grulev3ParserRULE_ruleDescription
 	return p
 }
 
-func InitEmptyMulDivOperatorsContext(p *MulDivOperatorsContext) {
+func InitEmptyRuleDescriptionContext(p *RuleDescriptionContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+	p.RuleIndex = grulev3ParserRULE_ruleDescription
 }
 
-func (*MulDivOperatorsContext) IsMulDivOperatorsContext() {}
+func (*RuleDescriptionContext) IsRuleDescriptionContext() {}
 
-func NewMulDivOperatorsContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MulDivOperatorsContext {
-	var p = new(MulDivOperatorsContext)
+func NewRuleDescriptionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleDescriptionContext {
+	var p = new(RuleDescriptionContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+	p.RuleIndex = grulev3ParserRULE_ruleDescription
 
 	return p
 }
 
-func (s *MulDivOperatorsContext) GetParser() antlr.Parser { return s.parser }
-
-func (s *MulDivOperatorsContext) MUL() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserMUL, 0)
-}
+func (s *RuleDescriptionContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *MulDivOperatorsContext) DIV() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserDIV, 0)
+func (s *RuleDescriptionContext) DQUOTA_STRING() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserDQUOTA_STRING, 0)
 }
 
-func (s *MulDivOperatorsContext) MOD() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserMOD, 0)
+func (s *RuleDescriptionContext) SQUOTA_STRING() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSQUOTA_STRING, 0)
 }
 
-func (s *MulDivOperatorsContext) GetRuleContext() antlr.RuleContext {
+func (s *RuleDescriptionContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *MulDivOperatorsContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *RuleDescriptionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *MulDivOperatorsContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *RuleDescriptionContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterMulDivOperators(s)
+		listenerT.EnterRuleDescription(s)
 	}
 }
 
-func (s *MulDivOperatorsContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *RuleDescriptionContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitMulDivOperators(s)
+		listenerT.ExitRuleDescription(s)
 	}
 }
 
-func (s *MulDivOperatorsContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *RuleDescriptionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitMulDivOperators(s)
+		return t.VisitRuleDescription(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) MulDivOperators() (localctx IMulDivOperatorsContext) {
-	localctx = NewMulDivOperatorsContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 22, grulev3ParserRULE_mulDivOperators)
+func (p *grulev3Parser) RuleDescription() (localctx IRuleDescriptionContext) {
+	localctx = NewRuleDescriptionContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 24, grulev3ParserRULE_ruleDescription)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(151)
+		p.SetState(210)
 		_la = p.GetTokenStream().LA(1)
 
-		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&112) != 0) {
+		if !(_la == grulev3ParserDQUOTA_STRING || _la == grulev3ParserSQUOTA_STRING) {
 			p.GetErrorHandler().RecoverInline(p)
 		} else {
 			p.GetErrorHandler().ReportMatch(p)
@@ -2402,118 +2908,119 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IAddMinusOperatorsContext is an interface to support dynamic dispatch.
-type IAddMinusOperatorsContext interface {
+// IRuleIdContext is an interface to support dynamic dispatch.
+type IRuleIdContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	PLUS() antlr.TerminalNode
-	MINUS() antlr.TerminalNode
-	BITAND() antlr.TerminalNode
-	BITOR() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	StringLiteral() IStringLiteralContext
 
-	// IsAddMinusOperatorsContext differentiates from other interfaces.
-	IsAddMinusOperatorsContext()
+	// IsRuleIdContext differentiates from other interfaces.
+	IsRuleIdContext()
 }
 
-type AddMinusOperatorsContext struct {
+type RuleIdContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyAddMinusOperatorsContext() *AddMinusOperatorsContext {
-	var p = new(AddMinusOperatorsContext)
+func NewEmptyRuleIdContext() *RuleIdContext {
+	var p = new(RuleIdContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+	p.RuleIndex = grulev3ParserRULE_ruleId
 	return p
 }
 
-func InitEmptyAddMinusOperatorsContext(p *AddMinusOperatorsContext) {
+func InitEmptyRuleIdContext(p *RuleIdContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+	p.RuleIndex = grulev3ParserRULE_ruleId
 }
 
-func (*AddMinusOperatorsContext) IsAddMinusOperatorsContext() {}
+func (*RuleIdContext) IsRuleIdContext() {}
 
-func NewAddMinusOperatorsContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AddMinusOperatorsContext {
-	var p = new(AddMinusOperatorsContext)
+func NewRuleIdContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *RuleIdContext {
+	var p = new(RuleIdContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+	p.RuleIndex = grulev3ParserRULE_ruleId
 
 	return p
 }
 
-func (s *AddMinusOperatorsContext) GetParser() antlr.Parser { return s.parser }
-
-func (s *AddMinusOperatorsContext) PLUS() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserPLUS, 0)
-}
+func (s *RuleIdContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *AddMinusOperatorsContext) MINUS() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserMINUS, 0)
+func (s *RuleIdContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *AddMinusOperatorsContext) BITAND() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserBITAND, 0)
-}
+func (s *RuleIdContext) StringLiteral() IStringLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IStringLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
 
-func (s *AddMinusOperatorsContext) BITOR() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserBITOR, 0)
+	if t == nil {
+		return nil
+	}
+
+	return t.(IStringLiteralContext)
 }
 
-func (s *AddMinusOperatorsContext) GetRuleContext() antlr.RuleContext {
+func (s *RuleIdContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *AddMinusOperatorsContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *RuleIdContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *AddMinusOperatorsContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *RuleIdContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterAddMinusOperators(s)
+		listenerT.EnterRuleId(s)
 	}
 }
 
-func (s *AddMinusOperatorsContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *RuleIdContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitAddMinusOperators(s)
+		listenerT.ExitRuleId(s)
 	}
 }
 
-func (s *AddMinusOperatorsContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *RuleIdContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitAddMinusOperators(s)
+		return t.VisitRuleId(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) AddMinusOperators() (localctx IAddMinusOperatorsContext) {
-	localctx = NewAddMinusOperatorsContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 24, grulev3ParserRULE_addMinusOperators)
-	var _la int
-
+func (p *grulev3Parser) RuleId() (localctx IRuleIdContext) {
+	localctx = NewRuleIdContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 26, grulev3ParserRULE_ruleId)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(153)
-		_la = p.GetTokenStream().LA(1)
-
-		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&206158430220) != 0) {
-			p.GetErrorHandler().RecoverInline(p)
-		} else {
-			p.GetErrorHandler().ReportMatch(p)
-			p.Consume()
+		p.SetState(212)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
 	}
+	{
+		p.SetState(213)
+		p.StringLiteral()
+	}
 
 errorExit:
 	if p.HasError() {
@@ -2528,128 +3035,119 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IComparisonOperatorContext is an interface to support dynamic dispatch.
-type IComparisonOperatorContext interface {
+// IWhenScopeContext is an interface to support dynamic dispatch.
+type IWhenScopeContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	GT() antlr.TerminalNode
-	LT() antlr.TerminalNode
-	GTE() antlr.TerminalNode
-	LTE() antlr.TerminalNode
-	EQUALS() antlr.TerminalNode
-	NOTEQUALS() antlr.TerminalNode
+	WHEN() antlr.TerminalNode
+	Expression() IExpressionContext
 
-	// IsComparisonOperatorContext differentiates from other interfaces.
-	IsComparisonOperatorContext()
+	// IsWhenScopeContext differentiates from other interfaces.
+	IsWhenScopeContext()
 }
 
-type ComparisonOperatorContext struct {
+type WhenScopeContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyComparisonOperatorContext() *ComparisonOperatorContext {
-	var p = new(ComparisonOperatorContext)
+func NewEmptyWhenScopeContext() *WhenScopeContext {
+	var p = new(WhenScopeContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+	p.RuleIndex = grulev3ParserRULE_whenScope
 	return p
 }
 
-func InitEmptyComparisonOperatorContext(p *ComparisonOperatorContext) {
+func InitEmptyWhenScopeContext(p *WhenScopeContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+	p.RuleIndex = grulev3ParserRULE_whenScope
 }
 
-func (*ComparisonOperatorContext) IsComparisonOperatorContext() {}
+func (*WhenScopeContext) IsWhenScopeContext() {}
 
-func NewComparisonOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ComparisonOperatorContext {
-	var p = new(ComparisonOperatorContext)
+func NewWhenScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *WhenScopeContext {
+	var p = new(WhenScopeContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+	p.RuleIndex = grulev3ParserRULE_whenScope
 
 	return p
 }
 
-func (s *ComparisonOperatorContext) GetParser() antlr.Parser { return s.parser }
-
-func (s *ComparisonOperatorContext) GT() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserGT, 0)
-}
-
-func (s *ComparisonOperatorContext) LT() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserLT, 0)
-}
+func (s *WhenScopeContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ComparisonOperatorContext) GTE() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserGTE, 0)
+func (s *WhenScopeContext) WHEN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserWHEN, 0)
 }
 
-func (s *ComparisonOperatorContext) LTE() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserLTE, 0)
-}
+func (s *WhenScopeContext) Expression() IExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
 
-func (s *ComparisonOperatorContext) EQUALS() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserEQUALS, 0)
-}
+	if t == nil {
+		return nil
+	}
 
-func (s *ComparisonOperatorContext) NOTEQUALS() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserNOTEQUALS, 0)
+	return t.(IExpressionContext)
 }
 
-func (s *ComparisonOperatorContext) GetRuleContext() antlr.RuleContext {
+func (s *WhenScopeContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ComparisonOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *WhenScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ComparisonOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *WhenScopeContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterComparisonOperator(s)
+		listenerT.EnterWhenScope(s)
 	}
 }
 
-func (s *ComparisonOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *WhenScopeContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitComparisonOperator(s)
+		listenerT.ExitWhenScope(s)
 	}
 }
 
-func (s *ComparisonOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *WhenScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitComparisonOperator(s)
+		return t.VisitWhenScope(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) ComparisonOperator() (localctx IComparisonOperatorContext) {
-	localctx = NewComparisonOperatorContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 26, grulev3ParserRULE_comparisonOperator)
-	var _la int
-
+func (p *grulev3Parser) WhenScope() (localctx IWhenScopeContext) {
+	localctx = NewWhenScopeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 28, grulev3ParserRULE_whenScope)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(155)
-		_la = p.GetTokenStream().LA(1)
-
-		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&66605547520) != 0) {
-			p.GetErrorHandler().RecoverInline(p)
-		} else {
-			p.GetErrorHandler().ReportMatch(p)
-			p.Consume()
+		p.SetState(215)
+		p.Match(grulev3ParserWHEN)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
 	}
+	{
+		p.SetState(216)
+		p.expression(0)
+	}
 
 errorExit:
 	if p.HasError() {
@@ -2664,98 +3162,154 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IAndLogicOperatorContext is an interface to support dynamic dispatch.
-type IAndLogicOperatorContext interface {
+// IThenScopeContext is an interface to support dynamic dispatch.
+type IThenScopeContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	AND() antlr.TerminalNode
+	THEN() antlr.TerminalNode
+	ScriptBlock() IScriptBlockContext
+	ThenExpressionList() IThenExpressionListContext
 
-	// IsAndLogicOperatorContext differentiates from other interfaces.
-	IsAndLogicOperatorContext()
+	// IsThenScopeContext differentiates from other interfaces.
+	IsThenScopeContext()
 }
 
-type AndLogicOperatorContext struct {
+type ThenScopeContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyAndLogicOperatorContext() *AndLogicOperatorContext {
-	var p = new(AndLogicOperatorContext)
+func NewEmptyThenScopeContext() *ThenScopeContext {
+	var p = new(ThenScopeContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+	p.RuleIndex = grulev3ParserRULE_thenScope
 	return p
 }
 
-func InitEmptyAndLogicOperatorContext(p *AndLogicOperatorContext) {
+func InitEmptyThenScopeContext(p *ThenScopeContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+	p.RuleIndex = grulev3ParserRULE_thenScope
 }
 
-func (*AndLogicOperatorContext) IsAndLogicOperatorContext() {}
+func (*ThenScopeContext) IsThenScopeContext() {}
 
-func NewAndLogicOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AndLogicOperatorContext {
-	var p = new(AndLogicOperatorContext)
+func NewThenScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenScopeContext {
+	var p = new(ThenScopeContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+	p.RuleIndex = grulev3ParserRULE_thenScope
 
 	return p
 }
 
-func (s *AndLogicOperatorContext) GetParser() antlr.Parser { return s.parser }
+func (s *ThenScopeContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *AndLogicOperatorContext) AND() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserAND, 0)
+func (s *ThenScopeContext) THEN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserTHEN, 0)
 }
 
-func (s *AndLogicOperatorContext) GetRuleContext() antlr.RuleContext {
+func (s *ThenScopeContext) ScriptBlock() IScriptBlockContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IScriptBlockContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IScriptBlockContext)
+}
+
+func (s *ThenScopeContext) ThenExpressionList() IThenExpressionListContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IThenExpressionListContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IThenExpressionListContext)
+}
+
+func (s *ThenScopeContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *AndLogicOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *ThenScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *AndLogicOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *ThenScopeContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterAndLogicOperator(s)
+		listenerT.EnterThenScope(s)
 	}
 }
 
-func (s *AndLogicOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *ThenScopeContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitAndLogicOperator(s)
+		listenerT.ExitThenScope(s)
 	}
 }
 
-func (s *AndLogicOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *ThenScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitAndLogicOperator(s)
+		return t.VisitThenScope(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) AndLogicOperator() (localctx IAndLogicOperatorContext) {
-	localctx = NewAndLogicOperatorContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 28, grulev3ParserRULE_andLogicOperator)
+func (p *grulev3Parser) ThenScope() (localctx IThenScopeContext) {
+	localctx = NewThenScopeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 30, grulev3ParserRULE_thenScope)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(157)
-		p.Match(grulev3ParserAND)
+		p.SetState(218)
+		p.Match(grulev3ParserTHEN)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
+	p.SetState(221)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 16, p.GetParserRuleContext()) {
+	case 1:
+		{
+			p.SetState(219)
+			p.ScriptBlock()
+		}
+
+	case 2:
+		{
+			p.SetState(220)
+			p.ThenExpressionList()
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
 
 errorExit:
 	if p.HasError() {
@@ -2770,93 +3324,140 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IOrLogicOperatorContext is an interface to support dynamic dispatch.
-type IOrLogicOperatorContext interface {
+// IElseScopeContext is an interface to support dynamic dispatch.
+type IElseScopeContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	OR() antlr.TerminalNode
-
-	// IsOrLogicOperatorContext differentiates from other interfaces.
-	IsOrLogicOperatorContext()
+	SIMPLENAME() antlr.TerminalNode
+	LR_BRACE() antlr.TerminalNode
+	ThenExpressionList() IThenExpressionListContext
+	RR_BRACE() antlr.TerminalNode
+
+	// IsElseScopeContext differentiates from other interfaces.
+	IsElseScopeContext()
 }
 
-type OrLogicOperatorContext struct {
+type ElseScopeContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyOrLogicOperatorContext() *OrLogicOperatorContext {
-	var p = new(OrLogicOperatorContext)
+func NewEmptyElseScopeContext() *ElseScopeContext {
+	var p = new(ElseScopeContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+	p.RuleIndex = grulev3ParserRULE_elseScope
 	return p
 }
 
-func InitEmptyOrLogicOperatorContext(p *OrLogicOperatorContext) {
+func InitEmptyElseScopeContext(p *ElseScopeContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+	p.RuleIndex = grulev3ParserRULE_elseScope
 }
 
-func (*OrLogicOperatorContext) IsOrLogicOperatorContext() {}
+func (*ElseScopeContext) IsElseScopeContext() {}
 
-func NewOrLogicOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *OrLogicOperatorContext {
-	var p = new(OrLogicOperatorContext)
+func NewElseScopeContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ElseScopeContext {
+	var p = new(ElseScopeContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+	p.RuleIndex = grulev3ParserRULE_elseScope
 
 	return p
 }
 
-func (s *OrLogicOperatorContext) GetParser() antlr.Parser { return s.parser }
+func (s *ElseScopeContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *OrLogicOperatorContext) OR() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserOR, 0)
+func (s *ElseScopeContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
-func (s *OrLogicOperatorContext) GetRuleContext() antlr.RuleContext {
+func (s *ElseScopeContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *ElseScopeContext) ThenExpressionList() IThenExpressionListContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IThenExpressionListContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IThenExpressionListContext)
+}
+
+func (s *ElseScopeContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *ElseScopeContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *OrLogicOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *ElseScopeContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *OrLogicOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *ElseScopeContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterOrLogicOperator(s)
+		listenerT.EnterElseScope(s)
 	}
 }
 
-func (s *OrLogicOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *ElseScopeContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitOrLogicOperator(s)
+		listenerT.ExitElseScope(s)
 	}
 }
 
-func (s *OrLogicOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *ElseScopeContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitOrLogicOperator(s)
+		return t.VisitElseScope(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) OrLogicOperator() (localctx IOrLogicOperatorContext) {
-	localctx = NewOrLogicOperatorContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 30, grulev3ParserRULE_orLogicOperator)
+func (p *grulev3Parser) ElseScope() (localctx IElseScopeContext) {
+	localctx = NewElseScopeContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 32, grulev3ParserRULE_elseScope)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(159)
-		p.Match(grulev3ParserOR)
+		p.SetState(223)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(224)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(225)
+		p.ThenExpressionList()
+	}
+	{
+		p.SetState(226)
+		p.Match(grulev3ParserRR_BRACE)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
@@ -2876,63 +3477,3410 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IExpressionAtomContext is an interface to support dynamic dispatch.
-type IExpressionAtomContext interface {
+// IScriptBlockContext is an interface to support dynamic dispatch.
+type IScriptBlockContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	Constant() IConstantContext
-	Variable() IVariableContext
-	FunctionCall() IFunctionCallContext
-	NEGATION() antlr.TerminalNode
-	ExpressionAtom() IExpressionAtomContext
-	MethodCall() IMethodCallContext
-	MemberVariable() IMemberVariableContext
-	ArrayMapSelector() IArrayMapSelectorContext
+	SIMPLENAME() antlr.TerminalNode
+	SCRIPT_LIT() antlr.TerminalNode
+
+	// IsScriptBlockContext differentiates from other interfaces.
+	IsScriptBlockContext()
+}
+
+type ScriptBlockContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyScriptBlockContext() *ScriptBlockContext {
+	var p = new(ScriptBlockContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_scriptBlock
+	return p
+}
+
+func InitEmptyScriptBlockContext(p *ScriptBlockContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_scriptBlock
+}
+
+func (*ScriptBlockContext) IsScriptBlockContext() {}
+
+func NewScriptBlockContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ScriptBlockContext {
+	var p = new(ScriptBlockContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_scriptBlock
+
+	return p
+}
+
+func (s *ScriptBlockContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ScriptBlockContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *ScriptBlockContext) SCRIPT_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSCRIPT_LIT, 0)
+}
+
+func (s *ScriptBlockContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ScriptBlockContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ScriptBlockContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterScriptBlock(s)
+	}
+}
+
+func (s *ScriptBlockContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitScriptBlock(s)
+	}
+}
+
+func (s *ScriptBlockContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitScriptBlock(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) ScriptBlock() (localctx IScriptBlockContext) {
+	localctx = NewScriptBlockContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 34, grulev3ParserRULE_scriptBlock)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(228)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(229)
+		p.Match(grulev3ParserSCRIPT_LIT)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IThenExpressionListContext is an interface to support dynamic dispatch.
+type IThenExpressionListContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	AllThenExpression() []IThenExpressionContext
+	ThenExpression(i int) IThenExpressionContext
+	AllSEMICOLON() []antlr.TerminalNode
+	SEMICOLON(i int) antlr.TerminalNode
+
+	// IsThenExpressionListContext differentiates from other interfaces.
+	IsThenExpressionListContext()
+}
+
+type ThenExpressionListContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyThenExpressionListContext() *ThenExpressionListContext {
+	var p = new(ThenExpressionListContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+	return p
+}
+
+func InitEmptyThenExpressionListContext(p *ThenExpressionListContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+}
+
+func (*ThenExpressionListContext) IsThenExpressionListContext() {}
+
+func NewThenExpressionListContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenExpressionListContext {
+	var p = new(ThenExpressionListContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_thenExpressionList
+
+	return p
+}
+
+func (s *ThenExpressionListContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ThenExpressionListContext) AllThenExpression() []IThenExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IThenExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IThenExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IThenExpressionContext); ok {
+			tst[i] = t.(IThenExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *ThenExpressionListContext) ThenExpression(i int) IThenExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IThenExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IThenExpressionContext)
+}
+
+func (s *ThenExpressionListContext) AllSEMICOLON() []antlr.TerminalNode {
+	return s.GetTokens(grulev3ParserSEMICOLON)
+}
+
+func (s *ThenExpressionListContext) SEMICOLON(i int) antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSEMICOLON, i)
+}
+
+func (s *ThenExpressionListContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ThenExpressionListContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ThenExpressionListContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterThenExpressionList(s)
+	}
+}
+
+func (s *ThenExpressionListContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitThenExpressionList(s)
+	}
+}
+
+func (s *ThenExpressionListContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitThenExpressionList(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) ThenExpressionList() (localctx IThenExpressionListContext) {
+	localctx = NewThenExpressionListContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 36, grulev3ParserRULE_thenExpressionList)
+	var _alt int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(231)
+		p.ThenExpression()
+	}
+	{
+		p.SetState(232)
+		p.Match(grulev3ParserSEMICOLON)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(238)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 17, p.GetParserRuleContext())
+	if p.HasError() {
+		goto errorExit
+	}
+	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
+		if _alt == 1 {
+			{
+				p.SetState(233)
+				p.ThenExpression()
+			}
+			{
+				p.SetState(234)
+				p.Match(grulev3ParserSEMICOLON)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+
+		}
+		p.SetState(240)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 17, p.GetParserRuleContext())
+		if p.HasError() {
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IThenExpressionContext is an interface to support dynamic dispatch.
+type IThenExpressionContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	Assignment() IAssignmentContext
+	CollectStatement() ICollectStatementContext
+	SwitchStatement() ISwitchStatementContext
+	ExpressionAtom() IExpressionAtomContext
+
+	// IsThenExpressionContext differentiates from other interfaces.
+	IsThenExpressionContext()
+}
+
+type ThenExpressionContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyThenExpressionContext() *ThenExpressionContext {
+	var p = new(ThenExpressionContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_thenExpression
+	return p
+}
+
+func InitEmptyThenExpressionContext(p *ThenExpressionContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_thenExpression
+}
+
+func (*ThenExpressionContext) IsThenExpressionContext() {}
+
+func NewThenExpressionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ThenExpressionContext {
+	var p = new(ThenExpressionContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_thenExpression
+
+	return p
+}
+
+func (s *ThenExpressionContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ThenExpressionContext) Assignment() IAssignmentContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IAssignmentContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IAssignmentContext)
+}
+
+func (s *ThenExpressionContext) CollectStatement() ICollectStatementContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ICollectStatementContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICollectStatementContext)
+}
+
+func (s *ThenExpressionContext) SwitchStatement() ISwitchStatementContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ISwitchStatementContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ISwitchStatementContext)
+}
+
+func (s *ThenExpressionContext) ExpressionAtom() IExpressionAtomContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionAtomContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionAtomContext)
+}
+
+func (s *ThenExpressionContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ThenExpressionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ThenExpressionContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterThenExpression(s)
+	}
+}
+
+func (s *ThenExpressionContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitThenExpression(s)
+	}
+}
+
+func (s *ThenExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitThenExpression(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) ThenExpression() (localctx IThenExpressionContext) {
+	localctx = NewThenExpressionContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 38, grulev3ParserRULE_thenExpression)
+	p.SetState(245)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 18, p.GetParserRuleContext()) {
+	case 1:
+		p.EnterOuterAlt(localctx, 1)
+		{
+			p.SetState(241)
+			p.Assignment()
+		}
+
+	case 2:
+		p.EnterOuterAlt(localctx, 2)
+		{
+			p.SetState(242)
+			p.CollectStatement()
+		}
+
+	case 3:
+		p.EnterOuterAlt(localctx, 3)
+		{
+			p.SetState(243)
+			p.SwitchStatement()
+		}
+
+	case 4:
+		p.EnterOuterAlt(localctx, 4)
+		{
+			p.SetState(244)
+			p.expressionAtom(0)
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// ICollectStatementContext is an interface to support dynamic dispatch.
+type ICollectStatementContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	AllSIMPLENAME() []antlr.TerminalNode
+	SIMPLENAME(i int) antlr.TerminalNode
+	AllExpression() []IExpressionContext
+	Expression(i int) IExpressionContext
+	IN() antlr.TerminalNode
+
+	// IsCollectStatementContext differentiates from other interfaces.
+	IsCollectStatementContext()
+}
+
+type CollectStatementContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyCollectStatementContext() *CollectStatementContext {
+	var p = new(CollectStatementContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_collectStatement
+	return p
+}
+
+func InitEmptyCollectStatementContext(p *CollectStatementContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_collectStatement
+}
+
+func (*CollectStatementContext) IsCollectStatementContext() {}
+
+func NewCollectStatementContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CollectStatementContext {
+	var p = new(CollectStatementContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_collectStatement
+
+	return p
+}
+
+func (s *CollectStatementContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *CollectStatementContext) AllSIMPLENAME() []antlr.TerminalNode {
+	return s.GetTokens(grulev3ParserSIMPLENAME)
+}
+
+func (s *CollectStatementContext) SIMPLENAME(i int) antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, i)
+}
+
+func (s *CollectStatementContext) AllExpression() []IExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IExpressionContext); ok {
+			tst[i] = t.(IExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *CollectStatementContext) Expression(i int) IExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *CollectStatementContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
+func (s *CollectStatementContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *CollectStatementContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *CollectStatementContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterCollectStatement(s)
+	}
+}
+
+func (s *CollectStatementContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitCollectStatement(s)
+	}
+}
+
+func (s *CollectStatementContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitCollectStatement(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) CollectStatement() (localctx ICollectStatementContext) {
+	localctx = NewCollectStatementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 40, grulev3ParserRULE_collectStatement)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(247)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(248)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == grulev3ParserIN || _la == grulev3ParserSIMPLENAME) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+	{
+		p.SetState(249)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(250)
+		p.expression(0)
+	}
+	p.SetState(253)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserSIMPLENAME {
+		{
+			p.SetState(251)
+			p.Match(grulev3ParserSIMPLENAME)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(252)
+			p.expression(0)
+		}
+
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// ISwitchStatementContext is an interface to support dynamic dispatch.
+type ISwitchStatementContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	SIMPLENAME() antlr.TerminalNode
+	Expression() IExpressionContext
+	LR_BRACE() antlr.TerminalNode
+	AllSwitchCase() []ISwitchCaseContext
+	SwitchCase(i int) ISwitchCaseContext
+	RR_BRACE() antlr.TerminalNode
+
+	// IsSwitchStatementContext differentiates from other interfaces.
+	IsSwitchStatementContext()
+}
+
+type SwitchStatementContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptySwitchStatementContext() *SwitchStatementContext {
+	var p = new(SwitchStatementContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_switchStatement
+	return p
+}
+
+func InitEmptySwitchStatementContext(p *SwitchStatementContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_switchStatement
+}
+
+func (*SwitchStatementContext) IsSwitchStatementContext() {}
+
+func NewSwitchStatementContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *SwitchStatementContext {
+	var p = new(SwitchStatementContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_switchStatement
+
+	return p
+}
+
+func (s *SwitchStatementContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *SwitchStatementContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *SwitchStatementContext) Expression() IExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *SwitchStatementContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *SwitchStatementContext) AllSwitchCase() []ISwitchCaseContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(ISwitchCaseContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]ISwitchCaseContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(ISwitchCaseContext); ok {
+			tst[i] = t.(ISwitchCaseContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *SwitchStatementContext) SwitchCase(i int) ISwitchCaseContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ISwitchCaseContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ISwitchCaseContext)
+}
+
+func (s *SwitchStatementContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *SwitchStatementContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *SwitchStatementContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *SwitchStatementContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterSwitchStatement(s)
+	}
+}
+
+func (s *SwitchStatementContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitSwitchStatement(s)
+	}
+}
+
+func (s *SwitchStatementContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitSwitchStatement(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) SwitchStatement() (localctx ISwitchStatementContext) {
+	localctx = NewSwitchStatementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 42, grulev3ParserRULE_switchStatement)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(255)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(256)
+		p.expression(0)
+	}
+	{
+		p.SetState(257)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(258)
+		p.SwitchCase()
+	}
+	p.SetState(262)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	for _la == grulev3ParserSIMPLENAME {
+		{
+			p.SetState(259)
+			p.SwitchCase()
+		}
+
+		p.SetState(264)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+	}
+	{
+		p.SetState(265)
+		p.Match(grulev3ParserRR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// ISwitchCaseContext is an interface to support dynamic dispatch.
+type ISwitchCaseContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	SIMPLENAME() antlr.TerminalNode
+	LR_BRACE() antlr.TerminalNode
+	RR_BRACE() antlr.TerminalNode
+	AllExpression() []IExpressionContext
+	Expression(i int) IExpressionContext
+	ThenExpressionList() IThenExpressionListContext
+
+	// IsSwitchCaseContext differentiates from other interfaces.
+	IsSwitchCaseContext()
+}
+
+type SwitchCaseContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptySwitchCaseContext() *SwitchCaseContext {
+	var p = new(SwitchCaseContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_switchCase
+	return p
+}
+
+func InitEmptySwitchCaseContext(p *SwitchCaseContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_switchCase
+}
+
+func (*SwitchCaseContext) IsSwitchCaseContext() {}
+
+func NewSwitchCaseContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *SwitchCaseContext {
+	var p = new(SwitchCaseContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_switchCase
+
+	return p
+}
+
+func (s *SwitchCaseContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *SwitchCaseContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *SwitchCaseContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *SwitchCaseContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *SwitchCaseContext) AllExpression() []IExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IExpressionContext); ok {
+			tst[i] = t.(IExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *SwitchCaseContext) Expression(i int) IExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *SwitchCaseContext) ThenExpressionList() IThenExpressionListContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IThenExpressionListContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IThenExpressionListContext)
+}
+
+func (s *SwitchCaseContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *SwitchCaseContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *SwitchCaseContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterSwitchCase(s)
+	}
+}
+
+func (s *SwitchCaseContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitSwitchCase(s)
+	}
+}
+
+func (s *SwitchCaseContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitSwitchCase(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) SwitchCase() (localctx ISwitchCaseContext) {
+	localctx = NewSwitchCaseContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 44, grulev3ParserRULE_switchCase)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(267)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(276)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 22, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(268)
+			p.expression(0)
+		}
+		p.SetState(273)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		for _la == grulev3ParserT__0 {
+			{
+				p.SetState(269)
+				p.Match(grulev3ParserT__0)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+			{
+				p.SetState(270)
+				p.expression(0)
+			}
+
+			p.SetState(275)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+			_la = p.GetTokenStream().LA(1)
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
+	{
+		p.SetState(278)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(280)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&1128644287948399112) != 0 {
+		{
+			p.SetState(279)
+			p.ThenExpressionList()
+		}
+
+	}
+	{
+		p.SetState(282)
+		p.Match(grulev3ParserRR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IAssignmentContext is an interface to support dynamic dispatch.
+type IAssignmentContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	Variable() IVariableContext
+	ASSIGN() antlr.TerminalNode
+	PLUS_ASIGN() antlr.TerminalNode
+	MINUS_ASIGN() antlr.TerminalNode
+	DIV_ASIGN() antlr.TerminalNode
+	MUL_ASIGN() antlr.TerminalNode
+	MatchExpression() IMatchExpressionContext
+	Expression() IExpressionContext
+
+	// IsAssignmentContext differentiates from other interfaces.
+	IsAssignmentContext()
+}
+
+type AssignmentContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyAssignmentContext() *AssignmentContext {
+	var p = new(AssignmentContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_assignment
+	return p
+}
+
+func InitEmptyAssignmentContext(p *AssignmentContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_assignment
+}
+
+func (*AssignmentContext) IsAssignmentContext() {}
+
+func NewAssignmentContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AssignmentContext {
+	var p = new(AssignmentContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_assignment
+
+	return p
+}
+
+func (s *AssignmentContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *AssignmentContext) Variable() IVariableContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IVariableContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IVariableContext)
+}
+
+func (s *AssignmentContext) ASSIGN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserASSIGN, 0)
+}
+
+func (s *AssignmentContext) PLUS_ASIGN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserPLUS_ASIGN, 0)
+}
+
+func (s *AssignmentContext) MINUS_ASIGN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMINUS_ASIGN, 0)
+}
+
+func (s *AssignmentContext) DIV_ASIGN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserDIV_ASIGN, 0)
+}
+
+func (s *AssignmentContext) MUL_ASIGN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMUL_ASIGN, 0)
+}
+
+func (s *AssignmentContext) MatchExpression() IMatchExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMatchExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchExpressionContext)
+}
+
+func (s *AssignmentContext) Expression() IExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *AssignmentContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *AssignmentContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *AssignmentContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterAssignment(s)
+	}
+}
+
+func (s *AssignmentContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitAssignment(s)
+	}
+}
+
+func (s *AssignmentContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitAssignment(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) Assignment() (localctx IAssignmentContext) {
+	localctx = NewAssignmentContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 46, grulev3ParserRULE_assignment)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(284)
+		p.variable(0)
+	}
+	{
+		p.SetState(285)
+		_la = p.GetTokenStream().LA(1)
+
+		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&33285996544) != 0) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+	p.SetState(288)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 24, p.GetParserRuleContext()) {
+	case 1:
+		{
+			p.SetState(286)
+			p.MatchExpression()
+		}
+
+	case 2:
+		{
+			p.SetState(287)
+			p.expression(0)
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IMatchExpressionContext is an interface to support dynamic dispatch.
+type IMatchExpressionContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	SIMPLENAME() antlr.TerminalNode
+	Expression() IExpressionContext
+	LR_BRACE() antlr.TerminalNode
+	AllMatchArm() []IMatchArmContext
+	MatchArm(i int) IMatchArmContext
+	RR_BRACE() antlr.TerminalNode
+
+	// IsMatchExpressionContext differentiates from other interfaces.
+	IsMatchExpressionContext()
+}
+
+type MatchExpressionContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchExpressionContext() *MatchExpressionContext {
+	var p = new(MatchExpressionContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_matchExpression
+	return p
+}
+
+func InitEmptyMatchExpressionContext(p *MatchExpressionContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_matchExpression
+}
+
+func (*MatchExpressionContext) IsMatchExpressionContext() {}
+
+func NewMatchExpressionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchExpressionContext {
+	var p = new(MatchExpressionContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_matchExpression
+
+	return p
+}
+
+func (s *MatchExpressionContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchExpressionContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *MatchExpressionContext) Expression() IExpressionContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *MatchExpressionContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *MatchExpressionContext) AllMatchArm() []IMatchArmContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IMatchArmContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IMatchArmContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IMatchArmContext); ok {
+			tst[i] = t.(IMatchArmContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *MatchExpressionContext) MatchArm(i int) IMatchArmContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMatchArmContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMatchArmContext)
+}
+
+func (s *MatchExpressionContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *MatchExpressionContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchExpressionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchExpressionContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterMatchExpression(s)
+	}
+}
+
+func (s *MatchExpressionContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitMatchExpression(s)
+	}
+}
+
+func (s *MatchExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitMatchExpression(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) MatchExpression() (localctx IMatchExpressionContext) {
+	localctx = NewMatchExpressionContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 48, grulev3ParserRULE_matchExpression)
+	var _la int
+
+	var _alt int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(290)
+		p.Match(grulev3ParserSIMPLENAME)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(291)
+		p.expression(0)
+	}
+	{
+		p.SetState(292)
+		p.Match(grulev3ParserLR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(293)
+		p.MatchArm()
+	}
+	p.SetState(298)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 25, p.GetParserRuleContext())
+	if p.HasError() {
+		goto errorExit
+	}
+	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
+		if _alt == 1 {
+			{
+				p.SetState(294)
+				p.Match(grulev3ParserT__0)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+			{
+				p.SetState(295)
+				p.MatchArm()
+			}
+
+		}
+		p.SetState(300)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 25, p.GetParserRuleContext())
+		if p.HasError() {
+			goto errorExit
+		}
+	}
+	p.SetState(302)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserT__0 {
+		{
+			p.SetState(301)
+			p.Match(grulev3ParserT__0)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	}
+	{
+		p.SetState(304)
+		p.Match(grulev3ParserRR_BRACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IMatchArmContext is an interface to support dynamic dispatch.
+type IMatchArmContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	ARROW() antlr.TerminalNode
+	AllExpression() []IExpressionContext
+	Expression(i int) IExpressionContext
+	UNDERSCORE() antlr.TerminalNode
+	ComparisonOperator() IComparisonOperatorContext
+
+	// IsMatchArmContext differentiates from other interfaces.
+	IsMatchArmContext()
+}
+
+type MatchArmContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMatchArmContext() *MatchArmContext {
+	var p = new(MatchArmContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_matchArm
+	return p
+}
+
+func InitEmptyMatchArmContext(p *MatchArmContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_matchArm
+}
+
+func (*MatchArmContext) IsMatchArmContext() {}
+
+func NewMatchArmContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MatchArmContext {
+	var p = new(MatchArmContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_matchArm
+
+	return p
+}
+
+func (s *MatchArmContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MatchArmContext) ARROW() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserARROW, 0)
+}
+
+func (s *MatchArmContext) AllExpression() []IExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IExpressionContext); ok {
+			tst[i] = t.(IExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *MatchArmContext) Expression(i int) IExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *MatchArmContext) UNDERSCORE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserUNDERSCORE, 0)
+}
+
+func (s *MatchArmContext) ComparisonOperator() IComparisonOperatorContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IComparisonOperatorContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IComparisonOperatorContext)
+}
+
+func (s *MatchArmContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MatchArmContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MatchArmContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterMatchArm(s)
+	}
+}
+
+func (s *MatchArmContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitMatchArm(s)
+	}
+}
+
+func (s *MatchArmContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitMatchArm(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) MatchArm() (localctx IMatchArmContext) {
+	localctx = NewMatchArmContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 50, grulev3ParserRULE_matchArm)
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(311)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetTokenStream().LA(1) {
+	case grulev3ParserUNDERSCORE:
+		{
+			p.SetState(306)
+			p.Match(grulev3ParserUNDERSCORE)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case grulev3ParserMINUS, grulev3ParserLR_BRACE, grulev3ParserLR_BRACKET, grulev3ParserLS_BRACKET, grulev3ParserTRUE, grulev3ParserFALSE, grulev3ParserNIL_LITERAL, grulev3ParserNEGATION, grulev3ParserIN, grulev3ParserEQUALS, grulev3ParserGT, grulev3ParserLT, grulev3ParserGTE, grulev3ParserLTE, grulev3ParserNOTEQUALS, grulev3ParserAPPROX_EQUALS, grulev3ParserSIMPLENAME, grulev3ParserDQUOTA_STRING, grulev3ParserSQUOTA_STRING, grulev3ParserDECIMAL_FLOAT_LIT, grulev3ParserHEX_FLOAT_LIT, grulev3ParserDEC_LIT, grulev3ParserHEX_LIT, grulev3ParserOCT_LIT, grulev3ParserQUANTITY_LIT, grulev3ParserSUFFIX_LIT:
+		p.SetState(308)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+
+		if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 27, p.GetParserRuleContext()) == 1 {
+			{
+				p.SetState(307)
+				p.ComparisonOperator()
+			}
+
+		} else if p.HasError() { // JIM
+			goto errorExit
+		}
+		{
+			p.SetState(310)
+			p.expression(0)
+		}
+
+	default:
+		p.SetError(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+		goto errorExit
+	}
+	{
+		p.SetState(313)
+		p.Match(grulev3ParserARROW)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(314)
+		p.expression(0)
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IExpressionContext is an interface to support dynamic dispatch.
+type IExpressionContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	LR_BRACKET() antlr.TerminalNode
+	AllExpression() []IExpressionContext
+	Expression(i int) IExpressionContext
+	RR_BRACKET() antlr.TerminalNode
+	NEGATION() antlr.TerminalNode
+	ExpressionAtom() IExpressionAtomContext
+	MulDivOperators() IMulDivOperatorsContext
+	AddMinusOperators() IAddMinusOperatorsContext
+	APPROX_EQUALS() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	ComparisonOperator() IComparisonOperatorContext
+	AndLogicOperator() IAndLogicOperatorContext
+	OrLogicOperator() IOrLogicOperatorContext
+
+	// IsExpressionContext differentiates from other interfaces.
+	IsExpressionContext()
+}
+
+type ExpressionContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyExpressionContext() *ExpressionContext {
+	var p = new(ExpressionContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_expression
+	return p
+}
+
+func InitEmptyExpressionContext(p *ExpressionContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_expression
+}
+
+func (*ExpressionContext) IsExpressionContext() {}
+
+func NewExpressionContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ExpressionContext {
+	var p = new(ExpressionContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_expression
+
+	return p
+}
+
+func (s *ExpressionContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ExpressionContext) LR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACKET, 0)
+}
+
+func (s *ExpressionContext) AllExpression() []IExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IExpressionContext); ok {
+			tst[i] = t.(IExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *ExpressionContext) Expression(i int) IExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *ExpressionContext) RR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACKET, 0)
+}
+
+func (s *ExpressionContext) NEGATION() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserNEGATION, 0)
+}
+
+func (s *ExpressionContext) ExpressionAtom() IExpressionAtomContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionAtomContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionAtomContext)
+}
+
+func (s *ExpressionContext) MulDivOperators() IMulDivOperatorsContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMulDivOperatorsContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMulDivOperatorsContext)
+}
+
+func (s *ExpressionContext) AddMinusOperators() IAddMinusOperatorsContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IAddMinusOperatorsContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IAddMinusOperatorsContext)
+}
+
+func (s *ExpressionContext) APPROX_EQUALS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserAPPROX_EQUALS, 0)
+}
+
+func (s *ExpressionContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *ExpressionContext) ComparisonOperator() IComparisonOperatorContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IComparisonOperatorContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IComparisonOperatorContext)
+}
+
+func (s *ExpressionContext) AndLogicOperator() IAndLogicOperatorContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IAndLogicOperatorContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IAndLogicOperatorContext)
+}
+
+func (s *ExpressionContext) OrLogicOperator() IOrLogicOperatorContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IOrLogicOperatorContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IOrLogicOperatorContext)
+}
+
+func (s *ExpressionContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ExpressionContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ExpressionContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterExpression(s)
+	}
+}
+
+func (s *ExpressionContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitExpression(s)
+	}
+}
+
+func (s *ExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitExpression(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) Expression() (localctx IExpressionContext) {
+	return p.expression(0)
+}
+
+func (p *grulev3Parser) expression(_p int) (localctx IExpressionContext) {
+	var _parentctx antlr.ParserRuleContext = p.GetParserRuleContext()
+
+	_parentState := p.GetState()
+	localctx = NewExpressionContext(p, p.GetParserRuleContext(), _parentState)
+	var _prevctx IExpressionContext = localctx
+	var _ antlr.ParserRuleContext = _prevctx // TODO: To prevent unused variable warning.
+	_startState := 52
+	p.EnterRecursionRule(localctx, 52, grulev3ParserRULE_expression, _p)
+	var _la int
+
+	var _alt int
+
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(325)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 30, p.GetParserRuleContext()) {
+	case 1:
+		p.SetState(318)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if _la == grulev3ParserNEGATION {
+			{
+				p.SetState(317)
+				p.Match(grulev3ParserNEGATION)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+
+		}
+		{
+			p.SetState(320)
+			p.Match(grulev3ParserLR_BRACKET)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(321)
+			p.expression(0)
+		}
+		{
+			p.SetState(322)
+			p.Match(grulev3ParserRR_BRACKET)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case 2:
+		{
+			p.SetState(324)
+			p.expressionAtom(0)
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
+	p.SetState(355)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 32, p.GetParserRuleContext())
+	if p.HasError() {
+		goto errorExit
+	}
+	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
+		if _alt == 1 {
+			if p.GetParseListeners() != nil {
+				p.TriggerExitRuleEvent()
+			}
+			_prevctx = localctx
+			p.SetState(353)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+
+			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 31, p.GetParserRuleContext()) {
+			case 1:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(327)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 8)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 8)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(328)
+					p.MulDivOperators()
+				}
+				{
+					p.SetState(329)
+					p.expression(9)
+				}
+
+			case 2:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(331)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 7)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 7)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(332)
+					p.AddMinusOperators()
+				}
+				{
+					p.SetState(333)
+					p.expression(8)
+				}
+
+			case 3:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(335)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 6)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 6)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(336)
+					p.Match(grulev3ParserAPPROX_EQUALS)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
+				}
+				{
+					p.SetState(337)
+					p.expression(0)
+				}
+				{
+					p.SetState(338)
+					p.Match(grulev3ParserSIMPLENAME)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
+				}
+				{
+					p.SetState(339)
+					p.expression(7)
+				}
+
+			case 4:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(341)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 5)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 5)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(342)
+					p.ComparisonOperator()
+				}
+				{
+					p.SetState(343)
+					p.expression(6)
+				}
+
+			case 5:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(345)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(346)
+					p.AndLogicOperator()
+				}
+				{
+					p.SetState(347)
+					p.expression(5)
+				}
+
+			case 6:
+				localctx = NewExpressionContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expression)
+				p.SetState(349)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(350)
+					p.OrLogicOperator()
+				}
+				{
+					p.SetState(351)
+					p.expression(4)
+				}
+
+			case antlr.ATNInvalidAltNumber:
+				goto errorExit
+			}
+
+		}
+		p.SetState(357)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 32, p.GetParserRuleContext())
+		if p.HasError() {
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.UnrollRecursionContexts(_parentctx)
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IMulDivOperatorsContext is an interface to support dynamic dispatch.
+type IMulDivOperatorsContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	MUL() antlr.TerminalNode
+	DIV() antlr.TerminalNode
+	MOD() antlr.TerminalNode
+
+	// IsMulDivOperatorsContext differentiates from other interfaces.
+	IsMulDivOperatorsContext()
+}
+
+type MulDivOperatorsContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyMulDivOperatorsContext() *MulDivOperatorsContext {
+	var p = new(MulDivOperatorsContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+	return p
+}
+
+func InitEmptyMulDivOperatorsContext(p *MulDivOperatorsContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+}
+
+func (*MulDivOperatorsContext) IsMulDivOperatorsContext() {}
+
+func NewMulDivOperatorsContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *MulDivOperatorsContext {
+	var p = new(MulDivOperatorsContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_mulDivOperators
+
+	return p
+}
+
+func (s *MulDivOperatorsContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *MulDivOperatorsContext) MUL() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMUL, 0)
+}
+
+func (s *MulDivOperatorsContext) DIV() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserDIV, 0)
+}
+
+func (s *MulDivOperatorsContext) MOD() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMOD, 0)
+}
+
+func (s *MulDivOperatorsContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *MulDivOperatorsContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *MulDivOperatorsContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterMulDivOperators(s)
+	}
+}
+
+func (s *MulDivOperatorsContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitMulDivOperators(s)
+	}
+}
+
+func (s *MulDivOperatorsContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitMulDivOperators(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) MulDivOperators() (localctx IMulDivOperatorsContext) {
+	localctx = NewMulDivOperatorsContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 54, grulev3ParserRULE_mulDivOperators)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(358)
+		_la = p.GetTokenStream().LA(1)
+
+		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&112) != 0) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IAddMinusOperatorsContext is an interface to support dynamic dispatch.
+type IAddMinusOperatorsContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	PLUS() antlr.TerminalNode
+	MINUS() antlr.TerminalNode
+	BITAND() antlr.TerminalNode
+	BITOR() antlr.TerminalNode
+
+	// IsAddMinusOperatorsContext differentiates from other interfaces.
+	IsAddMinusOperatorsContext()
+}
+
+type AddMinusOperatorsContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyAddMinusOperatorsContext() *AddMinusOperatorsContext {
+	var p = new(AddMinusOperatorsContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+	return p
+}
+
+func InitEmptyAddMinusOperatorsContext(p *AddMinusOperatorsContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+}
+
+func (*AddMinusOperatorsContext) IsAddMinusOperatorsContext() {}
+
+func NewAddMinusOperatorsContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AddMinusOperatorsContext {
+	var p = new(AddMinusOperatorsContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_addMinusOperators
+
+	return p
+}
+
+func (s *AddMinusOperatorsContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *AddMinusOperatorsContext) PLUS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserPLUS, 0)
+}
+
+func (s *AddMinusOperatorsContext) MINUS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMINUS, 0)
+}
+
+func (s *AddMinusOperatorsContext) BITAND() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserBITAND, 0)
+}
+
+func (s *AddMinusOperatorsContext) BITOR() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserBITOR, 0)
+}
+
+func (s *AddMinusOperatorsContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *AddMinusOperatorsContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *AddMinusOperatorsContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterAddMinusOperators(s)
+	}
+}
+
+func (s *AddMinusOperatorsContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitAddMinusOperators(s)
+	}
+}
+
+func (s *AddMinusOperatorsContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitAddMinusOperators(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) AddMinusOperators() (localctx IAddMinusOperatorsContext) {
+	localctx = NewAddMinusOperatorsContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 56, grulev3ParserRULE_addMinusOperators)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(360)
+		_la = p.GetTokenStream().LA(1)
+
+		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&6597069766668) != 0) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IComparisonOperatorContext is an interface to support dynamic dispatch.
+type IComparisonOperatorContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	GT() antlr.TerminalNode
+	LT() antlr.TerminalNode
+	GTE() antlr.TerminalNode
+	LTE() antlr.TerminalNode
+	EQUALS() antlr.TerminalNode
+	NOTEQUALS() antlr.TerminalNode
+	APPROX_EQUALS() antlr.TerminalNode
+	IN() antlr.TerminalNode
+
+	// IsComparisonOperatorContext differentiates from other interfaces.
+	IsComparisonOperatorContext()
+}
+
+type ComparisonOperatorContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyComparisonOperatorContext() *ComparisonOperatorContext {
+	var p = new(ComparisonOperatorContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+	return p
+}
+
+func InitEmptyComparisonOperatorContext(p *ComparisonOperatorContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+}
+
+func (*ComparisonOperatorContext) IsComparisonOperatorContext() {}
+
+func NewComparisonOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ComparisonOperatorContext {
+	var p = new(ComparisonOperatorContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_comparisonOperator
+
+	return p
+}
+
+func (s *ComparisonOperatorContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ComparisonOperatorContext) GT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserGT, 0)
+}
+
+func (s *ComparisonOperatorContext) LT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLT, 0)
+}
+
+func (s *ComparisonOperatorContext) GTE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserGTE, 0)
+}
+
+func (s *ComparisonOperatorContext) LTE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLTE, 0)
+}
+
+func (s *ComparisonOperatorContext) EQUALS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserEQUALS, 0)
+}
+
+func (s *ComparisonOperatorContext) NOTEQUALS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserNOTEQUALS, 0)
+}
+
+func (s *ComparisonOperatorContext) APPROX_EQUALS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserAPPROX_EQUALS, 0)
+}
+
+func (s *ComparisonOperatorContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
+func (s *ComparisonOperatorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ComparisonOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ComparisonOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterComparisonOperator(s)
+	}
+}
+
+func (s *ComparisonOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitComparisonOperator(s)
+	}
+}
+
+func (s *ComparisonOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitComparisonOperator(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) ComparisonOperator() (localctx IComparisonOperatorContext) {
+	localctx = NewComparisonOperatorContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 58, grulev3ParserRULE_comparisonOperator)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(362)
+		_la = p.GetTokenStream().LA(1)
+
+		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&2164864843776) != 0) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IAndLogicOperatorContext is an interface to support dynamic dispatch.
+type IAndLogicOperatorContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	AND() antlr.TerminalNode
+
+	// IsAndLogicOperatorContext differentiates from other interfaces.
+	IsAndLogicOperatorContext()
+}
+
+type AndLogicOperatorContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyAndLogicOperatorContext() *AndLogicOperatorContext {
+	var p = new(AndLogicOperatorContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+	return p
+}
+
+func InitEmptyAndLogicOperatorContext(p *AndLogicOperatorContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+}
+
+func (*AndLogicOperatorContext) IsAndLogicOperatorContext() {}
+
+func NewAndLogicOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *AndLogicOperatorContext {
+	var p = new(AndLogicOperatorContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_andLogicOperator
+
+	return p
+}
+
+func (s *AndLogicOperatorContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *AndLogicOperatorContext) AND() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserAND, 0)
+}
+
+func (s *AndLogicOperatorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *AndLogicOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *AndLogicOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterAndLogicOperator(s)
+	}
+}
+
+func (s *AndLogicOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitAndLogicOperator(s)
+	}
+}
+
+func (s *AndLogicOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitAndLogicOperator(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) AndLogicOperator() (localctx IAndLogicOperatorContext) {
+	localctx = NewAndLogicOperatorContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 60, grulev3ParserRULE_andLogicOperator)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(364)
+		p.Match(grulev3ParserAND)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IOrLogicOperatorContext is an interface to support dynamic dispatch.
+type IOrLogicOperatorContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	OR() antlr.TerminalNode
+
+	// IsOrLogicOperatorContext differentiates from other interfaces.
+	IsOrLogicOperatorContext()
+}
+
+type OrLogicOperatorContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyOrLogicOperatorContext() *OrLogicOperatorContext {
+	var p = new(OrLogicOperatorContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+	return p
+}
+
+func InitEmptyOrLogicOperatorContext(p *OrLogicOperatorContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+}
+
+func (*OrLogicOperatorContext) IsOrLogicOperatorContext() {}
+
+func NewOrLogicOperatorContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *OrLogicOperatorContext {
+	var p = new(OrLogicOperatorContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_orLogicOperator
+
+	return p
+}
+
+func (s *OrLogicOperatorContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *OrLogicOperatorContext) OR() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserOR, 0)
+}
+
+func (s *OrLogicOperatorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *OrLogicOperatorContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *OrLogicOperatorContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterOrLogicOperator(s)
+	}
+}
+
+func (s *OrLogicOperatorContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitOrLogicOperator(s)
+	}
+}
+
+func (s *OrLogicOperatorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitOrLogicOperator(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) OrLogicOperator() (localctx IOrLogicOperatorContext) {
+	localctx = NewOrLogicOperatorContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 62, grulev3ParserRULE_orLogicOperator)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(366)
+		p.Match(grulev3ParserOR)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IExpressionAtomContext is an interface to support dynamic dispatch.
+type IExpressionAtomContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	Constant() IConstantContext
+	Variable() IVariableContext
+	Quantifier() IQuantifierContext
+	FunctionCall() IFunctionCallContext
+	NEGATION() antlr.TerminalNode
+	ExpressionAtom() IExpressionAtomContext
+	MethodCall() IMethodCallContext
+	MemberVariable() IMemberVariableContext
+	ArrayMapSelector() IArrayMapSelectorContext
+
+	// IsExpressionAtomContext differentiates from other interfaces.
+	IsExpressionAtomContext()
+}
+
+type ExpressionAtomContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyExpressionAtomContext() *ExpressionAtomContext {
+	var p = new(ExpressionAtomContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_expressionAtom
+	return p
+}
+
+func InitEmptyExpressionAtomContext(p *ExpressionAtomContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_expressionAtom
+}
+
+func (*ExpressionAtomContext) IsExpressionAtomContext() {}
+
+func NewExpressionAtomContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ExpressionAtomContext {
+	var p = new(ExpressionAtomContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_expressionAtom
+
+	return p
+}
+
+func (s *ExpressionAtomContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *ExpressionAtomContext) Constant() IConstantContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IConstantContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IConstantContext)
+}
+
+func (s *ExpressionAtomContext) Variable() IVariableContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IVariableContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IVariableContext)
+}
+
+func (s *ExpressionAtomContext) Quantifier() IQuantifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IQuantifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IQuantifierContext)
+}
+
+func (s *ExpressionAtomContext) FunctionCall() IFunctionCallContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IFunctionCallContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFunctionCallContext)
+}
+
+func (s *ExpressionAtomContext) NEGATION() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserNEGATION, 0)
+}
+
+func (s *ExpressionAtomContext) ExpressionAtom() IExpressionAtomContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionAtomContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionAtomContext)
+}
+
+func (s *ExpressionAtomContext) MethodCall() IMethodCallContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMethodCallContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMethodCallContext)
+}
+
+func (s *ExpressionAtomContext) MemberVariable() IMemberVariableContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IMemberVariableContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IMemberVariableContext)
+}
+
+func (s *ExpressionAtomContext) ArrayMapSelector() IArrayMapSelectorContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IArrayMapSelectorContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IArrayMapSelectorContext)
+}
+
+func (s *ExpressionAtomContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *ExpressionAtomContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ExpressionAtomContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterExpressionAtom(s)
+	}
+}
+
+func (s *ExpressionAtomContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitExpressionAtom(s)
+	}
+}
+
+func (s *ExpressionAtomContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitExpressionAtom(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) ExpressionAtom() (localctx IExpressionAtomContext) {
+	return p.expressionAtom(0)
+}
+
+func (p *grulev3Parser) expressionAtom(_p int) (localctx IExpressionAtomContext) {
+	var _parentctx antlr.ParserRuleContext = p.GetParserRuleContext()
+
+	_parentState := p.GetState()
+	localctx = NewExpressionAtomContext(p, p.GetParserRuleContext(), _parentState)
+	var _prevctx IExpressionAtomContext = localctx
+	var _ antlr.ParserRuleContext = _prevctx // TODO: To prevent unused variable warning.
+	_startState := 64
+	p.EnterRecursionRule(localctx, 64, grulev3ParserRULE_expressionAtom, _p)
+	var _alt int
+
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(375)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 33, p.GetParserRuleContext()) {
+	case 1:
+		{
+			p.SetState(369)
+			p.Constant()
+		}
+
+	case 2:
+		{
+			p.SetState(370)
+			p.variable(0)
+		}
+
+	case 3:
+		{
+			p.SetState(371)
+			p.Quantifier()
+		}
+
+	case 4:
+		{
+			p.SetState(372)
+			p.FunctionCall()
+		}
+
+	case 5:
+		{
+			p.SetState(373)
+			p.Match(grulev3ParserNEGATION)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(374)
+			p.expressionAtom(1)
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
+	p.SetState(385)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 35, p.GetParserRuleContext())
+	if p.HasError() {
+		goto errorExit
+	}
+	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
+		if _alt == 1 {
+			if p.GetParseListeners() != nil {
+				p.TriggerExitRuleEvent()
+			}
+			_prevctx = localctx
+			p.SetState(383)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+
+			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 34, p.GetParserRuleContext()) {
+			case 1:
+				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
+				p.SetState(377)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(378)
+					p.MethodCall()
+				}
+
+			case 2:
+				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
+				p.SetState(379)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(380)
+					p.MemberVariable()
+				}
+
+			case 3:
+				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
+				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
+				p.SetState(381)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+					goto errorExit
+				}
+				{
+					p.SetState(382)
+					p.ArrayMapSelector()
+				}
+
+			case antlr.ATNInvalidAltNumber:
+				goto errorExit
+			}
+
+		}
+		p.SetState(387)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 35, p.GetParserRuleContext())
+		if p.HasError() {
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.UnrollRecursionContexts(_parentctx)
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IConstantContext is an interface to support dynamic dispatch.
+type IConstantContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
 
-	// IsExpressionAtomContext differentiates from other interfaces.
-	IsExpressionAtomContext()
+	// Getter signatures
+	StringLiteral() IStringLiteralContext
+	IntegerLiteral() IIntegerLiteralContext
+	FloatLiteral() IFloatLiteralContext
+	QuantityLiteral() IQuantityLiteralContext
+	SuffixLiteral() ISuffixLiteralContext
+	BooleanLiteral() IBooleanLiteralContext
+	CollectionLiteral() ICollectionLiteralContext
+	NIL_LITERAL() antlr.TerminalNode
+
+	// IsConstantContext differentiates from other interfaces.
+	IsConstantContext()
 }
 
-type ExpressionAtomContext struct {
+type ConstantContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyExpressionAtomContext() *ExpressionAtomContext {
-	var p = new(ExpressionAtomContext)
+func NewEmptyConstantContext() *ConstantContext {
+	var p = new(ConstantContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_expressionAtom
+	p.RuleIndex = grulev3ParserRULE_constant
 	return p
 }
 
-func InitEmptyExpressionAtomContext(p *ExpressionAtomContext) {
+func InitEmptyConstantContext(p *ConstantContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_expressionAtom
+	p.RuleIndex = grulev3ParserRULE_constant
 }
 
-func (*ExpressionAtomContext) IsExpressionAtomContext() {}
+func (*ConstantContext) IsConstantContext() {}
 
-func NewExpressionAtomContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ExpressionAtomContext {
-	var p = new(ExpressionAtomContext)
+func NewConstantContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ConstantContext {
+	var p = new(ConstantContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_expressionAtom
+	p.RuleIndex = grulev3ParserRULE_constant
 
 	return p
 }
 
-func (s *ExpressionAtomContext) GetParser() antlr.Parser { return s.parser }
+func (s *ConstantContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ExpressionAtomContext) Constant() IConstantContext {
+func (s *ConstantContext) StringLiteral() IStringLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IConstantContext); ok {
+		if _, ok := ctx.(IStringLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -2942,13 +6890,13 @@ func (s *ExpressionAtomContext) Constant() IConstantContext {
 		return nil
 	}
 
-	return t.(IConstantContext)
+	return t.(IStringLiteralContext)
 }
 
-func (s *ExpressionAtomContext) Variable() IVariableContext {
+func (s *ConstantContext) IntegerLiteral() IIntegerLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IVariableContext); ok {
+		if _, ok := ctx.(IIntegerLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -2958,13 +6906,13 @@ func (s *ExpressionAtomContext) Variable() IVariableContext {
 		return nil
 	}
 
-	return t.(IVariableContext)
+	return t.(IIntegerLiteralContext)
 }
 
-func (s *ExpressionAtomContext) FunctionCall() IFunctionCallContext {
+func (s *ConstantContext) FloatLiteral() IFloatLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IFunctionCallContext); ok {
+		if _, ok := ctx.(IFloatLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -2974,17 +6922,13 @@ func (s *ExpressionAtomContext) FunctionCall() IFunctionCallContext {
 		return nil
 	}
 
-	return t.(IFunctionCallContext)
-}
-
-func (s *ExpressionAtomContext) NEGATION() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserNEGATION, 0)
+	return t.(IFloatLiteralContext)
 }
 
-func (s *ExpressionAtomContext) ExpressionAtom() IExpressionAtomContext {
+func (s *ConstantContext) QuantityLiteral() IQuantityLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IExpressionAtomContext); ok {
+		if _, ok := ctx.(IQuantityLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -2994,13 +6938,13 @@ func (s *ExpressionAtomContext) ExpressionAtom() IExpressionAtomContext {
 		return nil
 	}
 
-	return t.(IExpressionAtomContext)
+	return t.(IQuantityLiteralContext)
 }
 
-func (s *ExpressionAtomContext) MethodCall() IMethodCallContext {
+func (s *ConstantContext) SuffixLiteral() ISuffixLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IMethodCallContext); ok {
+		if _, ok := ctx.(ISuffixLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -3010,13 +6954,13 @@ func (s *ExpressionAtomContext) MethodCall() IMethodCallContext {
 		return nil
 	}
 
-	return t.(IMethodCallContext)
+	return t.(ISuffixLiteralContext)
 }
 
-func (s *ExpressionAtomContext) MemberVariable() IMemberVariableContext {
+func (s *ConstantContext) BooleanLiteral() IBooleanLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IMemberVariableContext); ok {
+		if _, ok := ctx.(IBooleanLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -3026,13 +6970,13 @@ func (s *ExpressionAtomContext) MemberVariable() IMemberVariableContext {
 		return nil
 	}
 
-	return t.(IMemberVariableContext)
+	return t.(IBooleanLiteralContext)
 }
 
-func (s *ExpressionAtomContext) ArrayMapSelector() IArrayMapSelectorContext {
+func (s *ConstantContext) CollectionLiteral() ICollectionLiteralContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IArrayMapSelectorContext); ok {
+		if _, ok := ctx.(ICollectionLiteralContext); ok {
 			t = ctx.(antlr.RuleContext)
 			break
 		}
@@ -3042,176 +6986,405 @@ func (s *ExpressionAtomContext) ArrayMapSelector() IArrayMapSelectorContext {
 		return nil
 	}
 
-	return t.(IArrayMapSelectorContext)
+	return t.(ICollectionLiteralContext)
 }
 
-func (s *ExpressionAtomContext) GetRuleContext() antlr.RuleContext {
+func (s *ConstantContext) NIL_LITERAL() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserNIL_LITERAL, 0)
+}
+
+func (s *ConstantContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ExpressionAtomContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *ConstantContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *ConstantContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterConstant(s)
+	}
+}
+
+func (s *ConstantContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitConstant(s)
+	}
+}
+
+func (s *ConstantContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitConstant(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) Constant() (localctx IConstantContext) {
+	localctx = NewConstantContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 66, grulev3ParserRULE_constant)
+	p.SetState(396)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 36, p.GetParserRuleContext()) {
+	case 1:
+		p.EnterOuterAlt(localctx, 1)
+		{
+			p.SetState(388)
+			p.StringLiteral()
+		}
+
+	case 2:
+		p.EnterOuterAlt(localctx, 2)
+		{
+			p.SetState(389)
+			p.IntegerLiteral()
+		}
+
+	case 3:
+		p.EnterOuterAlt(localctx, 3)
+		{
+			p.SetState(390)
+			p.FloatLiteral()
+		}
+
+	case 4:
+		p.EnterOuterAlt(localctx, 4)
+		{
+			p.SetState(391)
+			p.QuantityLiteral()
+		}
+
+	case 5:
+		p.EnterOuterAlt(localctx, 5)
+		{
+			p.SetState(392)
+			p.SuffixLiteral()
+		}
+
+	case 6:
+		p.EnterOuterAlt(localctx, 6)
+		{
+			p.SetState(393)
+			p.BooleanLiteral()
+		}
+
+	case 7:
+		p.EnterOuterAlt(localctx, 7)
+		{
+			p.SetState(394)
+			p.CollectionLiteral()
+		}
+
+	case 8:
+		p.EnterOuterAlt(localctx, 8)
+		{
+			p.SetState(395)
+			p.Match(grulev3ParserNIL_LITERAL)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// ICollectionLiteralContext is an interface to support dynamic dispatch.
+type ICollectionLiteralContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	LR_BRACE() antlr.TerminalNode
+	RR_BRACE() antlr.TerminalNode
+	AllCollectionElement() []ICollectionElementContext
+	CollectionElement(i int) ICollectionElementContext
+	LS_BRACKET() antlr.TerminalNode
+	RS_BRACKET() antlr.TerminalNode
+
+	// IsCollectionLiteralContext differentiates from other interfaces.
+	IsCollectionLiteralContext()
+}
+
+type CollectionLiteralContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyCollectionLiteralContext() *CollectionLiteralContext {
+	var p = new(CollectionLiteralContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_collectionLiteral
+	return p
+}
+
+func InitEmptyCollectionLiteralContext(p *CollectionLiteralContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_collectionLiteral
+}
+
+func (*CollectionLiteralContext) IsCollectionLiteralContext() {}
+
+func NewCollectionLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CollectionLiteralContext {
+	var p = new(CollectionLiteralContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_collectionLiteral
+
+	return p
+}
+
+func (s *CollectionLiteralContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *CollectionLiteralContext) LR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACE, 0)
+}
+
+func (s *CollectionLiteralContext) RR_BRACE() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACE, 0)
+}
+
+func (s *CollectionLiteralContext) AllCollectionElement() []ICollectionElementContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(ICollectionElementContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]ICollectionElementContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(ICollectionElementContext); ok {
+			tst[i] = t.(ICollectionElementContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *CollectionLiteralContext) CollectionElement(i int) ICollectionElementContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ICollectionElementContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ICollectionElementContext)
+}
+
+func (s *CollectionLiteralContext) LS_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLS_BRACKET, 0)
+}
+
+func (s *CollectionLiteralContext) RS_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRS_BRACKET, 0)
+}
+
+func (s *CollectionLiteralContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *CollectionLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ExpressionAtomContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *CollectionLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterExpressionAtom(s)
+		listenerT.EnterCollectionLiteral(s)
 	}
 }
 
-func (s *ExpressionAtomContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *CollectionLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitExpressionAtom(s)
+		listenerT.ExitCollectionLiteral(s)
 	}
 }
 
-func (s *ExpressionAtomContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *CollectionLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitExpressionAtom(s)
+		return t.VisitCollectionLiteral(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) ExpressionAtom() (localctx IExpressionAtomContext) {
-	return p.expressionAtom(0)
-}
-
-func (p *grulev3Parser) expressionAtom(_p int) (localctx IExpressionAtomContext) {
-	var _parentctx antlr.ParserRuleContext = p.GetParserRuleContext()
-
-	_parentState := p.GetState()
-	localctx = NewExpressionAtomContext(p, p.GetParserRuleContext(), _parentState)
-	var _prevctx IExpressionAtomContext = localctx
-	var _ antlr.ParserRuleContext = _prevctx // TODO: To prevent unused variable warning.
-	_startState := 32
-	p.EnterRecursionRule(localctx, 32, grulev3ParserRULE_expressionAtom, _p)
-	var _alt int
+func (p *grulev3Parser) CollectionLiteral() (localctx ICollectionLiteralContext) {
+	localctx = NewCollectionLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 68, grulev3ParserRULE_collectionLiteral)
+	var _la int
 
-	p.EnterOuterAlt(localctx, 1)
-	p.SetState(167)
+	p.SetState(422)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 9, p.GetParserRuleContext()) {
-	case 1:
+	switch p.GetTokenStream().LA(1) {
+	case grulev3ParserLR_BRACE:
+		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(162)
-			p.Constant()
+			p.SetState(398)
+			p.Match(grulev3ParserLR_BRACE)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
-
-	case 2:
-		{
-			p.SetState(163)
-			p.variable(0)
+		p.SetState(407)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
 		}
+		_la = p.GetTokenStream().LA(1)
 
-	case 3:
+		if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&1128573919128723976) != 0 {
+			{
+				p.SetState(399)
+				p.CollectionElement()
+			}
+			p.SetState(404)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+			_la = p.GetTokenStream().LA(1)
+
+			for _la == grulev3ParserT__0 {
+				{
+					p.SetState(400)
+					p.Match(grulev3ParserT__0)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
+				}
+				{
+					p.SetState(401)
+					p.CollectionElement()
+				}
+
+				p.SetState(406)
+				p.GetErrorHandler().Sync(p)
+				if p.HasError() {
+					goto errorExit
+				}
+				_la = p.GetTokenStream().LA(1)
+			}
+
+		}
 		{
-			p.SetState(164)
-			p.FunctionCall()
+			p.SetState(409)
+			p.Match(grulev3ParserRR_BRACE)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
 
-	case 4:
+	case grulev3ParserLS_BRACKET:
+		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(165)
-			p.Match(grulev3ParserNEGATION)
+			p.SetState(410)
+			p.Match(grulev3ParserLS_BRACKET)
 			if p.HasError() {
 				// Recognition error - abort rule
 				goto errorExit
 			}
 		}
-		{
-			p.SetState(166)
-			p.expressionAtom(1)
+		p.SetState(419)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
 		}
+		_la = p.GetTokenStream().LA(1)
 
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
-	}
-	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
-	p.SetState(177)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
-	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 11, p.GetParserRuleContext())
-	if p.HasError() {
-		goto errorExit
-	}
-	for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
-		if _alt == 1 {
-			if p.GetParseListeners() != nil {
-				p.TriggerExitRuleEvent()
+		if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&1128573919128723976) != 0 {
+			{
+				p.SetState(411)
+				p.CollectionElement()
 			}
-			_prevctx = localctx
-			p.SetState(175)
+			p.SetState(416)
 			p.GetErrorHandler().Sync(p)
 			if p.HasError() {
 				goto errorExit
 			}
+			_la = p.GetTokenStream().LA(1)
 
-			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 10, p.GetParserRuleContext()) {
-			case 1:
-				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
-				p.SetState(169)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
-					goto errorExit
-				}
+			for _la == grulev3ParserT__0 {
 				{
-					p.SetState(170)
-					p.MethodCall()
-				}
-
-			case 2:
-				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
-				p.SetState(171)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
-					goto errorExit
+					p.SetState(412)
+					p.Match(grulev3ParserT__0)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
 				}
 				{
-					p.SetState(172)
-					p.MemberVariable()
+					p.SetState(413)
+					p.CollectionElement()
 				}
 
-			case 3:
-				localctx = NewExpressionAtomContext(p, _parentctx, _parentState)
-				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_expressionAtom)
-				p.SetState(173)
-
-				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+				p.SetState(418)
+				p.GetErrorHandler().Sync(p)
+				if p.HasError() {
 					goto errorExit
 				}
-				{
-					p.SetState(174)
-					p.ArrayMapSelector()
-				}
-
-			case antlr.ATNInvalidAltNumber:
-				goto errorExit
+				_la = p.GetTokenStream().LA(1)
 			}
 
 		}
-		p.SetState(179)
-		p.GetErrorHandler().Sync(p)
-		if p.HasError() {
-			goto errorExit
-		}
-		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 11, p.GetParserRuleContext())
-		if p.HasError() {
-			goto errorExit
+		{
+			p.SetState(421)
+			p.Match(grulev3ParserRS_BRACKET)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
+
+	default:
+		p.SetError(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+		goto errorExit
 	}
 
 errorExit:
@@ -3222,115 +7395,90 @@ errorExit:
 		p.GetErrorHandler().Recover(p, v)
 		p.SetError(nil)
 	}
-	p.UnrollRecursionContexts(_parentctx)
+	p.ExitRule()
 	return localctx
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IConstantContext is an interface to support dynamic dispatch.
-type IConstantContext interface {
+// ICollectionElementContext is an interface to support dynamic dispatch.
+type ICollectionElementContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	StringLiteral() IStringLiteralContext
-	IntegerLiteral() IIntegerLiteralContext
-	FloatLiteral() IFloatLiteralContext
-	BooleanLiteral() IBooleanLiteralContext
-	NIL_LITERAL() antlr.TerminalNode
+	AllConstant() []IConstantContext
+	Constant(i int) IConstantContext
+	COLON() antlr.TerminalNode
 
-	// IsConstantContext differentiates from other interfaces.
-	IsConstantContext()
+	// IsCollectionElementContext differentiates from other interfaces.
+	IsCollectionElementContext()
 }
 
-type ConstantContext struct {
+type CollectionElementContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyConstantContext() *ConstantContext {
-	var p = new(ConstantContext)
+func NewEmptyCollectionElementContext() *CollectionElementContext {
+	var p = new(CollectionElementContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_constant
+	p.RuleIndex = grulev3ParserRULE_collectionElement
 	return p
 }
 
-func InitEmptyConstantContext(p *ConstantContext) {
+func InitEmptyCollectionElementContext(p *CollectionElementContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_constant
+	p.RuleIndex = grulev3ParserRULE_collectionElement
 }
 
-func (*ConstantContext) IsConstantContext() {}
+func (*CollectionElementContext) IsCollectionElementContext() {}
 
-func NewConstantContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *ConstantContext {
-	var p = new(ConstantContext)
+func NewCollectionElementContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *CollectionElementContext {
+	var p = new(CollectionElementContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_constant
+	p.RuleIndex = grulev3ParserRULE_collectionElement
 
 	return p
 }
 
-func (s *ConstantContext) GetParser() antlr.Parser { return s.parser }
-
-func (s *ConstantContext) StringLiteral() IStringLiteralContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IStringLiteralContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
-		}
-	}
-
-	if t == nil {
-		return nil
-	}
-
-	return t.(IStringLiteralContext)
-}
+func (s *CollectionElementContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *ConstantContext) IntegerLiteral() IIntegerLiteralContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IIntegerLiteralContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+func (s *CollectionElementContext) AllConstant() []IConstantContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IConstantContext); ok {
+			len++
 		}
 	}
 
-	if t == nil {
-		return nil
-	}
-
-	return t.(IIntegerLiteralContext)
-}
-
-func (s *ConstantContext) FloatLiteral() IFloatLiteralContext {
-	var t antlr.RuleContext
-	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IFloatLiteralContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+	tst := make([]IConstantContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IConstantContext); ok {
+			tst[i] = t.(IConstantContext)
+			i++
 		}
 	}
 
-	if t == nil {
-		return nil
-	}
-
-	return t.(IFloatLiteralContext)
+	return tst
 }
 
-func (s *ConstantContext) BooleanLiteral() IBooleanLiteralContext {
+func (s *CollectionElementContext) Constant(i int) IConstantContext {
 	var t antlr.RuleContext
+	j := 0
 	for _, ctx := range s.GetChildren() {
-		if _, ok := ctx.(IBooleanLiteralContext); ok {
-			t = ctx.(antlr.RuleContext)
-			break
+		if _, ok := ctx.(IConstantContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
 		}
 	}
 
@@ -3338,94 +7486,74 @@ func (s *ConstantContext) BooleanLiteral() IBooleanLiteralContext {
 		return nil
 	}
 
-	return t.(IBooleanLiteralContext)
+	return t.(IConstantContext)
 }
 
-func (s *ConstantContext) NIL_LITERAL() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserNIL_LITERAL, 0)
+func (s *CollectionElementContext) COLON() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserCOLON, 0)
 }
 
-func (s *ConstantContext) GetRuleContext() antlr.RuleContext {
+func (s *CollectionElementContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *ConstantContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *CollectionElementContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *ConstantContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *CollectionElementContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterConstant(s)
+		listenerT.EnterCollectionElement(s)
 	}
 }
 
-func (s *ConstantContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *CollectionElementContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitConstant(s)
+		listenerT.ExitCollectionElement(s)
 	}
 }
 
-func (s *ConstantContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *CollectionElementContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitConstant(s)
+		return t.VisitCollectionElement(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) Constant() (localctx IConstantContext) {
-	localctx = NewConstantContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 34, grulev3ParserRULE_constant)
-	p.SetState(185)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
-
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 12, p.GetParserRuleContext()) {
-	case 1:
-		p.EnterOuterAlt(localctx, 1)
-		{
-			p.SetState(180)
-			p.StringLiteral()
-		}
-
-	case 2:
-		p.EnterOuterAlt(localctx, 2)
-		{
-			p.SetState(181)
-			p.IntegerLiteral()
-		}
-
-	case 3:
-		p.EnterOuterAlt(localctx, 3)
-		{
-			p.SetState(182)
-			p.FloatLiteral()
-		}
-
-	case 4:
-		p.EnterOuterAlt(localctx, 4)
-		{
-			p.SetState(183)
-			p.BooleanLiteral()
-		}
+func (p *grulev3Parser) CollectionElement() (localctx ICollectionElementContext) {
+	localctx = NewCollectionElementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 70, grulev3ParserRULE_collectionElement)
+	var _la int
 
-	case 5:
-		p.EnterOuterAlt(localctx, 5)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(424)
+		p.Constant()
+	}
+	p.SetState(427)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserCOLON {
 		{
-			p.SetState(184)
-			p.Match(grulev3ParserNIL_LITERAL)
+			p.SetState(425)
+			p.Match(grulev3ParserCOLON)
 			if p.HasError() {
 				// Recognition error - abort rule
 				goto errorExit
 			}
 		}
+		{
+			p.SetState(426)
+			p.Constant()
+		}
 
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
 	}
 
 errorExit:
@@ -3450,6 +7578,7 @@ type IVariableContext interface {
 
 	// Getter signatures
 	SIMPLENAME() antlr.TerminalNode
+	IN() antlr.TerminalNode
 	Variable() IVariableContext
 	MemberVariable() IMemberVariableContext
 	ArrayMapSelector() IArrayMapSelectorContext
@@ -3494,6 +7623,10 @@ func (s *VariableContext) SIMPLENAME() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
+func (s *VariableContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
 func (s *VariableContext) Variable() IVariableContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
@@ -3583,27 +7716,49 @@ func (p *grulev3Parser) variable(_p int) (localctx IVariableContext) {
 	localctx = NewVariableContext(p, p.GetParserRuleContext(), _parentState)
 	var _prevctx IVariableContext = localctx
 	var _ antlr.ParserRuleContext = _prevctx // TODO: To prevent unused variable warning.
-	_startState := 36
-	p.EnterRecursionRule(localctx, 36, grulev3ParserRULE_variable, _p)
+	_startState := 72
+	p.EnterRecursionRule(localctx, 72, grulev3ParserRULE_variable, _p)
 	var _alt int
 
 	p.EnterOuterAlt(localctx, 1)
-	{
-		p.SetState(188)
-		p.Match(grulev3ParserSIMPLENAME)
-		if p.HasError() {
-			// Recognition error - abort rule
-			goto errorExit
-		}
+	p.SetState(432)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
 	}
 
+	switch p.GetTokenStream().LA(1) {
+	case grulev3ParserSIMPLENAME:
+		{
+			p.SetState(430)
+			p.Match(grulev3ParserSIMPLENAME)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case grulev3ParserIN:
+		{
+			p.SetState(431)
+			p.Match(grulev3ParserIN)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	default:
+		p.SetError(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+		goto errorExit
+	}
 	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
-	p.SetState(196)
+	p.SetState(440)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
-	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 14, p.GetParserRuleContext())
+	_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 45, p.GetParserRuleContext())
 	if p.HasError() {
 		goto errorExit
 	}
@@ -3613,38 +7768,38 @@ func (p *grulev3Parser) variable(_p int) (localctx IVariableContext) {
 				p.TriggerExitRuleEvent()
 			}
 			_prevctx = localctx
-			p.SetState(194)
+			p.SetState(438)
 			p.GetErrorHandler().Sync(p)
 			if p.HasError() {
 				goto errorExit
 			}
 
-			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 13, p.GetParserRuleContext()) {
+			switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 44, p.GetParserRuleContext()) {
 			case 1:
 				localctx = NewVariableContext(p, _parentctx, _parentState)
 				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_variable)
-				p.SetState(190)
+				p.SetState(434)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
 					goto errorExit
 				}
 				{
-					p.SetState(191)
+					p.SetState(435)
 					p.MemberVariable()
 				}
 
 			case 2:
 				localctx = NewVariableContext(p, _parentctx, _parentState)
 				p.PushNewRecursionContext(localctx, _startState, grulev3ParserRULE_variable)
-				p.SetState(192)
+				p.SetState(436)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
-					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
+					p.SetError(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
 					goto errorExit
 				}
 				{
-					p.SetState(193)
+					p.SetState(437)
 					p.ArrayMapSelector()
 				}
 
@@ -3653,12 +7808,12 @@ func (p *grulev3Parser) variable(_p int) (localctx IVariableContext) {
 			}
 
 		}
-		p.SetState(198)
+		p.SetState(442)
 		p.GetErrorHandler().Sync(p)
 		if p.HasError() {
 			goto errorExit
 		}
-		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 14, p.GetParserRuleContext())
+		_alt = p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 45, p.GetParserRuleContext())
 		if p.HasError() {
 			goto errorExit
 		}
@@ -3781,10 +7936,10 @@ func (s *ArrayMapSelectorContext) Accept(visitor antlr.ParseTreeVisitor) interfa
 
 func (p *grulev3Parser) ArrayMapSelector() (localctx IArrayMapSelectorContext) {
 	localctx = NewArrayMapSelectorContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 38, grulev3ParserRULE_arrayMapSelector)
+	p.EnterRule(localctx, 74, grulev3ParserRULE_arrayMapSelector)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(199)
+		p.SetState(443)
 		p.Match(grulev3ParserLS_BRACKET)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -3792,11 +7947,11 @@ func (p *grulev3Parser) ArrayMapSelector() (localctx IArrayMapSelectorContext) {
 		}
 	}
 	{
-		p.SetState(200)
+		p.SetState(444)
 		p.expression(0)
 	}
 	{
-		p.SetState(201)
+		p.SetState(445)
 		p.Match(grulev3ParserRS_BRACKET)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -3827,6 +7982,7 @@ type IMemberVariableContext interface {
 	// Getter signatures
 	DOT() antlr.TerminalNode
 	SIMPLENAME() antlr.TerminalNode
+	IN() antlr.TerminalNode
 
 	// IsMemberVariableContext differentiates from other interfaces.
 	IsMemberVariableContext()
@@ -3872,6 +8028,10 @@ func (s *MemberVariableContext) SIMPLENAME() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserSIMPLENAME, 0)
 }
 
+func (s *MemberVariableContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
 func (s *MemberVariableContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
@@ -3904,10 +8064,12 @@ func (s *MemberVariableContext) Accept(visitor antlr.ParseTreeVisitor) interface
 
 func (p *grulev3Parser) MemberVariable() (localctx IMemberVariableContext) {
 	localctx = NewMemberVariableContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 40, grulev3ParserRULE_memberVariable)
+	p.EnterRule(localctx, 76, grulev3ParserRULE_memberVariable)
+	var _la int
+
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(203)
+		p.SetState(447)
 		p.Match(grulev3ParserDOT)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -3915,13 +8077,243 @@ func (p *grulev3Parser) MemberVariable() (localctx IMemberVariableContext) {
 		}
 	}
 	{
-		p.SetState(204)
+		p.SetState(448)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == grulev3ParserIN || _la == grulev3ParserSIMPLENAME) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IQuantifierContext is an interface to support dynamic dispatch.
+type IQuantifierContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	AllSIMPLENAME() []antlr.TerminalNode
+	SIMPLENAME(i int) antlr.TerminalNode
+	LR_BRACKET() antlr.TerminalNode
+	AllExpression() []IExpressionContext
+	Expression(i int) IExpressionContext
+	LAMBDA() antlr.TerminalNode
+	RR_BRACKET() antlr.TerminalNode
+	IN() antlr.TerminalNode
+
+	// IsQuantifierContext differentiates from other interfaces.
+	IsQuantifierContext()
+}
+
+type QuantifierContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyQuantifierContext() *QuantifierContext {
+	var p = new(QuantifierContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_quantifier
+	return p
+}
+
+func InitEmptyQuantifierContext(p *QuantifierContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_quantifier
+}
+
+func (*QuantifierContext) IsQuantifierContext() {}
+
+func NewQuantifierContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *QuantifierContext {
+	var p = new(QuantifierContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_quantifier
+
+	return p
+}
+
+func (s *QuantifierContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *QuantifierContext) AllSIMPLENAME() []antlr.TerminalNode {
+	return s.GetTokens(grulev3ParserSIMPLENAME)
+}
+
+func (s *QuantifierContext) SIMPLENAME(i int) antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, i)
+}
+
+func (s *QuantifierContext) LR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLR_BRACKET, 0)
+}
+
+func (s *QuantifierContext) AllExpression() []IExpressionContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IExpressionContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IExpressionContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IExpressionContext); ok {
+			tst[i] = t.(IExpressionContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *QuantifierContext) Expression(i int) IExpressionContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IExpressionContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *QuantifierContext) LAMBDA() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserLAMBDA, 0)
+}
+
+func (s *QuantifierContext) RR_BRACKET() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserRR_BRACKET, 0)
+}
+
+func (s *QuantifierContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
+func (s *QuantifierContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *QuantifierContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *QuantifierContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterQuantifier(s)
+	}
+}
+
+func (s *QuantifierContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitQuantifier(s)
+	}
+}
+
+func (s *QuantifierContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitQuantifier(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) Quantifier() (localctx IQuantifierContext) {
+	localctx = NewQuantifierContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 78, grulev3ParserRULE_quantifier)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(450)
 		p.Match(grulev3ParserSIMPLENAME)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
+	{
+		p.SetState(451)
+		p.Match(grulev3ParserLR_BRACKET)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(452)
+		p.expression(0)
+	}
+	{
+		p.SetState(453)
+		p.Match(grulev3ParserT__0)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(454)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == grulev3ParserIN || _la == grulev3ParserSIMPLENAME) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
+		}
+	}
+	{
+		p.SetState(455)
+		p.Match(grulev3ParserLAMBDA)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(456)
+		p.expression(0)
+	}
+	{
+		p.SetState(457)
+		p.Match(grulev3ParserRR_BRACKET)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
 
 errorExit:
 	if p.HasError() {
@@ -3944,9 +8336,10 @@ type IFunctionCallContext interface {
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	SIMPLENAME() antlr.TerminalNode
 	LR_BRACKET() antlr.TerminalNode
 	RR_BRACKET() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	IN() antlr.TerminalNode
 	ArgumentList() IArgumentListContext
 
 	// IsFunctionCallContext differentiates from other interfaces.
@@ -3985,10 +8378,6 @@ func NewFunctionCallContext(parser antlr.Parser, parent antlr.ParserRuleContext,
 
 func (s *FunctionCallContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *FunctionCallContext) SIMPLENAME() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserSIMPLENAME, 0)
-}
-
 func (s *FunctionCallContext) LR_BRACKET() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserLR_BRACKET, 0)
 }
@@ -3997,6 +8386,14 @@ func (s *FunctionCallContext) RR_BRACKET() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserRR_BRACKET, 0)
 }
 
+func (s *FunctionCallContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *FunctionCallContext) IN() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserIN, 0)
+}
+
 func (s *FunctionCallContext) ArgumentList() IArgumentListContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
@@ -4045,42 +8442,45 @@ func (s *FunctionCallContext) Accept(visitor antlr.ParseTreeVisitor) interface{}
 
 func (p *grulev3Parser) FunctionCall() (localctx IFunctionCallContext) {
 	localctx = NewFunctionCallContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 42, grulev3ParserRULE_functionCall)
+	p.EnterRule(localctx, 80, grulev3ParserRULE_functionCall)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(206)
-		p.Match(grulev3ParserSIMPLENAME)
-		if p.HasError() {
-			// Recognition error - abort rule
-			goto errorExit
+		p.SetState(459)
+		_la = p.GetTokenStream().LA(1)
+
+		if !(_la == grulev3ParserIN || _la == grulev3ParserSIMPLENAME) {
+			p.GetErrorHandler().RecoverInline(p)
+		} else {
+			p.GetErrorHandler().ReportMatch(p)
+			p.Consume()
 		}
 	}
 	{
-		p.SetState(207)
+		p.SetState(460)
 		p.Match(grulev3ParserLR_BRACKET)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
 		}
 	}
-	p.SetState(209)
+	p.SetState(462)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
 	_la = p.GetTokenStream().LA(1)
 
-	if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&259209881978888) != 0 {
+	if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&1128644287948401160) != 0 {
 		{
-			p.SetState(208)
+			p.SetState(461)
 			p.ArgumentList()
 		}
 
 	}
 	{
-		p.SetState(211)
+		p.SetState(464)
 		p.Match(grulev3ParserRR_BRACKET)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -4200,10 +8600,10 @@ func (s *MethodCallContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 
 func (p *grulev3Parser) MethodCall() (localctx IMethodCallContext) {
 	localctx = NewMethodCallContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 44, grulev3ParserRULE_methodCall)
+	p.EnterRule(localctx, 82, grulev3ParserRULE_methodCall)
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(213)
+		p.SetState(466)
 		p.Match(grulev3ParserDOT)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -4211,7 +8611,7 @@ func (p *grulev3Parser) MethodCall() (localctx IMethodCallContext) {
 		}
 	}
 	{
-		p.SetState(214)
+		p.SetState(467)
 		p.FunctionCall()
 	}
 
@@ -4348,15 +8748,15 @@ func (s *ArgumentListContext) Accept(visitor antlr.ParseTreeVisitor) interface{}
 
 func (p *grulev3Parser) ArgumentList() (localctx IArgumentListContext) {
 	localctx = NewArgumentListContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 46, grulev3ParserRULE_argumentList)
+	p.EnterRule(localctx, 84, grulev3ParserRULE_argumentList)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(216)
+		p.SetState(469)
 		p.expression(0)
 	}
-	p.SetState(221)
+	p.SetState(474)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -4365,7 +8765,7 @@ func (p *grulev3Parser) ArgumentList() (localctx IArgumentListContext) {
 
 	for _la == grulev3ParserT__0 {
 		{
-			p.SetState(217)
+			p.SetState(470)
 			p.Match(grulev3ParserT__0)
 			if p.HasError() {
 				// Recognition error - abort rule
@@ -4373,11 +8773,11 @@ func (p *grulev3Parser) ArgumentList() (localctx IArgumentListContext) {
 			}
 		}
 		{
-			p.SetState(218)
+			p.SetState(471)
 			p.expression(0)
 		}
 
-		p.SetState(223)
+		p.SetState(476)
 		p.GetErrorHandler().Sync(p)
 		if p.HasError() {
 			goto errorExit
@@ -4509,25 +8909,25 @@ func (s *FloatLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{}
 
 func (p *grulev3Parser) FloatLiteral() (localctx IFloatLiteralContext) {
 	localctx = NewFloatLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 48, grulev3ParserRULE_floatLiteral)
-	p.SetState(226)
+	p.EnterRule(localctx, 86, grulev3ParserRULE_floatLiteral)
+	p.SetState(479)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 17, p.GetParserRuleContext()) {
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 48, p.GetParserRuleContext()) {
 	case 1:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(224)
+			p.SetState(477)
 			p.DecimalFloatLiteral()
 		}
 
 	case 2:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(225)
+			p.SetState(478)
 			p.HexadecimalFloatLiteral()
 		}
 
@@ -4635,11 +9035,11 @@ func (s *DecimalFloatLiteralContext) Accept(visitor antlr.ParseTreeVisitor) inte
 
 func (p *grulev3Parser) DecimalFloatLiteral() (localctx IDecimalFloatLiteralContext) {
 	localctx = NewDecimalFloatLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 50, grulev3ParserRULE_decimalFloatLiteral)
+	p.EnterRule(localctx, 88, grulev3ParserRULE_decimalFloatLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(229)
+	p.SetState(482)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -4648,7 +9048,7 @@ func (p *grulev3Parser) DecimalFloatLiteral() (localctx IDecimalFloatLiteralCont
 
 	if _la == grulev3ParserMINUS {
 		{
-			p.SetState(228)
+			p.SetState(481)
 			p.Match(grulev3ParserMINUS)
 			if p.HasError() {
 				// Recognition error - abort rule
@@ -4658,7 +9058,7 @@ func (p *grulev3Parser) DecimalFloatLiteral() (localctx IDecimalFloatLiteralCont
 
 	}
 	{
-		p.SetState(231)
+		p.SetState(484)
 		p.Match(grulev3ParserDECIMAL_FLOAT_LIT)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -4766,11 +9166,11 @@ func (s *HexadecimalFloatLiteralContext) Accept(visitor antlr.ParseTreeVisitor)
 
 func (p *grulev3Parser) HexadecimalFloatLiteral() (localctx IHexadecimalFloatLiteralContext) {
 	localctx = NewHexadecimalFloatLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 52, grulev3ParserRULE_hexadecimalFloatLiteral)
+	p.EnterRule(localctx, 90, grulev3ParserRULE_hexadecimalFloatLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(234)
+	p.SetState(487)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -4779,7 +9179,7 @@ func (p *grulev3Parser) HexadecimalFloatLiteral() (localctx IHexadecimalFloatLit
 
 	if _la == grulev3ParserMINUS {
 		{
-			p.SetState(233)
+			p.SetState(486)
 			p.Match(grulev3ParserMINUS)
 			if p.HasError() {
 				// Recognition error - abort rule
@@ -4789,7 +9189,7 @@ func (p *grulev3Parser) HexadecimalFloatLiteral() (localctx IHexadecimalFloatLit
 
 	}
 	{
-		p.SetState(236)
+		p.SetState(489)
 		p.Match(grulev3ParserHEX_FLOAT_LIT)
 		if p.HasError() {
 			// Recognition error - abort rule
@@ -4938,37 +9338,299 @@ func (s *IntegerLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface
 
 func (p *grulev3Parser) IntegerLiteral() (localctx IIntegerLiteralContext) {
 	localctx = NewIntegerLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 54, grulev3ParserRULE_integerLiteral)
-	p.SetState(241)
+	p.EnterRule(localctx, 92, grulev3ParserRULE_integerLiteral)
+	p.SetState(494)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 20, p.GetParserRuleContext()) {
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 51, p.GetParserRuleContext()) {
 	case 1:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(238)
+			p.SetState(491)
 			p.DecimalLiteral()
 		}
 
-	case 2:
-		p.EnterOuterAlt(localctx, 2)
+	case 2:
+		p.EnterOuterAlt(localctx, 2)
+		{
+			p.SetState(492)
+			p.HexadecimalLiteral()
+		}
+
+	case 3:
+		p.EnterOuterAlt(localctx, 3)
+		{
+			p.SetState(493)
+			p.OctalLiteral()
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IDecimalLiteralContext is an interface to support dynamic dispatch.
+type IDecimalLiteralContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	DEC_LIT() antlr.TerminalNode
+	MINUS() antlr.TerminalNode
+
+	// IsDecimalLiteralContext differentiates from other interfaces.
+	IsDecimalLiteralContext()
+}
+
+type DecimalLiteralContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyDecimalLiteralContext() *DecimalLiteralContext {
+	var p = new(DecimalLiteralContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+	return p
+}
+
+func InitEmptyDecimalLiteralContext(p *DecimalLiteralContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+}
+
+func (*DecimalLiteralContext) IsDecimalLiteralContext() {}
+
+func NewDecimalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *DecimalLiteralContext {
+	var p = new(DecimalLiteralContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+
+	return p
+}
+
+func (s *DecimalLiteralContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *DecimalLiteralContext) DEC_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserDEC_LIT, 0)
+}
+
+func (s *DecimalLiteralContext) MINUS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMINUS, 0)
+}
+
+func (s *DecimalLiteralContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *DecimalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *DecimalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterDecimalLiteral(s)
+	}
+}
+
+func (s *DecimalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitDecimalLiteral(s)
+	}
+}
+
+func (s *DecimalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitDecimalLiteral(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) DecimalLiteral() (localctx IDecimalLiteralContext) {
+	localctx = NewDecimalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 94, grulev3ParserRULE_decimalLiteral)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(497)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserMINUS {
+		{
+			p.SetState(496)
+			p.Match(grulev3ParserMINUS)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	}
+	{
+		p.SetState(499)
+		p.Match(grulev3ParserDEC_LIT)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IHexadecimalLiteralContext is an interface to support dynamic dispatch.
+type IHexadecimalLiteralContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	HEX_LIT() antlr.TerminalNode
+	MINUS() antlr.TerminalNode
+
+	// IsHexadecimalLiteralContext differentiates from other interfaces.
+	IsHexadecimalLiteralContext()
+}
+
+type HexadecimalLiteralContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyHexadecimalLiteralContext() *HexadecimalLiteralContext {
+	var p = new(HexadecimalLiteralContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+	return p
+}
+
+func InitEmptyHexadecimalLiteralContext(p *HexadecimalLiteralContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+}
+
+func (*HexadecimalLiteralContext) IsHexadecimalLiteralContext() {}
+
+func NewHexadecimalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *HexadecimalLiteralContext {
+	var p = new(HexadecimalLiteralContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+
+	return p
+}
+
+func (s *HexadecimalLiteralContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *HexadecimalLiteralContext) HEX_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserHEX_LIT, 0)
+}
+
+func (s *HexadecimalLiteralContext) MINUS() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMINUS, 0)
+}
+
+func (s *HexadecimalLiteralContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *HexadecimalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *HexadecimalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.EnterHexadecimalLiteral(s)
+	}
+}
+
+func (s *HexadecimalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(grulev3Listener); ok {
+		listenerT.ExitHexadecimalLiteral(s)
+	}
+}
+
+func (s *HexadecimalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case grulev3Visitor:
+		return t.VisitHexadecimalLiteral(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *grulev3Parser) HexadecimalLiteral() (localctx IHexadecimalLiteralContext) {
+	localctx = NewHexadecimalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 96, grulev3ParserRULE_hexadecimalLiteral)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(502)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == grulev3ParserMINUS {
 		{
-			p.SetState(239)
-			p.HexadecimalLiteral()
+			p.SetState(501)
+			p.Match(grulev3ParserMINUS)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
 		}
 
-	case 3:
-		p.EnterOuterAlt(localctx, 3)
-		{
-			p.SetState(240)
-			p.OctalLiteral()
+	}
+	{
+		p.SetState(504)
+		p.Match(grulev3ParserHEX_LIT)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
 		}
-
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
 	}
 
 errorExit:
@@ -4984,98 +9646,98 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IDecimalLiteralContext is an interface to support dynamic dispatch.
-type IDecimalLiteralContext interface {
+// IOctalLiteralContext is an interface to support dynamic dispatch.
+type IOctalLiteralContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	DEC_LIT() antlr.TerminalNode
+	OCT_LIT() antlr.TerminalNode
 	MINUS() antlr.TerminalNode
 
-	// IsDecimalLiteralContext differentiates from other interfaces.
-	IsDecimalLiteralContext()
+	// IsOctalLiteralContext differentiates from other interfaces.
+	IsOctalLiteralContext()
 }
 
-type DecimalLiteralContext struct {
+type OctalLiteralContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyDecimalLiteralContext() *DecimalLiteralContext {
-	var p = new(DecimalLiteralContext)
+func NewEmptyOctalLiteralContext() *OctalLiteralContext {
+	var p = new(OctalLiteralContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+	p.RuleIndex = grulev3ParserRULE_octalLiteral
 	return p
 }
 
-func InitEmptyDecimalLiteralContext(p *DecimalLiteralContext) {
+func InitEmptyOctalLiteralContext(p *OctalLiteralContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+	p.RuleIndex = grulev3ParserRULE_octalLiteral
 }
 
-func (*DecimalLiteralContext) IsDecimalLiteralContext() {}
+func (*OctalLiteralContext) IsOctalLiteralContext() {}
 
-func NewDecimalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *DecimalLiteralContext {
-	var p = new(DecimalLiteralContext)
+func NewOctalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *OctalLiteralContext {
+	var p = new(OctalLiteralContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_decimalLiteral
+	p.RuleIndex = grulev3ParserRULE_octalLiteral
 
 	return p
 }
 
-func (s *DecimalLiteralContext) GetParser() antlr.Parser { return s.parser }
+func (s *OctalLiteralContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *DecimalLiteralContext) DEC_LIT() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserDEC_LIT, 0)
+func (s *OctalLiteralContext) OCT_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserOCT_LIT, 0)
 }
 
-func (s *DecimalLiteralContext) MINUS() antlr.TerminalNode {
+func (s *OctalLiteralContext) MINUS() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserMINUS, 0)
 }
 
-func (s *DecimalLiteralContext) GetRuleContext() antlr.RuleContext {
+func (s *OctalLiteralContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *DecimalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *OctalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *DecimalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *OctalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterDecimalLiteral(s)
+		listenerT.EnterOctalLiteral(s)
 	}
 }
 
-func (s *DecimalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *OctalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitDecimalLiteral(s)
+		listenerT.ExitOctalLiteral(s)
 	}
 }
 
-func (s *DecimalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *OctalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitDecimalLiteral(s)
+		return t.VisitOctalLiteral(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) DecimalLiteral() (localctx IDecimalLiteralContext) {
-	localctx = NewDecimalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 56, grulev3ParserRULE_decimalLiteral)
+func (p *grulev3Parser) OctalLiteral() (localctx IOctalLiteralContext) {
+	localctx = NewOctalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 98, grulev3ParserRULE_octalLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(244)
+	p.SetState(507)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -5084,7 +9746,7 @@ func (p *grulev3Parser) DecimalLiteral() (localctx IDecimalLiteralContext) {
 
 	if _la == grulev3ParserMINUS {
 		{
-			p.SetState(243)
+			p.SetState(506)
 			p.Match(grulev3ParserMINUS)
 			if p.HasError() {
 				// Recognition error - abort rule
@@ -5094,8 +9756,8 @@ func (p *grulev3Parser) DecimalLiteral() (localctx IDecimalLiteralContext) {
 
 	}
 	{
-		p.SetState(246)
-		p.Match(grulev3ParserDEC_LIT)
+		p.SetState(509)
+		p.Match(grulev3ParserOCT_LIT)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
@@ -5115,122 +9777,214 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IHexadecimalLiteralContext is an interface to support dynamic dispatch.
-type IHexadecimalLiteralContext interface {
+// IQuantityLiteralContext is an interface to support dynamic dispatch.
+type IQuantityLiteralContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	HEX_LIT() antlr.TerminalNode
+	QUANTITY_LIT() antlr.TerminalNode
 	MINUS() antlr.TerminalNode
+	MOD() antlr.TerminalNode
+	SIMPLENAME() antlr.TerminalNode
+	DecimalLiteral() IDecimalLiteralContext
+	DecimalFloatLiteral() IDecimalFloatLiteralContext
 
-	// IsHexadecimalLiteralContext differentiates from other interfaces.
-	IsHexadecimalLiteralContext()
+	// IsQuantityLiteralContext differentiates from other interfaces.
+	IsQuantityLiteralContext()
 }
 
-type HexadecimalLiteralContext struct {
+type QuantityLiteralContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyHexadecimalLiteralContext() *HexadecimalLiteralContext {
-	var p = new(HexadecimalLiteralContext)
+func NewEmptyQuantityLiteralContext() *QuantityLiteralContext {
+	var p = new(QuantityLiteralContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+	p.RuleIndex = grulev3ParserRULE_quantityLiteral
 	return p
 }
 
-func InitEmptyHexadecimalLiteralContext(p *HexadecimalLiteralContext) {
+func InitEmptyQuantityLiteralContext(p *QuantityLiteralContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+	p.RuleIndex = grulev3ParserRULE_quantityLiteral
 }
 
-func (*HexadecimalLiteralContext) IsHexadecimalLiteralContext() {}
+func (*QuantityLiteralContext) IsQuantityLiteralContext() {}
 
-func NewHexadecimalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *HexadecimalLiteralContext {
-	var p = new(HexadecimalLiteralContext)
+func NewQuantityLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *QuantityLiteralContext {
+	var p = new(QuantityLiteralContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_hexadecimalLiteral
+	p.RuleIndex = grulev3ParserRULE_quantityLiteral
 
 	return p
 }
 
-func (s *HexadecimalLiteralContext) GetParser() antlr.Parser { return s.parser }
+func (s *QuantityLiteralContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *HexadecimalLiteralContext) HEX_LIT() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserHEX_LIT, 0)
+func (s *QuantityLiteralContext) QUANTITY_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserQUANTITY_LIT, 0)
 }
 
-func (s *HexadecimalLiteralContext) MINUS() antlr.TerminalNode {
+func (s *QuantityLiteralContext) MINUS() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserMINUS, 0)
 }
 
-func (s *HexadecimalLiteralContext) GetRuleContext() antlr.RuleContext {
+func (s *QuantityLiteralContext) MOD() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserMOD, 0)
+}
+
+func (s *QuantityLiteralContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSIMPLENAME, 0)
+}
+
+func (s *QuantityLiteralContext) DecimalLiteral() IDecimalLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IDecimalLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IDecimalLiteralContext)
+}
+
+func (s *QuantityLiteralContext) DecimalFloatLiteral() IDecimalFloatLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IDecimalFloatLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IDecimalFloatLiteralContext)
+}
+
+func (s *QuantityLiteralContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *HexadecimalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *QuantityLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *HexadecimalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *QuantityLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterHexadecimalLiteral(s)
+		listenerT.EnterQuantityLiteral(s)
 	}
 }
 
-func (s *HexadecimalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *QuantityLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitHexadecimalLiteral(s)
+		listenerT.ExitQuantityLiteral(s)
 	}
 }
 
-func (s *HexadecimalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *QuantityLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitHexadecimalLiteral(s)
+		return t.VisitQuantityLiteral(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) HexadecimalLiteral() (localctx IHexadecimalLiteralContext) {
-	localctx = NewHexadecimalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 58, grulev3ParserRULE_hexadecimalLiteral)
+func (p *grulev3Parser) QuantityLiteral() (localctx IQuantityLiteralContext) {
+	localctx = NewQuantityLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 100, grulev3ParserRULE_quantityLiteral)
 	var _la int
 
-	p.EnterOuterAlt(localctx, 1)
-	p.SetState(249)
+	p.SetState(521)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
 	}
-	_la = p.GetTokenStream().LA(1)
 
-	if _la == grulev3ParserMINUS {
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 57, p.GetParserRuleContext()) {
+	case 1:
+		p.EnterOuterAlt(localctx, 1)
+		p.SetState(512)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if _la == grulev3ParserMINUS {
+			{
+				p.SetState(511)
+				p.Match(grulev3ParserMINUS)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+
+		}
 		{
-			p.SetState(248)
-			p.Match(grulev3ParserMINUS)
+			p.SetState(514)
+			p.Match(grulev3ParserQUANTITY_LIT)
 			if p.HasError() {
 				// Recognition error - abort rule
 				goto errorExit
 			}
 		}
 
-	}
-	{
-		p.SetState(251)
-		p.Match(grulev3ParserHEX_LIT)
+	case 2:
+		p.EnterOuterAlt(localctx, 2)
+		p.SetState(517)
+		p.GetErrorHandler().Sync(p)
 		if p.HasError() {
-			// Recognition error - abort rule
 			goto errorExit
 		}
+
+		switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 56, p.GetParserRuleContext()) {
+		case 1:
+			{
+				p.SetState(515)
+				p.DecimalLiteral()
+			}
+
+		case 2:
+			{
+				p.SetState(516)
+				p.DecimalFloatLiteral()
+			}
+
+		case antlr.ATNInvalidAltNumber:
+			goto errorExit
+		}
+		{
+			p.SetState(519)
+			_la = p.GetTokenStream().LA(1)
+
+			if !(_la == grulev3ParserMOD || _la == grulev3ParserSIMPLENAME) {
+				p.GetErrorHandler().RecoverInline(p)
+			} else {
+				p.GetErrorHandler().ReportMatch(p)
+				p.Consume()
+			}
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
 	}
 
 errorExit:
@@ -5246,98 +10000,98 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
-// IOctalLiteralContext is an interface to support dynamic dispatch.
-type IOctalLiteralContext interface {
+// ISuffixLiteralContext is an interface to support dynamic dispatch.
+type ISuffixLiteralContext interface {
 	antlr.ParserRuleContext
 
 	// GetParser returns the parser.
 	GetParser() antlr.Parser
 
 	// Getter signatures
-	OCT_LIT() antlr.TerminalNode
+	SUFFIX_LIT() antlr.TerminalNode
 	MINUS() antlr.TerminalNode
 
-	// IsOctalLiteralContext differentiates from other interfaces.
-	IsOctalLiteralContext()
+	// IsSuffixLiteralContext differentiates from other interfaces.
+	IsSuffixLiteralContext()
 }
 
-type OctalLiteralContext struct {
+type SuffixLiteralContext struct {
 	antlr.BaseParserRuleContext
 	parser antlr.Parser
 }
 
-func NewEmptyOctalLiteralContext() *OctalLiteralContext {
-	var p = new(OctalLiteralContext)
+func NewEmptySuffixLiteralContext() *SuffixLiteralContext {
+	var p = new(SuffixLiteralContext)
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_octalLiteral
+	p.RuleIndex = grulev3ParserRULE_suffixLiteral
 	return p
 }
 
-func InitEmptyOctalLiteralContext(p *OctalLiteralContext) {
+func InitEmptySuffixLiteralContext(p *SuffixLiteralContext) {
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
-	p.RuleIndex = grulev3ParserRULE_octalLiteral
+	p.RuleIndex = grulev3ParserRULE_suffixLiteral
 }
 
-func (*OctalLiteralContext) IsOctalLiteralContext() {}
+func (*SuffixLiteralContext) IsSuffixLiteralContext() {}
 
-func NewOctalLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *OctalLiteralContext {
-	var p = new(OctalLiteralContext)
+func NewSuffixLiteralContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *SuffixLiteralContext {
+	var p = new(SuffixLiteralContext)
 
 	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
 
 	p.parser = parser
-	p.RuleIndex = grulev3ParserRULE_octalLiteral
+	p.RuleIndex = grulev3ParserRULE_suffixLiteral
 
 	return p
 }
 
-func (s *OctalLiteralContext) GetParser() antlr.Parser { return s.parser }
+func (s *SuffixLiteralContext) GetParser() antlr.Parser { return s.parser }
 
-func (s *OctalLiteralContext) OCT_LIT() antlr.TerminalNode {
-	return s.GetToken(grulev3ParserOCT_LIT, 0)
+func (s *SuffixLiteralContext) SUFFIX_LIT() antlr.TerminalNode {
+	return s.GetToken(grulev3ParserSUFFIX_LIT, 0)
 }
 
-func (s *OctalLiteralContext) MINUS() antlr.TerminalNode {
+func (s *SuffixLiteralContext) MINUS() antlr.TerminalNode {
 	return s.GetToken(grulev3ParserMINUS, 0)
 }
 
-func (s *OctalLiteralContext) GetRuleContext() antlr.RuleContext {
+func (s *SuffixLiteralContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
-func (s *OctalLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+func (s *SuffixLiteralContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
 	return antlr.TreesStringTree(s, ruleNames, recog)
 }
 
-func (s *OctalLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
+func (s *SuffixLiteralContext) EnterRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.EnterOctalLiteral(s)
+		listenerT.EnterSuffixLiteral(s)
 	}
 }
 
-func (s *OctalLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
+func (s *SuffixLiteralContext) ExitRule(listener antlr.ParseTreeListener) {
 	if listenerT, ok := listener.(grulev3Listener); ok {
-		listenerT.ExitOctalLiteral(s)
+		listenerT.ExitSuffixLiteral(s)
 	}
 }
 
-func (s *OctalLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+func (s *SuffixLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	switch t := visitor.(type) {
 	case grulev3Visitor:
-		return t.VisitOctalLiteral(s)
+		return t.VisitSuffixLiteral(s)
 
 	default:
 		return t.VisitChildren(s)
 	}
 }
 
-func (p *grulev3Parser) OctalLiteral() (localctx IOctalLiteralContext) {
-	localctx = NewOctalLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 60, grulev3ParserRULE_octalLiteral)
+func (p *grulev3Parser) SuffixLiteral() (localctx ISuffixLiteralContext) {
+	localctx = NewSuffixLiteralContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 102, grulev3ParserRULE_suffixLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(254)
+	p.SetState(524)
 	p.GetErrorHandler().Sync(p)
 	if p.HasError() {
 		goto errorExit
@@ -5346,7 +10100,7 @@ func (p *grulev3Parser) OctalLiteral() (localctx IOctalLiteralContext) {
 
 	if _la == grulev3ParserMINUS {
 		{
-			p.SetState(253)
+			p.SetState(523)
 			p.Match(grulev3ParserMINUS)
 			if p.HasError() {
 				// Recognition error - abort rule
@@ -5356,8 +10110,8 @@ func (p *grulev3Parser) OctalLiteral() (localctx IOctalLiteralContext) {
 
 	}
 	{
-		p.SetState(256)
-		p.Match(grulev3ParserOCT_LIT)
+		p.SetState(526)
+		p.Match(grulev3ParserSUFFIX_LIT)
 		if p.HasError() {
 			// Recognition error - abort rule
 			goto errorExit
@@ -5464,12 +10218,12 @@ func (s *StringLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{
 
 func (p *grulev3Parser) StringLiteral() (localctx IStringLiteralContext) {
 	localctx = NewStringLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 62, grulev3ParserRULE_stringLiteral)
+	p.EnterRule(localctx, 104, grulev3ParserRULE_stringLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(258)
+		p.SetState(528)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == grulev3ParserDQUOTA_STRING || _la == grulev3ParserSQUOTA_STRING) {
@@ -5580,12 +10334,12 @@ func (s *BooleanLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface
 
 func (p *grulev3Parser) BooleanLiteral() (localctx IBooleanLiteralContext) {
 	localctx = NewBooleanLiteralContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 64, grulev3ParserRULE_booleanLiteral)
+	p.EnterRule(localctx, 106, grulev3ParserRULE_booleanLiteral)
 	var _la int
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(260)
+		p.SetState(530)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == grulev3ParserTRUE || _la == grulev3ParserFALSE) {
@@ -5611,21 +10365,21 @@ errorExit:
 
 func (p *grulev3Parser) Sempred(localctx antlr.RuleContext, ruleIndex, predIndex int) bool {
 	switch ruleIndex {
-	case 10:
+	case 26:
 		var t *ExpressionContext = nil
 		if localctx != nil {
 			t = localctx.(*ExpressionContext)
 		}
 		return p.Expression_Sempred(t, predIndex)
 
-	case 16:
+	case 32:
 		var t *ExpressionAtomContext = nil
 		if localctx != nil {
 			t = localctx.(*ExpressionAtomContext)
 		}
 		return p.ExpressionAtom_Sempred(t, predIndex)
 
-	case 18:
+	case 36:
 		var t *VariableContext = nil
 		if localctx != nil {
 			t = localctx.(*VariableContext)
@@ -5640,18 +10394,21 @@ func (p *grulev3Parser) Sempred(localctx antlr.RuleContext, ruleIndex, predIndex
 func (p *grulev3Parser) Expression_Sempred(localctx antlr.RuleContext, predIndex int) bool {
 	switch predIndex {
 	case 0:
-		return p.Precpred(p.GetParserRuleContext(), 7)
+		return p.Precpred(p.GetParserRuleContext(), 8)
 
 	case 1:
-		return p.Precpred(p.GetParserRuleContext(), 6)
+		return p.Precpred(p.GetParserRuleContext(), 7)
 
 	case 2:
-		return p.Precpred(p.GetParserRuleContext(), 5)
+		return p.Precpred(p.GetParserRuleContext(), 6)
 
 	case 3:
-		return p.Precpred(p.GetParserRuleContext(), 4)
+		return p.Precpred(p.GetParserRuleContext(), 5)
 
 	case 4:
+		return p.Precpred(p.GetParserRuleContext(), 4)
+
+	case 5:
 		return p.Precpred(p.GetParserRuleContext(), 3)
 
 	default:
@@ -5661,13 +10418,13 @@ func (p *grulev3Parser) Expression_Sempred(localctx antlr.RuleContext, predIndex
 
 func (p *grulev3Parser) ExpressionAtom_Sempred(localctx antlr.RuleContext, predIndex int) bool {
 	switch predIndex {
-	case 5:
+	case 6:
 		return p.Precpred(p.GetParserRuleContext(), 4)
 
-	case 6:
+	case 7:
 		return p.Precpred(p.GetParserRuleContext(), 3)
 
-	case 7:
+	case 8:
 		return p.Precpred(p.GetParserRuleContext(), 2)
 
 	default:
@@ -5677,11 +10434,11 @@ func (p *grulev3Parser) ExpressionAtom_Sempred(localctx antlr.RuleContext, predI
 
 func (p *grulev3Parser) Variable_Sempred(localctx antlr.RuleContext, predIndex int) bool {
 	switch predIndex {
-	case 8:
-		return p.Precpred(p.GetParserRuleContext(), 3)
-
 	case 9:
-		return p.Precpred(p.GetParserRuleContext(), 2)
+		return p.Precpred(p.GetParserRuleContext(), 4)
+
+	case 10:
+		return p.Precpred(p.GetParserRuleContext(), 3)
 
 	default:
 		panic("No predicate with index: " + fmt.Sprint(predIndex))
@@ -13,30 +13,78 @@ type grulev3Visitor interface {
 	// Visit a parse tree produced by grulev3Parser#ruleEntry.
 	VisitRuleEntry(ctx *RuleEntryContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#ruleAnnotation.
+	VisitRuleAnnotation(ctx *RuleAnnotationContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#testEntry.
+	VisitTestEntry(ctx *TestEntryContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#haltEntry.
+	VisitHaltEntry(ctx *HaltEntryContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#givenScope.
+	VisitGivenScope(ctx *GivenScopeContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#expectScope.
+	VisitExpectScope(ctx *ExpectScopeContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#salience.
 	VisitSalience(ctx *SalienceContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#maxFires.
+	VisitMaxFires(ctx *MaxFiresContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#cooldown.
+	VisitCooldown(ctx *CooldownContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#criticality.
+	VisitCriticality(ctx *CriticalityContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#ruleName.
 	VisitRuleName(ctx *RuleNameContext) interface{}
 
 	// Visit a parse tree produced by grulev3Parser#ruleDescription.
 	VisitRuleDescription(ctx *RuleDescriptionContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#ruleId.
+	VisitRuleId(ctx *RuleIdContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#whenScope.
 	VisitWhenScope(ctx *WhenScopeContext) interface{}
 
 	// Visit a parse tree produced by grulev3Parser#thenScope.
 	VisitThenScope(ctx *ThenScopeContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#elseScope.
+	VisitElseScope(ctx *ElseScopeContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#scriptBlock.
+	VisitScriptBlock(ctx *ScriptBlockContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#thenExpressionList.
 	VisitThenExpressionList(ctx *ThenExpressionListContext) interface{}
 
 	// Visit a parse tree produced by grulev3Parser#thenExpression.
 	VisitThenExpression(ctx *ThenExpressionContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#collectStatement.
+	VisitCollectStatement(ctx *CollectStatementContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#switchStatement.
+	VisitSwitchStatement(ctx *SwitchStatementContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#switchCase.
+	VisitSwitchCase(ctx *SwitchCaseContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#assignment.
 	VisitAssignment(ctx *AssignmentContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#matchExpression.
+	VisitMatchExpression(ctx *MatchExpressionContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#matchArm.
+	VisitMatchArm(ctx *MatchArmContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#expression.
 	VisitExpression(ctx *ExpressionContext) interface{}
 
@@ -61,6 +109,12 @@ type grulev3Visitor interface {
 	// Visit a parse tree produced by grulev3Parser#constant.
 	VisitConstant(ctx *ConstantContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#collectionLiteral.
+	VisitCollectionLiteral(ctx *CollectionLiteralContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#collectionElement.
+	VisitCollectionElement(ctx *CollectionElementContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#variable.
 	VisitVariable(ctx *VariableContext) interface{}
 
@@ -70,6 +124,9 @@ type grulev3Visitor interface {
 	// Visit a parse tree produced by grulev3Parser#memberVariable.
 	VisitMemberVariable(ctx *MemberVariableContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#quantifier.
+	VisitQuantifier(ctx *QuantifierContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#functionCall.
 	VisitFunctionCall(ctx *FunctionCallContext) interface{}
 
@@ -100,6 +157,12 @@ type grulev3Visitor interface {
 	// Visit a parse tree produced by grulev3Parser#octalLiteral.
 	VisitOctalLiteral(ctx *OctalLiteralContext) interface{}
 
+	// Visit a parse tree produced by grulev3Parser#quantityLiteral.
+	VisitQuantityLiteral(ctx *QuantityLiteralContext) interface{}
+
+	// Visit a parse tree produced by grulev3Parser#suffixLiteral.
+	VisitSuffixLiteral(ctx *SuffixLiteralContext) interface{}
+
 	// Visit a parse tree produced by grulev3Parser#stringLiteral.
 	VisitStringLiteral(ctx *StringLiteralContext) interface{}
 
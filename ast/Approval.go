@@ -0,0 +1,38 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+// NewApproval create new Approval AST object
+func NewApproval() *Approval {
+
+	return &Approval{
+		Categories: make([]string, 0),
+	}
+}
+
+// Approval is a simple AST object that stores the approval categories of a rule, such as "large-refund"
+type Approval struct {
+	Categories []string
+}
+
+// ApprovalReceiver must be implemented by any AST object that stores rule approval categories
+type ApprovalReceiver interface {
+	AcceptApproval(approval *Approval) error
+}
+
+// AcceptStringLiteral accept the assigned string
+func (a *Approval) AcceptStringLiteral(lit *StringLiteral) {
+	a.Categories = append(a.Categories, lit.String)
+}
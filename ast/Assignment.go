@@ -18,6 +18,7 @@ import (
 	"errors"
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"reflect"
 	"strings"
 )
 
@@ -35,6 +36,7 @@ type Assignment struct {
 
 	Variable      *Variable
 	Expression    *Expression
+	Match         *MatchExpression
 	IsAssign      bool
 	IsPlusAssign  bool
 	IsMinusAssign bool
@@ -60,6 +62,10 @@ func (e *Assignment) MakeCatalog(cat *Catalog) {
 			meta.ExpressionID = e.Expression.AstID
 			e.Expression.MakeCatalog(cat)
 		}
+		if e.Match != nil {
+			meta.MatchExpressionID = e.Match.AstID
+			e.Match.MakeCatalog(cat)
+		}
 		meta.IsAssign = e.IsAssign
 		meta.IsPlusAssign = e.IsPlusAssign
 		meta.IsMinusAssign = e.IsMinusAssign
@@ -97,6 +103,15 @@ func (e *Assignment) Clone(cloneTable *pkg.CloneTable) *Assignment {
 			cloneTable.MarkCloned(e.Expression.AstID, cloned.AstID, e.Expression, cloned)
 		}
 	}
+	if e.Match != nil {
+		if cloneTable.IsCloned(e.Match.AstID) {
+			clone.Match = cloneTable.Records[e.Match.AstID].CloneInstance.(*MatchExpression)
+		} else {
+			cloned := e.Match.Clone(cloneTable)
+			clone.Match = cloned
+			cloneTable.MarkCloned(e.Match.AstID, cloned.AstID, e.Match, cloned)
+		}
+	}
 	clone.IsAssign = e.IsAssign
 	clone.IsDivAssign = e.IsDivAssign
 	clone.IsMinusAssign = e.IsMinusAssign
@@ -117,6 +132,17 @@ func (e *Assignment) AcceptExpression(exp *Expression) error {
 	return nil
 }
 
+// AcceptMatchExpression will accept a MatchExpression AST graph into this ast graph
+func (e *Assignment) AcceptMatchExpression(match *MatchExpression) error {
+	if e.Match != nil {
+
+		return errors.New("match expression for assignment already assigned")
+	}
+	e.Match = match
+
+	return nil
+}
+
 // AcceptVariable will accept an Variable AST graph into this ast graph
 func (e *Assignment) AcceptVariable(vari *Variable) error {
 	if e.Variable != nil {
@@ -160,7 +186,11 @@ func (e *Assignment) GetSnapshot() string {
 	if e.IsPlusAssign {
 		buff.WriteString("+=")
 	}
-	buff.WriteString(e.Expression.GetSnapshot())
+	if e.Match != nil {
+		buff.WriteString(e.Match.GetSnapshot())
+	} else {
+		buff.WriteString(e.Expression.GetSnapshot())
+	}
 	buff.WriteString(")")
 
 	return buff.String()
@@ -174,7 +204,13 @@ func (e *Assignment) SetGrlText(grlText string) {
 
 // Execute will execute this graph in the Then scope
 func (e *Assignment) Execute(dataContext IDataContext, memory *WorkingMemory) error {
-	exprVal, err := e.Expression.Evaluate(dataContext, memory)
+	var exprVal reflect.Value
+	var err error
+	if e.Match != nil {
+		exprVal, err = e.Match.Evaluate(dataContext, memory)
+	} else {
+		exprVal, err = e.Expression.Evaluate(dataContext, memory)
+	}
 	if err != nil {
 		return err
 	}
@@ -16,9 +16,6 @@ package ast
 
 import (
 	"github.com/hyperjumptech/grule-rule-engine/logger"
-	"github.com/rs/zerolog"
-	"github.com/sirupsen/logrus"
-	"go.uber.org/zap"
 )
 
 const (
@@ -34,6 +31,16 @@ const (
 	EXPRESSION = "E"
 	// EXPRESSIONATOM signature for expression atom snapshot
 	EXPRESSIONATOM = "A"
+	// MATCHEXPRESSION signature for match expression snapshot
+	MATCHEXPRESSION = "ME"
+	// COLLECTSTATEMENT signature for collect statement snapshot
+	COLLECTSTATEMENT = "CS"
+	// SWITCHSTATEMENT signature for switch statement snapshot
+	SWITCHSTATEMENT = "SW"
+	// QUANTIFIER signature for quantifier snapshot
+	QUANTIFIER = "Q"
+	// HALTENTRY signature for halt entry snapshot
+	HALTENTRY = "H"
 	// FUNCTIONCALL signature for function call snapshot
 	FUNCTIONCALL = "F"
 	// RULEENTRY signature for rule entry snapshot
@@ -62,30 +69,8 @@ var (
 
 // SetLogger changes default logger on external
 func SetLogger(log interface{}) {
-	var entry logger.LogEntry
-
-	switch log.(type) {
-	case *zap.Logger:
-		log, ok := log.(*zap.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewZap(log)
-	case *logrus.Logger:
-		log, ok := log.(*logrus.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewLogrus(log)
-	case *zerolog.Logger:
-		log, ok := log.(*zerolog.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewZero(log)
-	default:
+	entry, ok := logger.FromExternal(log)
+	if !ok {
 
 		return
 	}
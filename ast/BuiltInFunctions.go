@@ -15,6 +15,8 @@
 package ast
 
 import (
+	"fmt"
+	"io"
 	"math"
 	"reflect"
 	"slices"
@@ -22,6 +24,7 @@ import (
 	"time"
 
 	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/model"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
 )
 
@@ -41,6 +44,48 @@ type BuiltInFunctions struct {
 	Knowledge     *KnowledgeBase
 	WorkingMemory *WorkingMemory
 	DataContext   IDataContext
+	Outcomes      OutcomeRecorder
+	Outputs       Emitter
+	// Scratchpad is the value node of the per-execution scratchpad the engine added into the data context, if any.
+	Scratchpad model.ValueNode
+	// Templates renders the templates of Template, if any.
+	Templates TemplateExecutor
+	// Calls observes the functions and methods called by the rules, if any.
+	Calls CallObserver
+	// DateNames are the names FormatDate and ParseDate use, those of RegisterDateNames if nil.
+	DateNames *DateNamesRegistry
+}
+
+// OutcomeRecorder receives the decision outcomes recorded by rules using RecordOutcome.
+type OutcomeRecorder interface {
+	RecordOutcome(knowledgeBase, version, ruleID, ruleName, label string)
+}
+
+// Emitter receives the messages emitted by rules using Emit.
+type Emitter interface {
+	Emit(message interface{})
+}
+
+// SinkEmitter is an Emitter that also receives the messages emitted by rules annotated with @sink, together with
+// the names of the sinks they are routed to.
+type SinkEmitter interface {
+	EmitToSinks(sinks []string, message interface{})
+}
+
+// TemplateExecutor renders the templates called by rules using Template, such as a text/template or
+// html/template Template.
+type TemplateExecutor interface {
+	ExecuteTemplate(writer io.Writer, name string, data interface{}) error
+}
+
+// grlLog returns the logger of the GRL, GrlLogger unless the working memory has a logger of its own.
+func (gf *BuiltInFunctions) grlLog() logger.LogEntry {
+	if gf.WorkingMemory == nil {
+
+		return GrlLogger
+	}
+
+	return gf.WorkingMemory.Logger.Or(GrlLogger)
 }
 
 // Complete will cause the engine to stop processing further rules in the current cycle.
@@ -78,7 +123,7 @@ func (gf *BuiltInFunctions) Now() time.Time {
 
 // Log extension to log.Print
 func (gf *BuiltInFunctions) Log(text string) {
-	GrlLogger.Println(text)
+	gf.grlLog().Println(text)
 }
 
 // StringContains extension to strings.Contains
@@ -89,7 +134,7 @@ func (gf *BuiltInFunctions) StringContains(str, substr string) bool {
 
 // LogFormat extension to log.Printf
 func (gf *BuiltInFunctions) LogFormat(format string, i interface{}) {
-	GrlLogger.Printf(format, i)
+	gf.grlLog().Printf(format, i)
 }
 
 // IsNil Enables nill checking on variables.
@@ -141,6 +186,59 @@ func (gf *BuiltInFunctions) Retract(ruleName string) {
 	gf.Knowledge.RetractRule(ruleName)
 }
 
+// RecordOutcome will count a decision outcome label, such as "approved" or "declined", for the executing rule
+// and knowledge base version. It should be called from the then scope, the counters are kept by the engine.
+func (gf *BuiltInFunctions) RecordOutcome(label string) {
+	if gf.Outcomes == nil {
+		gf.grlLog().Warnf("RecordOutcome(\"%s\") called while no outcome recorder is set, outcome is ignored", label)
+
+		return
+	}
+	ruleID, ruleName := "", ""
+	if entry := gf.DataContext.GetRuleEntry(); entry != nil {
+		ruleID, ruleName = entry.RuleID, entry.RuleName
+	}
+	gf.Outcomes.RecordOutcome(gf.Knowledge.Name, gf.Knowledge.Version, ruleID, ruleName, label)
+}
+
+// Emit will send a message, such as an event or a command built by a fact function, out of the execution.
+// The messages are collected by the engine by their type, so the rules do not have to encode their results into facts.
+// The messages of a rule annotated with @sink are also routed to its sinks.
+func (gf *BuiltInFunctions) Emit(message interface{}) {
+	if gf.Outputs == nil {
+		gf.grlLog().Warnf("Emit(%v) called while no outputs are set, message is ignored", message)
+
+		return
+	}
+	if message == nil {
+		gf.grlLog().Warnf("Emit called with nil, message is ignored")
+
+		return
+	}
+	if entry := gf.DataContext.GetRuleEntry(); entry != nil && len(entry.Sinks) > 0 {
+		if sinkEmitter, ok := gf.Outputs.(SinkEmitter); ok {
+			sinkEmitter.EmitToSinks(entry.Sinks, message)
+
+			return
+		}
+	}
+	gf.Outputs.Emit(message)
+}
+
+// Template will render the named template with the data, such as a fact, and return the rendered text.
+// The templates are those of the engine. A missing template or a failed rendering fails the rule.
+func (gf *BuiltInFunctions) Template(name string, data interface{}) string {
+	if gf.Templates == nil {
+		panic(fmt.Sprintf("Template(\"%s\") called while no templates are set", name))
+	}
+	var buffer strings.Builder
+	if err := gf.Templates.ExecuteTemplate(&buffer, name, data); err != nil {
+		panic(err)
+	}
+
+	return buffer.String()
+}
+
 // GetTimeYear will get the year value of time
 func (gf *BuiltInFunctions) GetTimeYear(time time.Time) int {
 
@@ -543,3 +641,53 @@ func (gf *BuiltInFunctions) Trunc(x float64) float64 {
 func (gf *BuiltInFunctions) ContainsStr(s []string, v string) bool {
 	return slices.Contains(s, v)
 }
+
+// ToInt casts the value into an int64 with model.CastToInt. A value that can not be cast fails the rule.
+func (gf *BuiltInFunctions) ToInt(value interface{}) int64 {
+	i, err := model.CastToInt(reflect.ValueOf(value))
+	if err != nil {
+		panic(err)
+	}
+
+	return i
+}
+
+// ToUint casts the value into an uint64 with model.CastToUint. A value that can not be cast fails the rule.
+func (gf *BuiltInFunctions) ToUint(value interface{}) uint64 {
+	i, err := model.CastToUint(reflect.ValueOf(value))
+	if err != nil {
+		panic(err)
+	}
+
+	return i
+}
+
+// ToFloat casts the value into a float64 with model.CastToFloat. A value that can not be cast fails the rule.
+func (gf *BuiltInFunctions) ToFloat(value interface{}) float64 {
+	f, err := model.CastToFloat(reflect.ValueOf(value))
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+// ToString casts the value into a string with model.CastToString. A value that can not be cast fails the rule.
+func (gf *BuiltInFunctions) ToString(value interface{}) string {
+	s, err := model.CastToString(reflect.ValueOf(value))
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// ToBool casts the value into a bool with model.CastToBool. A value that can not be cast fails the rule.
+func (gf *BuiltInFunctions) ToBool(value interface{}) bool {
+	b, err := model.CastToBool(reflect.ValueOf(value))
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
@@ -0,0 +1,68 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// ArgumentValidator is implemented by the facts whose functions are called by rules, such as a pack of scoring
+// functions added into the data context, to check the arguments before every call. The call fails with the
+// error ValidateArguments returns, the function is not called.
+type ArgumentValidator interface {
+	ValidateArguments(function string, args []reflect.Value) error
+}
+
+// CallObserver receives every function and method called by rules, with the values of the arguments and the
+// result. Methods are named by the GRL text of their receiver followed by the method name, such as "User.Score".
+type CallObserver interface {
+	ObserveCall(function string, args []reflect.Value, result reflect.Value, err error)
+}
+
+// callFunction calls the function of the receiver once the receiver, if it is an ArgumentValidator, accepted the
+// arguments. The call is reported to the observer of the built-in functions, if any.
+func callFunction(dataContext IDataContext, receiver model.ValueNode, name, function string, args []reflect.Value) (reflect.Value, error) {
+	var result reflect.Value
+	var err error
+	if validator, ok := valueInterface(receiver.Value()).(ArgumentValidator); ok {
+		err = validator.ValidateArguments(function, args)
+		if err != nil {
+			err = fmt.Errorf("invalid arguments for %s. got %w", name, err)
+		}
+	}
+	if err == nil {
+		result, err = receiver.CallFunction(function, args...)
+	}
+	if defunc := dataContext.Get("DEFUNC"); defunc != nil {
+		if builtIn, ok := valueInterface(defunc.Value()).(*BuiltInFunctions); ok && builtIn.Calls != nil {
+			builtIn.Calls.ObserveCall(name, args, result, err)
+		}
+	}
+
+	return result, err
+}
+
+// valueInterface returns the interface of the value, nil if it has none.
+func valueInterface(value reflect.Value) interface{} {
+	if !value.IsValid() || !value.CanInterface() {
+
+		return nil
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,310 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// collectionNil tags the nil elements of a collection, the other elements are tagged with their ValueType.
+const collectionNil = 0xFF
+
+// writeCollection writes the elements of a constant Set or OrderedMap into the value bytes of its meta.
+// The number of elements comes first, followed by every element, or every key and its value, tagged with its type.
+func writeCollection(buff *bytes.Buffer, collection interface{}) {
+	word := make([]byte, 8)
+	switch col := collection.(type) {
+	case pkg.Set:
+		values := col.Values()
+		binary.LittleEndian.PutUint64(word, uint64(len(values)))
+		buff.Write(word)
+		for _, value := range values {
+			writeCollectionElement(buff, value)
+		}
+	case pkg.OrderedMap:
+		keys := col.Keys()
+		binary.LittleEndian.PutUint64(word, uint64(len(keys)))
+		buff.Write(word)
+		for _, key := range keys {
+			writeCollectionElement(buff, key)
+			writeCollectionElement(buff, col.Get(key))
+		}
+	}
+}
+
+// writeCollectionElement writes one tagged element. Collections only hold the values of GRL constants,
+// anything else is written as nil.
+func writeCollectionElement(buff *bytes.Buffer, value interface{}) {
+	word := make([]byte, 8)
+	switch val := value.(type) {
+	case string:
+		buff.WriteByte(byte(TypeString))
+		binary.LittleEndian.PutUint64(word, uint64(len(val)))
+		buff.Write(word)
+		buff.WriteString(val)
+	case int64:
+		buff.WriteByte(byte(TypeInteger))
+		binary.LittleEndian.PutUint64(word, uint64(val))
+		buff.Write(word)
+	case float64:
+		buff.WriteByte(byte(TypeFloat))
+		binary.LittleEndian.PutUint64(word, math.Float64bits(val))
+		buff.Write(word)
+	case bool:
+		buff.WriteByte(byte(TypeBoolean))
+		if val {
+			buff.WriteByte(1)
+		} else {
+			buff.WriteByte(0)
+		}
+	case pkg.Quantity:
+		buff.WriteByte(byte(TypeQuantity))
+		binary.LittleEndian.PutUint64(word, math.Float64bits(val.Value))
+		buff.Write(word)
+		binary.LittleEndian.PutUint64(word, uint64(len(val.Unit)))
+		buff.Write(word)
+		buff.WriteString(val.Unit)
+	case pkg.Set:
+		buff.WriteByte(byte(TypeSet))
+		writeCollection(buff, val)
+	case pkg.OrderedMap:
+		buff.WriteByte(byte(TypeOrderedMap))
+		writeCollection(buff, val)
+	default:
+		buff.WriteByte(collectionNil)
+	}
+}
+
+// readCollection reads a Set or an OrderedMap as written by writeCollection.
+func readCollection(reader io.Reader, valueType ValueType) (interface{}, error) {
+	count, err := readCollectionWord(reader)
+	if err != nil {
+
+		return nil, err
+	}
+	elements := make([]interface{}, 0)
+	width := uint64(1)
+	if valueType == TypeOrderedMap {
+		width = 2
+	}
+	for index := uint64(0); index < count*width; index++ {
+		element, err := readCollectionElement(reader)
+		if err != nil {
+
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	if valueType == TypeOrderedMap {
+
+		return pkg.NewOrderedMap(elements...)
+	}
+
+	return pkg.NewSet(elements...)
+}
+
+func readCollectionElement(reader io.Reader) (interface{}, error) {
+	tag := make([]byte, 1)
+	_, err := io.ReadFull(reader, tag)
+	if err != nil {
+
+		return nil, err
+	}
+	switch ValueType(tag[0]) {
+	case TypeString:
+		length, err := readCollectionWord(reader)
+		if err != nil {
+
+			return nil, err
+		}
+		data := make([]byte, length)
+		_, err = io.ReadFull(reader, data)
+
+		return string(data), err
+	case TypeInteger:
+		word, err := readCollectionWord(reader)
+
+		return int64(word), err
+	case TypeFloat:
+		word, err := readCollectionWord(reader)
+
+		return math.Float64frombits(word), err
+	case TypeBoolean:
+		_, err := io.ReadFull(reader, tag)
+
+		return tag[0] == 1, err
+	case TypeQuantity:
+		word, err := readCollectionWord(reader)
+		if err != nil {
+
+			return nil, err
+		}
+		length, err := readCollectionWord(reader)
+		if err != nil {
+
+			return nil, err
+		}
+		unit := make([]byte, length)
+		_, err = io.ReadFull(reader, unit)
+
+		return pkg.Quantity{Value: math.Float64frombits(word), Unit: string(unit)}, err
+	case TypeSet, TypeOrderedMap:
+
+		return readCollection(reader, ValueType(tag[0]))
+	case collectionNil:
+
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown collection element type %d", tag[0])
+}
+
+func readCollectionWord(reader io.Reader) (uint64, error) {
+	word := make([]byte, 8)
+	_, err := io.ReadFull(reader, word)
+	if err != nil {
+
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(word), nil
+}
+
+// collectionLiteralJSON converts a collection element into its JSON literal, a set lists its elements
+// and an ordered map its keys along their values.
+func collectionLiteralJSON(value interface{}) *catalogLiteralJSON {
+	switch val := value.(type) {
+	case string:
+
+		return &catalogLiteralJSON{Type: "string", Value: val}
+	case int64:
+
+		return &catalogLiteralJSON{Type: "integer", Value: val}
+	case float64:
+
+		return &catalogLiteralJSON{Type: "float", Value: val}
+	case bool:
+
+		return &catalogLiteralJSON{Type: "boolean", Value: val}
+	case pkg.Quantity:
+
+		return &catalogLiteralJSON{Type: "quantity", Value: val.Value, Unit: val.Unit}
+	case pkg.Set:
+		literal := &catalogLiteralJSON{Type: "set", Elements: make([]*catalogLiteralJSON, 0, val.Len())}
+		for _, element := range val.Values() {
+			literal.Elements = append(literal.Elements, collectionLiteralJSON(element))
+		}
+
+		return literal
+	case pkg.OrderedMap:
+		literal := &catalogLiteralJSON{Type: "orderedMap", Keys: make([]*catalogLiteralJSON, 0, val.Len()), Elements: make([]*catalogLiteralJSON, 0, val.Len())}
+		for _, key := range val.Keys() {
+			literal.Keys = append(literal.Keys, collectionLiteralJSON(key))
+			literal.Elements = append(literal.Elements, collectionLiteralJSON(val.Get(key)))
+		}
+
+		return literal
+	}
+
+	return &catalogLiteralJSON{Type: "nil"}
+}
+
+// collectionValue converts a JSON literal back into the collection element it was made of.
+func collectionValue(literal *catalogLiteralJSON) (interface{}, error) {
+	if literal == nil {
+
+		return nil, fmt.Errorf("collection element has no literal")
+	}
+	number := func() (json.Number, error) {
+		value, ok := literal.Value.(json.Number)
+		if !ok {
+
+			return "", fmt.Errorf("%s literal %v is not a number", literal.Type, literal.Value)
+		}
+
+		return value, nil
+	}
+	switch literal.Type {
+	case "nil":
+
+		return nil, nil
+	case "string":
+		value, _ := literal.Value.(string)
+
+		return value, nil
+	case "boolean":
+		value, _ := literal.Value.(bool)
+
+		return value, nil
+	case "integer":
+		value, err := number()
+		if err != nil {
+
+			return nil, err
+		}
+
+		return value.Int64()
+	case "float", "quantity":
+		value, err := number()
+		if err != nil {
+
+			return nil, err
+		}
+		float, err := value.Float64()
+		if err != nil || literal.Type == "float" {
+
+			return float, err
+		}
+
+		return pkg.Quantity{Value: float, Unit: literal.Unit}, nil
+	case "set", "orderedMap":
+		if literal.Type == "orderedMap" && len(literal.Keys) != len(literal.Elements) {
+
+			return nil, fmt.Errorf("ordered map literal has %d keys and %d values", len(literal.Keys), len(literal.Elements))
+		}
+		elements := make([]interface{}, 0, len(literal.Keys)+len(literal.Elements))
+		for i, element := range literal.Elements {
+			if literal.Type == "orderedMap" {
+				key, err := collectionValue(literal.Keys[i])
+				if err != nil {
+
+					return nil, err
+				}
+				elements = append(elements, key)
+			}
+			value, err := collectionValue(element)
+			if err != nil {
+
+				return nil, err
+			}
+			elements = append(elements, value)
+		}
+		if literal.Type == "orderedMap" {
+
+			return pkg.NewOrderedMap(elements...)
+		}
+
+		return pkg.NewSet(elements...)
+	}
+
+	return nil, fmt.Errorf("unknown literal type %s", literal.Type)
+}
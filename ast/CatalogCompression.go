@@ -0,0 +1,237 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// CompressionNone stores the catalog as is.
+	CompressionNone = ""
+	// CompressionGzip stores the catalog compressed with gzip.
+	CompressionGzip = "gzip"
+	// CompressionZstd stores the catalog compressed with zstd. There is no zstd codec built in,
+	// it has to be registered with RegisterCatalogCodec before it can be used.
+	CompressionZstd = "zstd"
+
+	// catalogWriteBufferSize is the size of the buffer placed in front of the writer when storing a catalog,
+	// a catalog is written in many small pieces.
+	catalogWriteBufferSize = 64 * 1024
+)
+
+var (
+	// GzipMagic is the header of a gzip stream.
+	GzipMagic = []byte{0x1f, 0x8b}
+	// ZstdMagic is the header of a zstd frame.
+	ZstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// ErrUnknownCompression is returned when a catalog is stored or loaded with a compression that has no codec registered.
+	ErrUnknownCompression = errors.New("unknown catalog compression")
+)
+
+// defaultCatalogCodecs are the codecs of the registries that are nil, RegisterCatalogCodec adds into them.
+var defaultCatalogCodecs = NewCatalogCodecRegistry()
+
+// CatalogCodec compresses and decompresses a catalog stream.
+type CatalogCodec struct {
+	// Name is used to choose the codec when storing a catalog.
+	Name string
+	// Magic are the first bytes of a compressed stream, they are used to recognize the codec when loading a catalog.
+	Magic []byte
+	// NewWriter wraps the writer, the returned writer is closed once the catalog is written, the wrapped one is not.
+	NewWriter func(writer io.Writer) (io.WriteCloser, error)
+	// NewReader wraps the reader, the returned reader is closed once the catalog is read, the wrapped one is not.
+	NewReader func(reader io.Reader) (io.ReadCloser, error)
+}
+
+// CatalogCodecRegistry keeps the catalog codecs by name. A nil registry is the default one of the process, so two
+// knowledge libraries may each plug in codecs of their own, see KnowledgeLibrary.CatalogCodecs.
+type CatalogCodecRegistry struct {
+	mutex  sync.RWMutex
+	codecs map[string]*CatalogCodec
+}
+
+// NewCatalogCodecRegistry create a CatalogCodecRegistry knowing the built in gzip codec.
+func NewCatalogCodecRegistry() *CatalogCodecRegistry {
+
+	return &CatalogCodecRegistry{
+		codecs: map[string]*CatalogCodec{
+			CompressionGzip: {
+				Name:  CompressionGzip,
+				Magic: GzipMagic,
+				NewWriter: func(writer io.Writer) (io.WriteCloser, error) {
+
+					return gzip.NewWriter(writer), nil
+				},
+				NewReader: func(reader io.Reader) (io.ReadCloser, error) {
+
+					return gzip.NewReader(reader)
+				},
+			},
+		},
+	}
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *CatalogCodecRegistry) or() *CatalogCodecRegistry {
+	if registry == nil {
+
+		return defaultCatalogCodecs
+	}
+
+	return registry
+}
+
+// Register adds or replaces the codec of the same name.
+func (registry *CatalogCodecRegistry) Register(codec *CatalogCodec) error {
+	if codec == nil || len(codec.Name) == 0 || len(codec.Magic) == 0 || codec.NewWriter == nil || codec.NewReader == nil {
+
+		return fmt.Errorf("catalog codec must have a name, a magic, a writer and a reader")
+	}
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.codecs[codec.Name] = codec
+
+	return nil
+}
+
+func (registry *CatalogCodecRegistry) get(compression string) (*CatalogCodec, error) {
+	registry = registry.or()
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	codec, ok := registry.codecs[compression]
+	if !ok {
+
+		return nil, fmt.Errorf("%w %q, register its codec with RegisterCatalogCodec", ErrUnknownCompression, compression)
+	}
+
+	return codec, nil
+}
+
+// detect finds the codec whose magic the header starts with, nil if the header is a plain catalog.
+func (registry *CatalogCodecRegistry) detect(header []byte) (*CatalogCodec, error) {
+	registry = registry.or()
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	for _, codec := range registry.codecs {
+		if bytes.HasPrefix(header, codec.Magic) {
+
+			return codec, nil
+		}
+	}
+	if bytes.HasPrefix(header, ZstdMagic) {
+
+		return nil, fmt.Errorf("%w %q, register its codec with RegisterCatalogCodec", ErrUnknownCompression, CompressionZstd)
+	}
+
+	return nil, nil
+}
+
+// RegisterCatalogCodec adds or replaces the codec of the same name in the default registry. This is how zstd, or any
+// other compression, is plugged in without this library depending on it.
+func RegisterCatalogCodec(codec *CatalogCodec) error {
+
+	return defaultCatalogCodecs.Register(codec)
+}
+
+// WriteCompressedCatalogToWriter stores the catalog like WriteCatalogToWriter does, compressed using the named compression.
+// The writes are buffered and the compressed stream is finished before returning, but the writer is not closed.
+func (cat *Catalog) WriteCompressedCatalogToWriter(writer io.Writer, compression string) error {
+
+	return cat.writeCompressed(writer, compression, nil)
+}
+
+// writeCompressed stores the catalog compressed with the codec of the registry, the default registry if nil.
+func (cat *Catalog) writeCompressed(writer io.Writer, compression string, codecs *CatalogCodecRegistry) error {
+	buffered := bufio.NewWriterSize(writer, catalogWriteBufferSize)
+	if compression == CompressionNone {
+		err := cat.WriteCatalogToWriter(buffered)
+		if err != nil {
+
+			return err
+		}
+
+		return buffered.Flush()
+	}
+	codec, err := codecs.get(compression)
+	if err != nil {
+
+		return err
+	}
+	compressed, err := codec.NewWriter(buffered)
+	if err != nil {
+
+		return fmt.Errorf("failed to create %s writer : %w", codec.Name, err)
+	}
+	err = cat.WriteCatalogToWriter(compressed)
+	if err != nil {
+		_ = compressed.Close()
+
+		return err
+	}
+	err = compressed.Close()
+	if err != nil {
+
+		return fmt.Errorf("failed to finish %s stream : %w", codec.Name, err)
+	}
+
+	return buffered.Flush()
+}
+
+// ReadCompressedCatalogFromReader reads a catalog like ReadCatalogFromReader does. The compression is recognized
+// from the first bytes of the stream, a catalog that is not compressed is read as is.
+// The stream is decompressed as it is read, it is never held in memory as a whole.
+func (cat *Catalog) ReadCompressedCatalogFromReader(reader io.Reader) error {
+
+	return cat.readCompressed(reader, nil)
+}
+
+// readCompressed reads the catalog decompressed with the codecs of the registry, the default registry if nil.
+func (cat *Catalog) readCompressed(reader io.Reader, codecs *CatalogCodecRegistry) error {
+	// Only the header is read ahead, so a plain catalog leaves the reader right after its end.
+	header := make([]byte, len(ZstdMagic))
+	count, err := io.ReadFull(reader, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+
+		return err
+	}
+	header = header[:count]
+	stream := io.MultiReader(bytes.NewReader(header), reader)
+	codec, err := codecs.detect(header)
+	if err != nil {
+
+		return err
+	}
+	if codec == nil {
+
+		return cat.ReadCatalogFromReader(stream)
+	}
+	decompressed, err := codec.NewReader(stream)
+	if err != nil {
+
+		return fmt.Errorf("failed to create %s reader : %w", codec.Name, err)
+	}
+	defer decompressed.Close()
+
+	return cat.ReadCatalogFromReader(bufio.NewReader(decompressed))
+}
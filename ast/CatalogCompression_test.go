@@ -0,0 +1,130 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prefixWriter belongs to a codec that only writes its magic in front of the catalog,
+// enough to see a registered codec being picked up.
+type prefixWriter struct {
+	io.Writer
+}
+
+func (w prefixWriter) Close() error {
+
+	return nil
+}
+
+func TestCatalog_CompressedRoundTrip(t *testing.T) {
+	assert.NoError(t, RegisterCatalogCodec(&CatalogCodec{
+		Name:  "prefix",
+		Magic: []byte("PFX1"),
+		NewWriter: func(writer io.Writer) (io.WriteCloser, error) {
+			_, err := writer.Write([]byte("PFX1"))
+
+			return prefixWriter{Writer: writer}, err
+		},
+		NewReader: func(reader io.Reader) (io.ReadCloser, error) {
+			_, err := io.ReadFull(reader, make([]byte, 4))
+
+			return io.NopCloser(reader), err
+		},
+	}))
+
+	for _, compression := range []string{CompressionNone, CompressionGzip, "prefix"} {
+		cat := newTestCatalog()
+		buffer := &bytes.Buffer{}
+		assert.NoError(t, cat.WriteCompressedCatalogToWriter(buffer, compression), compression)
+
+		loaded := &Catalog{}
+		assert.NoError(t, loaded.ReadCompressedCatalogFromReader(buffer), compression)
+		assert.True(t, cat.Equals(loaded), compression)
+	}
+}
+
+func TestKnowledgeLibrary_CatalogCodecs(t *testing.T) {
+	codecs := NewCatalogCodecRegistry()
+	assert.NoError(t, codecs.Register(&CatalogCodec{
+		Name:  "private",
+		Magic: []byte("PRV1"),
+		NewWriter: func(writer io.Writer) (io.WriteCloser, error) {
+			_, err := writer.Write([]byte("PRV1"))
+
+			return prefixWriter{Writer: writer}, err
+		},
+		NewReader: func(reader io.Reader) (io.ReadCloser, error) {
+			_, err := io.ReadFull(reader, make([]byte, 4))
+
+			return io.NopCloser(reader), err
+		},
+	}))
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, newTestCatalog().writeCompressed(buffer, "private", codecs))
+	stored := buffer.Bytes()
+
+	// the codecs of a library are not known to the others.
+	_, err := NewKnowledgeLibrary().LoadKnowledgeBaseFromReader(bytes.NewReader(stored), true)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(newTestCatalog().WriteCompressedCatalogToWriter(&bytes.Buffer{}, "private"), ErrUnknownCompression))
+
+	lib := NewKnowledgeLibrary()
+	lib.CatalogCodecs = codecs
+	kb, err := lib.LoadKnowledgeBaseFromReader(bytes.NewReader(stored), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", kb.Name)
+}
+
+func TestCatalog_CompressedIsSmaller(t *testing.T) {
+	cat := newTestCatalog()
+	plain := &bytes.Buffer{}
+	assert.NoError(t, cat.WriteCatalogToWriter(plain))
+	compressed := &bytes.Buffer{}
+	assert.NoError(t, cat.WriteCompressedCatalogToWriter(compressed, CompressionGzip))
+	assert.True(t, bytes.HasPrefix(compressed.Bytes(), GzipMagic))
+	assert.Less(t, compressed.Len(), plain.Len())
+}
+
+func TestCatalog_ReadPlainCatalogsInSequence(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	first := newTestCatalog()
+	second := newTestCatalog()
+	second.KnowledgeBaseName = "Second"
+	assert.NoError(t, first.WriteCatalogToWriter(buffer))
+	assert.NoError(t, second.WriteCatalogToWriter(buffer))
+
+	loaded := &Catalog{}
+	assert.NoError(t, loaded.ReadCompressedCatalogFromReader(buffer))
+	assert.Equal(t, "Test", loaded.KnowledgeBaseName)
+	assert.NoError(t, loaded.ReadCompressedCatalogFromReader(buffer))
+	assert.Equal(t, "Second", loaded.KnowledgeBaseName)
+}
+
+func TestCatalog_UnknownCompression(t *testing.T) {
+	cat := newTestCatalog()
+	err := cat.WriteCompressedCatalogToWriter(&bytes.Buffer{}, CompressionZstd)
+	assert.True(t, errors.Is(err, ErrUnknownCompression))
+
+	err = (&Catalog{}).ReadCompressedCatalogFromReader(bytes.NewReader(append(ZstdMagic, 0, 0, 0)))
+	assert.True(t, errors.Is(err, ErrUnknownCompression))
+
+	assert.Error(t, RegisterCatalogCodec(&CatalogCodec{Name: CompressionZstd}))
+}
@@ -0,0 +1,518 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+)
+
+const (
+	// CatalogDeltaFormat identifies a catalog delta written by WriteCatalogDeltaToWriter.
+	CatalogDeltaFormat = "grule-catalog-delta"
+)
+
+// CatalogDelta holds the difference between two catalogs, so a catalog already loaded by a node can be patched into
+// the next one without transferring the nodes that did not change. Make it with MakeCatalogDelta.
+//
+// The ids of the AST nodes are generated every time GRL is built, the nodes of the new catalog are matched with the
+// identical nodes of the base catalog and take their ids. The patched catalog is therefore not the new catalog as is,
+// it is the catalog ApplyDelta returns, which is the base of the next delta.
+type CatalogDelta struct {
+	// KnowledgeBaseName and KnowledgeBaseVersion are the knowledge base of the patched catalog.
+	KnowledgeBaseName    string
+	KnowledgeBaseVersion string
+	// BaseKnowledgeBaseName and BaseKnowledgeBaseVersion are the knowledge base of the catalog the delta applies to.
+	BaseKnowledgeBaseName    string
+	BaseKnowledgeBaseVersion string
+	MemoryName               string
+	MemoryVersion            string
+	// BaseDigest and TargetDigest are the Digest of the catalog the delta applies to and of the patched catalog.
+	BaseDigest   string
+	TargetDigest string
+	// Data holds the metas that are added or changed, keyed by their id.
+	Data map[string]Meta
+	// RemovedIDs are the ids of the metas that are removed.
+	RemovedIDs []string
+
+	memory catalogMemoryDelta
+}
+
+// catalogMemoryDelta holds the changes of the working memory maps of a catalog.
+type catalogMemoryDelta struct {
+	VariableSnapshots       mapDelta[string]   `json:"variableSnapshots"`
+	ExpressionSnapshots     mapDelta[string]   `json:"expressionSnapshots"`
+	ExpressionAtomSnapshots mapDelta[string]   `json:"expressionAtomSnapshots"`
+	ExpressionVariables     mapDelta[[]string] `json:"expressionVariables"`
+	ExpressionAtomVariables mapDelta[[]string] `json:"expressionAtomVariables"`
+}
+
+// mapDelta holds the entries of a map that are set and the keys that are removed.
+type mapDelta[V any] struct {
+	Set     map[string]V `json:"set,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+}
+
+// catalogDeltaJSON is the JSON document of a catalog delta.
+type catalogDeltaJSON struct {
+	Format        string             `json:"format"`
+	Version       string             `json:"version"`
+	KnowledgeBase nameVersionJSON    `json:"knowledgeBase"`
+	Base          nameVersionJSON    `json:"base"`
+	Memory        nameVersionJSON    `json:"memory"`
+	BaseDigest    string             `json:"baseDigest"`
+	TargetDigest  string             `json:"targetDigest"`
+	Nodes         []catalogNodeJSON  `json:"nodes"`
+	RemovedNodes  []string           `json:"removedNodes,omitempty"`
+	MemoryChanges catalogMemoryDelta `json:"memoryChanges"`
+}
+
+// Digest returns the SHA-256 of the content of this catalog, as hexadecimal. Two catalogs with the same digest
+// rebuild identical knowledge bases, down to the ids of their nodes.
+func (cat *Catalog) Digest() (string, error) {
+	hash := sha256.New()
+	err := cat.WriteCatalogToJSON(hash)
+	if err != nil {
+
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// MakeCatalogDelta creates the delta patching the base catalog into the target catalog. Every node of the target
+// identical to a node of the base, by its type, snapshot and GRL text, takes the id of that node. Only the nodes
+// that are not identical, or whose children are not, are held in the delta.
+func MakeCatalogDelta(base, target *Catalog) (*CatalogDelta, error) {
+	baseDigest, err := base.Digest()
+	if err != nil {
+
+		return nil, err
+	}
+	baseNodes, err := catalogNodes(base)
+	if err != nil {
+
+		return nil, err
+	}
+	targetNodes, err := catalogNodes(target)
+	if err != nil {
+
+		return nil, err
+	}
+	ids := matchCatalogNodes(baseNodes, targetNodes)
+
+	delta := &CatalogDelta{
+		KnowledgeBaseName:        target.KnowledgeBaseName,
+		KnowledgeBaseVersion:     target.KnowledgeBaseVersion,
+		BaseKnowledgeBaseName:    base.KnowledgeBaseName,
+		BaseKnowledgeBaseVersion: base.KnowledgeBaseVersion,
+		MemoryName:               target.MemoryName,
+		MemoryVersion:            target.MemoryVersion,
+		BaseDigest:               baseDigest,
+		Data:                     make(map[string]Meta),
+		RemovedIDs:               make([]string, 0),
+	}
+	kept := make(map[string]bool, len(targetNodes))
+	for id, node := range targetNodes {
+		node = remapNode(node, ids)
+		kept[node.ID] = true
+		if baseNode, ok := baseNodes[node.ID]; ok && reflect.DeepEqual(baseNode, node) {
+
+			continue
+		}
+		meta, err := remappedMeta(node)
+		if err != nil {
+
+			return nil, fmt.Errorf("can not patch node %s. got %w", id, err)
+		}
+		delta.Data[node.ID] = meta
+	}
+	for id := range baseNodes {
+		if !kept[id] {
+			delta.RemovedIDs = append(delta.RemovedIDs, id)
+		}
+	}
+	sort.Strings(delta.RemovedIDs)
+
+	remapID := func(id string) string {
+		if mapped, ok := ids[id]; ok {
+
+			return mapped
+		}
+
+		return id
+	}
+	remapIDs := func(list []string) []string {
+		ret := make([]string, len(list))
+		for i, id := range list {
+			ret[i] = remapID(id)
+		}
+
+		return ret
+	}
+	delta.memory = catalogMemoryDelta{
+		VariableSnapshots:       diffMap(base.MemoryVariableSnapshotMap, remapMap(target.MemoryVariableSnapshotMap, nil, remapID), stringEquals),
+		ExpressionSnapshots:     diffMap(base.MemoryExpressionSnapshotMap, remapMap(target.MemoryExpressionSnapshotMap, nil, remapID), stringEquals),
+		ExpressionAtomSnapshots: diffMap(base.MemoryExpressionAtomSnapshotMap, remapMap(target.MemoryExpressionAtomSnapshotMap, nil, remapID), stringEquals),
+		ExpressionVariables:     diffMap(base.MemoryExpressionVariableMap, remapMap(target.MemoryExpressionVariableMap, remapID, remapIDs), sameIDs),
+		ExpressionAtomVariables: diffMap(base.MemoryExpressionAtomVariableMap, remapMap(target.MemoryExpressionAtomVariableMap, remapID, remapIDs), sameIDs),
+	}
+
+	patched, err := base.ApplyDelta(delta)
+	if err != nil {
+
+		return nil, err
+	}
+	delta.TargetDigest, err = patched.Digest()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return delta, nil
+}
+
+// ApplyDelta returns the catalog patched by the delta, this catalog is left unchanged. It returns an error if the
+// delta was not made from this catalog, or if the patched catalog is not the one the delta was made for.
+func (cat *Catalog) ApplyDelta(delta *CatalogDelta) (*Catalog, error) {
+	digest, err := cat.Digest()
+	if err != nil {
+
+		return nil, err
+	}
+	if digest != delta.BaseDigest {
+
+		return nil, fmt.Errorf("catalog delta applies to the catalog %s, not to %s", delta.BaseDigest, digest)
+	}
+	patched := &Catalog{
+		KnowledgeBaseName:               delta.KnowledgeBaseName,
+		KnowledgeBaseVersion:            delta.KnowledgeBaseVersion,
+		Data:                            make(map[string]Meta, len(cat.Data)+len(delta.Data)),
+		MemoryName:                      delta.MemoryName,
+		MemoryVersion:                   delta.MemoryVersion,
+		MemoryVariableSnapshotMap:       patchMap(cat.MemoryVariableSnapshotMap, delta.memory.VariableSnapshots),
+		MemoryExpressionSnapshotMap:     patchMap(cat.MemoryExpressionSnapshotMap, delta.memory.ExpressionSnapshots),
+		MemoryExpressionAtomSnapshotMap: patchMap(cat.MemoryExpressionAtomSnapshotMap, delta.memory.ExpressionAtomSnapshots),
+		MemoryExpressionVariableMap:     patchMap(cat.MemoryExpressionVariableMap, delta.memory.ExpressionVariables),
+		MemoryExpressionAtomVariableMap: patchMap(cat.MemoryExpressionAtomVariableMap, delta.memory.ExpressionAtomVariables),
+	}
+	for id, meta := range cat.Data {
+		patched.Data[id] = meta
+	}
+	for _, id := range delta.RemovedIDs {
+		delete(patched.Data, id)
+	}
+	for id, meta := range delta.Data {
+		patched.Data[id] = meta
+	}
+	if len(delta.TargetDigest) > 0 {
+		digest, err = patched.Digest()
+		if err != nil {
+
+			return nil, err
+		}
+		if digest != delta.TargetDigest {
+
+			return nil, fmt.Errorf("catalog patched by the delta is %s, expecting %s", digest, delta.TargetDigest)
+		}
+	}
+
+	return patched, nil
+}
+
+// WriteCatalogDeltaToWriter will store the delta as a JSON document using provided writer.
+// You are responsible for closing the writing stream once its done.
+func (delta *CatalogDelta) WriteCatalogDeltaToWriter(writer io.Writer) error {
+	doc := catalogDeltaJSON{
+		Format:        CatalogDeltaFormat,
+		Version:       CatalogJSONVersion,
+		KnowledgeBase: nameVersionJSON{Name: delta.KnowledgeBaseName, Version: delta.KnowledgeBaseVersion},
+		Base:          nameVersionJSON{Name: delta.BaseKnowledgeBaseName, Version: delta.BaseKnowledgeBaseVersion},
+		Memory:        nameVersionJSON{Name: delta.MemoryName, Version: delta.MemoryVersion},
+		BaseDigest:    delta.BaseDigest,
+		TargetDigest:  delta.TargetDigest,
+		Nodes:         make([]catalogNodeJSON, 0, len(delta.Data)),
+		RemovedNodes:  delta.RemovedIDs,
+		MemoryChanges: delta.memory,
+	}
+	ids := make([]string, 0, len(delta.Data))
+	for id := range delta.Data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		node, err := nodeToJSON(delta.Data[id])
+		if err != nil {
+
+			return fmt.Errorf("can not write node %s to JSON. got %w", id, err)
+		}
+		doc.Nodes = append(doc.Nodes, node)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(doc)
+}
+
+// ReadCatalogDeltaFromReader would read a delta written by WriteCatalogDeltaToWriter from reader.
+// You are responsible for closing the reader stream once its done.
+func ReadCatalogDeltaFromReader(reader io.Reader) (*CatalogDelta, error) {
+	var doc catalogDeltaJSON
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+
+		return nil, fmt.Errorf("invalid catalog delta. got %w", err)
+	}
+	if doc.Format != CatalogDeltaFormat || doc.Version != CatalogJSONVersion {
+
+		return nil, fmt.Errorf("%w: catalog delta format %q version %q, expecting %q version %q", ErrIncompatibleCatalog, doc.Format, doc.Version, CatalogDeltaFormat, CatalogJSONVersion)
+	}
+	delta := &CatalogDelta{
+		KnowledgeBaseName:        doc.KnowledgeBase.Name,
+		KnowledgeBaseVersion:     doc.KnowledgeBase.Version,
+		BaseKnowledgeBaseName:    doc.Base.Name,
+		BaseKnowledgeBaseVersion: doc.Base.Version,
+		MemoryName:               doc.Memory.Name,
+		MemoryVersion:            doc.Memory.Version,
+		BaseDigest:               doc.BaseDigest,
+		TargetDigest:             doc.TargetDigest,
+		Data:                     make(map[string]Meta, len(doc.Nodes)),
+		RemovedIDs:               doc.RemovedNodes,
+		memory:                   doc.MemoryChanges,
+	}
+	for _, node := range doc.Nodes {
+		meta, err := nodeFromJSON(node)
+		if err != nil {
+
+			return nil, fmt.Errorf("can not read node %s from JSON. got %w", node.ID, err)
+		}
+		delta.Data[node.ID] = meta
+	}
+
+	return delta, nil
+}
+
+// catalogNodes returns the nodes of the catalog in their JSON form, keyed by their id.
+func catalogNodes(cat *Catalog) (map[string]catalogNodeJSON, error) {
+	nodes := make(map[string]catalogNodeJSON, len(cat.Data))
+	for id, meta := range cat.Data {
+		node, err := nodeToJSON(meta)
+		if err != nil {
+
+			return nil, fmt.Errorf("can not read node %s. got %w", id, err)
+		}
+		nodes[id] = node
+	}
+
+	return nodes, nil
+}
+
+// nodeKey identifies the nodes that are identical but for their id.
+func nodeKey(node catalogNodeJSON) string {
+
+	return node.Type + "\x00" + node.Snapshot + "\x00" + node.GrlText
+}
+
+// matchCatalogNodes maps the id of the target nodes to the id of an identical base node, each base node is taken once.
+// The rule and halt entries are matched first, then the children of the matched nodes with the children at the same
+// edge, last the remaining nodes by their key alone.
+func matchCatalogNodes(baseNodes, targetNodes map[string]catalogNodeJSON) map[string]string {
+	baseByKey := make(map[string][]string)
+	for _, id := range sortedNodeIDs(baseNodes) {
+		key := nodeKey(baseNodes[id])
+		baseByKey[key] = append(baseByKey[key], id)
+	}
+	ids := make(map[string]string)
+	taken := make(map[string]bool)
+	claim := func(targetID string) (string, bool) {
+		for _, baseID := range baseByKey[nodeKey(targetNodes[targetID])] {
+			if !taken[baseID] {
+				ids[targetID] = baseID
+				taken[baseID] = true
+
+				return baseID, true
+			}
+		}
+
+		return "", false
+	}
+
+	pending := make([][2]string, 0)
+	for _, id := range sortedNodeIDs(targetNodes) {
+		nodeType := targetNodes[id].Type
+		if nodeType != nodeTypeNames[TypeRuleEntry] && nodeType != nodeTypeNames[TypeHaltEntry] {
+
+			continue
+		}
+		if baseID, ok := claim(id); ok {
+			pending = append(pending, [2]string{id, baseID})
+		}
+	}
+	for len(pending) > 0 {
+		pair := pending[0]
+		pending = pending[1:]
+		baseEdges := baseNodes[pair[1]].Edges
+		for i, edge := range targetNodes[pair[0]].Edges {
+			if _, matched := ids[edge.Target]; matched || i >= len(baseEdges) {
+
+				continue
+			}
+			child, ok := targetNodes[edge.Target]
+			baseEdge := baseEdges[i]
+			if !ok || baseEdge.Label != edge.Label || taken[baseEdge.Target] || nodeKey(child) != nodeKey(baseNodes[baseEdge.Target]) {
+
+				continue
+			}
+			ids[edge.Target] = baseEdge.Target
+			taken[baseEdge.Target] = true
+			pending = append(pending, [2]string{edge.Target, baseEdge.Target})
+		}
+	}
+	for _, id := range sortedNodeIDs(targetNodes) {
+		if _, matched := ids[id]; !matched {
+			claim(id)
+		}
+	}
+	// a new node can not keep an id another node took.
+	for id := range targetNodes {
+		if _, matched := ids[id]; !matched && taken[id] {
+			ids[id] = unique.NewID()
+		}
+	}
+
+	return ids
+}
+
+func sortedNodeIDs(nodes map[string]catalogNodeJSON) []string {
+	ret := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ret = append(ret, id)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// remapNode returns the node with its id and the targets of its edges mapped.
+func remapNode(node catalogNodeJSON, ids map[string]string) catalogNodeJSON {
+	if mapped, ok := ids[node.ID]; ok {
+		node.ID = mapped
+	}
+	edges := make([]catalogEdgeJSON, len(node.Edges))
+	for i, edge := range node.Edges {
+		if mapped, ok := ids[edge.Target]; ok {
+			edge.Target = mapped
+		}
+		edges[i] = edge
+	}
+	if len(edges) > 0 {
+		node.Edges = edges
+	}
+
+	return node
+}
+
+// remappedMeta returns the meta of the node, read back the way ReadCatalogDeltaFromReader reads it.
+func remappedMeta(node catalogNodeJSON) (Meta, error) {
+	data, err := json.Marshal(node)
+	if err != nil {
+
+		return nil, err
+	}
+	var read catalogNodeJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&read); err != nil {
+
+		return nil, err
+	}
+
+	return nodeFromJSON(read)
+}
+
+// remapMap returns the map with its keys mapped by remapKey, if set, and its values mapped by remapValue.
+func remapMap[V any](m map[string]V, remapKey func(string) string, remapValue func(V) V) map[string]V {
+	ret := make(map[string]V, len(m))
+	for key, value := range m {
+		if remapKey != nil {
+			key = remapKey(key)
+		}
+		ret[key] = remapValue(value)
+	}
+
+	return ret
+}
+
+func stringEquals(a, b string) bool {
+
+	return a == b
+}
+
+// sameIDs tells whether the lists hold the same ids, the order the working memory indexed them in does not matter.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+
+		return false
+	}
+	sortedA, sortedB := slices.Clone(a), slices.Clone(b)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	return slices.Equal(sortedA, sortedB)
+}
+
+// diffMap returns the changes turning the base map into the target map.
+func diffMap[V any](base, target map[string]V, equals func(a, b V) bool) mapDelta[V] {
+	delta := mapDelta[V]{Set: make(map[string]V)}
+	for key, value := range target {
+		if baseValue, ok := base[key]; !ok || !equals(baseValue, value) {
+			delta.Set[key] = value
+		}
+	}
+	for key := range base {
+		if _, ok := target[key]; !ok {
+			delta.Removed = append(delta.Removed, key)
+		}
+	}
+	sort.Strings(delta.Removed)
+
+	return delta
+}
+
+// patchMap returns a copy of the map with the changes applied.
+func patchMap[V any](m map[string]V, delta mapDelta[V]) map[string]V {
+	ret := make(map[string]V, len(m)+len(delta.Set))
+	for key, value := range m {
+		ret[key] = value
+	}
+	for _, key := range delta.Removed {
+		delete(ret, key)
+	}
+	for key, value := range delta.Set {
+		ret[key] = value
+	}
+
+	return ret
+}
@@ -0,0 +1,848 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// CatalogJSONFormat identifies a catalog written by WriteCatalogToJSON.
+	CatalogJSONFormat = "grule-catalog"
+	// CatalogJSONVersion is the version of the JSON catalog format. It changes only when a reader of the previous
+	// version can not read the new one.
+	CatalogJSONVersion = "1"
+)
+
+// catalogJSON is the JSON document of a catalog.
+type catalogJSON struct {
+	Format        string            `json:"format"`
+	Version       string            `json:"version"`
+	KnowledgeBase nameVersionJSON   `json:"knowledgeBase"`
+	Memory        catalogMemoryJSON `json:"memory"`
+	Nodes         []catalogNodeJSON `json:"nodes"`
+}
+
+type nameVersionJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// catalogMemoryJSON is the working memory of a catalog, the maps are keyed by snapshot or by node id.
+type catalogMemoryJSON struct {
+	nameVersionJSON
+	VariableSnapshots       map[string]string   `json:"variableSnapshots"`
+	ExpressionSnapshots     map[string]string   `json:"expressionSnapshots"`
+	ExpressionAtomSnapshots map[string]string   `json:"expressionAtomSnapshots"`
+	ExpressionVariables     map[string][]string `json:"expressionVariables"`
+	ExpressionAtomVariables map[string][]string `json:"expressionAtomVariables"`
+}
+
+// catalogNodeJSON is an AST node, its edges point to the ids of its children.
+type catalogNodeJSON struct {
+	ID         string                `json:"id"`
+	Type       string                `json:"type"`
+	GrlText    string                `json:"grlText"`
+	Snapshot   string                `json:"snapshot"`
+	Attributes catalogAttributesJSON `json:"attributes"`
+	Edges      []catalogEdgeJSON     `json:"edges,omitempty"`
+	Literal    *catalogLiteralJSON   `json:"literal,omitempty"`
+}
+
+// catalogAttributesJSON holds the attributes of every node type, only those of the node type are set.
+type catalogAttributesJSON struct {
+	RuleName            string   `json:"ruleName,omitempty"`
+	RuleDescription     string   `json:"ruleDescription,omitempty"`
+	RuleID              string   `json:"ruleId,omitempty"`
+	Salience            int      `json:"salience,omitempty"`
+	MaxFires            int      `json:"maxFires,omitempty"`
+	CooldownNanoseconds int64    `json:"cooldownNanoseconds,omitempty"`
+	Criticality         string   `json:"criticality,omitempty"`
+	Profiles            []string `json:"profiles,omitempty"`
+	Sinks               []string `json:"sinks,omitempty"`
+	Approvals           []string `json:"approvals,omitempty"`
+	ScriptLanguage      string   `json:"scriptLanguage,omitempty"`
+	ScriptSource        string   `json:"scriptSource,omitempty"`
+	FunctionName        string   `json:"functionName,omitempty"`
+	VariableName        string   `json:"variableName,omitempty"`
+	Name                string   `json:"name,omitempty"`
+	Operator            string   `json:"operator,omitempty"`
+	AssignOperator      string   `json:"assignOperator,omitempty"`
+	Negated             bool     `json:"negated,omitempty"`
+	ArmGrlTexts         []string `json:"armGrlTexts,omitempty"`
+	ArmOperators        []string `json:"armOperators,omitempty"`
+	CaseGrlTexts        []string `json:"caseGrlTexts,omitempty"`
+	CaseValueCounts     []int    `json:"caseValueCounts,omitempty"`
+	Quantifier          string   `json:"quantifier,omitempty"`
+}
+
+// catalogEdgeJSON points from a node to one of its children, index orders the children of the same label.
+type catalogEdgeJSON struct {
+	Label  string `json:"label"`
+	Target string `json:"target"`
+	Index  *int   `json:"index,omitempty"`
+}
+
+// catalogLiteralJSON is the value of a constant node, its type is string, integer, float, boolean, quantity, set,
+// orderedMap or nil. The elements of a set, and the keys and values of an ordered map, are literals as well.
+type catalogLiteralJSON struct {
+	Type     string                `json:"type"`
+	Value    interface{}           `json:"value,omitempty"`
+	Unit     string                `json:"unit,omitempty"`
+	Keys     []*catalogLiteralJSON `json:"keys,omitempty"`
+	Elements []*catalogLiteralJSON `json:"elements,omitempty"`
+}
+
+var nodeTypeNames = map[NodeType]string{
+	TypeArgumentList:       "ArgumentList",
+	TypeArrayMapSelector:   "ArrayMapSelector",
+	TypeAssignment:         "Assignment",
+	TypeExpression:         "Expression",
+	TypeConstant:           "Constant",
+	TypeExpressionAtom:     "ExpressionAtom",
+	TypeFunctionCall:       "FunctionCall",
+	TypeRuleEntry:          "RuleEntry",
+	TypeThenExpression:     "ThenExpression",
+	TypeThenExpressionList: "ThenExpressionList",
+	TypeThenScope:          "ThenScope",
+	TypeVariable:           "Variable",
+	TypeWhenScope:          "WhenScope",
+	TypeMatchExpression:    "MatchExpression",
+	TypeHaltEntry:          "HaltEntry",
+	TypeCollectStatement:   "CollectStatement",
+	TypeSwitchStatement:    "SwitchStatement",
+	TypeQuantifier:         "Quantifier",
+}
+
+// WriteCatalogToJSON will store the content of this Catalog as a JSON document using provided writer,
+// to be read by tools written in any language. The nodes are ordered by their id.
+// You are responsible for closing the writing stream once its done.
+func (cat *Catalog) WriteCatalogToJSON(writer io.Writer) error {
+	doc := catalogJSON{
+		Format:        CatalogJSONFormat,
+		Version:       CatalogJSONVersion,
+		KnowledgeBase: nameVersionJSON{Name: cat.KnowledgeBaseName, Version: cat.KnowledgeBaseVersion},
+		Memory: catalogMemoryJSON{
+			nameVersionJSON:         nameVersionJSON{Name: cat.MemoryName, Version: cat.MemoryVersion},
+			VariableSnapshots:       cat.MemoryVariableSnapshotMap,
+			ExpressionSnapshots:     cat.MemoryExpressionSnapshotMap,
+			ExpressionAtomSnapshots: cat.MemoryExpressionAtomSnapshotMap,
+			ExpressionVariables:     cat.MemoryExpressionVariableMap,
+			ExpressionAtomVariables: cat.MemoryExpressionAtomVariableMap,
+		},
+		Nodes: make([]catalogNodeJSON, 0, len(cat.Data)),
+	}
+	ids := make([]string, 0, len(cat.Data))
+	for id := range cat.Data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		node, err := nodeToJSON(cat.Data[id])
+		if err != nil {
+
+			return fmt.Errorf("can not write node %s to JSON. got %w", id, err)
+		}
+		doc.Nodes = append(doc.Nodes, node)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(doc)
+}
+
+// ReadCatalogFromJSON would read a JSON document written by WriteCatalogToJSON from reader.
+// It will replace all values already sets in a catalog.
+// You are responsible for closing the reader stream once its done.
+func (cat *Catalog) ReadCatalogFromJSON(reader io.Reader) error {
+	var doc catalogJSON
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+
+		return fmt.Errorf("invalid JSON catalog. got %w", err)
+	}
+	if doc.Format != CatalogJSONFormat || doc.Version != CatalogJSONVersion {
+
+		return fmt.Errorf("%w: JSON catalog format %q version %q, expecting %q version %q", ErrIncompatibleCatalog, doc.Format, doc.Version, CatalogJSONFormat, CatalogJSONVersion)
+	}
+	data := make(map[string]Meta, len(doc.Nodes))
+	for _, node := range doc.Nodes {
+		meta, err := nodeFromJSON(node)
+		if err != nil {
+
+			return fmt.Errorf("can not read node %s from JSON. got %w", node.ID, err)
+		}
+		data[node.ID] = meta
+	}
+
+	cat.KnowledgeBaseName = doc.KnowledgeBase.Name
+	cat.KnowledgeBaseVersion = doc.KnowledgeBase.Version
+	cat.MemoryName = doc.Memory.Name
+	cat.MemoryVersion = doc.Memory.Version
+	cat.MemoryVariableSnapshotMap = orEmpty(doc.Memory.VariableSnapshots)
+	cat.MemoryExpressionSnapshotMap = orEmpty(doc.Memory.ExpressionSnapshots)
+	cat.MemoryExpressionAtomSnapshotMap = orEmpty(doc.Memory.ExpressionAtomSnapshots)
+	cat.MemoryExpressionVariableMap = orEmpty(doc.Memory.ExpressionVariables)
+	cat.MemoryExpressionAtomVariableMap = orEmpty(doc.Memory.ExpressionAtomVariables)
+	cat.Data = data
+
+	return nil
+}
+
+func orEmpty[V any](m map[string]V) map[string]V {
+	if m == nil {
+
+		return make(map[string]V)
+	}
+
+	return m
+}
+
+// edgesJSON collects the edges of a node, the empty targets are left out.
+type edgesJSON []catalogEdgeJSON
+
+func (edges *edgesJSON) add(label, target string) {
+	if len(target) > 0 {
+		*edges = append(*edges, catalogEdgeJSON{Label: label, Target: target})
+	}
+}
+
+func (edges *edgesJSON) addList(label string, targets []string) {
+	for i, target := range targets {
+		if len(target) > 0 {
+			index := i
+			*edges = append(*edges, catalogEdgeJSON{Label: label, Target: target, Index: &index})
+		}
+	}
+}
+
+// target returns the target of the edge with the label, empty if there is none.
+func (edges edgesJSON) target(label string) string {
+	for _, edge := range edges {
+		if edge.Label == label {
+
+			return edge.Target
+		}
+	}
+
+	return ""
+}
+
+// targets returns the targets of the edges with the label by their index, at least length of them.
+func (edges edgesJSON) targets(label string, length int) ([]string, error) {
+	for _, edge := range edges {
+		if edge.Label == label && edge.Index != nil && *edge.Index >= length {
+			length = *edge.Index + 1
+		}
+	}
+	targets := make([]string, length)
+	for _, edge := range edges {
+		if edge.Label != label {
+
+			continue
+		}
+		if edge.Index == nil || *edge.Index < 0 {
+
+			return nil, fmt.Errorf("edge %s to %s has no valid index", label, edge.Target)
+		}
+		targets[*edge.Index] = edge.Target
+	}
+
+	return targets, nil
+}
+
+func nodeToJSON(meta Meta) (catalogNodeJSON, error) {
+	node := catalogNodeJSON{
+		ID:       meta.GetAstID(),
+		Type:     nodeTypeNames[meta.GetASTType()],
+		GrlText:  meta.GetGrlText(),
+		Snapshot: meta.GetSnapshot(),
+	}
+	edges := edgesJSON{}
+	attributes := &node.Attributes
+	switch m := meta.(type) {
+	case *ArgumentListMeta:
+		edges.addList("argument", m.ArgumentASTIDs)
+	case *ArrayMapSelectorMeta:
+		edges.add("expression", m.ExpressionID)
+	case *AssigmentMeta:
+		attributes.AssignOperator = assignOperator(m)
+		edges.add("variable", m.VariableID)
+		edges.add("expression", m.ExpressionID)
+		edges.add("matchExpression", m.MatchExpressionID)
+	case *ConstantMeta:
+		literal, err := constantLiteral(m)
+		if err != nil {
+
+			return node, err
+		}
+		node.Literal = literal
+	case *ExpressionMeta:
+		if (len(m.LeftExpressionID) > 0 && len(m.RightExpressionID) > 0) || m.Operator != 0 {
+			attributes.Operator = operatorSymbol(m.Operator)
+		}
+		attributes.Negated = m.Negated
+		edges.add("left", m.LeftExpressionID)
+		edges.add("right", m.RightExpressionID)
+		edges.add("single", m.SingleExpressionID)
+		edges.add("atom", m.ExpressionAtomID)
+		edges.add("tolerance", m.ToleranceExpressionID)
+	case *ExpressionAtomMeta:
+		attributes.VariableName = m.VariableName
+		attributes.Negated = m.Negated
+		edges.add("constant", m.ConstantID)
+		edges.add("functionCall", m.FunctionCallID)
+		edges.add("variable", m.VariableID)
+		edges.add("atom", m.ExpressionAtomID)
+		edges.add("selector", m.ArrayMapSelectorID)
+		edges.add("quantifier", m.QuantifierID)
+	case *FunctionCallMeta:
+		attributes.FunctionName = m.FunctionName
+		edges.add("arguments", m.ArgumentListID)
+	case *MatchExpressionMeta:
+		attributes.ArmGrlTexts = m.ArmGrlTexts
+		attributes.ArmOperators = make([]string, len(m.ArmOperators))
+		for i, operator := range m.ArmOperators {
+			attributes.ArmOperators[i] = operatorSymbol(operator)
+		}
+		edges.add("subject", m.SubjectID)
+		edges.addList("armPattern", m.ArmPatternIDs)
+		edges.addList("armValue", m.ArmValueIDs)
+	case *RuleEntryMeta:
+		attributes.RuleName = m.RuleName
+		attributes.RuleDescription = m.RuleDescription
+		attributes.RuleID = m.RuleID
+		attributes.Salience = m.Salience
+		attributes.MaxFires = m.MaxFires
+		attributes.CooldownNanoseconds = int64(m.Cooldown)
+		if m.Criticality != CriticalityNormal {
+			attributes.Criticality = m.Criticality.String()
+		}
+		attributes.Profiles = m.Profiles
+		attributes.Sinks = m.Sinks
+		attributes.Approvals = m.Approvals
+		edges.add("when", m.WhenScopeID)
+		edges.add("then", m.ThenScopeID)
+		edges.add("else", m.ElseScopeID)
+	case *ThenExpressionMeta:
+		edges.add("assignment", m.AssignmentID)
+		edges.add("atom", m.ExpressionAtomID)
+		edges.add("collect", m.CollectStatementID)
+		edges.add("switch", m.SwitchStatementID)
+	case *SwitchStatementMeta:
+		// the values of every case are numbered one after the other, counted by caseValueCounts.
+		attributes.CaseGrlTexts = m.CaseGrlTexts
+		attributes.CaseValueCounts = make([]int, len(m.CaseValueIDs))
+		values := make([]string, 0)
+		for i, ids := range m.CaseValueIDs {
+			attributes.CaseValueCounts[i] = len(ids)
+			values = append(values, ids...)
+		}
+		edges.add("subject", m.SubjectID)
+		edges.addList("caseValue", values)
+		edges.addList("caseThen", m.CaseThenIDs)
+	case *CollectStatementMeta:
+		attributes.Name = m.Name
+		edges.add("source", m.SourceID)
+		edges.add("condition", m.ConditionID)
+	case *QuantifierMeta:
+		attributes.Quantifier = QuantifierExists
+		if m.ForAll {
+			attributes.Quantifier = QuantifierForAll
+		}
+		attributes.Name = m.Name
+		edges.add("source", m.SourceID)
+		edges.add("predicate", m.PredicateID)
+	case *ThenExpressionListMeta:
+		edges.addList("thenExpression", m.ThenExpressionIDs)
+	case *ThenScopeMeta:
+		attributes.ScriptLanguage = m.ScriptLanguage
+		attributes.ScriptSource = m.ScriptSource
+		edges.add("thenExpressionList", m.ThenExpressionListID)
+	case *VariableMeta:
+		attributes.Name = m.Name
+		edges.add("variable", m.VariableID)
+		edges.add("selector", m.ArrayMapSelectorID)
+	case *WhenScopeMeta:
+		edges.add("expression", m.ExpressionID)
+	case *HaltEntryMeta:
+		edges.add("expression", m.ExpressionID)
+	default:
+
+		return node, fmt.Errorf("unknown node meta %T", meta)
+	}
+	node.Edges = edges
+
+	return node, nil
+}
+
+func nodeFromJSON(node catalogNodeJSON) (Meta, error) {
+	nodeMeta := NodeMeta{AstID: node.ID, GrlText: node.GrlText, Snapshot: node.Snapshot}
+	edges := edgesJSON(node.Edges)
+	attributes := node.Attributes
+	switch node.Type {
+	case "ArgumentList":
+		arguments, err := edges.targets("argument", 0)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return &ArgumentListMeta{NodeMeta: nodeMeta, ArgumentASTIDs: arguments}, nil
+	case "ArrayMapSelector":
+
+		return &ArrayMapSelectorMeta{NodeMeta: nodeMeta, ExpressionID: edges.target("expression")}, nil
+	case "Assignment":
+		meta := &AssigmentMeta{
+			NodeMeta:          nodeMeta,
+			VariableID:        edges.target("variable"),
+			ExpressionID:      edges.target("expression"),
+			MatchExpressionID: edges.target("matchExpression"),
+		}
+		switch attributes.AssignOperator {
+		case "=":
+			meta.IsAssign = true
+		case "+=":
+			meta.IsPlusAssign = true
+		case "-=":
+			meta.IsMinusAssign = true
+		case "/=":
+			meta.IsDivAssign = true
+		case "*=":
+			meta.IsMulAssign = true
+		case "":
+		default:
+
+			return nil, fmt.Errorf("unknown assign operator %s", attributes.AssignOperator)
+		}
+
+		return meta, nil
+	case "Constant":
+
+		return literalConstant(nodeMeta, node.Literal)
+	case "Expression":
+		meta := &ExpressionMeta{
+			NodeMeta:              nodeMeta,
+			LeftExpressionID:      edges.target("left"),
+			RightExpressionID:     edges.target("right"),
+			SingleExpressionID:    edges.target("single"),
+			ExpressionAtomID:      edges.target("atom"),
+			Negated:               attributes.Negated,
+			ToleranceExpressionID: edges.target("tolerance"),
+		}
+		if len(attributes.Operator) > 0 {
+			operator, err := operatorOf(attributes.Operator)
+			if err != nil {
+
+				return nil, err
+			}
+			meta.Operator = operator
+		}
+
+		return meta, nil
+	case "ExpressionAtom":
+
+		return &ExpressionAtomMeta{
+			NodeMeta:           nodeMeta,
+			VariableName:       attributes.VariableName,
+			ConstantID:         edges.target("constant"),
+			FunctionCallID:     edges.target("functionCall"),
+			VariableID:         edges.target("variable"),
+			Negated:            attributes.Negated,
+			ExpressionAtomID:   edges.target("atom"),
+			ArrayMapSelectorID: edges.target("selector"),
+			QuantifierID:       edges.target("quantifier"),
+		}, nil
+	case "FunctionCall":
+
+		return &FunctionCallMeta{NodeMeta: nodeMeta, FunctionName: attributes.FunctionName, ArgumentListID: edges.target("arguments")}, nil
+	case "MatchExpression":
+		meta := &MatchExpressionMeta{
+			NodeMeta:     nodeMeta,
+			SubjectID:    edges.target("subject"),
+			ArmGrlTexts:  attributes.ArmGrlTexts,
+			ArmOperators: make([]int, len(attributes.ArmOperators)),
+		}
+		if meta.ArmGrlTexts == nil {
+			meta.ArmGrlTexts = make([]string, 0)
+		}
+		for i, symbol := range attributes.ArmOperators {
+			operator, err := operatorOf(symbol)
+			if err != nil {
+
+				return nil, err
+			}
+			meta.ArmOperators[i] = operator
+		}
+		var err error
+		if meta.ArmPatternIDs, err = edges.targets("armPattern", len(meta.ArmGrlTexts)); err != nil {
+
+			return nil, err
+		}
+		if meta.ArmValueIDs, err = edges.targets("armValue", len(meta.ArmGrlTexts)); err != nil {
+
+			return nil, err
+		}
+
+		return meta, nil
+	case "RuleEntry":
+		meta := &RuleEntryMeta{
+			NodeMeta:        nodeMeta,
+			RuleName:        attributes.RuleName,
+			RuleDescription: attributes.RuleDescription,
+			RuleID:          attributes.RuleID,
+			Salience:        attributes.Salience,
+			MaxFires:        attributes.MaxFires,
+			Cooldown:        time.Duration(attributes.CooldownNanoseconds),
+			Profiles:        attributes.Profiles,
+			Sinks:           attributes.Sinks,
+			Approvals:       attributes.Approvals,
+			WhenScopeID:     edges.target("when"),
+			ThenScopeID:     edges.target("then"),
+			ElseScopeID:     edges.target("else"),
+		}
+		if len(attributes.Criticality) > 0 {
+			criticality, err := ParseCriticality(attributes.Criticality)
+			if err != nil {
+
+				return nil, err
+			}
+			meta.Criticality = criticality
+		}
+
+		return meta, nil
+	case "ThenExpression":
+
+		return &ThenExpressionMeta{
+			NodeMeta:           nodeMeta,
+			AssignmentID:       edges.target("assignment"),
+			ExpressionAtomID:   edges.target("atom"),
+			CollectStatementID: edges.target("collect"),
+			SwitchStatementID:  edges.target("switch"),
+		}, nil
+	case "SwitchStatement":
+		meta := &SwitchStatementMeta{
+			NodeMeta:     nodeMeta,
+			SubjectID:    edges.target("subject"),
+			CaseGrlTexts: attributes.CaseGrlTexts,
+			CaseValueIDs: make([][]string, len(attributes.CaseGrlTexts)),
+		}
+		if meta.CaseGrlTexts == nil {
+			meta.CaseGrlTexts = make([]string, 0)
+		}
+		if len(attributes.CaseValueCounts) != len(meta.CaseGrlTexts) {
+
+			return nil, fmt.Errorf("switch statement %s has %d value counts for %d cases", nodeMeta.AstID, len(attributes.CaseValueCounts), len(meta.CaseGrlTexts))
+		}
+		total := 0
+		for _, count := range attributes.CaseValueCounts {
+			if count < 0 {
+
+				return nil, fmt.Errorf("switch statement %s has a negative value count", nodeMeta.AstID)
+			}
+			total += count
+		}
+		values, err := edges.targets("caseValue", total)
+		if err != nil {
+
+			return nil, err
+		}
+		if len(values) != total {
+
+			return nil, fmt.Errorf("switch statement %s has %d values, %d are counted", nodeMeta.AstID, len(values), total)
+		}
+		for i, count := range attributes.CaseValueCounts {
+			meta.CaseValueIDs[i], values = values[:count], values[count:]
+		}
+		if meta.CaseThenIDs, err = edges.targets("caseThen", len(meta.CaseGrlTexts)); err != nil {
+
+			return nil, err
+		}
+
+		return meta, nil
+	case "CollectStatement":
+
+		return &CollectStatementMeta{
+			NodeMeta:    nodeMeta,
+			Name:        attributes.Name,
+			SourceID:    edges.target("source"),
+			ConditionID: edges.target("condition"),
+		}, nil
+	case "Quantifier":
+		if attributes.Quantifier != QuantifierExists && attributes.Quantifier != QuantifierForAll {
+
+			return nil, fmt.Errorf("quantifier %s is neither %s nor %s", nodeMeta.AstID, QuantifierExists, QuantifierForAll)
+		}
+
+		return &QuantifierMeta{
+			NodeMeta:    nodeMeta,
+			ForAll:      attributes.Quantifier == QuantifierForAll,
+			Name:        attributes.Name,
+			SourceID:    edges.target("source"),
+			PredicateID: edges.target("predicate"),
+		}, nil
+	case "ThenExpressionList":
+		thenExpressions, err := edges.targets("thenExpression", 0)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return &ThenExpressionListMeta{NodeMeta: nodeMeta, ThenExpressionIDs: thenExpressions}, nil
+	case "ThenScope":
+
+		return &ThenScopeMeta{
+			NodeMeta:             nodeMeta,
+			ThenExpressionListID: edges.target("thenExpressionList"),
+			ScriptLanguage:       attributes.ScriptLanguage,
+			ScriptSource:         attributes.ScriptSource,
+		}, nil
+	case "Variable":
+
+		return &VariableMeta{NodeMeta: nodeMeta, Name: attributes.Name, VariableID: edges.target("variable"), ArrayMapSelectorID: edges.target("selector")}, nil
+	case "WhenScope":
+
+		return &WhenScopeMeta{NodeMeta: nodeMeta, ExpressionID: edges.target("expression")}, nil
+	case "HaltEntry":
+
+		return &HaltEntryMeta{NodeMeta: nodeMeta, ExpressionID: edges.target("expression")}, nil
+	}
+
+	return nil, fmt.Errorf("unknown node type %q", node.Type)
+}
+
+func assignOperator(meta *AssigmentMeta) string {
+	switch {
+	case meta.IsAssign:
+
+		return "="
+	case meta.IsPlusAssign:
+
+		return "+="
+	case meta.IsMinusAssign:
+
+		return "-="
+	case meta.IsDivAssign:
+
+		return "/="
+	case meta.IsMulAssign:
+
+		return "*="
+	}
+
+	return ""
+}
+
+// operatorOf returns the operator of the symbol written by operatorSymbol.
+func operatorOf(symbol string) (int, error) {
+	for operator := OpMul; operator <= OpIn; operator++ {
+		if operatorSymbol(operator) == symbol {
+
+			return operator, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown operator %s", symbol)
+}
+
+// constantLiteral decodes the value bytes of the constant, as written by Constant.MakeCatalog.
+func constantLiteral(meta *ConstantMeta) (*catalogLiteralJSON, error) {
+	if meta.IsNil {
+
+		return &catalogLiteralJSON{Type: "nil"}, nil
+	}
+	data := meta.ValueBytes
+	need := func(length int) error {
+		if len(data) < length {
+
+			return io.ErrUnexpectedEOF
+		}
+
+		return nil
+	}
+	switch meta.ValueType {
+	case TypeString:
+		if err := need(8); err != nil {
+
+			return nil, err
+		}
+		length := binary.LittleEndian.Uint64(data)
+		if err := need(8 + int(length)); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "string", Value: string(data[8 : 8+length])}, nil
+	case TypeSuffixLiteral:
+		if err := need(8); err != nil {
+
+			return nil, err
+		}
+		length := binary.LittleEndian.Uint64(data)
+		if err := need(8 + int(length)); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "suffix", Value: string(data[8 : 8+length])}, nil
+	case TypeInteger:
+		if err := need(8); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "integer", Value: int64(binary.LittleEndian.Uint64(data))}, nil
+	case TypeFloat:
+		if err := need(8); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "float", Value: math.Float64frombits(binary.LittleEndian.Uint64(data))}, nil
+	case TypeBoolean:
+		if err := need(1); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "boolean", Value: data[0] == 1}, nil
+	case TypeQuantity:
+		if err := need(16); err != nil {
+
+			return nil, err
+		}
+		length := binary.LittleEndian.Uint64(data[8:])
+		if err := need(16 + int(length)); err != nil {
+
+			return nil, err
+		}
+
+		return &catalogLiteralJSON{Type: "quantity", Value: math.Float64frombits(binary.LittleEndian.Uint64(data)), Unit: string(data[16 : 16+length])}, nil
+	case TypeSet, TypeOrderedMap:
+		collection, err := readCollection(bytes.NewReader(data), meta.ValueType)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return collectionLiteralJSON(collection), nil
+	}
+
+	return nil, fmt.Errorf("unknown constant value type %d", meta.ValueType)
+}
+
+// literalConstant encodes the literal into the value bytes of a constant, as written by Constant.MakeCatalog.
+func literalConstant(nodeMeta NodeMeta, literal *catalogLiteralJSON) (*ConstantMeta, error) {
+	if literal == nil {
+
+		return nil, fmt.Errorf("constant has no literal")
+	}
+	meta := &ConstantMeta{NodeMeta: nodeMeta}
+	var buff bytes.Buffer
+	word := make([]byte, 8)
+	number := func() (json.Number, error) {
+		value, ok := literal.Value.(json.Number)
+		if !ok {
+
+			return "", fmt.Errorf("%s literal %v is not a number", literal.Type, literal.Value)
+		}
+
+		return value, nil
+	}
+	switch literal.Type {
+	case "nil":
+		meta.IsNil = true
+	case "string", "suffix":
+		value, _ := literal.Value.(string)
+		meta.ValueType = TypeString
+		if literal.Type == "suffix" {
+			meta.ValueType = TypeSuffixLiteral
+		}
+		binary.LittleEndian.PutUint64(word, uint64(len(value)))
+		buff.Write(word)
+		buff.WriteString(value)
+	case "integer":
+		value, err := number()
+		if err != nil {
+
+			return nil, err
+		}
+		integer, err := value.Int64()
+		if err != nil {
+
+			return nil, err
+		}
+		meta.ValueType = TypeInteger
+		binary.LittleEndian.PutUint64(word, uint64(integer))
+		buff.Write(word)
+	case "float", "quantity":
+		value := json.Number("0")
+		if literal.Value != nil {
+			var err error
+			if value, err = number(); err != nil {
+
+				return nil, err
+			}
+		}
+		float, err := value.Float64()
+		if err != nil {
+
+			return nil, err
+		}
+		meta.ValueType = TypeFloat
+		binary.LittleEndian.PutUint64(word, math.Float64bits(float))
+		buff.Write(word)
+		if literal.Type == "quantity" {
+			meta.ValueType = TypeQuantity
+			binary.LittleEndian.PutUint64(word, uint64(len(literal.Unit)))
+			buff.Write(word)
+			buff.WriteString(literal.Unit)
+		}
+	case "boolean":
+		value, _ := literal.Value.(bool)
+		meta.ValueType = TypeBoolean
+		if value {
+			buff.WriteByte(1)
+		} else {
+			buff.WriteByte(0)
+		}
+	case "set", "orderedMap":
+		collection, err := collectionValue(literal)
+		if err != nil {
+
+			return nil, err
+		}
+		meta.ValueType = TypeSet
+		if literal.Type == "orderedMap" {
+			meta.ValueType = TypeOrderedMap
+		}
+		writeCollection(&buff, collection)
+	default:
+
+		return nil, fmt.Errorf("unknown literal type %s", literal.Type)
+	}
+	meta.ValueBytes = buff.Bytes()
+
+	return meta, nil
+}
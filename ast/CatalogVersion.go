@@ -0,0 +1,848 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxVersionLength is the longest version string accepted in a catalog header.
+	// Anything longer means the stream is not a catalog at all.
+	maxVersionLength = 16
+)
+
+var (
+	// ErrNotCatalog is returned when the stream being read does not start with a catalog header.
+	ErrNotCatalog = errors.New("stream is not a grule catalog")
+
+	// ErrIncompatibleCatalog is returned when the catalog is written in a version this library can not read.
+	// Use errors.As with *CatalogVersionError to obtain the offending version.
+	ErrIncompatibleCatalog = errors.New("incompatible catalog version")
+)
+
+// CatalogVersionError describes a catalog that was written in an unsupported format version.
+type CatalogVersionError struct {
+	// Version is the format version found in the stream.
+	Version string
+	// Supported lists all format versions this library is able to read.
+	Supported []string
+}
+
+// Error returns the description of the incompatibility.
+func (e *CatalogVersionError) Error() string {
+	hint := "it was written by an unknown library version"
+	if compareCatalogVersion(e.Version, Version) > 0 {
+		hint = "it was written by a newer library version, upgrade grule to load it"
+	} else if compareCatalogVersion(e.Version, Version) < 0 {
+		hint = "it was written by an older library version that can not be migrated, rebuild it from the GRL"
+	}
+
+	return fmt.Sprintf("%s : catalog version is %s, supported versions are %s, %s", ErrIncompatibleCatalog.Error(), e.Version, strings.Join(e.Supported, ", "), hint)
+}
+
+// Is makes CatalogVersionError matches ErrIncompatibleCatalog in errors.Is.
+func (e *CatalogVersionError) Is(target error) bool {
+
+	return target == ErrIncompatibleCatalog
+}
+
+// catalogFormat describes how to read metas written in a specific catalog version,
+// and how to upgrade them into the next version.
+// Every time the binary layout of a Meta changes, the Version is raised and the
+// previous layout is registered in catalogFormats together with its upgrade function.
+type catalogFormat struct {
+	// readMeta reads a meta of the specified type laid out in this version.
+	readMeta func(reader io.Reader, nodeType NodeType) (Meta, error)
+	// next is the version this format upgrades into, empty for the current version.
+	next string
+	// upgrade migrate the catalog data from this version into the next version.
+	upgrade func(cat *Catalog) error
+}
+
+// catalogFormats holds all catalog versions that can be read.
+var catalogFormats = map[string]*catalogFormat{
+	"1.8": {
+		readMeta: readMetaV18,
+		next:     "1.9",
+		upgrade:  upgradeFromV18,
+	},
+	"1.9": {
+		readMeta: readMetaV19,
+		next:     "1.10",
+		upgrade:  upgradeFromV19,
+	},
+	"1.10": {
+		readMeta: readMetaV110,
+		next:     "1.11",
+		upgrade:  upgradeFromV110,
+	},
+	"1.11": {
+		readMeta: readMetaV111,
+		next:     "1.12",
+		upgrade:  upgradeFromV111,
+	},
+	"1.12": {
+		readMeta: readMetaV112,
+		next:     "1.13",
+		upgrade:  upgradeFromV112,
+	},
+	"1.13": {
+		readMeta: readMetaV114,
+		next:     "1.14",
+		upgrade:  upgradeFromV113,
+	},
+	"1.14": {
+		readMeta: readMetaV114,
+		next:     "1.15",
+		upgrade:  upgradeFromV114,
+	},
+	"1.15": {
+		readMeta: readMetaV115,
+		next:     "1.16",
+		upgrade:  upgradeFromV115,
+	},
+	"1.16": {
+		readMeta: readMetaV116,
+		next:     "1.17",
+		upgrade:  upgradeFromV116,
+	},
+	"1.17": {
+		readMeta: readMetaV117,
+		next:     "1.18",
+		upgrade:  upgradeFromV117,
+	},
+	"1.18": {
+		readMeta: readMetaV118,
+		next:     "1.19",
+		upgrade:  upgradeFromV118,
+	},
+	"1.19": {
+		readMeta: readMetaV119,
+		next:     "1.20",
+		upgrade:  upgradeFromV119,
+	},
+	"1.20": {
+		readMeta: readMetaV120,
+		next:     "1.21",
+		upgrade:  upgradeFromV120,
+	},
+	"1.21": {
+		readMeta: readMetaV121,
+		next:     Version,
+		upgrade:  upgradeFromV121,
+	},
+	Version: {
+		readMeta: readMeta,
+	},
+}
+
+// SupportedCatalogVersions returns all catalog versions that can be read by this library, oldest first.
+// Catalog other than the current Version will be migrated when read.
+func SupportedCatalogVersions() []string {
+	ret := make([]string, 0, len(catalogFormats))
+	for version := range catalogFormats {
+		ret = append(ret, version)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+
+		return compareCatalogVersion(ret[i], ret[j]) < 0
+	})
+
+	return ret
+}
+
+// readMeta reads a meta in the current format.
+func readMeta(reader io.Reader, nodeType NodeType) (Meta, error) {
+	meta, err := newMeta(nodeType)
+	if err != nil {
+
+		return nil, err
+	}
+	err = meta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV18 reads a meta written in catalog version 1.8.
+// Only the rule entry layout differs from version 1.9.
+func readMetaV18(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV19(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV18From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV19 reads a meta written in catalog version 1.9.
+// Only the rule entry layout differs from version 1.10.
+func readMetaV19(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV110(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV19From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV110 reads a meta written in catalog version 1.10.
+// Only the assignment layout differs from version 1.11.
+func readMetaV110(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeAssignment {
+
+		return readMetaV111(reader, nodeType)
+	}
+	meta := &AssigmentMeta{}
+	err := meta.readMetaV110From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV111 reads a meta written in catalog version 1.11.
+// Only the rule entry layout differs from version 1.12.
+func readMetaV111(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV112(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV111From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV112 reads a meta written in catalog version 1.12.
+// Only the then scope layout differs from version 1.13.
+func readMetaV112(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeThenScope {
+
+		return readMetaV114(reader, nodeType)
+	}
+	meta := &ThenScopeMeta{}
+	err := meta.readMetaV112From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV114 reads a meta written in catalog version 1.13 or 1.14.
+// Only the rule entry layout differs from version 1.15.
+func readMetaV114(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV115(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV114From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV115 reads a meta written in catalog version 1.15.
+// Only the rule entry layout differs from version 1.16.
+func readMetaV115(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV116(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV115From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV116 reads a meta written in catalog version 1.16.
+// Only the then expression layout differs from version 1.17.
+func readMetaV116(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeThenExpression {
+
+		return readMetaV117(reader, nodeType)
+	}
+	meta := &ThenExpressionMeta{}
+	err := meta.readMetaV116From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV117 reads a meta written in catalog version 1.17.
+// Only the rule entry layout differs from version 1.18.
+func readMetaV117(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV118(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV117From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV118 reads a meta written in catalog version 1.18.
+// Only the expression layout differs from version 1.19.
+func readMetaV118(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeExpression {
+
+		return readMetaV119(reader, nodeType)
+	}
+	meta := &ExpressionMeta{}
+	err := meta.readMetaV118From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV119 reads a meta written in catalog version 1.19.
+// Only the rule entry layout differs from the current format.
+func readMetaV119(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeRuleEntry {
+
+		return readMetaV120(reader, nodeType)
+	}
+	meta := &RuleEntryMeta{}
+	err := meta.readMetaV119From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV120 reads a meta written in catalog version 1.20.
+// Only the then expression layout differs from the current format.
+func readMetaV120(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeThenExpression {
+
+		return readMetaV121(reader, nodeType)
+	}
+	meta := &ThenExpressionMeta{}
+	err := meta.readMetaV120From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// readMetaV121 reads a meta written in catalog version 1.21.
+// Only the expression atom layout differs from the current format.
+func readMetaV121(reader io.Reader, nodeType NodeType) (Meta, error) {
+	if nodeType != TypeExpressionAtom {
+
+		return readMeta(reader, nodeType)
+	}
+	meta := &ExpressionAtomMeta{}
+	err := meta.readMetaV121From(reader)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// upgradeFromV18 migrates a catalog version 1.8 into 1.9.
+// Rules written in 1.8 have neither max-fires nor cooldown, which are the zero values, so there is nothing to convert.
+func upgradeFromV18(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV19 migrates a catalog version 1.9 into 1.10.
+// Rules written in 1.9 have no id, they keep being identified by their name.
+func upgradeFromV19(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV110 migrates a catalog version 1.10 into 1.11.
+// Assignments written in 1.10 always assign an expression, never a match.
+func upgradeFromV110(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV111 migrates a catalog version 1.11 into 1.12.
+// Rules written in 1.11 have no criticality, which is the zero value normal.
+func upgradeFromV111(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV112 migrates a catalog version 1.12 into 1.13.
+// Then scopes written in 1.12 are always GRL, never a script.
+func upgradeFromV112(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV113 migrates a catalog version 1.13 into 1.14.
+// The layout of the metas is unchanged, knowledge bases written in 1.13 have no halt entry.
+func upgradeFromV113(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV114 migrates a catalog version 1.14 into 1.15.
+// Rules written in 1.14 have no sink, their messages are only collected by their type.
+func upgradeFromV114(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV115 migrates a catalog version 1.15 into 1.16.
+// Rules written in 1.15 have no approval category, they fire without approval.
+func upgradeFromV115(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV116 migrates a catalog version 1.16 into 1.17.
+// Then expressions written in 1.16 are never a collect statement.
+func upgradeFromV116(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV117 migrates a catalog version 1.17 into 1.18.
+// Rules written in 1.17 have no profile, they are active in every profile.
+func upgradeFromV117(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV118 migrates a catalog version 1.18 into 1.19.
+// Expressions written in 1.18 have no tolerance, as there was no ~== comparison.
+func upgradeFromV118(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV119 migrates a catalog version 1.19 into 1.20.
+// Rules written in 1.19 have no else scope, they do nothing when their when scope is false.
+func upgradeFromV119(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV120 migrates a catalog version 1.20 into 1.21.
+// Then expressions written in 1.20 are never a switch statement.
+func upgradeFromV120(cat *Catalog) error {
+
+	return nil
+}
+
+// upgradeFromV121 migrates a catalog version 1.21 into 1.22.
+// Expression atoms written in 1.21 are never a quantifier.
+func upgradeFromV121(cat *Catalog) error {
+
+	return nil
+}
+
+// newMeta creates an empty meta for the specified type.
+func newMeta(nodeType NodeType) (Meta, error) {
+	switch nodeType {
+	case TypeArgumentList:
+
+		return &ArgumentListMeta{}, nil
+	case TypeArrayMapSelector:
+
+		return &ArrayMapSelectorMeta{}, nil
+	case TypeAssignment:
+
+		return &AssigmentMeta{}, nil
+	case TypeConstant:
+
+		return &ConstantMeta{}, nil
+	case TypeExpression:
+
+		return &ExpressionMeta{}, nil
+	case TypeExpressionAtom:
+
+		return &ExpressionAtomMeta{}, nil
+	case TypeFunctionCall:
+
+		return &FunctionCallMeta{}, nil
+	case TypeRuleEntry:
+
+		return &RuleEntryMeta{}, nil
+	case TypeThenExpression:
+
+		return &ThenExpressionMeta{}, nil
+	case TypeThenExpressionList:
+
+		return &ThenExpressionListMeta{}, nil
+	case TypeThenScope:
+
+		return &ThenScopeMeta{}, nil
+	case TypeVariable:
+
+		return &VariableMeta{}, nil
+	case TypeWhenScope:
+
+		return &WhenScopeMeta{}, nil
+	case TypeHaltEntry:
+
+		return &HaltEntryMeta{}, nil
+	case TypeMatchExpression:
+
+		return &MatchExpressionMeta{}, nil
+	case TypeCollectStatement:
+
+		return &CollectStatementMeta{}, nil
+	case TypeSwitchStatement:
+
+		return &SwitchStatementMeta{}, nil
+	case TypeQuantifier:
+
+		return &QuantifierMeta{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown meta number %d", nodeType)
+}
+
+// readCatalogVersion reads the catalog header and returns the format used to read the rest of the stream.
+func readCatalogVersion(reader io.Reader) (string, *catalogFormat, error) {
+	length := make([]byte, 8)
+	count, err := io.ReadFull(reader, length)
+	TotalRead += uint64(count)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+
+			return "", nil, fmt.Errorf("%w : missing catalog header", ErrNotCatalog)
+		}
+
+		return "", nil, err
+	}
+	strLen := binary.LittleEndian.Uint64(length)
+	if strLen == 0 || strLen > maxVersionLength {
+
+		return "", nil, fmt.Errorf("%w : invalid header length %d", ErrNotCatalog, strLen)
+	}
+	strByte := make([]byte, strLen)
+	count, err = io.ReadFull(reader, strByte)
+	TotalRead += uint64(count)
+	if err != nil {
+
+		return "", nil, fmt.Errorf("%w : truncated catalog header", ErrNotCatalog)
+	}
+	ReadCount++
+	version := string(strByte)
+	if _, ok := parseCatalogVersion(version); !ok {
+
+		return "", nil, fmt.Errorf("%w : invalid catalog version %q", ErrNotCatalog, version)
+	}
+	format, ok := catalogFormats[version]
+	if !ok {
+
+		return version, nil, &CatalogVersionError{
+			Version:   version,
+			Supported: SupportedCatalogVersions(),
+		}
+	}
+
+	return version, format, nil
+}
+
+// migrateCatalog upgrades the catalog read in the specified version until it reaches the current Version.
+func migrateCatalog(cat *Catalog, version string) error {
+	for version != Version {
+		format := catalogFormats[version]
+		if format == nil || format.upgrade == nil {
+
+			return &CatalogVersionError{
+				Version:   version,
+				Supported: SupportedCatalogVersions(),
+			}
+		}
+		err := format.upgrade(cat)
+		if err != nil {
+
+			return fmt.Errorf("failed to migrate catalog from version %s to %s : %w", version, format.next, err)
+		}
+		version = format.next
+	}
+
+	return nil
+}
+
+// parseCatalogVersion parses a dot separated numeric version such as "1.8".
+func parseCatalogVersion(version string) ([]int, bool) {
+	parts := strings.Split(version, ".")
+	ret := make([]int, len(parts))
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 {
+
+			return nil, false
+		}
+		ret[i] = num
+	}
+
+	return ret, true
+}
+
+// compareCatalogVersion returns negative if a is older than b, positive if a is newer than b, or zero otherwise.
+func compareCatalogVersion(a, b string) int {
+	va, okA := parseCatalogVersion(a)
+	vb, okB := parseCatalogVersion(b)
+	if !okA || !okB {
+
+		return 0
+	}
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var na, nb int
+		if i < len(va) {
+			na = va[i]
+		}
+		if i < len(vb) {
+			nb = vb[i]
+		}
+		if na != nb {
+
+			return na - nb
+		}
+	}
+
+	return 0
+}
+
+// Verify checks that every reference between metas in this catalog points to an existing meta of the expected type.
+// A catalog that fail this verification can not be rebuilt into a KnowledgeBase.
+func (cat *Catalog) Verify() error {
+	for astID, meta := range cat.Data {
+		if meta.GetAstID() != astID {
+
+			return fmt.Errorf("catalog entry %s contains meta with ast id %s", astID, meta.GetAstID())
+		}
+		refs, err := metaReferences(meta)
+		if err != nil {
+
+			return err
+		}
+		for _, ref := range refs {
+			if err := cat.verifyReference(ref.astID, ref.nodeType); err != nil {
+
+				return fmt.Errorf("meta %s : %w", astID, err)
+			}
+		}
+	}
+
+	for key, value := range cat.MemoryExpressionSnapshotMap {
+		if err := cat.verifyReference(value, TypeExpression); err != nil {
+
+			return fmt.Errorf("working memory expression snapshot %s : %w", key, err)
+		}
+	}
+	for key, value := range cat.MemoryExpressionAtomSnapshotMap {
+		if err := cat.verifyReference(value, TypeExpressionAtom); err != nil {
+
+			return fmt.Errorf("working memory expression atom snapshot %s : %w", key, err)
+		}
+	}
+	for key, values := range cat.MemoryExpressionVariableMap {
+		if err := cat.verifyReference(key, TypeVariable); err != nil {
+
+			return fmt.Errorf("working memory expression variable map : %w", err)
+		}
+		for _, value := range values {
+			if err := cat.verifyReference(value, TypeExpression); err != nil {
+
+				return fmt.Errorf("working memory expression variable map of %s : %w", key, err)
+			}
+		}
+	}
+	for key, values := range cat.MemoryExpressionAtomVariableMap {
+		if err := cat.verifyReference(key, TypeVariable); err != nil {
+
+			return fmt.Errorf("working memory expression atom variable map : %w", err)
+		}
+		for _, value := range values {
+			if err := cat.verifyReference(value, TypeExpressionAtom); err != nil {
+
+				return fmt.Errorf("working memory expression atom variable map of %s : %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cat *Catalog) verifyReference(astID string, nodeType NodeType) error {
+	meta, ok := cat.Data[astID]
+	if !ok {
+
+		return fmt.Errorf("reference to ast id %s is not catalogued", astID)
+	}
+	if meta.GetASTType() != nodeType {
+
+		return fmt.Errorf("reference to ast id %s expect meta type %d but it is %d", astID, nodeType, meta.GetASTType())
+	}
+
+	return nil
+}
+
+type metaReference struct {
+	astID    string
+	nodeType NodeType
+}
+
+// metaReferences lists all non empty references a meta made to other metas.
+// ThenExpressionList and variable snapshot references are not included, as
+// BuildKnowledgeBase already tolerates their absence.
+func metaReferences(meta Meta) ([]metaReference, error) {
+	ret := make([]metaReference, 0)
+	add := func(astID string, nodeType NodeType) {
+		if len(astID) > 0 {
+			ret = append(ret, metaReference{astID: astID, nodeType: nodeType})
+		}
+	}
+	switch amet := meta.(type) {
+	case *ArgumentListMeta:
+		for _, id := range amet.ArgumentASTIDs {
+			add(id, TypeExpression)
+		}
+	case *ArrayMapSelectorMeta:
+		add(amet.ExpressionID, TypeExpression)
+	case *AssigmentMeta:
+		add(amet.ExpressionID, TypeExpression)
+		add(amet.VariableID, TypeVariable)
+		add(amet.MatchExpressionID, TypeMatchExpression)
+	case *MatchExpressionMeta:
+		if len(amet.ArmPatternIDs) != len(amet.ArmOperators) || len(amet.ArmValueIDs) != len(amet.ArmOperators) {
+
+			return nil, fmt.Errorf("match expression %s has inconsistent arms", amet.AstID)
+		}
+		add(amet.SubjectID, TypeExpression)
+		for i := range amet.ArmOperators {
+			add(amet.ArmPatternIDs[i], TypeExpression)
+			add(amet.ArmValueIDs[i], TypeExpression)
+		}
+	case *CollectStatementMeta:
+		add(amet.SourceID, TypeExpression)
+		add(amet.ConditionID, TypeExpression)
+	case *SwitchStatementMeta:
+		if len(amet.CaseValueIDs) != len(amet.CaseGrlTexts) || len(amet.CaseThenIDs) != len(amet.CaseGrlTexts) {
+
+			return nil, fmt.Errorf("switch statement %s has inconsistent cases", amet.AstID)
+		}
+		add(amet.SubjectID, TypeExpression)
+		for i := range amet.CaseGrlTexts {
+			for _, id := range amet.CaseValueIDs[i] {
+				add(id, TypeExpression)
+			}
+			add(amet.CaseThenIDs[i], TypeThenExpressionList)
+		}
+	case *QuantifierMeta:
+		add(amet.SourceID, TypeExpression)
+		add(amet.PredicateID, TypeExpression)
+	case *ConstantMeta:
+		// constant have no reference
+	case *ExpressionMeta:
+		add(amet.LeftExpressionID, TypeExpression)
+		add(amet.RightExpressionID, TypeExpression)
+		add(amet.SingleExpressionID, TypeExpression)
+		add(amet.ExpressionAtomID, TypeExpressionAtom)
+		add(amet.ToleranceExpressionID, TypeExpression)
+	case *ExpressionAtomMeta:
+		add(amet.ConstantID, TypeConstant)
+		add(amet.ExpressionAtomID, TypeExpressionAtom)
+		add(amet.VariableID, TypeVariable)
+		add(amet.FunctionCallID, TypeFunctionCall)
+		add(amet.ArrayMapSelectorID, TypeArrayMapSelector)
+		add(amet.QuantifierID, TypeQuantifier)
+	case *FunctionCallMeta:
+		add(amet.ArgumentListID, TypeArgumentList)
+	case *RuleEntryMeta:
+		add(amet.WhenScopeID, TypeWhenScope)
+		add(amet.ThenScopeID, TypeThenScope)
+		add(amet.ElseScopeID, TypeThenScope)
+	case *ThenExpressionMeta:
+		add(amet.AssignmentID, TypeAssignment)
+		add(amet.ExpressionAtomID, TypeExpressionAtom)
+		add(amet.CollectStatementID, TypeCollectStatement)
+		add(amet.SwitchStatementID, TypeSwitchStatement)
+	case *ThenExpressionListMeta:
+		// missing then expression is logged and skipped by BuildKnowledgeBase
+	case *ThenScopeMeta:
+		add(amet.ThenExpressionListID, TypeThenExpressionList)
+	case *VariableMeta:
+		add(amet.VariableID, TypeVariable)
+		add(amet.ArrayMapSelectorID, TypeArrayMapSelector)
+	case *WhenScopeMeta:
+		add(amet.ExpressionID, TypeExpression)
+	case *HaltEntryMeta:
+		add(amet.ExpressionID, TypeExpression)
+	default:
+
+		return nil, fmt.Errorf("unrecognized meta type %d", meta.GetASTType())
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,335 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCatalog() *Catalog {
+
+	return &Catalog{
+		KnowledgeBaseName:    "Test",
+		KnowledgeBaseVersion: "0.0.1",
+		Data: map[string]Meta{
+			"rule": &RuleEntryMeta{
+				NodeMeta:    NodeMeta{AstID: "rule"},
+				RuleName:    "TestRule",
+				Salience:    10,
+				WhenScopeID: "when",
+			},
+			"when": &WhenScopeMeta{
+				NodeMeta:     NodeMeta{AstID: "when"},
+				ExpressionID: "expr",
+			},
+			"expr": &ExpressionMeta{
+				NodeMeta:         NodeMeta{AstID: "expr"},
+				ExpressionAtomID: "atom",
+			},
+			"atom": &ExpressionAtomMeta{
+				NodeMeta:   NodeMeta{AstID: "atom"},
+				ConstantID: "const",
+			},
+			"const": &ConstantMeta{
+				NodeMeta:   NodeMeta{AstID: "const"},
+				ValueType:  TypeBoolean,
+				ValueBytes: []byte{1},
+			},
+		},
+		MemoryName:                  "Test",
+		MemoryVersion:               "0.0.1",
+		MemoryExpressionSnapshotMap: map[string]string{"true": "expr"},
+	}
+}
+
+func writeTestCatalog(t *testing.T, cat *Catalog, version string) []byte {
+	buffer := &bytes.Buffer{}
+	err := cat.WriteCatalogToWriter(buffer)
+	assert.NoError(t, err)
+	data := buffer.Bytes()[8+len(Version):]
+
+	header := &bytes.Buffer{}
+	assert.NoError(t, WriteStringToWriter(header, version))
+
+	return append(header.Bytes(), data...)
+}
+
+func TestCatalog_ReadIncompatibleVersion(t *testing.T) {
+	data := writeTestCatalog(t, newTestCatalog(), "99.0")
+	err := (&Catalog{}).ReadCatalogFromReader(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrIncompatibleCatalog))
+	var versionErr *CatalogVersionError
+	assert.True(t, errors.As(err, &versionErr))
+	assert.Equal(t, "99.0", versionErr.Version)
+	assert.Contains(t, versionErr.Supported, Version)
+	assert.Contains(t, err.Error(), "newer library version")
+
+	data = writeTestCatalog(t, newTestCatalog(), "0.1")
+	err = (&Catalog{}).ReadCatalogFromReader(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrIncompatibleCatalog))
+	assert.Contains(t, err.Error(), "older library version")
+}
+
+func TestCatalog_ReadNotCatalog(t *testing.T) {
+	err := (&Catalog{}).ReadCatalogFromReader(bytes.NewReader([]byte("this is definitely not a catalog")))
+	assert.True(t, errors.Is(err, ErrNotCatalog))
+
+	err = (&Catalog{}).ReadCatalogFromReader(bytes.NewReader(nil))
+	assert.True(t, errors.Is(err, ErrNotCatalog))
+
+	data := writeTestCatalog(t, newTestCatalog(), "abc")
+	err = (&Catalog{}).ReadCatalogFromReader(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrNotCatalog))
+}
+
+func TestCatalog_ReadTruncated(t *testing.T) {
+	data := writeTestCatalog(t, newTestCatalog(), Version)
+	err := (&Catalog{}).ReadCatalogFromReader(bytes.NewReader(data[:len(data)/2]))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF))
+}
+
+func TestCatalog_ReadMigrate(t *testing.T) {
+	catalogFormats["1.7"] = &catalogFormat{
+		readMeta: readMeta,
+		next:     Version,
+		upgrade: func(cat *Catalog) error {
+			for _, meta := range cat.Data {
+				if rule, ok := meta.(*RuleEntryMeta); ok {
+					rule.RuleDescription = "migrated"
+				}
+			}
+
+			return nil
+		},
+	}
+	defer delete(catalogFormats, "1.7")
+	assert.Equal(t, []string{"1.7", "1.8", "1.9", "1.10", "1.11", "1.12", "1.13", "1.14", "1.15", "1.16", "1.17", "1.18", "1.19", "1.20", "1.21", Version}, SupportedCatalogVersions())
+
+	data := writeTestCatalog(t, newTestCatalog(), "1.7")
+	cat := &Catalog{}
+	err := cat.ReadCatalogFromReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "migrated", cat.Data["rule"].(*RuleEntryMeta).RuleDescription)
+
+	kb, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, kb.RuleEntries["TestRule"].Salience)
+}
+
+func TestCatalog_ReadOlderAssignment(t *testing.T) {
+	assignment := &AssigmentMeta{
+		NodeMeta:     NodeMeta{AstID: "assign"},
+		VariableID:   "var",
+		ExpressionID: "expr",
+		IsAssign:     true,
+	}
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, assignment.WriteMetaTo(buffer))
+	// catalogs older than 1.11 have no match expression id at the end of an assignment.
+	data := buffer.Bytes()[:buffer.Len()-8]
+
+	for _, version := range []string{"1.8", "1.9", "1.10"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeAssignment)
+		assert.NoError(t, err, version)
+		assert.True(t, assignment.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+}
+
+func TestCatalog_ReadOlderExpression(t *testing.T) {
+	expression := &ExpressionMeta{
+		NodeMeta:          NodeMeta{AstID: "expr"},
+		LeftExpressionID:  "left",
+		RightExpressionID: "right",
+		Operator:          OpEq,
+	}
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, expression.WriteMetaTo(buffer))
+	// catalogs older than 1.19 have no tolerance id at the end of an expression.
+	data := buffer.Bytes()[:buffer.Len()-8]
+
+	for _, version := range []string{"1.17", "1.18"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeExpression)
+		assert.NoError(t, err, version)
+		assert.True(t, expression.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+}
+
+func TestCatalog_ReadOlderRuleEntry(t *testing.T) {
+	rule := &RuleEntryMeta{
+		NodeMeta:    NodeMeta{AstID: "rule"},
+		RuleName:    "TestRule",
+		RuleID:      "R-1",
+		WhenScopeID: "when",
+		ThenScopeID: "then",
+	}
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, rule.WriteMetaTo(buffer))
+	// catalogs older than 1.20 have no else scope id at the end of a rule entry.
+	data := buffer.Bytes()[:buffer.Len()-8]
+
+	for _, version := range []string{"1.18", "1.19"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeRuleEntry)
+		assert.NoError(t, err, version)
+		assert.True(t, rule.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+
+	// catalogs older than 1.18 have no profiles either.
+	data = data[:len(data)-8]
+
+	for _, version := range []string{"1.16", "1.17"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeRuleEntry)
+		assert.NoError(t, err, version)
+		assert.True(t, rule.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+
+	// catalogs older than 1.16 have no approvals either.
+	data = data[:len(data)-8]
+
+	reader := bytes.NewReader(data)
+	meta, err := catalogFormats["1.15"].readMeta(reader, TypeRuleEntry)
+	assert.NoError(t, err)
+	assert.True(t, rule.Equals(meta))
+	assert.Equal(t, 0, reader.Len())
+
+	// catalogs older than 1.15 have no sinks either.
+	data = data[:len(data)-8]
+
+	for _, version := range []string{"1.12", "1.13", "1.14"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeRuleEntry)
+		assert.NoError(t, err, version)
+		assert.True(t, rule.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+
+	// catalogs older than 1.12 have no criticality either.
+	data = data[:len(data)-8]
+
+	for _, version := range []string{"1.10", "1.11"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeRuleEntry)
+		assert.NoError(t, err, version)
+		assert.True(t, rule.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+}
+
+func TestCatalog_ReadOlderThenExpression(t *testing.T) {
+	thenExpr := &ThenExpressionMeta{
+		NodeMeta:     NodeMeta{AstID: "then-expr"},
+		AssignmentID: "assign",
+	}
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, thenExpr.WriteMetaTo(buffer))
+	// catalogs older than 1.21 have no switch statement id at the end of a then expression.
+	data := buffer.Bytes()[:buffer.Len()-8]
+
+	for _, version := range []string{"1.17", "1.19", "1.20"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeThenExpression)
+		assert.NoError(t, err, version)
+		assert.True(t, thenExpr.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+
+	// catalogs older than 1.17 have no collect statement id either.
+	data = data[:len(data)-8]
+
+	for _, version := range []string{"1.12", "1.13", "1.14", "1.15", "1.16"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeThenExpression)
+		assert.NoError(t, err, version)
+		assert.True(t, thenExpr.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+}
+
+func TestCatalog_ReadOlderExpressionAtom(t *testing.T) {
+	atom := &ExpressionAtomMeta{
+		NodeMeta:   NodeMeta{AstID: "atom"},
+		VariableID: "variable",
+		Negated:    true,
+	}
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, atom.WriteMetaTo(buffer))
+	// catalogs older than 1.22 have no quantifier id at the end of an expression atom.
+	data := buffer.Bytes()[:buffer.Len()-8]
+
+	for _, version := range []string{"1.8", "1.15", "1.20", "1.21"} {
+		reader := bytes.NewReader(data)
+		meta, err := catalogFormats[version].readMeta(reader, TypeExpressionAtom)
+		assert.NoError(t, err, version)
+		assert.True(t, atom.Equals(meta), version)
+		assert.Equal(t, 0, reader.Len(), version)
+	}
+}
+
+func TestCatalog_Verify(t *testing.T) {
+	cat := newTestCatalog()
+	assert.NoError(t, cat.Verify())
+
+	cat.Data["atom"].(*ExpressionAtomMeta).ConstantID = "missing"
+	_, err := cat.BuildKnowledgeBase()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing is not catalogued")
+
+	cat = newTestCatalog()
+	cat.Data["when"].(*WhenScopeMeta).ExpressionID = "atom"
+	assert.Error(t, cat.Verify())
+
+	cat = newTestCatalog()
+	cat.MemoryExpressionSnapshotMap["false"] = "const"
+	assert.Error(t, cat.Verify())
+}
+
+func TestCatalog_RateLimitRoundTrip(t *testing.T) {
+	cat := newTestCatalog()
+	rule := cat.Data["rule"].(*RuleEntryMeta)
+	rule.MaxFires = 2
+	rule.Cooldown = 10 * time.Minute
+	rule.Criticality = CriticalityLow
+	rule.Profiles = []string{"eu-only", "beta"}
+	rule.Sinks = []string{"fraud-queue"}
+	rule.Approvals = []string{"large-refund"}
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, cat.WriteCatalogToWriter(buffer))
+	loaded := &Catalog{}
+	assert.NoError(t, loaded.ReadCatalogFromReader(buffer))
+	assert.True(t, rule.Equals(loaded.Data["rule"]))
+
+	kb, err := loaded.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, kb.RuleEntries["TestRule"].MaxFires)
+	assert.Equal(t, 10*time.Minute, kb.RuleEntries["TestRule"].Cooldown)
+	assert.Equal(t, CriticalityLow, kb.RuleEntries["TestRule"].Criticality)
+	assert.Equal(t, []string{"fraud-queue"}, kb.RuleEntries["TestRule"].Sinks)
+	assert.Equal(t, []string{"large-refund"}, kb.RuleEntries["TestRule"].Approvals)
+}
@@ -0,0 +1,226 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// CollectItemName is the name the element being tested is known by in the where condition of a collect statement.
+const CollectItemName = "item"
+
+// NewCollectStatement creates new CollectStatement instance
+func NewCollectStatement() *CollectStatement {
+
+	return &CollectStatement{
+		AstID: unique.NewID(),
+	}
+}
+
+// CollectStatement AST graph node of `collect HighValueItems from Cart.Items where item.Price > 100`.
+// Every element of the source for which the condition is true is collected into a new fact named Name.
+// Without a condition, every element is collected.
+type CollectStatement struct {
+	AstID   string
+	GrlText string
+
+	Name      string
+	Source    *Expression
+	Condition *Expression
+}
+
+// CollectStatementReceiver must be implemented by all other ast graph that uses a collect statement
+type CollectStatementReceiver interface {
+	AcceptCollectStatement(collect *CollectStatement) error
+}
+
+// AcceptExpression will accept the source, then the condition Expression into this CollectStatement
+func (e *CollectStatement) AcceptExpression(exp *Expression) error {
+	if e.Source == nil {
+		e.Source = exp
+
+		return nil
+	}
+	if e.Condition != nil {
+
+		return errors.New("condition for collect already assigned")
+	}
+	e.Condition = exp
+
+	return nil
+}
+
+// MakeCatalog will create a catalog entry from CollectStatement node.
+func (e *CollectStatement) MakeCatalog(cat *Catalog) {
+	meta := &CollectStatementMeta{
+		NodeMeta: NodeMeta{
+			AstID:    e.AstID,
+			GrlText:  e.GrlText,
+			Snapshot: e.GetSnapshot(),
+		},
+		Name: e.Name,
+	}
+	if cat.AddMeta(e.AstID, meta) {
+		if e.Source != nil {
+			meta.SourceID = e.Source.AstID
+			e.Source.MakeCatalog(cat)
+		}
+		if e.Condition != nil {
+			meta.ConditionID = e.Condition.AstID
+			e.Condition.MakeCatalog(cat)
+		}
+	}
+}
+
+// Clone will clone this CollectStatement. The new clone will have an identical structure
+func (e *CollectStatement) Clone(cloneTable *pkg.CloneTable) *CollectStatement {
+
+	return &CollectStatement{
+		AstID:     unique.NewID(),
+		GrlText:   e.GrlText,
+		Name:      e.Name,
+		Source:    cloneMatchExpression(cloneTable, e.Source),
+		Condition: cloneMatchExpression(cloneTable, e.Condition),
+	}
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *CollectStatement) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax related to this graph when it wast constructed
+func (e *CollectStatement) GetGrlText() string {
+
+	return e.GrlText
+}
+
+// GetSnapshot will create a structure signature or AST graph
+func (e *CollectStatement) GetSnapshot() string {
+	var buff strings.Builder
+	buff.WriteString(COLLECTSTATEMENT)
+	buff.WriteString("(")
+	buff.WriteString(e.Name)
+	buff.WriteString("<-")
+	if e.Source != nil {
+		buff.WriteString(e.Source.GetSnapshot())
+	}
+	if e.Condition != nil {
+		buff.WriteString("?")
+		buff.WriteString(e.Condition.GetSnapshot())
+	}
+	buff.WriteString(")")
+
+	return buff.String()
+}
+
+// SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
+// call this function.
+func (e *CollectStatement) SetGrlText(grlText string) {
+	e.GrlText = grlText
+}
+
+// Execute evaluates the source and adds the elements matching the condition into the data context as a new fact.
+// The collection is a slice of the source element type, it is empty when nothing matches.
+func (e *CollectStatement) Execute(dataContext IDataContext, memory *WorkingMemory) error {
+	source, err := e.Source.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return fmt.Errorf("collect source error. got %w", err)
+	}
+	for source.Kind() == reflect.Ptr || source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+	if source.Kind() != reflect.Slice && source.Kind() != reflect.Array {
+
+		return fmt.Errorf("can not collect from %s, it is not an array or slice", e.Source.GrlText)
+	}
+	collected := reflect.MakeSlice(reflect.SliceOf(source.Type().Elem()), 0, source.Len())
+	if e.Condition == nil {
+		collected = reflect.AppendSlice(collected, source.Slice(0, source.Len()))
+	} else {
+		collected, err = e.collectMatching(source, collected, dataContext, memory)
+		if err != nil {
+
+			return err
+		}
+	}
+	err = dataContext.Add(e.Name, collected.Interface())
+	if err != nil {
+
+		return err
+	}
+	dataContext.IncrementVariableChangeCount()
+	memory.Reset(e.Name)
+
+	return nil
+}
+
+// collectMatching binds every element of the source as the item fact and appends those the condition holds for.
+// The item fact that was in the data context before is put back afterward.
+func (e *CollectStatement) collectMatching(source, collected reflect.Value, dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
+	previous := dataContext.Get(CollectItemName)
+	defer func() {
+		restoreCollectItem(dataContext, previous)
+		memory.Reset(CollectItemName)
+	}()
+	for i := 0; i < source.Len(); i++ {
+		element := source.Index(i)
+		err := dataContext.Add(CollectItemName, element.Interface())
+		if err != nil {
+
+			return collected, err
+		}
+		memory.Reset(CollectItemName)
+		val, err := e.Condition.Evaluate(dataContext, memory)
+		if err != nil {
+
+			return collected, fmt.Errorf("collect condition %s error on element %d. got %w", e.Condition.GrlText, i, err)
+		}
+		if val.Kind() != reflect.Bool {
+
+			return collected, fmt.Errorf("collect condition %s is not a boolean expression", e.Condition.GrlText)
+		}
+		if val.Bool() {
+			collected = reflect.Append(collected, element)
+		}
+	}
+
+	return collected, nil
+}
+
+// restoreCollectItem puts back the item fact a collect statement replaced, or removes the item if there was none.
+func restoreCollectItem(dataContext IDataContext, previous model.ValueNode) {
+	if ctx, ok := dataContext.(*DataContext); ok {
+		if previous == nil {
+			delete(ctx.ObjectStore, CollectItemName)
+		} else {
+			ctx.ObjectStore[CollectItemName] = previous
+		}
+
+		return
+	}
+	if previous != nil {
+		_ = dataContext.Add(CollectItemName, previous.Value().Interface())
+	}
+}
@@ -43,6 +43,9 @@ type Constant struct {
 	WorkingMemory *WorkingMemory
 	Value         reflect.Value
 	IsNil         bool
+	// Literal is the text of a suffix literal such as 10_USD, its Value is parsed again from it when
+	// the constant is loaded from a catalog.
+	Literal string
 }
 
 // MakeCatalog will create a catalog entry from Constant node.
@@ -56,7 +59,17 @@ func (e *Constant) MakeCatalog(cat *Catalog) {
 	}
 	if cat.AddMeta(e.AstID, meta) {
 		var buff bytes.Buffer
-		switch e.Value.Kind() {
+		kind := e.Value.Kind()
+		if len(e.Literal) > 0 {
+			// the user value is parsed again from the literal when the catalog is loaded.
+			kind = reflect.Invalid
+			meta.ValueType = TypeSuffixLiteral
+			length := make([]byte, 8)
+			binary.LittleEndian.PutUint64(length, uint64(len(e.Literal)))
+			buff.Write(length)
+			buff.WriteString(e.Literal)
+		}
+		switch kind {
 		case reflect.String:
 			meta.ValueType = TypeString
 			length := make([]byte, 8)
@@ -81,6 +94,25 @@ func (e *Constant) MakeCatalog(cat *Catalog) {
 			} else {
 				buff.WriteByte(0)
 			}
+		case reflect.Struct:
+			if quantity, ok := e.Value.Interface().(pkg.Quantity); ok {
+				meta.ValueType = TypeQuantity
+				floatData := make([]byte, 8)
+				binary.LittleEndian.PutUint64(floatData, math.Float64bits(quantity.Value))
+				buff.Write(floatData)
+				length := make([]byte, 8)
+				binary.LittleEndian.PutUint64(length, uint64(len(quantity.Unit)))
+				buff.Write(length)
+				buff.WriteString(quantity.Unit)
+			}
+			switch e.Value.Interface().(type) {
+			case pkg.Set:
+				meta.ValueType = TypeSet
+				writeCollection(&buff, e.Value.Interface())
+			case pkg.OrderedMap:
+				meta.ValueType = TypeOrderedMap
+				writeCollection(&buff, e.Value.Interface())
+			}
 		}
 		meta.ValueBytes = buff.Bytes()
 		meta.IsNil = e.IsNil
@@ -93,6 +125,7 @@ func (e *Constant) Clone(cloneTable *pkg.CloneTable) *Constant {
 		AstID:   unique.NewID(),
 		GrlText: e.GrlText,
 		Value:   e.Value,
+		Literal: e.Literal,
 	}
 
 	return clone
@@ -122,6 +155,12 @@ func (e *Constant) GetSnapshot() string {
 	buff.WriteString("(")
 	buff.WriteString(e.Value.Kind().String())
 	buff.WriteString("->")
+	if len(e.Literal) > 0 {
+		buff.WriteString(e.Literal)
+		buff.WriteString(")")
+
+		return buff.String()
+	}
 	switch e.Value.Kind() {
 	case reflect.String:
 		buff.WriteString(fmt.Sprintf("\"%s\"", e.Value.String()))
@@ -133,6 +172,13 @@ func (e *Constant) GetSnapshot() string {
 		buff.WriteString(fmt.Sprintf("%f", e.Value.Float()))
 	case reflect.Bool:
 		buff.WriteString(fmt.Sprintf("%v", e.Value.Bool()))
+	case reflect.Struct:
+		if quantity, ok := e.Value.Interface().(pkg.Quantity); ok {
+			buff.WriteString(quantity.String())
+		}
+		if pkg.IsCollection(e.Value) {
+			buff.WriteString(fmt.Sprintf("%s", e.Value.Interface()))
+		}
 	}
 	buff.WriteString(")")
 
@@ -160,6 +206,39 @@ func (e *Constant) AcceptFloatLiteral(fun *FloatLiteral) {
 	e.Value = reflect.ValueOf(fun.Float)
 }
 
+// AcceptQuantityLiteral will accept quantity literal
+func (e *Constant) AcceptQuantityLiteral(fun *QuantityLiteral) {
+	e.Value = reflect.ValueOf(fun.Quantity)
+}
+
+// AcceptSuffixLiteral will accept suffix literal
+func (e *Constant) AcceptSuffixLiteral(fun *SuffixLiteral) {
+	e.Value = reflect.ValueOf(fun.Value)
+	e.Literal = fun.Literal
+}
+
+// AcceptCollectionLiteral will accept set or ordered map literal
+func (e *Constant) AcceptCollectionLiteral(fun *CollectionLiteral) error {
+	collection, err := fun.Collection()
+	if err != nil {
+
+		return err
+	}
+	e.Value = reflect.ValueOf(collection)
+
+	return nil
+}
+
+// Interface returns the value of this constant, nil for the nil literal.
+func (e *Constant) Interface() interface{} {
+	if e.IsNil || !e.Value.IsValid() {
+
+		return nil
+	}
+
+	return e.Value.Interface()
+}
+
 // AcceptBooleanLiteral will accept boolean literal
 func (e *Constant) AcceptBooleanLiteral(fun *BooleanLiteral) {
 	e.Value = reflect.ValueOf(fun.Boolean)
@@ -0,0 +1,35 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import "time"
+
+// NewCooldown create new Cooldown AST object
+func NewCooldown(duration time.Duration) *Cooldown {
+
+	return &Cooldown{
+		Duration: duration,
+	}
+}
+
+// Cooldown is a simple AST object that stores the minimum duration between two firings of a rule
+type Cooldown struct {
+	Duration time.Duration
+}
+
+// CooldownReceiver must be implemented by any AST object that stores cooldown
+type CooldownReceiver interface {
+	AcceptCooldown(cooldown *Cooldown) error
+}
@@ -0,0 +1,71 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Criticality tells how important a rule is, low criticality rules are skipped when the engine is degraded.
+type Criticality int
+
+const (
+	// CriticalityLow marks a rule that can be shed under load, such as an expensive enrichment.
+	CriticalityLow Criticality = -1
+	// CriticalityNormal is the criticality of a rule that does not declare any.
+	CriticalityNormal Criticality = 0
+	// CriticalityHigh marks a rule that must always be evaluated, such as a mandatory compliance check.
+	CriticalityHigh Criticality = 1
+)
+
+// ParseCriticality parses the criticality name as written in GRL, low, normal or high regardless of the case.
+func ParseCriticality(name string) (Criticality, error) {
+	switch strings.ToLower(name) {
+	case "low":
+
+		return CriticalityLow, nil
+	case "normal":
+
+		return CriticalityNormal, nil
+	case "high":
+
+		return CriticalityHigh, nil
+	}
+
+	return CriticalityNormal, fmt.Errorf("criticality must be low, normal or high, got %s", name)
+}
+
+// String returns the criticality name as written in GRL.
+func (c Criticality) String() string {
+	switch c {
+	case CriticalityLow:
+
+		return "low"
+	case CriticalityNormal:
+
+		return "normal"
+	case CriticalityHigh:
+
+		return "high"
+	}
+
+	return fmt.Sprintf("Criticality(%d)", int(c))
+}
+
+// CriticalityReceiver must be implemented by any AST object that stores criticality
+type CriticalityReceiver interface {
+	AcceptCriticality(criticality Criticality) error
+}
@@ -32,6 +32,22 @@ func NewDataContext() IDataContext {
 	}
 }
 
+// NewDataContextWithAccessors will create a new DataContext instance whose facts resolve the fields they do not
+// export through their methods, as named by the convention, such as model.StandardAccessors().
+func NewDataContextWithAccessors(convention model.AccessorConvention) IDataContext {
+
+	return NewDataContextWithOptions(model.NodeOptions{Accessors: &convention})
+}
+
+// NewDataContextWithOptions will create a new DataContext instance whose facts, and the nodes reached from them,
+// have the options. Its facts are described into options.Types, if set, instead of the cache of model.PrepareType.
+func NewDataContextWithOptions(options model.NodeOptions) IDataContext {
+	ctx := NewDataContext().(*DataContext)
+	ctx.options = options
+
+	return ctx
+}
+
 // DataContext holds all structs instance to be used in rule execution environment.
 type DataContext struct {
 	ObjectStore map[string]model.ValueNode
@@ -40,6 +56,35 @@ type DataContext struct {
 	variableChangeCount uint64
 	complete            bool
 	ruleEntry           *RuleEntry
+	// options are given to the node of every fact.
+	options model.NodeOptions
+}
+
+// nodeOptionsProvider is implemented by the data contexts whose facts have node options.
+type nodeOptionsProvider interface {
+	NodeOptions() model.NodeOptions
+}
+
+// Accessors returns the convention resolving the fields the facts do not export, nil if there is none.
+func (ctx *DataContext) Accessors() *model.AccessorConvention {
+
+	return ctx.options.Accessors
+}
+
+// NodeOptions returns the options given to the node of every fact.
+func (ctx *DataContext) NodeOptions() model.NodeOptions {
+
+	return ctx.options
+}
+
+// nodeOptionsOf returns the node options of the data context, the zero options if it has none.
+func nodeOptionsOf(dataCtx IDataContext) model.NodeOptions {
+	if provider, ok := dataCtx.(nodeOptionsProvider); ok {
+
+		return provider.NodeOptions()
+	}
+
+	return model.NodeOptions{}
 }
 
 func (ctx *DataContext) GetKeys() []string {
@@ -58,6 +103,11 @@ func (ctx *DataContext) Complete() {
 	ctx.complete = true
 }
 
+// ResetComplete clears the completed mark, so the DataContext can be used for another execution
+func (ctx *DataContext) ResetComplete() {
+	ctx.complete = false
+}
+
 // IsComplete checks whether the DataContext has been completed
 func (ctx *DataContext) IsComplete() bool {
 
@@ -80,6 +130,7 @@ type IDataContext interface {
 	HasVariableChange() bool
 
 	Add(key string, obj interface{}) error
+	AddAll(facts map[string]interface{}) error
 	AddJSON(key string, JSON []byte) error
 	Get(key string) model.ValueNode
 	GetKeys() []string
@@ -113,7 +164,23 @@ func (ctx *DataContext) HasVariableChange() bool {
 
 // Add will add struct instance into rule execution context
 func (ctx *DataContext) Add(key string, obj interface{}) error {
-	ctx.ObjectStore[key] = model.NewGoValueNode(reflect.ValueOf(obj), key)
+	ctx.ObjectStore[key] = model.NewGoValueNodeWithOptions(reflect.ValueOf(obj), key, ctx.options)
+
+	return nil
+}
+
+// AddAll will add many struct instances into rule execution context at once. The fields of every distinct struct
+// type are described once up front, instead of on the first access to the fields of each type during the execution.
+func (ctx *DataContext) AddAll(facts map[string]interface{}) error {
+	prepared := make(map[reflect.Type]bool)
+	for key, obj := range facts {
+		value := reflect.ValueOf(obj)
+		if value.IsValid() && !prepared[value.Type()] {
+			prepared[value.Type()] = true
+			ctx.options.Types.Prepare(value.Type())
+		}
+		ctx.ObjectStore[key] = model.NewGoValueNodeWithOptions(value, key, ctx.options)
+	}
 
 	return nil
 }
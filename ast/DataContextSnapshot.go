@@ -0,0 +1,413 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// ErrReadOnlySnapshot is returned, or panicked with, when something tries to modify a DataContextSnapshot.
+var ErrReadOnlySnapshot = errors.New("data context snapshot is read only")
+
+// NewDataContextSnapshot creates a read only view of the facts in the data context, as they are now.
+func NewDataContextSnapshot(dataCtx IDataContext) *DataContextSnapshot {
+
+	return &DataContextSnapshot{
+		dataCtx: dataCtx,
+		nodes:   make(map[string]*snapshotNode),
+	}
+}
+
+// DataContextSnapshot is a read only view of a data context, it is safe to be read by concurrent evaluations.
+// Fact values are copied the first time they are read, every later read of the same fact or field returns
+// the copy. Any attempt to modify the snapshot fails with ErrReadOnlySnapshot, facts are modified through the
+// data context itself once the evaluations are done.
+type DataContextSnapshot struct {
+	mutex   sync.Mutex
+	dataCtx IDataContext
+	nodes   map[string]*snapshotNode
+}
+
+// node returns the snapshot node of a path, the create function is called on the first read of the path only.
+func (s *DataContextSnapshot) node(parent *snapshotNode, path string, create func() (model.ValueNode, error)) (*snapshotNode, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if node, ok := s.nodes[path]; ok {
+
+		return node, nil
+	}
+	valueNode, err := create()
+	if err != nil {
+
+		return nil, err
+	}
+	node := &snapshotNode{
+		snapshot: s,
+		parent:   parent,
+		path:     path,
+		node:     valueNode,
+		value:    copyValue(valueNode.Value()),
+	}
+	s.nodes[path] = node
+
+	return node, nil
+}
+
+// copyValue copies a value that is not a reference, so later changes to the fact do not affect it.
+// References such as pointers, slices and maps are kept, what they refer to is copied when read.
+func copyValue(value reflect.Value) reflect.Value {
+	if !value.IsValid() || !value.CanInterface() {
+
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+
+		return value
+	}
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	return copied
+}
+
+// Get returns the read only node of a fact, nil if the fact is not in the data context.
+func (s *DataContextSnapshot) Get(key string) model.ValueNode {
+	node, err := s.node(nil, key, func() (model.ValueNode, error) {
+		valueNode := s.dataCtx.Get(key)
+		if valueNode == nil {
+
+			return nil, &MissingFactError{Name: key}
+		}
+
+		return valueNode, nil
+	})
+	if err != nil {
+
+		return nil
+	}
+
+	return node
+}
+
+// GetKeys returns the keys of the facts in the data context.
+func (s *DataContextSnapshot) GetKeys() []string {
+
+	return s.dataCtx.GetKeys()
+}
+
+// IsRetracted checks if a key fact is currently retracted.
+func (s *DataContextSnapshot) IsRetracted(key string) bool {
+
+	return s.dataCtx.IsRetracted(key)
+}
+
+// Retracted returns list of retracted key facts.
+func (s *DataContextSnapshot) Retracted() []string {
+
+	return s.dataCtx.Retracted()
+}
+
+// IsComplete checks whether the data context has been completed.
+func (s *DataContextSnapshot) IsComplete() bool {
+
+	return s.dataCtx.IsComplete()
+}
+
+// HasVariableChange returns true if there are variable changes in the data context.
+func (s *DataContextSnapshot) HasVariableChange() bool {
+
+	return s.dataCtx.HasVariableChange()
+}
+
+// GetRuleEntry returns the rule entry the data context is executing.
+func (s *DataContextSnapshot) GetRuleEntry() *RuleEntry {
+
+	return s.dataCtx.GetRuleEntry()
+}
+
+// Add fails, the snapshot is read only.
+func (s *DataContextSnapshot) Add(key string, obj interface{}) error {
+
+	return fmt.Errorf("can not add fact %s. got %w", key, ErrReadOnlySnapshot)
+}
+
+// AddAll fails, the snapshot is read only.
+func (s *DataContextSnapshot) AddAll(facts map[string]interface{}) error {
+
+	return fmt.Errorf("can not add %d facts. got %w", len(facts), ErrReadOnlySnapshot)
+}
+
+// AddJSON fails, the snapshot is read only.
+func (s *DataContextSnapshot) AddJSON(key string, JSON []byte) error {
+
+	return fmt.Errorf("can not add fact %s. got %w", key, ErrReadOnlySnapshot)
+}
+
+// Retract panics, the snapshot is read only.
+func (s *DataContextSnapshot) Retract(key string) {
+	panic(fmt.Errorf("can not retract fact %s. got %w", key, ErrReadOnlySnapshot))
+}
+
+// Complete panics, the snapshot is read only.
+func (s *DataContextSnapshot) Complete() {
+	panic(fmt.Errorf("can not complete. got %w", ErrReadOnlySnapshot))
+}
+
+// Reset panics, the snapshot is read only.
+func (s *DataContextSnapshot) Reset() {
+	panic(fmt.Errorf("can not reset. got %w", ErrReadOnlySnapshot))
+}
+
+// ResetVariableChangeCount panics, the snapshot is read only.
+func (s *DataContextSnapshot) ResetVariableChangeCount() {
+	panic(fmt.Errorf("can not reset the variable change count. got %w", ErrReadOnlySnapshot))
+}
+
+// IncrementVariableChangeCount panics, the snapshot is read only.
+func (s *DataContextSnapshot) IncrementVariableChangeCount() {
+	panic(fmt.Errorf("can not change a variable. got %w", ErrReadOnlySnapshot))
+}
+
+// SetRuleEntry panics, the snapshot is read only.
+func (s *DataContextSnapshot) SetRuleEntry(re *RuleEntry) {
+	panic(fmt.Errorf("can not set the rule entry. got %w", ErrReadOnlySnapshot))
+}
+
+// snapshotNode is the read only model.ValueNode of a DataContextSnapshot.
+type snapshotNode struct {
+	snapshot *DataContextSnapshot
+	parent   *snapshotNode
+	path     string
+	node     model.ValueNode
+	value    reflect.Value
+}
+
+// child returns the snapshot node of a member of this node.
+func (n *snapshotNode) child(member string, create func() (model.ValueNode, error)) (model.ValueNode, error) {
+	child, err := n.snapshot.node(n, n.path+member, create)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// childValue returns the copied value of a member of this node.
+func (n *snapshotNode) childValue(member string, read func() (reflect.Value, error)) (reflect.Value, error) {
+	child, err := n.snapshot.node(n, n.path+member, func() (model.ValueNode, error) {
+		value, err := read()
+		if err != nil {
+
+			return nil, err
+		}
+
+		return n.node.ContinueWithValue(value, member), nil
+	})
+	if err != nil {
+
+		return reflect.Value{}, err
+	}
+
+	return child.value, nil
+}
+
+func (n *snapshotNode) IdentifiedAs() string {
+
+	return n.node.IdentifiedAs()
+}
+
+func (n *snapshotNode) Value() reflect.Value {
+
+	return n.value
+}
+
+func (n *snapshotNode) HasParent() bool {
+
+	return n.parent != nil
+}
+
+func (n *snapshotNode) Parent() model.ValueNode {
+	if n.parent == nil {
+
+		return nil
+	}
+
+	return n.parent
+}
+
+// ContinueWithValue returns a node of a value computed from this node, such as a function result. It is not
+// part of the snapshot, it is not cached.
+func (n *snapshotNode) ContinueWithValue(value reflect.Value, identifiedAs string) model.ValueNode {
+
+	return &snapshotNode{
+		snapshot: NewDataContextSnapshot(n.snapshot.dataCtx),
+		parent:   n,
+		path:     identifiedAs,
+		node:     n.node.ContinueWithValue(value, identifiedAs),
+		value:    value,
+	}
+}
+
+func (n *snapshotNode) GetValue() (reflect.Value, error) {
+
+	return n.value, nil
+}
+
+func (n *snapshotNode) GetType() (reflect.Type, error) {
+
+	return n.node.GetType()
+}
+
+func (n *snapshotNode) IsArray() bool {
+
+	return n.node.IsArray()
+}
+
+func (n *snapshotNode) GetArrayType() (reflect.Type, error) {
+
+	return n.node.GetArrayType()
+}
+
+func (n *snapshotNode) GetArrayValueAt(index int) (reflect.Value, error) {
+
+	return n.childValue(fmt.Sprintf("[%d]", index), func() (reflect.Value, error) {
+
+		return n.node.GetArrayValueAt(index)
+	})
+}
+
+func (n *snapshotNode) GetChildNodeByIndex(index int) (model.ValueNode, error) {
+
+	return n.child(fmt.Sprintf("[%d]", index), func() (model.ValueNode, error) {
+
+		return n.node.GetChildNodeByIndex(index)
+	})
+}
+
+func (n *snapshotNode) SetArrayValueAt(index int, value reflect.Value) error {
+
+	return fmt.Errorf("can not set %s[%d]. got %w", n.path, index, ErrReadOnlySnapshot)
+}
+
+func (n *snapshotNode) AppendValue(value []reflect.Value) error {
+
+	return fmt.Errorf("can not append to %s. got %w", n.path, ErrReadOnlySnapshot)
+}
+
+func (n *snapshotNode) Length() (int, error) {
+
+	return n.node.Length()
+}
+
+func (n *snapshotNode) IsMap() bool {
+
+	return n.node.IsMap()
+}
+
+func (n *snapshotNode) GetMapValueAt(index reflect.Value) (reflect.Value, error) {
+
+	return n.childValue(fmt.Sprintf("[%#v]", index.Interface()), func() (reflect.Value, error) {
+
+		return n.node.GetMapValueAt(index)
+	})
+}
+
+func (n *snapshotNode) SetMapValueAt(index, newValue reflect.Value) error {
+
+	return fmt.Errorf("can not set %s[%v]. got %w", n.path, index, ErrReadOnlySnapshot)
+}
+
+func (n *snapshotNode) GetChildNodeBySelector(index reflect.Value) (model.ValueNode, error) {
+
+	return n.child(fmt.Sprintf("[%#v]", index.Interface()), func() (model.ValueNode, error) {
+
+		return n.node.GetChildNodeBySelector(index)
+	})
+}
+
+func (n *snapshotNode) IsInterface() bool {
+
+	return n.node.IsInterface()
+}
+
+func (n *snapshotNode) IsObject() bool {
+
+	return n.node.IsObject()
+}
+
+func (n *snapshotNode) GetObjectValueByField(field string) (reflect.Value, error) {
+
+	return n.childValue("."+field, func() (reflect.Value, error) {
+
+		return n.node.GetObjectValueByField(field)
+	})
+}
+
+func (n *snapshotNode) GetObjectTypeByField(field string) (reflect.Type, error) {
+
+	return n.node.GetObjectTypeByField(field)
+}
+
+func (n *snapshotNode) SetObjectValueByField(field string, newValue reflect.Value) error {
+
+	return fmt.Errorf("can not set %s.%s. got %w", n.path, field, ErrReadOnlySnapshot)
+}
+
+// CallFunction calls the function on the fact itself, functions called in when scopes evaluated concurrently
+// must be safe for concurrent use.
+func (n *snapshotNode) CallFunction(funcName string, args ...reflect.Value) (reflect.Value, error) {
+
+	return n.node.CallFunction(funcName, args...)
+}
+
+func (n *snapshotNode) GetChildNodeByField(field string) (model.ValueNode, error) {
+
+	return n.child("."+field, func() (model.ValueNode, error) {
+
+		return n.node.GetChildNodeByField(field)
+	})
+}
+
+func (n *snapshotNode) IsTime() bool {
+
+	return n.node.IsTime()
+}
+
+func (n *snapshotNode) IsInteger() bool {
+
+	return n.node.IsInteger()
+}
+
+func (n *snapshotNode) IsReal() bool {
+
+	return n.node.IsReal()
+}
+
+func (n *snapshotNode) IsBool() bool {
+
+	return n.node.IsBool()
+}
+
+func (n *snapshotNode) IsString() bool {
+
+	return n.node.IsString()
+}
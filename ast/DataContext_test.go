@@ -16,6 +16,11 @@ package ast
 
 import (
 	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/model"
+	"github.com/stretchr/testify/assert"
 )
 
 type TestAStruct struct {
@@ -42,3 +47,37 @@ func (tcs *TestCStruct) EchoVariad(ss ...string) int {
 
 	return len(ss)
 }
+
+func TestDataContext_AddAll(t *testing.T) {
+	facts := make(map[string]interface{})
+	for i := 0; i < 50; i++ {
+		facts[fmt.Sprintf("C%d", i)] = &TestCStruct{Str: fmt.Sprintf("fact %d", i), It: i}
+	}
+	facts["A"] = &TestAStruct{}
+	dctx := NewDataContext()
+	assert.NoError(t, dctx.AddAll(facts))
+	assert.Len(t, dctx.GetKeys(), 51)
+
+	val, err := dctx.Get("C42").GetObjectValueByField("Str")
+	assert.NoError(t, err)
+	assert.Equal(t, "fact 42", val.String())
+	assert.NoError(t, dctx.Get("C7").SetObjectValueByField("It", reflect.ValueOf(70)))
+	assert.Equal(t, 70, facts["C7"].(*TestCStruct).It)
+
+	assert.ErrorIs(t, NewDataContextSnapshot(dctx).AddAll(facts), ErrReadOnlySnapshot)
+}
+
+func TestDataContext_Options(t *testing.T) {
+	types := model.NewTypeDescriptors()
+	dctx := NewDataContextWithOptions(model.NodeOptions{Types: types})
+	assert.NoError(t, dctx.AddAll(map[string]interface{}{"C": &TestCStruct{Str: "fact"}}))
+	val, err := dctx.Get("C").GetObjectValueByField("Str")
+	assert.NoError(t, err)
+	assert.Equal(t, "fact", val.String())
+	assert.Nil(t, dctx.(*DataContext).Accessors())
+
+	// the quantifiers bind their elements with the options of the data context.
+	bound := &boundDataContext{IDataContext: dctx, name: "E"}
+	assert.Equal(t, types, nodeOptionsOf(bound).Types)
+	assert.Equal(t, model.NodeOptions{}, nodeOptionsOf(NewDataContext()))
+}
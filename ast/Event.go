@@ -0,0 +1,102 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is implemented by the facts that happened at a point in time, such as a payment attempt.
+// The temporal built-in functions compare events, a time.Time is a point event too.
+type Event interface {
+	EventTime() time.Time
+}
+
+// IntervalEvent is an event that lasted from its EventTime until its EventEnd, such as an account lock.
+type IntervalEvent interface {
+	Event
+	EventEnd() time.Time
+}
+
+// eventInterval returns when the event started and ended, a point event ends when it starts.
+func eventInterval(event interface{}) (start, end time.Time) {
+	switch e := event.(type) {
+	case time.Time:
+
+		return e, e
+	case IntervalEvent:
+
+		return e.EventTime(), e.EventEnd()
+	case Event:
+
+		return e.EventTime(), e.EventTime()
+	}
+	panic(fmt.Sprintf("%T is not an event, it must be a time.Time or implement ast.Event", event))
+}
+
+// boundedBy tells whether the gap is within the optional bound, there is no bound if none is given.
+func boundedBy(gap time.Duration, within []time.Duration) bool {
+	if len(within) == 0 {
+
+		return true
+	}
+
+	return gap <= within[0]
+}
+
+// Before will check if the event ended before the other event started, e.g. Before(Lock, Payment, "5m") is true if
+// the payment was attempted within 5 minutes after the end of the lock.
+func (gf *BuiltInFunctions) Before(event, other interface{}, within ...time.Duration) bool {
+	_, end := eventInterval(event)
+	otherStart, _ := eventInterval(other)
+
+	return end.Before(otherStart) && boundedBy(otherStart.Sub(end), within)
+}
+
+// After will check if the event started after the other event ended, within the bound if any.
+func (gf *BuiltInFunctions) After(event, other interface{}, within ...time.Duration) bool {
+
+	return gf.Before(other, event, within...)
+}
+
+// Coincides will check if both events started and ended at the same time, give or take the tolerance if any.
+func (gf *BuiltInFunctions) Coincides(event, other interface{}, tolerance ...time.Duration) bool {
+	start, end := eventInterval(event)
+	otherStart, otherEnd := eventInterval(other)
+	startGap, endGap := start.Sub(otherStart).Abs(), end.Sub(otherEnd).Abs()
+	if len(tolerance) == 0 {
+
+		return startGap == 0 && endGap == 0
+	}
+
+	return startGap <= tolerance[0] && endGap <= tolerance[0]
+}
+
+// Overlaps will check if the event started before the other event and ended while the other event was going on.
+func (gf *BuiltInFunctions) Overlaps(event, other interface{}) bool {
+	start, end := eventInterval(event)
+	otherStart, otherEnd := eventInterval(other)
+
+	return start.Before(otherStart) && end.After(otherStart) && end.Before(otherEnd)
+}
+
+// During will check if the event started and ended while the other event was going on.
+func (gf *BuiltInFunctions) During(event, other interface{}) bool {
+	start, end := eventInterval(event)
+	otherStart, otherEnd := eventInterval(other)
+
+	return start.After(otherStart) && end.Before(otherEnd)
+}
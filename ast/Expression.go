@@ -55,8 +55,15 @@ const (
 	OpAnd
 	// OpOr Logical Or operator
 	OpOr
+	// OpApproxEq Approximately Equals operator, the numbers differ by at most the tolerance
+	OpApproxEq
+	// OpIn Membership operator, the value is an element of the array, slice or set, or a key of the map
+	OpIn
 )
 
+// DefaultTolerance is the tolerance of the ~== comparisons that have no within, unless the engine sets another one.
+const DefaultTolerance = 1e-9
+
 // NewExpression creates new Expression instance
 func NewExpression() *Expression {
 
@@ -79,6 +86,9 @@ type Expression struct {
 	Negated          bool
 	Value            reflect.Value
 
+	// Tolerance is the within expression of a ~== comparison, nil if it has none.
+	Tolerance *Expression
+
 	Evaluated bool
 }
 
@@ -108,6 +118,10 @@ func (e *Expression) MakeCatalog(cat *Catalog) {
 			meta.ExpressionAtomID = e.ExpressionAtom.AstID
 			e.ExpressionAtom.MakeCatalog(cat)
 		}
+		if e.Tolerance != nil {
+			meta.ToleranceExpressionID = e.Tolerance.AstID
+			e.Tolerance.MakeCatalog(cat)
+		}
 		meta.Operator = e.Operator
 		meta.Negated = e.Negated
 	}
@@ -163,6 +177,16 @@ func (e *Expression) Clone(cloneTable *pkg.CloneTable) *Expression {
 		}
 	}
 
+	if e.Tolerance != nil {
+		if cloneTable.IsCloned(e.Tolerance.AstID) {
+			clone.Tolerance = cloneTable.Records[e.Tolerance.AstID].CloneInstance.(*Expression)
+		} else {
+			cloned := e.Tolerance.Clone(cloneTable)
+			clone.Tolerance = cloned
+			cloneTable.MarkCloned(e.Tolerance.AstID, cloned.AstID, e.Tolerance, cloned)
+		}
+	}
+
 	return clone
 }
 
@@ -224,43 +248,17 @@ func (e *Expression) GetSnapshot() string {
 		buff.WriteString(e.LeftExpression.GetSnapshot())
 		buff.WriteString(")")
 
-		switch e.Operator {
-		case OpMul:
-			buff.WriteString("*")
-		case OpDiv:
-			buff.WriteString("/")
-		case OpMod:
-			buff.WriteString("%")
-		case OpAdd:
-			buff.WriteString("+")
-		case OpSub:
-			buff.WriteString("-")
-		case OpBitAnd:
-			buff.WriteString("&")
-		case OpBitOr:
-			buff.WriteString("|")
-		case OpGT:
-			buff.WriteString(">")
-		case OpLT:
-			buff.WriteString("<")
-		case OpGTE:
-			buff.WriteString(">=")
-		case OpLTE:
-			buff.WriteString("<=")
-		case OpEq:
-			buff.WriteString("==")
-		case OpNEq:
-			buff.WriteString("!=")
-		case OpAnd:
-			buff.WriteString("&&")
-		case OpOr:
-			buff.WriteString("||")
-		}
+		buff.WriteString(operatorSymbol(e.Operator))
 
 		buff.WriteString("ER(")
 		buff.WriteString(e.RightExpression.GetSnapshot())
 		buff.WriteString(")")
 	}
+	if e.Tolerance != nil {
+		buff.WriteString("within(")
+		buff.WriteString(e.Tolerance.GetSnapshot())
+		buff.WriteString(")")
+	}
 	if e.ExpressionAtom != nil {
 		buff.WriteString("EA(")
 		buff.WriteString(e.ExpressionAtom.GetSnapshot())
@@ -271,6 +269,65 @@ func (e *Expression) GetSnapshot() string {
 	return buff.String()
 }
 
+// operatorSymbol returns the GRL symbol of an operator as used in snapshots.
+func operatorSymbol(operator int) string {
+	switch operator {
+	case OpMul:
+
+		return "*"
+	case OpDiv:
+
+		return "/"
+	case OpMod:
+
+		return "%"
+	case OpAdd:
+
+		return "+"
+	case OpSub:
+
+		return "-"
+	case OpBitAnd:
+
+		return "&"
+	case OpBitOr:
+
+		return "|"
+	case OpGT:
+
+		return ">"
+	case OpLT:
+
+		return "<"
+	case OpGTE:
+
+		return ">="
+	case OpLTE:
+
+		return "<="
+	case OpEq:
+
+		return "=="
+	case OpNEq:
+
+		return "!="
+	case OpAnd:
+
+		return "&&"
+	case OpOr:
+
+		return "||"
+	case OpApproxEq:
+
+		return "~=="
+	case OpIn:
+
+		return "in"
+	}
+
+	return ""
+}
+
 // SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
 // call this function.
 func (e *Expression) SetGrlText(grlText string) {
@@ -279,7 +336,7 @@ func (e *Expression) SetGrlText(grlText string) {
 
 // Evaluate will evaluate this AST graph for when scope evaluation
 func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
-	if e.Evaluated == true {
+	if e.Evaluated == true && memory.caches() {
 
 		return e.Value, nil
 	}
@@ -300,7 +357,7 @@ func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (
 				if e.Value.Kind() == reflect.Bool {
 					e.Value = reflect.ValueOf(!e.Value.Bool())
 				} else {
-					AstLog.Warnf("Expression \"%s\" is a negation to non boolean value, negation is ignored.", e.SingleExpression.GrlText)
+					memory.log().Warnf("Expression \"%s\" is a negation to non boolean value, negation is ignored.", e.SingleExpression.GrlText)
 				}
 			}
 			e.Evaluated = true
@@ -316,7 +373,7 @@ func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (
 		if e.Operator == OpAnd {
 			if lerr != nil {
 
-				return reflect.Value{}, fmt.Errorf("left hand expression error. got %v", lerr)
+				return reflect.Value{}, fmt.Errorf("left hand expression error. got %w", lerr)
 			}
 			val, opErr = pkg.EvaluateLogicSingle(lval)
 			if opErr == nil && !val.Bool() {
@@ -329,7 +386,7 @@ func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (
 		if e.Operator == OpOr {
 			if lerr != nil {
 
-				return reflect.Value{}, fmt.Errorf("left hand expression error. got %v", lerr)
+				return reflect.Value{}, fmt.Errorf("left hand expression error. got %w", lerr)
 			}
 			val, opErr = pkg.EvaluateLogicSingle(lval)
 			if opErr == nil && val.Bool() {
@@ -343,44 +400,63 @@ func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (
 		rval, rerr := e.RightExpression.Evaluate(dataContext, memory)
 		if lerr != nil {
 
-			return reflect.Value{}, fmt.Errorf("left hand expression error. got %v", lerr)
+			return reflect.Value{}, fmt.Errorf("left hand expression error. got %w", lerr)
 		}
 		if rerr != nil {
 
-			return reflect.Value{}, fmt.Errorf("right hand expression error.  got %v", rerr)
+			return reflect.Value{}, fmt.Errorf("right hand expression error.  got %w", rerr)
 		}
+		if memory != nil && (e.Operator >= OpGT && e.Operator <= OpNEq || e.Operator == OpApproxEq) {
+			lval, rval, opErr = pkg.CoerceStringNumber(memory.StringNumberComparison, operatorSymbol(e.Operator), lval, rval)
+			if opErr != nil {
 
-		switch e.Operator {
-		case OpMul:
-			val, opErr = pkg.EvaluateMultiplication(lval, rval)
-		case OpDiv:
-			val, opErr = pkg.EvaluateDivision(lval, rval)
-		case OpMod:
-			val, opErr = pkg.EvaluateModulo(lval, rval)
-		case OpAdd:
-			val, opErr = pkg.EvaluateAddition(lval, rval)
-		case OpSub:
-			val, opErr = pkg.EvaluateSubtraction(lval, rval)
-		case OpBitAnd:
-			val, opErr = pkg.EvaluateBitAnd(lval, rval)
-		case OpBitOr:
-			val, opErr = pkg.EvaluateBitOr(lval, rval)
-		case OpGT:
-			val, opErr = pkg.EvaluateGreaterThan(lval, rval)
-		case OpLT:
-			val, opErr = pkg.EvaluateLesserThan(lval, rval)
-		case OpGTE:
-			val, opErr = pkg.EvaluateGreaterThanEqual(lval, rval)
-		case OpLTE:
-			val, opErr = pkg.EvaluateLesserThanEqual(lval, rval)
-		case OpEq:
-			val, opErr = pkg.EvaluateEqual(lval, rval)
-		case OpNEq:
-			val, opErr = pkg.EvaluateNotEqual(lval, rval)
-		case OpAnd:
-			val, opErr = pkg.EvaluateLogicAnd(lval, rval)
-		case OpOr:
-			val, opErr = pkg.EvaluateLogicOr(lval, rval)
+				return reflect.Value{}, opErr
+			}
+		}
+
+		if memory.computesQuantity(e.Operator, lval, rval) {
+			val, opErr = memory.Units.Evaluate(operatorSymbol(e.Operator), lval, rval)
+		} else {
+			switch e.Operator {
+			case OpMul:
+				val, opErr = pkg.EvaluateMultiplication(lval, rval)
+			case OpDiv:
+				val, opErr = pkg.EvaluateDivision(lval, rval)
+			case OpMod:
+				val, opErr = pkg.EvaluateModulo(lval, rval)
+			case OpAdd:
+				val, opErr = pkg.EvaluateAddition(lval, rval)
+			case OpSub:
+				val, opErr = pkg.EvaluateSubtraction(lval, rval)
+			case OpBitAnd:
+				val, opErr = pkg.EvaluateBitAnd(lval, rval)
+			case OpBitOr:
+				val, opErr = pkg.EvaluateBitOr(lval, rval)
+			case OpGT:
+				val, opErr = pkg.EvaluateGreaterThan(lval, rval)
+			case OpLT:
+				val, opErr = pkg.EvaluateLesserThan(lval, rval)
+			case OpGTE:
+				val, opErr = pkg.EvaluateGreaterThanEqual(lval, rval)
+			case OpLTE:
+				val, opErr = pkg.EvaluateLesserThanEqual(lval, rval)
+			case OpEq:
+				val, opErr = pkg.EvaluateEqual(lval, rval)
+			case OpNEq:
+				val, opErr = pkg.EvaluateNotEqual(lval, rval)
+			case OpAnd:
+				val, opErr = pkg.EvaluateLogicAnd(lval, rval)
+			case OpOr:
+				val, opErr = pkg.EvaluateLogicOr(lval, rval)
+			case OpApproxEq:
+				var tolerance reflect.Value
+				tolerance, opErr = e.tolerance(dataContext, memory)
+				if opErr == nil {
+					val, opErr = pkg.EvaluateApproxEqual(lval, rval, tolerance)
+				}
+			case OpIn:
+				val, opErr = pkg.EvaluateIn(lval, rval)
+			}
 		}
 		if opErr == nil {
 			e.Value = val
@@ -392,3 +468,23 @@ func (e *Expression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (
 
 	return reflect.Value{}, nil
 }
+
+// tolerance evaluates the tolerance of a ~== comparison: its within expression, or else the tolerance of the working
+// memory, or else DefaultTolerance.
+func (e *Expression) tolerance(dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
+	if e.Tolerance != nil {
+		val, err := e.Tolerance.Evaluate(dataContext, memory)
+		if err != nil {
+
+			return reflect.Value{}, fmt.Errorf("tolerance expression error. got %w", err)
+		}
+
+		return val, nil
+	}
+	if memory != nil && memory.Tolerance > 0 {
+
+		return reflect.ValueOf(memory.Tolerance), nil
+	}
+
+	return reflect.ValueOf(DefaultTolerance), nil
+}
@@ -45,6 +45,7 @@ type ExpressionAtom struct {
 	Negated          bool
 	ExpressionAtom   *ExpressionAtom
 	ArrayMapSelector *ArrayMapSelector
+	Quantifier       *Quantifier
 
 	Value     reflect.Value
 	ValueNode model.ValueNode
@@ -82,6 +83,10 @@ func (e *ExpressionAtom) MakeCatalog(cat *Catalog) {
 			meta.ArrayMapSelectorID = e.ArrayMapSelector.AstID
 			e.ArrayMapSelector.MakeCatalog(cat)
 		}
+		if e.Quantifier != nil {
+			meta.QuantifierID = e.Quantifier.AstID
+			e.Quantifier.MakeCatalog(cat)
+		}
 		meta.VariableName = e.VariableName
 		meta.Negated = e.Negated
 	}
@@ -151,6 +156,16 @@ func (e *ExpressionAtom) Clone(cloneTable *pkg.CloneTable) *ExpressionAtom {
 		}
 	}
 
+	if e.Quantifier != nil {
+		if cloneTable.IsCloned(e.Quantifier.AstID) {
+			clone.Quantifier = cloneTable.Records[e.Quantifier.AstID].CloneInstance.(*Quantifier)
+		} else {
+			cloned := e.Quantifier.Clone(cloneTable)
+			clone.Quantifier = cloned
+			cloneTable.MarkCloned(e.Quantifier.AstID, cloned.AstID, e.Quantifier, cloned)
+		}
+	}
+
 	return clone
 }
 
@@ -203,6 +218,17 @@ func (e *ExpressionAtom) AcceptConstant(cons *Constant) error {
 	return nil
 }
 
+// AcceptQuantifier will accept a Quantifier AST graph into this ast graph
+func (e *ExpressionAtom) AcceptQuantifier(quantifier *Quantifier) error {
+	if e.Quantifier != nil {
+
+		return errors.New("quantifier for ExpressionAtom already assigned")
+	}
+	e.Quantifier = quantifier
+
+	return nil
+}
+
 // AcceptArrayMapSelector accept an array map selector into this variable graph
 func (e *ExpressionAtom) AcceptArrayMapSelector(sel *ArrayMapSelector) error {
 	e.ArrayMapSelector = sel
@@ -231,6 +257,8 @@ func (e *ExpressionAtom) GetSnapshot() string {
 		buff.WriteString(e.Variable.GetSnapshot())
 	} else if e.Constant != nil {
 		buff.WriteString(e.Constant.GetSnapshot())
+	} else if e.Quantifier != nil {
+		buff.WriteString(e.Quantifier.GetSnapshot())
 	} else if e.FunctionCall != nil && e.ExpressionAtom == nil {
 		buff.WriteString(e.FunctionCall.GetSnapshot())
 	} else if e.FunctionCall == nil && e.ExpressionAtom != nil && len(e.VariableName) == 0 {
@@ -265,7 +293,7 @@ func (e *ExpressionAtom) SetGrlText(grlText string) {
 
 // Evaluate will evaluate this AST graph for when scope evaluation
 func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemory) (val reflect.Value, err error) {
-	if e.Evaluated == true {
+	if e.Evaluated == true && memory.caches() {
 
 		return e.Value, nil
 	}
@@ -294,6 +322,17 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 
 		return val, err
 	}
+	if e.Quantifier != nil {
+		val, err := e.Quantifier.Evaluate(dataContext, memory)
+		if err != nil {
+
+			return reflect.Value{}, err
+		}
+		e.Value = val
+		e.ValueNode = model.NewGoValueNode(val, e.Quantifier.Keyword()+"()")
+
+		return val, nil
+	}
 	if e.ExpressionAtom == nil && e.FunctionCall != nil {
 		valueNode := dataContext.Get("DEFUNC")
 		args, err := e.FunctionCall.EvaluateArgumentList(dataContext, memory)
@@ -301,7 +340,7 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 
 			return reflect.Value{}, err
 		}
-		ret, err := valueNode.CallFunction(e.FunctionCall.FunctionName, args...)
+		ret, err := callFunction(dataContext, valueNode, e.FunctionCall.FunctionName, e.FunctionCall.FunctionName, args)
 		if err != nil {
 
 			return reflect.Value{}, err
@@ -325,7 +364,7 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 				e.Value = reflect.ValueOf(!e.Value.Bool())
 				e.ValueNode = model.NewGoValueNode(e.Value, fmt.Sprintf("!%s", e.GrlText))
 			} else {
-				AstLog.Warnf("Expression \"%s\" is a negation to non boolean value, negation is ignored.", e.ExpressionAtom.GrlText)
+				memory.log().Warnf("Expression \"%s\" is a negation to non boolean value, negation is ignored.", e.ExpressionAtom.GrlText)
 			}
 		}
 
@@ -340,13 +379,18 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 			return reflect.ValueOf(nil), err
 		}
 
+		if receiver := e.ExpressionAtom.ValueNode.Value(); nilReceiver(receiver, e.FunctionCall.FunctionName, true) {
+
+			return e.evaluateNilChain(memory, receiver, e.FunctionCall.FunctionName, true)
+		}
+
 		args, err := e.FunctionCall.EvaluateArgumentList(dataContext, memory)
 		if err != nil {
 
 			return reflect.ValueOf(nil), err
 		}
 
-		retVal, err := e.ExpressionAtom.ValueNode.CallFunction(e.FunctionCall.FunctionName, args...)
+		retVal, err := callFunction(dataContext, e.ExpressionAtom.ValueNode, e.ExpressionAtom.GrlText+"."+e.FunctionCall.FunctionName, e.FunctionCall.FunctionName, args)
 		if err != nil {
 
 			return reflect.ValueOf(nil), err
@@ -366,6 +410,10 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 
 			return reflect.Value{}, err
 		}
+		if receiver := e.ExpressionAtom.ValueNode.Value(); nilReceiver(receiver, e.VariableName, false) {
+
+			return e.evaluateNilChain(memory, receiver, e.VariableName, false)
+		}
 		valueNode, err := e.ExpressionAtom.ValueNode.GetChildNodeByField(e.VariableName)
 		if err != nil {
 
@@ -415,3 +463,96 @@ func (e *ExpressionAtom) Evaluate(dataContext IDataContext, memory *WorkingMemor
 
 	return reflect.Value{}, fmt.Errorf("this portion of code should not be reached")
 }
+
+// evaluateNilChain evaluates the call, or the field, of a chain whose receiver is nil, such as the IsBlacklisted
+// of Fact.Account().Owner().IsBlacklisted() when Owner returns nil. It is an error naming the nil part of the chain,
+// unless the working memory chains nil safely, then it is the zero value of what the call or the field would be.
+func (e *ExpressionAtom) evaluateNilChain(memory *WorkingMemory, receiver reflect.Value, name string, call bool) (reflect.Value, error) {
+	if memory == nil || !memory.NilSafeChaining {
+		if call {
+
+			return reflect.Value{}, fmt.Errorf("%s is nil, can not call %s", e.ExpressionAtom.GrlText, name)
+		}
+
+		return reflect.Value{}, fmt.Errorf("%s is nil, can not get %s", e.ExpressionAtom.GrlText, name)
+	}
+	typ, ok := nilChainType(receiver, name, call)
+	if !ok {
+
+		return reflect.Value{}, fmt.Errorf("%s is nil and has no type, can not tell what %s is", e.ExpressionAtom.GrlText, name)
+	}
+	e.Value = reflect.Value{}
+	if typ != nil {
+		e.Value = reflect.Zero(typ)
+	}
+	e.ValueNode = e.ExpressionAtom.ValueNode.ContinueWithValue(e.Value, name)
+	e.Evaluated = true
+
+	return e.Value, nil
+}
+
+// nilReceiver tells if the receiver of a call, or of a field, is nil. A nil pointer whose type declares the method
+// with a pointer receiver is not nil here, the method is called as Go does and may handle the nil itself.
+func nilReceiver(receiver reflect.Value, name string, call bool) bool {
+	if !receiver.IsValid() {
+
+		return true
+	}
+	switch receiver.Kind() {
+	case reflect.Interface:
+
+		return receiver.IsNil()
+	case reflect.Ptr:
+		if !receiver.IsNil() {
+
+			return false
+		}
+		if call {
+			_, valueReceiver := receiver.Type().Elem().MethodByName(name)
+			_, declared := receiver.Type().MethodByName(name)
+
+			return valueReceiver || !declared
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// nilChainType returns the type the call, or the field, of the nil receiver returns, nil if the method returns
+// nothing. It returns false if the receiver has no such method or field.
+func nilChainType(receiver reflect.Value, name string, call bool) (reflect.Type, bool) {
+	if !receiver.IsValid() {
+
+		return nil, false
+	}
+	typ := receiver.Type()
+	if call {
+		method, ok := typ.MethodByName(name)
+		if !ok {
+
+			return nil, false
+		}
+		if method.Type.NumOut() == 0 {
+
+			return nil, true
+		}
+
+		return method.Type.Out(0), true
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+
+		return nil, false
+	}
+	field, ok := typ.FieldByName(name)
+	if !ok {
+
+		return nil, false
+	}
+
+	return field.Type, true
+}
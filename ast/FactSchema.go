@@ -0,0 +1,400 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ReferenceKind tells what an UnresolvedReference refers to.
+type ReferenceKind int
+
+const (
+	// ReferenceFact is a fact the data context is expected to hold, such as Order.
+	ReferenceFact ReferenceKind = iota
+	// ReferenceField is a field of a fact, such as Order.Discount.
+	ReferenceField
+	// ReferenceFunction is a built-in function, such as Foo(), or a method of a fact, such as Order.Total().
+	ReferenceFunction
+)
+
+// String returns the kind name.
+func (kind ReferenceKind) String() string {
+	switch kind {
+	case ReferenceField:
+
+		return "field"
+	case ReferenceFunction:
+
+		return "function"
+	default:
+
+		return "fact"
+	}
+}
+
+// NewFactSchema creates new empty FactSchema.
+func NewFactSchema() *FactSchema {
+
+	return &FactSchema{facts: make(map[string]reflect.Type)}
+}
+
+// FactSchema lists the facts a service adds into the data context, with their types, so the references of the rules
+// can be checked before the rules are executed. See KnowledgeBase.UnresolvedReferences.
+type FactSchema struct {
+	facts map[string]reflect.Type
+}
+
+// Add registers the fact by the value the service adds into the data context under the name, such as &Order{}.
+// A nil value registers a fact whose members are not known, such as a JSON fact or a map, the references to its
+// members are then not checked. The scratchpad of the engine and the facts added by the enrichers are facts too.
+func (schema *FactSchema) Add(name string, fact interface{}) {
+	if fact == nil {
+		schema.facts[name] = nil
+
+		return
+	}
+	schema.facts[name] = reflect.TypeOf(fact)
+}
+
+// UnresolvedReference is a fact, a field or a function referenced by the rules that is not known.
+type UnresolvedReference struct {
+	Kind ReferenceKind
+	// Name is the reference as written in the rules, such as Order, Order.Discount or Order.Total.
+	Name string
+	// Referrers are the rules referencing it, by name, and the halt conditions, as "halt" and their condition, sorted.
+	Referrers []string
+}
+
+// String returns the reference as a message, such as "field Order.Discount referenced by Discount, Promotion".
+func (reference UnresolvedReference) String() string {
+
+	return reference.Kind.String() + " " + reference.Name + " referenced by " + strings.Join(reference.Referrers, ", ")
+}
+
+// UnresolvedReferences lists the facts, the fields and the functions referenced by the rules and the halt
+// conditions that are neither known to the Schema nor built in, sorted by their name. Every fact is unresolved if
+// the knowledge base has no Schema. The facts filled by collect statements are known, as well as the
+// functions of the strings, arrays and maps. The members of a value whose type is only known at run time, such as
+// an interface{} field, are not checked.
+func (e *KnowledgeBase) UnresolvedReferences() []UnresolvedReference {
+	resolver := &referenceResolver{
+		schema:     e.Schema,
+		collected:  make(map[string]bool),
+		bound:      make(map[string]reflect.Type),
+		references: make(map[string]*UnresolvedReference),
+	}
+	for _, entry := range e.RuleEntries {
+		for _, thenExpr := range entry.ThenExpressions() {
+			if thenExpr != nil && thenExpr.Collect != nil {
+				resolver.collected[thenExpr.Collect.Name] = true
+			}
+		}
+	}
+	for _, entry := range e.RuleEntries {
+		resolver.referrer = entry.RuleName
+		if entry.WhenScope != nil {
+			resolver.expression(entry.WhenScope.Expression)
+		}
+		for _, thenExpr := range entry.ThenExpressions() {
+			if thenExpr == nil {
+
+				continue
+			}
+			if assignment := thenExpr.Assignment; assignment != nil {
+				resolver.variable(assignment.Variable)
+				resolver.expression(assignment.Expression)
+				if assignment.Match != nil {
+					resolver.expression(assignment.Match.Subject)
+					for _, arm := range assignment.Match.Arms {
+						resolver.expression(arm.Pattern)
+						resolver.expression(arm.Value)
+					}
+				}
+			}
+			if collect := thenExpr.Collect; collect != nil {
+				source := resolver.expression(collect.Source)
+				resolver.item = nil
+				if source != nil && (source.Kind() == reflect.Slice || source.Kind() == reflect.Array) {
+					resolver.item = source.Elem()
+				}
+				resolver.inCollect = true
+				resolver.expression(collect.Condition)
+				resolver.inCollect = false
+			}
+			if switchStmt := thenExpr.Switch; switchStmt != nil {
+				resolver.expression(switchStmt.Subject)
+				for _, switchCase := range switchStmt.Cases {
+					for _, value := range switchCase.Values {
+						resolver.expression(value)
+					}
+				}
+			}
+			resolver.atom(thenExpr.ExpressionAtom)
+		}
+	}
+	for _, halt := range e.HaltEntries {
+		resolver.referrer = "halt " + halt.Expression.GrlText
+		resolver.expression(halt.Expression)
+	}
+
+	unresolved := make([]UnresolvedReference, 0, len(resolver.references))
+	for _, reference := range resolver.references {
+		sort.Strings(reference.Referrers)
+		unresolved = append(unresolved, *reference)
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+
+		return unresolved[i].Name < unresolved[j].Name
+	})
+
+	return unresolved
+}
+
+// referenceResolver infers the types of the variables and the calls of the rules, the nil type is a value whose
+// members are not checked.
+type referenceResolver struct {
+	schema *FactSchema
+	// collected are the facts filled by the collect statements.
+	collected map[string]bool
+	// item is the type of the element tested by the condition of a collect statement, when inCollect.
+	item      reflect.Type
+	inCollect bool
+	// bound are the types of the elements tested by the predicates of the quantifiers being resolved, by name.
+	bound map[string]reflect.Type
+	// referrer is the rule, or the halt condition, being resolved.
+	referrer   string
+	references map[string]*UnresolvedReference
+}
+
+// unresolved records the reference made by the current referrer.
+func (resolver *referenceResolver) unresolved(kind ReferenceKind, name string) {
+	reference, ok := resolver.references[name]
+	if !ok {
+		reference = &UnresolvedReference{Kind: kind, Name: name}
+		resolver.references[name] = reference
+	}
+	for _, referrer := range reference.Referrers {
+		if referrer == resolver.referrer {
+
+			return
+		}
+	}
+	reference.Referrers = append(reference.Referrers, resolver.referrer)
+}
+
+func (resolver *referenceResolver) expression(expr *Expression) reflect.Type {
+	if expr == nil {
+
+		return nil
+	}
+	resolver.expression(expr.LeftExpression)
+	resolver.expression(expr.RightExpression)
+	resolver.expression(expr.Tolerance)
+	if expr.SingleExpression != nil {
+
+		return resolver.expression(expr.SingleExpression)
+	}
+
+	return resolver.atom(expr.ExpressionAtom)
+}
+
+func (resolver *referenceResolver) atom(atom *ExpressionAtom) reflect.Type {
+	if atom == nil {
+
+		return nil
+	}
+	switch {
+	case atom.Variable != nil:
+
+		return resolver.variable(atom.Variable)
+	case atom.Quantifier != nil:
+		quantifier := atom.Quantifier
+		element := elementOf(resolver.expression(quantifier.Source))
+		previous, shadowed := resolver.bound[quantifier.Name]
+		resolver.bound[quantifier.Name] = element
+		resolver.expression(quantifier.Predicate)
+		if shadowed {
+			resolver.bound[quantifier.Name] = previous
+		} else {
+			delete(resolver.bound, quantifier.Name)
+		}
+
+		return reflect.TypeOf(true)
+	case atom.FunctionCall != nil:
+		if atom.FunctionCall.ArgumentList != nil {
+			for _, arg := range atom.FunctionCall.ArgumentList.Arguments {
+				resolver.expression(arg)
+			}
+		}
+		if atom.ExpressionAtom == nil {
+
+			return resolver.function(atom.FunctionCall.FunctionName)
+		}
+
+		return resolver.method(resolver.atom(atom.ExpressionAtom), atom.ExpressionAtom.GrlText, atom.FunctionCall.FunctionName)
+	case atom.ExpressionAtom != nil && len(atom.VariableName) > 0:
+
+		return resolver.field(resolver.atom(atom.ExpressionAtom), atom.GrlText, atom.VariableName)
+	case atom.ExpressionAtom != nil && atom.ArrayMapSelector != nil:
+		resolver.expression(atom.ArrayMapSelector.Expression)
+
+		return elementOf(resolver.atom(atom.ExpressionAtom))
+	case atom.ExpressionAtom != nil:
+
+		return resolver.atom(atom.ExpressionAtom)
+	}
+
+	return nil
+}
+
+func (resolver *referenceResolver) variable(variable *Variable) reflect.Type {
+	if variable == nil {
+
+		return nil
+	}
+	if variable.Variable == nil {
+
+		return resolver.fact(variable.Name)
+	}
+	parent := resolver.variable(variable.Variable)
+	if variable.ArrayMapSelector != nil {
+		resolver.expression(variable.ArrayMapSelector.Expression)
+
+		return elementOf(parent)
+	}
+
+	return resolver.field(parent, variable.GrlText, variable.Name)
+}
+
+// fact resolves the type of a fact.
+func (resolver *referenceResolver) fact(name string) reflect.Type {
+	if element, ok := resolver.bound[name]; ok {
+
+		return element
+	}
+	if resolver.inCollect && name == CollectItemName {
+
+		return resolver.item
+	}
+	if resolver.collected[name] {
+
+		return nil
+	}
+	if resolver.schema != nil {
+		if factType, ok := resolver.schema.facts[name]; ok {
+
+			return factType
+		}
+	}
+	resolver.unresolved(ReferenceFact, name)
+
+	return nil
+}
+
+// field resolves the type of the field of a value of the parent type.
+func (resolver *referenceResolver) field(parent reflect.Type, path, name string) reflect.Type {
+	for parent != nil && parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent == nil {
+
+		return nil
+	}
+	switch parent.Kind() {
+	case reflect.Struct:
+		if field, ok := parent.FieldByName(name); ok && field.IsExported() {
+
+			return field.Type
+		}
+		resolver.unresolved(ReferenceField, path)
+	case reflect.Map:
+
+		return elementOf(parent)
+	}
+
+	return nil
+}
+
+// method resolves the type returned by the method of a value of the receiver type. Only the methods of the structs
+// are checked, the strings, arrays and maps have functions of their own.
+func (resolver *referenceResolver) method(receiver reflect.Type, receiverPath, name string) reflect.Type {
+	if receiver == nil {
+
+		return nil
+	}
+	base := receiver
+	for base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+
+		return nil
+	}
+	method, ok := reflect.PointerTo(base).MethodByName(name)
+	if !ok {
+		resolver.unresolved(ReferenceFunction, receiverPath+"."+name)
+
+		return nil
+	}
+
+	return returnedBy(method.Type)
+}
+
+// function resolves the type returned by the built-in function.
+func (resolver *referenceResolver) function(name string) reflect.Type {
+	method, ok := reflect.TypeOf(&BuiltInFunctions{}).MethodByName(name)
+	if !ok {
+		resolver.unresolved(ReferenceFunction, name)
+
+		return nil
+	}
+
+	return returnedBy(method.Type)
+}
+
+// returnedBy returns the type of the single value the function returns, nil if it returns an interface.
+func returnedBy(function reflect.Type) reflect.Type {
+	if function.NumOut() != 1 || function.Out(0).Kind() == reflect.Interface {
+
+		return nil
+	}
+
+	return function.Out(0)
+}
+
+// elementOf returns the type of the elements of an array or a map type, nil for the other types.
+func elementOf(container reflect.Type) reflect.Type {
+	for container != nil && container.Kind() == reflect.Ptr {
+		container = container.Elem()
+	}
+	if container == nil {
+
+		return nil
+	}
+	switch container.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if container.Elem().Kind() == reflect.Interface {
+
+			return nil
+		}
+
+		return container.Elem()
+	}
+
+	return nil
+}
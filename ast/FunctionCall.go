@@ -133,7 +133,7 @@ func (e *FunctionCall) EvaluateArgumentList(dataContext IDataContext, memory *Wo
 		return nil, err
 	}
 	if dataContext == nil {
-		AstLog.Errorf("Datacontext for function call %s (%s) is nil", e.FunctionName, e.AstID)
+		memory.log().Errorf("Datacontext for function call %s (%s) is nil", e.FunctionName, e.AstID)
 	}
 
 	return args, nil
@@ -21,12 +21,15 @@ func NewGrl() *Grl {
 
 	return &Grl{
 		RuleEntries: make(map[string]*RuleEntry, 0),
+		TestEntries: make(map[string]*TestEntry, 0),
 	}
 }
 
-// Grl will contains multiple RuleEntries
+// Grl will contains multiple RuleEntries, the TestEntries that checks them and the HaltEntries that stop their execution
 type Grl struct {
 	RuleEntries map[string]*RuleEntry
+	TestEntries map[string]*TestEntry
+	HaltEntries []*HaltEntry
 }
 
 // GrlReceiver is interface for objects that should hold a GRL, will be called by ANTLR walker.
@@ -47,3 +50,24 @@ func (g *Grl) ReceiveRuleEntry(entry *RuleEntry) error {
 
 	return nil
 }
+
+// ReceiveTestEntry will make this GRL to accept test entries created by ANTLR walker
+func (g *Grl) ReceiveTestEntry(entry *TestEntry) error {
+	if g.TestEntries == nil {
+		g.TestEntries = make(map[string]*TestEntry)
+	}
+	if _, ok := g.TestEntries[entry.TestName]; ok {
+
+		return fmt.Errorf("duplicate test entry %s", entry.TestName)
+	}
+	g.TestEntries[entry.TestName] = entry
+
+	return nil
+}
+
+// ReceiveHaltEntry will make this GRL to accept halt entries created by ANTLR walker
+func (g *Grl) ReceiveHaltEntry(entry *HaltEntry) error {
+	g.HaltEntries = append(g.HaltEntries, entry)
+
+	return nil
+}
@@ -0,0 +1,151 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// NewHaltEntry creates new instance of HaltEntry
+func NewHaltEntry() *HaltEntry {
+
+	return &HaltEntry{
+		AstID: unique.NewID(),
+	}
+}
+
+// HaltEntry AST graph node of a halt declaration, such as halt when Fact.Decision != "";
+// Its expression is evaluated after every rule fired, the execution stops as soon as it is true.
+type HaltEntry struct {
+	AstID   string
+	GrlText string
+
+	Expression *Expression
+}
+
+// HaltEntryReceiver must be implemented by AST object that stores HaltEntry
+type HaltEntryReceiver interface {
+	ReceiveHaltEntry(entry *HaltEntry) error
+}
+
+// MakeCatalog create a catalog entry for this AST Node
+func (e *HaltEntry) MakeCatalog(cat *Catalog) {
+	meta := &HaltEntryMeta{
+		NodeMeta: NodeMeta{
+			AstID:    e.AstID,
+			GrlText:  e.GrlText,
+			Snapshot: e.GetSnapshot(),
+		},
+	}
+	if cat.AddMeta(e.AstID, meta) {
+		if e.Expression != nil {
+			meta.ExpressionID = e.Expression.AstID
+			e.Expression.MakeCatalog(cat)
+		}
+	}
+}
+
+// Clone will clone this HaltEntry. The new clone will have an identical structure
+func (e *HaltEntry) Clone(cloneTable *pkg.CloneTable) *HaltEntry {
+	clone := &HaltEntry{
+		AstID:   unique.NewID(),
+		GrlText: e.GrlText,
+	}
+
+	if e.Expression != nil {
+		if cloneTable.IsCloned(e.Expression.AstID) {
+			clone.Expression = cloneTable.Records[e.Expression.AstID].CloneInstance.(*Expression)
+		} else {
+			cloned := e.Expression.Clone(cloneTable)
+			clone.Expression = cloned
+			cloneTable.MarkCloned(e.Expression.AstID, cloned.AstID, e.Expression, cloned)
+		}
+	}
+
+	return clone
+}
+
+// AcceptExpression will accept Expression AST graph node into this node
+func (e *HaltEntry) AcceptExpression(exp *Expression) error {
+	if e.Expression == nil {
+		e.Expression = exp
+
+		return nil
+	}
+
+	return errors.New("expression for halt entry already assigned")
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *HaltEntry) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax related to this graph when it wast constructed
+func (e *HaltEntry) GetGrlText() string {
+
+	return e.GrlText
+}
+
+// GetSnapshot will create a structure signature or AST graph
+func (e *HaltEntry) GetSnapshot() string {
+	var buff strings.Builder
+	buff.WriteString(HALTENTRY)
+	buff.WriteString("(")
+	if e.Expression != nil {
+		buff.WriteString(e.Expression.GetSnapshot())
+	}
+	buff.WriteString(")")
+
+	return buff.String()
+}
+
+// SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
+// call this function.
+func (e *HaltEntry) SetGrlText(grlText string) {
+	e.GrlText = grlText
+}
+
+// Evaluate tells whether the declared condition to halt the execution holds.
+func (e *HaltEntry) Evaluate(dataContext IDataContext, memory *WorkingMemory) (halt bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error while evaluating halt condition %s ! recovered : %v", e.GrlText, r)
+			halt = false
+		}
+	}()
+	if e.Expression == nil {
+
+		return false, nil
+	}
+	val, err := e.Expression.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return false, fmt.Errorf("evaluating halt condition %s raised an error. got %w", e.GrlText, err)
+	}
+	if val.Kind() != reflect.Bool {
+
+		return false, fmt.Errorf("halt condition %s must be a boolean expression, got %s", e.GrlText, val.Kind().String())
+	}
+
+	return val.Bool(), nil
+}
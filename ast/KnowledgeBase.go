@@ -17,6 +17,7 @@ package ast
 import (
 	"fmt"
 	"io"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -34,9 +35,156 @@ func NewKnowledgeLibrary() *KnowledgeLibrary {
 	}
 }
 
+// Sibling create an empty KnowledgeLibrary having the registries of this library, so the knowledge bases built
+// aside in it are built as they would be in this library.
+func (lib *KnowledgeLibrary) Sibling() *KnowledgeLibrary {
+	sibling := NewKnowledgeLibrary()
+	sibling.Units = lib.Units
+	sibling.LiteralSuffixes = lib.LiteralSuffixes
+	sibling.ScriptLanguages = lib.ScriptLanguages
+	sibling.CatalogCodecs = lib.CatalogCodecs
+
+	return sibling
+}
+
 // KnowledgeLibrary is a knowledgebase store.
 type KnowledgeLibrary struct {
 	Library map[string]*KnowledgeBase
+
+	// Units, if set, are the units of the quantities of the rules built or loaded into this library, and of the
+	// quantities its knowledge bases compute, instead of those of pkg.RegisterUnit.
+	Units *pkg.UnitRegistry
+
+	// LiteralSuffixes, if set, parse the suffix literals of the rules built or loaded into this library instead of
+	// the parsers of pkg.RegisterLiteralSuffix.
+	LiteralSuffixes *pkg.LiteralSuffixRegistry
+
+	// ScriptLanguages, if set, compile the scripts of the rules built or loaded into this library instead of the
+	// languages of RegisterScriptLanguage.
+	ScriptLanguages *ScriptLanguageRegistry
+
+	// CatalogCodecs, if set, compress and decompress the catalogs stored and loaded by this library instead of the
+	// codecs of RegisterCatalogCodec.
+	CatalogCodecs *CatalogCodecRegistry
+
+	// usageLock guards usages, instances are created and collected concurrently.
+	usageLock sync.Mutex
+	// usages counts the instances of every knowledge base, keyed by GetKnowledgeBaseKey. The usage of a
+	// removed knowledge base is kept until all its instances are collected.
+	usages map[string]*instanceUsage
+}
+
+// instanceUsage counts the instances of a knowledge base.
+type instanceUsage struct {
+	name    string
+	version string
+	created uint64
+	live    int64
+	// removed is set by RemoveKnowledgeBase, the usage is dropped once the last instance is collected.
+	removed bool
+}
+
+// KnowledgeBaseUsage tells how many instances of a knowledge base the library created, and how many of them
+// are still in memory.
+type KnowledgeBaseUsage struct {
+	Name    string
+	Version string
+	// Loaded tells whether the knowledge base is in the library, false once it is removed.
+	Loaded bool
+	// Created is the number of instances created by NewKnowledgeBaseInstance.
+	Created uint64
+	// Live is the number of instances not collected yet. An instance is only collected once nothing refers to it,
+	// such as an InstancePool or a cache of the application, and the garbage collector ran.
+	Live int64
+}
+
+// RemoveKnowledgeBase removes the KnowledgeBase blue print of the name and version from the library, so its
+// memory is released once the instances created from it are not used anymore. The instances keep working.
+// It returns false if the library has no such KnowledgeBase.
+func (lib *KnowledgeLibrary) RemoveKnowledgeBase(name, version string) bool {
+	key := GetKnowledgeBaseKey(name, version)
+	if _, ok := lib.Library[key]; !ok {
+
+		return false
+	}
+	delete(lib.Library, key)
+	lib.usageLock.Lock()
+	defer lib.usageLock.Unlock()
+	if usage, ok := lib.usages[key]; ok {
+		usage.removed = true
+		if usage.live == 0 {
+			delete(lib.usages, key)
+		}
+	}
+
+	return true
+}
+
+// Usage returns the instance usage of the knowledge bases of the library, and of the removed knowledge bases
+// that still have instances in memory, sorted by name and version. A removed knowledge base whose instances stay
+// live tells the application still holds them.
+func (lib *KnowledgeLibrary) Usage() []KnowledgeBaseUsage {
+	lib.usageLock.Lock()
+	defer lib.usageLock.Unlock()
+	ret := make([]KnowledgeBaseUsage, 0, len(lib.Library))
+	for key, knowledgeBase := range lib.Library {
+		usage := KnowledgeBaseUsage{Name: knowledgeBase.Name, Version: knowledgeBase.Version, Loaded: true}
+		if counted, ok := lib.usages[key]; ok {
+			usage.Created, usage.Live = counted.created, counted.live
+		}
+		ret = append(ret, usage)
+	}
+	for key, counted := range lib.usages {
+		if _, ok := lib.Library[key]; !ok {
+			ret = append(ret, KnowledgeBaseUsage{Name: counted.name, Version: counted.version, Created: counted.created, Live: counted.live})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Name != ret[j].Name {
+
+			return ret[i].Name < ret[j].Name
+		}
+
+		return ret[i].Version < ret[j].Version
+	})
+
+	return ret
+}
+
+// trackInstance counts the new instance of the knowledge base, until it is collected.
+func (lib *KnowledgeLibrary) trackInstance(instance *KnowledgeBase) {
+	key := GetKnowledgeBaseKey(instance.Name, instance.Version)
+	lib.usageLock.Lock()
+	defer lib.usageLock.Unlock()
+	if lib.usages == nil {
+		lib.usages = make(map[string]*instanceUsage)
+	}
+	usage, ok := lib.usages[key]
+	if !ok {
+		usage = &instanceUsage{name: instance.Name, version: instance.Version}
+		lib.usages[key] = usage
+	}
+	usage.created++
+	usage.live++
+	usage.removed = false
+	runtime.AddCleanup(instance, lib.releaseInstance, key)
+}
+
+// releaseInstance is called once an instance is collected. It runs in the goroutine of the cleanups, so it takes
+// the usage lock to count the instance as no longer live, and forgets the usage of a removed knowledge base once
+// its last instance is collected.
+func (lib *KnowledgeLibrary) releaseInstance(key string) {
+	lib.usageLock.Lock()
+	defer lib.usageLock.Unlock()
+	usage, ok := lib.usages[key]
+	if !ok {
+
+		return
+	}
+	usage.live--
+	if usage.removed && usage.live == 0 {
+		delete(lib.usages, key)
+	}
 }
 
 // GetKnowledgeBase will get the actual KnowledgeBase blue print that will be used to create instances.
@@ -54,6 +202,7 @@ func (lib *KnowledgeLibrary) GetKnowledgeBase(name, version string) *KnowledgeBa
 		RuleEntries:   make(map[string]*RuleEntry),
 		WorkingMemory: NewWorkingMemory(name, version),
 	}
+	knowledgeBase.WorkingMemory.Units = lib.Units
 	lib.Library[GetKnowledgeBaseKey(name, version)] = knowledgeBase
 
 	return knowledgeBase
@@ -70,12 +219,14 @@ func (lib *KnowledgeLibrary) RemoveRuleEntry(ruleName, name string, version stri
 			lib.Library[nameVersion].RuleEntries[ruleName].Deleted = true
 			delete(lib.Library[nameVersion].RuleEntries, ruleName)
 			lib.Library[nameVersion].RuleEntries[ruleEntry.RuleName] = ruleEntry
+			lib.Library[nameVersion].ruleTables = nil
 		}
 	}
 }
 
 // LoadKnowledgeBaseFromReader will load the KnowledgeBase stored using StoreKnowledgeBaseToWriter function
 // be it from file, or anywhere. The reader we needed is a plain io.Reader, thus closing the source stream is your responsibility.
+// A KnowledgeBase stored with StoreKnowledgeBaseToWriterCompressed is recognized and decompressed while it is read.
 // This should hopefully speedup loading huge ruleset by storing and reading them
 // without having to parse the GRL.
 func (lib *KnowledgeLibrary) LoadKnowledgeBaseFromReader(reader io.Reader, overwrite bool) (retKb *KnowledgeBase, retErr error) {
@@ -87,12 +238,12 @@ func (lib *KnowledgeLibrary) LoadKnowledgeBaseFromReader(reader io.Reader, overw
 	}()
 
 	catalog := &Catalog{}
-	err := catalog.ReadCatalogFromReader(reader)
+	err := catalog.readCompressed(reader, lib.CatalogCodecs)
 	if err != nil && err != io.EOF {
 
 		return nil, err
 	}
-	knowledgeBase, err := catalog.BuildKnowledgeBase()
+	knowledgeBase, err := catalog.BuildKnowledgeBaseFor(lib)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +268,7 @@ func (lib *KnowledgeLibrary) LoadKnowledgeBaseFromReader(reader io.Reader, overw
 //
 // The stored binary file is greatly increased (easily 10x fold) due to lots of generated keys for AST Nodes
 // that was also saved. To overcome this, the use of archive/zip package for Readers and Writers could cut down the
-// binary size quite a lot.
+// binary size quite a lot, or use StoreKnowledgeBaseToWriterCompressed.
 func (lib *KnowledgeLibrary) StoreKnowledgeBaseToWriter(writer io.Writer, name, version string) error {
 	kb := lib.GetKnowledgeBase(name, version)
 	cat := kb.MakeCatalog()
@@ -126,6 +277,50 @@ func (lib *KnowledgeLibrary) StoreKnowledgeBaseToWriter(writer io.Writer, name,
 	return err
 }
 
+// StoreKnowledgeBaseToWriterCompressed will store a KnowledgeBase in binary form like StoreKnowledgeBaseToWriter,
+// compressed using the named compression, e.g. CompressionGzip. The catalog is compressed as it is written,
+// LoadKnowledgeBaseFromReader recognizes the compression by itself.
+func (lib *KnowledgeLibrary) StoreKnowledgeBaseToWriterCompressed(writer io.Writer, name, version, compression string) error {
+	kb := lib.GetKnowledgeBase(name, version)
+	cat := kb.MakeCatalog()
+
+	return cat.writeCompressed(writer, compression, lib.CatalogCodecs)
+}
+
+// LoadCatalogDeltaFromReader reads a delta written by CatalogDelta.WriteCatalogDeltaToWriter and applies it to
+// the catalog of its base KnowledgeBase, which must be in this library. The patched KnowledgeBase is added like
+// LoadKnowledgeBaseFromReader does, and is the base of the next delta.
+func (lib *KnowledgeLibrary) LoadCatalogDeltaFromReader(reader io.Reader, overwrite bool) (*KnowledgeBase, error) {
+	delta, err := ReadCatalogDeltaFromReader(reader)
+	if err != nil {
+
+		return nil, err
+	}
+	base, ok := lib.Library[GetKnowledgeBaseKey(delta.BaseKnowledgeBaseName, delta.BaseKnowledgeBaseVersion)]
+	if !ok {
+
+		return nil, fmt.Errorf("KnowledgeBase %s version %s the delta applies to is not in this library", delta.BaseKnowledgeBaseName, delta.BaseKnowledgeBaseVersion)
+	}
+	catalog, err := base.MakeCatalog().ApplyDelta(delta)
+	if err != nil {
+
+		return nil, err
+	}
+	knowledgeBase, err := catalog.BuildKnowledgeBaseFor(lib)
+	if err != nil {
+
+		return nil, err
+	}
+	key := GetKnowledgeBaseKey(knowledgeBase.Name, knowledgeBase.Version)
+	if _, exist := lib.Library[key]; exist && !overwrite {
+
+		return nil, fmt.Errorf("KnowledgeBase %s version %s exist", knowledgeBase.Name, knowledgeBase.Version)
+	}
+	lib.Library[key] = knowledgeBase
+
+	return knowledgeBase, nil
+}
+
 // NewKnowledgeBaseInstance will create a new instance based on KnowledgeBase blue print
 // identified by its name and version
 func (lib *KnowledgeLibrary) NewKnowledgeBaseInstance(name, version string) (*KnowledgeBase, error) {
@@ -137,6 +332,7 @@ func (lib *KnowledgeLibrary) NewKnowledgeBaseInstance(name, version string) (*Kn
 		}
 		if knowledgeBase.IsIdentical(newClone) {
 			AstLog.Debugf("Successfully create instance [%s:%s]", newClone.Name, newClone.Version)
+			lib.trackInstance(newClone)
 
 			return newClone, nil
 		}
@@ -157,6 +353,26 @@ type KnowledgeBase struct {
 	DataContext   IDataContext
 	WorkingMemory *WorkingMemory
 	RuleEntries   map[string]*RuleEntry
+
+	// TestEntries are the test blocks declared next to the rules. They are kept only in the blueprint,
+	// they are neither cloned into instances nor stored in the catalog.
+	TestEntries map[string]*TestEntry
+
+	// HaltEntries are the conditions that stop the execution as soon as one holds after a rule fired.
+	HaltEntries []*HaltEntry
+
+	// Schema lists the facts the host provides to the rules, see UnresolvedReferences. Nil if not known.
+	Schema *FactSchema
+
+	// Sources describe the resources the rules were built from, in the order they were built, so an audit can tell
+	// which version of every rule file the knowledge base was compiled from. They are not stored in the catalog.
+	Sources []pkg.ResourceMetadata
+
+	// ruleIDs indexes the rule entries by their RuleID, it is built on first use.
+	ruleIDs map[string]*RuleEntry
+
+	// ruleTables are the tables the rule entries are compacted into, they are built on first use. See RuleTables.
+	ruleTables []*RuleTable
 }
 
 // MakeCatalog will create a catalog entry for all AST Nodes under the KnowledgeBase
@@ -179,6 +395,9 @@ func (e *KnowledgeBase) MakeCatalog() *Catalog {
 	for _, v := range e.RuleEntries {
 		v.MakeCatalog(catalog)
 	}
+	for _, v := range e.HaltEntries {
+		v.MakeCatalog(catalog)
+	}
 	e.WorkingMemory.MakeCatalog(catalog)
 
 	return catalog
@@ -210,6 +429,14 @@ func (e *KnowledgeBase) GetSnapshot() string {
 		buffer.WriteString(e.RuleEntries[k].GetSnapshot())
 	}
 	buffer.WriteString("]")
+	if len(e.HaltEntries) > 0 {
+		halts := make([]string, len(e.HaltEntries))
+		for i, entry := range e.HaltEntries {
+			halts[i] = entry.GetSnapshot()
+		}
+		sort.Strings(halts)
+		buffer.WriteString(fmt.Sprintf("[%s]", strings.Join(halts, ",")))
+	}
 
 	return buffer.String()
 }
@@ -220,6 +447,8 @@ func (e *KnowledgeBase) Clone(cloneTable *pkg.CloneTable) (*KnowledgeBase, error
 		Name:        e.Name,
 		Version:     e.Version,
 		RuleEntries: make(map[string]*RuleEntry),
+		Schema:      e.Schema,
+		Sources:     append([]pkg.ResourceMetadata(nil), e.Sources...),
 	}
 	if e.RuleEntries != nil {
 		for k, entry := range e.RuleEntries {
@@ -232,6 +461,15 @@ func (e *KnowledgeBase) Clone(cloneTable *pkg.CloneTable) (*KnowledgeBase, error
 			}
 		}
 	}
+	for _, entry := range e.HaltEntries {
+		if cloneTable.IsCloned(entry.AstID) {
+			clone.HaltEntries = append(clone.HaltEntries, cloneTable.Records[entry.AstID].CloneInstance.(*HaltEntry))
+		} else {
+			cloned := entry.Clone(cloneTable)
+			clone.HaltEntries = append(clone.HaltEntries, cloned)
+			cloneTable.MarkCloned(entry.AstID, cloned.AstID, entry, cloned)
+		}
+	}
 	if e.WorkingMemory != nil {
 		wm, err := e.WorkingMemory.Clone(cloneTable)
 		if err != nil {
@@ -243,6 +481,13 @@ func (e *KnowledgeBase) Clone(cloneTable *pkg.CloneTable) (*KnowledgeBase, error
 	return clone, nil
 }
 
+// AddSource records the resource whose rules were added into this knowledge base.
+func (e *KnowledgeBase) AddSource(source pkg.ResourceMetadata) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.Sources = append(e.Sources, source)
+}
+
 // AddRuleEntry add ruleentry into this knowledge base.
 // return an error if a rule entry with the same name already exist in this knowledge base.
 func (e *KnowledgeBase) AddRuleEntry(entry *RuleEntry) error {
@@ -252,11 +497,76 @@ func (e *KnowledgeBase) AddRuleEntry(entry *RuleEntry) error {
 
 		return fmt.Errorf("rule entry %s already exist", entry.RuleName)
 	}
+	if other := e.ruleEntryByID(entry.RuleID); other != nil {
+
+		return fmt.Errorf("rule entry %s has the same id %s as rule entry %s", entry.RuleName, entry.RuleID, other.RuleName)
+	}
 	e.RuleEntries[entry.RuleName] = entry
+	e.ruleTables = nil
+	if len(entry.RuleID) > 0 {
+		e.ruleIDs[entry.RuleID] = entry
+	}
 
 	return nil
 }
 
+// AddTestEntry add test entry into this knowledge base.
+// return an error if a test entry with the same name already exist in this knowledge base.
+func (e *KnowledgeBase) AddTestEntry(entry *TestEntry) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.TestEntries == nil {
+		e.TestEntries = make(map[string]*TestEntry)
+	}
+	if _, ok := e.TestEntries[entry.TestName]; ok {
+
+		return fmt.Errorf("test entry %s already exist", entry.TestName)
+	}
+	e.TestEntries[entry.TestName] = entry
+
+	return nil
+}
+
+// AddHaltEntry add halt entry into this knowledge base.
+// A halt entry with the same condition as one already in this knowledge base is ignored, so a resource can be loaded again.
+func (e *KnowledgeBase) AddHaltEntry(entry *HaltEntry) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, other := range e.HaltEntries {
+		if other.GrlText == entry.GrlText {
+
+			return
+		}
+	}
+	e.HaltEntries = append(e.HaltEntries, entry)
+}
+
+// RuleEntryByID returns the rule entry of the specified id, or nil if no rule declares it.
+func (e *KnowledgeBase) RuleEntryByID(id string) *RuleEntry {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.ruleEntryByID(id)
+}
+
+func (e *KnowledgeBase) ruleEntryByID(id string) *RuleEntry {
+	if e.ruleIDs == nil {
+		e.ruleIDs = make(map[string]*RuleEntry)
+		for _, entry := range e.RuleEntries {
+			if len(entry.RuleID) > 0 {
+				e.ruleIDs[entry.RuleID] = entry
+			}
+		}
+	}
+	entry, ok := e.ruleIDs[id]
+	if !ok || entry.Deleted || len(id) == 0 {
+
+		return nil
+	}
+
+	return entry
+}
+
 // ContainsRuleEntry will check if a rule with such name is already exist in this knowledge base.
 func (e *KnowledgeBase) ContainsRuleEntry(name string) bool {
 	_, ok := e.RuleEntries[name]
@@ -276,6 +586,7 @@ func (e *KnowledgeBase) RemoveRuleEntry(name string) {
 		e.RuleEntries[name].Deleted = true
 		delete(e.RuleEntries, name)
 		e.RuleEntries[ruleEntry.RuleName] = ruleEntry
+		e.ruleTables = nil
 	}
 }
 
@@ -284,6 +595,26 @@ func (e *KnowledgeBase) InitializeContext(dataCtx IDataContext) {
 	e.DataContext = dataCtx
 }
 
+// InitializeCollections adds an empty collection into the data context for every collect statement of the rules,
+// so rules reading a collection can be evaluated before the collect statement fills it.
+func (e *KnowledgeBase) InitializeCollections(dataCtx IDataContext) error {
+	for _, re := range e.RuleEntries {
+		for _, thenExpr := range re.ThenExpressions() {
+			if thenExpr == nil || thenExpr.Collect == nil {
+
+				continue
+			}
+			err := dataCtx.Add(thenExpr.Collect.Name, []interface{}{})
+			if err != nil {
+
+				return fmt.Errorf("error while initializing collection %s of rule %s. got %w", thenExpr.Collect.Name, re.RuleName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // RetractRule will retract the selected rule for execution on the next cycle.
 func (e *KnowledgeBase) RetractRule(ruleName string) {
 	for _, re := range e.RuleEntries {
@@ -305,12 +636,14 @@ func (e *KnowledgeBase) IsRuleRetracted(ruleName string) bool {
 	return false
 }
 
-// Reset will restore all rule in the knowledge
+// Reset will restore all rule in the knowledge.
+// The fire count of each rule is cleared, but the last fired time is kept so cooldown holds across executions.
 func (e *KnowledgeBase) Reset() {
 	for _, re := range e.RuleEntries {
 		if re.Retracted {
 			re.Retracted = false
 		}
+		re.FireCount = 0
 	}
 }
 
@@ -14,6 +14,12 @@
 
 package ast
 
+import (
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
 // IntegerLiteral will hold IntegerLiteral constant AST data
 type IntegerLiteral struct {
 	Integer int64
@@ -34,6 +40,97 @@ type BooleanLiteral struct {
 	Boolean bool
 }
 
+// QuantityLiteral will hold QuantityLiteral constant AST data
+type QuantityLiteral struct {
+	Quantity pkg.Quantity
+}
+
+// SuffixLiteral will hold SuffixLiteral constant AST data, the value parsed from a literal such as 10_USD
+// by the parser registered for its suffix with pkg.RegisterLiteralSuffix
+type SuffixLiteral struct {
+	Literal string
+	Value   interface{}
+}
+
+// CollectionLiteral will hold CollectionLiteral constant AST data, it is a pkg.Set such as {"US", "CA"}
+// or ["US", "CA"], or a pkg.OrderedMap such as {"US": 10, "CA": 5}
+type CollectionLiteral struct {
+	Keys   []*Constant
+	Values []*Constant
+	// List is true for a literal written in brackets, which can only be a set.
+	List bool
+
+	element []*Constant
+}
+
+// NewCollectionLiteral create new instance of CollectionLiteral
+func NewCollectionLiteral() *CollectionLiteral {
+
+	return &CollectionLiteral{
+		Keys:    make([]*Constant, 0),
+		Values:  make([]*Constant, 0),
+		element: make([]*Constant, 0, 2),
+	}
+}
+
+// AcceptConstant will accept the constants of the element being parsed
+func (e *CollectionLiteral) AcceptConstant(con *Constant) error {
+	e.element = append(e.element, con)
+
+	return nil
+}
+
+// CloseElement ends the element being parsed, that is a set element or a key and its value.
+func (e *CollectionLiteral) CloseElement() error {
+	defer func() {
+		e.element = e.element[:0]
+	}()
+	switch len(e.element) {
+	case 1:
+		e.Keys = append(e.Keys, e.element[0])
+		e.Values = append(e.Values, nil)
+	case 2:
+		e.Keys = append(e.Keys, e.element[0])
+		e.Values = append(e.Values, e.element[1])
+	default:
+
+		return fmt.Errorf("collection element must be a constant or a key and its value")
+	}
+
+	return nil
+}
+
+// Collection returns the pkg.Set or the pkg.OrderedMap of the literal.
+// An empty literal {} is an empty set.
+func (e *CollectionLiteral) Collection() (interface{}, error) {
+	isMap := len(e.Values) > 0 && e.Values[0] != nil
+	if isMap && e.List {
+
+		return nil, fmt.Errorf("list literal can not hold map entries, write an ordered map in braces")
+	}
+	elements := make([]interface{}, 0, 2*len(e.Keys))
+	for i, key := range e.Keys {
+		if (e.Values[i] != nil) != isMap {
+
+			return nil, fmt.Errorf("collection literal must not mix set elements and map entries")
+		}
+		if pkg.IsCollection(key.Value) {
+
+			return nil, fmt.Errorf("collection literal %s can not be an element of a set or a key of an ordered map", key.GrlText)
+		}
+		elements = append(elements, key.Interface())
+		if isMap {
+			elements = append(elements, e.Values[i].Interface())
+		}
+	}
+	if isMap {
+
+		return pkg.NewOrderedMap(elements...)
+	}
+
+	return pkg.NewSet(elements...)
+}
+
 // IntegerLiteralReceiver should be implemented by AST graph node to receive a IntegerLiteral AST graph node
 type IntegerLiteralReceiver interface {
 	AcceptIntegerLiteral(fun *IntegerLiteral)
@@ -53,3 +150,18 @@ type FloatLiteralReceiver interface {
 type BooleanLiteralReceiver interface {
 	AcceptBooleanLiteral(fun *BooleanLiteral)
 }
+
+// QuantityLiteralReceiver should be implemented by AST graph node to receive a QuantityLiteral AST graph node
+type QuantityLiteralReceiver interface {
+	AcceptQuantityLiteral(fun *QuantityLiteral)
+}
+
+// SuffixLiteralReceiver should be implemented by AST graph node to receive a SuffixLiteral AST graph node
+type SuffixLiteralReceiver interface {
+	AcceptSuffixLiteral(fun *SuffixLiteral)
+}
+
+// CollectionLiteralReceiver should be implemented by AST graph node to receive a CollectionLiteral AST graph node
+type CollectionLiteralReceiver interface {
+	AcceptCollectionLiteral(fun *CollectionLiteral) error
+}
@@ -0,0 +1,283 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DateNames are the month and week day names of a language, from January and from Sunday.
+type DateNames struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+}
+
+// goDateNames are the names the layouts of the time package use.
+var goDateNames = DateNames{
+	Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+}
+
+// defaultDateNames are the date names of the registries that are nil, RegisterDateNames adds into them.
+var defaultDateNames = NewDateNamesRegistry()
+
+// DateNamesRegistry keeps the date names by language. A nil registry is the default one of the process, so two
+// engines may each format the dates with names of their own, see GruleEngine.DateNames.
+type DateNamesRegistry struct {
+	mutex sync.RWMutex
+	names map[string]DateNames
+}
+
+// NewDateNamesRegistry create a DateNamesRegistry knowing the built in languages, en, id, de, fr, es and nl.
+func NewDateNamesRegistry() *DateNamesRegistry {
+
+	return &DateNamesRegistry{
+		names: map[string]DateNames{
+			"en": goDateNames,
+			"id": {
+				Months:      [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+				ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+				Days:        [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+				ShortDays:   [7]string{"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"},
+			},
+			"de": {
+				Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+				ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+				Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+				ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+			},
+			"fr": {
+				Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+				ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+				Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+				ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			},
+			"es": {
+				Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+				ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+				Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+				ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			},
+			"nl": {
+				Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+				ShortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+				Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+				ShortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+			},
+		},
+	}
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *DateNamesRegistry) or() *DateNamesRegistry {
+	if registry == nil {
+
+		return defaultDateNames
+	}
+
+	return registry
+}
+
+// Register adds, or replaces, the date names of a language such as "pt", used by FormatDate and ParseDate.
+func (registry *DateNamesRegistry) Register(lang string, names DateNames) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+
+		return fmt.Errorf("invalid language %s. got %w", lang, err)
+	}
+	base, _ := tag.Base()
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.names[base.String()] = names
+
+	return nil
+}
+
+// localeNames returns the date names of the locale language, it panics if the language has none.
+func (registry *DateNamesRegistry) localeNames(locale string) DateNames {
+	base, _ := localeTag(locale).Base()
+	registry = registry.or()
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	names, ok := registry.names[base.String()]
+	if !ok {
+		panic(fmt.Sprintf("no date names for locale %s, register them with ast.RegisterDateNames", locale))
+	}
+
+	return names
+}
+
+// RegisterDateNames adds, or replaces, the date names of a language such as "pt" in the default registry, used by
+// FormatDate and ParseDate.
+func RegisterDateNames(lang string, names DateNames) error {
+
+	return defaultDateNames.Register(lang, names)
+}
+
+// localeTag parses the locale, such as "de-DE", it panics if the locale is invalid.
+func localeTag(locale string) language.Tag {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		panic(fmt.Sprintf("invalid locale %s. got %v", locale, err))
+	}
+
+	return tag
+}
+
+// separators returns the grouping and the decimal separators of the locale.
+func separators(tag language.Tag) (group, decimal string) {
+	// 1234.5 is formatted like 1,234.5 and the separators are read back.
+	formatted := []rune(message.NewPrinter(tag).Sprint(number.Decimal(1234.5)))
+	var digits int
+	for _, r := range formatted {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case digits == 1:
+			group += string(r)
+		case digits == 4:
+			decimal += string(r)
+		}
+	}
+
+	return group, decimal
+}
+
+// ParseNumber will parse a number formatted by the conventions of the locale, e.g. ParseNumber("1.234,56", "de-DE")
+// is 1234.56. Grouping separators and spaces are ignored.
+func (gf *BuiltInFunctions) ParseNumber(text, locale string) float64 {
+	group, decimal := separators(localeTag(locale))
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == ' ' || strings.ContainsRune(group, r) {
+
+			return -1
+		}
+
+		return r
+	}, text)
+	if decimal != "." {
+		cleaned = strings.Replace(cleaned, decimal, ".", 1)
+	}
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		panic(fmt.Sprintf("%q is not a number in locale %s", text, locale))
+	}
+
+	return value
+}
+
+// FormatNumber will format a number with the specified decimals, by the conventions of the locale.
+func (gf *BuiltInFunctions) FormatNumber(value float64, decimals int64, locale string) string {
+
+	return message.NewPrinter(localeTag(locale)).Sprint(number.Decimal(value, number.Scale(int(decimals))))
+}
+
+// FormatCurrency will format an amount of the currency, such as "EUR", by the conventions of the locale.
+// The amount is rounded to the decimals of the currency.
+func (gf *BuiltInFunctions) FormatCurrency(amount float64, currencyCode, locale string) string {
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		panic(fmt.Sprintf("invalid currency %s. got %v", currencyCode, err))
+	}
+
+	return message.NewPrinter(localeTag(locale)).Sprint(currency.Symbol(unit.Amount(amount)))
+}
+
+// FormatDate will format a time according to the layout, as TimeFormat does, with the month and day names of the
+// locale, e.g. FormatDate(t, "02 January 2006", "id-ID") is "17 Agustus 1945".
+func (gf *BuiltInFunctions) FormatDate(t time.Time, layout, locale string) string {
+	names := gf.DateNames.localeNames(locale)
+	var stringBuilder strings.Builder
+	for len(layout) > 0 {
+		index, name := nextDateName(layout)
+		if index < 0 {
+			stringBuilder.WriteString(t.Format(layout))
+
+			break
+		}
+		stringBuilder.WriteString(t.Format(layout[:index]))
+		switch name {
+		case "January":
+			stringBuilder.WriteString(names.Months[t.Month()-1])
+		case "Jan":
+			stringBuilder.WriteString(names.ShortMonths[t.Month()-1])
+		case "Monday":
+			stringBuilder.WriteString(names.Days[t.Weekday()])
+		case "Mon":
+			stringBuilder.WriteString(names.ShortDays[t.Weekday()])
+		}
+		layout = layout[index+len(name):]
+	}
+
+	return stringBuilder.String()
+}
+
+// nextDateName returns where the first month or day name element of the layout is, -1 if there is none.
+func nextDateName(layout string) (int, string) {
+	index, name := -1, ""
+	// the long names first, Jan is a prefix of January.
+	for _, element := range []string{"January", "Monday", "Jan", "Mon"} {
+		if i := strings.Index(layout, element); i >= 0 && (index < 0 || i < index) {
+			index, name = i, element
+		}
+	}
+
+	return index, name
+}
+
+// ParseDate will parse a date formatted according to the layout with the month and day names of the locale,
+// e.g. ParseDate("17 Agustus 1945", "02 January 2006", "id-ID").
+func (gf *BuiltInFunctions) ParseDate(text, layout, locale string) time.Time {
+	names := gf.DateNames.localeNames(locale)
+	english := goDateNames
+	pairs := make([][2]string, 0, 38)
+	for i := range names.Months {
+		pairs = append(pairs, [2]string{names.Months[i], english.Months[i]}, [2]string{names.ShortMonths[i], english.ShortMonths[i]})
+	}
+	for i := range names.Days {
+		pairs = append(pairs, [2]string{names.Days[i], english.Days[i]}, [2]string{names.ShortDays[i], english.ShortDays[i]})
+	}
+	// the longest names first, so a short name never replaces a part of a long one.
+	sort.SliceStable(pairs, func(i, j int) bool {
+
+		return len(pairs[i][0]) > len(pairs[j][0])
+	})
+	oldNew := make([]string, 0, 2*len(pairs))
+	for _, pair := range pairs {
+		oldNew = append(oldNew, pair[0], pair[1])
+	}
+	t, err := time.Parse(layout, strings.NewReplacer(oldNew...).Replace(text))
+	if err != nil {
+		panic(fmt.Sprintf("%q is not a date formatted as %s in locale %s. got %v", text, layout, locale, err))
+	}
+
+	return t
+}
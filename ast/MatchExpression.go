@@ -0,0 +1,282 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// NewMatchExpression creates new MatchExpression instance
+func NewMatchExpression() *MatchExpression {
+
+	return &MatchExpression{
+		AstID: unique.NewID(),
+		Arms:  make([]*MatchArm, 0),
+	}
+}
+
+// MatchExpression AST graph node of `match Subject { > 800 => "A", _ => "C" }`.
+// The subject is evaluated once and the value of the first matching arm is returned.
+type MatchExpression struct {
+	AstID   string
+	GrlText string
+
+	Subject *Expression
+	Arms    []*MatchArm
+}
+
+// NewMatchArm creates new MatchArm instance, an arm without comparison operator matches on equality.
+func NewMatchArm() *MatchArm {
+
+	return &MatchArm{
+		Operator: OpEq,
+	}
+}
+
+// MatchArm is a single branch of a MatchExpression. It is part of its MatchExpression and not a node on its own.
+type MatchArm struct {
+	GrlText string
+
+	// Operator compares the subject to the pattern, it is one of the comparison operators.
+	Operator int
+	// Default is true for the `_` arm, which has no pattern and always matches.
+	Default bool
+	Pattern *Expression
+	Value   *Expression
+}
+
+// MatchExpressionReceiver must be implemented by all other ast graph that uses a match expression
+type MatchExpressionReceiver interface {
+	AcceptMatchExpression(match *MatchExpression) error
+}
+
+// MatchArmReceiver must be implemented by all other ast graph that uses a match arm
+type MatchArmReceiver interface {
+	AcceptMatchArm(arm *MatchArm) error
+}
+
+// AcceptExpression will accept the subject Expression into this MatchExpression
+func (e *MatchExpression) AcceptExpression(exp *Expression) error {
+	if e.Subject != nil {
+
+		return errors.New("subject for match already assigned")
+	}
+	e.Subject = exp
+
+	return nil
+}
+
+// AcceptMatchArm will accept a MatchArm into this MatchExpression
+func (e *MatchExpression) AcceptMatchArm(arm *MatchArm) error {
+	if len(e.Arms) > 0 && e.Arms[len(e.Arms)-1].Default {
+
+		return fmt.Errorf("match arm %s is never reached, it follows the default arm", arm.GrlText)
+	}
+	e.Arms = append(e.Arms, arm)
+
+	return nil
+}
+
+// AcceptExpression will accept the pattern, then the value Expression into this MatchArm
+func (e *MatchArm) AcceptExpression(exp *Expression) error {
+	if !e.Default && e.Pattern == nil {
+		e.Pattern = exp
+
+		return nil
+	}
+	if e.Value != nil {
+
+		return errors.New("value for match arm already assigned")
+	}
+	e.Value = exp
+
+	return nil
+}
+
+// MakeCatalog will create a catalog entry from MatchExpression node.
+func (e *MatchExpression) MakeCatalog(cat *Catalog) {
+	meta := &MatchExpressionMeta{
+		NodeMeta: NodeMeta{
+			AstID:    e.AstID,
+			GrlText:  e.GrlText,
+			Snapshot: e.GetSnapshot(),
+		},
+	}
+	if cat.AddMeta(e.AstID, meta) {
+		if e.Subject != nil {
+			meta.SubjectID = e.Subject.AstID
+			e.Subject.MakeCatalog(cat)
+		}
+		meta.ArmGrlTexts = make([]string, len(e.Arms))
+		meta.ArmOperators = make([]int, len(e.Arms))
+		meta.ArmPatternIDs = make([]string, len(e.Arms))
+		meta.ArmValueIDs = make([]string, len(e.Arms))
+		for i, arm := range e.Arms {
+			meta.ArmGrlTexts[i] = arm.GrlText
+			meta.ArmOperators[i] = arm.Operator
+			if arm.Pattern != nil {
+				meta.ArmPatternIDs[i] = arm.Pattern.AstID
+				arm.Pattern.MakeCatalog(cat)
+			}
+			if arm.Value != nil {
+				meta.ArmValueIDs[i] = arm.Value.AstID
+				arm.Value.MakeCatalog(cat)
+			}
+		}
+	}
+}
+
+// Clone will clone this MatchExpression. The new clone will have an identical structure
+func (e *MatchExpression) Clone(cloneTable *pkg.CloneTable) *MatchExpression {
+	clone := &MatchExpression{
+		AstID:   unique.NewID(),
+		GrlText: e.GrlText,
+		Subject: cloneMatchExpression(cloneTable, e.Subject),
+		Arms:    make([]*MatchArm, len(e.Arms)),
+	}
+	for i, arm := range e.Arms {
+		clone.Arms[i] = &MatchArm{
+			GrlText:  arm.GrlText,
+			Operator: arm.Operator,
+			Default:  arm.Default,
+			Pattern:  cloneMatchExpression(cloneTable, arm.Pattern),
+			Value:    cloneMatchExpression(cloneTable, arm.Value),
+		}
+	}
+
+	return clone
+}
+
+// cloneMatchExpression clones an expression used by a match, reusing the clone if the expression was already cloned.
+func cloneMatchExpression(cloneTable *pkg.CloneTable, expr *Expression) *Expression {
+	if expr == nil {
+
+		return nil
+	}
+	if cloneTable.IsCloned(expr.AstID) {
+
+		return cloneTable.Records[expr.AstID].CloneInstance.(*Expression)
+	}
+	cloned := expr.Clone(cloneTable)
+	cloneTable.MarkCloned(expr.AstID, cloned.AstID, expr, cloned)
+
+	return cloned
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *MatchExpression) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax related to this graph when it wast constructed
+func (e *MatchExpression) GetGrlText() string {
+
+	return e.GrlText
+}
+
+// GetSnapshot will create a structure signature or AST graph
+func (e *MatchExpression) GetSnapshot() string {
+	var buff strings.Builder
+	buff.WriteString(MATCHEXPRESSION)
+	buff.WriteString("(")
+	if e.Subject != nil {
+		buff.WriteString(e.Subject.GetSnapshot())
+	}
+	for _, arm := range e.Arms {
+		buff.WriteString("MA(")
+		if arm.Default {
+			buff.WriteString("_")
+		} else {
+			buff.WriteString(operatorSymbol(arm.Operator))
+			buff.WriteString(arm.Pattern.GetSnapshot())
+		}
+		buff.WriteString("=>")
+		buff.WriteString(arm.Value.GetSnapshot())
+		buff.WriteString(")")
+	}
+	buff.WriteString(")")
+
+	return buff.String()
+}
+
+// SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
+// call this function.
+func (e *MatchExpression) SetGrlText(grlText string) {
+	e.GrlText = grlText
+}
+
+// Evaluate evaluates the subject, then the arms in order until one matches. Only the value of that arm is evaluated.
+func (e *MatchExpression) Evaluate(dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
+	subject, err := e.Subject.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return reflect.Value{}, fmt.Errorf("match subject error. got %w", err)
+	}
+	for _, arm := range e.Arms {
+		matched, err := arm.matches(subject, dataContext, memory)
+		if err != nil {
+
+			return reflect.Value{}, err
+		}
+		if matched {
+
+			return arm.Value.Evaluate(dataContext, memory)
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("no arm of %s matches %v, add a default arm `_ => ...`", e.GrlText, pkg.ValueToInterface(subject))
+}
+
+func (e *MatchArm) matches(subject reflect.Value, dataContext IDataContext, memory *WorkingMemory) (bool, error) {
+	if e.Default {
+
+		return true, nil
+	}
+	pattern, err := e.Pattern.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return false, fmt.Errorf("match arm %s error. got %w", e.GrlText, err)
+	}
+	var val reflect.Value
+	switch e.Operator {
+	case OpGT:
+		val, err = pkg.EvaluateGreaterThan(subject, pattern)
+	case OpLT:
+		val, err = pkg.EvaluateLesserThan(subject, pattern)
+	case OpGTE:
+		val, err = pkg.EvaluateGreaterThanEqual(subject, pattern)
+	case OpLTE:
+		val, err = pkg.EvaluateLesserThanEqual(subject, pattern)
+	case OpNEq:
+		val, err = pkg.EvaluateNotEqual(subject, pattern)
+	case OpIn:
+		val, err = pkg.EvaluateIn(subject, pattern)
+	default:
+		val, err = pkg.EvaluateEqual(subject, pattern)
+	}
+	if err != nil {
+
+		return false, fmt.Errorf("match arm %s error. got %w", e.GrlText, err)
+	}
+
+	return val.Kind() == reflect.Bool && val.Bool(), nil
+}
@@ -0,0 +1,36 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+// NewMaxFires create new MaxFires AST object
+func NewMaxFires() *MaxFires {
+
+	return &MaxFires{}
+}
+
+// MaxFires is a simple AST object that stores how many times a rule may fire within one execution
+type MaxFires struct {
+	MaxFiresValue int64
+}
+
+// MaxFiresReceiver must be implemented by any AST object that stores max-fires
+type MaxFiresReceiver interface {
+	AcceptMaxFires(maxFires *MaxFires) error
+}
+
+// AcceptIntegerLiteral accept the assigned integer
+func (mf *MaxFires) AcceptIntegerLiteral(lit *IntegerLiteral) {
+	mf.MaxFiresValue = lit.Integer
+}
@@ -0,0 +1,38 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+// NewProfile create new Profile AST object
+func NewProfile() *Profile {
+
+	return &Profile{
+		Names: make([]string, 0),
+	}
+}
+
+// Profile is a simple AST object that stores the profile names a rule is restricted to
+type Profile struct {
+	Names []string
+}
+
+// ProfileReceiver must be implemented by any AST object that stores rule profiles
+type ProfileReceiver interface {
+	AcceptProfile(profile *Profile) error
+}
+
+// AcceptStringLiteral accept the assigned string
+func (p *Profile) AcceptStringLiteral(lit *StringLiteral) {
+	p.Names = append(p.Names, lit.String)
+}
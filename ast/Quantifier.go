@@ -0,0 +1,229 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+const (
+	// QuantifierExists is the keyword of a quantifier true when the predicate holds for at least one element.
+	QuantifierExists = "exists"
+	// QuantifierForAll is the keyword of a quantifier true when the predicate holds for every element.
+	QuantifierForAll = "forall"
+)
+
+// NewQuantifier creates new Quantifier instance
+func NewQuantifier() *Quantifier {
+
+	return &Quantifier{
+		AstID: unique.NewID(),
+	}
+}
+
+// Quantifier AST graph node of `exists(Fact.Orders, o -> o.Amount > 1000)` and `forall(Fact.Orders, o -> o.Paid)`.
+// The predicate is evaluated for the elements of the source, each bound as a fact named Name, until one decides
+// the result. exists is false and forall is true for an empty source.
+type Quantifier struct {
+	AstID   string
+	GrlText string
+
+	ForAll    bool
+	Name      string
+	Source    *Expression
+	Predicate *Expression
+}
+
+// QuantifierReceiver must be implemented by all other ast graph that uses a quantifier
+type QuantifierReceiver interface {
+	AcceptQuantifier(quantifier *Quantifier) error
+}
+
+// AcceptExpression will accept the source, then the predicate Expression into this Quantifier
+func (e *Quantifier) AcceptExpression(exp *Expression) error {
+	if e.Source == nil {
+		e.Source = exp
+
+		return nil
+	}
+	if e.Predicate != nil {
+
+		return errors.New("predicate for quantifier already assigned")
+	}
+	e.Predicate = exp
+
+	return nil
+}
+
+// Keyword returns the GRL keyword of this Quantifier, exists or forall.
+func (e *Quantifier) Keyword() string {
+	if e.ForAll {
+
+		return QuantifierForAll
+	}
+
+	return QuantifierExists
+}
+
+// MakeCatalog will create a catalog entry from Quantifier node.
+func (e *Quantifier) MakeCatalog(cat *Catalog) {
+	meta := &QuantifierMeta{
+		NodeMeta: NodeMeta{
+			AstID:    e.AstID,
+			GrlText:  e.GrlText,
+			Snapshot: e.GetSnapshot(),
+		},
+		ForAll: e.ForAll,
+		Name:   e.Name,
+	}
+	if cat.AddMeta(e.AstID, meta) {
+		if e.Source != nil {
+			meta.SourceID = e.Source.AstID
+			e.Source.MakeCatalog(cat)
+		}
+		if e.Predicate != nil {
+			meta.PredicateID = e.Predicate.AstID
+			e.Predicate.MakeCatalog(cat)
+		}
+	}
+}
+
+// Clone will clone this Quantifier. The new clone will have an identical structure
+func (e *Quantifier) Clone(cloneTable *pkg.CloneTable) *Quantifier {
+
+	return &Quantifier{
+		AstID:     unique.NewID(),
+		GrlText:   e.GrlText,
+		ForAll:    e.ForAll,
+		Name:      e.Name,
+		Source:    cloneMatchExpression(cloneTable, e.Source),
+		Predicate: cloneMatchExpression(cloneTable, e.Predicate),
+	}
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *Quantifier) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax related to this graph when it wast constructed
+func (e *Quantifier) GetGrlText() string {
+
+	return e.GrlText
+}
+
+// GetSnapshot will create a structure signature or AST graph
+func (e *Quantifier) GetSnapshot() string {
+	var buff strings.Builder
+	buff.WriteString(QUANTIFIER)
+	buff.WriteString("(")
+	buff.WriteString(e.Keyword())
+	buff.WriteString(":")
+	buff.WriteString(e.Name)
+	buff.WriteString("<-")
+	if e.Source != nil {
+		buff.WriteString(e.Source.GetSnapshot())
+	}
+	buff.WriteString("?")
+	if e.Predicate != nil {
+		buff.WriteString(e.Predicate.GetSnapshot())
+	}
+	buff.WriteString(")")
+
+	return buff.String()
+}
+
+// SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
+// call this function.
+func (e *Quantifier) SetGrlText(grlText string) {
+	e.GrlText = grlText
+}
+
+// Evaluate evaluates the predicate for the elements of the source, in order, and stops at the first element that
+// decides the result. The element is only visible to the predicate, the data context itself is not modified, so a
+// quantifier can be evaluated against a read only DataContextSnapshot.
+func (e *Quantifier) Evaluate(dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
+	source, err := e.Source.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return reflect.Value{}, fmt.Errorf("%s source error. got %w", e.Keyword(), err)
+	}
+	for source.Kind() == reflect.Ptr || source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+	if source.Kind() != reflect.Slice && source.Kind() != reflect.Array {
+
+		return reflect.Value{}, fmt.Errorf("can not evaluate %s over %s, it is not an array or slice", e.Keyword(), e.Source.GrlText)
+	}
+	// the expressions reading the element must not keep the value of the last one.
+	defer memory.Reset(e.Name)
+	for i := 0; i < source.Len(); i++ {
+		element := source.Index(i)
+		bound := &boundDataContext{
+			IDataContext: dataContext,
+			name:         e.Name,
+			node:         model.NewGoValueNodeWithOptions(element, e.Name, nodeOptionsOf(dataContext)),
+		}
+		memory.Reset(e.Name)
+		val, err := e.Predicate.Evaluate(bound, memory)
+		if err != nil {
+
+			return reflect.Value{}, fmt.Errorf("%s predicate %s error on element %d. got %w", e.Keyword(), e.Predicate.GrlText, i, err)
+		}
+		if val.Kind() != reflect.Bool {
+
+			return reflect.Value{}, fmt.Errorf("%s predicate %s is not a boolean expression", e.Keyword(), e.Predicate.GrlText)
+		}
+		// exists is decided by the first element the predicate holds for, forall by the first it does not.
+		if val.Bool() != e.ForAll {
+
+			return reflect.ValueOf(!e.ForAll), nil
+		}
+	}
+
+	return reflect.ValueOf(e.ForAll), nil
+}
+
+// boundDataContext is the data context seen by the predicate of a quantifier, where the element being tested is
+// a fact. Everything else is the data context the quantifier is evaluated against.
+type boundDataContext struct {
+	IDataContext
+	name string
+	node model.ValueNode
+}
+
+// Get returns the element for the name it is bound to, the fact of the data context otherwise.
+func (ctx *boundDataContext) Get(key string) model.ValueNode {
+	if key == ctx.name {
+
+		return ctx.node
+	}
+
+	return ctx.IDataContext.Get(key)
+}
+
+// NodeOptions returns the node options of the data context the quantifier is evaluated against.
+func (ctx *boundDataContext) NodeOptions() model.NodeOptions {
+
+	return nodeOptionsOf(ctx.IDataContext)
+}
@@ -0,0 +1,131 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RuleChange describes how a rule differs between two knowledge bases.
+type RuleChange struct {
+	// RuleID is the stable id of the rule, its name if it does not declare an id.
+	RuleID string
+	// OldName is empty if the rule is added.
+	OldName string
+	// NewName is empty if the rule is removed.
+	NewName string
+	// Renamed is true if the rule keeps its id under another name.
+	Renamed bool
+	// Modified is true if the behaviour of the rule changes, that is its when, then, salience, max-fires or cooldown.
+	Modified bool
+}
+
+// Added tells whether the rule only exists in the new knowledge base.
+func (c RuleChange) Added() bool {
+
+	return len(c.OldName) == 0
+}
+
+// Removed tells whether the rule only exists in the old knowledge base.
+func (c RuleChange) Removed() bool {
+
+	return len(c.NewName) == 0
+}
+
+// String describes the change.
+func (c RuleChange) String() string {
+	switch {
+	case c.Added():
+
+		return fmt.Sprintf("added %s (%s)", c.NewName, c.RuleID)
+	case c.Removed():
+
+		return fmt.Sprintf("removed %s (%s)", c.OldName, c.RuleID)
+	case c.Renamed && c.Modified:
+
+		return fmt.Sprintf("renamed and modified %s to %s (%s)", c.OldName, c.NewName, c.RuleID)
+	case c.Renamed:
+
+		return fmt.Sprintf("renamed %s to %s (%s)", c.OldName, c.NewName, c.RuleID)
+	}
+
+	return fmt.Sprintf("modified %s (%s)", c.NewName, c.RuleID)
+}
+
+// DiffKnowledgeBases compares the rules of two knowledge bases, rules are matched by their StableID so a renamed rule
+// that declares an id is reported as renamed rather than as removed and added. Unchanged rules are not reported.
+// The changes are sorted by rule id.
+func DiffKnowledgeBases(old, new *KnowledgeBase) []RuleChange {
+	oldRules := rulesByStableID(old)
+	newRules := rulesByStableID(new)
+
+	changes := make([]RuleChange, 0)
+	for id, oldRule := range oldRules {
+		newRule, ok := newRules[id]
+		if !ok {
+			changes = append(changes, RuleChange{RuleID: id, OldName: oldRule.RuleName})
+
+			continue
+		}
+		change := RuleChange{
+			RuleID:   id,
+			OldName:  oldRule.RuleName,
+			NewName:  newRule.RuleName,
+			Renamed:  oldRule.RuleName != newRule.RuleName,
+			Modified: behaviourSnapshot(oldRule) != behaviourSnapshot(newRule),
+		}
+		if change.Renamed || change.Modified {
+			changes = append(changes, change)
+		}
+	}
+	for id, newRule := range newRules {
+		if _, ok := oldRules[id]; !ok {
+			changes = append(changes, RuleChange{RuleID: id, NewName: newRule.RuleName})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+
+		return changes[i].RuleID < changes[j].RuleID
+	})
+
+	return changes
+}
+
+func rulesByStableID(kb *KnowledgeBase) map[string]*RuleEntry {
+	ret := make(map[string]*RuleEntry)
+	if kb == nil {
+
+		return ret
+	}
+	for _, entry := range kb.RuleEntries {
+		if !entry.Deleted {
+			ret[entry.StableID()] = entry
+		}
+	}
+
+	return ret
+}
+
+// behaviourSnapshot is the snapshot of the rule without its name, id and description.
+func behaviourSnapshot(entry *RuleEntry) string {
+
+	snapshot := fmt.Sprintf("SAL:%d MF:%d CD:%s CR:%s W:%s T:%s", entry.Salience, entry.MaxFires, entry.Cooldown, entry.Criticality, entry.WhenScope.GetSnapshot(), entry.ThenScope.GetSnapshot())
+	if entry.ElseScope != nil {
+		snapshot += " E:" + entry.ElseScope.GetSnapshot()
+	}
+
+	return snapshot
+}
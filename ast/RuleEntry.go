@@ -16,9 +16,12 @@ package ast
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
 
@@ -46,9 +49,65 @@ type RuleEntry struct {
 	Salience        int
 	WhenScope       *WhenScope
 	ThenScope       *ThenScope
+	// ElseScope, if set, is executed when the rule is selected because its when scope is false, such as the
+	// rule of a pair testing the same condition, one firing when it holds and the other when it does not.
+	ElseScope *ThenScope
+
+	// RuleID is the stable identifier of the rule, it stays the same when the rule is renamed. Empty if not declared.
+	RuleID string
+
+	// MaxFires is the maximum number of times this rule may fire within one execution. Zero means unlimited.
+	MaxFires int
+	// Cooldown is the minimum duration between two firings of this rule, it holds across executions
+	// as long as the same KnowledgeBase instance is used. Zero means no cooldown.
+	Cooldown time.Duration
+	// Criticality tells whether this rule is skipped when the engine is degraded.
+	Criticality Criticality
+	// Profiles restricts this rule to engines running with at least one of these profiles active.
+	// Empty means the rule is active in every profile.
+	Profiles []string
+	// Sinks are the names of the sinks the messages emitted by this rule are routed to, such as "fraud-queue".
+	// Empty means the messages are only collected by their type.
+	Sinks []string
+	// Approvals are the approval categories of this rule, such as "large-refund". A matching rule of a category
+	// waits for a decision before it fires. Empty means the rule fires without approval.
+	Approvals []string
 
 	Retracted bool
 	Deleted   bool //If this is true, it will be ignored while execution and fetching the matching rules
+
+	// FireCount is the number of times this rule has fired in the current execution.
+	FireCount int
+	// LastFired is the last time this rule has fired.
+	LastFired time.Time
+}
+
+// StableID returns the RuleID, or the RuleName if the rule has no id.
+func (e *RuleEntry) StableID() string {
+	if len(e.RuleID) > 0 {
+
+		return e.RuleID
+	}
+
+	return e.RuleName
+}
+
+// InProfiles tells whether this rule is active when the given profiles are active.
+func (e *RuleEntry) InProfiles(active []string) bool {
+	if len(e.Profiles) == 0 {
+
+		return true
+	}
+	for _, profile := range e.Profiles {
+		for _, act := range active {
+			if profile == act {
+
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 // MakeCatalog will create a catalog entry from RuleEntry node.
@@ -69,9 +128,20 @@ func (e *RuleEntry) MakeCatalog(cat *Catalog) {
 			meta.ThenScopeID = e.ThenScope.AstID
 			e.ThenScope.MakeCatalog(cat)
 		}
+		if e.ElseScope != nil {
+			meta.ElseScopeID = e.ElseScope.AstID
+			e.ElseScope.MakeCatalog(cat)
+		}
 		meta.RuleName = e.RuleName
 		meta.RuleDescription = e.RuleDescription
+		meta.RuleID = e.RuleID
 		meta.Salience = e.Salience
+		meta.MaxFires = e.MaxFires
+		meta.Cooldown = e.Cooldown
+		meta.Criticality = e.Criticality
+		meta.Profiles = e.Profiles
+		meta.Sinks = e.Sinks
+		meta.Approvals = e.Approvals
 	}
 }
 
@@ -87,6 +157,85 @@ func (e *RuleEntry) AcceptSalience(salience *Salience) error {
 	return nil
 }
 
+// AcceptRuleID will accept the stable rule id
+func (e *RuleEntry) AcceptRuleID(ruleID *RuleID) error {
+	if len(ruleID.ID) == 0 {
+
+		return fmt.Errorf("id of rule %s must not be empty", e.RuleName)
+	}
+	e.RuleID = ruleID.ID
+
+	return nil
+}
+
+// AcceptMaxFires will accept max-fires value
+func (e *RuleEntry) AcceptMaxFires(maxFires *MaxFires) error {
+	if maxFires.MaxFiresValue < 1 || maxFires.MaxFiresValue > math.MaxInt32 {
+
+		return fmt.Errorf("max-fires must be between 1 and %d, got %d", math.MaxInt32, maxFires.MaxFiresValue)
+	}
+	e.MaxFires = int(maxFires.MaxFiresValue)
+
+	return nil
+}
+
+// AcceptCooldown will accept cooldown value
+func (e *RuleEntry) AcceptCooldown(cooldown *Cooldown) error {
+	if cooldown.Duration <= 0 {
+
+		return fmt.Errorf("cooldown must be a positive duration, got %s", cooldown.Duration)
+	}
+	e.Cooldown = cooldown.Duration
+
+	return nil
+}
+
+// AcceptCriticality will accept criticality value
+func (e *RuleEntry) AcceptCriticality(criticality Criticality) error {
+	e.Criticality = criticality
+
+	return nil
+}
+
+// AcceptProfile will accept the profiles this rule is restricted to
+func (e *RuleEntry) AcceptProfile(profile *Profile) error {
+	for _, name := range profile.Names {
+		if len(name) == 0 {
+
+			return fmt.Errorf("profile name must not be empty")
+		}
+		e.Profiles = append(e.Profiles, name)
+	}
+
+	return nil
+}
+
+// AcceptSink will accept the sinks this rule routes its emitted messages to
+func (e *RuleEntry) AcceptSink(sink *Sink) error {
+	for _, name := range sink.Names {
+		if len(name) == 0 {
+
+			return fmt.Errorf("sink name must not be empty")
+		}
+		e.Sinks = append(e.Sinks, name)
+	}
+
+	return nil
+}
+
+// AcceptApproval will accept the approval categories of this rule
+func (e *RuleEntry) AcceptApproval(approval *Approval) error {
+	for _, category := range approval.Categories {
+		if len(category) == 0 {
+
+			return fmt.Errorf("approval category must not be empty")
+		}
+		e.Approvals = append(e.Approvals, category)
+	}
+
+	return nil
+}
+
 // AcceptWhenScope will accept WhenScope AST Graph into this AST Graph
 func (e *RuleEntry) AcceptWhenScope(when *WhenScope) error {
 	e.WhenScope = when
@@ -94,13 +243,46 @@ func (e *RuleEntry) AcceptWhenScope(when *WhenScope) error {
 	return nil
 }
 
-// AcceptThenScope will accept ThenScope AST Graph into this AST Graph
+// AcceptThenScope will accept ThenScope AST Graph into this AST Graph. A script not compiled yet is compiled with
+// the languages of RegisterScriptLanguage.
 func (e *RuleEntry) AcceptThenScope(thenScope *ThenScope) error {
+	if thenScope.Script != nil && thenScope.Script.Program == nil {
+		err := thenScope.Script.Compile(e.RuleName)
+		if err != nil {
+
+			return err
+		}
+	}
 	e.ThenScope = thenScope
 
 	return nil
 }
 
+// ThenExpressions returns the then expressions of the then scope followed by those of the else scope. A switch
+// statement is followed by the then expressions of its cases. A script then scope has none.
+func (e *RuleEntry) ThenExpressions() []*ThenExpression {
+	thenExprs := make([]*ThenExpression, 0)
+	for _, scope := range []*ThenScope{e.ThenScope, e.ElseScope} {
+		if scope != nil && scope.ThenExpressionList != nil {
+			thenExprs = appendThenExpressions(thenExprs, scope.ThenExpressionList.ThenExpressions)
+		}
+	}
+
+	return thenExprs
+}
+
+// ElseScopeReceiver should be implemented by any rule AST object that receive the ThenScope of an else branch
+type ElseScopeReceiver interface {
+	AcceptElseScope(elseScope *ThenScope) error
+}
+
+// AcceptElseScope will accept the ThenScope AST Graph of the else branch into this AST Graph
+func (e *RuleEntry) AcceptElseScope(elseScope *ThenScope) error {
+	e.ElseScope = elseScope
+
+	return nil
+}
+
 // Clone will clone this RuleEntry. The new clone will have an identical structure
 func (e *RuleEntry) Clone(cloneTable *pkg.CloneTable) *RuleEntry {
 	clone := &RuleEntry{
@@ -108,7 +290,14 @@ func (e *RuleEntry) Clone(cloneTable *pkg.CloneTable) *RuleEntry {
 		GrlText:         e.GrlText,
 		RuleName:        e.RuleName,
 		RuleDescription: e.RuleDescription,
+		RuleID:          e.RuleID,
 		Salience:        e.Salience,
+		MaxFires:        e.MaxFires,
+		Cooldown:        e.Cooldown,
+		Criticality:     e.Criticality,
+		Profiles:        e.Profiles,
+		Sinks:           e.Sinks,
+		Approvals:       e.Approvals,
 		Retracted:       false,
 		Deleted:         e.Deleted,
 	}
@@ -132,6 +321,16 @@ func (e *RuleEntry) Clone(cloneTable *pkg.CloneTable) *RuleEntry {
 		}
 	}
 
+	if e.ElseScope != nil {
+		if cloneTable.IsCloned(e.ElseScope.AstID) {
+			clone.ElseScope = cloneTable.Records[e.ElseScope.AstID].CloneInstance.(*ThenScope)
+		} else {
+			clonedElseScope := e.ElseScope.Clone(cloneTable)
+			clone.ElseScope = clonedElseScope
+			cloneTable.MarkCloned(e.ElseScope.AstID, clonedElseScope.AstID, e.ElseScope, clonedElseScope)
+		}
+	}
+
 	return clone
 }
 
@@ -152,7 +351,33 @@ func (e *RuleEntry) GetSnapshot() string {
 	var buff strings.Builder
 	buff.WriteString(RULEENTRY)
 	buff.WriteString("(")
-	buff.WriteString(fmt.Sprintf("N:%s DEC:\"%s\" SAL:%d W:%s T:%s}", e.RuleName, e.RuleDescription, e.Salience, e.WhenScope.GetSnapshot(), e.ThenScope.GetSnapshot()))
+	buff.WriteString(fmt.Sprintf("N:%s DEC:\"%s\" SAL:%d ", e.RuleName, e.RuleDescription, e.Salience))
+	if len(e.RuleID) > 0 {
+		buff.WriteString(fmt.Sprintf("ID:%q ", e.RuleID))
+	}
+	if e.MaxFires > 0 {
+		buff.WriteString(fmt.Sprintf("MF:%d ", e.MaxFires))
+	}
+	if e.Cooldown > 0 {
+		buff.WriteString(fmt.Sprintf("CD:%s ", e.Cooldown))
+	}
+	if e.Criticality != CriticalityNormal {
+		buff.WriteString(fmt.Sprintf("CR:%s ", e.Criticality))
+	}
+	if len(e.Profiles) > 0 {
+		buff.WriteString(fmt.Sprintf("PR:%q ", e.Profiles))
+	}
+	if len(e.Sinks) > 0 {
+		buff.WriteString(fmt.Sprintf("SK:%q ", e.Sinks))
+	}
+	if len(e.Approvals) > 0 {
+		buff.WriteString(fmt.Sprintf("AP:%q ", e.Approvals))
+	}
+	buff.WriteString(fmt.Sprintf("W:%s T:%s", e.WhenScope.GetSnapshot(), e.ThenScope.GetSnapshot()))
+	if e.ElseScope != nil {
+		buff.WriteString(fmt.Sprintf(" E:%s", e.ElseScope.GetSnapshot()))
+	}
+	buff.WriteString("}")
 	buff.WriteString(")")
 
 	return buff.String()
@@ -164,6 +389,27 @@ func (e *RuleEntry) SetGrlText(grlText string) {
 	e.GrlText = grlText
 }
 
+// CanFire tells whether this rule is still allowed to fire at the specified time according to its
+// max-fires and cooldown attributes.
+func (e *RuleEntry) CanFire(now time.Time) bool {
+	if e.MaxFires > 0 && e.FireCount >= e.MaxFires {
+
+		return false
+	}
+	if e.Cooldown > 0 && !e.LastFired.IsZero() && now.Sub(e.LastFired) < e.Cooldown {
+
+		return false
+	}
+
+	return true
+}
+
+// MarkFired records that this rule has fired at the specified time.
+func (e *RuleEntry) MarkFired(now time.Time) {
+	e.FireCount++
+	e.LastFired = now
+}
+
 // Evaluate will evaluate this AST graph for when scope evaluation
 func (e *RuleEntry) Evaluate(ctx context.Context, dataContext IDataContext, memory *WorkingMemory) (can bool, err error) {
 	if ctx.Err() != nil {
@@ -182,9 +428,13 @@ func (e *RuleEntry) Evaluate(ctx context.Context, dataContext IDataContext, memo
 	}
 	val, err := e.WhenScope.Evaluate(dataContext, memory)
 	if err != nil {
-		AstLog.Errorf("Error while evaluating rule %s, got %v", e.RuleName, err)
+		if errors.Is(err, ErrMissingFact) {
+			memory.log().Debugf("Rule %s refers to a missing fact, got %v", e.RuleName, err)
+		} else {
+			memory.log().Errorf("Error while evaluating rule %s, got %v", e.RuleName, err)
+		}
 
-		return false, fmt.Errorf("evaluating expression in rule '%s' the when raised an error. got %v", e.RuleName, err)
+		return false, fmt.Errorf("evaluating expression in rule '%s' the when raised an error. got %w", e.RuleName, err)
 	}
 	if val.Kind() != reflect.Bool {
 
@@ -210,5 +460,24 @@ func (e *RuleEntry) Execute(ctx context.Context, dataContext IDataContext, memor
 		}
 	}()
 
-	return e.ThenScope.Execute(dataContext, memory)
+	return e.ThenScope.ExecuteWithContext(ctx, dataContext, memory)
+}
+
+// ExecuteElse will execute this graph in the Else scope, when the rule is selected because its when scope is false
+func (e *RuleEntry) ExecuteElse(ctx context.Context, dataContext IDataContext, memory *WorkingMemory) (err error) {
+	if ctx.Err() != nil {
+
+		return fmt.Errorf("context error on executing rule %s. got %w", e.RuleName, ctx.Err())
+	}
+	if e.ElseScope == nil {
+
+		return fmt.Errorf("RuleEntry %s have no else scope", e.RuleName)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rule engine execute panic on the else scope of rule %s ! recovered : %v", e.RuleName, r)
+		}
+	}()
+
+	return e.ElseScope.ExecuteWithContext(ctx, dataContext, memory)
 }
@@ -0,0 +1,36 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+// NewRuleID create new RuleID AST object
+func NewRuleID() *RuleID {
+
+	return &RuleID{}
+}
+
+// RuleID is a simple AST object that stores the stable identifier of a rule, it does not change when the rule is renamed
+type RuleID struct {
+	ID string
+}
+
+// RuleIDReceiver must be implemented by any AST object that stores rule id
+type RuleIDReceiver interface {
+	AcceptRuleID(ruleID *RuleID) error
+}
+
+// AcceptStringLiteral accept the assigned string
+func (id *RuleID) AcceptStringLiteral(lit *StringLiteral) {
+	id.ID = lit.String
+}
@@ -0,0 +1,253 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// MinRuleTableSize is the minimum number of rules compacted into one RuleTable.
+const MinRuleTableSize = 2
+
+// RuleTable indexes the rules whose when scopes differ only in the constant one of their variables is compared to,
+// such as the generated rules
+//
+//	when Fact.Country == "FR" && Fact.Amount > 100
+//	when Fact.Country == "DE" && Fact.Amount > 100
+//
+// Once the variable is evaluated, only the rules indexed by its value may match, the others need not be evaluated.
+type RuleTable struct {
+	// Variable is the expression atom compared to the constants, taken from one of the rules.
+	Variable *ExpressionAtom
+	// Rules indexes the rules by the constant their when scope compares the variable to.
+	Rules map[interface{}][]*RuleEntry
+	// Members are all the rules of the table.
+	Members []*RuleEntry
+
+	// class is the class of the constants, all constants of a table have the same class.
+	class reflect.Kind
+}
+
+// Candidates evaluates the variable of the table and returns the rules that may match its value. It returns false
+// if the value can not be looked up, such as a value of another class than the constants, then every member of the
+// table may match.
+func (t *RuleTable) Candidates(dataContext IDataContext, memory *WorkingMemory) ([]*RuleEntry, bool) {
+	val, err := t.Variable.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return nil, false
+	}
+	key, class, ok := ruleTableKey(val)
+	if !ok || class != t.class {
+
+		return nil, false
+	}
+
+	return t.Rules[key], true
+}
+
+// ruleTableKey normalizes a value compared with == into a map key, numbers become float64, the way
+// pkg.EvaluateEqual compares numbers of different types. It returns the class of the value.
+func ruleTableKey(val reflect.Value) (interface{}, reflect.Kind, bool) {
+	val = pkg.GetValueElem(val)
+	if !val.IsValid() || pkg.IsQuantity(val) || pkg.IsCollection(val) {
+
+		return nil, reflect.Invalid, false
+	}
+	switch val.Kind() {
+	case reflect.String:
+
+		return val.String(), reflect.String, true
+	case reflect.Bool:
+
+		return val.Bool(), reflect.Bool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return float64(val.Int()), reflect.Float64, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// pkg.EvaluateEqual compares a large unsigned integer to a signed one after wrapping it around.
+		if val.Uint() > math.MaxInt64 {
+
+			return nil, reflect.Invalid, false
+		}
+
+		return float64(val.Uint()), reflect.Float64, true
+	case reflect.Float32, reflect.Float64:
+
+		return val.Float(), reflect.Float64, true
+	}
+
+	return nil, reflect.Invalid, false
+}
+
+// ruleTableEntry is a rule that may be compacted, with one of the equalities its when scope requires.
+type ruleTableEntry struct {
+	rule     *RuleEntry
+	variable *ExpressionAtom
+	key      interface{}
+	class    reflect.Kind
+}
+
+// RuleTables returns the tables the rules of this knowledge base are compacted into. A rule belongs to at most
+// one table, the rules that are not in any table are evaluated one by one. The tables are built on first use.
+func (e *KnowledgeBase) RuleTables() []*RuleTable {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.ruleTables == nil {
+		e.ruleTables = buildRuleTables(e.RuleEntries)
+	}
+
+	return e.ruleTables
+}
+
+// buildRuleTables groups the rules by the shape of their when scope with one required equality left out.
+func buildRuleTables(entries map[string]*RuleEntry) []*RuleTable {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := make(map[string][]ruleTableEntry)
+	for _, name := range names {
+		rule := entries[name]
+		if rule.Deleted || rule.WhenScope == nil || rule.WhenScope.Expression == nil {
+
+			continue
+		}
+		conjuncts := flattenConjunction(rule.WhenScope.Expression, nil)
+		snapshots := make([]string, len(conjuncts))
+		for i, conjunct := range conjuncts {
+			snapshots[i] = conjunct.GetSnapshot()
+		}
+		for i, conjunct := range conjuncts {
+			variable, constant := tableEquality(conjunct)
+			if variable == nil {
+
+				continue
+			}
+			key, class, ok := ruleTableKey(constant.Value)
+			if !ok {
+
+				continue
+			}
+			shape := make([]string, len(snapshots))
+			copy(shape, snapshots)
+			shape[i] = variable.GetSnapshot() + "==?" + class.String()
+			groupKey := strings.Join(shape, "&&")
+			groups[groupKey] = append(groups[groupKey], ruleTableEntry{rule: rule, variable: variable, key: key, class: class})
+		}
+	}
+
+	// the largest groups are made tables first, a rule already in a table is left out of the smaller ones.
+	groupKeys := make([]string, 0, len(groups))
+	for key, group := range groups {
+		if len(group) >= MinRuleTableSize {
+			groupKeys = append(groupKeys, key)
+		}
+	}
+	sort.Slice(groupKeys, func(i, j int) bool {
+		if len(groups[groupKeys[i]]) != len(groups[groupKeys[j]]) {
+
+			return len(groups[groupKeys[i]]) > len(groups[groupKeys[j]])
+		}
+
+		return groupKeys[i] < groupKeys[j]
+	})
+	tables := make([]*RuleTable, 0)
+	compacted := make(map[*RuleEntry]bool)
+	for _, groupKey := range groupKeys {
+		members := make([]ruleTableEntry, 0, len(groups[groupKey]))
+		for _, member := range groups[groupKey] {
+			if !compacted[member.rule] {
+				members = append(members, member)
+			}
+		}
+		if len(members) < MinRuleTableSize {
+
+			continue
+		}
+		table := &RuleTable{
+			Variable: members[0].variable,
+			Rules:    make(map[interface{}][]*RuleEntry),
+			Members:  make([]*RuleEntry, 0, len(members)),
+			class:    members[0].class,
+		}
+		for _, member := range members {
+			table.Rules[member.key] = append(table.Rules[member.key], member.rule)
+			table.Members = append(table.Members, member.rule)
+			compacted[member.rule] = true
+		}
+		tables = append(tables, table)
+		AstLog.Debugf("Compacted %d rules comparing %s into a rule table", len(members), table.Variable.GetGrlText())
+	}
+
+	return tables
+}
+
+// flattenConjunction appends the operands of the && chain of the expression, in the order they are evaluated.
+func flattenConjunction(expr *Expression, conjuncts []*Expression) []*Expression {
+	switch {
+	case expr.LeftExpression != nil && expr.RightExpression != nil && expr.Operator == OpAnd:
+		conjuncts = flattenConjunction(expr.LeftExpression, conjuncts)
+
+		return flattenConjunction(expr.RightExpression, conjuncts)
+	case expr.SingleExpression != nil && !expr.Negated:
+
+		return flattenConjunction(expr.SingleExpression, conjuncts)
+	}
+
+	return append(conjuncts, expr)
+}
+
+// tableEquality returns the variable and the constant of an expression such as Fact.Country == "FR", or nils if
+// the expression is not an equality between a variable and a constant.
+func tableEquality(expr *Expression) (*ExpressionAtom, *Constant) {
+	if expr.Operator != OpEq || expr.LeftExpression == nil || expr.RightExpression == nil {
+
+		return nil, nil
+	}
+	left, right := expr.LeftExpression.ExpressionAtom, expr.RightExpression.ExpressionAtom
+	if left == nil || right == nil {
+
+		return nil, nil
+	}
+	if isTableVariable(left) && isTableConstant(right) {
+
+		return left, right.Constant
+	}
+	if isTableVariable(right) && isTableConstant(left) {
+
+		return right, left.Constant
+	}
+
+	return nil, nil
+}
+
+// isTableVariable tells whether the atom is a plain variable, evaluating it calls no function.
+func isTableVariable(atom *ExpressionAtom) bool {
+
+	return atom.Variable != nil && atom.FunctionCall == nil && atom.ExpressionAtom == nil && atom.Constant == nil && !atom.Negated
+}
+
+func isTableConstant(atom *ExpressionAtom) bool {
+
+	return atom.Constant != nil && !atom.Constant.IsNil && !atom.Negated
+}
@@ -0,0 +1,215 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultScriptLanguages are the languages of the registries that are nil, RegisterScriptLanguage adds into them.
+var defaultScriptLanguages = NewScriptLanguageRegistry()
+
+// ScriptLanguage compiles the scripts a rule may have as then scope instead of GRL actions, such as
+//
+//	then starlark ```
+//	    total = 0
+//	    for item in Order.Items:
+//	        total += item.Price
+//	    Order.Total = total
+//	```
+//
+// No language is built in, rules with a script can only be built or loaded once their language is registered
+// with RegisterScriptLanguage, or into the KnowledgeLibrary.ScriptLanguages. This is how scripts are opted in.
+type ScriptLanguage interface {
+	// Name is the language name written after then, it is compared regardless of the case.
+	Name() string
+	// Compile checks the script of a rule, its errors are reported when the rule is built.
+	Compile(ruleName, source string) (ScriptProgram, error)
+}
+
+// ScriptProgram is a compiled script. It is shared by every instance of the KnowledgeBase, so it must be safe
+// to execute concurrently.
+type ScriptProgram interface {
+	// Execute runs the script against the facts of the data context, it must stop once the context is done.
+	Execute(ctx context.Context, dataContext IDataContext) error
+}
+
+// ScriptLanguageRegistry keeps the script languages by name. A nil registry is the default one of the process, so
+// two knowledge libraries may each opt in languages of their own, see KnowledgeLibrary.ScriptLanguages.
+type ScriptLanguageRegistry struct {
+	mutex     sync.RWMutex
+	languages map[string]ScriptLanguage
+}
+
+// NewScriptLanguageRegistry create a ScriptLanguageRegistry without any language.
+func NewScriptLanguageRegistry() *ScriptLanguageRegistry {
+
+	return &ScriptLanguageRegistry{languages: make(map[string]ScriptLanguage)}
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *ScriptLanguageRegistry) or() *ScriptLanguageRegistry {
+	if registry == nil {
+
+		return defaultScriptLanguages
+	}
+
+	return registry
+}
+
+// Register adds or replaces the script language of the same name.
+func (registry *ScriptLanguageRegistry) Register(language ScriptLanguage) error {
+	if language == nil || len(language.Name()) == 0 {
+
+		return fmt.Errorf("script language must have a name")
+	}
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.languages[strings.ToLower(language.Name())] = language
+
+	return nil
+}
+
+// Unregister removes the script language, rules using it can no longer be built or loaded with this registry.
+func (registry *ScriptLanguageRegistry) Unregister(name string) {
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.languages, strings.ToLower(name))
+}
+
+func (registry *ScriptLanguageRegistry) get(name string) (ScriptLanguage, error) {
+	registry = registry.or()
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	language, ok := registry.languages[strings.ToLower(name)]
+	if !ok {
+
+		return nil, fmt.Errorf("script language %s is not registered, register it with RegisterScriptLanguage", name)
+	}
+
+	return language, nil
+}
+
+// RegisterScriptLanguage adds or replaces the script language of the same name in the default registry.
+func RegisterScriptLanguage(language ScriptLanguage) error {
+
+	return defaultScriptLanguages.Register(language)
+}
+
+// UnregisterScriptLanguage removes the script language from the default registry, rules using it can no longer be
+// built or loaded.
+func UnregisterScriptLanguage(name string) {
+	defaultScriptLanguages.Unregister(name)
+}
+
+// NewScriptBlock create new ScriptBlock from its language and the script as written in GRL, between triple backticks.
+// The common indentation of the script lines is removed, so the script can be indented along with the rule.
+func NewScriptBlock(language, text string) *ScriptBlock {
+
+	return &ScriptBlock{
+		Language: strings.ToLower(language),
+		Source:   dedentScript(strings.TrimSuffix(strings.TrimPrefix(text, "```"), "```")),
+	}
+}
+
+// ScriptBlock is a then scope written in a script language instead of GRL.
+type ScriptBlock struct {
+	Language string
+	Source   string
+
+	// Program is the compiled script, nil until the rule owning the script compiles it.
+	Program ScriptProgram
+}
+
+// ScriptBlockReceiver must be implemented by any AST object that will hold a ScriptBlock
+type ScriptBlockReceiver interface {
+	AcceptScriptBlock(script *ScriptBlock) error
+}
+
+// Compile compiles the script with its language registered with RegisterScriptLanguage.
+func (e *ScriptBlock) Compile(ruleName string) error {
+
+	return e.CompileWith(ruleName, nil)
+}
+
+// CompileWith compiles the script with its language of the registry, the default registry if nil.
+func (e *ScriptBlock) CompileWith(ruleName string, languages *ScriptLanguageRegistry) error {
+	language, err := languages.get(e.Language)
+	if err != nil {
+
+		return fmt.Errorf("rule %s : %w", ruleName, err)
+	}
+	program, err := language.Compile(ruleName, e.Source)
+	if err != nil {
+
+		return fmt.Errorf("rule %s has an invalid %s script. got %w", ruleName, e.Language, err)
+	}
+	e.Program = program
+
+	return nil
+}
+
+// Execute runs the script. The script may have changed any fact, so the whole working memory is reset
+// for the next cycle to evaluate every expression again.
+func (e *ScriptBlock) Execute(ctx context.Context, dataContext IDataContext, memory *WorkingMemory) error {
+	if e.Program == nil {
+
+		return fmt.Errorf("%s script is not compiled", e.Language)
+	}
+	err := e.Program.Execute(ctx, dataContext)
+	dataContext.IncrementVariableChangeCount()
+	memory.ResetAll()
+
+	return err
+}
+
+// dedentScript removes the blank lines around the script and the indentation all of its lines have in common.
+func dedentScript(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && len(strings.TrimSpace(lines[0])) == 0 {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && len(strings.TrimSpace(lines[len(lines)-1])) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	indent := ""
+	first := true
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) == 0 {
+
+			continue
+		}
+		lineIndent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			indent = lineIndent
+			first = false
+
+			continue
+		}
+		for !strings.HasPrefix(lineIndent, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -19,10 +19,12 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
 	"github.com/sirupsen/logrus"
 	"io"
 	"math"
 	"reflect"
+	"time"
 )
 
 // NodeType is to label a Meta information within catalog
@@ -67,9 +69,31 @@ const (
 	TypeFloat
 	// TypeBoolean variable type boolean label
 	TypeBoolean
+	// TypeQuantity variable type quantity label
+	TypeQuantity
+	// TypeSuffixLiteral variable type of the user values parsed from suffix literals such as 10_USD
+	TypeSuffixLiteral
+	// TypeSet variable type set label
+	TypeSet
+	// TypeOrderedMap variable type ordered map label
+	TypeOrderedMap
 
 	// Version will be written to the stream and used for compatibility check
-	Version = "1.8"
+	Version = "1.22"
+)
+
+const (
+	// TypeMatchExpression meta type of MatchExpression. It is declared apart to keep the value of
+	// the node types and value types above unchanged.
+	TypeMatchExpression NodeType = iota + TypeWhenScope + 1
+	// TypeHaltEntry meta type of HaltEntry
+	TypeHaltEntry
+	// TypeCollectStatement meta type of CollectStatement
+	TypeCollectStatement
+	// TypeSwitchStatement meta type of SwitchStatement
+	TypeSwitchStatement
+	// TypeQuantifier meta type of Quantifier
+	TypeQuantifier
 )
 
 // Catalog used to catalog all AST nodes in a KnowledgeBase.
@@ -102,6 +126,24 @@ type Catalog struct {
 // the rebuilt KnowledgeBase is identical to the original KnowledgeBase from
 // which this Catalog was built.
 func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
+
+	return cat.BuildKnowledgeBaseFor(nil)
+}
+
+// BuildKnowledgeBaseFor rebuilds the knowledgebase like BuildKnowledgeBase does, with the units, the literal suffixes
+// and the script languages of the library. The knowledgebase is not added into the library.
+func (cat *Catalog) BuildKnowledgeBaseFor(lib *KnowledgeLibrary) (*KnowledgeBase, error) {
+	var units *pkg.UnitRegistry
+	var suffixes *pkg.LiteralSuffixRegistry
+	var languages *ScriptLanguageRegistry
+	if lib != nil {
+		units, suffixes, languages = lib.Units, lib.LiteralSuffixes, lib.ScriptLanguages
+	}
+	err := cat.Verify()
+	if err != nil {
+
+		return nil, fmt.Errorf("catalog verification failed : %w", err)
+	}
 	workingMem := &WorkingMemory{
 		Name:                      cat.MemoryName,
 		Version:                   cat.MemoryVersion,
@@ -111,6 +153,7 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 		expressionVariableMap:     make(map[*Variable][]*Expression),
 		expressionAtomVariableMap: make(map[*Variable][]*ExpressionAtom),
 		ID:                        unique.NewID(),
+		Units:                     units,
 	}
 	knowledgeBase := &KnowledgeBase{
 		Name:          cat.KnowledgeBaseName,
@@ -153,6 +196,54 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 				IsMulAssign:   amet.IsMulAssign,
 			}
 			importTable[amet.AstID] = assignment
+		case TypeMatchExpression:
+			amet := meta.(*MatchExpressionMeta)
+			match := &MatchExpression{
+				AstID:   amet.AstID,
+				GrlText: amet.GrlText,
+				Arms:    make([]*MatchArm, len(amet.ArmOperators)),
+			}
+			for i := range match.Arms {
+				match.Arms[i] = &MatchArm{
+					Operator: amet.ArmOperators[i],
+					Default:  len(amet.ArmPatternIDs[i]) == 0,
+				}
+				if i < len(amet.ArmGrlTexts) {
+					match.Arms[i].GrlText = amet.ArmGrlTexts[i]
+				}
+			}
+			importTable[amet.AstID] = match
+		case TypeCollectStatement:
+			amet := meta.(*CollectStatementMeta)
+			collect := &CollectStatement{
+				AstID:   amet.AstID,
+				GrlText: amet.GrlText,
+				Name:    amet.Name,
+			}
+			importTable[amet.AstID] = collect
+		case TypeSwitchStatement:
+			amet := meta.(*SwitchStatementMeta)
+			switchStmt := &SwitchStatement{
+				AstID:   amet.AstID,
+				GrlText: amet.GrlText,
+				Cases:   make([]*SwitchCase, len(amet.CaseGrlTexts)),
+			}
+			for i := range switchStmt.Cases {
+				switchStmt.Cases[i] = &SwitchCase{
+					GrlText: amet.CaseGrlTexts[i],
+					Default: len(amet.CaseValueIDs[i]) == 0,
+				}
+			}
+			importTable[amet.AstID] = switchStmt
+		case TypeQuantifier:
+			amet := meta.(*QuantifierMeta)
+			quantifier := &Quantifier{
+				AstID:   amet.AstID,
+				GrlText: amet.GrlText,
+				ForAll:  amet.ForAll,
+				Name:    amet.Name,
+			}
+			importTable[amet.AstID] = quantifier
 		case TypeExpression:
 			amet := meta.(*ExpressionMeta)
 			expression := &Expression{
@@ -213,6 +304,44 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 				bits := binary.LittleEndian.Uint64(arr)
 				float := math.Float64frombits(bits)
 				newConst.Value = reflect.ValueOf(float)
+			case TypeQuantity:
+				arr := make([]byte, 16)
+				_, err := buffer.Read(arr)
+				if err != nil {
+					return nil, err
+				}
+				unit := make([]byte, binary.LittleEndian.Uint64(arr[8:]))
+				_, err = buffer.Read(unit)
+				if err != nil {
+					return nil, err
+				}
+				newConst.Value = reflect.ValueOf(pkg.Quantity{
+					Value: math.Float64frombits(binary.LittleEndian.Uint64(arr[:8])),
+					Unit:  string(unit),
+				})
+			case TypeSet, TypeOrderedMap:
+				collection, err := readCollection(buffer, amet.ValueType)
+				if err != nil {
+					return nil, err
+				}
+				newConst.Value = reflect.ValueOf(collection)
+			case TypeSuffixLiteral:
+				length := make([]byte, 8)
+				_, err := buffer.Read(length)
+				if err != nil {
+					return nil, err
+				}
+				literal := make([]byte, binary.LittleEndian.Uint64(length))
+				_, err = buffer.Read(literal)
+				if err != nil {
+					return nil, err
+				}
+				value, err := suffixes.Parse(string(literal))
+				if err != nil {
+					return nil, err
+				}
+				newConst.Value = reflect.ValueOf(value)
+				newConst.Literal = string(literal)
 			}
 			importTable[amet.AstID] = newConst
 		case TypeExpressionAtom:
@@ -246,6 +375,13 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 				RuleName:        amet.RuleName,
 				RuleDescription: amet.RuleDescription,
 				Salience:        amet.Salience,
+				MaxFires:        amet.MaxFires,
+				Cooldown:        amet.Cooldown,
+				RuleID:          amet.RuleID,
+				Criticality:     amet.Criticality,
+				Profiles:        amet.Profiles,
+				Sinks:           amet.Sinks,
+				Approvals:       amet.Approvals,
 				WhenScope:       nil,
 				ThenScope:       nil,
 			}
@@ -275,6 +411,12 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 				GrlText:            amet.GrlText,
 				ThenExpressionList: nil,
 			}
+			if len(amet.ScriptLanguage) > 0 {
+				n.Script = &ScriptBlock{
+					Language: amet.ScriptLanguage,
+					Source:   amet.ScriptSource,
+				}
+			}
 			importTable[amet.AstID] = n
 		case TypeVariable:
 			amet := meta.(*VariableMeta)
@@ -294,6 +436,15 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 				Expression: nil,
 			}
 			importTable[amet.AstID] = n
+		case TypeHaltEntry:
+			amet := meta.(*HaltEntryMeta)
+			haltEntry := &HaltEntry{
+				AstID:      amet.AstID,
+				GrlText:    amet.GrlText,
+				Expression: nil,
+			}
+			importTable[amet.AstID] = haltEntry
+			knowledgeBase.HaltEntries = append(knowledgeBase.HaltEntries, haltEntry)
 		default:
 			return nil, fmt.Errorf("unrecognized meta type %d", meta.GetASTType())
 		}
@@ -327,6 +478,55 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			if len(amet.VariableID) > 0 {
 				assignment.Variable = importTable[amet.VariableID].(*Variable)
 			}
+			if len(amet.MatchExpressionID) > 0 {
+				assignment.Match = importTable[amet.MatchExpressionID].(*MatchExpression)
+			}
+		case TypeMatchExpression:
+			match := node.(*MatchExpression)
+			amet := meta.(*MatchExpressionMeta)
+			if len(amet.SubjectID) > 0 {
+				match.Subject = importTable[amet.SubjectID].(*Expression)
+			}
+			for i, arm := range match.Arms {
+				if len(amet.ArmPatternIDs[i]) > 0 {
+					arm.Pattern = importTable[amet.ArmPatternIDs[i]].(*Expression)
+				}
+				if len(amet.ArmValueIDs[i]) > 0 {
+					arm.Value = importTable[amet.ArmValueIDs[i]].(*Expression)
+				}
+			}
+		case TypeCollectStatement:
+			collect := node.(*CollectStatement)
+			amet := meta.(*CollectStatementMeta)
+			if len(amet.SourceID) > 0 {
+				collect.Source = importTable[amet.SourceID].(*Expression)
+			}
+			if len(amet.ConditionID) > 0 {
+				collect.Condition = importTable[amet.ConditionID].(*Expression)
+			}
+		case TypeSwitchStatement:
+			switchStmt := node.(*SwitchStatement)
+			amet := meta.(*SwitchStatementMeta)
+			if len(amet.SubjectID) > 0 {
+				switchStmt.Subject = importTable[amet.SubjectID].(*Expression)
+			}
+			for i, switchCase := range switchStmt.Cases {
+				for _, id := range amet.CaseValueIDs[i] {
+					switchCase.Values = append(switchCase.Values, importTable[id].(*Expression))
+				}
+				if len(amet.CaseThenIDs[i]) > 0 {
+					switchCase.Then = importTable[amet.CaseThenIDs[i]].(*ThenExpressionList)
+				}
+			}
+		case TypeQuantifier:
+			quantifier := node.(*Quantifier)
+			amet := meta.(*QuantifierMeta)
+			if len(amet.SourceID) > 0 {
+				quantifier.Source = importTable[amet.SourceID].(*Expression)
+			}
+			if len(amet.PredicateID) > 0 {
+				quantifier.Predicate = importTable[amet.PredicateID].(*Expression)
+			}
 		case TypeExpression:
 			expr := node.(*Expression)
 			amet := meta.(*ExpressionMeta)
@@ -342,6 +542,9 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			if len(amet.ExpressionAtomID) > 0 {
 				expr.ExpressionAtom = importTable[amet.ExpressionAtomID].(*ExpressionAtom)
 			}
+			if len(amet.ToleranceExpressionID) > 0 {
+				expr.Tolerance = importTable[amet.ToleranceExpressionID].(*Expression)
+			}
 		case TypeConstant:
 			// nothing todo
 
@@ -363,6 +566,9 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			if len(amet.ArrayMapSelectorID) > 0 {
 				expressAtm.ArrayMapSelector = importTable[amet.ArrayMapSelectorID].(*ArrayMapSelector)
 			}
+			if len(amet.QuantifierID) > 0 {
+				expressAtm.Quantifier = importTable[amet.QuantifierID].(*Quantifier)
+			}
 		case TypeFunctionCall:
 			funcCall := node.(*FunctionCall)
 			amet := meta.(*FunctionCallMeta)
@@ -377,6 +583,16 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			}
 			if len(amet.ThenScopeID) > 0 {
 				ruleEntry.ThenScope = importTable[amet.ThenScopeID].(*ThenScope)
+				if ruleEntry.ThenScope.Script != nil {
+					err := ruleEntry.ThenScope.Script.CompileWith(ruleEntry.RuleName, languages)
+					if err != nil {
+
+						return nil, err
+					}
+				}
+			}
+			if len(amet.ElseScopeID) > 0 {
+				ruleEntry.ElseScope = importTable[amet.ElseScopeID].(*ThenScope)
 			}
 		case TypeThenExpression:
 			thenExpr := node.(*ThenExpression)
@@ -384,6 +600,12 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			if len(amet.AssignmentID) > 0 {
 				thenExpr.Assignment = importTable[amet.AssignmentID].(*Assignment)
 			}
+			if len(amet.CollectStatementID) > 0 {
+				thenExpr.Collect = importTable[amet.CollectStatementID].(*CollectStatement)
+			}
+			if len(amet.SwitchStatementID) > 0 {
+				thenExpr.Switch = importTable[amet.SwitchStatementID].(*SwitchStatement)
+			}
 			if len(amet.ExpressionAtomID) > 0 {
 				thenExpr.ExpressionAtom = importTable[amet.ExpressionAtomID].(*ExpressionAtom)
 			}
@@ -421,6 +643,12 @@ func (cat *Catalog) BuildKnowledgeBase() (*KnowledgeBase, error) {
 			if len(amet.ExpressionID) > 0 {
 				whenScope.Expression = importTable[amet.ExpressionID].(*Expression)
 			}
+		case TypeHaltEntry:
+			haltEntry := node.(*HaltEntry)
+			amet := meta.(*HaltEntryMeta)
+			if len(amet.ExpressionID) > 0 {
+				haltEntry.Expression = importTable[amet.ExpressionID].(*Expression)
+			}
 		default:
 			return nil, fmt.Errorf("unknown AST type")
 		}
@@ -577,24 +805,22 @@ func (cat *Catalog) Equals(that *Catalog) bool {
 
 // ReadCatalogFromReader would read a byte stream from reader
 // It will replace all values already sets in a catalog.
+// A catalog written by an older library version is migrated into the current Version,
+// if the version can not be read, the returned error matches ErrIncompatibleCatalog.
 // You are responsible for closing the reader stream once its done.
 func (cat *Catalog) ReadCatalogFromReader(reader io.Reader) error {
 	// Read the catalog file version.
-	str, err := ReadStringFromReader(reader) // V
+	version, format, err := readCatalogVersion(reader) // V
 	if err != nil {
 
 		return err
 	}
-	if str != Version {
-
-		return fmt.Errorf("invalid version %s", str)
-	}
 
 	// Read the knowledgebase name.
-	str, err = ReadStringFromReader(reader) // V
+	str, err := ReadStringFromReader(reader) // V
 	if err != nil {
 
-		return err
+		return fmt.Errorf("failed to read knowledgebase name : %w", err)
 	}
 	cat.KnowledgeBaseName = str
 
@@ -602,7 +828,7 @@ func (cat *Catalog) ReadCatalogFromReader(reader io.Reader) error {
 	str, err = ReadStringFromReader(reader) // V
 	if err != nil {
 
-		return err
+		return fmt.Errorf("failed to read knowledgebase version : %w", err)
 	}
 	cat.KnowledgeBaseVersion = str
 
@@ -610,7 +836,7 @@ func (cat *Catalog) ReadCatalogFromReader(reader io.Reader) error {
 	count, err := ReadIntFromReader(reader) // V
 	if err != nil {
 
-		return err
+		return fmt.Errorf("failed to read meta count : %w", err)
 	}
 
 	cat.Data = make(map[string]Meta)
@@ -619,49 +845,17 @@ func (cat *Catalog) ReadCatalogFromReader(reader io.Reader) error {
 		key, err := ReadStringFromReader(reader) // V
 		if err != nil {
 
-			return err
+			return fmt.Errorf("failed to read meta %d of %d : %w", i+1, count, err)
 		}
 		metaType, err := ReadIntFromReader(reader) // V
 		if err != nil {
 
-			return err
-		}
-		var meta Meta
-		switch NodeType(metaType) {
-		case TypeArgumentList:
-			meta = &ArgumentListMeta{}
-		case TypeArrayMapSelector:
-			meta = &ArrayMapSelectorMeta{}
-		case TypeAssignment:
-			meta = &AssigmentMeta{}
-		case TypeConstant:
-			meta = &ConstantMeta{}
-		case TypeExpression:
-			meta = &ExpressionMeta{}
-		case TypeExpressionAtom:
-			meta = &ExpressionAtomMeta{}
-		case TypeFunctionCall:
-			meta = &FunctionCallMeta{}
-		case TypeRuleEntry:
-			meta = &RuleEntryMeta{}
-		case TypeThenExpression:
-			meta = &ThenExpressionMeta{}
-		case TypeThenExpressionList:
-			meta = &ThenExpressionListMeta{}
-		case TypeThenScope:
-			meta = &ThenScopeMeta{}
-		case TypeVariable:
-			meta = &VariableMeta{}
-		case TypeWhenScope:
-			meta = &WhenScopeMeta{}
-		default:
-
-			return fmt.Errorf("unknown meta number %d", metaType)
+			return fmt.Errorf("failed to read type of meta %s : %w", key, err)
 		}
-		err = meta.ReadMetaFrom(reader) // V
+		meta, err := format.readMeta(reader, NodeType(metaType)) // V
 		if err != nil {
 
-			return err
+			return fmt.Errorf("failed to read meta %s in catalog version %s : %w", key, version, err)
 		}
 		cat.Data[key] = meta
 	}
@@ -808,7 +1002,7 @@ func (cat *Catalog) ReadCatalogFromReader(reader io.Reader) error {
 		cat.MemoryExpressionAtomVariableMap[key] = content
 	}
 
-	return nil
+	return migrateCatalog(cat, version)
 }
 
 // WriteCatalogToWriter will store the content of this Catalog
@@ -1287,6 +1481,8 @@ type AssigmentMeta struct {
 	IsMinusAssign bool
 	IsDivAssign   bool
 	IsMulAssign   bool
+
+	MatchExpressionID string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -1324,6 +1520,10 @@ func (meta *AssigmentMeta) Equals(that Meta) bool {
 
 			return false
 		}
+		if meta.MatchExpressionID != ins.MatchExpressionID {
+
+			return false
+		}
 
 		return true
 	}
@@ -1384,13 +1584,26 @@ func (meta *AssigmentMeta) WriteMetaTo(writer io.Writer) error {
 		return err
 	}
 
-	return nil
+	return WriteStringToWriter(writer, meta.MatchExpressionID)
 }
 
 // ReadMetaFrom write basic AST Node information meta data from reader.
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *AssigmentMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV110From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.MatchExpressionID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV110From reads the assignment meta as laid out in catalog version 1.10,
+// which predates the match expression.
+func (meta *AssigmentMeta) readMetaV110From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -1448,6 +1661,159 @@ func (meta *AssigmentMeta) ReadMetaFrom(reader io.Reader) error {
 	return nil
 }
 
+// MatchExpressionMeta meta data for a MatchExpression node, its arms are laid out in parallel slices.
+// A default arm has an empty pattern id.
+type MatchExpressionMeta struct {
+	NodeMeta
+	SubjectID     string
+	ArmGrlTexts   []string
+	ArmOperators  []int
+	ArmPatternIDs []string
+	ArmValueIDs   []string
+}
+
+// Equals basic function to test equality of two MetaNode
+func (meta *MatchExpressionMeta) Equals(that Meta) bool {
+	if ins, ok := that.(*MatchExpressionMeta); ok {
+		if !meta.NodeMeta.Equals(that) {
+
+			return false
+		}
+		if meta.SubjectID != ins.SubjectID {
+
+			return false
+		}
+		if len(meta.ArmOperators) != len(ins.ArmOperators) || len(meta.ArmGrlTexts) != len(ins.ArmGrlTexts) {
+
+			return false
+		}
+		for k, v := range meta.ArmOperators {
+			if ins.ArmOperators[k] != v || ins.ArmPatternIDs[k] != meta.ArmPatternIDs[k] || ins.ArmValueIDs[k] != meta.ArmValueIDs[k] {
+
+				return false
+			}
+		}
+		for k, v := range meta.ArmGrlTexts {
+			if ins.ArmGrlTexts[k] != v {
+
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// GetASTType returns the meta type of this AST Node
+func (meta *MatchExpressionMeta) GetASTType() NodeType {
+
+	return TypeMatchExpression
+}
+
+// WriteMetaTo write basic AST Node information meta data into writer.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *MatchExpressionMeta) WriteMetaTo(writer io.Writer) error {
+	err := meta.NodeMeta.WriteMetaTo(writer)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.SubjectID)
+	if err != nil {
+
+		return err
+	}
+
+	// Write the number of arms, then each arm
+	err = WriteIntToWriter(writer, uint64(len(meta.ArmOperators)))
+	if err != nil {
+
+		return err
+	}
+	for i, operator := range meta.ArmOperators {
+		grlText := ""
+		if i < len(meta.ArmGrlTexts) {
+			grlText = meta.ArmGrlTexts[i]
+		}
+		err = WriteStringToWriter(writer, grlText)
+		if err != nil {
+
+			return err
+		}
+		err = WriteIntToWriter(writer, uint64(operator))
+		if err != nil {
+
+			return err
+		}
+		err = WriteStringToWriter(writer, meta.ArmPatternIDs[i])
+		if err != nil {
+
+			return err
+		}
+		err = WriteStringToWriter(writer, meta.ArmValueIDs[i])
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadMetaFrom write basic AST Node information meta data from reader.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *MatchExpressionMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.NodeMeta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.SubjectID, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+
+	count, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ArmGrlTexts = make([]string, count)
+	meta.ArmOperators = make([]int, count)
+	meta.ArmPatternIDs = make([]string, count)
+	meta.ArmValueIDs = make([]string, count)
+	for index := uint64(0); index < count; index++ {
+		meta.ArmGrlTexts[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+		operator, err := ReadIntFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+		meta.ArmOperators[index] = int(operator)
+		meta.ArmPatternIDs[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+		meta.ArmValueIDs[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ConstantMeta meta data for an Constant node
 type ConstantMeta struct {
 	NodeMeta
@@ -1574,12 +1940,13 @@ func (meta *ConstantMeta) ReadMetaFrom(reader io.Reader) error {
 // ExpressionMeta meta data for an Expression node
 type ExpressionMeta struct {
 	NodeMeta
-	LeftExpressionID   string
-	RightExpressionID  string
-	SingleExpressionID string
-	ExpressionAtomID   string
-	Operator           int
-	Negated            bool
+	LeftExpressionID      string
+	RightExpressionID     string
+	SingleExpressionID    string
+	ExpressionAtomID      string
+	Operator              int
+	Negated               bool
+	ToleranceExpressionID string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -1613,6 +1980,10 @@ func (meta *ExpressionMeta) Equals(that Meta) bool {
 
 			return false
 		}
+		if meta.ToleranceExpressionID != ins.ToleranceExpressionID {
+
+			return false
+		}
 
 		return true
 	}
@@ -1665,6 +2036,11 @@ func (meta *ExpressionMeta) WriteMetaTo(writer io.Writer) error {
 
 		return err
 	}
+	err = WriteStringToWriter(writer, meta.ToleranceExpressionID)
+	if err != nil {
+
+		return err
+	}
 
 	return nil
 }
@@ -1673,6 +2049,19 @@ func (meta *ExpressionMeta) WriteMetaTo(writer io.Writer) error {
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *ExpressionMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV118From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ToleranceExpressionID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV118From reads the expression meta as laid out in catalog version 1.18,
+// which predates the tolerance of the ~== comparisons.
+func (meta *ExpressionMeta) readMetaV118From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -1728,6 +2117,7 @@ type ExpressionAtomMeta struct {
 	Negated            bool
 	ExpressionAtomID   string
 	ArrayMapSelectorID string
+	QuantifierID       string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -1766,6 +2156,10 @@ func (meta *ExpressionAtomMeta) Equals(that Meta) bool {
 
 			return false
 		}
+		if meta.QuantifierID != ins.QuantifierID {
+
+			return false
+		}
 
 		return true
 	}
@@ -1824,13 +2218,26 @@ func (meta *ExpressionAtomMeta) WriteMetaTo(writer io.Writer) error {
 		return err
 	}
 
-	return nil
+	return WriteStringToWriter(writer, meta.QuantifierID)
 }
 
 // ReadMetaFrom write basic AST Node information meta data from reader.
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *ExpressionAtomMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV121From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.QuantifierID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV121From reads the expression atom meta as laid out in catalog version 1.21,
+// which predates the quantifiers.
+func (meta *ExpressionAtomMeta) readMetaV121From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -1972,8 +2379,16 @@ type RuleEntryMeta struct {
 	RuleName        string
 	RuleDescription string
 	Salience        int
+	MaxFires        int
+	Cooldown        time.Duration
+	RuleID          string
+	Criticality     Criticality
+	Profiles        []string
+	Sinks           []string
+	Approvals       []string
 	WhenScopeID     string
 	ThenScopeID     string
+	ElseScopeID     string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -1995,18 +2410,68 @@ func (meta *RuleEntryMeta) Equals(that Meta) bool {
 
 			return false
 		}
-		if meta.WhenScopeID != ins.WhenScopeID {
+		if meta.MaxFires != ins.MaxFires {
 
 			return false
 		}
-		if meta.ThenScopeID != ins.ThenScopeID {
+		if meta.Cooldown != ins.Cooldown {
 
 			return false
 		}
+		if meta.RuleID != ins.RuleID {
+
+			return false
+		}
+		if meta.Criticality != ins.Criticality {
+
+			return false
+		}
+		if len(meta.Profiles) != len(ins.Profiles) {
+
+			return false
+		}
+		for k, v := range meta.Profiles {
+			if ins.Profiles[k] != v {
+
+				return false
+			}
+		}
+		if len(meta.Sinks) != len(ins.Sinks) {
+
+			return false
+		}
+		for k, v := range meta.Sinks {
+			if ins.Sinks[k] != v {
+
+				return false
+			}
+		}
+		if len(meta.Approvals) != len(ins.Approvals) {
+
+			return false
+		}
+		for k, v := range meta.Approvals {
+			if ins.Approvals[k] != v {
+
+				return false
+			}
+		}
+		if meta.WhenScopeID != ins.WhenScopeID {
+
+			return false
+		}
+		if meta.ThenScopeID != ins.ThenScopeID {
+
+			return false
+		}
+		if meta.ElseScopeID != ins.ElseScopeID {
+
+			return false
+		}
+
+		return true
+	}
 
-		return true
-	}
-
 	return false
 }
 
@@ -2050,14 +2515,213 @@ func (meta *RuleEntryMeta) WriteMetaTo(writer io.Writer) error {
 
 		return err
 	}
+	err = WriteIntToWriter(writer, uint64(meta.MaxFires))
+	if err != nil {
 
-	return nil
+		return err
+	}
+	err = WriteIntToWriter(writer, uint64(meta.Cooldown))
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.RuleID)
+	if err != nil {
+
+		return err
+	}
+	err = WriteIntToWriter(writer, uint64(meta.Criticality))
+	if err != nil {
+
+		return err
+	}
+	err = WriteIntToWriter(writer, uint64(len(meta.Sinks)))
+	if err != nil {
+
+		return err
+	}
+	for _, v := range meta.Sinks {
+		err = WriteStringToWriter(writer, v)
+		if err != nil {
+
+			return err
+		}
+	}
+	err = WriteIntToWriter(writer, uint64(len(meta.Approvals)))
+	if err != nil {
+
+		return err
+	}
+	for _, v := range meta.Approvals {
+		err = WriteStringToWriter(writer, v)
+		if err != nil {
+
+			return err
+		}
+	}
+	err = WriteIntToWriter(writer, uint64(len(meta.Profiles)))
+	if err != nil {
+
+		return err
+	}
+	for _, v := range meta.Profiles {
+		err = WriteStringToWriter(writer, v)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return WriteStringToWriter(writer, meta.ElseScopeID)
 }
 
 // ReadMetaFrom write basic AST Node information meta data from reader.
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *RuleEntryMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV119From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ElseScopeID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV119From reads the rule entry meta as laid out in catalog version 1.19,
+// which predates the else scope.
+func (meta *RuleEntryMeta) readMetaV119From(reader io.Reader) error {
+	err := meta.readMetaV117From(reader)
+	if err != nil {
+
+		return err
+	}
+	count, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Profiles = make([]string, count)
+	for index := uint64(0); index < count; index++ {
+		meta.Profiles[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMetaV117From reads the rule entry meta as laid out in catalog version 1.17,
+// which predates the profiles attribute.
+func (meta *RuleEntryMeta) readMetaV117From(reader io.Reader) error {
+	err := meta.readMetaV115From(reader)
+	if err != nil {
+
+		return err
+	}
+	count, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Approvals = make([]string, count)
+	for index := uint64(0); index < count; index++ {
+		meta.Approvals[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMetaV115From reads the rule entry meta as laid out in catalog version 1.15,
+// which predates the approvals attribute.
+func (meta *RuleEntryMeta) readMetaV115From(reader io.Reader) error {
+	err := meta.readMetaV114From(reader)
+	if err != nil {
+
+		return err
+	}
+	count, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Sinks = make([]string, count)
+	for index := uint64(0); index < count; index++ {
+		meta.Sinks[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMetaV114From reads the rule entry meta as laid out in catalog version 1.14,
+// which predates the sinks attribute.
+func (meta *RuleEntryMeta) readMetaV114From(reader io.Reader) error {
+	err := meta.readMetaV111From(reader)
+	if err != nil {
+
+		return err
+	}
+	i, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Criticality = Criticality(int64(i))
+
+	return nil
+}
+
+// readMetaV111From reads the rule entry meta as laid out in catalog version 1.11,
+// which predates the criticality attribute.
+func (meta *RuleEntryMeta) readMetaV111From(reader io.Reader) error {
+	err := meta.readMetaV19From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.RuleID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV19From reads the rule entry meta as laid out in catalog version 1.9,
+// which predates the rule id attribute.
+func (meta *RuleEntryMeta) readMetaV19From(reader io.Reader) error {
+	err := meta.readMetaV18From(reader)
+	if err != nil {
+
+		return err
+	}
+	i, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.MaxFires = int(i)
+	i, err = ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Cooldown = time.Duration(i)
+
+	return nil
+}
+
+// readMetaV18From reads the rule entry meta as laid out in catalog version 1.8,
+// which predates the max-fires and cooldown attributes.
+func (meta *RuleEntryMeta) readMetaV18From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -2101,8 +2765,10 @@ func (meta *RuleEntryMeta) ReadMetaFrom(reader io.Reader) error {
 type ThenExpressionMeta struct {
 	NodeMeta
 
-	AssignmentID     string
-	ExpressionAtomID string
+	AssignmentID       string
+	ExpressionAtomID   string
+	CollectStatementID string
+	SwitchStatementID  string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -2120,6 +2786,14 @@ func (meta *ThenExpressionMeta) Equals(that Meta) bool {
 
 			return false
 		}
+		if meta.CollectStatementID != ins.CollectStatementID {
+
+			return false
+		}
+		if meta.SwitchStatementID != ins.SwitchStatementID {
+
+			return false
+		}
 
 		return true
 	}
@@ -2153,13 +2827,45 @@ func (meta *ThenExpressionMeta) WriteMetaTo(writer io.Writer) error {
 		return err
 	}
 
-	return nil
+	err = WriteStringToWriter(writer, meta.CollectStatementID)
+	if err != nil {
+
+		return err
+	}
+
+	return WriteStringToWriter(writer, meta.SwitchStatementID)
 }
 
 // ReadMetaFrom write basic AST Node information meta data from reader.
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *ThenExpressionMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV120From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.SwitchStatementID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV120From reads the then expression meta as laid out in catalog version 1.20,
+// which predates the switch statement.
+func (meta *ThenExpressionMeta) readMetaV120From(reader io.Reader) error {
+	err := meta.readMetaV116From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.CollectStatementID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV116From reads the then expression meta as laid out in catalog version 1.16,
+// which predates the collect statement.
+func (meta *ThenExpressionMeta) readMetaV116From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -2278,6 +2984,8 @@ func (meta *ThenExpressionListMeta) ReadMetaFrom(reader io.Reader) error {
 type ThenScopeMeta struct {
 	NodeMeta
 	ThenExpressionListID string
+	ScriptLanguage       string
+	ScriptSource         string
 }
 
 // Equals basic function to test equality of two MetaNode
@@ -2291,6 +2999,14 @@ func (meta *ThenScopeMeta) Equals(that Meta) bool {
 
 			return false
 		}
+		if meta.ScriptLanguage != ins.ScriptLanguage {
+
+			return false
+		}
+		if meta.ScriptSource != ins.ScriptSource {
+
+			return false
+		}
 
 		return true
 	}
@@ -2318,6 +3034,16 @@ func (meta *ThenScopeMeta) WriteMetaTo(writer io.Writer) error {
 
 		return err
 	}
+	err = WriteStringToWriter(writer, meta.ScriptLanguage)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.ScriptSource)
+	if err != nil {
+
+		return err
+	}
 
 	return nil
 }
@@ -2326,6 +3052,24 @@ func (meta *ThenScopeMeta) WriteMetaTo(writer io.Writer) error {
 // One should not use this function directly, unless for testing
 // serialization of single ASTNode.
 func (meta *ThenScopeMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.readMetaV112From(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ScriptLanguage, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ScriptSource, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// readMetaV112From reads the then scope meta as laid out in catalog version 1.12,
+// which predates the script then scope.
+func (meta *ThenScopeMeta) readMetaV112From(reader io.Reader) error {
 	err := meta.NodeMeta.ReadMetaFrom(reader)
 	if err != nil {
 
@@ -2508,6 +3252,409 @@ func (meta *WhenScopeMeta) ReadMetaFrom(reader io.Reader) error {
 	return nil
 }
 
+// HaltEntryMeta meta data for an HaltEntry node
+type HaltEntryMeta struct {
+	NodeMeta
+	ExpressionID string
+}
+
+// Equals basic function to test equality of two MetaNode
+func (meta *HaltEntryMeta) Equals(that Meta) bool {
+	if ins, ok := that.(*HaltEntryMeta); ok {
+		if !meta.NodeMeta.Equals(that) {
+
+			return false
+		}
+		if meta.ExpressionID != ins.ExpressionID {
+
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// GetASTType returns the meta type of this AST Node
+func (meta *HaltEntryMeta) GetASTType() NodeType {
+
+	return TypeHaltEntry
+}
+
+// WriteMetaTo write basic AST Node information meta data into writer.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *HaltEntryMeta) WriteMetaTo(writer io.Writer) error {
+	err := meta.NodeMeta.WriteMetaTo(writer)
+	if err != nil {
+
+		return err
+	}
+
+	return WriteStringToWriter(writer, meta.ExpressionID)
+}
+
+// ReadMetaFrom write basic AST Node information meta data from reader.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *HaltEntryMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.NodeMeta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ExpressionID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// CollectStatementMeta meta data for a CollectStatement node
+type CollectStatementMeta struct {
+	NodeMeta
+
+	Name        string
+	SourceID    string
+	ConditionID string
+}
+
+// Equals basic function to test equality of two MetaNode
+func (meta *CollectStatementMeta) Equals(that Meta) bool {
+	if ins, ok := that.(*CollectStatementMeta); ok {
+		if !meta.NodeMeta.Equals(that) {
+
+			return false
+		}
+		if meta.Name != ins.Name {
+
+			return false
+		}
+		if meta.SourceID != ins.SourceID {
+
+			return false
+		}
+		if meta.ConditionID != ins.ConditionID {
+
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// GetASTType returns the meta type of this AST Node
+func (meta *CollectStatementMeta) GetASTType() NodeType {
+
+	return TypeCollectStatement
+}
+
+// WriteMetaTo write basic AST Node information meta data into writer.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *CollectStatementMeta) WriteMetaTo(writer io.Writer) error {
+	err := meta.NodeMeta.WriteMetaTo(writer)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.Name)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.SourceID)
+	if err != nil {
+
+		return err
+	}
+
+	return WriteStringToWriter(writer, meta.ConditionID)
+}
+
+// ReadMetaFrom write basic AST Node information meta data from reader.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *CollectStatementMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.NodeMeta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Name, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.SourceID, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ConditionID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// QuantifierMeta meta data for a Quantifier node
+type QuantifierMeta struct {
+	NodeMeta
+
+	ForAll      bool
+	Name        string
+	SourceID    string
+	PredicateID string
+}
+
+// Equals basic function to test equality of two MetaNode
+func (meta *QuantifierMeta) Equals(that Meta) bool {
+	if ins, ok := that.(*QuantifierMeta); ok {
+		if !meta.NodeMeta.Equals(that) {
+
+			return false
+		}
+		if meta.ForAll != ins.ForAll {
+
+			return false
+		}
+		if meta.Name != ins.Name {
+
+			return false
+		}
+		if meta.SourceID != ins.SourceID {
+
+			return false
+		}
+		if meta.PredicateID != ins.PredicateID {
+
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// GetASTType returns the meta type of this AST Node
+func (meta *QuantifierMeta) GetASTType() NodeType {
+
+	return TypeQuantifier
+}
+
+// WriteMetaTo write basic AST Node information meta data into writer.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *QuantifierMeta) WriteMetaTo(writer io.Writer) error {
+	err := meta.NodeMeta.WriteMetaTo(writer)
+	if err != nil {
+
+		return err
+	}
+	err = WriteBoolToWriter(writer, meta.ForAll)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.Name)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.SourceID)
+	if err != nil {
+
+		return err
+	}
+
+	return WriteStringToWriter(writer, meta.PredicateID)
+}
+
+// ReadMetaFrom write basic AST Node information meta data from reader.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *QuantifierMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.NodeMeta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.ForAll, err = ReadBoolFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.Name, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.SourceID, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.PredicateID, err = ReadStringFromReader(reader)
+
+	return err
+}
+
+// SwitchStatementMeta meta data for a SwitchStatement node, its cases are laid out in parallel slices.
+// A default case has no value id, a case with an empty body has an empty then id.
+type SwitchStatementMeta struct {
+	NodeMeta
+	SubjectID    string
+	CaseGrlTexts []string
+	CaseValueIDs [][]string
+	CaseThenIDs  []string
+}
+
+// Equals basic function to test equality of two MetaNode
+func (meta *SwitchStatementMeta) Equals(that Meta) bool {
+	if ins, ok := that.(*SwitchStatementMeta); ok {
+		if !meta.NodeMeta.Equals(that) {
+
+			return false
+		}
+		if meta.SubjectID != ins.SubjectID {
+
+			return false
+		}
+		if len(meta.CaseGrlTexts) != len(ins.CaseGrlTexts) {
+
+			return false
+		}
+		for k, v := range meta.CaseGrlTexts {
+			if ins.CaseGrlTexts[k] != v || ins.CaseThenIDs[k] != meta.CaseThenIDs[k] {
+
+				return false
+			}
+			if len(ins.CaseValueIDs[k]) != len(meta.CaseValueIDs[k]) {
+
+				return false
+			}
+			for i, id := range meta.CaseValueIDs[k] {
+				if ins.CaseValueIDs[k][i] != id {
+
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// GetASTType returns the meta type of this AST Node
+func (meta *SwitchStatementMeta) GetASTType() NodeType {
+
+	return TypeSwitchStatement
+}
+
+// WriteMetaTo write basic AST Node information meta data into writer.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *SwitchStatementMeta) WriteMetaTo(writer io.Writer) error {
+	err := meta.NodeMeta.WriteMetaTo(writer)
+	if err != nil {
+
+		return err
+	}
+	err = WriteStringToWriter(writer, meta.SubjectID)
+	if err != nil {
+
+		return err
+	}
+
+	// Write the number of cases, then each case with the number of its values
+	err = WriteIntToWriter(writer, uint64(len(meta.CaseGrlTexts)))
+	if err != nil {
+
+		return err
+	}
+	for i, grlText := range meta.CaseGrlTexts {
+		err = WriteStringToWriter(writer, grlText)
+		if err != nil {
+
+			return err
+		}
+		err = WriteIntToWriter(writer, uint64(len(meta.CaseValueIDs[i])))
+		if err != nil {
+
+			return err
+		}
+		for _, id := range meta.CaseValueIDs[i] {
+			err = WriteStringToWriter(writer, id)
+			if err != nil {
+
+				return err
+			}
+		}
+		err = WriteStringToWriter(writer, meta.CaseThenIDs[i])
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadMetaFrom write basic AST Node information meta data from reader.
+// One should not use this function directly, unless for testing
+// serialization of single ASTNode.
+func (meta *SwitchStatementMeta) ReadMetaFrom(reader io.Reader) error {
+	err := meta.NodeMeta.ReadMetaFrom(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.SubjectID, err = ReadStringFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+
+	count, err := ReadIntFromReader(reader)
+	if err != nil {
+
+		return err
+	}
+	meta.CaseGrlTexts = make([]string, count)
+	meta.CaseValueIDs = make([][]string, count)
+	meta.CaseThenIDs = make([]string, count)
+	for index := uint64(0); index < count; index++ {
+		meta.CaseGrlTexts[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+		values, err := ReadIntFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+		meta.CaseValueIDs[index] = make([]string, values)
+		for i := uint64(0); i < values; i++ {
+			meta.CaseValueIDs[index][i], err = ReadStringFromReader(reader)
+			if err != nil {
+
+				return err
+			}
+		}
+		meta.CaseThenIDs[index], err = ReadStringFromReader(reader)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
 var (
 	// TotalRead counter to track total byte read
 	TotalRead = uint64(0)
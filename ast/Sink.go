@@ -0,0 +1,38 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+// NewSink create new Sink AST object
+func NewSink() *Sink {
+
+	return &Sink{
+		Names: make([]string, 0),
+	}
+}
+
+// Sink is a simple AST object that stores the names of the sinks a rule routes its emitted messages to
+type Sink struct {
+	Names []string
+}
+
+// SinkReceiver must be implemented by any AST object that stores rule sinks
+type SinkReceiver interface {
+	AcceptSink(sink *Sink) error
+}
+
+// AcceptStringLiteral accept the assigned string
+func (s *Sink) AcceptStringLiteral(lit *StringLiteral) {
+	s.Names = append(s.Names, lit.String)
+}
@@ -0,0 +1,299 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// NewSwitchStatement creates new SwitchStatement instance
+func NewSwitchStatement() *SwitchStatement {
+
+	return &SwitchStatement{
+		AstID: unique.NewID(),
+		Cases: make([]*SwitchCase, 0),
+	}
+}
+
+// SwitchStatement AST graph node of `switch Customer.Tier { case "gold", "platinum" { ... } default { ... } }`.
+// The subject is evaluated once and the then expressions of the first case having a value equal to it are executed,
+// there is no fall through. Without a matching case, the default case is executed if there is one.
+type SwitchStatement struct {
+	AstID   string
+	GrlText string
+
+	Subject *Expression
+	Cases   []*SwitchCase
+}
+
+// SwitchCase is a single case of a SwitchStatement. It is part of its SwitchStatement and not a node on its own.
+type SwitchCase struct {
+	GrlText string
+
+	// Default is true for the `default` case, which has no value and always matches.
+	Default bool
+	Values  []*Expression
+	// Then is nil for a case with an empty body.
+	Then *ThenExpressionList
+}
+
+// SwitchStatementReceiver must be implemented by all other ast graph that uses a switch statement
+type SwitchStatementReceiver interface {
+	AcceptSwitchStatement(switchStmt *SwitchStatement) error
+}
+
+// SwitchCaseReceiver must be implemented by all other ast graph that uses a switch case
+type SwitchCaseReceiver interface {
+	AcceptSwitchCase(switchCase *SwitchCase) error
+}
+
+// AcceptExpression will accept the subject Expression into this SwitchStatement
+func (e *SwitchStatement) AcceptExpression(exp *Expression) error {
+	if e.Subject != nil {
+
+		return errors.New("subject for switch already assigned")
+	}
+	e.Subject = exp
+
+	return nil
+}
+
+// AcceptSwitchCase will accept a SwitchCase into this SwitchStatement
+func (e *SwitchStatement) AcceptSwitchCase(switchCase *SwitchCase) error {
+	if len(e.Cases) > 0 && e.Cases[len(e.Cases)-1].Default {
+
+		return fmt.Errorf("switch case %s is never reached, it follows the default case", switchCase.GrlText)
+	}
+	e.Cases = append(e.Cases, switchCase)
+
+	return nil
+}
+
+// AcceptExpression will accept a value Expression into this SwitchCase
+func (e *SwitchCase) AcceptExpression(exp *Expression) error {
+	if e.Default {
+
+		return errors.New("the default case of a switch has no value")
+	}
+	e.Values = append(e.Values, exp)
+
+	return nil
+}
+
+// AcceptThenExpressionList will accept the then expressions executed by this SwitchCase
+func (e *SwitchCase) AcceptThenExpressionList(list *ThenExpressionList) error {
+	if e.Then != nil {
+
+		return errors.New("then expressions for switch case already assigned")
+	}
+	e.Then = list
+
+	return nil
+}
+
+// ThenExpressions returns the then expressions of every case, those of the switch statements nested in them
+// included, in the order they are written.
+func (e *SwitchStatement) ThenExpressions() []*ThenExpression {
+	thenExprs := make([]*ThenExpression, 0)
+	for _, switchCase := range e.Cases {
+		if switchCase.Then != nil {
+			thenExprs = appendThenExpressions(thenExprs, switchCase.Then.ThenExpressions)
+		}
+	}
+
+	return thenExprs
+}
+
+// appendThenExpressions appends the then expressions, each followed by the then expressions of its switch statement.
+func appendThenExpressions(thenExprs []*ThenExpression, list []*ThenExpression) []*ThenExpression {
+	for _, thenExpr := range list {
+		thenExprs = append(thenExprs, thenExpr)
+		if thenExpr != nil && thenExpr.Switch != nil {
+			thenExprs = append(thenExprs, thenExpr.Switch.ThenExpressions()...)
+		}
+	}
+
+	return thenExprs
+}
+
+// MakeCatalog will create a catalog entry from SwitchStatement node.
+func (e *SwitchStatement) MakeCatalog(cat *Catalog) {
+	meta := &SwitchStatementMeta{
+		NodeMeta: NodeMeta{
+			AstID:    e.AstID,
+			GrlText:  e.GrlText,
+			Snapshot: e.GetSnapshot(),
+		},
+	}
+	if cat.AddMeta(e.AstID, meta) {
+		if e.Subject != nil {
+			meta.SubjectID = e.Subject.AstID
+			e.Subject.MakeCatalog(cat)
+		}
+		meta.CaseGrlTexts = make([]string, len(e.Cases))
+		meta.CaseValueIDs = make([][]string, len(e.Cases))
+		meta.CaseThenIDs = make([]string, len(e.Cases))
+		for i, switchCase := range e.Cases {
+			meta.CaseGrlTexts[i] = switchCase.GrlText
+			meta.CaseValueIDs[i] = make([]string, len(switchCase.Values))
+			for j, value := range switchCase.Values {
+				meta.CaseValueIDs[i][j] = value.AstID
+				value.MakeCatalog(cat)
+			}
+			if switchCase.Then != nil {
+				meta.CaseThenIDs[i] = switchCase.Then.AstID
+				switchCase.Then.MakeCatalog(cat)
+			}
+		}
+	}
+}
+
+// Clone will clone this SwitchStatement. The new clone will have an identical structure
+func (e *SwitchStatement) Clone(cloneTable *pkg.CloneTable) *SwitchStatement {
+	clone := &SwitchStatement{
+		AstID:   unique.NewID(),
+		GrlText: e.GrlText,
+		Subject: cloneMatchExpression(cloneTable, e.Subject),
+		Cases:   make([]*SwitchCase, len(e.Cases)),
+	}
+	for i, switchCase := range e.Cases {
+		cloned := &SwitchCase{
+			GrlText: switchCase.GrlText,
+			Default: switchCase.Default,
+			Values:  make([]*Expression, len(switchCase.Values)),
+		}
+		for j, value := range switchCase.Values {
+			cloned.Values[j] = cloneMatchExpression(cloneTable, value)
+		}
+		if switchCase.Then != nil {
+			if cloneTable.IsCloned(switchCase.Then.AstID) {
+				cloned.Then = cloneTable.Records[switchCase.Then.AstID].CloneInstance.(*ThenExpressionList)
+			} else {
+				cloned.Then = switchCase.Then.Clone(cloneTable)
+				cloneTable.MarkCloned(switchCase.Then.AstID, cloned.Then.AstID, switchCase.Then, cloned.Then)
+			}
+		}
+		clone.Cases[i] = cloned
+	}
+
+	return clone
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *SwitchStatement) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax related to this graph when it wast constructed
+func (e *SwitchStatement) GetGrlText() string {
+
+	return e.GrlText
+}
+
+// GetSnapshot will create a structure signature or AST graph
+func (e *SwitchStatement) GetSnapshot() string {
+	var buff strings.Builder
+	buff.WriteString(SWITCHSTATEMENT)
+	buff.WriteString("(")
+	if e.Subject != nil {
+		buff.WriteString(e.Subject.GetSnapshot())
+	}
+	for _, switchCase := range e.Cases {
+		buff.WriteString("SC(")
+		if switchCase.Default {
+			buff.WriteString("_")
+		}
+		for i, value := range switchCase.Values {
+			if i > 0 {
+				buff.WriteString(",")
+			}
+			buff.WriteString(value.GetSnapshot())
+		}
+		buff.WriteString("=>")
+		if switchCase.Then != nil {
+			buff.WriteString(switchCase.Then.GetSnapshot())
+		}
+		buff.WriteString(")")
+	}
+	buff.WriteString(")")
+
+	return buff.String()
+}
+
+// SetGrlText set the expression syntax related to this graph when it was constructed. Only ANTLR4 listener should
+// call this function.
+func (e *SwitchStatement) SetGrlText(grlText string) {
+	e.GrlText = grlText
+}
+
+// Execute evaluates the subject, then the values of the cases in order until one is equal to it, and executes the
+// then expressions of that case only. Nothing is executed when no case matches and there is no default case.
+func (e *SwitchStatement) Execute(dataContext IDataContext, memory *WorkingMemory) error {
+	subject, err := e.Subject.Evaluate(dataContext, memory)
+	if err != nil {
+
+		return fmt.Errorf("switch subject error. got %w", err)
+	}
+	for _, switchCase := range e.Cases {
+		matched, err := switchCase.matches(subject, dataContext, memory)
+		if err != nil {
+
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if switchCase.Then == nil {
+
+			return nil
+		}
+
+		return switchCase.Then.Execute(dataContext, memory)
+	}
+
+	return nil
+}
+
+func (e *SwitchCase) matches(subject reflect.Value, dataContext IDataContext, memory *WorkingMemory) (bool, error) {
+	if e.Default {
+
+		return true, nil
+	}
+	for _, value := range e.Values {
+		val, err := value.Evaluate(dataContext, memory)
+		if err != nil {
+
+			return false, fmt.Errorf("switch case %s error. got %w", e.GrlText, err)
+		}
+		equal, err := pkg.EvaluateEqual(subject, val)
+		if err != nil {
+
+			return false, fmt.Errorf("switch case %s error. got %w", e.GrlText, err)
+		}
+		if equal.Kind() == reflect.Bool && equal.Bool() {
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,132 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// TestFiredFunction is the name of the function available in the expect clause of a test block,
+// Fired("RuleName") tells whether the rule has fired during the test.
+const TestFiredFunction = "Fired"
+
+// NewTestEntry create new instance of TestEntry
+func NewTestEntry(name, version string) *TestEntry {
+
+	return &TestEntry{
+		AstID:         unique.NewID(),
+		TestName:      "No Name",
+		WorkingMemory: NewWorkingMemory(name, version),
+	}
+}
+
+// TestEntry AST graph node of a test block. Test blocks are never executed as rules, they are run by the test runner
+// which prepares the facts using the Given list, executes the rules and then evaluates the Expect expression.
+type TestEntry struct {
+	AstID   string
+	GrlText string
+
+	TestName string
+	Given    *ThenExpressionList
+	Expect   *Expression
+
+	// WorkingMemory holds the expressions of this test, so they are not part of the rules working memory.
+	WorkingMemory *WorkingMemory
+}
+
+// TestEntryReceiver should be implemented by any rule AST object that receive a TestEntry
+type TestEntryReceiver interface {
+	ReceiveTestEntry(entry *TestEntry) error
+}
+
+// AcceptStringLiteral will accept the test name
+func (e *TestEntry) AcceptStringLiteral(name *StringLiteral) {
+	e.TestName = name.String
+}
+
+// AcceptThenExpressionList will accept the given list of this test
+func (e *TestEntry) AcceptThenExpressionList(list *ThenExpressionList) error {
+	if e.Given != nil {
+
+		return fmt.Errorf("given list for test %s already assigned", e.TestName)
+	}
+	e.Given = list
+
+	return nil
+}
+
+// AcceptExpression will accept the expect expression of this test
+func (e *TestEntry) AcceptExpression(exp *Expression) error {
+	if e.Expect != nil {
+
+		return fmt.Errorf("expect expression for test %s already assigned", e.TestName)
+	}
+	e.Expect = exp
+
+	return nil
+}
+
+// Clone will clone this TestEntry along with its working memory, so a run of the test does not reset the expressions
+// of the blueprint another run is evaluating.
+func (e *TestEntry) Clone(cloneTable *pkg.CloneTable) (*TestEntry, error) {
+	clone := &TestEntry{
+		AstID:    unique.NewID(),
+		GrlText:  e.GrlText,
+		TestName: e.TestName,
+	}
+	if e.Given != nil {
+		if cloneTable.IsCloned(e.Given.AstID) {
+			clone.Given = cloneTable.Records[e.Given.AstID].CloneInstance.(*ThenExpressionList)
+		} else {
+			cloned := e.Given.Clone(cloneTable)
+			clone.Given = cloned
+			cloneTable.MarkCloned(e.Given.AstID, cloned.AstID, e.Given, cloned)
+		}
+	}
+	if e.Expect != nil {
+		if cloneTable.IsCloned(e.Expect.AstID) {
+			clone.Expect = cloneTable.Records[e.Expect.AstID].CloneInstance.(*Expression)
+		} else {
+			cloned := e.Expect.Clone(cloneTable)
+			clone.Expect = cloned
+			cloneTable.MarkCloned(e.Expect.AstID, cloned.AstID, e.Expect, cloned)
+		}
+	}
+	if e.WorkingMemory != nil {
+		wm, err := e.WorkingMemory.Clone(cloneTable)
+		if err != nil {
+
+			return nil, err
+		}
+		clone.WorkingMemory = wm
+	}
+
+	return clone, nil
+}
+
+// GetAstID get the UUID asigned for this AST graph node
+func (e *TestEntry) GetAstID() string {
+
+	return e.AstID
+}
+
+// GetGrlText get the expression syntax
+func (e *TestEntry) GetGrlText() string {
+
+	return e.GrlText
+}
@@ -34,6 +34,8 @@ type ThenExpression struct {
 	GrlText string
 
 	Assignment     *Assignment
+	Collect        *CollectStatement
+	Switch         *SwitchStatement
 	ExpressionAtom *ExpressionAtom
 }
 
@@ -51,6 +53,14 @@ func (e *ThenExpression) MakeCatalog(cat *Catalog) {
 			meta.AssignmentID = e.Assignment.AstID
 			e.Assignment.MakeCatalog(cat)
 		}
+		if e.Collect != nil {
+			meta.CollectStatementID = e.Collect.AstID
+			e.Collect.MakeCatalog(cat)
+		}
+		if e.Switch != nil {
+			meta.SwitchStatementID = e.Switch.AstID
+			e.Switch.MakeCatalog(cat)
+		}
 		if e.ExpressionAtom != nil {
 			meta.ExpressionAtomID = e.ExpressionAtom.AstID
 			e.ExpressionAtom.MakeCatalog(cat)
@@ -80,6 +90,26 @@ func (e *ThenExpression) Clone(cloneTable *pkg.CloneTable) *ThenExpression {
 		}
 	}
 
+	if e.Collect != nil {
+		if cloneTable.IsCloned(e.Collect.AstID) {
+			clone.Collect = cloneTable.Records[e.Collect.AstID].CloneInstance.(*CollectStatement)
+		} else {
+			cloned := e.Collect.Clone(cloneTable)
+			clone.Collect = cloned
+			cloneTable.MarkCloned(e.Collect.AstID, cloned.AstID, e.Collect, cloned)
+		}
+	}
+
+	if e.Switch != nil {
+		if cloneTable.IsCloned(e.Switch.AstID) {
+			clone.Switch = cloneTable.Records[e.Switch.AstID].CloneInstance.(*SwitchStatement)
+		} else {
+			cloned := e.Switch.Clone(cloneTable)
+			clone.Switch = cloned
+			cloneTable.MarkCloned(e.Switch.AstID, cloned.AstID, e.Switch, cloned)
+		}
+	}
+
 	if e.ExpressionAtom != nil {
 		if cloneTable.IsCloned(e.ExpressionAtom.AstID) {
 			clone.ExpressionAtom = cloneTable.Records[e.ExpressionAtom.AstID].CloneInstance.(*ExpressionAtom)
@@ -100,6 +130,20 @@ func (e *ThenExpression) AcceptAssignment(assignment *Assignment) error {
 	return nil
 }
 
+// AcceptCollectStatement will accept a CollectStatement AST graph into this Then ast graph
+func (e *ThenExpression) AcceptCollectStatement(collect *CollectStatement) error {
+	e.Collect = collect
+
+	return nil
+}
+
+// AcceptSwitchStatement will accept a SwitchStatement AST graph into this Then ast graph
+func (e *ThenExpression) AcceptSwitchStatement(switchStmt *SwitchStatement) error {
+	e.Switch = switchStmt
+
+	return nil
+}
+
 // AcceptExpressionAtom will accept an AcceptExpressionAtom AST graph into this ast graph
 func (e *ThenExpression) AcceptExpressionAtom(exp *ExpressionAtom) error {
 	e.ExpressionAtom = exp
@@ -127,6 +171,12 @@ func (e *ThenExpression) GetSnapshot() string {
 	if e.Assignment != nil {
 		buff.WriteString(e.Assignment.GetSnapshot())
 	}
+	if e.Collect != nil {
+		buff.WriteString(e.Collect.GetSnapshot())
+	}
+	if e.Switch != nil {
+		buff.WriteString(e.Switch.GetSnapshot())
+	}
 	if e.ExpressionAtom != nil {
 		buff.WriteString(e.ExpressionAtom.GetSnapshot())
 	}
@@ -146,9 +196,29 @@ func (e *ThenExpression) Execute(dataContext IDataContext, memory *WorkingMemory
 	if e.Assignment != nil {
 		err := e.Assignment.Execute(dataContext, memory)
 		if err != nil {
-			AstLog.Errorf("error while executing assignment %s. got %s", e.Assignment.GrlText, err.Error())
+			memory.log().Errorf("error while executing assignment %s. got %s", e.Assignment.GrlText, err.Error())
+		} else {
+			memory.log().Debugf("success executing assignment %s", e.Assignment.GrlText)
+		}
+
+		return err
+	}
+	if e.Collect != nil {
+		err := e.Collect.Execute(dataContext, memory)
+		if err != nil {
+			memory.log().Errorf("error while executing collect %s. got %s", e.Collect.GrlText, err.Error())
+		} else {
+			memory.log().Debugf("success executing collect %s", e.Collect.GrlText)
+		}
+
+		return err
+	}
+	if e.Switch != nil {
+		err := e.Switch.Execute(dataContext, memory)
+		if err != nil {
+			memory.log().Errorf("error while executing switch %s. got %s", e.Switch.GrlText, err.Error())
 		} else {
-			AstLog.Debugf("success executing assignment %s", e.Assignment.GrlText)
+			memory.log().Debugf("success executing switch %s", e.Switch.GrlText)
 		}
 
 		return err
@@ -156,11 +226,11 @@ func (e *ThenExpression) Execute(dataContext IDataContext, memory *WorkingMemory
 	if e.ExpressionAtom != nil {
 		_, err := e.ExpressionAtom.Evaluate(dataContext, memory)
 		if err != nil {
-			AstLog.Errorf("error while executing expression %s. got %s", e.ExpressionAtom.GrlText, err.Error())
+			memory.log().Errorf("error while executing expression %s. got %s", e.ExpressionAtom.GrlText, err.Error())
 
 			return err
 		}
-		AstLog.Debugf("success executing ExpressionAtom %s", e.ExpressionAtom.GrlText)
+		memory.log().Debugf("success executing ExpressionAtom %s", e.ExpressionAtom.GrlText)
 
 		return nil
 	}
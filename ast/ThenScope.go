@@ -15,6 +15,8 @@
 package ast
 
 import (
+	"context"
+	"fmt"
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
 	"strings"
@@ -34,6 +36,8 @@ type ThenScope struct {
 	GrlText string
 
 	ThenExpressionList *ThenExpressionList
+	// Script replaces the expression list when the then scope is written in a script language.
+	Script *ScriptBlock
 }
 
 // MakeCatalog create a catalog entry for this AST Node
@@ -50,6 +54,10 @@ func (e *ThenScope) MakeCatalog(cat *Catalog) {
 			meta.ThenExpressionListID = e.ThenExpressionList.AstID
 			e.ThenExpressionList.MakeCatalog(cat)
 		}
+		if e.Script != nil {
+			meta.ScriptLanguage = e.Script.Language
+			meta.ScriptSource = e.Script.Source
+		}
 	}
 }
 
@@ -63,6 +71,8 @@ func (e *ThenScope) Clone(cloneTable *pkg.CloneTable) *ThenScope {
 	clone := &ThenScope{
 		AstID:   unique.NewID(),
 		GrlText: e.GrlText,
+		// the compiled script is immutable, the clone can share it.
+		Script: e.Script,
 	}
 
 	if e.ThenExpressionList != nil {
@@ -85,6 +95,13 @@ func (e *ThenScope) AcceptThenExpressionList(list *ThenExpressionList) error {
 	return nil
 }
 
+// AcceptScriptBlock will accept the script written in place of the ThenExpressionList
+func (e *ThenScope) AcceptScriptBlock(script *ScriptBlock) error {
+	e.Script = script
+
+	return nil
+}
+
 // GetAstID get the UUID asigned for this AST graph node
 func (e *ThenScope) GetAstID() string {
 
@@ -105,6 +122,9 @@ func (e *ThenScope) GetSnapshot() string {
 	if e.ThenExpressionList != nil {
 		buff.WriteString(e.ThenExpressionList.GetSnapshot())
 	}
+	if e.Script != nil {
+		buff.WriteString(fmt.Sprintf("%s:%q", e.Script.Language, e.Script.Source))
+	}
 	buff.WriteString(")")
 
 	return buff.String()
@@ -118,8 +138,18 @@ func (e *ThenScope) SetGrlText(grlText string) {
 
 // Execute will execute this graph in the Then scope
 func (e *ThenScope) Execute(dataContext IDataContext, memory *WorkingMemory) error {
+
+	return e.ExecuteWithContext(context.Background(), dataContext, memory)
+}
+
+// ExecuteWithContext will execute this graph in the Then scope, a script stops once the context is done.
+func (e *ThenScope) ExecuteWithContext(ctx context.Context, dataContext IDataContext, memory *WorkingMemory) error {
+	if e.Script != nil {
+
+		return e.Script.Execute(ctx, dataContext, memory)
+	}
 	if e.ThenExpressionList == nil {
-		AstLog.Warnf("Can not execute nil expression list")
+		memory.log().Warnf("Can not execute nil expression list")
 	}
 
 	return e.ThenExpressionList.Execute(dataContext, memory)
@@ -15,6 +15,7 @@
 package ast
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
 	"github.com/hyperjumptech/grule-rule-engine/model"
@@ -24,6 +25,40 @@ import (
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
 )
 
+// ErrMissingFact is wrapped by MissingFactError, use errors.Is to detect rules referencing facts that are not in the data context.
+var ErrMissingFact = errors.New("missing fact")
+
+// MissingFactError is returned when a variable refers to a fact that has not been added to the data context.
+type MissingFactError struct {
+	Name string
+}
+
+// Error returns the error message.
+func (e *MissingFactError) Error() string {
+
+	return fmt.Sprintf("non existent key %s", e.Name)
+}
+
+// Unwrap returns ErrMissingFact.
+func (e *MissingFactError) Unwrap() error {
+
+	return ErrMissingFact
+}
+
+// QuantityAssignmentError is returned when a rule assigns a quantity, such as 100 USD, to a field that can not
+// hold it. Only a percentage may be assigned to a number, as the fraction it is of 100.
+type QuantityAssignmentError struct {
+	Rule  string
+	Field string
+	Unit  string
+}
+
+// Error returns the error message.
+func (e *QuantityAssignmentError) Error() string {
+
+	return fmt.Sprintf("rule %s can not assign a quantity in %s to %s, which is not a pkg.Quantity", e.Rule, e.Unit, e.Field)
+}
+
 // NewVariable create new instance of Variable
 func NewVariable() *Variable {
 
@@ -177,6 +212,13 @@ func (e *Variable) Assign(newVal reflect.Value, dataContext IDataContext, memory
 		if err != nil {
 			return err
 		}
+		if current, fieldErr := e.Variable.ValueNode.GetObjectValueByField(e.Name); fieldErr == nil && current.IsValid() {
+			newVal, err = e.quantityInto(newVal, current.Type(), dataContext, memory)
+			if err != nil {
+
+				return err
+			}
+		}
 		err = e.Variable.ValueNode.SetObjectValueByField(e.Name, newVal)
 		if err == nil {
 			dataContext.IncrementVariableChangeCount()
@@ -196,6 +238,13 @@ func (e *Variable) Assign(newVal reflect.Value, dataContext IDataContext, memory
 
 			return err
 		}
+		if container := pkg.GetValueElem(e.Variable.ValueNode.Value()); container.Kind() == reflect.Array || container.Kind() == reflect.Slice || container.Kind() == reflect.Map {
+			newVal, err = e.quantityInto(newVal, container.Type().Elem(), dataContext, memory)
+			if err != nil {
+
+				return err
+			}
+		}
 		if e.Variable.ValueNode.IsArray() {
 			err := e.Variable.ValueNode.SetArrayValueAt(int(e.ArrayMapSelector.Value.Int()), newVal)
 			if err == nil {
@@ -217,13 +266,37 @@ func (e *Variable) Assign(newVal reflect.Value, dataContext IDataContext, memory
 	return fmt.Errorf("this code part should not be reached")
 }
 
+// quantityInto returns the value to assign to a target of the specified type. A quantity assigned to a number is
+// converted if it is a percentage, any other quantity assigned to a target that is not a Quantity is an error.
+func (e *Variable) quantityInto(newVal reflect.Value, target reflect.Type, dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
+	if !pkg.IsQuantity(newVal) || target.Kind() == reflect.Interface || newVal.Type().AssignableTo(target) {
+
+		return newVal, nil
+	}
+	quantity := newVal.Interface().(pkg.Quantity)
+	var units *pkg.UnitRegistry
+	if memory != nil {
+		units = memory.Units
+	}
+	if unit, ok := units.Lookup(quantity.Unit); ok && unit.Dimension == pkg.DimensionPercent && pkg.IsNumber(reflect.Zero(target)) {
+
+		return reflect.ValueOf(quantity.Value / 100), nil
+	}
+	rule := ""
+	if entry := dataContext.GetRuleEntry(); entry != nil {
+		rule = entry.RuleName
+	}
+
+	return reflect.Value{}, &QuantityAssignmentError{Rule: rule, Field: e.GrlText, Unit: quantity.Unit}
+}
+
 // Evaluate will evaluate this AST graph for when scope evaluation
 func (e *Variable) Evaluate(dataContext IDataContext, memory *WorkingMemory) (reflect.Value, error) {
 	if len(e.Name) > 0 && e.Variable == nil {
 		valueNode := dataContext.Get(e.Name)
 		if valueNode == nil {
 
-			return reflect.ValueOf(nil), fmt.Errorf("non existent key %s", e.Name)
+			return reflect.ValueOf(nil), &MissingFactError{Name: e.Name}
 		}
 		e.ValueNode = valueNode
 		e.Value = valueNode.Value()
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperjumptech/grule-rule-engine/ast/unique"
 	"github.com/hyperjumptech/grule-rule-engine/logger"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -48,6 +49,53 @@ type WorkingMemory struct {
 	expressionVariableMap     map[*Variable][]*Expression
 	expressionAtomVariableMap map[*Variable][]*ExpressionAtom
 	ID                        string
+
+	// StringNumberComparison selects how the when and then scopes compare a string to a number.
+	StringNumberComparison pkg.StringNumberComparison
+
+	// NilSafeChaining makes a call, or a field, applied to a nil in a chain such as Fact.Account().Owner().Name
+	// the zero value of what it would be, instead of an error.
+	NilSafeChaining bool
+
+	// Tolerance is the tolerance of the ~== comparisons that have no within, DefaultTolerance if zero.
+	Tolerance float64
+
+	// Units are the units the quantities are computed with, those of pkg.RegisterUnit if nil. The knowledge bases
+	// have the units of their library, see KnowledgeLibrary.Units.
+	Units *pkg.UnitRegistry
+
+	// SinglePass keeps no evaluation between two reads, so the assignments have nothing to invalidate. The
+	// engine sets it when the when scopes are only evaluated once, see GruleEngine.SinglePass.
+	SinglePass bool
+
+	// Logger is the logger of the evaluation and the execution of the rules, AstLog if it wraps no logger.
+	Logger logger.LogEntry
+}
+
+// log returns the logger of this working memory, AstLog for a nil working memory.
+func (workingMem *WorkingMemory) log() logger.LogEntry {
+	if workingMem == nil {
+
+		return AstLog
+	}
+
+	return workingMem.Logger.Or(AstLog)
+}
+
+// computesQuantity tells whether the operator applies to a quantity and is computed with the units of this working
+// memory, the operators of pkg using the units of pkg.RegisterUnit.
+func (workingMem *WorkingMemory) computesQuantity(operator int, left, right reflect.Value) bool {
+	if workingMem == nil || workingMem.Units == nil || operator > OpNEq {
+
+		return false
+	}
+	left, right = pkg.GetValueElem(left), pkg.GetValueElem(right)
+	if pkg.IsCollection(left) || pkg.IsCollection(right) {
+
+		return false
+	}
+
+	return pkg.IsQuantity(left) || pkg.IsQuantity(right)
 }
 
 // MakeCatalog create a catalog entry of this working memory
@@ -84,25 +132,25 @@ func (workingMem *WorkingMemory) MakeCatalog(cat *Catalog) {
 
 // DebugContent will shows the working memory mapping content
 func (workingMem *WorkingMemory) DebugContent() {
-	if AstLog.Level <= logger.DebugLevel {
+	if workingMem.log().Level <= logger.DebugLevel {
 		for varName, vari := range workingMem.variableSnapshotMap {
-			AstLog.Debugf("Variable %s : %s : %s", varName, vari.GrlText, vari.AstID)
+			workingMem.log().Debugf("Variable %s : %s : %s", varName, vari.GrlText, vari.AstID)
 
 			if exprs, ok := workingMem.expressionVariableMap[vari]; ok {
-				AstLog.Debugf("  %d expressions", len(exprs))
+				workingMem.log().Debugf("  %d expressions", len(exprs))
 				for i, ex := range exprs {
-					AstLog.Debugf("   expr %d: %s", i, ex.GrlText)
+					workingMem.log().Debugf("   expr %d: %s", i, ex.GrlText)
 				}
 			} else {
-				AstLog.Debugf("  no expressions mapped for variable %s", vari.GrlText)
+				workingMem.log().Debugf("  no expressions mapped for variable %s", vari.GrlText)
 			}
 			if expratms, ok := workingMem.expressionAtomVariableMap[vari]; ok {
-				AstLog.Debugf("  %d expression atoms", len(expratms))
+				workingMem.log().Debugf("  %d expression atoms", len(expratms))
 				for i, ex := range expratms {
-					AstLog.Debugf("   expr atm %d: %s", i, ex.GrlText)
+					workingMem.log().Debugf("   expr atm %d: %s", i, ex.GrlText)
 				}
 			} else {
-				AstLog.Debugf("  no expressions atom mapped for variable %s", vari.GrlText)
+				workingMem.log().Debugf("  no expressions atom mapped for variable %s", vari.GrlText)
 			}
 		}
 	}
@@ -144,11 +192,17 @@ func (workingMem *WorkingMemory) Equals(that *WorkingMemory) bool {
 
 // Clone will clone this WorkingMemory. The new clone will have an identical structure
 func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemory, error) {
-	AstLog.Debugf("Cloning working memory %s:%s", workingMem.Name, workingMem.Version)
+	workingMem.log().Debugf("Cloning working memory %s:%s", workingMem.Name, workingMem.Version)
 	clone := NewWorkingMemory(workingMem.Name, workingMem.Version)
+	clone.StringNumberComparison = workingMem.StringNumberComparison
+	clone.NilSafeChaining = workingMem.NilSafeChaining
+	clone.Tolerance = workingMem.Tolerance
+	clone.Units = workingMem.Units
+	clone.SinglePass = workingMem.SinglePass
+	clone.Logger = workingMem.Logger
 
 	if workingMem.expressionSnapshotMap != nil {
-		AstLog.Debugf("Cloning %d expressionSnapshotMap entries", len(workingMem.expressionSnapshotMap))
+		workingMem.log().Debugf("Cloning %d expressionSnapshotMap entries", len(workingMem.expressionSnapshotMap))
 		for k, expr := range workingMem.expressionSnapshotMap {
 			if cloneTable.IsCloned(expr.AstID) {
 				clone.expressionSnapshotMap[k] = cloneTable.Records[expr.AstID].CloneInstance.(*Expression)
@@ -160,7 +214,7 @@ func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemo
 	}
 
 	if workingMem.expressionAtomSnapshotMap != nil {
-		AstLog.Debugf("Cloning %d expressionAtomSnapshotMap entries", len(workingMem.expressionAtomSnapshotMap))
+		workingMem.log().Debugf("Cloning %d expressionAtomSnapshotMap entries", len(workingMem.expressionAtomSnapshotMap))
 		for k, exprAtm := range workingMem.expressionAtomSnapshotMap {
 			if cloneTable.IsCloned(exprAtm.AstID) {
 				clone.expressionAtomSnapshotMap[k] = cloneTable.Records[exprAtm.AstID].CloneInstance.(*ExpressionAtom)
@@ -172,7 +226,7 @@ func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemo
 	}
 
 	if workingMem.variableSnapshotMap != nil {
-		AstLog.Debugf("Cloning %d variableSnapshotMap entries", len(workingMem.variableSnapshotMap))
+		workingMem.log().Debugf("Cloning %d variableSnapshotMap entries", len(workingMem.variableSnapshotMap))
 		for key, variable := range workingMem.variableSnapshotMap {
 			if cloneTable.IsCloned(variable.AstID) {
 				clone.variableSnapshotMap[key] = cloneTable.Records[variable.AstID].CloneInstance.(*Variable)
@@ -184,7 +238,7 @@ func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemo
 	}
 
 	if workingMem.expressionVariableMap != nil {
-		AstLog.Debugf("Cloning %d expressionVariableMap entries", len(workingMem.expressionVariableMap))
+		workingMem.log().Debugf("Cloning %d expressionVariableMap entries", len(workingMem.expressionVariableMap))
 		for key, exprArr := range workingMem.expressionVariableMap {
 			if cloneTable.IsCloned(key.AstID) {
 				clonedVari := cloneTable.Records[key.AstID].CloneInstance.(*Variable)
@@ -205,7 +259,7 @@ func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemo
 	}
 
 	if workingMem.expressionAtomVariableMap != nil {
-		AstLog.Debugf("Cloning %d expressionAtomVariableMap entries", len(workingMem.expressionAtomVariableMap))
+		workingMem.log().Debugf("Cloning %d expressionAtomVariableMap entries", len(workingMem.expressionAtomVariableMap))
 		for key, exprAtmArr := range workingMem.expressionAtomVariableMap {
 			if cloneTable.IsCloned(key.AstID) {
 				clonedVari := cloneTable.Records[key.AstID].CloneInstance.(*Variable)
@@ -236,13 +290,13 @@ func (workingMem *WorkingMemory) Clone(cloneTable *pkg.CloneTable) (*WorkingMemo
 
 // IndexVariables will index all expression and expression atoms that contains a speciffic variable name
 func (workingMem *WorkingMemory) IndexVariables() {
-	if AstLog.Level <= logger.DebugLevel {
-		AstLog.Debugf("Indexing %d expressions, %d expression atoms and %d variables.", len(workingMem.expressionSnapshotMap), len(workingMem.expressionAtomSnapshotMap), len(workingMem.variableSnapshotMap))
+	if workingMem.log().Level <= logger.DebugLevel {
+		workingMem.log().Debugf("Indexing %d expressions, %d expression atoms and %d variables.", len(workingMem.expressionSnapshotMap), len(workingMem.expressionAtomSnapshotMap), len(workingMem.variableSnapshotMap))
 	}
 	start := time.Now()
 	defer func() {
 		dur := time.Since(start)
-		AstLog.Tracef("Working memory indexing takes %d ms", dur/time.Millisecond)
+		workingMem.log().Tracef("Working memory indexing takes %d ms", dur/time.Millisecond)
 	}()
 	workingMem.expressionVariableMap = make(map[*Variable][]*Expression)
 	workingMem.expressionAtomVariableMap = make(map[*Variable][]*ExpressionAtom)
@@ -276,11 +330,11 @@ func (workingMem *WorkingMemory) IndexVariables() {
 func (workingMem *WorkingMemory) AddExpression(exp *Expression) *Expression {
 	snapshot := exp.GetSnapshot()
 	if expr, ok := workingMem.expressionSnapshotMap[snapshot]; ok {
-		AstLog.Tracef("%s : Ignored Expression Snapshot : %s", workingMem.ID, snapshot)
+		workingMem.log().Tracef("%s : Ignored Expression Snapshot : %s", workingMem.ID, snapshot)
 
 		return expr
 	}
-	AstLog.Tracef("%s : Added Expression Snapshot : %s", workingMem.ID, snapshot)
+	workingMem.log().Tracef("%s : Added Expression Snapshot : %s", workingMem.ID, snapshot)
 	workingMem.expressionSnapshotMap[snapshot] = exp
 
 	return exp
@@ -291,11 +345,11 @@ func (workingMem *WorkingMemory) AddExpression(exp *Expression) *Expression {
 func (workingMem *WorkingMemory) AddExpressionAtom(exp *ExpressionAtom) *ExpressionAtom {
 	snapshot := exp.GetSnapshot()
 	if expr, ok := workingMem.expressionAtomSnapshotMap[snapshot]; ok {
-		AstLog.Tracef("%s : Ignored ExpressionAtom Snapshot : %s", workingMem.ID, snapshot)
+		workingMem.log().Tracef("%s : Ignored ExpressionAtom Snapshot : %s", workingMem.ID, snapshot)
 
 		return expr
 	}
-	AstLog.Tracef("%s : Added ExpressionAtom Snapshot : %s", workingMem.ID, snapshot)
+	workingMem.log().Tracef("%s : Added ExpressionAtom Snapshot : %s", workingMem.ID, snapshot)
 	workingMem.expressionAtomSnapshotMap[snapshot] = exp
 
 	return exp
@@ -306,11 +360,11 @@ func (workingMem *WorkingMemory) AddExpressionAtom(exp *ExpressionAtom) *Express
 func (workingMem *WorkingMemory) AddVariable(vari *Variable) *Variable {
 	snapshot := vari.GetSnapshot()
 	if v, ok := workingMem.variableSnapshotMap[snapshot]; ok {
-		AstLog.Tracef("%s : Ignored Variable Snapshot : %s", workingMem.ID, snapshot)
+		workingMem.log().Tracef("%s : Ignored Variable Snapshot : %s", workingMem.ID, snapshot)
 
 		return v
 	}
-	AstLog.Tracef("%s : Added Variable Snapshot : %s", workingMem.ID, snapshot)
+	workingMem.log().Tracef("%s : Added Variable Snapshot : %s", workingMem.ID, snapshot)
 	workingMem.variableSnapshotMap[snapshot] = vari
 
 	return vari
@@ -319,7 +373,11 @@ func (workingMem *WorkingMemory) AddVariable(vari *Variable) *Variable {
 // Reset will reset the evaluated status of a specific variable if its contains a variable name in its signature.
 // Returns true if any expression was reset, false if otherwise
 func (workingMem *WorkingMemory) Reset(name string) bool {
-	AstLog.Tracef("------- resetting  %s", name)
+	if !workingMem.caches() {
+
+		return false
+	}
+	workingMem.log().Tracef("------- resetting  %s", name)
 	for _, vari := range workingMem.variableSnapshotMap {
 		if vari.GrlText == name {
 
@@ -343,33 +401,43 @@ func (workingMem *WorkingMemory) Reset(name string) bool {
 // ResetVariable will reset the evaluated status of a specific expression if its contains a variable name in its signature.
 // Returns true if any expression was reset, false if otherwise
 func (workingMem *WorkingMemory) ResetVariable(variable *Variable) bool {
-	AstLog.Tracef("------- resetting variable %s : %s", variable.GrlText, variable.AstID)
-	if AstLog.Level == logger.TraceLevel {
-		AstLog.Tracef("%s : Resetting %s", workingMem.ID, variable.GetSnapshot())
+	if !workingMem.caches() {
+
+		return false
+	}
+	workingMem.log().Tracef("------- resetting variable %s : %s", variable.GrlText, variable.AstID)
+	if workingMem.log().Level == logger.TraceLevel {
+		workingMem.log().Tracef("%s : Resetting %s", workingMem.ID, variable.GetSnapshot())
 	}
 	reseted := false
 	if arr, ok := workingMem.expressionVariableMap[variable]; ok {
 		for _, expr := range arr {
-			AstLog.Tracef("------ reset expr : %s", expr.GrlText)
+			workingMem.log().Tracef("------ reset expr : %s", expr.GrlText)
 			expr.Evaluated = false
 			reseted = true
 		}
 	} else {
-		AstLog.Warnf("No expression to reset for variable %s", variable.GrlText)
+		workingMem.log().Warnf("No expression to reset for variable %s", variable.GrlText)
 	}
 	if arr, ok := workingMem.expressionAtomVariableMap[variable]; ok {
 		for _, expr := range arr {
-			AstLog.Tracef("------ reset expr atm : %s", expr.GrlText)
+			workingMem.log().Tracef("------ reset expr atm : %s", expr.GrlText)
 			expr.Evaluated = false
 			reseted = true
 		}
 	} else {
-		AstLog.Warnf("No expression atom to reset for variable %s", variable.GrlText)
+		workingMem.log().Warnf("No expression atom to reset for variable %s", variable.GrlText)
 	}
 
 	return reseted
 }
 
+// caches tells whether the expressions keep their evaluation until they are reset, they do not in single pass.
+func (workingMem *WorkingMemory) caches() bool {
+
+	return workingMem == nil || !workingMem.SinglePass
+}
+
 // ResetAll sets all expression evaluated status to false.
 // Returns true if any expression was reset, false if otherwise
 func (workingMem *WorkingMemory) ResetAll() bool {
@@ -20,6 +20,7 @@ import (
 
 // NewID will create a new unique ID string for this runtime.
 // Uniqueness between system or apps is not necessary.
+// It keeps no state, the engines and builders of one process share no counter through it.
 func NewID() string {
 	return uuid.NewString()
 }
@@ -0,0 +1,503 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// CycleDetection tells the RuleBuilder what to do with the rules that would fire until MaxCycle.
+type CycleDetection int
+
+const (
+	// CycleDetectionOff does not look for cycles.
+	CycleDetectionOff CycleDetection = iota
+	// CycleDetectionWarn logs every cycle found, the rules are still added.
+	CycleDetectionWarn
+	// CycleDetectionStrict rejects a resource whose rules certainly loop, the other cycles found are logged.
+	CycleDetectionStrict
+)
+
+// CycleSeverity tells how sure a CycleFinding is.
+type CycleSeverity int
+
+const (
+	// CycleWarning is a configuration that loops unless the facts happen to stop it.
+	CycleWarning CycleSeverity = iota
+	// CycleError is a configuration that loops whatever the facts are, once its rules fire.
+	CycleError
+)
+
+// String returns the severity name.
+func (severity CycleSeverity) String() string {
+	if severity == CycleError {
+
+		return "error"
+	}
+
+	return "warning"
+}
+
+// CycleFinding is a rule, or rules, that keep re-triggering each other.
+type CycleFinding struct {
+	Severity CycleSeverity
+	// Rules are the participating rules, sorted by name.
+	Rules  []string
+	Reason string
+}
+
+// Error returns the finding as a message.
+func (finding CycleFinding) Error() string {
+
+	return fmt.Sprintf("%s: rule %s %s", finding.Severity, strings.Join(finding.Rules, " and "), finding.Reason)
+}
+
+// ruleFlow is what a rule reads in its when scope and what its then scope changes.
+type ruleFlow struct {
+	entry  *ast.RuleEntry
+	reads  []string
+	writes []string
+	// constants are the constant values assigned with =, by target.
+	constants map[string]reflect.Value
+	// limited rules stop firing on their own: they retract themselves, have max-fires or a cooldown.
+	limited bool
+	// opaque rules may change anything: they call methods of the facts or run a script.
+	opaque bool
+	// volatile rules have a condition that may change without any write, such as one calling Now().
+	volatile bool
+	stops    bool
+	retracts []string
+}
+
+func newRuleFlow(entry *ast.RuleEntry) *ruleFlow {
+	flow := &ruleFlow{
+		entry:     entry,
+		constants: make(map[string]reflect.Value),
+		limited:   entry.MaxFires > 0 || entry.Cooldown > 0,
+	}
+	when := &sanitizerScan{}
+	if entry.WhenScope != nil {
+		when.scanExpression(entry.WhenScope.Expression)
+	}
+	flow.reads = when.variables
+	for _, call := range when.calls {
+		if call.ExpressionAtom == nil && call.FunctionCall.FunctionName == "Now" {
+			flow.volatile = true
+		}
+	}
+	if entry.ThenScope == nil || entry.ThenScope.Script != nil {
+		flow.opaque = true
+
+		return flow
+	}
+	// the else scope changes the facts too, but only the constants of the then scope tell whether the condition
+	// stays true after the rule fired because it held. The cases of a switch may not execute, their constants
+	// are not certain either.
+	then := &sanitizerScan{}
+	for _, scope := range []*ast.ThenScope{entry.ThenScope, entry.ElseScope} {
+		if scope == nil || scope.ThenExpressionList == nil {
+
+			continue
+		}
+		certain := len(scope.ThenExpressionList.ThenExpressions)
+		thenExprs := append([]*ast.ThenExpression{}, scope.ThenExpressionList.ThenExpressions...)
+		for _, thenExpr := range scope.ThenExpressionList.ThenExpressions {
+			if thenExpr.Switch != nil {
+				thenExprs = append(thenExprs, thenExpr.Switch.ThenExpressions()...)
+			}
+		}
+		for i, thenExpr := range thenExprs {
+			then.scanSwitch(thenExpr.Switch)
+			if assignment := thenExpr.Assignment; assignment != nil {
+				then.assignments = append(then.assignments, assignment.Variable.GrlText)
+				if constant := constantOf(assignment.Expression); scope == entry.ThenScope && i < certain && assignment.IsAssign && assignment.Match == nil && constant.IsValid() {
+					flow.constants[assignment.Variable.GrlText] = constant
+				}
+				then.scanExpression(assignment.Expression)
+				then.scanMatch(assignment.Match)
+			}
+			if collect := thenExpr.Collect; collect != nil {
+				then.assignments = append(then.assignments, collect.Name)
+				then.scanCollect(collect)
+			}
+			then.scanAtom(thenExpr.ExpressionAtom)
+		}
+	}
+	flow.writes = then.assignments
+	for _, call := range then.calls {
+		switch {
+		case call.ExpressionAtom != nil:
+			flow.opaque = true
+		case isRetractOf(call, entry.RuleName):
+			flow.limited = true
+		case call.FunctionCall.FunctionName == "Complete":
+			flow.stops = true
+		case call.FunctionCall.FunctionName == "Retract":
+			flow.retracts = append(flow.retracts, retractedRule(call))
+		}
+	}
+
+	return flow
+}
+
+// writesAny tells whether the rule assigns a variable the other rule reads.
+func (flow *ruleFlow) writesAny(reads []string) (string, bool) {
+	for _, target := range flow.writes {
+		for _, read := range reads {
+			if isSameOrMember(read, target) || isSameOrMember(target, read) {
+
+				return target, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// guarded tells whether one of the constants the rule assigns makes its own condition false. Only the top level
+// conjuncts of the when scope comparing an assigned variable with a constant are checked, e.g. `Fact.Done == false`
+// when the then scope assigns `Fact.Done = true`.
+func (flow *ruleFlow) guarded() bool {
+	if flow.entry.WhenScope == nil {
+
+		return false
+	}
+	for _, conjunct := range conjuncts(flow.entry.WhenScope.Expression, nil) {
+		if holds, known := flow.afterThen(conjunct); known && !holds {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// keepsTrue tells whether the conditions the rule writes into stay true after its then scope, in which case the
+// writes can not stop the rule.
+func (flow *ruleFlow) keepsTrue() bool {
+	if flow.entry.WhenScope == nil {
+
+		return false
+	}
+	for _, conjunct := range conjuncts(flow.entry.WhenScope.Expression, nil) {
+		scan := &sanitizerScan{}
+		scan.scanExpression(conjunct)
+		if _, written := flow.writesAny(scan.variables); !written {
+
+			continue
+		}
+		if holds, known := flow.afterThen(conjunct); !known || !holds {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// afterThen evaluates a conjunct comparing a variable with a constant, using the constant the then scope assigns
+// to that variable. known is false if the conjunct can not be evaluated so.
+func (flow *ruleFlow) afterThen(conjunct *ast.Expression) (holds, known bool) {
+	if atom := conjunct.ExpressionAtom; atom != nil {
+		// a boolean variable, negated as in `!Fact.Done`.
+		negated := conjunct.Negated
+		for atom.Negated && atom.ExpressionAtom != nil && atom.FunctionCall == nil {
+			negated = !negated
+			atom = atom.ExpressionAtom
+		}
+		if atom.Negated {
+			negated = !negated
+		}
+		if atom.Variable == nil {
+
+			return false, false
+		}
+		value, ok := flow.constants[atom.Variable.GrlText]
+		if !ok || value.Kind() != reflect.Bool {
+
+			return false, false
+		}
+
+		return value.Bool() != negated, true
+	}
+	if conjunct.LeftExpression == nil || conjunct.RightExpression == nil || conjunct.Operator < ast.OpGT || conjunct.Operator > ast.OpNEq {
+
+		return false, false
+	}
+	left, right := conjunct.LeftExpression.ExpressionAtom, conjunct.RightExpression.ExpressionAtom
+	if left == nil || right == nil {
+
+		return false, false
+	}
+	operator := conjunct.Operator
+	if left.Constant != nil && right.Variable != nil {
+		left, right = right, left
+		operator = mirrored(operator)
+	}
+	if left.Variable == nil || right.Constant == nil {
+
+		return false, false
+	}
+	value, ok := flow.constants[left.Variable.GrlText]
+	if !ok {
+
+		return false, false
+	}
+
+	return compareConstants(value, right.Constant.Value, operator)
+}
+
+// conjuncts returns the expressions ANDed at the top level of the expression.
+func conjuncts(expr *ast.Expression, into []*ast.Expression) []*ast.Expression {
+	if expr == nil {
+
+		return into
+	}
+	if expr.Operator == ast.OpAnd && expr.LeftExpression != nil && expr.RightExpression != nil {
+
+		return conjuncts(expr.RightExpression, conjuncts(expr.LeftExpression, into))
+	}
+	if expr.SingleExpression != nil && !expr.Negated {
+
+		return conjuncts(expr.SingleExpression, into)
+	}
+
+	return append(into, expr)
+}
+
+func constantOf(expr *ast.Expression) reflect.Value {
+	for expr != nil && expr.SingleExpression != nil && !expr.Negated {
+		expr = expr.SingleExpression
+	}
+	if expr == nil || expr.ExpressionAtom == nil || expr.ExpressionAtom.Constant == nil || expr.ExpressionAtom.Negated {
+
+		return reflect.Value{}
+	}
+
+	return expr.ExpressionAtom.Constant.Value
+}
+
+func mirrored(operator int) int {
+	switch operator {
+	case ast.OpGT:
+
+		return ast.OpLT
+	case ast.OpLT:
+
+		return ast.OpGT
+	case ast.OpGTE:
+
+		return ast.OpLTE
+	case ast.OpLTE:
+
+		return ast.OpGTE
+	}
+
+	return operator
+}
+
+// compareConstants compares two constants of the same kind, known is false if they are of different kinds.
+func compareConstants(left, right reflect.Value, operator int) (holds, known bool) {
+	if !left.IsValid() || !right.IsValid() {
+
+		return false, false
+	}
+	order := 0
+	switch {
+	case pkg.IsNumber(left) && pkg.IsNumber(right):
+		l, r := numberOf(left), numberOf(right)
+		if l < r {
+			order = -1
+		} else if l > r {
+			order = 1
+		}
+	case left.Kind() == reflect.String && right.Kind() == reflect.String:
+		order = strings.Compare(left.String(), right.String())
+	case left.Kind() == reflect.Bool && right.Kind() == reflect.Bool:
+		if operator != ast.OpEq && operator != ast.OpNEq {
+
+			return false, false
+		}
+		if left.Bool() != right.Bool() {
+			order = 1
+		}
+	default:
+
+		return false, false
+	}
+	switch operator {
+	case ast.OpGT:
+
+		return order > 0, true
+	case ast.OpLT:
+
+		return order < 0, true
+	case ast.OpGTE:
+
+		return order >= 0, true
+	case ast.OpLTE:
+
+		return order <= 0, true
+	case ast.OpEq:
+
+		return order == 0, true
+	}
+
+	return order != 0, true
+}
+
+func numberOf(value reflect.Value) float64 {
+	switch pkg.GetBaseKind(value) {
+	case reflect.Int64:
+
+		return float64(value.Int())
+	case reflect.Uint64:
+
+		return float64(value.Uint())
+	}
+
+	return value.Float()
+}
+
+// retractedRule returns the rule name a Retract call retracts, empty if it is not a constant.
+func retractedRule(call *ast.ExpressionAtom) string {
+	if call.FunctionCall.ArgumentList == nil || len(call.FunctionCall.ArgumentList.Arguments) != 1 {
+
+		return ""
+	}
+	value := constantOf(call.FunctionCall.ArgumentList.Arguments[0])
+	if !value.IsValid() || value.Kind() != reflect.String {
+
+		return ""
+	}
+
+	return value.String()
+}
+
+// DetectCycles looks for the rules that would fire until MaxCycle.
+//
+// It is an error for a rule, once its condition is true, to keep it true: it does not retract itself, has no
+// max-fires nor cooldown, and nothing can change what its when scope reads. Neither itself, as its writes keep the
+// condition true, nor any other rule. Rules calling methods of the facts or running scripts may change anything,
+// they prevent such errors.
+//
+// It is a warning for a rule to assign a variable its own when scope reads without a guard, a constant assignment
+// that makes its condition false. It is a warning too for two unguarded rules of the same salience to assign what
+// the other one reads, they may re-trigger each other.
+func DetectCycles(rules []*ast.RuleEntry) []CycleFinding {
+	flows := make([]*ruleFlow, 0, len(rules))
+	for _, entry := range rules {
+		if !entry.Deleted {
+			flows = append(flows, newRuleFlow(entry))
+		}
+	}
+	sort.Slice(flows, func(i, j int) bool {
+
+		return flows[i].entry.RuleName < flows[j].entry.RuleName
+	})
+	anyOpaque, anyStops := false, false
+	retracted := make(map[string]bool)
+	for _, flow := range flows {
+		anyOpaque = anyOpaque || flow.opaque
+		anyStops = anyStops || flow.stops
+		for _, name := range flow.retracts {
+			retracted[name] = true
+		}
+	}
+
+	findings := make([]CycleFinding, 0)
+	for _, flow := range flows {
+		name := flow.entry.RuleName
+		if flow.limited || flow.opaque {
+
+			continue
+		}
+		if target, self := flow.writesAny(flow.reads); self {
+			if flow.guarded() {
+
+				continue
+			}
+			if flow.keepsTrue() && !flow.volatile && !anyOpaque && !anyStops && !retracted[name] && !writtenByOthers(flow, flows) {
+				findings = append(findings, CycleFinding{
+					Severity: CycleError,
+					Rules:    []string{name},
+					Reason:   fmt.Sprintf("assigns %s but its when scope stays true, it fires until MaxCycle", target),
+				})
+
+				continue
+			}
+			findings = append(findings, CycleFinding{
+				Severity: CycleWarning,
+				Rules:    []string{name},
+				Reason:   fmt.Sprintf("assigns %s that its when scope reads, without retracting itself, max-fires, cooldown nor a guard", target),
+			})
+
+			continue
+		}
+		if !flow.volatile && !anyOpaque && !anyStops && !retracted[name] && !writtenByOthers(flow, flows) {
+			findings = append(findings, CycleFinding{
+				Severity: CycleError,
+				Rules:    []string{name},
+				Reason:   "changes nothing its when scope reads and does not retract itself, it fires until MaxCycle",
+			})
+		}
+	}
+
+	for i, flow := range flows {
+		if flow.limited || flow.opaque || flow.guarded() {
+
+			continue
+		}
+		for _, other := range flows[i+1:] {
+			if other.limited || other.opaque || other.guarded() || flow.entry.Salience != other.entry.Salience {
+
+				continue
+			}
+			target, triggers := flow.writesAny(other.reads)
+			otherTarget, triggered := other.writesAny(flow.reads)
+			if triggers && triggered {
+				findings = append(findings, CycleFinding{
+					Severity: CycleWarning,
+					Rules:    []string{flow.entry.RuleName, other.entry.RuleName},
+					Reason:   fmt.Sprintf("have the same salience and re-trigger each other through %s and %s", target, otherTarget),
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// writtenByOthers tells whether another rule assigns a variable the rule reads.
+func writtenByOthers(flow *ruleFlow, flows []*ruleFlow) bool {
+	for _, other := range flows {
+		if other == flow {
+
+			continue
+		}
+		if _, written := other.writesAny(flow.reads); written {
+
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,154 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func detectCycles(t *testing.T, grl string) []CycleFinding {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, NewRuleBuilder(lib).BuildRuleFromResource("Cycles", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	rules := make([]*ast.RuleEntry, 0)
+	for _, entry := range lib.GetKnowledgeBase("Cycles", "0.0.1").RuleEntries {
+		rules = append(rules, entry)
+	}
+
+	return DetectCycles(rules)
+}
+
+func TestDetectCycles(t *testing.T) {
+	findings := detectCycles(t, `
+rule NoRetract "Forgot to retract" {
+	when
+		Order.Total > 100
+	then
+		Order.Discount = 10;
+}
+rule SameValue "Writes the value it checks" {
+	when
+		Order.Status == "new" && Order.Total > 0
+	then
+		Order.Status = "new";
+}
+rule Counting "Counts until the condition is false" {
+	when
+		Counter.Value < 10
+	then
+		Counter.Value = Counter.Value + 1;
+}`)
+	assert.Equal(t, []CycleFinding{
+		{Severity: CycleWarning, Rules: []string{"Counting"}, Reason: "assigns Counter.Value that its when scope reads, without retracting itself, max-fires, cooldown nor a guard"},
+		{Severity: CycleError, Rules: []string{"NoRetract"}, Reason: "changes nothing its when scope reads and does not retract itself, it fires until MaxCycle"},
+		{Severity: CycleError, Rules: []string{"SameValue"}, Reason: "assigns Order.Status but its when scope stays true, it fires until MaxCycle"},
+	}, findings)
+
+	findings = detectCycles(t, `
+rule Guarded "Guarded by the status it assigns" {
+	when
+		Order.Status == "new" && Order.Total > 0
+	then
+		Order.Status = "paid";
+}
+rule Flagged "Guarded by a flag" {
+	when
+		!Order.Flagged && Order.Total > 0
+	then
+		Order.Flagged = true;
+}
+rule Retracted "Retracts itself" {
+	when
+		Order.Total > 100
+	then
+		Order.Discount = 10;
+		Retract("Retracted");
+}
+rule Limited "Has max-fires" max-fires 1 {
+	when
+		Order.Total > 100
+	then
+		Order.Discount = 10;
+}
+rule Calls "Calls a method that may change the facts" {
+	when
+		Order.Total > 100
+	then
+		Order.Apply();
+}`)
+	assert.Empty(t, findings)
+
+	findings = detectCycles(t, `
+rule Ping "Answers pong" {
+	when
+		Game.Pong >= Game.Ping
+	then
+		Game.Ping = Game.Pong + 1;
+}
+rule Pong "Answers ping" {
+	when
+		Game.Ping > Game.Pong
+	then
+		Game.Pong = Game.Ping;
+}`)
+	assert.Len(t, findings, 3)
+	assert.Equal(t, CycleFinding{
+		Severity: CycleWarning,
+		Rules:    []string{"Ping", "Pong"},
+		Reason:   "have the same salience and re-trigger each other through Game.Ping and Game.Pong",
+	}, findings[2])
+	assert.Equal(t, "warning: rule Ping and Pong have the same salience and re-trigger each other through Game.Ping and Game.Pong", findings[2].Error())
+}
+
+func TestRuleBuilder_CycleDetection(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	rb.CycleDetection = CycleDetectionStrict
+	err := rb.BuildRuleFromResource("Cycles", "0.0.1", pkg.NewBytesResource([]byte(`
+rule Total "Computes the total" {
+	when
+		Order.Total == 0
+	then
+		Order.Total = Order.Price * Order.Quantity;
+		Retract("Total");
+}`)))
+	assert.NoError(t, err)
+
+	discount := `
+rule Discount "Forgot to retract" {
+	when
+		Order.Total > 100
+	then
+		Order.Discount = 10;
+}`
+	err = rb.BuildRuleFromResource("Cycles", "0.0.1", pkg.NewBytesResource([]byte(discount)))
+	assert.NoError(t, err, "Order.Total is written by a rule already in the knowledge base")
+
+	lib = ast.NewKnowledgeLibrary()
+	rb = NewRuleBuilder(lib)
+	rb.CycleDetection = CycleDetectionStrict
+	err = rb.BuildRuleFromResource("Cycles", "0.0.1", pkg.NewBytesResource([]byte(discount)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected by cycle detection")
+	assert.Contains(t, err.Error(), "error: rule Discount changes nothing its when scope reads")
+	assert.Empty(t, lib.GetKnowledgeBase("Cycles", "0.0.1").RuleEntries)
+
+	rb.CycleDetection = CycleDetectionWarn
+	assert.NoError(t, rb.BuildRuleFromResource("Cycles", "0.0.1", pkg.NewBytesResource([]byte(discount))))
+	assert.Len(t, lib.GetKnowledgeBase("Cycles", "0.0.1").RuleEntries, 1)
+}
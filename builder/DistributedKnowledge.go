@@ -0,0 +1,470 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// ErrCatalogNotFound should be returned by CatalogStore when it has no catalog for the requested hash.
+var ErrCatalogNotFound = errors.New("catalog not found")
+
+// KnowledgeBaseUpdate is published when a node has rebuilt a KnowledgeBase.
+type KnowledgeBaseUpdate struct {
+	Name    string
+	Version string
+	// Hash is the content hash of the resources the KnowledgeBase was built from.
+	Hash string
+	// Origin is the NodeID of the publishing node.
+	Origin string
+}
+
+// UpdateBus is the pub-sub channel shared by all nodes, it can be backed by Redis, NATS, Kafka and alike.
+type UpdateBus interface {
+	// Publish sends the update to every subscriber.
+	Publish(ctx context.Context, update KnowledgeBaseUpdate) error
+	// Subscribe calls the handler for every published update until the context is done.
+	Subscribe(ctx context.Context, handler func(update KnowledgeBaseUpdate)) error
+}
+
+// CatalogStore is the shared storage where a node puts the serialized catalog of a KnowledgeBase it has rebuilt.
+type CatalogStore interface {
+	PutCatalog(ctx context.Context, name, version, hash string, catalog []byte) error
+	GetCatalog(ctx context.Context, name, version, hash string) ([]byte, error)
+}
+
+// DeltaCatalogStore is a CatalogStore that also keeps the delta between the successive catalogs of a KnowledgeBase,
+// so a peer pulls only the changes since the catalog it uses. See ast.CatalogDelta.
+type DeltaCatalogStore interface {
+	CatalogStore
+	PutCatalogDelta(ctx context.Context, name, version, baseHash, hash string, delta []byte) error
+	GetCatalogDelta(ctx context.Context, name, version, baseHash, hash string) ([]byte, error)
+}
+
+// ResourceSource gives the current resources of a KnowledgeBase, it is used by a node to rebuild
+// when the catalog can not be pulled from the CatalogStore.
+type ResourceSource func(name, version string) ([]pkg.Resource, error)
+
+// NewDistributedKnowledge create new instance of DistributedKnowledge. The store and source are optional, but at
+// least one of them is needed for peers to follow the updates.
+func NewDistributedKnowledge(nodeID string, bus UpdateBus, store CatalogStore, source ResourceSource) *DistributedKnowledge {
+
+	return &DistributedKnowledge{
+		NodeID:           nodeID,
+		Bus:              bus,
+		Store:            store,
+		Source:           source,
+		KnowledgeLibrary: ast.NewKnowledgeLibrary(),
+		hashes:           make(map[string]string),
+	}
+}
+
+// DistributedKnowledge keeps the KnowledgeBases of a node consistent with its peers. When a node rebuilds a
+// KnowledgeBase from updated resources it stores the catalog and publishes the content hash, the peers then
+// pull the catalog, or rebuild from their ResourceSource, so every node executes the same rules.
+//
+// The KnowledgeLibrary must only be accessed through the DistributedKnowledge functions once Listen is called.
+type DistributedKnowledge struct {
+	NodeID           string
+	Bus              UpdateBus
+	Store            CatalogStore
+	Source           ResourceSource
+	KnowledgeLibrary *ast.KnowledgeLibrary
+	// Sanitizer, if set, is used for every rebuild.
+	Sanitizer *Sanitizer
+	// Logger, if it wraps a logger, is used instead of BuilderLog, by the rebuilds too.
+	Logger logger.LogEntry
+
+	mutex  sync.RWMutex
+	hashes map[string]string
+}
+
+// log returns the logger of this node.
+func (dk *DistributedKnowledge) log() logger.LogEntry {
+
+	return dk.Logger.Or(BuilderLog)
+}
+
+// loadedResource is a Resource already loaded, so its content is read only once for hashing and building.
+type loadedResource struct {
+	name string
+	data []byte
+}
+
+func (res *loadedResource) Load() ([]byte, error) {
+
+	return res.data, nil
+}
+
+func (res *loadedResource) String() string {
+
+	return res.name
+}
+
+// ContentHash returns the hex encoded SHA-256 hash of the resources content, in their order.
+func ContentHash(resources []pkg.Resource) (string, error) {
+	_, hash, err := loadResources(resources)
+
+	return hash, err
+}
+
+func loadResources(resources []pkg.Resource) ([]pkg.Resource, string, error) {
+	hasher := sha256.New()
+	loaded := make([]pkg.Resource, len(resources))
+	for i, res := range resources {
+		data, err := res.Load()
+		if err != nil {
+
+			return nil, "", fmt.Errorf("error loading resource %s. got %w", res.String(), err)
+		}
+		// length prefix each resource so moving text between resources changes the hash.
+		fmt.Fprintf(hasher, "%d:", len(data))
+		hasher.Write(data)
+		loaded[i] = &loadedResource{name: res.String(), data: data}
+	}
+
+	return loaded, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Hash returns the content hash of the KnowledgeBase currently used by this node, empty if there is none.
+func (dk *DistributedKnowledge) Hash(name, version string) string {
+	dk.mutex.RLock()
+	defer dk.mutex.RUnlock()
+
+	return dk.hashes[ast.GetKnowledgeBaseKey(name, version)]
+}
+
+// NewKnowledgeBaseInstance is the concurrency safe KnowledgeLibrary.NewKnowledgeBaseInstance.
+func (dk *DistributedKnowledge) NewKnowledgeBaseInstance(name, version string) (*ast.KnowledgeBase, error) {
+	dk.mutex.RLock()
+	defer dk.mutex.RUnlock()
+
+	return dk.KnowledgeLibrary.NewKnowledgeBaseInstance(name, version)
+}
+
+// Rebuild builds the KnowledgeBase from the resources, stores its catalog and publishes the update to the peers.
+// Nothing happens if the resources content is the same as the one currently used.
+// A KnowledgeBase that fails to build is never used nor published.
+func (dk *DistributedKnowledge) Rebuild(ctx context.Context, name, version string, resources []pkg.Resource) error {
+	loaded, hash, err := loadResources(resources)
+	if err != nil {
+
+		return err
+	}
+	if dk.Hash(name, version) == hash {
+		dk.log().Debugf("KnowledgeBase %s version %s is already at %s", name, version, hash)
+
+		return nil
+	}
+	kb, catalog, err := dk.build(name, version, loaded)
+	if err != nil {
+
+		return err
+	}
+	deltaStore, storesDeltas := dk.Store.(DeltaCatalogStore)
+	var delta []byte
+	baseHash := dk.Hash(name, version)
+	if storesDeltas && len(baseHash) > 0 {
+		kb, catalog, delta, err = dk.patch(name, version, kb)
+		if err != nil {
+			dk.log().Warnf("Can not make the catalog delta of KnowledgeBase %s version %s. %v", name, version, err)
+			delta = nil
+		}
+	}
+	if dk.Store != nil {
+		if err := dk.Store.PutCatalog(ctx, name, version, hash, catalog); err != nil {
+
+			return fmt.Errorf("error storing catalog of KnowledgeBase %s version %s. got %w", name, version, err)
+		}
+	}
+	if delta != nil {
+		if err := deltaStore.PutCatalogDelta(ctx, name, version, baseHash, hash, delta); err != nil {
+
+			return fmt.Errorf("error storing catalog delta of KnowledgeBase %s version %s. got %w", name, version, err)
+		}
+	}
+	dk.use(kb, hash)
+
+	return dk.Bus.Publish(ctx, KnowledgeBaseUpdate{
+		Name:    name,
+		Version: version,
+		Hash:    hash,
+		Origin:  dk.NodeID,
+	})
+}
+
+// Listen subscribes to the bus and applies the updates published by peers until the context is done.
+func (dk *DistributedKnowledge) Listen(ctx context.Context) error {
+
+	return dk.Bus.Subscribe(ctx, func(update KnowledgeBaseUpdate) {
+		if err := dk.Apply(ctx, update); err != nil {
+			dk.log().Errorf("Failed to apply update of KnowledgeBase %s version %s from %s. got %v", update.Name, update.Version, update.Origin, err)
+		}
+	})
+}
+
+// Apply brings the KnowledgeBase of this node to the published update. It first pulls the catalog from the
+// CatalogStore, and rebuilds from the ResourceSource if the catalog is not available.
+func (dk *DistributedKnowledge) Apply(ctx context.Context, update KnowledgeBaseUpdate) error {
+	if update.Origin == dk.NodeID || dk.Hash(update.Name, update.Version) == update.Hash {
+
+		return nil
+	}
+
+	var pullErr error
+	if deltaStore, ok := dk.Store.(DeltaCatalogStore); ok && len(dk.Hash(update.Name, update.Version)) > 0 {
+		err := dk.applyDelta(ctx, deltaStore, update)
+		if err == nil {
+
+			return nil
+		}
+		dk.log().Debugf("Can not patch KnowledgeBase %s version %s to %s, pulling the catalog. %v", update.Name, update.Version, update.Hash, err)
+	}
+	if dk.Store != nil {
+		catalog, err := dk.Store.GetCatalog(ctx, update.Name, update.Version, update.Hash)
+		if err == nil {
+			kb, err := dk.KnowledgeLibrary.Sibling().LoadKnowledgeBaseFromReader(bytes.NewReader(catalog), true)
+			if err == nil {
+				dk.use(kb, update.Hash)
+
+				return nil
+			}
+			pullErr = fmt.Errorf("error loading catalog. got %w", err)
+		} else {
+			pullErr = fmt.Errorf("error pulling catalog. got %w", err)
+		}
+		dk.log().Warnf("Can not pull KnowledgeBase %s version %s at %s, rebuilding. %v", update.Name, update.Version, update.Hash, pullErr)
+	}
+	if dk.Source == nil {
+
+		return errors.Join(fmt.Errorf("no resource source to rebuild KnowledgeBase %s version %s", update.Name, update.Version), pullErr)
+	}
+
+	resources, err := dk.Source(update.Name, update.Version)
+	if err != nil {
+
+		return fmt.Errorf("error getting resources of KnowledgeBase %s version %s. got %w", update.Name, update.Version, err)
+	}
+	loaded, hash, err := loadResources(resources)
+	if err != nil {
+
+		return err
+	}
+	if hash != update.Hash {
+		// the source is not yet, or no longer, at the published content. Use it anyway, the node that
+		// changed it will publish its own update.
+		dk.log().Warnf("Resources of KnowledgeBase %s version %s hash to %s while %s was published", update.Name, update.Version, hash, update.Hash)
+	}
+	kb, _, err := dk.build(update.Name, update.Version, loaded)
+	if err != nil {
+
+		return err
+	}
+	dk.use(kb, hash)
+
+	return nil
+}
+
+// build builds the KnowledgeBase aside, in its own library, and returns it with its serialized catalog.
+func (dk *DistributedKnowledge) build(name, version string, resources []pkg.Resource) (*ast.KnowledgeBase, []byte, error) {
+	lib := dk.KnowledgeLibrary.Sibling()
+	rb := NewRuleBuilder(lib)
+	rb.Sanitizer = dk.Sanitizer
+	rb.Logger = dk.Logger
+	if err := rb.BuildRuleFromResources(name, version, resources); err != nil {
+
+		return nil, nil, err
+	}
+	var buffer bytes.Buffer
+	if err := lib.StoreKnowledgeBaseToWriter(&buffer, name, version); err != nil {
+
+		return nil, nil, fmt.Errorf("error writing catalog of KnowledgeBase %s version %s. got %w", name, version, err)
+	}
+
+	return lib.GetKnowledgeBase(name, version), buffer.Bytes(), nil
+}
+
+// patch returns the rebuilt KnowledgeBase patched from the one in use, with its serialized catalog and delta, so the
+// ids of its nodes are those of the catalog the peers have.
+func (dk *DistributedKnowledge) patch(name, version string, rebuilt *ast.KnowledgeBase) (*ast.KnowledgeBase, []byte, []byte, error) {
+	dk.mutex.RLock()
+	base := dk.KnowledgeLibrary.GetKnowledgeBase(name, version).MakeCatalog()
+	dk.mutex.RUnlock()
+	delta, err := ast.MakeCatalogDelta(base, rebuilt.MakeCatalog())
+	if err != nil {
+
+		return nil, nil, nil, err
+	}
+	patched, err := base.ApplyDelta(delta)
+	if err != nil {
+
+		return nil, nil, nil, err
+	}
+	kb, err := patched.BuildKnowledgeBaseFor(dk.KnowledgeLibrary)
+	if err != nil {
+
+		return nil, nil, nil, err
+	}
+	var catalog, deltaBuffer bytes.Buffer
+	if err := patched.WriteCatalogToWriter(&catalog); err != nil {
+
+		return nil, nil, nil, err
+	}
+	if err := delta.WriteCatalogDeltaToWriter(&deltaBuffer); err != nil {
+
+		return nil, nil, nil, err
+	}
+
+	return kb, catalog.Bytes(), deltaBuffer.Bytes(), nil
+}
+
+// applyDelta pulls the delta from the catalog in use to the update and patches the KnowledgeBase with it.
+func (dk *DistributedKnowledge) applyDelta(ctx context.Context, store DeltaCatalogStore, update KnowledgeBaseUpdate) error {
+	delta, err := store.GetCatalogDelta(ctx, update.Name, update.Version, dk.Hash(update.Name, update.Version), update.Hash)
+	if err != nil {
+
+		return err
+	}
+	lib := dk.KnowledgeLibrary.Sibling()
+	dk.mutex.RLock()
+	lib.Library[ast.GetKnowledgeBaseKey(update.Name, update.Version)] = dk.KnowledgeLibrary.GetKnowledgeBase(update.Name, update.Version)
+	dk.mutex.RUnlock()
+	kb, err := lib.LoadCatalogDeltaFromReader(bytes.NewReader(delta), true)
+	if err != nil {
+
+		return err
+	}
+	dk.use(kb, update.Hash)
+
+	return nil
+}
+
+func (dk *DistributedKnowledge) use(kb *ast.KnowledgeBase, hash string) {
+	key := ast.GetKnowledgeBaseKey(kb.Name, kb.Version)
+	dk.mutex.Lock()
+	defer dk.mutex.Unlock()
+	dk.KnowledgeLibrary.Library[key] = kb
+	dk.hashes[key] = hash
+	dk.log().Debugf("KnowledgeBase %s version %s is now at %s", kb.Name, kb.Version, hash)
+}
+
+// NewInMemoryBus create new instance of InMemoryBus
+func NewInMemoryBus() *InMemoryBus {
+
+	return &InMemoryBus{}
+}
+
+// InMemoryBus is an UpdateBus within a single process, for tests and for nodes sharing one process.
+type InMemoryBus struct {
+	mutex    sync.RWMutex
+	handlers map[int]func(update KnowledgeBaseUpdate)
+	nextID   int
+}
+
+// Publish calls every subscribed handler synchronously.
+func (bus *InMemoryBus) Publish(ctx context.Context, update KnowledgeBaseUpdate) error {
+	bus.mutex.RLock()
+	handlers := make([]func(update KnowledgeBaseUpdate), 0, len(bus.handlers))
+	for _, handler := range bus.handlers {
+		handlers = append(handlers, handler)
+	}
+	bus.mutex.RUnlock()
+	for _, handler := range handlers {
+		handler(update)
+	}
+
+	return ctx.Err()
+}
+
+// Subscribe registers the handler and blocks until the context is done.
+func (bus *InMemoryBus) Subscribe(ctx context.Context, handler func(update KnowledgeBaseUpdate)) error {
+	bus.mutex.Lock()
+	if bus.handlers == nil {
+		bus.handlers = make(map[int]func(update KnowledgeBaseUpdate))
+	}
+	id := bus.nextID
+	bus.nextID++
+	bus.handlers[id] = handler
+	bus.mutex.Unlock()
+
+	<-ctx.Done()
+
+	bus.mutex.Lock()
+	delete(bus.handlers, id)
+	bus.mutex.Unlock()
+
+	return nil
+}
+
+// NewInMemoryCatalogStore create new instance of InMemoryCatalogStore
+func NewInMemoryCatalogStore() *InMemoryCatalogStore {
+
+	return &InMemoryCatalogStore{
+		catalogs: make(map[string][]byte),
+	}
+}
+
+// InMemoryCatalogStore is a CatalogStore within a single process.
+type InMemoryCatalogStore struct {
+	mutex    sync.RWMutex
+	catalogs map[string][]byte
+}
+
+// PutCatalog stores the catalog.
+func (store *InMemoryCatalogStore) PutCatalog(ctx context.Context, name, version, hash string, catalog []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.catalogs[ast.GetKnowledgeBaseKey(name, version)+"@"+hash] = catalog
+
+	return nil
+}
+
+// GetCatalog returns the stored catalog, or ErrCatalogNotFound.
+func (store *InMemoryCatalogStore) GetCatalog(ctx context.Context, name, version, hash string) ([]byte, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	catalog, ok := store.catalogs[ast.GetKnowledgeBaseKey(name, version)+"@"+hash]
+	if !ok {
+
+		return nil, ErrCatalogNotFound
+	}
+
+	return catalog, nil
+}
+
+// PutCatalogDelta stores the catalog delta.
+func (store *InMemoryCatalogStore) PutCatalogDelta(ctx context.Context, name, version, baseHash, hash string, delta []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.catalogs[ast.GetKnowledgeBaseKey(name, version)+"@"+baseHash+".."+hash] = delta
+
+	return nil
+}
+
+// GetCatalogDelta returns the stored catalog delta, or ErrCatalogNotFound.
+func (store *InMemoryCatalogStore) GetCatalogDelta(ctx context.Context, name, version, baseHash, hash string) ([]byte, error) {
+
+	return store.GetCatalog(ctx, name, version, baseHash+".."+hash)
+}
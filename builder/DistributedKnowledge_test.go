@@ -0,0 +1,125 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const distributedRuleV1 = `rule One "first" { when true then Retract("One"); }`
+
+const distributedRuleV2 = `
+rule One "first" { when true then Retract("One"); }
+rule Two "second" { when true then Retract("Two"); }`
+
+func TestDistributedKnowledge_PullCatalog(t *testing.T) {
+	bus := NewInMemoryBus()
+	store := NewInMemoryCatalogStore()
+	nodeA := NewDistributedKnowledge("A", bus, store, nil)
+	nodeB := NewDistributedKnowledge("B", bus, store, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = nodeB.Listen(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		bus.mutex.RLock()
+		defer bus.mutex.RUnlock()
+
+		return len(bus.handlers) == 1
+	}, time.Second, time.Millisecond)
+
+	err := nodeA.Rebuild(ctx, "Dist", "1", []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV1))})
+	assert.NoError(t, err)
+	hash := nodeA.Hash("Dist", "1")
+	assert.NotEmpty(t, hash)
+	assert.Equal(t, hash, nodeB.Hash("Dist", "1"))
+	kb, err := nodeB.NewKnowledgeBaseInstance("Dist", "1")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, 1)
+
+	err = nodeA.Rebuild(ctx, "Dist", "1", []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV2))})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, nodeA.Hash("Dist", "1"))
+	assert.Equal(t, nodeA.Hash("Dist", "1"), nodeB.Hash("Dist", "1"))
+	kb, err = nodeB.NewKnowledgeBaseInstance("Dist", "1")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, 2)
+
+	// a failed build is neither used nor published
+	err = nodeA.Rebuild(ctx, "Dist", "1", []pkg.Resource{pkg.NewBytesResource([]byte(`rule Broken {`))})
+	assert.Error(t, err)
+	assert.Equal(t, nodeA.Hash("Dist", "1"), nodeB.Hash("Dist", "1"))
+}
+
+func TestDistributedKnowledge_RebuildFromSource(t *testing.T) {
+	source := func(name, version string) ([]pkg.Resource, error) {
+
+		return []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV2))}, nil
+	}
+	nodeA := NewDistributedKnowledge("A", NewInMemoryBus(), nil, nil)
+	nodeB := NewDistributedKnowledge("B", NewInMemoryBus(), NewInMemoryCatalogStore(), source)
+
+	ctx := context.Background()
+	resources := []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV2))}
+	assert.NoError(t, nodeA.Rebuild(ctx, "Dist", "1", resources))
+	hash, err := ContentHash([]pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV2))})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, nodeA.Hash("Dist", "1"))
+
+	// node B store does not have the catalog, so it rebuilds from its source.
+	update := KnowledgeBaseUpdate{Name: "Dist", Version: "1", Hash: hash, Origin: "A"}
+	assert.NoError(t, nodeB.Apply(ctx, update))
+	assert.Equal(t, hash, nodeB.Hash("Dist", "1"))
+	kb, err := nodeB.NewKnowledgeBaseInstance("Dist", "1")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, 2)
+
+	nodeC := NewDistributedKnowledge("C", NewInMemoryBus(), NewInMemoryCatalogStore(), nil)
+	assert.Error(t, nodeC.Apply(ctx, update))
+	assert.Empty(t, nodeC.Hash("Dist", "1"))
+}
+
+func TestDistributedKnowledge_PullDelta(t *testing.T) {
+	bus := NewInMemoryBus()
+	store := NewInMemoryCatalogStore()
+	nodeA := NewDistributedKnowledge("A", bus, store, nil)
+	nodeB := NewDistributedKnowledge("B", bus, store, nil)
+	ctx := context.Background()
+
+	assert.NoError(t, nodeA.Rebuild(ctx, "Dist", "1", []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV1))}))
+	first := KnowledgeBaseUpdate{Name: "Dist", Version: "1", Hash: nodeA.Hash("Dist", "1"), Origin: "A"}
+	assert.NoError(t, nodeB.Apply(ctx, first))
+
+	assert.NoError(t, nodeA.Rebuild(ctx, "Dist", "1", []pkg.Resource{pkg.NewBytesResource([]byte(distributedRuleV2))}))
+	second := KnowledgeBaseUpdate{Name: "Dist", Version: "1", Hash: nodeA.Hash("Dist", "1"), Origin: "A"}
+	delta, err := store.GetCatalogDelta(ctx, "Dist", "1", first.Hash, second.Hash)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, delta)
+
+	// without the full catalog, the peer patches the catalog it uses
+	store.mutex.Lock()
+	delete(store.catalogs, "Dist:1@"+second.Hash)
+	store.mutex.Unlock()
+	assert.NoError(t, nodeB.Apply(ctx, second))
+	assert.Equal(t, second.Hash, nodeB.Hash("Dist", "1"))
+	assert.True(t, nodeB.KnowledgeLibrary.GetKnowledgeBase("Dist", "1").IsIdentical(nodeA.KnowledgeLibrary.GetKnowledgeBase("Dist", "1")))
+}
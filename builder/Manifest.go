@@ -0,0 +1,420 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+const (
+	// ManifestFileName is the name of the manifest file at the root of a rule repository.
+	ManifestFileName = "grule.mod"
+
+	// defaultManifestRules is the rule file pattern used when the manifest declares none.
+	defaultManifestRules = "**/*.grl"
+)
+
+// Manifest describes a rule repository, in the spirit of go.mod :
+//
+//	// comments are allowed
+//	knowledgebase Payments 1.4.0
+//	rules payments/**/*.grl
+//	require Fraud >=1.2.0, <2.0.0
+//	require Common ^1.0.0
+//	functions Strings Dates
+//	cost fields 40 calls 10 depth 8
+//
+// The knowledgebase line is mandatory. Rules are the file patterns of the GRL files relative to the
+// repository, every .grl file if none. Each require names another KnowledgeBase that must be in the library
+// with a version satisfying the constraint, and functions names the facts carrying functions the rules call,
+// which the data context must provide. Cost sets the CostLimits of the rules, by field accesses, function
+// calls and expression depth.
+type Manifest struct {
+	Name       string
+	Version    string
+	Rules      []string
+	Requires   []ManifestRequirement
+	Functions  []string
+	CostLimits CostLimits
+
+	// Costs are the estimated costs of the rules of the KnowledgeBase, filled by BuildRulesFromManifest.
+	Costs []RuleCost
+	// CostWarnings are the rules whose cost exceeds the CostLimits, filled by BuildRulesFromManifest.
+	CostWarnings []error
+}
+
+// ManifestRequirement is a KnowledgeBase required by a manifest.
+type ManifestRequirement struct {
+	Name string
+	// Constraint is a list of comma or space separated comparisons, such as ">=1.2.0, <2.0.0".
+	// A bare version requires that exact version, ^1.2.0 allows any 1.x.y from 1.2.0 and
+	// ~1.2.0 allows any 1.2.x from 1.2.0.
+	Constraint string
+
+	comparisons []versionComparison
+}
+
+// ParseManifest reads the manifest content, errors mention the line they are found on.
+func ParseManifest(data []byte) (*Manifest, error) {
+	manifest := &Manifest{
+		Rules:     make([]string, 0),
+		Requires:  make([]ManifestRequirement, 0),
+		Functions: make([]string, 0),
+	}
+	for index, line := range strings.Split(string(data), "\n") {
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+
+			continue
+		}
+		lineNumber := index + 1
+		switch fields[0] {
+		case "knowledgebase":
+			if len(fields) != 3 {
+
+				return nil, fmt.Errorf("%s line %d : expecting knowledgebase <name> <version>", ManifestFileName, lineNumber)
+			}
+			if len(manifest.Name) > 0 {
+
+				return nil, fmt.Errorf("%s line %d : knowledgebase is already declared", ManifestFileName, lineNumber)
+			}
+			manifest.Name = fields[1]
+			manifest.Version = fields[2]
+		case "rules":
+			if len(fields) < 2 {
+
+				return nil, fmt.Errorf("%s line %d : expecting rules <pattern>...", ManifestFileName, lineNumber)
+			}
+			manifest.Rules = append(manifest.Rules, fields[1:]...)
+		case "require":
+			if len(fields) < 3 {
+
+				return nil, fmt.Errorf("%s line %d : expecting require <name> <constraint>", ManifestFileName, lineNumber)
+			}
+			requirement := ManifestRequirement{
+				Name:       fields[1],
+				Constraint: strings.Join(fields[2:], " "),
+			}
+			comparisons, err := parseVersionConstraint(requirement.Constraint)
+			if err != nil {
+
+				return nil, fmt.Errorf("%s line %d : %w", ManifestFileName, lineNumber, err)
+			}
+			requirement.comparisons = comparisons
+			manifest.Requires = append(manifest.Requires, requirement)
+		case "functions":
+			if len(fields) < 2 {
+
+				return nil, fmt.Errorf("%s line %d : expecting functions <name>...", ManifestFileName, lineNumber)
+			}
+			manifest.Functions = append(manifest.Functions, fields[1:]...)
+		case "cost":
+			if len(fields) < 3 || len(fields)%2 == 0 {
+
+				return nil, fmt.Errorf("%s line %d : expecting cost <fields|calls|depth> <limit>...", ManifestFileName, lineNumber)
+			}
+			for i := 1; i < len(fields); i += 2 {
+				limit, err := strconv.Atoi(fields[i+1])
+				if err != nil || limit < 0 {
+
+					return nil, fmt.Errorf("%s line %d : invalid cost limit %q", ManifestFileName, lineNumber, fields[i+1])
+				}
+				switch fields[i] {
+				case "fields":
+					manifest.CostLimits.FieldAccesses = limit
+				case "calls":
+					manifest.CostLimits.FunctionCalls = limit
+				case "depth":
+					manifest.CostLimits.ExpressionDepth = limit
+				default:
+
+					return nil, fmt.Errorf("%s line %d : unknown cost %s", ManifestFileName, lineNumber, fields[i])
+				}
+			}
+		default:
+
+			return nil, fmt.Errorf("%s line %d : unknown directive %s", ManifestFileName, lineNumber, fields[0])
+		}
+	}
+	if len(manifest.Name) == 0 {
+
+		return nil, fmt.Errorf("%s : missing knowledgebase <name> <version>", ManifestFileName)
+	}
+
+	return manifest, nil
+}
+
+// LoadManifest reads and parses the manifest from a resource.
+func LoadManifest(resource pkg.Resource) (*Manifest, error) {
+	data, err := resource.Load()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return ParseManifest(data)
+}
+
+// Satisfies tells whether the version satisfies the requirement constraint.
+func (requirement ManifestRequirement) Satisfies(version string) bool {
+	comparisons := requirement.comparisons
+	if comparisons == nil {
+		parsed, err := parseVersionConstraint(requirement.Constraint)
+		if err != nil {
+
+			return false
+		}
+		comparisons = parsed
+	}
+	for _, comparison := range comparisons {
+		if !comparison.matches(version) {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate checks every requirement against the KnowledgeBases in the library, all unmet requirements are reported.
+func (manifest *Manifest) Validate(library *ast.KnowledgeLibrary) error {
+	versions := make(map[string][]string)
+	for _, kb := range library.Library {
+		versions[kb.Name] = append(versions[kb.Name], kb.Version)
+	}
+
+	errs := make([]error, 0)
+	for _, requirement := range manifest.Requires {
+		found := versions[requirement.Name]
+		if len(found) == 0 {
+			errs = append(errs, fmt.Errorf("%s %s requires %s %s, which is not in the library", manifest.Name, manifest.Version, requirement.Name, requirement.Constraint))
+
+			continue
+		}
+		satisfied := false
+		for _, version := range found {
+			if requirement.Satisfies(version) {
+				satisfied = true
+
+				break
+			}
+		}
+		if !satisfied {
+			sort.Strings(found)
+			errs = append(errs, fmt.Errorf("%s %s requires %s %s, the library only has %s", manifest.Name, manifest.Version, requirement.Name, requirement.Constraint, strings.Join(found, ", ")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckDataContext checks that the data context provides every function the manifest declares.
+func (manifest *Manifest) CheckDataContext(dataContext ast.IDataContext) error {
+	keys := make(map[string]bool)
+	for _, key := range dataContext.GetKeys() {
+		keys[key] = true
+	}
+	missing := make([]string, 0)
+	for _, function := range manifest.Functions {
+		if !keys[function] {
+			missing = append(missing, function)
+		}
+	}
+	if len(missing) > 0 {
+
+		return fmt.Errorf("%s %s requires functions %s, which are not in the data context", manifest.Name, manifest.Version, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// BuildRulesFromManifest builds the rule repository at basePath, described by its grule.mod manifest, into the
+// KnowledgeBase the manifest declares. The requirements are validated before any rule is built, so an incompatible
+// repository is rejected as a whole. The parsed manifest is returned so the caller can check the data context later on,
+// along with the estimated costs of the rules. The rules exceeding the cost limits of the manifest are logged.
+func (builder *RuleBuilder) BuildRulesFromManifest(basePath string) (*Manifest, error) {
+	manifest, err := LoadManifest(pkg.NewFileResource(filepath.Join(basePath, ManifestFileName)))
+	if err != nil {
+
+		return nil, err
+	}
+	err = manifest.Validate(builder.KnowledgeLibrary)
+	if err != nil {
+
+		return nil, err
+	}
+	patterns := manifest.Rules
+	if len(patterns) == 0 {
+		patterns = []string{defaultManifestRules}
+	}
+	resources, err := pkg.NewFileResourceBundle(basePath, patterns...).Load()
+	if err != nil {
+
+		return nil, err
+	}
+	if len(resources) == 0 {
+
+		return nil, fmt.Errorf("%s %s declares no rule file in %s", manifest.Name, manifest.Version, basePath)
+	}
+	err = builder.BuildRuleFromResources(manifest.Name, manifest.Version, resources)
+	if err != nil {
+
+		return nil, err
+	}
+	knowledgeBase := builder.KnowledgeLibrary.GetKnowledgeBase(manifest.Name, manifest.Version)
+	rules := make([]*ast.RuleEntry, 0, len(knowledgeBase.RuleEntries))
+	for _, entry := range knowledgeBase.RuleEntries {
+		rules = append(rules, entry)
+	}
+	manifest.Costs = EstimateCosts(rules)
+	manifest.CostWarnings = make([]error, 0)
+	for _, cost := range manifest.Costs {
+		if err := manifest.CostLimits.Check(cost); err != nil {
+			builder.log().Warnf("%s %s %s", manifest.Name, manifest.Version, err.Error())
+			manifest.CostWarnings = append(manifest.CostWarnings, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// versionComparison is a single comparison of a version constraint.
+type versionComparison struct {
+	operator string
+	version  []int
+}
+
+func parseVersionConstraint(constraint string) ([]versionComparison, error) {
+	ret := make([]versionComparison, 0)
+	for _, field := range strings.FieldsFunc(constraint, func(r rune) bool {
+
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		operator := ""
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(field, candidate) {
+				operator = candidate
+
+				break
+			}
+		}
+		version, ok := parseManifestVersion(strings.TrimPrefix(field, operator))
+		if !ok {
+
+			return nil, fmt.Errorf("invalid version constraint %q", field)
+		}
+		if len(operator) == 0 {
+			operator = "="
+		}
+		ret = append(ret, versionComparison{operator: operator, version: version})
+	}
+	if len(ret) == 0 {
+
+		return nil, fmt.Errorf("empty version constraint")
+	}
+
+	return ret, nil
+}
+
+func (comparison versionComparison) matches(version string) bool {
+	parsed, ok := parseManifestVersion(version)
+	if !ok {
+
+		return false
+	}
+	diff := compareManifestVersion(parsed, comparison.version)
+	switch comparison.operator {
+	case ">=":
+
+		return diff >= 0
+	case "<=":
+
+		return diff <= 0
+	case "!=":
+
+		return diff != 0
+	case ">":
+
+		return diff > 0
+	case "<":
+
+		return diff < 0
+	case "^":
+		// same major version, unless it is 0 where the minor version must be the same.
+		if diff < 0 || versionPart(parsed, 0) != versionPart(comparison.version, 0) {
+
+			return false
+		}
+
+		return versionPart(comparison.version, 0) != 0 || versionPart(parsed, 1) == versionPart(comparison.version, 1)
+	case "~":
+
+		return diff >= 0 && versionPart(parsed, 0) == versionPart(comparison.version, 0) && versionPart(parsed, 1) == versionPart(comparison.version, 1)
+	}
+
+	return diff == 0
+}
+
+// parseManifestVersion parses a dot separated numeric version such as 1.2.0, an optional leading v is ignored.
+func parseManifestVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if len(version) == 0 {
+
+		return nil, false
+	}
+	parts := strings.Split(version, ".")
+	ret := make([]int, len(parts))
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 {
+
+			return nil, false
+		}
+		ret[i] = num
+	}
+
+	return ret, true
+}
+
+func versionPart(version []int, index int) int {
+	if index < len(version) {
+
+		return version[index]
+	}
+
+	return 0
+}
+
+func compareManifestVersion(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if diff := versionPart(a, i) - versionPart(b, i); diff != 0 {
+
+			return diff
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,124 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifest = `// payments rules
+knowledgebase Payments 1.4.0
+rules rules/*.grl
+require Fraud >=1.2.0, <2.0.0
+require Common ^1.0.0 // any 1.x
+functions Strings
+cost calls 5 depth 1
+`
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest([]byte(testManifest))
+	assert.NoError(t, err)
+	assert.Equal(t, "Payments", manifest.Name)
+	assert.Equal(t, "1.4.0", manifest.Version)
+	assert.Equal(t, []string{"rules/*.grl"}, manifest.Rules)
+	assert.Equal(t, []string{"Strings"}, manifest.Functions)
+	assert.Equal(t, CostLimits{FunctionCalls: 5, ExpressionDepth: 1}, manifest.CostLimits)
+	if assert.Len(t, manifest.Requires, 2) {
+		assert.Equal(t, "Fraud", manifest.Requires[0].Name)
+		assert.Equal(t, ">=1.2.0, <2.0.0", manifest.Requires[0].Constraint)
+	}
+
+	testData := []string{
+		"rules *.grl",
+		"knowledgebase Payments",
+		"knowledgebase A 1\nknowledgebase B 1",
+		"knowledgebase A 1\nrequire B",
+		"knowledgebase A 1\nrequire B >=one",
+		"knowledgebase A 1\nreplace B C",
+		"knowledgebase A 1\ncost calls",
+		"knowledgebase A 1\ncost calls -1",
+		"knowledgebase A 1\ncost loops 3",
+	}
+	for _, data := range testData {
+		_, err := ParseManifest([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestManifestRequirement_Satisfies(t *testing.T) {
+	testData := []struct {
+		constraint string
+		version    string
+		satisfies  bool
+	}{
+		{constraint: "1.2.0", version: "1.2", satisfies: true},
+		{constraint: "=1.2.0", version: "1.2.1", satisfies: false},
+		{constraint: ">=1.2.0, <2.0.0", version: "1.9.3", satisfies: true},
+		{constraint: ">=1.2.0 <2.0.0", version: "2.0.0", satisfies: false},
+		{constraint: "^1.2.0", version: "1.10.0", satisfies: true},
+		{constraint: "^1.2.0", version: "1.1.9", satisfies: false},
+		{constraint: "^0.2.0", version: "0.3.0", satisfies: false},
+		{constraint: "~1.2.0", version: "1.2.7", satisfies: true},
+		{constraint: "~1.2.0", version: "1.3.0", satisfies: false},
+		{constraint: "!=1.0.0", version: "v1.0.1", satisfies: true},
+		{constraint: ">1.0.0", version: "latest", satisfies: false},
+	}
+	for _, td := range testData {
+		requirement := ManifestRequirement{Name: "A", Constraint: td.constraint}
+		assert.Equal(t, td.satisfies, requirement.Satisfies(td.version), "%s %s", td.constraint, td.version)
+	}
+}
+
+func TestRuleBuilder_BuildRulesFromManifest(t *testing.T) {
+	basePath := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(basePath, "rules"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(basePath, ManifestFileName), []byte(testManifest), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(basePath, "rules", "pay.grl"), []byte(`rule Pay { when Fact.Amount > 0 then Retract("Pay"); }`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(basePath, "ignored.grl"), []byte(`rule Ignored { when true then Retract("Ignored"); }`), 0o644))
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	// Fraud is not in the library, Common is in the wrong version : nothing is built.
+	rb.MustBuildRuleFromResource("Common", "2.0.0", pkg.NewBytesResource([]byte(`rule C { when true then Retract("C"); }`)))
+	_, err := rb.BuildRulesFromManifest(basePath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires Fraud >=1.2.0, <2.0.0, which is not in the library")
+	assert.Contains(t, err.Error(), "requires Common ^1.0.0, the library only has 2.0.0")
+	_, ok := lib.Library[ast.GetKnowledgeBaseKey("Payments", "1.4.0")]
+	assert.False(t, ok)
+
+	rb.MustBuildRuleFromResource("Common", "1.3.0", pkg.NewBytesResource([]byte(`rule C { when true then Retract("C"); }`)))
+	rb.MustBuildRuleFromResource("Fraud", "1.2.5", pkg.NewBytesResource([]byte(`rule F { when true then Retract("F"); }`)))
+	manifest, err := rb.BuildRulesFromManifest(basePath)
+	assert.NoError(t, err)
+	kb := lib.GetKnowledgeBase("Payments", "1.4.0")
+	assert.Len(t, kb.RuleEntries, 1)
+	assert.NotNil(t, kb.RuleEntries["Pay"])
+	assert.Equal(t, []RuleCost{{Rule: "Pay", FieldAccesses: 1, FunctionCalls: 1, ExpressionDepth: 2}}, manifest.Costs)
+	if assert.Len(t, manifest.CostWarnings, 1) {
+		assert.Equal(t, "rule Pay is expensive : expression depth 2 over 1", manifest.CostWarnings[0].Error())
+	}
+
+	dataContext := ast.NewDataContext()
+	assert.Error(t, manifest.CheckDataContext(dataContext))
+	assert.NoError(t, dataContext.Add("Strings", &struct{}{}))
+	assert.NoError(t, manifest.CheckDataContext(dataContext))
+}
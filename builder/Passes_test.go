@@ -0,0 +1,75 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/ir"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type PassesSession struct {
+	Seconds int64
+	Expired bool
+	Warned  bool
+}
+
+const passesRules = `
+rule Expire "expires the sessions older than a day" salience 10 {
+	when
+		Session.Seconds > 60 * 60 * 24 && !Session.Expired
+	then
+		Session.Expired = true;
+}
+rule Warn "warns the sessions older than an hour" {
+	when
+		false && Session.Seconds > 0 || Session.Seconds > 60 * 60 && !Session.Warned
+	then
+		Session.Warned = true;
+}
+halt when Session.Seconds >= 2 * 86400 && 1 == 1;
+`
+
+func TestRuleBuilder_Passes(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	rb.Passes = ir.DefaultPasses()
+	assert.NoError(t, rb.BuildRuleFromResource("Sessions", "1.0.0", pkg.NewBytesResource([]byte(passesRules))))
+
+	kb := lib.GetKnowledgeBase("Sessions", "1.0.0")
+	assert.Equal(t, "whenSession.Seconds>86400&&!Session.Expired", kb.RuleEntries["Expire"].WhenScope.GrlText)
+	assert.Equal(t, "whenSession.Seconds>3600&&!Session.Warned", kb.RuleEntries["Warn"].WhenScope.GrlText)
+	assert.Equal(t, 10, kb.RuleEntries["Expire"].Salience)
+	if assert.Len(t, kb.HaltEntries, 1) {
+		assert.Equal(t, "Session.Seconds>=172800", kb.HaltEntries[0].Expression.GrlText)
+	}
+
+	// the rules of a resource loaded again already exist, as without passes.
+	assert.Error(t, rb.BuildRuleFromResource("Sessions", "1.0.0", pkg.NewBytesResource([]byte(passesRules))))
+	assert.Len(t, kb.HaltEntries, 1)
+
+	session := &PassesSession{Seconds: 7200}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Session", session))
+	instance, err := lib.NewKnowledgeBaseInstance("Sessions", "1.0.0")
+	assert.NoError(t, err)
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, instance))
+	assert.True(t, session.Warned)
+	assert.False(t, session.Expired)
+}
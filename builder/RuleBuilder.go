@@ -15,12 +15,13 @@
 package builder
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/ir"
 	"github.com/hyperjumptech/grule-rule-engine/logger"
-	"github.com/rs/zerolog"
-	"github.com/sirupsen/logrus"
-	"go.uber.org/zap"
+	"slices"
 	"time"
 
 	"github.com/antlr4-go/antlr/v4"
@@ -41,30 +42,8 @@ var (
 
 // SetLogger changes default logger on external
 func SetLogger(log interface{}) {
-	var entry logger.LogEntry
-
-	switch log.(type) {
-	case *zap.Logger:
-		log, ok := log.(*zap.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewZap(log)
-	case *logrus.Logger:
-		log, ok := log.(*logrus.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewLogrus(log)
-	case *zerolog.Logger:
-		log, ok := log.(*zerolog.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewZero(log)
-	default:
+	entry, ok := logger.FromExternal(log)
+	if !ok {
 
 		return
 	}
@@ -83,6 +62,49 @@ func NewRuleBuilder(KnowledgeLibrary *ast.KnowledgeLibrary) *RuleBuilder {
 // RuleBuilder builds rule from GRL script into contained KnowledgeBase
 type RuleBuilder struct {
 	KnowledgeLibrary *ast.KnowledgeLibrary
+
+	// Sanitizer, if set, validates every resource before any of its rules is added into the KnowledgeBase.
+	Sanitizer *Sanitizer
+
+	// CycleDetection, if not off, looks for the rules of every resource that would fire until MaxCycle,
+	// together with the rules already in the KnowledgeBase. See DetectCycles.
+	CycleDetection CycleDetection
+
+	// StrictWhenScopes rejects the rules whose when scope has side effects, such as calling Retract, Changed,
+	// the setters of the facts or Append on an array. The when scopes are evaluated again on every cycle.
+	StrictWhenScopes bool
+
+	// Schema, if set, is recorded on the KnowledgeBase, so KnowledgeBase.UnresolvedReferences lists the facts, the
+	// fields and the functions the rules reference that the host does not provide.
+	Schema *ast.FactSchema
+
+	// Passes, if set, optimize the rules of every resource before they are added into the KnowledgeBase. The rules
+	// are lifted into the intermediate representation of package ir, rewritten by the passes in order and lowered
+	// back, such as with ir.DefaultPasses().
+	Passes []ir.Pass
+
+	// CostLimits, if set, logs a warning for every rule of a resource whose estimated cost exceeds a limit, so the
+	// expensive rules are noticed before they run. See EstimateCost.
+	CostLimits *CostLimits
+
+	// Logger, if it wraps a logger, is used instead of BuilderLog by this builder and the parser of its resources,
+	// so the builders of two tenants can log apart. See logger.FromExternal.
+	Logger logger.LogEntry
+}
+
+// log returns the logger of this builder.
+func (builder *RuleBuilder) log() logger.LogEntry {
+
+	return builder.Logger.Or(BuilderLog)
+}
+
+// WithSanitizer returns a RuleBuilder of the same KnowledgeLibrary that validates resources with another sanitizer,
+// so the bundles of each team can be constrained on their own.
+func (builder *RuleBuilder) WithSanitizer(sanitizer *Sanitizer) *RuleBuilder {
+	constrained := *builder
+	constrained.Sanitizer = sanitizer
+
+	return &constrained
 }
 
 // MustBuildRuleFromResources is similar to BuildRuleFromResources, with the difference is, it will panic if rule script contains error.
@@ -115,9 +137,26 @@ func (builder *RuleBuilder) BuildRulesFromBundle(name, version string, bundle pk
 	return builder.BuildRuleFromResources(name, version, bundles)
 }
 
+// BuildRulesFromBundleWithContext is the same as BuildRulesFromBundle, the bundle and its resources are loaded
+// within the context, so the loading can be bound by a deadline or cancelled.
+func (builder *RuleBuilder) BuildRulesFromBundleWithContext(ctx context.Context, name, version string, bundle pkg.ResourceBundle) error {
+	bundles, err := pkg.LoadBundle(ctx, bundle)
+	if err != nil {
+
+		return err
+	}
+
+	return builder.BuildRuleFromResourcesWithContext(ctx, name, version, bundles)
+}
+
 // MustBuildRulesFromBundle is the same with BuildRulesFromBundle but it will panic if any error arises during loading resource and inserting it to knowledgebase
 func (builder *RuleBuilder) MustBuildRulesFromBundle(name, version string, bundle pkg.ResourceBundle) {
-	builder.MustBuildRuleFromResources(name, version, bundle.MustLoad())
+	resources, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+	builder.MustBuildRuleFromResources(name, version, resources)
 }
 
 // BuildRuleFromResources will load rules from multiple resources. It will return an error if it encounter an error on the first script it found.
@@ -133,6 +172,31 @@ func (builder *RuleBuilder) BuildRuleFromResources(name, version string, resourc
 	return nil
 }
 
+// BuildRuleFromResourcesWithContext is the same as BuildRuleFromResources, the resources are loaded within the context.
+func (builder *RuleBuilder) BuildRuleFromResourcesWithContext(ctx context.Context, name, version string, resource []pkg.Resource) error {
+	for _, v := range resource {
+		err := builder.BuildRuleFromResourceWithContext(ctx, name, version, v)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BuildRuleFromResourceWithContext is the same as BuildRuleFromResource, the resource is loaded within the context.
+func (builder *RuleBuilder) BuildRuleFromResourceWithContext(ctx context.Context, name, version string, resource pkg.Resource) error {
+	startTime := time.Now()
+	data, err := pkg.LoadResource(ctx, resource)
+	if err != nil {
+
+		return err
+	}
+
+	return builder.buildRuleFromData(name, version, data, resource, startTime)
+}
+
 // BuildRuleFromResource will load rules from a single resource. It will return an error if it encounter an error on the specified resource.
 func (builder *RuleBuilder) BuildRuleFromResource(name, version string, resource pkg.Resource) error {
 	// save the starting time, we need to see the loading time in debug log
@@ -145,40 +209,34 @@ func (builder *RuleBuilder) BuildRuleFromResource(name, version string, resource
 		return err
 	}
 
-	// Immediately parse the loaded resource
-	is := antlr.NewInputStream(string(data))
-	lexer := parser.Newgrulev3Lexer(is)
-
-	errReporter := &pkg.GruleErrorReporter{
-		Errors: make([]error, 0),
-	}
-
-	lexer.RemoveErrorListeners()
-	lexer.AddErrorListener(errReporter)
-
-	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	return builder.buildRuleFromData(name, version, data, resource, startTime)
+}
 
+// buildRuleFromData adds the rules of the loaded GRL data, from the resource, into the knowledge base. The resource
+// is recorded into the sources of the knowledge base once its rules are added.
+func (builder *RuleBuilder) buildRuleFromData(name, version string, data []byte, resource pkg.Resource, startTime time.Time) error {
+	origin := resource.String()
 	knowledgeBase := builder.KnowledgeLibrary.GetKnowledgeBase(name, version)
 	if knowledgeBase == nil {
 
 		return fmt.Errorf("KnowledgeBase %s:%s is not in this library", name, version)
 	}
+	if builder.Schema != nil {
+		knowledgeBase.Schema = builder.Schema
+	}
 
-	listener := antlr2.NewGruleV3ParserListener(knowledgeBase, errReporter)
-
-	psr := parser.Newgrulev3Parser(stream)
-
-	psr.RemoveErrorListeners()
-	psr.AddErrorListener(errReporter)
-
-	psr.BuildParseTrees = true
-	antlr.ParseTreeWalkerDefault.Walk(listener, psr.Grl())
+	errReporter := &pkg.GruleErrorReporter{
+		Errors: make([]error, 0),
+	}
+	grl, err := builder.parseGrl(knowledgeBase, string(data), origin, builder.Sanitizer, errReporter)
+	if err != nil {
 
-	grl := listener.Grl
+		return err
+	}
 	for _, ruleEntry := range grl.RuleEntries {
 		err := knowledgeBase.AddRuleEntry(ruleEntry)
 		if err != nil && err.Error() != "rule entry TestNoDesc already exist" {
-			BuilderLog.Tracef("warning while adding rule entry : %s. got %s, possibly already added by antlr listener", ruleEntry.RuleName, err.Error())
+			builder.log().Tracef("warning while adding rule entry : %s. got %s, possibly already added by antlr listener", ruleEntry.RuleName, err.Error())
 		}
 	}
 
@@ -188,15 +246,189 @@ func (builder *RuleBuilder) BuildRuleFromResource(name, version string, resource
 	dur := time.Now().Sub(startTime)
 
 	if errReporter.HasError() {
-		BuilderLog.Errorf("GRL syntax error. got %s", errReporter.Error())
+		builder.log().Errorf("GRL syntax error. got %s", errReporter.Error())
 		for i, err := range errReporter.Errors {
-			BuilderLog.Errorf("%d : %s", i, err.Error())
+			builder.log().Errorf("%d : %s", i, err.Error())
 		}
 
 		return errReporter
 	}
 
-	BuilderLog.Debugf("Loading rule resource : %s success. Time taken %d ms", resource.String(), dur.Nanoseconds()/1e6)
+	builder.checkCosts(grl, origin)
+	knowledgeBase.AddSource(pkg.MetadataOf(resource, data))
+	builder.log().Debugf("Loading rule resource : %s success. Time taken %d ms", origin, dur.Nanoseconds()/1e6)
 
 	return nil
 }
+
+// checkCosts logs the rules of the GRL whose estimated cost exceeds the cost limits.
+func (builder *RuleBuilder) checkCosts(grl *ast.Grl, origin string) {
+	if builder.CostLimits == nil {
+
+		return
+	}
+	rules := make([]*ast.RuleEntry, 0, len(grl.RuleEntries))
+	for _, entry := range grl.RuleEntries {
+		rules = append(rules, entry)
+	}
+	for _, cost := range EstimateCosts(rules) {
+		if err := builder.CostLimits.Check(cost); err != nil {
+			builder.log().Warnf("GRL resource %s %s", origin, err.Error())
+		}
+	}
+}
+
+// checkCycles detects the cycles the rules of the GRL take part in, along with the rules already in the knowledge base.
+// The findings are logged, in strict mode the errors reject the GRL.
+func (builder *RuleBuilder) checkCycles(knowledgeBase *ast.KnowledgeBase, grl *ast.Grl, origin string) error {
+	if builder.CycleDetection == CycleDetectionOff {
+
+		return nil
+	}
+	rules := make([]*ast.RuleEntry, 0, len(knowledgeBase.RuleEntries)+len(grl.RuleEntries))
+	for name, entry := range knowledgeBase.RuleEntries {
+		if _, replaced := grl.RuleEntries[name]; !replaced {
+			rules = append(rules, entry)
+		}
+	}
+	for _, entry := range grl.RuleEntries {
+		rules = append(rules, entry)
+	}
+	var errs []error
+	for _, finding := range DetectCycles(rules) {
+		if !slices.ContainsFunc(finding.Rules, func(name string) bool {
+			_, added := grl.RuleEntries[name]
+
+			return added
+		}) {
+
+			continue
+		}
+		if finding.Severity == CycleError && builder.CycleDetection == CycleDetectionStrict {
+			errs = append(errs, finding)
+
+			continue
+		}
+		builder.log().Warnf("GRL resource %s cycle %s", origin, finding.Error())
+	}
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+		builder.log().Errorf("GRL rejected by cycle detection. got %v", err)
+
+		return fmt.Errorf("GRL resource %s rejected by cycle detection. got %w", origin, err)
+	}
+
+	return nil
+}
+
+// newListener returns the listener walking the GRL into the knowledge base, with the logger of this builder and the
+// registries of its library.
+func (builder *RuleBuilder) newListener(knowledgeBase *ast.KnowledgeBase, errReporter *pkg.GruleErrorReporter) *antlr2.GruleV3ParserListener {
+	listener := antlr2.NewGruleV3ParserListener(knowledgeBase, errReporter)
+	listener.Logger = builder.Logger
+	listener.Units = builder.KnowledgeLibrary.Units
+	listener.LiteralSuffixes = builder.KnowledgeLibrary.LiteralSuffixes
+	listener.ScriptLanguages = builder.KnowledgeLibrary.ScriptLanguages
+
+	return listener
+}
+
+// parseGrl parses the GRL text and walks it into the knowledge base, it returns the walked GRL.
+// Syntax errors are collected in the error reporter, the returned error is set only when the text is rejected by the sanitizer.
+// The caller must re-index the working memory of the knowledge base.
+func (builder *RuleBuilder) parseGrl(knowledgeBase *ast.KnowledgeBase, text, origin string, sanitizer *Sanitizer, errReporter *pkg.GruleErrorReporter) (*ast.Grl, error) {
+	is := antlr.NewInputStream(text)
+	lexer := parser.Newgrulev3Lexer(is)
+
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errReporter)
+
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+
+	listener := builder.newListener(knowledgeBase, errReporter)
+
+	psr := parser.Newgrulev3Parser(stream)
+
+	psr.RemoveErrorListeners()
+	psr.AddErrorListener(errReporter)
+
+	psr.BuildParseTrees = true
+	tree := psr.Grl()
+
+	if sanitizer != nil || builder.CycleDetection != CycleDetectionOff || builder.StrictWhenScopes || len(builder.Passes) > 0 {
+		// Build the rules aside first, nothing from a rejected resource may reach the knowledge base.
+		if errReporter.HasError() {
+
+			return nil, errReporter
+		}
+		scratch := builder.KnowledgeLibrary.Sibling().GetKnowledgeBase(knowledgeBase.Name, knowledgeBase.Version)
+		scratchListener := builder.newListener(scratch, errReporter)
+		antlr.ParseTreeWalkerDefault.Walk(scratchListener, tree)
+		if errReporter.HasError() {
+
+			return nil, errReporter
+		}
+		if sanitizer != nil {
+			if err := sanitizer.Check(scratchListener.Grl); err != nil {
+				builder.log().Errorf("GRL rejected by sanitizer. got %v", err)
+
+				return nil, fmt.Errorf("GRL resource %s rejected by sanitizer. got %w", origin, err)
+			}
+		}
+		if builder.StrictWhenScopes {
+			if err := checkWhenScopes(scratchListener.Grl); err != nil {
+				builder.log().Errorf("GRL rejected by strict when scopes. got %v", err)
+
+				return nil, fmt.Errorf("GRL resource %s rejected by strict when scopes. got %w", origin, err)
+			}
+		}
+		if err := builder.checkCycles(knowledgeBase, scratchListener.Grl, origin); err != nil {
+
+			return nil, err
+		}
+		if len(builder.Passes) > 0 {
+
+			return builder.optimizeGrl(knowledgeBase, scratchListener.Grl, origin, errReporter)
+		}
+	}
+
+	antlr.ParseTreeWalkerDefault.Walk(listener, tree)
+
+	return listener.Grl, nil
+}
+
+// optimizeGrl runs the passes over the rules of the GRL walked aside and adds the optimized rules into the knowledge
+// base, as the listener adds the rules it walks.
+func (builder *RuleBuilder) optimizeGrl(knowledgeBase *ast.KnowledgeBase, grl *ast.Grl, origin string, errReporter *pkg.GruleErrorReporter) (*ast.Grl, error) {
+	program, err := ir.Lift(grl)
+	if err == nil {
+		err = ir.Optimize(program, builder.Passes...)
+	}
+	var optimized *ast.Grl
+	if err == nil {
+		optimized, err = ir.Lower(program, knowledgeBase.WorkingMemory)
+	}
+	if err != nil {
+		builder.log().Errorf("GRL optimization failed. got %v", err)
+
+		return nil, fmt.Errorf("error while optimizing GRL resource %s. got %w", origin, err)
+	}
+	optimized.TestEntries = grl.TestEntries
+	for _, ruleEntry := range optimized.RuleEntries {
+		err := knowledgeBase.AddRuleEntry(ruleEntry)
+		if err != nil {
+			errReporter.AddError(err)
+		}
+	}
+	for _, testEntry := range optimized.TestEntries {
+		err := knowledgeBase.AddTestEntry(testEntry)
+		if err != nil {
+			errReporter.AddError(err)
+		}
+	}
+	for _, haltEntry := range optimized.HaltEntries {
+		knowledgeBase.AddHaltEntry(haltEntry)
+	}
+
+	return optimized, nil
+}
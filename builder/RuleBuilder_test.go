@@ -49,3 +49,21 @@ Then
 
 	assert.Equal(t, re.GetSnapshot(), reClone.GetSnapshot())
 }
+
+func TestRuleBuilder_Sources(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Sources", "0.1.1", pkg.NewBytesResource([]byte(`rule A { when true then Retract("A"); }`))))
+	assert.Error(t, rb.BuildRuleFromResource("Sources", "0.1.1", pkg.NewBytesResource([]byte(`rule B { when true then `))))
+	assert.NoError(t, rb.BuildRuleFromResource("Sources", "0.1.1", pkg.NewBytesResource([]byte(`rule C { when true then Retract("C"); }`))))
+
+	// the resources whose rules failed to build are not recorded.
+	kb := lib.GetKnowledgeBase("Sources", "0.1.1")
+	if assert.Len(t, kb.Sources, 2) {
+		assert.Equal(t, "Byte array resources 39 bytes", kb.Sources[0].Origin)
+		assert.NotEqual(t, kb.Sources[0].Checksum, kb.Sources[1].Checksum)
+	}
+	instance, err := lib.NewKnowledgeBaseInstance("Sources", "0.1.1")
+	assert.NoError(t, err)
+	assert.Equal(t, kb.Sources, instance.Sources)
+}
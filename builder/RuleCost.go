@@ -0,0 +1,184 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// RuleCost is the cost of a rule estimated from its GRL, without evaluating it. The when scope, the then scope and
+// the else scope are counted together.
+type RuleCost struct {
+	Rule string `json:"rule"`
+	// FieldAccesses counts the fields, array elements and map values read or assigned, Order.Lines[0].Price is three.
+	FieldAccesses int `json:"fieldAccesses"`
+	// FunctionCalls counts the calls of the built-in functions and of the methods of the facts.
+	FunctionCalls int `json:"functionCalls"`
+	// ExpressionDepth is the nesting of the deepest expression, the operators, parentheses and call arguments
+	// included. A single variable or constant is one.
+	ExpressionDepth int `json:"expressionDepth"`
+}
+
+// CostLimits are the costs above which a rule is reported as expensive. Zero is no limit.
+type CostLimits struct {
+	FieldAccesses   int
+	FunctionCalls   int
+	ExpressionDepth int
+}
+
+// Check returns an error naming every limit the cost of the rule exceeds, nil if it exceeds none.
+func (limits CostLimits) Check(cost RuleCost) error {
+	exceeded := make([]string, 0)
+	if limits.FieldAccesses > 0 && cost.FieldAccesses > limits.FieldAccesses {
+		exceeded = append(exceeded, fmt.Sprintf("%d field accesses over %d", cost.FieldAccesses, limits.FieldAccesses))
+	}
+	if limits.FunctionCalls > 0 && cost.FunctionCalls > limits.FunctionCalls {
+		exceeded = append(exceeded, fmt.Sprintf("%d function calls over %d", cost.FunctionCalls, limits.FunctionCalls))
+	}
+	if limits.ExpressionDepth > 0 && cost.ExpressionDepth > limits.ExpressionDepth {
+		exceeded = append(exceeded, fmt.Sprintf("expression depth %d over %d", cost.ExpressionDepth, limits.ExpressionDepth))
+	}
+	if len(exceeded) == 0 {
+
+		return nil
+	}
+
+	return fmt.Errorf("rule %s is expensive : %s", cost.Rule, strings.Join(exceeded, ", "))
+}
+
+// EstimateCosts estimates the cost of every rule, sorted by the rule names.
+func EstimateCosts(rules []*ast.RuleEntry) []RuleCost {
+	costs := make([]RuleCost, 0, len(rules))
+	for _, entry := range rules {
+		costs = append(costs, EstimateCost(entry))
+	}
+	sort.Slice(costs, func(i, j int) bool {
+
+		return costs[i].Rule < costs[j].Rule
+	})
+
+	return costs
+}
+
+// EstimateCost estimates the cost of the rule. A script then scope is not counted.
+func EstimateCost(entry *ast.RuleEntry) RuleCost {
+	scan := &costScan{cost: RuleCost{Rule: entry.RuleName}}
+	if entry.WhenScope != nil {
+		scan.root(scan.expression(entry.WhenScope.Expression))
+	}
+	for _, thenExpr := range entry.ThenExpressions() {
+		if assignment := thenExpr.Assignment; assignment != nil {
+			scan.root(scan.variable(assignment.Variable))
+			scan.root(scan.expression(assignment.Expression))
+			if match := assignment.Match; match != nil {
+				scan.root(scan.expression(match.Subject))
+				for _, arm := range match.Arms {
+					scan.root(scan.expression(arm.Pattern))
+					scan.root(scan.expression(arm.Value))
+				}
+			}
+		}
+		if collect := thenExpr.Collect; collect != nil {
+			scan.root(scan.expression(collect.Source))
+			scan.root(scan.expression(collect.Condition))
+		}
+		if switchStmt := thenExpr.Switch; switchStmt != nil {
+			scan.root(scan.expression(switchStmt.Subject))
+			for _, switchCase := range switchStmt.Cases {
+				for _, value := range switchCase.Values {
+					scan.root(scan.expression(value))
+				}
+			}
+		}
+		scan.root(scan.atom(thenExpr.ExpressionAtom))
+	}
+
+	return scan.cost
+}
+
+// costScan counts the field accesses and the function calls of a rule, the walking functions return the depth of
+// the walked node.
+type costScan struct {
+	cost RuleCost
+}
+
+func (scan *costScan) root(depth int) {
+	if depth > scan.cost.ExpressionDepth {
+		scan.cost.ExpressionDepth = depth
+	}
+}
+
+func (scan *costScan) expression(expr *ast.Expression) int {
+	if expr == nil {
+
+		return 0
+	}
+	depth := max(scan.expression(expr.LeftExpression), scan.expression(expr.RightExpression),
+		scan.expression(expr.SingleExpression), scan.expression(expr.Tolerance))
+	if expr.ExpressionAtom != nil {
+
+		return max(depth, scan.atom(expr.ExpressionAtom))
+	}
+
+	return depth + 1
+}
+
+func (scan *costScan) atom(atom *ast.ExpressionAtom) int {
+	if atom == nil {
+
+		return 0
+	}
+	depth := 1
+	if atom.FunctionCall != nil {
+		scan.cost.FunctionCalls++
+		if atom.FunctionCall.ArgumentList != nil {
+			for _, arg := range atom.FunctionCall.ArgumentList.Arguments {
+				depth = max(depth, scan.expression(arg)+1)
+			}
+		}
+	}
+	if atom.ArrayMapSelector != nil {
+		scan.cost.FieldAccesses++
+		depth = max(depth, scan.expression(atom.ArrayMapSelector.Expression)+1)
+	}
+	if atom.Quantifier != nil {
+		depth = max(depth, scan.expression(atom.Quantifier.Source)+1, scan.expression(atom.Quantifier.Predicate)+1)
+	}
+	if len(atom.VariableName) > 0 {
+		scan.cost.FieldAccesses++
+	}
+
+	return max(depth, scan.variable(atom.Variable), scan.atom(atom.ExpressionAtom))
+}
+
+// variable counts the members selected along the path of the variable, the fact it starts from is not a field.
+func (scan *costScan) variable(variable *ast.Variable) int {
+	depth := 0
+	for ; variable != nil; variable = variable.Variable {
+		depth = max(depth, 1)
+		if variable.ArrayMapSelector != nil {
+			scan.cost.FieldAccesses++
+			depth = max(depth, scan.expression(variable.ArrayMapSelector.Expression)+1)
+		} else if variable.Variable != nil {
+			scan.cost.FieldAccesses++
+		}
+	}
+
+	return depth
+}
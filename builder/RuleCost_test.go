@@ -0,0 +1,61 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const costRules = `
+rule Cheap { when Fact.Done == false then Fact.Done = true; }
+rule Expensive {
+	when
+		Fact.Customer.Orders[0].Total > 100 && (Fact.Score(Fact.Customer.Name) > 3 || Fact.Customer.Tags.Len() > 2)
+	then
+		Fact.Customer.Discount = Fact.Rate(Fact.Customer.Tier) * 2;
+		Retract("Expensive");
+	else {
+		Fact.Customer.Discount = 0;
+	}
+}
+`
+
+func TestEstimateCosts(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, NewRuleBuilder(lib).BuildRuleFromResource("Cost", "1.0.0", pkg.NewBytesResource([]byte(costRules))))
+	kb := lib.GetKnowledgeBase("Cost", "1.0.0")
+	rules := make([]*ast.RuleEntry, 0)
+	for _, entry := range kb.RuleEntries {
+		rules = append(rules, entry)
+	}
+
+	costs := EstimateCosts(rules)
+	assert.Equal(t, []RuleCost{
+		{Rule: "Cheap", FieldAccesses: 2, FunctionCalls: 0, ExpressionDepth: 2},
+		{Rule: "Expensive", FieldAccesses: 14, FunctionCalls: 4, ExpressionDepth: 6},
+	}, costs)
+
+	limits := CostLimits{FieldAccesses: 10, FunctionCalls: 4, ExpressionDepth: 4}
+	assert.NoError(t, limits.Check(costs[0]))
+	err := limits.Check(costs[1])
+	if assert.Error(t, err) {
+		assert.Equal(t, "rule Expensive is expensive : 14 field accesses over 10, expression depth 6 over 4", err.Error())
+	}
+	assert.NoError(t, CostLimits{}.Check(costs[1]))
+}
@@ -0,0 +1,460 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Sanitizer validates GRL coming from untrusted sources before it is added into a KnowledgeBase.
+// Set it to RuleBuilder.Sanitizer, every rule of a resource is then checked and the whole resource is
+// rejected if any rule uses a construct the sanitizer does not allow.
+//
+// Function names are the built-in function names such as "Retract" or "Now". Methods are named by the GRL
+// text of their receiver followed by the method name, such as "User.Save", or "*.Save" for any receiver.
+type Sanitizer struct {
+	// DisallowFunctionCalls rejects any function or method call, except Retract of the rule itself.
+	DisallowFunctionCalls bool
+	// AllowedFunctions, when not empty, is the only functions and methods a rule may call.
+	AllowedFunctions []string
+	// DeniedFunctions are functions and methods a rule may not call.
+	DeniedFunctions []string
+	// ReadOnlyFacts are facts, or fact members such as "User.Role", a rule may not assign to.
+	ReadOnlyFacts []string
+	// DisallowSelfTriggering rejects rules that assign a value their own when scope reads,
+	// unless they retract themselves or have max-fires. Such rules may fire over and over until MaxCycle.
+	DisallowSelfTriggering bool
+	// MaxRules limits the number of rules in a single resource, zero means unlimited.
+	MaxRules int
+	// AllowScripts accepts rules whose then scope is a script. A script can not be checked by the sanitizer,
+	// it may call and assign anything the script language allows.
+	AllowScripts bool
+	// DisallowedFeatures are the language constructs the rules may not use.
+	DisallowedFeatures []LanguageFeature
+}
+
+// LanguageFeature is a GRL construct that can be disallowed by a Sanitizer.
+type LanguageFeature string
+
+const (
+	// FeatureMethodCalls is calling the methods of facts, such as User.Save(). Built-in functions remain allowed.
+	FeatureMethodCalls LanguageFeature = "method-calls"
+	// FeatureCompoundAssignments is the +=, -=, *= and /= assignments.
+	FeatureCompoundAssignments LanguageFeature = "compound-assignments"
+	// FeatureMatch is assigning the value of a match expression.
+	FeatureMatch LanguageFeature = "match"
+	// FeatureCollect is collecting the matching elements of an array into a new fact.
+	FeatureCollect LanguageFeature = "collect"
+	// FeatureSwitch is branching the then scope with a switch statement.
+	FeatureSwitch LanguageFeature = "switch"
+	// FeatureQuantifiers is testing the elements of an array with exists and forall.
+	FeatureQuantifiers LanguageFeature = "quantifiers"
+	// FeatureSelectors is selecting array elements and map values, such as User.Tags[0].
+	FeatureSelectors LanguageFeature = "selectors"
+	// FeatureSalience is declaring the salience of a rule.
+	FeatureSalience LanguageFeature = "salience"
+	// FeatureMaxFires is declaring the max-fires of a rule.
+	FeatureMaxFires LanguageFeature = "max-fires"
+	// FeatureCooldown is declaring the cooldown of a rule.
+	FeatureCooldown LanguageFeature = "cooldown"
+	// FeatureTests is declaring test blocks next to the rules.
+	FeatureTests LanguageFeature = "tests"
+	// FeatureHalts is declaring halt conditions next to the rules.
+	FeatureHalts LanguageFeature = "halts"
+)
+
+// Check validates all rule entries and halt conditions of the GRL and returns every violation found.
+func (s *Sanitizer) Check(grl *ast.Grl) error {
+	ruleNames := make([]string, 0, len(grl.RuleEntries))
+	for name := range grl.RuleEntries {
+		ruleNames = append(ruleNames, name)
+	}
+	sort.Strings(ruleNames)
+
+	errs := make([]error, 0)
+	if s.MaxRules > 0 && len(ruleNames) > s.MaxRules {
+		errs = append(errs, fmt.Errorf("GRL contains %d rules, only %d are allowed", len(ruleNames), s.MaxRules))
+	}
+	for _, name := range ruleNames {
+		errs = append(errs, s.checkRuleEntry(grl.RuleEntries[name])...)
+	}
+	if len(grl.TestEntries) > 0 && s.disallows(FeatureTests) {
+		errs = append(errs, fmt.Errorf("GRL contains %d test blocks, %s are not allowed", len(grl.TestEntries), FeatureTests))
+	}
+	if len(grl.HaltEntries) > 0 && s.disallows(FeatureHalts) {
+		errs = append(errs, fmt.Errorf("GRL contains %d halt conditions, %s are not allowed", len(grl.HaltEntries), FeatureHalts))
+	}
+	for _, entry := range grl.HaltEntries {
+		halt := &sanitizerScan{}
+		halt.scanExpression(entry.Expression)
+		for _, call := range halt.calls {
+			name := callName(call)
+			if s.DisallowFunctionCalls {
+				errs = append(errs, fmt.Errorf("halt condition %s calls %s, function calls are not allowed", entry.GrlText, name))
+			} else if !s.isFunctionAllowed(call) {
+				errs = append(errs, fmt.Errorf("halt condition %s calls %s which is not allowed", entry.GrlText, name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sanitizerScan collects everything the sanitizer needs to know about a part of a rule.
+type sanitizerScan struct {
+	calls       []*ast.ExpressionAtom
+	variables   []string
+	assignments []string
+	selectors   []string
+	matches     int
+	collects    int
+	switches    int
+	compounds   int
+	quantifiers int
+}
+
+func (s *Sanitizer) checkRuleEntry(entry *ast.RuleEntry) []error {
+	errs := make([]error, 0)
+	when := &sanitizerScan{}
+	if entry.WhenScope != nil {
+		when.scanExpression(entry.WhenScope.Expression)
+	}
+	if !s.AllowScripts && entry.ThenScope != nil && entry.ThenScope.Script != nil {
+		errs = append(errs, fmt.Errorf("rule %s has a %s script then scope, scripts are not allowed", entry.RuleName, entry.ThenScope.Script.Language))
+	}
+	then := &sanitizerScan{}
+	for _, thenExpr := range entry.ThenExpressions() {
+		if thenExpr.Assignment != nil {
+			if thenExpr.Assignment.Match != nil {
+				then.matches++
+			}
+			if !thenExpr.Assignment.IsAssign {
+				then.compounds++
+			}
+			then.assignments = append(then.assignments, thenExpr.Assignment.Variable.GrlText)
+			then.scanVariable(thenExpr.Assignment.Variable)
+			then.scanExpression(thenExpr.Assignment.Expression)
+			then.scanMatch(thenExpr.Assignment.Match)
+		}
+		if thenExpr.Collect != nil {
+			then.collects++
+			then.assignments = append(then.assignments, thenExpr.Collect.Name)
+			then.scanCollect(thenExpr.Collect)
+		}
+		if thenExpr.Switch != nil {
+			then.switches++
+			then.scanSwitch(thenExpr.Switch)
+		}
+		then.scanAtom(thenExpr.ExpressionAtom)
+	}
+
+	selfRetract := false
+	for _, call := range append(when.calls, then.calls...) {
+		if isRetractOf(call, entry.RuleName) {
+			selfRetract = true
+
+			continue
+		}
+		name := callName(call)
+		if s.DisallowFunctionCalls {
+			errs = append(errs, fmt.Errorf("rule %s calls %s, function calls are not allowed", entry.RuleName, name))
+		} else if !s.isFunctionAllowed(call) {
+			errs = append(errs, fmt.Errorf("rule %s calls %s which is not allowed", entry.RuleName, name))
+		}
+	}
+
+	for _, target := range then.assignments {
+		for _, readOnly := range s.ReadOnlyFacts {
+			if isSameOrMember(target, readOnly) {
+				errs = append(errs, fmt.Errorf("rule %s assigns to %s which is read only", entry.RuleName, target))
+
+				break
+			}
+		}
+	}
+
+	if s.DisallowSelfTriggering && !selfRetract && entry.MaxFires == 0 {
+	assignments:
+		for _, target := range then.assignments {
+			for _, read := range when.variables {
+				if isSameOrMember(read, target) || isSameOrMember(target, read) {
+					errs = append(errs, fmt.Errorf("rule %s assigns to %s that its when scope reads, without retracting itself or max-fires", entry.RuleName, target))
+
+					break assignments
+				}
+			}
+		}
+	}
+
+	return append(errs, s.checkFeatures(entry, when, then)...)
+}
+
+// disallows tells if the language feature is disallowed.
+func (s *Sanitizer) disallows(feature LanguageFeature) bool {
+	for _, disallowed := range s.DisallowedFeatures {
+		if disallowed == feature {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkFeatures returns an error for every disallowed language feature the rule uses.
+func (s *Sanitizer) checkFeatures(entry *ast.RuleEntry, when, then *sanitizerScan) []error {
+	if len(s.DisallowedFeatures) == 0 {
+
+		return nil
+	}
+	used := make([]string, 0)
+	use := func(feature LanguageFeature, what string) {
+		if s.disallows(feature) {
+			used = append(used, fmt.Sprintf("%s (%s)", feature, what))
+		}
+	}
+	for _, call := range append(when.calls, then.calls...) {
+		if call.ExpressionAtom != nil {
+			use(FeatureMethodCalls, callName(call))
+		}
+	}
+	if then.compounds > 0 {
+		use(FeatureCompoundAssignments, fmt.Sprintf("%d assignments", then.compounds))
+	}
+	if then.matches > 0 {
+		use(FeatureMatch, fmt.Sprintf("%d assignments", then.matches))
+	}
+	if then.collects > 0 {
+		use(FeatureCollect, fmt.Sprintf("%d collects", then.collects))
+	}
+	if then.switches > 0 {
+		use(FeatureSwitch, fmt.Sprintf("%d switches", then.switches))
+	}
+	if quantifiers := when.quantifiers + then.quantifiers; quantifiers > 0 {
+		use(FeatureQuantifiers, fmt.Sprintf("%d quantifiers", quantifiers))
+	}
+	for _, selector := range append(when.selectors, then.selectors...) {
+		use(FeatureSelectors, selector)
+	}
+	if entry.Salience != 0 {
+		use(FeatureSalience, fmt.Sprintf("salience %d", entry.Salience))
+	}
+	if entry.MaxFires != 0 {
+		use(FeatureMaxFires, fmt.Sprintf("max-fires %d", entry.MaxFires))
+	}
+	if entry.Cooldown != 0 {
+		use(FeatureCooldown, fmt.Sprintf("cooldown %s", entry.Cooldown))
+	}
+
+	errs := make([]error, 0, len(used))
+	for _, feature := range used {
+		errs = append(errs, fmt.Errorf("rule %s uses %s, which is not allowed", entry.RuleName, feature))
+	}
+
+	return errs
+}
+
+func (s *Sanitizer) isFunctionAllowed(call *ast.ExpressionAtom) bool {
+	for _, denied := range s.DeniedFunctions {
+		if callMatches(call, denied) {
+
+			return false
+		}
+	}
+	if len(s.AllowedFunctions) == 0 {
+
+		return true
+	}
+	for _, allowed := range s.AllowedFunctions {
+		if callMatches(call, allowed) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// callName returns the name of a function call atom as used in the sanitizer lists.
+func callName(call *ast.ExpressionAtom) string {
+	if call.ExpressionAtom == nil {
+
+		return call.FunctionCall.FunctionName
+	}
+
+	return call.ExpressionAtom.GrlText + "." + call.FunctionCall.FunctionName
+}
+
+func callMatches(call *ast.ExpressionAtom, pattern string) bool {
+	if call.ExpressionAtom != nil && strings.HasPrefix(pattern, "*.") {
+
+		return pattern[2:] == call.FunctionCall.FunctionName
+	}
+
+	return pattern == callName(call)
+}
+
+// isRetractOf tells if the call is Retract of the specified rule.
+func isRetractOf(call *ast.ExpressionAtom, ruleName string) bool {
+	if call.ExpressionAtom != nil || call.FunctionCall.FunctionName != "Retract" || call.FunctionCall.ArgumentList == nil {
+
+		return false
+	}
+	args := call.FunctionCall.ArgumentList.Arguments
+	if len(args) != 1 || args[0].ExpressionAtom == nil || args[0].ExpressionAtom.Constant == nil {
+
+		return false
+	}
+	value := args[0].ExpressionAtom.Constant.Value
+
+	return value.IsValid() && value.Kind() == reflect.String && value.String() == ruleName
+}
+
+// isSameOrMember tells if path is the same as base, or a member or element of it.
+func isSameOrMember(path, base string) bool {
+	if !strings.HasPrefix(path, base) {
+
+		return false
+	}
+
+	return len(path) == len(base) || path[len(base)] == '.' || path[len(base)] == '['
+}
+
+func (scan *sanitizerScan) scanExpression(expr *ast.Expression) {
+	if expr == nil {
+
+		return
+	}
+	scan.scanExpression(expr.LeftExpression)
+	scan.scanExpression(expr.RightExpression)
+	scan.scanExpression(expr.SingleExpression)
+	scan.scanExpression(expr.Tolerance)
+	scan.scanAtom(expr.ExpressionAtom)
+}
+
+func (scan *sanitizerScan) scanMatch(match *ast.MatchExpression) {
+	if match == nil {
+
+		return
+	}
+	scan.scanExpression(match.Subject)
+	for _, arm := range match.Arms {
+		scan.scanExpression(arm.Pattern)
+		scan.scanExpression(arm.Value)
+	}
+}
+
+// scanSwitch scans the subject and the case values of a switch, the then expressions of its cases are scanned
+// as those of the rule.
+func (scan *sanitizerScan) scanSwitch(switchStmt *ast.SwitchStatement) {
+	if switchStmt == nil {
+
+		return
+	}
+	scan.scanExpression(switchStmt.Subject)
+	for _, switchCase := range switchStmt.Cases {
+		for _, value := range switchCase.Values {
+			scan.scanExpression(value)
+		}
+	}
+}
+
+// scanCollect scans the source and condition of a collect. The item the condition reads is bound by the
+// collect itself, it is not recorded as a variable the rule reads.
+func (scan *sanitizerScan) scanCollect(collect *ast.CollectStatement) {
+	if collect == nil {
+
+		return
+	}
+	scan.scanExpression(collect.Source)
+	condition := &sanitizerScan{}
+	condition.scanExpression(collect.Condition)
+	scan.calls = append(scan.calls, condition.calls...)
+	scan.selectors = append(scan.selectors, condition.selectors...)
+	scan.quantifiers += condition.quantifiers
+	for _, variable := range condition.variables {
+		if !isSameOrMember(variable, ast.CollectItemName) {
+			scan.variables = append(scan.variables, variable)
+		}
+	}
+}
+
+// scanQuantifier scans the source and predicate of an exists or a forall. The element the predicate reads is bound
+// by the quantifier itself, it is not recorded as a variable the rule reads.
+func (scan *sanitizerScan) scanQuantifier(quantifier *ast.Quantifier) {
+	if quantifier == nil {
+
+		return
+	}
+	scan.quantifiers++
+	scan.scanExpression(quantifier.Source)
+	predicate := &sanitizerScan{}
+	predicate.scanExpression(quantifier.Predicate)
+	scan.calls = append(scan.calls, predicate.calls...)
+	scan.selectors = append(scan.selectors, predicate.selectors...)
+	scan.quantifiers += predicate.quantifiers
+	for _, variable := range predicate.variables {
+		if !isSameOrMember(variable, quantifier.Name) {
+			scan.variables = append(scan.variables, variable)
+		}
+	}
+}
+
+func (scan *sanitizerScan) scanAtom(atom *ast.ExpressionAtom) {
+	if atom == nil {
+
+		return
+	}
+	scan.scanQuantifier(atom.Quantifier)
+	if atom.FunctionCall != nil {
+		scan.calls = append(scan.calls, atom)
+		if atom.FunctionCall.ArgumentList != nil {
+			for _, arg := range atom.FunctionCall.ArgumentList.Arguments {
+				scan.scanExpression(arg)
+			}
+		}
+	}
+	if atom.ArrayMapSelector != nil {
+		scan.selectors = append(scan.selectors, atom.GrlText)
+		scan.scanExpression(atom.ArrayMapSelector.Expression)
+	}
+	if len(atom.VariableName) > 0 {
+		scan.variables = append(scan.variables, atom.GrlText)
+	}
+	scan.scanVariable(atom.Variable)
+	scan.scanAtom(atom.ExpressionAtom)
+}
+
+// scanVariable records the full path of the variable only, the receivers it is selected from are not
+// read on their own. Expressions used as array or map selectors along the path are scanned.
+func (scan *sanitizerScan) scanVariable(variable *ast.Variable) {
+	if variable == nil {
+
+		return
+	}
+	scan.variables = append(scan.variables, variable.GrlText)
+	for ; variable != nil; variable = variable.Variable {
+		if variable.ArrayMapSelector != nil {
+			scan.selectors = append(scan.selectors, variable.GrlText)
+			scan.scanExpression(variable.ArrayMapSelector.Expression)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildSanitized(sanitizer *Sanitizer, grl string) (*ast.KnowledgeLibrary, error) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	rb.Sanitizer = sanitizer
+
+	return lib, rb.BuildRuleFromResource("Sanitized", "0.0.1", pkg.NewBytesResource([]byte(grl)))
+}
+
+func TestSanitizer_FunctionCalls(t *testing.T) {
+	grl := `
+rule CallRule "Calls functions" {
+	when
+		User.Name.ToUpper() == "ADMIN" && Now() > 0
+	then
+		User.Delete();
+		Retract("CallRule");
+}`
+	_, err := buildSanitized(&Sanitizer{}, grl)
+	assert.NoError(t, err)
+
+	lib, err := buildSanitized(&Sanitizer{DisallowFunctionCalls: true}, grl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule CallRule calls User.Name.ToUpper, function calls are not allowed")
+	assert.Contains(t, err.Error(), "rule CallRule calls User.Delete, function calls are not allowed")
+	assert.NotContains(t, err.Error(), "Retract")
+	assert.Empty(t, lib.GetKnowledgeBase("Sanitized", "0.0.1").RuleEntries)
+
+	_, err = buildSanitized(&Sanitizer{DeniedFunctions: []string{"*.Delete"}}, grl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule CallRule calls User.Delete which is not allowed")
+
+	_, err = buildSanitized(&Sanitizer{AllowedFunctions: []string{"*.ToUpper", "Now"}}, grl)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "Now")
+
+	_, err = buildSanitized(&Sanitizer{AllowedFunctions: []string{"*.ToUpper", "Now", "User.Delete"}}, grl)
+	assert.NoError(t, err)
+}
+
+func TestSanitizer_Assignments(t *testing.T) {
+	grl := `
+rule Promote "Promotes the user" {
+	when
+		User.Points > 100
+	then
+		User.Role.Name = "gold";
+		Retract("Promote");
+}`
+	_, err := buildSanitized(&Sanitizer{ReadOnlyFacts: []string{"Account", "User.Roles"}}, grl)
+	assert.NoError(t, err)
+
+	_, err = buildSanitized(&Sanitizer{ReadOnlyFacts: []string{"User.Role"}}, grl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule Promote assigns to User.Role.Name which is read only")
+}
+
+func TestSanitizer_SelfTriggering(t *testing.T) {
+	sanitizer := &Sanitizer{DisallowSelfTriggering: true}
+	_, err := buildSanitized(sanitizer, `
+rule Loop "Keeps firing" {
+	when
+		User.Points > 100
+	then
+		User.Points = User.Points + 1;
+}`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule Loop assigns to User.Points that its when scope reads")
+
+	_, err = buildSanitized(sanitizer, `
+rule Once "Fires once" max-fires 1 {
+	when
+		User.Points > 100
+	then
+		User.Points = User.Points + 1;
+}
+rule Retracted "Retracts itself" {
+	when
+		User.Points > 100
+	then
+		User.Points = User.Points + 1;
+		Retract("Retracted");
+}
+rule Other "Changes something else" {
+	when
+		User.Points > 100
+	then
+		User.Level = 2;
+}`)
+	assert.NoError(t, err)
+}
+
+func TestSanitizer_MaxRules(t *testing.T) {
+	_, err := buildSanitized(&Sanitizer{MaxRules: 1}, `
+rule One { when true then Retract("One"); }
+rule Two { when true then Retract("Two"); }`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GRL contains 2 rules, only 1 are allowed")
+}
+
+func TestSanitizer_DisallowedFeatures(t *testing.T) {
+	grl := `
+rule Tiered "Uses many features" salience 10 max-fires 1 cooldown 1h {
+	when
+		User.Tags[0] == "vip" && User.Name.HasPrefix("a") && exists(User.Tags, t -> t == "gold")
+	then
+		User.Points += 10;
+		User.Tier = match User.Points { >= 100 => "gold", _ => "silver" };
+		switch User.Tier { case "gold" { User.Level = 2; } };
+}
+test "vip gets points" {
+	expect User.Points > 0;
+}
+halt when User.Tier == "gold";`
+	_, err := buildSanitized(&Sanitizer{}, grl)
+	assert.NoError(t, err)
+
+	_, err = buildSanitized(&Sanitizer{DisallowedFeatures: []LanguageFeature{
+		FeatureMethodCalls, FeatureCompoundAssignments, FeatureMatch, FeatureSwitch, FeatureQuantifiers, FeatureSelectors,
+		FeatureSalience, FeatureMaxFires, FeatureCooldown, FeatureTests, FeatureHalts,
+	}}, grl)
+	assert.Error(t, err)
+	for _, message := range []string{
+		"rule Tiered uses method-calls (User.Name.HasPrefix), which is not allowed",
+		"rule Tiered uses compound-assignments (1 assignments), which is not allowed",
+		"rule Tiered uses match (1 assignments), which is not allowed",
+		"rule Tiered uses switch (1 switches), which is not allowed",
+		"rule Tiered uses quantifiers (1 quantifiers), which is not allowed",
+		"rule Tiered uses selectors (User.Tags[0]), which is not allowed",
+		"rule Tiered uses salience (salience 10), which is not allowed",
+		"rule Tiered uses max-fires (max-fires 1), which is not allowed",
+		"rule Tiered uses cooldown (cooldown 1h0m0s), which is not allowed",
+		"GRL contains 1 test blocks, tests are not allowed",
+		"GRL contains 1 halt conditions, halts are not allowed",
+	} {
+		assert.Contains(t, err.Error(), message)
+	}
+
+	// each bundle is built with the sanitizer of its team.
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	constrained := rb.WithSanitizer(&Sanitizer{DisallowedFeatures: []LanguageFeature{FeatureMatch}})
+	assert.Error(t, constrained.BuildRuleFromResource("Product", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	assert.NoError(t, rb.BuildRuleFromResource("Platform", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	assert.Nil(t, rb.Sanitizer)
+}
@@ -0,0 +1,101 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type SchemaLine struct {
+	Price    float64
+	Quantity int
+}
+
+type SchemaOrder struct {
+	Customer string
+	Lines    []*SchemaLine
+	Placed   time.Time
+	Extra    interface{}
+	Discount float64
+}
+
+func (order *SchemaOrder) Total() float64 {
+
+	return 0
+}
+
+const schemaRules = `
+rule Discount "discounts the large orders" {
+	when
+		Order.Total() > 100 && Order.Customer.HasPrefix("VIP") && Order.Lines[0].Price > 10 && Order.Extra.Whatever == 1
+	then
+		Order.Discount = 10;
+		Order.Discont = 10;
+		Log(Order.Placed.Format("2006"));
+}
+rule Loyalty "rewards the loyal customers" {
+	when
+		Customer.Years > 3 && Order.Grand() > 10 && Order.Placed.Yesterday() && exists(Order.Lines, l -> l.Price > 10 && l.Weight > 1)
+	then
+		Order.Discount = Order.Lines[0].Prize;
+		collect Expensive from Order.Lines where item.Price > 10 && item.Cost > 1;
+		Notify(Expensive.Len());
+}
+halt when Order.Closed
+`
+
+func TestRuleBuilder_Schema(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	rb.Schema = ast.NewFactSchema()
+	rb.Schema.Add("Order", &SchemaOrder{})
+	assert.NoError(t, rb.BuildRuleFromResource("Schema", "0.0.1", pkg.NewBytesResource([]byte(schemaRules))))
+
+	kb, err := lib.NewKnowledgeBaseInstance("Schema", "0.0.1")
+	assert.NoError(t, err)
+	unresolved := kb.UnresolvedReferences()
+	names := make([]string, 0, len(unresolved))
+	for _, reference := range unresolved {
+		names = append(names, reference.String())
+	}
+	assert.Equal(t, []string{
+		"fact Customer referenced by Loyalty",
+		"function Notify referenced by Loyalty",
+		"field Order.Closed referenced by halt Order.Closed",
+		"field Order.Discont referenced by Discount",
+		"function Order.Grand referenced by Loyalty",
+		"field Order.Lines[0].Prize referenced by Loyalty",
+		"function Order.Placed.Yesterday referenced by Loyalty",
+		"field item.Cost referenced by Loyalty",
+		"field l.Weight referenced by Loyalty",
+	}, names)
+	assert.Equal(t, ast.ReferenceFact, unresolved[0].Kind)
+
+	// a dynamic fact is not checked.
+	rb.Schema.Add("Customer", nil)
+	assert.Len(t, kb.UnresolvedReferences(), 8)
+
+	// without a schema, every fact is unresolved.
+	kb.Schema = nil
+	unresolved = kb.UnresolvedReferences()
+	assert.Equal(t, "Customer", unresolved[0].Name)
+	assert.Equal(t, "Order", unresolved[2].Name)
+	assert.Equal(t, []string{"Discount", "Loyalty", "halt Order.Closed"}, unresolved[2].Referrers)
+}
@@ -0,0 +1,211 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// BuildRuleFromReader will load rules from a reader one entry at a time, so a very large GRL text is never held
+// in memory as a whole, only the AST of the rules is kept.
+// Every entry is added into the knowledge base as soon as it is parsed. If an entry has an error, the entries before it
+// stay in the knowledge base and the rest of the reader is not read.
+// If the Sanitizer is set, every entry is checked before it is added and MaxRules counts the rules of the whole reader.
+func (builder *RuleBuilder) BuildRuleFromReader(name, version string, reader io.Reader) error {
+	startTime := time.Now()
+	knowledgeBase := builder.KnowledgeLibrary.GetKnowledgeBase(name, version)
+	if knowledgeBase == nil {
+
+		return fmt.Errorf("KnowledgeBase %s:%s is not in this library", name, version)
+	}
+	if builder.Schema != nil {
+		knowledgeBase.Schema = builder.Schema
+	}
+
+	var sanitizer *Sanitizer
+	if builder.Sanitizer != nil {
+		chunkSanitizer := *builder.Sanitizer
+		chunkSanitizer.MaxRules = 0
+		sanitizer = &chunkSanitizer
+	}
+
+	chunker := newGrlChunker(reader)
+	defer knowledgeBase.WorkingMemory.IndexVariables()
+	ruleCount := 0
+	for {
+		text, line, err := chunker.next()
+		if errors.Is(err, io.EOF) {
+
+			break
+		}
+		if err != nil {
+
+			return err
+		}
+		errReporter := &pkg.GruleErrorReporter{
+			Errors:     make([]error, 0),
+			LineOffset: line - 1,
+		}
+		origin := fmt.Sprintf("reader line %d", line)
+		grl, err := builder.parseGrl(knowledgeBase, text, origin, sanitizer, errReporter)
+		if err != nil {
+
+			return err
+		}
+		if errReporter.HasError() {
+			builder.log().Errorf("GRL syntax error at %s. got %s", origin, errReporter.Error())
+			for i, err := range errReporter.Errors {
+				builder.log().Errorf("%d : %s", i, err.Error())
+			}
+
+			return errReporter
+		}
+		ruleCount += len(grl.RuleEntries)
+		if builder.Sanitizer != nil && builder.Sanitizer.MaxRules > 0 && ruleCount > builder.Sanitizer.MaxRules {
+
+			return fmt.Errorf("GRL resource %s rejected by sanitizer. got %w", origin, fmt.Errorf("GRL contains more than %d rules", builder.Sanitizer.MaxRules))
+		}
+	}
+
+	builder.log().Debugf("Loading %d rules from reader success. Time taken %d ms", ruleCount, time.Now().Sub(startTime).Nanoseconds()/1e6)
+
+	return nil
+}
+
+// grlChunker splits a GRL text into pieces that each ends with a complete top level entry, such as a rule.
+// It only tracks braces, strings, comments and scripts, the pieces are validated by the parser.
+type grlChunker struct {
+	reader *bufio.Reader
+	line   int
+	done   bool
+}
+
+func newGrlChunker(reader io.Reader) *grlChunker {
+
+	return &grlChunker{
+		reader: bufio.NewReader(reader),
+		line:   1,
+	}
+}
+
+// next returns the next piece of GRL text and the line it starts at, or io.EOF when the reader is exhausted.
+func (c *grlChunker) next() (string, int, error) {
+	if c.done {
+
+		return "", 0, io.EOF
+	}
+	const (
+		normal = iota
+		doubleQuoted
+		singleQuoted
+		lineComment
+		blockComment
+		script
+	)
+	var buff strings.Builder
+	startLine := c.line
+	state := normal
+	depth := 0
+	escaped := false
+	// ticks counts the consecutive backticks, three of them open or close a script.
+	ticks := 0
+	var previous rune
+	for {
+		r, _, err := c.reader.ReadRune()
+		if errors.Is(err, io.EOF) {
+			c.done = true
+			if len(strings.TrimSpace(buff.String())) == 0 {
+
+				return "", 0, io.EOF
+			}
+
+			// whatever is left is given to the parser, it reports unbalanced braces or stray text.
+			return buff.String(), startLine, nil
+		}
+		if err != nil {
+
+			return "", 0, err
+		}
+		buff.WriteRune(r)
+		if r == '\n' {
+			c.line++
+		}
+		if r == '`' {
+			ticks++
+		} else {
+			ticks = 0
+		}
+
+		switch state {
+		case normal:
+			switch {
+			case r == '"':
+				state = doubleQuoted
+			case r == '\'':
+				state = singleQuoted
+			case r == '/' && previous == '/':
+				state = lineComment
+			case r == '*' && previous == '/':
+				state = blockComment
+				// the star may not close the comment it opens.
+				r = 0
+			case ticks == 3:
+				state = script
+				ticks = 0
+			case r == '{':
+				depth++
+			case r == '}':
+				depth--
+				if depth == 0 {
+
+					return buff.String(), startLine, nil
+				}
+			}
+		case doubleQuoted, singleQuoted:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"' && state == doubleQuoted, r == '\'' && state == singleQuoted:
+				state = normal
+				// a closing quote may not start a comment.
+				r = 0
+			}
+		case lineComment:
+			if r == '\n' {
+				state = normal
+			}
+		case blockComment:
+			if r == '/' && previous == '*' {
+				state = normal
+				r = 0
+			}
+		case script:
+			if ticks == 3 {
+				state = normal
+				ticks = 0
+			}
+		}
+		previous = r
+	}
+}
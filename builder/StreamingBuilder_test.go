@@ -0,0 +1,115 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrlChunker(t *testing.T) {
+	grl := `// leading comment with a brace {
+rule A "desc with } brace" { when true then Retract("A"); }
+/* block } comment */ rule B 'single \' quoted {' {
+	when
+		Fact.Name == "}}" // trailing }
+	then
+		Retract("B");
+}
+   `
+	chunker := newGrlChunker(strings.NewReader(grl))
+	text, line, err := chunker.next()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, line)
+	assert.True(t, strings.HasSuffix(text, `Retract("A"); }`))
+
+	text, line, err = chunker.next()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, line)
+	assert.True(t, strings.HasPrefix(text, "\n/* block } comment */ rule B"))
+	assert.True(t, strings.HasSuffix(text, "Retract(\"B\");\n}"))
+
+	_, _, err = chunker.next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestGrlChunker_Script(t *testing.T) {
+	grl := "rule A { when true then starlark ```\n# close } here, it's a comment\nRetract(\"A\")\n``` }\nrule B { when true then Retract(\"B\"); }"
+	chunker := newGrlChunker(strings.NewReader(grl))
+	text, line, err := chunker.next()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, line)
+	assert.True(t, strings.HasSuffix(text, "``` }"))
+
+	text, line, err = chunker.next()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, line)
+	assert.True(t, strings.HasSuffix(text, `Retract("B"); }`))
+
+	_, _, err = chunker.next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRuleBuilder_BuildRuleFromReader(t *testing.T) {
+	const ruleCount = 500
+	reader, writer := io.Pipe()
+	go func() {
+		for i := 0; i < ruleCount; i++ {
+			_, _ = fmt.Fprintf(writer, "rule R%d \"rule {%d}\" {\n\twhen\n\t\tFact.Value == %d\n\tthen\n\t\tFact.Name = \"R%d\";\n}\n", i, i, i, i)
+		}
+		_ = writer.Close()
+	}()
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromReader("Stream", "1", reader))
+	kb, err := lib.NewKnowledgeBaseInstance("Stream", "1")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, ruleCount)
+	assert.Equal(t, "rule {499}", kb.RuleEntries["R499"].RuleDescription)
+}
+
+func TestRuleBuilder_BuildRuleFromReaderErrors(t *testing.T) {
+	grl := `rule A { when true then Retract("A"); }
+
+
+rule B { when true then Retract("B") }
+rule C { when true then Retract("C"); }`
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	err := rb.BuildRuleFromReader("Stream", "1", strings.NewReader(grl))
+	reporter, ok := err.(*pkg.GruleErrorReporter)
+	if assert.True(t, ok) && assert.Len(t, reporter.Errors, 1) {
+		assert.Contains(t, reporter.Errors[0].Error(), "grl error on 4:")
+	}
+	// the rule before the error is kept, the rules after it are not read.
+	assert.Len(t, lib.GetKnowledgeBase("Stream", "1").RuleEntries, 1)
+
+	rb = NewRuleBuilder(ast.NewKnowledgeLibrary())
+	rb.Sanitizer = &Sanitizer{MaxRules: 2}
+	err = rb.BuildRuleFromReader("Stream", "1", strings.NewReader(distributedRuleV2+`
+rule Three "third" { when true then Retract("Three"); }`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "more than 2 rules")
+	}
+	err = rb.BuildRuleFromReader("Limited", "1", strings.NewReader(distributedRuleV2))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"unicode"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// sideEffectFunctions are the built-in functions that change the facts, the working memory or the execution.
+var sideEffectFunctions = map[string]bool{
+	"Changed":       true,
+	"Forget":        true,
+	"Complete":      true,
+	"Retract":       true,
+	"RecordOutcome": true,
+	"Emit":          true,
+}
+
+// mutatingValueFunctions are the functions of the array and map values that change them.
+var mutatingValueFunctions = map[string]bool{
+	"Append": true,
+	"Clear":  true,
+}
+
+// checkWhenScopes rejects the rules of the GRL whose when scope has side effects, likewise the halt conditions.
+// An assignment is already a syntax error in a when scope, the remaining side effects are the calls to the built-in
+// functions that change the execution, the functions changing arrays and maps and the setters of the facts.
+func checkWhenScopes(grl *ast.Grl) error {
+	names := make([]string, 0, len(grl.RuleEntries))
+	for name := range grl.RuleEntries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		entry := grl.RuleEntries[name]
+		if entry.WhenScope == nil {
+
+			continue
+		}
+		when := &sanitizerScan{}
+		when.scanExpression(entry.WhenScope.Expression)
+		for _, call := range when.calls {
+			if reason := sideEffectOf(call); len(reason) > 0 {
+				errs = append(errs, fmt.Errorf("rule %s calls %s in its when scope, %s", entry.RuleName, callName(call), reason))
+			}
+		}
+	}
+	for _, entry := range grl.HaltEntries {
+		halt := &sanitizerScan{}
+		halt.scanExpression(entry.Expression)
+		for _, call := range halt.calls {
+			if reason := sideEffectOf(call); len(reason) > 0 {
+				errs = append(errs, fmt.Errorf("halt condition %s calls %s, %s", entry.GrlText, callName(call), reason))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sideEffectOf tells why the call has a side effect, empty if it has none the builder knows of.
+func sideEffectOf(call *ast.ExpressionAtom) string {
+	name := call.FunctionCall.FunctionName
+	if call.ExpressionAtom == nil {
+		if sideEffectFunctions[name] {
+
+			return "the built-in function changes the execution"
+		}
+
+		return ""
+	}
+	if mutatingValueFunctions[name] {
+
+		return "the function changes the value it is called on"
+	}
+	if isSetterName(name) {
+
+		return "the method is a setter"
+	}
+
+	return ""
+}
+
+// isSetterName tells if the method name follows the setter convention of model.StandardAccessors, such as SetStatus.
+func isSetterName(name string) bool {
+	prefix := model.StandardAccessors().SetterPrefix
+	if len(prefix) == 0 || len(name) <= len(prefix) || name[:len(prefix)] != prefix {
+
+		return false
+	}
+
+	return unicode.IsUpper(rune(name[len(prefix)]))
+}
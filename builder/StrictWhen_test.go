@@ -0,0 +1,76 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictWhenScopes(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := NewRuleBuilder(lib)
+	rb.StrictWhenScopes = true
+
+	err := rb.BuildRuleFromResource("Strict", "0.0.1", pkg.NewBytesResource([]byte(`
+rule Pure "Reads only" {
+	when
+		Fact.Name.HasPrefix("A") && Fact.Items.Len() > 0 && Fact.IsValid()
+	then
+		Fact.Items.Append("checked");
+		Retract("Pure");
+}`)))
+	assert.NoError(t, err)
+	assert.Len(t, lib.GetKnowledgeBase("Strict", "0.0.1").RuleEntries, 1)
+
+	err = rb.BuildRuleFromResource("Strict", "0.0.2", pkg.NewBytesResource([]byte(`
+rule Retracting "Retracts while matching" {
+	when
+		Fact.X == 1 && Retract("Other") == nil
+	then
+		Fact.X = 2;
+}
+rule Mutating "Changes the facts while matching" {
+	when
+		Fact.SetStatus("seen") == nil || Fact.Items.Append("x") == nil
+	then
+		Fact.X = 3;
+}
+rule Settle "Calls a method that is not a setter" {
+	when
+		Fact.Settle() && Fact.Setup
+	then
+		Fact.X = 4;
+}`)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `rule Retracting calls Retract in its when scope, the built-in function changes the execution`)
+	assert.Contains(t, err.Error(), `rule Mutating calls Fact.SetStatus in its when scope, the method is a setter`)
+	assert.Contains(t, err.Error(), `rule Mutating calls Fact.Items.Append in its when scope, the function changes the value it is called on`)
+	assert.NotContains(t, err.Error(), "Settle")
+	assert.Len(t, lib.GetKnowledgeBase("Strict", "0.0.2").RuleEntries, 0)
+
+	err = rb.BuildRuleFromResource("Strict", "0.0.3", pkg.NewBytesResource([]byte(`
+rule Assigning "Assigns instead of comparing" {
+	when
+		Fact.X = 1
+	then
+		Fact.X = 2;
+}`)))
+	assert.Error(t, err)
+	assert.Len(t, lib.GetKnowledgeBase("Strict", "0.0.3").RuleEntries, 0)
+}
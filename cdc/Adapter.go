@@ -0,0 +1,229 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+const (
+	// DefaultChangeFact is the default fact name of the Change.
+	DefaultChangeFact = "Change"
+	// DefaultBeforeFact is the default fact name of the before image.
+	DefaultBeforeFact = "Before"
+	// DefaultAfterFact is the default fact name of the after image.
+	DefaultAfterFact = "After"
+)
+
+var (
+	// logFields default fields for grule
+	logFields = logger.Fields{
+		"package": "cdc",
+	}
+
+	// log is a logger instance with default fields for grule
+	log = logger.Log.WithFields(logFields)
+)
+
+// NewAdapter creates new Adapter executing the change events with the specified knowledge base of the library.
+func NewAdapter(library *ast.KnowledgeLibrary, name, version string) *Adapter {
+
+	return &Adapter{
+		Library:    library,
+		Name:       name,
+		Version:    version,
+		Engine:     engine.NewGruleEngine(),
+		ChangeFact: DefaultChangeFact,
+		BeforeFact: DefaultBeforeFact,
+		AfterFact:  DefaultAfterFact,
+	}
+}
+
+// Adapter executes a knowledge base for every change event of a stream, such as
+//
+//	rule PriceDrop "the price of a product dropped" {
+//	    when
+//	        Change.Op == "u" && After.price < Before.price
+//	    then
+//	        Emit("price-drop");
+//	        Retract("PriceDrop");
+//	}
+//
+// Each event gets a new instance of the knowledge base. The images are JSON facts whose fields are named as the
+// columns, the before image is absent from creates and snapshot reads and the after image from deletes. Rules
+// reading an absent image fail their evaluation, unless the engine tolerates MissingFacts.
+type Adapter struct {
+	Library *ast.KnowledgeLibrary
+	Name    string
+	Version string
+	Engine  *engine.GruleEngine
+
+	// ChangeFact, BeforeFact and AfterFact are the fact names of the change and its images.
+	ChangeFact string
+	BeforeFact string
+	AfterFact  string
+
+	// Facts, if set, adds more facts into the data context of an event, such as the lookups the rules need.
+	Facts func(event *Event, dataCtx ast.IDataContext) error
+}
+
+// Decision is the outcome of the execution of a change event. Use engine.Emitted on its Outputs to obtain the
+// messages the rules emitted, such as engine.Emitted[string](decision.Outputs).
+type Decision struct {
+	Event   *Event
+	Outputs *engine.Outputs
+	// Err is the error that failed the execution, if any. Outputs has no message then.
+	Err error
+}
+
+// log returns the logger of the engine of the adapter, the default logger of the package if it has none.
+func (adapter *Adapter) log() logger.LogEntry {
+	if adapter.Engine == nil {
+
+		return log
+	}
+
+	return adapter.Engine.Logger.Or(log)
+}
+
+// Handle parses the message as a Debezium change event and executes it. It returns nil decision for a tombstone.
+func (adapter *Adapter) Handle(ctx context.Context, message []byte) (*Decision, error) {
+	event, err := ParseEvent(message)
+	if err != nil {
+
+		return nil, err
+	}
+	if event == nil {
+
+		return nil, nil
+	}
+	decision := adapter.Execute(ctx, event)
+
+	return decision, decision.Err
+}
+
+// Execute executes the change event with a new instance of the knowledge base.
+func (adapter *Adapter) Execute(ctx context.Context, event *Event) *Decision {
+	decision := &Decision{Event: event, Outputs: engine.NewOutputs()}
+	knowledgeBase, err := adapter.Library.NewKnowledgeBaseInstance(adapter.Name, adapter.Version)
+	if err != nil {
+		decision.Err = err
+
+		return decision
+	}
+	dataCtx, err := adapter.dataContext(event)
+	if err != nil {
+		decision.Err = err
+
+		return decision
+	}
+	err = adapter.Engine.ExecuteWithContext(engine.WithOutputs(ctx, decision.Outputs), dataCtx, knowledgeBase)
+	if err != nil {
+		decision.Err = fmt.Errorf("error while executing the %s change of %s. got %w", event.Change.Op, event.Change.Table, err)
+	}
+
+	return decision
+}
+
+// Consume executes every message received from the channel until it is closed or the context is done, and sends
+// a decision for each into the decisions channel. A message that fails is sent with its Err, the consumption goes on.
+// Tombstones are skipped.
+func (adapter *Adapter) Consume(ctx context.Context, messages <-chan []byte, decisions chan<- *Decision) error {
+	for {
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case message, ok := <-messages:
+			if !ok {
+
+				return nil
+			}
+			event, err := ParseEvent(message)
+			if err != nil {
+				adapter.log().Errorf("Failed parsing change event. Got error %v", err)
+				decision := &Decision{Err: err}
+				if err := send(ctx, decisions, decision); err != nil {
+
+					return err
+				}
+
+				continue
+			}
+			if event == nil {
+
+				continue
+			}
+			decision := adapter.Execute(ctx, event)
+			if decision.Err != nil {
+				adapter.log().Errorf("Failed executing change event. Got error %v", decision.Err)
+			}
+			if err := send(ctx, decisions, decision); err != nil {
+
+				return err
+			}
+		}
+	}
+}
+
+// send sends the decision unless the context is done first.
+func send(ctx context.Context, decisions chan<- *Decision, decision *Decision) error {
+	select {
+	case <-ctx.Done():
+
+		return ctx.Err()
+	case decisions <- decision:
+
+		return nil
+	}
+}
+
+// dataContext prepares the facts of the event.
+func (adapter *Adapter) dataContext(event *Event) (ast.IDataContext, error) {
+	dataCtx := ast.NewDataContext()
+	err := dataCtx.Add(adapter.ChangeFact, event.Change)
+	if err != nil {
+
+		return nil, err
+	}
+	if event.Before != nil {
+		err = dataCtx.AddJSON(adapter.BeforeFact, event.Before)
+		if err != nil {
+
+			return nil, fmt.Errorf("before image is not valid JSON. got %w", err)
+		}
+	}
+	if event.After != nil {
+		err = dataCtx.AddJSON(adapter.AfterFact, event.After)
+		if err != nil {
+
+			return nil, fmt.Errorf("after image is not valid JSON. got %w", err)
+		}
+	}
+	if adapter.Facts != nil {
+		err = adapter.Facts(event, dataCtx)
+		if err != nil {
+
+			return nil, err
+		}
+	}
+
+	return dataCtx, nil
+}
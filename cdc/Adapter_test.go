@@ -0,0 +1,133 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const priceRules = `
+rule PriceDrop "the price of a product dropped" {
+	when
+		Change.Op == "u" && Change.Table == "products" && After.price < Before.price
+	then
+		Emit("price-drop " + After.name);
+		Retract("PriceDrop");
+}
+
+rule NewProduct "a product was created" {
+	when
+		Change.Op == "c"
+	then
+		Emit("new " + After.name);
+		Retract("NewProduct");
+}
+`
+
+const updateWithSchema = `{
+	"schema": {"type": "struct", "name": "shop.public.products.Envelope"},
+	"payload": {
+		"before": {"id": 1, "name": "lamp", "price": 30},
+		"after": {"id": 1, "name": "lamp", "price": 25},
+		"source": {"connector": "postgresql", "db": "shop", "schema": "public", "table": "products"},
+		"op": "u",
+		"ts_ms": 1700000000000
+	}
+}`
+
+func newTestAdapter(t *testing.T) *Adapter {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Prices", "1", pkg.NewBytesResource([]byte(priceRules))))
+
+	return NewAdapter(lib, "Prices", "1")
+}
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(updateWithSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, &Change{Op: OpUpdate, Connector: "postgresql", Database: "shop", Schema: "public", Table: "products", Timestamp: 1700000000000}, event.Change)
+	assert.JSONEq(t, `{"id": 1, "name": "lamp", "price": 30}`, string(event.Before))
+	assert.JSONEq(t, `{"id": 1, "name": "lamp", "price": 25}`, string(event.After))
+
+	// without the schema, the created row has no before image.
+	event, err = ParseEvent([]byte(`{"before": null, "after": {"id": 2}, "source": {"table": "products"}, "op": "c"}`))
+	assert.NoError(t, err)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, OpCreate, event.Change.Op)
+
+	for _, tombstone := range []string{``, `null`, `{"schema": null, "payload": null}`} {
+		event, err = ParseEvent([]byte(tombstone))
+		assert.NoError(t, err)
+		assert.Nil(t, event, tombstone)
+	}
+
+	_, err = ParseEvent([]byte(`{"op": "x"}`))
+	assert.Error(t, err)
+	_, err = ParseEvent([]byte(`[1]`))
+	assert.Error(t, err)
+}
+
+func TestAdapter_Handle(t *testing.T) {
+	adapter := newTestAdapter(t)
+	decision, err := adapter.Handle(context.Background(), []byte(updateWithSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"price-drop lamp"}, engine.Emitted[string](decision.Outputs))
+
+	decision, err = adapter.Handle(context.Background(), []byte(`{"after": {"id": 2, "name": "desk", "price": 100}, "source": {"table": "products"}, "op": "c"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new desk"}, engine.Emitted[string](decision.Outputs))
+
+	decision, err = adapter.Handle(context.Background(), []byte(`null`))
+	assert.NoError(t, err)
+	assert.Nil(t, decision)
+}
+
+func TestAdapter_Consume(t *testing.T) {
+	adapter := newTestAdapter(t)
+	adapter.Facts = func(event *Event, dataCtx ast.IDataContext) error {
+
+		return dataCtx.Add("Threshold", &struct{ Value int64 }{Value: 10})
+	}
+	messages := make(chan []byte, 4)
+	decisions := make(chan *Decision, 4)
+	messages <- []byte(updateWithSchema)
+	messages <- []byte(`null`)
+	messages <- []byte(`not json`)
+	messages <- []byte(`{"before": {"id": 1, "price": 25}, "source": {"table": "products"}, "op": "d"}`)
+	close(messages)
+	assert.NoError(t, adapter.Consume(context.Background(), messages, decisions))
+	close(decisions)
+
+	received := make([]*Decision, 0)
+	for decision := range decisions {
+		received = append(received, decision)
+	}
+	assert.Len(t, received, 3)
+	assert.Equal(t, []string{"price-drop lamp"}, engine.Emitted[string](received[0].Outputs))
+	assert.Error(t, received[1].Err)
+	assert.NoError(t, received[2].Err)
+	assert.Empty(t, engine.Emitted[string](received[2].Outputs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, adapter.Consume(ctx, make(chan []byte), decisions))
+}
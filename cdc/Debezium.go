@@ -0,0 +1,130 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package cdc runs rules against the change data capture events of a database, each change is executed by a
+// knowledge base with its before and after images as facts.
+package cdc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// OpCreate is the operation of an inserted row.
+	OpCreate = "c"
+	// OpUpdate is the operation of an updated row.
+	OpUpdate = "u"
+	// OpDelete is the operation of a deleted row.
+	OpDelete = "d"
+	// OpRead is the operation of a row read by a snapshot.
+	OpRead = "r"
+	// OpTruncate is the operation of a truncated table.
+	OpTruncate = "t"
+)
+
+// Change describes a change event, it is added into the data context next to the before and after images.
+type Change struct {
+	// Op is the operation, one of OpCreate, OpUpdate, OpDelete, OpRead or OpTruncate.
+	Op string
+	// Connector is the connector that captured the change, such as postgresql.
+	Connector string
+	// Database, Schema and Table locate the changed row.
+	Database string
+	Schema   string
+	Table    string
+	// Timestamp is the time the connector processed the change, in milliseconds since the epoch.
+	Timestamp int64
+}
+
+// Event is a change data capture event, the images are the JSON rows before and after the change.
+// An image the operation does not have is nil, such as the before image of a created row.
+type Event struct {
+	Change *Change
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// debeziumPayload is the Debezium change event envelope.
+type debeziumPayload struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+	Source struct {
+		Connector string `json:"connector"`
+		DB        string `json:"db"`
+		Schema    string `json:"schema"`
+		Table     string `json:"table"`
+	} `json:"source"`
+	Op   string `json:"op"`
+	TsMs int64  `json:"ts_ms"`
+}
+
+// ParseEvent reads a Debezium change event, written by the JSON converter with or without its schema.
+// It returns nil event for a tombstone, the message following a delete for log compaction.
+func ParseEvent(message []byte) (*Event, error) {
+	message = bytes.TrimSpace(message)
+	if len(message) == 0 || bytes.Equal(message, []byte("null")) {
+
+		return nil, nil
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(message, &envelope); err != nil {
+
+		return nil, fmt.Errorf("change event is not a JSON object. got %w", err)
+	}
+	if payload, ok := envelope["payload"]; ok {
+		if _, hasSchema := envelope["schema"]; hasSchema {
+			if bytes.Equal(bytes.TrimSpace(payload), []byte("null")) {
+
+				return nil, nil
+			}
+			message = payload
+		}
+	}
+	payload := &debeziumPayload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+
+		return nil, fmt.Errorf("change event is not a Debezium envelope. got %w", err)
+	}
+	switch payload.Op {
+	case OpCreate, OpUpdate, OpDelete, OpRead, OpTruncate:
+	default:
+
+		return nil, fmt.Errorf("change event has unknown operation %q", payload.Op)
+	}
+
+	return &Event{
+		Change: &Change{
+			Op:        payload.Op,
+			Connector: payload.Source.Connector,
+			Database:  payload.Source.DB,
+			Schema:    payload.Source.Schema,
+			Table:     payload.Source.Table,
+			Timestamp: payload.TsMs,
+		},
+		Before: image(payload.Before),
+		After:  image(payload.After),
+	}, nil
+}
+
+// image returns the row image, nil if the event has none.
+func image(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+
+		return nil
+	}
+
+	return raw
+}
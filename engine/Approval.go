@@ -0,0 +1,355 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// ActivationStatus is the state of the decision on an Activation.
+type ActivationStatus string
+
+const (
+	// ActivationPending waits for a decision.
+	ActivationPending ActivationStatus = "pending"
+	// ActivationAccepted lets the rule fire the next time it matches.
+	ActivationAccepted ActivationStatus = "accepted"
+	// ActivationRejected keeps the rule from firing.
+	ActivationRejected ActivationStatus = "rejected"
+	// ActivationDeferred postpones the decision until a given time.
+	ActivationDeferred ActivationStatus = "deferred"
+	// ActivationFired is an accepted activation whose rule has fired, it does not fire again.
+	ActivationFired ActivationStatus = "fired"
+)
+
+// Activation is a rule annotated with @approval whose when scope matched, waiting for a human decision before its
+// then scope fires. It is identified by the subject of the execution, such as the refund being processed, the
+// KnowledgeBase and the rule, so executions of the same subject find the decision taken in between.
+type Activation struct {
+	ID            string `json:"id"`
+	Subject       string `json:"subject"`
+	KnowledgeBase string `json:"knowledgeBase"`
+	Version       string `json:"version"`
+	// RuleID is the stable id of the rule, see ast.RuleEntry.StableID.
+	RuleID   string `json:"ruleId"`
+	RuleName string `json:"ruleName"`
+	// Categories are the approval categories of the rule, such as "large-refund".
+	Categories []string         `json:"categories"`
+	Status     ActivationStatus `json:"status"`
+	// Reason is the reason given with the last decision.
+	Reason string `json:"reason,omitempty"`
+	// Until is when a deferred activation waits for a decision again, zero if it is deferred indefinitely.
+	Until     time.Time `json:"until,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	DecidedAt time.Time `json:"decidedAt,omitempty"`
+	FiredAt   time.Time `json:"firedAt,omitempty"`
+
+	approvals *Approvals
+}
+
+// Accept lets the rule fire the next time the subject is executed and the rule still matches.
+func (activation *Activation) Accept(reason string) error {
+
+	return activation.decide(ActivationAccepted, time.Time{}, reason)
+}
+
+// Reject keeps the rule from firing for the subject.
+func (activation *Activation) Reject(reason string) error {
+
+	return activation.decide(ActivationRejected, time.Time{}, reason)
+}
+
+// Defer postpones the decision, the activation is pending again after until. A zero until defers it indefinitely.
+func (activation *Activation) Defer(until time.Time, reason string) error {
+
+	return activation.decide(ActivationDeferred, until, reason)
+}
+
+// decide records the decision and saves it in the store of the Approvals the activation comes from.
+func (activation *Activation) decide(status ActivationStatus, until time.Time, reason string) error {
+	if activation.approvals == nil {
+
+		return fmt.Errorf("activation %s was not obtained from Approvals", activation.ID)
+	}
+	if activation.Status == ActivationFired {
+
+		return fmt.Errorf("activation %s already fired", activation.ID)
+	}
+	decided := *activation
+	decided.Status = status
+	decided.Until = until
+	decided.Reason = reason
+	decided.DecidedAt = time.Now()
+	err := activation.approvals.Store.Save(&decided)
+	if err != nil {
+
+		return fmt.Errorf("error while saving activation %s. got %w", activation.ID, err)
+	}
+	*activation = decided
+
+	return nil
+}
+
+// waiting tells whether the activation waits for a decision at the given time.
+func (activation *Activation) waiting(now time.Time) bool {
+
+	return activation.Status == ActivationPending ||
+		(activation.Status == ActivationDeferred && !activation.Until.IsZero() && !now.Before(activation.Until))
+}
+
+// ActivationStore is a storage driver persisting the activations, so the decisions survive process restarts.
+// It must be safe for concurrent use.
+type ActivationStore interface {
+	// Load returns the activation, or nil if the store has none.
+	Load(id string) (*Activation, error)
+	// Save replaces the activation.
+	Save(activation *Activation) error
+	// List returns the activations of the status.
+	List(status ActivationStatus) ([]*Activation, error)
+}
+
+// NewMemoryActivationStore creates new MemoryActivationStore.
+func NewMemoryActivationStore() *MemoryActivationStore {
+
+	return &MemoryActivationStore{activations: make(map[string]Activation)}
+}
+
+// MemoryActivationStore keeps the activations in memory. They do not survive the process, it is meant for tests.
+type MemoryActivationStore struct {
+	lock        sync.Mutex
+	activations map[string]Activation
+}
+
+// Load implements ActivationStore.
+func (store *MemoryActivationStore) Load(id string) (*Activation, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	activation, ok := store.activations[id]
+	if !ok {
+
+		return nil, nil
+	}
+
+	return &activation, nil
+}
+
+// Save implements ActivationStore.
+func (store *MemoryActivationStore) Save(activation *Activation) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.activations[activation.ID] = *activation
+
+	return nil
+}
+
+// List implements ActivationStore.
+func (store *MemoryActivationStore) List(status ActivationStatus) ([]*Activation, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	activations := make([]*Activation, 0)
+	for _, activation := range store.activations {
+		if activation.Status == status {
+			activation := activation
+			activations = append(activations, &activation)
+		}
+	}
+
+	return activations, nil
+}
+
+// NewApprovals creates new Approvals keeping the activations in the store.
+func NewApprovals(store ActivationStore) *Approvals {
+
+	return &Approvals{Store: store}
+}
+
+// Approvals inserts a human decision between the matching and the firing of the rules annotated with @approval.
+// Set it as GruleEngine.Approvals: a matching rule of an approval category is not fired but recorded as a pending
+// Activation, which the approvers Accept, Reject or Defer. The next execution of the same subject fires the
+// accepted activations once.
+type Approvals struct {
+	Store ActivationStore
+}
+
+// Activation returns the activation of the id, bound to these Approvals so it can be decided. Nil if there is none.
+func (approvals *Approvals) Activation(id string) (*Activation, error) {
+	activation, err := approvals.Store.Load(id)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while loading activation %s. got %w", id, err)
+	}
+	if activation != nil {
+		activation.approvals = approvals
+	}
+
+	return activation, nil
+}
+
+// Pending returns the activations waiting for a decision, the pending ones and the deferred ones whose time came,
+// the oldest first.
+func (approvals *Approvals) Pending() ([]*Activation, error) {
+	pending, err := approvals.Store.List(ActivationPending)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while listing the pending activations. got %w", err)
+	}
+	deferred, err := approvals.Store.List(ActivationDeferred)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while listing the deferred activations. got %w", err)
+	}
+	now := time.Now()
+	for _, activation := range deferred {
+		if activation.waiting(now) {
+			pending = append(pending, activation)
+		}
+	}
+	for _, activation := range pending {
+		activation.approvals = approvals
+	}
+	sort.Slice(pending, func(i, j int) bool {
+
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+
+	return pending, nil
+}
+
+type approvalSubjectKey struct{}
+
+// WithApprovalSubject attaches the subject of the execution, such as the id of the refund being processed, to the
+// context given to ExecuteWithContext. The activations of the execution are identified by the subject.
+func WithApprovalSubject(ctx context.Context, subject string) context.Context {
+
+	return context.WithValue(ctx, approvalSubjectKey{}, subject)
+}
+
+// approvalGate holds the rules of an execution waiting for a decision.
+type approvalGate struct {
+	approvals *Approvals
+	subject   string
+	knowledge *ast.KnowledgeBase
+	// held tells, by rule, whether a rule already looked up in this execution is held.
+	held map[*ast.RuleEntry]bool
+	// accepted are the accepted activations of the rules that may fire.
+	accepted map[*ast.RuleEntry]*Activation
+	log      logger.LogEntry
+}
+
+// approvalGate prepares the gate of an execution, nil if no rule of the knowledge base needs an approval.
+func (g *GruleEngine) approvalGate(ctx context.Context, knowledge *ast.KnowledgeBase) (*approvalGate, error) {
+	for _, ruleEntry := range knowledge.RuleEntries {
+		if len(ruleEntry.Approvals) == 0 {
+
+			continue
+		}
+		if g.Approvals == nil {
+
+			return nil, fmt.Errorf("rule %s requires approval but the engine has no Approvals", ruleEntry.RuleName)
+		}
+		subject, _ := ctx.Value(approvalSubjectKey{}).(string)
+		if len(subject) == 0 {
+
+			return nil, fmt.Errorf("rule %s requires approval but the execution has no subject, see WithApprovalSubject", ruleEntry.RuleName)
+		}
+
+		return &approvalGate{
+			approvals: g.Approvals,
+			subject:   subject,
+			knowledge: knowledge,
+			held:      make(map[*ast.RuleEntry]bool),
+			accepted:  make(map[*ast.RuleEntry]*Activation),
+			log:       g.log(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// holds tells whether the matching rule must wait for a decision. The first match of a rule needing an approval
+// records a pending activation.
+func (gate *approvalGate) holds(ruleEntry *ast.RuleEntry) (bool, error) {
+	if gate == nil || len(ruleEntry.Approvals) == 0 {
+
+		return false, nil
+	}
+	if held, ok := gate.held[ruleEntry]; ok {
+
+		return held, nil
+	}
+	id := fmt.Sprintf("%s/%s/%s", gate.subject, gate.knowledge.Name, ruleEntry.StableID())
+	activation, err := gate.approvals.Activation(id)
+	if err != nil {
+
+		return false, err
+	}
+	if activation == nil {
+		activation = &Activation{
+			ID:            id,
+			Subject:       gate.subject,
+			KnowledgeBase: gate.knowledge.Name,
+			Version:       gate.knowledge.Version,
+			RuleID:        ruleEntry.StableID(),
+			RuleName:      ruleEntry.RuleName,
+			Categories:    ruleEntry.Approvals,
+			Status:        ActivationPending,
+			CreatedAt:     time.Now(),
+		}
+		err = gate.approvals.Store.Save(activation)
+		if err != nil {
+
+			return false, fmt.Errorf("error while saving activation %s. got %w", id, err)
+		}
+		gate.log.Debugf("Rule %s waits for approval as activation %s", ruleEntry.RuleName, id)
+	}
+	held := activation.Status != ActivationAccepted
+	if !held {
+		gate.accepted[ruleEntry] = activation
+	}
+	gate.held[ruleEntry] = held
+
+	return held, nil
+}
+
+// fired records that the rule of an accepted activation fired, it is held for the rest of the execution.
+func (gate *approvalGate) fired(ruleEntry *ast.RuleEntry) error {
+	if gate == nil {
+
+		return nil
+	}
+	activation, ok := gate.accepted[ruleEntry]
+	if !ok {
+
+		return nil
+	}
+	delete(gate.accepted, ruleEntry)
+	gate.held[ruleEntry] = true
+	activation.Status = ActivationFired
+	activation.FiredAt = time.Now()
+	err := gate.approvals.Store.Save(activation)
+	if err != nil {
+
+		return fmt.Errorf("error while saving activation %s. got %w", activation.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ApprovalRefund struct {
+	Amount   int
+	Refunded bool
+	Notified bool
+}
+
+const approvalRules = `
+@approval("large-refund")
+rule LargeRefund "refunds above 1000 need an approval" {
+	when
+		Refund.Amount > 1000 && !Refund.Refunded
+	then
+		Refund.Refunded = true;
+}
+rule SmallRefund "refunds up to 1000 are paid at once" {
+	when
+		Refund.Amount <= 1000 && !Refund.Refunded
+	then
+		Refund.Refunded = true;
+}
+rule Notify "notifies the refunds" {
+	when
+		!Refund.Notified
+	then
+		Refund.Notified = true;
+}`
+
+func TestGruleEngine_Approvals(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Refunds", "1.0.0", pkg.NewBytesResource([]byte(approvalRules))))
+
+	execute := func(eng *GruleEngine, subject string, refund *ApprovalRefund) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Refund", refund))
+		kb, err := lib.NewKnowledgeBaseInstance("Refunds", "1.0.0")
+		assert.NoError(t, err)
+		ctx := context.Background()
+		if len(subject) > 0 {
+			ctx = WithApprovalSubject(ctx, subject)
+		}
+
+		return eng.ExecuteWithContext(ctx, dctx, kb)
+	}
+
+	eng := NewGruleEngine()
+	assert.ErrorContains(t, execute(eng, "refund-1", &ApprovalRefund{Amount: 5000}), "the engine has no Approvals")
+	eng.Approvals = NewApprovals(NewMemoryActivationStore())
+	assert.ErrorContains(t, execute(eng, "", &ApprovalRefund{Amount: 5000}), "see WithApprovalSubject")
+
+	// the small refund fires without approval.
+	refund := &ApprovalRefund{Amount: 200}
+	assert.NoError(t, execute(eng, "refund-0", refund))
+	assert.True(t, refund.Refunded)
+
+	// the large refund waits, the other rules fire.
+	refund = &ApprovalRefund{Amount: 5000}
+	assert.NoError(t, execute(eng, "refund-1", refund))
+	assert.False(t, refund.Refunded)
+	assert.True(t, refund.Notified)
+	pending, err := eng.Approvals.Pending()
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "refund-1/Refunds/LargeRefund", pending[0].ID)
+		assert.Equal(t, []string{"large-refund"}, pending[0].Categories)
+		assert.Equal(t, ActivationPending, pending[0].Status)
+		assert.NoError(t, pending[0].Accept("checked by finance"))
+	}
+	pending, err = eng.Approvals.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	// the accepted activation fires once.
+	assert.NoError(t, execute(eng, "refund-1", refund))
+	assert.True(t, refund.Refunded)
+	activation, err := eng.Approvals.Activation("refund-1/Refunds/LargeRefund")
+	assert.NoError(t, err)
+	assert.Equal(t, ActivationFired, activation.Status)
+	assert.Equal(t, "checked by finance", activation.Reason)
+	assert.Error(t, activation.Reject("too late"))
+	refund.Refunded = false
+	assert.NoError(t, execute(eng, "refund-1", refund))
+	assert.False(t, refund.Refunded)
+
+	// a rejected activation does not fire.
+	refund = &ApprovalRefund{Amount: 3000}
+	assert.NoError(t, execute(eng, "refund-2", refund))
+	activation, err = eng.Approvals.Activation("refund-2/Refunds/LargeRefund")
+	assert.NoError(t, err)
+	assert.NoError(t, activation.Reject("duplicate"))
+	assert.NoError(t, execute(eng, "refund-2", refund))
+	assert.False(t, refund.Refunded)
+
+	// a deferred activation waits for a decision again once its time came.
+	assert.NoError(t, execute(eng, "refund-3", &ApprovalRefund{Amount: 3000}))
+	activation, err = eng.Approvals.Activation("refund-3/Refunds/LargeRefund")
+	assert.NoError(t, err)
+	assert.NoError(t, activation.Defer(time.Now().Add(time.Hour), "waiting for the receipt"))
+	pending, err = eng.Approvals.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+	assert.NoError(t, activation.Defer(time.Now().Add(-time.Minute), "waiting for the receipt"))
+	pending, err = eng.Approvals.Pending()
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, ActivationDeferred, pending[0].Status)
+	}
+
+	// a single pass execution fires the accepted activations as well.
+	assert.NoError(t, pending[0].Accept("receipt received"))
+	eng.SinglePass = true
+	refund = &ApprovalRefund{Amount: 3000}
+	assert.NoError(t, execute(eng, "refund-3", refund))
+	assert.True(t, refund.Refunded)
+	activation, err = eng.Approvals.Activation("refund-3/Refunds/LargeRefund")
+	assert.NoError(t, err)
+	assert.Equal(t, ActivationFired, activation.Status)
+}
+
+func TestGruleEngine_Approvals_SingleRuleAndMatching(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Refunds", "1.0.0", pkg.NewBytesResource([]byte(approvalRules))))
+	single := `@approval("large-refund") rule LargeRefund { when Refund.Amount > 1000 && !Refund.Refunded then Refund.Refunded = true; }`
+	assert.NoError(t, rb.BuildRuleFromResource("LargeRefund", "1.0.0", pkg.NewBytesResource([]byte(single))))
+	eng := NewGruleEngine()
+	eng.Approvals = NewApprovals(NewMemoryActivationStore())
+	ctx := WithApprovalSubject(context.Background(), "refund-1")
+
+	// the single rule waits for its approval, then fires once.
+	refund := &ApprovalRefund{Amount: 5000}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Refund", refund))
+	kb, err := lib.NewKnowledgeBaseInstance("LargeRefund", "1.0.0")
+	assert.NoError(t, err)
+	_, err = eng.ExecuteSingleRule(context.Background(), dctx, kb)
+	assert.ErrorContains(t, err, "see WithApprovalSubject")
+	fired, err := eng.ExecuteSingleRule(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.False(t, fired)
+	assert.False(t, refund.Refunded)
+	activation, err := eng.Approvals.Activation("refund-1/LargeRefund/LargeRefund")
+	assert.NoError(t, err)
+	if assert.NotNil(t, activation) {
+		assert.Equal(t, ActivationPending, activation.Status)
+		assert.NoError(t, activation.Accept("checked by finance"))
+	}
+	fired, err = eng.ExecuteSingleRule(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.True(t, fired)
+	assert.True(t, refund.Refunded)
+	activation, err = eng.Approvals.Activation("refund-1/LargeRefund/LargeRefund")
+	assert.NoError(t, err)
+	assert.Equal(t, ActivationFired, activation.Status)
+
+	// the rule waiting for its approval does not match.
+	refund = &ApprovalRefund{Amount: 5000}
+	dctx = ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Refund", refund))
+	kb, err = lib.NewKnowledgeBaseInstance("Refunds", "1.0.0")
+	assert.NoError(t, err)
+	matching, err := eng.FetchMatchingRulesWithContext(ctx, dctx, kb)
+	assert.NoError(t, err)
+	if assert.Len(t, matching, 1) {
+		assert.Equal(t, "Notify", matching[0].RuleName)
+	}
+	activation, err = eng.Approvals.Activation("refund-1/Refunds/LargeRefund")
+	assert.NoError(t, err)
+	if assert.NotNil(t, activation) {
+		assert.NoError(t, activation.Accept("checked by finance"))
+	}
+	matching, err = eng.FetchMatchingRulesWithContext(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.Len(t, matching, 2)
+}
@@ -0,0 +1,684 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+const (
+	// BatchChunkSize is the number of facts evaluated together by the columnar evaluation of ExecuteBatch.
+	BatchChunkSize = 4096
+)
+
+// completeResetter is implemented by data contexts that can be reused after Complete has been called.
+type completeResetter interface {
+	ResetComplete()
+}
+
+// ExecuteBatch executes the knowledge base once for every fact in the batch. The batch must be a slice of structs
+// or a slice of pointers to structs. Before each execution the fact is added into the data context under factName,
+// replacing the previous fact of the batch, so rules can modify it in place.
+//
+// If the when scope of every rule is built only from comparisons between fields of the batch fact and constants,
+// combined with &&, || and !, the first cycle is evaluated column by column across the batch without reflection
+// per fact. Facts that can not match any rule are then skipped without running the engine, and they do not
+// notify the listeners. Any other rule, or a rule having an else scope, makes the whole batch evaluated one fact
+// at a time.
+func (g *GruleEngine) ExecuteBatch(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase, factName string, batch interface{}) error {
+	if knowledge == nil || dataCtx == nil {
+
+		return fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
+	}
+	batchValue := reflect.ValueOf(batch)
+	if batchValue.Kind() != reflect.Slice {
+
+		return fmt.Errorf("batch must be a slice, got %T", batch)
+	}
+	elemType := batchValue.Type().Elem()
+	pointers := elemType.Kind() == reflect.Ptr
+	if pointers {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+
+		return fmt.Errorf("batch must be a slice of struct or pointer to struct, got %T", batch)
+	}
+
+	startTime := time.Now()
+	vectorizer := newBatchVectorizer(knowledge, factName, elemType, pointers)
+	if vectorizer == nil {
+		g.log().Debugf("Batch of %d facts will be evaluated fact by fact", batchValue.Len())
+	}
+
+	var skipped int
+	mask := make([]bool, 0, BatchChunkSize)
+	for start := 0; start < batchValue.Len(); start += BatchChunkSize {
+		end := start + BatchChunkSize
+		if end > batchValue.Len() {
+			end = batchValue.Len()
+		}
+		mask = mask[:0]
+		if vectorizer != nil {
+			mask = vectorizer.candidates(batchValue, start, end, mask)
+		}
+		for i := start; i < end; i++ {
+			if ctx.Err() != nil {
+				g.log().Error("Context canceled")
+
+				return ctx.Err()
+			}
+			if len(mask) > 0 && !mask[i-start] {
+				skipped++
+				// a skipped fact is an execution where no rule fired, it counts toward the fire rates.
+				if g.Metrics != nil {
+					g.Metrics.recordExecution(knowledge.Name, knowledge.Version)
+				}
+
+				continue
+			}
+			fact := batchValue.Index(i)
+			if !pointers {
+				fact = fact.Addr()
+			}
+			err := dataCtx.Add(factName, fact.Interface())
+			if err != nil {
+
+				return err
+			}
+			dataCtx.Reset()
+			if resetter, ok := dataCtx.(completeResetter); ok {
+				resetter.ResetComplete()
+			}
+			err = g.ExecuteWithContext(ctx, dataCtx, knowledge)
+			if err != nil {
+
+				return fmt.Errorf("error while executing fact #%d of the batch. got %w", i, err)
+			}
+		}
+	}
+	g.log().Debugf("Finished batch execution of %d facts, %d skipped by columnar evaluation. Duration %d ms.", batchValue.Len(), skipped, time.Since(startTime).Milliseconds())
+
+	return nil
+}
+
+// batchVectorizer evaluates the when scope of all rules across a chunk of facts at once.
+type batchVectorizer struct {
+	factName string
+	elemType reflect.Type
+	pointers bool
+	rules    []batchPredicate
+
+	// facts holds the address of every fact in the current chunk.
+	facts   []unsafe.Pointer
+	columns map[batchColumnKey]interface{}
+}
+
+// batchPredicate fills out with the boolean result of an expression for every fact in the current chunk.
+type batchPredicate func(out []bool)
+
+type batchColumnKey struct {
+	offset uintptr
+	class  reflect.Kind
+}
+
+// batchOperand is either a primitive field of the batch fact or a constant.
+type batchOperand struct {
+	field    *reflect.StructField
+	offset   uintptr
+	constant reflect.Value
+}
+
+// class returns the kind used to read or compare the operand, Int64, Uint64, Float64, String or Bool.
+func (o *batchOperand) class() reflect.Kind {
+	kind := o.constant.Kind()
+	if o.field != nil {
+		kind = o.field.Type.Kind()
+	}
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return reflect.Int64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		return reflect.Uint64
+	case reflect.Float32, reflect.Float64:
+
+		return reflect.Float64
+	case reflect.String, reflect.Bool:
+
+		return kind
+	}
+
+	return reflect.Invalid
+}
+
+// newBatchVectorizer compiles the when scope of every rule in the knowledge base.
+// It returns nil if any of them can not be evaluated column by column, or has an else scope.
+func newBatchVectorizer(knowledge *ast.KnowledgeBase, factName string, elemType reflect.Type, pointers bool) *batchVectorizer {
+	vectorizer := &batchVectorizer{
+		factName: factName,
+		elemType: elemType,
+		pointers: pointers,
+		rules:    make([]batchPredicate, 0, len(knowledge.RuleEntries)),
+		facts:    make([]unsafe.Pointer, 0, BatchChunkSize),
+		columns:  make(map[batchColumnKey]interface{}),
+	}
+	for _, ruleEntry := range knowledge.RuleEntries {
+		if ruleEntry.Deleted {
+
+			continue
+		}
+		if ruleEntry.WhenScope == nil || ruleEntry.WhenScope.Expression == nil {
+
+			return nil
+		}
+		if ruleEntry.ElseScope != nil {
+			// a rule having an else scope fires whether its condition holds or not, every fact is a candidate.
+			log.Debugf("Rule %s has an else scope, it can not be evaluated in columnar mode", ruleEntry.RuleName)
+
+			return nil
+		}
+		predicate := vectorizer.compileExpression(ruleEntry.WhenScope.Expression)
+		if predicate == nil {
+			log.Debugf("Rule %s can not be evaluated in columnar mode", ruleEntry.RuleName)
+
+			return nil
+		}
+		vectorizer.rules = append(vectorizer.rules, predicate)
+	}
+
+	return vectorizer
+}
+
+// candidates appends to mask whether each fact between start and end may match at least one rule.
+func (v *batchVectorizer) candidates(batchValue reflect.Value, start, end int, mask []bool) []bool {
+	v.facts = v.facts[:0]
+	for i := start; i < end; i++ {
+		fact := batchValue.Index(i)
+		if v.pointers {
+			if fact.IsNil() {
+				// a nil fact must fail the same way as it does in the scalar evaluation.
+				for j := start; j < end; j++ {
+					mask = append(mask, true)
+				}
+
+				return mask
+			}
+			v.facts = append(v.facts, fact.UnsafePointer())
+		} else {
+			v.facts = append(v.facts, fact.Addr().UnsafePointer())
+		}
+	}
+	for k := range v.columns {
+		delete(v.columns, k)
+	}
+
+	for range v.facts {
+		mask = append(mask, false)
+	}
+	result := make([]bool, len(v.facts))
+	for _, rule := range v.rules {
+		rule(result)
+		for i, can := range result {
+			mask[i] = mask[i] || can
+		}
+	}
+
+	return mask
+}
+
+func (v *batchVectorizer) compileExpression(expr *ast.Expression) batchPredicate {
+	switch {
+	case expr.ExpressionAtom != nil:
+
+		return v.compileBoolAtom(expr.ExpressionAtom)
+	case expr.SingleExpression != nil:
+		single := v.compileExpression(expr.SingleExpression)
+		if single == nil || !expr.Negated {
+
+			return single
+		}
+
+		return func(out []bool) {
+			single(out)
+			for i := range out {
+				out[i] = !out[i]
+			}
+		}
+	case expr.LeftExpression != nil && expr.RightExpression != nil:
+		switch expr.Operator {
+		case ast.OpAnd, ast.OpOr:
+
+			return v.compileLogic(expr)
+		case ast.OpGT, ast.OpLT, ast.OpGTE, ast.OpLTE, ast.OpEq, ast.OpNEq:
+
+			return v.compileComparison(expr)
+		}
+	}
+
+	return nil
+}
+
+func (v *batchVectorizer) compileLogic(expr *ast.Expression) batchPredicate {
+	left := v.compileExpression(expr.LeftExpression)
+	right := v.compileExpression(expr.RightExpression)
+	if left == nil || right == nil {
+
+		return nil
+	}
+	and := expr.Operator == ast.OpAnd
+	var buffer []bool
+
+	return func(out []bool) {
+		left(out)
+		if cap(buffer) < len(out) {
+			buffer = make([]bool, len(out))
+		}
+		buffer = buffer[:len(out)]
+		right(buffer)
+		for i := range out {
+			if and {
+				out[i] = out[i] && buffer[i]
+			} else {
+				out[i] = out[i] || buffer[i]
+			}
+		}
+	}
+}
+
+// compileBoolAtom compiles an atom used directly as a boolean, such as Fact.Active or !Fact.Active.
+func (v *batchVectorizer) compileBoolAtom(atom *ast.ExpressionAtom) batchPredicate {
+	if atom.ExpressionAtom != nil && atom.FunctionCall == nil && len(atom.VariableName) == 0 && atom.ArrayMapSelector == nil {
+		inner := v.compileBoolAtom(atom.ExpressionAtom)
+		if inner == nil || !atom.Negated {
+
+			return inner
+		}
+
+		return func(out []bool) {
+			inner(out)
+			for i := range out {
+				out[i] = !out[i]
+			}
+		}
+	}
+	operand := v.compileOperand(atom)
+	if operand == nil || operand.class() != reflect.Bool {
+
+		return nil
+	}
+	if operand.field == nil {
+		value := operand.constant.Bool()
+
+		return func(out []bool) {
+			for i := range out {
+				out[i] = value
+			}
+		}
+	}
+
+	return func(out []bool) {
+		copy(out, v.boolColumn(operand))
+	}
+}
+
+// compileOperand resolves an atom into a field of the batch fact or a constant.
+func (v *batchVectorizer) compileOperand(atom *ast.ExpressionAtom) *batchOperand {
+	if atom.Constant != nil {
+		if atom.Constant.IsNil || !atom.Constant.Value.IsValid() {
+
+			return nil
+		}
+		operand := &batchOperand{constant: atom.Constant.Value}
+		if operand.class() == reflect.Invalid {
+
+			return nil
+		}
+
+		return operand
+	}
+	if atom.Variable == nil {
+
+		return nil
+	}
+	path := make([]string, 0)
+	for variable := atom.Variable; variable != nil; variable = variable.Variable {
+		if variable.ArrayMapSelector != nil || len(variable.Name) == 0 {
+
+			return nil
+		}
+		path = append([]string{variable.Name}, path...)
+	}
+	if len(path) < 2 || path[0] != v.factName {
+
+		return nil
+	}
+
+	var offset uintptr
+	structType := v.elemType
+	var field reflect.StructField
+	for _, name := range path[1:] {
+		if structType.Kind() != reflect.Struct {
+
+			return nil
+		}
+		found, ok := structType.FieldByName(name)
+		if !ok {
+
+			return nil
+		}
+		// promoted fields must not be reached through an embedded pointer.
+		embedded := structType
+		for _, index := range found.Index[:len(found.Index)-1] {
+			embeddedField := embedded.Field(index)
+			if embeddedField.Type.Kind() != reflect.Struct {
+
+				return nil
+			}
+			offset += embeddedField.Offset
+			embedded = embeddedField.Type
+		}
+		offset += found.Offset
+		field = found
+		structType = found.Type
+	}
+	operand := &batchOperand{field: &field, offset: offset}
+	if operand.class() == reflect.Invalid {
+
+		return nil
+	}
+
+	return operand
+}
+
+// comparisonClass returns the kind both operands are compared in, following the conversions of pkg.EvaluateEqual
+// and its siblings, or Invalid if the comparison is not supported by the columnar evaluation.
+func comparisonClass(left, right reflect.Kind, operator int) reflect.Kind {
+	numeric := func(kind reflect.Kind) bool {
+
+		return kind == reflect.Int64 || kind == reflect.Uint64 || kind == reflect.Float64
+	}
+	switch {
+	case numeric(left) && numeric(right):
+		if left == reflect.Float64 || right == reflect.Float64 {
+
+			return reflect.Float64
+		}
+		if left == reflect.Uint64 && right == reflect.Uint64 {
+
+			return reflect.Uint64
+		}
+
+		return reflect.Int64
+	case left == reflect.String && right == reflect.String:
+
+		return reflect.String
+	case left == reflect.Bool && right == reflect.Bool && (operator == ast.OpEq || operator == ast.OpNEq):
+
+		return reflect.Bool
+	}
+
+	return reflect.Invalid
+}
+
+func (v *batchVectorizer) compileComparison(expr *ast.Expression) batchPredicate {
+	if expr.LeftExpression.ExpressionAtom == nil || expr.RightExpression.ExpressionAtom == nil {
+
+		return nil
+	}
+	left := v.compileOperand(expr.LeftExpression.ExpressionAtom)
+	right := v.compileOperand(expr.RightExpression.ExpressionAtom)
+	if left == nil || right == nil || (left.field == nil && right.field == nil) {
+
+		return nil
+	}
+	operator := expr.Operator
+	class := comparisonClass(left.class(), right.class(), operator)
+	switch class {
+	case reflect.Int64:
+
+		return compileTypedComparison(v, left, right, operator, v.int64Column, reflect.Value.Int)
+	case reflect.Uint64:
+
+		return compileTypedComparison(v, left, right, operator, v.uint64Column, reflect.Value.Uint)
+	case reflect.Float64:
+
+		return compileTypedComparison(v, left, right, operator, v.float64Column, func(val reflect.Value) float64 {
+			switch val.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+				return float64(val.Int())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+				return float64(val.Uint())
+			}
+
+			return val.Float()
+		})
+	case reflect.String:
+
+		return compileTypedComparison(v, left, right, operator, v.stringColumn, reflect.Value.String)
+	case reflect.Bool:
+
+		return compileBoolComparison(v, left, right, operator)
+	}
+
+	return nil
+}
+
+type batchOrdered interface {
+	~int64 | ~uint64 | ~float64 | ~string
+}
+
+// compileTypedComparison builds the comparison loop for operands read as T. constant converts a constant operand into T.
+func compileTypedComparison[T batchOrdered](v *batchVectorizer, left, right *batchOperand, operator int,
+	column func(*batchOperand) []T, constant func(reflect.Value) T) batchPredicate {
+	compare := batchComparator[T](operator)
+	if compare == nil {
+
+		return nil
+	}
+	switch {
+	case left.field != nil && right.field != nil:
+
+		return func(out []bool) {
+			lvals, rvals := column(left), column(right)
+			for i := range out {
+				out[i] = compare(lvals[i], rvals[i])
+			}
+		}
+	case left.field != nil:
+		rval := constant(right.constant)
+
+		return func(out []bool) {
+			lvals := column(left)
+			for i := range out {
+				out[i] = compare(lvals[i], rval)
+			}
+		}
+	default:
+		lval := constant(left.constant)
+
+		return func(out []bool) {
+			rvals := column(right)
+			for i := range out {
+				out[i] = compare(lval, rvals[i])
+			}
+		}
+	}
+}
+
+func batchComparator[T batchOrdered](operator int) func(a, b T) bool {
+	switch operator {
+	case ast.OpGT:
+
+		return func(a, b T) bool { return a > b }
+	case ast.OpLT:
+
+		return func(a, b T) bool { return a < b }
+	case ast.OpGTE:
+
+		return func(a, b T) bool { return a >= b }
+	case ast.OpLTE:
+
+		return func(a, b T) bool { return a <= b }
+	case ast.OpEq:
+
+		return func(a, b T) bool { return a == b }
+	case ast.OpNEq:
+
+		return func(a, b T) bool { return a != b }
+	}
+
+	return nil
+}
+
+func compileBoolComparison(v *batchVectorizer, left, right *batchOperand, operator int) batchPredicate {
+	equal := operator == ast.OpEq
+	read := func(operand *batchOperand, i int, values []bool) bool {
+		if operand.field == nil {
+
+			return operand.constant.Bool()
+		}
+
+		return values[i]
+	}
+
+	return func(out []bool) {
+		var lvals, rvals []bool
+		if left.field != nil {
+			lvals = v.boolColumn(left)
+		}
+		if right.field != nil {
+			rvals = v.boolColumn(right)
+		}
+		for i := range out {
+			out[i] = (read(left, i, lvals) == read(right, i, rvals)) == equal
+		}
+	}
+}
+
+// column returns the cached values of a field for the current chunk, reading them with read on first use.
+func column[T any](v *batchVectorizer, operand *batchOperand, class reflect.Kind, read func(field unsafe.Pointer) T) []T {
+	key := batchColumnKey{offset: operand.offset, class: class}
+	if values, ok := v.columns[key]; ok {
+
+		return values.([]T)
+	}
+	values := make([]T, len(v.facts))
+	for i, fact := range v.facts {
+		values[i] = read(unsafe.Add(fact, operand.offset))
+	}
+	v.columns[key] = values
+
+	return values
+}
+
+func (v *batchVectorizer) int64Column(operand *batchOperand) []int64 {
+	var read func(field unsafe.Pointer) int64
+	switch operand.field.Type.Kind() {
+	case reflect.Int8:
+		read = func(field unsafe.Pointer) int64 { return int64(*(*int8)(field)) }
+	case reflect.Int16:
+		read = func(field unsafe.Pointer) int64 { return int64(*(*int16)(field)) }
+	case reflect.Int32:
+		read = func(field unsafe.Pointer) int64 { return int64(*(*int32)(field)) }
+	case reflect.Int64:
+		read = func(field unsafe.Pointer) int64 { return *(*int64)(field) }
+	case reflect.Int:
+		read = func(field unsafe.Pointer) int64 { return int64(*(*int)(field)) }
+	default:
+		readUnsigned := uintReader(operand.field.Type.Kind())
+		read = func(field unsafe.Pointer) int64 { return int64(readUnsigned(field)) }
+	}
+
+	return column(v, operand, reflect.Int64, read)
+}
+
+func (v *batchVectorizer) uint64Column(operand *batchOperand) []uint64 {
+
+	return column(v, operand, reflect.Uint64, uintReader(operand.field.Type.Kind()))
+}
+
+func (v *batchVectorizer) float64Column(operand *batchOperand) []float64 {
+	var read func(field unsafe.Pointer) float64
+	switch operand.field.Type.Kind() {
+	case reflect.Float32:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*float32)(field)) }
+	case reflect.Float64:
+		read = func(field unsafe.Pointer) float64 { return *(*float64)(field) }
+	case reflect.Int8:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*int8)(field)) }
+	case reflect.Int16:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*int16)(field)) }
+	case reflect.Int32:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*int32)(field)) }
+	case reflect.Int64:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*int64)(field)) }
+	case reflect.Int:
+		read = func(field unsafe.Pointer) float64 { return float64(*(*int)(field)) }
+	default:
+		readUnsigned := uintReader(operand.field.Type.Kind())
+		read = func(field unsafe.Pointer) float64 { return float64(readUnsigned(field)) }
+	}
+
+	return column(v, operand, reflect.Float64, read)
+}
+
+func (v *batchVectorizer) stringColumn(operand *batchOperand) []string {
+
+	return column(v, operand, reflect.String, func(field unsafe.Pointer) string {
+
+		return *(*string)(field)
+	})
+}
+
+func (v *batchVectorizer) boolColumn(operand *batchOperand) []bool {
+
+	return column(v, operand, reflect.Bool, func(field unsafe.Pointer) bool {
+
+		return *(*bool)(field)
+	})
+}
+
+// uintReader returns the function reading an unsigned field of the specified kind.
+func uintReader(kind reflect.Kind) func(field unsafe.Pointer) uint64 {
+	switch kind {
+	case reflect.Uint8:
+
+		return func(field unsafe.Pointer) uint64 { return uint64(*(*uint8)(field)) }
+	case reflect.Uint16:
+
+		return func(field unsafe.Pointer) uint64 { return uint64(*(*uint16)(field)) }
+	case reflect.Uint32:
+
+		return func(field unsafe.Pointer) uint64 { return uint64(*(*uint32)(field)) }
+	case reflect.Uint64:
+
+		return func(field unsafe.Pointer) uint64 { return *(*uint64)(field) }
+	case reflect.Uintptr:
+
+		return func(field unsafe.Pointer) uint64 { return uint64(*(*uintptr)(field)) }
+	}
+
+	return func(field unsafe.Pointer) uint64 { return uint64(*(*uint)(field)) }
+}
@@ -0,0 +1,199 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type BatchAudit struct {
+	Checked bool
+}
+
+type BatchRow struct {
+	BatchAudit
+	ID     int
+	Score  float32
+	Count  uint16
+	Region string
+	Active bool
+	Grade  string
+}
+
+const batchRules = `
+rule HighScore "Grade high scores in active rows" salience 10 {
+	when
+		Row.Active && Row.Score >= 80 && Row.Grade == ""
+	then
+		Row.Grade = "A";
+}
+
+rule Regional "Grade rows of a region" {
+	when
+		(Row.Region == "EU" || Row.Count > 100) && !Row.Checked && 3 < Row.ID
+	then
+		Row.Checked = true;
+}
+`
+
+func newBatchRows(n int) []BatchRow {
+	rows := make([]BatchRow, n)
+	for i := range rows {
+		rows[i] = BatchRow{
+			ID:     i,
+			Score:  float32(i % 100),
+			Count:  uint16(i % 150),
+			Region: []string{"EU", "US", "APAC"}[i%3],
+			Active: i%2 == 0,
+		}
+	}
+
+	return rows
+}
+
+func buildBatchKnowledge(t *testing.T, grl string) *ast.KnowledgeBase {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Batch", "0.0.1", pkg.NewBytesResource([]byte(grl)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("Batch", "0.0.1")
+	assert.NoError(t, err)
+
+	return kb
+}
+
+func TestGruleEngine_ExecuteBatch(t *testing.T) {
+	kb := buildBatchKnowledge(t, batchRules)
+	assert.NotNil(t, newBatchVectorizer(kb, "Row", reflect.TypeOf(BatchRow{}), false))
+
+	rows := newBatchRows(BatchChunkSize + 500)
+	engine := NewGruleEngine()
+	err := engine.ExecuteBatch(context.Background(), ast.NewDataContext(), kb, "Row", rows)
+	assert.NoError(t, err)
+
+	// the result must be identical to executing each row on its own.
+	expected := newBatchRows(len(rows))
+	for i := range expected {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Row", &expected[i]))
+		assert.NoError(t, engine.Execute(dctx, kb))
+	}
+	assert.Equal(t, expected, rows)
+	assert.Equal(t, "A", rows[80].Grade)
+	assert.Equal(t, "", rows[81].Grade)
+	assert.True(t, rows[6].Checked)
+	assert.False(t, rows[4].Checked)
+}
+
+func TestGruleEngine_ExecuteBatchPointers(t *testing.T) {
+	kb := buildBatchKnowledge(t, batchRules)
+	rows := newBatchRows(300)
+	pointers := make([]*BatchRow, len(rows))
+	for i := range rows {
+		pointers[i] = &rows[i]
+	}
+	err := NewGruleEngine().ExecuteBatch(context.Background(), ast.NewDataContext(), kb, "Row", pointers)
+	assert.NoError(t, err)
+	assert.Equal(t, "A", rows[280].Grade)
+	assert.True(t, rows[6].Checked)
+}
+
+func TestGruleEngine_ExecuteBatchFallback(t *testing.T) {
+	kb := buildBatchKnowledge(t, `
+rule Length "Uses a function" {
+	when
+		Row.Region.Len() == 4 && Row.Grade == ""
+	then
+		Row.Grade = "APAC";
+}`)
+	assert.Nil(t, newBatchVectorizer(kb, "Row", reflect.TypeOf(BatchRow{}), false))
+
+	rows := newBatchRows(10)
+	err := NewGruleEngine().ExecuteBatch(context.Background(), ast.NewDataContext(), kb, "Row", rows)
+	assert.NoError(t, err)
+	assert.Equal(t, "APAC", rows[2].Grade)
+	assert.Equal(t, "", rows[3].Grade)
+
+	err = NewGruleEngine().ExecuteBatch(context.Background(), ast.NewDataContext(), kb, "Row", rows[0])
+	assert.Error(t, err)
+}
+
+type BatchElseRow struct {
+	N int
+	R string
+}
+
+func TestGruleEngine_ExecuteBatchElse(t *testing.T) {
+	kb := buildBatchKnowledge(t, `
+rule Else "Labels every row" {
+	when
+		B.N > 10
+	then
+		B.R = "hi";
+		Retract("Else");
+	else {
+		B.R = "lo";
+		Retract("Else");
+	}
+}`)
+	// the rows not matching the condition still run the else scope, they can not be filtered out.
+	assert.Nil(t, newBatchVectorizer(kb, "B", reflect.TypeOf(BatchElseRow{}), false))
+
+	rows := []BatchElseRow{{N: 1}, {N: 20}}
+	err := NewGruleEngine().ExecuteBatch(context.Background(), ast.NewDataContext(), kb, "B", rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []BatchElseRow{{N: 1, R: "lo"}, {N: 20, R: "hi"}}, rows)
+}
+
+func TestBatchVectorizer_Candidates(t *testing.T) {
+	rows := newBatchRows(200)
+	for _, when := range []string{
+		"Row.Score > 50",
+		"Row.Score <= 50.5",
+		"Row.Count != 7 && Row.ID >= 20",
+		"Row.ID == Row.Count",
+		"Row.Count < Row.Score",
+		"Row.Region > \"EU\"",
+		"Row.Active == false",
+		"!(Row.Active || Row.ID < 10)",
+		"Row.Checked",
+		"-3 > Row.ID",
+	} {
+		kb := buildBatchKnowledge(t, fmt.Sprintf(`rule Test { when %s then Retract("Test"); }`, when))
+		vectorizer := newBatchVectorizer(kb, "Row", reflect.TypeOf(BatchRow{}), false)
+		if !assert.NotNil(t, vectorizer, when) {
+
+			continue
+		}
+		mask := vectorizer.candidates(reflect.ValueOf(rows), 0, len(rows), nil)
+		for i := range rows {
+			dctx := ast.NewDataContext()
+			assert.NoError(t, dctx.Add("Row", &rows[i]))
+			kb.WorkingMemory.ResetAll()
+			kb.InitializeContext(dctx)
+			can, err := kb.RuleEntries["Test"].Evaluate(context.Background(), dctx, kb.WorkingMemory)
+			assert.NoError(t, err)
+			assert.Equal(t, can, mask[i], "%s on row %d", when, i)
+		}
+	}
+}
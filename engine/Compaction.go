@@ -0,0 +1,47 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// excludedByRuleTables looks up the rule tables of the knowledge base and returns the rule entries that can not match
+// the facts, they need not be evaluated in this cycle. It returns nil if the engine does not compact rules.
+func (g *GruleEngine) excludedByRuleTables(dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) map[*ast.RuleEntry]bool {
+	if !g.CompactRules {
+
+		return nil
+	}
+	var excluded map[*ast.RuleEntry]bool
+	for _, table := range knowledge.RuleTables() {
+		candidates, ok := table.Candidates(dataCtx, knowledge.WorkingMemory)
+		if !ok {
+			// the rules of the table are evaluated one by one, as if they were not compacted.
+			continue
+		}
+		if excluded == nil {
+			excluded = make(map[*ast.RuleEntry]bool)
+		}
+		for _, member := range table.Members {
+			excluded[member] = true
+		}
+		for _, candidate := range candidates {
+			delete(excluded, candidate)
+		}
+	}
+
+	return excluded
+}
@@ -0,0 +1,125 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type CompactionFact struct {
+	Code   int
+	Amount float64
+	Rate   float64
+	Done   bool
+}
+
+type evaluationCounter struct {
+	evaluated int
+}
+
+func (c *evaluationCounter) EvaluateRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry, candidate bool) {
+	c.evaluated++
+}
+
+func (c *evaluationCounter) ExecuteRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry) {
+}
+
+func (c *evaluationCounter) BeginCycle(ctx context.Context, cycle uint64) {}
+
+// compactionRules generates one rule per code, they differ only in the code and the rate.
+func compactionRules(codes int) string {
+	var grl strings.Builder
+	for i := 0; i < codes; i++ {
+		grl.WriteString(fmt.Sprintf(`
+rule Code%d {
+	when
+		!Fact.Done && Fact.Code == %d && Fact.Amount > 100
+	then
+		Fact.Rate = %d.5;
+		Fact.Done = true;
+}`, i, i, i))
+	}
+	grl.WriteString(`
+rule Other {
+	when
+		Fact.Amount < 0
+	then
+		Fact.Done = true;
+}`)
+
+	return grl.String()
+}
+
+func TestKnowledgeBase_RuleTables(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Compaction", "1.0.0", pkg.NewBytesResource([]byte(compactionRules(50)))))
+	kb, err := lib.NewKnowledgeBaseInstance("Compaction", "1.0.0")
+	assert.NoError(t, err)
+
+	tables := kb.RuleTables()
+	assert.Len(t, tables, 1)
+	assert.Len(t, tables[0].Members, 50)
+	assert.Len(t, tables[0].Rules, 50)
+	assert.Equal(t, "Code7", tables[0].Rules[float64(7)][0].RuleName)
+
+	kb.RemoveRuleEntry("Code7")
+	assert.Len(t, kb.RuleTables()[0].Members, 49)
+}
+
+func TestGruleEngine_CompactRules(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Compaction", "1.0.0", pkg.NewBytesResource([]byte(compactionRules(50)))))
+
+	execute := func(eng *GruleEngine, fact *CompactionFact) int {
+		counter := &evaluationCounter{}
+		eng.Listeners = []GruleEngineListener{counter}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		kb, err := lib.NewKnowledgeBaseInstance("Compaction", "1.0.0")
+		assert.NoError(t, err)
+		assert.NoError(t, eng.Execute(dctx, kb))
+
+		return counter.evaluated
+	}
+
+	eng := NewGruleEngine()
+	fact := &CompactionFact{Code: 42, Amount: 200}
+	assert.Equal(t, 2*51, execute(eng, fact))
+	assert.Equal(t, 42.5, fact.Rate)
+
+	eng.CompactRules = true
+	fact = &CompactionFact{Code: 42, Amount: 200}
+	assert.Equal(t, 2*2, execute(eng, fact), "only Code42 and Other are evaluated in both cycles")
+	assert.Equal(t, 42.5, fact.Rate)
+	assert.True(t, fact.Done)
+
+	fact = &CompactionFact{Code: 99, Amount: 200}
+	assert.Equal(t, 1, execute(eng, fact))
+	assert.False(t, fact.Done)
+
+	fact = &CompactionFact{Code: 3, Amount: 50}
+	execute(eng, fact)
+	assert.False(t, fact.Done, "the other conditions of the compacted rules still hold")
+}
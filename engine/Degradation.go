@@ -0,0 +1,60 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// SetDegraded switches the engine in or out of degraded mode. A degraded engine skips the rules of low criticality,
+// so expensive enrichments can be shed under load while the mandatory checks keep being evaluated.
+// It is safe to switch while executions are running, they see the change from their next execution.
+func (g *GruleEngine) SetDegraded(degraded bool) {
+	var flag int32
+	if degraded {
+		flag = 1
+	}
+	atomic.StoreInt32(&g.degraded, flag)
+}
+
+// IsDegraded tells whether the engine is in degraded mode.
+func (g *GruleEngine) IsDegraded() bool {
+
+	return atomic.LoadInt32(&g.degraded) == 1
+}
+
+// shedsLowCriticality tells whether the rules of low criticality are skipped by this execution, which are then logged.
+func (g *GruleEngine) shedsLowCriticality(knowledge *ast.KnowledgeBase) bool {
+	if !g.IsDegraded() {
+
+		return false
+	}
+	skipped := make([]string, 0)
+	for _, ruleEntry := range knowledge.RuleEntries {
+		if ruleEntry.Criticality == ast.CriticalityLow && !ruleEntry.Deleted {
+			skipped = append(skipped, ruleEntry.RuleName)
+		}
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		g.log().Warnf("Degraded mode, skipping %d low criticality rules of knowledge '%s' version %s : %s", len(skipped), knowledge.Name, knowledge.Version, strings.Join(skipped, ", "))
+	}
+
+	return true
+}
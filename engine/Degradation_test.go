@@ -0,0 +1,105 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type DegradationFact struct {
+	Enriched  bool
+	Checked   bool
+	Validated bool
+}
+
+const degradationRules = `
+rule Enrich "expensive enrichment" criticality low {
+	when
+		!Fact.Enriched
+	then
+		Fact.Enriched = true;
+}
+rule Check "mandatory compliance check" salience 10 criticality HIGH {
+	when
+		!Fact.Checked
+	then
+		Fact.Checked = true;
+}
+rule Validate "no declared criticality" {
+	when
+		!Fact.Validated
+	then
+		Fact.Validated = true;
+}`
+
+func TestGruleEngine_Degraded(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Degradation", "1.0.0", pkg.NewBytesResource([]byte(degradationRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("Degradation", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, ast.CriticalityLow, kb.RuleEntries["Enrich"].Criticality)
+	assert.Equal(t, ast.CriticalityHigh, kb.RuleEntries["Check"].Criticality)
+	assert.Equal(t, ast.CriticalityNormal, kb.RuleEntries["Validate"].Criticality)
+
+	eng := NewGruleEngine()
+	execute := func() *DegradationFact {
+		fact := &DegradationFact{}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		kb, err := lib.NewKnowledgeBaseInstance("Degradation", "1.0.0")
+		assert.NoError(t, err)
+		assert.NoError(t, eng.Execute(dctx, kb))
+
+		return fact
+	}
+
+	fact := execute()
+	assert.True(t, fact.Enriched)
+	assert.True(t, fact.Checked)
+	assert.True(t, fact.Validated)
+
+	eng.SetDegraded(true)
+	assert.True(t, eng.IsDegraded())
+	fact = execute()
+	assert.False(t, fact.Enriched)
+	assert.True(t, fact.Checked)
+	assert.True(t, fact.Validated)
+
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", &DegradationFact{}))
+	matching, err := eng.FetchMatchingRules(dctx, kb)
+	assert.NoError(t, err)
+	assert.Len(t, matching, 2)
+
+	eng.SetDegraded(false)
+	assert.True(t, execute().Enriched)
+}
+
+func TestGruleEngine_CriticalityErrors(t *testing.T) {
+	testData := []string{
+		`rule A criticality urgent { when true then Retract("A"); }`,
+		`rule A severity low { when true then Retract("A"); }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		assert.Error(t, rb.BuildRuleFromResource("Criticality", "1", pkg.NewBytesResource([]byte(grl))), grl)
+	}
+}
@@ -0,0 +1,59 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Enricher derives facts from the facts of the data context before the rules are evaluated, such as the age of an
+// applicant from their birth date or the country of a request from its IP address.
+type Enricher struct {
+	// Name identifies the enricher in the traces and the errors.
+	Name string
+	// Enrich returns the derived facts by the name they are added under, nil if there is nothing to add.
+	Enrich func(ctx context.Context, dataCtx ast.IDataContext) (map[string]interface{}, error)
+}
+
+// enrich runs the Enrichers of the engine in their order, each seeing the facts added by the ones before it.
+// A derived fact replaces the fact of the same name, such as the one derived by a previous execution.
+func (g *GruleEngine) enrich(ctx context.Context, dataCtx ast.IDataContext) error {
+	for _, enricher := range g.Enrichers {
+		facts, err := enricher.Enrich(ctx, dataCtx)
+		if err != nil {
+
+			return fmt.Errorf("error while enriching the facts with %s. got %w", enricher.Name, err)
+		}
+		if err := dataCtx.AddAll(facts); err != nil {
+
+			return fmt.Errorf("error while adding the facts of %s. got %w", enricher.Name, err)
+		}
+		names := make([]string, 0, len(facts))
+		for name := range facts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		g.log().Debugf("Enricher %s added facts %v", enricher.Name, names)
+		if trace := traceFrom(ctx); trace != nil {
+			trace.record(TraceEvent{Kind: TraceEnrich, Enricher: enricher.Name, Facts: names})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type EnrichedApplicant struct {
+	BirthDate time.Time
+	Approved  bool
+}
+
+type ApplicantProfile struct {
+	Age   int
+	Adult bool
+}
+
+const enrichmentRules = `
+rule Approve "adults are approved" {
+	when
+		Profile.Adult && !Applicant.Approved
+	then
+		Applicant.Approved = true;
+}`
+
+func TestGruleEngine_Enrichers(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Enrichment", "1.0.0", pkg.NewBytesResource([]byte(enrichmentRules))))
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	traces := make([]*Trace, 0)
+	eng := NewGruleEngine()
+	eng.Tracer = NewTracer(1, 0)
+	eng.Tracer.Sink = func(trace *Trace) {
+		traces = append(traces, trace)
+	}
+	eng.Enrichers = []Enricher{{
+		Name: "age",
+		Enrich: func(ctx context.Context, dataCtx ast.IDataContext) (map[string]interface{}, error) {
+			value, err := dataCtx.Get("Applicant").GetValue()
+			if err != nil {
+
+				return nil, err
+			}
+			applicant := value.Interface().(*EnrichedApplicant)
+
+			return map[string]interface{}{"Profile": &ApplicantProfile{Age: now.Year() - applicant.BirthDate.Year()}}, nil
+		},
+	}, {
+		Name: "adult",
+		Enrich: func(ctx context.Context, dataCtx ast.IDataContext) (map[string]interface{}, error) {
+			// the profile derived by the enricher before.
+			value, err := dataCtx.Get("Profile").GetValue()
+			if err != nil {
+
+				return nil, err
+			}
+			profile := value.Interface().(*ApplicantProfile)
+			profile.Adult = profile.Age >= 18
+
+			return nil, nil
+		},
+	}}
+	execute := func(applicant *EnrichedApplicant) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Applicant", applicant))
+		kb, err := lib.NewKnowledgeBaseInstance("Enrichment", "1.0.0")
+		assert.NoError(t, err)
+
+		return eng.Execute(dctx, kb)
+	}
+
+	adult := &EnrichedApplicant{BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.NoError(t, execute(adult))
+	assert.True(t, adult.Approved)
+	minor := &EnrichedApplicant{BirthDate: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.NoError(t, execute(minor))
+	assert.False(t, minor.Approved)
+
+	assert.Len(t, traces, 2)
+	assert.Equal(t, TraceEvent{Kind: TraceEnrich, Enricher: "age", Facts: []string{"Profile"}}, traces[0].Events[0])
+	assert.Equal(t, TraceEvent{Kind: TraceEnrich, Enricher: "adult", Facts: []string{}}, traces[0].Events[1])
+	assert.Contains(t, traces[0].String(), "enrich age : Profile")
+
+	failure := errors.New("geo database unavailable")
+	eng.Enrichers = append(eng.Enrichers, Enricher{
+		Name: "geo",
+		Enrich: func(ctx context.Context, dataCtx ast.IDataContext) (map[string]interface{}, error) {
+
+			return nil, failure
+		},
+	})
+	err := execute(&EnrichedApplicant{})
+	assert.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), "geo")
+}
@@ -0,0 +1,116 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// EngineError is the fact the engine records the evaluation errors of the rules into, when its ErrorFact is set.
+// Rules react to it like to any other fact, such as a rule falling back to a manual review
+// when EngineError.Failed("Score").
+type EngineError struct {
+	// Rule is the name of the rule that failed last.
+	Rule string
+	// Message is the error of the rule that failed last.
+	Message string
+	// Count is the number of rules that failed.
+	Count int
+	// Rules are the names of the rules that failed, in the order they failed first.
+	Rules []string
+}
+
+// Failed tells whether the when scope of the rule failed to evaluate during this execution.
+func (e *EngineError) Failed(rule string) bool {
+	for _, name := range e.Rules {
+		if name == rule {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorFactTracker records the evaluation errors into the error fact, once per rule and execution.
+type errorFactTracker struct {
+	name string
+	fact *EngineError
+	// recorded is true if an error was recorded since the current cycle started.
+	recorded bool
+}
+
+// prepareErrorFact adds an empty EngineError into the data context and returns the tracker recording the errors into
+// it, nil if the engine has no error fact. A fact the caller added under the same name is kept, the errors are then
+// handled as if the engine had no error fact.
+func (g *GruleEngine) prepareErrorFact(dataCtx ast.IDataContext) (*errorFactTracker, error) {
+	if len(g.ErrorFact) == 0 {
+
+		return nil, nil
+	}
+	if existing := dataCtx.Get(g.ErrorFact); existing != nil {
+		if _, ok := existing.Value().Interface().(*EngineError); !ok {
+			g.log().Warnf("Fact %s shadows the error fact of the engine, rename the fact or change the ErrorFact of the engine", g.ErrorFact)
+
+			return nil, nil
+		}
+	}
+	tracker := &errorFactTracker{
+		name: g.ErrorFact,
+		fact: &EngineError{},
+	}
+	if err := dataCtx.Add(g.ErrorFact, tracker.fact); err != nil {
+
+		return nil, err
+	}
+
+	return tracker, nil
+}
+
+// record records the when scope error of the rule into the error fact and tells the rules reading it to evaluate
+// again. It returns false if the tracker is nil, the error is then handled by the caller.
+func (t *errorFactTracker) record(ruleName string, err error, dataCtx ast.IDataContext, memory *ast.WorkingMemory) bool {
+	if t == nil {
+
+		return false
+	}
+	if t.fact.Failed(ruleName) {
+
+		return true
+	}
+	t.fact.Rule = ruleName
+	t.fact.Message = err.Error()
+	t.fact.Count++
+	t.fact.Rules = append(t.fact.Rules, ruleName)
+	t.recorded = true
+	dataCtx.IncrementVariableChangeCount()
+	memory.Reset(t.name)
+
+	return true
+}
+
+// beginCycle forgets the errors recorded during the previous cycle.
+func (t *errorFactTracker) beginCycle() {
+	if t != nil {
+		t.recorded = false
+	}
+}
+
+// pending tells whether an error was recorded during this cycle, the rules reacting to it are then evaluated once
+// more before the execution ends.
+func (t *errorFactTracker) pending() bool {
+
+	return t != nil && t.recorded
+}
@@ -0,0 +1,109 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ErrorFactLoan struct {
+	Available bool
+	Decision  string
+}
+
+// Score stands for a scoring service, it panics when the service is not available.
+func (l *ErrorFactLoan) Score() int64 {
+	if !l.Available {
+		panic("scoring service unavailable")
+	}
+
+	return 700
+}
+
+const errorFactRules = `
+rule Approve "approves the loans scoring well" {
+	when
+		Loan.Decision == "" && Loan.Score() > 600
+	then
+		Loan.Decision = "approved";
+}
+rule Review "sends the loan to a manual review when it can not be scored" {
+	when
+		Loan.Decision == "" && EngineError.Failed("Approve")
+	then
+		Loan.Decision = "review " + EngineError.Message;
+}`
+
+func TestErrorFact(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("ErrorFact", "0.0.1", pkg.NewBytesResource([]byte(errorFactRules))))
+
+	parallel := NewGruleEngine()
+	parallel.ParallelEvaluation = 2
+	for _, eng := range []*GruleEngine{NewGruleEngine(), parallel} {
+		eng.ErrorFact = DefaultErrorFact
+		eng.ReturnErrOnFailedRuleEvaluation = true
+
+		kb, err := lib.NewKnowledgeBaseInstance("ErrorFact", "0.0.1")
+		assert.NoError(t, err)
+		loan := &ErrorFactLoan{}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Loan", loan))
+		assert.NoError(t, eng.Execute(dctx, kb))
+		assert.Contains(t, loan.Decision, "review ")
+		assert.Contains(t, loan.Decision, "scoring service unavailable")
+		failures := dctx.Get(DefaultErrorFact).Value().Interface().(*EngineError)
+		assert.Equal(t, []string{"Approve"}, failures.Rules)
+		assert.Equal(t, 1, failures.Count)
+		assert.Equal(t, "Approve", failures.Rule)
+
+		// the error fact is emptied on the next execution of the same data context.
+		loan.Available, loan.Decision = true, ""
+		kb, err = lib.NewKnowledgeBaseInstance("ErrorFact", "0.0.1")
+		assert.NoError(t, err)
+		assert.NoError(t, eng.Execute(dctx, kb))
+		assert.Equal(t, "approved", loan.Decision)
+		failures = dctx.Get(DefaultErrorFact).Value().Interface().(*EngineError)
+		assert.Equal(t, 0, failures.Count)
+	}
+}
+
+func TestErrorFact_Disabled(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("ErrorFact", "0.0.1", pkg.NewBytesResource([]byte(errorFactRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("ErrorFact", "0.0.1")
+	assert.NoError(t, err)
+
+	// without an error fact the evaluation error is returned as before.
+	eng := NewGruleEngine()
+	eng.ReturnErrOnFailedRuleEvaluation = true
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Loan", &ErrorFactLoan{}))
+	assert.Error(t, eng.Execute(dctx, kb))
+
+	// a fact of the caller under the same name shadows the error fact.
+	eng.ErrorFact = DefaultErrorFact
+	own := ast.NewDataContext()
+	assert.NoError(t, own.Add("Loan", &ErrorFactLoan{}))
+	assert.NoError(t, own.AddJSON(DefaultErrorFact, []byte(`{"Kept": true}`)))
+	kb, err = lib.NewKnowledgeBaseInstance("ErrorFact", "0.0.1")
+	assert.NoError(t, err)
+	assert.Error(t, eng.Execute(own, kb))
+}
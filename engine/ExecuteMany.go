@@ -0,0 +1,427 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// ExecuteMany function is the same as ExecuteManyWithContext(context.Background())
+func (g *GruleEngine) ExecuteMany(dataCtx ast.IDataContext, knowledges []*ast.KnowledgeBase) error {
+
+	return g.ExecuteManyWithContext(context.Background(), dataCtx, knowledges)
+}
+
+// ExecuteManyWithContext executes independent knowledge bases, such as validation, fraud and pricing, concurrently.
+// Each knowledge base is executed against its own copy of the facts of the data context, so none sees what the
+// others change. Once all are done, the changes are merged into the facts field by field, in the order of the
+// knowledge bases : if two of them change the same field differently, the change of the later one is kept and a
+// warning is logged. Facts a knowledge base adds are added into the data context too.
+//
+// If any execution fails, the facts are left unchanged and the error of the first failing knowledge base, in their
+// order, is returned. Unexported fields are copied as they are, a change made through them by a function is seen by
+// every knowledge base and not merged.
+func (g *GruleEngine) ExecuteManyWithContext(ctx context.Context, dataCtx ast.IDataContext, knowledges []*ast.KnowledgeBase) error {
+	if dataCtx == nil {
+
+		return fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
+	}
+	seen := make(map[*ast.KnowledgeBase]bool, len(knowledges))
+	for _, knowledge := range knowledges {
+		if knowledge == nil {
+
+			return fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
+		}
+		// an instance keeps the evaluated values in its nodes, it can not be executed twice at once.
+		if seen[knowledge] {
+
+			return fmt.Errorf("knowledge base '%s' version %s instance is given more than once", knowledge.Name, knowledge.Version)
+		}
+		seen[knowledge] = true
+	}
+
+	// base is what the facts were, each execution is merged from its difference with it.
+	base, err := g.copyFacts(dataCtx)
+	if err != nil {
+
+		return err
+	}
+	views := make([]ast.IDataContext, len(knowledges))
+	for i := range knowledges {
+		views[i], err = g.copyFacts(dataCtx)
+		if err != nil {
+
+			return err
+		}
+	}
+
+	errs := make([]error, len(knowledges))
+	var wg sync.WaitGroup
+	for i, knowledge := range knowledges {
+		wg.Add(1)
+		go func(i int, knowledge *ast.KnowledgeBase) {
+			defer wg.Done()
+			errs[i] = g.ExecuteWithContext(ctx, views[i], knowledge)
+		}(i, knowledge)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+
+			return fmt.Errorf("error while executing knowledge base '%s' version %s. got %w", knowledges[i].Name, knowledges[i].Version, err)
+		}
+	}
+
+	merger := &factMerger{changes: make(map[string]factChange), log: g.log()}
+	for i, knowledge := range knowledges {
+		merger.knowledge = fmt.Sprintf("'%s' version %s", knowledge.Name, knowledge.Version)
+		err = g.mergeFacts(merger, dataCtx, base, views[i])
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// managedFact tells whether the engine adds the fact itself at the start of every execution.
+func (g *GruleEngine) managedFact(name string) bool {
+
+	return name == "DEFUNC" || (len(g.Scratchpad) > 0 && name == g.Scratchpad) || (len(g.ErrorFact) > 0 && name == g.ErrorFact)
+}
+
+// copyFacts returns a new data context holding a deep copy of every fact of the data context, but the facts the engine
+// adds itself. The retracted facts stay retracted.
+func (g *GruleEngine) copyFacts(dataCtx ast.IDataContext) (ast.IDataContext, error) {
+	view := ast.NewDataContext()
+	copier := &factCopier{copies: make(map[factPointer]reflect.Value)}
+	for _, key := range dataCtx.GetKeys() {
+		if g.managedFact(key) {
+
+			continue
+		}
+		if err := addFactCopy(view, key, dataCtx.Get(key), copier); err != nil {
+
+			return nil, err
+		}
+	}
+	for _, key := range dataCtx.Retracted() {
+		view.Retract(key)
+	}
+
+	return view, nil
+}
+
+// addFactCopy adds a copy of the fact node into the data context under the key.
+func addFactCopy(dataCtx ast.IDataContext, key string, node model.ValueNode, copier *factCopier) error {
+	if node == nil {
+
+		return nil
+	}
+	if _, ok := node.(*model.JSONValueNode); ok {
+		data, err := json.Marshal(node.Value().Interface())
+		if err != nil {
+
+			return fmt.Errorf("can not copy JSON fact %s. got %w", key, err)
+		}
+
+		return dataCtx.AddJSON(key, data)
+	}
+	if !node.Value().IsValid() {
+
+		return dataCtx.Add(key, nil)
+	}
+
+	return dataCtx.Add(key, copier.copy(node.Value()).Interface())
+}
+
+// mergeFacts merges the changes the execution made to the facts of the view into the facts of the data context.
+// The facts are merged by their names, in order, so the merge does not depend on the order of the map iteration.
+func (g *GruleEngine) mergeFacts(merger *factMerger, dataCtx, base, view ast.IDataContext) error {
+	keys := view.GetKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if g.managedFact(key) {
+
+			continue
+		}
+		changed := view.Get(key)
+		original := dataCtx.Get(key)
+		before := base.Get(key)
+		if original == nil || before == nil {
+			// the fact was added by the execution.
+			if err := addFactCopy(dataCtx, key, changed, &factCopier{copies: make(map[factPointer]reflect.Value)}); err != nil {
+
+				return err
+			}
+
+			continue
+		}
+		merger.merge(key, original.Value(), before.Value(), changed.Value())
+	}
+
+	return nil
+}
+
+// factPointer identifies a value referred to by a pointer, by its address and type.
+type factPointer struct {
+	address uintptr
+	typ     reflect.Type
+}
+
+// factCopier deep copies the exported content of facts. A value referred to by several pointers is copied once, so
+// the copies refer to each other as the facts do.
+type factCopier struct {
+	copies map[factPointer]reflect.Value
+}
+
+// copy returns a deep copy of the value.
+func (c *factCopier) copy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+
+			return value
+		}
+		key := factPointer{address: value.Pointer(), typ: value.Type()}
+		if copied, ok := c.copies[key]; ok {
+
+			return copied
+		}
+		copied := reflect.New(value.Type().Elem())
+		c.copies[key] = copied
+		copied.Elem().Set(c.copy(value.Elem()))
+
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(c.copy(value.Elem()))
+
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(c.copy(value.Index(i)))
+		}
+
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(c.copy(value.Index(i)))
+		}
+
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+
+		return copied
+	case reflect.Struct:
+		// unexported fields are copied as they are, the exported ones are copied deep.
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(c.copy(value.Field(i)))
+			}
+		}
+
+		return copied
+	}
+
+	return value
+}
+
+// factChange is the last change merged into a path of the facts.
+type factChange struct {
+	knowledge string
+	value     reflect.Value
+}
+
+// factMerger merges the changes of the executions into the facts, and warns when two of them change the same path.
+type factMerger struct {
+	knowledge string
+	changes   map[string]factChange
+	log       logger.LogEntry
+}
+
+// merge merges into the original value what changed between the before and the changed values, recursively, so two
+// executions changing different fields of the same fact both have their change kept.
+func (m *factMerger) merge(path string, original, before, changed reflect.Value) {
+	if !original.IsValid() || !before.IsValid() || !changed.IsValid() || before.Type() != changed.Type() || original.Type() != changed.Type() {
+		m.set(path, original, changed)
+
+		return
+	}
+	if reflect.DeepEqual(before.Interface(), changed.Interface()) {
+
+		return
+	}
+	switch changed.Kind() {
+	case reflect.Ptr:
+		if !original.IsNil() && !before.IsNil() && !changed.IsNil() {
+			m.merge(path, original.Elem(), before.Elem(), changed.Elem())
+
+			return
+		}
+	case reflect.Interface:
+		// what an interface holds is not addressable, only what it refers to can be merged in place.
+		if refersTo(original) && refersTo(before) && refersTo(changed) && sameTypes(original.Elem(), before.Elem(), changed.Elem()) {
+			m.merge(path, original.Elem(), before.Elem(), changed.Elem())
+
+			return
+		}
+	case reflect.Struct:
+		// a change of an unexported field can only be merged with the whole struct, such as a time.Time.
+		exported := reflect.New(changed.Type()).Elem()
+		exported.Set(before)
+		for i := 0; i < changed.NumField(); i++ {
+			if exported.Field(i).CanSet() {
+				exported.Field(i).Set(changed.Field(i))
+			}
+		}
+		if reflect.DeepEqual(exported.Interface(), changed.Interface()) && original.CanSet() {
+			for i := 0; i < changed.NumField(); i++ {
+				if exported.Field(i).CanSet() {
+					m.merge(path+"."+changed.Type().Field(i).Name, original.Field(i), before.Field(i), changed.Field(i))
+				}
+			}
+
+			return
+		}
+	case reflect.Slice, reflect.Array:
+		if changed.Kind() == reflect.Array || (!original.IsNil() && !before.IsNil() && !changed.IsNil() && original.Len() == changed.Len() && before.Len() == changed.Len()) {
+			for i := 0; i < changed.Len(); i++ {
+				m.merge(fmt.Sprintf("%s[%d]", path, i), original.Index(i), before.Index(i), changed.Index(i))
+			}
+
+			return
+		}
+	case reflect.Map:
+		if !original.IsNil() && !before.IsNil() && !changed.IsNil() {
+			m.mergeMap(path, original, before, changed)
+
+			return
+		}
+	}
+	m.set(path, original, changed)
+}
+
+// mergeMap merges the entries of the map that were added, changed or deleted, in the order of their keys.
+func (m *factMerger) mergeMap(path string, original, before, changed reflect.Value) {
+	keys := make([]reflect.Value, 0, changed.Len()+before.Len())
+	keys = append(keys, changed.MapKeys()...)
+	for _, key := range before.MapKeys() {
+		if !changed.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	for _, key := range keys {
+		entry := fmt.Sprintf("%s[%v]", path, key.Interface())
+		value, previous, current := changed.MapIndex(key), before.MapIndex(key), original.MapIndex(key)
+		if !value.IsValid() {
+			m.record(entry, reflect.Value{})
+			original.SetMapIndex(key, reflect.Value{})
+
+			continue
+		}
+		if previous.IsValid() && reflect.DeepEqual(previous.Interface(), value.Interface()) {
+
+			continue
+		}
+		// entries are not addressable, only what they refer to can be merged in place.
+		if previous.IsValid() && current.IsValid() && refersTo(current) && refersTo(value) && refersTo(previous) && sameTypes(current, previous, value) {
+			m.merge(entry, current, previous, value)
+
+			continue
+		}
+		m.record(entry, value)
+		original.SetMapIndex(key, value)
+	}
+}
+
+// refersTo tells whether a changed map entry can be merged in place, because it is a pointer or a map, possibly in an
+// interface such as the objects of a JSON fact.
+func refersTo(value reflect.Value) bool {
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	return (value.Kind() == reflect.Ptr || value.Kind() == reflect.Map) && !value.IsNil()
+}
+
+// sameTypes tells whether the values held by interfaces have the same type.
+func sameTypes(original, before, changed reflect.Value) bool {
+
+	return original.Type() == changed.Type() && before.Type() == changed.Type()
+}
+
+// set replaces the original value with the changed one, if it can be set.
+func (m *factMerger) set(path string, original, changed reflect.Value) {
+	if !original.IsValid() || !original.CanSet() || !changed.IsValid() || !changed.Type().AssignableTo(original.Type()) {
+
+		return
+	}
+	m.record(path, changed)
+	original.Set(changed)
+}
+
+// record records the change of a path by the current execution, it warns if an earlier execution changed it otherwise.
+func (m *factMerger) record(path string, value reflect.Value) {
+	if previous, ok := m.changes[path]; ok && previous.knowledge != m.knowledge && !equalValues(previous.value, value) {
+		m.log.Warnf("Knowledge bases %s and %s both changed %s, the change of %s is kept", previous.knowledge, m.knowledge, path, m.knowledge)
+	}
+	m.changes[path] = factChange{knowledge: m.knowledge, value: value}
+}
+
+// equalValues tells whether two changed values are deeply equal, a deletion only equals a deletion.
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+
+		return a.IsValid() == b.IsValid()
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,111 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ManyApplicant struct {
+	Age int
+}
+
+type ManyApplication struct {
+	Applicant *ManyApplicant
+	Amount    float64
+	Valid     bool
+	Risk      int
+	Price     float64
+	Note      string
+	Flags     map[string]bool
+}
+
+var manyRules = map[string]string{
+	"Validation": `
+rule Adult { when Application.Applicant.Age >= 18 && !Application.Valid then Application.Valid = true; Application.Note = "valid"; }`,
+	"Fraud": `
+rule Large { when Application.Amount > 1000 && Application.Risk == 0 then Application.Risk = 5; Application.Flags["large"] = true; Report.checked = true; }`,
+	"Pricing": `
+rule Price { when Application.Price == 0 && Application.Risk == 0 then Application.Price = Application.Amount * 0.1; Application.Note = "priced"; }`,
+}
+
+func newManyKnowledgeBases(t *testing.T, names ...string) []*ast.KnowledgeBase {
+	t.Helper()
+	lib := ast.NewKnowledgeLibrary()
+	knowledges := make([]*ast.KnowledgeBase, 0, len(names))
+	for _, name := range names {
+		assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource(name, "0.0.1", pkg.NewBytesResource([]byte(manyRules[name]))))
+		kb, err := lib.NewKnowledgeBaseInstance(name, "0.0.1")
+		assert.NoError(t, err)
+		knowledges = append(knowledges, kb)
+	}
+
+	return knowledges
+}
+
+func TestGruleEngine_ExecuteMany(t *testing.T) {
+	for _, order := range [][]string{{"Validation", "Fraud", "Pricing"}, {"Pricing", "Fraud", "Validation"}} {
+		applicant := &ManyApplicant{Age: 30}
+		application := &ManyApplication{Applicant: applicant, Amount: 2000, Flags: map[string]bool{"new": true}}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Application", application))
+		assert.NoError(t, dctx.AddJSON("Report", []byte(`{"checked": false, "source": "web"}`)))
+
+		assert.NoError(t, NewGruleEngine().ExecuteMany(dctx, newManyKnowledgeBases(t, order...)))
+
+		// every knowledge base saw the facts as they were, pricing did not see the risk set by fraud.
+		assert.True(t, application.Valid)
+		assert.Equal(t, 5, application.Risk)
+		assert.Equal(t, float64(200), application.Price)
+		assert.Equal(t, map[string]bool{"new": true, "large": true}, application.Flags)
+		assert.Same(t, applicant, application.Applicant)
+		checked, err := dctx.Get("Report").GetObjectValueByField("checked")
+		assert.NoError(t, err)
+		assert.Equal(t, true, checked.Interface())
+		source, err := dctx.Get("Report").GetObjectValueByField("source")
+		assert.NoError(t, err)
+		assert.Equal(t, "web", source.Interface())
+
+		// the later knowledge base wins a conflicting change.
+		if order[0] == "Validation" {
+			assert.Equal(t, "priced", application.Note)
+		} else {
+			assert.Equal(t, "valid", application.Note)
+		}
+	}
+}
+
+func TestGruleEngine_ExecuteMany_Error(t *testing.T) {
+	application := &ManyApplication{Applicant: &ManyApplicant{Age: 30}, Amount: 2000}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Application", application))
+
+	// the fraud rules write into a nil map and a missing fact, the facts are left unchanged.
+	err := NewGruleEngine().ExecuteMany(dctx, newManyKnowledgeBases(t, "Validation", "Fraud"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'Fraud'")
+	assert.False(t, application.Valid)
+	assert.Equal(t, 0, application.Risk)
+
+	knowledges := newManyKnowledgeBases(t, "Validation")
+	assert.Error(t, NewGruleEngine().ExecuteMany(dctx, []*ast.KnowledgeBase{knowledges[0], knowledges[0]}))
+	assert.Error(t, NewGruleEngine().ExecuteMany(dctx, []*ast.KnowledgeBase{nil}))
+	assert.Error(t, NewGruleEngine().ExecuteMany(nil, knowledges))
+}
@@ -0,0 +1,203 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// RuleExplanation tells why the when scope of a rule matches the facts or not.
+type RuleExplanation struct {
+	Rule    string
+	Matched bool
+	// Error evaluating the whole when scope, such as a missing fact.
+	Error error
+	// Conjuncts are the expressions joined by && at the top of the when scope, a single one if there is no &&.
+	Conjuncts []ConjunctExplanation
+}
+
+// ConjunctExplanation is the result of one expression of a when scope joined by &&.
+// Unlike the rule evaluation, every conjunct is evaluated, even after one of them is false.
+type ConjunctExplanation struct {
+	Expression string
+	Matched    bool
+	Error      error
+	// Operands are the left and right hand side of the conjunct operator, such as Fact.Amount and 100
+	// in Fact.Amount > 100, none if the conjunct is a single value like Fact.IsValid().
+	Operands []OperandValue
+}
+
+// OperandValue is an operand of a conjunct as resolved against the facts.
+type OperandValue struct {
+	Expression string
+	Value      interface{}
+	Error      error
+}
+
+// ExplainRule evaluates the when scope of the named rule against the facts, without executing it, and explains the
+// result of each conjunct with its resolved operands. It answers why a rule did or did not fire for some facts.
+// A retracted rule is explained as if it was not retracted. The functions called in the when scope may be called more
+// than once, the working memory of the knowledge base is reset.
+func ExplainRule(dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase, ruleName string) (*RuleExplanation, error) {
+	if knowledge == nil || dataCtx == nil {
+
+		return nil, fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
+	}
+	ruleEntry, ok := knowledge.RuleEntries[ruleName]
+	if !ok || ruleEntry.Deleted {
+
+		return nil, fmt.Errorf("rule %s is not in knowledge base '%s' version %s", ruleName, knowledge.Name, knowledge.Version)
+	}
+	if ruleEntry.WhenScope == nil || ruleEntry.WhenScope.Expression == nil {
+
+		return nil, fmt.Errorf("rule %s has no when scope", ruleName)
+	}
+	// a bare engine keeps the working memory as it is by default and adds no scratchpad.
+	if err := (&GruleEngine{}).prepareKnowledge(knowledge, dataCtx); err != nil {
+
+		return nil, err
+	}
+
+	explanation := &RuleExplanation{Rule: ruleName}
+	val, err := explainEvaluate(ruleEntry.WhenScope.Expression, dataCtx, knowledge.WorkingMemory)
+	switch {
+	case err != nil:
+		explanation.Error = err
+	case val.Kind() != reflect.Bool:
+		explanation.Error = fmt.Errorf("the when is not a boolean expression : %s", ruleEntry.WhenScope.Expression.GetGrlText())
+	default:
+		explanation.Matched = val.Bool()
+	}
+	for _, conjunct := range conjunctsOf(ruleEntry.WhenScope.Expression) {
+		explanation.Conjuncts = append(explanation.Conjuncts, explainConjunct(conjunct, dataCtx, knowledge.WorkingMemory))
+	}
+
+	return explanation, nil
+}
+
+// String lists the conjuncts, each with its result and operands.
+func (explanation *RuleExplanation) String() string {
+	var buff strings.Builder
+	switch {
+	case explanation.Error != nil:
+		fmt.Fprintf(&buff, "rule %s can not be evaluated : %v\n", explanation.Rule, explanation.Error)
+	case explanation.Matched:
+		fmt.Fprintf(&buff, "rule %s matches\n", explanation.Rule)
+	default:
+		fmt.Fprintf(&buff, "rule %s does not match\n", explanation.Rule)
+	}
+	for _, conjunct := range explanation.Conjuncts {
+		result := fmt.Sprintf("%t", conjunct.Matched)
+		if conjunct.Error != nil {
+			result = "error"
+		}
+		fmt.Fprintf(&buff, "  [%s] %s", result, conjunct.Expression)
+		operands := make([]string, 0, len(conjunct.Operands))
+		for _, operand := range conjunct.Operands {
+			if operand.Error != nil {
+				operands = append(operands, fmt.Sprintf("%s : %v", operand.Expression, operand.Error))
+			} else {
+				operands = append(operands, fmt.Sprintf("%s = %s", operand.Expression, formatOperand(operand.Value)))
+			}
+		}
+		if len(operands) > 0 {
+			fmt.Fprintf(&buff, " where %s", strings.Join(operands, ", "))
+		}
+		if conjunct.Error != nil && len(operands) == 0 {
+			fmt.Fprintf(&buff, " : %v", conjunct.Error)
+		}
+		buff.WriteString("\n")
+	}
+
+	return buff.String()
+}
+
+func formatOperand(value interface{}) string {
+	if text, ok := value.(string); ok {
+
+		return fmt.Sprintf("%q", text)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// conjunctsOf flattens the && at the top of the expression, looking into the parentheses that are not negated.
+func conjunctsOf(expr *ast.Expression) []*ast.Expression {
+	if expr.SingleExpression != nil && !expr.Negated {
+
+		return conjunctsOf(expr.SingleExpression)
+	}
+	if expr.LeftExpression != nil && expr.RightExpression != nil && expr.Operator == ast.OpAnd {
+
+		return append(conjunctsOf(expr.LeftExpression), conjunctsOf(expr.RightExpression)...)
+	}
+
+	return []*ast.Expression{expr}
+}
+
+func explainConjunct(expr *ast.Expression, dataCtx ast.IDataContext, memory *ast.WorkingMemory) ConjunctExplanation {
+	conjunct := ConjunctExplanation{Expression: expr.GetGrlText()}
+	val, err := explainEvaluate(expr, dataCtx, memory)
+	switch {
+	case err != nil:
+		conjunct.Error = err
+	case val.Kind() != reflect.Bool:
+		conjunct.Error = fmt.Errorf("expression %s is not a boolean", expr.GetGrlText())
+	default:
+		conjunct.Matched = val.Bool()
+	}
+	for operands := expr; operands != nil; operands = operands.SingleExpression {
+		if operands.LeftExpression != nil && operands.RightExpression != nil {
+			conjunct.Operands = []OperandValue{
+				explainOperand(operands.LeftExpression, dataCtx, memory),
+				explainOperand(operands.RightExpression, dataCtx, memory),
+			}
+
+			break
+		}
+	}
+
+	return conjunct
+}
+
+func explainOperand(expr *ast.Expression, dataCtx ast.IDataContext, memory *ast.WorkingMemory) OperandValue {
+	operand := OperandValue{Expression: expr.GetGrlText()}
+	val, err := explainEvaluate(expr, dataCtx, memory)
+	if err != nil {
+		operand.Error = err
+
+		return operand
+	}
+	if val.IsValid() && val.CanInterface() {
+		operand.Value = val.Interface()
+	}
+
+	return operand
+}
+
+// explainEvaluate evaluates the expression, recovering the panic of a function as the rule evaluation does.
+func explainEvaluate(expr *ast.Expression, dataCtx ast.IDataContext, memory *ast.WorkingMemory) (val reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error while evaluating %s ! recovered : %v", expr.GetGrlText(), r)
+		}
+	}()
+
+	return expr.Evaluate(dataCtx, memory)
+}
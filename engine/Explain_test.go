@@ -0,0 +1,85 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+const explainRules = `
+rule HighValue "High value customer in Indonesia" {
+	when
+		Fact.Amount > 100 && (Fact.Country == "ID" && !Fact.Flagged) && (Fact.Amount < 1000 || Fact.Country == "SG")
+	then
+		Fact.Flagged = true;
+}
+rule Other "Another rule" {
+	when
+		Fact.Flagged
+	then
+		Retract("Other");
+}`
+
+func TestExplainRule(t *testing.T) {
+	kb := newConditionKnowledgeBase(t, explainRules)
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", &ConditionFact{Amount: 50, Country: "SG"}))
+
+	explanation, err := ExplainRule(dctx, kb, "HighValue")
+	assert.NoError(t, err)
+	assert.False(t, explanation.Matched)
+	assert.NoError(t, explanation.Error)
+	assert.Len(t, explanation.Conjuncts, 4)
+	assert.Equal(t, ConjunctExplanation{
+		Expression: "Fact.Amount>100",
+		Operands: []OperandValue{
+			{Expression: "Fact.Amount", Value: int64(50)},
+			{Expression: "100", Value: int64(100)},
+		},
+	}, explanation.Conjuncts[0])
+	assert.Equal(t, "Fact.Country==\"ID\"", explanation.Conjuncts[1].Expression)
+	assert.False(t, explanation.Conjuncts[1].Matched)
+	assert.Equal(t, "SG", explanation.Conjuncts[1].Operands[0].Value)
+	assert.Equal(t, "!Fact.Flagged", explanation.Conjuncts[2].Expression)
+	assert.True(t, explanation.Conjuncts[2].Matched, "conjuncts after a false one are evaluated too")
+	assert.True(t, explanation.Conjuncts[3].Matched)
+	assert.Equal(t, "Fact.Amount<1000", explanation.Conjuncts[3].Operands[0].Expression)
+	assert.Equal(t, true, explanation.Conjuncts[3].Operands[0].Value)
+	assert.Equal(t, `rule HighValue does not match
+  [false] Fact.Amount>100 where Fact.Amount = 50, 100 = 100
+  [false] Fact.Country=="ID" where Fact.Country = "SG", "ID" = "ID"
+  [true] !Fact.Flagged
+  [true] Fact.Amount<1000||Fact.Country=="SG" where Fact.Amount<1000 = true, Fact.Country=="SG" = true
+`, explanation.String())
+
+	assert.NoError(t, dctx.Add("Fact", &ConditionFact{Amount: 500, Country: "ID"}))
+	explanation, err = ExplainRule(dctx, kb, "HighValue")
+	assert.NoError(t, err)
+	assert.True(t, explanation.Matched)
+
+	missing := ast.NewDataContext()
+	explanation, err = ExplainRule(missing, kb, "HighValue")
+	assert.NoError(t, err)
+	assert.False(t, explanation.Matched)
+	assert.Error(t, explanation.Error)
+	assert.Error(t, explanation.Conjuncts[0].Error)
+	assert.Error(t, explanation.Conjuncts[0].Operands[0].Error)
+
+	_, err = ExplainRule(dctx, kb, "Unknown")
+	assert.Error(t, err)
+}
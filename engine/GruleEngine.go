@@ -17,18 +17,23 @@ package engine
 import (
 	"context"
 	"fmt"
-	"github.com/rs/zerolog"
-	"github.com/sirupsen/logrus"
-	"go.uber.org/zap"
 	"sort"
 	"time"
 
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
 )
 
 const (
 	DefaultCycleCount = 5000
+
+	// DefaultScratchpad is the name of the scratchpad of the engines created with NewGruleEngine.
+	DefaultScratchpad = "tmp"
+
+	// DefaultErrorFact is the usual name of the error fact. The engines created with NewGruleEngine have none, their
+	// evaluation errors are handled as before unless ErrorFact is set.
+	DefaultErrorFact = "EngineError"
 )
 
 var (
@@ -43,30 +48,8 @@ var (
 
 // SetLogger changes default logger on external
 func SetLogger(externalLog interface{}) {
-	var entry logger.LogEntry
-
-	switch externalLog.(type) {
-	case *zap.Logger:
-		log, ok := externalLog.(*zap.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewZap(log)
-	case *logrus.Logger:
-		log, ok := externalLog.(*logrus.Logger)
-		if !ok {
-
-			return
-		}
-		entry = logger.NewLogrus(log)
-	case *zerolog.Logger:
-		log, ok := externalLog.(*zerolog.Logger)
-		if !ok {
-			return
-		}
-		entry = logger.NewZero(log)
-	default:
+	entry, ok := logger.FromExternal(externalLog)
+	if !ok {
 
 		return
 	}
@@ -79,7 +62,9 @@ func SetLogger(externalLog interface{}) {
 func NewGruleEngine() *GruleEngine {
 
 	return &GruleEngine{
-		MaxCycle: DefaultCycleCount,
+		MaxCycle:   DefaultCycleCount,
+		Metrics:    NewMetrics(),
+		Scratchpad: DefaultScratchpad,
 	}
 }
 
@@ -88,6 +73,124 @@ type GruleEngine struct {
 	MaxCycle                        uint64
 	ReturnErrOnFailedRuleEvaluation bool
 	Listeners                       []GruleEngineListener
+
+	// Metrics keeps the outcome counters recorded by rules, if nil outcomes are not recorded.
+	Metrics *Metrics
+
+	// MissingFacts makes the engine tolerate facts that were not added to the data context, if nil they are errors.
+	MissingFacts *MissingFacts
+
+	// Tracer, if set, records a trace of a sample of the executions.
+	Tracer *Tracer
+
+	// History, if set, records every execution into its ExecutionStore, along with its trace if the Tracer sampled it.
+	History *History
+
+	// ParallelEvaluation is the number of goroutines evaluating the when scopes of a cycle concurrently, against a
+	// read only snapshot of the data context. Zero or one evaluates them one by one. Functions called in when
+	// scopes must be safe for concurrent use.
+	ParallelEvaluation int
+
+	// StringNumberComparison selects how a string is compared to a number, such as a fact field "42" to 42.
+	// The default pkg.StringNumberUnchecked keeps the historical behavior.
+	StringNumberComparison pkg.StringNumberComparison
+
+	// NilSafeChaining makes a chain of calls or fields reaching a nil, such as Fact.Account().Owner().IsBlacklisted()
+	// when Owner returns a nil pointer or a nil interface, go on with the zero value of what each remaining call or
+	// field returns, false for IsBlacklisted. Without it, the chain is an error naming the part that is nil.
+	NilSafeChaining bool
+
+	// Tolerance is the tolerance of the ~== comparisons of the rules that do not set theirs with within, such as
+	// Order.Total ~== 99.99 within 0.005. If zero, ast.DefaultTolerance is used.
+	Tolerance float64
+
+	// Logger, if it wraps a logger, is used instead of the default logger by this engine, by the rules it executes
+	// and by the scripts of their then scopes, such as logger.FromExternal(tenantZap). Two engines of one process
+	// can so log at different levels or to different destinations. See logger.FromExternal.
+	Logger logger.LogEntry
+
+	// Scratchpad names the fact holding the transient values rules share within one execution, such as
+	// tmp.Subtotal. It is emptied at the start of every execution. Empty has no scratchpad. See DefaultScratchpad.
+	Scratchpad string
+
+	// ErrorFact names the fact the evaluation errors of the rules are recorded into, as an EngineError, instead of
+	// being logged and returned if ReturnErrOnFailedRuleEvaluation is set. Rules declare their fallbacks on it, such
+	// as EngineError.Failed("Score"), and are evaluated once more after a failure. It is emptied at the start of every
+	// execution. Empty has no error fact. See DefaultErrorFact.
+	ErrorFact string
+
+	// StopWhen, if set, is asked after every rule fired whether the execution is done, such as once a final decision
+	// fact is set. The execution then stops without running the remaining cycles, as do the halt conditions of GRL.
+	StopWhen func(dataCtx ast.IDataContext) bool
+
+	// Templates, if set, renders the templates rules call with Template, such as the text/template parsed
+	// by ParseTemplates.
+	Templates ast.TemplateExecutor
+
+	// DateNames, if set, are the month and day names FormatDate and ParseDate use instead of those of
+	// ast.RegisterDateNames, so the engines of two tenants may name them apart.
+	DateNames *ast.DateNamesRegistry
+
+	// Enrichers derive facts from the facts of the data context, in their order, before the first cycle of every
+	// execution, so the derivations are not repeated as early rules in every knowledge base.
+	Enrichers []Enricher
+
+	// SinglePass evaluates the when scope of every rule once, against the facts as they are when the execution
+	// starts, then fires the matching rules by their salience, each at most once. The changes made by the then
+	// scopes do not make any rule evaluated again, there is no forward chaining, and the working memory is neither
+	// reset nor invalidated by the assignments. See executeSinglePass.
+	SinglePass bool
+
+	// Profiles are the active profiles, such as "eu-only". A rule annotated with @profile is only evaluated
+	// when at least one of its profiles is active, the rules without annotation are always evaluated.
+	Profiles []string
+
+	// CompactRules makes the engine look up, instead of evaluating one by one, the rules whose when scopes differ
+	// only in the constant a variable is compared to, such as generated rules testing Fact.Country == "FR",
+	// Fact.Country == "DE" and so on. The variable is evaluated once per cycle and only the rules indexed by its
+	// value are evaluated. A rule left out this way is not reported to the listeners as evaluated, nor is an error
+	// its when scope would have raised. See ast.KnowledgeBase.RuleTables.
+	CompactRules bool
+
+	// Approvals holds the rules annotated with @approval until an approver accepts their activation. An execution
+	// of a knowledge base having such rules needs Approvals and a subject attached with WithApprovalSubject.
+	Approvals *Approvals
+
+	// degraded is 1 when the engine skips the rules of low criticality, see SetDegraded. It is a plain integer
+	// accessed atomically so an engine can still be copied, such as by RunTests.
+	degraded int32
+}
+
+// log returns the logger of this engine, the default logger of the package if the engine has none.
+func (g *GruleEngine) log() logger.LogEntry {
+
+	return g.Logger.Or(log)
+}
+
+// prepareMissingFacts adds the missing facts defaults into the data context and returns the tracker used to tolerate
+// the remaining missing facts. It returns nil tracker if the engine does not tolerate missing facts.
+func (g *GruleEngine) prepareMissingFacts(dataCtx ast.IDataContext) (*missingFactTracker, error) {
+	if g.MissingFacts == nil {
+
+		return nil, nil
+	}
+	err := g.MissingFacts.addDefaults(dataCtx, g.log())
+	if err != nil {
+
+		return nil, err
+	}
+
+	return &missingFactTracker{warned: make(map[string]bool), log: g.log()}, nil
+}
+
+// outcomeRecorder returns the recorder for the RecordOutcome built-in function.
+func (g *GruleEngine) outcomeRecorder() ast.OutcomeRecorder {
+	if g.Metrics == nil {
+
+		return nil
+	}
+
+	return g.Metrics
 }
 
 // Execute function is the same as ExecuteWithContext(context.Background())
@@ -98,6 +201,9 @@ func (g *GruleEngine) Execute(dataCtx ast.IDataContext, knowledge *ast.Knowledge
 
 // notifyEvaluateRuleEntry will notify all registered listener that a rule is being evaluated.
 func (g *GruleEngine) notifyEvaluateRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry, candidate bool) {
+	if trace := traceFrom(ctx); trace != nil {
+		trace.record(TraceEvent{Kind: TraceEvaluate, Cycle: cycle, Rule: entry.RuleName, Candidate: candidate})
+	}
 	if g.Listeners != nil && len(g.Listeners) > 0 {
 		for _, gl := range g.Listeners {
 			gl.EvaluateRuleEntry(ctx, cycle, entry, candidate)
@@ -107,6 +213,9 @@ func (g *GruleEngine) notifyEvaluateRuleEntry(ctx context.Context, cycle uint64,
 
 // notifyEvaluateRuleEntry will notify all registered listener that a rule is being executed.
 func (g *GruleEngine) notifyExecuteRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry) {
+	if trace := traceFrom(ctx); trace != nil {
+		trace.record(TraceEvent{Kind: TraceExecute, Cycle: cycle, Rule: entry.RuleName})
+	}
 	if g.Listeners != nil && len(g.Listeners) > 0 {
 		for _, gl := range g.Listeners {
 			gl.ExecuteRuleEntry(ctx, cycle, entry)
@@ -116,6 +225,9 @@ func (g *GruleEngine) notifyExecuteRuleEntry(ctx context.Context, cycle uint64,
 
 // notifyEvaluateRuleEntry will notify all registered listener that a rule is being executed.
 func (g *GruleEngine) notifyBeginCycle(ctx context.Context, cycle uint64) {
+	if trace := traceFrom(ctx); trace != nil {
+		trace.record(TraceEvent{Kind: TraceBeginCycle, Cycle: cycle})
+	}
 	if g.Listeners != nil && len(g.Listeners) > 0 {
 		for _, gl := range g.Listeners {
 			gl.BeginCycle(ctx, cycle)
@@ -123,42 +235,149 @@ func (g *GruleEngine) notifyBeginCycle(ctx context.Context, cycle uint64) {
 	}
 }
 
-// ExecuteWithContext function will execute a knowledge evaluation and action against data context.
-// The engine will evaluate context cancelation status in each cycle.
-// The engine also do conflict resolution of which rule to execute.
+// ExecuteWithContext executes the rules of the KnowledgeBase against the DataContext until no rule can fire. Every
+// cycle checks the context for a cancellation, then resolves the conflict of which matching rule to execute.
+// The execution stops as soon as the StopWhen predicate or a halt condition of the KnowledgeBase holds after a rule
+// fired. A SecurityContext attached with WithSecurityContext restricts which rules may fire, and a degraded engine
+// does not evaluate the rules of low criticality, see SetDegraded. The rules annotated with @approval wait for the
+// decision on their activation, see Approvals. Outputs attached with WithOutputs collect the messages the rules emit.
+// Every execution and every rule fired are counted into the rule statistics of the Metrics, and the History records
+// the execution along with the fact ids attached with WithFactIDs.
 func (g *GruleEngine) ExecuteWithContext(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) error {
 	if knowledge == nil || dataCtx == nil {
 
 		return fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
 	}
+	if g.Logger.Logger != nil {
+		// the scripts of the then scopes log into the logger of the engine.
+		ctx = logger.NewContext(ctx, g.Logger)
+	}
+	trace := g.Tracer.start(knowledge)
+	record := g.History.start(ctx, knowledge)
+	if trace == nil && record == nil {
 
-	log.Debugf("Starting rule execution using knowledge '%s' version %s. Contains %d rule entries", knowledge.Name, knowledge.Version, len(knowledge.RuleEntries))
+		return g.execute(ctx, dataCtx, knowledge)
+	}
+	executionCtx := ctx
+	if trace != nil {
+		executionCtx = withTrace(executionCtx, trace)
+	}
+	if record != nil {
+		executionCtx = withRecord(executionCtx, record)
+	}
+	err := g.execute(executionCtx, dataCtx, knowledge)
+	if trace != nil {
+		g.Tracer.finish(trace, err, g.log())
+	}
+	if record != nil {
+		g.History.finish(ctx, record, trace, err)
+	}
+
+	return err
+}
+
+// execute is ExecuteWithContext once the arguments are checked.
+func (g *GruleEngine) execute(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) error {
+
+	g.log().Debugf("Starting rule execution using knowledge '%s' version %s. Contains %d rule entries", knowledge.Name, knowledge.Version, len(knowledge.RuleEntries))
 
 	// Prepare the timer, we need to measure the processing time in debug mode.
 	startTime := time.Now()
 
+	if g.Metrics != nil {
+		g.Metrics.recordExecution(knowledge.Name, knowledge.Version)
+	}
+
+	// the emitted messages are published only if the execution succeeds.
+	emission := OutputsFrom(ctx).begin()
+	if err := emission.checkSinks(knowledge); err != nil {
+
+		return err
+	}
+
+	scratchpad, err := g.prepareScratchpad(dataCtx)
+	if err != nil {
+
+		return err
+	}
+
+	failures, err := g.prepareErrorFact(dataCtx)
+	if err != nil {
+
+		return err
+	}
+
 	// Prepare the build-in function and add to datacontext.
 	defunc := &ast.BuiltInFunctions{
 		Knowledge:     knowledge,
 		WorkingMemory: knowledge.WorkingMemory,
 		DataContext:   dataCtx,
+		Outcomes:      g.outcomeRecorder(),
+		Outputs:       emission.emitter(),
+		Scratchpad:    scratchpad,
+		Templates:     g.Templates,
+		DateNames:     g.DateNames,
+		Calls:         g.Tracer.calls(traceFrom(ctx)),
 	}
-	err := dataCtx.Add("DEFUNC", defunc)
+	err = dataCtx.Add("DEFUNC", defunc)
 	if err != nil {
-		log.Error("DEFUNC add err")
+		g.log().Error("DEFUNC add err")
 
 		return err
 	}
 
-	// Working memory need to be resetted. all Expression will be set as not evaluated.
-	log.Debugf("Resetting Working memory")
-	knowledge.WorkingMemory.ResetAll()
+	err = g.enrich(ctx, dataCtx)
+	if err != nil {
+
+		return err
+	}
+
+	err = knowledge.InitializeCollections(dataCtx)
+	if err != nil {
+
+		return err
+	}
+
+	// Working memory need to be resetted. all Expression will be set as not evaluated. A single pass keeps no
+	// evaluation, there is nothing to reset.
+	if !g.SinglePass {
+		g.log().Debugf("Resetting Working memory")
+		knowledge.WorkingMemory.ResetAll()
+	}
+	knowledge.WorkingMemory.StringNumberComparison = g.StringNumberComparison
+	knowledge.WorkingMemory.NilSafeChaining = g.NilSafeChaining
+	knowledge.WorkingMemory.Tolerance = g.Tolerance
+	knowledge.WorkingMemory.SinglePass = g.SinglePass
+	knowledge.WorkingMemory.Logger = g.Logger
 	knowledge.Reset()
 
+	missing, err := g.prepareMissingFacts(dataCtx)
+	if err != nil {
+
+		return err
+	}
+
 	// Initialize all AST with datacontext and working memory
-	log.Debugf("Initializing Context")
+	g.log().Debugf("Initializing Context")
 	knowledge.InitializeContext(dataCtx)
 
+	security := SecurityContextFrom(ctx)
+	degraded := g.shedsLowCriticality(knowledge)
+	approvals, err := g.approvalGate(ctx, knowledge)
+	if err != nil {
+
+		return err
+	}
+
+	var parallel *parallelEvaluator
+	if g.ParallelEvaluation > 1 {
+		parallel, err = newParallelEvaluator(knowledge, dataCtx, g.ParallelEvaluation)
+		if err != nil {
+
+			return fmt.Errorf("error while preparing the parallel evaluation. got %w", err)
+		}
+	}
+
 	var cycle uint64
 
 	/*
@@ -168,35 +387,96 @@ func (g *GruleEngine) ExecuteWithContext(ctx context.Context, dataCtx ast.IDataC
 	*/
 	for {
 		if ctx.Err() != nil {
-			log.Error("Context canceled")
+			g.log().Error("Context canceled")
 
 			return ctx.Err()
 		}
 
 		g.notifyBeginCycle(ctx, cycle+1)
+		failures.beginCycle()
+
+		// Rules that the rule tables tell can not match are not evaluated.
+		excluded := g.excludedByRuleTables(dataCtx, knowledge)
+
+		// Evaluate the when scopes of all rule entry that may be executed at once, before any is selected.
+		var evaluated map[string]evaluation
+		if parallel != nil {
+			keys := make([]string, 0, len(knowledge.RuleEntries))
+			for key, ruleEntry := range knowledge.RuleEntries {
+				if !(degraded && ruleEntry.Criticality == ast.CriticalityLow) && !excluded[ruleEntry] && ruleEntry.InProfiles(g.Profiles) && !ruleEntry.Retracted && !ruleEntry.Deleted && ruleEntry.CanFire(time.Now()) {
+					keys = append(keys, key)
+				}
+			}
+			evaluated = parallel.evaluate(ctx, dataCtx, keys)
+		}
 
 		// Select all rule entry that can be executed.
-		log.Tracef("Select all rule entry that can be executed.")
+		g.log().Tracef("Select all rule entry that can be executed.")
 		runnable := make([]*ast.RuleEntry, 0)
-		for _, ruleEntry := range knowledge.RuleEntries {
+		// the runnable rules selected because their when scope is false, to execute their else scope.
+		otherwise := make(map[*ast.RuleEntry]bool)
+		for key, ruleEntry := range knowledge.RuleEntries {
 			if ctx.Err() != nil {
-				log.Error("Context canceled")
+				g.log().Error("Context canceled")
 
 				return ctx.Err()
 			}
-			if !ruleEntry.Retracted && !ruleEntry.Deleted {
-				// test if this rule entry v can execute.
-				can, err := ruleEntry.Evaluate(ctx, dataCtx, knowledge.WorkingMemory)
+			if degraded && ruleEntry.Criticality == ast.CriticalityLow {
+
+				continue
+			}
+			if excluded[ruleEntry] {
+
+				continue
+			}
+			if !ruleEntry.InProfiles(g.Profiles) {
+
+				continue
+			}
+			if !ruleEntry.Retracted && !ruleEntry.Deleted && ruleEntry.CanFire(time.Now()) {
+				// the security predicates are ANDed in front of the rule condition.
+				allowed, err := security.allows(dataCtx, ruleEntry)
 				if err != nil {
-					log.Errorf("Failed testing condition for rule : %s. Got error %v", ruleEntry.RuleName, err)
+					g.log().Errorf("Failed testing security predicate for rule : %s. Got error %v", ruleEntry.RuleName, err)
+
+					return err
+				}
+				if !allowed {
+					g.notifyEvaluateRuleEntry(ctx, cycle+1, ruleEntry, false)
+
+					continue
+				}
+				// test if this rule entry v can execute.
+				var can bool
+				if result, ok := evaluated[key]; ok {
+					can, err = result.can, result.err
+				} else {
+					can, err = ruleEntry.Evaluate(ctx, dataCtx, knowledge.WorkingMemory)
+				}
+				if err != nil && missing != nil && missing.tolerate(ruleEntry.RuleName, err) {
+					can = false
+				} else if err != nil && failures.record(ruleEntry.RuleName, err, dataCtx, knowledge.WorkingMemory) {
+					g.log().Warnf("Failed testing condition for rule : %s, recorded into fact %s. Got error %v", ruleEntry.RuleName, g.ErrorFact, err)
+				} else if err != nil {
+					g.log().Errorf("Failed testing condition for rule : %s. Got error %v", ruleEntry.RuleName, err)
 					if g.ReturnErrOnFailedRuleEvaluation {
 
 						return err
 					}
 				}
-				// if can, add into runnable array
-				if can {
-					runnable = append(runnable, ruleEntry)
+				// a rule whose when scope is false is runnable too if it has an else scope.
+				elseBranch := !can && err == nil && ruleEntry.ElseScope != nil
+				// if can, add into runnable array, unless it waits for an approval.
+				if can || elseBranch {
+					held, err := approvals.holds(ruleEntry)
+					if err != nil {
+
+						return err
+					}
+					if !held {
+						runnable = append(runnable, ruleEntry)
+						otherwise[ruleEntry] = elseBranch
+					}
 				}
 				// notify all listeners that a rule's when scope is been evaluated.
 				g.notifyEvaluateRuleEntry(ctx, cycle+1, ruleEntry, can)
@@ -205,17 +485,27 @@ func (g *GruleEngine) ExecuteWithContext(ctx context.Context, dataCtx ast.IDataC
 
 		// disabled to test the rete's variable change detection.
 		// knowledge.RuleContextReset()
-		log.Tracef("Selected rules %d.", len(runnable))
+		g.log().Tracef("Selected rules %d.", len(runnable))
+
+		if g.SinglePass {
+			cycle, err = g.executeSinglePass(ctx, dataCtx, knowledge, approvals, runnable, otherwise)
+			if err != nil {
+
+				return err
+			}
+
+			break
+		}
 
 		// If there are rules to execute, sort them by their Salience
 		if len(runnable) > 0 {
 			// add the cycle counter
 			cycle++
 
-			log.Debugf("Cycle #%d", cycle)
+			g.log().Debugf("Cycle #%d", cycle)
 			// if cycle is above the maximum allowed cycle, returnan error indicated the cycle has ended.
 			if cycle > g.MaxCycle {
-				log.Error("Max cycle reached")
+				g.log().Error("Max cycle reached")
 
 				return fmt.Errorf("the GruleEngine successfully selected rule candidate for execution after %d cycles, this could possibly caused by rule entry(s) that keep added into execution pool but when executed it does not change any data in context. Please evaluate your rule entries \"When\" and \"Then\" scope. You can adjust the maximum cycle using GruleEngine.MaxCycle variable", g.MaxCycle)
 			}
@@ -230,83 +520,195 @@ func (g *GruleEngine) ExecuteWithContext(ctx context.Context, dataCtx ast.IDataC
 					}
 				}
 			}
-			// set the current rule entry to run. This is for trace ability purpose
-			dataCtx.SetRuleEntry(runner)
-			// notify listeners that we are about to execute a rule entry then scope
-			g.notifyExecuteRuleEntry(ctx, cycle, runner)
 			// execute the top most prioritized rule
-			err := runner.Execute(ctx, dataCtx, knowledge.WorkingMemory)
+			done, err := g.fire(ctx, dataCtx, knowledge, cycle, runner, otherwise[runner])
 			if err != nil {
-				log.Errorf("Failed execution rule : %s. Got error %v", runner.RuleName, err)
 
-				return fmt.Errorf("error while executing rule %s. got %w", runner.RuleName, err)
+				return err
 			}
+			err = approvals.fired(runner)
+			if err != nil {
 
-			if dataCtx.IsComplete() {
+				return err
+			}
+			if done {
 				break
 			}
+		} else if failures.pending() {
+			// the rules reacting to the errors of this cycle must see them.
+			g.log().Debugf("No rule to run, evaluating the rules again for the errors recorded into fact %s", g.ErrorFact)
 		} else {
 			// No more rule can be executed, so we are done here.
-			log.Debugf("No more rule to run")
+			g.log().Debugf("No more rule to run")
 
 			break
 		}
 	}
-	log.Debugf("Finished Rules execution. With knowledge base '%s' version %s. Total #%d cycles. Duration %d ms.", knowledge.Name, knowledge.Version, cycle, time.Now().Sub(startTime).Nanoseconds()/1e6)
+	g.log().Debugf("Finished Rules execution. With knowledge base '%s' version %s. Total #%d cycles. Duration %d ms.", knowledge.Name, knowledge.Version, cycle, time.Now().Sub(startTime).Nanoseconds()/1e6)
 
-	return nil
+	return emission.publish()
+}
+
+// fire executes the then scope of the rule entry in the cycle, or its else scope if otherwise is true. It returns
+// true if the execution is done, because the data context is complete or the execution halts.
+func (g *GruleEngine) fire(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase, cycle uint64, runner *ast.RuleEntry, otherwise bool) (bool, error) {
+	// set the current rule entry to run. This is for trace ability purpose
+	dataCtx.SetRuleEntry(runner)
+	// notify listeners that we are about to execute a rule entry then scope
+	g.notifyExecuteRuleEntry(ctx, cycle, runner)
+	var err error
+	if otherwise {
+		err = runner.ExecuteElse(ctx, dataCtx, knowledge.WorkingMemory)
+	} else {
+		err = runner.Execute(ctx, dataCtx, knowledge.WorkingMemory)
+	}
+	if err != nil {
+		g.log().Errorf("Failed execution rule : %s. Got error %v", runner.RuleName, err)
+
+		return false, fmt.Errorf("error while executing rule %s. got %w", runner.RuleName, err)
+	}
+	runner.MarkFired(time.Now())
+	if record := recordFrom(ctx); record != nil {
+		record.Fired = append(record.Fired, runner.RuleName)
+	}
+	if g.Metrics != nil {
+		g.Metrics.recordFire(knowledge.Name, knowledge.Version, runner.StableID())
+	}
+
+	if dataCtx.IsComplete() {
+
+		return true, nil
+	}
+	halt, err := g.halts(dataCtx, knowledge)
+	if err != nil {
+		g.log().Errorf("Failed testing halt conditions. Got error %v", err)
+
+		return false, err
+	}
+
+	return halt, nil
 }
 
 // FetchMatchingRules function is responsible to fetch all the rules that matches to a fact against all rule entries
 // Returns []*ast.RuleEntry order by salience
 func (g *GruleEngine) FetchMatchingRules(dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) ([]*ast.RuleEntry, error) {
+
+	return g.FetchMatchingRulesWithContext(context.Background(), dataCtx, knowledge)
+}
+
+// FetchMatchingRulesWithContext fetches the matching rules as FetchMatchingRules does, leaving out the rules the
+// SecurityContext attached with WithSecurityContext does not allow and the rules waiting for an approval, see Approvals.
+func (g *GruleEngine) FetchMatchingRulesWithContext(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) ([]*ast.RuleEntry, error) {
 	if knowledge == nil || dataCtx == nil {
 
 		return nil, fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
 	}
 
-	log.Debugf("Starting rule matching using knowledge '%s' version %s. Contains %d rule entries", knowledge.Name, knowledge.Version, len(knowledge.RuleEntries))
+	g.log().Debugf("Starting rule matching using knowledge '%s' version %s. Contains %d rule entries", knowledge.Name, knowledge.Version, len(knowledge.RuleEntries))
+	scratchpad, err := g.prepareScratchpad(dataCtx)
+	if err != nil {
+
+		return nil, err
+	}
+
 	// Prepare the build-in function and add to datacontext.
 	defunc := &ast.BuiltInFunctions{
 		Knowledge:     knowledge,
 		WorkingMemory: knowledge.WorkingMemory,
 		DataContext:   dataCtx,
+		Outcomes:      g.outcomeRecorder(),
+		Scratchpad:    scratchpad,
+		Templates:     g.Templates,
+		DateNames:     g.DateNames,
 	}
-	err := dataCtx.Add("DEFUNC", defunc)
+	err = dataCtx.Add("DEFUNC", defunc)
+	if err != nil {
+		g.log().Error("DEFUNC add err")
+
+		return nil, err
+	}
+
+	err = knowledge.InitializeCollections(dataCtx)
 	if err != nil {
-		log.Error("DEFUNC add err")
 
 		return nil, err
 	}
 
 	// Working memory need to be resetted. all Expression will be set as not evaluated.
-	log.Debugf("Resetting Working memory")
+	g.log().Debugf("Resetting Working memory")
 	knowledge.WorkingMemory.ResetAll()
+	knowledge.WorkingMemory.Logger = g.Logger
+	missing, err := g.prepareMissingFacts(dataCtx)
+	if err != nil {
+
+		return nil, err
+	}
+
 	// Initialize all AST with datacontext and working memory
-	log.Debugf("Initializing Context")
+	g.log().Debugf("Initializing Context")
 	knowledge.InitializeContext(dataCtx)
 
 	//Loop through all the rule entries available in the knowledge base and add to the response list if it is able to evaluate
 	// Select all rule entry that can be executed.
-	log.Tracef("Select all rule entry that can be executed.")
+	g.log().Tracef("Select all rule entry that can be executed.")
+	security := SecurityContextFrom(ctx)
+	degraded := g.shedsLowCriticality(knowledge)
+	excluded := g.excludedByRuleTables(dataCtx, knowledge)
+	approvals, err := g.approvalGate(ctx, knowledge)
+	if err != nil {
+
+		return nil, err
+	}
 	runnable := make([]*ast.RuleEntry, 0)
 	for _, entries := range knowledge.RuleEntries {
+		if degraded && entries.Criticality == ast.CriticalityLow {
+
+			continue
+		}
+		if excluded[entries] {
+
+			continue
+		}
+		if !entries.InProfiles(g.Profiles) {
+
+			continue
+		}
 		if !entries.Deleted {
-			// test if this rule entry v can execute.
-			can, err := entries.Evaluate(context.Background(), dataCtx, knowledge.WorkingMemory)
+			allowed, err := security.allows(dataCtx, entries)
 			if err != nil {
-				log.Errorf("Failed testing condition for rule : %s. Got error %v", entries.RuleName, err)
+				g.log().Errorf("Failed testing security predicate for rule : %s. Got error %v", entries.RuleName, err)
+
+				return nil, err
+			}
+			if !allowed {
+
+				continue
+			}
+			// test if this rule entry v can execute.
+			can, err := entries.Evaluate(ctx, dataCtx, knowledge.WorkingMemory)
+			if err != nil && missing != nil && missing.tolerate(entries.RuleName, err) {
+				can = false
+			} else if err != nil {
+				g.log().Errorf("Failed testing condition for rule : %s. Got error %v", entries.RuleName, err)
 				if g.ReturnErrOnFailedRuleEvaluation {
 					return nil, err
 				}
 			}
+			if can {
+				held, err := approvals.holds(entries)
+				if err != nil {
+
+					return nil, err
+				}
+				can = !held
+			}
 			// if can, add into runnable array
 			if can {
 				runnable = append(runnable, entries)
 			}
 		}
 	}
-	log.Debugf("Matching rules length %d.", len(runnable))
+	g.log().Debugf("Matching rules length %d.", len(runnable))
 	if len(runnable) > 1 {
 		sort.SliceStable(runnable, func(i, j int) bool {
 
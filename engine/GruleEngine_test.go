@@ -685,3 +685,135 @@ func TestGruleEngine_Follows_logical_operator_precedence(t *testing.T) {
 	assert.Equal(t, fact.Result, true)
 	assert.Equal(t, fact.NetAmount, float32(143.32))
 }
+
+type FireCounter struct {
+	Count int
+}
+
+const rateLimitedRules = `
+rule LimitedRule "Fires at most 3 times per execution" salience 10 max-fires 3 per execution {
+	when
+		Counter.Count < 10
+	then
+		Counter.Count = Counter.Count + 1;
+}`
+
+func TestGruleEngine_MaxFires(t *testing.T) {
+	counter := &FireCounter{}
+	dctx := ast.NewDataContext()
+	err := dctx.Add("Counter", counter)
+	assert.NoError(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err = rb.BuildRuleFromResource("RateLimit", "0.0.1", pkg.NewBytesResource([]byte(rateLimitedRules)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("RateLimit", "0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, kb.RuleEntries["LimitedRule"].MaxFires)
+
+	engine := NewGruleEngine()
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counter.Count)
+
+	// the limit applies to each execution
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, counter.Count)
+}
+
+const cooldownRules = `
+rule CooldownRule "Fires once an hour" max-fires 1 cooldown 1h {
+	when
+		Counter.Count < 10
+	then
+		Counter.Count = Counter.Count + 1;
+}`
+
+func TestGruleEngine_Cooldown(t *testing.T) {
+	counter := &FireCounter{}
+	dctx := ast.NewDataContext()
+	err := dctx.Add("Counter", counter)
+	assert.NoError(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err = rb.BuildRuleFromResource("Cooldown", "0.0.1", pkg.NewBytesResource([]byte(cooldownRules)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("Cooldown", "0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, kb.RuleEntries["CooldownRule"].Cooldown)
+
+	engine := NewGruleEngine()
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counter.Count)
+
+	// same instance, the rule is still cooling down
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counter.Count)
+
+	kb.RuleEntries["CooldownRule"].LastFired = time.Now().Add(-2 * time.Hour)
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.Count)
+
+	// a new instance does not inherit the cooldown state
+	kb, err = lib.NewKnowledgeBaseInstance("Cooldown", "0.0.1")
+	assert.NoError(t, err)
+	err = engine.Execute(dctx, kb)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counter.Count)
+}
+
+type Throttle struct {
+	Cooldown int
+	MaxFires int
+	Fired    bool
+}
+
+func TestGruleEngine_RateLimitKeywordsAsNames(t *testing.T) {
+	throttle := &Throttle{}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("F", throttle))
+
+	// cooldown and max-fires are only keywords after the name of a rule.
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Names", "0.0.1", pkg.NewBytesResource([]byte(`
+rule Cooldown "named as the keyword" max-fires 1 per execution cooldown 1m {
+	when
+		F.Cooldown == 0 && F.MaxFires-1 < 0 && !F.Fired
+	then
+		F.Cooldown = 30;
+		F.Fired = true;
+}`)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("Names", "0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, kb.RuleEntries["Cooldown"].MaxFires)
+	assert.Equal(t, time.Minute, kb.RuleEntries["Cooldown"].Cooldown)
+	assert.NoError(t, NewGruleEngine().Execute(dctx, kb))
+	assert.True(t, throttle.Fired)
+	assert.Equal(t, 30, throttle.Cooldown)
+
+	for _, header := range []string{"max - fires 1", "max-fire 1", "cooldwn 1m"} {
+		err = rb.BuildRuleFromResource("Invalid", "0.0.1", pkg.NewBytesResource([]byte(`rule R `+header+` { when true then Retract("R"); }`)))
+		assert.Error(t, err, header)
+	}
+}
+
+func TestGruleEngine_InvalidRateLimit(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Invalid", "0.0.1", pkg.NewBytesResource([]byte(`
+rule ZeroFires max-fires 0 {
+	when
+		true
+	then
+		Retract("ZeroFires");
+}`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// halts tells whether the execution should stop after a rule fired, because the StopWhen predicate of the engine
+// or a halt condition declared in the knowledge base holds.
+func (g *GruleEngine) halts(dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) (bool, error) {
+	if g.StopWhen != nil && g.StopWhen(dataCtx) {
+		g.log().Debugf("Execution halted by the StopWhen predicate")
+
+		return true, nil
+	}
+	for _, entry := range knowledge.HaltEntries {
+		halt, err := entry.Evaluate(dataCtx, knowledge.WorkingMemory)
+		if err != nil {
+
+			return false, fmt.Errorf("error while evaluating halt condition. got %w", err)
+		}
+		if halt {
+			g.log().Debugf("Execution halted by %s", entry.GrlText)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,242 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// ExecutionRecord is what the History keeps of one execution: when it ran, for which facts, and the rules it fired.
+type ExecutionRecord struct {
+	ID            string        `json:"id"`
+	KnowledgeBase string        `json:"knowledgeBase"`
+	Version       string        `json:"version"`
+	Start         time.Time     `json:"start"`
+	Duration      time.Duration `json:"duration"`
+	// FactIDs are the ids attached with WithFactIDs, such as the number of the order the execution decided on.
+	FactIDs []string `json:"factIds,omitempty"`
+	// Fired are the names of the rules fired, in the order they fired. A rule firing several times is repeated.
+	Fired []string `json:"fired,omitempty"`
+	// Error is the error the execution ended with, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+	// Trace is the trace of the execution, if the Tracer of the engine sampled it.
+	Trace *Trace `json:"trace,omitempty"`
+}
+
+// HasFired tells if the rule fired during the execution.
+func (record *ExecutionRecord) HasFired(rule string) bool {
+	for _, fired := range record.Fired {
+		if fired == rule {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExecutionQuery selects the records of ExecutionStore.QueryExecutions. The zero value of a criterion matches any
+// record, the records match every criterion set.
+type ExecutionQuery struct {
+	KnowledgeBase string
+	// FactID matches the executions having the fact id.
+	FactID string
+	// Rule matches the executions that fired the rule.
+	Rule string
+	// From and To bound the start of the executions, From included and To excluded.
+	From time.Time
+	To   time.Time
+	// Limit is the maximum number of records returned, zero is unlimited.
+	Limit int
+}
+
+// matches tells if the record matches every criterion of the query.
+func (query ExecutionQuery) matches(record *ExecutionRecord) bool {
+	if len(query.KnowledgeBase) > 0 && record.KnowledgeBase != query.KnowledgeBase {
+
+		return false
+	}
+	if len(query.Rule) > 0 && !record.HasFired(query.Rule) {
+
+		return false
+	}
+	if !query.From.IsZero() && record.Start.Before(query.From) {
+
+		return false
+	}
+	if !query.To.IsZero() && !record.Start.Before(query.To) {
+
+		return false
+	}
+	if len(query.FactID) == 0 {
+
+		return true
+	}
+	for _, factID := range record.FactIDs {
+		if factID == query.FactID {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExecutionStore is a storage driver persisting the history of the executions, so support tooling can look up the
+// decisions made, such as every execution that fired a rule last Tuesday. It must be safe for concurrent use.
+type ExecutionStore interface {
+	// SaveExecution adds the record of an execution.
+	SaveExecution(ctx context.Context, record *ExecutionRecord) error
+	// QueryExecutions returns the records matching the query, the most recent first.
+	QueryExecutions(ctx context.Context, query ExecutionQuery) ([]*ExecutionRecord, error)
+}
+
+// NewMemoryExecutionStore creates new MemoryExecutionStore.
+func NewMemoryExecutionStore() *MemoryExecutionStore {
+
+	return &MemoryExecutionStore{records: make([]*ExecutionRecord, 0)}
+}
+
+// MemoryExecutionStore keeps the records in memory. They do not survive the process, it is meant for tests.
+type MemoryExecutionStore struct {
+	lock    sync.Mutex
+	records []*ExecutionRecord
+}
+
+// SaveExecution implements ExecutionStore.
+func (store *MemoryExecutionStore) SaveExecution(ctx context.Context, record *ExecutionRecord) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	saved := *record
+	store.records = append(store.records, &saved)
+
+	return nil
+}
+
+// QueryExecutions implements ExecutionStore.
+func (store *MemoryExecutionStore) QueryExecutions(ctx context.Context, query ExecutionQuery) ([]*ExecutionRecord, error) {
+	store.lock.Lock()
+	records := make([]*ExecutionRecord, 0)
+	for _, record := range store.records {
+		if query.matches(record) {
+			found := *record
+			records = append(records, &found)
+		}
+	}
+	store.lock.Unlock()
+	sort.SliceStable(records, func(i, j int) bool {
+
+		return records[i].Start.After(records[j].Start)
+	})
+	if query.Limit > 0 && len(records) > query.Limit {
+		records = records[:query.Limit]
+	}
+
+	return records, nil
+}
+
+// NewHistory creates new History saving the executions into the store.
+func NewHistory(store ExecutionStore) *History {
+
+	return &History{Store: store}
+}
+
+// History records every execution of the engine into an ExecutionStore. Set it to GruleEngine.History.
+// History is safe to be shared by concurrent executions.
+type History struct {
+	Store ExecutionStore
+	// Timeout bounds the saving of a record, zero is unlimited. The record is saved once the execution ended, within
+	// its context but not canceled by it.
+	Timeout time.Duration
+}
+
+// Query returns the records of the executions matching the query, the most recent first.
+func (history *History) Query(ctx context.Context, query ExecutionQuery) ([]*ExecutionRecord, error) {
+	records, err := history.Store.QueryExecutions(ctx, query)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while querying the execution history. got %w", err)
+	}
+
+	return records, nil
+}
+
+type historyKey struct{}
+
+type factIDsKey struct{}
+
+// WithFactIDs attaches the ids of the facts of the execution, such as the number of the order being decided on, to
+// the context given to ExecuteWithContext. The History records them, so the execution can be queried by them.
+func WithFactIDs(ctx context.Context, factIDs ...string) context.Context {
+
+	return context.WithValue(ctx, factIDsKey{}, factIDs)
+}
+
+// start returns the record of an execution, nil if there is no history.
+func (history *History) start(ctx context.Context, knowledge *ast.KnowledgeBase) *ExecutionRecord {
+	if history == nil || history.Store == nil {
+
+		return nil
+	}
+	factIDs, _ := ctx.Value(factIDsKey{}).([]string)
+
+	return &ExecutionRecord{
+		ID:            uuid.New().String(),
+		KnowledgeBase: knowledge.Name,
+		Version:       knowledge.Version,
+		Start:         time.Now(),
+		FactIDs:       factIDs,
+	}
+}
+
+// finish ends the record and saves it. A record that can not be saved is logged, the execution itself went fine.
+func (history *History) finish(ctx context.Context, record *ExecutionRecord, trace *Trace, err error) {
+	record.Duration = time.Since(record.Start)
+	record.Trace = trace
+	if err != nil {
+		record.Error = err.Error()
+	}
+	ctx = context.WithoutCancel(ctx)
+	if history.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, history.Timeout)
+		defer cancel()
+	}
+	saveErr := history.Store.SaveExecution(ctx, record)
+	if saveErr != nil {
+		logger.FromContext(ctx).Or(log).Errorf("Failed saving the record of execution %s of knowledge base '%s'. Got error %v", record.ID, record.KnowledgeBase, saveErr)
+	}
+}
+
+// withRecord attaches the record of the execution to its context.
+func withRecord(ctx context.Context, record *ExecutionRecord) context.Context {
+
+	return context.WithValue(ctx, historyKey{}, record)
+}
+
+// recordFrom returns the record attached to the context, nil if the execution is not recorded.
+func recordFrom(ctx context.Context) *ExecutionRecord {
+	record, _ := ctx.Value(historyKey{}).(*ExecutionRecord)
+
+	return record
+}
@@ -0,0 +1,90 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Loan", "1.0.0", pkg.NewBytesResource([]byte(outcomeRules))))
+
+	engine := NewGruleEngine()
+	engine.History = NewHistory(NewMemoryExecutionStore())
+	start := time.Now()
+	execute := func(loanID string, score int64) {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Loan", &LoanApplication{Score: score}))
+		kb, err := lib.NewKnowledgeBaseInstance("Loan", "1.0.0")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.ExecuteWithContext(WithFactIDs(context.Background(), loanID), dctx, kb))
+	}
+	execute("loan-1", 800)
+	execute("loan-2", 600)
+	engine.Tracer = NewTracer(1, 0)
+	engine.Tracer.Sink = func(trace *Trace) {}
+	execute("loan-1", 750)
+
+	ctx := context.Background()
+	records, err := engine.History.Query(ctx, ExecutionQuery{FactID: "loan-1"})
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		// the most recent first, traced as it was sampled.
+		assert.Equal(t, []string{"Approve"}, records[0].Fired)
+		assert.NotNil(t, records[0].Trace)
+		assert.Nil(t, records[1].Trace)
+		assert.Equal(t, "Loan", records[1].KnowledgeBase)
+		assert.Equal(t, []string{"loan-1"}, records[1].FactIDs)
+		assert.NotEqual(t, records[0].ID, records[1].ID)
+	}
+
+	records, err = engine.History.Query(ctx, ExecutionQuery{Rule: "Decline"})
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, []string{"loan-2"}, records[0].FactIDs)
+	}
+	records, err = engine.History.Query(ctx, ExecutionQuery{Rule: "Approve", From: start, To: time.Now(), Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	records, err = engine.History.Query(ctx, ExecutionQuery{KnowledgeBase: "Loan", To: start})
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
+
+func TestSelectExecutions(t *testing.T) {
+	statement, args, err := selectExecutions(DefaultExecutionTable, ExecutionQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, knowledge_base, version, started_at, duration_ns, fact_ids, fired, error, trace FROM grule_executions ORDER BY started_at DESC", statement)
+	assert.Len(t, args, 0)
+
+	from := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	statement, args, err = selectExecutions("audit.executions", ExecutionQuery{FactID: "order-42", Rule: "Refund", From: from, To: from.AddDate(0, 0, 1), Limit: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, knowledge_base, version, started_at, duration_ns, fact_ids, fired, error, trace FROM audit.executions"+
+		" WHERE fact_ids @> $1::jsonb AND fired @> $2::jsonb AND started_at >= $3 AND started_at < $4 ORDER BY started_at DESC LIMIT $5", statement)
+	assert.Equal(t, []interface{}{`["order-42"]`, `["Refund"]`, from, from.AddDate(0, 0, 1), 50}, args)
+
+	_, err = (&PostgresExecutionStore{Table: "executions; DROP TABLE rules"}).QueryExecutions(context.Background(), ExecutionQuery{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,273 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+const (
+	// RefreshExecutions is the reason of a refresh after RefreshPolicy.MaxExecutions executions.
+	RefreshExecutions = "executions"
+	// RefreshAge is the reason of a refresh after RefreshPolicy.MaxAge.
+	RefreshAge = "age"
+	// RefreshWarmUp is the reason of an instance created to fill the pool up to its size.
+	RefreshWarmUp = "warm-up"
+)
+
+// KnowledgeBaseSource creates the KnowledgeBase instances of a pool, such as ast.KnowledgeLibrary
+// or builder.DistributedKnowledge.
+type KnowledgeBaseSource interface {
+	NewKnowledgeBaseInstance(name, version string) (*ast.KnowledgeBase, error)
+}
+
+// RefreshPolicy tells when the instances of an InstancePool are replaced by new ones. Zero disables the limit.
+type RefreshPolicy struct {
+	// MaxExecutions is the number of executions after which an instance is refreshed.
+	MaxExecutions int
+	// MaxAge is the time after which an instance is refreshed, picking up a KnowledgeBase swapped in the source.
+	MaxAge time.Duration
+	// Interval is how often Run looks for instances to refresh, one second if zero.
+	Interval time.Duration
+}
+
+// RefreshEvent describes an instance created by the refresher of an InstancePool.
+type RefreshEvent struct {
+	Name    string
+	Version string
+	// Reason is RefreshExecutions, RefreshAge or RefreshWarmUp.
+	Reason string
+	// Executions and Age are those of the replaced instance.
+	Executions int
+	Age        time.Duration
+	// Err is the error creating the new instance, the old one is then kept.
+	Err error
+}
+
+type pooledInstance struct {
+	knowledgeBase *ast.KnowledgeBase
+	created       time.Time
+	executions    int
+}
+
+// NewInstancePool creates a pool keeping up to size warm KnowledgeBase instances of the name and version.
+func NewInstancePool(source KnowledgeBaseSource, name, version string, size int) *InstancePool {
+
+	return &InstancePool{
+		Source:   source,
+		Name:     name,
+		Version:  version,
+		Size:     size,
+		borrowed: make(map[*ast.KnowledgeBase]*pooledInstance),
+	}
+}
+
+// InstancePool keeps warm KnowledgeBase instances ready for execution, so a request does not wait for
+// the KnowledgeBase to be cloned. Run refreshes the instances according to the Policy in the background.
+type InstancePool struct {
+	Source  KnowledgeBaseSource
+	Name    string
+	Version string
+	Size    int
+	Policy  RefreshPolicy
+	// OnRefresh, if set, is called after every refresh.
+	OnRefresh func(event RefreshEvent)
+
+	mutex    sync.Mutex
+	idle     []*pooledInstance
+	borrowed map[*ast.KnowledgeBase]*pooledInstance
+}
+
+// Get takes an instance from the pool, or creates one if none is idle. Give it back with Put once executed.
+func (pool *InstancePool) Get() (*ast.KnowledgeBase, error) {
+	pool.mutex.Lock()
+	if len(pool.idle) > 0 {
+		instance := pool.idle[len(pool.idle)-1]
+		pool.idle = pool.idle[:len(pool.idle)-1]
+		pool.borrowed[instance.knowledgeBase] = instance
+		pool.mutex.Unlock()
+
+		return instance.knowledgeBase, nil
+	}
+	pool.mutex.Unlock()
+
+	instance, err := pool.newInstance()
+	if err != nil {
+
+		return nil, err
+	}
+	pool.mutex.Lock()
+	pool.borrowed[instance.knowledgeBase] = instance
+	pool.mutex.Unlock()
+
+	return instance.knowledgeBase, nil
+}
+
+// Put gives back an instance taken with Get after one execution. Instances beyond the pool size are dropped.
+func (pool *InstancePool) Put(knowledgeBase *ast.KnowledgeBase) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	instance, ok := pool.borrowed[knowledgeBase]
+	if !ok {
+
+		return
+	}
+	delete(pool.borrowed, knowledgeBase)
+	instance.executions++
+	if len(pool.idle) < pool.Size {
+		pool.idle = append(pool.idle, instance)
+	}
+}
+
+// Execute runs the engine on an instance of the pool.
+func (pool *InstancePool) Execute(ctx context.Context, engine *GruleEngine, dataCtx ast.IDataContext) error {
+	knowledgeBase, err := pool.Get()
+	if err != nil {
+
+		return err
+	}
+	defer pool.Put(knowledgeBase)
+
+	return engine.ExecuteWithContext(ctx, dataCtx, knowledgeBase)
+}
+
+// Idle returns the number of instances ready in the pool.
+func (pool *InstancePool) Idle() int {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	return len(pool.idle)
+}
+
+// Run fills the pool up to its size, then refreshes its idle instances according to the Policy until
+// the context is done.
+func (pool *InstancePool) Run(ctx context.Context) error {
+	interval := pool.Policy.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pool.Refresh()
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh replaces the idle instances due for refresh and fills the pool up to its size once.
+// Instances in use are checked once they are given back.
+func (pool *InstancePool) Refresh() {
+	now := time.Now()
+	pool.mutex.Lock()
+	var due []*pooledInstance
+	var reasons []string
+	kept := pool.idle[:0]
+	for _, instance := range pool.idle {
+		reason := pool.refreshReason(instance, now)
+		if len(reason) == 0 {
+			kept = append(kept, instance)
+
+			continue
+		}
+		due = append(due, instance)
+		reasons = append(reasons, reason)
+	}
+	pool.idle = kept
+	missing := pool.Size - len(pool.idle) - len(due) - len(pool.borrowed)
+	pool.mutex.Unlock()
+
+	for i, old := range due {
+		event := RefreshEvent{
+			Name:       pool.Name,
+			Version:    pool.Version,
+			Reason:     reasons[i],
+			Executions: old.executions,
+			Age:        now.Sub(old.created),
+		}
+		instance, err := pool.newInstance()
+		if err != nil {
+			event.Err = err
+			instance = old
+		}
+		pool.release(instance)
+		pool.notify(event)
+	}
+	for i := 0; i < missing; i++ {
+		event := RefreshEvent{
+			Name:    pool.Name,
+			Version: pool.Version,
+			Reason:  RefreshWarmUp,
+		}
+		instance, err := pool.newInstance()
+		if err != nil {
+			event.Err = err
+			pool.notify(event)
+
+			return
+		}
+		pool.release(instance)
+		pool.notify(event)
+	}
+}
+
+func (pool *InstancePool) refreshReason(instance *pooledInstance, now time.Time) string {
+	if pool.Policy.MaxExecutions > 0 && instance.executions >= pool.Policy.MaxExecutions {
+
+		return RefreshExecutions
+	}
+	if pool.Policy.MaxAge > 0 && now.Sub(instance.created) >= pool.Policy.MaxAge {
+
+		return RefreshAge
+	}
+
+	return ""
+}
+
+func (pool *InstancePool) newInstance() (*pooledInstance, error) {
+	knowledgeBase, err := pool.Source.NewKnowledgeBaseInstance(pool.Name, pool.Version)
+	if err != nil {
+
+		return nil, fmt.Errorf("error creating instance of KnowledgeBase %s version %s. got %w", pool.Name, pool.Version, err)
+	}
+
+	return &pooledInstance{knowledgeBase: knowledgeBase, created: time.Now()}, nil
+}
+
+func (pool *InstancePool) release(instance *pooledInstance) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if len(pool.idle) < pool.Size {
+		pool.idle = append(pool.idle, instance)
+	}
+}
+
+func (pool *InstancePool) notify(event RefreshEvent) {
+	if event.Err != nil {
+		log.Warnf("Refresh of KnowledgeBase %s version %s failed. %v", event.Name, event.Version, event.Err)
+	}
+	if pool.OnRefresh != nil {
+		pool.OnRefresh(event)
+	}
+}
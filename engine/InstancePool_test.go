@@ -0,0 +1,127 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type PoolFact struct {
+	Result string
+}
+
+func poolRule(result string) string {
+
+	return `rule Set { when Fact.Result == "" then Fact.Result = "` + result + `"; Retract("Set"); }`
+}
+
+func TestInstancePool(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Pool", "0.0.1", pkg.NewBytesResource([]byte(poolRule("v1")))))
+
+	var events []RefreshEvent
+	pool := NewInstancePool(lib, "Pool", "0.0.1", 2)
+	pool.Policy = RefreshPolicy{MaxExecutions: 2}
+	pool.OnRefresh = func(event RefreshEvent) {
+		events = append(events, event)
+	}
+	pool.Refresh()
+	assert.Equal(t, 2, pool.Idle())
+	assert.Len(t, events, 2)
+	assert.Equal(t, RefreshWarmUp, events[0].Reason)
+
+	execute := func() string {
+		fact := &PoolFact{}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		assert.NoError(t, pool.Execute(context.Background(), NewGruleEngine(), dctx))
+
+		return fact.Result
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "v1", execute())
+	}
+	assert.Equal(t, 2, pool.Idle())
+
+	// the same instance was executed twice
+	events = nil
+	pool.Refresh()
+	assert.Len(t, events, 1)
+	assert.Equal(t, RefreshExecutions, events[0].Reason)
+	assert.Equal(t, 2, events[0].Executions)
+	assert.NoError(t, events[0].Err)
+	assert.Equal(t, 2, pool.Idle())
+
+	// a KnowledgeBase swapped in the library is picked up once the instances are old enough
+	swapped := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(swapped).BuildRuleFromResource("Pool", "0.0.1", pkg.NewBytesResource([]byte(poolRule("v2")))))
+	lib.Library[ast.GetKnowledgeBaseKey("Pool", "0.0.1")] = swapped.GetKnowledgeBase("Pool", "0.0.1")
+	assert.Equal(t, "v1", execute())
+	pool.Policy = RefreshPolicy{MaxAge: time.Nanosecond}
+	events = nil
+	pool.Refresh()
+	assert.Len(t, events, 2)
+	assert.Equal(t, RefreshAge, events[0].Reason)
+	assert.Equal(t, "v2", execute())
+	assert.Equal(t, "v2", execute())
+
+	// a failed refresh keeps the old instance
+	pool.Name = "Missing"
+	events = nil
+	pool.Refresh()
+	assert.Len(t, events, 2)
+	assert.Error(t, events[0].Err)
+	assert.Equal(t, 2, pool.Idle())
+}
+
+func TestInstancePool_Run(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Pool", "0.0.1", pkg.NewBytesResource([]byte(poolRule("v1")))))
+
+	var mutex sync.Mutex
+	var refreshes int
+	pool := NewInstancePool(lib, "Pool", "0.0.1", 3)
+	pool.Policy = RefreshPolicy{MaxAge: 5 * time.Millisecond, Interval: time.Millisecond}
+	pool.OnRefresh = func(event RefreshEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if event.Reason == RefreshAge {
+			refreshes++
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- pool.Run(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return refreshes >= 3
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, 3, pool.Idle())
+}
@@ -0,0 +1,97 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const tenantRules = `
+rule Greet "greets the tenant" {
+	when
+		Tenant.Greeted == false
+	then
+		Log("hello " + Tenant.Name);
+		Tenant.Greeted = true;
+}
+`
+
+type Tenant struct {
+	Name    string
+	Greeted bool
+}
+
+func newTenantLogger(t *testing.T, level logrus.Level) (logger.LogEntry, *bytes.Buffer) {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	logrusLogger := logrus.New()
+	logrusLogger.SetOutput(buffer)
+	logrusLogger.SetLevel(level)
+	entry, ok := logger.FromExternal(logrusLogger)
+	assert.True(t, ok)
+
+	return entry, buffer
+}
+
+func TestGruleEngine_Logger(t *testing.T) {
+	acmeLog, acmeBuffer := newTenantLogger(t, logrus.DebugLevel)
+	globexLog, globexBuffer := newTenantLogger(t, logrus.InfoLevel)
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	rb.Logger = acmeLog
+	assert.NoError(t, rb.BuildRuleFromResource("Tenant", "0.0.1", pkg.NewBytesResource([]byte(tenantRules))))
+	assert.Contains(t, acmeBuffer.String(), "Loading rule resource")
+
+	for _, tenant := range []struct {
+		name   string
+		log    logger.LogEntry
+		buffer *bytes.Buffer
+	}{
+		{name: "acme", log: acmeLog, buffer: acmeBuffer},
+		{name: "globex", log: globexLog, buffer: globexBuffer},
+	} {
+		kb, err := lib.NewKnowledgeBaseInstance("Tenant", "0.0.1")
+		assert.NoError(t, err)
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Tenant", &Tenant{Name: tenant.name}))
+		eng := NewGruleEngine()
+		eng.Logger = tenant.log
+		assert.NoError(t, eng.Execute(dctx, kb))
+		assert.Contains(t, tenant.buffer.String(), "hello "+tenant.name)
+	}
+
+	// each engine logs into its own logger only, at its own level.
+	assert.NotContains(t, acmeBuffer.String(), "hello globex")
+	assert.NotContains(t, globexBuffer.String(), "hello acme")
+	assert.Contains(t, acmeBuffer.String(), "Starting rule execution")
+	assert.NotContains(t, globexBuffer.String(), "Starting rule execution")
+}
+
+func TestLogEntry_Or(t *testing.T) {
+	assert.Equal(t, log, logger.LogEntry{}.Or(log))
+	entry, _ := newTenantLogger(t, logrus.InfoLevel)
+	assert.Equal(t, entry, entry.Or(log))
+	_, ok := logger.FromExternal("not a logger")
+	assert.False(t, ok)
+}
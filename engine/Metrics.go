@@ -0,0 +1,123 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"sort"
+	"sync"
+)
+
+// OutcomeKey identifies a decision outcome counter.
+type OutcomeKey struct {
+	KnowledgeBase string
+	Version       string
+	// RuleID is the id declared by the rule, it stays the same when the rule is renamed. Empty if not declared.
+	RuleID   string
+	RuleName string
+	Label    string
+}
+
+// OutcomeCount is the value of a decision outcome counter.
+type OutcomeCount struct {
+	OutcomeKey
+	Count uint64
+}
+
+// NewMetrics create new instance of Metrics
+func NewMetrics() *Metrics {
+
+	return &Metrics{
+		outcomes:   make(map[OutcomeKey]uint64),
+		executions: make(map[versionKey]uint64),
+		fires:      make(map[ruleKey]uint64),
+	}
+}
+
+// Metrics holds the counters of a GruleEngine. It is safe to be used by concurrent executions.
+type Metrics struct {
+	mutex    sync.RWMutex
+	outcomes map[OutcomeKey]uint64
+
+	// executions and fires are the rule statistics, see RuleStatistics.
+	executions map[versionKey]uint64
+	fires      map[ruleKey]uint64
+}
+
+// RecordOutcome increments the counter of an outcome label, it is called by the RecordOutcome built-in function.
+func (m *Metrics) RecordOutcome(knowledgeBase, version, ruleID, ruleName, label string) {
+	key := OutcomeKey{
+		KnowledgeBase: knowledgeBase,
+		Version:       version,
+		RuleID:        ruleID,
+		RuleName:      ruleName,
+		Label:         label,
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.outcomes[key]++
+}
+
+// Outcomes returns a snapshot of all outcome counters, sorted by knowledge base, version, rule name and label.
+func (m *Metrics) Outcomes() []OutcomeCount {
+	m.mutex.RLock()
+	ret := make([]OutcomeCount, 0, len(m.outcomes))
+	for key, count := range m.outcomes {
+		ret = append(ret, OutcomeCount{OutcomeKey: key, Count: count})
+	}
+	m.mutex.RUnlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i].OutcomeKey, ret[j].OutcomeKey
+		if a.KnowledgeBase != b.KnowledgeBase {
+
+			return a.KnowledgeBase < b.KnowledgeBase
+		}
+		if a.Version != b.Version {
+
+			return a.Version < b.Version
+		}
+		if a.RuleName != b.RuleName {
+
+			return a.RuleName < b.RuleName
+		}
+
+		return a.Label < b.Label
+	})
+
+	return ret
+}
+
+// OutcomeTotal returns the count of an outcome label summed over all rules of a knowledge base version.
+func (m *Metrics) OutcomeTotal(knowledgeBase, version, label string) uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	total := uint64(0)
+	for key, count := range m.outcomes {
+		if key.KnowledgeBase == knowledgeBase && key.Version == version && key.Label == label {
+			total += count
+		}
+	}
+
+	return total
+}
+
+// Reset clears all counters.
+func (m *Metrics) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.outcomes = make(map[OutcomeKey]uint64)
+	m.executions = make(map[versionKey]uint64)
+	m.fires = make(map[ruleKey]uint64)
+}
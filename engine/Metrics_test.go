@@ -0,0 +1,84 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type LoanApplication struct {
+	Score    int64
+	Decision string
+}
+
+const outcomeRules = `
+rule Approve "Approve good scores" {
+	when
+		Loan.Decision == "" && Loan.Score >= 700
+	then
+		Loan.Decision = "approved";
+		RecordOutcome("approved");
+}
+rule Decline "Decline bad scores" {
+	when
+		Loan.Decision == "" && Loan.Score < 700
+	then
+		Loan.Decision = "declined";
+		RecordOutcome("declined");
+}`
+
+func TestGruleEngine_RecordOutcome(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Loan", "1.0.0", pkg.NewBytesResource([]byte(outcomeRules)))
+	assert.NoError(t, err)
+	err = rb.BuildRuleFromResource("Loan", "2.0.0", pkg.NewBytesResource([]byte(outcomeRules)))
+	assert.NoError(t, err)
+
+	engine := NewGruleEngine()
+	execute := func(version string, score int64) {
+		dctx := ast.NewDataContext()
+		err := dctx.Add("Loan", &LoanApplication{Score: score})
+		assert.NoError(t, err)
+		kb, err := lib.NewKnowledgeBaseInstance("Loan", version)
+		assert.NoError(t, err)
+		assert.NoError(t, engine.Execute(dctx, kb))
+	}
+	for _, score := range []int64{800, 750, 600} {
+		execute("1.0.0", score)
+	}
+	execute("2.0.0", 500)
+
+	assert.Equal(t, uint64(2), engine.Metrics.OutcomeTotal("Loan", "1.0.0", "approved"))
+	assert.Equal(t, uint64(1), engine.Metrics.OutcomeTotal("Loan", "1.0.0", "declined"))
+	assert.Equal(t, uint64(0), engine.Metrics.OutcomeTotal("Loan", "2.0.0", "approved"))
+	assert.Equal(t, []OutcomeCount{
+		{OutcomeKey{"Loan", "1.0.0", "", "Approve", "approved"}, 2},
+		{OutcomeKey{"Loan", "1.0.0", "", "Decline", "declined"}, 1},
+		{OutcomeKey{"Loan", "2.0.0", "", "Decline", "declined"}, 1},
+	}, engine.Metrics.Outcomes())
+
+	engine.Metrics.Reset()
+	assert.Empty(t, engine.Metrics.Outcomes())
+
+	// without metrics the outcome is ignored
+	engine.Metrics = nil
+	execute("1.0.0", 800)
+}
@@ -0,0 +1,70 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"errors"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// MissingFacts makes the engine tolerate rules referring to facts that were not added to the data context.
+// A fact with a default is added to the data context before the execution, so the rules see its zero values.
+// A rule whose when scope refers to a missing fact without a default does not match and a warning is logged,
+// even if ReturnErrOnFailedRuleEvaluation is set. Then scopes referring to missing facts still fail.
+type MissingFacts struct {
+	// Defaults creates the value of a fact by its name, such as func() interface{} { return &Enrichment{} }.
+	Defaults map[string]func() interface{}
+}
+
+// addDefaults adds the default of every fact that is not in the data context.
+func (mf *MissingFacts) addDefaults(dataCtx ast.IDataContext, log logger.LogEntry) error {
+	for name, newFact := range mf.Defaults {
+		if dataCtx.Get(name) != nil {
+
+			continue
+		}
+		log.Warnf("Fact %s is not in the data context, using its default", name)
+		err := dataCtx.Add(name, newFact())
+		if err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// missingFactTracker warns once per execution for every missing fact.
+type missingFactTracker struct {
+	warned map[string]bool
+	log    logger.LogEntry
+}
+
+// tolerate tells whether the when scope error of the rule is caused by a missing fact, and logs it.
+func (t *missingFactTracker) tolerate(ruleName string, err error) bool {
+	var missing *ast.MissingFactError
+	if !errors.As(err, &missing) {
+
+		return false
+	}
+	if !t.warned[missing.Name] {
+		t.warned[missing.Name] = true
+		t.log.Warnf("Rule %s refers to fact %s which is not in the data context, the rule does not match", ruleName, missing.Name)
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Enrichment struct {
+	RiskScore int64
+	Blocked   bool
+}
+
+const missingFactRules = `
+rule Base "always decide" salience 10 {
+	when
+		Loan.Decision == ""
+	then
+		Loan.Decision = "approved";
+}
+rule Risky "decline risky loans" salience 20 {
+	when
+		Loan.Decision == "" && Enrich.RiskScore > 50
+	then
+		Loan.Decision = "declined";
+}
+rule Fraud "block fraudulent loans" salience 30 {
+	when
+		Loan.Decision == "" && Fraud.Blocked
+	then
+		Loan.Decision = "blocked";
+}`
+
+func TestGruleEngine_MissingFacts(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Missing", "1.0.0", pkg.NewBytesResource([]byte(missingFactRules)))
+	assert.NoError(t, err)
+
+	execute := func(engine *GruleEngine, enrich *Enrichment) (*LoanApplication, error) {
+		loan := &LoanApplication{}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Loan", loan))
+		if enrich != nil {
+			assert.NoError(t, dctx.Add("Enrich", enrich))
+		}
+		kb, err := lib.NewKnowledgeBaseInstance("Missing", "1.0.0")
+		assert.NoError(t, err)
+
+		return loan, engine.Execute(dctx, kb)
+	}
+
+	strict := NewGruleEngine()
+	strict.ReturnErrOnFailedRuleEvaluation = true
+	_, err = execute(strict, &Enrichment{})
+	assert.True(t, errors.Is(err, ast.ErrMissingFact))
+
+	tolerant := NewGruleEngine()
+	tolerant.ReturnErrOnFailedRuleEvaluation = true
+	tolerant.MissingFacts = &MissingFacts{
+		Defaults: map[string]func() interface{}{
+			"Enrich": func() interface{} {
+
+				return &Enrichment{}
+			},
+		},
+	}
+	// Fraud has no default so its rule never matches, Enrich is added with its zero values.
+	loan, err := execute(tolerant, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "approved", loan.Decision)
+
+	loan, err = execute(tolerant, &Enrichment{RiskScore: 80})
+	assert.NoError(t, err)
+	assert.Equal(t, "declined", loan.Decision)
+
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Loan", &LoanApplication{}))
+	kb, err := lib.NewKnowledgeBaseInstance("Missing", "1.0.0")
+	assert.NoError(t, err)
+	matching, err := tolerant.FetchMatchingRules(dctx, kb)
+	assert.NoError(t, err)
+	if assert.Len(t, matching, 1) {
+		assert.Equal(t, "Base", matching[0].RuleName)
+	}
+}
@@ -0,0 +1,235 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+type outputsKey struct{}
+
+// NewOutputs create new instance of Outputs
+func NewOutputs() *Outputs {
+
+	return &Outputs{
+		messages: make(map[reflect.Type][]interface{}),
+		channels: make(map[reflect.Type][]reflect.Value),
+		sinks:    make(map[string]Sink),
+	}
+}
+
+// Outputs collects the messages the rules emit with the Emit built-in function, keyed by their Go type, such as
+//
+//	then
+//	    Emit(Offers.Propose(Customer.ID, 10));
+//	    Retract("ProposeOffer");
+//
+// The messages of an execution are published once it succeeds, in the order they were emitted. An execution that
+// fails publishes none of its messages. Outputs is safe to be shared by concurrent executions.
+//
+// A rule annotated with @sink("fraud-queue") also routes the messages it emits to the sink registered with that
+// name, such as a Kafka topic, so the rules decide where each message goes.
+type Outputs struct {
+	mutex    sync.RWMutex
+	messages map[reflect.Type][]interface{}
+	channels map[reflect.Type][]reflect.Value
+	sinks    map[string]Sink
+}
+
+// Sink receives the published messages of the rules annotated with its name. An error is returned by the
+// execution publishing the message, once the message is given to the other sinks.
+type Sink func(message interface{}) error
+
+// SinkChannel returns a Sink sending the messages into the channel. A message that is not of type T is an error.
+// Sending blocks the execution publishing the message, the channel should be buffered or be received from by
+// another goroutine.
+func SinkChannel[T any](channel chan<- T) Sink {
+
+	return func(message interface{}) error {
+		typed, ok := message.(T)
+		if !ok {
+
+			return fmt.Errorf("sink channel of %s can not receive %T", reflect.TypeOf((*T)(nil)).Elem(), message)
+		}
+		channel <- typed
+
+		return nil
+	}
+}
+
+// WithOutputs attaches the outputs to the context given to ExecuteWithContext.
+func WithOutputs(ctx context.Context, outputs *Outputs) context.Context {
+
+	return context.WithValue(ctx, outputsKey{}, outputs)
+}
+
+// OutputsFrom returns the outputs attached to the context, nil if there is none.
+func OutputsFrom(ctx context.Context) *Outputs {
+	outputs, _ := ctx.Value(outputsKey{}).(*Outputs)
+
+	return outputs
+}
+
+// Emitted returns the published messages of type T. A message emitted as a pointer is only returned for the
+// pointer type.
+func Emitted[T any](outputs *Outputs) []T {
+	outputs.mutex.RLock()
+	defer outputs.mutex.RUnlock()
+	messages := outputs.messages[reflect.TypeOf((*T)(nil)).Elem()]
+	ret := make([]T, len(messages))
+	for i, message := range messages {
+		ret[i] = message.(T)
+	}
+
+	return ret
+}
+
+// Subscribe sends every message of type T published from now on into the channel. Sending blocks the execution
+// publishing the message, the channel should be buffered or be received from by another goroutine.
+func Subscribe[T any](outputs *Outputs, channel chan<- T) {
+	messageType := reflect.TypeOf((*T)(nil)).Elem()
+	outputs.mutex.Lock()
+	defer outputs.mutex.Unlock()
+	outputs.channels[messageType] = append(outputs.channels[messageType], reflect.ValueOf(channel))
+}
+
+// RegisterSink registers the sink receiving the messages of the rules annotated with @sink and the name.
+// Registering a name again replaces its sink.
+func (outputs *Outputs) RegisterSink(name string, sink Sink) {
+	outputs.mutex.Lock()
+	defer outputs.mutex.Unlock()
+	outputs.sinks[name] = sink
+}
+
+// Reset forgets every published message, the subscriptions are kept.
+func (outputs *Outputs) Reset() {
+	outputs.mutex.Lock()
+	defer outputs.mutex.Unlock()
+	outputs.messages = make(map[reflect.Type][]interface{})
+}
+
+// begin returns the emitter of an execution, nil if there are no outputs.
+func (outputs *Outputs) begin() *outputEmission {
+	if outputs == nil {
+
+		return nil
+	}
+
+	return &outputEmission{outputs: outputs}
+}
+
+// checkSinks returns an error if a rule of the knowledge base routes to a sink that is not registered,
+// so a misspelled sink fails the execution before any rule fires.
+func (emission *outputEmission) checkSinks(knowledge *ast.KnowledgeBase) error {
+	if emission == nil {
+
+		return nil
+	}
+	emission.outputs.mutex.RLock()
+	defer emission.outputs.mutex.RUnlock()
+	for _, entry := range knowledge.RuleEntries {
+		for _, name := range entry.Sinks {
+			if _, ok := emission.outputs.sinks[name]; !ok {
+
+				return fmt.Errorf("rule %s routes to sink %s which is not registered", entry.RuleName, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// outputEmission holds the messages of one execution until it succeeds.
+type outputEmission struct {
+	outputs *Outputs
+	pending []pendingMessage
+}
+
+// pendingMessage is an emitted message with the sinks of the rule that emitted it.
+type pendingMessage struct {
+	message interface{}
+	sinks   []string
+}
+
+// Emit implements ast.Emitter
+func (emission *outputEmission) Emit(message interface{}) {
+	emission.pending = append(emission.pending, pendingMessage{message: message})
+}
+
+// EmitToSinks implements ast.SinkEmitter
+func (emission *outputEmission) EmitToSinks(sinks []string, message interface{}) {
+	emission.pending = append(emission.pending, pendingMessage{message: message, sinks: sinks})
+}
+
+// emitter returns the emitter given to the built-in functions, a nil interface if there are no outputs.
+func (emission *outputEmission) emitter() ast.Emitter {
+	if emission == nil {
+
+		return nil
+	}
+
+	return emission
+}
+
+// publish adds the pending messages to the outputs, then sends them to the subscribed channels and to the sinks
+// of the rules that emitted them. It returns the errors of the sinks.
+func (emission *outputEmission) publish() error {
+	if emission == nil || len(emission.pending) == 0 {
+
+		return nil
+	}
+	type delivery struct {
+		channel reflect.Value
+		sink    Sink
+		name    string
+		message interface{}
+	}
+	deliveries := make([]delivery, 0)
+	emission.outputs.mutex.Lock()
+	for _, pending := range emission.pending {
+		messageType := reflect.TypeOf(pending.message)
+		emission.outputs.messages[messageType] = append(emission.outputs.messages[messageType], pending.message)
+		for _, channel := range emission.outputs.channels[messageType] {
+			deliveries = append(deliveries, delivery{channel: channel, message: pending.message})
+		}
+		for _, name := range pending.sinks {
+			deliveries = append(deliveries, delivery{sink: emission.outputs.sinks[name], name: name, message: pending.message})
+		}
+	}
+	emission.outputs.mutex.Unlock()
+	// channels and sinks are sent outside of the lock, a receiver may read the outputs.
+	errs := make([]error, 0)
+	for _, d := range deliveries {
+		switch {
+		case d.sink != nil:
+			if err := d.sink(d.message); err != nil {
+				errs = append(errs, fmt.Errorf("error while sending to sink %s. got %w", d.name, err))
+			}
+		case len(d.name) > 0:
+			errs = append(errs, fmt.Errorf("sink %s is not registered", d.name))
+		default:
+			d.channel.Send(reflect.ValueOf(d.message))
+		}
+	}
+	emission.pending = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,178 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type OfferProposed struct {
+	CustomerID string
+	Percent    int64
+}
+
+type OfferCustomer struct {
+	ID      string
+	Spent   int64
+	Offered bool
+}
+
+func (c *OfferCustomer) Propose(percent int64) OfferProposed {
+
+	return OfferProposed{CustomerID: c.ID, Percent: percent}
+}
+
+const outputRules = `
+rule ProposeOffer "propose an offer to big spenders" {
+	when
+		Customer.Spent > 1000 && !Customer.Offered
+	then
+		Emit(Customer.Propose(10));
+		Emit("offer for " + Customer.ID);
+		Customer.Offered = true;
+}
+rule Broken "fails after emitting" {
+	when
+		Customer.Spent < 0
+	then
+		Emit(Customer.Propose(0));
+		Customer.Missing = true;
+}`
+
+func TestGruleEngine_Outputs(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Outputs", "1.0.0", pkg.NewBytesResource([]byte(outputRules))))
+
+	outputs := NewOutputs()
+	proposals := make(chan OfferProposed, 10)
+	Subscribe(outputs, proposals)
+	ctx := WithOutputs(context.Background(), outputs)
+	execute := func(customer *OfferCustomer) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Customer", customer))
+		kb, err := lib.NewKnowledgeBaseInstance("Outputs", "1.0.0")
+		assert.NoError(t, err)
+
+		return NewGruleEngine().ExecuteWithContext(ctx, dctx, kb)
+	}
+
+	assert.NoError(t, execute(&OfferCustomer{ID: "c1", Spent: 5000}))
+	assert.NoError(t, execute(&OfferCustomer{ID: "c2", Spent: 10}))
+	assert.NoError(t, execute(&OfferCustomer{ID: "c3", Spent: 2000}))
+	assert.Error(t, execute(&OfferCustomer{ID: "c4", Spent: -1}))
+
+	assert.Equal(t, []OfferProposed{{CustomerID: "c1", Percent: 10}, {CustomerID: "c3", Percent: 10}}, Emitted[OfferProposed](outputs))
+	assert.Equal(t, []string{"offer for c1", "offer for c3"}, Emitted[string](outputs))
+	assert.Empty(t, Emitted[*OfferProposed](outputs))
+	assert.Len(t, proposals, 2)
+	assert.Equal(t, "c1", (<-proposals).CustomerID)
+
+	outputs.Reset()
+	assert.Empty(t, Emitted[OfferProposed](outputs))
+	assert.Nil(t, OutputsFrom(context.Background()))
+
+	// without outputs, emitted messages are ignored.
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Customer", &OfferCustomer{ID: "c5", Spent: 5000}))
+	kb, err := lib.NewKnowledgeBaseInstance("Outputs", "1.0.0")
+	assert.NoError(t, err)
+	assert.NoError(t, NewGruleEngine().Execute(dctx, kb))
+}
+
+const sinkRules = `
+@sink("fraud-queue")
+rule Fraud "route suspicious spending to the fraud queue" salience 10 {
+	when
+		Customer.Spent > 9000 && !Customer.Offered
+	then
+		Emit(Customer.Propose(0));
+		Customer.Offered = true;
+}
+@sink("offers", "audit")
+rule ProposeOffer "propose an offer to big spenders" {
+	when
+		Customer.Spent > 1000 && !Customer.Offered
+	then
+		Emit(Customer.Propose(10));
+		Customer.Offered = true;
+}`
+
+func TestGruleEngine_OutputsSinks(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Sinks", "1.0.0", pkg.NewBytesResource([]byte(sinkRules))))
+	assert.Equal(t, []string{"offers", "audit"}, lib.GetKnowledgeBase("Sinks", "1.0.0").RuleEntries["ProposeOffer"].Sinks)
+
+	outputs := NewOutputs()
+	fraud := make(chan OfferProposed, 10)
+	offers := make(chan OfferProposed, 10)
+	audit := make([]interface{}, 0)
+	outputs.RegisterSink("fraud-queue", SinkChannel(fraud))
+	outputs.RegisterSink("offers", SinkChannel(offers))
+	ctx := WithOutputs(context.Background(), outputs)
+	execute := func(customer *OfferCustomer) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Customer", customer))
+		kb, err := lib.NewKnowledgeBaseInstance("Sinks", "1.0.0")
+		assert.NoError(t, err)
+
+		return NewGruleEngine().ExecuteWithContext(ctx, dctx, kb)
+	}
+
+	// the audit sink is missing, no rule fires.
+	err := execute(&OfferCustomer{ID: "c1", Spent: 5000})
+	assert.ErrorContains(t, err, "rule ProposeOffer routes to sink audit which is not registered")
+	assert.Empty(t, Emitted[OfferProposed](outputs))
+
+	outputs.RegisterSink("audit", func(message interface{}) error {
+		audit = append(audit, message)
+
+		return nil
+	})
+	assert.NoError(t, execute(&OfferCustomer{ID: "c1", Spent: 5000}))
+	assert.NoError(t, execute(&OfferCustomer{ID: "c2", Spent: 10000}))
+	assert.Equal(t, OfferProposed{CustomerID: "c1", Percent: 10}, <-offers)
+	assert.Equal(t, OfferProposed{CustomerID: "c2", Percent: 0}, <-fraud)
+	assert.Empty(t, offers)
+	assert.Empty(t, fraud)
+	assert.Equal(t, []interface{}{OfferProposed{CustomerID: "c1", Percent: 10}}, audit)
+	// routed messages are collected by their type as well.
+	assert.Len(t, Emitted[OfferProposed](outputs), 2)
+
+	outputs.RegisterSink("audit", func(message interface{}) error {
+
+		return fmt.Errorf("audit is down")
+	})
+	err = execute(&OfferCustomer{ID: "c3", Spent: 2000})
+	assert.ErrorContains(t, err, "error while sending to sink audit. got audit is down")
+	// the other sinks still receive the message.
+	assert.Equal(t, "c3", (<-offers).CustomerID)
+
+	outputs.RegisterSink("offers", SinkChannel(make(chan string, 1)))
+	outputs.RegisterSink("audit", func(message interface{}) error {
+
+		return nil
+	})
+	err = execute(&OfferCustomer{ID: "c4", Spent: 2000})
+	assert.ErrorContains(t, err, "sink channel of string can not receive engine.OfferProposed")
+}
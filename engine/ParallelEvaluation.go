@@ -0,0 +1,81 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// evaluation is the outcome of evaluating the when scope of a rule entry.
+type evaluation struct {
+	can bool
+	err error
+}
+
+// parallelEvaluator evaluates the when scopes of a cycle concurrently. The AST keeps the evaluated values in its
+// nodes, so each worker evaluates on its own clone of the knowledge base, against a snapshot of the data context
+// taken at the start of the cycle. Then scopes are still executed one by one on the knowledge base itself.
+type parallelEvaluator struct {
+	workers []*ast.KnowledgeBase
+}
+
+// newParallelEvaluator clones the knowledge base once for each worker.
+func newParallelEvaluator(knowledge *ast.KnowledgeBase, dataCtx ast.IDataContext, workers int) (*parallelEvaluator, error) {
+	evaluator := &parallelEvaluator{workers: make([]*ast.KnowledgeBase, workers)}
+	for i := range evaluator.workers {
+		clone, err := knowledge.Clone(pkg.NewCloneTable())
+		if err != nil {
+
+			return nil, err
+		}
+		clone.Reset()
+		clone.InitializeContext(dataCtx)
+		evaluator.workers[i] = clone
+	}
+
+	return evaluator, nil
+}
+
+// evaluate evaluates the when scopes of the rule entries, identified by their key in the knowledge base.
+func (p *parallelEvaluator) evaluate(ctx context.Context, dataCtx ast.IDataContext, keys []string) map[string]evaluation {
+	snapshot := ast.NewDataContextSnapshot(dataCtx)
+	results := make([]evaluation, len(keys))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for _, worker := range p.workers {
+		wg.Add(1)
+		go func(worker *ast.KnowledgeBase) {
+			defer wg.Done()
+			// the facts may have changed since the previous cycle, nothing evaluated then is kept.
+			worker.WorkingMemory.ResetAll()
+			for i := int(next.Add(1) - 1); i < len(keys); i = int(next.Add(1) - 1) {
+				results[i].can, results[i].err = worker.RuleEntries[keys[i]].Evaluate(ctx, snapshot, worker.WorkingMemory)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	evaluations := make(map[string]evaluation, len(keys))
+	for i, key := range keys {
+		evaluations[key] = results[i]
+	}
+
+	return evaluations
+}
@@ -0,0 +1,110 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ParallelOrder struct {
+	Items    []int64
+	Total    int64
+	Discount int64
+	Tier     string
+	Labels   map[string]string
+}
+
+const parallelRules = `
+rule Total "Sum the items" salience 10 {
+	when
+		Order.Total == 0 && Order.Items.Len() > 0
+	then
+		Order.Total = Order.Items[0] + Order.Items[1] + Order.Items[2];
+}
+rule Gold "Big orders are gold" {
+	when
+		Order.Tier == "" && Order.Total >= 100
+	then
+		Order.Tier = "gold";
+}
+rule Silver "Other orders are silver" {
+	when
+		Order.Tier == "" && Order.Total > 0 && Order.Total < 100
+	then
+		Order.Tier = "silver";
+}
+rule GoldDiscount "Gold orders get a discount" {
+	when
+		Order.Tier == "gold" && Order.Discount == 0 && Order.Labels["channel"] == "web"
+	then
+		Order.Discount = Order.Total / 10;
+		Retract("GoldDiscount");
+}`
+
+func TestGruleEngine_ParallelEvaluation(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Parallel", "0.0.1", pkg.NewBytesResource([]byte(parallelRules))))
+
+	execute := func(workers int, items ...int64) *ParallelOrder {
+		order := &ParallelOrder{Items: items, Labels: map[string]string{"channel": "web"}}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Order", order))
+		kb, err := lib.NewKnowledgeBaseInstance("Parallel", "0.0.1")
+		assert.NoError(t, err)
+		engine := NewGruleEngine()
+		engine.ParallelEvaluation = workers
+		engine.ReturnErrOnFailedRuleEvaluation = true
+		assert.NoError(t, engine.Execute(dctx, kb))
+
+		return order
+	}
+
+	for _, items := range [][]int64{{50, 40, 30}, {10, 20, 30}} {
+		sequential := execute(0, items...)
+		parallel := execute(4, items...)
+		assert.Equal(t, sequential, parallel)
+	}
+	assert.Equal(t, &ParallelOrder{Items: []int64{50, 40, 30}, Total: 120, Discount: 12, Tier: "gold", Labels: map[string]string{"channel": "web"}}, execute(3, 50, 40, 30))
+}
+
+func TestDataContextSnapshot_ReadOnly(t *testing.T) {
+	order := &ParallelOrder{Total: 10, Labels: map[string]string{"channel": "web"}}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Order", order))
+	snapshot := ast.NewDataContextSnapshot(dctx)
+
+	node := snapshot.Get("Order")
+	total, err := node.GetObjectValueByField("Total")
+	assert.NoError(t, err)
+	order.Total = 20
+	// the snapshot keeps the value of its first read.
+	again, err := node.GetObjectValueByField("Total")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), total.Int())
+	assert.Equal(t, int64(10), again.Int())
+
+	assert.ErrorIs(t, node.SetObjectValueByField("Total", total), ast.ErrReadOnlySnapshot)
+	assert.ErrorIs(t, snapshot.Add("Other", order), ast.ErrReadOnlySnapshot)
+	assert.Panics(t, func() {
+		snapshot.Retract("Order")
+	})
+	assert.Nil(t, snapshot.Get("Missing"))
+}
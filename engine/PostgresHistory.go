@@ -0,0 +1,249 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultExecutionTable is the table of a PostgresExecutionStore whose Table is empty.
+const DefaultExecutionTable = "grule_executions"
+
+// tableName accepts a table name, optionally qualified by its schema, that needs no quoting.
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewPostgresExecutionStore creates new PostgresExecutionStore keeping the records in the default table.
+func NewPostgresExecutionStore(db *sql.DB) *PostgresExecutionStore {
+
+	return &PostgresExecutionStore{DB: db}
+}
+
+// PostgresExecutionStore keeps the records in a PostgreSQL table, one row per execution. The fact ids and the rules
+// fired are JSONB arrays with GIN indexes, so the queries by fact id or by rule do not scan the table.
+type PostgresExecutionStore struct {
+	// DB is the connection pool, opened with the PostgreSQL driver of the application such as
+	// github.com/jackc/pgx/v5/stdlib or github.com/lib/pq.
+	DB *sql.DB
+	// Table is the name of the table, optionally qualified by its schema. DefaultExecutionTable if empty.
+	Table string
+}
+
+func (store *PostgresExecutionStore) table() (string, error) {
+	if len(store.Table) == 0 {
+
+		return DefaultExecutionTable, nil
+	}
+	if !tableName.MatchString(store.Table) {
+
+		return "", fmt.Errorf("execution table name %q is not a valid PostgreSQL identifier", store.Table)
+	}
+
+	return store.Table, nil
+}
+
+// CreateTable creates the table and its indexes if they do not exist yet.
+func (store *PostgresExecutionStore) CreateTable(ctx context.Context) error {
+	table, err := store.table()
+	if err != nil {
+
+		return err
+	}
+	index := strings.ReplaceAll(table, ".", "_")
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + table + ` (
+	id TEXT PRIMARY KEY,
+	knowledge_base TEXT NOT NULL,
+	version TEXT NOT NULL,
+	started_at TIMESTAMPTZ NOT NULL,
+	duration_ns BIGINT NOT NULL,
+	fact_ids JSONB NOT NULL DEFAULT '[]',
+	fired JSONB NOT NULL DEFAULT '[]',
+	error TEXT NOT NULL DEFAULT '',
+	trace JSONB
+)`,
+		`CREATE INDEX IF NOT EXISTS ` + index + `_started_at ON ` + table + ` (started_at)`,
+		`CREATE INDEX IF NOT EXISTS ` + index + `_fact_ids ON ` + table + ` USING GIN (fact_ids)`,
+		`CREATE INDEX IF NOT EXISTS ` + index + `_fired ON ` + table + ` USING GIN (fired)`,
+	}
+	for _, statement := range statements {
+		_, err := store.DB.ExecContext(ctx, statement)
+		if err != nil {
+
+			return fmt.Errorf("error while creating the execution table %s. got %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveExecution implements ExecutionStore.
+func (store *PostgresExecutionStore) SaveExecution(ctx context.Context, record *ExecutionRecord) error {
+	table, err := store.table()
+	if err != nil {
+
+		return err
+	}
+	factIDs, err := jsonArray(record.FactIDs)
+	if err != nil {
+
+		return err
+	}
+	fired, err := jsonArray(record.Fired)
+	if err != nil {
+
+		return err
+	}
+	var trace interface{}
+	if record.Trace != nil {
+		data, err := json.Marshal(record.Trace)
+		if err != nil {
+
+			return fmt.Errorf("error while encoding the trace of execution %s. got %w", record.ID, err)
+		}
+		trace = string(data)
+	}
+	_, err = store.DB.ExecContext(ctx, `INSERT INTO `+table+
+		` (id, knowledge_base, version, started_at, duration_ns, fact_ids, fired, error, trace)`+
+		` VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb, $8, $9::jsonb)`,
+		record.ID, record.KnowledgeBase, record.Version, record.Start, int64(record.Duration), factIDs, fired, record.Error, trace)
+	if err != nil {
+
+		return fmt.Errorf("error while saving execution %s into %s. got %w", record.ID, table, err)
+	}
+
+	return nil
+}
+
+// QueryExecutions implements ExecutionStore.
+func (store *PostgresExecutionStore) QueryExecutions(ctx context.Context, query ExecutionQuery) ([]*ExecutionRecord, error) {
+	table, err := store.table()
+	if err != nil {
+
+		return nil, err
+	}
+	statement, args, err := selectExecutions(table, query)
+	if err != nil {
+
+		return nil, err
+	}
+	rows, err := store.DB.QueryContext(ctx, statement, args...)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while querying the executions of %s. got %w", table, err)
+	}
+	defer rows.Close()
+	records := make([]*ExecutionRecord, 0)
+	for rows.Next() {
+		record := &ExecutionRecord{}
+		var duration int64
+		var factIDs, fired, trace []byte
+		err := rows.Scan(&record.ID, &record.KnowledgeBase, &record.Version, &record.Start, &duration, &factIDs, &fired, &record.Error, &trace)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the executions of %s. got %w", table, err)
+		}
+		record.Duration = time.Duration(duration)
+		err = json.Unmarshal(factIDs, &record.FactIDs)
+		if err == nil {
+			err = json.Unmarshal(fired, &record.Fired)
+		}
+		if err == nil && len(trace) > 0 {
+			record.Trace = &Trace{}
+			err = json.Unmarshal(trace, record.Trace)
+		}
+		if err != nil {
+
+			return nil, fmt.Errorf("error while decoding execution %s. got %w", record.ID, err)
+		}
+		records = append(records, record)
+	}
+	err = rows.Err()
+	if err != nil {
+
+		return nil, fmt.Errorf("error while reading the executions of %s. got %w", table, err)
+	}
+
+	return records, nil
+}
+
+// selectExecutions returns the statement selecting the executions of the table that match the query, and its
+// arguments.
+func selectExecutions(table string, query ExecutionQuery) (string, []interface{}, error) {
+	conditions := make([]string, 0, 5)
+	args := make([]interface{}, 0, 6)
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if len(query.KnowledgeBase) > 0 {
+		where("knowledge_base = $%d", query.KnowledgeBase)
+	}
+	if len(query.FactID) > 0 {
+		factIDs, err := jsonArray([]string{query.FactID})
+		if err != nil {
+
+			return "", nil, err
+		}
+		where("fact_ids @> $%d::jsonb", factIDs)
+	}
+	if len(query.Rule) > 0 {
+		fired, err := jsonArray([]string{query.Rule})
+		if err != nil {
+
+			return "", nil, err
+		}
+		where("fired @> $%d::jsonb", fired)
+	}
+	if !query.From.IsZero() {
+		where("started_at >= $%d", query.From)
+	}
+	if !query.To.IsZero() {
+		where("started_at < $%d", query.To)
+	}
+	var statement strings.Builder
+	statement.WriteString("SELECT id, knowledge_base, version, started_at, duration_ns, fact_ids, fired, error, trace FROM ")
+	statement.WriteString(table)
+	if len(conditions) > 0 {
+		statement.WriteString(" WHERE ")
+		statement.WriteString(strings.Join(conditions, " AND "))
+	}
+	statement.WriteString(" ORDER BY started_at DESC")
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		fmt.Fprintf(&statement, " LIMIT $%d", len(args))
+	}
+
+	return statement.String(), args, nil
+}
+
+// jsonArray encodes the strings as a JSON array, an empty array if there are none.
+func jsonArray(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+
+		return "", err
+	}
+
+	return string(data), nil
+}
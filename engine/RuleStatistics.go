@@ -0,0 +1,298 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// versionKey identifies a knowledge base version.
+type versionKey struct {
+	knowledgeBase string
+	version       string
+}
+
+// ruleKey identifies a rule of a knowledge base version by its stable id.
+type ruleKey struct {
+	versionKey
+	rule string
+}
+
+// RuleStatistics is the number of times a rule has fired, along with the number of executions of its knowledge
+// base version. Rules are identified by their RuleID, or their name if they have none, so they can be compared
+// across versions even when renamed.
+type RuleStatistics struct {
+	KnowledgeBase string `json:"knowledgeBase"`
+	Version       string `json:"version"`
+	Rule          string `json:"rule"`
+	Fires         uint64 `json:"fires"`
+	Executions    uint64 `json:"executions"`
+}
+
+// FireRate returns the average number of fires per execution.
+func (statistics RuleStatistics) FireRate() float64 {
+	if statistics.Executions == 0 {
+
+		return 0
+	}
+
+	return float64(statistics.Fires) / float64(statistics.Executions)
+}
+
+// StatisticsStore persists the rule statistics of Metrics, so they survive restarts.
+type StatisticsStore interface {
+	LoadStatistics() ([]RuleStatistics, error)
+	SaveStatistics(statistics []RuleStatistics) error
+}
+
+// recordExecution counts an execution of the knowledge base version.
+func (m *Metrics) recordExecution(knowledgeBase, version string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.executions[versionKey{knowledgeBase: knowledgeBase, version: version}]++
+}
+
+// recordFire counts a fire of the rule.
+func (m *Metrics) recordFire(knowledgeBase, version, rule string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fires[ruleKey{versionKey: versionKey{knowledgeBase: knowledgeBase, version: version}, rule: rule}]++
+}
+
+// RuleStatistics returns a snapshot of the statistics of every rule that has fired, sorted by knowledge base,
+// version and rule.
+func (m *Metrics) RuleStatistics() []RuleStatistics {
+	m.mutex.RLock()
+	ret := make([]RuleStatistics, 0, len(m.fires))
+	fired := make(map[versionKey]bool)
+	for key, fires := range m.fires {
+		fired[key.versionKey] = true
+		ret = append(ret, RuleStatistics{
+			KnowledgeBase: key.knowledgeBase,
+			Version:       key.version,
+			Rule:          key.rule,
+			Fires:         fires,
+			Executions:    m.executions[key.versionKey],
+		})
+	}
+	// versions executed without any rule firing are kept, their executions matter to the rates.
+	for key, executions := range m.executions {
+		if !fired[key] {
+			ret = append(ret, RuleStatistics{KnowledgeBase: key.knowledgeBase, Version: key.version, Executions: executions})
+		}
+	}
+	m.mutex.RUnlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.KnowledgeBase != b.KnowledgeBase {
+
+			return a.KnowledgeBase < b.KnowledgeBase
+		}
+		if a.Version != b.Version {
+
+			return a.Version < b.Version
+		}
+
+		return a.Rule < b.Rule
+	})
+
+	return ret
+}
+
+// LoadStatistics adds the statistics persisted in the store to the counters, call it once at start up.
+func (m *Metrics) LoadStatistics(store StatisticsStore) error {
+	statistics, err := store.LoadStatistics()
+	if err != nil {
+
+		return fmt.Errorf("can not load rule statistics. got %w", err)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	loaded := make(map[versionKey]bool)
+	for _, stat := range statistics {
+		version := versionKey{knowledgeBase: stat.KnowledgeBase, version: stat.Version}
+		// every statistics of a version carries the same executions, they are counted once.
+		if !loaded[version] {
+			loaded[version] = true
+			m.executions[version] += stat.Executions
+		}
+		if len(stat.Rule) > 0 {
+			m.fires[ruleKey{versionKey: version, rule: stat.Rule}] += stat.Fires
+		}
+	}
+
+	return nil
+}
+
+// SaveStatistics writes the snapshot of the rule statistics into the store.
+func (m *Metrics) SaveStatistics(store StatisticsStore) error {
+	err := store.SaveStatistics(m.RuleStatistics())
+	if err != nil {
+
+		return fmt.Errorf("can not save rule statistics. got %w", err)
+	}
+
+	return nil
+}
+
+// DriftOptions tells when the fire rate of a rule has drifted between two versions.
+type DriftOptions struct {
+	// Threshold is the change of fire rate, in fires per execution, beyond which a rule has drifted.
+	Threshold float64
+	// MinExecutions is the number of executions each version needs before their rates are compared.
+	MinExecutions uint64
+}
+
+// RuleDrift is a rule whose fire rate has changed beyond the threshold between two versions.
+type RuleDrift struct {
+	Rule             string
+	PreviousFireRate float64
+	FireRate         float64
+	// Added is true if the rule never fired in the previous version, Removed if it never fired in the new one.
+	Added   bool
+	Removed bool
+}
+
+// Change returns the change of fire rate, negative if the rule fires less often.
+func (drift RuleDrift) Change() float64 {
+
+	return drift.FireRate - drift.PreviousFireRate
+}
+
+// DetectDrift compares the fire rate of every rule of the knowledge base between the previous version and the new
+// one, and returns the rules whose rate changed beyond the threshold, sorted by rule. Each drift is logged as a
+// warning, so an unintended change of behavior brought by a rules release gets noticed.
+func (m *Metrics) DetectDrift(knowledgeBase, previousVersion, version string, options DriftOptions) ([]RuleDrift, error) {
+	m.mutex.RLock()
+	previousKey := versionKey{knowledgeBase: knowledgeBase, version: previousVersion}
+	currentKey := versionKey{knowledgeBase: knowledgeBase, version: version}
+	previousExecutions, executions := m.executions[previousKey], m.executions[currentKey]
+	rates := make(map[string][2]float64)
+	for key, fires := range m.fires {
+		if key.versionKey != previousKey && key.versionKey != currentKey {
+
+			continue
+		}
+		rate := rates[key.rule]
+		if key.versionKey == previousKey {
+			rate[0] = float64(fires) / math.Max(float64(previousExecutions), 1)
+		}
+		if key.versionKey == currentKey {
+			rate[1] = float64(fires) / math.Max(float64(executions), 1)
+		}
+		rates[key.rule] = rate
+	}
+	m.mutex.RUnlock()
+
+	if previousExecutions < options.MinExecutions || executions < options.MinExecutions || executions == 0 || previousExecutions == 0 {
+
+		return nil, fmt.Errorf("knowledge base %s has %d executions in version %s and %d in version %s, at least %d needed. got %w",
+			knowledgeBase, previousExecutions, previousVersion, executions, version, options.MinExecutions, ErrNotEnoughExecutions)
+	}
+
+	ret := make([]RuleDrift, 0)
+	for rule, rate := range rates {
+		drift := RuleDrift{
+			Rule:             rule,
+			PreviousFireRate: rate[0],
+			FireRate:         rate[1],
+			Added:            rate[0] == 0,
+			Removed:          rate[1] == 0,
+		}
+		if math.Abs(drift.Change()) > options.Threshold {
+			ret = append(ret, drift)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+
+		return ret[i].Rule < ret[j].Rule
+	})
+	for _, drift := range ret {
+		log.Warnf("Rule %s of knowledge base '%s' drifted from %.4f fires per execution in version %s to %.4f in version %s",
+			drift.Rule, knowledgeBase, drift.PreviousFireRate, previousVersion, drift.FireRate, version)
+	}
+
+	return ret, nil
+}
+
+// ErrNotEnoughExecutions is returned by DetectDrift when a version has not been executed enough to be compared.
+var ErrNotEnoughExecutions = errors.New("not enough executions to detect drift")
+
+// NewFileStatisticsStore create new StatisticsStore keeping the statistics in a JSON file.
+func NewFileStatisticsStore(path string) *FileStatisticsStore {
+
+	return &FileStatisticsStore{
+		Path: path,
+	}
+}
+
+// FileStatisticsStore keeps the rule statistics in a JSON file. The file is replaced as a whole when saved,
+// so a crash while saving leaves the previous statistics.
+type FileStatisticsStore struct {
+	Path string
+}
+
+// LoadStatistics reads the statistics file, no statistics if the file does not exist yet.
+func (store *FileStatisticsStore) LoadStatistics() ([]RuleStatistics, error) {
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+
+		return nil, nil
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	statistics := make([]RuleStatistics, 0)
+	err = json.Unmarshal(data, &statistics)
+	if err != nil {
+
+		return nil, fmt.Errorf("invalid statistics file %s. got %w", store.Path, err)
+	}
+
+	return statistics, nil
+}
+
+// SaveStatistics writes the statistics into a temporary file, then renames it over the statistics file.
+func (store *FileStatisticsStore) SaveStatistics(statistics []RuleStatistics) error {
+	data, err := json.MarshalIndent(statistics, "", "  ")
+	if err != nil {
+
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(store.Path), filepath.Base(store.Path)+".*")
+	if err != nil {
+
+		return err
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+
+		return err
+	}
+
+	return os.Rename(temp.Name(), store.Path)
+}
@@ -0,0 +1,77 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_DetectDrift(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Loan", "1.0.0", pkg.NewBytesResource([]byte(outcomeRules))))
+	// the release lowers the approval score by mistake.
+	lowered := strings.ReplaceAll(outcomeRules, "700", "500")
+	assert.NoError(t, rb.BuildRuleFromResource("Loan", "2.0.0", pkg.NewBytesResource([]byte(lowered))))
+
+	engine := NewGruleEngine()
+	execute := func(version string, score int64) {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Loan", &LoanApplication{Score: score}))
+		kb, err := lib.NewKnowledgeBaseInstance("Loan", version)
+		assert.NoError(t, err)
+		assert.NoError(t, engine.Execute(dctx, kb))
+	}
+	scores := []int64{800, 750, 600, 550}
+	for _, score := range scores {
+		execute("1.0.0", score)
+	}
+
+	// the statistics survive a restart through the store.
+	store := NewFileStatisticsStore(filepath.Join(t.TempDir(), "statistics.json"))
+	assert.NoError(t, engine.Metrics.LoadStatistics(store))
+	assert.NoError(t, engine.Metrics.SaveStatistics(store))
+	engine = NewGruleEngine()
+	assert.NoError(t, engine.Metrics.LoadStatistics(store))
+	assert.Equal(t, []RuleStatistics{
+		{KnowledgeBase: "Loan", Version: "1.0.0", Rule: "Approve", Fires: 2, Executions: 4},
+		{KnowledgeBase: "Loan", Version: "1.0.0", Rule: "Decline", Fires: 2, Executions: 4},
+	}, engine.Metrics.RuleStatistics())
+
+	_, err := engine.Metrics.DetectDrift("Loan", "1.0.0", "2.0.0", DriftOptions{Threshold: 0.1, MinExecutions: 4})
+	assert.ErrorIs(t, err, ErrNotEnoughExecutions)
+
+	for _, score := range scores {
+		execute("2.0.0", score)
+	}
+	drifts, err := engine.Metrics.DetectDrift("Loan", "1.0.0", "2.0.0", DriftOptions{Threshold: 0.1, MinExecutions: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []RuleDrift{
+		{Rule: "Approve", PreviousFireRate: 0.5, FireRate: 1},
+		{Rule: "Decline", PreviousFireRate: 0.5, FireRate: 0, Removed: true},
+	}, drifts)
+	assert.Equal(t, -0.5, drifts[1].Change())
+
+	drifts, err = engine.Metrics.DetectDrift("Loan", "1.0.0", "1.0.0", DriftOptions{Threshold: 0.1})
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+}
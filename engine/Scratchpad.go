@@ -0,0 +1,47 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// prepareScratchpad adds an empty scratchpad into the data context and returns its value node, nil if the engine has
+// no scratchpad. The scratchpad of a previous execution is replaced, a fact the caller added under the same name is
+// kept and shadows the scratchpad.
+func (g *GruleEngine) prepareScratchpad(dataCtx ast.IDataContext) (model.ValueNode, error) {
+	if len(g.Scratchpad) == 0 {
+
+		return nil, nil
+	}
+	if existing := dataCtx.Get(g.Scratchpad); existing != nil {
+		var previous *ast.BuiltInFunctions
+		if defunc := dataCtx.Get("DEFUNC"); defunc != nil {
+			previous, _ = defunc.Value().Interface().(*ast.BuiltInFunctions)
+		}
+		if previous == nil || previous.Scratchpad != existing {
+			g.log().Warnf("Fact %s shadows the scratchpad of the engine, rename the fact or change the Scratchpad of the engine", g.Scratchpad)
+
+			return nil, nil
+		}
+	}
+	if err := dataCtx.AddJSON(g.Scratchpad, []byte("{}")); err != nil {
+
+		return nil, err
+	}
+
+	return dataCtx.Get(g.Scratchpad), nil
+}
@@ -0,0 +1,97 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ScratchpadOrder struct {
+	Price    float64
+	Quantity int64
+	Total    float64
+}
+
+const scratchpadRules = `
+rule Subtotal "Computes the subtotal" salience 20 {
+	when
+		Order.Total == 0
+	then
+		tmp.Subtotal = Order.Price * Order.Quantity;
+		Retract("Subtotal");
+}
+rule Discount "Discounts large subtotals" salience 10 {
+	when
+		Order.Total == 0 && tmp.Subtotal > 100
+	then
+		tmp.Subtotal = tmp.Subtotal - 10;
+		Retract("Discount");
+}
+rule Total "Copies the subtotal into the order" {
+	when
+		Order.Total == 0
+	then
+		Order.Total = tmp.Subtotal;
+		Retract("Total");
+}`
+
+func TestScratchpad(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Scratchpad", "0.0.1", pkg.NewBytesResource([]byte(scratchpadRules))))
+	eng := NewGruleEngine()
+
+	order := &ScratchpadOrder{Price: 30, Quantity: 5}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Order", order))
+	kb, err := lib.NewKnowledgeBaseInstance("Scratchpad", "0.0.1")
+	assert.NoError(t, err)
+	assert.NoError(t, eng.Execute(dctx, kb))
+	assert.Equal(t, float64(140), order.Total)
+
+	// the scratchpad is emptied on the next execution of the same data context.
+	subtotal, err := dctx.Get("tmp").GetObjectValueByField("Subtotal")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(140), subtotal.Interface())
+	order.Total, order.Quantity = 0, 1
+	kb, err = lib.NewKnowledgeBaseInstance("Scratchpad", "0.0.1")
+	assert.NoError(t, err)
+	assert.NoError(t, eng.Execute(dctx, kb))
+	assert.Equal(t, float64(30), order.Total)
+
+	// a fact of the caller under the same name shadows the scratchpad.
+	own := ast.NewDataContext()
+	assert.NoError(t, own.Add("Order", &ScratchpadOrder{Price: 30, Quantity: 5}))
+	assert.NoError(t, own.AddJSON("tmp", []byte(`{"Subtotal": 1, "Kept": true}`)))
+	kb, err = lib.NewKnowledgeBaseInstance("Scratchpad", "0.0.1")
+	assert.NoError(t, err)
+	assert.NoError(t, eng.Execute(own, kb))
+	kept, err := own.Get("tmp").GetObjectValueByField("Kept")
+	assert.NoError(t, err)
+	assert.Equal(t, true, kept.Interface())
+
+	// without a scratchpad tmp is an unknown fact.
+	eng.Scratchpad = ""
+	eng.ReturnErrOnFailedRuleEvaluation = true
+	none := ast.NewDataContext()
+	assert.NoError(t, none.Add("Order", &ScratchpadOrder{Price: 30, Quantity: 5}))
+	kb, err = lib.NewKnowledgeBaseInstance("Scratchpad", "0.0.1")
+	assert.NoError(t, err)
+	assert.Error(t, eng.Execute(none, kb))
+}
@@ -0,0 +1,98 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+type securityContextKey struct{}
+
+// SecurityPredicate is a host condition ANDed onto the when scope of the designated rules. It is evaluated before the
+// rule condition, a rule whose predicate is false is not a candidate and its own condition is not even evaluated.
+type SecurityPredicate struct {
+	// Name identifies the predicate in errors, e.g. "tenant".
+	Name string
+	// Rules designates the rules by their name or RuleID, the predicate applies to every rule if empty.
+	Rules []string
+	// Condition decides whether the rule may fire against the data context.
+	Condition func(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error)
+}
+
+// SecurityContext carries the predicates the host enforces during an execution, regardless of how the rules are
+// authored. This is how multi tenant data is scoped, e.g. by a predicate that checks the tenant of the facts.
+type SecurityContext struct {
+	Predicates []SecurityPredicate
+}
+
+// WithSecurityContext attaches the security context to the context given to ExecuteWithContext.
+func WithSecurityContext(ctx context.Context, securityContext *SecurityContext) context.Context {
+
+	return context.WithValue(ctx, securityContextKey{}, securityContext)
+}
+
+// SecurityContextFrom returns the security context attached to the context, nil if there is none.
+func SecurityContextFrom(ctx context.Context) *SecurityContext {
+	securityContext, _ := ctx.Value(securityContextKey{}).(*SecurityContext)
+
+	return securityContext
+}
+
+// allows evaluates every predicate designating the rule. A failing predicate is an error, the rule is then not allowed.
+func (sc *SecurityContext) allows(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error) {
+	if sc == nil {
+
+		return true, nil
+	}
+	for _, predicate := range sc.Predicates {
+		if !predicate.designates(rule) {
+
+			continue
+		}
+		if predicate.Condition == nil {
+
+			return false, fmt.Errorf("security predicate %s has no condition", predicate.Name)
+		}
+		allowed, err := predicate.Condition(dataCtx, rule)
+		if err != nil {
+
+			return false, fmt.Errorf("security predicate %s failed on rule %s. got %w", predicate.Name, rule.RuleName, err)
+		}
+		if !allowed {
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (predicate SecurityPredicate) designates(rule *ast.RuleEntry) bool {
+	if len(predicate.Rules) == 0 {
+
+		return true
+	}
+	for _, name := range predicate.Rules {
+		if name == rule.RuleName || (len(rule.RuleID) > 0 && name == rule.RuleID) {
+
+			return true
+		}
+	}
+
+	return false
+}
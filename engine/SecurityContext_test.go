@@ -0,0 +1,133 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type TenantRecord struct {
+	TenantID string
+	Secret   string
+	Copied   string
+	Counted  bool
+}
+
+const securityRules = `
+rule CopySecret "forgot to check the tenant" id "R-COPY" {
+	when
+		Record.Copied == ""
+	then
+		Record.Copied = Record.Secret;
+}
+rule Count "not tenant specific" {
+	when
+		!Record.Counted
+	then
+		Record.Counted = true;
+}`
+
+func TestGruleEngine_SecurityContext(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Security", "1.0.0", pkg.NewBytesResource([]byte(securityRules))))
+
+	execute := func(ctx context.Context, record *TenantRecord) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Record", record))
+		kb, err := lib.NewKnowledgeBaseInstance("Security", "1.0.0")
+		assert.NoError(t, err)
+
+		return NewGruleEngine().ExecuteWithContext(ctx, dctx, kb)
+	}
+	scoped := func(tenant string, record *TenantRecord) context.Context {
+
+		return WithSecurityContext(context.Background(), &SecurityContext{
+			Predicates: []SecurityPredicate{{
+				Name:  "tenant",
+				Rules: []string{"R-COPY"},
+				Condition: func(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error) {
+
+					return record.TenantID == tenant, nil
+				},
+			}},
+		})
+	}
+
+	own := &TenantRecord{TenantID: "acme", Secret: "s3cret"}
+	assert.NoError(t, execute(scoped("acme", own), own))
+	assert.Equal(t, "s3cret", own.Copied)
+	assert.True(t, own.Counted)
+
+	other := &TenantRecord{TenantID: "globex", Secret: "s3cret"}
+	assert.NoError(t, execute(scoped("acme", other), other))
+	assert.Empty(t, other.Copied)
+	assert.True(t, other.Counted, "rules not designated by the predicate still fire")
+
+	failing := &TenantRecord{TenantID: "acme"}
+	ctx := WithSecurityContext(context.Background(), &SecurityContext{
+		Predicates: []SecurityPredicate{{
+			Name: "broken",
+			Condition: func(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error) {
+
+				return false, errors.New("no session")
+			},
+		}},
+	})
+	err := execute(ctx, failing)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "security predicate broken failed")
+	assert.False(t, failing.Counted)
+
+	assert.Nil(t, SecurityContextFrom(context.Background()))
+}
+
+func TestGruleEngine_FetchMatchingRulesWithContext(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Security", "1.0.0", pkg.NewBytesResource([]byte(securityRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("Security", "1.0.0")
+	assert.NoError(t, err)
+
+	record := &TenantRecord{TenantID: "globex", Secret: "s3cret"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Record", record))
+	ctx := WithSecurityContext(context.Background(), &SecurityContext{
+		Predicates: []SecurityPredicate{{
+			Name:  "tenant",
+			Rules: []string{"R-COPY"},
+			Condition: func(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error) {
+
+				return record.TenantID == "acme", nil
+			},
+		}},
+	})
+
+	eng := NewGruleEngine()
+	matching, err := eng.FetchMatchingRules(dctx, kb)
+	assert.NoError(t, err)
+	assert.Len(t, matching, 2)
+	matching, err = eng.FetchMatchingRulesWithContext(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.Len(t, matching, 1)
+	assert.Equal(t, "Count", matching[0].RuleName)
+}
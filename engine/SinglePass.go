@@ -0,0 +1,65 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// executeSinglePass fires the runnable rule entries, selected once at the start of the execution, in the order of
+// their salience and then of their names. A rule entry retracted by a rule fired before it is skipped. Every rule
+// fired counts as a cycle, it returns the number of cycles. The rule entries of otherwise execute their else scope.
+func (g *GruleEngine) executeSinglePass(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase, approvals *approvalGate, runnable []*ast.RuleEntry, otherwise map[*ast.RuleEntry]bool) (uint64, error) {
+	sort.SliceStable(runnable, func(i, j int) bool {
+		if runnable[i].Salience != runnable[j].Salience {
+
+			return runnable[i].Salience > runnable[j].Salience
+		}
+
+		return runnable[i].RuleName < runnable[j].RuleName
+	})
+	var cycle uint64
+	for _, runner := range runnable {
+		if ctx.Err() != nil {
+			g.log().Error("Context canceled")
+
+			return cycle, ctx.Err()
+		}
+		if runner.Retracted || runner.Deleted {
+
+			continue
+		}
+		cycle++
+		g.log().Debugf("Cycle #%d", cycle)
+		done, err := g.fire(ctx, dataCtx, knowledge, cycle, runner, otherwise[runner])
+		if err != nil {
+
+			return cycle, err
+		}
+		err = approvals.fired(runner)
+		if err != nil {
+
+			return cycle, err
+		}
+		if done {
+			break
+		}
+	}
+
+	return cycle, nil
+}
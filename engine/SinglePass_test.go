@@ -0,0 +1,141 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type SinglePassFact struct {
+	Score  int
+	Hits   int
+	Class  string
+	Trail  string
+	Review bool
+}
+
+const singlePassRules = `
+rule Count "keeps matching after it fired" {
+	when
+		Fact.Score > 10
+	then
+		Fact.Hits = Fact.Hits + 1;
+		Fact.Trail = Fact.Trail + "C";
+}
+rule High "high score" salience 10 {
+	when
+		Fact.Score > 50
+	then
+		Fact.Class = "high";
+		Fact.Trail = Fact.Trail + "H";
+}
+rule Review "only matches once classified" salience 5 {
+	when
+		Fact.Class == "high"
+	then
+		Fact.Review = true;
+}`
+
+func TestGruleEngine_SinglePass(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("SinglePass", "1.0.0", pkg.NewBytesResource([]byte(singlePassRules))))
+
+	execute := func(eng *GruleEngine, fact *SinglePassFact) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		kb, err := lib.NewKnowledgeBaseInstance("SinglePass", "1.0.0")
+		assert.NoError(t, err)
+
+		return eng.Execute(dctx, kb)
+	}
+
+	// chaining, Count keeps firing until the maximum cycle.
+	eng := NewGruleEngine()
+	eng.MaxCycle = 20
+	assert.Error(t, execute(eng, &SinglePassFact{Score: 70}))
+
+	eng.SinglePass = true
+	fact := &SinglePassFact{Score: 70}
+	assert.NoError(t, execute(eng, fact))
+	assert.Equal(t, 1, fact.Hits)
+	assert.Equal(t, "high", fact.Class)
+	assert.Equal(t, "HC", fact.Trail)
+	assert.False(t, fact.Review, "Review did not match the facts at the start")
+
+	fact = &SinglePassFact{Score: 70, Class: "high"}
+	assert.NoError(t, execute(eng, fact))
+	assert.True(t, fact.Review)
+
+	fact = &SinglePassFact{Score: 5}
+	assert.NoError(t, execute(eng, fact))
+	assert.Equal(t, 0, fact.Hits)
+	assert.Empty(t, fact.Trail)
+}
+
+func TestGruleEngine_SinglePassSkipsInvalidation(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("SinglePass", "1.0.0", pkg.NewBytesResource([]byte(singlePassRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("SinglePass", "1.0.0")
+	assert.NoError(t, err)
+
+	execute := func(eng *GruleEngine, fact *SinglePassFact) {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		assert.NoError(t, eng.Execute(dctx, kb))
+	}
+
+	// chaining, the evaluations are kept and the assignments invalidate them.
+	eng := NewGruleEngine()
+	execute(eng, &SinglePassFact{Score: 5})
+	assert.False(t, kb.WorkingMemory.SinglePass)
+	assert.True(t, kb.WorkingMemory.Reset("Fact.Score"))
+
+	// single pass, there is nothing to invalidate, the then scopes still read the facts as they are.
+	eng.SinglePass = true
+	fact := &SinglePassFact{Score: 70, Trail: "S"}
+	execute(eng, fact)
+	assert.True(t, kb.WorkingMemory.SinglePass)
+	assert.False(t, kb.WorkingMemory.Reset("Fact.Score"))
+	assert.Equal(t, "SHC", fact.Trail)
+	assert.Equal(t, 1, fact.Hits)
+}
+
+func TestGruleEngine_SinglePassHalts(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("SinglePass", "1.0.0", pkg.NewBytesResource([]byte(singlePassRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("SinglePass", "1.0.0")
+	assert.NoError(t, err)
+
+	eng := NewGruleEngine()
+	eng.SinglePass = true
+	fact := &SinglePassFact{Score: 70}
+	eng.StopWhen = func(dataCtx ast.IDataContext) bool {
+
+		return len(fact.Class) > 0
+	}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", fact))
+	assert.NoError(t, eng.Execute(dctx, kb))
+	assert.Equal(t, "H", fact.Trail)
+	assert.Equal(t, 0, fact.Hits)
+}
@@ -0,0 +1,182 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// EvaluateCondition evaluates the when scope of the only rule of the knowledge base, without executing its then scope.
+// This is the fast path for knowledge bases compiled from a single user defined condition : there is no agenda,
+// no cycle, no listener notified and the built-in functions are only added into the data context once.
+// The rule is selected as ExecuteWithContext selects it : it does not match if none of its profiles is active, if the
+// engine is degraded and its criticality is low, if the SecurityContext of the context forbids it or if it waits
+// for an approval.
+func (g *GruleEngine) EvaluateCondition(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) (bool, error) {
+	ruleEntry, can, _, err := g.evaluateSingleRule(ctx, dataCtx, knowledge)
+
+	return can && ruleEntry != nil, err
+}
+
+// ExecuteSingleRule evaluates the only rule of the knowledge base and executes its then scope once if the condition
+// is true, or its else scope if it is false, it returns whether the rule has fired. The rule is selected as by
+// EvaluateCondition. Unlike ExecuteWithContext the rule is never evaluated again after it has fired, max-fires and
+// cooldown are not applied.
+func (g *GruleEngine) ExecuteSingleRule(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) (bool, error) {
+	ruleEntry, can, approvals, err := g.evaluateSingleRule(ctx, dataCtx, knowledge)
+	if err != nil || ruleEntry == nil || (!can && ruleEntry.ElseScope == nil) {
+
+		return false, err
+	}
+	dataCtx.SetRuleEntry(ruleEntry)
+	if can {
+		err = ruleEntry.Execute(ctx, dataCtx, knowledge.WorkingMemory)
+	} else {
+		err = ruleEntry.ExecuteElse(ctx, dataCtx, knowledge.WorkingMemory)
+	}
+	if err != nil {
+
+		return false, fmt.Errorf("error while executing rule %s. got %w", ruleEntry.RuleName, err)
+	}
+
+	return true, approvals.fired(ruleEntry)
+}
+
+// evaluateSingleRule evaluates the only rule of the knowledge base, it returns it with the approval gate it passed.
+// The rule entry is nil if the rule is not selected, or if it waits for an approval.
+func (g *GruleEngine) evaluateSingleRule(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) (*ast.RuleEntry, bool, *approvalGate, error) {
+	ruleEntry, err := g.prepareSingleRule(dataCtx, knowledge)
+	if err != nil {
+
+		return nil, false, nil, err
+	}
+	selected, err := g.selects(ctx, dataCtx, knowledge, ruleEntry)
+	if err != nil || !selected {
+
+		return nil, false, nil, err
+	}
+	can, err := ruleEntry.Evaluate(ctx, dataCtx, knowledge.WorkingMemory)
+	if err != nil || (!can && ruleEntry.ElseScope == nil) {
+
+		return ruleEntry, false, nil, err
+	}
+	approvals, err := g.approvalGate(ctx, knowledge)
+	if err != nil {
+
+		return nil, false, nil, err
+	}
+	held, err := approvals.holds(ruleEntry)
+	if err != nil || held {
+
+		return nil, false, nil, err
+	}
+
+	return ruleEntry, can, approvals, nil
+}
+
+// selects tells whether the rule entry is selected for evaluation as in ExecuteWithContext : one of its profiles is
+// active, the engine does not shed it and the SecurityContext attached to the context allows it.
+func (g *GruleEngine) selects(ctx context.Context, dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase, ruleEntry *ast.RuleEntry) (bool, error) {
+	if !ruleEntry.InProfiles(g.Profiles) {
+
+		return false, nil
+	}
+	if ruleEntry.Criticality == ast.CriticalityLow && g.shedsLowCriticality(knowledge) {
+
+		return false, nil
+	}
+	allowed, err := SecurityContextFrom(ctx).allows(dataCtx, ruleEntry)
+	if err != nil {
+		g.log().Errorf("Failed testing security predicate for rule : %s. Got error %v", ruleEntry.RuleName, err)
+
+		return false, err
+	}
+
+	return allowed, nil
+}
+
+// prepareSingleRule returns the only rule of the knowledge base after resetting the working memory, so nothing
+// evaluated against a previous data context is reused.
+func (g *GruleEngine) prepareSingleRule(dataCtx ast.IDataContext, knowledge *ast.KnowledgeBase) (*ast.RuleEntry, error) {
+	if knowledge == nil || dataCtx == nil {
+
+		return nil, fmt.Errorf("nil KnowledgeBase or DataContext is not allowed")
+	}
+	var single *ast.RuleEntry
+	count := 0
+	for _, ruleEntry := range knowledge.RuleEntries {
+		if ruleEntry.Deleted {
+
+			continue
+		}
+		single = ruleEntry
+		count++
+	}
+	if count != 1 {
+
+		return nil, fmt.Errorf("knowledge base '%s' version %s must have a single rule, it has %d", knowledge.Name, knowledge.Version, count)
+	}
+	if err := g.prepareKnowledge(knowledge, dataCtx); err != nil {
+
+		return nil, err
+	}
+	single.Retracted = false
+
+	return single, nil
+}
+
+// prepareKnowledge adds the built-in functions and the scratchpad of the engine into the data context, sets the
+// working memory up as an execution of the engine does and resets it, so the rules can be evaluated outside of an
+// execution.
+func (g *GruleEngine) prepareKnowledge(knowledge *ast.KnowledgeBase, dataCtx ast.IDataContext) error {
+	scratchpad, err := g.prepareScratchpad(dataCtx)
+	if err != nil {
+
+		return err
+	}
+	var defunc *ast.BuiltInFunctions
+	if node := dataCtx.Get("DEFUNC"); node != nil {
+		defunc, _ = node.Value().Interface().(*ast.BuiltInFunctions)
+	}
+	// the built-in functions of a past execution hold its outputs, they can not be reused.
+	if defunc == nil || defunc.Knowledge != knowledge || defunc.DataContext != dataCtx || defunc.Outputs != nil {
+		defunc = &ast.BuiltInFunctions{
+			Knowledge:     knowledge,
+			WorkingMemory: knowledge.WorkingMemory,
+			DataContext:   dataCtx,
+		}
+		if err := dataCtx.Add("DEFUNC", defunc); err != nil {
+
+			return err
+		}
+	}
+	defunc.Outcomes = g.outcomeRecorder()
+	defunc.Scratchpad = scratchpad
+	defunc.Templates = g.Templates
+	defunc.DateNames = g.DateNames
+
+	knowledge.WorkingMemory.StringNumberComparison = g.StringNumberComparison
+	knowledge.WorkingMemory.NilSafeChaining = g.NilSafeChaining
+	knowledge.WorkingMemory.Tolerance = g.Tolerance
+	knowledge.WorkingMemory.SinglePass = g.SinglePass
+	knowledge.WorkingMemory.Logger = g.Logger
+	knowledge.WorkingMemory.ResetAll()
+	knowledge.InitializeContext(dataCtx)
+
+	return nil
+}
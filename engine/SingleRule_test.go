@@ -0,0 +1,153 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ConditionFact struct {
+	Amount  int64
+	Country string
+	Flagged bool
+}
+
+const conditionRule = `rule UserCondition { when Fact.Amount > 100 && Fact.Country == "ID" then Fact.Flagged = true; }`
+
+func newConditionKnowledgeBase(t testing.TB, grl string) *ast.KnowledgeBase {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Condition", "1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Condition", "1")
+	assert.NoError(t, err)
+
+	return kb
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	kb := newConditionKnowledgeBase(t, conditionRule)
+	eng := NewGruleEngine()
+	testData := []struct {
+		fact ConditionFact
+		want bool
+	}{
+		{fact: ConditionFact{Amount: 200, Country: "ID"}, want: true},
+		{fact: ConditionFact{Amount: 200, Country: "SG"}, want: false},
+		{fact: ConditionFact{Amount: 50, Country: "ID"}, want: false},
+		{fact: ConditionFact{Amount: 101, Country: "ID"}, want: true},
+	}
+	for _, td := range testData {
+		fact := td.fact
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", &fact))
+		matched, err := eng.EvaluateCondition(context.Background(), dctx, kb)
+		assert.NoError(t, err)
+		assert.Equal(t, td.want, matched, td.fact)
+		assert.False(t, fact.Flagged, "the then scope is not executed")
+	}
+
+	// the same data context can be evaluated again after the fact changed.
+	fact := &ConditionFact{Amount: 50, Country: "ID"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", fact))
+	matched, err := eng.EvaluateCondition(context.Background(), dctx, kb)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	fact.Amount = 500
+	matched, err = eng.EvaluateCondition(context.Background(), dctx, kb)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	fired, err := eng.ExecuteSingleRule(context.Background(), dctx, kb)
+	assert.NoError(t, err)
+	assert.True(t, fired)
+	assert.True(t, fact.Flagged)
+
+	_, err = eng.EvaluateCondition(context.Background(), dctx, newConditionKnowledgeBase(t, conditionRule+` rule Other { when true then Retract("Other"); }`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must have a single rule, it has 2")
+	_, err = eng.EvaluateCondition(context.Background(), nil, kb)
+	assert.Error(t, err)
+}
+
+func TestExecuteSingleRule_Selection(t *testing.T) {
+	fact := &ConditionFact{Amount: 200, Country: "ID"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", fact))
+	kb := newConditionKnowledgeBase(t, conditionRule)
+	eng := NewGruleEngine()
+
+	// the security context of the caller forbids the rule, as it would within ExecuteWithContext.
+	ctx := WithSecurityContext(context.Background(), &SecurityContext{Predicates: []SecurityPredicate{{
+		Name: "tenant",
+		Condition: func(dataCtx ast.IDataContext, rule *ast.RuleEntry) (bool, error) {
+			return false, nil
+		},
+	}}})
+	matched, err := eng.EvaluateCondition(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	fired, err := eng.ExecuteSingleRule(ctx, dctx, kb)
+	assert.NoError(t, err)
+	assert.False(t, fired)
+	assert.False(t, fact.Flagged)
+
+	// a rule of an inactive profile is not selected either.
+	profiled := newConditionKnowledgeBase(t, `@profile("eu-only") `+conditionRule)
+	matched, err = eng.EvaluateCondition(context.Background(), dctx, profiled)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	eng.Profiles = []string{"eu-only"}
+	matched, err = eng.EvaluateCondition(context.Background(), dctx, profiled)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	// the scratchpad and the working memory settings of the engine apply.
+	scratch := newConditionKnowledgeBase(t, `rule UserCondition { when Fact.Amount == "200" then tmp.Seen = true; Fact.Flagged = tmp.Seen; }`)
+	fact.Flagged = false
+	eng.StringNumberComparison = pkg.StringNumberNumeric
+	fired, err = eng.ExecuteSingleRule(context.Background(), dctx, scratch)
+	assert.NoError(t, err)
+	assert.True(t, fired)
+	assert.True(t, fact.Flagged)
+	eng.StringNumberComparison = pkg.StringNumberError
+	_, err = eng.EvaluateCondition(context.Background(), dctx, scratch)
+	assert.Error(t, err)
+}
+
+func BenchmarkEvaluateCondition(b *testing.B) {
+	kb := newConditionKnowledgeBase(b, conditionRule)
+	fact := &ConditionFact{Amount: 200, Country: "SG"}
+	dctx := ast.NewDataContext()
+	assert.NoError(b, dctx.Add("Fact", fact))
+	b.Run("EvaluateCondition", func(b *testing.B) {
+		eng := NewGruleEngine()
+		for i := 0; i < b.N; i++ {
+			_, _ = eng.EvaluateCondition(context.Background(), dctx, kb)
+		}
+	})
+	b.Run("ExecuteWithContext", func(b *testing.B) {
+		eng := NewGruleEngine()
+		for i := 0; i < b.N; i++ {
+			_ = eng.ExecuteWithContext(context.Background(), dctx, kb)
+		}
+	})
+}
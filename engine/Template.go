@@ -0,0 +1,42 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// ParseTemplates parses the text templates of the resources, such as those of a FileResourceBundle, into one set
+// for GruleEngine.Templates. The templates rules render are the ones defined in them, such as
+// {{define "offer_email"}}Dear {{.Name}}, ...{{end}}.
+func ParseTemplates(resources ...pkg.Resource) (*template.Template, error) {
+	templates := template.New("")
+	for _, resource := range resources {
+		data, err := resource.Load()
+		if err != nil {
+
+			return nil, fmt.Errorf("error loading template %s. got %w", resource.String(), err)
+		}
+		if _, err := templates.New(resource.String()).Parse(string(data)); err != nil {
+
+			return nil, fmt.Errorf("error parsing template %s. got %w", resource.String(), err)
+		}
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,82 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	htmltemplate "html/template"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type TemplateCustomer struct {
+	Name     string
+	Discount int64
+	Email    string
+}
+
+const templateRules = `
+rule Offer "Composes the offer email" {
+	when
+		Customer.Email == "" && Customer.Discount > 0
+	then
+		Customer.Email = Template("offer_email", Customer);
+}`
+
+func executeTemplate(t *testing.T, eng *GruleEngine, customer *TemplateCustomer) error {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Template", "0.0.1", pkg.NewBytesResource([]byte(templateRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("Template", "0.0.1")
+	assert.NoError(t, err)
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Customer", customer))
+
+	return eng.Execute(dctx, kb)
+}
+
+func TestTemplate(t *testing.T) {
+	templates, err := ParseTemplates(
+		pkg.NewBytesResource([]byte(`{{define "offer_email"}}Dear {{.Name}}, enjoy {{.Discount}}% off{{template "signature"}}{{end}}`)),
+		pkg.NewBytesResource([]byte(`{{define "signature"}}. The Shop{{end}}`)))
+	assert.NoError(t, err)
+	eng := NewGruleEngine()
+	eng.Templates = templates
+
+	customer := &TemplateCustomer{Name: "Ana", Discount: 15}
+	assert.NoError(t, executeTemplate(t, eng, customer))
+	assert.Equal(t, "Dear Ana, enjoy 15% off. The Shop", customer.Email)
+
+	// html templates escape the facts
+	html, err := htmltemplate.New("").Parse(`{{define "offer_email"}}<p>Dear {{.Name}}</p>{{end}}`)
+	assert.NoError(t, err)
+	eng.Templates = html
+	customer = &TemplateCustomer{Name: "<Ana>", Discount: 15}
+	assert.NoError(t, executeTemplate(t, eng, customer))
+	assert.Equal(t, "<p>Dear &lt;Ana&gt;</p>", customer.Email)
+
+	eng.Templates, err = ParseTemplates()
+	assert.NoError(t, err)
+	err = executeTemplate(t, eng, &TemplateCustomer{Name: "Ana", Discount: 15})
+	assert.ErrorContains(t, err, "offer_email")
+	eng.Templates = nil
+	err = executeTemplate(t, eng, &TemplateCustomer{Name: "Ana", Discount: 15})
+	assert.ErrorContains(t, err, "no templates are set")
+
+	_, err = ParseTemplates(pkg.NewBytesResource([]byte(`{{define "broken"}}`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,179 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// TestResult is the outcome of one test block.
+type TestResult struct {
+	TestName string
+	Passed   bool
+	// Fired lists the rules that fired during the test, in firing order.
+	Fired []string
+	// Err is set when the test could not be run, such as when the given list or the rules fail.
+	Err error
+}
+
+// testFunctions are the built-in functions available while running a test block.
+type testFunctions struct {
+	*ast.BuiltInFunctions
+	fired map[string]bool
+}
+
+// Fired tells whether the rule has fired during the test.
+func (tf *testFunctions) Fired(ruleName string) bool {
+
+	return tf.fired[ruleName]
+}
+
+// firedRecorder is a GruleEngineListener that records the firing rules.
+type firedRecorder struct {
+	fired []string
+}
+
+// EvaluateRuleEntry is ignored by the recorder.
+func (r *firedRecorder) EvaluateRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry, candidate bool) {
+}
+
+// ExecuteRuleEntry records the executed rule.
+func (r *firedRecorder) ExecuteRuleEntry(ctx context.Context, cycle uint64, entry *ast.RuleEntry) {
+	r.fired = append(r.fired, entry.RuleName)
+}
+
+// BeginCycle is ignored by the recorder.
+func (r *firedRecorder) BeginCycle(ctx context.Context, cycle uint64) {}
+
+// RunTests runs every test block of the knowledge base, ordered by their names. For each test a new KnowledgeBase instance
+// and a new data context from newDataContext is used, the given list is executed on it, then the rules are executed and
+// finally the expect expression is evaluated.
+// The returned error is set only when the tests can not be run at all, failing tests are reported in the results.
+func (g *GruleEngine) RunTests(ctx context.Context, lib *ast.KnowledgeLibrary, name, version string, newDataContext func() (ast.IDataContext, error)) ([]TestResult, error) {
+	if lib == nil || newDataContext == nil {
+
+		return nil, fmt.Errorf("nil KnowledgeLibrary or data context factory is not allowed")
+	}
+	blueprint, ok := lib.Library[ast.GetKnowledgeBaseKey(name, version)]
+	if !ok {
+
+		return nil, fmt.Errorf("KnowledgeBase %s version %s is not in the library", name, version)
+	}
+	names := make([]string, 0, len(blueprint.TestEntries))
+	for testName := range blueprint.TestEntries {
+		names = append(names, testName)
+	}
+	sort.Strings(names)
+
+	results := make([]TestResult, 0, len(names))
+	for _, testName := range names {
+		result := g.runTest(ctx, lib, blueprint.TestEntries[testName], name, version, newDataContext)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (g *GruleEngine) runTest(ctx context.Context, lib *ast.KnowledgeLibrary, blueprint *ast.TestEntry, name, version string, newDataContext func() (ast.IDataContext, error)) TestResult {
+	result := TestResult{TestName: blueprint.TestName}
+	knowledge, err := lib.NewKnowledgeBaseInstance(name, version)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+	// the test is run on a clone, the working memory of the blueprint is shared by the concurrent runs.
+	test, err := blueprint.Clone(pkg.NewCloneTable())
+	if err != nil {
+		result.Err = fmt.Errorf("can not clone test %s. got %w", blueprint.TestName, err)
+
+		return result
+	}
+	dataCtx, err := newDataContext()
+	if err != nil {
+		result.Err = fmt.Errorf("can not create data context. got %w", err)
+
+		return result
+	}
+	functions := &testFunctions{
+		BuiltInFunctions: &ast.BuiltInFunctions{
+			Knowledge:     knowledge,
+			WorkingMemory: test.WorkingMemory,
+			DataContext:   dataCtx,
+			DateNames:     g.DateNames,
+		},
+		fired: make(map[string]bool),
+	}
+	err = dataCtx.Add("DEFUNC", functions)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	test.WorkingMemory.ResetAll()
+	if test.Given != nil {
+		err = test.Given.Execute(dataCtx, test.WorkingMemory)
+		if err != nil {
+			result.Err = fmt.Errorf("given list of test %s failed. got %w", test.TestName, err)
+
+			return result
+		}
+	}
+
+	// the rules run with every setting of the engine, the recorder is added to a copy of its listeners.
+	recorder := &firedRecorder{}
+	runner := *g
+	runner.Listeners = append(append([]GruleEngineListener{}, g.Listeners...), recorder)
+	err = runner.ExecuteWithContext(ctx, dataCtx, knowledge)
+	result.Fired = recorder.fired
+	if err != nil {
+		result.Err = fmt.Errorf("rules execution of test %s failed. got %w", test.TestName, err)
+
+		return result
+	}
+
+	// the rules replaced DEFUNC and changed the facts, the expect expression must see both.
+	for _, ruleName := range recorder.fired {
+		functions.fired[ruleName] = true
+	}
+	err = dataCtx.Add("DEFUNC", functions)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+	test.WorkingMemory.ResetAll()
+	val, err := test.Expect.Evaluate(dataCtx, test.WorkingMemory)
+	if err != nil {
+		result.Err = fmt.Errorf("expect expression of test %s failed. got %w", test.TestName, err)
+
+		return result
+	}
+	if val.Kind() != reflect.Bool {
+		result.Err = fmt.Errorf("expect expression of test %s must be a boolean, got %s", test.TestName, val.Kind().String())
+
+		return result
+	}
+	result.Passed = val.Bool()
+
+	return result
+}
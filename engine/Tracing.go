@@ -0,0 +1,260 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+type traceKey struct{}
+
+// NewTracer create new instance of Tracer, tracing the sampleRate fraction of executions with at most maxEvents
+// events each.
+func NewTracer(sampleRate float64, maxEvents int) *Tracer {
+
+	return &Tracer{
+		SampleRate: sampleRate,
+		MaxEvents:  maxEvents,
+	}
+}
+
+// Tracer records what the engine does during a sample of the executions: the cycles, the when scopes evaluated
+// and the rules fired, and optionally the functions the rules called. Set it to GruleEngine.Tracer.
+// Tracer is safe to be shared by concurrent executions.
+type Tracer struct {
+	// SampleRate is the fraction of executions traced, 0.01 traces about one execution out of a hundred,
+	// 1 traces them all and 0 none.
+	SampleRate float64
+	// MaxEvents limits the number of events recorded by a trace, zero means unlimited. The events beyond
+	// the limit are dropped and a truncated event tells how many.
+	MaxEvents int
+	// Sink receives every finished trace, from the goroutine of the execution. If nil the traces are logged.
+	Sink func(trace *Trace)
+	// CaptureCalls records a call event for every function and method the rules call, with the values of the
+	// arguments and the result. A function cached by the working memory is recorded the first time only.
+	CaptureCalls bool
+	// Redact, if set, formats the values of the call events instead of %v, such as hiding the arguments of
+	// User.Authenticate. Index is the position of the argument, or -1 for the result.
+	Redact func(function string, index int, value interface{}) string
+}
+
+// TraceEventKind tells what happened in a trace event.
+type TraceEventKind string
+
+const (
+	// TraceEnrich is an enricher adding the facts it derived, before the first cycle.
+	TraceEnrich TraceEventKind = "enrich"
+	// TraceBeginCycle is the beginning of a cycle.
+	TraceBeginCycle TraceEventKind = "begin-cycle"
+	// TraceEvaluate is the evaluation of the when scope of a rule.
+	TraceEvaluate TraceEventKind = "evaluate"
+	// TraceExecute is the execution of the then scope of a rule.
+	TraceExecute TraceEventKind = "execute"
+	// TraceCall is a function or method called by a rule, recorded if Tracer.CaptureCalls is set.
+	TraceCall TraceEventKind = "call"
+	// TraceTruncated marks the end of a truncated trace.
+	TraceTruncated TraceEventKind = "truncated"
+)
+
+// TraceEvent is one thing the engine did during a traced execution.
+type TraceEvent struct {
+	Kind  TraceEventKind `json:"kind"`
+	Cycle uint64         `json:"cycle,omitempty"`
+	Rule  string         `json:"rule,omitempty"`
+	// Candidate tells, for an evaluate event, if the when scope is true.
+	Candidate bool `json:"candidate,omitempty"`
+	// Dropped is, for a truncated event, the number of events that were not recorded.
+	Dropped int `json:"dropped,omitempty"`
+	// Enricher is, for an enrich event, the name of the enricher, and Facts the names of the facts it added.
+	Enricher string   `json:"enricher,omitempty"`
+	Facts    []string `json:"facts,omitempty"`
+	// Function is, for a call event, the function called, Arguments and Result the formatted values it was
+	// called with and returned, and CallError the error of a failed call.
+	Function  string   `json:"function,omitempty"`
+	Arguments []string `json:"arguments,omitempty"`
+	Result    string   `json:"result,omitempty"`
+	CallError string   `json:"callError,omitempty"`
+}
+
+// Trace is the record of one execution.
+type Trace struct {
+	KnowledgeBase string        `json:"knowledgeBase"`
+	Version       string        `json:"version"`
+	Start         time.Time     `json:"start"`
+	Duration      time.Duration `json:"duration"`
+	// Error is the error the execution ended with, empty if it succeeded.
+	Error  string       `json:"error,omitempty"`
+	Events []TraceEvent `json:"events"`
+
+	maxEvents int
+	dropped   int
+}
+
+// Truncated tells if events were dropped because the trace reached Tracer.MaxEvents.
+func (trace *Trace) Truncated() bool {
+
+	return trace.dropped > 0
+}
+
+// String returns the trace as text, one event per line.
+func (trace *Trace) String() string {
+	var stringBuilder strings.Builder
+	fmt.Fprintf(&stringBuilder, "trace of %s version %s started at %s, took %s", trace.KnowledgeBase, trace.Version, trace.Start.Format(time.RFC3339Nano), trace.Duration)
+	if len(trace.Error) > 0 {
+		fmt.Fprintf(&stringBuilder, ", failed with %s", trace.Error)
+	}
+	for _, event := range trace.Events {
+		switch event.Kind {
+		case TraceEnrich:
+			fmt.Fprintf(&stringBuilder, "\nenrich %s : %s", event.Enricher, strings.Join(event.Facts, ", "))
+		case TraceBeginCycle:
+			fmt.Fprintf(&stringBuilder, "\ncycle %d", event.Cycle)
+		case TraceEvaluate:
+			fmt.Fprintf(&stringBuilder, "\n  evaluate %s : %t", event.Rule, event.Candidate)
+		case TraceExecute:
+			fmt.Fprintf(&stringBuilder, "\n  execute %s", event.Rule)
+		case TraceCall:
+			fmt.Fprintf(&stringBuilder, "\n  call %s(%s)", event.Function, strings.Join(event.Arguments, ", "))
+			if len(event.CallError) > 0 {
+				fmt.Fprintf(&stringBuilder, " failed with %s", event.CallError)
+			} else if len(event.Result) > 0 {
+				fmt.Fprintf(&stringBuilder, " = %s", event.Result)
+			}
+		case TraceTruncated:
+			fmt.Fprintf(&stringBuilder, "\n... %d more events truncated", event.Dropped)
+		}
+	}
+
+	return stringBuilder.String()
+}
+
+// record adds the event, or counts it as dropped if the trace is full.
+func (trace *Trace) record(event TraceEvent) {
+	if trace.maxEvents > 0 && len(trace.Events) >= trace.maxEvents {
+		trace.dropped++
+
+		return
+	}
+	trace.Events = append(trace.Events, event)
+}
+
+// start returns the trace of an execution, nil if the execution is not sampled.
+func (tracer *Tracer) start(knowledge *ast.KnowledgeBase) *Trace {
+	if tracer == nil || tracer.SampleRate <= 0 || (tracer.SampleRate < 1 && rand.Float64() >= tracer.SampleRate) {
+
+		return nil
+	}
+	events := 64
+	if tracer.MaxEvents > 0 && tracer.MaxEvents < events {
+		events = tracer.MaxEvents
+	}
+
+	return &Trace{
+		KnowledgeBase: knowledge.Name,
+		Version:       knowledge.Version,
+		Start:         time.Now(),
+		Events:        make([]TraceEvent, 0, events),
+		maxEvents:     tracer.MaxEvents,
+	}
+}
+
+// finish ends the trace and hands it to the sink, or logs it into the logger of the engine.
+func (tracer *Tracer) finish(trace *Trace, err error, log logger.LogEntry) {
+	trace.Duration = time.Since(trace.Start)
+	if err != nil {
+		trace.Error = err.Error()
+	}
+	if trace.dropped > 0 {
+		trace.Events = append(trace.Events, TraceEvent{Kind: TraceTruncated, Dropped: trace.dropped})
+	}
+	if tracer.Sink != nil {
+		tracer.Sink(trace)
+
+		return
+	}
+	log.Infof("%s", trace)
+}
+
+// calls returns the observer recording the calls of the rules into the trace, nil if the calls are not captured.
+func (tracer *Tracer) calls(trace *Trace) ast.CallObserver {
+	if trace == nil || !tracer.CaptureCalls {
+
+		return nil
+	}
+
+	return &callTracer{tracer: tracer, trace: trace}
+}
+
+// callTracer records the calls of the rules as call events. The when scopes evaluated in parallel call
+// functions concurrently, so the recording is serialized.
+type callTracer struct {
+	mutex  sync.Mutex
+	tracer *Tracer
+	trace  *Trace
+}
+
+// ObserveCall records the call event.
+func (c *callTracer) ObserveCall(function string, args []reflect.Value, result reflect.Value, err error) {
+	event := TraceEvent{Kind: TraceCall, Function: function, Arguments: make([]string, len(args))}
+	for i, arg := range args {
+		event.Arguments[i] = c.format(function, i, arg)
+	}
+	if err != nil {
+		event.CallError = err.Error()
+	} else if result.IsValid() {
+		event.Result = c.format(function, -1, result)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.trace.record(event)
+}
+
+func (c *callTracer) format(function string, index int, value reflect.Value) string {
+	val := pkg.ValueToInterface(value)
+	if c.tracer.Redact != nil {
+
+		return c.tracer.Redact(function, index, val)
+	}
+	if str, ok := val.(string); ok {
+
+		return strconv.Quote(str)
+	}
+
+	return fmt.Sprintf("%v", val)
+}
+
+// withTrace attaches the trace of the execution to its context.
+func withTrace(ctx context.Context, trace *Trace) context.Context {
+
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// traceFrom returns the trace attached to the context, nil if the execution is not traced.
+func traceFrom(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+
+	return trace
+}
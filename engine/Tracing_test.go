@@ -0,0 +1,158 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracer(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Loan", "1.0.0", pkg.NewBytesResource([]byte(outcomeRules))))
+
+	traces := make([]*Trace, 0)
+	engine := NewGruleEngine()
+	engine.Tracer = NewTracer(1, 0)
+	engine.Tracer.Sink = func(trace *Trace) {
+		traces = append(traces, trace)
+	}
+	execute := func() {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Loan", &LoanApplication{Score: 800}))
+		kb, err := lib.NewKnowledgeBaseInstance("Loan", "1.0.0")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.Execute(dctx, kb))
+	}
+
+	execute()
+	assert.Len(t, traces, 1)
+	trace := traces[0]
+	assert.Equal(t, "Loan", trace.KnowledgeBase)
+	assert.False(t, trace.Truncated())
+	// two cycles, both rules evaluated in each and Approve executed in the first.
+	assert.Len(t, trace.Events, 7)
+	assert.Contains(t, trace.Events, TraceEvent{Kind: TraceEvaluate, Cycle: 1, Rule: "Approve", Candidate: true})
+	assert.Contains(t, trace.Events, TraceEvent{Kind: TraceExecute, Cycle: 1, Rule: "Approve"})
+	assert.Contains(t, trace.String(), "  execute Approve")
+
+	engine.Tracer.MaxEvents = 4
+	execute()
+	trace = traces[1]
+	assert.True(t, trace.Truncated())
+	assert.Len(t, trace.Events, 5)
+	assert.Equal(t, TraceEvent{Kind: TraceTruncated, Dropped: 3}, trace.Events[4])
+	assert.Contains(t, trace.String(), "... 3 more events truncated")
+
+	engine.Tracer.SampleRate = 0
+	execute()
+	assert.Len(t, traces, 2)
+
+	engine.Tracer.SampleRate = 0.25
+	for i := 0; i < 1000; i++ {
+		execute()
+	}
+	assert.InDelta(t, 250, len(traces)-2, 100)
+}
+
+type scoringFunctions struct{}
+
+func (f *scoringFunctions) Score(name string, weight int64) int64 {
+
+	return int64(len(name)) * weight
+}
+
+func (f *scoringFunctions) ValidateArguments(function string, args []reflect.Value) error {
+	if function == "Score" && args[1].Int() < 0 {
+
+		return fmt.Errorf("weight %d is negative", args[1].Int())
+	}
+
+	return nil
+}
+
+const callRules = `
+rule Scored "score the applicant" {
+	when
+		Applicant.Score == 0
+	then
+		Applicant.Score = Scoring.Score(Applicant.Name, Applicant.Weight);
+		Retract("Scored");
+}
+`
+
+type scoredApplicant struct {
+	Name   string
+	Weight int64
+	Score  int64
+}
+
+func TestTracer_CaptureCalls(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Scoring", "1.0.0", pkg.NewBytesResource([]byte(callRules))))
+
+	traces := make([]*Trace, 0)
+	engine := NewGruleEngine()
+	engine.Tracer = NewTracer(1, 0)
+	engine.Tracer.CaptureCalls = true
+	engine.Tracer.Sink = func(trace *Trace) {
+		traces = append(traces, trace)
+	}
+	execute := func(applicant *scoredApplicant) error {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Applicant", applicant))
+		assert.NoError(t, dctx.Add("Scoring", &scoringFunctions{}))
+		kb, err := lib.NewKnowledgeBaseInstance("Scoring", "1.0.0")
+		assert.NoError(t, err)
+
+		return engine.Execute(dctx, kb)
+	}
+
+	assert.NoError(t, execute(&scoredApplicant{Name: "ann", Weight: 2}))
+	assert.Contains(t, traces[0].Events, TraceEvent{Kind: TraceCall, Function: "Scoring.Score", Arguments: []string{`"ann"`, "2"}, Result: "6"})
+	assert.Contains(t, traces[0].Events, TraceEvent{Kind: TraceCall, Function: "Retract", Arguments: []string{`"Scored"`}})
+	assert.Contains(t, traces[0].String(), `  call Scoring.Score("ann", 2) = 6`)
+
+	// the function is not called with arguments it does not accept.
+	err := execute(&scoredApplicant{Name: "bob", Weight: -1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid arguments for Scoring.Score")
+	assert.Contains(t, traces[1].String(), "call Scoring.Score(\"bob\", -1) failed with invalid arguments for Scoring.Score. got weight -1 is negative")
+
+	engine.Tracer.Redact = func(function string, index int, value interface{}) string {
+		if function == "Scoring.Score" && index == 0 {
+
+			return "***"
+		}
+
+		return fmt.Sprintf("%v", value)
+	}
+	assert.NoError(t, execute(&scoredApplicant{Name: "cid", Weight: 1}))
+	assert.Contains(t, traces[2].Events, TraceEvent{Kind: TraceCall, Function: "Scoring.Score", Arguments: []string{"***", "1"}, Result: "3"})
+
+	engine.Tracer.CaptureCalls = false
+	assert.NoError(t, execute(&scoredApplicant{Name: "dan", Weight: 1}))
+	for _, event := range traces[3].Events {
+		assert.NotEqual(t, TraceCall, event.Kind)
+	}
+}
@@ -0,0 +1,107 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// EncapsulatedOrder keeps its state private, the rules reach it through the getters and setters.
+type EncapsulatedOrder struct {
+	status   string
+	quantity int
+	Priority int
+}
+
+func (o *EncapsulatedOrder) GetStatus() string {
+	return o.status
+}
+
+func (o *EncapsulatedOrder) SetStatus(status string) error {
+	if status == "" {
+		return errors.New("status can not be empty")
+	}
+	o.status = status
+
+	return nil
+}
+
+func (o *EncapsulatedOrder) GetQuantity() int {
+	return o.quantity
+}
+
+func (o *EncapsulatedOrder) SetQuantity(quantity int) {
+	o.quantity = quantity
+}
+
+const accessorRules = `
+rule Ship "Ship the paid orders" {
+	when
+		Order.Status == "paid" && Order.Quantity > 0
+	then
+		Order.Status = "shipped";
+		Order.Quantity = Order.Quantity - 1;
+		Order.Priority = 2;
+}
+rule Clear "Clearing the status is refused by the setter" {
+	when
+		Order.Status == "cancelled"
+	then
+		Order.Status = "";
+}`
+
+func TestAccessorFieldAccess(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Accessor", "0.0.1", pkg.NewBytesResource([]byte(accessorRules))))
+
+	execute := func(order *EncapsulatedOrder) error {
+		dctx := ast.NewDataContextWithAccessors(model.StandardAccessors())
+		assert.NoError(t, dctx.Add("Order", order))
+		kb, err := lib.NewKnowledgeBaseInstance("Accessor", "0.0.1")
+		assert.NoError(t, err)
+
+		return engine.NewGruleEngine().Execute(dctx, kb)
+	}
+
+	order := &EncapsulatedOrder{status: "paid", quantity: 3}
+	assert.NoError(t, execute(order))
+	assert.Equal(t, "shipped", order.status)
+	assert.Equal(t, 2, order.quantity)
+	assert.Equal(t, 2, order.Priority)
+
+	err := execute(&EncapsulatedOrder{status: "cancelled"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status can not be empty")
+
+	// a data context created without a convention does not call the getters.
+	dctx := ast.NewDataContext()
+	order = &EncapsulatedOrder{status: "paid", quantity: 3}
+	assert.NoError(t, dctx.Add("Order", order))
+	kb, err := lib.NewKnowledgeBaseInstance("Accessor", "0.0.1")
+	assert.NoError(t, err)
+	eng := engine.NewGruleEngine()
+	eng.ReturnErrOnFailedRuleEvaluation = true
+	assert.Error(t, eng.Execute(dctx, kb))
+	assert.Equal(t, "paid", order.status)
+}
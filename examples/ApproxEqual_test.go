@@ -0,0 +1,97 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ApproxFact struct {
+	Price  float64
+	Within float64
+	Result string
+}
+
+func TestApproxEqual(t *testing.T) {
+	testData := []struct {
+		condition string
+		price     float64
+		tolerance float64
+		result    string
+		fails     bool
+	}{
+		{condition: `Fact.Price ~== 0.3`, price: 0.1 + 0.2, result: "matched"},
+		{condition: `Fact.Price ~== 9.99`, price: 9.991, result: ""},
+		{condition: `Fact.Price ~== 9.99 within 0.01`, price: 9.991, result: "matched"},
+		{condition: `Fact.Price ~== 9.99 within 0.0001`, price: 9.991, result: ""},
+		{condition: `Fact.Price ~== 10 within 0.5 && Fact.Price > 9`, price: 9.6, result: "matched"},
+		{condition: `Fact.Price ~== 9.99`, price: 9.991, tolerance: 0.01, result: "matched"},
+		{condition: `Fact.Price ~== 9.99 within 0.0001`, price: 9.991, tolerance: 0.01, result: ""},
+		{condition: `Fact.Price ~== 9.99 within -1`, price: 9.99, fails: true},
+		{condition: `Fact.Price ~== "9.99"`, price: 9.99, fails: true},
+	}
+	for _, td := range testData {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Approx { when Fact.Result == "" && ` + td.condition + ` then Fact.Result = "matched"; }`
+		assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Approx", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+		kb, err := lib.NewKnowledgeBaseInstance("Approx", "0.0.1")
+		assert.NoError(t, err)
+
+		fact := &ApproxFact{Price: td.price}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		eng := engine.NewGruleEngine()
+		eng.ReturnErrOnFailedRuleEvaluation = true
+		eng.Tolerance = td.tolerance
+		err = eng.Execute(dctx, kb)
+		if td.fails {
+			assert.Error(t, err, "%s with %v", td.condition, td.price)
+		} else {
+			assert.NoError(t, err, "%s with %v", td.condition, td.price)
+			assert.Equal(t, td.result, fact.Result, "%s with %v", td.condition, td.price)
+		}
+	}
+}
+
+func TestApproxEqual_WithinOnlyAfterApproxEqual(t *testing.T) {
+	for _, condition := range []string{`Fact.Price == 9.99 within 0.01`, `Fact.Price within 0.01`, `Fact.Price ~== 9.99 upto 0.01`} {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Approx { when ` + condition + ` then Fact.Result = "matched"; }`
+		err := builder.NewRuleBuilder(lib).BuildRuleFromResource("Approx", "0.0.1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, condition)
+	}
+}
+
+func TestApproxEqual_WithinIsNotReserved(t *testing.T) {
+	// within is a contextual keyword, facts and fields may still be named after it.
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule Approx { when within.Result == "" && within.Price ~== 9.99 within within.Within then within.Result = "matched"; within.Within = 0; }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Approx", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Approx", "0.0.1")
+	assert.NoError(t, err)
+
+	fact := &ApproxFact{Price: 9.991, Within: 0.01}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("within", fact))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+	assert.Equal(t, "matched", fact.Result)
+	assert.Equal(t, float64(0), fact.Within)
+}
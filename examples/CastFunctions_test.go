@@ -0,0 +1,72 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type CastFact struct {
+	Code     string
+	Quantity uint32
+	Balance  int64
+	Ratio    float64
+	Label    string
+	Enabled  bool
+}
+
+func executeCast(t *testing.T, fact *CastFact, then string) error {
+	lib := ast.NewKnowledgeLibrary()
+	rule := `rule Cast { when true then ` + then + ` Retract("Cast"); }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Cast", "0.0.1", pkg.NewBytesResource([]byte(rule))))
+	kb, err := lib.NewKnowledgeBaseInstance("Cast", "0.0.1")
+	assert.NoError(t, err)
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Fact", fact))
+
+	return engine.NewGruleEngine().Execute(dctx, kb)
+}
+
+func TestCastFunctions(t *testing.T) {
+	fact := &CastFact{Code: "250", Balance: -7}
+	err := executeCast(t, fact, `
+		Fact.Quantity = ToUint(Fact.Code);
+		Fact.Ratio = ToFloat(Fact.Code) / 1000;
+		Fact.Label = ToString(Fact.Quantity) + "/" + ToString(Fact.Ratio);
+		Fact.Enabled = ToBool("true") && ToInt(Fact.Ratio) == 0;`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(250), fact.Quantity)
+	assert.Equal(t, 0.25, fact.Ratio)
+	assert.Equal(t, "250/0.25", fact.Label)
+	assert.True(t, fact.Enabled)
+
+	// a negative value is neither cast nor assigned into an unsigned field
+	err = executeCast(t, fact, `Fact.Quantity = ToUint(Fact.Balance);`)
+	assert.ErrorContains(t, err, "negative value -7 is not allowed")
+	err = executeCast(t, fact, `Fact.Quantity = Fact.Balance;`)
+	assert.ErrorContains(t, err, "negative value -7 can not be set into uint32")
+	err = executeCast(t, fact, `Fact.Quantity = 5000000000;`)
+	assert.ErrorContains(t, err, "overflows uint32")
+	assert.Equal(t, uint32(250), fact.Quantity)
+
+	err = executeCast(t, fact, `Fact.Balance = ToInt("twelve");`)
+	assert.ErrorContains(t, err, `"twelve" is not an integer`)
+}
@@ -0,0 +1,124 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type DeltaFact struct {
+	Score  int64
+	Points int64
+}
+
+// deltaRules generates one rule per band, the band of the modified index awards more points.
+func deltaRules(count, modified int) string {
+	var grl strings.Builder
+	for i := 0; i < count; i++ {
+		points := i
+		if i == modified {
+			points = 1000
+		}
+		grl.WriteString(fmt.Sprintf(`
+rule Band%d "band %d" {
+	when
+		DeltaFact.Score >= %d && DeltaFact.Score < %d
+	then
+		DeltaFact.Points = %d;
+		Retract("Band%d");
+}`, i, i, i*10, i*10+10, points, i))
+	}
+
+	return grl.String()
+}
+
+func deltaCatalog(t *testing.T, version, grl string) *ast.Catalog {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Delta", version, pkg.NewBytesResource([]byte(grl))))
+
+	return lib.GetKnowledgeBase("Delta", version).MakeCatalog()
+}
+
+func deltaPoints(t *testing.T, lib *ast.KnowledgeLibrary, version string, score int64) int64 {
+	fact := &DeltaFact{Score: score}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("DeltaFact", fact))
+	kb, err := lib.NewKnowledgeBaseInstance("Delta", version)
+	assert.NoError(t, err)
+	assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+
+	return fact.Points
+}
+
+func TestCatalogDelta(t *testing.T) {
+	// the node loads the first version as shipped.
+	base := deltaCatalog(t, "1", deltaRules(100, -1))
+	var full bytes.Buffer
+	assert.NoError(t, base.WriteCatalogToWriter(&full))
+	node := ast.NewKnowledgeLibrary()
+	_, err := node.LoadKnowledgeBaseFromReader(bytes.NewReader(full.Bytes()), false)
+	assert.NoError(t, err)
+
+	// the second version changes a single rule and is built again from GRL.
+	target := deltaCatalog(t, "2", deltaRules(100, 42))
+	delta, err := ast.MakeCatalogDelta(base, target)
+	assert.NoError(t, err)
+	var wire bytes.Buffer
+	assert.NoError(t, delta.WriteCatalogDeltaToWriter(&wire))
+	assert.Less(t, wire.Len()*100, full.Len(), "delta of %d bytes for a catalog of %d bytes", wire.Len(), full.Len())
+
+	kb, err := node.LoadCatalogDeltaFromReader(&wire, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", kb.Version)
+	assert.Equal(t, int64(1000), deltaPoints(t, node, "2", 425))
+	assert.Equal(t, int64(41), deltaPoints(t, node, "2", 415))
+	assert.Equal(t, int64(42), deltaPoints(t, node, "1", 425))
+
+	built := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(built).BuildRuleFromResource("Delta", "2", pkg.NewBytesResource([]byte(deltaRules(100, 42)))))
+	assert.True(t, built.GetKnowledgeBase("Delta", "2").IsIdentical(kb))
+
+	// the next delta is made from the patched catalog, rules are removed too.
+	patched, err := base.ApplyDelta(delta)
+	assert.NoError(t, err)
+	next, err := ast.MakeCatalogDelta(patched, deltaCatalog(t, "3", deltaRules(90, -1)))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, next.RemovedIDs)
+	wire.Reset()
+	assert.NoError(t, next.WriteCatalogDeltaToWriter(&wire))
+	_, err = node.LoadCatalogDeltaFromReader(&wire, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), deltaPoints(t, node, "3", 425))
+	assert.Equal(t, int64(0), deltaPoints(t, node, "3", 950))
+
+	// a delta does not apply to another catalog.
+	_, err = target.ApplyDelta(next)
+	assert.Error(t, err)
+	wire.Reset()
+	assert.NoError(t, next.WriteCatalogDeltaToWriter(&wire))
+	_, err = node.LoadCatalogDeltaFromReader(&wire, true)
+	assert.NoError(t, err)
+	_, err = ast.NewKnowledgeLibrary().LoadCatalogDeltaFromReader(bytes.NewReader(wire.Bytes()), false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type CollectItem struct {
+	Name  string
+	Price float64
+}
+
+type CollectCart struct {
+	Items     []*CollectItem
+	Premium   bool
+	ItemCount int64
+}
+
+const CollectRules = `
+rule Premium "a cart with two expensive items is premium" salience 10 {
+	when
+		!Cart.Premium && HighValueItems.Len() >= 2
+	then
+		Cart.Premium = true;
+}
+
+rule CollectHighValue "collect the expensive items" {
+	when
+		Cart.ItemCount == 0
+	then
+		collect HighValueItems from Cart.Items where item.Price > 100 && item.Name != "";
+		collect AllItems FROM Cart.Items;
+		Cart.ItemCount = AllItems.Len();
+		Retract("CollectHighValue");
+}
+`
+
+func TestCollect(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Collect", "1", pkg.NewBytesResource([]byte(CollectRules))))
+
+	// the collect survives a catalog round trip.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Collect", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	testData := []struct {
+		prices  []float64
+		premium bool
+		matched int
+	}{
+		{prices: []float64{150, 20, 300}, premium: true, matched: 2},
+		{prices: []float64{150, 20, 30}, premium: false, matched: 1},
+		{prices: []float64{}, premium: false, matched: 0},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			cart := &CollectCart{Items: make([]*CollectItem, 0)}
+			for _, price := range td.prices {
+				cart.Items = append(cart.Items, &CollectItem{Name: "item", Price: price})
+			}
+			dataContext := ast.NewDataContext()
+			assert.NoError(t, dataContext.Add("Cart", cart))
+			kb, err := library.NewKnowledgeBaseInstance("Collect", "1")
+			assert.NoError(t, err)
+			assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+			assert.Equal(t, td.premium, cart.Premium, td.prices)
+			assert.Equal(t, int64(len(td.prices)), cart.ItemCount, td.prices)
+
+			collected := dataContext.Get("HighValueItems").Value().Interface().([]*CollectItem)
+			assert.Len(t, collected, td.matched, td.prices)
+			for _, item := range collected {
+				assert.Greater(t, item.Price, 100.0)
+			}
+			// the item the condition is tested against does not remain as a fact.
+			assert.Nil(t, dataContext.Get("item"))
+		}
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	testData := []string{
+		`rule A { when true then collect Items of Cart.Items where item.Price > 1; }`,
+		`rule A { when true then collect Items from Cart.Items when item.Price > 1; }`,
+		`rule A { when true then gather Items from Cart.Items; }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		err := rb.BuildRuleFromResource("Collect", "1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	grl := `rule NotArray { when Cart.ItemCount == 0 then collect Items from Cart.Premium; Retract("NotArray"); }`
+	assert.NoError(t, rb.BuildRuleFromResource("Collect", "1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Collect", "1")
+	assert.NoError(t, err)
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Cart", &CollectCart{}))
+	err = engine.NewGruleEngine().Execute(dataContext, kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not an array or slice")
+}
@@ -0,0 +1,123 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Shipment struct {
+	Country  string
+	Channel  string
+	Denied   pkg.Set
+	Decision string
+	Limit    int64
+}
+
+const CollectionRules = `
+rule Deny salience 10 {
+	when
+		Shipment.Decision == "" && Shipment.Denied.Union({"KP", "IR"}).Has(Shipment.Country)
+	then
+		Shipment.Decision = "deny";
+}
+rule Allow salience 5 {
+	when
+		Shipment.Decision == "" && {"US", "CA", "MX"}.Has(Shipment.Country)
+	then
+		Shipment.Decision = "allow";
+		Shipment.Limit = {"US": 100, "CA": 50, "MX": 20}.Get(Shipment.Country);
+		Shipment.Denied = Shipment.Denied | {"KP", "IR"};
+}
+rule Review {
+	when
+		Shipment.Decision == "" && {"web", "app"}.Has(Shipment.Channel) && {"web"} - {"app"} == {"web"}
+	then
+		Shipment.Decision = "review";
+}
+`
+
+func TestCollection(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Collection", "1", pkg.NewBytesResource([]byte(CollectionRules))))
+
+	// the literals survive a binary and a JSON catalog round trip.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Collection", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	var doc bytes.Buffer
+	assert.NoError(t, lib.GetKnowledgeBase("Collection", "1").MakeCatalog().WriteCatalogToJSON(&doc))
+	assert.Contains(t, doc.String(), `"type": "orderedMap"`)
+	cat := &ast.Catalog{}
+	assert.NoError(t, cat.ReadCatalogFromJSON(&doc))
+	fromJSON, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.True(t, lib.GetKnowledgeBase("Collection", "1").IsIdentical(fromJSON))
+
+	denied, err := pkg.NewSet("CU")
+	assert.NoError(t, err)
+	testData := []struct {
+		country  string
+		channel  string
+		decision string
+		limit    int64
+	}{
+		{"IR", "web", "deny", 0},
+		{"CU", "web", "deny", 0},
+		{"CA", "web", "allow", 50},
+		{"FR", "web", "review", 0},
+		{"FR", "phone", "", 0},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			shipment := &Shipment{Country: td.country, Channel: td.channel, Denied: denied}
+			dataContext := ast.NewDataContext()
+			assert.NoError(t, dataContext.Add("Shipment", shipment))
+			kb, err := library.NewKnowledgeBaseInstance("Collection", "1")
+			assert.NoError(t, err)
+			assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+			assert.Equal(t, td.decision, shipment.Decision, td.country)
+			assert.Equal(t, td.limit, shipment.Limit, td.country)
+			if td.decision == "allow" {
+				assert.Equal(t, `{"CU", "KP", "IR"}`, shipment.Denied.String())
+			}
+		}
+	}
+	assert.Equal(t, `{"CU"}`, denied.String(), "set of the fact must not be modified")
+}
+
+func TestCollectionErrors(t *testing.T) {
+	testData := []string{
+		`rule A { when {"a", "b": 1}.Has("a") then Retract("A"); }`,
+		`rule A { when {{"a"}}.Has("a") then Retract("A"); }`,
+		`rule A { when {"a": 1, {"b"}: 2}.Has("a") then Retract("A"); }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		err := rb.BuildRuleFromResource("Collection", "1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+}
@@ -0,0 +1,58 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Withdrawal struct {
+	Amount  float64
+	Flagged bool
+}
+
+const ConfigRule = `
+rule LargeWithdrawal "flag withdrawal above the configured limit" {
+	when
+		!Withdrawal.Flagged && Withdrawal.Amount > Config.GetFloat("limits.max_amount", 1000)
+	then
+		Withdrawal.Flagged = true;
+}
+`
+
+func TestConfigFactInRule(t *testing.T) {
+	config := pkg.NewConfigFact(0, pkg.MapConfigSource{"limits.max_amount": "500"})
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Config", "1.0.0", pkg.NewBytesResource([]byte(ConfigRule)))
+	assert.NoError(t, err)
+
+	for _, amount := range []float64{400, 600} {
+		withdrawal := &Withdrawal{Amount: amount}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Withdrawal", withdrawal))
+		assert.NoError(t, dataContext.Add("Config", config))
+		kb, err := lib.NewKnowledgeBaseInstance("Config", "1.0.0")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+		assert.Equal(t, amount > 500, withdrawal.Flagged, amount)
+	}
+}
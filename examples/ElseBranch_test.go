@@ -0,0 +1,100 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const elseRules = `
+rule Adult "tells the adults from the minors" salience 10 {
+	when
+		Person.Age >= 18
+	then
+		Person.Category = "adult";
+		Retract("Adult");
+	else {
+		Person.Category = "minor";
+		Retract("Adult");
+	}
+}
+rule Greet "greets once categorized" {
+	when
+		Person.Category != "" && Person.Greeting == ""
+	then
+		Person.Greeting = "Hello " + Person.Category;
+}
+`
+
+type ElsePerson struct {
+	Age      int
+	Category string
+	Greeting string
+}
+
+func TestElseBranch(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Else", "0.0.1", pkg.NewBytesResource([]byte(elseRules))))
+
+	// the else scope survives a round trip through the catalog.
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(buffer, "Else", "0.0.1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(buffer, true)
+	assert.NoError(t, err)
+
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for age, expected := range map[int]string{42: "Hello adult", 12: "Hello minor"} {
+			kb, err := library.NewKnowledgeBaseInstance("Else", "0.0.1")
+			assert.NoError(t, err)
+			person := &ElsePerson{Age: age}
+			dctx := ast.NewDataContext()
+			assert.NoError(t, dctx.Add("Person", person))
+			assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+			assert.Equal(t, expected, person.Greeting)
+			assert.Equal(t, 1, kb.RuleEntries["Adult"].FireCount)
+		}
+	}
+}
+
+func TestElseBranch_SingleRule(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule Check { when Person.Age >= 18 then Person.Category = "adult"; else { Person.Category = "minor"; } }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Single", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Single", "0.0.1")
+	assert.NoError(t, err)
+
+	person := &ElsePerson{Age: 12}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Person", person))
+	fired, err := engine.NewGruleEngine().ExecuteSingleRule(context.Background(), dctx, kb)
+	assert.NoError(t, err)
+	assert.True(t, fired)
+	assert.Equal(t, "minor", person.Category)
+}
+
+func TestElseBranch_Keyword(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule Check { when Person.Age >= 18 then Person.Category = "adult"; otherwise { Person.Category = "minor"; } }`
+	assert.Error(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Keyword", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+}
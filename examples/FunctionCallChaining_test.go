@@ -128,3 +128,141 @@ rule SetTreeName "Set the top most tree name" {
 	assert.Equal(t, "verified", Tree.Name)
 	assert.Equal(t, "SUCCESS", Tree.Child.Child.Child.Name)
 }
+
+type ChainPerson struct {
+	Name        string
+	Blacklisted bool
+}
+
+func (p ChainPerson) IsBlacklisted() bool {
+	return p.Blacklisted
+}
+
+func (p *ChainPerson) Nickname() string {
+	if p == nil {
+		return "nobody"
+	}
+
+	return p.Name
+}
+
+type ChainNamed interface {
+	GetName() string
+}
+
+func (p *ChainPerson) GetName() string {
+	return p.Name
+}
+
+type ChainAccount struct {
+	owner *ChainPerson
+	Calls int
+}
+
+func (a *ChainAccount) Owner() ChainPerson {
+	a.Calls++
+
+	return *a.owner
+}
+
+func (a *ChainAccount) OwnerPtr() *ChainPerson {
+	a.Calls++
+
+	return a.owner
+}
+
+func (a *ChainAccount) Named() ChainNamed {
+	if a.owner == nil {
+		return nil
+	}
+
+	return a.owner
+}
+
+type ChainFact struct {
+	account *ChainAccount
+	Result  string
+	Count   int
+}
+
+func (f *ChainFact) Account() *ChainAccount {
+	return f.account
+}
+
+func executeChain(t *testing.T, grl string, fact *ChainFact, nilSafe bool) error {
+	dataContext := ast.NewDataContext()
+	err := dataContext.Add("Fact", fact)
+	assert.NoError(t, err)
+	lib := ast.NewKnowledgeLibrary()
+	err = builder.NewRuleBuilder(lib).BuildRuleFromResource("TestStructChaining", "0.0.1", pkg.NewBytesResource([]byte(grl)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("TestStructChaining", "0.0.1")
+	assert.NoError(t, err)
+	eng := &engine.GruleEngine{MaxCycle: 20, ReturnErrOnFailedRuleEvaluation: true, NilSafeChaining: nilSafe}
+
+	return eng.Execute(dataContext, kb)
+}
+
+func TestStructReturningChain(t *testing.T) {
+	testData := []string{
+		`Fact.Account().Owner().IsBlacklisted()`,
+		`Fact.Account().OwnerPtr().IsBlacklisted()`,
+		`Fact.Account().Owner().Name == "Eve"`,
+		`Fact.Account().OwnerPtr().Name == "Eve"`,
+		`Fact.Account().Named().GetName() == "Eve"`,
+	}
+	for _, when := range testData {
+		fact := &ChainFact{account: &ChainAccount{owner: &ChainPerson{Name: "Eve", Blacklisted: true}}}
+		err := executeChain(t, `rule Chain { when `+when+` then Fact.Result = "matched"; Retract("Chain"); }`, fact, false)
+		assert.NoError(t, err, when)
+		assert.Equal(t, "matched", fact.Result, when)
+	}
+}
+
+func TestStructReturningChainNil(t *testing.T) {
+	testData := []struct {
+		when      string
+		errorText string
+		nilSafe   bool
+	}{
+		{`Fact.Account().OwnerPtr().IsBlacklisted()`, "Fact.Account().OwnerPtr() is nil, can not call IsBlacklisted", false},
+		{`Fact.Account().OwnerPtr().Name == ""`, "Fact.Account().OwnerPtr() is nil, can not get Name", false},
+		{`Fact.Account().Named().GetName() == ""`, "Fact.Account().Named() is nil, can not call GetName", false},
+		{`!Fact.Account().OwnerPtr().IsBlacklisted()`, "", true},
+		{`Fact.Account().OwnerPtr().Name == ""`, "", true},
+		{`Fact.Account().Named().GetName() == ""`, "", true},
+		// a method with a pointer receiver is called with the nil, as Go does.
+		{`Fact.Account().OwnerPtr().Nickname() == "nobody"`, "", false},
+	}
+	for _, td := range testData {
+		fact := &ChainFact{account: &ChainAccount{}}
+		err := executeChain(t, `rule Chain { when `+td.when+` then Fact.Result = "matched"; Retract("Chain"); }`, fact, td.nilSafe)
+		if len(td.errorText) > 0 {
+			assert.ErrorContains(t, err, td.errorText, td.when)
+		} else {
+			assert.NoError(t, err, td.when)
+			assert.Equal(t, "matched", fact.Result, td.when)
+		}
+	}
+}
+
+func TestStructReturningChainCached(t *testing.T) {
+	fact := &ChainFact{account: &ChainAccount{owner: &ChainPerson{Name: "Eve"}}}
+	err := executeChain(t, `
+rule Count salience 10 {
+	when
+		Fact.Account().OwnerPtr().Name == "Eve" && Fact.Count < 3
+	then
+		Fact.Count = Fact.Count + 1;
+}
+rule Other {
+	when
+		Fact.Account().OwnerPtr().Name != "Eve"
+	then
+		Fact.Result = "other";
+}`, fact, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fact.Count)
+	// the chain is shared by the rules and its result kept for the execution.
+	assert.Equal(t, 1, fact.account.Calls)
+}
@@ -0,0 +1,168 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Member struct {
+	Tier     string
+	Discount int64
+}
+
+const MemberRules = `
+rule GoldDiscount "gold members get 5" {
+	when
+		Member.Tier == "gold" && Member.Discount == 0
+	then
+		Member.Discount = 5;
+}
+
+test "gold member gets a discount" {
+	given {
+		Member.Tier = "gold";
+	}
+	expect fired("GoldDiscount") && Member.Discount == 5;
+}
+
+test "silver member gets nothing" {
+	given {
+		Member.Tier = "silver";
+	}
+	expect !fired("GoldDiscount") && Member.Discount == 0
+}
+
+test "wrong expectation" {
+	expect Member.Discount == 5;
+}
+`
+
+func TestGrlTestBlocks(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("Members", "1.0.0", pkg.NewBytesResource([]byte(MemberRules)))
+	assert.NoError(t, err)
+
+	eng := engine.NewGruleEngine()
+	results, err := eng.RunTests(context.Background(), lib, "Members", "1.0.0", func() (ast.IDataContext, error) {
+		dataContext := ast.NewDataContext()
+
+		return dataContext, dataContext.Add("Member", &Member{})
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "gold member gets a discount", results[0].TestName)
+		assert.NoError(t, results[0].Err)
+		assert.True(t, results[0].Passed)
+		assert.Equal(t, []string{"GoldDiscount"}, results[0].Fired)
+
+		assert.Equal(t, "silver member gets nothing", results[1].TestName)
+		assert.NoError(t, results[1].Err)
+		assert.True(t, results[1].Passed)
+		assert.Empty(t, results[1].Fired)
+
+		assert.Equal(t, "wrong expectation", results[2].TestName)
+		assert.NoError(t, results[2].Err)
+		assert.False(t, results[2].Passed)
+	}
+
+	// test blocks are ignored when the rules are executed.
+	member := &Member{Tier: "gold"}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Member", member))
+	kb, err := lib.NewKnowledgeBaseInstance("Members", "1.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, 1)
+	assert.NoError(t, eng.Execute(dataContext, kb))
+	assert.Equal(t, int64(5), member.Discount)
+}
+
+const ProfiledMemberRules = `
+@profile("loyalty")
+rule GoldDiscount "gold members get 5 through the scratchpad" {
+	when
+		Member.Tier == "gold" && Member.Discount == 0
+	then
+		tmp.Discount = 5;
+		Member.Discount = tmp.Discount;
+}
+
+test "gold member gets a discount" {
+	given {
+		Member.Tier = "gold";
+	}
+	expect fired("GoldDiscount") && Member.Discount == 5;
+}
+`
+
+func TestGrlTestBlocks_EngineSettings(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Members", "1.0.0", pkg.NewBytesResource([]byte(ProfiledMemberRules))))
+	newDataContext := func() (ast.IDataContext, error) {
+		dataContext := ast.NewDataContext()
+
+		return dataContext, dataContext.Add("Member", &Member{})
+	}
+
+	// the rules of the test run with the profiles and the scratchpad of the engine.
+	eng := engine.NewGruleEngine()
+	results, err := eng.RunTests(context.Background(), lib, "Members", "1.0.0", newDataContext)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.NoError(t, results[0].Err)
+		assert.False(t, results[0].Passed)
+	}
+	eng.Profiles = []string{"loyalty"}
+
+	// the runs do not share the working memory of the test, they may run at once.
+	var wg sync.WaitGroup
+	passed := make([]bool, 8)
+	for i := range passed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results, err := eng.RunTests(context.Background(), lib, "Members", "1.0.0", newDataContext)
+			passed[i] = err == nil && len(results) == 1 && results[0].Err == nil && results[0].Passed
+		}(i)
+	}
+	wg.Wait()
+	for _, ok := range passed {
+		assert.True(t, ok)
+	}
+}
+
+func TestGrlTestBlockErrors(t *testing.T) {
+	testData := []string{
+		`tset "typo" { expect true; }`,
+		`test "typo" { gvien { Member.Tier = "gold"; } expect true; }`,
+		`test "dup" { expect true; } test "dup" { expect false; }`,
+	}
+	for _, grl := range testData {
+		lib := ast.NewKnowledgeLibrary()
+		rb := builder.NewRuleBuilder(lib)
+		err := rb.BuildRuleFromResource("Members", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+}
@@ -0,0 +1,156 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Claim struct {
+	Amount   int64
+	Decision string
+	Audited  bool
+	Fired    int64
+}
+
+const ClaimRules = `
+rule RejectLarge "large claims are rejected" salience 10 {
+	when
+		Claim.Amount > 1000 && Claim.Decision == ""
+	then
+		Claim.Decision = "reject";
+		Claim.Fired = Claim.Fired + 1;
+}
+
+rule ApproveSmall "small claims are approved" salience 10 {
+	when
+		Claim.Amount <= 1000 && Claim.Decision == ""
+	then
+		Claim.Decision = "approve";
+		Claim.Fired = Claim.Fired + 1;
+}
+
+rule Audit "every claim is audited" salience -10 {
+	when
+		Claim.Audited == false
+	then
+		Claim.Audited = true;
+		Claim.Fired = Claim.Fired + 1;
+}
+`
+
+func TestHaltWhen(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Claims", "1", pkg.NewBytesResource([]byte(ClaimRules))))
+	assert.NoError(t, rb.BuildRuleFromResource("Halting", "1", pkg.NewBytesResource([]byte(ClaimRules+`
+halt when Claim.Decision == "reject";`))))
+
+	// the halt condition survives a catalog round trip.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Halting", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	// and a JSON catalog round trip.
+	var doc bytes.Buffer
+	assert.NoError(t, lib.GetKnowledgeBase("Halting", "1").MakeCatalog().WriteCatalogToJSON(&doc))
+	cat := &ast.Catalog{}
+	assert.NoError(t, cat.ReadCatalogFromJSON(&doc))
+	fromJSON, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.Len(t, fromJSON.HaltEntries, 1)
+	assert.True(t, lib.GetKnowledgeBase("Halting", "1").IsIdentical(fromJSON))
+
+	testData := []struct {
+		amount   int64
+		decision string
+		audited  bool
+		fired    int64
+	}{
+		{amount: 5000, decision: "reject", audited: false, fired: 1},
+		{amount: 500, decision: "approve", audited: true, fired: 2},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			claim := &Claim{Amount: td.amount}
+			dataContext := ast.NewDataContext()
+			assert.NoError(t, dataContext.Add("Claim", claim))
+			kb, err := library.NewKnowledgeBaseInstance("Halting", "1")
+			assert.NoError(t, err)
+			assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+			assert.Equal(t, td.decision, claim.Decision)
+			assert.Equal(t, td.audited, claim.Audited)
+			assert.Equal(t, td.fired, claim.Fired)
+		}
+	}
+
+	// without the halt condition every rule fires.
+	claim := &Claim{Amount: 5000}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Claim", claim))
+	kb, err := lib.NewKnowledgeBaseInstance("Claims", "1")
+	assert.NoError(t, err)
+	assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+	assert.True(t, claim.Audited)
+	assert.Equal(t, int64(2), claim.Fired)
+}
+
+func TestHaltWhenStopWhen(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Claims", "1", pkg.NewBytesResource([]byte(ClaimRules))))
+
+	gruleEngine := engine.NewGruleEngine()
+	gruleEngine.StopWhen = func(dataCtx ast.IDataContext) bool {
+		claim := dataCtx.Get("Claim").Value().Interface().(*Claim)
+
+		return len(claim.Decision) > 0
+	}
+	claim := &Claim{Amount: 500}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Claim", claim))
+	kb, err := lib.NewKnowledgeBaseInstance("Claims", "1")
+	assert.NoError(t, err)
+	assert.NoError(t, gruleEngine.Execute(dataContext, kb))
+	assert.Equal(t, "approve", claim.Decision)
+	assert.False(t, claim.Audited)
+	assert.Equal(t, int64(1), claim.Fired)
+}
+
+func TestHaltWhenErrors(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Claims", "1", pkg.NewBytesResource([]byte(ClaimRules+`
+halt when Claim.Amount;`))))
+	kb, err := lib.NewKnowledgeBaseInstance("Claims", "1")
+	assert.NoError(t, err)
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Claim", &Claim{Amount: 500}))
+	err = engine.NewGruleEngine().Execute(dataContext, kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be a boolean expression")
+
+	err = builder.NewRuleBuilder(ast.NewKnowledgeLibrary()).BuildRuleFromResource("Claims", "1", pkg.NewBytesResource([]byte(`stop when Claim.Amount > 1;`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const inOperatorRules = `
+rule NorthAmerica "flags the north american shipments" {
+	when
+		Shipment.Country in ["US", "CA", "MX"] && !Shipment.NorthAmerica
+	then
+		Shipment.NorthAmerica = true;
+}
+
+rule Allowed "allows the shipments of an allowed code" {
+	when
+		Shipment.Code in AllowedCodes && !(Shipment.Zone in {1, 2}) && !Shipment.Allowed
+	then
+		Shipment.Allowed = true;
+}
+
+rule Discounted "tells the discounted tiers" {
+	when
+		Shipment.Tier in Shipment.Discounts && Shipment.Region == ""
+	then
+		Shipment.Region = match Shipment.Country { in ["US", "CA"] => "north", _ => "south" };
+}
+`
+
+type InShipment struct {
+	Country      string
+	Code         string
+	Zone         int
+	Tier         string
+	Discounts    map[string]float64
+	NorthAmerica bool
+	Allowed      bool
+	Region       string
+}
+
+func TestInOperator(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("In", "0.0.1", pkg.NewBytesResource([]byte(inOperatorRules))))
+
+	// the in operator survives a binary and a JSON catalog round trip.
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(buffer, "In", "0.0.1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(buffer, true)
+	assert.NoError(t, err)
+
+	var doc bytes.Buffer
+	assert.NoError(t, lib.GetKnowledgeBase("In", "0.0.1").MakeCatalog().WriteCatalogToJSON(&doc))
+	cat := &ast.Catalog{}
+	assert.NoError(t, cat.ReadCatalogFromJSON(&doc))
+	fromJSON, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.True(t, lib.GetKnowledgeBase("In", "0.0.1").IsIdentical(fromJSON))
+
+	discounts := map[string]float64{"gold": 0.2, "silver": 0.1}
+	testData := []struct {
+		shipment     InShipment
+		northAmerica bool
+		allowed      bool
+		region       string
+	}{
+		{shipment: InShipment{Country: "US", Code: "A1", Zone: 3, Tier: "gold"}, northAmerica: true, allowed: true, region: "north"},
+		{shipment: InShipment{Country: "MX", Code: "B2", Zone: 1, Tier: "bronze"}, northAmerica: true},
+		{shipment: InShipment{Country: "FR", Code: "Z9", Zone: 4, Tier: "silver"}, region: "south"},
+		{shipment: InShipment{Country: "us", Code: "B2", Zone: 5}, allowed: true},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			kb, err := library.NewKnowledgeBaseInstance("In", "0.0.1")
+			assert.NoError(t, err)
+			shipment := td.shipment
+			shipment.Discounts = discounts
+			dctx := ast.NewDataContext()
+			assert.NoError(t, dctx.Add("Shipment", &shipment))
+			assert.NoError(t, dctx.Add("AllowedCodes", []string{"A1", "B2"}))
+			assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+			assert.Equal(t, td.northAmerica, shipment.NorthAmerica, td.shipment.Country)
+			assert.Equal(t, td.allowed, shipment.Allowed, td.shipment.Country)
+			assert.Equal(t, td.region, shipment.Region, td.shipment.Country)
+		}
+	}
+}
+
+func TestInOperator_Invalid(t *testing.T) {
+	for _, when := range []string{
+		`Shipment.Country in ["US": 1]`,
+		`Shipment.Country in ["US", Shipment.Code]`,
+		`Shipment.Country in`,
+	} {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule NorthAmerica { when ` + when + ` then Shipment.NorthAmerica = true; }`
+		assert.Error(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("In", "0.0.1", pkg.NewBytesResource([]byte(grl))), when)
+	}
+
+	// the right hand side must be an array, a slice, a map or a collection.
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule NorthAmerica { when Shipment.Country in Shipment.Code then Shipment.NorthAmerica = true; }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("In", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("In", "0.0.1")
+	assert.NoError(t, err)
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Shipment", &InShipment{Country: "US", Code: "US"}))
+	eng := engine.NewGruleEngine()
+	eng.ReturnErrOnFailedRuleEvaluation = true
+	assert.Error(t, eng.Execute(dctx, kb))
+}
+
+func TestInOperator_NotReserved(t *testing.T) {
+	// in is an operator between two expressions only, facts, fields and functions may still be named after it.
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule NorthAmerica { when in.Country IN ["US", "CA"] && in.Code.In("A1", "B2") && !in.NorthAmerica then in.NorthAmerica = true; }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("In", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("In", "0.0.1")
+	assert.NoError(t, err)
+
+	shipment := &InShipment{Country: "CA", Code: "B2"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("in", shipment))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+	assert.True(t, shipment.NorthAmerica)
+}
+
+type InCodes struct {
+	Codes   []string
+	Checked bool
+}
+
+func TestInOperator_RuleAndVariableNames(t *testing.T) {
+	// in may also name a rule, a collection and the variable of a quantifier.
+	lib := ast.NewKnowledgeLibrary()
+	grl := `
+rule In "d" salience 10 {
+	when
+		exists(Shipment.Codes, in -> in == "B2") && !Shipment.Checked
+	then
+		collect in from Shipment.Codes where item != "B2";
+		Shipment.Checked = true;
+}
+
+rule Others "counts the other codes" {
+	when
+		in.Len() == 2 && Shipment.Checked
+	then
+		Retract("Others");
+}`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("In", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("In", "0.0.1")
+	assert.NoError(t, err)
+	assert.NotNil(t, kb.RuleEntries["In"])
+
+	codes := &InCodes{Codes: []string{"A1", "B2", "C3"}}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Shipment", codes))
+	eng := engine.NewGruleEngine()
+	assert.NoError(t, eng.Execute(dctx, kb))
+	assert.True(t, codes.Checked)
+	assert.True(t, kb.RuleEntries["Others"].Retracted)
+}
@@ -70,3 +70,78 @@ then
 	err = eng.Execute(dataContext, kb)
 	assert.NoError(t, err)
 }
+
+type PaymentProvider interface {
+	Charge(amount int64) string
+}
+
+type StripeProvider struct {
+	Charged int64
+}
+
+func (s *StripeProvider) Charge(amount int64) string {
+	s.Charged += amount
+
+	return "stripe"
+}
+
+type VoucherProvider string
+
+func (v VoucherProvider) Charge(amount int64) string {
+
+	return "voucher " + string(v)
+}
+
+type PaymentOrder struct {
+	Amount   int64
+	Receipt  string
+	Provider StripeProvider
+}
+
+func TestInterfaceFact(t *testing.T) {
+	rule := `rule Pay "charges the order" {
+when
+	Order.Receipt == ""
+then
+	Order.Receipt = Provider.Charge(Order.Amount);
+	Retract("Pay");
+}`
+	lib := ast.NewKnowledgeLibrary()
+	err := builder.NewRuleBuilder(lib).BuildRuleFromResource("PaymentTest", "0.1.1", pkg.NewBytesResource([]byte(rule)))
+	assert.NoError(t, err)
+	eng := &engine.GruleEngine{MaxCycle: 3, ReturnErrOnFailedRuleEvaluation: true}
+
+	stripe := &StripeProvider{}
+	var byConcreteType PaymentProvider = stripe
+	var byStringType PaymentProvider = VoucherProvider("XMAS")
+	for _, provider := range []interface{}{byConcreteType, byStringType, &byConcreteType, &byStringType} {
+		order := &PaymentOrder{Amount: 100}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Order", order))
+		assert.NoError(t, dataContext.Add("Provider", provider))
+		kb, err := lib.NewKnowledgeBaseInstance("PaymentTest", "0.1.1")
+		assert.NoError(t, err)
+		assert.NoError(t, eng.Execute(dataContext, kb))
+		assert.NotEmpty(t, order.Receipt)
+	}
+	assert.Equal(t, int64(200), stripe.Charged)
+
+	// the pointer receiver method of a struct field is called on the field itself.
+	rule = `rule PayField "charges the order with its provider" {
+when
+	Order.Receipt == ""
+then
+	Order.Receipt = Order.Provider.Charge(Order.Amount);
+	Retract("PayField");
+}`
+	err = builder.NewRuleBuilder(lib).BuildRuleFromResource("PaymentFieldTest", "0.1.1", pkg.NewBytesResource([]byte(rule)))
+	assert.NoError(t, err)
+	order := &PaymentOrder{Amount: 100}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Order", order))
+	kb, err := lib.NewKnowledgeBaseInstance("PaymentFieldTest", "0.1.1")
+	assert.NoError(t, err)
+	assert.NoError(t, eng.Execute(dataContext, kb))
+	assert.Equal(t, "stripe", order.Receipt)
+	assert.Equal(t, int64(100), order.Provider.Charged)
+}
@@ -0,0 +1,105 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const tenantRule = `
+rule Discount {
+	when
+		Order.Total > 100 && Order.Discount == 0
+	then
+		Order.Discount = 10;
+}`
+
+type TenantOrder struct {
+	Total    int64
+	Discount int64
+}
+
+// collectedUsage runs the garbage collector until the live instances of the library drop to live.
+func collectedUsage(lib *ast.KnowledgeLibrary, live int64) []ast.KnowledgeBaseUsage {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runtime.GC()
+		usage := lib.Usage()
+		total := int64(0)
+		for _, u := range usage {
+			total += u.Live
+		}
+		if total <= live || time.Now().After(deadline) {
+
+			return usage
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestKnowledgeLibrary_RemoveKnowledgeBase(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("tenant-a", "1", pkg.NewBytesResource([]byte(tenantRule))))
+	assert.NoError(t, rb.BuildRuleFromResource("tenant-b", "1", pkg.NewBytesResource([]byte(tenantRule))))
+
+	execute := func(name string) *ast.KnowledgeBase {
+		kb, err := lib.NewKnowledgeBaseInstance(name, "1")
+		assert.NoError(t, err)
+		order := &TenantOrder{Total: 200}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Order", order))
+		assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+		assert.Equal(t, int64(10), order.Discount)
+
+		return kb
+	}
+	for i := 0; i < 3; i++ {
+		execute("tenant-a")
+	}
+	kept := execute("tenant-b")
+
+	assert.Equal(t, []ast.KnowledgeBaseUsage{
+		{Name: "tenant-a", Version: "1", Loaded: true, Created: 3, Live: 0},
+		{Name: "tenant-b", Version: "1", Loaded: true, Created: 1, Live: 1},
+	}, collectedUsage(lib, 1))
+
+	assert.True(t, lib.RemoveKnowledgeBase("tenant-a", "1"))
+	assert.True(t, lib.RemoveKnowledgeBase("tenant-b", "1"))
+	assert.False(t, lib.RemoveKnowledgeBase("tenant-b", "1"))
+	_, err := lib.NewKnowledgeBaseInstance("tenant-a", "1")
+	assert.Error(t, err)
+
+	// the kept instance still works, and is reported until it is collected.
+	order := &TenantOrder{Total: 500}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Order", order))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kept))
+	assert.Equal(t, int64(10), order.Discount)
+	assert.Equal(t, []ast.KnowledgeBaseUsage{
+		{Name: "tenant-b", Version: "1", Loaded: false, Created: 1, Live: 1},
+	}, lib.Usage())
+
+	runtime.KeepAlive(kept)
+	assert.Empty(t, collectedUsage(lib, 0))
+}
@@ -0,0 +1,122 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type UpstreamInvoice struct {
+	AmountText string
+	DueText    string
+	Amount     float64
+	Due        time.Time
+	Summary    string
+}
+
+const localeRules = `
+rule ParseInvoice "Parse the German formatted invoice" {
+	when
+		Invoice.Summary == ""
+	then
+		Invoice.Amount = ParseNumber(Invoice.AmountText, "de-DE");
+		Invoice.Due = ParseDate(Invoice.DueText, "2. January 2006", "de-DE");
+		Invoice.Summary = FormatCurrency(Invoice.Amount, "IDR", "id-ID") + " jatuh tempo " + FormatDate(Invoice.Due, "02 January 2006", "id-ID");
+}`
+
+func TestLocaleFunctions(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Locale", "0.0.1", pkg.NewBytesResource([]byte(localeRules))))
+
+	invoice := &UpstreamInvoice{AmountText: "1.234.567,56", DueText: "3. März 2025"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Invoice", invoice))
+	kb, err := lib.NewKnowledgeBaseInstance("Locale", "0.0.1")
+	assert.NoError(t, err)
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+
+	assert.Equal(t, 1234567.56, invoice.Amount)
+	assert.Equal(t, time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC), invoice.Due)
+	assert.Equal(t, "Rp 1.234.568 jatuh tempo 03 Maret 2025", invoice.Summary)
+}
+
+func TestLocaleFunctions_Functions(t *testing.T) {
+	defunc := &ast.BuiltInFunctions{}
+
+	assert.Equal(t, 1234.56, defunc.ParseNumber("1,234.56", "en-US"))
+	assert.Equal(t, -1234.5, defunc.ParseNumber("-1 234,5", "fr-FR"))
+	assert.Equal(t, "1.234,50", defunc.FormatNumber(1234.5, 2, "de-DE"))
+	assert.Equal(t, "12,34,567", defunc.FormatNumber(1234567, 0, "hi-IN"))
+	assert.Equal(t, "$ 1,234.50", defunc.FormatCurrency(1234.5, "USD", "en-US"))
+
+	independence := time.Date(1945, time.August, 17, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Jum, 17 Agu 1945", defunc.FormatDate(independence, "Mon, 02 Jan 2006", "id-ID"))
+	assert.Equal(t, "Friday 17 August", defunc.FormatDate(independence, "Monday 02 January", "en"))
+	assert.Equal(t, independence.Truncate(24*time.Hour), defunc.ParseDate("Jumat, 17 Agustus 1945", "Monday, 02 January 2006", "id-ID"))
+
+	assert.Panics(t, func() {
+		defunc.ParseNumber("not a number", "de-DE")
+	})
+	assert.Panics(t, func() {
+		defunc.FormatDate(independence, "02 January 2006", "ja-JP")
+	})
+	assert.NoError(t, ast.RegisterDateNames("pt", ast.DateNames{
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	}))
+	assert.Equal(t, "17 de agosto de 1945", defunc.FormatDate(independence, "02 de January de 2006", "pt-BR"))
+}
+
+func TestLocaleFunctions_EngineDateNames(t *testing.T) {
+	grl := `rule ItalianDue { when Invoice.Summary == "" then Invoice.Summary = FormatDate(Invoice.Due, "02 January 2006", "it-IT"); }`
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Italian", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	dateNames := ast.NewDateNamesRegistry()
+	assert.NoError(t, dateNames.Register("it", ast.DateNames{
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	}))
+
+	execute := func(eng *engine.GruleEngine) (*UpstreamInvoice, error) {
+		invoice := &UpstreamInvoice{Due: time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Invoice", invoice))
+		kb, err := lib.NewKnowledgeBaseInstance("Italian", "0.0.1")
+		assert.NoError(t, err)
+
+		return invoice, eng.Execute(dctx, kb)
+	}
+	eng := engine.NewGruleEngine()
+	eng.DateNames = dateNames
+	invoice, err := execute(eng)
+	assert.NoError(t, err)
+	assert.Equal(t, "03 marzo 2025", invoice.Summary)
+
+	// the date names of an engine are not known to the others.
+	_, err = execute(engine.NewGruleEngine())
+	assert.Error(t, err)
+}
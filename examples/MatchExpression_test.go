@@ -0,0 +1,114 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Applicant struct {
+	Score   int64
+	Country string
+	Band    string
+	Limit   int64
+}
+
+const MatchRules = `
+rule Banding "band the applicant by score" {
+	when
+		Applicant.Band == ""
+	then
+		Applicant.Band = match Applicant.Score {
+			> 800 => "A",
+			> 650 => "B",
+			_ => "C",
+		};
+		Applicant.Limit = MATCH Applicant.Country { "ID" => 1000, "SG" => 2000 * 2, _ => 0 };
+		Retract("Banding");
+}
+`
+
+func TestMatchExpression(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Match", "1", pkg.NewBytesResource([]byte(MatchRules))))
+
+	// the match survives a catalog round trip.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Match", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	testData := []struct {
+		score   int64
+		country string
+		band    string
+		limit   int64
+	}{
+		{score: 900, country: "SG", band: "A", limit: 4000},
+		{score: 801, country: "ID", band: "A", limit: 1000},
+		{score: 800, country: "ID", band: "B", limit: 1000},
+		{score: 651, country: "US", band: "B", limit: 0},
+		{score: 650, country: "SG", band: "C", limit: 4000},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			applicant := &Applicant{Score: td.score, Country: td.country}
+			dataContext := ast.NewDataContext()
+			assert.NoError(t, dataContext.Add("Applicant", applicant))
+			kb, err := library.NewKnowledgeBaseInstance("Match", "1")
+			assert.NoError(t, err)
+			assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+			assert.Equal(t, td.band, applicant.Band, td.score)
+			assert.Equal(t, td.limit, applicant.Limit, td.country)
+		}
+	}
+}
+
+func TestMatchExpressionWithoutDefault(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	grl := `rule NoDefault { when Applicant.Band == "" then Applicant.Band = match Applicant.Score { >= 100 => "high" }; Retract("NoDefault"); }`
+	assert.NoError(t, rb.BuildRuleFromResource("Match", "1", pkg.NewBytesResource([]byte(grl))))
+
+	kb, err := lib.NewKnowledgeBaseInstance("Match", "1")
+	assert.NoError(t, err)
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Applicant", &Applicant{Score: 10}))
+	err = engine.NewGruleEngine().Execute(dataContext, kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no arm of")
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	testData := []string{
+		`rule A { when true then Applicant.Band = match Applicant.Score { _ => "C", > 1 => "A" }; }`,
+		`rule A { when true then Applicant.Band = matches Applicant.Score { _ => "C" }; }`,
+		`rule A { when true then Applicant.Band = match Applicant.Score { }; }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		err := rb.BuildRuleFromResource("Match", "1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+}
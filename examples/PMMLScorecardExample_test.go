@@ -0,0 +1,87 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const riskScorecard = `<PMML version="4.4">
+  <DataDictionary>
+    <DataField name="age" dataType="integer"/>
+    <DataField name="owns home" dataType="boolean"/>
+  </DataDictionary>
+  <Scorecard modelName="risk" initialScore="50">
+    <Characteristics>
+      <Characteristic name="age">
+        <Attribute partialScore="-10"><SimplePredicate field="age" operator="lessThan" value="25"/></Attribute>
+        <Attribute partialScore="20"><SimplePredicate field="age" operator="lessThan" value="60"/></Attribute>
+        <Attribute partialScore="5"><True/></Attribute>
+      </Characteristic>
+      <Characteristic name="home">
+        <Attribute partialScore="30"><SimplePredicate field="owns home" operator="equal" value="true"/></Attribute>
+      </Characteristic>
+    </Characteristics>
+  </Scorecard>
+</PMML>`
+
+type RiskApplicant struct {
+	Age       int64
+	OwnsHome  bool
+	Score     float64
+	HandScore string
+}
+
+const handWrittenRisk = `
+rule HighRisk "Hand written rule using the imported score" salience -10 {
+	when
+		Applicant.HandScore == "" && Applicant.Score < 60
+	then
+		Applicant.HandScore = "high risk";
+}`
+
+func TestPMMLScorecard(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	resource, err := pkg.NewPMMLResourceFromResource(pkg.NewBytesResource([]byte(riskScorecard)), pkg.PMMLOptions{
+		Fact:   "Applicant",
+		Target: "Applicant.Score",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, rb.BuildRuleFromResource("Risk", "0.0.1", resource))
+	assert.NoError(t, rb.BuildRuleFromResource("Risk", "0.0.1", pkg.NewBytesResource([]byte(handWrittenRisk))))
+
+	score := func(applicant *RiskApplicant) *RiskApplicant {
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Applicant", applicant))
+		kb, err := lib.NewKnowledgeBaseInstance("Risk", "0.0.1")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+
+		return applicant
+	}
+
+	assert.Equal(t, 100.0, score(&RiskApplicant{Age: 40, OwnsHome: true}).Score)
+	assert.Equal(t, 55.0, score(&RiskApplicant{Age: 70, OwnsHome: false}).Score)
+	young := score(&RiskApplicant{Age: 20})
+	assert.Equal(t, 40.0, young.Score)
+	assert.Equal(t, "high risk", young.HandScore)
+}
@@ -0,0 +1,122 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type ProfileOrder struct {
+	Vat      float64
+	Shipping string
+	Checked  bool
+}
+
+const ProfileRules = `
+@profile("eu-only")
+rule EuVat {
+	when
+		Order.Vat == 0
+	then
+		Order.Vat = 0.2;
+}
+@profile("us-only", "ca-only") @profile("intl")
+rule PostalShipping {
+	when
+		Order.Shipping == ""
+	then
+		Order.Shipping = "postal";
+}
+rule Check {
+	when
+		!Order.Checked
+	then
+		Order.Checked = true;
+}
+`
+
+func TestProfile(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Profile", "1", pkg.NewBytesResource([]byte(ProfileRules))))
+	kb, err := lib.NewKnowledgeBaseInstance("Profile", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"us-only", "ca-only", "intl"}, kb.RuleEntries["PostalShipping"].Profiles)
+
+	testData := []struct {
+		profiles []string
+		vat      float64
+		shipping string
+	}{
+		{nil, 0, ""},
+		{[]string{"eu-only"}, 0.2, ""},
+		{[]string{"intl"}, 0, "postal"},
+		{[]string{"ca-only", "eu-only"}, 0.2, "postal"},
+	}
+	for _, td := range testData {
+		order := &ProfileOrder{}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Order", order))
+		eng := engine.NewGruleEngine()
+		eng.Profiles = td.profiles
+		assert.NoError(t, eng.Execute(dataContext, kb))
+		assert.Equal(t, td.vat, order.Vat, td.profiles)
+		assert.Equal(t, td.shipping, order.Shipping, td.profiles)
+		assert.True(t, order.Checked, td.profiles)
+	}
+
+	// the profiles survive a catalog round trip and apply to the matching rules.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Profile", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+	kb, err = loaded.NewKnowledgeBaseInstance("Profile", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eu-only"}, kb.RuleEntries["EuVat"].Profiles)
+
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Order", &ProfileOrder{}))
+	eng := engine.NewGruleEngine()
+	eng.Profiles = []string{"eu-only"}
+	matching, err := eng.FetchMatchingRules(dataContext, kb)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(matching))
+	for _, rule := range matching {
+		names = append(names, rule.RuleName)
+	}
+	assert.ElementsMatch(t, []string{"EuVat", "Check"}, names)
+}
+
+func TestProfileErrors(t *testing.T) {
+	testData := []string{
+		`@profiles("eu") rule A { when true then Retract("A"); }`,
+		`@profile("") rule A { when true then Retract("A"); }`,
+		`@profile() rule A { when true then Retract("A"); }`,
+		`rule A @profile("eu") { when true then Retract("A"); }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		err := rb.BuildRuleFromResource("Profile", "1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+}
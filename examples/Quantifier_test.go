@@ -0,0 +1,157 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const quantifierRules = `
+rule LargeOrder "flags a customer having a large order" {
+	when
+		exists(Customer.Orders, o -> o.Amount > 1000) && !Customer.Large
+	then
+		Customer.Large = true;
+}
+
+rule AllPaid "trusts a customer having paid every order" {
+	when
+		FORALL(Customer.Orders, o -> o.Paid && o.Amount > 0) && !Customer.Trusted
+	then
+		Customer.Trusted = true;
+}
+
+rule Mixed "flags a customer having both paid and unpaid orders" {
+	when
+		exists(Customer.Orders, o -> o.Paid) && !forall(Customer.Orders, o -> o.Paid) && !Customer.Mixed
+	then
+		Customer.Mixed = true;
+}
+`
+
+type QuantifierOrder struct {
+	Amount float64
+	Paid   bool
+}
+
+type QuantifierCustomer struct {
+	Orders  []*QuantifierOrder
+	Large   bool
+	Trusted bool
+	Mixed   bool
+}
+
+func TestQuantifier(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Quantifier", "0.0.1", pkg.NewBytesResource([]byte(quantifierRules))))
+
+	// the quantifiers survive a binary and a JSON catalog round trip.
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(buffer, "Quantifier", "0.0.1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(buffer, true)
+	assert.NoError(t, err)
+
+	var doc bytes.Buffer
+	assert.NoError(t, lib.GetKnowledgeBase("Quantifier", "0.0.1").MakeCatalog().WriteCatalogToJSON(&doc))
+	cat := &ast.Catalog{}
+	assert.NoError(t, cat.ReadCatalogFromJSON(&doc))
+	fromJSON, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.True(t, lib.GetKnowledgeBase("Quantifier", "0.0.1").IsIdentical(fromJSON))
+
+	testData := []struct {
+		name    string
+		orders  []*QuantifierOrder
+		large   bool
+		trusted bool
+		mixed   bool
+	}{
+		{name: "empty", orders: []*QuantifierOrder{}, trusted: true},
+		{name: "small paid", orders: []*QuantifierOrder{{Amount: 10, Paid: true}, {Amount: 20, Paid: true}}, trusted: true},
+		{name: "large unpaid", orders: []*QuantifierOrder{{Amount: 10, Paid: true}, {Amount: 2000}}, large: true, mixed: true},
+		{name: "large paid", orders: []*QuantifierOrder{{Amount: 1500, Paid: true}}, large: true, trusted: true},
+		{name: "zero paid", orders: []*QuantifierOrder{{Amount: 0, Paid: true}}},
+	}
+	// the parallel evaluation reads the facts through a read only snapshot.
+	parallel := engine.NewGruleEngine()
+	parallel.ParallelEvaluation = 2
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, eng := range []*engine.GruleEngine{engine.NewGruleEngine(), parallel} {
+			for _, td := range testData {
+				kb, err := library.NewKnowledgeBaseInstance("Quantifier", "0.0.1")
+				assert.NoError(t, err)
+				customer := &QuantifierCustomer{Orders: td.orders}
+				dctx := ast.NewDataContext()
+				assert.NoError(t, dctx.Add("Customer", customer))
+				assert.NoError(t, eng.Execute(dctx, kb))
+				assert.Equal(t, td.large, customer.Large, td.name)
+				assert.Equal(t, td.trusted, customer.Trusted, td.name)
+				assert.Equal(t, td.mixed, customer.Mixed, td.name)
+			}
+		}
+	}
+}
+
+func TestQuantifier_BoundNameShadowsFact(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule Large { when exists(Customer.Orders, Customer -> Customer.Amount > 1000) && !Customer.Large then Customer.Large = true; }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Quantifier", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Quantifier", "0.0.1")
+	assert.NoError(t, err)
+
+	customer := &QuantifierCustomer{Orders: []*QuantifierOrder{{Amount: 2000}}}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Customer", customer))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+	assert.True(t, customer.Large)
+	assert.NotNil(t, dctx.Get("Customer"))
+}
+
+func TestQuantifier_Invalid(t *testing.T) {
+	for _, when := range []string{
+		`any(Customer.Orders, o -> o.Paid)`,
+		`exists(Customer.Orders, o => o.Paid)`,
+		`exists(Customer.Orders, o -> )`,
+	} {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Paid { when ` + when + ` then Customer.Trusted = true; }`
+		assert.Error(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Quantifier", "0.0.1", pkg.NewBytesResource([]byte(grl))), when)
+	}
+
+	// the source must be an array or a slice and the predicate a boolean expression.
+	for _, when := range []string{
+		`exists(Customer.Large, o -> o.Paid)`,
+		`forall(Customer.Orders, o -> o.Amount)`,
+	} {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Paid { when ` + when + ` then Customer.Trusted = true; }`
+		assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Quantifier", "0.0.1", pkg.NewBytesResource([]byte(grl))), when)
+		kb, err := lib.NewKnowledgeBaseInstance("Quantifier", "0.0.1")
+		assert.NoError(t, err)
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Customer", &QuantifierCustomer{Orders: []*QuantifierOrder{{Amount: 1}}}))
+		eng := engine.NewGruleEngine()
+		eng.ReturnErrOnFailedRuleEvaluation = true
+		assert.Error(t, eng.Execute(dctx, kb), when)
+	}
+}
@@ -0,0 +1,177 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Parcel struct {
+	Price    float64
+	Discount float64
+	Weight   pkg.Quantity
+	Total    pkg.Quantity
+	Heavy    bool
+	Modulo   int64
+	Negative int64
+}
+
+const QuantityRule = `
+rule ParcelRule "Quantities" {
+	when
+		Parcel.Weight > 1500g && !Parcel.Heavy
+	then
+		Parcel.Heavy = true;
+		Parcel.Discount = Parcel.Price * 10%;
+		Parcel.Total = Parcel.Weight.Value * 2.5 USD + 100 USD;
+		Parcel.Weight = Parcel.Weight + 500g;
+		Parcel.Modulo = 10 % 3;
+		Parcel.Negative = 10 % -3;
+}
+`
+
+func executeQuantityRule(t *testing.T, grl string, parcel *Parcel) error {
+	dataContext := ast.NewDataContext()
+	err := dataContext.Add("Parcel", parcel)
+	assert.NoError(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err = rb.BuildRuleFromResource("TestQuantity", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	if err != nil {
+
+		return err
+	}
+
+	// run from a catalog round trip so the quantity constants are serialized too.
+	var buff bytes.Buffer
+	err = lib.StoreKnowledgeBaseToWriter(&buff, "TestQuantity", "1.0.0")
+	assert.NoError(t, err)
+	loaded := ast.NewKnowledgeLibrary()
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	kb, err := loaded.NewKnowledgeBaseInstance("TestQuantity", "1.0.0")
+	assert.NoError(t, err)
+	eng := &engine.GruleEngine{MaxCycle: 5, ReturnErrOnFailedRuleEvaluation: true}
+
+	return eng.Execute(dataContext, kb)
+}
+
+func TestQuantityLiterals(t *testing.T) {
+	parcel := &Parcel{
+		Price:  80,
+		Weight: pkg.Quantity{Value: 2, Unit: "kg"},
+	}
+	err := executeQuantityRule(t, QuantityRule, parcel)
+	assert.NoError(t, err)
+	assert.True(t, parcel.Heavy)
+	assert.Equal(t, float64(8), parcel.Discount)
+	assert.Equal(t, pkg.Quantity{Value: 105, Unit: "USD"}, parcel.Total)
+	assert.Equal(t, pkg.Quantity{Value: 2.5, Unit: "kg"}, parcel.Weight)
+	assert.Equal(t, int64(1), parcel.Modulo)
+	assert.Equal(t, int64(1), parcel.Negative)
+}
+
+func TestQuantityMixedUnits(t *testing.T) {
+	testData := []struct {
+		expression string
+		errorText  string
+	}{
+		{"Parcel.Weight > 5 USD", "units do not match"},
+		{"Parcel.Weight > 5", "units do not match"},
+		{"Parcel.Price + 10% > 0", "multiply it"},
+		{"1 USD + 1 EUR > 0 USD", "units do not match"},
+		{"Parcel.Weight * 2kg > 0kg", "units do not match"},
+	}
+	for _, td := range testData {
+		grl := `rule Mixed { when ` + td.expression + ` then Retract("Mixed"); }`
+		err := executeQuantityRule(t, grl, &Parcel{Weight: pkg.Quantity{Value: 1, Unit: "kg"}})
+		assert.Error(t, err, td.expression)
+		if err != nil {
+			assert.Contains(t, err.Error(), td.errorText, td.expression)
+		}
+	}
+
+	err := executeQuantityRule(t, `rule Unknown { when Parcel.Price > 5 parsecs then Retract("Unknown"); }`, &Parcel{})
+	reporter, ok := err.(*pkg.GruleErrorReporter)
+	if assert.True(t, ok) && assert.Len(t, reporter.Errors, 1) {
+		assert.Contains(t, reporter.Errors[0].Error(), "unknown unit parsecs")
+	}
+}
+
+func TestQuantityAssignment(t *testing.T) {
+	// a percentage assigned to a number is the fraction it is of 100.
+	parcel := &Parcel{}
+	assert.NoError(t, executeQuantityRule(t, `rule Percent { when Parcel.Discount == 0 then Parcel.Discount = 15%; }`, parcel))
+	assert.Equal(t, 0.15, parcel.Discount)
+
+	for _, then := range []string{`Parcel.Price = 100 USD;`, `Parcel.Modulo = 2kg;`} {
+		grl := `rule Money { when Parcel.Price == 0 then ` + then + ` }`
+		err := executeQuantityRule(t, grl, &Parcel{})
+		var assignErr *ast.QuantityAssignmentError
+		if assert.ErrorAs(t, err, &assignErr, then) {
+			assert.Equal(t, "Money", assignErr.Rule)
+		}
+	}
+
+	err := executeQuantityRule(t, `rule Money { when Parcel.Price == 0 then Parcel.Price = 100 USD; }`, &Parcel{})
+	assert.EqualError(t, err, "error while executing rule Money. got rule Money can not assign a quantity in USD to Parcel.Price, which is not a pkg.Quantity")
+}
+
+type Trip struct {
+	Distance pkg.Quantity
+	Far      bool
+}
+
+func TestQuantityLibraryUnits(t *testing.T) {
+	grl := `rule FarTrip { when Trip.Distance > 2league && !Trip.Far then Trip.Far = true; Trip.Distance = Trip.Distance + 1league; }`
+	units := pkg.NewUnitRegistry()
+	assert.NoError(t, units.Register("league", "length", 4828.032))
+
+	// the units of a library are not known to the others.
+	err := builder.NewRuleBuilder(ast.NewKnowledgeLibrary()).BuildRuleFromResource("Trip", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	assert.Error(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	lib.Units = units
+	err = builder.NewRuleBuilder(lib).BuildRuleFromResource("Trip", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	assert.NoError(t, err)
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Trip", "1.0.0"))
+	loaded := ast.NewKnowledgeLibrary()
+	loaded.Units = units
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		kb, err := library.NewKnowledgeBaseInstance("Trip", "1.0.0")
+		assert.NoError(t, err)
+		trip := &Trip{Distance: pkg.Quantity{Value: 3, Unit: "league"}}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Trip", trip))
+		eng := &engine.GruleEngine{MaxCycle: 5, ReturnErrOnFailedRuleEvaluation: true}
+		assert.NoError(t, eng.Execute(dataContext, kb))
+		assert.True(t, trip.Far)
+		assert.Equal(t, pkg.Quantity{Value: 4, Unit: "league"}, trip.Distance)
+	}
+}
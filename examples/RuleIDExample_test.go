@@ -0,0 +1,124 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Account struct {
+	Id      string
+	Balance int64
+	Status  string
+}
+
+const RuleIDRulesV1 = `
+rule LowBalance "balance is low" id "R-000123" salience 10 {
+	when
+		Account.Status == "" && Account.Balance < 100 && Account.Id != ""
+	then
+		Account.Status = "low";
+		RecordOutcome("low");
+}
+rule Obsolete {
+	when
+		false
+	then
+		Retract("Obsolete");
+}
+`
+
+const RuleIDRulesV2 = `
+rule BalanceBelowMinimum "balance is low" ID 'R-000123' salience 10 {
+	when
+		Account.Status == "" && Account.Balance < 100 && Account.Id != ""
+	then
+		Account.Status = "low";
+		RecordOutcome("low");
+}
+rule HighBalance id "R-000200" {
+	when
+		Account.Status == "" && Account.Balance > 1000
+	then
+		Account.Status = "high";
+}
+`
+
+func buildRuleIDRules(t *testing.T, lib *ast.KnowledgeLibrary, version, grl string) *ast.KnowledgeBase {
+	rb := builder.NewRuleBuilder(lib)
+	err := rb.BuildRuleFromResource("RuleID", version, pkg.NewBytesResource([]byte(grl)))
+	assert.NoError(t, err)
+
+	return lib.GetKnowledgeBase("RuleID", version)
+}
+
+func TestRuleID(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	v1 := buildRuleIDRules(t, lib, "1", RuleIDRulesV1)
+	v2 := buildRuleIDRules(t, lib, "2", RuleIDRulesV2)
+	assert.Equal(t, "R-000123", v1.RuleEntries["LowBalance"].RuleID)
+	assert.Equal(t, "Obsolete", v1.RuleEntries["Obsolete"].StableID())
+	assert.Equal(t, "BalanceBelowMinimum", v2.RuleEntryByID("R-000123").RuleName)
+
+	// the id survives a catalog round trip and shows up in the outcome counters.
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "RuleID", "2"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+	kb, err := loaded.NewKnowledgeBaseInstance("RuleID", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "R-000200", kb.RuleEntries["HighBalance"].RuleID)
+
+	account := &Account{Id: "A1", Balance: 50}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Account", account))
+	eng := engine.NewGruleEngine()
+	assert.NoError(t, eng.Execute(dataContext, kb))
+	assert.Equal(t, "low", account.Status)
+	assert.Equal(t, []engine.OutcomeCount{
+		{OutcomeKey: engine.OutcomeKey{KnowledgeBase: "RuleID", Version: "2", RuleID: "R-000123", RuleName: "BalanceBelowMinimum", Label: "low"}, Count: 1},
+	}, eng.Metrics.Outcomes())
+
+	changes := ast.DiffKnowledgeBases(v1, v2)
+	if assert.Len(t, changes, 3) {
+		assert.Equal(t, "removed Obsolete (Obsolete)", changes[0].String())
+		assert.True(t, changes[1].Renamed)
+		assert.False(t, changes[1].Modified)
+		assert.Equal(t, "renamed LowBalance to BalanceBelowMinimum (R-000123)", changes[1].String())
+		assert.True(t, changes[2].Added())
+		assert.Equal(t, "R-000200", changes[2].RuleID)
+	}
+}
+
+func TestRuleIDErrors(t *testing.T) {
+	testData := []string{
+		`rule A idx "R-1" { when true then Retract("A"); }`,
+		`rule A id "" { when true then Retract("A"); }`,
+		`rule A id "R-1" { when true then Retract("A"); } rule B id "R-1" { when true then Retract("B"); }`,
+	}
+	for _, grl := range testData {
+		rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+		err := rb.BuildRuleFromResource("RuleID", "1", pkg.NewBytesResource([]byte(grl)))
+		assert.Error(t, err, grl)
+	}
+}
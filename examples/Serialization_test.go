@@ -16,6 +16,7 @@ package examples
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 	"github.com/hyperjumptech/grule-rule-engine/builder"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
@@ -89,3 +90,61 @@ func TestSerializationOnFile(t *testing.T) {
 	// compare that the original knowledgebase is exacly the same to the loaded one.
 	assert.True(t, lib.GetKnowledgeBase("Purchase Calculator", "0.0.1").IsIdentical(kb2))
 }
+
+const jsonCatalogRules = `
+rule Weigh "weigh the parcel" salience 5 cooldown 1m criticality high {
+	when
+		Parcel.Weight > 500g && !Parcel.Heavy && Parcel.Label != nil
+	then
+		Parcel.Heavy = true;
+		Parcel.Price += 2.5;
+		Parcel.Band = match Parcel.Price { >= 10 => "A", _ => "B" };
+		Retract("Weigh");
+}`
+
+func TestSerializationJSON(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Purchase Calculator", "0.0.1", pkg.NewFileResource("CashFlowRule.grl")))
+	assert.NoError(t, rb.BuildRuleFromResource("Purchase Calculator", "0.0.1", pkg.NewBytesResource([]byte(jsonCatalogRules))))
+
+	kb := lib.GetKnowledgeBase("Purchase Calculator", "0.0.1")
+	cat := kb.MakeCatalog()
+	buff := &bytes.Buffer{}
+	assert.NoError(t, cat.WriteCatalogToJSON(buff))
+
+	// the document is plain JSON, readable without Grule.
+	var doc struct {
+		Format string `json:"format"`
+		Nodes  []struct {
+			Type       string                 `json:"type"`
+			Attributes map[string]interface{} `json:"attributes"`
+			Literal    map[string]interface{} `json:"literal"`
+		} `json:"nodes"`
+	}
+	assert.NoError(t, json.Unmarshal(buff.Bytes(), &doc))
+	assert.Equal(t, ast.CatalogJSONFormat, doc.Format)
+	literals := make([]map[string]interface{}, 0)
+	var weigh map[string]interface{}
+	for _, node := range doc.Nodes {
+		if node.Literal != nil {
+			literals = append(literals, node.Literal)
+		}
+		if node.Type == "RuleEntry" && node.Attributes["ruleName"] == "Weigh" {
+			weigh = node.Attributes
+		}
+	}
+	assert.Contains(t, literals, map[string]interface{}{"type": "quantity", "value": 500.0, "unit": "g"})
+	assert.Contains(t, literals, map[string]interface{}{"type": "nil"})
+	assert.Equal(t, map[string]interface{}{"ruleName": "Weigh", "ruleDescription": "weigh the parcel", "salience": 5.0, "cooldownNanoseconds": 6e10, "criticality": "high"}, weigh)
+
+	cat2 := &ast.Catalog{}
+	assert.NoError(t, cat2.ReadCatalogFromJSON(bytes.NewReader(buff.Bytes())))
+	assert.True(t, cat.Equals(cat2))
+	kb2, err := cat2.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.True(t, kb.IsIdentical(kb2))
+
+	err = (&ast.Catalog{}).ReadCatalogFromJSON(bytes.NewReader([]byte(`{"format":"grule-catalog","version":"99"}`)))
+	assert.ErrorIs(t, err, ast.ErrIncompatibleCatalog)
+}
@@ -0,0 +1,74 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type StringNumberFact struct {
+	Code   string
+	Result string
+}
+
+func TestStringNumberComparison(t *testing.T) {
+	testData := []struct {
+		condition string
+		code      string
+		mode      pkg.StringNumberComparison
+		result    string
+		fails     bool
+	}{
+		{condition: `Fact.Code == 42`, code: "42", mode: pkg.StringNumberUnchecked, result: ""},
+		{condition: `42 == Fact.Code`, code: "42", mode: pkg.StringNumberUnchecked, fails: true},
+		{condition: `Fact.Code > 10`, code: "9", mode: pkg.StringNumberUnchecked, fails: true},
+		{condition: `Fact.Code == 42`, code: "42", mode: pkg.StringNumberError, fails: true},
+		{condition: `Fact.Code == "42"`, code: "42", mode: pkg.StringNumberError, result: "matched"},
+		{condition: `Fact.Code > 10`, code: "9", mode: pkg.StringNumberLexical, result: "matched"},
+		{condition: `10 < Fact.Code`, code: "9", mode: pkg.StringNumberLexical, result: "matched"},
+		{condition: `Fact.Code == 42`, code: "42", mode: pkg.StringNumberLexical, result: "matched"},
+		{condition: `Fact.Code > 10`, code: "9", mode: pkg.StringNumberNumeric, result: ""},
+		{condition: `Fact.Code >= 9.5`, code: " 9.5 ", mode: pkg.StringNumberNumeric, result: "matched"},
+		{condition: `42 == Fact.Code`, code: "42", mode: pkg.StringNumberNumeric, result: "matched"},
+		{condition: `Fact.Code == 42`, code: "forty two", mode: pkg.StringNumberNumeric, fails: true},
+	}
+	for _, td := range testData {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Compare { when Fact.Result == "" && ` + td.condition + ` then Fact.Result = "matched"; }`
+		assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("StringNumber", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+		kb, err := lib.NewKnowledgeBaseInstance("StringNumber", "0.0.1")
+		assert.NoError(t, err)
+
+		fact := &StringNumberFact{Code: td.code}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Fact", fact))
+		eng := engine.NewGruleEngine()
+		eng.ReturnErrOnFailedRuleEvaluation = true
+		eng.StringNumberComparison = td.mode
+		err = eng.Execute(dctx, kb)
+		if td.fails {
+			assert.Error(t, err, "%s with %q in %s mode", td.condition, td.code, td.mode)
+		} else {
+			assert.NoError(t, err, "%s with %q in %s mode", td.condition, td.code, td.mode)
+			assert.Equal(t, td.result, fact.Result, "%s with %q in %s mode", td.condition, td.code, td.mode)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+type Invoice struct {
+	Due     time.Time
+	Fee     Money
+	Overdue bool
+}
+
+const SuffixLiteralRule = `
+rule OverdueRule "Suffix literals" {
+	when
+		Invoice.Due < 2024-05-01_D && !Invoice.Overdue
+	then
+		Invoice.Overdue = true;
+		Invoice.Fee = 12.50_EURO;
+}
+`
+
+func init() {
+	_ = pkg.RegisterLiteralSuffix("D", func(text string) (interface{}, error) {
+
+		return time.Parse("2006-01-02", text)
+	})
+	_ = pkg.RegisterLiteralSuffix("EURO", func(text string) (interface{}, error) {
+		amount, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return Money{Cents: int64(amount * 100), Currency: "EUR"}, nil
+	})
+}
+
+func executeSuffixLiteralRule(t *testing.T, grl string, invoice *Invoice) error {
+	dataContext := ast.NewDataContext()
+	err := dataContext.Add("Invoice", invoice)
+	assert.NoError(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	err = rb.BuildRuleFromResource("TestSuffix", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	if err != nil {
+
+		return err
+	}
+
+	// run from a catalog round trip so the literals are parsed again when loaded.
+	var buff bytes.Buffer
+	err = lib.StoreKnowledgeBaseToWriter(&buff, "TestSuffix", "1.0.0")
+	assert.NoError(t, err)
+	loaded := ast.NewKnowledgeLibrary()
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	kb, err := loaded.NewKnowledgeBaseInstance("TestSuffix", "1.0.0")
+	assert.NoError(t, err)
+	eng := &engine.GruleEngine{MaxCycle: 5, ReturnErrOnFailedRuleEvaluation: true}
+
+	return eng.Execute(dataContext, kb)
+}
+
+func TestSuffixLiterals(t *testing.T) {
+	invoice := &Invoice{Due: time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)}
+	err := executeSuffixLiteralRule(t, SuffixLiteralRule, invoice)
+	assert.NoError(t, err)
+	assert.True(t, invoice.Overdue)
+	assert.Equal(t, Money{Cents: 1250, Currency: "EUR"}, invoice.Fee)
+
+	invoice = &Invoice{Due: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	err = executeSuffixLiteralRule(t, SuffixLiteralRule, invoice)
+	assert.NoError(t, err)
+	assert.False(t, invoice.Overdue)
+}
+
+func TestSuffixLiteralErrors(t *testing.T) {
+	testData := []struct {
+		expression string
+		errorText  string
+	}{
+		{"Invoice.Due < 2024-13-01_D", "error while parsing literal 2024-13-01_D"},
+		{"Invoice.Fee == 10_GOLD", "literal suffix GOLD is not registered"},
+	}
+	for _, td := range testData {
+		grl := `rule Invalid { when ` + td.expression + ` then Retract("Invalid"); }`
+		err := executeSuffixLiteralRule(t, grl, &Invoice{})
+		reporter, ok := err.(*pkg.GruleErrorReporter)
+		if assert.True(t, ok, td.expression) && assert.Len(t, reporter.Errors, 1, td.expression) {
+			assert.Contains(t, reporter.Errors[0].Error(), td.errorText, td.expression)
+		}
+	}
+}
+
+func TestSuffixLiterals_KnowledgeLibrary(t *testing.T) {
+	grl := `rule Thousands { when Invoice.Fee.Cents < 5_K && !Invoice.Overdue then Invoice.Overdue = true; }`
+	suffixes := pkg.NewLiteralSuffixRegistry()
+	assert.NoError(t, suffixes.Register("K", func(text string) (interface{}, error) {
+		value, err := strconv.ParseInt(text, 10, 64)
+
+		return value * 1000, err
+	}))
+
+	// the suffixes of a library are not known to the others.
+	err := builder.NewRuleBuilder(ast.NewKnowledgeLibrary()).BuildRuleFromResource("Thousands", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	assert.Error(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	lib.LiteralSuffixes = suffixes
+	err = builder.NewRuleBuilder(lib).BuildRuleFromResource("Thousands", "1.0.0", pkg.NewBytesResource([]byte(grl)))
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("Thousands", "1.0.0")
+	assert.NoError(t, err)
+	invoice := &Invoice{Fee: Money{Cents: 4999}}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Invoice", invoice))
+	assert.NoError(t, (&engine.GruleEngine{MaxCycle: 5}).Execute(dataContext, kb))
+	assert.True(t, invoice.Overdue)
+}
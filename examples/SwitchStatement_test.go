@@ -0,0 +1,131 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const switchRules = `
+rule Discount "gives the discount of the tier" {
+	when
+		Customer.Discount == 0 && Customer.Note == ""
+	then
+		switch Customer.Tier {
+			case "gold", "platinum" {
+				Customer.Discount = 20;
+				switch Customer.Years {
+					case 10 { Customer.Note = "loyal"; }
+				};
+			}
+			case "silver" {
+				Customer.Discount = 10;
+			}
+			case "bronze" {
+			}
+			default {
+				Customer.Discount = 1;
+			}
+		};
+		Customer.Note = Customer.Note + "done";
+		Retract("Discount");
+}
+`
+
+type SwitchCustomer struct {
+	Tier     string
+	Years    int
+	Discount int
+	Note     string
+}
+
+func TestSwitchStatement(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Switch", "0.0.1", pkg.NewBytesResource([]byte(switchRules))))
+
+	// the switch statement survives a binary and a JSON catalog round trip.
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(buffer, "Switch", "0.0.1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err := loaded.LoadKnowledgeBaseFromReader(buffer, true)
+	assert.NoError(t, err)
+
+	var doc bytes.Buffer
+	assert.NoError(t, lib.GetKnowledgeBase("Switch", "0.0.1").MakeCatalog().WriteCatalogToJSON(&doc))
+	cat := &ast.Catalog{}
+	assert.NoError(t, cat.ReadCatalogFromJSON(&doc))
+	fromJSON, err := cat.BuildKnowledgeBase()
+	assert.NoError(t, err)
+	assert.True(t, lib.GetKnowledgeBase("Switch", "0.0.1").IsIdentical(fromJSON))
+
+	testData := []struct {
+		customer SwitchCustomer
+		discount int
+		note     string
+	}{
+		{customer: SwitchCustomer{Tier: "gold", Years: 10}, discount: 20, note: "loyaldone"},
+		{customer: SwitchCustomer{Tier: "platinum", Years: 2}, discount: 20, note: "done"},
+		{customer: SwitchCustomer{Tier: "silver", Years: 10}, discount: 10, note: "done"},
+		{customer: SwitchCustomer{Tier: "bronze"}, discount: 0, note: "done"},
+		{customer: SwitchCustomer{Tier: "none"}, discount: 1, note: "done"},
+	}
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		for _, td := range testData {
+			kb, err := library.NewKnowledgeBaseInstance("Switch", "0.0.1")
+			assert.NoError(t, err)
+			customer := td.customer
+			dctx := ast.NewDataContext()
+			assert.NoError(t, dctx.Add("Customer", &customer))
+			assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+			assert.Equal(t, td.discount, customer.Discount, td.customer.Tier)
+			assert.Equal(t, td.note, customer.Note, td.customer.Tier)
+		}
+	}
+}
+
+func TestSwitchStatement_WithoutDefault(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	grl := `rule Tier { when Customer.Note == "" then switch Customer.Tier { case "gold" { Customer.Discount = 20; } }; Customer.Note = "done"; }`
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Switch", "0.0.1", pkg.NewBytesResource([]byte(grl))))
+	kb, err := lib.NewKnowledgeBaseInstance("Switch", "0.0.1")
+	assert.NoError(t, err)
+
+	customer := &SwitchCustomer{Tier: "silver"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Customer", customer))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+	assert.Equal(t, 0, customer.Discount)
+	assert.Equal(t, "done", customer.Note)
+}
+
+func TestSwitchStatement_Invalid(t *testing.T) {
+	for _, then := range []string{
+		`switch Customer.Tier { default { Customer.Discount = 1; } case "gold" { Customer.Discount = 20; } };`,
+		`switch Customer.Tier { when "gold" { Customer.Discount = 20; } };`,
+		`switch Customer.Tier { case { Customer.Discount = 20; } };`,
+		`select Customer.Tier { case "gold" { Customer.Discount = 20; } };`,
+	} {
+		lib := ast.NewKnowledgeLibrary()
+		grl := `rule Tier { when Customer.Note == "" then ` + then + ` }`
+		assert.Error(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Switch", "0.0.1", pkg.NewBytesResource([]byte(grl))), then)
+	}
+}
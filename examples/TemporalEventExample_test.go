@@ -0,0 +1,111 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package examples
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type AccountLock struct {
+	LockedAt   time.Time
+	UnlockedAt time.Time
+}
+
+func (l *AccountLock) EventTime() time.Time {
+	return l.LockedAt
+}
+
+func (l *AccountLock) EventEnd() time.Time {
+	return l.UnlockedAt
+}
+
+type PaymentAttempt struct {
+	AttemptedAt time.Time
+	Flag        string
+}
+
+func (p *PaymentAttempt) EventTime() time.Time {
+	return p.AttemptedAt
+}
+
+const temporalRules = `
+rule WhileLocked "Payment attempted while the account is locked" salience 10 {
+	when
+		Payment.Flag == "" && During(Payment, Lock)
+	then
+		Payment.Flag = "blocked";
+}
+rule RightAfterUnlock "Payment attempted right after the account was unlocked" {
+	when
+		Payment.Flag == "" && After(Payment, Lock, "5m")
+	then
+		Payment.Flag = "review";
+}
+rule Clear "Any other payment" salience -10 {
+	when
+		Payment.Flag == ""
+	then
+		Payment.Flag = "clear";
+}`
+
+func TestTemporalEvents(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Temporal", "0.0.1", pkg.NewBytesResource([]byte(temporalRules))))
+
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	lock := &AccountLock{LockedAt: base, UnlockedAt: base.Add(time.Hour)}
+	flag := func(attemptedAt time.Time) string {
+		payment := &PaymentAttempt{AttemptedAt: attemptedAt}
+		dctx := ast.NewDataContext()
+		assert.NoError(t, dctx.Add("Lock", lock))
+		assert.NoError(t, dctx.Add("Payment", payment))
+		kb, err := lib.NewKnowledgeBaseInstance("Temporal", "0.0.1")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.NewGruleEngine().Execute(dctx, kb))
+
+		return payment.Flag
+	}
+
+	assert.Equal(t, "blocked", flag(base.Add(30*time.Minute)))
+	assert.Equal(t, "review", flag(base.Add(63*time.Minute)))
+	assert.Equal(t, "clear", flag(base.Add(2*time.Hour)))
+	assert.Equal(t, "clear", flag(base.Add(-time.Minute)))
+}
+
+func TestTemporalEvents_Operators(t *testing.T) {
+	defunc := &ast.BuiltInFunctions{}
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	lock := &AccountLock{LockedAt: base, UnlockedAt: base.Add(time.Hour)}
+	overlapping := &AccountLock{LockedAt: base.Add(-time.Minute), UnlockedAt: base.Add(time.Minute)}
+
+	assert.True(t, defunc.Before(base.Add(-time.Hour), lock))
+	assert.False(t, defunc.Before(base.Add(-time.Hour), lock, 5*time.Minute))
+	assert.True(t, defunc.Overlaps(overlapping, lock))
+	assert.False(t, defunc.Overlaps(lock, overlapping))
+	assert.False(t, defunc.During(overlapping, lock))
+	assert.True(t, defunc.Coincides(lock, &AccountLock{LockedAt: base, UnlockedAt: base.Add(time.Hour)}))
+	assert.True(t, defunc.Coincides(overlapping, lock, time.Hour))
+	assert.Panics(t, func() {
+		defunc.During("noon", lock)
+	})
+}
@@ -0,0 +1,530 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package ir is the intermediate representation of the rules, between the ANTLR parse tree and the AST the engine
+// executes. The GRL listener builds AST nodes shaped after the grammar: parentheses, member chains written as
+// variables or as expression atoms, and negations at two levels. The IR has a single node per operation, so
+// optimization passes and front-ends other than GRL, such as JSON, CEL or DMN, share one representation and one
+// backend:
+//
+//	program, err := ir.Lift(grl)
+//	err = ir.Optimize(program, ir.DefaultPasses()...)
+//	optimized, err := ir.Lower(program, knowledgeBase.WorkingMemory)
+//
+// Lift converts the rules of a GRL into a Program, the passes rewrite it, Lower converts it back into AST nodes.
+// Use builder.RuleBuilder.Passes to optimize the rules a RuleBuilder loads.
+package ir
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Kind is the operation of an Expr.
+type Kind int
+
+const (
+	// KindConstant is a literal value, held by Expr.Constant.
+	KindConstant Kind = iota
+	// KindFact is a fact of the data context, Expr.Name is its name, such as Order.
+	KindFact
+	// KindField is the field Expr.Name of the value of its single operand, such as Order.Total.
+	KindField
+	// KindIndex is the element of the array or the map of its first operand, selected by its second operand.
+	KindIndex
+	// KindCall is the call of the built-in function Expr.Name, its operands are the arguments.
+	KindCall
+	// KindMethod is the call of the method Expr.Name of the value of its first operand, the other operands are the
+	// arguments.
+	KindMethod
+	// KindNot is the negation of its single operand. A value that is not a boolean is not negated.
+	KindNot
+	// KindBinary is the Expr.Operator of its two operands, such as ast.OpAdd. The right operand of ast.OpAnd and
+	// ast.OpOr is only evaluated when the left operand does not decide the result. An ast.OpApproxEq may have a
+	// third operand, its tolerance.
+	KindBinary
+	// KindExists tells whether its second operand is true for an element of its first operand, the array tested.
+	// The element is bound as the fact Expr.Name while the second operand is evaluated.
+	KindExists
+	// KindForAll tells whether its second operand is true for every element of its first operand, bound as
+	// KindExists binds them.
+	KindForAll
+)
+
+// Expr is an expression of the IR. Its operands are evaluated before it, left to right. After
+// CommonSubexpressions an Expr may be the operand of several expressions, the passes must not expect a tree.
+type Expr struct {
+	Kind Kind
+	// Operator is the ast operator of a KindBinary, such as ast.OpAdd.
+	Operator int
+	// Name is the fact of a KindFact, the field of a KindField, the function of a KindCall or a KindMethod and the
+	// element of a KindExists or a KindForAll.
+	Name string
+	// Constant is the value of a KindConstant.
+	Constant *ast.Constant
+	Operands []*Expr
+}
+
+// NewConstant creates new KindConstant of the value. Only the values GRL has a literal for are accepted: the
+// booleans, the strings, the integers and the finite floats.
+func NewConstant(value reflect.Value) (*Expr, error) {
+	constant := ast.NewConstant()
+	constant.Value = value
+	switch value.Kind() {
+	case reflect.Bool:
+		constant.GrlText = strconv.FormatBool(value.Bool())
+	case reflect.String:
+		constant.GrlText = strconv.Quote(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		constant.GrlText = strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		constant.GrlText = strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		float := value.Float()
+		if math.IsInf(float, 0) || math.IsNaN(float) {
+
+			return nil, fmt.Errorf("float %v has no GRL literal", float)
+		}
+		constant.GrlText = strconv.FormatFloat(float, 'f', -1, 64)
+		if !strings.ContainsAny(constant.GrlText, ".e") {
+			constant.GrlText += ".0"
+		}
+	default:
+
+		return nil, fmt.Errorf("value of kind %s has no GRL literal", value.Kind())
+	}
+
+	return &Expr{Kind: KindConstant, Constant: constant}, nil
+}
+
+// String returns the expression as GRL, without spaces as the GRL texts of the AST nodes are.
+func (expr *Expr) String() string {
+	var buff strings.Builder
+	expr.write(&buff)
+
+	return buff.String()
+}
+
+func (expr *Expr) write(buff *strings.Builder) {
+	switch expr.Kind {
+	case KindConstant:
+		if expr.Constant.IsNil {
+			buff.WriteString("nil")
+		} else {
+			buff.WriteString(expr.Constant.GrlText)
+		}
+	case KindFact:
+		buff.WriteString(expr.Name)
+	case KindField:
+		expr.Operands[0].write(buff)
+		buff.WriteString(".")
+		buff.WriteString(expr.Name)
+	case KindIndex:
+		expr.Operands[0].write(buff)
+		buff.WriteString("[")
+		expr.Operands[1].write(buff)
+		buff.WriteString("]")
+	case KindCall:
+		writeCall(buff, expr.Name, expr.Operands)
+	case KindMethod:
+		expr.Operands[0].write(buff)
+		buff.WriteString(".")
+		writeCall(buff, expr.Name, expr.Operands[1:])
+	case KindNot:
+		buff.WriteString("!")
+		writeOperand(buff, expr.Operands[0], !expr.Operands[0].atom())
+	case KindBinary:
+		precedence := expr.precedence()
+		left, right := expr.Operands[0], expr.Operands[1]
+		writeOperand(buff, left, left.Kind == KindBinary && left.precedence() < precedence)
+		buff.WriteString(symbolOf(expr.Operator))
+		writeOperand(buff, right, right.Kind == KindBinary && right.precedence() <= precedence)
+		if len(expr.Operands) > 2 {
+			tolerance := expr.Operands[2]
+			buff.WriteString("within")
+			writeOperand(buff, tolerance, tolerance.Kind == KindBinary && tolerance.precedence() <= withinPrecedence)
+		}
+	case KindExists, KindForAll:
+		keyword := ast.QuantifierExists
+		if expr.Kind == KindForAll {
+			keyword = ast.QuantifierForAll
+		}
+		buff.WriteString(keyword)
+		buff.WriteString("(")
+		expr.Operands[0].write(buff)
+		buff.WriteString(",")
+		buff.WriteString(expr.Name)
+		buff.WriteString("->")
+		expr.Operands[1].write(buff)
+		buff.WriteString(")")
+	}
+}
+
+func writeCall(buff *strings.Builder, name string, args []*Expr) {
+	buff.WriteString(name)
+	buff.WriteString("(")
+	for i, arg := range args {
+		if i > 0 {
+			buff.WriteString(",")
+		}
+		arg.write(buff)
+	}
+	buff.WriteString(")")
+}
+
+func writeOperand(buff *strings.Builder, operand *Expr, parenthesized bool) {
+	if parenthesized {
+		buff.WriteString("(")
+	}
+	operand.write(buff)
+	if parenthesized {
+		buff.WriteString(")")
+	}
+}
+
+// atom tells whether the expression is an expression atom of the grammar, one that needs no parentheses.
+func (expr *Expr) atom() bool {
+	switch expr.Kind {
+	case KindBinary:
+
+		return false
+	case KindNot:
+
+		return expr.Operands[0].atom()
+	}
+
+	return true
+}
+
+// variable tells whether the expression is a variable of the grammar, one that can be assigned.
+func (expr *Expr) variable() bool {
+	switch expr.Kind {
+	case KindFact:
+
+		return true
+	case KindField, KindIndex:
+
+		return expr.Operands[0].variable()
+	}
+
+	return false
+}
+
+// withinPrecedence is the precedence of a ~== comparison having a within, which binds before the other comparisons.
+const withinPrecedence = 5
+
+// precedenceOf returns the precedence of the operator, the operators of a higher precedence bind first.
+func precedenceOf(operator int) int {
+	switch operator {
+	case ast.OpMul, ast.OpDiv, ast.OpMod:
+
+		return 7
+	case ast.OpAdd, ast.OpSub, ast.OpBitAnd, ast.OpBitOr:
+
+		return 6
+	case ast.OpAnd:
+
+		return 2
+	case ast.OpOr:
+
+		return 1
+	}
+
+	return 4
+}
+
+// precedence returns the precedence of a KindBinary, the one of its within if it is a ~== comparison having one.
+func (expr *Expr) precedence() int {
+	if len(expr.Operands) > 2 {
+
+		return withinPrecedence
+	}
+
+	return precedenceOf(expr.Operator)
+}
+
+// symbolOf returns the GRL symbol of the operator.
+func symbolOf(operator int) string {
+	switch operator {
+	case ast.OpMul:
+
+		return "*"
+	case ast.OpDiv:
+
+		return "/"
+	case ast.OpMod:
+
+		return "%"
+	case ast.OpAdd:
+
+		return "+"
+	case ast.OpSub:
+
+		return "-"
+	case ast.OpBitAnd:
+
+		return "&"
+	case ast.OpBitOr:
+
+		return "|"
+	case ast.OpGT:
+
+		return ">"
+	case ast.OpLT:
+
+		return "<"
+	case ast.OpGTE:
+
+		return ">="
+	case ast.OpLTE:
+
+		return "<="
+	case ast.OpEq:
+
+		return "=="
+	case ast.OpNEq:
+
+		return "!="
+	case ast.OpApproxEq:
+
+		return "~=="
+	case ast.OpIn:
+
+		return " in "
+	case ast.OpAnd:
+
+		return "&&"
+	}
+
+	return "||"
+}
+
+// StmtKind is the operation of a Stmt.
+type StmtKind int
+
+const (
+	// StmtAssign assigns Value to Target with the Assign operator.
+	StmtAssign StmtKind = iota
+	// StmtMatch assigns the value of the first of the Arms matching Value to Target with the Assign operator.
+	StmtMatch
+	// StmtCollect collects the elements of Value for which Condition is true into a new fact named Name. Condition
+	// refers to the element as ast.CollectItemName, it is nil if every element is collected.
+	StmtCollect
+	// StmtEval evaluates Value for its effects, such as the call Retract("Discount").
+	StmtEval
+	// StmtSwitch executes the statements of the first of the Cases having a value equal to Value.
+	StmtSwitch
+)
+
+// Assign operators of StmtAssign and StmtMatch.
+const (
+	Assign      = "="
+	PlusAssign  = "+="
+	MinusAssign = "-="
+	DivAssign   = "/="
+	MulAssign   = "*="
+)
+
+// Stmt is a statement of the then scope of a Rule.
+type Stmt struct {
+	Kind StmtKind
+	// Target is the variable assigned by a StmtAssign or a StmtMatch, a chain of KindFact, KindField and KindIndex.
+	Target *Expr
+	// Assign is the assign operator of a StmtAssign or a StmtMatch, such as PlusAssign.
+	Assign    string
+	Value     *Expr
+	Arms      []*Arm
+	Name      string
+	Condition *Expr
+	Cases     []*Case
+}
+
+// Arm is an arm of a StmtMatch.
+type Arm struct {
+	// Operator compares the subject to the Pattern, it is one of the comparison operators of ast.
+	Operator int
+	// Default is true for the `_` arm, which has no Pattern and always matches.
+	Default bool
+	Pattern *Expr
+	Value   *Expr
+}
+
+// Case is a case of a StmtSwitch.
+type Case struct {
+	// Default is true for the `default` case, which has no Values and always matches.
+	Default bool
+	Values  []*Expr
+	Then    []*Stmt
+}
+
+// String returns the statement as GRL, without spaces.
+func (stmt *Stmt) String() string {
+	var buff strings.Builder
+	switch stmt.Kind {
+	case StmtAssign:
+		stmt.Target.write(&buff)
+		buff.WriteString(stmt.Assign)
+		stmt.Value.write(&buff)
+	case StmtMatch:
+		stmt.Target.write(&buff)
+		buff.WriteString(stmt.Assign)
+		buff.WriteString("match")
+		stmt.Value.write(&buff)
+		buff.WriteString("{")
+		for i, arm := range stmt.Arms {
+			if i > 0 {
+				buff.WriteString(",")
+			}
+			buff.WriteString(arm.String())
+		}
+		buff.WriteString("}")
+	case StmtCollect:
+		buff.WriteString("collect")
+		buff.WriteString(stmt.Name)
+		buff.WriteString("from")
+		stmt.Value.write(&buff)
+		if stmt.Condition != nil {
+			buff.WriteString("where")
+			stmt.Condition.write(&buff)
+		}
+	case StmtEval:
+		stmt.Value.write(&buff)
+	case StmtSwitch:
+		buff.WriteString("switch")
+		stmt.Value.write(&buff)
+		buff.WriteString("{")
+		for _, switchCase := range stmt.Cases {
+			buff.WriteString(switchCase.String())
+		}
+		buff.WriteString("}")
+	}
+
+	return buff.String()
+}
+
+// String returns the case as GRL, without spaces.
+func (switchCase *Case) String() string {
+	var buff strings.Builder
+	if switchCase.Default {
+		buff.WriteString("default")
+	} else {
+		buff.WriteString("case")
+		for i, value := range switchCase.Values {
+			if i > 0 {
+				buff.WriteString(",")
+			}
+			value.write(&buff)
+		}
+	}
+	buff.WriteString("{")
+	for _, stmt := range switchCase.Then {
+		buff.WriteString(stmt.String())
+		buff.WriteString(";")
+	}
+	buff.WriteString("}")
+
+	return buff.String()
+}
+
+// String returns the arm as GRL, without spaces.
+func (arm *Arm) String() string {
+	if arm.Default {
+
+		return "_=>" + arm.Value.String()
+	}
+	operator := ""
+	if arm.Operator != ast.OpEq {
+		operator = symbolOf(arm.Operator)
+	}
+
+	return operator + arm.Pattern.String() + "=>" + arm.Value.String()
+}
+
+// Rule is a rule of a Program. Its name, salience and annotations stay in its Entry, Lower replaces the scopes of
+// the Entry by the ones of the Rule.
+type Rule struct {
+	Entry *ast.RuleEntry
+	When  *Expr
+	// Then is nil if the then scope of the Entry is a script, which is kept as it is.
+	Then []*Stmt
+	// Else is nil if the Entry has no else scope.
+	Else []*Stmt
+}
+
+// Halt is a halt condition of a Program.
+type Halt struct {
+	When *Expr
+}
+
+// Program is the IR of the rules and the halt conditions of a GRL.
+type Program struct {
+	// Rules are sorted by their name.
+	Rules []*Rule
+	Halts []*Halt
+}
+
+// Transform replaces every expression of the program, the operands first, by the expression rewrite returns for
+// it, which may be the expression itself. An expression shared by several others is rewritten once.
+func (program *Program) Transform(rewrite func(expr *Expr) *Expr) {
+	done := make(map[*Expr]*Expr)
+	var visit func(expr *Expr) *Expr
+	visit = func(expr *Expr) *Expr {
+		if expr == nil {
+
+			return nil
+		}
+		if rewritten, ok := done[expr]; ok {
+
+			return rewritten
+		}
+		for i, operand := range expr.Operands {
+			expr.Operands[i] = visit(operand)
+		}
+		rewritten := rewrite(expr)
+		done[expr] = rewritten
+
+		return rewritten
+	}
+	var visitStmts func(stmts []*Stmt)
+	visitStmts = func(stmts []*Stmt) {
+		for _, stmt := range stmts {
+			stmt.Target = visit(stmt.Target)
+			stmt.Value = visit(stmt.Value)
+			stmt.Condition = visit(stmt.Condition)
+			for _, arm := range stmt.Arms {
+				arm.Pattern = visit(arm.Pattern)
+				arm.Value = visit(arm.Value)
+			}
+			for _, switchCase := range stmt.Cases {
+				for i, value := range switchCase.Values {
+					switchCase.Values[i] = visit(value)
+				}
+				visitStmts(switchCase.Then)
+			}
+		}
+	}
+	for _, rule := range program.Rules {
+		rule.When = visit(rule.When)
+		visitStmts(rule.Then)
+		visitStmts(rule.Else)
+	}
+	for _, halt := range program.Halts {
+		halt.When = visit(halt.When)
+	}
+}
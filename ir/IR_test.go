@@ -0,0 +1,201 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ir
+
+import (
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+	antlr2 "github.com/hyperjumptech/grule-rule-engine/antlr"
+	parser "github.com/hyperjumptech/grule-rule-engine/antlr/parser/grulev3"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const irRules = `
+rule Discount "discounts the large orders" salience 10 {
+	when
+		Order.Total() > 100 && !(Order.Customer.Blocked || Order.Lines[0].Price < 1) && !Order.Discounted
+	then
+		Order.Discount += Order.Total() * 0.1 - 10;
+		Order.Tags["discount"] = Order.Get("tier").Name;
+		Retract("Discount");
+}
+rule Grade "grades the customers" {
+	when
+		Order.Customer.Grade == ""
+	then
+		Order.Customer.Grade = match Order.Total() { > 800 => "A", <= 100 => "C", _ => "B" };
+		collect Large from Order.Lines where item.Price * item.Quantity > 50;
+}
+halt when Order.Discounted && Order.Customer.Grade != "";
+`
+
+// parse walks the GRL into a new knowledge base, as the rule builder does.
+func parse(t *testing.T, grl string) *ast.Grl {
+	t.Helper()
+	errReporter := &pkg.GruleErrorReporter{Errors: make([]error, 0)}
+	lexer := parser.Newgrulev3Lexer(antlr.NewInputStream(grl))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errReporter)
+	psr := parser.Newgrulev3Parser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	psr.RemoveErrorListeners()
+	psr.AddErrorListener(errReporter)
+	psr.BuildParseTrees = true
+	listener := antlr2.NewGruleV3ParserListener(ast.NewKnowledgeLibrary().GetKnowledgeBase("T", "1"), errReporter)
+	antlr.ParseTreeWalkerDefault.Walk(listener, psr.Grl())
+	assert.False(t, errReporter.HasError(), errReporter.Error())
+
+	return listener.Grl
+}
+
+// snapshots returns the snapshots of the scopes of the rules and of the halt conditions.
+func snapshots(grl *ast.Grl) map[string][]string {
+	snapshots := make(map[string][]string)
+	for name, entry := range grl.RuleEntries {
+		snapshots[name] = append(snapshots[name], entry.WhenScope.Expression.GetSnapshot())
+		for _, thenExpr := range entry.ThenScope.ThenExpressionList.ThenExpressions {
+			snapshots[name] = append(snapshots[name], thenExpr.GetSnapshot())
+		}
+	}
+	for _, halt := range grl.HaltEntries {
+		snapshots["halt"] = append(snapshots["halt"], halt.Expression.GetSnapshot())
+	}
+
+	return snapshots
+}
+
+func TestLiftLower(t *testing.T) {
+	grl := parse(t, irRules)
+	expected := snapshots(grl)
+
+	program, err := Lift(grl)
+	assert.NoError(t, err)
+	if !assert.Len(t, program.Rules, 2) || !assert.Len(t, program.Halts, 1) {
+
+		return
+	}
+	discount := program.Rules[0]
+	assert.Equal(t, "Discount", discount.Entry.RuleName)
+	assert.Equal(t, "Order.Total()>100&&!(Order.Customer.Blocked||Order.Lines[0].Price<1)&&!Order.Discounted", discount.When.String())
+	assert.Equal(t, KindBinary, discount.When.Kind)
+	assert.Equal(t, ast.OpAnd, discount.When.Operator)
+	assert.Equal(t, "Order.Discount+=Order.Total()*0.1-10", discount.Then[0].String())
+	assert.Equal(t, `Order.Tags["discount"]=Order.Get("tier").Name`, discount.Then[1].String())
+	assert.Equal(t, KindField, discount.Then[1].Value.Kind)
+	assert.Equal(t, KindMethod, discount.Then[1].Value.Operands[0].Kind)
+	assert.Equal(t, StmtEval, discount.Then[2].Kind)
+	grade := program.Rules[1]
+	assert.Equal(t, StmtMatch, grade.Then[0].Kind)
+	assert.Equal(t, `Order.Customer.Grade=matchOrder.Total(){>800=>"A",<=100=>"C",_=>"B"}`, grade.Then[0].String())
+	assert.Equal(t, "collectLargefromOrder.Lineswhereitem.Price*item.Quantity>50", grade.Then[1].String())
+
+	lowered, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots(lowered))
+	assert.Equal(t, "whenOrder.Customer.Grade==\"\"", lowered.RuleEntries["Grade"].WhenScope.GrlText)
+	assert.Equal(t, 10, lowered.RuleEntries["Discount"].Salience)
+}
+
+func TestExpr_String(t *testing.T) {
+	fact := func(name string) *Expr {
+
+		return &Expr{Kind: KindFact, Name: name}
+	}
+	binary := func(operator int, left, right *Expr) *Expr {
+
+		return &Expr{Kind: KindBinary, Operator: operator, Operands: []*Expr{left, right}}
+	}
+	assert.Equal(t, "(A+B)*C", binary(ast.OpMul, binary(ast.OpAdd, fact("A"), fact("B")), fact("C")).String())
+	assert.Equal(t, "A*B+C", binary(ast.OpAdd, binary(ast.OpMul, fact("A"), fact("B")), fact("C")).String())
+	assert.Equal(t, "A-(B-C)", binary(ast.OpSub, fact("A"), binary(ast.OpSub, fact("B"), fact("C"))).String())
+	assert.Equal(t, "A-B-C", binary(ast.OpSub, binary(ast.OpSub, fact("A"), fact("B")), fact("C")).String())
+	not := &Expr{Kind: KindNot, Operands: []*Expr{binary(ast.OpOr, fact("A"), fact("B"))}}
+	assert.Equal(t, "!(A||B)", not.String())
+	assert.Equal(t, "!(!(A||B))", (&Expr{Kind: KindNot, Operands: []*Expr{not}}).String())
+}
+
+func TestLiftLower_WithinElse(t *testing.T) {
+	grl := parse(t, `rule Price { when Order.Total() ~== 9.99 within 0.01 && Order.Discount ~== 0 then Retract("Price"); else { Order.Discount = 1; } }`)
+	expected := snapshots(grl)
+
+	program, err := Lift(grl)
+	assert.NoError(t, err)
+	when := program.Rules[0].When
+	assert.Equal(t, "Order.Total()~==9.99within0.01&&Order.Discount~==0", when.String())
+	assert.Len(t, when.Operands[0].Operands, 3)
+	assert.Len(t, when.Operands[1].Operands, 2)
+	assert.Equal(t, "Order.Discount=1", program.Rules[0].Else[0].String())
+
+	lowered, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots(lowered))
+	assert.Equal(t, grl.RuleEntries["Price"].GetSnapshot(), lowered.RuleEntries["Price"].GetSnapshot())
+	assert.Equal(t, "else{Order.Discount=1;}", lowered.RuleEntries["Price"].ElseScope.GrlText)
+}
+
+func TestLiftLower_Switch(t *testing.T) {
+	grl := parse(t, `rule Tier { when Customer.Discount == 0 then switch Customer.Tier { case "gold", "platinum" { Customer.Discount = 20; switch Customer.Years { case 10 { Customer.Note = "loyal"; } }; } case "bronze" { } default { Customer.Discount = 1; } }; }`)
+	expected := snapshots(grl)
+
+	program, err := Lift(grl)
+	assert.NoError(t, err)
+	stmt := program.Rules[0].Then[0]
+	assert.Equal(t, StmtSwitch, stmt.Kind)
+	assert.Len(t, stmt.Cases, 3)
+	assert.Equal(t, grl.RuleEntries["Tier"].ThenScope.ThenExpressionList.ThenExpressions[0].Switch.GrlText, stmt.String())
+
+	lowered, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots(lowered))
+	assert.Equal(t, grl.RuleEntries["Tier"].GetSnapshot(), lowered.RuleEntries["Tier"].GetSnapshot())
+}
+
+func TestLiftLower_Quantifier(t *testing.T) {
+	grl := parse(t, `rule Large { when exists(Customer.Orders, o -> o.Amount > 10 * 100) && !forall(Customer.Orders, o -> o.Paid) then Customer.Large = true; }`)
+	expected := snapshots(grl)
+
+	program, err := Lift(grl)
+	assert.NoError(t, err)
+	when := program.Rules[0].When
+	assert.Equal(t, KindExists, when.Operands[0].Kind)
+	assert.Equal(t, "o", when.Operands[0].Name)
+	assert.Equal(t, KindForAll, when.Operands[1].Operands[0].Kind)
+	assert.Equal(t, grl.RuleEntries["Large"].WhenScope.Expression.GrlText, when.String())
+
+	lowered, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots(lowered))
+
+	// the constants of the predicate are folded as any other.
+	assert.NoError(t, Optimize(program, DefaultPasses()...))
+	assert.Equal(t, "exists(Customer.Orders,o->o.Amount>1000)&&!forall(Customer.Orders,o->o.Paid)", when.String())
+}
+
+func TestLiftLower_In(t *testing.T) {
+	grl := parse(t, `rule Ship { when Ship.Country in ["US", "CA"] && !(Ship.Code in Codes) then Retract("Ship"); }`)
+	expected := snapshots(grl)
+
+	program, err := Lift(grl)
+	assert.NoError(t, err)
+	when := program.Rules[0].When
+	assert.Equal(t, `Ship.Country in ["US","CA"]&&!(Ship.Code in Codes)`, when.String())
+	assert.Equal(t, ast.OpIn, when.Operands[0].Operator)
+
+	lowered, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots(lowered))
+}
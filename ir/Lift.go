@@ -0,0 +1,342 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ir
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Lift converts the rules and the halt conditions of the GRL into a Program. The test entries are not part of it.
+func Lift(grl *ast.Grl) (*Program, error) {
+	program := &Program{
+		Rules: make([]*Rule, 0, len(grl.RuleEntries)),
+		Halts: make([]*Halt, 0, len(grl.HaltEntries)),
+	}
+	for _, entry := range grl.RuleEntries {
+		rule, err := liftRule(entry)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while lifting rule %s. got %w", entry.RuleName, err)
+		}
+		program.Rules = append(program.Rules, rule)
+	}
+	sort.Slice(program.Rules, func(i, j int) bool {
+
+		return program.Rules[i].Entry.RuleName < program.Rules[j].Entry.RuleName
+	})
+	for _, entry := range grl.HaltEntries {
+		when, err := liftExpression(entry.Expression)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while lifting halt %s. got %w", entry.GrlText, err)
+		}
+		program.Halts = append(program.Halts, &Halt{When: when})
+	}
+
+	return program, nil
+}
+
+func liftRule(entry *ast.RuleEntry) (*Rule, error) {
+	if entry.WhenScope == nil || entry.ThenScope == nil {
+
+		return nil, fmt.Errorf("rule has no when or then scope")
+	}
+	when, err := liftExpression(entry.WhenScope.Expression)
+	if err != nil {
+
+		return nil, err
+	}
+	rule := &Rule{Entry: entry, When: when}
+	if entry.ElseScope != nil && entry.ElseScope.ThenExpressionList != nil {
+		rule.Else, err = liftStatements(entry.ElseScope.ThenExpressionList)
+		if err != nil {
+
+			return nil, err
+		}
+	}
+	if entry.ThenScope.Script != nil || entry.ThenScope.ThenExpressionList == nil {
+
+		return rule, nil
+	}
+	rule.Then, err = liftStatements(entry.ThenScope.ThenExpressionList)
+
+	return rule, err
+}
+
+func liftStatements(list *ast.ThenExpressionList) ([]*Stmt, error) {
+	stmts := make([]*Stmt, 0, len(list.ThenExpressions))
+	for _, thenExpr := range list.ThenExpressions {
+		stmt, err := liftStatement(thenExpr)
+		if err != nil {
+
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, nil
+}
+
+func liftStatement(thenExpr *ast.ThenExpression) (*Stmt, error) {
+	switch {
+	case thenExpr.Assignment != nil:
+		assignment := thenExpr.Assignment
+		target, err := liftVariable(assignment.Variable)
+		if err != nil {
+
+			return nil, err
+		}
+		stmt := &Stmt{Kind: StmtAssign, Target: target}
+		switch {
+		case assignment.IsPlusAssign:
+			stmt.Assign = PlusAssign
+		case assignment.IsMinusAssign:
+			stmt.Assign = MinusAssign
+		case assignment.IsDivAssign:
+			stmt.Assign = DivAssign
+		case assignment.IsMulAssign:
+			stmt.Assign = MulAssign
+		default:
+			stmt.Assign = Assign
+		}
+		if assignment.Match == nil {
+			stmt.Value, err = liftExpression(assignment.Expression)
+
+			return stmt, err
+		}
+		stmt.Kind = StmtMatch
+		stmt.Value, err = liftExpression(assignment.Match.Subject)
+		if err != nil {
+
+			return nil, err
+		}
+		for _, matchArm := range assignment.Match.Arms {
+			arm := &Arm{Operator: matchArm.Operator, Default: matchArm.Default}
+			if !arm.Default {
+				arm.Pattern, err = liftExpression(matchArm.Pattern)
+				if err != nil {
+
+					return nil, err
+				}
+			}
+			arm.Value, err = liftExpression(matchArm.Value)
+			if err != nil {
+
+				return nil, err
+			}
+			stmt.Arms = append(stmt.Arms, arm)
+		}
+
+		return stmt, nil
+	case thenExpr.Collect != nil:
+		source, err := liftExpression(thenExpr.Collect.Source)
+		if err != nil {
+
+			return nil, err
+		}
+		stmt := &Stmt{Kind: StmtCollect, Name: thenExpr.Collect.Name, Value: source}
+		if thenExpr.Collect.Condition != nil {
+			stmt.Condition, err = liftExpression(thenExpr.Collect.Condition)
+		}
+
+		return stmt, err
+	case thenExpr.Switch != nil:
+		subject, err := liftExpression(thenExpr.Switch.Subject)
+		if err != nil {
+
+			return nil, err
+		}
+		stmt := &Stmt{Kind: StmtSwitch, Value: subject}
+		for _, switchCase := range thenExpr.Switch.Cases {
+			lifted := &Case{Default: switchCase.Default, Then: make([]*Stmt, 0)}
+			for _, value := range switchCase.Values {
+				liftedValue, err := liftExpression(value)
+				if err != nil {
+
+					return nil, err
+				}
+				lifted.Values = append(lifted.Values, liftedValue)
+			}
+			if switchCase.Then != nil {
+				lifted.Then, err = liftStatements(switchCase.Then)
+				if err != nil {
+
+					return nil, err
+				}
+			}
+			stmt.Cases = append(stmt.Cases, lifted)
+		}
+
+		return stmt, nil
+	case thenExpr.ExpressionAtom != nil:
+		value, err := liftAtom(thenExpr.ExpressionAtom)
+
+		return &Stmt{Kind: StmtEval, Value: value}, err
+	}
+
+	return nil, fmt.Errorf("then expression %s has no statement", thenExpr.GrlText)
+}
+
+func liftExpression(expr *ast.Expression) (*Expr, error) {
+	if expr == nil {
+
+		return nil, fmt.Errorf("missing expression")
+	}
+	switch {
+	case expr.LeftExpression != nil && expr.RightExpression != nil:
+		left, err := liftExpression(expr.LeftExpression)
+		if err != nil {
+
+			return nil, err
+		}
+		right, err := liftExpression(expr.RightExpression)
+		if err != nil {
+
+			return nil, err
+		}
+		operands := []*Expr{left, right}
+		if expr.Tolerance != nil {
+			tolerance, err := liftExpression(expr.Tolerance)
+			if err != nil {
+
+				return nil, err
+			}
+			operands = append(operands, tolerance)
+		}
+
+		return &Expr{Kind: KindBinary, Operator: expr.Operator, Operands: operands}, nil
+	case expr.SingleExpression != nil:
+		single, err := liftExpression(expr.SingleExpression)
+		if err != nil || !expr.Negated {
+
+			return single, err
+		}
+
+		return &Expr{Kind: KindNot, Operands: []*Expr{single}}, nil
+	case expr.ExpressionAtom != nil:
+
+		return liftAtom(expr.ExpressionAtom)
+	}
+
+	return nil, fmt.Errorf("expression %s has no operand", expr.GrlText)
+}
+
+func liftAtom(atom *ast.ExpressionAtom) (*Expr, error) {
+	switch {
+	case atom.Constant != nil:
+
+		return &Expr{Kind: KindConstant, Constant: atom.Constant}, nil
+	case atom.Variable != nil:
+
+		return liftVariable(atom.Variable)
+	case atom.Quantifier != nil:
+		source, err := liftExpression(atom.Quantifier.Source)
+		if err != nil {
+
+			return nil, err
+		}
+		predicate, err := liftExpression(atom.Quantifier.Predicate)
+		if err != nil {
+
+			return nil, err
+		}
+		kind := KindExists
+		if atom.Quantifier.ForAll {
+			kind = KindForAll
+		}
+
+		return &Expr{Kind: kind, Name: atom.Quantifier.Name, Operands: []*Expr{source, predicate}}, nil
+	case atom.FunctionCall != nil:
+		operands := make([]*Expr, 0, 1)
+		kind := KindCall
+		if atom.ExpressionAtom != nil {
+			receiver, err := liftAtom(atom.ExpressionAtom)
+			if err != nil {
+
+				return nil, err
+			}
+			operands = append(operands, receiver)
+			kind = KindMethod
+		}
+		if atom.FunctionCall.ArgumentList != nil {
+			for _, argument := range atom.FunctionCall.ArgumentList.Arguments {
+				arg, err := liftExpression(argument)
+				if err != nil {
+
+					return nil, err
+				}
+				operands = append(operands, arg)
+			}
+		}
+
+		return &Expr{Kind: kind, Name: atom.FunctionCall.FunctionName, Operands: operands}, nil
+	case atom.ExpressionAtom != nil:
+		inner, err := liftAtom(atom.ExpressionAtom)
+		if err != nil {
+
+			return nil, err
+		}
+		switch {
+		case len(atom.VariableName) > 0:
+
+			return &Expr{Kind: KindField, Name: atom.VariableName, Operands: []*Expr{inner}}, nil
+		case atom.ArrayMapSelector != nil:
+			selector, err := liftExpression(atom.ArrayMapSelector.Expression)
+			if err != nil {
+
+				return nil, err
+			}
+
+			return &Expr{Kind: KindIndex, Operands: []*Expr{inner, selector}}, nil
+		case atom.Negated:
+
+			return &Expr{Kind: KindNot, Operands: []*Expr{inner}}, nil
+		}
+
+		return inner, nil
+	}
+
+	return nil, fmt.Errorf("expression atom %s has no operand", atom.GrlText)
+}
+
+func liftVariable(variable *ast.Variable) (*Expr, error) {
+	if variable == nil {
+
+		return nil, fmt.Errorf("missing variable")
+	}
+	if variable.Variable == nil {
+
+		return &Expr{Kind: KindFact, Name: variable.Name}, nil
+	}
+	parent, err := liftVariable(variable.Variable)
+	if err != nil {
+
+		return nil, err
+	}
+	if variable.ArrayMapSelector != nil {
+		selector, err := liftExpression(variable.ArrayMapSelector.Expression)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return &Expr{Kind: KindIndex, Operands: []*Expr{parent, selector}}, nil
+	}
+
+	return &Expr{Kind: KindField, Name: variable.Name, Operands: []*Expr{parent}}, nil
+}
@@ -0,0 +1,391 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ir
+
+import (
+	"fmt"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Lower converts the program into AST nodes added into the working memory, as the GRL listener does, and returns
+// them as a GRL. The rule entries of the GRL are the entries of the rules of the program, whose when and then
+// scopes are replaced. The caller must re-index the working memory.
+func Lower(program *Program, memory *ast.WorkingMemory) (*ast.Grl, error) {
+	lowering := &lowering{
+		memory:      memory,
+		expressions: make(map[*Expr]*ast.Expression),
+		atoms:       make(map[*Expr]*ast.ExpressionAtom),
+		variables:   make(map[*Expr]*ast.Variable),
+	}
+	grl := ast.NewGrl()
+	for _, rule := range program.Rules {
+		when, err := lowering.expression(rule.When)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while lowering rule %s. got %w", rule.Entry.RuleName, err)
+		}
+		whenScope := ast.NewWhenScope()
+		whenScope.GrlText = "when" + when.GrlText
+		whenScope.Expression = when
+		rule.Entry.WhenScope = whenScope
+		if rule.Then != nil {
+			thenScope, err := lowering.thenScope(rule.Then)
+			if err != nil {
+
+				return nil, fmt.Errorf("error while lowering rule %s. got %w", rule.Entry.RuleName, err)
+			}
+			rule.Entry.ThenScope = thenScope
+		}
+		if rule.Else != nil {
+			elseScope, err := lowering.thenScope(rule.Else)
+			if err != nil {
+
+				return nil, fmt.Errorf("error while lowering rule %s. got %w", rule.Entry.RuleName, err)
+			}
+			elseScope.GrlText = "else{" + elseScope.ThenExpressionList.GrlText + "}"
+			rule.Entry.ElseScope = elseScope
+		}
+		grl.RuleEntries[rule.Entry.RuleName] = rule.Entry
+	}
+	for _, halt := range program.Halts {
+		when, err := lowering.expression(halt.When)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while lowering halt %s. got %w", halt.When, err)
+		}
+		entry := ast.NewHaltEntry()
+		entry.GrlText = "haltwhen" + when.GrlText + ";"
+		entry.Expression = when
+		grl.HaltEntries = append(grl.HaltEntries, entry)
+	}
+
+	return grl, nil
+}
+
+// lowering keeps the AST node of every lowered Expr, so an Expr shared by several others is lowered once.
+type lowering struct {
+	memory      *ast.WorkingMemory
+	expressions map[*Expr]*ast.Expression
+	atoms       map[*Expr]*ast.ExpressionAtom
+	variables   map[*Expr]*ast.Variable
+}
+
+func (lowering *lowering) thenScope(stmts []*Stmt) (*ast.ThenScope, error) {
+	list, err := lowering.thenExpressionList(stmts)
+	if err != nil {
+
+		return nil, err
+	}
+	thenScope := ast.NewThenScope()
+	thenScope.GrlText = "then" + list.GrlText
+	thenScope.ThenExpressionList = list
+
+	return thenScope, nil
+}
+
+func (lowering *lowering) thenExpressionList(stmts []*Stmt) (*ast.ThenExpressionList, error) {
+	list := ast.NewThenExpressionList()
+	for _, stmt := range stmts {
+		thenExpr := ast.NewThenExpression()
+		thenExpr.GrlText = stmt.String()
+		var err error
+		switch stmt.Kind {
+		case StmtAssign, StmtMatch:
+			thenExpr.Assignment, err = lowering.assignment(stmt)
+		case StmtCollect:
+			thenExpr.Collect, err = lowering.collect(stmt)
+		case StmtEval:
+			thenExpr.ExpressionAtom, err = lowering.atom(stmt.Value)
+		case StmtSwitch:
+			thenExpr.Switch, err = lowering.switchStatement(stmt)
+		default:
+			err = fmt.Errorf("unknown statement kind %d", stmt.Kind)
+		}
+		if err != nil {
+
+			return nil, err
+		}
+		list.ThenExpressions = append(list.ThenExpressions, thenExpr)
+		list.GrlText += thenExpr.GrlText + ";"
+	}
+
+	return list, nil
+}
+
+func (lowering *lowering) assignment(stmt *Stmt) (*ast.Assignment, error) {
+	assignment := ast.NewAssignment()
+	assignment.GrlText = stmt.String()
+	switch stmt.Assign {
+	case Assign:
+		assignment.IsAssign = true
+	case PlusAssign:
+		assignment.IsPlusAssign = true
+	case MinusAssign:
+		assignment.IsMinusAssign = true
+	case DivAssign:
+		assignment.IsDivAssign = true
+	case MulAssign:
+		assignment.IsMulAssign = true
+	default:
+
+		return nil, fmt.Errorf("unknown assign operator %q", stmt.Assign)
+	}
+	var err error
+	assignment.Variable, err = lowering.variable(stmt.Target)
+	if err != nil {
+
+		return nil, err
+	}
+	if stmt.Kind == StmtAssign {
+		assignment.Expression, err = lowering.expression(stmt.Value)
+
+		return assignment, err
+	}
+	match := ast.NewMatchExpression()
+	match.GrlText = stmt.String()[len(stmt.Target.String())+len(stmt.Assign):]
+	match.Subject, err = lowering.expression(stmt.Value)
+	if err != nil {
+
+		return nil, err
+	}
+	for _, arm := range stmt.Arms {
+		matchArm := ast.NewMatchArm()
+		matchArm.GrlText = arm.String()
+		matchArm.Operator = arm.Operator
+		matchArm.Default = arm.Default
+		if !arm.Default {
+			matchArm.Pattern, err = lowering.expression(arm.Pattern)
+			if err != nil {
+
+				return nil, err
+			}
+		}
+		matchArm.Value, err = lowering.expression(arm.Value)
+		if err != nil {
+
+			return nil, err
+		}
+		match.Arms = append(match.Arms, matchArm)
+	}
+	assignment.Match = match
+
+	return assignment, nil
+}
+
+func (lowering *lowering) collect(stmt *Stmt) (*ast.CollectStatement, error) {
+	collect := ast.NewCollectStatement()
+	collect.GrlText = stmt.String()
+	collect.Name = stmt.Name
+	var err error
+	collect.Source, err = lowering.expression(stmt.Value)
+	if err != nil || stmt.Condition == nil {
+
+		return collect, err
+	}
+	collect.Condition, err = lowering.expression(stmt.Condition)
+
+	return collect, err
+}
+
+func (lowering *lowering) switchStatement(stmt *Stmt) (*ast.SwitchStatement, error) {
+	switchStmt := ast.NewSwitchStatement()
+	switchStmt.GrlText = stmt.String()
+	var err error
+	switchStmt.Subject, err = lowering.expression(stmt.Value)
+	if err != nil {
+
+		return nil, err
+	}
+	for _, switchCase := range stmt.Cases {
+		lowered := &ast.SwitchCase{GrlText: switchCase.String(), Default: switchCase.Default}
+		for _, value := range switchCase.Values {
+			loweredValue, err := lowering.expression(value)
+			if err != nil {
+
+				return nil, err
+			}
+			lowered.Values = append(lowered.Values, loweredValue)
+		}
+		if len(switchCase.Then) > 0 {
+			lowered.Then, err = lowering.thenExpressionList(switchCase.Then)
+			if err != nil {
+
+				return nil, err
+			}
+		}
+		switchStmt.Cases = append(switchStmt.Cases, lowered)
+	}
+
+	return switchStmt, nil
+}
+
+func (lowering *lowering) expression(expr *Expr) (*ast.Expression, error) {
+	if lowered, ok := lowering.expressions[expr]; ok {
+
+		return lowered, nil
+	}
+	expression := ast.NewExpression()
+	expression.GrlText = expr.String()
+	var err error
+	switch {
+	case expr.Kind == KindBinary:
+		expression.Operator = expr.Operator
+		expression.LeftExpression, err = lowering.expression(expr.Operands[0])
+		if err != nil {
+
+			return nil, err
+		}
+		expression.RightExpression, err = lowering.expression(expr.Operands[1])
+		if err == nil && len(expr.Operands) > 2 {
+			expression.Tolerance, err = lowering.expression(expr.Operands[2])
+		}
+	case expr.Kind == KindNot && !expr.Operands[0].atom():
+		expression.Negated = true
+		expression.SingleExpression, err = lowering.expression(expr.Operands[0])
+	default:
+		expression.ExpressionAtom, err = lowering.atom(expr)
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	lowered := lowering.memory.AddExpression(expression)
+	lowering.expressions[expr] = lowered
+
+	return lowered, nil
+}
+
+func (lowering *lowering) atom(expr *Expr) (*ast.ExpressionAtom, error) {
+	if lowered, ok := lowering.atoms[expr]; ok {
+
+		return lowered, nil
+	}
+	atom := ast.NewExpressionAtom()
+	atom.GrlText = expr.String()
+	var err error
+	switch {
+	case expr.Kind == KindConstant:
+		atom.Constant = expr.Constant
+	case expr.variable():
+		atom.Variable, err = lowering.variable(expr)
+	case expr.Kind == KindField:
+		atom.VariableName = expr.Name
+		atom.ExpressionAtom, err = lowering.atom(expr.Operands[0])
+	case expr.Kind == KindIndex:
+		atom.ExpressionAtom, err = lowering.atom(expr.Operands[0])
+		if err == nil {
+			atom.ArrayMapSelector, err = lowering.selector(expr.Operands[1])
+		}
+	case expr.Kind == KindCall:
+		atom.FunctionCall, err = lowering.call(expr.Name, expr.Operands)
+	case expr.Kind == KindMethod:
+		atom.ExpressionAtom, err = lowering.atom(expr.Operands[0])
+		if err == nil {
+			atom.FunctionCall, err = lowering.call(expr.Name, expr.Operands[1:])
+		}
+	case expr.Kind == KindNot:
+		atom.Negated = true
+		atom.ExpressionAtom, err = lowering.atom(expr.Operands[0])
+	case expr.Kind == KindExists || expr.Kind == KindForAll:
+		atom.Quantifier, err = lowering.quantifier(expr)
+	default:
+		err = fmt.Errorf("%s is not an expression atom", expr)
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	lowered := lowering.memory.AddExpressionAtom(atom)
+	lowering.atoms[expr] = lowered
+
+	return lowered, nil
+}
+
+func (lowering *lowering) variable(expr *Expr) (*ast.Variable, error) {
+	if lowered, ok := lowering.variables[expr]; ok {
+
+		return lowered, nil
+	}
+	if !expr.variable() {
+
+		return nil, fmt.Errorf("%s is not a variable", expr)
+	}
+	variable := ast.NewVariable()
+	variable.GrlText = expr.String()
+	var err error
+	switch expr.Kind {
+	case KindFact:
+		variable.Name = expr.Name
+	case KindField:
+		variable.Name = expr.Name
+		variable.Variable, err = lowering.variable(expr.Operands[0])
+	case KindIndex:
+		variable.Variable, err = lowering.variable(expr.Operands[0])
+		if err == nil {
+			variable.ArrayMapSelector, err = lowering.selector(expr.Operands[1])
+		}
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	lowered := lowering.memory.AddVariable(variable)
+	lowering.variables[expr] = lowered
+
+	return lowered, nil
+}
+
+func (lowering *lowering) selector(expr *Expr) (*ast.ArrayMapSelector, error) {
+	selector := ast.NewArrayMapSelector()
+	selector.GrlText = "[" + expr.String() + "]"
+	var err error
+	selector.Expression, err = lowering.expression(expr)
+
+	return selector, err
+}
+
+func (lowering *lowering) quantifier(expr *Expr) (*ast.Quantifier, error) {
+	quantifier := ast.NewQuantifier()
+	quantifier.GrlText = expr.String()
+	quantifier.ForAll = expr.Kind == KindForAll
+	quantifier.Name = expr.Name
+	var err error
+	quantifier.Source, err = lowering.expression(expr.Operands[0])
+	if err != nil {
+
+		return nil, err
+	}
+	quantifier.Predicate, err = lowering.expression(expr.Operands[1])
+
+	return quantifier, err
+}
+
+func (lowering *lowering) call(name string, args []*Expr) (*ast.FunctionCall, error) {
+	call := ast.NewFunctionCall()
+	call.FunctionName = name
+	for _, arg := range args {
+		argument, err := lowering.expression(arg)
+		if err != nil {
+
+			return nil, err
+		}
+		call.ArgumentList.Arguments = append(call.ArgumentList.Arguments, argument)
+	}
+	call.GrlText = (&Expr{Kind: KindCall, Name: name, Operands: args}).String()
+	call.ArgumentList.GrlText = call.GrlText[len(name)+1 : len(call.GrlText)-1]
+
+	return call, nil
+}
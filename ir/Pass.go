@@ -0,0 +1,298 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ir
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// Pass rewrites a Program. A pass must keep what the rules do: the result of every expression, the errors and the
+// calls of the functions, which may have effects. Program.Transform is the usual way to implement one.
+type Pass interface {
+	// Name names the pass in the errors.
+	Name() string
+	Run(program *Program) error
+}
+
+// DefaultPasses returns the passes of this package, in the order they work best.
+func DefaultPasses() []Pass {
+
+	return []Pass{ConstantFolding{}, DeadBranchPruning{}, CommonSubexpressions{}}
+}
+
+// Optimize runs the passes over the program, one after the other.
+func Optimize(program *Program, passes ...Pass) error {
+	for _, pass := range passes {
+		err := pass.Run(program)
+		if err != nil {
+
+			return fmt.Errorf("error while running pass %s. got %w", pass.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// ConstantFolding evaluates the operations whose operands are all constants, such as 60 * 60 * 24, once when the
+// rules are built instead of at every evaluation. An operation that would fail is kept, so it fails at run time
+// as before. The comparisons of a string with a number are kept too, they depend on
+// ast.WorkingMemory.StringNumberComparison, and so are the ~== comparisons, whose tolerance may be the one of
+// ast.WorkingMemory.
+type ConstantFolding struct{}
+
+// Name implements Pass.
+func (ConstantFolding) Name() string {
+
+	return "constant folding"
+}
+
+// Run implements Pass.
+func (ConstantFolding) Run(program *Program) error {
+	program.Transform(func(expr *Expr) *Expr {
+		switch expr.Kind {
+		case KindNot:
+			if value, ok := constantValue(expr.Operands[0]); ok && value.Kind() == reflect.Bool {
+
+				return folded(expr, reflect.ValueOf(!value.Bool()), nil)
+			}
+		case KindBinary:
+			left, leftOk := constantValue(expr.Operands[0])
+			right, rightOk := constantValue(expr.Operands[1])
+			if !leftOk || !rightOk || expr.Operator == ast.OpApproxEq {
+
+				return expr
+			}
+			if expr.Operator >= ast.OpGT && expr.Operator <= ast.OpNEq && (left.Kind() == reflect.String) != (right.Kind() == reflect.String) {
+
+				return expr
+			}
+			value, err := evaluate(expr.Operator, left, right)
+
+			return folded(expr, value, err)
+		}
+
+		return expr
+	})
+
+	return nil
+}
+
+// folded returns the constant of the value an operation evaluated to, the operation if it failed.
+func folded(expr *Expr, value reflect.Value, err error) *Expr {
+	if err != nil {
+
+		return expr
+	}
+	constant, err := NewConstant(value)
+	if err != nil {
+
+		return expr
+	}
+
+	return constant
+}
+
+// constantValue returns the value of a constant the operators of pkg evaluate as it is. The nil, the quantities,
+// the collections and the suffix literals are left to run time.
+func constantValue(expr *Expr) (reflect.Value, bool) {
+	if expr.Kind != KindConstant || expr.Constant.IsNil || len(expr.Constant.Literal) > 0 {
+
+		return reflect.Value{}, false
+	}
+	switch expr.Constant.Value.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+
+		return expr.Constant.Value, true
+	}
+
+	return reflect.Value{}, false
+}
+
+// evaluate evaluates the binary operator as ast.Expression does.
+func evaluate(operator int, left, right reflect.Value) (reflect.Value, error) {
+	switch operator {
+	case ast.OpMul:
+
+		return pkg.EvaluateMultiplication(left, right)
+	case ast.OpDiv:
+
+		return pkg.EvaluateDivision(left, right)
+	case ast.OpMod:
+
+		return pkg.EvaluateModulo(left, right)
+	case ast.OpAdd:
+
+		return pkg.EvaluateAddition(left, right)
+	case ast.OpSub:
+
+		return pkg.EvaluateSubtraction(left, right)
+	case ast.OpBitAnd:
+
+		return pkg.EvaluateBitAnd(left, right)
+	case ast.OpBitOr:
+
+		return pkg.EvaluateBitOr(left, right)
+	case ast.OpGT:
+
+		return pkg.EvaluateGreaterThan(left, right)
+	case ast.OpLT:
+
+		return pkg.EvaluateLesserThan(left, right)
+	case ast.OpGTE:
+
+		return pkg.EvaluateGreaterThanEqual(left, right)
+	case ast.OpLTE:
+
+		return pkg.EvaluateLesserThanEqual(left, right)
+	case ast.OpEq:
+
+		return pkg.EvaluateEqual(left, right)
+	case ast.OpNEq:
+
+		return pkg.EvaluateNotEqual(left, right)
+	case ast.OpIn:
+
+		return pkg.EvaluateIn(left, right)
+	case ast.OpAnd:
+
+		return pkg.EvaluateLogicAnd(left, right)
+	case ast.OpOr:
+
+		return pkg.EvaluateLogicOr(left, right)
+	}
+
+	return reflect.Value{}, fmt.Errorf("unknown operator %d", operator)
+}
+
+// DeadBranchPruning removes the operands of the logical operators that can not change their result, such as the
+// right operand of false && Fact.Expensive(), which is never evaluated, or the true of Fact.Active && true.
+// A double negation of a boolean is removed as well. An operand is only removed when the result stays a boolean.
+type DeadBranchPruning struct{}
+
+// Name implements Pass.
+func (DeadBranchPruning) Name() string {
+
+	return "dead branch pruning"
+}
+
+// Run implements Pass.
+func (DeadBranchPruning) Run(program *Program) error {
+	program.Transform(func(expr *Expr) *Expr {
+		if expr.Kind == KindNot {
+			if operand := expr.Operands[0]; operand.Kind == KindNot && boolean(operand.Operands[0]) {
+
+				return operand.Operands[0]
+			}
+
+			return expr
+		}
+		if expr.Kind != KindBinary || (expr.Operator != ast.OpAnd && expr.Operator != ast.OpOr) {
+
+			return expr
+		}
+		// the operand deciding the result alone, false for &&, true for ||.
+		decisive := expr.Operator == ast.OpOr
+		left, right := expr.Operands[0], expr.Operands[1]
+		if value, ok := constantValue(left); ok && value.Kind() == reflect.Bool {
+			if value.Bool() == decisive {
+
+				return left
+			}
+			if boolean(right) {
+
+				return right
+			}
+		}
+		if value, ok := constantValue(right); ok && value.Kind() == reflect.Bool && value.Bool() != decisive && boolean(left) {
+
+			return left
+		}
+
+		return expr
+	})
+
+	return nil
+}
+
+// boolean tells whether the expression always evaluates to a boolean, when it does not fail.
+func boolean(expr *Expr) bool {
+	switch expr.Kind {
+	case KindConstant:
+		value, ok := constantValue(expr)
+
+		return ok && value.Kind() == reflect.Bool
+	case KindBinary:
+
+		return expr.Operator >= ast.OpGT
+	case KindNot:
+
+		return boolean(expr.Operands[0])
+	case KindExists, KindForAll:
+
+		return true
+	}
+
+	return false
+}
+
+// CommonSubexpressions shares the expressions that are written several times, such as the Order.Total() of
+// Order.Total() > 100 && Order.Total() < 500, so every one of them is a single Expr the passes after it, and the
+// back-ends, handle once. Lower turns a shared Expr into a shared AST node, whose value the engine evaluates once
+// until a fact it depends on changes.
+type CommonSubexpressions struct{}
+
+// Name implements Pass.
+func (CommonSubexpressions) Name() string {
+
+	return "common sub-expression elimination"
+}
+
+// Run implements Pass.
+func (CommonSubexpressions) Run(program *Program) error {
+	shared := make(map[string]*Expr)
+	program.Transform(func(expr *Expr) *Expr {
+		// the operands are shared already, their addresses tell them apart.
+		var key strings.Builder
+		fmt.Fprintf(&key, "%d:%d:%s", expr.Kind, expr.Operator, expr.Name)
+		if expr.Constant != nil {
+			key.WriteString(":")
+			if expr.Constant.IsNil {
+				key.WriteString("nil")
+			} else {
+				key.WriteString(expr.Constant.GetSnapshot())
+			}
+		}
+		for _, operand := range expr.Operands {
+			fmt.Fprintf(&key, ":%p", operand)
+		}
+		if existing, ok := shared[key.String()]; ok {
+
+			return existing
+		}
+		shared[key.String()] = expr
+
+		return expr
+	})
+
+	return nil
+}
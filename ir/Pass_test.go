@@ -0,0 +1,89 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package ir
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+const passRules = `
+rule Folded "folds the constants" {
+	when
+		Session.Age > 60 * 60 * 24 && "a" + "b" == Session.Name && 10 / 0 > 1 && 1 > "0"
+	then
+		Session.Limit = (2 + 3) * 1.5;
+		Session.Flag = !(1 > 2);
+}
+rule Pruned "prunes the dead branches" {
+	when
+		(false && Session.Expensive()) || (true && Session.Age > 2) || (Session.Age > 1 && true) || !(!(Session.Age > 3)) || (true && Session.Active)
+	then
+		Session.Total = Session.Price() * 2 + Session.Price() * 2;
+		Session.Flag = true && Session.Name;
+}
+halt when 1 > 2 || Session.Done == true;
+`
+
+func optimize(t *testing.T, passes ...Pass) *Program {
+	t.Helper()
+	program, err := Lift(parse(t, passRules))
+	assert.NoError(t, err)
+	assert.NoError(t, Optimize(program, passes...))
+
+	return program
+}
+
+func TestConstantFolding(t *testing.T) {
+	program := optimize(t, ConstantFolding{})
+	folded := program.Rules[0]
+	// the division by zero fails at run time, the string to number comparison depends on the working memory.
+	assert.Equal(t, `Session.Age>86400&&"ab"==Session.Name&&10/0>1&&1>"0"`, folded.When.String())
+	assert.Equal(t, "Session.Limit=7.5", folded.Then[0].String())
+	assert.Equal(t, "Session.Flag=true", folded.Then[1].String())
+	assert.Equal(t, KindConstant, folded.Then[0].Value.Kind)
+	assert.Equal(t, 7.5, folded.Then[0].Value.Constant.Value.Float())
+	assert.Equal(t, "false||Session.Done==true", program.Halts[0].When.String())
+
+	_, err := Lower(program, ast.NewWorkingMemory("T", "1"))
+	assert.NoError(t, err)
+}
+
+func TestDeadBranchPruning(t *testing.T) {
+	program := optimize(t, DeadBranchPruning{})
+	pruned := program.Rules[1]
+	// Session.Active and Session.Name may not be booleans, true && Session.Active is kept.
+	assert.Equal(t, "Session.Age>2||Session.Age>1||Session.Age>3||true&&Session.Active", pruned.When.String())
+	assert.Equal(t, "Session.Flag=true&&Session.Name", pruned.Then[1].String())
+	assert.Equal(t, "1>2||Session.Done==true", program.Halts[0].When.String())
+
+	program = optimize(t, DefaultPasses()...)
+	assert.Equal(t, "Session.Done==true", program.Halts[0].When.String())
+}
+
+func TestCommonSubexpressions(t *testing.T) {
+	program := optimize(t, CommonSubexpressions{})
+	total := program.Rules[1].Then[0].Value
+	assert.Equal(t, "Session.Price()*2+Session.Price()*2", total.String())
+	assert.Same(t, total.Operands[0], total.Operands[1])
+
+	memory := ast.NewWorkingMemory("T", "1")
+	lowered, err := Lower(program, memory)
+	assert.NoError(t, err)
+	expression := lowered.RuleEntries["Pruned"].ThenScope.ThenExpressionList.ThenExpressions[0].Assignment.Expression
+	assert.Same(t, expression.LeftExpression, expression.RightExpression)
+}
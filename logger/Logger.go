@@ -15,6 +15,8 @@
 package logger
 
 import (
+	"context"
+
 	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
@@ -77,6 +79,7 @@ type Logger interface {
 }
 
 var (
+	// Log is the default logger, used by every engine, builder and resource that is not given a logger of its own.
 	Log LogEntry
 )
 
@@ -89,32 +92,60 @@ func init() {
 // logrusLogger := logrus.New()
 // SetLogger(logrusLogger)
 func SetLogger(externalLog interface{}) {
-	switch externalLog.(type) {
+	if entry, ok := FromExternal(externalLog); ok {
+		Log = entry
+	}
+}
+
+// FromExternal wraps a *zap.Logger, a *logrus.Logger or a *zerolog.Logger into a LogEntry without changing the
+// default logger, so that an engine, a builder or a resource can be given a logger of its own. It returns false
+// for any other logger.
+func FromExternal(externalLog interface{}) (LogEntry, bool) {
+	switch log := externalLog.(type) {
 	case *zap.Logger:
-		log, ok := externalLog.(*zap.Logger)
-		if !ok {
 
-			return
-		}
-		Log = NewZap(log)
+		return NewZap(log), true
 	case *logrus.Logger:
-		log, ok := externalLog.(*logrus.Logger)
-		if !ok {
 
-			return
-		}
-		Log = NewLogrus(log)
+		return NewLogrus(log), true
 	case *zerolog.Logger:
-		log, ok := externalLog.(*zerolog.Logger)
-		if !ok {
 
-			return
-		}
-		Log = NewZero(log)
+		return NewZero(log), true
 	default:
 
-		return
+		return LogEntry{}, false
+	}
+}
+
+// Or returns this entry, or the fallback if this entry wraps no logger. The instances having an optional logger
+// use it to fall back on the logger of their package.
+func (entry LogEntry) Or(fallback LogEntry) LogEntry {
+	if entry.Logger == nil {
+
+		return fallback
+	}
+
+	return entry
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the entry, the resources loaded and the scripts executed with
+// that context log into it instead of the default logger.
+func NewContext(ctx context.Context, entry LogEntry) context.Context {
+
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the entry carried by the context, or an entry wrapping no logger if it carries none.
+func FromContext(ctx context.Context) LogEntry {
+	if ctx == nil {
+
+		return LogEntry{}
 	}
+	entry, _ := ctx.Value(contextKey{}).(LogEntry)
+
+	return entry
 }
 
 // SetLogLevel will set the logger log level
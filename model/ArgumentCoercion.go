@@ -0,0 +1,284 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrArgumentCoercion is wrapped by every ArgumentCoercionError.
+	ErrArgumentCoercion = errors.New("argument can not be coerced")
+
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// CoercionTimeLayouts are the layouts tried, in order, when a string argument is passed to a time.Time parameter.
+	CoercionTimeLayouts = []string{
+		time.RFC3339Nano,
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+// ArgumentCoercionError is returned when a function call argument can not be converted
+// into the type of the function parameter.
+type ArgumentCoercionError struct {
+	// Function is the called function name.
+	Function string
+	// Index is the zero based argument index, or -1 if the number of arguments is wrong.
+	Index int
+	// From is the type of the supplied argument, nil if the argument is nil or missing.
+	From reflect.Type
+	// To is the type of the function parameter.
+	To reflect.Type
+	// Reason tells why the coercion failed.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ArgumentCoercionError) Error() string {
+	if e.Index < 0 {
+
+		return fmt.Sprintf("function %s %s", e.Function, e.Reason)
+	}
+	from := "nil"
+	if e.From != nil {
+		from = e.From.String()
+	}
+
+	return fmt.Sprintf("function %s argument %d can not be coerced from %s into %s, %s", e.Function, e.Index, from, e.To.String(), e.Reason)
+}
+
+// Unwrap returns ErrArgumentCoercion so the error can be tested with errors.Is.
+func (e *ArgumentCoercionError) Unwrap() error {
+
+	return ErrArgumentCoercion
+}
+
+// CoerceArguments converts every argument into the parameter type of the function, so the function can be called with
+// reflect.Value.Call without panic. Variadic arguments are converted into the variadic element type.
+func CoerceArguments(funcName string, funcType reflect.Type, args []reflect.Value) ([]reflect.Value, error) {
+	numIn := funcType.NumIn()
+	if funcType.IsVariadic() {
+		if len(args) < numIn-1 {
+
+			return nil, &ArgumentCoercionError{Function: funcName, Index: -1, Reason: fmt.Sprintf("requires at least %d arguments, got %d", numIn-1, len(args))}
+		}
+	} else if len(args) != numIn {
+
+		return nil, &ArgumentCoercionError{Function: funcName, Index: -1, Reason: fmt.Sprintf("requires %d arguments, got %d", numIn, len(args))}
+	}
+
+	ret := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var target reflect.Type
+		if funcType.IsVariadic() && i >= numIn-1 {
+			target = funcType.In(numIn - 1).Elem()
+		} else {
+			target = funcType.In(i)
+		}
+		val, reason := CoerceValue(arg, target)
+		if len(reason) > 0 {
+			err := &ArgumentCoercionError{Function: funcName, Index: i, To: target, Reason: reason}
+			if arg.IsValid() {
+				err.From = arg.Type()
+			}
+
+			return nil, err
+		}
+		ret[i] = val
+	}
+
+	return ret, nil
+}
+
+// CoerceValue converts the value into the target type according to the coercion matrix.
+// It returns the converted value, or a non empty reason when the conversion is not possible.
+//
+//   - nil goes into pointer, interface, slice, map, func and chan as their zero value.
+//   - any value goes as is into a type it is assignable to, including interfaces it implements.
+//   - a value goes into a pointer of its coerced type, and a non nil pointer goes into the coerced type of its element.
+//   - integers go into any integer or float type if the value fits, negative values do not go into unsigned types.
+//   - floats go into any float type if the value fits, and into integer types only if they have no fraction.
+//   - strings go into any string type, into time.Time using CoercionTimeLayouts and into time.Duration using time.ParseDuration.
+//   - bools go into any bool type.
+//
+// Strings are never parsed into numbers or bools, and numbers are never formatted into strings.
+func CoerceValue(val reflect.Value, target reflect.Type) (reflect.Value, string) {
+	if !val.IsValid() {
+		switch target.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+
+			return reflect.Zero(target), ""
+		}
+
+		return reflect.Value{}, "nil is not allowed"
+	}
+	if val.Type().AssignableTo(target) {
+
+		return val, ""
+	}
+	if target.Kind() == reflect.Ptr {
+		elem, reason := CoerceValue(val, target.Elem())
+		if len(reason) > 0 {
+
+			return reflect.Value{}, reason
+		}
+		ptr := reflect.New(target.Elem())
+		ptr.Elem().Set(elem)
+
+		return ptr, ""
+	}
+	if val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+
+			return reflect.Value{}, "nil is not allowed"
+		}
+
+		return CoerceValue(val.Elem(), target)
+	}
+
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return coerceInt(val, val.Int(), target)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if val.Uint() > math.MaxInt64 {
+			if target.Kind() == reflect.Uint64 || target.Kind() == reflect.Uint || target.Kind() == reflect.Uintptr {
+
+				return val.Convert(target), ""
+			}
+			if target.Kind() == reflect.Float32 || target.Kind() == reflect.Float64 {
+
+				return reflect.ValueOf(float64(val.Uint())).Convert(target), ""
+			}
+
+			return reflect.Value{}, fmt.Sprintf("value %d overflows", val.Uint())
+		}
+
+		return coerceInt(val, int64(val.Uint()), target)
+	case reflect.Float32, reflect.Float64:
+
+		return coerceFloat(val.Float(), target)
+	case reflect.String:
+
+		return coerceString(val, target)
+	case reflect.Bool:
+		if target.Kind() == reflect.Bool {
+
+			return val.Convert(target), ""
+		}
+	}
+	if val.Type().ConvertibleTo(target) && val.Kind() == target.Kind() {
+
+		return val.Convert(target), ""
+	}
+
+	return reflect.Value{}, "no coercion exists between these types"
+}
+
+func coerceInt(val reflect.Value, i int64, target reflect.Type) (reflect.Value, string) {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		ret := reflect.New(target).Elem()
+		if ret.OverflowInt(i) {
+
+			return reflect.Value{}, fmt.Sprintf("value %d overflows", i)
+		}
+		ret.SetInt(i)
+
+		return ret, ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		ret := reflect.New(target).Elem()
+		if i < 0 {
+
+			return reflect.Value{}, fmt.Sprintf("negative value %d is not allowed", i)
+		}
+		if ret.OverflowUint(uint64(i)) {
+
+			return reflect.Value{}, fmt.Sprintf("value %d overflows", i)
+		}
+		ret.SetUint(uint64(i))
+
+		return ret, ""
+	case reflect.Float32, reflect.Float64:
+
+		return reflect.ValueOf(float64(i)).Convert(target), ""
+	}
+
+	return reflect.Value{}, fmt.Sprintf("%s value can not be used as %s", val.Kind().String(), target.Kind().String())
+}
+
+func coerceFloat(f float64, target reflect.Type) (reflect.Value, string) {
+	switch target.Kind() {
+	case reflect.Float32, reflect.Float64:
+		ret := reflect.New(target).Elem()
+		if ret.OverflowFloat(f) {
+
+			return reflect.Value{}, fmt.Sprintf("value %g overflows", f)
+		}
+		ret.SetFloat(f)
+
+		return ret, ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+
+			return reflect.Value{}, fmt.Sprintf("value %g has a fraction", f)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+
+			return reflect.Value{}, fmt.Sprintf("value %g overflows", f)
+		}
+
+		return coerceInt(reflect.ValueOf(f), int64(f), target)
+	}
+
+	return reflect.Value{}, fmt.Sprintf("float value can not be used as %s", target.Kind().String())
+}
+
+func coerceString(val reflect.Value, target reflect.Type) (reflect.Value, string) {
+	switch {
+	case target == timeType:
+		for _, layout := range CoercionTimeLayouts {
+			if t, err := time.Parse(layout, val.String()); err == nil {
+
+				return reflect.ValueOf(t), ""
+			}
+		}
+
+		return reflect.Value{}, fmt.Sprintf("\"%s\" is not an ISO 8601 time", val.String())
+	case target == durationType:
+		d, err := time.ParseDuration(val.String())
+		if err != nil {
+
+			return reflect.Value{}, fmt.Sprintf("\"%s\" is not a duration", val.String())
+		}
+
+		return reflect.ValueOf(d), ""
+	case target.Kind() == reflect.String:
+
+		return val.Convert(target), ""
+	}
+
+	return reflect.Value{}, fmt.Sprintf("string value can not be used as %s", target.Kind().String())
+}
@@ -0,0 +1,141 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Currency string
+
+type Coerced struct {
+	Result string
+}
+
+func (c *Coerced) Narrow(a int32, b uint8, f float32) {
+	c.Result = fmt.Sprintf("%d %d %g", a, b, f)
+}
+
+func (c *Coerced) Optional(name *string, code Currency) {
+	if name == nil {
+		c.Result = "nil " + string(code)
+	} else {
+		c.Result = *name + " " + string(code)
+	}
+}
+
+func (c *Coerced) When(t time.Time, d time.Duration) {
+	c.Result = t.UTC().Format(time.RFC3339) + " " + d.String()
+}
+
+func (c *Coerced) Join(sep string, parts ...int) {
+	strs := make([]string, len(parts))
+	for i, p := range parts {
+		strs[i] = fmt.Sprint(p)
+	}
+	c.Result = strings.Join(strs, sep)
+}
+
+func TestCoerceValue(t *testing.T) {
+	int8Type := reflect.TypeOf(int8(0))
+	uintType := reflect.TypeOf(uint(0))
+	intType := reflect.TypeOf(0)
+	float32Type := reflect.TypeOf(float32(0))
+	strPtrType := reflect.TypeOf((*string)(nil))
+
+	testData := []struct {
+		value  interface{}
+		target reflect.Type
+		expect interface{}
+		fail   bool
+	}{
+		{int64(100), int8Type, int8(100), false},
+		{int64(200), int8Type, nil, true},
+		{int64(-1), uintType, nil, true},
+		{uint64(7), intType, 7, false},
+		{int64(3), float32Type, float32(3), false},
+		{float64(4), intType, 4, false},
+		{float64(4.5), intType, nil, true},
+		{float64(1e300), float32Type, nil, true},
+		{"IDR", reflect.TypeOf(Currency("")), Currency("IDR"), false},
+		{"123", intType, nil, true},
+		{int64(123), reflect.TypeOf(""), nil, true},
+		{"2024-02-03", reflect.TypeOf(time.Time{}), time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), false},
+		{"yesterday", reflect.TypeOf(time.Time{}), nil, true},
+		{"1m30s", reflect.TypeOf(time.Duration(0)), 90 * time.Second, false},
+		{true, reflect.TypeOf(false), true, false},
+		{nil, strPtrType, (*string)(nil), false},
+		{nil, intType, nil, true},
+	}
+	for i, td := range testData {
+		ret, reason := CoerceValue(reflect.ValueOf(td.value), td.target)
+		if td.fail {
+			assert.NotEmpty(t, reason, "test %d", i)
+
+			continue
+		}
+		assert.Empty(t, reason, "test %d", i)
+		assert.Equal(t, td.expect, ret.Interface(), "test %d", i)
+	}
+
+	ret, reason := CoerceValue(reflect.ValueOf("abc"), strPtrType)
+	assert.Empty(t, reason)
+	assert.Equal(t, "abc", *(ret.Interface().(*string)))
+}
+
+func TestCallFunctionCoercion(t *testing.T) {
+	coerced := &Coerced{}
+	node := NewGoValueNode(reflect.ValueOf(coerced), "Coerced")
+
+	_, err := node.CallFunction("Narrow", reflect.ValueOf(int64(-5)), reflect.ValueOf(int64(255)), reflect.ValueOf(float64(1.5)))
+	assert.NoError(t, err)
+	assert.Equal(t, "-5 255 1.5", coerced.Result)
+
+	_, err = node.CallFunction("Optional", reflect.ValueOf("Bob"), reflect.ValueOf("USD"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bob USD", coerced.Result)
+
+	_, err = node.CallFunction("Optional", reflect.Value{}, reflect.ValueOf("USD"))
+	assert.NoError(t, err)
+	assert.Equal(t, "nil USD", coerced.Result)
+
+	_, err = node.CallFunction("When", reflect.ValueOf("2024-02-03T04:05:06+07:00"), reflect.ValueOf("2h"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02-02T21:05:06Z 2h0m0s", coerced.Result)
+
+	_, err = node.CallFunction("Join", reflect.ValueOf(","), reflect.ValueOf(int64(1)), reflect.ValueOf(float64(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, "1,2", coerced.Result)
+
+	_, err = node.CallFunction("Narrow", reflect.ValueOf(int64(1)), reflect.ValueOf(int64(256)), reflect.ValueOf(float64(1)))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrArgumentCoercion))
+	var coercionErr *ArgumentCoercionError
+	assert.True(t, errors.As(err, &coercionErr))
+	assert.Equal(t, 1, coercionErr.Index)
+	assert.Equal(t, reflect.TypeOf(uint8(0)), coercionErr.To)
+
+	_, err = node.CallFunction("Narrow", reflect.ValueOf(int64(1)))
+	assert.True(t, errors.As(err, &coercionErr))
+	assert.Equal(t, -1, coercionErr.Index)
+	assert.Contains(t, err.Error(), "requires 3 arguments, got 1")
+}
@@ -0,0 +1,248 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCast is wrapped by every error of the explicit casts.
+var ErrCast = errors.New("value can not be cast")
+
+// castError returns the error casting the value into the named type.
+func castError(val reflect.Value, to, reason string) error {
+	from := "nil"
+	if val.IsValid() {
+		from = val.Type().String()
+	}
+
+	return fmt.Errorf("%w from %s into %s, %s", ErrCast, from, to, reason)
+}
+
+// castElem returns the value a pointer or an interface points to, or an invalid value if it is nil.
+func castElem(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+
+	return val
+}
+
+// CastToInt converts the value into an int64, unlike the implicit coercion of the function arguments:
+//
+//   - integers are kept if they fit, unsigned values above math.MaxInt64 fail.
+//   - floats are truncated toward zero, NaN, infinities and values out of the int64 range fail.
+//   - strings are parsed as base 10 integers, surrounding spaces ignored.
+//
+// nil, bools and any other type fail.
+func CastToInt(val reflect.Value) (int64, error) {
+	val = castElem(val)
+	if !val.IsValid() {
+
+		return 0, castError(val, "int64", "nil is not allowed")
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return val.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if val.Uint() > math.MaxInt64 {
+
+			return 0, castError(val, "int64", fmt.Sprintf("value %d overflows", val.Uint()))
+		}
+
+		return int64(val.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := math.Trunc(val.Float())
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+
+			return 0, castError(val, "int64", fmt.Sprintf("value %g overflows", val.Float()))
+		}
+
+		return int64(f), nil
+	case reflect.String:
+		i, err := strconv.ParseInt(strings.TrimSpace(val.String()), 10, 64)
+		if err != nil {
+
+			return 0, castError(val, "int64", fmt.Sprintf("%q is not an integer", val.String()))
+		}
+
+		return i, nil
+	}
+
+	return 0, castError(val, "int64", "no cast exists between these types")
+}
+
+// CastToUint converts the value into an uint64. It follows CastToInt, except that negative values fail,
+// floats down to -1 excluded truncate to 0, and values up to math.MaxUint64 are kept.
+func CastToUint(val reflect.Value) (uint64, error) {
+	val = castElem(val)
+	if !val.IsValid() {
+
+		return 0, castError(val, "uint64", "nil is not allowed")
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if val.Int() < 0 {
+
+			return 0, castError(val, "uint64", fmt.Sprintf("negative value %d is not allowed", val.Int()))
+		}
+
+		return uint64(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		return val.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := math.Trunc(val.Float())
+		if f < 0 {
+
+			return 0, castError(val, "uint64", fmt.Sprintf("negative value %g is not allowed", val.Float()))
+		}
+		if math.IsNaN(f) || f >= math.MaxUint64 {
+
+			return 0, castError(val, "uint64", fmt.Sprintf("value %g overflows", val.Float()))
+		}
+
+		return uint64(f), nil
+	case reflect.String:
+		i, err := strconv.ParseUint(strings.TrimSpace(val.String()), 10, 64)
+		if err != nil {
+
+			return 0, castError(val, "uint64", fmt.Sprintf("%q is not an unsigned integer", val.String()))
+		}
+
+		return i, nil
+	}
+
+	return 0, castError(val, "uint64", "no cast exists between these types")
+}
+
+// CastToFloat converts the value into a float64. Integers and floats are converted, integers beyond 2^53 losing
+// precision, and strings are parsed as decimal numbers. nil, bools and any other type fail.
+func CastToFloat(val reflect.Value) (float64, error) {
+	val = castElem(val)
+	if !val.IsValid() {
+
+		return 0, castError(val, "float64", "nil is not allowed")
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return float64(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		return float64(val.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+
+		return val.Float(), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val.String()), 64)
+		if err != nil {
+
+			return 0, castError(val, "float64", fmt.Sprintf("%q is not a number", val.String()))
+		}
+
+		return f, nil
+	}
+
+	return 0, castError(val, "float64", "no cast exists between these types")
+}
+
+// CastToString formats the value into a string. Integers are formatted in base 10, floats in the shortest
+// representation that reads back the same, bools as true or false, times as RFC 3339 and any fmt.Stringer
+// with its String. nil and any other type fail.
+func CastToString(val reflect.Value) (string, error) {
+	if val.IsValid() && val.CanInterface() {
+		if stringer, ok := val.Interface().(fmt.Stringer); ok && !(val.Kind() == reflect.Ptr && val.IsNil()) {
+			if t, ok := stringer.(time.Time); ok {
+
+				return t.Format(time.RFC3339Nano), nil
+			}
+
+			return stringer.String(), nil
+		}
+	}
+	val = castElem(val)
+	if !val.IsValid() {
+
+		return "", castError(val, "string", "nil is not allowed")
+	}
+	switch val.Kind() {
+	case reflect.String:
+
+		return val.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32:
+
+		return strconv.FormatFloat(val.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+
+		return strconv.FormatFloat(val.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+
+		return strconv.FormatBool(val.Bool()), nil
+	}
+
+	return "", castError(val, "string", "no cast exists between these types")
+}
+
+// CastToBool converts the value into a bool. Strings are parsed with strconv.ParseBool, such as "true", "F" or "1",
+// and the integers 0 and 1 are false and true. nil, floats, any other integer and any other type fail.
+func CastToBool(val reflect.Value) (bool, error) {
+	val = castElem(val)
+	if !val.IsValid() {
+
+		return false, castError(val, "bool", "nil is not allowed")
+	}
+	switch val.Kind() {
+	case reflect.Bool:
+
+		return val.Bool(), nil
+	case reflect.String:
+		b, err := strconv.ParseBool(strings.TrimSpace(val.String()))
+		if err != nil {
+
+			return false, castError(val, "bool", fmt.Sprintf("%q is not a boolean", val.String()))
+		}
+
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := CastToUint(val)
+		if err != nil || i > 1 {
+
+			return false, castError(val, "bool", "only 0 and 1 are allowed")
+		}
+
+		return i == 1, nil
+	}
+
+	return false, castError(val, "bool", "no cast exists between these types")
+}
@@ -0,0 +1,115 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type castStringer int
+
+func (s castStringer) String() string {
+
+	return "level-" + string(rune('0'+int(s)))
+}
+
+func TestCast(t *testing.T) {
+	seven := int64(7)
+	testData := []struct {
+		cast     func(reflect.Value) (interface{}, error)
+		value    interface{}
+		expected interface{}
+		fails    bool
+	}{
+		{castInt, int8(-3), int64(-3), false},
+		{castInt, uint64(math.MaxInt64), int64(math.MaxInt64), false},
+		{castInt, uint64(math.MaxInt64 + 1), nil, true},
+		{castInt, -2.9, int64(-2), false},
+		{castInt, 1e19, nil, true},
+		{castInt, math.NaN(), nil, true},
+		{castInt, " 42 ", int64(42), false},
+		{castInt, "4.2", nil, true},
+		{castInt, &seven, int64(7), false},
+		{castInt, true, nil, true},
+		{castInt, nil, nil, true},
+		{castUint, -1, nil, true},
+		{castUint, -0.5, uint64(0), false},
+		{castUint, uint64(math.MaxUint64), uint64(math.MaxUint64), false},
+		{castUint, 2e19, nil, true},
+		{castUint, "18446744073709551615", uint64(math.MaxUint64), false},
+		{castUint, "-1", nil, true},
+		{castFloat, int64(3), float64(3), false},
+		{castFloat, uint8(255), float64(255), false},
+		{castFloat, "2.5e3", float64(2500), false},
+		{castFloat, "abc", nil, true},
+		{castFloat, false, nil, true},
+		{castString, int64(-12), "-12", false},
+		{castString, uint64(math.MaxUint64), "18446744073709551615", false},
+		{castString, 0.1, "0.1", false},
+		{castString, float32(0.1), "0.1", false},
+		{castString, true, "true", false},
+		{castString, castStringer(3), "level-3", false},
+		{castString, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), "2024-02-29T12:00:00Z", false},
+		{castString, []int{1}, nil, true},
+		{castString, nil, nil, true},
+		{castBool, "T", true, false},
+		{castBool, "no", nil, true},
+		{castBool, 0, false, false},
+		{castBool, uint8(1), true, false},
+		{castBool, 2, nil, true},
+		{castBool, 1.0, nil, true},
+	}
+	for i, td := range testData {
+		ret, err := td.cast(reflect.ValueOf(td.value))
+		if td.fails {
+			assert.True(t, errors.Is(err, ErrCast), "test %d expected a cast error", i)
+
+			continue
+		}
+		assert.NoError(t, err, "test %d", i)
+		assert.Equal(t, td.expected, ret, "test %d", i)
+	}
+}
+
+func castInt(val reflect.Value) (interface{}, error) {
+
+	return CastToInt(val)
+}
+
+func castUint(val reflect.Value) (interface{}, error) {
+
+	return CastToUint(val)
+}
+
+func castFloat(val reflect.Value) (interface{}, error) {
+
+	return CastToFloat(val)
+}
+
+func castString(val reflect.Value) (interface{}, error) {
+
+	return CastToString(val)
+}
+
+func castBool(val reflect.Value) (interface{}, error) {
+
+	return CastToBool(val)
+}
@@ -0,0 +1,151 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AccessorConvention tells how the field of a struct is resolved through its methods when the struct has no such
+// exported field, e.g. `Fact.Status` calls `GetStatus()` and `Fact.Status = "x"` calls `SetStatus("x")`.
+type AccessorConvention struct {
+	// GetterPrefixes are tried in order, an empty prefix resolves `Fact.Status` with `Status()`.
+	// A getter takes no argument and returns exactly one value.
+	GetterPrefixes []string
+	// SetterPrefix names the setter, it takes exactly one argument and returns nothing or an error.
+	// No setter is used if empty.
+	SetterPrefix string
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// StandardAccessors returns the convention resolving `Fact.Status` with `GetStatus()` and assigning it with
+// `SetStatus(...)`. No convention is used unless the data context is created with one, see NewGoValueNodeWithAccessors.
+func StandardAccessors() AccessorConvention {
+
+	return AccessorConvention{
+		GetterPrefixes: []string{"Get"},
+		SetterPrefix:   "Set",
+	}
+}
+
+// getter returns the getter method of the field, an invalid value if there is none or no convention.
+func (convention *AccessorConvention) getter(object reflect.Value, field string) reflect.Value {
+	if convention == nil {
+
+		return reflect.Value{}
+	}
+	for _, prefix := range convention.GetterPrefixes {
+		method := methodOf(object, prefix+field)
+		if method.IsValid() && method.Type().NumIn() == 0 && method.Type().NumOut() == 1 {
+
+			return method
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// setter returns the setter method of the field, an invalid value if there is none or no convention.
+func (convention *AccessorConvention) setter(object reflect.Value, field string) reflect.Value {
+	if convention == nil || len(convention.SetterPrefix) == 0 {
+
+		return reflect.Value{}
+	}
+	method := methodOf(object, convention.SetterPrefix+field)
+	if !method.IsValid() || method.Type().NumIn() != 1 || method.Type().IsVariadic() {
+
+		return reflect.Value{}
+	}
+	switch method.Type().NumOut() {
+	case 0:
+
+		return method
+	case 1:
+		if method.Type().Out(0) == errorType {
+
+			return method
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// methodOf looks up the method on the pointer to the struct when possible, so pointer receivers are found too.
+func methodOf(object reflect.Value, name string) reflect.Value {
+	if object.Kind() == reflect.Interface && !object.IsNil() {
+		object = object.Elem()
+	}
+	if object.Kind() == reflect.Struct && object.CanAddr() {
+		object = object.Addr()
+	}
+	if !object.IsValid() || (object.Kind() == reflect.Ptr && object.IsNil()) {
+
+		return reflect.Value{}
+	}
+
+	return object.MethodByName(name)
+}
+
+// hasField tells whether the underlying struct has the field, whether exported or not.
+func (node *GoValueNode) hasField(field string) bool {
+	typ := node.thisValue.Type()
+	if node.thisValue.Kind() == reflect.Interface && !node.thisValue.IsNil() {
+		typ = node.thisValue.Elem().Type()
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+
+		return false
+	}
+
+	return node.options.Types.hasFieldNamed(typ, field)
+}
+
+// getThroughAccessor returns the field value by calling its getter.
+func (node *GoValueNode) getThroughAccessor(field string) (reflect.Value, bool) {
+	getter := node.options.Accessors.getter(node.thisValue, field)
+	if !getter.IsValid() {
+
+		return reflect.Value{}, false
+	}
+
+	return getter.Call(nil)[0], true
+}
+
+// setThroughAccessor assigns the field value by calling its setter, the value is coerced into the setter argument.
+func (node *GoValueNode) setThroughAccessor(field string, newValue reflect.Value) (bool, error) {
+	setter := node.options.Accessors.setter(node.thisValue, field)
+	if !setter.IsValid() {
+
+		return false, nil
+	}
+	name := node.options.Accessors.SetterPrefix + field
+	args, err := CoerceArguments(name, setter.Type(), []reflect.Value{newValue})
+	if err != nil {
+
+		return true, fmt.Errorf("this node identified as \"%s\" assigning field %s. got %w", node.IdentifiedAs(), field, err)
+	}
+	rets := setter.Call(args)
+	if len(rets) == 1 && !rets[0].IsNil() {
+
+		return true, fmt.Errorf("this node identified as \"%s\" assigning field %s. got %w", node.IdentifiedAs(), field, rets[0].Interface().(error))
+	}
+
+	return true, nil
+}
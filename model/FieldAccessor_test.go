@@ -0,0 +1,73 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Account struct {
+	balance float64
+	active  bool
+}
+
+func (a *Account) GetBalance() float64 {
+	return a.balance
+}
+
+func (a *Account) SetBalance(balance float64) {
+	a.balance = balance
+}
+
+func (a *Account) IsActive() bool {
+	return a.active
+}
+
+func TestGoValueNode_Accessors(t *testing.T) {
+	account := &Account{balance: 10, active: true}
+	// the fields are not resolved through the methods unless the node is created with a convention.
+	_, err := NewGoValueNode(reflect.ValueOf(account), "account").GetObjectValueByField("Balance")
+	assert.Error(t, err)
+	standard := StandardAccessors()
+	node := NewGoValueNodeWithAccessors(reflect.ValueOf(account), "account", &standard)
+	val, err := node.GetObjectValueByField("Balance")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, val.Float())
+	typ, err := node.GetObjectTypeByField("Balance")
+	assert.NoError(t, err)
+	assert.Equal(t, reflect.Float64, typ.Kind())
+
+	// the integer is coerced into the setter argument.
+	assert.NoError(t, node.SetObjectValueByField("Balance", reflect.ValueOf(int64(25))))
+	assert.Equal(t, 25.0, account.balance)
+	err = node.SetObjectValueByField("Balance", reflect.ValueOf("a lot"))
+	assert.ErrorIs(t, err, ErrArgumentCoercion)
+
+	_, err = node.GetObjectValueByField("Active")
+	assert.Error(t, err)
+	node = NewGoValueNodeWithAccessors(reflect.ValueOf(account), "account", &AccessorConvention{GetterPrefixes: []string{"Get", "Is"}})
+	val, err = node.GetObjectValueByField("Active")
+	assert.NoError(t, err)
+	assert.True(t, val.Bool())
+	// without a setter prefix the field can not be assigned.
+	assert.Error(t, node.SetObjectValueByField("Balance", reflect.ValueOf(1.0)))
+
+	node = NewGoValueNodeWithAccessors(reflect.ValueOf(account), "account", &AccessorConvention{})
+	_, err = node.GetObjectValueByField("Balance")
+	assert.Error(t, err)
+}
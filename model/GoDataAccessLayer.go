@@ -21,13 +21,41 @@ import (
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
 )
 
-// NewGoValueNode creates new instance of ValueNode backed by golang reflection
+// NewGoValueNode creates new instance of ValueNode backed by golang reflection.
+// A pointer to an interface variable, such as &provider, is the value of that variable typed as the interface, so
+// the rules call the methods of the interface whatever the concrete type assigned to it.
 func NewGoValueNode(value reflect.Value, identifiedAs string) ValueNode {
 
+	return NewGoValueNodeWithAccessors(value, identifiedAs, nil)
+}
+
+// NewGoValueNodeWithAccessors creates new instance of ValueNode that resolves the fields a struct does not export
+// through its methods, as named by the convention. The nodes of its fields and function results follow it too.
+// A nil convention only resolves the exported fields.
+func NewGoValueNodeWithAccessors(value reflect.Value, identifiedAs string, accessors *AccessorConvention) ValueNode {
+
+	return NewGoValueNodeWithOptions(value, identifiedAs, NodeOptions{Accessors: accessors})
+}
+
+// NodeOptions are the settings of a GoValueNode, the nodes of its fields, elements and function results have them too.
+type NodeOptions struct {
+	// Accessors, if set, resolves the fields a struct does not export through its methods.
+	Accessors *AccessorConvention
+	// Types, if set, caches the field layout of the structs instead of the cache of PrepareType.
+	Types *TypeDescriptors
+}
+
+// NewGoValueNodeWithOptions creates new instance of ValueNode with the options.
+func NewGoValueNodeWithOptions(value reflect.Value, identifiedAs string, options NodeOptions) ValueNode {
+	if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
 	return &GoValueNode{
 		parentNode:   nil,
 		identifiedAs: identifiedAs,
 		thisValue:    value,
+		options:      options,
 	}
 }
 
@@ -36,6 +64,7 @@ type GoValueNode struct {
 	parentNode   ValueNode
 	identifiedAs string
 	thisValue    reflect.Value
+	options      NodeOptions
 }
 
 // Value \n\nreturns the underlying reflect.Value
@@ -78,6 +107,7 @@ func (node *GoValueNode) ContinueWithValue(value reflect.Value, identifiedAs str
 		parentNode:   node,
 		identifiedAs: identifiedAs,
 		thisValue:    value,
+		options:      node.options,
 	}
 }
 
@@ -286,20 +316,24 @@ func (node *GoValueNode) GetObjectValueByField(field string) (reflect.Value, err
 			if !node.thisValue.IsNil() {
 				elem := node.thisValue.Elem()
 				if elem.Kind() == reflect.Ptr {
-					val = elem.Elem().FieldByName(field)
+					val = node.options.Types.fieldByName(elem.Elem(), field)
 				} else if elem.Kind() == reflect.Struct {
-					val = elem.FieldByName(field)
+					val = node.options.Types.fieldByName(elem, field)
 				}
 			}
 		} else if node.thisValue.Kind() == reflect.Ptr {
-			val = node.thisValue.Elem().FieldByName(field)
+			val = node.options.Types.fieldByName(node.thisValue.Elem(), field)
 		} else if node.thisValue.Kind() == reflect.Struct {
-			val = node.thisValue.FieldByName(field)
+			val = node.options.Types.fieldByName(node.thisValue, field)
 		}
 
 		if val.IsValid() {
 			return val, nil
 		}
+		if val, ok := node.getThroughAccessor(field); ok {
+
+			return val, nil
+		}
 
 		return reflect.Value{}, fmt.Errorf("this node have no field named %s", field)
 	}
@@ -310,6 +344,12 @@ func (node *GoValueNode) GetObjectValueByField(field string) (reflect.Value, err
 // GetObjectTypeByField will return underlying type of the value's field
 func (node *GoValueNode) GetObjectTypeByField(field string) (typ reflect.Type, err error) {
 	if node.IsObject() {
+		if !node.hasField(field) {
+			if getter := node.options.Accessors.getter(node.thisValue, field); getter.IsValid() {
+
+				return getter.Type().Out(0), nil
+			}
+		}
 		defer func() {
 			if r := recover(); r != nil {
 				err = fmt.Errorf("recovered : %v", r)
@@ -322,61 +362,27 @@ func (node *GoValueNode) GetObjectTypeByField(field string) (typ reflect.Type, e
 			if !node.thisValue.IsNil() {
 				elem := node.thisValue.Elem()
 				if elem.Kind() == reflect.Ptr {
-					return elem.Elem().FieldByName(field).Type(), nil
+					return node.options.Types.fieldByName(elem.Elem(), field).Type(), nil
 				} else if elem.Kind() == reflect.Struct {
-					return elem.FieldByName(field).Type(), nil
+					return node.options.Types.fieldByName(elem, field).Type(), nil
 				}
 			}
 		} else if node.thisValue.Kind() == reflect.Ptr {
-			return node.thisValue.Elem().FieldByName(field).Type(), nil
+			return node.options.Types.fieldByName(node.thisValue.Elem(), field).Type(), nil
 		} else if node.thisValue.Kind() == reflect.Struct {
-			return node.thisValue.FieldByName(field).Type(), nil
+			return node.options.Types.fieldByName(node.thisValue, field).Type(), nil
 		}
 	}
 
 	return nil, fmt.Errorf("this node identified as \"%s\" is not referring to an object", node.IdentifiedAs())
 }
 
-// SetNumberValue will assign a numeric value to a numeric target value
-// this helper function is to ensure assignment between numerical types is happening regardless of types, int, uint or float.
-// The rule designer should be careful as conversion of types in automatic way like this will cause lost of precision
-// during conversion. This will be removed in the future version.
+// SetNumberValue will assign a numeric value to a numeric target value, int, uint or float, with pkg.SetNumberValue.
+// A value that does not fit the target, such as a negative value into an unsigned target, is not assigned.
 func SetNumberValue(target, newvalue reflect.Value) error {
 	if pkg.IsNumber(target) && pkg.IsNumber(newvalue) {
-		switch target.Type().Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if pkg.GetBaseKind(newvalue) == reflect.Uint64 {
-				target.SetInt(int64(newvalue.Uint()))
-			} else if pkg.GetBaseKind(newvalue) == reflect.Float64 {
-				target.SetInt(int64(newvalue.Float()))
-			} else {
-				target.SetInt(newvalue.Int())
-			}
 
-			return nil
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			if pkg.GetBaseKind(newvalue) == reflect.Uint64 {
-				target.SetUint(newvalue.Uint())
-			} else if pkg.GetBaseKind(newvalue) == reflect.Float64 {
-				target.SetUint(uint64(newvalue.Float()))
-			} else {
-				target.SetUint(uint64(newvalue.Int()))
-			}
-
-			return nil
-		case reflect.Float32, reflect.Float64:
-			if pkg.GetBaseKind(newvalue) == reflect.Uint64 {
-				target.SetFloat(float64(newvalue.Uint()))
-			} else if pkg.GetBaseKind(newvalue) == reflect.Float64 {
-				target.SetFloat(newvalue.Float())
-			} else {
-				target.SetFloat(float64(newvalue.Int()))
-			}
-
-			return nil
-		}
-
-		return fmt.Errorf("this line should not be reached")
+		return pkg.SetNumberValue(target, newvalue)
 	}
 
 	return fmt.Errorf("this function only used for assigning number data to number variable")
@@ -396,7 +402,13 @@ func (node *GoValueNode) SetObjectValueByField(field string, newValue reflect.Va
 		objValue = objValue.Elem()
 	}
 
-	fieldVal := objValue.FieldByName(field)
+	fieldVal := node.options.Types.fieldByName(objValue, field)
+	if !fieldVal.IsValid() {
+		if ok, err := node.setThroughAccessor(field, newValue); ok {
+
+			return err
+		}
+	}
 	if fieldVal.IsValid() && fieldVal.CanAddr() && fieldVal.CanSet() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -418,6 +430,12 @@ func (node *GoValueNode) SetObjectValueByField(field string, newValue reflect.Va
 // CallFunction will call a function owned by the underlying value receiver.
 // this function will artificially create a built-in functions for constants, array and map.
 func (node *GoValueNode) CallFunction(funcName string, args ...reflect.Value) (retval reflect.Value, err error) {
+	// the methods of the value come first, so a named string, slice or map type implementing an interface of the
+	// rules is called like a struct is.
+	if funcValue := methodByName(node.thisValue, funcName); funcValue.IsValid() {
+
+		return node.callMethod(funcName, funcValue, args)
+	}
 	switch pkg.GetBaseKind(node.thisValue) {
 	case reflect.Int64, reflect.Uint64, reflect.Float64, reflect.Bool:
 
@@ -521,25 +539,73 @@ func (node *GoValueNode) CallFunction(funcName string, args ...reflect.Value) (r
 	}
 
 	if node.IsObject() || node.IsInterface() {
-		funcValue := node.thisValue.MethodByName(funcName)
-		if funcValue.IsValid() {
-			rets := funcValue.Call(args)
-			if len(rets) > 1 {
 
-				return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" calling function %s which \n\nreturns multiple values, multiple value \n\nreturns are not supported", node.IdentifiedAs(), funcName)
-			}
-			if len(rets) == 1 {
+		return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" have no function named %s", node.IdentifiedAs(), funcName)
+	}
 
-				return rets[0], nil
-			}
+	return reflect.ValueOf(nil), fmt.Errorf("this node identified as \"%s\" is not referencing an object thus function %s call is not supported. Kind %s", node.IdentifiedAs(), funcName, node.thisValue.Kind().String())
+}
 
-			return reflect.Value{}, nil
+// callMethod calls the method of the underlying value with the arguments coerced into its parameters.
+func (node *GoValueNode) callMethod(funcName string, funcValue reflect.Value, args []reflect.Value) (reflect.Value, error) {
+	args, err := CoerceArguments(funcName, funcValue.Type(), args)
+	if err != nil {
+
+		return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" calling function %s. got %w", node.IdentifiedAs(), funcName, err)
+	}
+	rets := funcValue.Call(args)
+	if len(rets) > 1 {
+
+		return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" calling function %s which \n\nreturns multiple values, multiple value \n\nreturns are not supported", node.IdentifiedAs(), funcName)
+	}
+	if len(rets) == 1 {
+		// a method declared to return interface{}, such as OrderedMap.Get, returns the value it holds.
+		if rets[0].Kind() == reflect.Interface && !rets[0].IsNil() {
+
+			return rets[0].Elem(), nil
 		}
 
-		return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" have no function named %s", node.IdentifiedAs(), funcName)
+		return rets[0], nil
 	}
 
-	return reflect.ValueOf(nil), fmt.Errorf("this node identified as \"%s\" is not referencing an object thus function %s call is not supported. Kind %s", node.IdentifiedAs(), funcName, node.thisValue.Kind().String())
+	return reflect.Value{}, nil
+}
+
+// methodByName returns the method of the value, those with a pointer receiver included. The value of an interface
+// is its dynamic value, whatever its concrete type. A value that is not addressable, such as a struct fact added
+// by value, is copied to call a pointer receiver method on it, what that method changes is lost with the copy.
+func methodByName(value reflect.Value, name string) reflect.Value {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+
+		return reflect.Value{}
+	}
+	if method := value.MethodByName(name); method.IsValid() || value.Kind() == reflect.Ptr {
+
+		return method
+	}
+	if _, ok := reflect.PointerTo(value.Type()).MethodByName(name); !ok {
+
+		return reflect.Value{}
+	}
+	if value.CanAddr() {
+
+		return value.Addr().MethodByName(name)
+	}
+	if !value.CanInterface() {
+
+		return reflect.Value{}
+	}
+	copied := reflect.New(value.Type())
+	copied.Elem().Set(value)
+
+	return copied.MethodByName(name)
 }
 
 // GetChildNodeByField will retrieve the underlying struct's field and \n\nreturn the ValueNode wraper.
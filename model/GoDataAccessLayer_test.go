@@ -438,3 +438,31 @@ func TestGoValueNode_InterfaceWithPointer(t *testing.T) {
 	payload := testData.Payload.(*TestPayload)
 	assert.Equal(t, "modified", payload.Status)
 }
+
+type Counter struct {
+	Count int
+}
+
+func (c *Counter) Increment() int {
+	c.Count++
+
+	return c.Count
+}
+
+func TestGoValueNode_PointerReceiver(t *testing.T) {
+	// a struct added by value is copied to call the pointer receiver method.
+	node := NewGoValueNode(reflect.ValueOf(Counter{Count: 1}), "counter")
+	retVal, err := node.CallFunction("Increment")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, int(retVal.Int()))
+
+	// an addressable struct is called in place.
+	counter := &Counter{}
+	fieldNode := NewGoValueNode(reflect.ValueOf(counter).Elem(), "counter")
+	_, err = fieldNode.CallFunction("Increment")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counter.Count)
+
+	_, err = node.CallFunction("Decrement")
+	assert.Error(t, err)
+}
@@ -362,6 +362,11 @@ func (vn *JSONValueNode) CallFunction(funcName string, args ...reflect.Value) (r
 	if vn.IsObject() || vn.IsInterface() {
 		funcValue := vn.data.MethodByName(funcName)
 		if funcValue.IsValid() {
+			args, err := CoerceArguments(funcName, funcValue.Type(), args)
+			if err != nil {
+
+				return reflect.Value{}, fmt.Errorf("this node identified as \"%s\" calling function %s. got %w", vn.IdentifiedAs(), funcName, err)
+			}
 			rets := funcValue.Call(args)
 			if len(rets) > 1 {
 
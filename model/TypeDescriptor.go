@@ -0,0 +1,109 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"sync"
+)
+
+// defaultTypeDescriptors are the descriptors of the caches that are nil, PrepareType describes the types into them.
+var defaultTypeDescriptors = NewTypeDescriptors()
+
+// TypeDescriptors caches the field layout of every struct type whose fields were accessed, by reflect.Type. A nil
+// cache is the default one of the process, so the facts of two embeddings may be described apart, see NodeOptions.
+type TypeDescriptors struct {
+	descriptors sync.Map
+}
+
+// NewTypeDescriptors create an empty TypeDescriptors, the types are described on their first access.
+func NewTypeDescriptors() *TypeDescriptors {
+
+	return &TypeDescriptors{}
+}
+
+// or returns this cache, the default cache if it is nil.
+func (cache *TypeDescriptors) or() *TypeDescriptors {
+	if cache == nil {
+
+		return defaultTypeDescriptors
+	}
+
+	return cache
+}
+
+// typeDescriptor is the field layout of a struct type, so accessing a field by its name does not walk the struct
+// and its embedded structs on every access as reflect.Value.FieldByName does.
+type typeDescriptor struct {
+	fields map[string][]int
+}
+
+// PrepareType describes the fields of the struct type, or of the struct a pointer type points to, up front in the
+// default cache. The fields of the facts are described on their first access otherwise.
+func PrepareType(typ reflect.Type) {
+	defaultTypeDescriptors.Prepare(typ)
+}
+
+// Prepare describes the fields of the struct type, or of the struct a pointer type points to, up front.
+// The fields of the facts are described on their first access otherwise.
+func (cache *TypeDescriptors) Prepare(typ reflect.Type) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ != nil && typ.Kind() == reflect.Struct {
+		cache.descriptorOf(typ)
+	}
+}
+
+// descriptorOf returns the descriptor of the struct type, describing it on the first call.
+func (cache *TypeDescriptors) descriptorOf(typ reflect.Type) *typeDescriptor {
+	cache = cache.or()
+	if descriptor, ok := cache.descriptors.Load(typ); ok {
+
+		return descriptor.(*typeDescriptor)
+	}
+	visible := reflect.VisibleFields(typ)
+	descriptor := &typeDescriptor{fields: make(map[string][]int, len(visible))}
+	for _, field := range visible {
+		descriptor.fields[field.Name] = field.Index
+	}
+	actual, _ := cache.descriptors.LoadOrStore(typ, descriptor)
+
+	return actual.(*typeDescriptor)
+}
+
+// fieldByName returns the field of the struct value like reflect.Value.FieldByName, using the descriptor of its type.
+// It returns the zero Value if there is no such field or it is reached through a nil embedded pointer.
+func (cache *TypeDescriptors) fieldByName(value reflect.Value, field string) reflect.Value {
+	index, ok := cache.descriptorOf(value.Type()).fields[field]
+	if !ok {
+
+		return reflect.Value{}
+	}
+	fieldVal, err := value.FieldByIndexErr(index)
+	if err != nil {
+
+		return reflect.Value{}
+	}
+
+	return fieldVal
+}
+
+// hasFieldNamed tells if the struct type has the field, like reflect.Type.FieldByName.
+func (cache *TypeDescriptors) hasFieldNamed(typ reflect.Type, field string) bool {
+	_, ok := cache.descriptorOf(typ).fields[field]
+
+	return ok
+}
@@ -0,0 +1,93 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DescribedAudit struct {
+	CreatedBy string
+	UpdatedBy string
+}
+
+type DescribedName struct {
+	Name string
+}
+
+type DescribedCustomer struct {
+	DescribedAudit
+	*DescribedName
+	ID      int
+	Segment string
+	Score   float64
+	score   int
+}
+
+func TestTypeDescriptor(t *testing.T) {
+	PrepareType(reflect.TypeOf(&DescribedCustomer{}))
+	_, ok := defaultTypeDescriptors.descriptors.Load(reflect.TypeOf(DescribedCustomer{}))
+	assert.True(t, ok)
+
+	customer := &DescribedCustomer{DescribedAudit: DescribedAudit{CreatedBy: "ops"}, ID: 7, Score: 0.5}
+	value := reflect.ValueOf(customer).Elem()
+	for _, field := range []string{"ID", "Segment", "Score", "CreatedBy", "DescribedAudit"} {
+		assert.Equal(t, value.FieldByName(field).Interface(), defaultTypeDescriptors.fieldByName(value, field).Interface(), field)
+	}
+	customer.score = 3
+	assert.Equal(t, int64(3), defaultTypeDescriptors.fieldByName(value, "score").Int())
+	assert.False(t, defaultTypeDescriptors.fieldByName(value, "Missing").IsValid())
+	assert.False(t, defaultTypeDescriptors.fieldByName(value, "Name").IsValid(), "the embedded pointer is nil")
+	customer.DescribedName = &DescribedName{Name: "Ann"}
+	assert.Equal(t, "Ann", defaultTypeDescriptors.fieldByName(value, "Name").Interface())
+
+	node := NewGoValueNode(reflect.ValueOf(customer), "Customer")
+	assert.NoError(t, node.SetObjectValueByField("UpdatedBy", reflect.ValueOf("batch")))
+	assert.Equal(t, "batch", customer.UpdatedBy)
+}
+
+type OwnDescribed struct {
+	Name string
+}
+
+func TestTypeDescriptors(t *testing.T) {
+	types := NewTypeDescriptors()
+	node := NewGoValueNodeWithOptions(reflect.ValueOf(&OwnDescribed{Name: "Ann"}), "Own", NodeOptions{Types: types})
+	val, err := node.GetObjectValueByField("Name")
+	assert.NoError(t, err)
+	assert.Equal(t, "Ann", val.String())
+
+	_, ok := types.descriptors.Load(reflect.TypeOf(OwnDescribed{}))
+	assert.True(t, ok)
+	_, ok = defaultTypeDescriptors.descriptors.Load(reflect.TypeOf(OwnDescribed{}))
+	assert.False(t, ok, "the types are described in the cache of the node only")
+}
+
+func BenchmarkFieldByName(b *testing.B) {
+	value := reflect.ValueOf(&DescribedCustomer{}).Elem()
+	b.Run("reflect", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = value.FieldByName("UpdatedBy")
+		}
+	})
+	b.Run("descriptor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = defaultTypeDescriptors.fieldByName(value, "UpdatedBy")
+		}
+	})
+}
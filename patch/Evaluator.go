@@ -0,0 +1,162 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+)
+
+const (
+	// DefaultFact is the default fact name of the patched document.
+	DefaultFact = "Fact"
+	// lockStripes is the number of locks the ids are spread over.
+	lockStripes = 64
+)
+
+// Store keeps the base fact documents the patches apply to. It must be safe for concurrent use.
+type Store interface {
+	// Load returns the document, or nil if the store has none.
+	Load(id string) (json.RawMessage, error)
+	// Save replaces the document.
+	Save(id string, document json.RawMessage) error
+}
+
+// NewMemoryStore creates new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+
+	return &MemoryStore{documents: make(map[string]json.RawMessage)}
+}
+
+// MemoryStore keeps the documents in memory. They do not survive the process.
+type MemoryStore struct {
+	lock      sync.Mutex
+	documents map[string]json.RawMessage
+}
+
+// Load implements Store.
+func (store *MemoryStore) Load(id string) (json.RawMessage, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.documents[id], nil
+}
+
+// Save implements Store.
+func (store *MemoryStore) Save(id string, document json.RawMessage) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.documents[id] = append(json.RawMessage(nil), document...)
+
+	return nil
+}
+
+// NewEvaluator creates new Evaluator patching the documents of the store and executing them with the specified
+// knowledge base of the library.
+func NewEvaluator(library *ast.KnowledgeLibrary, name, version string, store Store) *Evaluator {
+
+	return &Evaluator{
+		Library: library,
+		Name:    name,
+		Version: version,
+		Engine:  engine.NewGruleEngine(),
+		Store:   store,
+		Fact:    DefaultFact,
+	}
+}
+
+// Evaluator executes a knowledge base against fact documents that the callers update with JSON Merge Patches,
+// such as an API gateway sending only the fields of a request that changed:
+//
+//	evaluator := patch.NewEvaluator(lib, "Limits", "1.0.0", patch.NewMemoryStore())
+//	decision, err := evaluator.Evaluate(ctx, "customer-42", []byte(`{"cart": {"total": 120}}`))
+//	approved := engine.Emitted[string](decision.Outputs)
+//
+// The base document of the id is loaded from the Store, patched, added as a JSON fact, and the knowledge base is
+// executed. The patched document is saved as the new base once the execution succeeds. A failed execution leaves
+// the base as it was. The patches of the same id are evaluated one at a time.
+type Evaluator struct {
+	Library *ast.KnowledgeLibrary
+	Name    string
+	Version string
+	Engine  *engine.GruleEngine
+	Store   Store
+
+	// Fact is the fact name of the patched document.
+	Fact string
+
+	// locks serializes the patches of an id, the ids are spread over them by their hash.
+	locks [lockStripes]sync.Mutex
+}
+
+// Decision is the outcome of a patch evaluation. Use engine.Emitted on its Outputs to obtain the messages the
+// rules emitted, such as engine.Emitted[string](decision.Outputs).
+type Decision struct {
+	ID string
+	// Document is the patched document, the one the rules were executed against.
+	Document json.RawMessage
+	Outputs  *engine.Outputs
+}
+
+// Evaluate applies the merge patch to the base document of the id, executes the knowledge base against the patched
+// document and saves it as the new base. A missing base document is patched as null.
+func (evaluator *Evaluator) Evaluate(ctx context.Context, id string, mergePatch []byte) (*Decision, error) {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(id))
+	lock := &evaluator.locks[hash.Sum32()%lockStripes]
+	lock.Lock()
+	defer lock.Unlock()
+
+	base, err := evaluator.Store.Load(id)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while loading document %s. got %w", id, err)
+	}
+	document, err := MergePatch(base, mergePatch)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while patching document %s. got %w", id, err)
+	}
+	knowledgeBase, err := evaluator.Library.NewKnowledgeBaseInstance(evaluator.Name, evaluator.Version)
+	if err != nil {
+
+		return nil, err
+	}
+	dataCtx := ast.NewDataContext()
+	err = dataCtx.AddJSON(evaluator.Fact, document)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while adding document %s. got %w", id, err)
+	}
+	decision := &Decision{ID: id, Document: document, Outputs: engine.NewOutputs()}
+	err = evaluator.Engine.ExecuteWithContext(engine.WithOutputs(ctx, decision.Outputs), dataCtx, knowledgeBase)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while executing document %s. got %w", id, err)
+	}
+	err = evaluator.Store.Save(id, document)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while saving document %s. got %w", id, err)
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,71 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package patch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const limitRules = `
+rule OverLimit "the cart exceeds the limit of the customer" {
+	when
+		Fact.cart.total > Fact.limit
+	then
+		Emit("over limit");
+		Retract("OverLimit");
+}
+`
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Limits", "1", pkg.NewBytesResource([]byte(limitRules))))
+	store := NewMemoryStore()
+	assert.NoError(t, store.Save("customer-42", []byte(`{"limit": 100, "cart": {"total": 20, "items": 1}}`)))
+	evaluator := NewEvaluator(lib, "Limits", "1", store)
+
+	decision, err := evaluator.Evaluate(context.Background(), "customer-42", []byte(`{"cart": {"total": 120}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"limit": 100, "cart": {"total": 120, "items": 1}}`, string(decision.Document))
+	assert.Equal(t, []string{"over limit"}, engine.Emitted[string](decision.Outputs))
+
+	// the next patch applies to the saved document.
+	decision, err = evaluator.Evaluate(context.Background(), "customer-42", []byte(`{"limit": 500}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"limit": 500, "cart": {"total": 120, "items": 1}}`, string(decision.Document))
+	assert.Empty(t, engine.Emitted[string](decision.Outputs))
+
+	// a failed execution leaves the base as it was.
+	evaluator.Engine.ReturnErrOnFailedRuleEvaluation = true
+	_, err = evaluator.Evaluate(context.Background(), "customer-42", []byte(`{"cart": null}`))
+	assert.ErrorContains(t, err, "error while executing document customer-42")
+	saved, err := store.Load("customer-42")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"limit": 500, "cart": {"total": 120, "items": 1}}`, string(saved))
+
+	// a new document starts from the patch.
+	decision, err = evaluator.Evaluate(context.Background(), "customer-7", []byte(`{"limit": 10, "cart": {"total": 15}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"over limit"}, engine.Emitted[string](decision.Outputs))
+
+	_, err = evaluator.Evaluate(context.Background(), "customer-7", []byte(`{"limit":`))
+	assert.ErrorContains(t, err, "patch is not valid JSON")
+}
@@ -0,0 +1,84 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package patch evaluates rules against fact documents updated by JSON Merge Patches, so a gateway only sends the
+// fields that changed since its last request.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies the JSON Merge Patch of RFC 7396 to the document and returns the patched document.
+// The members of a patch object replace the members of the document, recursively, and a null member removes it.
+// A patch that is not an object replaces the whole document. A nil document is the same as null.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(bytes.TrimSpace(document)) > 0 {
+		err := decodeJSON(document, &target)
+		if err != nil {
+
+			return nil, fmt.Errorf("document is not valid JSON. got %w", err)
+		}
+	}
+	var changes interface{}
+	err := decodeJSON(patch, &changes)
+	if err != nil {
+
+		return nil, fmt.Errorf("patch is not valid JSON. got %w", err)
+	}
+
+	return json.Marshal(mergeValue(target, changes))
+}
+
+// mergeValue merges the patch value into the target value, as described by RFC 7396.
+func mergeValue(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+		} else {
+			object[name] = mergeValue(object[name], value)
+		}
+	}
+
+	return object
+}
+
+// decodeJSON decodes the single JSON value of the data, keeping the numbers as they are written.
+func decodeJSON(data []byte, value *interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(value)
+	if err != nil {
+
+		return err
+	}
+	if decoder.More() {
+
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	return nil
+}
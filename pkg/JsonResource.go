@@ -16,18 +16,24 @@ package pkg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // GruleJSON represents a rule in JSON format
 type GruleJSON struct {
 	Name        string        `json:"name"`
 	Description string        `json:"desc"`
+	ID          string        `json:"id"`
 	Salience    int           `json:"salience"`
+	MaxFires    int           `json:"maxFires"`
+	Cooldown    string        `json:"cooldown"`
+	Criticality string        `json:"criticality"`
 	When        interface{}   `json:"when"`
 	Then        []interface{} `json:"then"`
 }
@@ -61,6 +67,24 @@ func (jr *JSONResource) Load() ([]byte, error) {
 
 		return nil, err
 	}
+
+	return jr.parse(data)
+}
+
+// LoadContext is the same as Load, the underlying Resource is loaded within the context.
+func (jr *JSONResource) LoadContext(ctx context.Context) ([]byte, error) {
+	data, err := LoadResource(ctx, jr.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return jr.parse(data)
+}
+
+// parse the JSON rules of data into standard GRule syntax.
+func (jr *JSONResource) parse(data []byte) ([]byte, error) {
+	var err error
 	firstRune := string(bytes.TrimSpace(data)[0])
 
 	var ruleSet string
@@ -104,6 +128,35 @@ func (jrb *JSONResourceBundle) Load() ([]Resource, error) {
 
 		return nil, err
 	}
+
+	return jrb.wrap(ress)
+}
+
+// LoadContext is the same as Load, the underlying ResourceBundle is loaded within the context.
+func (jrb *JSONResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	ress, err := LoadBundle(ctx, jrb.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return jrb.wrap(ress)
+}
+
+// LoadWithHandler is the same as Load, the resources of the underlying ResourceBundle failing to load are reported to onError.
+func (jrb *JSONResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ress, err := LoadBundleWithHandler(jrb.subRes, onError)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return jrb.wrap(ress)
+}
+
+// wrap the resources of the underlying ResourceBundle into JSON resources.
+func (jrb *JSONResourceBundle) wrap(ress []Resource) ([]Resource, error) {
+	var err error
 	nress := make([]Resource, len(ress))
 	for i := 0; i < len(ress); i++ {
 		nress[i], err = NewJSONResourceFromResource(ress[i])
@@ -116,6 +169,9 @@ func (jrb *JSONResourceBundle) Load() ([]Resource, error) {
 }
 
 // MustLoad operates the same as load except it will panic in the event of an error.
+//
+// Deprecated: use JSONResourceBundle.Load, or JSONResourceBundle.LoadWithHandler to go on without the resources of the
+// underlying bundle failing to load.
 func (jrb *JSONResourceBundle) MustLoad() []Resource {
 	ress := jrb.subRes.MustLoad()
 	nress := make([]Resource, len(ress))
@@ -190,8 +246,29 @@ func parseRule(rule *GruleJSON) (string, error) {
 	stringBuilder.WriteString(rule.Name)
 	stringBuilder.WriteString(" ")
 	stringBuilder.WriteString(strconv.Quote(rule.Description))
+	if len(rule.ID) > 0 {
+		stringBuilder.WriteString(" id ")
+		stringBuilder.WriteString(strconv.Quote(rule.ID))
+	}
 	stringBuilder.WriteString(" salience ")
 	stringBuilder.WriteString(strconv.Itoa(rule.Salience))
+	if rule.MaxFires != 0 {
+		stringBuilder.WriteString(" max-fires ")
+		stringBuilder.WriteString(strconv.Itoa(rule.MaxFires))
+	}
+	if len(rule.Cooldown) > 0 {
+		cooldown, err := time.ParseDuration(rule.Cooldown)
+		if err != nil {
+
+			return "", fmt.Errorf("invalid cooldown for rule %s: %w", rule.Name, err)
+		}
+		stringBuilder.WriteString(" cooldown ")
+		stringBuilder.WriteString(cooldown.String())
+	}
+	if len(rule.Criticality) > 0 {
+		stringBuilder.WriteString(" criticality ")
+		stringBuilder.WriteString(rule.Criticality)
+	}
 	stringBuilder.WriteString(" {\n    when\n        ")
 	when, err := parseWhen(rule.When)
 	if err != nil {
@@ -262,3 +262,19 @@ func TestJSONBigIntConversion(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBigIntConversion, rs)
 }
+
+func TestJSONRateLimit(t *testing.T) {
+	rs, err := ParseJSONRule([]byte(`{"name": "Notify", "maxFires": 1, "cooldown": "90m", "when": "Fact.Ready", "then": ["Fact.Notify()"]}`))
+	assert.NoError(t, err)
+	assert.Contains(t, rs, `rule Notify "" salience 0 max-fires 1 cooldown 1h30m0s {`)
+
+	rs, err = ParseJSONRule([]byte(`{"name": "Notify", "id": "R-1", "when": "Fact.Ready", "then": ["Fact.Notify()"]}`))
+	assert.NoError(t, err)
+	assert.Contains(t, rs, `rule Notify "" id "R-1" salience 0 {`)
+
+	rs, err = ParseJSONRule([]byte(`{"name": "Enrich", "criticality": "low", "when": "Fact.Ready", "then": ["Fact.Enrich()"]}`))
+	assert.NoError(t, err)
+	assert.Contains(t, rs, `rule Enrich "" salience 0 criticality low {`)
+	_, err = ParseJSONRule([]byte(`{"name": "Notify", "cooldown": "soon", "when": "Fact.Ready", "then": ["Fact.Notify()"]}`))
+	assert.Error(t, err)
+}
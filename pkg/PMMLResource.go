@@ -0,0 +1,557 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PMMLOptions tells how the fields of a PMML model are bound to the facts.
+type PMMLOptions struct {
+	// Fact is the name of the fact holding the input fields, e.g. "Applicant" turns the field "age" into Applicant.Age.
+	Fact string
+	// Fields overrides the GRL variable of some input fields, e.g. {"age": "Applicant.Profile.Age"}.
+	Fields map[string]string
+	// Target is the GRL variable receiving the score, e.g. "Result.Score". A scorecard adds the partial scores to it,
+	// a tree assigns the score of the matching leaf.
+	Target string
+	// Salience of the generated rules. The initial score of a scorecard is assigned by a rule of Salience + 1.
+	Salience int
+}
+
+// PMMLResource will convert the scorecards and decision trees of a PMML document from underlying resource provider
+// into GRL rules.
+type PMMLResource struct {
+	subRes  Resource
+	options PMMLOptions
+}
+
+// NewPMMLResourceFromResource instantiates a new PMML resource converter from an underlying Resource.
+func NewPMMLResourceFromResource(res Resource, options PMMLOptions) (Resource, error) {
+	if _, ok := res.(*PMMLResource); ok {
+
+		return nil, fmt.Errorf("cannot create PMML resource from PMML resource")
+	}
+	if len(options.Fact) == 0 || len(options.Target) == 0 {
+
+		return nil, fmt.Errorf("PMML resource needs a fact and a target")
+	}
+
+	return &PMMLResource{
+		subRes:  res,
+		options: options,
+	}, nil
+}
+
+// Load will load the underlying Resource and convert the PMML models into standard GRule syntax.
+func (pr *PMMLResource) Load() ([]byte, error) {
+	data, err := pr.subRes.Load()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return pr.convert(data)
+}
+
+// LoadContext is the same as Load, the underlying Resource is loaded within the context.
+func (pr *PMMLResource) LoadContext(ctx context.Context) ([]byte, error) {
+	data, err := LoadResource(ctx, pr.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return pr.convert(data)
+}
+
+// convert the PMML models of data into standard GRule syntax.
+func (pr *PMMLResource) convert(data []byte) ([]byte, error) {
+	ruleSet, err := ParsePMML(data, pr.options)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return []byte(ruleSet), nil
+}
+
+// String will state the resource source.
+func (pr *PMMLResource) String() string {
+
+	return "PMML Resource, underlying resource: " + pr.subRes.String()
+}
+
+type pmmlDocument struct {
+	DataFields []pmmlDataField `xml:"DataDictionary>DataField"`
+	Scorecards []pmmlScorecard `xml:"Scorecard"`
+	Trees      []pmmlTree      `xml:"TreeModel"`
+}
+
+type pmmlDataField struct {
+	Name     string `xml:"name,attr"`
+	DataType string `xml:"dataType,attr"`
+}
+
+type pmmlScorecard struct {
+	ModelName       string               `xml:"modelName,attr"`
+	InitialScore    string               `xml:"initialScore,attr"`
+	Characteristics []pmmlCharacteristic `xml:"Characteristics>Characteristic"`
+}
+
+type pmmlCharacteristic struct {
+	Name       string          `xml:"name,attr"`
+	Attributes []pmmlAttribute `xml:"Attribute"`
+}
+
+type pmmlAttribute struct {
+	PartialScore string          `xml:"partialScore,attr"`
+	Elements     []pmmlPredicate `xml:",any"`
+}
+
+type pmmlTree struct {
+	ModelName string   `xml:"modelName,attr"`
+	Node      pmmlNode `xml:"Node"`
+}
+
+type pmmlNode struct {
+	Score    string          `xml:"score,attr"`
+	Nodes    []pmmlNode      `xml:"Node"`
+	Elements []pmmlPredicate `xml:",any"`
+}
+
+type pmmlPredicate struct {
+	XMLName         xml.Name
+	Field           string          `xml:"field,attr"`
+	Operator        string          `xml:"operator,attr"`
+	BooleanOperator string          `xml:"booleanOperator,attr"`
+	Value           string          `xml:"value,attr"`
+	Array           string          `xml:"Array"`
+	Elements        []pmmlPredicate `xml:",any"`
+}
+
+// predicateOf returns the predicate among the child elements, the other elements such as Extension are ignored.
+func predicateOf(elements []pmmlPredicate) (*pmmlPredicate, error) {
+	for i := range elements {
+		switch elements[i].XMLName.Local {
+		case "SimplePredicate", "CompoundPredicate", "SimpleSetPredicate", "True", "False":
+
+			return &elements[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("PMML element has no predicate")
+}
+
+// pmmlConverter turns the PMML predicates into GRL expressions.
+type pmmlConverter struct {
+	options PMMLOptions
+	// dataTypes are the types of the fields declared in the data dictionary.
+	dataTypes map[string]string
+	rules     []*GruleJSON
+}
+
+// ParsePMML accepts a PMML document and converts its scorecards and decision trees into GRule syntax.
+// Each bin of a scorecard characteristic becomes a rule adding its partial score to the target, a bin is only
+// used if none of the bins above it matches. Each leaf of a tree becomes a rule assigning its score to the target.
+func ParsePMML(data []byte, options PMMLOptions) (string, error) {
+	var doc pmmlDocument
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+
+		return "", fmt.Errorf("invalid PMML document. got %w", err)
+	}
+	if len(doc.Scorecards) == 0 && len(doc.Trees) == 0 {
+
+		return "", fmt.Errorf("PMML document has no Scorecard nor TreeModel")
+	}
+	converter := &pmmlConverter{
+		options:   options,
+		dataTypes: make(map[string]string),
+	}
+	for _, field := range doc.DataFields {
+		converter.dataTypes[field.Name] = field.DataType
+	}
+	for i, scorecard := range doc.Scorecards {
+		err := converter.scorecard(modelName(scorecard.ModelName, "Scorecard", i), scorecard)
+		if err != nil {
+
+			return "", err
+		}
+	}
+	for i, tree := range doc.Trees {
+		err := converter.tree(modelName(tree.ModelName, "Tree", i), tree)
+		if err != nil {
+
+			return "", err
+		}
+	}
+
+	var stringBuilder strings.Builder
+	for _, rule := range converter.rules {
+		text, err := parseRule(rule)
+		if err != nil {
+
+			return "", err
+		}
+		stringBuilder.WriteString(text)
+	}
+
+	return stringBuilder.String(), nil
+}
+
+func (converter *pmmlConverter) scorecard(name string, scorecard pmmlScorecard) error {
+	if len(scorecard.InitialScore) > 0 {
+		score, err := numberLiteral(scorecard.InitialScore)
+		if err != nil {
+
+			return fmt.Errorf("scorecard %s has an invalid initial score. got %w", name, err)
+		}
+		converter.add(name+"_Initial", fmt.Sprintf("Initial score of scorecard %s", name), converter.options.Salience+1,
+			"true", converter.options.Target+" = "+score)
+	}
+	for c, characteristic := range scorecard.Characteristics {
+		previous := make([]string, 0, len(characteristic.Attributes))
+		for a, attribute := range characteristic.Attributes {
+			condition, score, err := converter.bin(attribute)
+			if err != nil {
+
+				return fmt.Errorf("scorecard %s characteristic %s attribute %d. got %w", name, characteristic.Name, a, err)
+			}
+			converter.add(fmt.Sprintf("%s_%s_%d", name, identifier(characteristic.Name, fmt.Sprintf("C%d", c)), a),
+				fmt.Sprintf("Bin %d of characteristic %s of scorecard %s", a, characteristic.Name, name), converter.options.Salience,
+				exclusive(condition, previous), fmt.Sprintf("%s = %s + %s", converter.options.Target, converter.options.Target, score))
+			previous = append(previous, condition)
+		}
+	}
+
+	return nil
+}
+
+// bin returns the condition and the partial score of a scorecard attribute.
+func (converter *pmmlConverter) bin(attribute pmmlAttribute) (string, string, error) {
+	predicate, err := predicateOf(attribute.Elements)
+	if err != nil {
+
+		return "", "", err
+	}
+	condition, err := converter.expression(predicate)
+	if err != nil {
+
+		return "", "", err
+	}
+	score, err := numberLiteral(attribute.PartialScore)
+	if err != nil {
+
+		return "", "", fmt.Errorf("invalid partial score. got %w", err)
+	}
+
+	return condition, score, nil
+}
+
+func (converter *pmmlConverter) tree(name string, tree pmmlTree) error {
+	leaves := 0
+
+	return converter.node(name, tree.Node, nil, &leaves)
+}
+
+// node converts the leaves under the tree node, path holds the conditions leading to the node.
+func (converter *pmmlConverter) node(name string, node pmmlNode, path []string, leaves *int) error {
+	predicate, err := predicateOf(node.Elements)
+	if err != nil {
+
+		return fmt.Errorf("tree %s. got %w", name, err)
+	}
+	condition, err := converter.expression(predicate)
+	if err != nil {
+
+		return fmt.Errorf("tree %s. got %w", name, err)
+	}
+	if condition != "true" {
+		path = append(path, condition)
+	}
+	if len(node.Nodes) == 0 {
+		if len(node.Score) == 0 {
+
+			return fmt.Errorf("tree %s has a leaf without score", name)
+		}
+		when := "true"
+		if len(path) > 0 {
+			when = strings.Join(path, " && ")
+		}
+		score, err := numberLiteral(node.Score)
+		if err != nil {
+			score = strconv.Quote(node.Score)
+		}
+		converter.add(fmt.Sprintf("%s_Leaf%d", name, *leaves), fmt.Sprintf("Leaf %d of tree %s", *leaves, name),
+			converter.options.Salience, when, converter.options.Target+" = "+score)
+		*leaves++
+
+		return nil
+	}
+	// the first child whose predicate is true is followed.
+	previous := make([]string, 0, len(node.Nodes))
+	for _, child := range node.Nodes {
+		childPredicate, err := predicateOf(child.Elements)
+		if err != nil {
+
+			return fmt.Errorf("tree %s. got %w", name, err)
+		}
+		childCondition, err := converter.expression(childPredicate)
+		if err != nil {
+
+			return fmt.Errorf("tree %s. got %w", name, err)
+		}
+		childPath := path
+		if len(previous) > 0 {
+			childPath = append(append([]string{}, path...), "!("+strings.Join(previous, " || ")+")")
+		}
+		err = converter.node(name, child, childPath, leaves)
+		if err != nil {
+
+			return err
+		}
+		previous = append(previous, childCondition)
+	}
+
+	return nil
+}
+
+// add appends a rule that retracts itself, so it fires once per execution.
+func (converter *pmmlConverter) add(name, description string, salience int, when, then string) {
+	converter.rules = append(converter.rules, &GruleJSON{
+		Name:        name,
+		Description: description,
+		Salience:    salience,
+		When:        when,
+		Then:        []interface{}{then, fmt.Sprintf("Retract(%s)", strconv.Quote(name))},
+	})
+}
+
+func (converter *pmmlConverter) expression(predicate *pmmlPredicate) (string, error) {
+	switch predicate.XMLName.Local {
+	case "True":
+
+		return "true", nil
+	case "False":
+
+		return "false", nil
+	case "SimplePredicate":
+		field := converter.variable(predicate.Field)
+		switch predicate.Operator {
+		case "isMissing":
+
+			return fmt.Sprintf("IsZero(%s)", field), nil
+		case "isNotMissing":
+
+			return fmt.Sprintf("!IsZero(%s)", field), nil
+		}
+		operator, ok := map[string]string{
+			"equal":          "==",
+			"notEqual":       "!=",
+			"lessThan":       "<",
+			"lessOrEqual":    "<=",
+			"greaterThan":    ">",
+			"greaterOrEqual": ">=",
+		}[predicate.Operator]
+		if !ok {
+
+			return "", fmt.Errorf("unsupported SimplePredicate operator %s", predicate.Operator)
+		}
+		value, err := converter.value(predicate.Field, predicate.Value)
+		if err != nil {
+
+			return "", err
+		}
+
+		return fmt.Sprintf("%s %s %s", field, operator, value), nil
+	case "SimpleSetPredicate":
+		operator, join := "==", " || "
+		switch predicate.BooleanOperator {
+		case "isIn":
+		case "isNotIn":
+			operator, join = "!=", " && "
+		default:
+
+			return "", fmt.Errorf("unsupported SimpleSetPredicate operator %s", predicate.BooleanOperator)
+		}
+		values := splitPMMLArray(predicate.Array)
+		if len(values) == 0 {
+
+			return "", fmt.Errorf("SimpleSetPredicate on %s has no values", predicate.Field)
+		}
+		terms := make([]string, len(values))
+		for i, value := range values {
+			literal, err := converter.value(predicate.Field, value)
+			if err != nil {
+
+				return "", err
+			}
+			terms[i] = fmt.Sprintf("%s %s %s", converter.variable(predicate.Field), operator, literal)
+		}
+
+		return "(" + strings.Join(terms, join) + ")", nil
+	case "CompoundPredicate":
+		terms := make([]string, 0, len(predicate.Elements))
+		for i := range predicate.Elements {
+			if _, err := predicateOf(predicate.Elements[i : i+1]); err != nil {
+
+				continue
+			}
+			term, err := converter.expression(&predicate.Elements[i])
+			if err != nil {
+
+				return "", err
+			}
+			terms = append(terms, "("+term+")")
+		}
+		switch predicate.BooleanOperator {
+		case "and":
+
+			return "(" + strings.Join(terms, " && ") + ")", nil
+		case "or":
+
+			return "(" + strings.Join(terms, " || ") + ")", nil
+		case "xor":
+			if len(terms) != 2 {
+
+				return "", fmt.Errorf("xor CompoundPredicate must have 2 predicates, got %d", len(terms))
+			}
+
+			return fmt.Sprintf("(%s != %s)", terms[0], terms[1]), nil
+		}
+
+		return "", fmt.Errorf("unsupported CompoundPredicate operator %s", predicate.BooleanOperator)
+	}
+
+	return "", fmt.Errorf("unsupported predicate %s", predicate.XMLName.Local)
+}
+
+// variable returns the GRL variable bound to the input field.
+func (converter *pmmlConverter) variable(field string) string {
+	if variable, ok := converter.options.Fields[field]; ok {
+
+		return variable
+	}
+
+	return converter.options.Fact + "." + identifier(field, "Field")
+}
+
+// value returns the GRL literal of the value as typed by the data dictionary, a string if the field is not declared.
+func (converter *pmmlConverter) value(field, value string) (string, error) {
+	switch converter.dataTypes[field] {
+	case "integer", "float", "double":
+		literal, err := numberLiteral(value)
+		if err != nil {
+
+			return "", fmt.Errorf("field %s is numeric. got %w", field, err)
+		}
+
+		return literal, nil
+	case "boolean":
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+
+			return "", fmt.Errorf("field %s is boolean. got %w", field, err)
+		}
+
+		return strconv.FormatBool(b), nil
+	}
+
+	return strconv.Quote(value), nil
+}
+
+// exclusive appends to the condition the negation of the conditions of the bins above.
+func exclusive(condition string, previous []string) string {
+	if len(previous) == 0 {
+
+		return condition
+	}
+
+	return fmt.Sprintf("%s && !(%s)", condition, strings.Join(previous, " || "))
+}
+
+func numberLiteral(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+
+		return "", fmt.Errorf("%q is not a number", value)
+	}
+
+	return value, nil
+}
+
+func modelName(name, kind string, index int) string {
+
+	return identifier(name, fmt.Sprintf("%s%d", kind, index))
+}
+
+// identifier turns a PMML name such as "income level" into a GRL identifier such as IncomeLevel.
+func identifier(name, fallback string) string {
+	var stringBuilder strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		stringBuilder.WriteRune(r)
+	}
+	ret := stringBuilder.String()
+	if len(ret) == 0 || unicode.IsDigit(rune(ret[0])) {
+
+		return fallback + ret
+	}
+
+	return ret
+}
+
+// splitPMMLArray splits the content of a PMML Array, values with spaces are double quoted.
+func splitPMMLArray(content string) []string {
+	values := make([]string, 0)
+	var current strings.Builder
+	quoted, inValue := false, false
+	for _, r := range content {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inValue = true
+		case unicode.IsSpace(r) && !quoted:
+			if inValue {
+				values = append(values, current.String())
+				current.Reset()
+				inValue = false
+			}
+		default:
+			current.WriteRune(r)
+			inValue = true
+		}
+	}
+	if inValue {
+		values = append(values, current.String())
+	}
+
+	return values
+}
@@ -0,0 +1,134 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// PMMLScorecard is a credit scorecard as exported by the analytics tools.
+const PMMLScorecard = `<?xml version="1.0" encoding="UTF-8"?>
+<PMML xmlns="http://www.dmg.org/PMML-4_4" version="4.4">
+  <DataDictionary numberOfFields="3">
+    <DataField name="age" optype="continuous" dataType="double"/>
+    <DataField name="residence state" optype="categorical" dataType="string"/>
+    <DataField name="score" optype="continuous" dataType="double"/>
+  </DataDictionary>
+  <Scorecard modelName="credit risk" functionName="regression" initialScore="100" useReasonCodes="false">
+    <MiningSchema>
+      <MiningField name="age" usageType="active"/>
+      <MiningField name="residence state" usageType="active"/>
+      <MiningField name="score" usageType="predicted"/>
+    </MiningSchema>
+    <Characteristics>
+      <Characteristic name="age bins" baselineScore="0">
+        <Attribute partialScore="-10">
+          <SimplePredicate field="age" operator="lessThan" value="25"/>
+        </Attribute>
+        <Attribute partialScore="15">
+          <CompoundPredicate booleanOperator="and">
+            <SimplePredicate field="age" operator="greaterOrEqual" value="25"/>
+            <SimplePredicate field="age" operator="lessThan" value="60"/>
+          </CompoundPredicate>
+        </Attribute>
+        <Attribute partialScore="5">
+          <True/>
+        </Attribute>
+      </Characteristic>
+      <Characteristic name="state" baselineScore="0">
+        <Attribute partialScore="20">
+          <SimpleSetPredicate field="residence state" booleanOperator="isIn">
+            <Array n="2" type="string">CA "New York"</Array>
+          </SimpleSetPredicate>
+        </Attribute>
+        <Attribute partialScore="0.5">
+          <SimplePredicate field="residence state" operator="isMissing"/>
+        </Attribute>
+      </Characteristic>
+    </Characteristics>
+  </Scorecard>
+</PMML>`
+
+// PMMLTree is a simple decision tree.
+const PMMLTree = `<PMML version="4.4">
+  <DataDictionary>
+    <DataField name="income" dataType="double"/>
+    <DataField name="employed" dataType="string"/>
+  </DataDictionary>
+  <TreeModel modelName="approval" functionName="classification">
+    <Node score="decline">
+      <True/>
+      <Node score="approve">
+        <SimplePredicate field="income" operator="greaterThan" value="5000"/>
+      </Node>
+      <Node>
+        <SimplePredicate field="employed" operator="equal" value="yes"/>
+        <Node score="review">
+          <SimplePredicate field="income" operator="greaterThan" value="1000"/>
+        </Node>
+        <Node score="decline">
+          <True/>
+        </Node>
+      </Node>
+      <Node score="decline">
+        <True/>
+      </Node>
+    </Node>
+  </TreeModel>
+</PMML>`
+
+func TestParsePMML_Scorecard(t *testing.T) {
+	grl, err := ParsePMML([]byte(PMMLScorecard), PMMLOptions{Fact: "Applicant", Target: "Applicant.Score", Salience: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, grl, `rule CreditRisk_Initial "Initial score of scorecard CreditRisk" salience 11 {`)
+	assert.Contains(t, grl, "Applicant.Score = 100;")
+	assert.Contains(t, grl, `rule CreditRisk_AgeBins_1 "Bin 1 of characteristic age bins of scorecard CreditRisk" salience 10 {`)
+	assert.Contains(t, grl, "((Applicant.Age >= 25) && (Applicant.Age < 60)) && !(Applicant.Age < 25)")
+	assert.Contains(t, grl, "Applicant.Score = Applicant.Score + 15;")
+	assert.Contains(t, grl, `Retract("CreditRisk_AgeBins_1");`)
+	assert.Contains(t, grl, "true && !(Applicant.Age < 25 || ((Applicant.Age >= 25) && (Applicant.Age < 60)))")
+	assert.Contains(t, grl, `(Applicant.ResidenceState == "CA" || Applicant.ResidenceState == "New York")`)
+	assert.Contains(t, grl, "IsZero(Applicant.ResidenceState)")
+	assert.Equal(t, 6, strings.Count(grl, "rule "))
+
+	grl, err = ParsePMML([]byte(PMMLScorecard), PMMLOptions{Fact: "Applicant", Target: "Score", Fields: map[string]string{"age": "Applicant.Profile.Age"}})
+	assert.NoError(t, err)
+	assert.Contains(t, grl, "Applicant.Profile.Age < 25")
+}
+
+func TestParsePMML_Tree(t *testing.T) {
+	grl, err := ParsePMML([]byte(PMMLTree), PMMLOptions{Fact: "Loan", Target: "Loan.Decision"})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(grl, "rule "))
+	assert.Contains(t, grl, "Loan.Income > 5000\n    then\n        Loan.Decision = \"approve\";")
+	assert.Contains(t, grl, `!(Loan.Income > 5000) && Loan.Employed == "yes" && Loan.Income > 1000`)
+	assert.Contains(t, grl, `!(Loan.Income > 5000) && Loan.Employed == "yes" && !(Loan.Income > 1000)`)
+	assert.Contains(t, grl, `!(Loan.Income > 5000 || Loan.Employed == "yes")`)
+}
+
+func TestParsePMML_Invalid(t *testing.T) {
+	_, err := ParsePMML([]byte(`<PMML/>`), PMMLOptions{Fact: "Loan", Target: "Loan.Decision"})
+	assert.Error(t, err)
+	invalid := strings.Replace(PMMLScorecard, `value="25"/>`, `value="young"/>`, 1)
+	_, err = ParsePMML([]byte(invalid), PMMLOptions{Fact: "Loan", Target: "Loan.Decision"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "field age is numeric")
+
+	_, err = NewPMMLResourceFromResource(NewBytesResource([]byte(PMMLTree)), PMMLOptions{Fact: "Loan"})
+	assert.Error(t, err)
+}
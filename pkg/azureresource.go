@@ -0,0 +1,382 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// azureStorageResource is the resource the managed identity tokens are requested for.
+	azureStorageResource = "https://storage.azure.com/"
+
+	// azureStorageVersion is the version of the Blob service API, the bearer tokens require 2017-11-09 or later.
+	azureStorageVersion = "2021-08-06"
+
+	// azureIMDSTokenURL is the token endpoint of the instance metadata service of the virtual machines and AKS.
+	azureIMDSTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// NewAzureBlobResource will create a new Resource using the blob at the url, such as
+// https://account.blob.core.windows.net/container/rules.grl. sasToken, if not empty, authorizes the request.
+func NewAzureBlobResource(url, sasToken string) *AzureBlobResource {
+
+	return &AzureBlobResource{
+		URL:      url,
+		SASToken: sasToken,
+	}
+}
+
+// AzureBlobResource is a resource loaded from a blob of an Azure Storage container.
+// The request is authorized with the SASToken, or else the managed identity if ManagedIdentity is set,
+// or else not at all, such as for a public container.
+type AzureBlobResource struct {
+	// URL of the blob, without the SAS token.
+	URL string
+	// SASToken is the shared access signature query, with or without the leading question mark.
+	SASToken string
+	// ManagedIdentity authorizes the request with a token of the managed identity of the host.
+	ManagedIdentity bool
+	// ClientID selects a user assigned managed identity, the system assigned one is used if empty.
+	ClientID string
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	Bytes      []byte
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+
+	// token is the managed identity token, already obtained by the bundle for all of its blobs.
+	token string
+}
+
+// String will state the blob url, the SAS token is not shown.
+func (res *AzureBlobResource) String() string {
+
+	return fmt.Sprintf("From Azure blob [%s]", res.URL)
+}
+
+// Load will load the blob into byte array. This resource will cache the obtained result byte arrays,
+// so calling this function multiple times only downloads the blob once at the first time.
+func (res *AzureBlobResource) Load() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(res.Timeout))
+	defer cancel()
+
+	return res.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (res *AzureBlobResource) LoadContext(ctx context.Context) ([]byte, error) {
+	if res.Bytes != nil {
+
+		return res.Bytes, nil
+	}
+	if res.ManagedIdentity && len(res.SASToken) == 0 && len(res.token) == 0 {
+		token, err := azureManagedIdentityToken(ctx, azureClient(res.HTTPClient), res.ClientID)
+		if err != nil {
+
+			return nil, err
+		}
+		res.token = token
+	}
+	data, err := azureGet(ctx, azureClient(res.HTTPClient), res.URL, nil, res.SASToken, res.token)
+	if err != nil {
+
+		return nil, err
+	}
+	res.Bytes = data
+
+	return res.Bytes, nil
+}
+
+// NewAzureBlobResourceBundle will create a new instance of AzureBlobResourceBundle.
+// accountURL is the Blob service endpoint of the storage account, such as https://account.blob.core.windows.net.
+// prefix only lists the blobs whose name starts with it.
+// pathPattern are list of name patterns (glob), relative to the prefix, to filter the blobs.
+func NewAzureBlobResourceBundle(accountURL, container, prefix string, pathPattern ...string) *AzureBlobResourceBundle {
+
+	return &AzureBlobResourceBundle{
+		AccountURL:  accountURL,
+		Container:   container,
+		Prefix:      prefix,
+		PathPattern: pathPattern,
+	}
+}
+
+// AzureBlobResourceBundle is a helper struct to load multiple blobs from an Azure Storage container all at once by
+// specifying the prefix of their names and the name pattern to look for.
+// The requests are authorized with the SASToken, or else the managed identity if ManagedIdentity is set,
+// or else not at all, such as for a public container.
+type AzureBlobResourceBundle struct {
+	// AccountURL is the Blob service endpoint of the storage account, such as https://account.blob.core.windows.net
+	// or http://127.0.0.1:10000/devstoreaccount1 for Azurite.
+	AccountURL string
+	// The container name
+	Container string
+	// Only the blobs whose name starts with the prefix are listed, such as "rules/".
+	Prefix string
+	// List Glob like name pattern, relative to the prefix.
+	// *.grl           <- matches rules/abc.grl but not rules/anyfolder/abc.grl with the prefix "rules/"
+	// **/*.grl        <- matches rules/abc.grl or rules/abc/def.grl
+	// /rules/**/*.grl <- an absolute pattern is matched against the whole name
+	PathPattern []string
+	// SASToken is the shared access signature query of the container, it must allow to list and read.
+	SASToken string
+	// ManagedIdentity authorizes the requests with a token of the managed identity of the host.
+	ManagedIdentity bool
+	// ClientID selects a user assigned managed identity, the system assigned one is used if empty.
+	ClientID string
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	// Concurrency is the number of blobs downloaded at the same time, 8 if not positive.
+	Concurrency int
+	// Timeout bounds Load, and the download of every blob. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// azureEnumerationResults is a page of the blobs list.
+type azureEnumerationResults struct {
+	Blobs struct {
+		Blob []struct {
+			Name string `xml:"Name"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// Load lists the blobs under the Prefix and downloads all of those that conform to the PathPattern.
+// The resources are returned in the order of their names.
+func (bundle *AzureBlobResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *AzureBlobResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the blobs failing to download are reported to onError.
+func (bundle *AzureBlobResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.load(ctx, onError)
+}
+
+// load is the same as LoadContext, the blobs failing to download are reported to onError, if not nil.
+func (bundle *AzureBlobResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	client := azureClient(bundle.HTTPClient)
+	token := ""
+	if bundle.ManagedIdentity && len(bundle.SASToken) == 0 {
+		var err error
+		token, err = azureManagedIdentityToken(ctx, client, bundle.ClientID)
+		if err != nil {
+
+			return nil, err
+		}
+	}
+	containerURL := strings.TrimSuffix(bundle.AccountURL, "/") + "/" + url.PathEscape(bundle.Container)
+	names, err := bundle.list(ctx, client, containerURL, token)
+	if err != nil {
+
+		return nil, err
+	}
+	matched, err := matchObjectKeys(names, bundle.Prefix, bundle.PathPattern)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return loadObjects(ctx, matched, bundle.Concurrency, onError, func(name string) (Resource, error) {
+		contextLog(ctx).Debugf("Loading Azure blob %s/%s", bundle.Container, name)
+		res := &AzureBlobResource{
+			URL:             containerURL + "/" + strings.ReplaceAll(url.PathEscape(name), "%2F", "/"),
+			SASToken:        bundle.SASToken,
+			ManagedIdentity: bundle.ManagedIdentity,
+			ClientID:        bundle.ClientID,
+			HTTPClient:      bundle.HTTPClient,
+			Timeout:         bundle.Timeout,
+			token:           token,
+		}
+		_, err := res.Load()
+
+		return res, err
+	})
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use AzureBlobResourceBundle.LoadContext, or AzureBlobResourceBundle.LoadWithHandler to go on without the
+// blobs failing to download.
+func (bundle *AzureBlobResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// list returns the names of all blobs under the prefix, following the markers.
+func (bundle *AzureBlobResourceBundle) list(ctx context.Context, client *http.Client, containerURL, token string) ([]string, error) {
+	names := make([]string, 0)
+	marker := ""
+	for {
+		query := url.Values{}
+		query.Set("restype", "container")
+		query.Set("comp", "list")
+		if len(bundle.Prefix) > 0 {
+			query.Set("prefix", bundle.Prefix)
+		}
+		if len(marker) > 0 {
+			query.Set("marker", marker)
+		}
+		body, err := azureGet(ctx, client, containerURL, query, bundle.SASToken, token)
+		if err != nil {
+
+			return nil, err
+		}
+		page := &azureEnumerationResults{}
+		if err := xml.Unmarshal(body, page); err != nil {
+
+			return nil, fmt.Errorf("error while listing Azure container %s. got %w", bundle.Container, err)
+		}
+		for _, blob := range page.Blobs.Blob {
+			names = append(names, blob.Name)
+		}
+		if len(page.NextMarker) == 0 {
+
+			return names, nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+func azureClient(client *http.Client) *http.Client {
+	if client != nil {
+
+		return client
+	}
+
+	return &http.Client{}
+}
+
+// azureGet sends a GET request to the Blob service, authorized with the SAS token or the bearer token if any,
+// and returns the response body.
+func azureGet(ctx context.Context, client *http.Client, target string, query url.Values, sasToken, token string) ([]byte, error) {
+	params := make([]string, 0, 2)
+	if len(query) > 0 {
+		params = append(params, query.Encode())
+	}
+	if sas := strings.TrimPrefix(sasToken, "?"); len(sas) > 0 {
+		params = append(params, sas)
+	}
+	full := target
+	if len(params) > 0 {
+		full += "?" + strings.Join(params, "&")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, full, nil)
+	if err != nil {
+
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		azureErr := &struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}{}
+		if xml.Unmarshal(body, azureErr) != nil || len(azureErr.Code) == 0 {
+			azureErr.Code = resp.Status
+		}
+
+		return nil, fmt.Errorf("Azure Blob service responded %s to %s. %s", azureErr.Code, target, strings.TrimSpace(azureErr.Message))
+	}
+
+	return body, nil
+}
+
+// azureManagedIdentityToken gets a token for the storage from the managed identity endpoint of App Service,
+// Functions and Container Apps if IDENTITY_ENDPOINT is set, or else of the instance metadata service.
+func azureManagedIdentityToken(ctx context.Context, client *http.Client, clientID string) (string, error) {
+	query := url.Values{}
+	query.Set("resource", azureStorageResource)
+	if len(clientID) > 0 {
+		query.Set("client_id", clientID)
+	}
+	endpoint := os.Getenv("IDENTITY_ENDPOINT")
+	header, value := "Metadata", "true"
+	if len(endpoint) > 0 {
+		query.Set("api-version", "2019-08-01")
+		header, value = "X-IDENTITY-HEADER", os.Getenv("IDENTITY_HEADER")
+	} else {
+		query.Set("api-version", "2018-02-01")
+		endpoint = azureIMDSTokenURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+
+		return "", err
+	}
+	req.Header.Set(header, value)
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return "", fmt.Errorf("error while getting the managed identity token. got %w", err)
+	}
+	defer resp.Body.Close()
+	token := &struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+
+		return "", fmt.Errorf("invalid managed identity token response %s. got %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK || len(token.AccessToken) == 0 {
+
+		return "", fmt.Errorf("managed identity token request responded %s. %s %s", resp.Status, token.Error, token.Description)
+	}
+
+	return token.AccessToken, nil
+}
@@ -0,0 +1,120 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAzureBlobResourceBundle_Load(t *testing.T) {
+	blobs := map[string]string{
+		"rules/a.grl":          "rule A",
+		"rules/b.txt":          "not a rule",
+		"rules/nested/c.grl":   "rule C",
+		"rules/with space.grl": "rule D",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity" {
+			if r.Header.Get("X-IDENTITY-HEADER") != "secret" || r.URL.Query().Get("resource") != "https://storage.azure.com/" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_request","error_description":"Missing identity header"}`)
+
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"identity-token","token_type":"Bearer"}`)
+
+			return
+		}
+		if r.URL.Query().Get("sig") != "signed" && r.Header.Get("Authorization") != "Bearer identity-token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, "<Error><Code>AuthenticationFailed</Code><Message>Server failed to authenticate the request.</Message></Error>")
+
+			return
+		}
+		if r.Header.Get("x-ms-version") == "" {
+			t.Error("Expected the x-ms-version header")
+		}
+		if r.URL.Path == "/account/grl" && r.URL.Query().Get("comp") == "list" {
+			if r.URL.Query().Get("prefix") != "rules/" {
+				t.Errorf("Expected prefix rules/ but get %s", r.URL.Query().Get("prefix"))
+			}
+			if r.URL.Query().Get("marker") == "" {
+				fmt.Fprint(w, "<EnumerationResults><Blobs><Blob><Name>rules/a.grl</Name></Blob><Blob><Name>rules/b.txt</Name></Blob></Blobs><NextMarker>page2</NextMarker></EnumerationResults>")
+			} else {
+				fmt.Fprint(w, "<EnumerationResults><Blobs><Blob><Name>rules/nested/c.grl</Name></Blob><Blob><Name>rules/with space.grl</Name></Blob></Blobs><NextMarker /></EnumerationResults>")
+			}
+
+			return
+		}
+		content, ok := blobs[strings.TrimPrefix(r.URL.Path, "/account/grl/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>BlobNotFound</Code><Message>The specified blob does not exist.</Message></Error>")
+
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	check := func(resources []Resource, err error, expected ...string) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resources) != len(expected) {
+			t.Fatalf("Expected %d resources but get %d", len(expected), len(resources))
+		}
+		for i, res := range resources {
+			bytes, _ := res.Load()
+			if string(bytes) != expected[i] {
+				t.Errorf("Expected [%d] to be %s but get %s", i, expected[i], string(bytes))
+			}
+		}
+	}
+
+	bundle := NewAzureBlobResourceBundle(server.URL+"/account", "grl", "rules/", "**/*.grl")
+	bundle.SASToken = "?sv=2021-08-06&sig=signed"
+	resources, err := bundle.Load()
+	check(resources, err, "rule A", "rule C", "rule D")
+	if resources[0].String() != "From Azure blob ["+server.URL+"/account/grl/rules/a.grl]" {
+		t.Errorf("Unexpected resource name %s", resources[0].String())
+	}
+
+	t.Setenv("IDENTITY_ENDPOINT", server.URL+"/identity")
+	t.Setenv("IDENTITY_HEADER", "secret")
+	bundle.SASToken = ""
+	bundle.ManagedIdentity = true
+	bundle.PathPattern = []string{"*.grl"}
+	resources, err = bundle.Load()
+	check(resources, err, "rule A", "rule D")
+
+	bundle.ManagedIdentity = false
+	if _, err := bundle.Load(); err == nil || !strings.Contains(err.Error(), "AuthenticationFailed") {
+		t.Errorf("Expected authentication failure but get %v", err)
+	}
+
+	res := NewAzureBlobResource(server.URL+"/account/grl/rules/nested/c.grl", "sig=signed")
+	if bytes, err := res.Load(); err != nil || string(bytes) != "rule C" {
+		t.Errorf("Expected rule C but get %s, %v", string(bytes), err)
+	}
+	missing := NewAzureBlobResource(server.URL+"/account/grl/rules/missing.grl", "sig=signed")
+	if _, err := missing.Load(); err == nil || !strings.Contains(err.Error(), "BlobNotFound") {
+		t.Errorf("Expected blob not found but get %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RefreshableResource is a Resource keeping the content it loaded, which can be asked to load it again,
+// such as FileResource and URLResource.
+type RefreshableResource interface {
+	Resource
+	// RefreshContext loads the content again, it returns true if the content changed.
+	RefreshContext(ctx context.Context) (bool, error)
+}
+
+// NewCachedResource instantiates a new resource caching the content of the underlying Resource for the ttl.
+// A zero ttl caches the content until Invalidate is called.
+func NewCachedResource(res Resource, ttl time.Duration) *CachedResource {
+
+	return &CachedResource{
+		subRes: res,
+		ttl:    ttl,
+	}
+}
+
+// CachedResource caches the content of an underlying resource for a time to live, then loads it again on the
+// next Load. An underlying RefreshableResource, which keeps its own content, is refreshed instead, such as an
+// URLResource asking the server whether the content changed. CachedResource is safe for concurrent use,
+// concurrent loads of an expired content wait for a single load of the underlying resource.
+type CachedResource struct {
+	subRes Resource
+	ttl    time.Duration
+
+	mutex    sync.Mutex
+	data     []byte
+	loadedAt time.Time
+	// valid is false once the data is invalidated.
+	valid bool
+	// loaded tells the underlying resource was loaded once, so it is refreshed from now on.
+	loaded bool
+}
+
+// Load will return the cached content, loading the underlying resource if the content expired.
+func (cr *CachedResource) Load() ([]byte, error) {
+
+	return cr.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the underlying resource is loaded within the context.
+// A failed load returns the error and keeps the content expired, so the next Load tries again.
+func (cr *CachedResource) LoadContext(ctx context.Context) ([]byte, error) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	if cr.valid && (cr.ttl <= 0 || time.Since(cr.loadedAt) < cr.ttl) {
+
+		return cr.data, nil
+	}
+	if refreshable, ok := cr.subRes.(RefreshableResource); ok && cr.loaded {
+		if _, err := refreshable.RefreshContext(ctx); err != nil {
+
+			return nil, fmt.Errorf("error while refreshing %s. got %w", cr.subRes.String(), err)
+		}
+	}
+	data, err := LoadResource(ctx, cr.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+	cr.data = data
+	cr.loadedAt = time.Now()
+	cr.valid = true
+	cr.loaded = true
+
+	return cr.data, nil
+}
+
+// Invalidate expires the cached content, the next Load loads the underlying resource again.
+func (cr *CachedResource) Invalidate() {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.valid = false
+}
+
+// String will state the resource source.
+func (cr *CachedResource) String() string {
+	if cr.ttl <= 0 {
+
+		return "Cached Resource, underlying resource: " + cr.subRes.String()
+	}
+
+	return fmt.Sprintf("Cached Resource for %s, underlying resource: %s", cr.ttl, cr.subRes.String())
+}
@@ -0,0 +1,134 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingResource returns a new content on every Load.
+type countingResource struct {
+	loads atomic.Int32
+	fail  atomic.Bool
+}
+
+func (res *countingResource) Load() ([]byte, error) {
+	if res.fail.Load() {
+
+		return nil, fmt.Errorf("source is down")
+	}
+	time.Sleep(time.Millisecond)
+
+	return []byte(fmt.Sprintf("rule %d", res.loads.Add(1))), nil
+}
+
+func (res *countingResource) String() string {
+
+	return "counting resource"
+}
+
+func TestCachedResource(t *testing.T) {
+	inner := &countingResource{}
+	cached := NewCachedResource(inner, 50*time.Millisecond)
+	assert.Equal(t, "Cached Resource for 50ms, underlying resource: counting resource", cached.String())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := cached.Load()
+			assert.NoError(t, err)
+			assert.Equal(t, "rule 1", string(data))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), inner.loads.Load())
+
+	time.Sleep(60 * time.Millisecond)
+	data, err := cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule 2", string(data))
+
+	cached.Invalidate()
+	inner.fail.Store(true)
+	_, err = cached.Load()
+	assert.ErrorContains(t, err, "source is down")
+	inner.fail.Store(false)
+	data, err = cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule 3", string(data))
+
+	forever := NewCachedResource(inner, 0)
+	data, _ = forever.Load()
+	time.Sleep(10 * time.Millisecond)
+	again, _ := forever.Load()
+	assert.Equal(t, data, again)
+}
+
+func TestCachedResource_Refreshable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.grl")
+	assert.NoError(t, os.WriteFile(path, []byte("rule A"), 0o600))
+	cached := NewCachedResource(NewFileResource(path), 20*time.Millisecond)
+	data, err := cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A", string(data))
+
+	// the file resource keeps its bytes, the cache refreshes it once expired.
+	assert.NoError(t, os.WriteFile(path, []byte("rule B"), 0o600))
+	data, _ = cached.Load()
+	assert.Equal(t, "rule A", string(data))
+	time.Sleep(30 * time.Millisecond)
+	data, err = cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule B", string(data))
+
+	assert.NoError(t, os.Remove(path))
+	cached.Invalidate()
+	_, err = cached.Load()
+	assert.ErrorContains(t, err, "error while refreshing File resource at")
+
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("rule C"))
+	}))
+	defer server.Close()
+	cached = NewCachedResource(NewURLResource(server.URL), time.Hour)
+	data, err = cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule C", string(data))
+	cached.Invalidate()
+	data, err = cached.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule C", string(data))
+	assert.Equal(t, int32(1), downloads.Load())
+}
@@ -0,0 +1,469 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	setType        = reflect.TypeOf(Set{})
+	orderedMapType = reflect.TypeOf(OrderedMap{})
+)
+
+// Set is an insertion ordered set of values, it is the value of GRL literals such as {"US", "CA"}.
+// Facts may use it for their fields, such as allow and deny lists, so rules can test them with Has.
+// A Set is never modified, Union, Intersect and Difference return a new Set.
+type Set struct {
+	values []interface{}
+	index  map[interface{}]int
+}
+
+// NewSet create new instance of Set holding the values in their order, duplicates are dropped.
+// Integers are held as int64 and floats as float64, so 1 and int8(1) are the same element.
+func NewSet(values ...interface{}) (Set, error) {
+	set := Set{
+		values: make([]interface{}, 0, len(values)),
+		index:  make(map[interface{}]int, len(values)),
+	}
+	for _, value := range values {
+		key, err := collectionKey(value)
+		if err != nil {
+
+			return Set{}, err
+		}
+		set.add(key)
+	}
+
+	return set, nil
+}
+
+func (s *Set) add(key interface{}) {
+	if _, exist := s.index[key]; exist {
+
+		return
+	}
+	s.index[key] = len(s.values)
+	s.values = append(s.values, key)
+}
+
+func (s Set) derive(capacity int) Set {
+
+	return Set{
+		values: make([]interface{}, 0, capacity),
+		index:  make(map[interface{}]int, capacity),
+	}
+}
+
+// Has tells whether the value is an element of the set.
+func (s Set) Has(value interface{}) bool {
+	key, err := collectionKey(value)
+	if err != nil {
+
+		return false
+	}
+	_, exist := s.index[key]
+
+	return exist
+}
+
+// Len returns the number of elements in the set.
+func (s Set) Len() int {
+
+	return len(s.values)
+}
+
+// Values returns the elements of the set in their order.
+func (s Set) Values() []interface{} {
+
+	return append(make([]interface{}, 0, len(s.values)), s.values...)
+}
+
+// Union returns the elements of this set followed by the elements of the other set that are not in this set.
+func (s Set) Union(other Set) Set {
+	union := s.derive(len(s.values) + len(other.values))
+	for _, value := range s.values {
+		union.add(value)
+	}
+	for _, value := range other.values {
+		union.add(value)
+	}
+
+	return union
+}
+
+// Intersect returns the elements of this set that are also in the other set.
+func (s Set) Intersect(other Set) Set {
+	intersection := s.derive(len(s.values))
+	for _, value := range s.values {
+		if _, exist := other.index[value]; exist {
+			intersection.add(value)
+		}
+	}
+
+	return intersection
+}
+
+// Difference returns the elements of this set that are not in the other set.
+func (s Set) Difference(other Set) Set {
+	difference := s.derive(len(s.values))
+	for _, value := range s.values {
+		if _, exist := other.index[value]; !exist {
+			difference.add(value)
+		}
+	}
+
+	return difference
+}
+
+// Equals tells whether both sets have the same elements, regardless of their order.
+func (s Set) Equals(other Set) bool {
+	if len(s.values) != len(other.values) {
+
+		return false
+	}
+	for _, value := range s.values {
+		if _, exist := other.index[value]; !exist {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the set as written in GRL.
+func (s Set) String() string {
+	elements := make([]string, len(s.values))
+	for i, value := range s.values {
+		elements[i] = collectionString(value)
+	}
+
+	return "{" + strings.Join(elements, ", ") + "}"
+}
+
+// OrderedMap is an insertion ordered map, it is the value of GRL literals such as {"US": 10, "CA": 5}.
+// An OrderedMap is never modified, Union, Intersect and Difference return a new OrderedMap.
+type OrderedMap struct {
+	keys   []interface{}
+	values map[interface{}]interface{}
+}
+
+// NewOrderedMap create new instance of OrderedMap from alternating keys and values, such as
+// NewOrderedMap("US", 10, "CA", 5). A key repeated later replaces the value but keeps its first position.
+func NewOrderedMap(keyValues ...interface{}) (OrderedMap, error) {
+	if len(keyValues)%2 != 0 {
+
+		return OrderedMap{}, fmt.Errorf("ordered map needs a value for every key, got %d arguments", len(keyValues))
+	}
+	orderedMap := OrderedMap{
+		keys:   make([]interface{}, 0, len(keyValues)/2),
+		values: make(map[interface{}]interface{}, len(keyValues)/2),
+	}
+	for i := 0; i < len(keyValues); i += 2 {
+		key, err := collectionKey(keyValues[i])
+		if err != nil {
+
+			return OrderedMap{}, err
+		}
+		orderedMap.put(key, collectionValue(keyValues[i+1]))
+	}
+
+	return orderedMap, nil
+}
+
+func (m *OrderedMap) put(key, value interface{}) {
+	if _, exist := m.values[key]; !exist {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m OrderedMap) derive(capacity int) OrderedMap {
+
+	return OrderedMap{
+		keys:   make([]interface{}, 0, capacity),
+		values: make(map[interface{}]interface{}, capacity),
+	}
+}
+
+// Has tells whether the key is in the map.
+func (m OrderedMap) Has(key interface{}) bool {
+	normalized, err := collectionKey(key)
+	if err != nil {
+
+		return false
+	}
+	_, exist := m.values[normalized]
+
+	return exist
+}
+
+// Get returns the value of the key, or nil if the key is not in the map.
+func (m OrderedMap) Get(key interface{}) interface{} {
+	normalized, err := collectionKey(key)
+	if err != nil {
+
+		return nil
+	}
+
+	return m.values[normalized]
+}
+
+// Len returns the number of entries in the map.
+func (m OrderedMap) Len() int {
+
+	return len(m.keys)
+}
+
+// Keys returns the keys of the map in their order.
+func (m OrderedMap) Keys() []interface{} {
+
+	return append(make([]interface{}, 0, len(m.keys)), m.keys...)
+}
+
+// Values returns the values of the map in the order of their keys.
+func (m OrderedMap) Values() []interface{} {
+	values := make([]interface{}, len(m.keys))
+	for i, key := range m.keys {
+		values[i] = m.values[key]
+	}
+
+	return values
+}
+
+// KeySet returns the keys of the map as a Set.
+func (m OrderedMap) KeySet() Set {
+	set := Set{}.derive(len(m.keys))
+	for _, key := range m.keys {
+		set.add(key)
+	}
+
+	return set
+}
+
+// Union returns the entries of this map followed by the entries of the other map, the values of the other map
+// replace the values of the keys found in both.
+func (m OrderedMap) Union(other OrderedMap) OrderedMap {
+	union := m.derive(len(m.keys) + len(other.keys))
+	for _, key := range m.keys {
+		union.put(key, m.values[key])
+	}
+	for _, key := range other.keys {
+		union.put(key, other.values[key])
+	}
+
+	return union
+}
+
+// Intersect returns the entries of this map whose keys are also in the other map.
+func (m OrderedMap) Intersect(other OrderedMap) OrderedMap {
+	intersection := m.derive(len(m.keys))
+	for _, key := range m.keys {
+		if _, exist := other.values[key]; exist {
+			intersection.put(key, m.values[key])
+		}
+	}
+
+	return intersection
+}
+
+// Difference returns the entries of this map whose keys are not in the other map.
+func (m OrderedMap) Difference(other OrderedMap) OrderedMap {
+	difference := m.derive(len(m.keys))
+	for _, key := range m.keys {
+		if _, exist := other.values[key]; !exist {
+			difference.put(key, m.values[key])
+		}
+	}
+
+	return difference
+}
+
+// Equals tells whether both maps have the same entries, regardless of their order.
+func (m OrderedMap) Equals(other OrderedMap) bool {
+	if len(m.keys) != len(other.keys) {
+
+		return false
+	}
+	for _, key := range m.keys {
+		value, exist := other.values[key]
+		if !exist || !collectionValueEquals(m.values[key], value) {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the map as written in GRL.
+func (m OrderedMap) String() string {
+	entries := make([]string, len(m.keys))
+	for i, key := range m.keys {
+		entries[i] = collectionString(key) + ": " + collectionString(m.values[key])
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// IsCollection tells whether the value is a Set or an OrderedMap.
+func IsCollection(val reflect.Value) bool {
+
+	return val.IsValid() && (val.Type() == setType || val.Type() == orderedMapType)
+}
+
+// collectionKey normalizes a value into a set element or a map key, integers become int64 and floats float64.
+func collectionKey(value interface{}) (interface{}, error) {
+	value = collectionValue(value)
+	if value != nil && !reflect.TypeOf(value).Comparable() {
+
+		return nil, fmt.Errorf("%T can not be an element of a set or a key of an ordered map", value)
+	}
+
+	return value, nil
+}
+
+// collectionValue normalizes the numbers, booleans and strings held by a collection into their widest type.
+func collectionValue(value interface{}) interface{} {
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if val.Uint() > math.MaxInt64 {
+
+			return val.Uint()
+		}
+
+		return int64(val.Uint())
+	case reflect.Float32, reflect.Float64:
+
+		return val.Float()
+	case reflect.String:
+
+		return val.String()
+	case reflect.Bool:
+
+		return val.Bool()
+	case reflect.Pointer:
+		if !val.IsNil() && (val.Elem().Type() == setType || val.Elem().Type() == orderedMapType) {
+
+			return val.Elem().Interface()
+		}
+	}
+
+	return value
+}
+
+func collectionValueEquals(left, right interface{}) bool {
+	switch l := left.(type) {
+	case Set:
+		r, ok := right.(Set)
+
+		return ok && l.Equals(r)
+	case OrderedMap:
+		r, ok := right.(OrderedMap)
+
+		return ok && l.Equals(r)
+	}
+	if left != nil && !reflect.TypeOf(left).Comparable() {
+
+		return reflect.DeepEqual(left, right)
+	}
+
+	return left == right
+}
+
+func collectionString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+
+		return "nil"
+	case string:
+
+		return strconv.Quote(v)
+	case float64:
+
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// evaluateCollection evaluates an operation over two sets or two ordered maps. | is the union, & the
+// intersection and - the difference.
+func evaluateCollection(operator string, left, right reflect.Value) (reflect.Value, error) {
+	if !left.IsValid() || !right.IsValid() || left.Type() != right.Type() {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not evaluate %s on %s and %s", operator, describeOperand(left), describeOperand(right))
+	}
+	switch l := left.Interface().(type) {
+	case Set:
+		r := right.Interface().(Set)
+		switch operator {
+		case opBitOr:
+
+			return reflect.ValueOf(l.Union(r)), nil
+		case opBitAnd:
+
+			return reflect.ValueOf(l.Intersect(r)), nil
+		case opSub:
+
+			return reflect.ValueOf(l.Difference(r)), nil
+		case opEQ:
+
+			return reflect.ValueOf(l.Equals(r)), nil
+		case opNEQ:
+
+			return reflect.ValueOf(!l.Equals(r)), nil
+		}
+	case OrderedMap:
+		r := right.Interface().(OrderedMap)
+		switch operator {
+		case opBitOr:
+
+			return reflect.ValueOf(l.Union(r)), nil
+		case opBitAnd:
+
+			return reflect.ValueOf(l.Intersect(r)), nil
+		case opSub:
+
+			return reflect.ValueOf(l.Difference(r)), nil
+		case opEQ:
+
+			return reflect.ValueOf(l.Equals(r)), nil
+		case opNEQ:
+
+			return reflect.ValueOf(!l.Equals(r)), nil
+		}
+	}
+
+	return reflect.ValueOf(nil), fmt.Errorf("can not evaluate %s on %s", operator, describeOperand(left))
+}
+
+func describeOperand(val reflect.Value) string {
+	if !val.IsValid() {
+
+		return "nil"
+	}
+
+	return val.Type().String()
+}
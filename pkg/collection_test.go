@@ -0,0 +1,100 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	set, err := NewSet("US", 1, int8(1), uint(2), 2.5, "US")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, set.Len())
+	assert.Equal(t, []interface{}{"US", int64(1), int64(2), 2.5}, set.Values())
+	assert.True(t, set.Has(int32(1)))
+	assert.True(t, set.Has("US"))
+	assert.False(t, set.Has("CA"))
+	assert.False(t, set.Has([]string{"US"}))
+	assert.Equal(t, `{"US", 1, 2, 2.5}`, set.String())
+
+	other, err := NewSet("CA", 2, "US")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"US", 1, 2, 2.5, "CA"}`, set.Union(other).String())
+	assert.Equal(t, `{"US", 2}`, set.Intersect(other).String())
+	assert.Equal(t, `{1, 2.5}`, set.Difference(other).String())
+	assert.True(t, set.Intersect(other).Equals(other.Difference(Set{}).Intersect(set)))
+	assert.Equal(t, 4, set.Len(), "set must not be modified")
+	assert.Equal(t, 0, Set{}.Len())
+	assert.False(t, Set{}.Has("US"))
+
+	_, err = NewSet([]string{"US"})
+	assert.Error(t, err)
+}
+
+func TestOrderedMap(t *testing.T) {
+	limits, err := NewOrderedMap("US", 10, "CA", 5, "US", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"US", "CA"}, limits.Keys())
+	assert.Equal(t, []interface{}{int64(20), int64(5)}, limits.Values())
+	assert.Equal(t, int64(5), limits.Get("CA"))
+	assert.Nil(t, limits.Get("FR"))
+	assert.True(t, limits.Has("US"))
+	assert.Equal(t, `{"US": 20, "CA": 5}`, limits.String())
+	assert.Equal(t, `{"US", "CA"}`, limits.KeySet().String())
+
+	other, err := NewOrderedMap("FR", 1, "CA", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"US": 20, "CA": 7, "FR": 1}`, limits.Union(other).String())
+	assert.Equal(t, `{"CA": 5}`, limits.Intersect(other).String())
+	assert.Equal(t, `{"US": 20}`, limits.Difference(other).String())
+	assert.False(t, limits.Equals(limits.Union(other)))
+
+	_, err = NewOrderedMap("US")
+	assert.Error(t, err)
+}
+
+func TestCollectionOperators(t *testing.T) {
+	s := func(values ...interface{}) reflect.Value {
+		set, err := NewSet(values...)
+		assert.NoError(t, err)
+
+		return reflect.ValueOf(set)
+	}
+	testData := []struct {
+		evaluate func(left, right reflect.Value) (reflect.Value, error)
+		left     reflect.Value
+		right    reflect.Value
+		expect   string
+	}{
+		{EvaluateBitOr, s("a"), s("b", "a"), `{"a", "b"}`},
+		{EvaluateBitAnd, s("a", "b"), s("b"), `{"b"}`},
+		{EvaluateSubtraction, s("a", "b"), s("b"), `{"a"}`},
+		{EvaluateEqual, s("a", "b"), s("b", "a"), "true"},
+		{EvaluateNotEqual, s("a", "b"), s("b", "a"), "false"},
+	}
+	for i, td := range testData {
+		ret, err := td.evaluate(td.left, td.right)
+		if assert.NoError(t, err, "test %d", i) {
+			assert.Equal(t, td.expect, fmt.Sprintf("%v", ret.Interface()), "test %d", i)
+		}
+	}
+
+	_, err := EvaluateBitOr(s("a"), reflect.ValueOf("b"))
+	assert.Error(t, err)
+}
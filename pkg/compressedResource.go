@@ -0,0 +1,177 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Decompress returns the content of gzip or zstd compressed data, told apart by their magic numbers. Data that is
+// neither is returned as it is, so a resource may be compressed or not.
+func Decompress(data []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the gzip header. got %w", err)
+		}
+		reader = gzipReader
+	case bytes.HasPrefix(data, zstdMagic):
+		zstdReader, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the zstd header. got %w", err)
+		}
+		reader = zstdReader.IOReadCloser()
+	default:
+
+		return data, nil
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while decompressing. got %w", err)
+	}
+
+	return decompressed, nil
+}
+
+// CompressedResource decompresses the gzip or zstd content of an underlying resource provider when it is loaded,
+// such as a rules.grl.gz file or an URL serving rules.grl.zst. Content that is not compressed is returned as it is.
+type CompressedResource struct {
+	subRes Resource
+}
+
+// NewCompressedResource instantiates a new compressed resource decompressing the underlying Resource.
+func NewCompressedResource(res Resource) Resource {
+
+	return &CompressedResource{
+		subRes: res,
+	}
+}
+
+// Load will load the underlying Resource and decompress it.
+func (cr *CompressedResource) Load() ([]byte, error) {
+
+	return cr.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the underlying Resource is loaded within the context.
+func (cr *CompressedResource) LoadContext(ctx context.Context) ([]byte, error) {
+	data, err := LoadResource(ctx, cr.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+	decompressed, err := Decompress(data)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while decompressing %s. got %w", cr.subRes.String(), err)
+	}
+
+	return decompressed, nil
+}
+
+// String will state the resource source.
+func (cr *CompressedResource) String() string {
+
+	return "Compressed Resource, underlying resource: " + cr.subRes.String()
+}
+
+// Metadata implements ResourceInfo, describing the underlying Resource.
+func (cr *CompressedResource) Metadata() ResourceMetadata {
+
+	return metadataOf(cr.subRes)
+}
+
+// CompressedResourceBundle decompresses the resources of an underlying bundle resource provider when they are loaded,
+// such as a FileResourceBundle of the **/*.grl.gz files.
+type CompressedResourceBundle struct {
+	subRes ResourceBundle
+}
+
+// NewCompressedResourceBundle instantiates a new compressed resource bundle decompressing the resources of the
+// underlying ResourceBundle.
+func NewCompressedResourceBundle(bundle ResourceBundle) ResourceBundle {
+
+	return &CompressedResourceBundle{
+		subRes: bundle,
+	}
+}
+
+// Load will load the underlying ResourceBundle, its resources are decompressed when they are loaded.
+func (crb *CompressedResourceBundle) Load() ([]Resource, error) {
+	ress, err := crb.subRes.Load()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return crb.wrap(ress), nil
+}
+
+// LoadContext is the same as Load, the underlying ResourceBundle is loaded within the context.
+func (crb *CompressedResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	ress, err := LoadBundle(ctx, crb.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return crb.wrap(ress), nil
+}
+
+// LoadWithHandler is the same as Load, the resources of the underlying ResourceBundle failing to load are reported to onError.
+func (crb *CompressedResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ress, err := LoadBundleWithHandler(crb.subRes, onError)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return crb.wrap(ress), nil
+}
+
+// MustLoad operates the same as load except it will panic in the event of an error.
+//
+// Deprecated: use CompressedResourceBundle.Load, or CompressedResourceBundle.LoadWithHandler to go on without the
+// resources of the underlying bundle failing to load.
+func (crb *CompressedResourceBundle) MustLoad() []Resource {
+
+	return crb.wrap(crb.subRes.MustLoad())
+}
+
+func (crb *CompressedResourceBundle) wrap(ress []Resource) []Resource {
+	nress := make([]Resource, len(ress))
+	for i, res := range ress {
+		nress[i] = NewCompressedResource(res)
+	}
+
+	return nress
+}
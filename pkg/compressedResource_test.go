@@ -0,0 +1,74 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressedResource(t *testing.T) {
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	_, err := writer.Write([]byte(loremipsum))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	encoder, err := zstd.NewWriter(nil)
+	assert.NoError(t, err)
+	zstded := encoder.EncodeAll([]byte(loremipsum), nil)
+	assert.NoError(t, encoder.Close())
+
+	for _, data := range [][]byte{gzipped.Bytes(), zstded, []byte(loremipsum)} {
+		res := NewCompressedResource(NewBytesResource(data))
+		loaded, err := res.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, loremipsum, string(loaded))
+	}
+
+	_, err = NewCompressedResource(NewBytesResource(gzipped.Bytes()[:gzipped.Len()/2])).Load()
+	assert.Error(t, err)
+	_, err = NewCompressedResource(NewBytesResource(zstded[:len(zstded)/2])).Load()
+	assert.Error(t, err)
+}
+
+func TestCompressedResourceBundle(t *testing.T) {
+	dir := t.TempDir()
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	_, err := writer.Write([]byte("rule A {}"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.grl.gz"), gzipped.Bytes(), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.grl"), []byte("rule B {}"), 0o600))
+
+	bundle := NewCompressedResourceBundle(NewFileResourceBundle(dir, "*.grl*"))
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	data, err := resources[0].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A {}", string(data))
+	data, err = resources[1].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule B {}", string(data))
+	assert.Contains(t, resources[1].String(), "Compressed Resource, underlying resource: File resource at")
+}
@@ -0,0 +1,388 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// ConfigSource is a source of configuration values, keys are dotted such as "limits.max_amount".
+type ConfigSource interface {
+	// Lookup returns the value of the key, the boolean is false when the source does not have the key.
+	Lookup(key string) (string, bool, error)
+	String() string
+}
+
+// NewEnvConfigSource create a ConfigSource reading environment variables. The key "limits.max_amount" with
+// prefix "APP" is read from APP_LIMITS_MAX_AMOUNT.
+func NewEnvConfigSource(prefix string) *EnvConfigSource {
+
+	return &EnvConfigSource{Prefix: prefix}
+}
+
+// EnvConfigSource reads configuration values from environment variables.
+type EnvConfigSource struct {
+	Prefix string
+}
+
+// Lookup returns the environment variable of the key.
+func (src *EnvConfigSource) Lookup(key string) (string, bool, error) {
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if len(src.Prefix) > 0 {
+		name = strings.ToUpper(src.Prefix) + "_" + name
+	}
+	val, ok := os.LookupEnv(name)
+
+	return val, ok, nil
+}
+
+// String will state the config source.
+func (src *EnvConfigSource) String() string {
+
+	return fmt.Sprintf("environment config with prefix %s", src.Prefix)
+}
+
+// NewFlagConfigSource create a ConfigSource reading the flags of the flag set, flag.CommandLine is used if it is nil.
+// Only flags that are explicitly set are found, so the defaults of other sources still apply.
+func NewFlagConfigSource(flagSet *flag.FlagSet) *FlagConfigSource {
+	if flagSet == nil {
+		flagSet = flag.CommandLine
+	}
+
+	return &FlagConfigSource{FlagSet: flagSet}
+}
+
+// FlagConfigSource reads configuration values from command line flags, the key is the flag name.
+type FlagConfigSource struct {
+	FlagSet *flag.FlagSet
+}
+
+// Lookup returns the value of the flag named as the key.
+func (src *FlagConfigSource) Lookup(key string) (string, bool, error) {
+	var val string
+	found := false
+	src.FlagSet.Visit(func(f *flag.Flag) {
+		if f.Name == key {
+			val = f.Value.String()
+			found = true
+		}
+	})
+
+	return val, found, nil
+}
+
+// String will state the config source.
+func (src *FlagConfigSource) String() string {
+
+	return fmt.Sprintf("flag config %s", src.FlagSet.Name())
+}
+
+// NewConsulConfigSource create a ConfigSource reading the Consul KV store at address, such as http://localhost:8500.
+// The key "limits.max_amount" with prefix "app" is read from the KV path app/limits/max_amount.
+func NewConsulConfigSource(address, prefix, token string) *ConsulConfigSource {
+
+	return &ConsulConfigSource{
+		Address: strings.TrimSuffix(address, "/"),
+		Prefix:  strings.Trim(prefix, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// ConsulConfigSource reads configuration values from Consul KV using its HTTP API.
+type ConsulConfigSource struct {
+	Address string
+	Prefix  string
+	Token   string
+	Client  *http.Client
+}
+
+// Lookup returns the raw value of the KV path of the key.
+func (src *ConsulConfigSource) Lookup(key string) (string, bool, error) {
+	kvPath := strings.ReplaceAll(key, ".", "/")
+	if len(src.Prefix) > 0 {
+		kvPath = src.Prefix + "/" + kvPath
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, fmt.Sprintf("%s/v1/kv/%s?raw", src.Address, (&url.URL{Path: kvPath}).EscapedPath()), nil)
+	if err != nil {
+
+		return "", false, err
+	}
+	if len(src.Token) > 0 {
+		req.Header.Set("X-Consul-Token", src.Token)
+	}
+	resp, err := src.Client.Do(req)
+	if err != nil {
+
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+
+		return "", false, fmt.Errorf("consul returns status %d for key %s", resp.StatusCode, kvPath)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+
+		return "", false, err
+	}
+
+	return string(data), true, nil
+}
+
+// String will state the config source.
+func (src *ConsulConfigSource) String() string {
+
+	return fmt.Sprintf("consul config at %s/%s", src.Address, src.Prefix)
+}
+
+// MapConfigSource is a fixed set of configuration values.
+type MapConfigSource map[string]string
+
+// Lookup returns the value of the key.
+func (src MapConfigSource) Lookup(key string) (string, bool, error) {
+	val, ok := src[key]
+
+	return val, ok, nil
+}
+
+// String will state the config source.
+func (src MapConfigSource) String() string {
+
+	return "map config"
+}
+
+const (
+	// configRetryMin is the wait before the sources of a key that failed are read again, it doubles with every
+	// failure in a row up to configRetryMax.
+	configRetryMin = time.Second
+	configRetryMax = time.Minute
+)
+
+type configEntry struct {
+	value   string
+	found   bool
+	fetched time.Time
+	// failures counts the failed reads in a row, the sources are not read again before retryAt.
+	failures int
+	retryAt  time.Time
+}
+
+// NewConfigFact create a ConfigFact looking up the sources in order, the first source that has the key wins.
+// Values are cached for ttl, a zero ttl caches them forever.
+func NewConfigFact(ttl time.Duration, sources ...ConfigSource) *ConfigFact {
+
+	return NewConfigFactWithLogger(logger.LogEntry{}, ttl, sources...)
+}
+
+// NewConfigFactWithLogger create a ConfigFact as NewConfigFact does, that logs the sources failing and the values
+// that can not be converted into the log entry rather than into the default logger.
+func NewConfigFactWithLogger(log logger.LogEntry, ttl time.Duration, sources ...ConfigSource) *ConfigFact {
+
+	return &ConfigFact{
+		sources: sources,
+		ttl:     ttl,
+		cache:   make(map[string]configEntry),
+		now:     time.Now,
+		logger:  log,
+	}
+}
+
+// ConfigFact exposes configuration values to the rules, such as Config.GetFloat("limits.max_amount", 1000).
+// It has no exported fields so rules can only read from it.
+// The keys no source has are cached as well as the values. When a source fails the previously cached value is kept,
+// if there is none the default of the getter is used, and the sources are not read again for that key before a
+// delay growing with every failure in a row.
+type ConfigFact struct {
+	mutex   sync.Mutex
+	sources []ConfigSource
+	ttl     time.Duration
+	cache   map[string]configEntry
+	now     func() time.Time
+	logger  logger.LogEntry
+}
+
+// log returns the logger of this config fact, the default logger if it has none.
+func (cfg *ConfigFact) log() logger.LogEntry {
+
+	return cfg.logger.Or(logger.Log)
+}
+
+// lookup returns the value of the key from the cache, or from the sources when it expired. The sources are read
+// without holding the lock, so a slow source does not stall the reads of the other keys.
+func (cfg *ConfigFact) lookup(key string) (string, bool) {
+	cfg.mutex.Lock()
+	entry, cached := cfg.cache[key]
+	now := cfg.now()
+	if cached && (now.Before(entry.retryAt) || (entry.failures == 0 && (cfg.ttl == 0 || now.Sub(entry.fetched) < cfg.ttl))) {
+		cfg.mutex.Unlock()
+
+		return entry.value, entry.found
+	}
+	cfg.mutex.Unlock()
+
+	val, found, err := cfg.read(key)
+	cfg.mutex.Lock()
+	defer cfg.mutex.Unlock()
+	if err != nil {
+		entry.failures++
+		wait := configRetryMax
+		if entry.failures < 7 {
+			wait = configRetryMin << (entry.failures - 1)
+		}
+		entry.retryAt = now.Add(wait)
+		cfg.cache[key] = entry
+		cfg.log().Warnf("Can not read config %s, retrying in %s. got %v", key, wait, err)
+
+		return entry.value, entry.found
+	}
+	cfg.cache[key] = configEntry{value: val, found: found, fetched: now}
+
+	return val, found
+}
+
+// read looks the key up in the sources, the first source that has the key wins.
+func (cfg *ConfigFact) read(key string) (string, bool, error) {
+	for _, src := range cfg.sources {
+		val, found, err := src.Lookup(key)
+		if err != nil {
+
+			return "", false, fmt.Errorf("error while reading from %s. got %w", src.String(), err)
+		}
+		if found {
+
+			return val, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// Refresh drops the cached values so the next reads go to the sources.
+func (cfg *ConfigFact) Refresh() {
+	cfg.mutex.Lock()
+	defer cfg.mutex.Unlock()
+	cfg.cache = make(map[string]configEntry)
+}
+
+// Has tells whether any of the sources has the key.
+func (cfg *ConfigFact) Has(key string) bool {
+	_, found := cfg.lookup(key)
+
+	return found
+}
+
+// Get returns the value of the key, or an empty string if it is not found.
+func (cfg *ConfigFact) Get(key string) string {
+	val, _ := cfg.lookup(key)
+
+	return val
+}
+
+// GetString returns the value of the key, or defaultValue if it is not found.
+func (cfg *ConfigFact) GetString(key, defaultValue string) string {
+	val, found := cfg.lookup(key)
+	if !found {
+
+		return defaultValue
+	}
+
+	return val
+}
+
+// GetInt returns the value of the key as an integer, or defaultValue if it is not found or is not an integer.
+func (cfg *ConfigFact) GetInt(key string, defaultValue int64) int64 {
+	val, found := cfg.lookup(key)
+	if !found {
+
+		return defaultValue
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+	if err != nil {
+		cfg.log().Warnf("Config %s value %q is not an integer, using %d", key, val, defaultValue)
+
+		return defaultValue
+	}
+
+	return i
+}
+
+// GetFloat returns the value of the key as a float, or defaultValue if it is not found or is not a number.
+func (cfg *ConfigFact) GetFloat(key string, defaultValue float64) float64 {
+	val, found := cfg.lookup(key)
+	if !found {
+
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		cfg.log().Warnf("Config %s value %q is not a number, using %v", key, val, defaultValue)
+
+		return defaultValue
+	}
+
+	return f
+}
+
+// GetBool returns the value of the key as a boolean, or defaultValue if it is not found or is not a boolean.
+func (cfg *ConfigFact) GetBool(key string, defaultValue bool) bool {
+	val, found := cfg.lookup(key)
+	if !found {
+
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(val))
+	if err != nil {
+		cfg.log().Warnf("Config %s value %q is not a boolean, using %v", key, val, defaultValue)
+
+		return defaultValue
+	}
+
+	return b
+}
+
+// GetDuration returns the value of the key as a duration such as "1h30m", or defaultValue if it is not found or is
+// not a duration.
+func (cfg *ConfigFact) GetDuration(key string, defaultValue time.Duration) time.Duration {
+	val, found := cfg.lookup(key)
+	if !found {
+
+		return defaultValue
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(val))
+	if err != nil {
+		cfg.log().Warnf("Config %s value %q is not a duration, using %s", key, val, defaultValue)
+
+		return defaultValue
+	}
+
+	return d
+}
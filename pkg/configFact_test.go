@@ -0,0 +1,198 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFact_Sources(t *testing.T) {
+	t.Setenv("APP_LIMITS_MAX_AMOUNT", "2500.5")
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Int("retries", 3, "")
+	flags.Bool("strict", false, "")
+	assert.NoError(t, flags.Parse([]string{"-strict"}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/app/limits/window" || r.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+		_, _ = w.Write([]byte("90m"))
+	}))
+	defer server.Close()
+
+	cfg := NewConfigFact(0,
+		NewFlagConfigSource(flags),
+		NewEnvConfigSource("app"),
+		NewConsulConfigSource(server.URL, "app", "secret"),
+		MapConfigSource{"limits.max_amount": "10", "name": "grule"})
+
+	assert.Equal(t, 2500.5, cfg.GetFloat("limits.max_amount", 0))
+	assert.True(t, cfg.GetBool("strict", false))
+	// retries is not set on the command line, so its flag default is not used.
+	assert.False(t, cfg.Has("retries"))
+	assert.Equal(t, int64(7), cfg.GetInt("retries", 7))
+	assert.Equal(t, 90*time.Minute, cfg.GetDuration("limits.window", time.Minute))
+	assert.Equal(t, "grule", cfg.Get("name"))
+	assert.Equal(t, "", cfg.Get("unknown"))
+	assert.Equal(t, "fallback", cfg.GetString("unknown", "fallback"))
+	assert.Equal(t, int64(5), cfg.GetInt("name", 5))
+}
+
+func TestConfigFact_Cache(t *testing.T) {
+	values := MapConfigSource{"limit": "1"}
+	cfg := NewConfigFact(time.Minute, values)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg.now = func() time.Time {
+
+		return now
+	}
+
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	values["limit"] = "2"
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	now = now.Add(time.Minute)
+	assert.Equal(t, int64(2), cfg.GetInt("limit", 0))
+	values["limit"] = "3"
+	cfg.Refresh()
+	assert.Equal(t, int64(3), cfg.GetInt("limit", 0))
+}
+
+func TestConfigFact_StaleOnError(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+		_, _ = w.Write([]byte("42"))
+	}))
+	defer server.Close()
+
+	cfg := NewConfigFact(time.Nanosecond, NewConsulConfigSource(server.URL, "", ""))
+	assert.Equal(t, int64(42), cfg.GetInt("answer", 0))
+	failing.Store(true)
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, int64(42), cfg.GetInt("answer", 0))
+	assert.Equal(t, int64(-1), cfg.GetInt("other", -1))
+}
+
+// countingConfigSource counts its reads, fails when err is set and blocks while release is not closed.
+type countingConfigSource struct {
+	values  MapConfigSource
+	reads   atomic.Int32
+	err     error
+	release chan struct{}
+}
+
+func (src *countingConfigSource) Lookup(key string) (string, bool, error) {
+	src.reads.Add(1)
+	if src.release != nil {
+		<-src.release
+	}
+	if src.err != nil {
+
+		return "", false, src.err
+	}
+
+	return src.values.Lookup(key)
+}
+
+func (src *countingConfigSource) String() string {
+
+	return "counting"
+}
+
+func TestConfigFact_ReadOutsideLock(t *testing.T) {
+	src := &countingConfigSource{values: MapConfigSource{"fast": "1", "slow": "2"}}
+	cfg := NewConfigFact(0, src)
+	assert.Equal(t, int64(1), cfg.GetInt("fast", 0))
+
+	src.release = make(chan struct{})
+	done := make(chan int64)
+	go func() {
+		done <- cfg.GetInt("slow", 0)
+	}()
+	for src.reads.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	// the slow source is being read, the cached keys are still served.
+	assert.Equal(t, int64(1), cfg.GetInt("fast", 0))
+	close(src.release)
+	assert.Equal(t, int64(2), <-done)
+}
+
+func TestConfigFact_Backoff(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logrusLogger := logrus.New()
+	logrusLogger.SetOutput(buffer)
+	log, ok := logger.FromExternal(logrusLogger)
+	assert.True(t, ok)
+
+	src := &countingConfigSource{values: MapConfigSource{"limit": "1"}}
+	cfg := NewConfigFactWithLogger(log, time.Second, src)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg.now = func() time.Time {
+
+		return now
+	}
+
+	// the keys no source has are cached too.
+	assert.Equal(t, int64(5), cfg.GetInt("missing", 5))
+	assert.Equal(t, int64(5), cfg.GetInt("missing", 5))
+	assert.Equal(t, int32(1), src.reads.Load())
+
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(2), src.reads.Load())
+
+	src.err = errors.New("unreachable")
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(3), src.reads.Load())
+	assert.Contains(t, buffer.String(), "Can not read config limit, retrying in 1s")
+
+	// the failing source is not read again before the retry delay, that doubles with every failure.
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(3), src.reads.Load())
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(4), src.reads.Load())
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(1), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(4), src.reads.Load())
+
+	src.err = nil
+	src.values["limit"] = "2"
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(2), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(5), src.reads.Load())
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(2), cfg.GetInt("limit", 0))
+	assert.Equal(t, int32(6), src.reads.Load())
+}
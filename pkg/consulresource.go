@@ -0,0 +1,294 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// NewConsulResourceBundle will create a new instance of ConsulResourceBundle reading the keys of the Consul KV store
+// under the prefix, such as "grule/rules/". pathPattern are list of key patterns (glob), relative to the prefix,
+// to filter the keys. All keys under the prefix are read if none is given.
+// The address and the token are read from the CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN environment variables,
+// unless they are set in the bundle.
+func NewConsulResourceBundle(prefix string, pathPattern ...string) *ConsulResourceBundle {
+
+	return &ConsulResourceBundle{
+		Prefix:      prefix,
+		PathPattern: pathPattern,
+	}
+}
+
+// ConsulResourceBundle is a helper struct to load the GRL kept in the keys of the Consul KV store under a prefix.
+// Watch reloads them every time one of the keys is changed, using the blocking queries of Consul.
+type ConsulResourceBundle struct {
+	// Prefix of the keys to read, such as grule/rules/.
+	Prefix string
+	// List Glob like key pattern, relative to the prefix, such as **/*.grl. All keys are read if empty.
+	PathPattern []string
+	// Address of the Consul agent, such as http://127.0.0.1:8500. If empty, CONSUL_HTTP_ADDR or http://127.0.0.1:8500.
+	Address string
+	// Token authorizes the requests. If empty, CONSUL_HTTP_TOKEN is used.
+	Token string
+	// Datacenter to read from, the datacenter of the agent if empty.
+	Datacenter string
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	// WaitTime is how long a blocking query of Watch waits for a change, 5 minutes if zero.
+	WaitTime time.Duration
+	// RetryInterval is the delay before Watch queries again after an error, 5 seconds if zero.
+	RetryInterval time.Duration
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// consulKV is an entry of the KV listing.
+type consulKV struct {
+	Key         string `json:"Key"`
+	Value       []byte `json:"Value"`
+	ModifyIndex uint64 `json:"ModifyIndex"`
+}
+
+// Load lists the keys under the Prefix and returns the GRL they hold, in the order of their key.
+func (bundle *ConsulResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *ConsulResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	pairs, _, err := bundle.list(ctx, 0)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return bundle.resources(pairs)
+}
+
+// LoadWithHandler is the same as Load. The keys are all read by one request, its failure is reported to onError
+// with a nil resource: DecisionRetry reads the keys again and DecisionSkip returns no resource.
+func (bundle *ConsulResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return loadAllWithHandler(context.Background(), bundle.Load, onError)
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use ConsulResourceBundle.LoadContext, or ConsulResourceBundle.LoadWithHandler to retry the read of the
+// keys.
+func (bundle *ConsulResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// Watch loads the resources and calls onReload with them, then blocks on the keys under the Prefix and calls
+// onReload again after every change, until the context is done. A failed load is given to onReload with the
+// error, and retried after the RetryInterval.
+func (bundle *ConsulResourceBundle) Watch(ctx context.Context, onReload func(resources []Resource, err error)) error {
+	retry := bundle.RetryInterval
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	index := uint64(0)
+	for {
+		pairs, next, err := bundle.list(ctx, index)
+		if ctx.Err() != nil {
+
+			return ctx.Err()
+		}
+		switch {
+		case err != nil && index == 0:
+			onReload(nil, err)
+		case err != nil:
+			contextLog(ctx).Warnf("Watch of Consul prefix %s failed, retrying. %v", bundle.Prefix, err)
+		case next < index:
+			// the index went backwards, such as after a restore of the store, the keys are listed again.
+			index = 0
+
+			continue
+		case next != index:
+			index = next
+			resources, err := bundle.resources(pairs)
+			onReload(resources, err)
+
+			continue
+		default:
+
+			continue
+		}
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// list returns the keys under the Prefix with the index of the store. If index is not zero, the query blocks
+// until the store index goes past it or the WaitTime is over.
+func (bundle *ConsulResourceBundle) list(ctx context.Context, index uint64) ([]consulKV, uint64, error) {
+	query := url.Values{}
+	query.Set("recurse", "true")
+	if len(bundle.Datacenter) > 0 {
+		query.Set("dc", bundle.Datacenter)
+	}
+	if index > 0 {
+		wait := bundle.WaitTime
+		if wait <= 0 {
+			wait = 5 * time.Minute
+		}
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", strconv.FormatInt(wait.Milliseconds(), 10)+"ms")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundle.address()+"/v1/kv/"+strings.TrimPrefix(bundle.Prefix, "/")+"?"+query.Encode(), nil)
+	if err != nil {
+
+		return nil, 0, err
+	}
+	if token := bundle.token(); len(token) > 0 {
+		req.Header.Set("X-Consul-Token", token)
+	}
+	client := bundle.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	next, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	if next == 0 {
+		next = 1
+	}
+	if resp.StatusCode == http.StatusNotFound {
+
+		return make([]consulKV, 0), next, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+
+		return nil, 0, fmt.Errorf("Consul responded %s to %s. %s", resp.Status, req.URL.Path, strings.TrimSpace(string(body)))
+	}
+	pairs := make([]consulKV, 0)
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+
+		return nil, 0, fmt.Errorf("error while listing Consul prefix %s. got %w", bundle.Prefix, err)
+	}
+
+	return pairs, next, nil
+}
+
+// resources returns the GRL of the keys matching the PathPattern, sorted by key.
+func (bundle *ConsulResourceBundle) resources(pairs []consulKV) ([]Resource, error) {
+	values := make(map[string]consulKV, len(pairs))
+	keys := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if strings.HasSuffix(pair.Key, "/") {
+
+			continue
+		}
+		keys = append(keys, pair.Key)
+		values[pair.Key] = pair
+	}
+	sort.Strings(keys)
+	if len(bundle.PathPattern) > 0 {
+		var err error
+		keys, err = matchObjectKeys(keys, strings.TrimPrefix(bundle.Prefix, "/"), bundle.PathPattern)
+		if err != nil {
+
+			return nil, err
+		}
+	}
+	ret := make([]Resource, 0, len(keys))
+	for _, key := range keys {
+		logger.Log.Debugf("Loading Consul key %s", key)
+		ret = append(ret, &ConsulResource{
+			Key:         key,
+			ModifyIndex: values[key].ModifyIndex,
+			Bytes:       values[key].Value,
+		})
+	}
+
+	return ret, nil
+}
+
+func (bundle *ConsulResourceBundle) address() string {
+	address := bundle.Address
+	if len(address) == 0 {
+		address = os.Getenv("CONSUL_HTTP_ADDR")
+	}
+	if len(address) == 0 {
+		address = "127.0.0.1:8500"
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	return strings.TrimSuffix(address, "/")
+}
+
+func (bundle *ConsulResourceBundle) token() string {
+	if len(bundle.Token) > 0 {
+
+		return bundle.Token
+	}
+
+	return os.Getenv("CONSUL_HTTP_TOKEN")
+}
+
+// ConsulResource resource implementation that loaded from a key of the Consul KV store
+type ConsulResource struct {
+	Key string
+	// ModifyIndex is the index of the store at the last change of the key.
+	ModifyIndex uint64
+	Bytes       []byte
+}
+
+// String will state the resource key.
+func (res *ConsulResource) String() string {
+
+	return fmt.Sprintf("From Consul KV key %s", res.Key)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the key from Consul when
+// this method is called, it simply return the loaded data.
+func (res *ConsulResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
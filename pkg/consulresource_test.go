@@ -0,0 +1,176 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type consulServer struct {
+	mutex   sync.Mutex
+	index   uint64
+	keys    map[string]string
+	changed chan struct{}
+}
+
+func (server *consulServer) set(key, value string) {
+	server.mutex.Lock()
+	server.keys[key] = value
+	server.index++
+	server.mutex.Unlock()
+	select {
+	case server.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (server *consulServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != "acl-token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("ACL not found"))
+
+		return
+	}
+	if index := r.URL.Query().Get("index"); len(index) > 0 {
+		server.mutex.Lock()
+		current := strconv.FormatUint(server.index, 10)
+		server.mutex.Unlock()
+		if index == current {
+			select {
+			case <-server.changed:
+			case <-time.After(100 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+		}
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	pairs := make([]consulKV, 0)
+	for key, value := range server.keys {
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, consulKV{Key: key, Value: []byte(value), ModifyIndex: server.index})
+		}
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(server.index, 10))
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func newConsulServer() *consulServer {
+
+	return &consulServer{
+		index: 10,
+		keys: map[string]string{
+			"grule/rules/":              "",
+			"grule/rules/b.grl":         "rule B",
+			"grule/rules/pricing/a.grl": "rule A",
+			"grule/rules/readme.md":     "not a rule",
+			"grule/other/c.grl":         "rule C",
+		},
+		changed: make(chan struct{}),
+	}
+}
+
+func TestConsulResourceBundle(t *testing.T) {
+	server := httptest.NewServer(newConsulServer())
+	defer server.Close()
+
+	bundle := NewConsulResourceBundle("grule/rules/", "**/*.grl")
+	bundle.Address = server.URL
+	bundle.Token = "acl-token"
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	if assert.Len(t, resources, 2) {
+		assert.Equal(t, "From Consul KV key grule/rules/b.grl", resources[0].String())
+		data, err := resources[1].Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "rule A", string(data))
+	}
+
+	bundle.PathPattern = nil
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	bundle.Prefix = "grule/missing/"
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 0)
+
+	bundle.Token = "wrong"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "ACL not found")
+
+	// the keys are read again once the handler fixed the token.
+	bundle.Prefix = "grule/rules/"
+	resources, err = bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		assert.Nil(t, res)
+		bundle.Token = "acl-token"
+
+		return DecisionRetry
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+}
+
+func TestConsulResourceBundle_Watch(t *testing.T) {
+	api := newConsulServer()
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	bundle := NewConsulResourceBundle("grule/rules/", "**/*.grl")
+	bundle.Address = server.URL
+	bundle.Token = "acl-token"
+	reloads := make(chan []Resource)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bundle.Watch(ctx, func(resources []Resource, err error) {
+			assert.NoError(t, err)
+			reloads <- resources
+		})
+	}()
+
+	resources := <-reloads
+	assert.Len(t, resources, 2)
+	api.set("grule/rules/b.grl", "rule B2")
+	select {
+	case resources = <-reloads:
+	case <-time.After(time.Second):
+		t.Fatal("expected a reload after the change")
+	}
+	data, err := resources[0].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule B2", string(data))
+	assert.Equal(t, uint64(11), resources[0].(*ConsulResource).ModifyIndex)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -18,13 +18,13 @@
 package pkg
 
 import (
+	"context"
 	"embed"
 	"path/filepath"
 	"runtime"
 	"strings"
 
 	"github.com/bmatcuk/doublestar"
-	"github.com/hyperjumptech/grule-rule-engine/logger"
 )
 
 // EmbeddedResource is a struct that will load an embedded file from an embed.FS struct.
@@ -103,10 +103,19 @@ func NewEmbeddedResourceBundle(source embed.FS, basePath string, pathPattern ...
 // Load all embedded file resources that located under BasePath that conform to the PathPattern.
 func (bundle *EmbeddedResourceBundle) Load() ([]Resource, error) {
 
-	return bundle.loadPath(bundle.BasePath)
+	return bundle.loadPath(context.Background(), bundle.BasePath, nil)
+}
+
+// LoadWithHandler is the same as Load, the files failing to be read are reported to onError.
+func (bundle *EmbeddedResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return bundle.loadPath(context.Background(), bundle.BasePath, onError)
 }
 
 // MustLoad function is the same as Load with difference that it will panic if any error is raised
+//
+// Deprecated: use EmbeddedResourceBundle.Load, or EmbeddedResourceBundle.LoadWithHandler to go on without the files
+// failing to be read.
 func (bundle *EmbeddedResourceBundle) MustLoad() []Resource {
 	resources, err := bundle.Load()
 	if err != nil {
@@ -117,8 +126,8 @@ func (bundle *EmbeddedResourceBundle) MustLoad() []Resource {
 	return resources
 }
 
-func (bundle *EmbeddedResourceBundle) loadPath(path string) ([]Resource, error) {
-	logger.Log.Tracef("Enter embedded directory %s", path)
+func (bundle *EmbeddedResourceBundle) loadPath(ctx context.Context, path string, onError func(Resource, error) Decision) ([]Resource, error) {
+	contextLog(ctx).Tracef("Enter embedded directory %s", path)
 
 	finfos, err := bundle.Source.ReadDir(path)
 	if err != nil {
@@ -138,7 +147,7 @@ func (bundle *EmbeddedResourceBundle) loadPath(path string) ([]Resource, error)
 	for _, finfo := range finfos {
 		fulPath := filepath.Join(path, finfo.Name())
 		if finfo.IsDir() {
-			gres, err := bundle.loadPath(fulPath)
+			gres, err := bundle.loadPath(ctx, fulPath, onError)
 			if err != nil {
 
 				return nil, err
@@ -152,13 +161,19 @@ func (bundle *EmbeddedResourceBundle) loadPath(path string) ([]Resource, error)
 					return nil, err
 				}
 				if matched {
-					logger.Log.Debugf("Loading embedded file %s", fulPath)
+					contextLog(ctx).Debugf("Loading embedded file %s", fulPath)
 					gress := NewEmbeddedResource(bundle.Source, fulPath)
-					_, err := gress.Load()
+					skipped, err := retryLoad(ctx, gress, onError, func() error {
+						_, err := gress.Load()
+
+						return err
+					})
 					if err != nil {
 						return nil, err
 					}
-					ret = append(ret, gress)
+					if !skipped {
+						ret = append(ret, gress)
+					}
 
 					break
 				}
@@ -28,7 +28,10 @@ var rules embed.FS
 
 func TestEmbeddedResourceBundle_Load(t *testing.T) {
 	erb := NewEmbeddedResourceBundle(rules, ".", "/**/*.grl")
-	resources := erb.MustLoad()
+	resources, err := erb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(resources) != 6 {
 		t.Errorf("Expected 6 but get %d", len(resources))
 		t.FailNow()
@@ -0,0 +1,223 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Decrypter decrypts the content of an encrypted resource. Implement it to use another format than AES-GCM,
+// such as age with filippo.io/age.
+type Decrypter interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// AESGCMDecrypter decrypts the content sealed by EncryptAESGCM: a 12 bytes nonce followed by the ciphertext and
+// its 16 bytes authentication tag. The key is 16, 24 or 32 bytes long, for AES-128, AES-192 or AES-256.
+type AESGCMDecrypter struct {
+	// Key is the AES key, ignored if KeyFunc is set.
+	Key []byte
+	// KeyFunc, if set, returns the key on each decryption, such as a data key unwrapped by a KMS.
+	KeyFunc func(ctx context.Context) ([]byte, error)
+}
+
+// Decrypt implements Decrypter
+func (decrypter *AESGCMDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	key := decrypter.Key
+	if decrypter.KeyFunc != nil {
+		var err error
+		key, err = decrypter.KeyFunc(ctx)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while obtaining the decryption key. got %w", err)
+		}
+	}
+	gcm, err := newAESGCM(key)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+
+		return nil, errors.New("encrypted content is too short for AES-GCM")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while decrypting, wrong key or tampered content. got %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// EncryptAESGCM seals the plaintext with the key and a random nonce, in the format AESGCMDecrypter decrypts.
+func EncryptAESGCM(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+
+		return nil, fmt.Errorf("invalid AES key. got %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptedResource decrypts the content of an underlying resource provider when it is loaded.
+// The decrypted content is not cached, every Load decrypts again.
+type EncryptedResource struct {
+	subRes    Resource
+	decrypter Decrypter
+}
+
+// NewEncryptedResource instantiates a new encrypted resource decrypting the underlying Resource with the decrypter.
+func NewEncryptedResource(res Resource, decrypter Decrypter) Resource {
+
+	return &EncryptedResource{
+		subRes:    res,
+		decrypter: decrypter,
+	}
+}
+
+// Load will load the underlying Resource and decrypt it.
+func (er *EncryptedResource) Load() ([]byte, error) {
+	data, err := er.subRes.Load()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return er.decrypt(context.Background(), data)
+}
+
+// LoadContext is the same as Load, the underlying Resource is loaded and decrypted within the context.
+func (er *EncryptedResource) LoadContext(ctx context.Context) ([]byte, error) {
+	data, err := LoadResource(ctx, er.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return er.decrypt(ctx, data)
+}
+
+func (er *EncryptedResource) decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	plaintext, err := er.decrypter.Decrypt(ctx, data)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while decrypting %s. got %w", er.subRes.String(), err)
+	}
+
+	return plaintext, nil
+}
+
+// String will state the resource source.
+func (er *EncryptedResource) String() string {
+
+	return "Encrypted Resource, underlying resource: " + er.subRes.String()
+}
+
+// Metadata implements ResourceInfo, describing the underlying Resource.
+func (er *EncryptedResource) Metadata() ResourceMetadata {
+
+	return metadataOf(er.subRes)
+}
+
+// EncryptedResourceBundle decrypts the resources of an underlying bundle resource provider when they are loaded.
+type EncryptedResourceBundle struct {
+	subRes    ResourceBundle
+	decrypter Decrypter
+}
+
+// NewEncryptedResourceBundle instantiates a new encrypted resource bundle decrypting the resources of the underlying
+// ResourceBundle with the decrypter.
+func NewEncryptedResourceBundle(bundle ResourceBundle, decrypter Decrypter) ResourceBundle {
+
+	return &EncryptedResourceBundle{
+		subRes:    bundle,
+		decrypter: decrypter,
+	}
+}
+
+// Load will load the underlying ResourceBundle, its resources are decrypted when they are loaded.
+func (erb *EncryptedResourceBundle) Load() ([]Resource, error) {
+	ress, err := erb.subRes.Load()
+	if err != nil {
+
+		return nil, err
+	}
+
+	return erb.wrap(ress), nil
+}
+
+// LoadContext is the same as Load, the underlying ResourceBundle is loaded within the context.
+func (erb *EncryptedResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	ress, err := LoadBundle(ctx, erb.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return erb.wrap(ress), nil
+}
+
+// LoadWithHandler is the same as Load, the resources of the underlying ResourceBundle failing to load are reported to onError.
+func (erb *EncryptedResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ress, err := LoadBundleWithHandler(erb.subRes, onError)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return erb.wrap(ress), nil
+}
+
+// MustLoad operates the same as load except it will panic in the event of an error.
+//
+// Deprecated: use EncryptedResourceBundle.Load, or EncryptedResourceBundle.LoadWithHandler to go on without the
+// resources of the underlying bundle failing to load.
+func (erb *EncryptedResourceBundle) MustLoad() []Resource {
+
+	return erb.wrap(erb.subRes.MustLoad())
+}
+
+func (erb *EncryptedResourceBundle) wrap(ress []Resource) []Resource {
+	nress := make([]Resource, len(ress))
+	for i, res := range ress {
+		nress[i] = NewEncryptedResource(res, erb.decrypter)
+	}
+
+	return nress
+}
@@ -0,0 +1,86 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedResource(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	sealed, err := EncryptAESGCM(key, []byte(loremipsum))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sealed), "Lorem")
+
+	res := NewEncryptedResource(NewBytesResource(sealed), &AESGCMDecrypter{Key: key})
+	data, err := res.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, loremipsum, string(data))
+
+	_, err = NewEncryptedResource(NewBytesResource(sealed), &AESGCMDecrypter{Key: []byte("fedcba9876543210fedcba9876543210")}).Load()
+	assert.Error(t, err)
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = NewEncryptedResource(NewBytesResource(tampered), &AESGCMDecrypter{Key: key}).Load()
+	assert.Error(t, err)
+	_, err = NewEncryptedResource(NewBytesResource(sealed[:8]), &AESGCMDecrypter{Key: key}).Load()
+	assert.Error(t, err)
+	_, err = EncryptAESGCM([]byte("short"), []byte(loremipsum))
+	assert.Error(t, err)
+
+	// the key of a KMS, obtained within the loading context.
+	unavailable := errors.New("kms unavailable")
+	kms := &AESGCMDecrypter{KeyFunc: func(ctx context.Context) ([]byte, error) {
+		if ctx.Value(kmsKey{}) == nil {
+
+			return nil, unavailable
+		}
+
+		return key, nil
+	}}
+	res = NewEncryptedResource(NewBytesResource(sealed), kms)
+	_, err = res.Load()
+	assert.ErrorIs(t, err, unavailable)
+	data, err = LoadResource(context.WithValue(context.Background(), kmsKey{}, true), res)
+	assert.NoError(t, err)
+	assert.Equal(t, loremipsum, string(data))
+}
+
+type kmsKey struct{}
+
+func TestEncryptedResourceBundle(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	dir := t.TempDir()
+	for name, grl := range map[string]string{"a.grl": "rule A {}", "b.grl": "rule B {}"} {
+		sealed, err := EncryptAESGCM(key, []byte(grl))
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), sealed, 0o600))
+	}
+
+	bundle := NewEncryptedResourceBundle(NewFileResourceBundle(dir, "*.grl"), &AESGCMDecrypter{Key: key})
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	data, err := resources[0].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A {}", string(data))
+	assert.Contains(t, resources[1].String(), "Encrypted Resource, underlying resource: File resource at")
+}
@@ -23,6 +23,8 @@ import (
 type GruleErrorReporter struct {
 	*antlr.DefaultErrorListener // Embed default which ensures we fit the interface
 	Errors                      []error
+	// LineOffset is added to the line of syntax errors, used when a GRL text is parsed in pieces.
+	LineOffset int
 }
 
 // AddError simply add an error into this reporter
@@ -32,7 +34,7 @@ func (c *GruleErrorReporter) AddError(err error) {
 
 // SyntaxError call back which will be called upon parsing error
 func (c *GruleErrorReporter) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	c.Errors = append(c.Errors, fmt.Errorf("grl error on %d:%d %s", line, column, msg))
+	c.Errors = append(c.Errors, fmt.Errorf("grl error on %d:%d %s", line+c.LineOffset, column, msg))
 }
 
 // HasError check if this reporter has an error
@@ -0,0 +1,503 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewEtcdResourceBundle will create a new instance of EtcdResourceBundle reading the keys of etcd under the prefix,
+// such as "/grule/rules/". pathPattern are list of key patterns (glob), relative to the prefix, to filter the keys.
+// All keys under the prefix are read if none is given.
+func NewEtcdResourceBundle(endpoint, prefix string, pathPattern ...string) *EtcdResourceBundle {
+
+	return &EtcdResourceBundle{
+		Endpoint:    endpoint,
+		Prefix:      prefix,
+		PathPattern: pathPattern,
+	}
+}
+
+// EtcdResourceBundle is a helper struct to load the GRL kept in the keys of etcd under a prefix, through the
+// JSON gateway of the etcd v3 API. Pin the Revision so every replica reads the same keys and builds the same
+// knowledge base, whatever is written after. Watch reports every change of the keys after the loaded revision.
+type EtcdResourceBundle struct {
+	// Endpoint of an etcd member, such as http://127.0.0.1:2379.
+	Endpoint string
+	// Prefix of the keys to read, such as /grule/rules/.
+	Prefix string
+	// List Glob like key pattern, relative to the prefix, such as **/*.grl. All keys are read if empty.
+	PathPattern []string
+	// Revision, if not zero, is the revision of the store the keys are read at, the latest revision if zero.
+	// A revision that has been compacted fails to load.
+	Revision int64
+	// Username and Password authenticate to etcd, if its authentication is enabled.
+	Username string
+	Password string
+	// HTTPClient, if set, sends the requests, such as a client with the TLS certificates of the cluster.
+	HTTPClient *http.Client
+	// RetryInterval is the delay before Watch connects again after an error, 5 seconds if zero.
+	RetryInterval time.Duration
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// etcdInt is an int64 of the etcd JSON gateway, which writes them as strings.
+type etcdInt int64
+
+// UnmarshalJSON reads the int64 written as a string or as a number.
+func (value *etcdInt) UnmarshalJSON(data []byte) error {
+	parsed, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+
+		return err
+	}
+	*value = etcdInt(parsed)
+
+	return nil
+}
+
+// etcdKV is a key of the store.
+type etcdKV struct {
+	Key         []byte  `json:"key"`
+	Value       []byte  `json:"value"`
+	ModRevision etcdInt `json:"mod_revision"`
+}
+
+// etcdHeader is the header of every response.
+type etcdHeader struct {
+	Revision etcdInt `json:"revision"`
+}
+
+// etcdRangeResponse is a page of the keys.
+type etcdRangeResponse struct {
+	Header etcdHeader `json:"header"`
+	Kvs    []etcdKV   `json:"kvs"`
+	More   bool       `json:"more"`
+}
+
+// etcdWatchResponse is a message of the watch stream.
+type etcdWatchResponse struct {
+	Result *struct {
+		Header          etcdHeader `json:"header"`
+		Created         bool       `json:"created"`
+		Canceled        bool       `json:"canceled"`
+		CompactRevision etcdInt    `json:"compact_revision"`
+		CancelReason    string     `json:"cancel_reason"`
+		Events          []struct {
+			Type string `json:"type"`
+			Kv   etcdKV `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Load reads the keys under the Prefix at the Revision and returns the GRL they hold, in the order of their key.
+func (bundle *EtcdResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *EtcdResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	resources, _, err := bundle.LoadRevision(ctx)
+
+	return resources, err
+}
+
+// LoadWithHandler is the same as Load. The keys are all read at one revision, the failure to read them is reported
+// to onError with a nil resource: DecisionRetry reads the keys again and DecisionSkip returns no resource.
+func (bundle *EtcdResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return loadAllWithHandler(context.Background(), bundle.Load, onError)
+}
+
+// LoadRevision is the same as LoadContext, it also returns the revision the keys were read at, which is the
+// Revision to pin the other replicas to.
+func (bundle *EtcdResourceBundle) LoadRevision(ctx context.Context) ([]Resource, int64, error) {
+	token, err := bundle.authenticate(ctx)
+	if err != nil {
+
+		return nil, 0, err
+	}
+	state, revision, err := bundle.load(ctx, token, bundle.Revision)
+	if err != nil {
+
+		return nil, 0, err
+	}
+
+	return etcdResources(state), revision, nil
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use EtcdResourceBundle.LoadContext, or EtcdResourceBundle.LoadWithHandler to retry the read of the keys.
+func (bundle *EtcdResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// Watch loads the keys at the Revision and calls onChange with all of them added, then watches the keys and
+// calls onChange after every change from the loaded revision on, until the context is done. A failure is
+// reported with its error and the watch is resumed after the RetryInterval. If the revision to resume from has
+// been compacted, the keys are loaded again at the latest revision and their difference is reported.
+func (bundle *EtcdResourceBundle) Watch(ctx context.Context, onChange func(change ResourceChange)) error {
+	retry := bundle.RetryInterval
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	var (
+		state    map[string]*EtcdResource
+		revision int64
+	)
+	for {
+		token, err := bundle.authenticate(ctx)
+		if err == nil && state == nil {
+			state, revision, err = bundle.load(ctx, token, bundle.Revision)
+			if err == nil {
+				onChange(diffEtcdStates(nil, state))
+			}
+		}
+		if err == nil {
+			var compacted bool
+			compacted, err = bundle.watch(ctx, token, state, revision, func(next map[string]*EtcdResource, at int64) {
+				if change := diffEtcdStates(state, next); len(change.Added)+len(change.Changed)+len(change.Removed) > 0 {
+					onChange(change)
+				}
+				state, revision = next, at
+			})
+			if err == nil && compacted {
+				var (
+					next   map[string]*EtcdResource
+					latest int64
+				)
+				next, latest, err = bundle.load(ctx, token, 0)
+				if err == nil {
+					if change := diffEtcdStates(state, next); len(change.Added)+len(change.Changed)+len(change.Removed) > 0 {
+						onChange(change)
+					}
+					state, revision = next, latest
+
+					continue
+				}
+			}
+		}
+		if ctx.Err() != nil {
+
+			return ctx.Err()
+		}
+		if err != nil {
+			contextLog(ctx).Warnf("Watch of etcd prefix %s failed, retrying. %v", bundle.Prefix, err)
+			onChange(ResourceChange{Err: err})
+		}
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// load reads the matching keys at the revision, the latest if zero, page by page, and returns them with the
+// revision they were read at.
+func (bundle *EtcdResourceBundle) load(ctx context.Context, token string, revision int64) (map[string]*EtcdResource, int64, error) {
+	state := make(map[string]*EtcdResource)
+	key := []byte(bundle.Prefix)
+	for {
+		request := map[string]interface{}{
+			"key":       key,
+			"range_end": etcdPrefixEnd(bundle.Prefix),
+			"limit":     500,
+		}
+		if revision > 0 {
+			request["revision"] = revision
+		}
+		page := &etcdRangeResponse{}
+		if err := bundle.post(ctx, token, "/v3/kv/range", request, page); err != nil {
+
+			return nil, 0, err
+		}
+		// the following pages are read at the revision of the first one, so the keys are all of the same revision.
+		revision = int64(page.Header.Revision)
+		for _, kv := range page.Kvs {
+			if err := bundle.apply(state, "PUT", kv); err != nil {
+
+				return nil, 0, err
+			}
+		}
+		if !page.More || len(page.Kvs) == 0 {
+
+			break
+		}
+		key = append(append([]byte{}, page.Kvs[len(page.Kvs)-1].Key...), 0)
+	}
+
+	return state, revision, nil
+}
+
+// watch streams the changes after the revision of the state, calling onChange with the keys after every batch
+// of events, until the stream ends. It returns true if the revision has been compacted.
+func (bundle *EtcdResourceBundle) watch(ctx context.Context, token string, state map[string]*EtcdResource, revision int64, onChange func(state map[string]*EtcdResource, revision int64)) (bool, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key":            []byte(bundle.Prefix),
+			"range_end":      etcdPrefixEnd(bundle.Prefix),
+			"start_revision": revision + 1,
+		},
+	})
+	if err != nil {
+
+		return false, err
+	}
+	resp, err := bundle.send(ctx, token, "/v3/watch", body)
+	if err != nil {
+
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		message := &etcdWatchResponse{}
+		if err := decoder.Decode(message); err != nil {
+			if err == io.EOF {
+
+				return false, fmt.Errorf("watch of etcd prefix %s ended", bundle.Prefix)
+			}
+
+			return false, err
+		}
+		if message.Error != nil {
+
+			return false, fmt.Errorf("watch of etcd prefix %s failed. %s", bundle.Prefix, message.Error.Message)
+		}
+		result := message.Result
+		if result == nil {
+
+			continue
+		}
+		if result.CompactRevision > 0 {
+			contextLog(ctx).Warnf("Revision %d of etcd prefix %s has been compacted, loading the latest keys", revision, bundle.Prefix)
+
+			return true, nil
+		}
+		if result.Canceled {
+
+			return false, fmt.Errorf("watch of etcd prefix %s canceled. %s", bundle.Prefix, result.CancelReason)
+		}
+		if len(result.Events) == 0 {
+
+			continue
+		}
+		next := make(map[string]*EtcdResource, len(state))
+		for key, resource := range state {
+			next[key] = resource
+		}
+		for _, event := range result.Events {
+			if err := bundle.apply(next, event.Type, event.Kv); err != nil {
+
+				return false, err
+			}
+		}
+		onChange(next, int64(result.Header.Revision))
+		state = next
+	}
+}
+
+// apply puts or deletes the key in the state, if it matches the PathPattern.
+func (bundle *EtcdResourceBundle) apply(state map[string]*EtcdResource, eventType string, kv etcdKV) error {
+	key := string(kv.Key)
+	if len(bundle.PathPattern) > 0 {
+		matched, err := matchObjectKeys([]string{key}, bundle.Prefix, bundle.PathPattern)
+		if err != nil || len(matched) == 0 {
+
+			return err
+		}
+	} else if strings.HasSuffix(key, "/") {
+
+		return nil
+	}
+	if eventType == "DELETE" {
+		delete(state, key)
+
+		return nil
+	}
+	state[key] = &EtcdResource{
+		Key:         key,
+		ModRevision: int64(kv.ModRevision),
+		Bytes:       kv.Value,
+	}
+
+	return nil
+}
+
+// authenticate returns the token of the Username, or nothing without a Username.
+func (bundle *EtcdResourceBundle) authenticate(ctx context.Context) (string, error) {
+	if len(bundle.Username) == 0 {
+
+		return "", nil
+	}
+	response := &struct {
+		Token string `json:"token"`
+	}{}
+	err := bundle.post(ctx, "", "/v3/auth/authenticate", map[string]string{"name": bundle.Username, "password": bundle.Password}, response)
+	if err != nil {
+
+		return "", err
+	}
+
+	return response.Token, nil
+}
+
+// post sends the request to the gateway and decodes its response.
+func (bundle *EtcdResourceBundle) post(ctx context.Context, token, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+
+		return err
+	}
+	resp, err := bundle.send(ctx, token, path, body)
+	if err != nil {
+
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+
+		return fmt.Errorf("invalid response of etcd to %s. got %w", path, err)
+	}
+
+	return nil
+}
+
+// send posts the body and returns the response, which the caller must close.
+func (bundle *EtcdResourceBundle) send(ctx context.Context, token, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(bundle.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(token) > 0 {
+		req.Header.Set("Authorization", token)
+	}
+	client := bundle.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		status := &struct {
+			Message string `json:"message"`
+		}{}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, status) != nil || len(status.Message) == 0 {
+			status.Message = strings.TrimSpace(string(data))
+		}
+
+		return nil, fmt.Errorf("etcd responded %s to %s. %s", resp.Status, path, status.Message)
+	}
+
+	return resp, nil
+}
+
+// etcdPrefixEnd returns the end of the range of the keys starting with the prefix.
+func etcdPrefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return end[:i+1]
+		}
+	}
+
+	return []byte{0}
+}
+
+// etcdResources returns the resources of the state, sorted by key.
+func etcdResources(state map[string]*EtcdResource) []Resource {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	ret := make([]Resource, 0, len(keys))
+	for _, key := range keys {
+		ret = append(ret, state[key])
+	}
+
+	return ret
+}
+
+// diffEtcdStates returns the change from the last keys to the next ones.
+func diffEtcdStates(last, next map[string]*EtcdResource) ResourceChange {
+	digests := func(state map[string]*EtcdResource) map[string][sha256.Size]byte {
+		ret := make(map[string][sha256.Size]byte, len(state))
+		for key, resource := range state {
+			ret[key] = sha256.Sum256(resource.Bytes)
+		}
+
+		return ret
+	}
+
+	return diffScans(digests(last), digests(next), etcdResources(next))
+}
+
+// EtcdResource resource implementation that loaded from a key of etcd
+type EtcdResource struct {
+	Key string
+	// ModRevision is the revision of the last change of the key.
+	ModRevision int64
+	Bytes       []byte
+}
+
+// String will state the resource key and the revision of its last change.
+func (res *EtcdResource) String() string {
+
+	return fmt.Sprintf("From etcd key %s at revision %d", res.Key, res.ModRevision)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the key from etcd when
+// this method is called, it simply return the loaded data.
+func (res *EtcdResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
@@ -0,0 +1,263 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type etcdEvent struct {
+	revision int64
+	deleted  bool
+	key      string
+	value    string
+}
+
+// etcdServer serves the range and watch requests of the etcd JSON gateway from a history of events.
+type etcdServer struct {
+	mutex   sync.Mutex
+	history []etcdEvent
+	changed chan struct{}
+}
+
+func (server *etcdServer) put(key, value string, deleted bool) {
+	server.mutex.Lock()
+	server.history = append(server.history, etcdEvent{revision: int64(len(server.history) + 1), deleted: deleted, key: key, value: value})
+	server.mutex.Unlock()
+	select {
+	case server.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (server *etcdServer) kv(event etcdEvent) map[string]interface{} {
+
+	return map[string]interface{}{"key": []byte(event.key), "value": []byte(event.value), "mod_revision": strconv.FormatInt(event.revision, 10)}
+}
+
+func (server *etcdServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v3/auth/authenticate" {
+		request := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request["password"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"etcdserver: authentication failed","code":3,"message":"etcdserver: authentication failed"}`)
+
+			return
+		}
+		fmt.Fprint(w, `{"header":{"revision":"1"},"token":"session-token"}`)
+
+		return
+	}
+	if r.Header.Get("Authorization") != "session-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"etcdserver: user name is empty"}`)
+
+		return
+	}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		request := struct {
+			Key      []byte `json:"key"`
+			RangeEnd []byte `json:"range_end"`
+			Revision int64  `json:"revision"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		revision := int64(len(server.history))
+		if request.Revision > 0 {
+			revision = request.Revision
+		}
+		if revision > int64(len(server.history)) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"etcdserver: mvcc: required revision is a future revision"}`)
+
+			return
+		}
+		state := map[string]etcdEvent{}
+		for _, event := range server.history[:revision] {
+			if event.deleted {
+				delete(state, event.key)
+			} else {
+				state[event.key] = event
+			}
+		}
+		keys := make([]string, 0)
+		for key := range state {
+			if key >= string(request.Key) && key < string(request.RangeEnd) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		// two keys a page, to go through the pages.
+		kvs := make([]map[string]interface{}, 0)
+		for i := 0; i < len(keys) && i < 2; i++ {
+			kvs = append(kvs, server.kv(state[keys[i]]))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"header": map[string]string{"revision": strconv.FormatInt(revision, 10)},
+			"kvs":    kvs,
+			"more":   len(keys) > 2,
+		})
+	case "/v3/watch":
+		request := struct {
+			CreateRequest struct {
+				StartRevision int64 `json:"start_revision"`
+			} `json:"create_request"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		fmt.Fprint(w, `{"result":{"header":{"revision":"1"},"created":true}}`+"\n")
+		w.(http.Flusher).Flush()
+		next := request.CreateRequest.StartRevision
+		for {
+			server.mutex.Lock()
+			for ; next <= int64(len(server.history)); next++ {
+				event := server.history[next-1]
+				eventType := "PUT"
+				if event.deleted {
+					eventType = "DELETE"
+				}
+				message, _ := json.Marshal(map[string]interface{}{"result": map[string]interface{}{
+					"header": map[string]string{"revision": strconv.FormatInt(next, 10)},
+					"events": []map[string]interface{}{{"type": eventType, "kv": server.kv(event)}},
+				}})
+				fmt.Fprintln(w, string(message))
+			}
+			server.mutex.Unlock()
+			w.(http.Flusher).Flush()
+			select {
+			case <-server.changed:
+			case <-time.After(50 * time.Millisecond):
+			case <-r.Context().Done():
+
+				return
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newEtcdServer() *etcdServer {
+	server := &etcdServer{changed: make(chan struct{})}
+	server.put("/grule/rules/a.grl", "rule A", false)
+	server.put("/grule/rules/b.grl", "rule B", false)
+	server.put("/grule/other/c.grl", "rule C", false)
+	server.put("/grule/rules/readme.md", "not a rule", false)
+	server.put("/grule/rules/pricing/d.grl", "rule D", false)
+	server.put("/grule/rules/b.grl", "rule B2", false)
+
+	return server
+}
+
+func TestEtcdResourceBundle(t *testing.T) {
+	server := httptest.NewServer(newEtcdServer())
+	defer server.Close()
+
+	bundle := NewEtcdResourceBundle(server.URL, "/grule/rules/", "**/*.grl")
+	bundle.Username = "grule"
+	bundle.Password = "secret"
+	resources, revision, err := bundle.LoadRevision(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), revision)
+	if assert.Len(t, resources, 3) {
+		assert.Equal(t, "From etcd key /grule/rules/a.grl at revision 1", resources[0].String())
+		data, err := resources[1].Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "rule B2", string(data))
+	}
+
+	bundle.Revision = 4
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	if assert.Len(t, resources, 2) {
+		data, err := resources[1].Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "rule B", string(data))
+	}
+
+	bundle.PathPattern = nil
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	bundle.Revision = 100
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "future revision")
+
+	bundle.Password = "wrong"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "authentication failed")
+}
+
+func TestEtcdResourceBundle_Watch(t *testing.T) {
+	api := newEtcdServer()
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	bundle := NewEtcdResourceBundle(server.URL, "/grule/rules/", "**/*.grl")
+	bundle.Username = "grule"
+	bundle.Password = "secret"
+	bundle.Revision = 4
+	changes := make(chan ResourceChange)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bundle.Watch(ctx, func(change ResourceChange) {
+			assert.NoError(t, change.Err)
+			changes <- change
+		})
+	}()
+
+	next := func() ResourceChange {
+		select {
+		case change := <-changes:
+
+			return change
+		case <-time.After(time.Second):
+			t.Fatal("expected a change")
+		}
+
+		return ResourceChange{}
+	}
+	change := next()
+	assert.Equal(t, []string{"/grule/rules/a.grl", "/grule/rules/b.grl"}, change.Added)
+	// the changes after the pinned revision.
+	change = next()
+	assert.Equal(t, []string{"/grule/rules/pricing/d.grl"}, change.Added)
+	change = next()
+	assert.Equal(t, []string{"/grule/rules/b.grl"}, change.Changed)
+
+	api.put("/grule/rules/notes.md", "not a rule", false)
+	api.put("/grule/rules/a.grl", "", true)
+	change = next()
+	assert.Equal(t, []string{"/grule/rules/a.grl"}, change.Removed)
+	assert.Len(t, change.Resources, 2)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
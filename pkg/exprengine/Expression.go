@@ -0,0 +1,159 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package exprengine evaluates a single GRL expression against a DataContext, without defining rules.
+package exprengine
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/antlr4-go/antlr/v4"
+	antlr2 "github.com/hyperjumptech/grule-rule-engine/antlr"
+	parser "github.com/hyperjumptech/grule-rule-engine/antlr/parser/grulev3"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// Compile parses the GRL expression, e.g. `Order.Total * 1.1 > Customer.Limit`. The expression may use the
+// built-in functions and the functions of the facts, anything else than a single expression is an error.
+func Compile(expression string) (*Expression, error) {
+	errReporter := &pkg.GruleErrorReporter{
+		Errors: make([]error, 0),
+	}
+	lexer := parser.Newgrulev3Lexer(antlr.NewInputStream(expression))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errReporter)
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	psr := parser.Newgrulev3Parser(stream)
+	psr.RemoveErrorListeners()
+	psr.AddErrorListener(errReporter)
+	psr.BuildParseTrees = true
+	tree := psr.Expression()
+	if errReporter.HasError() {
+
+		return nil, errReporter
+	}
+	if stream.LA(1) != antlr.TokenEOF {
+
+		return nil, fmt.Errorf("expression %q has unexpected '%s' after the expression", expression, stream.LT(1).GetText())
+	}
+
+	knowledgeBase := ast.NewKnowledgeLibrary().GetKnowledgeBase("Expression", "0.0.0")
+	listener := antlr2.NewGruleV3ParserListener(knowledgeBase, errReporter)
+	// the expression is accepted by a when scope, just like the condition of a rule.
+	scope := ast.NewWhenScope()
+	listener.Stack.Push(scope)
+	antlr.ParseTreeWalkerDefault.Walk(listener, tree)
+	if errReporter.HasError() {
+
+		return nil, errReporter
+	}
+	if scope.Expression == nil {
+
+		return nil, fmt.Errorf("expression %q is not a valid GRL expression", expression)
+	}
+	knowledgeBase.WorkingMemory.IndexVariables()
+
+	return &Expression{
+		text:          expression,
+		knowledgeBase: knowledgeBase,
+		expression:    scope.Expression,
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(expression string) *Expression {
+	expr, err := Compile(expression)
+	if err != nil {
+		panic(fmt.Sprintf("exprengine: can not compile %q. got %v", expression, err))
+	}
+
+	return expr
+}
+
+// Expression is a compiled GRL expression. It is safe to be evaluated concurrently, the evaluations are serialized.
+type Expression struct {
+	mutex         sync.Mutex
+	text          string
+	knowledgeBase *ast.KnowledgeBase
+	expression    *ast.Expression
+}
+
+// String returns the expression text.
+func (expr *Expression) String() string {
+
+	return expr.text
+}
+
+// Eval evaluates the expression against the facts of the data context, the result is nil if the expression
+// evaluates to nil.
+func (expr *Expression) Eval(dataCtx ast.IDataContext) (interface{}, error) {
+	val, err := expr.evaluate(dataCtx)
+	if err != nil {
+
+		return nil, err
+	}
+	if !val.IsValid() {
+
+		return nil, nil
+	}
+
+	return pkg.ValueToInterface(val), nil
+}
+
+// EvalBool evaluates the expression, it is an error if the expression is not a boolean.
+func (expr *Expression) EvalBool(dataCtx ast.IDataContext) (bool, error) {
+	val, err := expr.evaluate(dataCtx)
+	if err != nil {
+
+		return false, err
+	}
+	if !val.IsValid() || val.Kind() != reflect.Bool {
+
+		return false, fmt.Errorf("expression %q is not a boolean", expr.text)
+	}
+
+	return val.Bool(), nil
+}
+
+func (expr *Expression) evaluate(dataCtx ast.IDataContext) (reflect.Value, error) {
+	if dataCtx == nil {
+
+		return reflect.Value{}, fmt.Errorf("nil DataContext is not allowed")
+	}
+	expr.mutex.Lock()
+	defer expr.mutex.Unlock()
+
+	defunc := &ast.BuiltInFunctions{
+		Knowledge:     expr.knowledgeBase,
+		WorkingMemory: expr.knowledgeBase.WorkingMemory,
+		DataContext:   dataCtx,
+	}
+	err := dataCtx.Add("DEFUNC", defunc)
+	if err != nil {
+
+		return reflect.Value{}, err
+	}
+	// nothing is memoized between evaluations, the facts may have changed.
+	expr.knowledgeBase.WorkingMemory.ResetAll()
+	val, err := expr.expression.Evaluate(dataCtx, expr.knowledgeBase.WorkingMemory)
+	if err != nil {
+
+		return reflect.Value{}, fmt.Errorf("error while evaluating expression %q. got %w", expr.text, err)
+	}
+
+	return val, nil
+}
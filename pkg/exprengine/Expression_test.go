@@ -0,0 +1,78 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package exprengine
+
+import (
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Order struct {
+	Total    float64
+	Items    []string
+	Customer string
+}
+
+func (o *Order) Discount(rate float64) float64 {
+	return o.Total * rate
+}
+
+func TestExpression(t *testing.T) {
+	order := &Order{Total: 150, Items: []string{"book", "pen"}, Customer: "acme"}
+	dctx := ast.NewDataContext()
+	assert.NoError(t, dctx.Add("Order", order))
+
+	filter, err := Compile(`Order.Total > 100 && Order.Items.Len() == 2 && Order.Customer.HasPrefix("ac")`)
+	assert.NoError(t, err)
+	matched, err := filter.EvalBool(dctx)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	// the facts are evaluated again on every evaluation.
+	order.Total = 50
+	matched, err = filter.EvalBool(dctx)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	computed := MustCompile(`Order.Total - Order.Discount(0.1)`)
+	val, err := computed.Eval(dctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 45.0, val)
+	_, err = computed.EvalBool(dctx)
+	assert.Error(t, err)
+
+	val, err = MustCompile(`Order.Customer + "-" + Order.Items[0]`).Eval(dctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme-book", val)
+
+	_, err = MustCompile(`Missing.Total > 1`).Eval(dctx)
+	assert.ErrorAs(t, err, new(*ast.MissingFactError))
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	_, err := Compile(`Order.Total >`)
+	assert.ErrorAs(t, err, new(*pkg.GruleErrorReporter))
+
+	// a rule smuggled after the expression is rejected.
+	_, err = Compile(`true then Order.Total = 0;`)
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		MustCompile(`(`)
+	})
+}
@@ -0,0 +1,469 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// gcsReadOnlyScope is the OAuth2 scope requested for the access tokens.
+	gcsReadOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+	// gcsDefaultTokenURI exchanges the refresh token of the user credentials.
+	gcsDefaultTokenURI = "https://oauth2.googleapis.com/token"
+)
+
+// NewGCSResourceBundle will create a new instance of GCSResourceBundle.
+// bucket is the name of the bucket, prefix only lists the objects whose name starts with it.
+// pathPattern are list of name patterns (glob), relative to the prefix, to filter the objects.
+// The requests are authorized with the application default credentials, unless the bundle is given credentials.
+func NewGCSResourceBundle(bucket, prefix string, pathPattern ...string) *GCSResourceBundle {
+
+	return &GCSResourceBundle{
+		Bucket:      bucket,
+		Prefix:      prefix,
+		PathPattern: pathPattern,
+	}
+}
+
+// GCSResourceBundle is a helper struct to load multiple objects from a Google Cloud Storage bucket all at once by
+// specifying the prefix of their names and the name pattern to look for.
+//
+// Unless AccessToken or CredentialsJSON is set, it looks for the application default credentials: the file named by
+// GOOGLE_APPLICATION_CREDENTIALS, the gcloud application default credentials of the user, and at last the metadata
+// server of Cloud Run, GKE or Compute Engine.
+type GCSResourceBundle struct {
+	// The bucket name
+	Bucket string
+	// Only the objects whose name starts with the prefix are listed, such as "rules/".
+	Prefix string
+	// List Glob like name pattern, relative to the prefix.
+	// *.grl           <- matches rules/abc.grl but not rules/anyfolder/abc.grl with the prefix "rules/"
+	// **/*.grl        <- matches rules/abc.grl or rules/abc/def.grl
+	// /rules/**/*.grl <- an absolute pattern is matched against the whole name
+	PathPattern []string
+	// AccessToken, if set, returns the OAuth2 access token of every load, such as one from golang.org/x/oauth2.
+	AccessToken func(ctx context.Context) (string, error)
+	// CredentialsJSON, if set, is the content of a service account key or an authorized user credentials file.
+	CredentialsJSON []byte
+	// Endpoint of the storage API, such as an emulator at http://localhost:4443. If empty, STORAGE_EMULATOR_HOST
+	// or else https://storage.googleapis.com is used. The requests to an emulator are not authorized.
+	Endpoint string
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	// Concurrency is the number of objects downloaded at the same time, 8 if not positive.
+	Concurrency int
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// gcsCredentialsFile is a service account key or an authorized user credentials file.
+type gcsCredentialsFile struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// gcsToken is the response of the token endpoints and of the metadata server.
+type gcsToken struct {
+	AccessToken string `json:"access_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// gcsObjects is a page of the objects list.
+type gcsObjects struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+}
+
+// Load lists the objects under the Prefix and downloads all of those that conform to the PathPattern.
+// The resources are returned in the order of their names.
+func (bundle *GCSResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *GCSResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the objects failing to download are reported to onError.
+func (bundle *GCSResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.load(ctx, onError)
+}
+
+// load is the same as LoadContext, the objects failing to download are reported to onError, if not nil.
+func (bundle *GCSResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	endpoint, emulated := bundle.endpoint()
+	token := ""
+	if !emulated {
+		var err error
+		token, err = bundle.token(ctx)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while authorizing to GCS bucket %s. got %w", bundle.Bucket, err)
+		}
+	}
+	names, err := bundle.list(ctx, endpoint, token)
+	if err != nil {
+
+		return nil, err
+	}
+	matched, err := matchObjectKeys(names, bundle.Prefix, bundle.PathPattern)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return loadObjects(ctx, matched, bundle.Concurrency, onError, func(name string) (Resource, error) {
+		contextLog(ctx).Debugf("Loading GCS object %s/%s", bundle.Bucket, name)
+		target := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", endpoint, url.PathEscape(bundle.Bucket), url.PathEscape(name))
+		res := &GCSResource{
+			Bucket: bundle.Bucket,
+			Name:   name,
+		}
+		var err error
+		res.Bytes, err = bundle.get(ctx, target, token)
+
+		return res, err
+	})
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use GCSResourceBundle.LoadContext, or GCSResourceBundle.LoadWithHandler to go on without the objects
+// failing to download.
+func (bundle *GCSResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// endpoint returns the storage API endpoint and if it is an emulator.
+func (bundle *GCSResourceBundle) endpoint() (string, bool) {
+	if len(bundle.Endpoint) > 0 {
+
+		return strings.TrimSuffix(bundle.Endpoint, "/"), false
+	}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); len(host) > 0 {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+
+		return strings.TrimSuffix(host, "/"), true
+	}
+
+	return "https://storage.googleapis.com", false
+}
+
+func (bundle *GCSResourceBundle) client() *http.Client {
+	if bundle.HTTPClient != nil {
+
+		return bundle.HTTPClient
+	}
+
+	return &http.Client{}
+}
+
+// list returns the names of all objects under the prefix, following the page tokens.
+func (bundle *GCSResourceBundle) list(ctx context.Context, endpoint, token string) ([]string, error) {
+	names := make([]string, 0)
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("fields", "items/name,nextPageToken")
+		if len(bundle.Prefix) > 0 {
+			query.Set("prefix", bundle.Prefix)
+		}
+		if len(pageToken) > 0 {
+			query.Set("pageToken", pageToken)
+		}
+		body, err := bundle.get(ctx, fmt.Sprintf("%s/storage/v1/b/%s/o?%s", endpoint, url.PathEscape(bundle.Bucket), query.Encode()), token)
+		if err != nil {
+
+			return nil, err
+		}
+		page := &gcsObjects{}
+		if err := json.Unmarshal(body, page); err != nil {
+
+			return nil, fmt.Errorf("error while listing GCS bucket %s. got %w", bundle.Bucket, err)
+		}
+		for _, item := range page.Items {
+			names = append(names, item.Name)
+		}
+		if len(page.NextPageToken) == 0 {
+
+			return names, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// get sends an authorized GET request and returns the response body.
+func (bundle *GCSResourceBundle) get(ctx context.Context, target, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := bundle.client().Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		if json.Unmarshal(body, apiErr) != nil || len(apiErr.Error.Message) == 0 {
+			apiErr.Error.Message = strings.TrimSpace(string(body))
+		}
+
+		return nil, fmt.Errorf("GCS bucket %s responded %s to %s. %s", bundle.Bucket, resp.Status, req.URL.Path, apiErr.Error.Message)
+	}
+
+	return body, nil
+}
+
+// token returns the access token of the explicit credentials, or else of the application default credentials.
+func (bundle *GCSResourceBundle) token(ctx context.Context) (string, error) {
+	if bundle.AccessToken != nil {
+
+		return bundle.AccessToken(ctx)
+	}
+	if len(bundle.CredentialsJSON) > 0 {
+
+		return bundle.tokenFromJSON(ctx, bundle.CredentialsJSON)
+	}
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); len(path) > 0 {
+		data, err := os.ReadFile(path)
+		if err != nil {
+
+			return "", err
+		}
+
+		return bundle.tokenFromJSON(ctx, data)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		data, err := os.ReadFile(filepath.Join(home, ".config", "gcloud", "application_default_credentials.json"))
+		if err == nil {
+
+			return bundle.tokenFromJSON(ctx, data)
+		}
+	}
+
+	return bundle.tokenFromMetadata(ctx)
+}
+
+// tokenFromJSON exchanges the credentials of a service account key or an authorized user for an access token.
+func (bundle *GCSResourceBundle) tokenFromJSON(ctx context.Context, data []byte) (string, error) {
+	creds := &gcsCredentialsFile{}
+	if err := json.Unmarshal(data, creds); err != nil {
+
+		return "", fmt.Errorf("invalid credentials. got %w", err)
+	}
+	form := url.Values{}
+	tokenURI := creds.TokenURI
+	switch creds.Type {
+	case "service_account":
+		assertion, err := gcsAssertion(creds, time.Now())
+		if err != nil {
+
+			return "", err
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	case "authorized_user":
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", creds.ClientID)
+		form.Set("client_secret", creds.ClientSecret)
+		form.Set("refresh_token", creds.RefreshToken)
+	default:
+
+		return "", fmt.Errorf("credentials of type %q are not supported", creds.Type)
+	}
+	if len(tokenURI) == 0 {
+		tokenURI = gcsDefaultTokenURI
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return bundle.fetchToken(req)
+}
+
+// tokenFromMetadata gets the access token of the default service account from the metadata server.
+func (bundle *GCSResourceBundle) tokenFromMetadata(ctx context.Context) (string, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if len(host) == 0 {
+		host = "metadata.google.internal"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token?scopes="+url.QueryEscape(gcsReadOnlyScope), nil)
+	if err != nil {
+
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	token, err := bundle.fetchToken(req)
+	if err != nil {
+
+		return "", fmt.Errorf("no application default credentials found. got %w", err)
+	}
+
+	return token, nil
+}
+
+// fetchToken sends the token request and returns the access token of the response.
+func (bundle *GCSResourceBundle) fetchToken(req *http.Request) (string, error) {
+	resp, err := bundle.client().Do(req)
+	if err != nil {
+
+		return "", err
+	}
+	defer resp.Body.Close()
+	token := &gcsToken{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+
+		return "", fmt.Errorf("invalid token response %s from %s. got %w", resp.Status, req.URL.Host, err)
+	}
+	if resp.StatusCode != http.StatusOK || len(token.AccessToken) == 0 {
+
+		return "", fmt.Errorf("token request to %s responded %s. %s %s", req.URL.Host, resp.Status, token.Error, token.Description)
+	}
+
+	return token.AccessToken, nil
+}
+
+// gcsAssertion returns the JWT signed with the service account key to exchange for an access token.
+func gcsAssertion(creds *gcsCredentialsFile, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+
+		return "", errors.New("the service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+
+		return "", fmt.Errorf("invalid service account private key. got %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+
+		return "", errors.New("the service account private key is not an RSA key")
+	}
+	tokenURI := creds.TokenURI
+	if len(tokenURI) == 0 {
+		tokenURI = gcsDefaultTokenURI
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": creds.PrivateKeyID})
+	if err != nil {
+
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   creds.ClientEmail,
+		"scope": gcsReadOnlyScope,
+		"aud":   tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// GCSResource resource implementation that loaded from a Google Cloud Storage bucket
+type GCSResource struct {
+	Bucket string
+	Name   string
+	Bytes  []byte
+}
+
+// String will state the resource bucket and object name.
+func (res *GCSResource) String() string {
+
+	return fmt.Sprintf("From GCS bucket [%s] %s", res.Bucket, res.Name)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the object from GCS when this
+// method is called, it simply return the loaded data.
+func (res *GCSResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
@@ -0,0 +1,142 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newGCSServer(t *testing.T, key *rsa.PublicKey) *httptest.Server {
+	objects := map[string]string{
+		"rules/a.grl":          "rule A",
+		"rules/b.txt":          "not a rule",
+		"rules/nested/c.grl":   "rule C",
+		"rules/with space.grl": "rule D",
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			assertion := strings.Split(r.FormValue("assertion"), ".")
+			signature, _ := base64.RawURLEncoding.DecodeString(assertion[len(assertion)-1])
+			digest := sha256.Sum256([]byte(assertion[0] + "." + assertion[1]))
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Invalid JWT Signature."}`)
+
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"service-token","token_type":"Bearer"}`)
+		case r.URL.Path == "/computeMetadata/v1/instance/service-accounts/default/token" && r.Header.Get("Metadata-Flavor") == "Google":
+			fmt.Fprint(w, `{"access_token":"metadata-token","token_type":"Bearer"}`)
+		case !strings.HasSuffix(r.Header.Get("Authorization"), "-token"):
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":401,"message":"Anonymous caller does not have storage.objects.list access."}}`)
+		case r.URL.Path == "/storage/v1/b/grl/o":
+			if r.URL.Query().Get("prefix") != "rules/" {
+				t.Errorf("Expected prefix rules/ but get %s", r.URL.Query().Get("prefix"))
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				fmt.Fprint(w, `{"nextPageToken":"page2","items":[{"name":"rules/a.grl"},{"name":"rules/b.txt"}]}`)
+			} else {
+				fmt.Fprint(w, `{"items":[{"name":"rules/nested/c.grl"},{"name":"rules/with space.grl"}]}`)
+			}
+		case strings.HasPrefix(r.URL.Path, "/storage/v1/b/grl/o/") && r.URL.Query().Get("alt") == "media":
+			content, ok := objects[strings.TrimPrefix(r.URL.Path, "/storage/v1/b/grl/o/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error":{"code":404,"message":"No such object"}}`)
+
+				return
+			}
+			fmt.Fprint(w, content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func expectGCSResources(t *testing.T, resources []Resource, err error, expected ...string) {
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != len(expected) {
+		t.Fatalf("Expected %d resources but get %d", len(expected), len(resources))
+	}
+	for i, res := range resources {
+		bytes, _ := res.Load()
+		if string(bytes) != expected[i] {
+			t.Errorf("Expected [%d] to be %s but get %s", i, expected[i], string(bytes))
+		}
+	}
+}
+
+func TestGCSResourceBundle_Load(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newGCSServer(t, &key.PublicKey)
+	defer server.Close()
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("STORAGE_EMULATOR_HOST", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "rules@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	bundle := NewGCSResourceBundle("grl", "rules/", "**/*.grl")
+	bundle.Endpoint = server.URL
+	bundle.CredentialsJSON = credentials
+	resources, err := bundle.Load()
+	expectGCSResources(t, resources, err, "rule A", "rule C", "rule D")
+	if resources[2].String() != "From GCS bucket [grl] rules/with space.grl" {
+		t.Errorf("Unexpected resource name %s", resources[2].String())
+	}
+
+	bundle.CredentialsJSON = nil
+	bundle.PathPattern = []string{"*.grl"}
+	resources, err = bundle.Load()
+	expectGCSResources(t, resources, err, "rule A", "rule D")
+
+	bundle.AccessToken = func(ctx context.Context) (string, error) {
+		return "injected", nil
+	}
+	_, err = bundle.Load()
+	if err == nil || !strings.Contains(err.Error(), "Anonymous caller") {
+		t.Errorf("Expected unauthorized but get %v", err)
+	}
+}
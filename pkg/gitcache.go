@@ -0,0 +1,144 @@
+//go:build go1.11
+// +build go1.11
+
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// gitCacheLocks serializes the loads sharing a clone in a CacheDir, by the directory of the clone.
+var gitCacheLocks sync.Map
+
+// gitCacheDir is the directory of the clone of the repository at url in the cacheDir.
+func gitCacheDir(cacheDir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))
+}
+
+// cachedCheckout opens the clone of the repository in the CacheDir, cloning it with the options the first time and
+// fetching it the next times, then checks out the commit to load and returns the files of the work tree.
+func (bundle *GITResourceBundle) cachedCheckout(ctx context.Context, opts *git.CloneOptions) (billy.Filesystem, gitRevision, error) {
+	dir := gitCacheDir(bundle.CacheDir, bundle.URL)
+	lock, _ := gitCacheLocks.LoadOrStore(dir, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	repository, err := git.PlainOpen(dir)
+	switch {
+	case errors.Is(err, git.ErrRepositoryNotExists):
+		contextLog(ctx).Debugf("Cloning git repository %s into %s", bundle.URL, dir)
+		opts.NoCheckout = true
+		repository, err = git.PlainCloneContext(ctx, dir, false, opts)
+		if err != nil {
+			// a partial clone would be taken for the cache on the next load.
+			_ = os.RemoveAll(dir)
+
+			return nil, gitRevision{}, err
+		}
+	case err != nil:
+
+		return nil, gitRevision{}, fmt.Errorf("error while opening the cached clone %s of %s. got %w", dir, bundle.URL, err)
+	default:
+		contextLog(ctx).Debugf("Fetching git repository %s into %s", bundle.URL, dir)
+		err = repository.FetchContext(ctx, bundle.cachedFetchOptions(opts))
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+
+			return nil, gitRevision{}, err
+		}
+	}
+
+	hash, err := bundle.cachedRevision(repository, opts)
+	if err != nil {
+
+		return nil, gitRevision{}, err
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+
+		return nil, gitRevision{}, err
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true})
+	if err != nil {
+
+		return nil, gitRevision{}, fmt.Errorf("error while checking out commit %s of %s. got %w", hash, bundle.URL, err)
+	}
+
+	revision, err := headRevision(repository)
+	if err != nil {
+
+		return nil, gitRevision{}, err
+	}
+
+	return worktree.Filesystem, revision, nil
+}
+
+// cachedFetchOptions fetches the ref the clone was made for. A ref name that is neither a branch nor a tag,
+// or no ref name at all, fetches the refs of the remote configuration.
+func (bundle *GITResourceBundle) cachedFetchOptions(opts *git.CloneOptions) *git.FetchOptions {
+	fetch := &git.FetchOptions{
+		RemoteName:   opts.RemoteName,
+		Auth:         opts.Auth,
+		ProxyOptions: opts.ProxyOptions,
+		Depth:        opts.Depth,
+		Tags:         git.AllTags,
+		Force:        true,
+	}
+	if opts.Tags == git.NoTags {
+		fetch.Tags = git.NoTags
+	}
+	switch {
+	case opts.ReferenceName.IsBranch():
+		fetch.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:refs/remotes/%s/%s", opts.ReferenceName, opts.RemoteName, opts.ReferenceName.Short()))}
+	case opts.ReferenceName.IsTag():
+		fetch.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", opts.ReferenceName, opts.ReferenceName))}
+	}
+
+	return fetch
+}
+
+// cachedRevision is the commit to check out of the cached clone: the CommitHash, or else the fetched ref.
+func (bundle *GITResourceBundle) cachedRevision(repository *git.Repository, opts *git.CloneOptions) (plumbing.Hash, error) {
+	if len(bundle.CommitHash) != 0 {
+
+		return plumbing.NewHash(bundle.CommitHash), nil
+	}
+	revision := plumbing.Revision(opts.ReferenceName)
+	if opts.ReferenceName.IsBranch() {
+		revision = plumbing.Revision(fmt.Sprintf("refs/remotes/%s/%s", opts.RemoteName, opts.ReferenceName.Short()))
+	}
+	hash, err := repository.ResolveRevision(revision)
+	if err != nil {
+
+		return plumbing.ZeroHash, fmt.Errorf("error while resolving %s of %s. got %w", opts.ReferenceName, bundle.URL, err)
+	}
+
+	return *hash, nil
+}
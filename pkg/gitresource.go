@@ -4,18 +4,128 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"sync"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
 	http2 "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage/memory"
 )
 
+// gitHTTPClients clones the repositories of the bundles having a HTTPClient with that client. go-git only uses
+// the transports installed for the whole process, so it is installed once for http and https, and any other
+// repository is cloned with the transport installed before. This is the only state the bundles share: the client
+// stays an option of every bundle, and the bundles of the same repository having a client are cloned one after the
+// other so each clone uses the client of its own bundle.
+var gitHTTPClients = &gitHTTPTransports{clients: make(map[string]*http.Client), busy: make(map[string]chan struct{})}
+
+// gitHTTPTransports is a go-git transport choosing the http client by the repository endpoint.
+type gitHTTPTransports struct {
+	mutex     sync.Mutex
+	installed bool
+	previous  map[string]transport.Transport
+	clients   map[string]*http.Client
+	// busy is closed once the endpoint it is kept for is no longer cloned with a client.
+	busy map[string]chan struct{}
+}
+
+// register makes the repository at url cloned with the client, until the returned function is called. It waits for
+// the clone of the same repository with another client to finish, or for the context to be done.
+func (t *gitHTTPTransports) register(ctx context.Context, url string, httpClient *http.Client) (func(), error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+
+		return nil, err
+	}
+	key := endpoint.String()
+	for {
+		t.mutex.Lock()
+		busy, ok := t.busy[key]
+		if !ok {
+			break
+		}
+		t.mutex.Unlock()
+		select {
+		case <-busy:
+		case <-ctx.Done():
+
+			return nil, ctx.Err()
+		}
+	}
+	defer t.mutex.Unlock()
+	if !t.installed {
+		t.previous = map[string]transport.Transport{"http": client.Protocols["http"], "https": client.Protocols["https"]}
+		client.InstallProtocol("http", t)
+		client.InstallProtocol("https", t)
+		t.installed = true
+	}
+	done := make(chan struct{})
+	t.busy[key] = done
+	t.clients[key] = httpClient
+
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		delete(t.clients, key)
+		delete(t.busy, key)
+		close(done)
+	}, nil
+}
+
+// transport returns the transport of the endpoint.
+func (t *gitHTTPTransports) transport(endpoint *transport.Endpoint) transport.Transport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if httpClient, ok := t.clients[endpoint.String()]; ok {
+
+		return http2.NewClient(httpClient)
+	}
+	if previous := t.previous[endpoint.Protocol]; previous != nil {
+
+		return previous
+	}
+
+	return http2.DefaultClient
+}
+
+// NewUploadPackSession implements transport.Transport
+func (t *gitHTTPTransports) NewUploadPackSession(endpoint *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+
+	return t.transport(endpoint).NewUploadPackSession(endpoint, auth)
+}
+
+// NewReceivePackSession implements transport.Transport
+func (t *gitHTTPTransports) NewReceivePackSession(endpoint *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+
+	return t.transport(endpoint).NewReceivePackSession(endpoint, auth)
+}
+
 // Load will load the file from your git repository
 func (bundle *GITResourceBundle) Load() ([]Resource, error) {
-	fileSystem := memfs.New()
+
+	return bundle.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the clone is cancelled once the context is done.
+func (bundle *GITResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the files of the repository failing to be read are reported to onError.
+// An error cloning the repository aborts the loading.
+func (bundle *GITResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return bundle.load(context.Background(), onError)
+}
+
+func (bundle *GITResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
 	CloneOpts := &git.CloneOptions{}
 	if len(bundle.URL) == 0 {
 
@@ -23,11 +133,29 @@ func (bundle *GITResourceBundle) Load() ([]Resource, error) {
 	}
 	CloneOpts.URL = bundle.URL
 
-	if len(bundle.RefName) == 0 {
-		CloneOpts.ReferenceName = plumbing.ReferenceName("refs/heads/master")
-	} else {
+	if len(bundle.RefName) != 0 && len(bundle.Tag) != 0 {
+
+		return nil, fmt.Errorf("GIT ref name and tag are both specified")
+	}
+	if len(bundle.CommitHash) != 0 && !plumbing.IsHash(bundle.CommitHash) {
+
+		return nil, fmt.Errorf("GIT commit hash %s is not a full hash", bundle.CommitHash)
+	}
+	switch {
+	case len(bundle.Tag) != 0:
+		CloneOpts.ReferenceName = plumbing.NewTagReferenceName(bundle.Tag)
+	case len(bundle.RefName) != 0:
 		CloneOpts.ReferenceName = plumbing.ReferenceName(bundle.RefName)
+	case len(bundle.CommitHash) == 0:
+		CloneOpts.ReferenceName = plumbing.ReferenceName("refs/heads/master")
+	}
+	if bundle.Depth > 0 {
+		// like git clone --depth, only the checked out ref is fetched, not the history of the other ones.
+		CloneOpts.Depth = bundle.Depth
+		CloneOpts.SingleBranch = len(CloneOpts.ReferenceName) != 0
+		CloneOpts.Tags = git.NoTags
 	}
+	CloneOpts.NoCheckout = len(bundle.CommitHash) != 0
 
 	if len(bundle.Remote) == 0 {
 		CloneOpts.RemoteName = "origin"
@@ -39,18 +167,90 @@ func (bundle *GITResourceBundle) Load() ([]Resource, error) {
 		return nil, fmt.Errorf("no path pattern specified")
 	}
 
-	if len(bundle.User) != 0 {
+	switch {
+	case len(bundle.Token) != 0 && len(bundle.User) != 0:
+		CloneOpts.Auth = &http2.BasicAuth{
+			Username: bundle.User,
+			Password: bundle.Token,
+		}
+	case len(bundle.Token) != 0:
+		CloneOpts.Auth = &http2.TokenAuth{
+			Token: bundle.Token,
+		}
+	case len(bundle.User) != 0:
 		CloneOpts.Auth = &http2.BasicAuth{
 			Username: bundle.User,
 			Password: bundle.Password,
 		}
 	}
 
-	_, err := git.Clone(memory.NewStorage(), fileSystem, CloneOpts)
+	if len(bundle.ProxyURL) != 0 {
+		CloneOpts.ProxyOptions = transport.ProxyOptions{
+			URL:      bundle.ProxyURL,
+			Username: bundle.ProxyUser,
+			Password: bundle.ProxyPassword,
+		}
+	}
+
+	if bundle.HTTPClient != nil {
+		unregister, err := gitHTTPClients.register(ctx, bundle.URL, bundle.HTTPClient)
+		if err != nil {
+
+			return nil, err
+		}
+		defer unregister()
+	}
+
+	if len(bundle.CacheDir) != 0 {
+		fileSystem, revision, err := bundle.cachedCheckout(ctx, CloneOpts)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return bundle.loadPath(ctx, bundle.URL, fileSystem, revision, onError)
+	}
+
+	fileSystem := memfs.New()
+	repository, err := git.CloneContext(ctx, memory.NewStorage(), fileSystem, CloneOpts)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(bundle.CommitHash) != 0 {
+		worktree, err := repository.Worktree()
+		if err != nil {
+
+			return nil, err
+		}
+		err = worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(bundle.CommitHash), Force: true})
+		if err != nil {
+
+			return nil, fmt.Errorf("error while checking out commit %s of %s. got %w", bundle.CommitHash, bundle.URL, err)
+		}
+	}
+
+	revision, err := headRevision(repository)
 	if err != nil {
 
 		return nil, err
 	}
 
-	return bundle.loadPath(bundle.URL, "/", fileSystem)
+	return bundle.loadPath(ctx, bundle.URL, fileSystem, revision, onError)
+}
+
+// headRevision returns the commit the repository is checked out at.
+func headRevision(repository *git.Repository) (gitRevision, error) {
+	head, err := repository.Head()
+	if err != nil {
+
+		return gitRevision{}, fmt.Errorf("error while reading the HEAD of the checkout. got %w", err)
+	}
+	commit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+
+		return gitRevision{}, fmt.Errorf("error while reading commit %s. got %w", head.Hash(), err)
+	}
+
+	return gitRevision{hash: commit.Hash.String(), when: commit.Committer.When}, nil
 }
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -12,3 +13,15 @@ func (bundle *GITResourceBundle) Load() ([]Resource, error) {
 
 	return nil, fmt.Errorf("GIT resources are not supported with Go 1.10 or below")
 }
+
+// LoadContext is the same as Load
+func (bundle *GITResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.Load()
+}
+
+// LoadWithHandler is the same as Load
+func (bundle *GITResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return bundle.Load()
+}
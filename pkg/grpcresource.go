@@ -0,0 +1,266 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// grpcGetRuleSetPath is the method path of GetRuleSet, as published in proto/ruleregistry/v1/ruleregistry.proto.
+	grpcGetRuleSetPath = "/grule.ruleregistry.v1.RuleRegistry/GetRuleSet"
+	// grpcMaxMessageSize bounds the rule set a registry may answer, the default of the gRPC servers.
+	grpcMaxMessageSize = 4 << 20
+)
+
+// grpcCodes are the names of the gRPC status codes, by their number.
+var grpcCodes = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS",
+	"PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+	"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+// NewGrpcResource will create a new Resource fetching a version of a rule set from the rule registry at the
+// target, such as "rules.internal:8443", with the GetRuleSet call of proto/ruleregistry/v1/ruleregistry.proto.
+// An empty version fetches the latest version. The connection is not encrypted unless TLS is set.
+func NewGrpcResource(target, name, version string) *GrpcResource {
+
+	return &GrpcResource{
+		Target:  target,
+		Name:    name,
+		Version: version,
+	}
+}
+
+// GrpcResource is a resource holding the GRL of a rule set served by a remote rule registry over gRPC.
+type GrpcResource struct {
+	// Target is the address of the registry, such as rules.internal:8443.
+	Target string
+	// Name of the rule set.
+	Name string
+	// Version of the rule set, the latest if empty.
+	Version string
+	// TLS configures the encryption of the connection, such as the CA of the registry or a client certificate.
+	// The connection is plain HTTP/2 if nil.
+	TLS *tls.Config
+	// Metadata are sent with the call, such as "authorization": "Bearer ...".
+	Metadata map[string]string
+	// RuleSetVersion is the version the registry returned, the latest version when Version is empty.
+	RuleSetVersion string
+	Bytes          []byte
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// String will state the registry and the rule set.
+func (res *GrpcResource) String() string {
+	if len(res.Version) == 0 {
+
+		return fmt.Sprintf("From gRPC rule registry %s rule set %s", res.Target, res.Name)
+	}
+
+	return fmt.Sprintf("From gRPC rule registry %s rule set %s version %s", res.Target, res.Name, res.Version)
+}
+
+// Load will load the resource into byte array. This resource will cache the obtained result byte arrays,
+// calling this function multiple times only calls the registry once. The call times out after
+// Timeout, use LoadContext to choose the deadline.
+func (res *GrpcResource) Load() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(res.Timeout))
+	defer cancel()
+
+	return res.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the call is bound by the context instead of Timeout.
+func (res *GrpcResource) LoadContext(ctx context.Context) ([]byte, error) {
+	if res.Bytes != nil {
+
+		return res.Bytes, nil
+	}
+	request := appendProtoString(nil, 1, res.Name)
+	request = appendProtoString(request, 2, res.Version)
+	response, err := res.call(ctx, grpcGetRuleSetPath, request)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while getting rule set %s from %s. got %w", res.Name, res.Target, err)
+	}
+	var grl []byte
+	err = readProtoStrings(response, func(field uint64, value []byte) {
+		switch field {
+		case 2:
+			res.RuleSetVersion = string(value)
+		case 3:
+			grl = value
+		}
+	})
+	if err != nil {
+
+		return nil, fmt.Errorf("error while getting rule set %s from %s. got %w", res.Name, res.Target, err)
+	}
+	if grl == nil {
+		grl = make([]byte, 0)
+	}
+	res.Bytes = grl
+
+	return res.Bytes, nil
+}
+
+// call sends the unary call of the method with the encoded request and returns the encoded response.
+func (res *GrpcResource) call(ctx context.Context, method string, message []byte) ([]byte, error) {
+	scheme := "http"
+	protocols := &http.Protocols{}
+	if res.TLS != nil {
+		scheme = "https"
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: res.TLS, Protocols: protocols}}
+	defer client.CloseIdleConnections()
+
+	// a gRPC message is framed by a compression flag and its length.
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scheme+"://"+res.Target+method, bytes.NewReader(append(frame, message...)))
+	if err != nil {
+
+		return nil, err
+	}
+	for key, value := range res.Metadata {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", strconv.FormatInt(time.Until(deadline).Milliseconds(), 10)+"m")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+
+		return nil, fmt.Errorf("registry responded %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, grpcMaxMessageSize+5+1))
+	if err != nil {
+
+		return nil, err
+	}
+	// a failed call may have no message, its status is then in the headers instead of the trailers.
+	status := resp.Trailer.Get("Grpc-Status")
+	statusMessage := resp.Trailer.Get("Grpc-Message")
+	if len(status) == 0 {
+		status = resp.Header.Get("Grpc-Status")
+		statusMessage = resp.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 0 || code >= len(grpcCodes) {
+
+			return nil, fmt.Errorf("registry responded with the invalid status %q", status)
+		}
+		if unescaped, err := url.PathUnescape(statusMessage); err == nil {
+			statusMessage = unescaped
+		}
+
+		return nil, fmt.Errorf("registry responded %s. %s", grpcCodes[code], statusMessage)
+	}
+	if len(data) < 5 {
+
+		return nil, fmt.Errorf("registry responded without a message")
+	}
+	if data[0] != 0 {
+
+		return nil, fmt.Errorf("registry responded with a compressed message")
+	}
+	length := binary.BigEndian.Uint32(data[1:5])
+	if length > grpcMaxMessageSize || int(length) != len(data)-5 {
+
+		return nil, fmt.Errorf("registry responded with a message of %d bytes in %d bytes", length, len(data)-5)
+	}
+
+	return data[5:], nil
+}
+
+// appendProtoString appends the string field of a protobuf message, an empty string is not written as in proto3.
+func appendProtoString(buf []byte, field uint64, value string) []byte {
+	if len(value) == 0 {
+
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, field<<3|2)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+
+	return append(buf, value...)
+}
+
+// readProtoStrings calls onString with the length delimited fields of a protobuf message, the other fields
+// are skipped.
+func readProtoStrings(message []byte, onString func(field uint64, value []byte)) error {
+	for len(message) > 0 {
+		tag, n := binary.Uvarint(message)
+		if n <= 0 {
+
+			return fmt.Errorf("malformed protobuf message")
+		}
+		message = message[n:]
+		switch tag & 7 {
+		case 0:
+			_, n = binary.Uvarint(message)
+			if n <= 0 {
+
+				return fmt.Errorf("malformed protobuf varint of field %d", tag>>3)
+			}
+			message = message[n:]
+		case 1, 5:
+			size := 8
+			if tag&7 == 5 {
+				size = 4
+			}
+			if len(message) < size {
+
+				return fmt.Errorf("malformed protobuf fixed field %d", tag>>3)
+			}
+			message = message[size:]
+		case 2:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || length > uint64(len(message)-n) {
+
+				return fmt.Errorf("malformed protobuf field %d", tag>>3)
+			}
+			onString(tag>>3, message[n:n+int(length)])
+			message = message[n+int(length):]
+		default:
+
+			return fmt.Errorf("unsupported protobuf wire type %d of field %d", tag&7, tag>>3)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// grpcRegistry serves GetRuleSet of the rule registry proto from a map of "name@version" to GRL.
+type grpcRegistry struct {
+	ruleSets map[string]string
+	latest   map[string]string
+}
+
+func (registry *grpcRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || r.URL.Path != grpcGetRuleSetPath || r.Header.Get("Content-Type") != "application/grpc+proto" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	fail := func(code, message string) {
+		w.Header().Set("Grpc-Status", code)
+		w.Header().Set("Grpc-Message", message)
+		w.WriteHeader(http.StatusOK)
+	}
+	if r.Header.Get("Authorization") != "Bearer registry-token" {
+		fail("16", "missing%20token")
+
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	fields := map[uint64]string{}
+	_ = readProtoStrings(body[5:], func(field uint64, value []byte) {
+		fields[field] = string(value)
+	})
+	version := fields[2]
+	if len(version) == 0 {
+		version = registry.latest[fields[1]]
+	}
+	grl, ok := registry.ruleSets[fields[1]+"@"+version]
+	if !ok {
+		fail("5", "rule set "+fields[1]+" version "+version+" not found")
+
+		return
+	}
+	// an unknown varint field is skipped by the client.
+	message := binary.AppendUvarint(nil, 4<<3)
+	message = binary.AppendUvarint(message, 42)
+	message = appendProtoString(message, 1, fields[1])
+	message = appendProtoString(message, 2, version)
+	message = appendProtoString(message, 3, grl)
+	frame := make([]byte, 5)
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	w.Header().Set("Trailer", "Grpc-Status")
+	_, _ = w.Write(append(frame, message...))
+	w.Header().Set("Grpc-Status", "0")
+}
+
+func newGrpcRegistry() *grpcRegistry {
+
+	return &grpcRegistry{
+		ruleSets: map[string]string{
+			"pricing@1.0.0": `rule Discount { when true then Retract("Discount"); }`,
+			"pricing@1.1.0": `rule Discount { when false then Retract("Discount"); }`,
+		},
+		latest: map[string]string{"pricing": "1.1.0"},
+	}
+}
+
+func TestGrpcResource(t *testing.T) {
+	server := httptest.NewUnstartedServer(newGrpcRegistry())
+	server.EnableHTTP2 = true
+	// the handshake of the untrusted client fails on the server as well.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	target := strings.TrimPrefix(server.URL, "https://")
+
+	resource := NewGrpcResource(target, "pricing", "1.0.0")
+	resource.TLS = &tls.Config{RootCAs: roots}
+	resource.Metadata = map[string]string{"authorization": "Bearer registry-token"}
+	data, err := resource.Load()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "when true")
+	assert.Equal(t, "1.0.0", resource.RuleSetVersion)
+	assert.Equal(t, "From gRPC rule registry "+target+" rule set pricing version 1.0.0", resource.String())
+
+	latest := NewGrpcResource(target, "pricing", "")
+	latest.TLS = resource.TLS
+	latest.Metadata = resource.Metadata
+	data, err = latest.Load()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "when false")
+	assert.Equal(t, "1.1.0", latest.RuleSetVersion)
+
+	missing := NewGrpcResource(target, "pricing", "9.9.9")
+	missing.TLS = resource.TLS
+	missing.Metadata = resource.Metadata
+	_, err = missing.Load()
+	assert.ErrorContains(t, err, "registry responded NOT_FOUND. rule set pricing version 9.9.9 not found")
+
+	unauthenticated := NewGrpcResource(target, "pricing", "1.0.0")
+	unauthenticated.TLS = resource.TLS
+	_, err = unauthenticated.Load()
+	assert.ErrorContains(t, err, "registry responded UNAUTHENTICATED. missing token")
+
+	// the certificate of the registry is not trusted.
+	untrusted := NewGrpcResource(target, "pricing", "1.0.0")
+	untrusted.TLS = &tls.Config{}
+	_, err = untrusted.Load()
+	assert.Error(t, err)
+}
+
+func TestGrpcResource_Plaintext(t *testing.T) {
+	server := httptest.NewUnstartedServer(newGrpcRegistry())
+	server.Config.Protocols = &http.Protocols{}
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	resource := NewGrpcResource(strings.TrimPrefix(server.URL, "http://"), "pricing", "1.0.0")
+	resource.Metadata = map[string]string{"authorization": "Bearer registry-token"}
+	data, err := resource.Load()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "when true")
+
+	// the rule set is kept, the registry is not called again.
+	server.Close()
+	data, err = resource.Load()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "when true")
+}
@@ -0,0 +1,739 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// KubernetesRuleAPIPath is the API path of the group and version of the Rule custom resource.
+	KubernetesRuleAPIPath = "/apis/grule.hyperjumptech.com/v1"
+
+	// kubernetesServiceAccountDir holds the token and the CA certificate mounted in the pods.
+	kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// NewKubernetesConfigMapBundle will create a new instance of KubernetesResourceBundle reading the GRL from the data
+// of the ConfigMaps in the namespace, all namespaces if empty, selected by the label selector, such as "app=grule".
+// pathPattern are list of key patterns (glob) to filter the data, all keys are read if none is given.
+func NewKubernetesConfigMapBundle(namespace, labelSelector string, pathPattern ...string) *KubernetesResourceBundle {
+
+	return &KubernetesResourceBundle{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		APIPath:       "/api/v1",
+		Resource:      "configmaps",
+		PathPattern:   pathPattern,
+	}
+}
+
+// NewKubernetesRuleBundle will create a new instance of KubernetesResourceBundle reading the GRL from the spec.grl
+// field of the Rule custom resources (rules.grule.hyperjumptech.com) selected by the label selector.
+func NewKubernetesRuleBundle(namespace, labelSelector string) *KubernetesResourceBundle {
+
+	return &KubernetesResourceBundle{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		APIPath:       KubernetesRuleAPIPath,
+		Resource:      "rules",
+		SpecField:     "grl",
+	}
+}
+
+// KubernetesResourceBundle is a helper struct to load the GRL kept in Kubernetes objects, ConfigMaps or a custom
+// resource, selected by their labels. Watch reloads them every time one of them is changed.
+//
+// Inside a pod it talks to the API server of the cluster with the service account of the pod, which needs the
+// permission to list and watch the resource. Outside a cluster it uses the current context of the kubeconfig.
+type KubernetesResourceBundle struct {
+	// Namespace of the objects, all namespaces if empty.
+	Namespace string
+	// LabelSelector selects the objects, such as "app=grule,tier in (gold, silver)".
+	LabelSelector string
+	// APIPath of the group and version of the resource, such as /api/v1 or KubernetesRuleAPIPath.
+	APIPath string
+	// Resource is the plural name of the resource, such as configmaps or rules.
+	Resource string
+	// List Glob like key pattern of the data to read, such as *.grl. All keys are read if empty.
+	PathPattern []string
+	// SpecField, if set, is the field of the spec holding the GRL, instead of the data of a ConfigMap.
+	SpecField string
+	// Server is the URL of the API server. If empty, the service of the cluster is used, or else the kubeconfig.
+	Server string
+	// Kubeconfig is the path of the kubeconfig file giving the server and the credentials, if Server is empty.
+	// If empty outside a cluster, the first file of the KUBECONFIG environment variable or ~/.kube/config is used.
+	Kubeconfig string
+	// Context of the kubeconfig to use. The current context of the kubeconfig if empty.
+	Context string
+	// Token authorizes the requests. If empty, the token of the kubeconfig, or of the service account of the pod,
+	// which is read on every request.
+	Token string
+	// HTTPClient, if set, sends the requests. If nil, the requests trust the CA certificate of the service account,
+	// or of the cluster of the kubeconfig.
+	HTTPClient *http.Client
+	// RetryInterval is the delay before Watch connects again after an error, 5 seconds if zero.
+	RetryInterval time.Duration
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+
+	mutex  sync.Mutex
+	target *kubernetesTarget
+}
+
+// kubernetesTarget is the API server the requests are sent to, and how, resolved at the first request of the bundle.
+type kubernetesTarget struct {
+	server string
+	// token is given by the kubeconfig, else tokenFile is read on every request, as the token may be rotated.
+	token     string
+	tokenFile string
+	client    *http.Client
+}
+
+// kubeconfig is the part of a kubeconfig file telling the server and the credentials of its contexts.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			TLSServerName            string `yaml:"tls-server-name"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string      `yaml:"token"`
+			TokenFile             string      `yaml:"tokenFile"`
+			ClientCertificate     string      `yaml:"client-certificate"`
+			ClientCertificateData string      `yaml:"client-certificate-data"`
+			ClientKey             string      `yaml:"client-key"`
+			ClientKeyData         string      `yaml:"client-key-data"`
+			Exec                  interface{} `yaml:"exec"`
+			AuthProvider          interface{} `yaml:"auth-provider"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// kubernetesObject is a ConfigMap or a custom resource.
+type kubernetesObject struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Data map[string]string      `json:"data"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
+// kubernetesList is a page of the objects list.
+type kubernetesList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+		Continue        string `json:"continue"`
+	} `json:"metadata"`
+	Items []kubernetesObject `json:"items"`
+}
+
+// kubernetesWatchEvent is a line of the watch stream.
+type kubernetesWatchEvent struct {
+	Type   string `json:"type"`
+	Object struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"object"`
+}
+
+// Load lists the selected objects and returns the GRL they hold, in the order of their namespace, name and key.
+func (bundle *KubernetesResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *KubernetesResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	resources, _, err := bundle.load(ctx, nil)
+
+	return resources, err
+}
+
+// LoadWithHandler is the same as Load, the objects not holding GRL are reported to onError.
+func (bundle *KubernetesResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	resources, _, err := bundle.load(ctx, onError)
+
+	return resources, err
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use KubernetesResourceBundle.LoadContext, or KubernetesResourceBundle.LoadWithHandler to go on without
+// the objects not holding GRL.
+func (bundle *KubernetesResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// Watch loads the resources and calls onReload with them, then watches the selected objects and calls onReload
+// again after every change, until the context is done. A failed reload is given to onReload with the error,
+// and retried after the RetryInterval.
+func (bundle *KubernetesResourceBundle) Watch(ctx context.Context, onReload func(resources []Resource, err error)) error {
+	retry := bundle.RetryInterval
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	version := ""
+	for {
+		if len(version) == 0 {
+			resources, listed, err := bundle.load(ctx, nil)
+			if ctx.Err() != nil {
+
+				return ctx.Err()
+			}
+			onReload(resources, err)
+			if err == nil {
+				version = listed
+			}
+		}
+		if len(version) > 0 {
+			changed, next, err := bundle.watch(ctx, version)
+			if ctx.Err() != nil {
+
+				return ctx.Err()
+			}
+			switch {
+			case err != nil:
+				contextLog(ctx).Warnf("Watch of Kubernetes %s failed, retrying. %v", bundle.Resource, err)
+			case changed:
+				version = ""
+
+				continue
+			default:
+				version = next
+
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// load lists all the selected objects, following the continue tokens, and returns their resources with the
+// resource version of the list. The objects not holding GRL are reported to onError, if not nil.
+func (bundle *KubernetesResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, string, error) {
+	objects := make([]kubernetesObject, 0)
+	version := ""
+	next := ""
+	for {
+		query := bundle.query()
+		query.Set("limit", "500")
+		if len(next) > 0 {
+			query.Set("continue", next)
+		}
+		resp, err := bundle.get(ctx, query)
+		if err != nil {
+
+			return nil, "", err
+		}
+		page := &kubernetesList{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		resp.Body.Close()
+		if err != nil {
+
+			return nil, "", fmt.Errorf("error while listing Kubernetes %s. got %w", bundle.Resource, err)
+		}
+		objects = append(objects, page.Items...)
+		version = page.Metadata.ResourceVersion
+		if len(page.Metadata.Continue) == 0 {
+
+			break
+		}
+		next = page.Metadata.Continue
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Metadata.Namespace != objects[j].Metadata.Namespace {
+
+			return objects[i].Metadata.Namespace < objects[j].Metadata.Namespace
+		}
+
+		return objects[i].Metadata.Name < objects[j].Metadata.Name
+	})
+
+	ret := make([]Resource, 0, len(objects))
+	for _, object := range objects {
+		var resources []Resource
+		skipped, err := retryLoad(ctx, &KubernetesResource{
+			Resource:  bundle.Resource,
+			Namespace: object.Metadata.Namespace,
+			Name:      object.Metadata.Name,
+		}, onError, func() error {
+			var err error
+			resources, err = bundle.resources(object)
+
+			return err
+		})
+		if err != nil {
+
+			return nil, "", err
+		}
+		if !skipped {
+			ret = append(ret, resources...)
+		}
+	}
+
+	return ret, version, nil
+}
+
+// resources returns the GRL held by the object.
+func (bundle *KubernetesResourceBundle) resources(object kubernetesObject) ([]Resource, error) {
+	if len(bundle.SpecField) > 0 {
+		grl, ok := object.Spec[bundle.SpecField].(string)
+		if !ok {
+
+			return nil, fmt.Errorf("Kubernetes %s %s/%s has no string spec.%s", bundle.Resource, object.Metadata.Namespace, object.Metadata.Name, bundle.SpecField)
+		}
+
+		return []Resource{&KubernetesResource{
+			Resource:  bundle.Resource,
+			Namespace: object.Metadata.Namespace,
+			Name:      object.Metadata.Name,
+			Key:       "spec." + bundle.SpecField,
+			Bytes:     []byte(grl),
+		}}, nil
+	}
+
+	keys := make([]string, 0, len(object.Data))
+	for key := range object.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	ret := make([]Resource, 0, len(keys))
+	for _, key := range keys {
+		matched := len(bundle.PathPattern) == 0
+		for _, pattern := range bundle.PathPattern {
+			ok, err := doublestar.Match(pattern, key)
+			if err != nil {
+
+				return nil, err
+			}
+			if ok {
+				matched = true
+
+				break
+			}
+		}
+		if !matched {
+
+			continue
+		}
+		logger.Log.Debugf("Loading Kubernetes %s %s/%s key %s", bundle.Resource, object.Metadata.Namespace, object.Metadata.Name, key)
+		ret = append(ret, &KubernetesResource{
+			Resource:  bundle.Resource,
+			Namespace: object.Metadata.Namespace,
+			Name:      object.Metadata.Name,
+			Key:       key,
+			Bytes:     []byte(object.Data[key]),
+		})
+	}
+
+	return ret, nil
+}
+
+// watch watches the objects from the resource version until one of them changes, or the server ends the watch.
+// It returns if an object changed, and else the resource version to watch from next, which is empty if the
+// version is too old to be watched and the objects must be listed again.
+func (bundle *KubernetesResourceBundle) watch(ctx context.Context, version string) (bool, string, error) {
+	query := bundle.query()
+	query.Set("watch", "true")
+	query.Set("allowWatchBookmarks", "true")
+	query.Set("resourceVersion", version)
+	resp, err := bundle.get(ctx, query)
+	if err != nil {
+
+		return false, "", err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		event := &kubernetesWatchEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+
+			return false, "", fmt.Errorf("invalid watch event of Kubernetes %s. got %w", bundle.Resource, err)
+		}
+		switch event.Type {
+		case "ADDED", "MODIFIED", "DELETED":
+
+			return true, "", nil
+		case "BOOKMARK":
+			version = event.Object.Metadata.ResourceVersion
+		case "ERROR":
+			if event.Object.Code == http.StatusGone {
+
+				return false, "", nil
+			}
+
+			return false, "", fmt.Errorf("watch of Kubernetes %s failed. %s", bundle.Resource, event.Object.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+
+		return false, "", err
+	}
+
+	return false, version, nil
+}
+
+// query returns the query selecting the objects.
+func (bundle *KubernetesResourceBundle) query() url.Values {
+	query := url.Values{}
+	if len(bundle.LabelSelector) > 0 {
+		query.Set("labelSelector", bundle.LabelSelector)
+	}
+
+	return query
+}
+
+// get sends an authorized GET request for the objects and returns the response, which the caller must close.
+func (bundle *KubernetesResourceBundle) get(ctx context.Context, query url.Values) (*http.Response, error) {
+	target, err := bundle.connect()
+	if err != nil {
+
+		return nil, err
+	}
+	path := strings.TrimSuffix(bundle.APIPath, "/")
+	if len(bundle.Namespace) > 0 {
+		path += "/namespaces/" + url.PathEscape(bundle.Namespace)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.server+path+"/"+bundle.Resource+"?"+query.Encode(), nil)
+	if err != nil {
+
+		return nil, err
+	}
+	if token := bundle.token(target); len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := bundle.HTTPClient
+	if client == nil {
+		client = target.client
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		status := &struct {
+			Message string `json:"message"`
+		}{}
+		if json.Unmarshal(body, status) != nil || len(status.Message) == 0 {
+			status.Message = strings.TrimSpace(string(body))
+		}
+
+		return nil, fmt.Errorf("Kubernetes API responded %s to %s. %s", resp.Status, req.URL.Path, status.Message)
+	}
+
+	return resp, nil
+}
+
+// connect returns the API server of the bundle, resolving it at the first request. A failure is not kept, so the
+// next request tries again.
+func (bundle *KubernetesResourceBundle) connect() (*kubernetesTarget, error) {
+	bundle.mutex.Lock()
+	defer bundle.mutex.Unlock()
+	if bundle.target == nil {
+		target, err := bundle.resolve()
+		if err != nil {
+
+			return nil, err
+		}
+		bundle.target = target
+	}
+
+	return bundle.target, nil
+}
+
+// resolve returns the Server, else the service of the cluster, else the cluster of the kubeconfig.
+func (bundle *KubernetesResourceBundle) resolve() (*kubernetesTarget, error) {
+	serviceAccount := &kubernetesTarget{
+		tokenFile: filepath.Join(kubernetesServiceAccountDir, "token"),
+		client:    &http.Client{},
+	}
+	if caCert, err := os.ReadFile(filepath.Join(kubernetesServiceAccountDir, "ca.crt")); err == nil {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(caCert)
+		serviceAccount.client = kubernetesClient(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+	}
+	if len(bundle.Server) > 0 {
+		serviceAccount.server = strings.TrimSuffix(bundle.Server, "/")
+
+		return serviceAccount, nil
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(bundle.Kubeconfig) == 0 && len(host) > 0 && len(port) > 0 {
+		serviceAccount.server = "https://" + net.JoinHostPort(host, port)
+
+		return serviceAccount, nil
+	}
+	path := bundle.kubeconfigPath()
+	if len(path) == 0 {
+
+		return nil, fmt.Errorf("not running in a Kubernetes cluster and no kubeconfig found, the Server of the bundle must be set")
+	}
+
+	return bundle.loadKubeconfig(path)
+}
+
+// kubeconfigPath returns the Kubeconfig, else the first file of KUBECONFIG, else ~/.kube/config if it exists.
+func (bundle *KubernetesResourceBundle) kubeconfigPath() string {
+	if len(bundle.Kubeconfig) > 0 {
+
+		return bundle.Kubeconfig
+	}
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if len(path) > 0 {
+
+			return path
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+
+		return ""
+	}
+	path := filepath.Join(home, ".kube", "config")
+	if _, err := os.Stat(path); err != nil {
+
+		return ""
+	}
+
+	return path
+}
+
+// loadKubeconfig returns the cluster and the credentials of the Context of the kubeconfig file, its current
+// context if empty. The users authenticating with a plugin are not supported, their Token must be set.
+func (bundle *KubernetesResourceBundle) loadKubeconfig(path string) (*kubernetesTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while reading kubeconfig %s. got %w", path, err)
+	}
+	config := &kubeconfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+
+		return nil, fmt.Errorf("error while parsing kubeconfig %s. got %w", path, err)
+	}
+	name := bundle.Context
+	if len(name) == 0 {
+		name = config.CurrentContext
+	}
+	clusterName, userName := "", ""
+	found := false
+	for _, kubeContext := range config.Contexts {
+		if kubeContext.Name == name {
+			clusterName, userName, found = kubeContext.Context.Cluster, kubeContext.Context.User, true
+
+			break
+		}
+	}
+	if !found {
+
+		return nil, fmt.Errorf("kubeconfig %s has no context %q", path, name)
+	}
+	// the files named by the kubeconfig are relative to it.
+	dir := filepath.Dir(path)
+	read := func(file, data string) ([]byte, error) {
+		if len(data) > 0 {
+
+			return base64.StdEncoding.DecodeString(data)
+		}
+		if len(file) == 0 {
+
+			return nil, nil
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+
+		return os.ReadFile(file)
+	}
+
+	target := &kubernetesTarget{}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	found = false
+	for _, cluster := range config.Clusters {
+		if cluster.Name != clusterName {
+
+			continue
+		}
+		found = true
+		target.server = strings.TrimSuffix(cluster.Cluster.Server, "/")
+		tlsConfig.ServerName = cluster.Cluster.TLSServerName
+		tlsConfig.InsecureSkipVerify = cluster.Cluster.InsecureSkipTLSVerify
+		caCert, err := read(cluster.Cluster.CertificateAuthority, cluster.Cluster.CertificateAuthorityData)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the certificate authority of cluster %s of kubeconfig %s. got %w", clusterName, path, err)
+		}
+		if len(caCert) > 0 {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			tlsConfig.RootCAs.AppendCertsFromPEM(caCert)
+		}
+
+		break
+	}
+	if !found || len(target.server) == 0 {
+
+		return nil, fmt.Errorf("kubeconfig %s has no server for cluster %q", path, clusterName)
+	}
+	for _, user := range config.Users {
+		if user.Name != userName {
+
+			continue
+		}
+		if (user.User.Exec != nil || user.User.AuthProvider != nil) && len(bundle.Token) == 0 {
+
+			return nil, fmt.Errorf("user %s of kubeconfig %s authenticates with a plugin, the Token of the bundle must be set", userName, path)
+		}
+		target.token = user.User.Token
+		target.tokenFile = user.User.TokenFile
+		if len(target.tokenFile) > 0 && !filepath.IsAbs(target.tokenFile) {
+			target.tokenFile = filepath.Join(dir, target.tokenFile)
+		}
+		cert, err := read(user.User.ClientCertificate, user.User.ClientCertificateData)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the client certificate of user %s of kubeconfig %s. got %w", userName, path, err)
+		}
+		key, err := read(user.User.ClientKey, user.User.ClientKeyData)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while reading the client key of user %s of kubeconfig %s. got %w", userName, path, err)
+		}
+		if len(cert) > 0 {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+
+				return nil, fmt.Errorf("invalid client certificate of user %s of kubeconfig %s. got %w", userName, path, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+
+		break
+	}
+	target.client = kubernetesClient(tlsConfig)
+
+	return target, nil
+}
+
+// kubernetesClient returns a client sending the requests with the TLS configuration, through the proxy of the
+// environment.
+func kubernetesClient(tlsConfig *tls.Config) *http.Client {
+
+	return &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}}
+}
+
+// token returns the Token, else the token of the target, else the content of its token file, read every time.
+func (bundle *KubernetesResourceBundle) token(target *kubernetesTarget) string {
+	if len(bundle.Token) > 0 {
+
+		return bundle.Token
+	}
+	if len(target.token) > 0 || len(target.tokenFile) == 0 {
+
+		return target.token
+	}
+	token, err := os.ReadFile(target.tokenFile)
+	if err != nil {
+
+		return ""
+	}
+
+	return strings.TrimSpace(string(token))
+}
+
+// KubernetesResource resource implementation that loaded from a key of a Kubernetes object
+type KubernetesResource struct {
+	Resource  string
+	Namespace string
+	Name      string
+	Key       string
+	Bytes     []byte
+}
+
+// String will state the resource object and key.
+func (res *KubernetesResource) String() string {
+
+	return fmt.Sprintf("From Kubernetes %s %s/%s key %s", res.Resource, res.Namespace, res.Name, res.Key)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the object from Kubernetes when
+// this method is called, it simply return the loaded data.
+func (res *KubernetesResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
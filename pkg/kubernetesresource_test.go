@@ -0,0 +1,227 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type kubernetesServer struct {
+	mutex   sync.Mutex
+	rule    string
+	changed chan struct{}
+}
+
+func (server *kubernetesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer cluster-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"kind":"Status","message":"Unauthorized","code":401}`)
+
+		return
+	}
+	if r.URL.Query().Get("labelSelector") != "app=grule" {
+		fmt.Fprint(w, `{"metadata":{"resourceVersion":"1"},"items":[]}`)
+
+		return
+	}
+	switch {
+	case r.URL.Path == "/api/v1/namespaces/grule/configmaps" && r.URL.Query().Get("watch") == "true":
+		w.(http.Flusher).Flush()
+		select {
+		case <-server.changed:
+			fmt.Fprintln(w, `{"type":"BOOKMARK","object":{"metadata":{"resourceVersion":"3"}}}`)
+			fmt.Fprintln(w, `{"type":"MODIFIED","object":{"metadata":{"name":"rules-b","resourceVersion":"4"}}}`)
+		case <-r.Context().Done():
+		}
+	case r.URL.Path == "/api/v1/namespaces/grule/configmaps" && r.URL.Query().Get("continue") == "":
+		fmt.Fprint(w, `{"metadata":{"resourceVersion":"2","continue":"page2"},"items":[
+			{"metadata":{"name":"rules-b","namespace":"grule"},"data":{"b.grl":"rule B","readme.md":"not a rule"}}]}`)
+	case r.URL.Path == "/api/v1/namespaces/grule/configmaps":
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		fmt.Fprintf(w, `{"metadata":{"resourceVersion":"2"},"items":[
+			{"metadata":{"name":"rules-a","namespace":"grule"},"data":{"a.grl":%q}}]}`, server.rule)
+	case r.URL.Path == KubernetesRuleAPIPath+"/rules":
+		fmt.Fprint(w, `{"metadata":{"resourceVersion":"7"},"items":[
+			{"metadata":{"name":"discount","namespace":"shop"},"spec":{"grl":"rule Discount"}},
+			{"metadata":{"name":"audit","namespace":"bank"},"spec":{"grl":"rule Audit"}}]}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"kind":"Status","message":"the server could not find the requested resource","code":404}`)
+	}
+}
+
+func TestKubernetesConfigMapBundle(t *testing.T) {
+	api := &kubernetesServer{rule: "rule A", changed: make(chan struct{})}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	bundle := NewKubernetesConfigMapBundle("grule", "app=grule", "*.grl")
+	bundle.Server = server.URL
+	bundle.Token = "cluster-token"
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "From Kubernetes configmaps grule/rules-a key a.grl", resources[0].String())
+	data, err := resources[1].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule B", string(data))
+
+	bundle.Token = "wrong"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "Unauthorized")
+}
+
+func TestKubernetesConfigMapBundle_Watch(t *testing.T) {
+	api := &kubernetesServer{rule: "rule A", changed: make(chan struct{})}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	bundle := NewKubernetesConfigMapBundle("grule", "app=grule")
+	bundle.Server = server.URL
+	bundle.Token = "cluster-token"
+	reloads := make(chan []Resource)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bundle.Watch(ctx, func(resources []Resource, err error) {
+			assert.NoError(t, err)
+			reloads <- resources
+		})
+	}()
+
+	resources := <-reloads
+	assert.Len(t, resources, 3)
+	api.mutex.Lock()
+	api.rule = "rule A2"
+	api.mutex.Unlock()
+	api.changed <- struct{}{}
+	select {
+	case resources = <-reloads:
+	case <-time.After(time.Second):
+		t.Fatal("expected a reload after the change")
+	}
+	data, err := resources[0].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A2", string(data))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestKubernetesRuleBundle(t *testing.T) {
+	server := httptest.NewServer(&kubernetesServer{})
+	defer server.Close()
+
+	bundle := NewKubernetesRuleBundle("", "app=grule")
+	bundle.Server = server.URL
+	bundle.Token = "cluster-token"
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "From Kubernetes rules bank/audit key spec.grl", resources[0].String())
+	data, err := resources[1].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule Discount", string(data))
+
+	bundle.SpecField = "missing"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "has no string spec.missing")
+
+	failed := make([]string, 0)
+	resources, err = bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		failed = append(failed, res.(*KubernetesResource).Name)
+
+		return DecisionSkip
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 0)
+	assert.Equal(t, []string{"audit", "discount"}, failed)
+}
+
+func TestKubernetesRuleBundle_Kubeconfig(t *testing.T) {
+	server := httptest.NewTLSServer(&kubernetesServer{})
+	defer server.Close()
+
+	caCert := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("cluster-token\n"), 0o600))
+	kubeconfig := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: reader
+- name: plugin
+  context:
+    cluster: test
+    user: sso
+clusters:
+- name: test
+  cluster:
+    server: `+server.URL+`
+    certificate-authority-data: `+caCert+`
+users:
+- name: reader
+  user:
+    tokenFile: token
+- name: sso
+  user:
+    exec:
+      command: sso-login
+`), 0o600))
+
+	bundle := NewKubernetesRuleBundle("", "app=grule")
+	bundle.Kubeconfig = kubeconfig
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	// the client is built once for the bundle.
+	client := bundle.target.client
+	_, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Same(t, client, bundle.target.client)
+
+	bundle = NewKubernetesRuleBundle("", "app=grule")
+	bundle.Kubeconfig = kubeconfig
+	bundle.Context = "plugin"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "authenticates with a plugin")
+	bundle.Token = "cluster-token"
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	bundle.Context = "missing"
+	bundle.target = nil
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, `has no context "missing"`)
+}
@@ -0,0 +1,145 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// LiteralSuffixParser parses the text in front of the suffix of a GRL literal, such as 10 of 10_USD or
+// 2024-05-01 of 2024-05-01_D, into a value of a user type. The text starts with a minus if the literal is negated.
+type LiteralSuffixParser func(text string) (interface{}, error)
+
+// defaultLiteralSuffixes are the suffixes of the registries that are nil, RegisterLiteralSuffix adds into them.
+var defaultLiteralSuffixes = NewLiteralSuffixRegistry()
+
+// LiteralSuffixRegistry keeps the parsers of the literal suffixes. A nil registry is the default one of the process,
+// so two knowledge libraries may each have suffixes of their own, see ast.KnowledgeLibrary.LiteralSuffixes.
+type LiteralSuffixRegistry struct {
+	mutex   sync.RWMutex
+	parsers map[string]LiteralSuffixParser
+}
+
+// NewLiteralSuffixRegistry create a LiteralSuffixRegistry without any suffix.
+func NewLiteralSuffixRegistry() *LiteralSuffixRegistry {
+
+	return &LiteralSuffixRegistry{parsers: make(map[string]LiteralSuffixParser)}
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *LiteralSuffixRegistry) or() *LiteralSuffixRegistry {
+	if registry == nil {
+
+		return defaultLiteralSuffixes
+	}
+
+	return registry
+}
+
+// Register adds the parser of the literals ending with the suffix, such as Register("USD", parser) for 10_USD.
+// The literals are parsed when the rules are built, so the suffix must be registered before.
+func (registry *LiteralSuffixRegistry) Register(suffix string, parser LiteralSuffixParser) error {
+	if !isLiteralSuffix(suffix) || parser == nil {
+
+		return fmt.Errorf("literal suffix needs a name starting with a letter and a parser")
+	}
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, exist := registry.parsers[suffix]; exist {
+
+		return fmt.Errorf("literal suffix %s is already registered", suffix)
+	}
+	registry.parsers[suffix] = parser
+
+	return nil
+}
+
+// Unregister removes the parser of the suffix, the rules already built keep their values.
+func (registry *LiteralSuffixRegistry) Unregister(suffix string) {
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.parsers, suffix)
+}
+
+// Parse returns the value of a literal such as 10_USD, -10_USD or 2024-05-01_D using the parser registered for
+// its suffix.
+func (registry *LiteralSuffixRegistry) Parse(literal string) (interface{}, error) {
+	split := strings.IndexByte(literal, '_')
+	if split <= 0 || !isLiteralSuffix(literal[split+1:]) {
+
+		return nil, fmt.Errorf("literal %s has no suffix", literal)
+	}
+	text, suffix := literal[:split], literal[split+1:]
+	registry = registry.or()
+	registry.mutex.RLock()
+	parser, ok := registry.parsers[suffix]
+	registry.mutex.RUnlock()
+	if !ok {
+
+		return nil, fmt.Errorf("literal suffix %s is not registered", suffix)
+	}
+	value, err := parser(text)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while parsing literal %s. got %w", literal, err)
+	}
+	if value == nil {
+
+		return nil, fmt.Errorf("parser of literal suffix %s returns nil for %s", suffix, literal)
+	}
+
+	return value, nil
+}
+
+// RegisterLiteralSuffix adds the parser of the literals ending with the suffix into the default registry, such as
+// RegisterLiteralSuffix("USD", parser) for 10_USD. The literals are parsed when the rules are built, so the suffix
+// must be registered before.
+func RegisterLiteralSuffix(suffix string, parser LiteralSuffixParser) error {
+
+	return defaultLiteralSuffixes.Register(suffix, parser)
+}
+
+// UnregisterLiteralSuffix removes the parser of the suffix from the default registry, the rules already built keep
+// their values.
+func UnregisterLiteralSuffix(suffix string) {
+	defaultLiteralSuffixes.Unregister(suffix)
+}
+
+// ParseSuffixLiteral returns the value of a literal such as 10_USD, -10_USD or 2024-05-01_D using the parser
+// registered for its suffix in the default registry.
+func ParseSuffixLiteral(literal string) (interface{}, error) {
+
+	return defaultLiteralSuffixes.Parse(literal)
+}
+
+func isLiteralSuffix(suffix string) bool {
+	for i, c := range suffix {
+		if i == 0 && !unicode.IsLetter(c) {
+
+			return false
+		}
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+
+			return false
+		}
+	}
+
+	return len(suffix) > 0
+}
@@ -0,0 +1,76 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSuffixLiteral(t *testing.T) {
+	err := RegisterLiteralSuffix("USD", func(text string) (interface{}, error) {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return NewQuantity(value, "USD")
+	})
+	assert.NoError(t, err)
+	defer UnregisterLiteralSuffix("USD")
+
+	assert.Error(t, RegisterLiteralSuffix("USD", func(text string) (interface{}, error) { return text, nil }))
+	assert.Error(t, RegisterLiteralSuffix("1X", func(text string) (interface{}, error) { return text, nil }))
+	assert.Error(t, RegisterLiteralSuffix("X", nil))
+	assert.NoError(t, RegisterLiteralSuffix("NIL", func(text string) (interface{}, error) { return nil, nil }))
+	defer UnregisterLiteralSuffix("NIL")
+
+	value, err := ParseSuffixLiteral("10_USD")
+	assert.NoError(t, err)
+	assert.Equal(t, Quantity{Value: 10, Unit: "USD"}, value)
+
+	value, err = ParseSuffixLiteral("-2.5_USD")
+	assert.NoError(t, err)
+	assert.Equal(t, Quantity{Value: -2.5, Unit: "USD"}, value)
+
+	for _, literal := range []string{"10", "10_", "10_1X", "10_EUR", "1.2.3_USD", "10_NIL"} {
+		_, err = ParseSuffixLiteral(literal)
+		assert.Error(t, err, literal)
+	}
+}
+
+func TestLiteralSuffixRegistry(t *testing.T) {
+	registry := NewLiteralSuffixRegistry()
+	assert.NoError(t, registry.Register("PTS", func(text string) (interface{}, error) {
+
+		return strconv.ParseInt(text, 10, 64)
+	}))
+	value, err := registry.Parse("12_PTS")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	// the suffixes of a registry are not known to the others.
+	_, err = ParseSuffixLiteral("12_PTS")
+	assert.Error(t, err)
+	_, err = NewLiteralSuffixRegistry().Parse("12_PTS")
+	assert.Error(t, err)
+
+	registry.Unregister("PTS")
+	_, err = registry.Parse("12_PTS")
+	assert.Error(t, err)
+}
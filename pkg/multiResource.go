@@ -0,0 +1,216 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DuplicatePolicy tells what a MultiResourceBundle does with resources of the same path loaded by different bundles.
+type DuplicatePolicy int
+
+const (
+	// DuplicateOverride keeps the resource of the bundle that comes last, such as local files overriding
+	// the files of a GIT bundle listed before them. The resource takes the place of the one it overrides.
+	DuplicateOverride DuplicatePolicy = iota
+	// DuplicateKeepFirst keeps the resource of the bundle that comes first, the others are dropped.
+	DuplicateKeepFirst
+	// DuplicateKeepAll keeps every resource, duplicates included.
+	DuplicateKeepAll
+	// DuplicateError fails the Load when two bundles load the same path.
+	DuplicateError
+)
+
+// ResourceOrder tells how a MultiResourceBundle orders the resources it returns.
+type ResourceOrder int
+
+const (
+	// OrderByBundle returns the resources of the first bundle, then of the second one and so on,
+	// each in the order its bundle loaded them.
+	OrderByBundle ResourceOrder = iota
+	// OrderByPath returns the resources sorted by their path.
+	OrderByPath
+)
+
+// NewMultiResourceBundle creates a new MultiResourceBundle loading the bundles in the given order.
+// Resources of the same path are overridden by the bundles that come later.
+func NewMultiResourceBundle(bundles ...ResourceBundle) *MultiResourceBundle {
+
+	return &MultiResourceBundle{
+		Bundles: bundles,
+	}
+}
+
+// MultiResourceBundle loads several bundles as one, such as a base GIT bundle layered with local file overrides.
+type MultiResourceBundle struct {
+	// Bundles are loaded in order, the order decides which resource the Duplicates policy keeps.
+	Bundles []ResourceBundle
+	// Duplicates tells what to do with resources of the same path, they are overridden by default.
+	Duplicates DuplicatePolicy
+	// Order tells how the resources are ordered, by bundle by default.
+	Order ResourceOrder
+	// PathOf returns the path of a resource loaded by a bundle, to find the duplicates. If nil, the path is
+	// relative to the base path or the prefix of the bundle for the bundles of this package, such as
+	// "rules/discount.grl" for both a FileResourceBundle and a GITResourceBundle.
+	PathOf func(bundle ResourceBundle, resource Resource) string
+}
+
+// Load loads all the bundles and returns their resources, the duplicates resolved.
+func (bundle *MultiResourceBundle) Load() ([]Resource, error) {
+
+	return bundle.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, it stops with the context error once the context is done.
+func (bundle *MultiResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(func(child ResourceBundle) ([]Resource, error) {
+
+		return LoadBundle(ctx, child)
+	})
+}
+
+// LoadWithHandler is the same as Load, the resources failing to load are reported to onError as they are by
+// LoadBundleWithHandler for every bundle.
+func (bundle *MultiResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return bundle.load(func(child ResourceBundle) ([]Resource, error) {
+
+		return LoadBundleWithHandler(child, onError)
+	})
+}
+
+func (bundle *MultiResourceBundle) load(loadBundle func(child ResourceBundle) ([]Resource, error)) ([]Resource, error) {
+	pathOf := bundle.PathOf
+	if pathOf == nil {
+		pathOf = resourcePath
+	}
+	ret := make([]Resource, 0)
+	paths := make([]string, 0)
+	index := make(map[string]int)
+	for i, child := range bundle.Bundles {
+		resources, err := loadBundle(child)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while loading bundle %d of the multi resource bundle. got %w", i, err)
+		}
+		for _, resource := range resources {
+			path := pathOf(child, resource)
+			at, duplicate := index[path]
+			if !duplicate || bundle.Duplicates == DuplicateKeepAll {
+				index[path] = len(ret)
+				ret = append(ret, resource)
+				paths = append(paths, path)
+
+				continue
+			}
+			switch bundle.Duplicates {
+			case DuplicateError:
+
+				return nil, fmt.Errorf("path %s is loaded by more than one bundle : %s and %s", path, ret[at], resource)
+			case DuplicateOverride:
+				ret[at] = resource
+			}
+		}
+	}
+	if bundle.Order == OrderByPath {
+		sort.Stable(&resourcesByPath{resources: ret, paths: paths})
+	}
+
+	return ret, nil
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during loading the bundles.
+//
+// Deprecated: use MultiResourceBundle.Load, or MultiResourceBundle.LoadWithHandler to go on without the bundles and
+// resources failing to load.
+func (bundle *MultiResourceBundle) MustLoad() []Resource {
+	resources, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return resources
+}
+
+// resourcesByPath sorts resources together with their paths.
+type resourcesByPath struct {
+	resources []Resource
+	paths     []string
+}
+
+func (r *resourcesByPath) Len() int {
+
+	return len(r.resources)
+}
+
+func (r *resourcesByPath) Less(i, j int) bool {
+
+	return r.paths[i] < r.paths[j]
+}
+
+func (r *resourcesByPath) Swap(i, j int) {
+	r.resources[i], r.resources[j] = r.resources[j], r.resources[i]
+	r.paths[i], r.paths[j] = r.paths[j], r.paths[i]
+}
+
+// resourcePath returns the path of the resource relative to the root of its bundle, using forward slashes.
+// Resources this package does not know are identified by their String.
+func resourcePath(bundle ResourceBundle, resource Resource) string {
+	switch res := resource.(type) {
+	case *FileResource:
+		path := res.Path
+		if files, ok := bundle.(*FileResourceBundle); ok {
+			if base, err := filepath.Abs(files.BasePath); err == nil {
+				if rel, err := filepath.Rel(base, path); err == nil {
+					path = rel
+				}
+			}
+		}
+
+		return filepath.ToSlash(path)
+	case *GITResource:
+
+		return strings.TrimLeft(res.Path, "/")
+	case *EmbeddedResource:
+		path := filepath.ToSlash(res.Path)
+		if embedded, ok := bundle.(*EmbeddedResourceBundle); ok {
+			path = strings.TrimPrefix(path, strings.TrimSuffix(embedded.BasePath, "/")+"/")
+		}
+
+		return path
+	case *S3Resource:
+		if objects, ok := bundle.(*S3ResourceBundle); ok {
+
+			return strings.TrimLeft(strings.TrimPrefix(res.Key, objects.Prefix), "/")
+		}
+
+		return res.Key
+	case *GCSResource:
+		if objects, ok := bundle.(*GCSResourceBundle); ok {
+
+			return strings.TrimLeft(strings.TrimPrefix(res.Name, objects.Prefix), "/")
+		}
+
+		return res.Name
+	}
+
+	return resource.String()
+}
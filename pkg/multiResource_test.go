@@ -0,0 +1,101 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRuleFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func loadedContents(t *testing.T, resources []Resource) []string {
+	ret := make([]string, len(resources))
+	for i, resource := range resources {
+		data, err := resource.Load()
+		assert.NoError(t, err)
+		ret[i] = string(data)
+	}
+
+	return ret
+}
+
+func TestMultiResourceBundle(t *testing.T) {
+	base := writeRuleFiles(t, map[string]string{
+		"rules/b.grl":        "base b",
+		"rules/a.grl":        "base a",
+		"rules/nested/c.grl": "base c",
+	})
+	overrides := writeRuleFiles(t, map[string]string{
+		"rules/a.grl": "override a",
+		"rules/d.grl": "override d",
+	})
+	bundle := NewMultiResourceBundle(
+		NewFileResourceBundle(base, "**/*.grl"),
+		NewFileResourceBundle(overrides, "**/*.grl"),
+	)
+
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"override a", "base b", "base c", "override d"}, loadedContents(t, resources))
+
+	bundle.Duplicates = DuplicateKeepFirst
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base a", "base b", "base c", "override d"}, loadedContents(t, resources))
+
+	bundle.Duplicates = DuplicateKeepAll
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 5)
+
+	bundle.Duplicates = DuplicateError
+	_, err = bundle.Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rules/a.grl")
+
+	bundle.Duplicates = DuplicateOverride
+	bundle.Order = OrderByPath
+	bundle.Bundles = []ResourceBundle{NewFileResourceBundle(overrides, "**/*.grl"), NewFileResourceBundle(base, "**/*.grl")}
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base a", "base b", "override d", "base c"}, loadedContents(t, resources))
+
+	bundle.PathOf = func(bundle ResourceBundle, resource Resource) string {
+
+		return filepath.Base(resource.(*FileResource).Path)
+	}
+	bundle.Order = OrderByBundle
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base a", "override d", "base b", "base c"}, loadedContents(t, resources))
+
+	bundle.Bundles = append(bundle.Bundles, NewFileResourceBundle(filepath.Join(base, "missing"), "**/*.grl"))
+	_, err = bundle.Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bundle 2")
+}
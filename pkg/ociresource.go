@@ -0,0 +1,570 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OCIManifestMediaType is the media type of the OCI image manifest describing an artifact.
+	OCIManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+	// ociTitleAnnotation names the file of a layer, as set by oras push.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	// ociDockerManifestMediaType is the media type of the Docker image manifest, served by older registries.
+	ociDockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// NewOCIResourceBundle will create a new instance of OCIResourceBundle pulling the artifact of the reference,
+// such as ghcr.io/acme/rules:1.4.0 or ghcr.io/acme/rules@sha256:... .
+// pathPattern are list of file name patterns (glob) to filter the files of the artifact.
+func NewOCIResourceBundle(reference string, pathPattern ...string) *OCIResourceBundle {
+
+	return &OCIResourceBundle{
+		Reference:   reference,
+		PathPattern: pathPattern,
+	}
+}
+
+// OCIResourceBundle is a helper struct to load the GRL files of an artifact pushed to a container registry, such
+// as with oras push. Every layer is a file named by its org.opencontainers.image.title annotation, a layer that is
+// a tar archive, such as a pushed directory, gives the files inside it.
+//
+// The manifest and every layer are checked against their digest. Pin the artifact with a digest reference, or with
+// Digest when the reference is a tag, so only the reviewed, or signed, artifact is ever loaded.
+type OCIResourceBundle struct {
+	// Reference of the artifact, registry/repository:tag or registry/repository@digest.
+	// Without a registry, the artifact is on Docker Hub.
+	Reference string
+	// List Glob like file name pattern, such as **/*.grl. All files are read if empty.
+	PathPattern []string
+	// Digest, if set, is the digest the manifest of the reference must have, such as sha256:3b1f... .
+	Digest string
+	// Username and Password authenticate to the registry or its token service, such as a user and its access token.
+	Username string
+	Password string
+	// PlainHTTP talks to the registry without TLS, such as a local registry at localhost:5000.
+	PlainHTTP bool
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	// Concurrency is the number of layers downloaded at the same time, 8 if not positive.
+	Concurrency int
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// ociReference is a parsed artifact reference.
+type ociReference struct {
+	Registry   string
+	Repository string
+	// Reference is the tag or the digest.
+	Reference string
+}
+
+// ociManifest is the part of the image manifest describing the layers.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// ociDescriptor points to a blob.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ociSession sends the requests of one load, keeping the bearer token given by the registry.
+type ociSession struct {
+	bundle *OCIResourceBundle
+	ref    ociReference
+	base   string
+	mutex  sync.Mutex
+	auth   string
+}
+
+// Load pulls the manifest of the artifact and returns the files of its layers matching the PathPattern,
+// in the order of the layers.
+func (bundle *OCIResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the requests are bound by the context instead of Timeout.
+func (bundle *OCIResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the layers failing to download are reported to onError.
+func (bundle *OCIResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.load(ctx, onError)
+}
+
+// load is the same as LoadContext, the layers failing to download are reported to onError, if not nil.
+func (bundle *OCIResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	ref, err := parseOCIReference(bundle.Reference)
+	if err != nil {
+
+		return nil, err
+	}
+	scheme := "https"
+	if bundle.PlainHTTP {
+		scheme = "http"
+	}
+	session := &ociSession{bundle: bundle, ref: ref, base: scheme + "://" + ref.Registry + "/v2/" + ref.Repository}
+
+	manifest, digest, err := session.manifest(ctx)
+	if err != nil {
+
+		return nil, err
+	}
+	layers := make([]string, 0, len(manifest.Layers))
+	byDigest := make(map[string]ociDescriptor)
+	position := make(map[string]int)
+	for _, layer := range manifest.Layers {
+		if _, ok := byDigest[layer.Digest]; !ok {
+			position[layer.Digest] = len(layers)
+			layers = append(layers, layer.Digest)
+			byDigest[layer.Digest] = layer
+		}
+	}
+	files := make([][]Resource, len(layers))
+	_, err = loadObjects(ctx, layers, bundle.Concurrency, onError, func(layerDigest string) (Resource, error) {
+		layer := byDigest[layerDigest]
+		contextLog(ctx).Debugf("Loading OCI layer %s of %s", layer.Digest, bundle.Reference)
+		// the layer stands for its files when it fails.
+		shell := &OCIResource{Reference: bundle.Reference, Digest: digest, Name: layer.Digest}
+		blob, err := session.get(ctx, session.base+"/blobs/"+layer.Digest, "")
+		if err != nil {
+
+			return shell, err
+		}
+		if err := checkOCIDigest(layer.Digest, blob); err != nil {
+
+			return shell, fmt.Errorf("layer of %s. %w", bundle.Reference, err)
+		}
+		resources, err := bundle.layerFiles(digest, layer, blob)
+		if err != nil {
+
+			return shell, err
+		}
+		files[position[layerDigest]] = resources
+
+		return nil, nil
+	})
+	if err != nil {
+
+		return nil, err
+	}
+	ret := make([]Resource, 0, len(layers))
+	for _, resources := range files {
+		ret = append(ret, resources...)
+	}
+
+	return ret, nil
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use OCIResourceBundle.LoadContext, or OCIResourceBundle.LoadWithHandler to go on without the layers
+// failing to download.
+func (bundle *OCIResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// layerFiles returns the files of the layer matching the PathPattern.
+func (bundle *OCIResourceBundle) layerFiles(manifestDigest string, layer ociDescriptor, blob []byte) ([]Resource, error) {
+	title := layer.Annotations[ociTitleAnnotation]
+	mediaType := layer.MediaType
+	if strings.Contains(mediaType, ".tar") {
+		var reader io.Reader = bytes.NewReader(blob)
+		if strings.HasSuffix(mediaType, "gzip") || bytes.HasPrefix(blob, []byte{0x1f, 0x8b}) {
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+
+				return nil, fmt.Errorf("layer %s of %s is not gzip compressed. got %w", layer.Digest, bundle.Reference, err)
+			}
+			defer gz.Close()
+			reader = gz
+		}
+		names := make([]string, 0)
+		contents := make(map[string][]byte)
+		archive := tar.NewReader(reader)
+		for {
+			header, err := archive.Next()
+			if errors.Is(err, io.EOF) {
+
+				break
+			}
+			if err != nil {
+
+				return nil, fmt.Errorf("layer %s of %s is not a tar archive. got %w", layer.Digest, bundle.Reference, err)
+			}
+			if header.Typeflag != tar.TypeReg {
+
+				continue
+			}
+			content, err := io.ReadAll(archive)
+			if err != nil {
+
+				return nil, err
+			}
+			names = append(names, header.Name)
+			contents[header.Name] = content
+		}
+		matched, err := bundle.match(names)
+		if err != nil {
+
+			return nil, err
+		}
+		ret := make([]Resource, 0, len(matched))
+		for _, name := range matched {
+			ret = append(ret, &OCIResource{
+				Reference: bundle.Reference,
+				Digest:    manifestDigest,
+				Name:      name,
+				Bytes:     contents[name],
+			})
+		}
+
+		return ret, nil
+	}
+
+	if len(title) == 0 {
+		title = layer.Digest
+	}
+	matched, err := bundle.match([]string{title})
+	if err != nil || len(matched) == 0 {
+
+		return nil, err
+	}
+
+	return []Resource{&OCIResource{
+		Reference: bundle.Reference,
+		Digest:    manifestDigest,
+		Name:      title,
+		Bytes:     blob,
+	}}, nil
+}
+
+// match returns the names matching the PathPattern, all of them if it is empty.
+func (bundle *OCIResourceBundle) match(names []string) ([]string, error) {
+	if len(bundle.PathPattern) == 0 {
+
+		return names, nil
+	}
+
+	return matchObjectKeys(names, "", bundle.PathPattern)
+}
+
+// manifest pulls the manifest and returns it with its digest, checked against the reference and the pinned Digest.
+func (session *ociSession) manifest(ctx context.Context) (*ociManifest, string, error) {
+	body, err := session.get(ctx, session.base+"/manifests/"+session.ref.Reference, OCIManifestMediaType+", "+ociDockerManifestMediaType)
+	if err != nil {
+
+		return nil, "", err
+	}
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if strings.Contains(session.ref.Reference, ":") {
+		if err := checkOCIDigest(session.ref.Reference, body); err != nil {
+
+			return nil, "", fmt.Errorf("manifest of %s. %w", session.bundle.Reference, err)
+		}
+	}
+	if len(session.bundle.Digest) > 0 && session.bundle.Digest != digest {
+
+		return nil, "", fmt.Errorf("manifest of %s has digest %s, not the pinned %s", session.bundle.Reference, digest, session.bundle.Digest)
+	}
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(body, manifest); err != nil {
+
+		return nil, "", fmt.Errorf("invalid manifest of %s. got %w", session.bundle.Reference, err)
+	}
+	if len(manifest.MediaType) > 0 && manifest.MediaType != OCIManifestMediaType && manifest.MediaType != ociDockerManifestMediaType {
+
+		return nil, "", fmt.Errorf("manifest of %s has media type %s, an image index or an artifact manifest is not supported", session.bundle.Reference, manifest.MediaType)
+	}
+
+	return manifest, digest, nil
+}
+
+// get sends a GET request and returns the response body. Once challenged by the registry, it authenticates
+// and sends the request again.
+func (session *ociSession) get(ctx context.Context, target, accept string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", accept)
+		}
+		session.mutex.Lock()
+		if len(session.auth) > 0 {
+			req.Header.Set("Authorization", session.auth)
+		}
+		session.mutex.Unlock()
+		resp, err := session.client().Do(req)
+		if err != nil {
+
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			if err := session.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+
+				return nil, err
+			}
+
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			apiErr := &struct {
+				Errors []struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			}{}
+			message := strings.TrimSpace(string(body))
+			if json.Unmarshal(body, apiErr) == nil && len(apiErr.Errors) > 0 {
+				message = apiErr.Errors[0].Code + " " + apiErr.Errors[0].Message
+			}
+
+			return nil, fmt.Errorf("OCI registry %s responded %s to %s. %s", session.ref.Registry, resp.Status, req.URL.Path, message)
+		}
+
+		return body, nil
+	}
+}
+
+// authenticate answers the challenge of the registry, with the credentials for a Basic challenge, or with the
+// token of the token service for a Bearer challenge.
+func (session *ociSession) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseOCIChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if len(session.bundle.Username) == 0 {
+
+			return fmt.Errorf("OCI registry %s requires a username and password", session.ref.Registry)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(session.bundle.Username, session.bundle.Password)
+		session.setAuth(req.Header.Get("Authorization"))
+
+		return nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || len(params["realm"]) == 0 {
+
+			return fmt.Errorf("OCI registry %s gave an invalid token realm %q", session.ref.Registry, params["realm"])
+		}
+		query := realm.Query()
+		if len(params["service"]) > 0 {
+			query.Set("service", params["service"])
+		}
+		scope := params["scope"]
+		if len(scope) == 0 {
+			scope = "repository:" + session.ref.Repository + ":pull"
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+
+			return err
+		}
+		if len(session.bundle.Username) > 0 {
+			req.SetBasicAuth(session.bundle.Username, session.bundle.Password)
+		}
+		resp, err := session.client().Do(req)
+		if err != nil {
+
+			return err
+		}
+		defer resp.Body.Close()
+		token := &struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		if resp.StatusCode != http.StatusOK {
+
+			return fmt.Errorf("token service of OCI registry %s responded %s", session.ref.Registry, resp.Status)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+
+			return fmt.Errorf("invalid token response of OCI registry %s. got %w", session.ref.Registry, err)
+		}
+		if len(token.Token) == 0 {
+			token.Token = token.AccessToken
+		}
+		session.setAuth("Bearer " + token.Token)
+
+		return nil
+	default:
+
+		return fmt.Errorf("OCI registry %s requires an unsupported authentication %q", session.ref.Registry, challenge)
+	}
+}
+
+func (session *ociSession) setAuth(auth string) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	session.auth = auth
+}
+
+func (session *ociSession) client() *http.Client {
+	if session.bundle.HTTPClient != nil {
+
+		return session.bundle.HTTPClient
+	}
+
+	return &http.Client{}
+}
+
+// parseOCIReference splits the reference into its registry, repository and tag or digest.
+func parseOCIReference(reference string) (ociReference, error) {
+	ref := ociReference{}
+	name := reference
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.Reference = name[at+1:]
+		name = name[:at]
+	} else if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		ref.Reference = name[colon+1:]
+		name = name[:colon]
+	}
+	if len(ref.Reference) == 0 {
+		ref.Reference = "latest"
+	}
+	if slash := strings.Index(name, "/"); slash > 0 && strings.ContainsAny(name[:slash], ".:") || strings.HasPrefix(name, "localhost/") {
+		ref.Registry = name[:slash]
+		ref.Repository = name[slash+1:]
+	} else {
+		ref.Registry = "registry-1.docker.io"
+		ref.Repository = name
+		if !strings.Contains(name, "/") {
+			ref.Repository = "library/" + name
+		}
+	}
+	if ref.Registry == "docker.io" || ref.Registry == "index.docker.io" {
+		ref.Registry = "registry-1.docker.io"
+	}
+	if len(ref.Repository) == 0 || strings.ToLower(ref.Repository) != ref.Repository {
+
+		return ociReference{}, fmt.Errorf("invalid OCI reference %q", reference)
+	}
+
+	return ref, nil
+}
+
+// parseOCIChallenge returns the scheme and the parameters of a WWW-Authenticate challenge.
+func parseOCIChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for len(rest) > 0 {
+		key, value, ok := strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if !ok {
+
+			break
+		}
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+
+				break
+			}
+			params[strings.ToLower(strings.TrimSpace(key))] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+
+	return scheme, params
+}
+
+// checkOCIDigest checks the content against its sha256 digest.
+func checkOCIDigest(digest string, content []byte) error {
+	algorithm, encoded, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+
+		return fmt.Errorf("digest %s is not supported, only sha256 is", digest)
+	}
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != encoded {
+
+		return fmt.Errorf("content does not match digest %s", digest)
+	}
+
+	return nil
+}
+
+// OCIResource resource implementation that loaded from a file of an OCI artifact
+type OCIResource struct {
+	Reference string
+	// Digest of the manifest the file was pulled from.
+	Digest string
+	Name   string
+	Bytes  []byte
+}
+
+// String will state the artifact reference and file name.
+func (res *OCIResource) String() string {
+
+	return fmt.Sprintf("From OCI artifact [%s] %s", res.Reference, res.Name)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the artifact from the registry
+// when this method is called, it simply return the loaded data.
+func (res *OCIResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
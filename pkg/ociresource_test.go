@@ -0,0 +1,186 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ociDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newOCIRegistry(t *testing.T, tamper bool) (*httptest.Server, string) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	writer := tar.NewWriter(gz)
+	for name, content := range map[string]string{"rules/b.grl": "rule B", "rules/notes.txt": "not a rule"} {
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, gz.Close())
+
+	blobs := map[string][]byte{}
+	layer := func(mediaType, title string, content []byte) ociDescriptor {
+		digest := ociDigest(content)
+		blobs[digest] = content
+
+		return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content)), Annotations: map[string]string{ociTitleAnnotation: title}}
+	}
+	manifest, err := json.Marshal(ociManifest{MediaType: OCIManifestMediaType, Layers: []ociDescriptor{
+		layer("application/vnd.grule.grl", "a.grl", []byte("rule A")),
+		layer("text/markdown", "README.md", []byte("# rules")),
+		layer("application/vnd.oci.image.layer.v1.tar+gzip", "rules", archive.Bytes()),
+	}})
+	assert.NoError(t, err)
+	digest := ociDigest(manifest)
+	if tamper {
+		blobs[ociDigest([]byte("rule A"))] = []byte("rule Evil")
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			user, password, _ := r.BasicAuth()
+			if user != "robot" || password != "secret" || r.URL.Query().Get("scope") != "repository:acme/rules:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+			fmt.Fprint(w, `{"token":"pull-token"}`)
+		case r.Header.Get("Authorization") != "Bearer pull-token":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test",scope="repository:acme/rules:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`)
+		case r.URL.Path == "/v2/acme/rules/manifests/1.4.0" || r.URL.Path == "/v2/acme/rules/manifests/"+digest:
+			if !strings.Contains(r.Header.Get("Accept"), OCIManifestMediaType) {
+				t.Errorf("Expected the OCI manifest to be accepted but get %s", r.Header.Get("Accept"))
+			}
+			w.Header().Set("Content-Type", OCIManifestMediaType)
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(r.URL.Path, "/v2/acme/rules/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/acme/rules/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`)
+		}
+	}))
+
+	return server, digest
+}
+
+func TestOCIResourceBundle(t *testing.T) {
+	server, digest := newOCIRegistry(t, false)
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+
+	bundle := NewOCIResourceBundle(registry+"/acme/rules:1.4.0", "**/*.grl")
+	bundle.PlainHTTP = true
+	bundle.Username = "robot"
+	bundle.Password = "secret"
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	assert.Equal(t, fmt.Sprintf("From OCI artifact [%s/acme/rules:1.4.0] a.grl", registry), resources[0].String())
+	data, err := resources[1].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule B", string(data))
+	assert.Equal(t, digest, resources[1].(*OCIResource).Digest)
+
+	// pinned by digest reference or by Digest
+	bundle.Reference = registry + "/acme/rules@" + digest
+	resources, err = bundle.Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+	bundle.Reference = registry + "/acme/rules:1.4.0"
+	bundle.Digest = ociDigest([]byte("another manifest"))
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "not the pinned")
+
+	bundle.Digest = ""
+	bundle.Password = "wrong"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "401")
+
+	bundle.Password = "secret"
+	bundle.Reference = registry + "/acme/rules:2.0.0"
+	_, err = bundle.Load()
+	assert.ErrorContains(t, err, "MANIFEST_UNKNOWN")
+}
+
+func TestOCIResourceBundle_TamperedLayer(t *testing.T) {
+	server, _ := newOCIRegistry(t, true)
+	defer server.Close()
+
+	bundle := NewOCIResourceBundle(strings.TrimPrefix(server.URL, "http://")+"/acme/rules:1.4.0", "**/*.grl")
+	bundle.PlainHTTP = true
+	bundle.Username = "robot"
+	bundle.Password = "secret"
+	_, err := bundle.Load()
+	assert.ErrorContains(t, err, "does not match digest")
+
+	failed := make([]string, 0)
+	resources, err := bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		failed = append(failed, res.String())
+
+		return DecisionSkip
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, []string{"From OCI artifact [" + bundle.Reference + "] " + ociDigest([]byte("rule A"))}, failed)
+}
+
+func TestParseOCIReference(t *testing.T) {
+	testData := []struct {
+		reference string
+		expected  ociReference
+	}{
+		{"ghcr.io/acme/rules:1.4.0", ociReference{"ghcr.io", "acme/rules", "1.4.0"}},
+		{"localhost:5000/rules", ociReference{"localhost:5000", "rules", "latest"}},
+		{"acme/rules@sha256:abc", ociReference{"registry-1.docker.io", "acme/rules", "sha256:abc"}},
+		{"rules", ociReference{"registry-1.docker.io", "library/rules", "latest"}},
+		{"docker.io/acme/rules:v1", ociReference{"registry-1.docker.io", "acme/rules", "v1"}},
+	}
+	for _, td := range testData {
+		ref, err := parseOCIReference(td.reference)
+		assert.NoError(t, err)
+		assert.Equal(t, td.expected, ref, td.reference)
+	}
+	_, err := parseOCIReference("ghcr.io/Acme/Rules")
+	assert.Error(t, err)
+}
@@ -0,0 +1,414 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+const (
+	// PercentUnit is the unit of percentages, 10% is 0.10 when multiplied with a number.
+	PercentUnit = "%"
+	// DimensionPercent is the dimension of PercentUnit.
+	DimensionPercent = "percent"
+	// DimensionMass is the dimension of the weight units, its base unit is the gram.
+	DimensionMass = "mass"
+	// DimensionCurrency prefixes the dimension of a currency, every currency is a dimension of its own.
+	DimensionCurrency = "currency:"
+)
+
+// Unit is a unit of measure, quantities of the same dimension can be converted into each other.
+type Unit struct {
+	Name      string
+	Dimension string
+	// Factor converts a value in this unit into the base unit of the dimension.
+	Factor float64
+}
+
+var (
+	quantityType = reflect.TypeOf(Quantity{})
+
+	// durationSuffix matches the names the GRL lexer reads as the end of a duration, such as ms in 5ms or m30s in
+	// 5m30s, a quantity can not be written with them.
+	durationSuffix = regexp.MustCompile(`^(ns|us|\x{00B5}s|ms|s|m|h)([0-9]+(\.[0-9]+)?(ns|us|\x{00B5}s|ms|s|m|h))*$`)
+
+	// defaultUnits are the units of the registries that are nil, RegisterUnit adds into them.
+	defaultUnits = NewUnitRegistry()
+)
+
+// UnitRegistry keeps the units of measure by name. A nil registry is the default one of the process, so two
+// knowledge libraries may each have units of their own, see ast.KnowledgeLibrary.Units.
+type UnitRegistry struct {
+	mutex sync.RWMutex
+	units map[string]Unit
+}
+
+// NewUnitRegistry create a UnitRegistry knowing the built in units, the percent and the weight units.
+func NewUnitRegistry() *UnitRegistry {
+
+	return &UnitRegistry{
+		units: map[string]Unit{
+			PercentUnit: {Name: PercentUnit, Dimension: DimensionPercent, Factor: 1},
+			"mg":        {Name: "mg", Dimension: DimensionMass, Factor: 0.001},
+			"g":         {Name: "g", Dimension: DimensionMass, Factor: 1},
+			"kg":        {Name: "kg", Dimension: DimensionMass, Factor: 1000},
+			"t":         {Name: "t", Dimension: DimensionMass, Factor: 1000000},
+			"oz":        {Name: "oz", Dimension: DimensionMass, Factor: 28.349523125},
+			"lb":        {Name: "lb", Dimension: DimensionMass, Factor: 453.59237},
+		},
+	}
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *UnitRegistry) or() *UnitRegistry {
+	if registry == nil {
+
+		return defaultUnits
+	}
+
+	return registry
+}
+
+// Register adds a unit that can be used as a GRL literal suffix, such as Register("km", "length", 1000).
+// The suffixes of the durations, such as ms, s, m or h, can not be units since 5m is the duration of five minutes.
+func (registry *UnitRegistry) Register(name, dimension string, factor float64) error {
+	if len(name) == 0 || len(dimension) == 0 || factor <= 0 {
+
+		return fmt.Errorf("unit needs a name, a dimension and a positive factor")
+	}
+	if durationSuffix.MatchString(name) {
+
+		return fmt.Errorf("unit %s would be read as a duration, such as 5%s", name, name)
+	}
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, exist := registry.units[name]; exist || isCurrencyCode(name) {
+
+		return fmt.Errorf("unit %s is already defined", name)
+	}
+	registry.units[name] = Unit{Name: name, Dimension: dimension, Factor: factor}
+
+	return nil
+}
+
+// Lookup returns the unit of the specified name. Any three upper case letters, such as USD, is a currency.
+func (registry *UnitRegistry) Lookup(name string) (Unit, bool) {
+	if isCurrencyCode(name) {
+
+		return Unit{Name: name, Dimension: DimensionCurrency + name, Factor: 1}, true
+	}
+	registry = registry.or()
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	unit, ok := registry.units[name]
+
+	return unit, ok
+}
+
+// NewQuantity create new instance of Quantity, the unit must be known to this registry.
+func (registry *UnitRegistry) NewQuantity(value float64, unit string) (Quantity, error) {
+	if _, ok := registry.Lookup(unit); !ok {
+
+		return Quantity{}, fmt.Errorf("unknown unit %s", unit)
+	}
+
+	return Quantity{Value: value, Unit: unit}, nil
+}
+
+// Convert converts the quantity into another unit of the same dimension, both units being looked up in this registry.
+func (registry *UnitRegistry) Convert(q Quantity, unit string) (Quantity, error) {
+	from, ok := registry.Lookup(q.Unit)
+	if !ok {
+
+		return Quantity{}, fmt.Errorf("unknown unit %s", q.Unit)
+	}
+	to, ok := registry.Lookup(unit)
+	if !ok {
+
+		return Quantity{}, fmt.Errorf("unknown unit %s", unit)
+	}
+	if from.Dimension != to.Dimension {
+
+		return Quantity{}, fmt.Errorf("can not convert %s into %s", q.String(), unit)
+	}
+	if from.Name == to.Name {
+
+		return q, nil
+	}
+
+	return Quantity{Value: q.Value * from.Factor / to.Factor, Unit: unit}, nil
+}
+
+// RegisterUnit adds a unit into the default registry, such as RegisterUnit("km", "length", 1000).
+// The suffixes of the durations, such as ms, s, m or h, can not be units since 5m is the duration of five minutes.
+func RegisterUnit(name, dimension string, factor float64) error {
+
+	return defaultUnits.Register(name, dimension, factor)
+}
+
+// LookupUnit returns the unit of the specified name from the default registry. Any three upper case letters,
+// such as USD, is a currency.
+func LookupUnit(name string) (Unit, bool) {
+
+	return defaultUnits.Lookup(name)
+}
+
+func isCurrencyCode(name string) bool {
+	if len(name) != 3 {
+
+		return false
+	}
+	for _, c := range name {
+		if c < 'A' || c > 'Z' {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// Quantity is a number with a unit, it is the value of GRL literals such as 10%, 5kg or 100 USD.
+// Facts may use it for their fields so rules can compare and compute with them.
+type Quantity struct {
+	Value float64
+	Unit  string
+}
+
+// NewQuantity create new instance of Quantity, the unit must be known to LookupUnit.
+func NewQuantity(value float64, unit string) (Quantity, error) {
+
+	return defaultUnits.NewQuantity(value, unit)
+}
+
+// String returns the quantity as written in GRL.
+func (q Quantity) String() string {
+	value := strconv.FormatFloat(q.Value, 'f', -1, 64)
+	if isCurrencyCode(q.Unit) {
+
+		return value + " " + q.Unit
+	}
+
+	return value + q.Unit
+}
+
+// In converts the quantity into another unit of the same dimension, the units are those of the default registry.
+func (q Quantity) In(unit string) (Quantity, error) {
+
+	return defaultUnits.Convert(q, unit)
+}
+
+// IsQuantity tells whether the value is a Quantity.
+func IsQuantity(val reflect.Value) bool {
+
+	return val.IsValid() && val.Type() == quantityType
+}
+
+// quantity operators, used in the error messages.
+const (
+	opMul    = "*"
+	opDiv    = "/"
+	opMod    = "%"
+	opAdd    = "+"
+	opSub    = "-"
+	opBitAnd = "&"
+	opBitOr  = "|"
+	opGT     = ">"
+	opLT     = "<"
+	opGTE    = ">="
+	opLTE    = "<="
+	opEQ     = "=="
+	opNEQ    = "!="
+)
+
+// operand is one side of an operation involving quantities. A plain number has no unit.
+type operand struct {
+	value   float64
+	unit    Unit
+	hasUnit bool
+}
+
+func (o operand) isPercent() bool {
+
+	return o.hasUnit && o.unit.Dimension == DimensionPercent
+}
+
+func (o operand) String() string {
+	if !o.hasUnit {
+
+		return strconv.FormatFloat(o.value, 'f', -1, 64)
+	}
+
+	return Quantity{Value: o.value, Unit: o.unit.Name}.String()
+}
+
+// operand returns the operand of the value, the unit of a quantity is looked up in this registry.
+func (registry *UnitRegistry) operand(val reflect.Value) (operand, error) {
+	if IsQuantity(val) {
+		q := val.Interface().(Quantity)
+		unit, ok := registry.Lookup(q.Unit)
+		if !ok {
+
+			return operand{}, fmt.Errorf("unknown unit %s", q.Unit)
+		}
+
+		return operand{value: q.Value, unit: unit, hasUnit: true}, nil
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return operand{value: float64(val.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		return operand{value: float64(val.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+
+		return operand{value: val.Float()}, nil
+	}
+	if !val.IsValid() {
+
+		return operand{}, fmt.Errorf("can not use nil with a quantity")
+	}
+
+	return operand{}, fmt.Errorf("can not use data type of %s with a quantity", val.Kind().String())
+}
+
+func (o operand) result() reflect.Value {
+	if !o.hasUnit {
+
+		return reflect.ValueOf(o.value)
+	}
+
+	return reflect.ValueOf(Quantity{Value: o.value, Unit: o.unit.Name})
+}
+
+// Evaluate evaluates the operator, such as "+" or ">=", where at least one side is a Quantity, like
+// EvaluateAddition or EvaluateGreaterThanEqual do, the units being looked up in this registry.
+func (registry *UnitRegistry) Evaluate(operator string, left, right reflect.Value) (reflect.Value, error) {
+
+	return registry.evaluate(operator, GetValueElem(left), GetValueElem(right))
+}
+
+// evaluate evaluates an operation where at least one side is a Quantity.
+// Quantities of different dimensions never mix, values of the same dimension are converted into the unit of the left
+// side. A percentage multiplied or divided with anything else acts as its ratio, so Price * 10% is a tenth of Price, but
+// adding percentage and a number is an error as it is ambiguous.
+func (registry *UnitRegistry) evaluate(operator string, left, right reflect.Value) (reflect.Value, error) {
+	l, err := registry.operand(left)
+	if err != nil {
+
+		return reflect.ValueOf(nil), err
+	}
+	r, err := registry.operand(right)
+	if err != nil {
+
+		return reflect.ValueOf(nil), err
+	}
+	mismatch := func() (reflect.Value, error) {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not evaluate %s %s %s, units do not match", l.String(), operator, r.String())
+	}
+
+	switch operator {
+	case opMul:
+		switch {
+		case l.isPercent() && (r.isPercent() || !r.hasUnit):
+
+			return operand{value: l.value * r.value / 100, unit: r.unit, hasUnit: r.hasUnit}.result(), nil
+		case l.isPercent():
+
+			return operand{value: r.value * l.value / 100, unit: r.unit, hasUnit: true}.result(), nil
+		case r.isPercent():
+
+			return operand{value: l.value * r.value / 100, unit: l.unit, hasUnit: l.hasUnit}.result(), nil
+		case l.hasUnit && r.hasUnit:
+
+			return mismatch()
+		case l.hasUnit:
+
+			return operand{value: l.value * r.value, unit: l.unit, hasUnit: true}.result(), nil
+		default:
+
+			return operand{value: l.value * r.value, unit: r.unit, hasUnit: true}.result(), nil
+		}
+	case opDiv:
+		if r.value == 0 {
+
+			return reflect.ValueOf(nil), fmt.Errorf("can not evaluate %s %s %s, division by zero", l.String(), operator, r.String())
+		}
+		switch {
+		case l.hasUnit && r.hasUnit && l.unit.Dimension == r.unit.Dimension:
+
+			return reflect.ValueOf(l.value * l.unit.Factor / (r.value * r.unit.Factor)), nil
+		case r.isPercent():
+
+			return operand{value: l.value * 100 / r.value, unit: l.unit, hasUnit: l.hasUnit}.result(), nil
+		case l.hasUnit && !r.hasUnit:
+
+			return operand{value: l.value / r.value, unit: l.unit, hasUnit: true}.result(), nil
+		default:
+
+			return mismatch()
+		}
+	case opAdd, opSub:
+		if !l.hasUnit || !r.hasUnit || l.unit.Dimension != r.unit.Dimension {
+			if l.isPercent() != r.isPercent() {
+
+				return reflect.ValueOf(nil), fmt.Errorf("can not evaluate %s %s %s, to change a value by a percentage multiply it, such as Value * (100%% %s %s)", l.String(), operator, r.String(), operator, r.String())
+			}
+
+			return mismatch()
+		}
+		rv := r.value * r.unit.Factor / l.unit.Factor
+		if operator == opSub {
+			rv = -rv
+		}
+
+		return operand{value: l.value + rv, unit: l.unit, hasUnit: true}.result(), nil
+	case opGT, opLT, opGTE, opLTE, opEQ, opNEQ:
+		if !l.hasUnit || !r.hasUnit || l.unit.Dimension != r.unit.Dimension {
+
+			return mismatch()
+		}
+		lv, rv := l.value*l.unit.Factor, r.value*r.unit.Factor
+		switch operator {
+		case opGT:
+
+			return reflect.ValueOf(lv > rv), nil
+		case opLT:
+
+			return reflect.ValueOf(lv < rv), nil
+		case opGTE:
+
+			return reflect.ValueOf(lv >= rv), nil
+		case opLTE:
+
+			return reflect.ValueOf(lv <= rv), nil
+		case opEQ:
+
+			return reflect.ValueOf(lv == rv), nil
+		default:
+
+			return reflect.ValueOf(lv != rv), nil
+		}
+	}
+
+	return reflect.ValueOf(nil), fmt.Errorf("operator %s is not supported for quantities", operator)
+}
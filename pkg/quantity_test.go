@@ -0,0 +1,128 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityArithmetic(t *testing.T) {
+	q := func(value float64, unit string) reflect.Value {
+
+		return reflect.ValueOf(Quantity{Value: value, Unit: unit})
+	}
+	n := reflect.ValueOf
+	testData := []struct {
+		evaluate func(left, right reflect.Value) (reflect.Value, error)
+		left     reflect.Value
+		right    reflect.Value
+		expect   interface{}
+	}{
+		{EvaluateMultiplication, n(int64(200)), q(10, "%"), float64(20)},
+		{EvaluateMultiplication, q(10, "%"), n(200.0), float64(20)},
+		{EvaluateMultiplication, q(100, "USD"), q(10, "%"), Quantity{10, "USD"}},
+		{EvaluateMultiplication, q(50, "%"), q(10, "%"), Quantity{5, "%"}},
+		{EvaluateMultiplication, n(int64(3)), q(2, "kg"), Quantity{6, "kg"}},
+		{EvaluateDivision, q(6, "kg"), n(int64(3)), Quantity{2, "kg"}},
+		{EvaluateDivision, q(3, "kg"), q(500, "g"), float64(6)},
+		{EvaluateDivision, n(int64(50)), q(50, "%"), float64(100)},
+		{EvaluateAddition, q(1, "kg"), q(500, "g"), Quantity{1.5, "kg"}},
+		{EvaluateSubtraction, q(10, "USD"), q(2.5, "USD"), Quantity{7.5, "USD"}},
+		{EvaluateAddition, q(10, "%"), q(5, "%"), Quantity{15, "%"}},
+		{EvaluateGreaterThan, q(1, "kg"), q(999, "g"), true},
+		{EvaluateLesserThanEqual, q(1, "lb"), q(16, "oz"), true},
+		{EvaluateEqual, q(1000, "mg"), q(1, "g"), true},
+		{EvaluateNotEqual, q(1, "USD"), q(1, "USD"), false},
+	}
+	for i, td := range testData {
+		ret, err := td.evaluate(td.left, td.right)
+		if assert.NoError(t, err, "test %d", i) {
+			if expected, ok := td.expect.(Quantity); ok {
+				actual := ret.Interface().(Quantity)
+				assert.Equal(t, expected.Unit, actual.Unit, "test %d", i)
+				assert.InDelta(t, expected.Value, actual.Value, 1e-9, "test %d", i)
+			} else {
+				assert.Equal(t, td.expect, ret.Interface(), "test %d", i)
+			}
+		}
+	}
+
+	failures := []struct {
+		evaluate func(left, right reflect.Value) (reflect.Value, error)
+		left     reflect.Value
+		right    reflect.Value
+	}{
+		{EvaluateAddition, q(1, "kg"), n(int64(1))},
+		{EvaluateAddition, n(100.0), q(10, "%")},
+		{EvaluateAddition, q(1, "USD"), q(1, "EUR")},
+		{EvaluateMultiplication, q(1, "kg"), q(1, "kg")},
+		{EvaluateDivision, n(int64(1)), q(1, "kg")},
+		{EvaluateDivision, q(1, "kg"), n(int64(0))},
+		{EvaluateModulo, q(5, "kg"), n(int64(2))},
+		{EvaluateGreaterThan, q(1, "kg"), n(int64(0))},
+		{EvaluateEqual, q(1, "kg"), n("1kg")},
+	}
+	for i, td := range failures {
+		_, err := td.evaluate(td.left, td.right)
+		assert.Error(t, err, "failure %d", i)
+	}
+}
+
+func TestQuantityUnits(t *testing.T) {
+	_, err := NewQuantity(1, "parsec")
+	assert.Error(t, err)
+	assert.NoError(t, RegisterUnit("parsec", "length", 3.0857e16))
+	assert.Error(t, RegisterUnit("parsec", "length", 1))
+	assert.Error(t, RegisterUnit("IDR", "currency", 1))
+	for _, name := range []string{"ns", "us", "\u00B5s", "ms", "s", "m", "h", "m30s", "h1.5m"} {
+		assert.Error(t, RegisterUnit(name, "length", 1), name)
+	}
+	assert.NoError(t, RegisterUnit("mi", "length", 1609.344))
+
+	quantity, err := NewQuantity(2, "kg")
+	assert.NoError(t, err)
+	pounds, err := quantity.In("lb")
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.40924, pounds.Value, 1e-5)
+	_, err = quantity.In("parsec")
+	assert.Error(t, err)
+
+	assert.Equal(t, "2kg", quantity.String())
+	assert.Equal(t, "10.5%", Quantity{10.5, "%"}.String())
+	assert.Equal(t, "100 USD", Quantity{100, "USD"}.String())
+}
+
+func TestUnitRegistry(t *testing.T) {
+	registry := NewUnitRegistry()
+	assert.NoError(t, registry.Register("league", "length", 4828.032))
+	_, ok := LookupUnit("league")
+	assert.False(t, ok, "the units of a registry are not known to the others")
+	_, err := NewQuantity(1, "league")
+	assert.Error(t, err)
+
+	quantity, err := registry.NewQuantity(2, "league")
+	assert.NoError(t, err)
+	_, err = registry.NewQuantity(1, "parsec")
+	assert.Error(t, err)
+
+	sum, err := registry.Evaluate("+", reflect.ValueOf(quantity), reflect.ValueOf(Quantity{Value: 1, Unit: "league"}))
+	assert.NoError(t, err)
+	assert.Equal(t, Quantity{Value: 3, Unit: "league"}, sum.Interface())
+	_, err = defaultUnits.Evaluate("+", reflect.ValueOf(quantity), reflect.ValueOf(Quantity{Value: 1, Unit: "league"}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,570 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+// RedisOptions tells how to connect to a Redis server.
+type RedisOptions struct {
+	// Addr is the host:port of the server, such as localhost:6379.
+	Addr string
+	// Username and Password authenticate the connection, Username is empty for the default user.
+	Username string
+	Password string
+	// DB is the database number selected after connecting.
+	DB int
+	// TLS, if set, connects with TLS using this configuration.
+	TLS *tls.Config
+}
+
+// NewRedisResource will create a new Resource reading the GRL stored under the key of a Redis server.
+func NewRedisResource(options RedisOptions, key string) *RedisResource {
+
+	return &RedisResource{
+		Options: options,
+		Key:     key,
+	}
+}
+
+// NewRedisHashResource will create a new Resource reading the GRL stored in the field of the hash at the key.
+func NewRedisHashResource(options RedisOptions, key, field string) *RedisResource {
+
+	return &RedisResource{
+		Options: options,
+		Key:     key,
+		Field:   field,
+	}
+}
+
+// RedisResource is a resource reading the GRL stored in a string key, or in a field of a hash key, of a Redis server.
+type RedisResource struct {
+	Options RedisOptions
+	Key     string
+	// Field of the hash at Key holding the GRL, if Key is a hash.
+	Field string
+	Bytes []byte
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// String will state the key and the field of the resource.
+func (res *RedisResource) String() string {
+	if len(res.Field) > 0 {
+
+		return fmt.Sprintf("Redis resource at %s key %s field %s", res.Options.Addr, res.Key, res.Field)
+	}
+
+	return fmt.Sprintf("Redis resource at %s key %s", res.Options.Addr, res.Key)
+}
+
+// Load will load the resource into byte array. This resource will cache the obtained result byte arrays.
+// So calling this function multiple times only reads the key once at the first time.
+// The read times out after Timeout, use LoadContext to choose the deadline.
+func (res *RedisResource) Load() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(res.Timeout))
+	defer cancel()
+
+	return res.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the read is bound by the context instead of Timeout.
+func (res *RedisResource) LoadContext(ctx context.Context) ([]byte, error) {
+	if res.Bytes != nil {
+
+		return res.Bytes, nil
+	}
+	conn, err := dialRedis(ctx, res.Options)
+	if err != nil {
+
+		return nil, err
+	}
+	defer conn.Close()
+	var reply interface{}
+	if len(res.Field) > 0 {
+		reply, err = conn.do("HGET", res.Key, res.Field)
+	} else {
+		reply, err = conn.do("GET", res.Key)
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+
+		return nil, fmt.Errorf("%s does not exist", res)
+	}
+	res.Bytes = data
+
+	return res.Bytes, nil
+}
+
+// NewRedisResourceBundle will create a new instance of RedisResourceBundle reading the keys matching the key
+// patterns, such as "rules:*", using the pattern syntax of the Redis SCAN command.
+func NewRedisResourceBundle(options RedisOptions, keyPattern ...string) *RedisResourceBundle {
+
+	return &RedisResourceBundle{
+		Options:    options,
+		KeyPattern: keyPattern,
+	}
+}
+
+// RedisResourceBundle is a helper struct to load the GRL stored in the keys of a Redis server. A string key is a
+// resource, every field of a hash key is a resource. Watch reloads them every time one of the keys is changed.
+//
+// Watch subscribes to the keyspace notifications, which the server only sends once enabled, such as with
+// CONFIG SET notify-keyspace-events K$hg .
+type RedisResourceBundle struct {
+	Options RedisOptions
+	// KeyPattern are the patterns of the keys to read, in the syntax of the Redis SCAN command, such as rules:*.
+	KeyPattern []string
+	// List Glob like field pattern of the hashes to read, such as *.grl. All fields are read if empty.
+	FieldPattern []string
+	// RetryInterval is the delay before Watch connects again after an error, 5 seconds if zero.
+	RetryInterval time.Duration
+	// Timeout bounds Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// Load scans the keys matching the KeyPattern and returns the GRL they hold, in the order of their key and field.
+func (bundle *RedisResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the reads are bound by the context instead of Timeout.
+func (bundle *RedisResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the keys failing to be read are reported to onError.
+func (bundle *RedisResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.load(ctx, onError)
+}
+
+// load reads the keys matching the KeyPattern, the keys failing to be read are reported to onError, if not nil.
+func (bundle *RedisResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	conn, err := dialRedis(ctx, bundle.Options)
+	if err != nil {
+
+		return nil, err
+	}
+	defer conn.Close()
+
+	keys, err := bundle.scan(conn)
+	if err != nil {
+
+		return nil, err
+	}
+	ret := make([]Resource, 0, len(keys))
+	for _, key := range keys {
+		var resources []Resource
+		skipped, err := retryLoad(ctx, &RedisResource{Options: bundle.Options, Key: key}, onError, func() error {
+			var err error
+			resources, err = bundle.key(ctx, conn, key)
+
+			return err
+		})
+		if err != nil {
+
+			return nil, err
+		}
+		if !skipped {
+			ret = append(ret, resources...)
+		}
+	}
+
+	return ret, nil
+}
+
+// key returns the GRL held by the key, a string or the fields of a hash.
+func (bundle *RedisResourceBundle) key(ctx context.Context, conn *redisConn, key string) ([]Resource, error) {
+	reply, err := conn.do("TYPE", key)
+	if err != nil {
+
+		return nil, err
+	}
+	switch reply {
+	case "string":
+		reply, err := conn.do("GET", key)
+		if err != nil {
+
+			return nil, err
+		}
+		data, ok := reply.([]byte)
+		if !ok {
+
+			return nil, nil
+		}
+		contextLog(ctx).Debugf("Loading Redis key %s", key)
+
+		return []Resource{&RedisResource{Options: bundle.Options, Key: key, Bytes: data}}, nil
+	case "hash":
+
+		return bundle.hash(conn, key)
+	case "none":
+		// deleted since the scan
+	default:
+		contextLog(ctx).Warnf("Skipping Redis key %s of type %v, only strings and hashes hold GRL", key, reply)
+	}
+
+	return nil, nil
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use RedisResourceBundle.LoadContext, or RedisResourceBundle.LoadWithHandler to go on without the keys
+// failing to be read.
+func (bundle *RedisResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// Watch loads the resources and calls onReload with them, then subscribes to the keyspace notifications of the
+// keys and calls onReload again after every change, until the context is done. A failed reload is given to
+// onReload with the error, and retried after the RetryInterval.
+func (bundle *RedisResourceBundle) Watch(ctx context.Context, onReload func(resources []Resource, err error)) error {
+	retry := bundle.RetryInterval
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	for {
+		err := bundle.watch(ctx, onReload)
+		if ctx.Err() != nil {
+
+			return ctx.Err()
+		}
+		contextLog(ctx).Warnf("Watch of Redis %s failed, retrying. %v", bundle.Options.Addr, err)
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// watch subscribes to the keyspace notifications, then loads the resources and loads them again after every
+// notification, until the subscription fails. Subscribing first, no change made during a load is missed.
+func (bundle *RedisResourceBundle) watch(ctx context.Context, onReload func(resources []Resource, err error)) error {
+	conn, err := dialRedis(ctx, bundle.Options)
+	if err != nil {
+		onReload(nil, err)
+
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	args := make([]string, 0, len(bundle.KeyPattern)+1)
+	args = append(args, "PSUBSCRIBE")
+	for _, pattern := range bundle.KeyPattern {
+		args = append(args, fmt.Sprintf("__keyspace@%d__:%s", bundle.Options.DB, pattern))
+	}
+	if err := conn.send(args...); err != nil {
+
+		return err
+	}
+	for range bundle.KeyPattern {
+		if _, err := conn.receive(); err != nil {
+
+			return err
+		}
+	}
+	// the subscription blocks the connection, no deadline applies to the wait for the notifications.
+	_ = conn.conn.SetDeadline(time.Time{})
+
+	changes := make(chan bool, 1)
+	failed := make(chan error, 1)
+	go func() {
+		for {
+			message, err := conn.receive()
+			if err != nil {
+				failed <- err
+
+				return
+			}
+			if fields, ok := message.([]interface{}); ok && len(fields) == 4 {
+				kind, _ := fields[0].([]byte)
+				if string(kind) != "pmessage" {
+
+					continue
+				}
+				contextLog(ctx).Debugf("Redis key changed, %s %s", fields[2], fields[3])
+				select {
+				case changes <- true:
+				default:
+				}
+			}
+		}
+	}()
+	for {
+		resources, err := bundle.LoadContext(ctx)
+		if ctx.Err() != nil {
+
+			return ctx.Err()
+		}
+		onReload(resources, err)
+		select {
+		case <-changes:
+		case err := <-failed:
+
+			return err
+		}
+	}
+}
+
+// scan returns the keys matching the KeyPattern, sorted.
+func (bundle *RedisResourceBundle) scan(conn *redisConn) ([]string, error) {
+	unique := make(map[string]bool)
+	for _, pattern := range bundle.KeyPattern {
+		cursor := "0"
+		for {
+			reply, err := conn.do("SCAN", cursor, "MATCH", pattern, "COUNT", "500")
+			if err != nil {
+
+				return nil, err
+			}
+			page, ok := reply.([]interface{})
+			if !ok || len(page) != 2 {
+
+				return nil, fmt.Errorf("invalid reply of Redis SCAN. got %v", reply)
+			}
+			next, _ := page[0].([]byte)
+			keys, _ := page[1].([]interface{})
+			for _, key := range keys {
+				if name, ok := key.([]byte); ok {
+					unique[string(name)] = true
+				}
+			}
+			cursor = string(next)
+			if cursor == "0" || len(cursor) == 0 {
+
+				break
+			}
+		}
+	}
+	ret := make([]string, 0, len(unique))
+	for key := range unique {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+// hash returns the fields of the hash matching the FieldPattern, sorted.
+func (bundle *RedisResourceBundle) hash(conn *redisConn, key string) ([]Resource, error) {
+	reply, err := conn.do("HGETALL", key)
+	if err != nil {
+
+		return nil, err
+	}
+	pairs, _ := reply.([]interface{})
+	values := make(map[string][]byte, len(pairs)/2)
+	fields := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		field, _ := pairs[i].([]byte)
+		value, _ := pairs[i+1].([]byte)
+		matched := len(bundle.FieldPattern) == 0
+		for _, pattern := range bundle.FieldPattern {
+			ok, err := doublestar.Match(pattern, string(field))
+			if err != nil {
+
+				return nil, err
+			}
+			if ok {
+				matched = true
+
+				break
+			}
+		}
+		if matched {
+			fields = append(fields, string(field))
+			values[string(field)] = value
+		}
+	}
+	sort.Strings(fields)
+	ret := make([]Resource, 0, len(fields))
+	for _, field := range fields {
+		logger.Log.Debugf("Loading Redis key %s field %s", key, field)
+		ret = append(ret, &RedisResource{Options: bundle.Options, Key: key, Field: field, Bytes: values[field]})
+	}
+
+	return ret, nil
+}
+
+// redisConn is a connection speaking the RESP protocol of Redis.
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialRedis connects to the server, authenticates and selects the database. The connection is bound to the
+// deadline of the context.
+func dialRedis(ctx context.Context, options RedisOptions) (*redisConn, error) {
+	dialer := &net.Dialer{}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if options.TLS != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: options.TLS}).DialContext(ctx, "tcp", options.Addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", options.Addr)
+	}
+	if err != nil {
+
+		return nil, fmt.Errorf("error while connecting to Redis %s. got %w", options.Addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	ret := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	if len(options.Password) > 0 {
+		args := []string{"AUTH", options.Password}
+		if len(options.Username) > 0 {
+			args = []string{"AUTH", options.Username, options.Password}
+		}
+		if _, err := ret.do(args...); err != nil {
+			ret.Close()
+
+			return nil, err
+		}
+	}
+	if options.DB != 0 {
+		if _, err := ret.do("SELECT", strconv.Itoa(options.DB)); err != nil {
+			ret.Close()
+
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// Close closes the connection.
+func (conn *redisConn) Close() error {
+
+	return conn.conn.Close()
+}
+
+// do sends the command and returns its reply.
+func (conn *redisConn) do(args ...string) (interface{}, error) {
+	if err := conn.send(args...); err != nil {
+
+		return nil, err
+	}
+
+	return conn.receive()
+}
+
+// send writes the command as an array of bulk strings.
+func (conn *redisConn) send(args ...string) error {
+	var command strings.Builder
+	command.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		command.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	_, err := io.WriteString(conn.conn, command.String())
+
+	return err
+}
+
+// receive reads a reply. A simple string is a string, a bulk string is a []byte, nil if it does not exist,
+// an integer is an int64 and an array is a []interface{}. An error reply is returned as an error.
+func (conn *redisConn) receive() (interface{}, error) {
+	line, err := conn.reader.ReadString('\n')
+	if err != nil {
+
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+
+		return nil, fmt.Errorf("invalid reply of Redis, empty line")
+	}
+	switch line[0] {
+	case '+':
+
+		return line[1:], nil
+	case '-':
+
+		return nil, errors.New("Redis responded " + line[1:])
+	case ':':
+
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(conn.reader, data); err != nil {
+
+			return nil, err
+		}
+
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+
+			return nil, err
+		}
+		ret := make([]interface{}, size)
+		for i := range ret {
+			ret[i], err = conn.receive()
+			if err != nil {
+
+				return nil, err
+			}
+		}
+
+		return ret, nil
+	}
+
+	return nil, fmt.Errorf("invalid reply of Redis %q", line)
+}
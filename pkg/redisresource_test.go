@@ -0,0 +1,222 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis serves the commands used by the Redis resources, keeping strings and hashes in memory.
+type fakeRedis struct {
+	listener    net.Listener
+	mutex       sync.Mutex
+	data        map[string]interface{}
+	subscribers []net.Conn
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := &fakeRedis{listener: listener, data: make(map[string]interface{})}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (server *fakeRedis) set(key string, value interface{}) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.data[key] = value
+	channel := "__keyspace@0__:" + key
+	for _, conn := range server.subscribers {
+		_, _ = fmt.Fprintf(conn, "*4\r\n$8\r\npmessage\r\n$16\r\n__keyspace@0__:*\r\n$%d\r\n%s\r\n$3\r\nset\r\n", len(channel), channel)
+	}
+}
+
+func (server *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	for {
+		command, err := reader.receive()
+		if err != nil {
+
+			return
+		}
+		args := make([]string, 0)
+		for _, arg := range command.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+		server.mutex.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[len(args)-1] == "secret" {
+				_, _ = fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				_, _ = fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "SCAN":
+			keys := make([]string, 0)
+			for key := range server.data {
+				if ok, _ := path.Match(args[3], key); ok {
+					keys = append(keys, key)
+				}
+			}
+			_, _ = fmt.Fprintf(conn, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+			for _, key := range keys {
+				_, _ = fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(key), key)
+			}
+		case "TYPE":
+			switch server.data[args[1]].(type) {
+			case string:
+				_, _ = fmt.Fprint(conn, "+string\r\n")
+			case map[string]string:
+				_, _ = fmt.Fprint(conn, "+hash\r\n")
+			case int:
+				_, _ = fmt.Fprint(conn, "+list\r\n")
+			default:
+				_, _ = fmt.Fprint(conn, "+none\r\n")
+			}
+		case "GET":
+			if value, ok := server.data[args[1]].(string); ok {
+				_, _ = fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				_, _ = fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "HGET":
+			if value, ok := server.data[args[1]].(map[string]string)[args[2]]; ok {
+				_, _ = fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				_, _ = fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "HGETALL":
+			hash := server.data[args[1]].(map[string]string)
+			_, _ = fmt.Fprintf(conn, "*%d\r\n", len(hash)*2)
+			for field, value := range hash {
+				_, _ = fmt.Fprintf(conn, "$%d\r\n%s\r\n$%d\r\n%s\r\n", len(field), field, len(value), value)
+			}
+		case "PSUBSCRIBE":
+			for i, pattern := range args[1:] {
+				_, _ = fmt.Fprintf(conn, "*3\r\n$10\r\npsubscribe\r\n$%d\r\n%s\r\n:%d\r\n", len(pattern), pattern, i+1)
+			}
+			server.subscribers = append(server.subscribers, conn)
+		default:
+			_, _ = fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		server.mutex.Unlock()
+	}
+}
+
+func TestRedisResource(t *testing.T) {
+	server := newFakeRedis(t)
+	defer server.listener.Close()
+	server.set("rules:a", "rule A {}")
+	server.set("rules:b", map[string]string{"one.grl": "rule One {}", "two.grl": "rule Two {}", "notes.txt": "notes"})
+	server.set("rules:list", 1)
+	server.set("other", "rule Other {}")
+	options := RedisOptions{Addr: server.listener.Addr().String(), Password: "secret"}
+
+	data, err := NewRedisResource(options, "rules:a").Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A {}", string(data))
+	data, err = NewRedisHashResource(options, "rules:b", "two.grl").Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule Two {}", string(data))
+	_, err = NewRedisResource(options, "rules:missing").Load()
+	assert.Error(t, err)
+	_, err = NewRedisResource(RedisOptions{Addr: options.Addr, Password: "wrong"}, "rules:a").Load()
+	assert.Error(t, err)
+
+	bundle := NewRedisResourceBundle(options, "rules:*")
+	bundle.FieldPattern = []string{"*.grl"}
+	resources, err := bundle.Load()
+	assert.NoError(t, err)
+	names := make([]string, 0)
+	for _, resource := range resources {
+		names = append(names, resource.String())
+	}
+	assert.Equal(t, []string{
+		"Redis resource at " + options.Addr + " key rules:a",
+		"Redis resource at " + options.Addr + " key rules:b field one.grl",
+		"Redis resource at " + options.Addr + " key rules:b field two.grl",
+	}, names)
+
+	// the hash can not be matched with an invalid field pattern, it is skipped.
+	bundle.FieldPattern = []string{"["}
+	_, err = bundle.Load()
+	assert.Error(t, err)
+	failed := make([]string, 0)
+	resources, err = bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		failed = append(failed, res.String())
+
+		return DecisionSkip
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, []string{"Redis resource at " + options.Addr + " key rules:b"}, failed)
+}
+
+func TestRedisResourceBundle_Watch(t *testing.T) {
+	server := newFakeRedis(t)
+	defer server.listener.Close()
+	server.set("rules:a", "rule A {}")
+	bundle := NewRedisResourceBundle(RedisOptions{Addr: server.listener.Addr().String()}, "rules:*")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := make(chan []Resource, 10)
+	done := make(chan error)
+	go func() {
+		done <- bundle.Watch(ctx, func(resources []Resource, err error) {
+			assert.NoError(t, err)
+			reloads <- resources
+		})
+	}()
+	assert.Len(t, <-reloads, 1)
+
+	server.set("rules:b", "rule B {}")
+	select {
+	case resources := <-reloads:
+		keys := make([]string, 0)
+		for _, resource := range resources {
+			keys = append(keys, resource.(*RedisResource).Key)
+		}
+		sort.Strings(keys)
+		assert.Equal(t, []string{"rules:a", "rules:b"}, keys)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was not notified")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -16,6 +16,7 @@ package pkg
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 )
@@ -23,6 +24,10 @@ import (
 // EvaluateMultiplication will evaluate multiplication operation over two value
 func EvaluateMultiplication(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opMul, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -90,6 +95,10 @@ func EvaluateMultiplication(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateDivision will evaluate division operation over two value
 func EvaluateDivision(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opDiv, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -157,6 +166,10 @@ func EvaluateDivision(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateModulo will evaluate modulo operation over two value
 func EvaluateModulo(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opMod, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -197,6 +210,10 @@ func EvaluateModulo(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateAddition will evaluate addition operation over two value
 func EvaluateAddition(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opAdd, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -308,6 +325,14 @@ func EvaluateAddition(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateSubtraction will evaluate subtraction operation over two value
 func EvaluateSubtraction(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsCollection(left) || IsCollection(right) {
+
+		return evaluateCollection(opSub, left, right)
+	}
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opSub, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -375,6 +400,14 @@ func EvaluateSubtraction(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateBitAnd will evaluate Bitwise And operation over two value
 func EvaluateBitAnd(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsCollection(left) || IsCollection(right) {
+
+		return evaluateCollection(opBitAnd, left, right)
+	}
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opBitAnd, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -415,6 +448,14 @@ func EvaluateBitAnd(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateBitOr will evaluate Bitwise Or operation over two value
 func EvaluateBitOr(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsCollection(left) || IsCollection(right) {
+
+		return evaluateCollection(opBitOr, left, right)
+	}
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opBitOr, left, right)
+	}
 	switch left.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		leftValue := left.Int()
@@ -455,6 +496,10 @@ func EvaluateBitOr(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateGreaterThan will evaluate GreaterThan operation over two value
 func EvaluateGreaterThan(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opGT, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -539,6 +584,10 @@ func EvaluateGreaterThan(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateLesserThan will evaluate LesserThan operation over two value
 func EvaluateLesserThan(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opLT, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -623,6 +672,10 @@ func EvaluateLesserThan(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateGreaterThanEqual will evaluate GreaterThanEqual operation over two value
 func EvaluateGreaterThanEqual(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opGTE, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -707,6 +760,10 @@ func EvaluateGreaterThanEqual(left, right reflect.Value) (reflect.Value, error)
 // EvaluateLesserThanEqual will evaluate LesserThanEqual operation over two value
 func EvaluateLesserThanEqual(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opLTE, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -791,6 +848,14 @@ func EvaluateLesserThanEqual(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateEqual will evaluate Equal operation over two value
 func EvaluateEqual(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsCollection(left) || IsCollection(right) {
+
+		return evaluateCollection(opEQ, left, right)
+	}
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opEQ, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -882,6 +947,14 @@ func EvaluateEqual(left, right reflect.Value) (reflect.Value, error) {
 // EvaluateNotEqual will evaluate NotEqual operation over two value
 func EvaluateNotEqual(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
+	if IsCollection(left) || IsCollection(right) {
+
+		return evaluateCollection(opNEQ, left, right)
+	}
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return defaultUnits.evaluate(opNEQ, left, right)
+	}
 	switch left.Kind() {
 	case reflect.String:
 		leftValue := left.String()
@@ -970,6 +1043,107 @@ func EvaluateNotEqual(left, right reflect.Value) (reflect.Value, error) {
 	}
 }
 
+// EvaluateApproxEqual will evaluate ApproxEqual operation over two value, true if the numbers differ by at most the
+// tolerance. A tolerance that is not a number, or is negative, is an error.
+func EvaluateApproxEqual(left, right, tolerance reflect.Value) (reflect.Value, error) {
+	left, right, tolerance = GetValueElem(left), GetValueElem(right), GetValueElem(tolerance)
+	if !IsNumber(tolerance) {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not use data type of %s as tolerance of ~== comparison", tolerance.Kind().String())
+	}
+	epsilon := numberToFloat(tolerance)
+	if epsilon < 0 || math.IsNaN(epsilon) {
+
+		return reflect.ValueOf(nil), fmt.Errorf("tolerance of ~== comparison can not be negative. got %v", epsilon)
+	}
+	if !IsNumber(left) {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not use data type of %s in ~== comparison", left.Kind().String())
+	}
+	if !IsNumber(right) {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not use data type of %s in ~== comparison", right.Kind().String())
+	}
+
+	return reflect.ValueOf(math.Abs(numberToFloat(left)-numberToFloat(right)) <= epsilon), nil
+}
+
+// EvaluateIn will evaluate In operation over two value, true if the left value is an element of the right array, slice
+// or Set, or a key of the right map or OrderedMap. The elements are compared as by ==, so an int field is found among
+// integer literals.
+func EvaluateIn(left, right reflect.Value) (reflect.Value, error) {
+	left, right = GetValueElem(left), GetValueElem(right)
+	if !right.IsValid() {
+
+		return reflect.ValueOf(nil), fmt.Errorf("can not evaluate in on nil")
+	}
+	var value interface{}
+	if left.IsValid() && left.CanInterface() {
+		value = left.Interface()
+	}
+	switch collection := right.Interface().(type) {
+	case Set:
+
+		return reflect.ValueOf(collection.Has(value)), nil
+	case OrderedMap:
+
+		return reflect.ValueOf(collection.Has(value)), nil
+	}
+	switch right.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < right.Len(); i++ {
+			if inEqual(left, right.Index(i)) {
+
+				return reflect.ValueOf(true), nil
+			}
+		}
+
+		return reflect.ValueOf(false), nil
+	case reflect.Map:
+		if left.IsValid() && left.Type() == right.Type().Key() {
+
+			return reflect.ValueOf(right.MapIndex(left).IsValid()), nil
+		}
+		iter := right.MapRange()
+		for iter.Next() {
+			if inEqual(left, iter.Key()) {
+
+				return reflect.ValueOf(true), nil
+			}
+		}
+
+		return reflect.ValueOf(false), nil
+	}
+
+	return reflect.ValueOf(nil), fmt.Errorf("can not evaluate in on %s, it is not an array, slice, map or collection", right.Kind().String())
+}
+
+// inEqual tells whether the value equals the element, values that can not be compared are not equal.
+func inEqual(value, element reflect.Value) bool {
+	if !value.IsValid() {
+		element = GetValueElem(element)
+
+		return !element.IsValid()
+	}
+	equal, err := EvaluateEqual(value, element)
+
+	return err == nil && equal.Kind() == reflect.Bool && equal.Bool()
+}
+
+// numberToFloat returns the number as a float64.
+func numberToFloat(number reflect.Value) float64 {
+	switch GetBaseKind(number) {
+	case reflect.Int64:
+
+		return float64(number.Int())
+	case reflect.Uint64:
+
+		return float64(number.Uint())
+	}
+
+	return number.Float()
+}
+
 // EvaluateLogicAnd will evaluate LogicalAnd operation over two value
 func EvaluateLogicAnd(left, right reflect.Value) (reflect.Value, error) {
 	left, right = GetValueElem(left), GetValueElem(right)
@@ -567,3 +567,54 @@ func TestEvaluateNotEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestEvaluateApproxEqual(t *testing.T) {
+	tolerance := reflect.ValueOf(0.5)
+	for _, va := range valuesA {
+		for _, vb := range valuesA {
+			vc, err := EvaluateApproxEqual(va, vb, tolerance)
+			if err != nil || !vc.Bool() {
+				t.Errorf("3 ~== 3 == false. got %v", err)
+			}
+		}
+		for _, vb := range valuesB {
+			vc, err := EvaluateApproxEqual(va, vb, tolerance)
+			if err != nil || vc.Bool() {
+				t.Errorf("3 ~== 12 == true. got %v", err)
+			}
+		}
+	}
+	if vc, err := EvaluateApproxEqual(reflect.ValueOf(0.1+0.2), reflect.ValueOf(0.3), reflect.ValueOf(1e-9)); err != nil || !vc.Bool() {
+		t.Errorf("0.1 + 0.2 ~== 0.3 == false. got %v", err)
+	}
+	if _, err := EvaluateApproxEqual(reflect.ValueOf(3), reflect.ValueOf(3), reflect.ValueOf(-1)); err == nil {
+		t.Errorf("Expected an error on a negative tolerance")
+	}
+	if _, err := EvaluateApproxEqual(reflect.ValueOf("3"), reflect.ValueOf(3), tolerance); err == nil {
+		t.Errorf("Expected an error comparing a string")
+	}
+}
+
+func TestEvaluateIn(t *testing.T) {
+	set, _ := NewSet("US", "CA")
+	for _, va := range valuesB {
+		for _, collection := range []interface{}{[]int64{12, 3}, [2]float64{3, 4}, []interface{}{"x", 3.0}, map[int]string{3: "three"}, map[uint8]bool{3: true}} {
+			vc, err := EvaluateIn(va, reflect.ValueOf(collection))
+			if err != nil || !vc.Bool() {
+				t.Errorf("3 in %v == false. got %v", collection, err)
+			}
+		}
+		for _, vb := range []interface{}{[]int{12}, map[string]int{"3": 3}, set, []string{}} {
+			vc, err := EvaluateIn(va, reflect.ValueOf(vb))
+			if err != nil || vc.Bool() {
+				t.Errorf("3 in %v == true. got %v", vb, err)
+			}
+		}
+	}
+	if vc, err := EvaluateIn(reflect.ValueOf("CA"), reflect.ValueOf(&set)); err != nil || !vc.Bool() {
+		t.Errorf("CA in %v == false. got %v", set, err)
+	}
+	if _, err := EvaluateIn(reflect.ValueOf("CA"), reflect.ValueOf("CANADA")); err == nil {
+		t.Errorf("Expected an error testing membership of a string")
+	}
+}
@@ -318,36 +318,11 @@ func SetAttributeValue(objVal reflect.Value, fieldName string, value reflect.Val
 			fieldVal.SetString(value.String())
 
 			break
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if GetBaseKind(value) == reflect.Uint64 {
-				fieldVal.SetInt(int64(value.Uint()))
-			} else if GetBaseKind(value) == reflect.Float64 {
-				fieldVal.SetInt(int64(value.Float()))
-			} else {
-				fieldVal.SetInt(value.Int())
-			}
-
-			break
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			if GetBaseKind(value) == reflect.Uint64 {
-				fieldVal.SetUint(value.Uint())
-			} else if GetBaseKind(value) == reflect.Float64 {
-				fieldVal.SetUint(uint64(value.Float()))
-			} else {
-				fieldVal.SetUint(uint64(value.Int()))
-			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
 
-			break
-		case reflect.Float32, reflect.Float64:
-			if GetBaseKind(value) == reflect.Uint64 {
-				fieldVal.SetFloat(float64(value.Uint()))
-			} else if GetBaseKind(value) == reflect.Float64 {
-				fieldVal.SetFloat(value.Float())
-			} else {
-				fieldVal.SetFloat(float64(value.Int()))
-			}
-
-			break
+			return SetNumberValue(fieldVal, value)
 		case reflect.Bool:
 			fieldVal.SetBool(value.Bool())
 
@@ -631,6 +606,86 @@ func reflectIsZero(value reflect.Value) bool {
 	}
 }
 
+// SetNumberValue sets the number value into the number field. Integers are set if they fit the field, a negative
+// value is never set into an unsigned field. Floats are truncated toward zero into integer fields, and set into
+// float fields if they fit. Use the ToInt, ToUint and ToFloat functions to cast a value explicitly.
+func SetNumberValue(fieldVal, value reflect.Value) error {
+	switch fieldVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch GetBaseKind(value) {
+		case reflect.Uint64:
+			if value.Uint() > math.MaxInt64 {
+
+				return fmt.Errorf("value %d overflows %s", value.Uint(), fieldVal.Type().String())
+			}
+			i = int64(value.Uint())
+		case reflect.Float64:
+			f := math.Trunc(value.Float())
+			if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+
+				return fmt.Errorf("value %g overflows %s", value.Float(), fieldVal.Type().String())
+			}
+			i = int64(f)
+		default:
+			i = value.Int()
+		}
+		if fieldVal.OverflowInt(i) {
+
+			return fmt.Errorf("value %d overflows %s", i, fieldVal.Type().String())
+		}
+		fieldVal.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch GetBaseKind(value) {
+		case reflect.Uint64:
+			u = value.Uint()
+		case reflect.Float64:
+			f := math.Trunc(value.Float())
+			if f < 0 {
+
+				return fmt.Errorf("negative value %g can not be set into %s", value.Float(), fieldVal.Type().String())
+			}
+			if math.IsNaN(f) || f >= math.MaxUint64 {
+
+				return fmt.Errorf("value %g overflows %s", value.Float(), fieldVal.Type().String())
+			}
+			u = uint64(f)
+		default:
+			if value.Int() < 0 {
+
+				return fmt.Errorf("negative value %d can not be set into %s", value.Int(), fieldVal.Type().String())
+			}
+			u = uint64(value.Int())
+		}
+		if fieldVal.OverflowUint(u) {
+
+			return fmt.Errorf("value %d overflows %s", u, fieldVal.Type().String())
+		}
+		fieldVal.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch GetBaseKind(value) {
+		case reflect.Uint64:
+			f = float64(value.Uint())
+		case reflect.Float64:
+			f = value.Float()
+		default:
+			f = float64(value.Int())
+		}
+		if fieldVal.OverflowFloat(f) {
+
+			return fmt.Errorf("value %g overflows %s", f, fieldVal.Type().String())
+		}
+		fieldVal.SetFloat(f)
+	default:
+
+		return fmt.Errorf("can not set number into %s", fieldVal.Type().String())
+	}
+
+	return nil
+}
+
 // GetBaseKind will try to obtain base obtainable kind of a value, so we know what method to call val.Int(), val.Uint(), etc.
 func GetBaseKind(val reflect.Value) reflect.Kind {
 	switch val.Kind() {
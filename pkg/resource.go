@@ -15,12 +15,17 @@
 package pkg
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-billy/v5"
@@ -31,12 +36,34 @@ import (
 )
 
 var (
+	// URLResourceTimeoutSecond is the default timeout of the loads of the remote resources, for the resources and
+	// the bundles whose Timeout is zero. Changing it changes the default of the whole process, prefer setting the
+	// Timeout of the resources, or loading them with a context.
 	URLResourceTimeoutSecond = 1800 // 30 minutes
 )
 
+// contextLog returns the logger carried by the context, see logger.NewContext, or else the default logger.
+func contextLog(ctx context.Context) logger.LogEntry {
+
+	return logger.FromContext(ctx).Or(logger.Log)
+}
+
+// loadTimeout returns the timeout, or URLResourceTimeoutSecond if it is not positive.
+func loadTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+
+		return timeout
+	}
+
+	return time.Duration(URLResourceTimeoutSecond) * time.Second
+}
+
 // ResourceBundle is a helper struct to help load multiple resource at once.
 type ResourceBundle interface {
 	Load() ([]Resource, error)
+	// MustLoad panics on the first resource failing to load.
+	//
+	// Deprecated: use Load, or LoadBundleWithHandler to go on without the failing resources.
 	MustLoad() []Resource
 }
 
@@ -46,6 +73,152 @@ type Resource interface {
 	String() string
 }
 
+// ResourceBundleWithContext is a ResourceBundle that can be loaded within a context, so the caller can bound
+// the loading time or cancel it, such as on shutdown.
+type ResourceBundleWithContext interface {
+	ResourceBundle
+	LoadContext(ctx context.Context) ([]Resource, error)
+}
+
+// ResourceWithContext is a Resource that can be loaded within a context.
+type ResourceWithContext interface {
+	Resource
+	LoadContext(ctx context.Context) ([]byte, error)
+}
+
+// LoadBundle loads the bundle within the context. A bundle that is not a ResourceBundleWithContext is loaded with Load,
+// and an error is returned as soon as the context is done, leaving the Load finish in the background.
+func LoadBundle(ctx context.Context, bundle ResourceBundle) ([]Resource, error) {
+	if bundleWithContext, ok := bundle.(ResourceBundleWithContext); ok {
+
+		return bundleWithContext.LoadContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+
+		return nil, err
+	}
+	type loaded struct {
+		resources []Resource
+		err       error
+	}
+	done := make(chan loaded, 1)
+	go func() {
+		resources, err := bundle.Load()
+		done <- loaded{resources: resources, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+
+		return nil, ctx.Err()
+	case result := <-done:
+
+		return result.resources, result.err
+	}
+}
+
+// Decision tells a bundle loaded with a handler what to do with a resource that failed to load.
+type Decision int
+
+const (
+	// DecisionAbort stops the loading, the bundle returns the error of the resource.
+	DecisionAbort Decision = iota
+	// DecisionSkip leaves the resource out, the loading goes on with the next resource.
+	DecisionSkip
+	// DecisionRetry loads the resource once more, the handler is called again if it fails again.
+	DecisionRetry
+)
+
+// ResourceBundleWithHandler is a ResourceBundle that reports every resource failing to load to the handler, which
+// decides whether the resource is skipped, retried or aborts the loading. The handler is also where the failures
+// are collected into a report, as Load only returns the resources that loaded.
+type ResourceBundleWithHandler interface {
+	ResourceBundle
+	LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error)
+}
+
+// LoadBundleWithHandler loads the bundle, reporting the resources failing to load to onError. A bundle that is not
+// a ResourceBundleWithHandler is loaded with Load, its error is reported with a nil resource: DecisionRetry loads
+// the bundle again and DecisionSkip returns no resource.
+func LoadBundleWithHandler(bundle ResourceBundle, onError func(Resource, error) Decision) ([]Resource, error) {
+	if bundleWithHandler, ok := bundle.(ResourceBundleWithHandler); ok {
+
+		return bundleWithHandler.LoadWithHandler(onError)
+	}
+
+	return loadAllWithHandler(context.Background(), bundle.Load, onError)
+}
+
+// loadAllWithHandler calls load, which loads all the resources at once, its error is reported to onError with a
+// nil resource: DecisionRetry calls load again and DecisionSkip returns no resource.
+func loadAllWithHandler(ctx context.Context, load func() ([]Resource, error), onError func(Resource, error) Decision) ([]Resource, error) {
+	var resources []Resource
+	skipped, err := retryLoad(ctx, nil, onError, func() error {
+		var err error
+		resources, err = load()
+
+		return err
+	})
+	if skipped {
+
+		return make([]Resource, 0), nil
+	}
+
+	return resources, err
+}
+
+// retryLoad calls load until it succeeds or onError decides otherwise. A nil onError aborts on the first error.
+// It returns true if the resource is to be skipped. The decisions are logged into the logger of the context.
+func retryLoad(ctx context.Context, resource Resource, onError func(Resource, error) Decision, load func() error) (bool, error) {
+	for {
+		err := load()
+		if err == nil || onError == nil {
+
+			return false, err
+		}
+		switch onError(resource, err) {
+		case DecisionSkip:
+			contextLog(ctx).Warnf("Skipping resource %v that failed to load : %v", resource, err)
+
+			return true, nil
+		case DecisionRetry:
+			contextLog(ctx).Debugf("Retrying resource %v that failed to load : %v", resource, err)
+		default:
+
+			return false, err
+		}
+	}
+}
+
+// LoadResource loads the resource within the context. A resource that is not a ResourceWithContext is loaded with Load,
+// and an error is returned as soon as the context is done, leaving the Load finish in the background.
+func LoadResource(ctx context.Context, resource Resource) ([]byte, error) {
+	if resourceWithContext, ok := resource.(ResourceWithContext); ok {
+
+		return resourceWithContext.LoadContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+
+		return nil, err
+	}
+	type loaded struct {
+		data []byte
+		err  error
+	}
+	done := make(chan loaded, 1)
+	go func() {
+		data, err := resource.Load()
+		done <- loaded{data: data, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+
+		return nil, ctx.Err()
+	case result := <-done:
+
+		return result.data, result.err
+	}
+}
+
 // NewReaderResource will create a new Resource using a common reader.
 func NewReaderResource(reader io.Reader) Resource {
 	return &ReaderResource{Reader: reader}
@@ -62,6 +235,28 @@ func (res *ReaderResource) Load() ([]byte, error) {
 	return io.ReadAll(res.Reader)
 }
 
+// LoadContext is the same as Load, the reading stops with the context error once the context is done.
+func (res *ReaderResource) LoadContext(ctx context.Context) ([]byte, error) {
+
+	return io.ReadAll(&contextReader{ctx: ctx, reader: res.Reader})
+}
+
+// contextReader reads from the reader until the context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// Read implements io.Reader
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+
+		return 0, err
+	}
+
+	return r.reader.Read(p)
+}
+
 // String will state the resource source.
 func (res *ReaderResource) String() string {
 
@@ -78,11 +273,14 @@ func NewFileResource(path string) Resource {
 
 // NewFileResourceBundle creates new instance of FileResourceBundle struct
 // basePath denotes the directory location where the file is located.
-// pathPattern are list of paths that filters the files. Its important that
-// the pattern will include the base path as it filter.
+// pathPattern are list of paths that filters the files.
+// Patterns always use forward slash as separator regardless of the operating system,
+// a back slash in the pattern is also treated as a separator.
+// A relative pattern is matched against the file path relative to the base path,
+// thus the pattern to accept all GRL file is "**/*.grl".
+// An absolute pattern is matched against the absolute file path,
 // For example, if the base path is "/some/base/path"
-// The pattern to accept all GRL file is "/some/base/path/**/*.grl".
-// This will accept all *.grl files under /some/base/path and its directories.
+// The pattern "/some/base/path/**/*.grl" will accept all *.grl files under /some/base/path and its directories.
 func NewFileResourceBundle(basePath string, pathPattern ...string) *FileResourceBundle {
 
 	return &FileResourceBundle{
@@ -93,24 +291,56 @@ func NewFileResourceBundle(basePath string, pathPattern ...string) *FileResource
 
 // FileResourceBundle is a helper struct to load multiple files all at once by specifying
 // the root location of the file and the file pattern to look for.
-// It will look into sub-directories for the file with pattern matching.
+// It will look into sub-directories for the file with pattern matching,
+// including directories that are symbolic links, as long as they don't link back to their own parent.
 type FileResourceBundle struct {
 	// The base path where all the
 	BasePath string
 	// List Glob like file pattern.
-	// *.grl           <- matches abc.grl but not /anyfolder/abc.grl
-	// **/*.grl        <- matches abc/def.grl or abc/def/ghi.grl or abc/def/.grl
-	// /abc/**/*.grl   <- matches /abc/def.grl or /abc/def/ghi.drl
+	// *.grl           <- matches abc.grl but not anyfolder/abc.grl
+	// **/*.grl        <- matches abc.grl, abc/def.grl or abc/def/ghi.grl
+	// abc/**/*.grl    <- matches abc/def.grl or abc/def/ghi.grl
+	// abc\**\*.grl    <- same as abc/**/*.grl
+	// /abc/**/*.grl   <- matches /abc/def.grl or /abc/def/ghi.grl
 	PathPattern []string
+	// Concurrency is the number of files matched and read at the same time, the files are read one after the other
+	// if it is zero or one. The resources are returned in the same order whatever the concurrency, and the error
+	// handler of LoadWithHandler may then be called from several goroutines at the same time.
+	Concurrency int
 }
 
 // Load all file resources that locateed under BasePath that conform to the PathPattern.
 func (bundle *FileResourceBundle) Load() ([]Resource, error) {
 
-	return bundle.loadPath(bundle.BasePath)
+	return bundle.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, it stops with the context error once the context is done.
+func (bundle *FileResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	basePath, err := filepath.Abs(bundle.BasePath)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return bundle.loadFS(ctx, os.DirFS(basePath), basePath, nil)
+}
+
+// LoadWithHandler is the same as Load, the files failing to be read are reported to onError.
+func (bundle *FileResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	basePath, err := filepath.Abs(bundle.BasePath)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return bundle.loadFS(context.Background(), os.DirFS(basePath), basePath, onError)
 }
 
 // MustLoad function is the same as Load with difference that it will panic if any error is raised
+//
+// Deprecated: use FileResourceBundle.Load, or FileResourceBundle.LoadWithHandler to go on without the files failing to
+// be read.
 func (bundle *FileResourceBundle) MustLoad() []Resource {
 	resources, err := bundle.Load()
 	if err != nil {
@@ -121,83 +351,211 @@ func (bundle *FileResourceBundle) MustLoad() []Resource {
 	return resources
 }
 
-func (bundle *FileResourceBundle) loadPath(path string) ([]Resource, error) {
-	logger.Log.Tracef("Enter directory %s", path)
-
-	finfos, err := os.ReadDir(path)
+// loadFS load all files in fsys that match the PathPattern. basePath is the absolute location of
+// fsys root in the OS, it is used to match the absolute patterns and to name the resulting resources.
+// The files failing to be read are reported to onError, if not nil.
+func (bundle *FileResourceBundle) loadFS(ctx context.Context, fsys fs.FS, basePath string, onError func(Resource, error) Decision) ([]Resource, error) {
+	rootInfo, err := fs.Stat(fsys, ".")
+	if err != nil {
 
+		return nil, err
+	}
+	files, err := walkDir(fsys, ".", []fs.FileInfo{rootInfo})
 	if err != nil {
 
 		return nil, err
 	}
-	ret := make([]Resource, 0)
-	for _, finfo := range finfos {
-		fulPath := fmt.Sprintf("%s/%s", path, finfo.Name())
-		fulPath, _ = filepath.Abs(fulPath)
-		if finfo.IsDir() {
-			gres, err := bundle.loadPath(fulPath)
+
+	return loadFiles(ctx, files, bundle.Concurrency, func(file string) (Resource, error) {
+		fullPath := filepath.Join(basePath, filepath.FromSlash(file))
+		for _, pattern := range bundle.PathPattern {
+			matched, err := matchPathPattern(pattern, file, filepath.ToSlash(fullPath))
 			if err != nil {
+
 				return nil, err
 			}
-			ret = append(ret, gres...)
-		} else {
-			for _, pattern := range bundle.PathPattern {
-				matched, err := doublestar.PathMatch(pattern, fulPath)
-				if err != nil {
-					return nil, err
+			if matched {
+				contextLog(ctx).Debugf("Loading file %s", fullPath)
+				gress := &FileResource{
+					Path: fullPath,
 				}
-				if matched {
-					logger.Log.Debugf("Loading file %s", fulPath)
-					bytes, err := os.ReadFile(fulPath)
-					if err != nil {
-						return nil, err
-					}
-					gress := &FileResource{
-						Path:  fulPath,
-						Bytes: bytes,
-					}
-					ret = append(ret, gress)
+				skipped, err := retryLoad(ctx, gress, onError, func() error {
+					var err error
+					gress.Bytes, err = fs.ReadFile(fsys, file)
 
-					break
+					return err
+				})
+				if err != nil || skipped {
+
+					return nil, err
 				}
+
+				return gress, nil
 			}
 		}
+
+		return nil, nil
+	})
+}
+
+// matchPathPattern check the pattern against relPath if the pattern is relative, or against absPath if its absolute.
+func matchPathPattern(pattern, relPath, absPath string) (bool, error) {
+	isAbs := filepath.IsAbs(pattern)
+	pattern = strings.ReplaceAll(pattern, "\\", "/")
+	if isAbs || strings.HasPrefix(pattern, "/") {
+
+		return doublestar.Match(pattern, absPath)
+	}
+
+	return doublestar.Match(pattern, relPath)
+}
+
+// walkDir list all files under dir, sorted by name, descending into sub-directories.
+// ancestors are the directories leading to dir, used to avoid following symbolic links into a loop.
+func walkDir(fsys fs.FS, dir string, ancestors []fs.FileInfo) ([]string, error) {
+	logger.Log.Tracef("Enter directory %s", dir)
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+
+		return nil, err
+	}
+	ret := make([]string, 0)
+	for _, entry := range entries {
+		entryPath := path.Join(dir, entry.Name())
+		if !entry.IsDir() && entry.Type()&fs.ModeSymlink == 0 {
+			ret = append(ret, entryPath)
+
+			continue
+		}
+		info, err := fs.Stat(fsys, entryPath)
+		if err != nil {
+			if entry.Type()&fs.ModeSymlink != 0 {
+				logger.Log.Warnf("Skipping broken symbolic link %s", entryPath)
+
+				continue
+			}
+
+			return nil, err
+		}
+		if !info.IsDir() {
+			ret = append(ret, entryPath)
+
+			continue
+		}
+		if isAncestor(info, ancestors) {
+			logger.Log.Warnf("Skipping directory %s, it links back to its own parent", entryPath)
+
+			continue
+		}
+		files, err := walkDir(fsys, entryPath, append(ancestors, info))
+		if err != nil {
+
+			return nil, err
+		}
+		ret = append(ret, files...)
 	}
 
 	return ret, nil
 }
 
+func isAncestor(info fs.FileInfo, ancestors []fs.FileInfo) bool {
+	for _, ancestor := range ancestors {
+		if os.SameFile(info, ancestor) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
 // FileResource is a struct that will hold the file path and readed data bytes.
 type FileResource struct {
 	Path  string
 	Bytes []byte
+	// LastModified is the modification time of the file when it was loaded.
+	LastModified time.Time
 }
 
 // Load will load the resource into byte array.
 // The load byte array will be cached by the FileResource. So Calling
 // Load multiple time will only load the file once on the first call.
-// If you wish to reload the file, simply create new instance using NewFileResource function.
+// If you wish to reload the file, call Refresh.
 func (res *FileResource) Load() ([]byte, error) {
+
+	return res.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the reading stops with the context error once the context is done.
+func (res *FileResource) LoadContext(ctx context.Context) ([]byte, error) {
 	if res.Bytes != nil {
 
 		return res.Bytes, nil
 	}
-	data, err := os.ReadFile(res.Path)
+	if err := ctx.Err(); err != nil {
+
+		return nil, err
+	}
+	file, err := os.Open(res.Path)
 	if err != nil {
 
 		return nil, err
 	}
+	defer file.Close()
+	data, err := io.ReadAll(&contextReader{ctx: ctx, reader: file})
+	if err != nil {
+
+		return nil, err
+	}
+	if info, err := file.Stat(); err == nil {
+		res.LastModified = info.ModTime()
+	}
 	res.Bytes = data
 
 	return res.Bytes, nil
 }
 
+// Refresh reads the file again, it returns true if its bytes differ from the loaded ones.
+func (res *FileResource) Refresh() (bool, error) {
+
+	return res.RefreshContext(context.Background())
+}
+
+// RefreshContext is the same as Refresh, the reading stops with the context error once the context is done.
+// The loaded bytes are kept if the file can not be read.
+func (res *FileResource) RefreshContext(ctx context.Context) (bool, error) {
+	previous := res.Bytes
+	res.Bytes = nil
+	data, err := res.LoadContext(ctx)
+	if err != nil {
+		res.Bytes = previous
+
+		return false, err
+	}
+
+	return previous == nil || !bytes.Equal(data, previous), nil
+}
+
 // String will state the resource file path.
 func (res *FileResource) String() string {
 
 	return fmt.Sprintf("File resource at %s", res.Path)
 }
 
+// Metadata implements ResourceInfo, the origin is the absolute path of the file.
+func (res *FileResource) Metadata() ResourceMetadata {
+	origin, err := filepath.Abs(res.Path)
+	if err != nil {
+		origin = res.Path
+	}
+
+	return ResourceMetadata{
+		Origin:       origin,
+		LastModified: res.LastModified,
+	}
+}
+
 // NewBytesResource will create a new Resource using a byte array.
 func NewBytesResource(bytes []byte) Resource {
 	return &BytesResource{
@@ -240,11 +598,46 @@ func NewURLResourceWithHeaders(url string, Header http.Header) Resource {
 	}
 }
 
+// NewURLResourceWithClient will create a new Resource using a resource as located in the url, fetched with the
+// client, such as a client going through a proxy or authenticating with a TLS client certificate.
+func NewURLResourceWithClient(url string, client *http.Client) Resource {
+
+	return &URLResource{
+		URL:    url,
+		Header: make(http.Header),
+		Client: client,
+	}
+}
+
+// NewURLResourceWithRetry will create a new Resource using a resource as located in the url, fetched with the client
+// and retried as the policy tells when the server or the network fails. A nil client uses a new http.Client.
+func NewURLResourceWithRetry(url string, client *http.Client, retry *RetryPolicy) Resource {
+
+	return &URLResource{
+		URL:    url,
+		Header: make(http.Header),
+		Client: client,
+		Retry:  retry,
+	}
+}
+
 // URLResource is a struct that will hold the byte array data and URL source
 type URLResource struct {
 	URL    string
 	Header http.Header
 	Bytes  []byte
+	// Client fetches the URL, if nil a new http.Client is used.
+	Client *http.Client
+	// Retry, if set, sends the request again when it fails or the server answers a retryable status code.
+	Retry *RetryPolicy
+	// Timeout bounds Load and Refresh, retries included. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+	// ETag and LastModified are the validators the server sent with the Bytes, Refresh sends them back so the
+	// server answers 304 Not Modified when the content did not change.
+	ETag         string
+	LastModified string
+	// PollInterval is how often Watch refreshes the resource, one minute if zero.
+	PollInterval time.Duration
 }
 
 // String will state the resource url.
@@ -253,29 +646,70 @@ func (res *URLResource) String() string {
 	return fmt.Sprintf("URL resource at %s", res.URL)
 }
 
+// Metadata implements ResourceInfo, the version is the ETag the server answered with, or its Last-Modified if it
+// sent no ETag.
+func (res *URLResource) Metadata() ResourceMetadata {
+	metadata := ResourceMetadata{
+		Origin:  res.URL,
+		Version: res.ETag,
+		ETag:    res.ETag,
+	}
+	if lastModified, err := http.ParseTime(res.LastModified); err == nil {
+		metadata.LastModified = lastModified
+	}
+	if len(metadata.Version) == 0 {
+		metadata.Version = res.LastModified
+	}
+
+	return metadata
+}
+
 // Load will load the resource into byte array. This resource will cache the obtained result byte arrays.
 // So calling this function multiple times only call the URL once at the first time.
 // If you want to refresh the load, you simply create a new instance of URLResource using
-// NewURLResource
+// NewURLResource. The request times out after Timeout, use LoadContext to choose the deadline.
+// A response other than 2xx is an error.
 func (res *URLResource) Load() ([]byte, error) {
-	if res.Bytes != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), res.timeout())
+	defer cancel()
 
-		return res.Bytes, nil
-	}
-	client := &http.Client{}
+	return res.LoadContext(ctx)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(URLResourceTimeoutSecond)*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+// timeout returns the Timeout of the resource, URLResourceTimeoutSecond if it has none.
+func (res *URLResource) timeout() time.Duration {
+
+	return loadTimeout(res.Timeout)
+}
+
+// client returns the Client of the resource, a new http.Client if it has none.
+func (res *URLResource) client() *http.Client {
+	if res.Client != nil {
 
-	if len(res.Header) > 0 {
-		req.Header = res.Header
+		return res.Client
 	}
-	if err != nil {
 
-		return nil, err
+	return &http.Client{}
+}
+
+// LoadContext is the same as Load, the request is bound by the context instead of Timeout.
+func (res *URLResource) LoadContext(ctx context.Context) ([]byte, error) {
+	if res.Bytes != nil {
+
+		return res.Bytes, nil
 	}
-	resp, err := client.Do(req)
+	resp, err := res.Retry.do(ctx, res.client(), func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+		if err != nil {
+
+			return nil, err
+		}
+		if len(res.Header) > 0 {
+			req.Header = res.Header.Clone()
+		}
+
+		return req, nil
+	})
 	if err != nil {
 
 		return nil, err
@@ -286,16 +720,117 @@ func (res *URLResource) Load() ([]byte, error) {
 			panic(err.Error())
 		}
 	}(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+
+		return nil, fmt.Errorf("error while loading URL resource at %s. got %s", res.URL, resp.Status)
+	}
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 
 		return nil, err
 	}
 	res.Bytes = data
+	res.ETag = resp.Header.Get("ETag")
+	res.LastModified = resp.Header.Get("Last-Modified")
 
 	return res.Bytes, nil
 }
 
+// Refresh asks the server whether the resource changed since it was loaded, sending If-None-Match and
+// If-Modified-Since, and downloads it again only if it did. It returns true if the new bytes differ from
+// the loaded ones. The request times out after Timeout, use RefreshContext to choose the deadline.
+func (res *URLResource) Refresh() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), res.timeout())
+	defer cancel()
+
+	return res.RefreshContext(ctx)
+}
+
+// RefreshContext is the same as Refresh, the request is bound by the context instead of Timeout.
+// A response other than 200 or 304 is an error, the loaded bytes are then kept.
+func (res *URLResource) RefreshContext(ctx context.Context) (bool, error) {
+	if res.Bytes == nil {
+		_, err := res.LoadContext(ctx)
+
+		return err == nil, err
+	}
+	resp, err := res.Retry.do(ctx, res.client(), func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+		if err != nil {
+
+			return nil, err
+		}
+		if len(res.Header) > 0 {
+			req.Header = res.Header.Clone()
+		}
+		if len(res.ETag) > 0 {
+			req.Header.Set("If-None-Match", res.ETag)
+		}
+		if len(res.LastModified) > 0 {
+			req.Header.Set("If-Modified-Since", res.LastModified)
+		}
+
+		return req, nil
+	})
+	if err != nil {
+
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		contextLog(ctx).Debugf("URL resource at %s is not modified", res.URL)
+
+		return false, nil
+	case http.StatusOK:
+	default:
+
+		return false, fmt.Errorf("error while refreshing URL resource at %s. got %s", res.URL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+
+		return false, err
+	}
+	changed := !bytes.Equal(data, res.Bytes)
+	res.Bytes = data
+	res.ETag = resp.Header.Get("ETag")
+	res.LastModified = resp.Header.Get("Last-Modified")
+
+	return changed, nil
+}
+
+// Watch loads the resource if it is not loaded yet, then refreshes it every PollInterval until the context is done.
+// onChange is called with the bytes once they are loaded and every time they change, or with the error of a failed
+// load or refresh.
+func (res *URLResource) Watch(ctx context.Context, onChange func(data []byte, err error)) error {
+	interval := res.PollInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	for {
+		loaded := res.Bytes != nil
+		changed, err := res.RefreshContext(ctx)
+		if ctx.Err() != nil {
+
+			return ctx.Err()
+		}
+		switch {
+		case err != nil:
+			onChange(nil, err)
+		case changed || !loaded:
+			onChange(res.Bytes, nil)
+		}
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
 // NewGITResourceBundle will create a new instance of GITResourceBundle
 // url is the GIT http/https url.
 // pathPattern are list of file pattern (glob) to filter files located in the repository
@@ -314,6 +849,14 @@ func NewGITResourceBundleWithAuth(url string, user string, password string, path
 	return resource
 }
 
+// NewGITResourceBundleWithToken will create a new instance of GITResourceBundle cloning with a personal access token.
+func NewGITResourceBundleWithToken(url string, token string, pathPattern ...string) *GITResourceBundle {
+	resource := NewGITResourceBundle(url, pathPattern...)
+	resource.Token = token
+
+	return resource
+}
+
 // GITResourceBundle is a helper struct to load multiple files from GIT all at once by specifying
 // the necessary information needed to communicate to the GIT server.
 // It will look into sub-directories, in the git, for the file with pattern matching.
@@ -322,72 +865,133 @@ type GITResourceBundle struct {
 	URL string
 	// The Ref name to checkout, if you dont know, let it empty
 	RefName string
+	// Tag is the name of the tag to checkout, such as v1.2.0, instead of the RefName.
+	Tag string
+	// CommitHash pins the files to the commit with this full hash. It is checked out from the history of the
+	// RefName or the Tag, or of all the branches if neither is specified.
+	CommitHash string
+	// Depth, if positive, makes a shallow clone of that many commits. The CommitHash must be within them.
+	Depth int
+	// CacheDir, if set, keeps a clone of the repository on disk, in a sub-directory named after the URL. The first
+	// Load clones into it, the next ones only fetch the new commits and check out the files.
+	CacheDir string
 	// The remote name. IF you left it empty, it will use origin
 	Remote string
 	// Specify the user name if your repository requires user/password authentication
 	User string
 	// Password for authentication
 	Password string
+	// Token is a personal access token. It is sent as the password of the User if specified, such as
+	// "x-access-token" for GitHub or "oauth2" for GitLab, or else as a bearer token.
+	Token string
+	// ProxyURL is the HTTP(S) proxy the repository is cloned through, such as http://proxy.corp.example:3128.
+	// If empty, the proxy of the HTTPS_PROXY and HTTP_PROXY environment variables is used.
+	ProxyURL string
+	// ProxyUser and ProxyPassword authenticate to the ProxyURL, if it requires so.
+	ProxyUser     string
+	ProxyPassword string
 	// File path pattern to load in your git. The path / is the root on the repository.
 	PathPattern []string
+	// HTTPClient, if set, clones http and https repositories, such as a client going through a proxy or
+	// authenticating with a TLS client certificate.
+	HTTPClient *http.Client
+	// Concurrency is the number of files of the checked out repository matched and read at the same time, as in
+	// FileResourceBundle. The files are read one after the other if it is zero or one.
+	Concurrency int
 }
 
-func (bundle *GITResourceBundle) loadPath(url, path string, fileSyst billy.Filesystem) ([]Resource, error) {
-	logger.Log.Tracef("Enter directory %s", path)
-	finfos, err := fileSyst.ReadDir(path)
+// gitRevision is the commit the files of a GIT resource bundle were checked out at.
+type gitRevision struct {
+	hash string
+	when time.Time
+}
+
+// loadPath load all files of the repository that match the PathPattern, the files failing to be read are reported
+// to onError, if not nil.
+func (bundle *GITResourceBundle) loadPath(ctx context.Context, url string, fileSyst billy.Filesystem, revision gitRevision, onError func(Resource, error) Decision) ([]Resource, error) {
+	files, err := listPath("/", fileSyst)
 	if err != nil {
 
 		return nil, err
 	}
-	ret := make([]Resource, 0)
-	for _, finfo := range finfos {
-		fulPath := fmt.Sprintf("%s/%s", path, finfo.Name())
-		if path == "/" && finfo.IsDir() {
-			fulPath = fmt.Sprintf("/%s", finfo.Name())
-		}
-		if finfo.IsDir() {
-			gres, err := bundle.loadPath(url, fulPath, fileSyst)
+
+	return loadFiles(ctx, files, bundle.Concurrency, func(fulPath string) (Resource, error) {
+		for _, pattern := range bundle.PathPattern {
+			matched, err := doublestar.Match(pattern, fulPath)
 			if err != nil {
 
 				return nil, err
 			}
-			ret = append(ret, gres...)
-		} else {
-			for _, pattern := range bundle.PathPattern {
-				matched, err := doublestar.Match(pattern, fulPath)
-				if err != nil {
-
-					return nil, err
+			if matched {
+				contextLog(ctx).Debugf("Loading git file %s", fulPath)
+				gress := &GITResource{
+					URL:         url,
+					Path:        fulPath,
+					Commit:      revision.hash,
+					CommittedAt: revision.when,
 				}
-				if matched {
-					logger.Log.Debugf("Loading git file %s", fulPath)
+				skipped, err := retryLoad(ctx, gress, onError, func() error {
 					f, err := fileSyst.Open(fulPath)
 					if err != nil {
 
-						return nil, err
+						return err
 					}
-					bytes, err := io.ReadAll(f)
-					if err != nil {
+					defer f.Close()
+					gress.Bytes, err = io.ReadAll(f)
 
-						return nil, err
-					}
-					gress := &GITResource{
-						URL:   url,
-						Path:  fulPath,
-						Bytes: bytes,
-					}
-					ret = append(ret, gress)
+					return err
+				})
+				if err != nil || skipped {
 
-					break
+					return nil, err
 				}
+
+				return gress, nil
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// listPath list all files under path of the checked out repository, descending into sub-directories.
+func listPath(path string, fileSyst billy.Filesystem) ([]string, error) {
+	logger.Log.Tracef("Enter directory %s", path)
+	finfos, err := fileSyst.ReadDir(path)
+	if err != nil {
+
+		return nil, err
+	}
+	ret := make([]string, 0)
+	for _, finfo := range finfos {
+		fulPath := fmt.Sprintf("%s/%s", path, finfo.Name())
+		if path == "/" && finfo.IsDir() {
+			fulPath = fmt.Sprintf("/%s", finfo.Name())
+			// the repository of a clone in the CacheDir
+			if finfo.Name() == ".git" {
+				continue
 			}
 		}
+		if !finfo.IsDir() {
+			ret = append(ret, fulPath)
+
+			continue
+		}
+		files, err := listPath(fulPath, fileSyst)
+		if err != nil {
+
+			return nil, err
+		}
+		ret = append(ret, files...)
 	}
 
 	return ret, nil
 }
 
 // MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use GITResourceBundle.LoadContext, or GITResourceBundle.LoadWithHandler to go on without the files
+// failing to be read from the repository.
 func (bundle *GITResourceBundle) MustLoad() []Resource {
 	res, err := bundle.Load()
 	if err != nil {
@@ -403,6 +1007,9 @@ type GITResource struct {
 	URL   string
 	Path  string
 	Bytes []byte
+	// Commit is the hash of the commit the file was checked out at, and CommittedAt the time of that commit.
+	Commit      string
+	CommittedAt time.Time
 }
 
 // String will state the resource url.
@@ -411,9 +1018,180 @@ func (res *GITResource) String() string {
 	return fmt.Sprintf("From GIT URL [%s] %s", res.URL, res.Path)
 }
 
+// Metadata implements ResourceInfo, the version is the commit hash.
+func (res *GITResource) Metadata() ResourceMetadata {
+
+	return ResourceMetadata{
+		Origin:       res.URL + "#/" + strings.TrimLeft(res.Path, "/"),
+		Version:      res.Commit,
+		Commit:       res.Commit,
+		LastModified: res.CommittedAt,
+	}
+}
+
 // Load will load the resource into byte array. This implementation will no re-load resources from git when this method
 // is called, it simply return the loaded data.
 func (res *GITResource) Load() ([]byte, error) {
 
 	return res.Bytes, nil
 }
+
+// objectDefaultConcurrency is the number of objects downloaded at the same time if the bundle concurrency is not set.
+const objectDefaultConcurrency = 8
+
+// matchObjectKeys returns the keys of the objects of a bucket that conform to one of the patterns. A relative pattern
+// is matched against the key relative to the prefix, an absolute pattern against the whole key. Folder markers are skipped.
+func matchObjectKeys(keys []string, prefix string, patterns []string) ([]string, error) {
+	matched := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+
+			continue
+		}
+		relKey := strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/")
+		for _, pattern := range patterns {
+			ok, err := matchPathPattern(pattern, relKey, "/"+key)
+			if err != nil {
+
+				return nil, err
+			}
+			if ok {
+				matched = append(matched, key)
+
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// loadFiles loads the resource of every file with up to concurrency loads at the same time, one after the other if
+// concurrency is zero or one. load returns a nil resource for a file not to be loaded, such as a file no pattern matches.
+// The resources are returned in the order of the files. The loading stops at the first error or once the context is done.
+func loadFiles(ctx context.Context, files []string, concurrency int, load func(file string) (Resource, error)) ([]Resource, error) {
+	loaded := make([]Resource, len(files))
+	if concurrency <= 1 {
+		for i, file := range files {
+			if err := ctx.Err(); err != nil {
+
+				return nil, err
+			}
+			var err error
+			loaded[i], err = load(file)
+			if err != nil {
+
+				return nil, err
+			}
+		}
+	} else {
+		loadCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		errs := make([]error, len(files))
+		queue := make(chan int)
+		var wg sync.WaitGroup
+		for worker := 0; worker < concurrency && worker < len(files); worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range queue {
+					loaded[i], errs[i] = load(files[i])
+					if errs[i] != nil {
+						cancel()
+					}
+				}
+			}()
+		}
+	enqueue:
+		for i := range files {
+			select {
+			case queue <- i:
+			case <-loadCtx.Done():
+				break enqueue
+			}
+		}
+		close(queue)
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+
+				return nil, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+
+			return nil, err
+		}
+	}
+	ret := make([]Resource, 0, len(files))
+	for _, resource := range loaded {
+		if resource != nil {
+			ret = append(ret, resource)
+		}
+	}
+
+	return ret, nil
+}
+
+// loadObjects loads the resource of every key with up to concurrency loads at the same time, keeping the order of the keys.
+// load returns the resource of the key even if it fails, so the failure is reported to onError, if not nil, with it.
+func loadObjects(ctx context.Context, keys []string, concurrency int, onError func(Resource, error) Decision, load func(key string) (Resource, error)) ([]Resource, error) {
+	if concurrency <= 0 {
+		concurrency = objectDefaultConcurrency
+	}
+	loaded := make([]Resource, len(keys))
+	errs := make([]error, len(keys))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(keys); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				loaded[i], errs[i] = loadObject(ctx, keys[i], onError, load)
+			}
+		}()
+	}
+	for i := range keys {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+
+			return nil, err
+		}
+	}
+	ret := make([]Resource, 0, len(keys))
+	for _, resource := range loaded {
+		if resource != nil {
+			ret = append(ret, resource)
+		}
+	}
+
+	return ret, nil
+}
+
+// loadObject loads the resource of the key, the failures are reported to onError, if not nil, as by retryLoad.
+// It returns a nil resource if the key is skipped.
+func loadObject(ctx context.Context, key string, onError func(Resource, error) Decision, load func(key string) (Resource, error)) (Resource, error) {
+	for {
+		resource, err := load(key)
+		if err == nil || onError == nil {
+
+			return resource, err
+		}
+		switch onError(resource, err) {
+		case DecisionSkip:
+			contextLog(ctx).Warnf("Skipping resource %v that failed to load : %v", resource, err)
+
+			return nil, nil
+		case DecisionRetry:
+			contextLog(ctx).Debugf("Retrying resource %v that failed to load : %v", resource, err)
+		default:
+
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ResourceMetadata tells which version of which source the content of a resource is, so the rules compiled into a
+// knowledge base can be traced back to the exact files they come from.
+type ResourceMetadata struct {
+	// Origin is where the resource comes from, such as the path of the file, the URL, or the GIT URL and path.
+	Origin string `json:"origin"`
+	// Version identifies the version of the content the source knows of: the commit of a GIT resource, the ETag of
+	// an URL resource. Empty if the source has none, such as a file.
+	Version string `json:"version,omitempty"`
+	// ETag is the ETag an URL resource was served with.
+	ETag string `json:"etag,omitempty"`
+	// Commit is the hash of the commit a GIT resource was checked out at.
+	Commit string `json:"commit,omitempty"`
+	// LastModified is when the content last changed: the modification time of a file, the Last-Modified of an URL
+	// resource or the time of the commit of a GIT resource. Zero if unknown.
+	LastModified time.Time `json:"lastModified,omitempty"`
+	// Checksum is the hexadecimal SHA-256 of the content, filled by MetadataOf.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// ResourceInfo is a Resource describing its source. The metadata are complete once the resource is loaded.
+// FileResource, URLResource and GITResource implement it, the wrapping resources such as CompressedResource
+// describe the resource they wrap.
+type ResourceInfo interface {
+	Resource
+	Metadata() ResourceMetadata
+}
+
+// MetadataOf returns the metadata of the resource and the checksum of its loaded content. A resource that is not a
+// ResourceInfo is described by its String only.
+func MetadataOf(res Resource, data []byte) ResourceMetadata {
+	metadata := metadataOf(res)
+	sum := sha256.Sum256(data)
+	metadata.Checksum = hex.EncodeToString(sum[:])
+
+	return metadata
+}
+
+// metadataOf returns the metadata of the resource, its String as origin if it is not a ResourceInfo.
+func metadataOf(res Resource) ResourceMetadata {
+	var metadata ResourceMetadata
+	if info, ok := res.(ResourceInfo); ok {
+		metadata = info.Metadata()
+	}
+	if len(metadata.Origin) == 0 {
+		metadata.Origin = res.String()
+	}
+
+	return metadata
+}
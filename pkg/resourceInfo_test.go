@@ -0,0 +1,87 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataOf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.grl")
+	assert.NoError(t, os.WriteFile(path, []byte("rule A {}"), 0o600))
+	modified := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, modified, modified))
+
+	res := NewFileResource(path)
+	data, err := res.Load()
+	assert.NoError(t, err)
+	metadata := MetadataOf(res, data)
+	assert.Equal(t, path, metadata.Origin)
+	assert.True(t, modified.Equal(metadata.LastModified))
+	assert.Equal(t, "7ffe5d230f10826fb3457857a014228093ed8666125217fe5152850298cbf45a", metadata.Checksum)
+
+	// a wrapping resource describes the resource it wraps.
+	assert.Equal(t, path, MetadataOf(NewCompressedResource(res), data).Origin)
+	assert.Equal(t, "Byte array resources 9 bytes", MetadataOf(NewBytesResource(data), data).Origin)
+	assert.Equal(t, metadata.Checksum, MetadataOf(NewBytesResource(data), data).Checksum)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v42"`)
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+	urlRes := NewURLResource(server.URL)
+	_, err = urlRes.Load()
+	assert.NoError(t, err)
+	metadata = MetadataOf(urlRes, data)
+	assert.Equal(t, server.URL, metadata.Origin)
+	assert.Equal(t, `"v42"`, metadata.Version)
+	assert.Equal(t, `"v42"`, metadata.ETag)
+	assert.True(t, modified.Equal(metadata.LastModified))
+}
+
+func TestMetadataOf_GIT(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is needed to clone a local repository")
+	}
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	assert.NoError(t, err)
+	head := commitRule(t, repository, dir, "rule A {}")
+
+	for _, cacheDir := range []string{"", t.TempDir()} {
+		bundle := NewGITResourceBundle("file://"+dir, "/**/*.grl")
+		bundle.CacheDir = cacheDir
+		resources, err := bundle.Load()
+		assert.NoError(t, err)
+		if assert.Len(t, resources, 1) {
+			metadata := MetadataOf(resources[0], nil)
+			assert.Equal(t, head.String(), metadata.Commit)
+			assert.Equal(t, head.String(), metadata.Version)
+			assert.Equal(t, "file://"+dir+"#/rules.grl", metadata.Origin)
+			assert.False(t, metadata.LastModified.IsZero())
+		}
+	}
+}
@@ -15,11 +15,33 @@
 package pkg
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -40,7 +62,10 @@ func TestFileResourceBundle_Load(t *testing.T) {
 	} else {
 		frb = NewFileResourceBundle(path, "/**/*.grl")
 	}
-	resources := frb.MustLoad()
+	resources, err := frb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(resources) != 6 {
 		t.Errorf("Expected 6 but get %d", len(resources))
 		t.FailNow()
@@ -111,7 +136,10 @@ func TestGitResource(t *testing.T) {
 			"/antlr/*.grl",
 		},
 	}
-	resources := gitRb.MustLoad()
+	resources, err := gitRb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(resources) != 2 {
 		t.Logf("Expected 2 drl but %d", len(resources))
 	}
@@ -123,3 +151,876 @@ func TestGitResource(t *testing.T) {
 		}
 	}
 }
+
+func TestFileResourceBundle_LoadRelativePattern(t *testing.T) {
+	frb := NewFileResourceBundle("test", "subfold2/**/*.grl")
+	resources, err := frb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 4 {
+		t.Fatalf("Expected 4 but get %d", len(resources))
+	}
+	for _, resource := range resources {
+		fres := resource.(*FileResource)
+		if !filepath.IsAbs(fres.Path) {
+			t.Errorf("Expect absolute path but %s", fres.Path)
+		}
+		if !strings.Contains(fres.Path, filepath.Join("test", "subfold2")) {
+			t.Errorf("Expect resource to be under subfold2 but %s", fres.Path)
+		}
+	}
+
+	frb = NewFileResourceBundle("test", "*.grl")
+	if resources, err := frb.Load(); err != nil {
+		t.Error(err)
+	} else if len(resources) != 0 {
+		t.Errorf("Expected 0 but get %d", len(resources))
+	}
+}
+
+func TestFileResourceBundle_LoadWindowsStylePattern(t *testing.T) {
+	frb := NewFileResourceBundle("test", "subfold2\\subfold21\\*.grl", "subfold1\\GrlFile11.grl")
+	resources, err := frb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 but get %d", len(resources))
+	}
+	if !strings.HasSuffix(resources[0].String(), "GrlFile11.grl") {
+		t.Errorf("Expect [0] to have suffix GrlFile11.grl. But %s", resources[0].String())
+	}
+	if !strings.HasSuffix(resources[2].String(), "GrlFile212.grl") {
+		t.Errorf("Expect [2] to have suffix GrlFile212.grl. But %s", resources[2].String())
+	}
+
+	absPath, err := filepath.Abs("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	frb = NewFileResourceBundle("test", strings.ReplaceAll(filepath.Join(absPath, "subfold1", "*.grl"), "/", "\\"))
+	if resources, err := frb.Load(); err != nil {
+		t.Error(err)
+	} else if len(resources) != 2 {
+		t.Errorf("Expected 2 but get %d", len(resources))
+	}
+}
+
+func TestFileResourceBundle_LoadSymlinkedDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	realDir := filepath.Join(tmpDir, "real")
+	if err := os.MkdirAll(filepath.Join(realDir, "inner"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(realDir, "inner", "Rule.grl"), []byte(loremipsum), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baseDir := filepath.Join(tmpDir, "base")
+	if err := os.Mkdir(baseDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(realDir, filepath.Join(baseDir, "linked")); err != nil {
+		t.Skipf("symbolic link is not supported : %v", err)
+	}
+	// a link back to the base directory must not be followed.
+	if err := os.Symlink(baseDir, filepath.Join(realDir, "loop")); err != nil {
+		t.Fatal(err)
+	}
+	// a dangling link is ignored.
+	if err := os.Symlink(filepath.Join(tmpDir, "missing"), filepath.Join(baseDir, "dangling.grl")); err != nil {
+		t.Fatal(err)
+	}
+
+	frb := NewFileResourceBundle(baseDir, "**/*.grl")
+	resources, err := frb.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 but get %d", len(resources))
+	}
+	fres := resources[0].(*FileResource)
+	if fres.Path != filepath.Join(baseDir, "linked", "inner", "Rule.grl") {
+		t.Errorf("Unexpected resource path %s", fres.Path)
+	}
+	if string(fres.Bytes) != loremipsum {
+		t.Errorf("Unexpected resource content")
+	}
+}
+
+func TestURLResource_Client(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(loremipsum))
+	}))
+	defer server.Close()
+
+	// the default client does not trust the test server certificate.
+	if _, err := NewURLResource(server.URL).Load(); err == nil {
+		t.Fatal("Expected an error loading with the default client")
+	}
+	loaded, err := NewURLResourceWithClient(server.URL, server.Client()).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(loaded) != loremipsum {
+		t.Errorf("Expected %d bytes but %d", len(loremipsum), len(loaded))
+	}
+}
+
+func TestGITResourceBundle_HTTPClient(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	bundle := NewGITResourceBundle(server.URL+"/rules.git", "/**/*.grl")
+	if _, err := bundle.Load(); err == nil {
+		t.Fatal("Expected an error cloning with the default client")
+	}
+	if requests.Load() != 0 {
+		t.Fatalf("Expected no request reaching the server but %d", requests.Load())
+	}
+
+	bundle.HTTPClient = server.Client()
+	if _, err := bundle.Load(); err == nil {
+		t.Fatal("Expected an error cloning a repository that does not exist")
+	}
+	if requests.Load() == 0 {
+		t.Fatal("Expected the repository cloned with the bundle client")
+	}
+}
+
+func TestGITResourceBundle_HTTPClientPerBundle(t *testing.T) {
+	first, second := &http.Client{}, &http.Client{}
+	unregister, err := gitHTTPClients.register(context.Background(), "https://git.example.com/org/rules.git", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another bundle of the same repository waits for the clone with the first client.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := gitHTTPClients.register(ctx, "https://git.example.com/org/rules.git", second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the second client waiting for the first clone but %v", err)
+	}
+	registered := make(chan func())
+	go func() {
+		unregisterSecond, err := gitHTTPClients.register(context.Background(), "https://git.example.com/org/rules.git", second)
+		if err != nil {
+			t.Error(err)
+		}
+		registered <- unregisterSecond
+	}()
+	unregister()
+	unregisterSecond := <-registered
+	endpoint, _ := transport.NewEndpoint("https://git.example.com/org/rules.git")
+	gitHTTPClients.mutex.Lock()
+	if gitHTTPClients.clients[endpoint.String()] != second {
+		t.Error("Expected the repository cloned with the second client once the first clone is done")
+	}
+	gitHTTPClients.mutex.Unlock()
+	unregisterSecond()
+}
+
+func TestURLResource_LoadContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte(loremipsum))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	res := NewURLResource(server.URL).(*URLResource)
+	if _, err := res.LoadContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline exceeded but %v", err)
+	}
+	if res.Bytes != nil {
+		t.Fatal("Expected nothing cached after a failed load")
+	}
+}
+
+func TestFileResourceBundle_LoadContext(t *testing.T) {
+	bundle := NewFileResourceBundle("test", "**/*.grl")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bundle.LoadContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the load cancelled but %v", err)
+	}
+	resources, err := bundle.LoadContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) == 0 {
+		t.Fatal("Expected the rule files loaded")
+	}
+}
+
+// blockingBundle is a ResourceBundle without context support, its Load waits until released.
+type blockingBundle struct {
+	release chan struct{}
+}
+
+func (bundle *blockingBundle) Load() ([]Resource, error) {
+	<-bundle.release
+
+	return []Resource{NewBytesResource([]byte(loremipsum))}, nil
+}
+
+func (bundle *blockingBundle) MustLoad() []Resource {
+	resources, _ := bundle.Load()
+
+	return resources
+}
+
+func TestLoadBundle(t *testing.T) {
+	bundle := &blockingBundle{release: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := LoadBundle(ctx, bundle); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline exceeded but %v", err)
+	}
+
+	close(bundle.release)
+	resources, err := LoadBundle(context.Background(), bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 resource but %d", len(resources))
+	}
+}
+
+func TestURLResource_Refresh(t *testing.T) {
+	var mutex sync.Mutex
+	content, etag := "rule A", `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 12 Oct 2026 10:00:00 GMT")
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+	set := func(newContent, newEtag string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		content, etag = newContent, newEtag
+	}
+
+	header := make(http.Header)
+	header.Set("Authorization", "Bearer token")
+	res := NewURLResourceWithHeaders(server.URL, header).(*URLResource)
+	changed, err := res.Refresh()
+	if err != nil || !changed || string(res.Bytes) != "rule A" {
+		t.Fatalf("Expected the first refresh to load the resource but %v %v %q", changed, err, res.Bytes)
+	}
+	if res.ETag != `"v1"` || res.LastModified != "Mon, 12 Oct 2026 10:00:00 GMT" {
+		t.Fatalf("Expected the validators kept but %q %q", res.ETag, res.LastModified)
+	}
+	if changed, err = res.Refresh(); err != nil || changed {
+		t.Fatalf("Expected not modified but %v %v", changed, err)
+	}
+	if header.Get("If-None-Match") != "" {
+		t.Fatal("Expected the headers of the resource not modified")
+	}
+
+	// a new version with the same bytes is not a change.
+	set("rule A", `"v2"`)
+	if changed, err = res.Refresh(); err != nil || changed || res.ETag != `"v2"` {
+		t.Fatalf("Expected the same bytes but %v %v %q", changed, err, res.ETag)
+	}
+	set("rule B", `"v3"`)
+	if changed, err = res.Refresh(); err != nil || !changed || string(res.Bytes) != "rule B" {
+		t.Fatalf("Expected the new bytes but %v %v %q", changed, err, res.Bytes)
+	}
+
+	res.Header = make(http.Header)
+	if _, err = res.Refresh(); err == nil || !strings.Contains(err.Error(), "401 Unauthorized") {
+		t.Fatalf("Expected the refresh to fail but %v", err)
+	}
+	if string(res.Bytes) != "rule B" {
+		t.Fatal("Expected the bytes kept after a failed refresh")
+	}
+}
+
+func TestURLResource_Watch(t *testing.T) {
+	var mutex sync.Mutex
+	content := "rule A"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("If-None-Match") == `"`+content+`"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+		w.Header().Set("ETag", `"`+content+`"`)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	res := NewURLResource(server.URL).(*URLResource)
+	res.PollInterval = 10 * time.Millisecond
+	changes := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- res.Watch(ctx, func(data []byte, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			changes <- string(data)
+		})
+	}()
+
+	if change := <-changes; change != "rule A" {
+		t.Fatalf("Expected the first load but %q", change)
+	}
+	mutex.Lock()
+	content = "rule B"
+	mutex.Unlock()
+	select {
+	case change := <-changes:
+		if change != "rule B" {
+			t.Fatalf("Expected the change but %q", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a change")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the watch cancelled but %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("Expected no change while the content is the same but %d", len(changes))
+	}
+}
+
+func TestGITResourceBundle_LoadContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var bundle ResourceBundleWithContext = NewGITResourceBundle(server.URL+"/rules.git", "/**/*.grl")
+	start := time.Now()
+	if _, err := bundle.LoadContext(ctx); err == nil {
+		t.Fatal("Expected an error cloning past the deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected the clone given up at the deadline but it took %s", elapsed)
+	}
+}
+
+// commitRule writes the rule file of the repository at dir and commits it.
+func commitRule(t *testing.T, repository *git.Repository, dir, content string) plumbing.Hash {
+	if err := os.WriteFile(filepath.Join(dir, "rules.grl"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("rules.grl"); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := worktree.Commit(content, &git.CommitOptions{
+		Author: &object.Signature{Name: "grule", Email: "grule@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash
+}
+
+func TestGITResourceBundle_Pinning(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is needed to clone a local repository")
+	}
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := commitRule(t, repository, dir, "first")
+	if _, err := repository.CreateTag("v1.0.0", first, nil); err != nil {
+		t.Fatal(err)
+	}
+	commitRule(t, repository, dir, "second")
+
+	load := func(bundle *GITResourceBundle) (string, error) {
+		resources, err := bundle.Load()
+		if err != nil {
+
+			return "", err
+		}
+		if len(resources) != 1 {
+			t.Fatalf("Expected 1 resource but %d", len(resources))
+		}
+		data, err := resources[0].Load()
+
+		return string(data), err
+	}
+
+	bundle := NewGITResourceBundle("file://"+dir, "/**/*.grl")
+	if content, err := load(bundle); err != nil || content != "second" {
+		t.Fatalf("Expected the master branch but %q, %v", content, err)
+	}
+
+	bundle = NewGITResourceBundle("file://"+dir, "/**/*.grl")
+	bundle.Tag = "v1.0.0"
+	if content, err := load(bundle); err != nil || content != "first" {
+		t.Fatalf("Expected the tagged commit but %q, %v", content, err)
+	}
+	bundle.Depth = 1
+	if content, err := load(bundle); err != nil || content != "first" {
+		t.Fatalf("Expected a shallow clone of the tagged commit but %q, %v", content, err)
+	}
+
+	bundle = NewGITResourceBundle("file://"+dir, "/**/*.grl")
+	bundle.CommitHash = first.String()
+	if content, err := load(bundle); err != nil || content != "first" {
+		t.Fatalf("Expected the pinned commit but %q, %v", content, err)
+	}
+
+	bundle.RefName = "refs/heads/master"
+	bundle.Depth = 1
+	if _, err := load(bundle); err == nil {
+		t.Fatal("Expected an error checking out a commit older than the shallow clone")
+	}
+
+	bundle = NewGITResourceBundle("file://"+dir, "/**/*.grl")
+	bundle.CommitHash = first.String()[:7]
+	if _, err := load(bundle); err == nil {
+		t.Fatal("Expected an error pinning an abbreviated hash")
+	}
+	bundle = NewGITResourceBundle("file://"+dir, "/**/*.grl")
+	bundle.Tag = "v1.0.0"
+	bundle.RefName = "refs/heads/master"
+	if _, err := load(bundle); err == nil {
+		t.Fatal("Expected an error specifying both a ref name and a tag")
+	}
+}
+
+func TestGITResourceBundle_TokenAndProxy(t *testing.T) {
+	var host, authorization, proxyAuthorization atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.Store(r.Host)
+		authorization.Store(r.Header.Get("Authorization"))
+		proxyAuthorization.Store(r.Header.Get("Proxy-Authorization"))
+		http.NotFound(w, r)
+	}))
+	defer proxy.Close()
+
+	bundle := NewGITResourceBundleWithToken("http://git.corp.example/rules.git", "secret", "/**/*.grl")
+	bundle.ProxyURL = proxy.URL
+	bundle.ProxyUser = "proxyuser"
+	bundle.ProxyPassword = "proxypassword"
+	if _, err := bundle.Load(); err == nil {
+		t.Fatal("Expected an error cloning a repository that does not exist")
+	}
+	if host.Load() != "git.corp.example" {
+		t.Fatalf("Expected the clone going through the proxy but %v", host.Load())
+	}
+	if authorization.Load() != "Bearer secret" {
+		t.Fatalf("Expected the token as a bearer token but %v", authorization.Load())
+	}
+	if proxyAuthorization.Load() != "Basic cHJveHl1c2VyOnByb3h5cGFzc3dvcmQ=" {
+		t.Fatalf("Expected the proxy credentials but %v", proxyAuthorization.Load())
+	}
+
+	bundle.User = "x-access-token"
+	if _, err := bundle.Load(); err == nil {
+		t.Fatal("Expected an error cloning a repository that does not exist")
+	}
+	if authorization.Load() != "Basic eC1hY2Nlc3MtdG9rZW46c2VjcmV0" {
+		t.Fatalf("Expected the token as the password of the user but %v", authorization.Load())
+	}
+}
+
+func TestGITResourceBundle_CacheDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is needed to clone a local repository")
+	}
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := commitRule(t, repository, dir, "first")
+	if _, err := repository.CreateTag("v1.0.0", first, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := t.TempDir()
+	load := func(bundle *GITResourceBundle) string {
+		bundle.CacheDir = cacheDir
+		resources, err := bundle.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resources) != 1 {
+			t.Fatalf("Expected 1 resource but %d", len(resources))
+		}
+		data, err := resources[0].Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(data)
+	}
+	url := "file://" + dir
+	if content := load(NewGITResourceBundle(url, "/**/*.grl")); content != "first" {
+		t.Fatalf("Expected the first commit but %q", content)
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected one clone in the cache directory but %d", len(entries))
+	}
+
+	commitRule(t, repository, dir, "second")
+	if content := load(NewGITResourceBundle(url, "/**/*.grl")); content != "second" {
+		t.Fatalf("Expected the fetched commit but %q", content)
+	}
+	tagged := NewGITResourceBundle(url, "/**/*.grl")
+	tagged.Tag = "v1.0.0"
+	if content := load(tagged); content != "first" {
+		t.Fatalf("Expected the tagged commit but %q", content)
+	}
+	pinned := NewGITResourceBundle(url, "/**/*.grl")
+	pinned.CommitHash = first.String()
+	if content := load(pinned); content != "first" {
+		t.Fatalf("Expected the pinned commit but %q", content)
+	}
+	if content := load(NewGITResourceBundle(url, "/**/*.grl")); content != "second" {
+		t.Fatalf("Expected the master branch checked out again but %q", content)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 1 {
+		t.Fatalf("Expected the clone reused but %d in the cache directory", len(entries))
+	}
+}
+
+// failingFS fails to open its failing files as many times as their count.
+type failingFS struct {
+	fs.FS
+	failing map[string]int
+}
+
+func (fsys *failingFS) Open(name string) (fs.File, error) {
+	if fsys.failing[name] > 0 {
+		fsys.failing[name]--
+
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	return fsys.FS.Open(name)
+}
+
+func TestFileResourceBundle_LoadWithHandler(t *testing.T) {
+	files := fstest.MapFS{
+		"a.grl":     {Data: []byte("a")},
+		"b.grl":     {Data: []byte("b")},
+		"sub/c.grl": {Data: []byte("c")},
+	}
+	bundle := NewFileResourceBundle("/rules", "**/*.grl")
+
+	fsys := &failingFS{FS: files, failing: map[string]int{"b.grl": 1}}
+	if _, err := bundle.loadFS(context.Background(), fsys, "/rules", nil); !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("Expected the loading aborted without handler but %v", err)
+	}
+
+	failed := make([]string, 0)
+	fsys = &failingFS{FS: files, failing: map[string]int{"b.grl": 1, "sub/c.grl": 2}}
+	resources, err := bundle.loadFS(context.Background(), fsys, "/rules", func(resource Resource, err error) Decision {
+		failed = append(failed, resource.String())
+		if strings.HasSuffix(resource.String(), "b.grl") {
+
+			return DecisionSkip
+		}
+
+		return DecisionRetry
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources but %d", len(resources))
+	}
+	if data, _ := resources[1].Load(); string(data) != "c" {
+		t.Fatalf("Expected the retried file loaded but %q", data)
+	}
+	if len(failed) != 3 {
+		t.Fatalf("Expected 3 failures reported but %v", failed)
+	}
+
+	fsys = &failingFS{FS: files, failing: map[string]int{"a.grl": 1}}
+	if _, err := bundle.loadFS(context.Background(), fsys, "/rules", func(Resource, error) Decision {
+
+		return DecisionAbort
+	}); !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("Expected the loading aborted by the handler but %v", err)
+	}
+}
+
+// flakyBundle is a ResourceBundle without handler support, its Load fails as many times as failures.
+type flakyBundle struct {
+	failures int
+}
+
+func (bundle *flakyBundle) Load() ([]Resource, error) {
+	if bundle.failures > 0 {
+		bundle.failures--
+
+		return nil, errors.New("unavailable")
+	}
+
+	return []Resource{NewBytesResource([]byte(loremipsum))}, nil
+}
+
+func (bundle *flakyBundle) MustLoad() []Resource {
+	resources, _ := bundle.Load()
+
+	return resources
+}
+
+func TestFileResourceBundle_LoadWithHandlerLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logrusLogger := logrus.New()
+	logrusLogger.SetOutput(buffer)
+	log, ok := logger.FromExternal(logrusLogger)
+	if !ok {
+		t.Fatal("Expected the logrus logger accepted")
+	}
+	ctx := logger.NewContext(context.Background(), log)
+
+	files := fstest.MapFS{"a.grl": {Data: []byte("a")}, "b.grl": {Data: []byte("b")}}
+	fsys := &failingFS{FS: files, failing: map[string]int{"b.grl": 1}}
+	resources, err := NewFileResourceBundle("/rules", "**/*.grl").loadFS(ctx, fsys, "/rules", func(Resource, error) Decision {
+
+		return DecisionSkip
+	})
+	if err != nil || len(resources) != 1 {
+		t.Fatalf("Expected the failing file skipped but %v, %v", resources, err)
+	}
+	if !strings.Contains(buffer.String(), "Skipping resource") {
+		t.Fatalf("Expected the skipped file logged through the logger of the context but %q", buffer.String())
+	}
+}
+
+func TestLoadBundleWithHandler(t *testing.T) {
+	retry := func(resource Resource, err error) Decision {
+		if resource != nil {
+			t.Fatalf("Expected no resource for a bundle without handler but %v", resource)
+		}
+
+		return DecisionRetry
+	}
+	resources, err := LoadBundleWithHandler(&flakyBundle{failures: 2}, retry)
+	if err != nil || len(resources) != 1 {
+		t.Fatalf("Expected the bundle loaded once retried but %v, %v", resources, err)
+	}
+
+	skip := func(Resource, error) Decision {
+
+		return DecisionSkip
+	}
+	resources, err = LoadBundleWithHandler(&flakyBundle{failures: 1}, skip)
+	if err != nil || len(resources) != 0 {
+		t.Fatalf("Expected the bundle skipped but %v, %v", resources, err)
+	}
+
+	multi := NewMultiResourceBundle(&flakyBundle{failures: 1}, NewFileResourceBundle("test", "**/*.grl"))
+	resources, err = multi.LoadWithHandler(skip)
+	if err != nil || len(resources) == 0 {
+		t.Fatalf("Expected the rule files loaded without the skipped bundle but %v, %v", resources, err)
+	}
+
+	// the wrapping bundles hand the handler to the bundle they wrap.
+	json, _ := NewJSONResourceBundleFromBundle(&flakyBundle{failures: 1})
+	for _, bundle := range []ResourceBundle{json, NewCompressedResourceBundle(&flakyBundle{failures: 1}), NewEncryptedResourceBundle(&flakyBundle{failures: 1}, nil)} {
+		resources, err = bundle.(ResourceBundleWithHandler).LoadWithHandler(retry)
+		if err != nil || len(resources) != 1 {
+			t.Fatalf("Expected the wrapped bundle loaded once retried but %v, %v", resources, err)
+		}
+	}
+}
+
+func TestURLResource_Retry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(loremipsum))
+		}
+	}))
+	defer server.Close()
+
+	// without retry, the failure of the server is not taken for the rules.
+	if _, err := NewURLResource(server.URL).Load(); err == nil {
+		t.Fatal("Expected an error loading a 503 response")
+	}
+	requests.Store(0)
+	retry := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	loaded, err := NewURLResourceWithRetry(server.URL, server.Client(), retry).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(loaded) != loremipsum || requests.Load() != 3 {
+		t.Fatalf("Expected the rules at the third attempt but %d bytes at attempt %d", len(loaded), requests.Load())
+	}
+
+	requests.Store(0)
+	retry.MaxAttempts = 2
+	if _, err := NewURLResourceWithRetry(server.URL, nil, retry).Load(); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("Expected the last response an error once the attempts are exhausted but %v", err)
+	}
+
+	requests.Store(0)
+	retry.RetryableStatusCodes = []int{http.StatusInternalServerError}
+	if _, err := NewURLResourceWithRetry(server.URL, nil, retry).Load(); err == nil || requests.Load() != 1 {
+		t.Fatalf("Expected a status code that is not retryable failing at once but %v after %d attempts", err, requests.Load())
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if wait := policy.backoff(attempt+1, nil); wait != expected {
+			t.Errorf("Expected a wait of %s after attempt %d but %s", expected, attempt+1, wait)
+		}
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if wait := policy.backoff(1, resp); wait != 3*time.Second {
+		t.Errorf("Expected the Retry-After of the server honored but %s", wait)
+	}
+}
+
+func TestURLResource_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	res := NewURLResource(server.URL).(*URLResource)
+	res.Timeout = 50 * time.Millisecond
+	if _, err := res.Load(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline exceeded but %v", err)
+	}
+}
+
+// brokenFS is a fs.FS failing to open the file named broken.
+type brokenFS struct {
+	fs.FS
+	broken string
+}
+
+func (fsys brokenFS) Open(name string) (fs.File, error) {
+	if name == fsys.broken {
+
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	return fsys.FS.Open(name)
+}
+
+func TestFileResourceBundle_Concurrency(t *testing.T) {
+	files := fstest.MapFS{}
+	for i := 0; i < 200; i++ {
+		files[fmt.Sprintf("dir%d/rule%03d.grl", i%7, i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("rule %d", i))}
+		files[fmt.Sprintf("dir%d/readme%03d.md", i%7, i)] = &fstest.MapFile{Data: []byte("not a rule")}
+	}
+	bundle := NewFileResourceBundle("/rules", "**/*.grl")
+	sequential, err := bundle.loadFS(context.Background(), files, "/rules", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.Concurrency = 8
+	concurrent, err := bundle.loadFS(context.Background(), files, "/rules", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sequential) != 200 || len(concurrent) != len(sequential) {
+		t.Fatalf("Expected 200 resources loaded either way but %d and %d", len(sequential), len(concurrent))
+	}
+	for i := range sequential {
+		if sequential[i].String() != concurrent[i].String() {
+			t.Fatalf("Expected resource %d to be %s but %s", i, sequential[i], concurrent[i])
+		}
+	}
+
+	if _, err := bundle.loadFS(context.Background(), brokenFS{FS: files, broken: "dir0/rule042.grl"}, "/rules", nil); !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("Expected the loading aborted by the broken file but %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bundle.loadFS(ctx, files, "/rules", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the loading canceled but %v", err)
+	}
+}
+
+func TestGITResourceBundle_Concurrency(t *testing.T) {
+	fileSystem := memfs.New()
+	for i := 0; i < 50; i++ {
+		if err := util.WriteFile(fileSystem, fmt.Sprintf("rules/set%d/rule%02d.grl", i%3, i), []byte(fmt.Sprintf("rule %d", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bundle := NewGITResourceBundle("https://example.com/rules.git", "/**/*.grl")
+	sequential, err := bundle.loadPath(context.Background(), bundle.URL, fileSystem, gitRevision{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.Concurrency = 4
+	concurrent, err := bundle.loadPath(context.Background(), bundle.URL, fileSystem, gitRevision{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sequential) != 50 || len(concurrent) != len(sequential) {
+		t.Fatalf("Expected 50 resources loaded either way but %d and %d", len(sequential), len(concurrent))
+	}
+	for i := range sequential {
+		if sequential[i].String() != concurrent[i].String() {
+			t.Fatalf("Expected resource %d to be %s but %s", i, sequential[i], concurrent[i])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewRetryPolicy creates new RetryPolicy making at most maxAttempts attempts, with the default backoff.
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+
+	return &RetryPolicy{MaxAttempts: maxAttempts}
+}
+
+// RetryPolicy tells how a request that failed is sent again: how many times, how long to wait in between and which
+// responses are failures worth retrying. The wait grows exponentially from InitialBackoff up to MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, the first one included. Zero or one sends the request once.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt, 100 milliseconds if zero.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts, 30 seconds if zero.
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after every attempt, 2 if zero.
+	Multiplier float64
+	// RetryableStatusCodes are the status codes of the responses retried, 408, 429, 500, 502, 503 and 504 if empty.
+	// The errors of the client, such as a refused connection, are always retried.
+	RetryableStatusCodes []int
+}
+
+// retryable tells if a response of the status code is retried.
+func (policy *RetryPolicy) retryable(statusCode int) bool {
+	codes := policy.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = []int{http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	for _, code := range codes {
+		if code == statusCode {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the wait before the attempt following the given failed one, attempts are counted from 1.
+// A Retry-After the server answered with is honored, within MaxBackoff.
+func (policy *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	wait, maxWait, multiplier := policy.InitialBackoff, policy.MaxBackoff, policy.Multiplier
+	if wait <= 0 {
+		wait = 100 * time.Millisecond
+	}
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	for i := 1; i < attempt && wait < maxWait; i++ {
+		wait = time.Duration(float64(wait) * multiplier)
+	}
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > wait {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+	if wait > maxWait {
+		wait = maxWait
+	}
+
+	return wait
+}
+
+// do sends the request made by newRequest with the client until a response is not to be retried, the attempts are
+// exhausted or the context is done. The response of the last attempt is returned, whatever its status code. A nil
+// policy sends the request once.
+func (policy *RetryPolicy) do(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	attempts := 1
+	if policy != nil && policy.MaxAttempts > 1 {
+		attempts = policy.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if attempt >= attempts || ctx.Err() != nil || (err == nil && !policy.retryable(resp.StatusCode)) {
+
+			return resp, err
+		}
+		wait := policy.backoff(attempt, resp)
+		if err != nil {
+			contextLog(ctx).Debugf("Attempt %d of %d to get %s failed, retrying in %s. got %v", attempt, attempts, req.URL, wait, err)
+		} else {
+			contextLog(ctx).Debugf("Attempt %d of %d to get %s answered %s, retrying in %s", attempt, attempts, req.URL, resp.Status, wait)
+			// the body is drained so the connection goes back to the pool.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
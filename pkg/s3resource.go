@@ -0,0 +1,406 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3EmptyPayloadHash is the SHA256 of the empty body of the GET requests.
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// NewS3ResourceBundle will create a new instance of S3ResourceBundle.
+// bucket is the name of the bucket, prefix only lists the objects whose key starts with it.
+// pathPattern are list of key patterns (glob), relative to the prefix, to filter the objects.
+// The credentials and the region are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+// and AWS_REGION environment variables, unless they are set in the bundle.
+func NewS3ResourceBundle(bucket, prefix string, pathPattern ...string) *S3ResourceBundle {
+
+	return &S3ResourceBundle{
+		Bucket:      bucket,
+		Prefix:      prefix,
+		PathPattern: pathPattern,
+	}
+}
+
+// S3ResourceBundle is a helper struct to load multiple objects from an S3 bucket all at once by specifying
+// the prefix of their keys and the key pattern to look for. It speaks the S3 REST API, so it also works with
+// S3 compatible storages such as MinIO.
+type S3ResourceBundle struct {
+	// The bucket name
+	Bucket string
+	// Only the objects whose key starts with the prefix are listed, such as "rules/".
+	Prefix string
+	// List Glob like key pattern, relative to the prefix.
+	// *.grl           <- matches rules/abc.grl but not rules/anyfolder/abc.grl with the prefix "rules/"
+	// **/*.grl        <- matches rules/abc.grl or rules/abc/def.grl
+	// /rules/**/*.grl <- an absolute pattern is matched against the whole key
+	PathPattern []string
+	// The region of the bucket, if empty AWS_REGION, AWS_DEFAULT_REGION or else us-east-1 is used.
+	Region string
+	// Endpoint of an S3 compatible storage, such as http://localhost:9000. Its objects are addressed by path,
+	// as in http://localhost:9000/bucket/key. If empty, the AWS virtual hosted endpoint of the bucket is used.
+	Endpoint string
+	// AccessKeyID and SecretAccessKey sign the requests, if both are empty the environment variables are used.
+	// The requests are not signed if there are no credentials at all, such as for a public bucket.
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken of temporary credentials.
+	SessionToken string
+	// HTTPClient, if set, sends the requests, such as a client going through a proxy.
+	HTTPClient *http.Client
+	// Concurrency is the number of objects downloaded at the same time, 8 if not positive.
+	Concurrency int
+	// Timeout bounds every request, LoadContext included. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// s3Credentials are the resolved settings used to sign the requests.
+type s3Credentials struct {
+	region       string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+}
+
+// s3ListBucketResult is the response of ListObjectsV2.
+type s3ListBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+}
+
+// s3Error is the error response of the S3 API.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// Load lists the objects under the Prefix and downloads all of those that conform to the PathPattern.
+// The resources are returned in the order of their keys.
+func (bundle *S3ResourceBundle) Load() ([]Resource, error) {
+
+	return bundle.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the requests are cancelled once the context is done.
+// Each of them still times out after Timeout.
+func (bundle *S3ResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the objects failing to download are reported to onError.
+func (bundle *S3ResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+
+	return bundle.load(context.Background(), onError)
+}
+
+// load downloads the objects matching the PathPattern, the objects failing to download are reported to onError, if not nil.
+func (bundle *S3ResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	creds := bundle.credentials()
+	keys, err := bundle.list(ctx, creds)
+	if err != nil {
+
+		return nil, err
+	}
+	matched, err := matchObjectKeys(keys, bundle.Prefix, bundle.PathPattern)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return loadObjects(ctx, matched, bundle.Concurrency, onError, func(key string) (Resource, error) {
+		contextLog(ctx).Debugf("Loading S3 object %s/%s", bundle.Bucket, key)
+		res := &S3Resource{
+			Bucket: bundle.Bucket,
+			Key:    key,
+		}
+		var err error
+		res.Bytes, err = bundle.get(ctx, creds, key)
+
+		return res, err
+	})
+}
+
+// MustLoad is the same as Load, the difference is it will panic if an error is raised during fetching resources.
+//
+// Deprecated: use S3ResourceBundle.LoadContext, or S3ResourceBundle.LoadWithHandler to go on without the objects
+// failing to download.
+func (bundle *S3ResourceBundle) MustLoad() []Resource {
+	res, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return res
+}
+
+// credentials resolves the region and the credentials from the bundle or else the environment variables.
+func (bundle *S3ResourceBundle) credentials() s3Credentials {
+	creds := s3Credentials{
+		region:       bundle.Region,
+		accessKeyID:  bundle.AccessKeyID,
+		secretKey:    bundle.SecretAccessKey,
+		sessionToken: bundle.SessionToken,
+	}
+	if len(creds.region) == 0 {
+		creds.region = os.Getenv("AWS_REGION")
+	}
+	if len(creds.region) == 0 {
+		creds.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if len(creds.region) == 0 {
+		creds.region = "us-east-1"
+	}
+	if len(creds.accessKeyID) == 0 && len(creds.secretKey) == 0 {
+		creds.accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		creds.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		creds.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+
+	return creds
+}
+
+// objectURL returns the URL of the key in the bucket, the URL of the bucket itself if the key is empty.
+func (bundle *S3ResourceBundle) objectURL(region, key string) (*url.URL, error) {
+	var base *url.URL
+	var err error
+	path := "/" + key
+	if len(bundle.Endpoint) == 0 {
+		base, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bundle.Bucket, region))
+	} else {
+		base, err = url.Parse(strings.TrimSuffix(bundle.Endpoint, "/"))
+		path = base.Path + "/" + bundle.Bucket + path
+	}
+	if err != nil {
+
+		return nil, err
+	}
+	base.Path = path
+	base.RawPath = s3Escape(path, false)
+
+	return base, nil
+}
+
+// list returns the keys of all objects under the prefix, following the continuation tokens.
+func (bundle *S3ResourceBundle) list(ctx context.Context, creds s3Credentials) ([]string, error) {
+	keys := make([]string, 0)
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if len(bundle.Prefix) > 0 {
+			query.Set("prefix", bundle.Prefix)
+		}
+		if len(token) > 0 {
+			query.Set("continuation-token", token)
+		}
+		body, err := bundle.do(ctx, creds, "", query)
+		if err != nil {
+
+			return nil, err
+		}
+		result := &s3ListBucketResult{}
+		if err := xml.Unmarshal(body, result); err != nil {
+
+			return nil, fmt.Errorf("error while listing S3 bucket %s. got %w", bundle.Bucket, err)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || len(result.NextContinuationToken) == 0 {
+
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// get downloads the object of the key.
+func (bundle *S3ResourceBundle) get(ctx context.Context, creds s3Credentials, key string) ([]byte, error) {
+
+	return bundle.do(ctx, creds, key, nil)
+}
+
+// do sends a signed GET request for the key, or for the bucket if the key is empty, and returns the response body.
+func (bundle *S3ResourceBundle) do(ctx context.Context, creds s3Credentials, key string, query url.Values) ([]byte, error) {
+	target, err := bundle.objectURL(creds.region, key)
+	if err != nil {
+
+		return nil, err
+	}
+	target.RawQuery = s3CanonicalQuery(query)
+
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout(bundle.Timeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(creds.accessKeyID) > 0 {
+		signS3Request(req, creds, time.Now())
+	}
+	client := bundle.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		s3Err := &s3Error{}
+		if xml.Unmarshal(body, s3Err) != nil || len(s3Err.Code) == 0 {
+			s3Err.Code = resp.Status
+		}
+
+		return nil, fmt.Errorf("S3 bucket %s responded %s to %s. %s", bundle.Bucket, s3Err.Code, target.Path, s3Err.Message)
+	}
+
+	return body, nil
+}
+
+// signS3Request signs the request with the AWS Signature Version 4 for the s3 service.
+func signS3Request(req *http.Request, creds s3Credentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", amzDate[:8], creds.region)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", s3EmptyPayloadHash)
+	if len(creds.sessionToken) > 0 {
+		req.Header.Set("x-amz-security-token", creds.sessionToken)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3EmptyPayloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+creds.secretKey), amzDate[:8])
+	for _, part := range []string{creds.region, "s3", "aws4_request"} {
+		key = s3HMAC(key, part)
+	}
+	signature := hex.EncodeToString(s3HMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.accessKeyID, scope, signedHeaders, signature))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery encodes the query sorted by name, as the signature requires.
+func s3CanonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			params = append(params, s3Escape(name, true)+"="+s3Escape(value, true))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// s3Escape percent-encodes all but the unreserved characters, the slashes are kept unless encodeSlash.
+func s3Escape(text string, encodeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(text) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
+
+// S3Resource resource implementation that loaded from an S3 bucket
+type S3Resource struct {
+	Bucket string
+	Key    string
+	Bytes  []byte
+}
+
+// String will state the resource bucket and key.
+func (res *S3Resource) String() string {
+
+	return fmt.Sprintf("From S3 bucket [%s] %s", res.Bucket, res.Key)
+}
+
+// Load will load the resource into byte array. This implementation will not re-load the object from S3 when this
+// method is called, it simply return the loaded data.
+func (res *S3Resource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
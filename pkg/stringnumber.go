@@ -0,0 +1,138 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StringNumberComparison selects how a string is compared to a number, such as a fact field "42" to 42.
+type StringNumberComparison int
+
+const (
+	// StringNumberUnchecked keeps the historical behavior : with the string on the left hand side == is false and
+	// != is true, with the string on the right hand side or in any other comparison it is an error.
+	StringNumberUnchecked StringNumberComparison = iota
+	// StringNumberError makes every comparison of a string to a number an error.
+	StringNumberError
+	// StringNumberLexical formats the number as a string and compares both strings, so "9" > 10 is true.
+	StringNumberLexical
+	// StringNumberNumeric parses the string as a number and compares both numbers, so "9" > 10 is false.
+	// A string that is not a number is an error.
+	StringNumberNumeric
+)
+
+// String returns the name of the comparison mode.
+func (mode StringNumberComparison) String() string {
+	switch mode {
+	case StringNumberUnchecked:
+
+		return "unchecked"
+	case StringNumberError:
+
+		return "error"
+	case StringNumberLexical:
+
+		return "lexical"
+	case StringNumberNumeric:
+
+		return "numeric"
+	}
+
+	return fmt.Sprintf("StringNumberComparison(%d)", int(mode))
+}
+
+// CoerceStringNumber converts the operands of the operator as the mode requires when one is a string and the
+// other a number, the operands are returned as they are otherwise.
+func CoerceStringNumber(mode StringNumberComparison, operator string, left, right reflect.Value) (reflect.Value, reflect.Value, error) {
+	if mode == StringNumberUnchecked {
+
+		return left, right, nil
+	}
+	left, right = GetValueElem(left), GetValueElem(right)
+	if IsQuantity(left) || IsQuantity(right) {
+
+		return left, right, nil
+	}
+	switch {
+	case left.Kind() == reflect.String && isNumberKind(right.Kind()):
+		converted, err := coerceString(mode, operator, left, right)
+
+		return converted, coerceNumber(mode, right), err
+	case right.Kind() == reflect.String && isNumberKind(left.Kind()):
+		converted, err := coerceString(mode, operator, right, left)
+
+		return coerceNumber(mode, left), converted, err
+	}
+
+	return left, right, nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+
+		return true
+	}
+
+	return false
+}
+
+// coerceString converts the string compared to the number, it parses the string in numeric mode.
+func coerceString(mode StringNumberComparison, operator string, text, number reflect.Value) (reflect.Value, error) {
+	switch mode {
+	case StringNumberError:
+
+		return text, fmt.Errorf("can not compare string %q to %s %v in %s comparison", text.String(), number.Kind().String(), number.Interface(), operator)
+	case StringNumberNumeric:
+		trimmed := strings.TrimSpace(text.String())
+		if integer, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+
+			return reflect.ValueOf(integer), nil
+		}
+		float, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+
+			return text, fmt.Errorf("can not compare string %q as a number in %s comparison", text.String(), operator)
+		}
+
+		return reflect.ValueOf(float), nil
+	}
+
+	return text, nil
+}
+
+// coerceNumber formats the number compared to the string in lexical mode.
+func coerceNumber(mode StringNumberComparison, number reflect.Value) reflect.Value {
+	if mode != StringNumberLexical {
+
+		return number
+	}
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return reflect.ValueOf(strconv.FormatInt(number.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		return reflect.ValueOf(strconv.FormatUint(number.Uint(), 10))
+	}
+
+	return reflect.ValueOf(strconv.FormatFloat(number.Float(), 'f', -1, 64))
+}
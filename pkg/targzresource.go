@@ -0,0 +1,297 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewTarGzResourceBundle will create a new instance of TarGzResourceBundle reading the tarball at path.
+// pathPattern are list of entry path patterns (glob) to filter the files of the tarball, such as "**/*.grl".
+func NewTarGzResourceBundle(path string, pathPattern ...string) *TarGzResourceBundle {
+
+	return &TarGzResourceBundle{
+		Path:        path,
+		PathPattern: pathPattern,
+	}
+}
+
+// NewTarGzResourceBundleFromReader will create a new instance of TarGzResourceBundle streaming the tarball
+// from the reader, such as the standard input of a pipeline step. The reader can only be loaded once.
+func NewTarGzResourceBundleFromReader(reader io.Reader, pathPattern ...string) *TarGzResourceBundle {
+
+	return &TarGzResourceBundle{
+		Reader:      reader,
+		PathPattern: pathPattern,
+	}
+}
+
+// NewTarGzResourceBundleFromURL will create a new instance of TarGzResourceBundle streaming the tarball from the url.
+func NewTarGzResourceBundleFromURL(url string, pathPattern ...string) *TarGzResourceBundle {
+
+	return &TarGzResourceBundle{
+		URL:         url,
+		PathPattern: pathPattern,
+	}
+}
+
+// TarGzResourceBundle is a helper struct to load the files of a gzip compressed tarball matching the path patterns.
+// The tarball is streamed, only the matching files are kept in memory and nothing is written to the disk.
+// A tarball that is not compressed is read as well. The tarball is read from Path, from Reader or from URL,
+// whichever is set first.
+type TarGzResourceBundle struct {
+	// Path of the tarball.
+	Path string
+	// Reader streams the tarball, if Path is empty.
+	Reader io.Reader
+	// URL the tarball is streamed from, if Path and Reader are empty.
+	URL string
+	// Header is sent with the download request, such as an Authorization header.
+	Header http.Header
+	// Client downloads the URL, if nil a new http.Client is used.
+	Client *http.Client
+	// List Glob like entry path pattern, relative to the root of the tarball.
+	// *.grl           <- matches abc.grl but not anyfolder/abc.grl
+	// **/*.grl        <- matches abc.grl, abc/def.grl or abc/def/ghi.grl
+	// /abc/**/*.grl   <- matches abc/def.grl or abc/def/ghi.grl
+	PathPattern []string
+	// Timeout bounds the download of URL by Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// Load streams the tarball and returns its files matching the PathPattern, in the order of the tarball.
+// The download of URL times out after Timeout, use LoadContext to choose the deadline.
+func (bundle *TarGzResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, the streaming stops with the context error once the context is done.
+func (bundle *TarGzResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+	ret, _, err := bundle.extract(ctx)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// LoadWithHandler is the same as Load, the failures are reported to onError with the file being extracted, or a nil
+// resource if the tarball can not be opened. As the tarball is streamed, nothing can be read after a failure:
+// DecisionSkip returns the files extracted before it, and DecisionRetry streams the tarball again from the start.
+// A Reader can not be read again, DecisionRetry then aborts.
+func (bundle *TarGzResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	for {
+		ret, failed, err := bundle.extract(ctx)
+		if err == nil {
+
+			return ret, nil
+		}
+		if onError == nil || ctx.Err() != nil {
+
+			return nil, err
+		}
+		switch onError(failed, err) {
+		case DecisionSkip:
+			contextLog(ctx).Warnf("Skipping the rest of tarball %s that failed to load : %v", bundle.source(), err)
+
+			return ret, nil
+		case DecisionRetry:
+			if len(bundle.Path) == 0 && bundle.Reader != nil {
+
+				return nil, err
+			}
+			contextLog(ctx).Debugf("Retrying tarball %s that failed to load : %v", bundle.source(), err)
+		default:
+
+			return nil, err
+		}
+	}
+}
+
+// extract streams the tarball and returns its files matching the PathPattern. On a failure it also returns the
+// files extracted before it, with the file being extracted, if any.
+func (bundle *TarGzResourceBundle) extract(ctx context.Context) ([]Resource, Resource, error) {
+	stream, err := bundle.open(ctx)
+	if err != nil {
+
+		return nil, nil, err
+	}
+	defer stream.Close()
+
+	buffered := bufio.NewReader(&contextReader{ctx: ctx, reader: stream})
+	var reader io.Reader = buffered
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+
+			return nil, nil, fmt.Errorf("error while reading tarball %s. got %w", bundle.source(), err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	ret := make([]Resource, 0)
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+
+			break
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+
+				return ret, nil, ctxErr
+			}
+
+			return ret, nil, fmt.Errorf("error while reading tarball %s. got %w", bundle.source(), err)
+		}
+		if header.Typeflag != tar.TypeReg {
+
+			continue
+		}
+		name := strings.TrimPrefix(header.Name, "./")
+		matched, err := matchObjectKeys([]string{name}, "", bundle.PathPattern)
+		if err != nil {
+
+			return nil, nil, err
+		}
+		if len(matched) == 0 {
+
+			continue
+		}
+		contextLog(ctx).Debugf("Extracting %s from tarball %s", name, bundle.source())
+		res := &TarGzResource{
+			Archive: bundle.source(),
+			Name:    name,
+		}
+		res.Bytes, err = io.ReadAll(archive)
+		if err != nil {
+
+			return ret, res, fmt.Errorf("error while extracting %s from tarball %s. got %w", name, bundle.source(), err)
+		}
+		ret = append(ret, res)
+	}
+
+	return ret, nil, nil
+}
+
+// MustLoad function is the same as Load with difference that it will panic if any error is raised
+//
+// Deprecated: use TarGzResourceBundle.Load, or TarGzResourceBundle.LoadWithHandler to keep the files extracted before a
+// failure.
+func (bundle *TarGzResourceBundle) MustLoad() []Resource {
+	resources, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return resources
+}
+
+// open returns the stream of the tarball from Path, Reader or URL.
+func (bundle *TarGzResourceBundle) open(ctx context.Context) (io.ReadCloser, error) {
+	switch {
+	case len(bundle.Path) > 0:
+
+		return os.Open(bundle.Path)
+	case bundle.Reader != nil:
+
+		return io.NopCloser(bundle.Reader), nil
+	case len(bundle.URL) > 0:
+		client := bundle.Client
+		if client == nil {
+			client = &http.Client{}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundle.URL, nil)
+		if err != nil {
+
+			return nil, err
+		}
+		for key, values := range bundle.Header {
+			req.Header[key] = values
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+
+			return nil, fmt.Errorf("error while downloading tarball %s. got status %s", bundle.URL, resp.Status)
+		}
+
+		return resp.Body, nil
+	default:
+
+		return nil, fmt.Errorf("tar.gz resource bundle needs a path, a reader or an url")
+	}
+}
+
+// source names the tarball in the resources and the errors.
+func (bundle *TarGzResourceBundle) source() string {
+	switch {
+	case len(bundle.Path) > 0:
+
+		return bundle.Path
+	case bundle.Reader != nil:
+
+		return "reader"
+	default:
+
+		return bundle.URL
+	}
+}
+
+// TarGzResource resource implementation that loaded from a file of a tarball
+type TarGzResource struct {
+	// Archive is the path or the URL of the tarball.
+	Archive string
+	// Name is the path of the file within the tarball.
+	Name  string
+	Bytes []byte
+}
+
+// String will state the tarball and the file path.
+func (res *TarGzResource) String() string {
+
+	return fmt.Sprintf("From tarball [%s] %s", res.Archive, res.Name)
+}
+
+// Load will load the resource into byte array. This implementation will not re-read the tarball
+// when this method is called, it simply return the extracted data.
+func (res *TarGzResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
@@ -0,0 +1,144 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestTar(t *testing.T, compress bool) []byte {
+	var buff bytes.Buffer
+	var gz *gzip.Writer
+	writer := tar.NewWriter(&buff)
+	if compress {
+		gz = gzip.NewWriter(&buff)
+		writer = tar.NewWriter(gz)
+	}
+	assert.NoError(t, writer.WriteHeader(&tar.Header{Name: "./rules/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	files, err := NewFileResourceBundle("./test", "**/*.grl").Load()
+	assert.NoError(t, err)
+	for _, file := range files {
+		path := file.(*FileResource).Path
+		name := "./rules/" + filepath.ToSlash(path[strings.Index(path, "test"+string(filepath.Separator))+5:])
+		data := file.(*FileResource).Bytes
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))}))
+		_, err = writer.Write(data)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.WriteHeader(&tar.Header{Name: "./README.md", Typeflag: tar.TypeReg, Mode: 0o644, Size: 7}))
+	_, err = writer.Write([]byte("# rules"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	if compress {
+		assert.NoError(t, gz.Close())
+	}
+
+	return buff.Bytes()
+}
+
+func TestTarGzResourceBundle_Load(t *testing.T) {
+	tarball := buildTestTar(t, true)
+	path := filepath.Join(t.TempDir(), "rules.tar.gz")
+	assert.NoError(t, os.WriteFile(path, tarball, 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		_, _ = w.Write(tarball)
+	}))
+	defer server.Close()
+	fromURL := NewTarGzResourceBundleFromURL(server.URL+"/rules.tar.gz", "/rules/**/*.grl")
+	fromURL.Header = http.Header{"Authorization": []string{"Bearer token"}}
+
+	for _, bundle := range []*TarGzResourceBundle{
+		NewTarGzResourceBundle(path, "**/*.grl"),
+		NewTarGzResourceBundleFromReader(bytes.NewReader(tarball), "rules/**/*.grl"),
+		NewTarGzResourceBundleFromReader(bytes.NewReader(buildTestTar(t, false)), "rules/**/*.grl"),
+		fromURL,
+	} {
+		resources, err := bundle.Load()
+		assert.NoError(t, err)
+		if assert.Len(t, resources, 6) {
+			assert.Equal(t, "From tarball ["+bundle.source()+"] rules/subfold1/GrlFile11.grl", resources[0].String())
+			data, err := resources[0].Load()
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), "rule")
+		}
+	}
+
+	resources, err := NewTarGzResourceBundle(path, "rules/subfold1/*.grl", "*.md").Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	_, err = NewTarGzResourceBundleFromURL(server.URL+"/rules.tar.gz", "**/*.grl").Load()
+	assert.Error(t, err)
+	_, err = NewTarGzResourceBundleFromReader(bytes.NewReader(tarball[:len(tarball)/2]), "**/*.grl").Load()
+	assert.Error(t, err)
+	_, err = (&TarGzResourceBundle{PathPattern: []string{"**/*.grl"}}).Load()
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewTarGzResourceBundle(path, "**/*.grl").LoadContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTarGzResourceBundle_LoadWithHandler(t *testing.T) {
+	tarball := buildTestTar(t, false)
+	// the tarball ends in the content of GrlFile22.grl, after its 512 bytes header.
+	truncated := tarball[:bytes.Index(tarball, []byte("./rules/subfold2/GrlFile22.grl"))+520]
+	path := filepath.Join(t.TempDir(), "rules.tar")
+	assert.NoError(t, os.WriteFile(path, truncated, 0o600))
+
+	// the truncated tarball is streamed again once, then the files read before the failure are kept.
+	bundle := NewTarGzResourceBundle(path, "**/*.grl")
+	_, err := bundle.Load()
+	assert.Error(t, err)
+	failed := make([]string, 0)
+	resources, err := bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		failed = append(failed, res.String())
+		if len(failed) == 1 {
+
+			return DecisionRetry
+		}
+
+		return DecisionSkip
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+	assert.Equal(t, []string{"From tarball [" + path + "] rules/subfold2/GrlFile22.grl", "From tarball [" + path + "] rules/subfold2/GrlFile22.grl"}, failed)
+
+	// a reader can not be streamed again.
+	retry := func(Resource, error) Decision {
+
+		return DecisionRetry
+	}
+	_, err = NewTarGzResourceBundleFromReader(bytes.NewReader(truncated), "**/*.grl").LoadWithHandler(retry)
+	assert.Error(t, err)
+}
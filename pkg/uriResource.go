@@ -0,0 +1,254 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ResourceFactory creates the resource of an URI whose scheme it is registered for.
+type ResourceFactory func(uri *url.URL) (Resource, error)
+
+// defaultResourceSchemes are the schemes of the registries that are nil, RegisterResourceScheme and
+// RegisterEmbeddedFS add into them.
+var defaultResourceSchemes = NewResourceSchemeRegistry()
+
+// ResourceSchemeRegistry keeps the factories of the URI schemes and the embedded file systems of the embed:// URIs.
+// A nil registry is the default one of the process, used by NewResourceFromURI, so two embeddings may each create
+// their resources from a registry of their own.
+type ResourceSchemeRegistry struct {
+	mutex     sync.RWMutex
+	factories map[string]ResourceFactory
+	embedded  map[string]embed.FS
+}
+
+// NewResourceSchemeRegistry create a ResourceSchemeRegistry knowing the built in schemes, see NewResourceFromURI.
+func NewResourceSchemeRegistry() *ResourceSchemeRegistry {
+	registry := &ResourceSchemeRegistry{
+		factories: map[string]ResourceFactory{
+			"file":      fileResourceFromURI,
+			"http":      urlResourceFromURI,
+			"https":     urlResourceFromURI,
+			"git+http":  gitResourceFromURI,
+			"git+https": gitResourceFromURI,
+			"s3":        s3ResourceFromURI,
+		},
+		embedded: make(map[string]embed.FS),
+	}
+	registry.factories["embed"] = registry.embeddedResourceFromURI
+
+	return registry
+}
+
+// or returns this registry, the default registry if it is nil.
+func (registry *ResourceSchemeRegistry) or() *ResourceSchemeRegistry {
+	if registry == nil {
+
+		return defaultResourceSchemes
+	}
+
+	return registry
+}
+
+// Register makes NewResource create the resources of the URIs of the scheme with the factory, such as "vault" for
+// vault://secret/rules. The scheme is case insensitive, a scheme registered again, built in ones included, is replaced.
+func (registry *ResourceSchemeRegistry) Register(scheme string, factory ResourceFactory) {
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.factories[strings.ToLower(scheme)] = factory
+}
+
+// RegisterEmbeddedFS names the embedded file system for the embed:// URIs, embed://name/path/to/rules.grl being
+// the file path/to/rules.grl of the file system registered as name.
+func (registry *ResourceSchemeRegistry) RegisterEmbeddedFS(name string, source embed.FS) {
+	registry = registry.or()
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.embedded[name] = source
+}
+
+// NewResource creates the resource of the rules at the URI like NewResourceFromURI does, with the schemes and the
+// embedded file systems of this registry.
+func (registry *ResourceSchemeRegistry) NewResource(uri string) (Resource, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while parsing resource URI %s. got %w", uri, err)
+	}
+	if len(parsed.Scheme) == 0 {
+
+		return nil, fmt.Errorf("resource URI %s has no scheme", uri)
+	}
+	registry = registry.or()
+	registry.mutex.RLock()
+	factory, ok := registry.factories[strings.ToLower(parsed.Scheme)]
+	registry.mutex.RUnlock()
+	if !ok {
+
+		return nil, fmt.Errorf("resource URI %s has the unknown scheme %s", uri, parsed.Scheme)
+	}
+
+	return factory(parsed)
+}
+
+// RegisterResourceScheme makes NewResourceFromURI create the resources of the URIs of the scheme with the factory,
+// such as "vault" for vault://secret/rules. The scheme is case insensitive, a scheme registered again, built in
+// ones included, is replaced.
+func RegisterResourceScheme(scheme string, factory ResourceFactory) {
+	defaultResourceSchemes.Register(scheme, factory)
+}
+
+// RegisterEmbeddedFS names the embedded file system for the embed:// URIs of NewResourceFromURI,
+// embed://name/path/to/rules.grl being the file path/to/rules.grl of the file system registered as name.
+func RegisterEmbeddedFS(name string, source embed.FS) {
+	defaultResourceSchemes.RegisterEmbeddedFS(name, source)
+}
+
+// NewResourceFromURI creates the resource of the rules at the URI, choosing the implementation by its scheme, so the
+// source of the rules can be configured with a single string. The built in schemes are:
+//
+//	file:///etc/rules/pricing.grl                                 a FileResource, file://rules/pricing.grl is relative
+//	https://rules.example.com/pricing.grl                         an URLResource, http too
+//	git+https://github.com/org/rules.git?tag=v1.2.0#/pricing.grl  the file of a GIT repository
+//	s3://bucket/rules/pricing.grl?region=eu-west-1                an object of an S3 bucket
+//	embed://name/rules/pricing.grl                                a file of an embedded FS, see RegisterEmbeddedFS
+//
+// The GIT URIs accept the ref, tag and commit parameters to choose the checked out revision, and the S3 URIs the
+// region and endpoint parameters. Other schemes are created by the factories of RegisterResourceScheme. The schemes
+// are those of the default registry, a ResourceSchemeRegistry of its own creates the resources apart.
+func NewResourceFromURI(uri string) (Resource, error) {
+
+	return defaultResourceSchemes.NewResource(uri)
+}
+
+func fileResourceFromURI(uri *url.URL) (Resource, error) {
+	path := uri.Path
+	if len(uri.Host) != 0 && uri.Host != "localhost" {
+		path = uri.Host + uri.Path
+	}
+	if len(path) == 0 {
+
+		return nil, fmt.Errorf("file URI %s has no path", uri.Redacted())
+	}
+
+	return NewFileResource(path), nil
+}
+
+func urlResourceFromURI(uri *url.URL) (Resource, error) {
+
+	return NewURLResource(uri.String()), nil
+}
+
+func gitResourceFromURI(uri *url.URL) (Resource, error) {
+	path := strings.TrimLeft(uri.Fragment, "/")
+	if len(path) == 0 {
+
+		return nil, fmt.Errorf("GIT URI %s has no file path in its fragment, such as #/rules.grl", uri.Redacted())
+	}
+	repository := *uri
+	repository.Scheme = strings.TrimPrefix(strings.ToLower(uri.Scheme), "git+")
+	repository.RawQuery = ""
+	repository.Fragment = ""
+	repository.User = nil
+	bundle := NewGITResourceBundle(repository.String(), "/"+path)
+	if uri.User != nil {
+		bundle.User = uri.User.Username()
+		bundle.Password, _ = uri.User.Password()
+	}
+	query := uri.Query()
+	bundle.RefName = query.Get("ref")
+	bundle.Tag = query.Get("tag")
+	bundle.CommitHash = query.Get("commit")
+
+	return &bundleFileResource{uri: uri.Redacted(), bundle: bundle}, nil
+}
+
+func s3ResourceFromURI(uri *url.URL) (Resource, error) {
+	key := strings.TrimPrefix(uri.Path, "/")
+	if len(uri.Host) == 0 || len(key) == 0 {
+
+		return nil, fmt.Errorf("S3 URI %s is not of the form s3://bucket/key", uri.Redacted())
+	}
+	bundle := NewS3ResourceBundle(uri.Host, key, "/"+key)
+	query := uri.Query()
+	bundle.Region = query.Get("region")
+	bundle.Endpoint = query.Get("endpoint")
+
+	return &bundleFileResource{uri: uri.Redacted(), bundle: bundle}, nil
+}
+
+func (registry *ResourceSchemeRegistry) embeddedResourceFromURI(uri *url.URL) (Resource, error) {
+	registry.mutex.RLock()
+	source, ok := registry.embedded[uri.Host]
+	registry.mutex.RUnlock()
+	if !ok {
+
+		return nil, fmt.Errorf("embedded file system %s of URI %s is not registered", uri.Host, uri.Redacted())
+	}
+
+	return NewEmbeddedResource(source, strings.TrimPrefix(uri.Path, "/")), nil
+}
+
+// bundleFileResource is the single file of a bundle, such as the file of a GIT repository. It is loaded by loading
+// the bundle.
+type bundleFileResource struct {
+	uri    string
+	bundle ResourceBundle
+	loaded Resource
+}
+
+// Load will load the bundle and the content of its file.
+func (res *bundleFileResource) Load() ([]byte, error) {
+
+	return res.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the bundle is loaded within the context.
+func (res *bundleFileResource) LoadContext(ctx context.Context) ([]byte, error) {
+	resources, err := LoadBundle(ctx, res.bundle)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(resources) != 1 {
+
+		return nil, fmt.Errorf("resource URI %s matches %d files instead of one", res.uri, len(resources))
+	}
+	res.loaded = resources[0]
+
+	return LoadResource(ctx, res.loaded)
+}
+
+// String will state the resource URI.
+func (res *bundleFileResource) String() string {
+
+	return "From URI " + res.uri
+}
+
+// Metadata implements ResourceInfo, describing the file once loaded.
+func (res *bundleFileResource) Metadata() ResourceMetadata {
+	if res.loaded == nil {
+
+		return ResourceMetadata{Origin: res.uri}
+	}
+
+	return metadataOf(res.loaded)
+}
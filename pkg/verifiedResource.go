@@ -0,0 +1,225 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// ErrIntegrity is wrapped by the errors of the verifiers when the content is not the one that was checksummed or
+// signed, so a tampered resource can be told apart from an unavailable one with errors.Is.
+var ErrIntegrity = errors.New("resource failed the integrity verification")
+
+// Verifier verifies the content of a resource before it is returned by a VerifiedResource. Implement it for
+// another checksum or signature scheme.
+type Verifier interface {
+	Verify(ctx context.Context, data []byte) error
+}
+
+// SHA256Verifier checks the SHA-256 checksum of the content, such as the one published next to the rules in a
+// SHA256SUMS file.
+type SHA256Verifier struct {
+	// Checksum is the expected checksum, in hexadecimal.
+	Checksum string
+}
+
+// Verify implements Verifier
+func (verifier *SHA256Verifier) Verify(ctx context.Context, data []byte) error {
+	expected, err := hex.DecodeString(strings.TrimSpace(verifier.Checksum))
+	if err != nil || len(expected) != sha256.Size {
+
+		return fmt.Errorf("checksum %q is not an hexadecimal SHA-256", verifier.Checksum)
+	}
+	actual := sha256.Sum256(data)
+	if subtle.ConstantTimeCompare(actual[:], expected) != 1 {
+
+		return fmt.Errorf("%w, SHA-256 is %x but %x was expected", ErrIntegrity, actual, expected)
+	}
+
+	return nil
+}
+
+// OpenPGPVerifier verifies a detached OpenPGP signature of the content, such as made by
+// gpg --detach-sign --armor rules.grl.
+type OpenPGPVerifier struct {
+	// KeyRing holds the public keys trusted to sign the content, armored or not, such as exported by
+	// gpg --export --armor.
+	KeyRing []byte
+	// Signature is the resource of the detached signature, armored or not, such as the URLResource of
+	// rules.grl.asc. It is loaded on every verification.
+	Signature Resource
+}
+
+// Verify implements Verifier
+func (verifier *OpenPGPVerifier) Verify(ctx context.Context, data []byte) error {
+	var keyRing openpgp.EntityList
+	var err error
+	if armored(verifier.KeyRing) {
+		keyRing, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(verifier.KeyRing))
+	} else {
+		keyRing, err = openpgp.ReadKeyRing(bytes.NewReader(verifier.KeyRing))
+	}
+	if err != nil {
+
+		return fmt.Errorf("error while reading the OpenPGP key ring. got %w", err)
+	}
+	signature, err := LoadResource(ctx, verifier.Signature)
+	if err != nil {
+
+		return fmt.Errorf("error while loading the signature %s. got %w", verifier.Signature.String(), err)
+	}
+	if armored(signature) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyRing, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+
+		return fmt.Errorf("%w, the OpenPGP signature %s does not verify. got %v", ErrIntegrity, verifier.Signature.String(), err)
+	}
+
+	return nil
+}
+
+func armored(data []byte) bool {
+
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN "))
+}
+
+// CosignVerifier verifies the signature made by cosign sign-blob --key with a key pair. The keyless signatures,
+// bound to a certificate and a transparency log, are not supported.
+type CosignVerifier struct {
+	// PublicKey is the PEM public key of the key pair, such as cosign.pub. ECDSA, Ed25519 and RSA keys are accepted.
+	PublicKey []byte
+	// Signature is the resource of the base64 signature, such as written by --output-signature. It is loaded on
+	// every verification.
+	Signature Resource
+}
+
+// Verify implements Verifier
+func (verifier *CosignVerifier) Verify(ctx context.Context, data []byte) error {
+	block, _ := pem.Decode(verifier.PublicKey)
+	if block == nil {
+
+		return errors.New("cosign public key is not PEM encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+
+		return fmt.Errorf("error while parsing the cosign public key. got %w", err)
+	}
+	encoded, err := LoadResource(ctx, verifier.Signature)
+	if err != nil {
+
+		return fmt.Errorf("error while loading the signature %s. got %w", verifier.Signature.String(), err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+
+		return fmt.Errorf("%w, the cosign signature %s is not base64", ErrIntegrity, verifier.Signature.String())
+	}
+	digest := sha256.Sum256(data)
+	verified := false
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		verified = ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		verified = ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		verified = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	default:
+
+		return fmt.Errorf("cosign public key of type %T is not supported", publicKey)
+	}
+	if !verified {
+
+		return fmt.Errorf("%w, the cosign signature %s does not verify", ErrIntegrity, verifier.Signature.String())
+	}
+
+	return nil
+}
+
+// VerifiedResource returns the content of an underlying resource only once every verifier accepted it, so the
+// rules fetched from an URL or a GIT repository reach the parser only if nobody tampered with them.
+type VerifiedResource struct {
+	subRes    Resource
+	verifiers []Verifier
+}
+
+// NewVerifiedResource instantiates a new verified resource checking the content of the underlying Resource with
+// every verifier, such as a SHA256Verifier and an OpenPGPVerifier.
+func NewVerifiedResource(res Resource, verifiers ...Verifier) Resource {
+
+	return &VerifiedResource{
+		subRes:    res,
+		verifiers: verifiers,
+	}
+}
+
+// Load will load the underlying Resource and verify it.
+func (vr *VerifiedResource) Load() ([]byte, error) {
+
+	return vr.LoadContext(context.Background())
+}
+
+// LoadContext is the same as Load, the underlying Resource and the signatures are loaded within the context.
+func (vr *VerifiedResource) LoadContext(ctx context.Context) ([]byte, error) {
+	data, err := LoadResource(ctx, vr.subRes)
+	if err != nil {
+
+		return nil, err
+	}
+	if len(vr.verifiers) == 0 {
+
+		return nil, fmt.Errorf("error while verifying %s. got no verifier", vr.subRes.String())
+	}
+	for _, verifier := range vr.verifiers {
+		err := verifier.Verify(ctx, data)
+		if err != nil {
+
+			return nil, fmt.Errorf("error while verifying %s. got %w", vr.subRes.String(), err)
+		}
+	}
+
+	return data, nil
+}
+
+// String will state the resource source.
+func (vr *VerifiedResource) String() string {
+
+	return "Verified Resource, underlying resource: " + vr.subRes.String()
+}
+
+// Metadata implements ResourceInfo, describing the underlying Resource.
+func (vr *VerifiedResource) Metadata() ResourceMetadata {
+
+	return metadataOf(vr.subRes)
+}
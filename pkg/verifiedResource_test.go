@@ -0,0 +1,101 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifiedResource_SHA256(t *testing.T) {
+	sum := sha256.Sum256([]byte(loremipsum))
+	res := NewVerifiedResource(NewBytesResource([]byte(loremipsum)), &SHA256Verifier{Checksum: hex.EncodeToString(sum[:])})
+	data, err := res.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, loremipsum, string(data))
+
+	_, err = NewVerifiedResource(NewBytesResource([]byte(loremipsum+" ")), &SHA256Verifier{Checksum: hex.EncodeToString(sum[:])}).Load()
+	assert.True(t, errors.Is(err, ErrIntegrity))
+	_, err = NewVerifiedResource(NewBytesResource([]byte(loremipsum)), &SHA256Verifier{Checksum: "abc"}).Load()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrIntegrity))
+	_, err = NewVerifiedResource(NewBytesResource([]byte(loremipsum))).Load()
+	assert.Error(t, err)
+}
+
+func TestVerifiedResource_OpenPGP(t *testing.T) {
+	entity, err := openpgp.NewEntity("Rules Publisher", "", "rules@example.com", nil)
+	assert.NoError(t, err)
+	var keyRing bytes.Buffer
+	writer, err := armor.Encode(&keyRing, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(writer))
+	assert.NoError(t, writer.Close())
+	var signature bytes.Buffer
+	assert.NoError(t, openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader([]byte(loremipsum)), nil))
+
+	verifier := &OpenPGPVerifier{KeyRing: keyRing.Bytes(), Signature: NewBytesResource(signature.Bytes())}
+	data, err := NewVerifiedResource(NewBytesResource([]byte(loremipsum)), verifier).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, loremipsum, string(data))
+
+	_, err = NewVerifiedResource(NewBytesResource([]byte("rule Tampered {}")), verifier).Load()
+	assert.True(t, errors.Is(err, ErrIntegrity))
+
+	// a binary signature of another key.
+	other, err := openpgp.NewEntity("Someone Else", "", "else@example.com", nil)
+	assert.NoError(t, err)
+	signature.Reset()
+	assert.NoError(t, openpgp.DetachSign(&signature, other, bytes.NewReader([]byte(loremipsum)), nil))
+	verifier.Signature = NewBytesResource(signature.Bytes())
+	_, err = NewVerifiedResource(NewBytesResource([]byte(loremipsum)), verifier).Load()
+	assert.True(t, errors.Is(err, ErrIntegrity))
+}
+
+func TestVerifiedResource_Cosign(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	digest := sha256.Sum256([]byte(loremipsum))
+	signed, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+
+	verifier := &CosignVerifier{PublicKey: publicKey, Signature: NewBytesResource([]byte(base64.StdEncoding.EncodeToString(signed) + "\n"))}
+	data, err := NewVerifiedResource(NewBytesResource([]byte(loremipsum)), verifier).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, loremipsum, string(data))
+
+	_, err = NewVerifiedResource(NewBytesResource([]byte("rule Tampered {}")), verifier).Load()
+	assert.True(t, errors.Is(err, ErrIntegrity))
+	verifier.PublicKey = []byte("not a key")
+	_, err = NewVerifiedResource(NewBytesResource([]byte(loremipsum)), verifier).Load()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrIntegrity))
+}
@@ -0,0 +1,189 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"sort"
+	"time"
+)
+
+// NewWatchingFileResourceBundle creates new instance of WatchingFileResourceBundle struct.
+// basePath and pathPattern are the same as those of NewFileResourceBundle.
+func NewWatchingFileResourceBundle(basePath string, pathPattern ...string) *WatchingFileResourceBundle {
+
+	return &WatchingFileResourceBundle{
+		FileResourceBundle: FileResourceBundle{
+			BasePath:    basePath,
+			PathPattern: pathPattern,
+		},
+	}
+}
+
+// WatchingFileResourceBundle is a FileResourceBundle that watches the files under its BasePath, and reports the
+// resources again every time a matching file is added, changed or removed. It scans the files every Interval,
+// which works the same on every operating system and file system, including network and container mounts.
+type WatchingFileResourceBundle struct {
+	FileResourceBundle
+	// Interval between two scans of the files, one second if zero.
+	Interval time.Duration
+}
+
+// ResourceChange is a change of the files of a WatchingFileResourceBundle, or of the keys of an EtcdResourceBundle.
+type ResourceChange struct {
+	// Resources are all the matching files after the change.
+	Resources []Resource
+	// Added, Changed and Removed are the paths of the files, or the keys, sorted.
+	Added   []string
+	Changed []string
+	Removed []string
+	// Err is the error scanning the files, the other fields are then empty.
+	Err error
+}
+
+// Watch loads the files and calls onChange with all of them added, then calls onChange again after every change
+// until the context is done. A change is reported once the files stay the same for one Interval, so a file still
+// being written is not loaded half way. A scan that fails is reported once with its error, and retried.
+func (bundle *WatchingFileResourceBundle) Watch(ctx context.Context, onChange func(change ResourceChange)) error {
+	interval := bundle.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	last, resources, err := bundle.scan()
+	if err != nil {
+
+		return err
+	}
+	onChange(diffScans(nil, last, resources))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var pending map[string][sha256.Size]byte
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current, resources, err := bundle.scan()
+		if err != nil {
+			if !failing {
+				onChange(ResourceChange{Err: err})
+			}
+			failing = true
+
+			continue
+		}
+		failing = false
+		if sameScans(last, current) {
+			pending = nil
+
+			continue
+		}
+		if pending == nil || !sameScans(pending, current) {
+			pending = current
+
+			continue
+		}
+		onChange(diffScans(last, current, resources))
+		last, pending = current, nil
+	}
+}
+
+// Changes is the same as Watch, pushing the changes into the returned channel instead, which is closed once the
+// context is done. The first change holds all the files, or the error loading them.
+func (bundle *WatchingFileResourceBundle) Changes(ctx context.Context) <-chan ResourceChange {
+	changes := make(chan ResourceChange)
+	go func() {
+		defer close(changes)
+		err := bundle.Watch(ctx, func(change ResourceChange) {
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			select {
+			case changes <- ResourceChange{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return changes
+}
+
+// scan loads the matching files and returns the digest of every file by path, with the resources.
+func (bundle *WatchingFileResourceBundle) scan() (map[string][sha256.Size]byte, []Resource, error) {
+	resources, err := bundle.Load()
+	if err != nil {
+
+		return nil, nil, err
+	}
+	digests := make(map[string][sha256.Size]byte, len(resources))
+	for _, resource := range resources {
+		file := resource.(*FileResource)
+		digests[file.Path] = sha256.Sum256(file.Bytes)
+	}
+
+	return digests, resources, nil
+}
+
+func sameScans(scan, other map[string][sha256.Size]byte) bool {
+	if len(scan) != len(other) {
+
+		return false
+	}
+	for path, digest := range scan {
+		if otherDigest, ok := other[path]; !ok || otherDigest != digest {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// diffScans returns the change from the last scan to the current one.
+func diffScans(last, current map[string][sha256.Size]byte, resources []Resource) ResourceChange {
+	change := ResourceChange{
+		Resources: resources,
+		Added:     make([]string, 0),
+		Changed:   make([]string, 0),
+		Removed:   make([]string, 0),
+	}
+	for path, digest := range current {
+		lastDigest, ok := last[path]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, path)
+		case lastDigest != digest:
+			change.Changed = append(change.Changed, path)
+		}
+	}
+	for path := range last {
+		if _, ok := current[path]; !ok {
+			change.Removed = append(change.Removed, path)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Changed)
+	sort.Strings(change.Removed)
+
+	return change
+}
@@ -0,0 +1,97 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nextChange(t *testing.T, changes <-chan ResourceChange) ResourceChange {
+	select {
+	case change := <-changes:
+
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change of the files")
+
+		return ResourceChange{}
+	}
+}
+
+func TestWatchingFileResourceBundle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.grl")
+	b := filepath.Join(dir, "nested", "b.grl")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(b), 0755))
+	assert.NoError(t, os.WriteFile(a, []byte("rule A"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a rule"), 0644))
+
+	bundle := NewWatchingFileResourceBundle(dir, "**/*.grl")
+	bundle.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := bundle.Changes(ctx)
+
+	change := nextChange(t, changes)
+	assert.NoError(t, change.Err)
+	assert.Len(t, change.Resources, 1)
+	assert.Equal(t, []string{a}, change.Added)
+
+	assert.NoError(t, os.WriteFile(b, []byte("rule B"), 0644))
+	change = nextChange(t, changes)
+	assert.Len(t, change.Resources, 2)
+	assert.Equal(t, []string{b}, change.Added)
+	assert.Empty(t, change.Changed)
+
+	// a file that is not matching is ignored
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("still not a rule"), 0644))
+	assert.NoError(t, os.WriteFile(a, []byte("rule A2"), 0644))
+	assert.NoError(t, os.Remove(b))
+	change = nextChange(t, changes)
+	assert.Equal(t, []string{a}, change.Changed)
+	assert.Equal(t, []string{b}, change.Removed)
+	data, err := change.Resources[0].Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "rule A2", string(data))
+
+	// a missing base path is reported once, then the files are found again
+	moved := dir + "-moved"
+	assert.NoError(t, os.Rename(dir, moved))
+	change = nextChange(t, changes)
+	assert.Error(t, change.Err)
+	assert.NoError(t, os.Rename(moved, dir))
+	assert.NoError(t, os.WriteFile(a, []byte("rule A3"), 0644))
+	change = nextChange(t, changes)
+	assert.NoError(t, change.Err)
+	assert.Equal(t, []string{a}, change.Changed)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchingFileResourceBundle_MissingBasePath(t *testing.T) {
+	bundle := NewWatchingFileResourceBundle(filepath.Join(t.TempDir(), "missing"), "**/*.grl")
+	err := bundle.Watch(context.Background(), func(change ResourceChange) {
+		t.Error("no change expected")
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewZipResourceBundle will create a new instance of ZipResourceBundle reading the zip file at path.
+// pathPattern are list of entry path patterns (glob) to filter the entries of the archive, such as "**/*.grl".
+func NewZipResourceBundle(path string, pathPattern ...string) *ZipResourceBundle {
+
+	return &ZipResourceBundle{
+		Path:        path,
+		PathPattern: pathPattern,
+	}
+}
+
+// NewZipResourceBundleFromReader will create a new instance of ZipResourceBundle reading the zip archive of size bytes
+// from the reader, such as a bytes.Reader holding an archive already in memory.
+func NewZipResourceBundleFromReader(reader io.ReaderAt, size int64, pathPattern ...string) *ZipResourceBundle {
+
+	return &ZipResourceBundle{
+		Reader:      reader,
+		Size:        size,
+		PathPattern: pathPattern,
+	}
+}
+
+// NewZipResourceBundleFromURL will create a new instance of ZipResourceBundle downloading the zip archive from the url.
+func NewZipResourceBundleFromURL(url string, pathPattern ...string) *ZipResourceBundle {
+
+	return &ZipResourceBundle{
+		URL:         url,
+		PathPattern: pathPattern,
+	}
+}
+
+// ZipResourceBundle is a helper struct to load the entries of a zip archive matching the path patterns,
+// without unpacking the archive to the disk first. The archive is read from Path, from Reader or downloaded
+// from URL, whichever is set first.
+type ZipResourceBundle struct {
+	// Path of the zip file.
+	Path string
+	// Reader and Size give the zip archive, if Path is empty.
+	Reader io.ReaderAt
+	Size   int64
+	// URL the zip archive is downloaded from, if Path and Reader are empty.
+	URL string
+	// Header is sent with the download request, such as an Authorization header.
+	Header http.Header
+	// Client downloads the URL, if nil a new http.Client is used.
+	Client *http.Client
+	// List Glob like entry path pattern, relative to the root of the archive.
+	// *.grl           <- matches abc.grl but not anyfolder/abc.grl
+	// **/*.grl        <- matches abc.grl, abc/def.grl or abc/def/ghi.grl
+	// /abc/**/*.grl   <- matches abc/def.grl or abc/def/ghi.grl
+	PathPattern []string
+	// Timeout bounds the download of URL by Load. URLResourceTimeoutSecond if zero.
+	Timeout time.Duration
+}
+
+// Load reads the zip archive and returns its entries matching the PathPattern, sorted by their path.
+// The download of URL times out after Timeout, use LoadContext to choose the deadline.
+func (bundle *ZipResourceBundle) Load() ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.LoadContext(ctx)
+}
+
+// LoadContext is the same as Load, it stops with the context error once the context is done.
+func (bundle *ZipResourceBundle) LoadContext(ctx context.Context) ([]Resource, error) {
+
+	return bundle.load(ctx, nil)
+}
+
+// LoadWithHandler is the same as Load, the entries failing to be extracted are reported to onError.
+func (bundle *ZipResourceBundle) LoadWithHandler(onError func(Resource, error) Decision) ([]Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout(bundle.Timeout))
+	defer cancel()
+
+	return bundle.load(ctx, onError)
+}
+
+// MustLoad function is the same as Load with difference that it will panic if any error is raised
+//
+// Deprecated: use ZipResourceBundle.Load, or ZipResourceBundle.LoadWithHandler to go on without the entries failing to
+// be extracted.
+func (bundle *ZipResourceBundle) MustLoad() []Resource {
+	resources, err := bundle.Load()
+	if err != nil {
+
+		panic(err)
+	}
+
+	return resources
+}
+
+// load extracts the entries matching the PathPattern, the entries failing to be extracted are reported to onError, if not nil.
+func (bundle *ZipResourceBundle) load(ctx context.Context, onError func(Resource, error) Decision) ([]Resource, error) {
+	archive, err := bundle.open(ctx)
+	if err != nil {
+
+		return nil, err
+	}
+	entries := make(map[string]*zip.File, len(archive.File))
+	names := make([]string, 0, len(archive.File))
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+
+			continue
+		}
+		if _, ok := entries[entry.Name]; !ok {
+			names = append(names, entry.Name)
+		}
+		entries[entry.Name] = entry
+	}
+	sort.Strings(names)
+	matched, err := matchObjectKeys(names, "", bundle.PathPattern)
+	if err != nil {
+
+		return nil, err
+	}
+	ret := make([]Resource, 0, len(matched))
+	for _, name := range matched {
+		if err := ctx.Err(); err != nil {
+
+			return nil, err
+		}
+		contextLog(ctx).Debugf("Extracting %s from zip archive %s", name, bundle.source())
+		gress := &ZipResource{
+			Archive: bundle.source(),
+			Name:    name,
+		}
+		skipped, err := retryLoad(ctx, gress, onError, func() error {
+			var err error
+			gress.Bytes, err = readZipEntry(entries[name])
+
+			return err
+		})
+		if err != nil {
+
+			return nil, fmt.Errorf("error while extracting %s from zip archive %s. got %w", name, bundle.source(), err)
+		}
+		if !skipped {
+			ret = append(ret, gress)
+		}
+	}
+
+	return ret, nil
+}
+
+// open returns the reader of the archive from Path, Reader or URL.
+func (bundle *ZipResourceBundle) open(ctx context.Context) (*zip.Reader, error) {
+	var (
+		reader io.ReaderAt
+		size   int64
+	)
+	switch {
+	case len(bundle.Path) > 0:
+		data, err := os.ReadFile(bundle.Path)
+		if err != nil {
+
+			return nil, err
+		}
+		reader, size = bytes.NewReader(data), int64(len(data))
+	case bundle.Reader != nil:
+		reader, size = bundle.Reader, bundle.Size
+	case len(bundle.URL) > 0:
+		download := &URLResource{URL: bundle.URL, Header: bundle.Header, Client: bundle.Client}
+		data, err := download.LoadContext(ctx)
+		if err != nil {
+
+			return nil, err
+		}
+		reader, size = bytes.NewReader(data), int64(len(data))
+	default:
+
+		return nil, fmt.Errorf("zip resource bundle needs a path, a reader or an url")
+	}
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while opening zip archive %s. got %w", bundle.source(), err)
+	}
+
+	return archive, nil
+}
+
+// source names the archive in the resources and the errors.
+func (bundle *ZipResourceBundle) source() string {
+	switch {
+	case len(bundle.Path) > 0:
+
+		return bundle.Path
+	case bundle.Reader != nil:
+
+		return "reader"
+	default:
+
+		return bundle.URL
+	}
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	file, err := entry.Open()
+	if err != nil {
+
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// ZipResource resource implementation that loaded from an entry of a zip archive
+type ZipResource struct {
+	// Archive is the path or the URL of the zip archive.
+	Archive string
+	// Name is the path of the entry within the archive.
+	Name  string
+	Bytes []byte
+}
+
+// String will state the archive and the entry path.
+func (res *ZipResource) String() string {
+
+	return fmt.Sprintf("From zip archive [%s] %s", res.Archive, strings.TrimPrefix(res.Name, "/"))
+}
+
+// Load will load the resource into byte array. This implementation will not re-read the archive
+// when this method is called, it simply return the extracted data.
+func (res *ZipResource) Load() ([]byte, error) {
+
+	return res.Bytes, nil
+}
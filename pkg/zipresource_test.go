@@ -0,0 +1,130 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestZip(t *testing.T, corrupt string) []byte {
+	var buff bytes.Buffer
+	writer := zip.NewWriter(&buff)
+	files, err := NewFileResourceBundle("./test", "**/*.grl").Load()
+	assert.NoError(t, err)
+	for _, file := range files {
+		path := file.(*FileResource).Path
+		name := "rules/" + filepath.ToSlash(path[strings.Index(path, "test"+string(filepath.Separator))+5:])
+		entry, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = entry.Write(file.(*FileResource).Bytes)
+		assert.NoError(t, err)
+	}
+	_, err = writer.Create("rules/subfold1/")
+	assert.NoError(t, err)
+	entry, err := writer.Create("README.md")
+	assert.NoError(t, err)
+	_, err = entry.Write([]byte("# rules"))
+	assert.NoError(t, err)
+	if len(corrupt) > 0 {
+		entry, err = writer.CreateRaw(&zip.FileHeader{Name: corrupt, Method: zip.Store, CRC32: 1, CompressedSize64: 4, UncompressedSize64: 4})
+		assert.NoError(t, err)
+		_, err = entry.Write([]byte("rule"))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	return buff.Bytes()
+}
+
+func TestZipResourceBundle_Load(t *testing.T) {
+	archive := buildTestZip(t, "")
+	path := filepath.Join(t.TempDir(), "rules.zip")
+	assert.NoError(t, os.WriteFile(path, archive, 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+	fromURL := NewZipResourceBundleFromURL(server.URL+"/rules.zip", "/rules/**/*.grl")
+	fromURL.Header = http.Header{"Authorization": []string{"Bearer token"}}
+
+	for _, bundle := range []*ZipResourceBundle{
+		NewZipResourceBundle(path, "**/*.grl"),
+		NewZipResourceBundleFromReader(bytes.NewReader(archive), int64(len(archive)), "rules/**/*.grl"),
+		fromURL,
+	} {
+		resources, err := bundle.Load()
+		assert.NoError(t, err)
+		if assert.Len(t, resources, 6) {
+			assert.Equal(t, "From zip archive ["+bundle.source()+"] rules/subfold1/GrlFile11.grl", resources[0].String())
+			assert.True(t, strings.HasSuffix(resources[5].String(), "rules/subfold2/subfold21/GrlFile212.grl"), resources[5].String())
+			data, err := resources[0].Load()
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), "rule")
+		}
+	}
+
+	resources, err := NewZipResourceBundle(path, "rules/subfold1/*.grl", "*.md").Load()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	_, err = NewZipResourceBundleFromReader(bytes.NewReader([]byte("not a zip")), 9, "**/*.grl").Load()
+	assert.Error(t, err)
+	_, err = (&ZipResourceBundle{PathPattern: []string{"**/*.grl"}}).Load()
+	assert.Error(t, err)
+}
+
+func TestZipResourceBundle_LoadWithHandler(t *testing.T) {
+	archive := buildTestZip(t, "rules/Broken.grl")
+	bundle := NewZipResourceBundleFromReader(bytes.NewReader(archive), int64(len(archive)), "**/*.grl")
+
+	_, err := bundle.Load()
+	assert.Error(t, err)
+
+	failed := make([]string, 0)
+	resources, err := bundle.LoadWithHandler(func(res Resource, err error) Decision {
+		failed = append(failed, res.String())
+
+		return DecisionSkip
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 6)
+	assert.Equal(t, []string{"From zip archive [reader] rules/Broken.grl"}, failed)
+}
+
+func TestZipResourceBundle_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	bundle := NewZipResourceBundleFromURL(server.URL+"/rules.zip", "**/*.grl")
+	bundle.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	_, err := bundle.Load()
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,250 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package script provides the script languages a rule then scope may be written in, in place of GRL.
+package script
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// StarlarkLanguage is the language name of a Starlark then scope.
+	StarlarkLanguage = "starlark"
+
+	// DefaultStarlarkMaxSteps is the default number of steps a Starlark script may execute.
+	DefaultStarlarkMaxSteps = 100000
+)
+
+var (
+	// logFields default fields for grule
+	logFields = logger.Fields{
+		"package": "script",
+	}
+
+	// log is a logger instance with default fields for grule
+	log = logger.Log.WithFields(logFields)
+
+	// starlarkFileOptions allows the statements a then scope needs at the top level of the script.
+	starlarkFileOptions = &syntax.FileOptions{
+		Set:             true,
+		While:           true,
+		TopLevelControl: true,
+		GlobalReassign:  true,
+	}
+)
+
+// NewStarlark create new Starlark language limited to DefaultStarlarkMaxSteps. Register it to opt in :
+//
+//	ast.RegisterScriptLanguage(script.NewStarlark())
+func NewStarlark() *Starlark {
+
+	return &Starlark{
+		MaxSteps: DefaultStarlarkMaxSteps,
+	}
+}
+
+// Starlark runs then scopes written in Starlark, https://github.com/bazelbuild/starlark.
+// The script is sandboxed, it can not load modules nor reach anything but the facts of the data context,
+// which are bound by their name, and the Retract, Complete and Emit built-in functions.
+// Fields of the facts can be read and assigned and their methods called. Slices and maps are copied into
+// Starlark lists and dicts, changing them does not change the fact until the whole field is assigned.
+type Starlark struct {
+	// MaxSteps is the number of steps a script may execute before it is cancelled, zero means unlimited.
+	MaxSteps uint64
+}
+
+// Name returns the language name, starlark.
+func (s *Starlark) Name() string {
+
+	return StarlarkLanguage
+}
+
+// Compile parses and resolves the script. Names that are not Starlark built-ins are expected to be facts,
+// modules can not be loaded.
+func (s *Starlark) Compile(ruleName, source string) (ast.ScriptProgram, error) {
+	// the resolver asks for every name the script neither defines nor finds in the universe.
+	found := make(map[string]bool)
+	isPredeclared := func(name string) bool {
+		if _, universal := starlark.Universe[name]; universal {
+
+			return false
+		}
+		found[name] = true
+
+		return true
+	}
+	_, program, err := starlark.SourceProgramOptions(starlarkFileOptions, ruleName, source, isPredeclared)
+	if err != nil {
+
+		return nil, err
+	}
+	if program.NumLoads() > 0 {
+
+		return nil, fmt.Errorf("load statements are not allowed")
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &starlarkProgram{
+		ruleName: ruleName,
+		program:  program,
+		names:    names,
+		maxSteps: s.MaxSteps,
+	}, nil
+}
+
+// starlarkProgram is a compiled Starlark then scope.
+type starlarkProgram struct {
+	ruleName string
+	program  *starlark.Program
+	// names are the facts and built-in functions the script refers to.
+	names    []string
+	maxSteps uint64
+}
+
+// Execute runs the script in a new thread, so the program can be executed concurrently.
+func (p *starlarkProgram) Execute(ctx context.Context, dataContext ast.IDataContext) error {
+	predeclared, err := p.bind(dataContext)
+	if err != nil {
+
+		return err
+	}
+	scriptLog := logger.FromContext(ctx).Or(log)
+	thread := &starlark.Thread{
+		Name: p.ruleName,
+		Print: func(thread *starlark.Thread, msg string) {
+			scriptLog.Infof("script of rule %s : %s", p.ruleName, msg)
+		},
+	}
+	if p.maxSteps > 0 {
+		thread.SetMaxExecutionSteps(p.maxSteps)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+	_, err = p.program.Init(thread, predeclared)
+	if err != nil {
+
+		return fmt.Errorf("script of rule %s failed. got %w", p.ruleName, err)
+	}
+
+	return nil
+}
+
+// bind creates the predeclared values of the names the script refers to.
+func (p *starlarkProgram) bind(dataContext ast.IDataContext) (starlark.StringDict, error) {
+	predeclared := make(starlark.StringDict, len(p.names))
+	for _, name := range p.names {
+		switch name {
+		case "Retract":
+			predeclared[name] = starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var ruleName string
+				err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &ruleName)
+				if err != nil {
+
+					return nil, err
+				}
+				defunc := builtInFunctions(dataContext)
+				if defunc == nil {
+
+					return nil, fmt.Errorf("%s is only available while the engine executes the rule", fn.Name())
+				}
+				defunc.Retract(ruleName)
+
+				return starlark.None, nil
+			})
+
+			continue
+		case "Emit":
+			predeclared[name] = starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var message starlark.Value
+				err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &message)
+				if err != nil {
+
+					return nil, err
+				}
+				defunc := builtInFunctions(dataContext)
+				if defunc == nil {
+
+					return nil, fmt.Errorf("%s is only available while the engine executes the rule", fn.Name())
+				}
+				converted, err := fromStarlark(message, anyType)
+				if err != nil {
+
+					return nil, err
+				}
+				defunc.Emit(converted.Interface())
+
+				return starlark.None, nil
+			})
+
+			continue
+		case "Complete":
+			predeclared[name] = starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0)
+				if err != nil {
+
+					return nil, err
+				}
+				dataContext.Complete()
+
+				return starlark.None, nil
+			})
+
+			continue
+		}
+		node := dataContext.Get(name)
+		if node == nil || dataContext.IsRetracted(name) {
+
+			return nil, fmt.Errorf("script of rule %s refers to %s, which is not in the data context", p.ruleName, name)
+		}
+		value, err := toStarlark(node.Value())
+		if err != nil {
+
+			return nil, fmt.Errorf("script of rule %s can not bind %s. got %w", p.ruleName, name, err)
+		}
+		predeclared[name] = value
+	}
+
+	return predeclared, nil
+}
+
+// builtInFunctions returns the built-in functions the engine added into the data context, they are needed to retract.
+func builtInFunctions(dataContext ast.IDataContext) *ast.BuiltInFunctions {
+	node := dataContext.Get("DEFUNC")
+	if node == nil {
+
+		return nil
+	}
+	defunc, _ := node.Value().Interface().(*ast.BuiltInFunctions)
+
+	return defunc
+}
@@ -0,0 +1,411 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package script
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.starlark.net/starlark"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	anyType   = reflect.TypeOf((*interface{})(nil)).Elem()
+
+	// naturalTypes are the Go types Starlark values are converted into when the Go side accepts any type.
+	naturalTypes = map[string]reflect.Type{
+		"bool":   reflect.TypeOf(false),
+		"int":    reflect.TypeOf(int64(0)),
+		"float":  reflect.TypeOf(float64(0)),
+		"string": reflect.TypeOf(""),
+		"list":   reflect.TypeOf([]interface{}{}),
+		"tuple":  reflect.TypeOf([]interface{}{}),
+		"dict":   reflect.TypeOf(map[string]interface{}{}),
+	}
+)
+
+// fact exposes a Go struct, or a pointer to one, to Starlark. Its exported fields are attributes that can be
+// read and assigned, and its exported methods can be called.
+type fact struct {
+	value reflect.Value
+}
+
+// String implements starlark.Value
+func (f *fact) String() string {
+
+	return fmt.Sprintf("%v", f.value.Interface())
+}
+
+// Type implements starlark.Value
+func (f *fact) Type() string {
+
+	return f.value.Type().String()
+}
+
+// Freeze implements starlark.Value, facts are owned by the host and are never frozen.
+func (f *fact) Freeze() {}
+
+// Truth implements starlark.Value
+func (f *fact) Truth() starlark.Bool {
+
+	return starlark.True
+}
+
+// Hash implements starlark.Value
+func (f *fact) Hash() (uint32, error) {
+
+	return 0, fmt.Errorf("unhashable type: %s", f.Type())
+}
+
+// Attr returns the field or the method of the name, nil if there is none.
+func (f *fact) Attr(name string) (starlark.Value, error) {
+	if fn := f.value.MethodByName(name); fn.IsValid() {
+
+		return &method{name: name, fn: fn}, nil
+	}
+	structValue := reflect.Indirect(f.value)
+	if field, ok := structValue.Type().FieldByName(name); ok && field.IsExported() {
+
+		return toStarlark(structValue.FieldByIndex(field.Index))
+	}
+
+	return nil, nil
+}
+
+// AttrNames returns the exported fields and methods.
+func (f *fact) AttrNames() []string {
+	names := make([]string, 0)
+	structType := reflect.Indirect(f.value).Type()
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).IsExported() {
+			names = append(names, structType.Field(i).Name)
+		}
+	}
+	for i := 0; i < f.value.Type().NumMethod(); i++ {
+		names = append(names, f.value.Type().Method(i).Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetField assigns the exported field, the value is converted into the field type.
+func (f *fact) SetField(name string, value starlark.Value) error {
+	structValue := reflect.Indirect(f.value)
+	field, ok := structValue.Type().FieldByName(name)
+	if !ok || !field.IsExported() {
+
+		return starlark.NoSuchAttrError(fmt.Sprintf("%s has no field %s", f.Type(), name))
+	}
+	fieldValue := structValue.FieldByIndex(field.Index)
+	if !fieldValue.CanSet() {
+
+		return fmt.Errorf("field %s of %s can not be assigned, it is not a fact nor the field of a fact", name, f.Type())
+	}
+	converted, err := fromStarlark(value, fieldValue.Type())
+	if err != nil {
+
+		return fmt.Errorf("can not assign %s.%s. got %w", f.Type(), name, err)
+	}
+	fieldValue.Set(converted)
+
+	return nil
+}
+
+// method is a method of a fact bound to its receiver.
+type method struct {
+	name string
+	fn   reflect.Value
+}
+
+// Name implements starlark.Callable
+func (m *method) Name() string {
+
+	return m.name
+}
+
+// String implements starlark.Value
+func (m *method) String() string {
+
+	return fmt.Sprintf("<method %s>", m.name)
+}
+
+// Type implements starlark.Value
+func (m *method) Type() string {
+
+	return "method"
+}
+
+// Freeze implements starlark.Value
+func (m *method) Freeze() {}
+
+// Truth implements starlark.Value
+func (m *method) Truth() starlark.Bool {
+
+	return starlark.True
+}
+
+// Hash implements starlark.Value
+func (m *method) Hash() (uint32, error) {
+
+	return 0, fmt.Errorf("unhashable type: method")
+}
+
+// CallInternal calls the method with the arguments converted into its parameter types. A trailing error result
+// is returned as the call error, the other results are converted back, as a tuple if there are many.
+func (m *method) CallInternal(thread *starlark.Thread, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+
+		return nil, fmt.Errorf("%s does not accept keyword arguments", m.name)
+	}
+	fnType := m.fn.Type()
+	fixed := fnType.NumIn()
+	if fnType.IsVariadic() {
+		fixed--
+	}
+	if len(args) < fixed || (!fnType.IsVariadic() && len(args) > fixed) {
+
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", m.name, fnType.NumIn(), len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		paramType := fnType.In(min(i, fnType.NumIn()-1))
+		if i >= fixed {
+			paramType = paramType.Elem()
+		}
+		converted, err := fromStarlark(arg, paramType)
+		if err != nil {
+
+			return nil, fmt.Errorf("argument %d of %s. got %w", i+1, m.name, err)
+		}
+		in[i] = converted
+	}
+	out := m.fn.Call(in)
+	if len(out) > 0 && out[len(out)-1].Type() == errorType {
+		if !out[len(out)-1].IsNil() {
+
+			return nil, out[len(out)-1].Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+	switch len(out) {
+	case 0:
+
+		return starlark.None, nil
+	case 1:
+
+		return toStarlark(out[0])
+	}
+	results := make(starlark.Tuple, len(out))
+	for i, result := range out {
+		converted, err := toStarlark(result)
+		if err != nil {
+
+			return nil, err
+		}
+		results[i] = converted
+	}
+
+	return results, nil
+}
+
+// toStarlark converts a Go value into Starlark. Structs stay facts, slices and maps are copied.
+func toStarlark(value reflect.Value) (starlark.Value, error) {
+	if !value.IsValid() {
+
+		return starlark.None, nil
+	}
+	switch value.Kind() {
+	case reflect.Bool:
+
+		return starlark.Bool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return starlark.MakeInt64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		return starlark.MakeUint64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+
+		return starlark.Float(value.Float()), nil
+	case reflect.String:
+
+		return starlark.String(value.String()), nil
+	case reflect.Pointer:
+		if value.IsNil() {
+
+			return starlark.None, nil
+		}
+		if value.Elem().Kind() == reflect.Struct {
+
+			return &fact{value: value}, nil
+		}
+
+		return toStarlark(value.Elem())
+	case reflect.Interface:
+		if value.IsNil() {
+
+			return starlark.None, nil
+		}
+
+		return toStarlark(value.Elem())
+	case reflect.Struct:
+
+		return &fact{value: value}, nil
+	case reflect.Slice, reflect.Array:
+		elems := make([]starlark.Value, value.Len())
+		for i := range elems {
+			elem, err := toStarlark(value.Index(i))
+			if err != nil {
+
+				return nil, err
+			}
+			elems[i] = elem
+		}
+
+		return starlark.NewList(elems), nil
+	case reflect.Map:
+		dict := starlark.NewDict(value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key, err := toStarlark(iter.Key())
+			if err != nil {
+
+				return nil, err
+			}
+			elem, err := toStarlark(iter.Value())
+			if err != nil {
+
+				return nil, err
+			}
+			err = dict.SetKey(key, elem)
+			if err != nil {
+
+				return nil, err
+			}
+		}
+
+		return dict, nil
+	}
+
+	return nil, fmt.Errorf("%s values are not supported in scripts", value.Type())
+}
+
+// fromStarlark converts a Starlark value into the Go type.
+func fromStarlark(value starlark.Value, goType reflect.Type) (reflect.Value, error) {
+	if value == starlark.None {
+
+		return reflect.Zero(goType), nil
+	}
+	if f, ok := value.(*fact); ok {
+		if f.value.Type().AssignableTo(goType) {
+
+			return f.value, nil
+		}
+		if f.value.Kind() == reflect.Pointer && f.value.Elem().Type().AssignableTo(goType) {
+
+			return f.value.Elem(), nil
+		}
+		if f.value.CanAddr() && f.value.Addr().Type().AssignableTo(goType) {
+
+			return f.value.Addr(), nil
+		}
+
+		return reflect.Value{}, fmt.Errorf("can not use %s as %s", f.Type(), goType)
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		if b, ok := value.(starlark.Bool); ok {
+
+			return reflect.ValueOf(bool(b)).Convert(goType), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := value.(starlark.Int); ok {
+			i64, exact := i.Int64()
+			converted := reflect.New(goType).Elem()
+			if !exact || converted.OverflowInt(i64) {
+
+				return reflect.Value{}, fmt.Errorf("%s overflows %s", i, goType)
+			}
+			converted.SetInt(i64)
+
+			return converted, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := value.(starlark.Int); ok {
+			u64, exact := i.Uint64()
+			converted := reflect.New(goType).Elem()
+			if !exact || converted.OverflowUint(u64) {
+
+				return reflect.Value{}, fmt.Errorf("%s overflows %s", i, goType)
+			}
+			converted.SetUint(u64)
+
+			return converted, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := starlark.AsFloat(value); ok {
+
+			return reflect.ValueOf(f).Convert(goType), nil
+		}
+	case reflect.String:
+		if s, ok := starlark.AsString(value); ok {
+
+			return reflect.ValueOf(s).Convert(goType), nil
+		}
+	case reflect.Slice:
+		if indexable, ok := value.(starlark.Indexable); ok {
+			converted := reflect.MakeSlice(goType, indexable.Len(), indexable.Len())
+			for i := 0; i < indexable.Len(); i++ {
+				elem, err := fromStarlark(indexable.Index(i), goType.Elem())
+				if err != nil {
+
+					return reflect.Value{}, err
+				}
+				converted.Index(i).Set(elem)
+			}
+
+			return converted, nil
+		}
+	case reflect.Map:
+		if dict, ok := value.(*starlark.Dict); ok {
+			converted := reflect.MakeMapWithSize(goType, dict.Len())
+			for _, item := range dict.Items() {
+				key, err := fromStarlark(item[0], goType.Key())
+				if err != nil {
+
+					return reflect.Value{}, err
+				}
+				elem, err := fromStarlark(item[1], goType.Elem())
+				if err != nil {
+
+					return reflect.Value{}, err
+				}
+				converted.SetMapIndex(key, elem)
+			}
+
+			return converted, nil
+		}
+	case reflect.Interface:
+		if natural, ok := naturalTypes[value.Type()]; ok && natural.AssignableTo(goType) {
+
+			return fromStarlark(value, natural)
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("can not convert %s to %s", value.Type(), goType)
+}
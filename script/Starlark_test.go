@@ -0,0 +1,245 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package script
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderItem struct {
+	Name  string
+	Price float64
+}
+
+type Customer struct {
+	Tier string
+}
+
+type Order struct {
+	Items    []OrderItem
+	Customer *Customer
+	Total    float64
+	Discount float64
+	Tags     []string
+	Notes    string
+}
+
+func (o *Order) Note(format string, args ...interface{}) string {
+	o.Notes = fmt.Sprintf(format, args...)
+
+	return o.Notes
+}
+
+const starlarkRules = `
+rule Total "sum the items with a script" salience 10 {
+	when
+		Order.Total == 0
+	then starlark ` + "```" + `
+		total = 0
+		for item in Order.Items:
+		    total += item.Price
+		Order.Total = total
+		if Order.Customer.Tier == "gold" and total > 100:
+		    Order.Discount = total * 0.1
+		    Order.Tags = sorted(set(Order.Tags + ["discounted"]))
+		Order.Note("%d items", len(Order.Items))
+		Emit(Order.Customer)
+		Retract("Total")
+	` + "```" + `
+}
+rule Reviewed "a GRL rule reacting to the script" {
+	when
+		Order.Discount > 0
+	then
+		Order.Customer.Tier = "reviewed";
+		Retract("Reviewed");
+}`
+
+func buildStarlarkRules(grl string) (*ast.KnowledgeLibrary, error) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+
+	return lib, rb.BuildRuleFromResource("Script", "1", pkg.NewBytesResource([]byte(grl)))
+}
+
+func TestStarlark(t *testing.T) {
+	assert.NoError(t, ast.RegisterScriptLanguage(NewStarlark()))
+	defer ast.UnregisterScriptLanguage(StarlarkLanguage)
+
+	lib, err := buildStarlarkRules(starlarkRules)
+	assert.NoError(t, err)
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Script", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		order := &Order{
+			Items:    []OrderItem{{Name: "a", Price: 80}, {Name: "b", Price: 40}},
+			Customer: &Customer{Tier: "gold"},
+			Tags:     []string{"web"},
+		}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Order", order))
+		kb, err := library.NewKnowledgeBaseInstance("Script", "1")
+		assert.NoError(t, err)
+		outputs := engine.NewOutputs()
+		assert.NoError(t, engine.NewGruleEngine().ExecuteWithContext(engine.WithOutputs(context.Background(), outputs), dataContext, kb))
+		assert.Equal(t, []*Customer{order.Customer}, engine.Emitted[*Customer](outputs))
+		assert.Equal(t, 120.0, order.Total)
+		assert.Equal(t, 12.0, order.Discount)
+		assert.Equal(t, []string{"discounted", "web"}, order.Tags)
+		assert.Equal(t, "2 items", order.Notes)
+		assert.Equal(t, "reviewed", order.Customer.Tier)
+	}
+}
+
+func TestStarlark_KnowledgeLibrary(t *testing.T) {
+	languages := ast.NewScriptLanguageRegistry()
+	assert.NoError(t, languages.Register(NewStarlark()))
+
+	// the languages of a library are not known to the others.
+	_, err := buildStarlarkRules(starlarkRules)
+	assert.Error(t, err)
+
+	lib := ast.NewKnowledgeLibrary()
+	lib.ScriptLanguages = languages
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromResource("Script", "1", pkg.NewBytesResource([]byte(starlarkRules))))
+	var buff bytes.Buffer
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Script", "1"))
+	loaded := ast.NewKnowledgeLibrary()
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.Error(t, err, "the loading library does not have the language")
+	buff.Reset()
+	assert.NoError(t, lib.StoreKnowledgeBaseToWriter(&buff, "Script", "1"))
+	loaded.ScriptLanguages = languages
+	_, err = loaded.LoadKnowledgeBaseFromReader(&buff, true)
+	assert.NoError(t, err)
+
+	for _, library := range []*ast.KnowledgeLibrary{lib, loaded} {
+		order := &Order{Items: []OrderItem{{Name: "a", Price: 80}}, Customer: &Customer{Tier: "silver"}}
+		dataContext := ast.NewDataContext()
+		assert.NoError(t, dataContext.Add("Order", order))
+		kb, err := library.NewKnowledgeBaseInstance("Script", "1")
+		assert.NoError(t, err)
+		assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+		assert.Equal(t, 80.0, order.Total)
+	}
+}
+
+func TestStarlark_BuildRuleFromReader(t *testing.T) {
+	assert.NoError(t, ast.RegisterScriptLanguage(NewStarlark()))
+	defer ast.UnregisterScriptLanguage(StarlarkLanguage)
+
+	// the braces and the quotes of a script do not end the rule it belongs to.
+	grl := `
+rule Tag "tags the order" {
+	when
+		Order.Notes == ""
+	then starlark ` + "```" + `
+		# close } here, the order's notes are set below
+		Order.Notes = "it's {tagged}"
+		Retract("Tag")
+	` + "```" + `
+}
+rule Total "a GRL rule after the script" {
+	when
+		Order.Total == 0
+	then
+		Order.Total = 1;
+}`
+	lib := ast.NewKnowledgeLibrary()
+	assert.NoError(t, builder.NewRuleBuilder(lib).BuildRuleFromReader("Script", "1", bytes.NewBufferString(grl)))
+	kb, err := lib.NewKnowledgeBaseInstance("Script", "1")
+	assert.NoError(t, err)
+	assert.Len(t, kb.RuleEntries, 2)
+
+	order := &Order{}
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Order", order))
+	assert.NoError(t, engine.NewGruleEngine().Execute(dataContext, kb))
+	assert.Equal(t, "it's {tagged}", order.Notes)
+	assert.Equal(t, 1.0, order.Total)
+}
+
+func TestStarlark_Sandbox(t *testing.T) {
+	language := NewStarlark()
+	language.MaxSteps = 1000
+	assert.NoError(t, ast.RegisterScriptLanguage(language))
+	defer ast.UnregisterScriptLanguage(StarlarkLanguage)
+
+	lib, err := buildStarlarkRules("rule Loop { when true then starlark ```\nwhile True:\n    pass\n``` }")
+	assert.NoError(t, err)
+	kb, err := lib.NewKnowledgeBaseInstance("Script", "1")
+	assert.NoError(t, err)
+	err = engine.NewGruleEngine().Execute(ast.NewDataContext(), kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "too many steps")
+
+	testData := []struct {
+		grl string
+		err string
+	}{
+		{grl: "rule A { when true then starlark ```load('os.star', 'os')``` }", err: "load statements are not allowed"},
+		{grl: "rule A { when true then starlark ```Order.Total = ``` }", err: "invalid starlark script"},
+		{grl: "rule A { when true then python ```Order.Total = 1``` }", err: "script language python is not registered"},
+	}
+	for _, td := range testData {
+		_, err := buildStarlarkRules(td.grl)
+		var reporter *pkg.GruleErrorReporter
+		if assert.ErrorAs(t, err, &reporter, td.grl) && assert.Len(t, reporter.Errors, 1, td.grl) {
+			assert.Contains(t, reporter.Errors[0].Error(), td.err, td.grl)
+		}
+	}
+
+	lib, err = buildStarlarkRules("rule A { when true then starlark ```Order.Missing = 1\nRetract('A')``` }")
+	assert.NoError(t, err)
+	kb, err = lib.NewKnowledgeBaseInstance("Script", "1")
+	assert.NoError(t, err)
+	err = engine.NewGruleEngine().Execute(ast.NewDataContext(), kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "refers to Order, which is not in the data context")
+	dataContext := ast.NewDataContext()
+	assert.NoError(t, dataContext.Add("Order", &Order{}))
+	err = engine.NewGruleEngine().Execute(dataContext, kb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no field Missing")
+}
+
+func TestStarlark_Sanitizer(t *testing.T) {
+	assert.NoError(t, ast.RegisterScriptLanguage(NewStarlark()))
+	defer ast.UnregisterScriptLanguage(StarlarkLanguage)
+
+	sanitizer := &builder.Sanitizer{}
+	rb := builder.NewRuleBuilder(ast.NewKnowledgeLibrary())
+	rb.Sanitizer = sanitizer
+	grl := "rule A { when true then starlark ```Retract('A')``` }"
+	err := rb.BuildRuleFromResource("Script", "1", pkg.NewBytesResource([]byte(grl)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "scripts are not allowed")
+
+	sanitizer.AllowScripts = true
+	assert.NoError(t, rb.BuildRuleFromResource("Script", "1", pkg.NewBytesResource([]byte(grl))))
+}
@@ -0,0 +1,231 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/logger"
+)
+
+var (
+	// logFields default fields for grule
+	logFields = logger.Fields{
+		"package": "session",
+	}
+
+	// log is a logger instance with default fields for grule
+	log = logger.Log.WithFields(logFields)
+)
+
+// NewSession creates new Session identified by id, executing a new instance of the knowledge base of the library.
+// The state is saved into the store after every execution, Restore brings it back after a restart.
+func NewSession(id string, library *ast.KnowledgeLibrary, name, version string, store Store) (*Session, error) {
+	knowledgeBase, err := library.NewKnowledgeBaseInstance(name, version)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return &Session{
+		ID:        id,
+		Knowledge: knowledgeBase,
+		Engine:    engine.NewGruleEngine(),
+		Store:     store,
+		facts:     make(map[string]interface{}),
+	}, nil
+}
+
+// Session is a long running decision session, such as the one of a device. Its facts and the state of its rules,
+// the agenda, are kept from one execution to the next and saved into the Store, so the session carries on where it
+// stopped after the process restarts:
+//
+//	sess, err := session.NewSession("pump-7", lib, "Pump", "1.0.0", store)
+//	err = sess.Add("Pump", &Pump{})
+//	restored, err := sess.Restore()
+//	...
+//	err = sess.Execute(ctx)
+//
+// The facts are saved in JSON, they must be pointers to values encoding/json can encode and decode.
+// The agenda keeps, for every rule, its fire count, whether it is retracted and the last time it fired, which
+// holds its cooldown across restarts. The engine resets the fire counts and the retractions at the start of every
+// execution, as it does without a session.
+type Session struct {
+	ID        string
+	Knowledge *ast.KnowledgeBase
+	Engine    *engine.GruleEngine
+	Store     Store
+
+	lock  sync.Mutex
+	facts map[string]interface{}
+	// unknown are the stored facts that were not added to this session, they are saved again untouched.
+	unknown map[string]json.RawMessage
+}
+
+// log returns the logger of the engine of the session, the default logger of the package if it has none.
+func (sess *Session) log() logger.LogEntry {
+	if sess.Engine == nil {
+
+		return log
+	}
+
+	return sess.Engine.Logger.Or(log)
+}
+
+// Add adds a fact into the session. The fact must be a pointer, Restore decodes the stored fact into it.
+func (sess *Session) Add(name string, fact interface{}) error {
+	value := reflect.ValueOf(fact)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+
+		return fmt.Errorf("fact %s of session %s must be a non nil pointer, got %T", name, sess.ID, fact)
+	}
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+	sess.facts[name] = fact
+
+	return nil
+}
+
+// Fact returns the fact added with the name, or nil.
+func (sess *Session) Fact(name string) interface{} {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	return sess.facts[name]
+}
+
+// Restore loads the state saved in the store into the facts added so far and into the rules of the knowledge base.
+// It returns false if the store has no state for this session, the facts are then left as they are.
+// A rule of the saved agenda the knowledge base no longer has is ignored.
+func (sess *Session) Restore() (bool, error) {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+	state, err := sess.Store.Load(sess.ID)
+	if err != nil {
+
+		return false, err
+	}
+	if state == nil {
+
+		return false, nil
+	}
+	sess.unknown = nil
+	for name, data := range state.Facts {
+		fact, ok := sess.facts[name]
+		if !ok {
+			if sess.unknown == nil {
+				sess.unknown = make(map[string]json.RawMessage)
+			}
+			sess.unknown[name] = data
+
+			continue
+		}
+		err = json.Unmarshal(data, fact)
+		if err != nil {
+
+			return false, fmt.Errorf("error while restoring fact %s of session %s. got %w", name, sess.ID, err)
+		}
+	}
+	for _, entry := range sess.Knowledge.RuleEntries {
+		if entry.Deleted {
+
+			continue
+		}
+		if ruleState, ok := state.Agenda[entry.StableID()]; ok {
+			entry.FireCount = ruleState.FireCount
+			entry.LastFired = ruleState.LastFired
+			entry.Retracted = ruleState.Retracted
+		}
+	}
+	sess.log().Debugf("Restored session %s saved at %s", sess.ID, state.SavedAt)
+
+	return true, nil
+}
+
+// Execute executes the knowledge base against the facts of the session, then saves the state into the store.
+// The state is saved even if the execution fails, the facts may have been modified by the rules fired before.
+func (sess *Session) Execute(ctx context.Context) error {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+	dataCtx := ast.NewDataContext()
+	for name, fact := range sess.facts {
+		err := dataCtx.Add(name, fact)
+		if err != nil {
+
+			return err
+		}
+	}
+	execErr := sess.Engine.ExecuteWithContext(ctx, dataCtx, sess.Knowledge)
+	err := sess.save()
+	if execErr != nil {
+
+		return execErr
+	}
+
+	return err
+}
+
+// Save saves the state of the session into the store.
+func (sess *Session) Save() error {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	return sess.save()
+}
+
+// Delete removes the state of the session from the store. The session itself is left as it is.
+func (sess *Session) Delete() error {
+
+	return sess.Store.Delete(sess.ID)
+}
+
+func (sess *Session) save() error {
+	state := &State{
+		Facts:   make(map[string]json.RawMessage, len(sess.facts)+len(sess.unknown)),
+		Agenda:  make(map[string]RuleState),
+		SavedAt: time.Now(),
+	}
+	for name, data := range sess.unknown {
+		state.Facts[name] = data
+	}
+	for name, fact := range sess.facts {
+		data, err := json.Marshal(fact)
+		if err != nil {
+
+			return fmt.Errorf("error while saving fact %s of session %s. got %w", name, sess.ID, err)
+		}
+		state.Facts[name] = data
+	}
+	for _, entry := range sess.Knowledge.RuleEntries {
+		if entry.Deleted {
+
+			continue
+		}
+		state.Agenda[entry.StableID()] = RuleState{
+			FireCount: entry.FireCount,
+			LastFired: entry.LastFired,
+			Retracted: entry.Retracted,
+		}
+	}
+
+	return sess.Store.Save(sess.ID, state)
+}
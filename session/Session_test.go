@@ -0,0 +1,114 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+type Pump struct {
+	Pressure int
+	Alarms   int
+	Checked  bool
+}
+
+const pumpRules = `
+rule Alarm "raise an alarm at most once an hour" cooldown 1h {
+	when
+		Pump.Pressure > 100
+	then
+		Pump.Alarms = Pump.Alarms + 1;
+}
+rule Check "check the pump" {
+	when
+		!Pump.Checked
+	then
+		Pump.Checked = true;
+}`
+
+func newPumpSession(t *testing.T, store Store) (*Session, *Pump) {
+	lib := ast.NewKnowledgeLibrary()
+	rb := builder.NewRuleBuilder(lib)
+	assert.NoError(t, rb.BuildRuleFromResource("Pump", "1.0.0", pkg.NewBytesResource([]byte(pumpRules))))
+	sess, err := NewSession("pump-7", lib, "Pump", "1.0.0", store)
+	assert.NoError(t, err)
+	pump := &Pump{}
+	assert.NoError(t, sess.Add("Pump", pump))
+
+	return sess, pump
+}
+
+func TestSession_BoltStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenBoltStore(path)
+	assert.NoError(t, err)
+
+	sess, pump := newPumpSession(t, store)
+	restored, err := sess.Restore()
+	assert.NoError(t, err)
+	assert.False(t, restored)
+	pump.Pressure = 120
+	assert.NoError(t, sess.Execute(context.Background()))
+	assert.Equal(t, 1, pump.Alarms)
+	assert.True(t, pump.Checked)
+	assert.NoError(t, store.Close())
+
+	// the process restarts.
+	store, err = OpenBoltStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	sess, pump = newPumpSession(t, store)
+	restored, err = sess.Restore()
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, 120, pump.Pressure)
+	assert.Equal(t, 1, pump.Alarms)
+	assert.True(t, pump.Checked)
+
+	assert.NoError(t, sess.Execute(context.Background()))
+	assert.Equal(t, 1, pump.Alarms, "the cooldown of Alarm survived the restart")
+
+	assert.NoError(t, sess.Delete())
+	sess, _ = newPumpSession(t, store)
+	restored, err = sess.Restore()
+	assert.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestSession_UnknownFacts(t *testing.T) {
+	store := NewMemoryStore()
+	sess, _ := newPumpSession(t, store)
+	assert.NoError(t, sess.Add("Other", &Pump{Pressure: 5}))
+	assert.NoError(t, sess.Save())
+
+	sess, _ = newPumpSession(t, store)
+	restored, err := sess.Restore()
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	assert.NoError(t, sess.Save())
+
+	state, err := store.Load("pump-7")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Pressure":5,"Alarms":0,"Checked":false}`, string(state.Facts["Other"]))
+
+	assert.Error(t, sess.Add("Value", Pump{}))
+}
@@ -0,0 +1,205 @@
+//  Copyright hyperjumptech/grule-rule-engine Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// State is what a Store keeps of a session: its facts and the state of its rules.
+type State struct {
+	// Facts are the facts of the session, encoded in JSON, by their name.
+	Facts map[string]json.RawMessage `json:"facts"`
+	// Agenda is the state of the rules, by their stable id.
+	Agenda map[string]RuleState `json:"agenda"`
+	// SavedAt is when the state was saved.
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// RuleState is the state of a rule kept between executions, such as the last time it fired for its cooldown.
+type RuleState struct {
+	FireCount int       `json:"fireCount,omitempty"`
+	LastFired time.Time `json:"lastFired,omitempty"`
+	Retracted bool      `json:"retracted,omitempty"`
+}
+
+// Store is a storage driver persisting the state of sessions, so they survive process restarts.
+// It must be safe for concurrent use.
+type Store interface {
+	// Load returns the state of the session, or nil if the store has none.
+	Load(id string) (*State, error)
+	// Save replaces the state of the session.
+	Save(id string, state *State) error
+	// Delete removes the state of the session, if any.
+	Delete(id string) error
+}
+
+// NewMemoryStore creates new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+
+	return &MemoryStore{states: make(map[string][]byte)}
+}
+
+// MemoryStore keeps the states in memory. They do not survive the process, it is meant for tests and for sessions
+// that only need to outlive their Session value.
+type MemoryStore struct {
+	lock   sync.Mutex
+	states map[string][]byte
+}
+
+// Load implements Store.
+func (store *MemoryStore) Load(id string) (*State, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	data, ok := store.states[id]
+	if !ok {
+
+		return nil, nil
+	}
+
+	return decodeState(id, data)
+}
+
+// Save implements Store.
+func (store *MemoryStore) Save(id string, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+
+		return fmt.Errorf("error while encoding the state of session %s. got %w", id, err)
+	}
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.states[id] = data
+
+	return nil
+}
+
+// Delete implements Store.
+func (store *MemoryStore) Delete(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	delete(store.states, id)
+
+	return nil
+}
+
+// boltBucket is the bucket of the BoltDB database holding the states.
+var boltBucket = []byte("grule-sessions")
+
+// OpenBoltStore opens, or creates, the BoltDB database file at path and returns a Store keeping the states in it.
+// The file stays locked until Close is called, only one process may use it at a time.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+
+		return nil, fmt.Errorf("error while opening the session database %s. got %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("error while preparing the session database %s. got %w", path, err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// BoltStore keeps the states in an embedded BoltDB database file, suited to edge devices without a database server.
+// Every Save is committed to the file before it returns.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Load implements Store.
+func (store *BoltStore) Load(id string) (*State, error) {
+	var data []byte
+	err := store.db.View(func(tx *bolt.Tx) error {
+		if stored := tx.Bucket(boltBucket).Get([]byte(id)); stored != nil {
+			// the stored bytes are only valid within the transaction.
+			data = append([]byte(nil), stored...)
+		}
+
+		return nil
+	})
+	if err != nil {
+
+		return nil, fmt.Errorf("error while loading the state of session %s. got %w", id, err)
+	}
+	if data == nil {
+
+		return nil, nil
+	}
+
+	return decodeState(id, data)
+}
+
+// Save implements Store.
+func (store *BoltStore) Save(id string, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+
+		return fmt.Errorf("error while encoding the state of session %s. got %w", id, err)
+	}
+	err = store.db.Update(func(tx *bolt.Tx) error {
+
+		return tx.Bucket(boltBucket).Put([]byte(id), data)
+	})
+	if err != nil {
+
+		return fmt.Errorf("error while saving the state of session %s. got %w", id, err)
+	}
+
+	return nil
+}
+
+// Delete implements Store.
+func (store *BoltStore) Delete(id string) error {
+	err := store.db.Update(func(tx *bolt.Tx) error {
+
+		return tx.Bucket(boltBucket).Delete([]byte(id))
+	})
+	if err != nil {
+
+		return fmt.Errorf("error while deleting the state of session %s. got %w", id, err)
+	}
+
+	return nil
+}
+
+// Close closes the database file.
+func (store *BoltStore) Close() error {
+
+	return store.db.Close()
+}
+
+func decodeState(id string, data []byte) (*State, error) {
+	state := &State{}
+	err := json.Unmarshal(data, state)
+	if err != nil {
+
+		return nil, fmt.Errorf("error while decoding the state of session %s. got %w", id, err)
+	}
+
+	return state, nil
+}